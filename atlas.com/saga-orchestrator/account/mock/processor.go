@@ -0,0 +1,51 @@
+package mock
+
+import (
+	"atlas-saga-orchestrator/kafka/message"
+	"github.com/Chronicle20/atlas-constants/world"
+	"github.com/google/uuid"
+)
+
+// ProcessorMock is a mock implementation of the account.Processor interface
+type ProcessorMock struct {
+	ChangeCharacterSlotsAndEmitFunc func(transactionId uuid.UUID, accountId uint32, worldId world.Id, amount int16) error
+	ChangeCharacterSlotsFunc        func(mb *message.Buffer) func(transactionId uuid.UUID, accountId uint32, worldId world.Id, amount int16) error
+	ChangePremiumTimeAndEmitFunc    func(transactionId uuid.UUID, accountId uint32, seconds int64) error
+	ChangePremiumTimeFunc           func(mb *message.Buffer) func(transactionId uuid.UUID, accountId uint32, seconds int64) error
+}
+
+// ChangeCharacterSlotsAndEmit is a mock implementation of the account.Processor.ChangeCharacterSlotsAndEmit method
+func (m *ProcessorMock) ChangeCharacterSlotsAndEmit(transactionId uuid.UUID, accountId uint32, worldId world.Id, amount int16) error {
+	if m.ChangeCharacterSlotsAndEmitFunc != nil {
+		return m.ChangeCharacterSlotsAndEmitFunc(transactionId, accountId, worldId, amount)
+	}
+	return nil
+}
+
+// ChangeCharacterSlots is a mock implementation of the account.Processor.ChangeCharacterSlots method
+func (m *ProcessorMock) ChangeCharacterSlots(mb *message.Buffer) func(transactionId uuid.UUID, accountId uint32, worldId world.Id, amount int16) error {
+	if m.ChangeCharacterSlotsFunc != nil {
+		return m.ChangeCharacterSlotsFunc(mb)
+	}
+	return func(transactionId uuid.UUID, accountId uint32, worldId world.Id, amount int16) error {
+		return nil
+	}
+}
+
+// ChangePremiumTimeAndEmit is a mock implementation of the account.Processor.ChangePremiumTimeAndEmit method
+func (m *ProcessorMock) ChangePremiumTimeAndEmit(transactionId uuid.UUID, accountId uint32, seconds int64) error {
+	if m.ChangePremiumTimeAndEmitFunc != nil {
+		return m.ChangePremiumTimeAndEmitFunc(transactionId, accountId, seconds)
+	}
+	return nil
+}
+
+// ChangePremiumTime is a mock implementation of the account.Processor.ChangePremiumTime method
+func (m *ProcessorMock) ChangePremiumTime(mb *message.Buffer) func(transactionId uuid.UUID, accountId uint32, seconds int64) error {
+	if m.ChangePremiumTimeFunc != nil {
+		return m.ChangePremiumTimeFunc(mb)
+	}
+	return func(transactionId uuid.UUID, accountId uint32, seconds int64) error {
+		return nil
+	}
+}
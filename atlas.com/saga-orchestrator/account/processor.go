@@ -0,0 +1,60 @@
+package account
+
+import (
+	"atlas-saga-orchestrator/kafka/message"
+	account2 "atlas-saga-orchestrator/kafka/message/account"
+	"atlas-saga-orchestrator/kafka/producer"
+	"context"
+	"github.com/Chronicle20/atlas-constants/world"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type Processor interface {
+	ChangeCharacterSlotsAndEmit(transactionId uuid.UUID, accountId uint32, worldId world.Id, amount int16) error
+	ChangeCharacterSlots(mb *message.Buffer) func(transactionId uuid.UUID, accountId uint32, worldId world.Id, amount int16) error
+	ChangePremiumTimeAndEmit(transactionId uuid.UUID, accountId uint32, seconds int64) error
+	ChangePremiumTime(mb *message.Buffer) func(transactionId uuid.UUID, accountId uint32, seconds int64) error
+}
+
+type ProcessorImpl struct {
+	l   logrus.FieldLogger
+	ctx context.Context
+	p   producer.Provider
+}
+
+func NewProcessor(l logrus.FieldLogger, ctx context.Context) Processor {
+	return &ProcessorImpl{
+		l:   l,
+		ctx: ctx,
+		p:   producer.ProviderImpl(l)(ctx),
+	}
+}
+
+// ChangeCharacterSlotsAndEmit requests the account's character slots in the world be changed by the amount, which is
+// negative to remove slots
+func (p *ProcessorImpl) ChangeCharacterSlotsAndEmit(transactionId uuid.UUID, accountId uint32, worldId world.Id, amount int16) error {
+	return message.Emit(p.p)(func(mb *message.Buffer) error {
+		return p.ChangeCharacterSlots(mb)(transactionId, accountId, worldId, amount)
+	})
+}
+
+func (p *ProcessorImpl) ChangeCharacterSlots(mb *message.Buffer) func(transactionId uuid.UUID, accountId uint32, worldId world.Id, amount int16) error {
+	return func(transactionId uuid.UUID, accountId uint32, worldId world.Id, amount int16) error {
+		return mb.Put(account2.EnvCommandTopic, ChangeCharacterSlotsProvider(transactionId, accountId, worldId, amount))
+	}
+}
+
+// ChangePremiumTimeAndEmit requests the account's premium time be changed by the seconds, which are negative to deduct
+// time
+func (p *ProcessorImpl) ChangePremiumTimeAndEmit(transactionId uuid.UUID, accountId uint32, seconds int64) error {
+	return message.Emit(p.p)(func(mb *message.Buffer) error {
+		return p.ChangePremiumTime(mb)(transactionId, accountId, seconds)
+	})
+}
+
+func (p *ProcessorImpl) ChangePremiumTime(mb *message.Buffer) func(transactionId uuid.UUID, accountId uint32, seconds int64) error {
+	return func(transactionId uuid.UUID, accountId uint32, seconds int64) error {
+		return mb.Put(account2.EnvCommandTopic, ChangePremiumTimeProvider(transactionId, accountId, seconds))
+	}
+}
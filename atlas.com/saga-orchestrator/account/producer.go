@@ -0,0 +1,37 @@
+package account
+
+import (
+	account2 "atlas-saga-orchestrator/kafka/message/account"
+	"github.com/Chronicle20/atlas-constants/world"
+	"github.com/Chronicle20/atlas-kafka/producer"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+)
+
+func ChangeCharacterSlotsProvider(transactionId uuid.UUID, accountId uint32, worldId world.Id, amount int16) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(accountId))
+	value := &account2.Command[account2.ChangeCharacterSlotsCommandBody]{
+		TransactionId: transactionId,
+		AccountId:     accountId,
+		Type:          account2.CommandTypeChangeCharacterSlots,
+		Body: account2.ChangeCharacterSlotsCommandBody{
+			WorldId: byte(worldId),
+			Amount:  amount,
+		},
+	}
+	return producer.SingleMessageProvider(key, value)
+}
+
+func ChangePremiumTimeProvider(transactionId uuid.UUID, accountId uint32, seconds int64) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(accountId))
+	value := &account2.Command[account2.ChangePremiumTimeCommandBody]{
+		TransactionId: transactionId,
+		AccountId:     accountId,
+		Type:          account2.CommandTypeChangePremiumTime,
+		Body: account2.ChangePremiumTimeCommandBody{
+			Seconds: seconds,
+		},
+	}
+	return producer.SingleMessageProvider(key, value)
+}
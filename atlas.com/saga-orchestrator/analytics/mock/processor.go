@@ -0,0 +1,30 @@
+package mock
+
+import (
+	"atlas-saga-orchestrator/kafka/message"
+	analytics2 "atlas-saga-orchestrator/kafka/message/analytics"
+)
+
+// ProcessorMock is a mock implementation of the analytics.Processor interface
+type ProcessorMock struct {
+	PublishAndEmitFunc func(e analytics2.Event) error
+	PublishFunc        func(mb *message.Buffer) func(e analytics2.Event) error
+}
+
+// PublishAndEmit is a mock implementation of the analytics.Processor.PublishAndEmit method
+func (m *ProcessorMock) PublishAndEmit(e analytics2.Event) error {
+	if m.PublishAndEmitFunc != nil {
+		return m.PublishAndEmitFunc(e)
+	}
+	return nil
+}
+
+// Publish is a mock implementation of the analytics.Processor.Publish method
+func (m *ProcessorMock) Publish(mb *message.Buffer) func(e analytics2.Event) error {
+	if m.PublishFunc != nil {
+		return m.PublishFunc(mb)
+	}
+	return func(e analytics2.Event) error {
+		return nil
+	}
+}
@@ -0,0 +1,41 @@
+package analytics
+
+import (
+	"atlas-saga-orchestrator/kafka/message"
+	analytics2 "atlas-saga-orchestrator/kafka/message/analytics"
+	"atlas-saga-orchestrator/kafka/producer"
+	"context"
+	"github.com/sirupsen/logrus"
+)
+
+type Processor interface {
+	PublishAndEmit(e analytics2.Event) error
+	Publish(mb *message.Buffer) func(e analytics2.Event) error
+}
+
+type ProcessorImpl struct {
+	l   logrus.FieldLogger
+	ctx context.Context
+	p   producer.Provider
+}
+
+func NewProcessor(l logrus.FieldLogger, ctx context.Context) Processor {
+	return &ProcessorImpl{
+		l:   l,
+		ctx: ctx,
+		p:   producer.ProviderImpl(l)(ctx),
+	}
+}
+
+// PublishAndEmit publishes the analytics event to the analytics topic
+func (p *ProcessorImpl) PublishAndEmit(e analytics2.Event) error {
+	return message.Emit(p.p)(func(mb *message.Buffer) error {
+		return p.Publish(mb)(e)
+	})
+}
+
+func (p *ProcessorImpl) Publish(mb *message.Buffer) func(e analytics2.Event) error {
+	return func(e analytics2.Event) error {
+		return mb.Put(analytics2.EnvEventTopic, EventProvider(e))
+	}
+}
@@ -0,0 +1,13 @@
+package analytics
+
+import (
+	analytics2 "atlas-saga-orchestrator/kafka/message/analytics"
+	"github.com/Chronicle20/atlas-kafka/producer"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/segmentio/kafka-go"
+)
+
+func EventProvider(e analytics2.Event) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(e.TransactionId.ID()))
+	return producer.SingleMessageProvider(key, &e)
+}
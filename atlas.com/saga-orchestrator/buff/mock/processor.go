@@ -0,0 +1,75 @@
+package mock
+
+import (
+	"atlas-saga-orchestrator/buff"
+	"atlas-saga-orchestrator/kafka/message"
+	buff2 "atlas-saga-orchestrator/kafka/message/buff"
+	"github.com/Chronicle20/atlas-constants/channel"
+	"github.com/Chronicle20/atlas-constants/world"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/google/uuid"
+)
+
+// ProcessorMock is a mock implementation of the buff.Processor interface
+type ProcessorMock struct {
+	GetByCharacterIdFunc      func(characterId uint32) ([]buff.Model, error)
+	ByCharacterIdProviderFunc func(characterId uint32) model.Provider[[]buff.Model]
+	CancelAllAndEmitFunc      func(transactionId uuid.UUID, worldId world.Id, channelId channel.Id, characterId uint32) error
+	CancelAllFunc             func(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, channelId channel.Id, characterId uint32) error
+	ApplyAndEmitFunc          func(transactionId uuid.UUID, worldId world.Id, channelId channel.Id, characterId uint32, fromId uint32, sourceId int32, duration int32, changes []buff2.StatChange) error
+	ApplyFunc                 func(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, channelId channel.Id, characterId uint32, fromId uint32, sourceId int32, duration int32, changes []buff2.StatChange) error
+}
+
+// GetByCharacterId is a mock implementation of the buff.Processor.GetByCharacterId method
+func (m *ProcessorMock) GetByCharacterId(characterId uint32) ([]buff.Model, error) {
+	if m.GetByCharacterIdFunc != nil {
+		return m.GetByCharacterIdFunc(characterId)
+	}
+	return []buff.Model{}, nil
+}
+
+// ByCharacterIdProvider is a mock implementation of the buff.Processor.ByCharacterIdProvider method
+func (m *ProcessorMock) ByCharacterIdProvider(characterId uint32) model.Provider[[]buff.Model] {
+	if m.ByCharacterIdProviderFunc != nil {
+		return m.ByCharacterIdProviderFunc(characterId)
+	}
+	return func() ([]buff.Model, error) {
+		return m.GetByCharacterId(characterId)
+	}
+}
+
+// CancelAllAndEmit is a mock implementation of the buff.Processor.CancelAllAndEmit method
+func (m *ProcessorMock) CancelAllAndEmit(transactionId uuid.UUID, worldId world.Id, channelId channel.Id, characterId uint32) error {
+	if m.CancelAllAndEmitFunc != nil {
+		return m.CancelAllAndEmitFunc(transactionId, worldId, channelId, characterId)
+	}
+	return nil
+}
+
+// CancelAll is a mock implementation of the buff.Processor.CancelAll method
+func (m *ProcessorMock) CancelAll(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, channelId channel.Id, characterId uint32) error {
+	if m.CancelAllFunc != nil {
+		return m.CancelAllFunc(mb)
+	}
+	return func(transactionId uuid.UUID, worldId world.Id, channelId channel.Id, characterId uint32) error {
+		return nil
+	}
+}
+
+// ApplyAndEmit is a mock implementation of the buff.Processor.ApplyAndEmit method
+func (m *ProcessorMock) ApplyAndEmit(transactionId uuid.UUID, worldId world.Id, channelId channel.Id, characterId uint32, fromId uint32, sourceId int32, duration int32, changes []buff2.StatChange) error {
+	if m.ApplyAndEmitFunc != nil {
+		return m.ApplyAndEmitFunc(transactionId, worldId, channelId, characterId, fromId, sourceId, duration, changes)
+	}
+	return nil
+}
+
+// Apply is a mock implementation of the buff.Processor.Apply method
+func (m *ProcessorMock) Apply(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, channelId channel.Id, characterId uint32, fromId uint32, sourceId int32, duration int32, changes []buff2.StatChange) error {
+	if m.ApplyFunc != nil {
+		return m.ApplyFunc(mb)
+	}
+	return func(transactionId uuid.UUID, worldId world.Id, channelId channel.Id, characterId uint32, fromId uint32, sourceId int32, duration int32, changes []buff2.StatChange) error {
+		return nil
+	}
+}
@@ -0,0 +1,12 @@
+package mock
+
+import (
+	"atlas-saga-orchestrator/buff"
+	"testing"
+)
+
+// TestProcessorMockImplementsProcessor verifies that ProcessorMock implements the buff.Processor interface
+func TestProcessorMockImplementsProcessor(t *testing.T) {
+	// This test will fail to compile if ProcessorMock doesn't implement buff.Processor
+	var _ buff.Processor = &ProcessorMock{}
+}
@@ -0,0 +1,92 @@
+package buff
+
+import "time"
+
+type Model struct {
+	sourceId  int32
+	duration  int32
+	changes   []StatChange
+	createdAt time.Time
+	expiresAt time.Time
+}
+
+func (m Model) SourceId() int32 {
+	return m.sourceId
+}
+
+func (m Model) Duration() int32 {
+	return m.duration
+}
+
+func (m Model) Changes() []StatChange {
+	return m.changes
+}
+
+func (m Model) CreatedAt() time.Time {
+	return m.createdAt
+}
+
+func (m Model) ExpiresAt() time.Time {
+	return m.expiresAt
+}
+
+type StatChange struct {
+	statType string
+	amount   int32
+}
+
+func (s StatChange) Type() string {
+	return s.statType
+}
+
+func (s StatChange) Amount() int32 {
+	return s.amount
+}
+
+func NewStatChange(statType string, amount int32) StatChange {
+	return StatChange{
+		statType: statType,
+		amount:   amount,
+	}
+}
+
+type ModelBuilder struct {
+	sourceId  int32
+	duration  int32
+	changes   []StatChange
+	createdAt time.Time
+	expiresAt time.Time
+}
+
+func NewBuilder(sourceId int32, duration int32) *ModelBuilder {
+	return &ModelBuilder{
+		sourceId: sourceId,
+		duration: duration,
+		changes:  make([]StatChange, 0),
+	}
+}
+
+func (b *ModelBuilder) AddChange(c StatChange) *ModelBuilder {
+	b.changes = append(b.changes, c)
+	return b
+}
+
+func (b *ModelBuilder) SetCreatedAt(createdAt time.Time) *ModelBuilder {
+	b.createdAt = createdAt
+	return b
+}
+
+func (b *ModelBuilder) SetExpiresAt(expiresAt time.Time) *ModelBuilder {
+	b.expiresAt = expiresAt
+	return b
+}
+
+func (b *ModelBuilder) Build() Model {
+	return Model{
+		sourceId:  b.sourceId,
+		duration:  b.duration,
+		changes:   b.changes,
+		createdAt: b.createdAt,
+		expiresAt: b.expiresAt,
+	}
+}
@@ -0,0 +1,69 @@
+package buff
+
+import (
+	"atlas-saga-orchestrator/kafka/message"
+	buff2 "atlas-saga-orchestrator/kafka/message/buff"
+	"atlas-saga-orchestrator/kafka/producer"
+	"context"
+	"github.com/Chronicle20/atlas-constants/channel"
+	"github.com/Chronicle20/atlas-constants/world"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/Chronicle20/atlas-rest/requests"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type Processor interface {
+	GetByCharacterId(characterId uint32) ([]Model, error)
+	ByCharacterIdProvider(characterId uint32) model.Provider[[]Model]
+	CancelAllAndEmit(transactionId uuid.UUID, worldId world.Id, channelId channel.Id, characterId uint32) error
+	CancelAll(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, channelId channel.Id, characterId uint32) error
+	ApplyAndEmit(transactionId uuid.UUID, worldId world.Id, channelId channel.Id, characterId uint32, fromId uint32, sourceId int32, duration int32, changes []buff2.StatChange) error
+	Apply(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, channelId channel.Id, characterId uint32, fromId uint32, sourceId int32, duration int32, changes []buff2.StatChange) error
+}
+
+type ProcessorImpl struct {
+	l   logrus.FieldLogger
+	ctx context.Context
+	p   producer.Provider
+}
+
+func NewProcessor(l logrus.FieldLogger, ctx context.Context) Processor {
+	return &ProcessorImpl{
+		l:   l,
+		ctx: ctx,
+		p:   producer.ProviderImpl(l)(ctx),
+	}
+}
+
+func (p *ProcessorImpl) GetByCharacterId(characterId uint32) ([]Model, error) {
+	return p.ByCharacterIdProvider(characterId)()
+}
+
+func (p *ProcessorImpl) ByCharacterIdProvider(characterId uint32) model.Provider[[]Model] {
+	return requests.SliceProvider[RestModel, Model](p.l, p.ctx)(requestByCharacterId(characterId), Extract, model.Filters[Model]())
+}
+
+func (p *ProcessorImpl) CancelAllAndEmit(transactionId uuid.UUID, worldId world.Id, channelId channel.Id, characterId uint32) error {
+	return message.Emit(p.p)(func(mb *message.Buffer) error {
+		return p.CancelAll(mb)(transactionId, worldId, channelId, characterId)
+	})
+}
+
+func (p *ProcessorImpl) CancelAll(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, channelId channel.Id, characterId uint32) error {
+	return func(transactionId uuid.UUID, worldId world.Id, channelId channel.Id, characterId uint32) error {
+		return mb.Put(buff2.EnvCommandTopic, CancelAllProvider(transactionId, worldId, channelId, characterId))
+	}
+}
+
+func (p *ProcessorImpl) ApplyAndEmit(transactionId uuid.UUID, worldId world.Id, channelId channel.Id, characterId uint32, fromId uint32, sourceId int32, duration int32, changes []buff2.StatChange) error {
+	return message.Emit(p.p)(func(mb *message.Buffer) error {
+		return p.Apply(mb)(transactionId, worldId, channelId, characterId, fromId, sourceId, duration, changes)
+	})
+}
+
+func (p *ProcessorImpl) Apply(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, channelId channel.Id, characterId uint32, fromId uint32, sourceId int32, duration int32, changes []buff2.StatChange) error {
+	return func(transactionId uuid.UUID, worldId world.Id, channelId channel.Id, characterId uint32, fromId uint32, sourceId int32, duration int32, changes []buff2.StatChange) error {
+		return mb.Put(buff2.EnvCommandTopic, ApplyProvider(transactionId, worldId, channelId, characterId, fromId, sourceId, duration, changes))
+	}
+}
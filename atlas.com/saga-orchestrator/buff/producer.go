@@ -0,0 +1,42 @@
+package buff
+
+import (
+	buff2 "atlas-saga-orchestrator/kafka/message/buff"
+	"github.com/Chronicle20/atlas-constants/channel"
+	"github.com/Chronicle20/atlas-constants/world"
+	"github.com/Chronicle20/atlas-kafka/producer"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+)
+
+func CancelAllProvider(transactionId uuid.UUID, worldId world.Id, channelId channel.Id, characterId uint32) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(characterId))
+	value := &buff2.Command[buff2.CancelAllCommandBody]{
+		TransactionId: transactionId,
+		WorldId:       worldId,
+		ChannelId:     channelId,
+		CharacterId:   characterId,
+		Type:          buff2.CommandTypeCancelAll,
+		Body:          buff2.CancelAllCommandBody{},
+	}
+	return producer.SingleMessageProvider(key, value)
+}
+
+func ApplyProvider(transactionId uuid.UUID, worldId world.Id, channelId channel.Id, characterId uint32, fromId uint32, sourceId int32, duration int32, changes []buff2.StatChange) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(characterId))
+	value := &buff2.Command[buff2.ApplyCommandBody]{
+		TransactionId: transactionId,
+		WorldId:       worldId,
+		ChannelId:     channelId,
+		CharacterId:   characterId,
+		Type:          buff2.CommandTypeApply,
+		Body: buff2.ApplyCommandBody{
+			FromId:   fromId,
+			SourceId: sourceId,
+			Duration: duration,
+			Changes:  changes,
+		},
+	}
+	return producer.SingleMessageProvider(key, value)
+}
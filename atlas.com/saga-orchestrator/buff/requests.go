@@ -0,0 +1,19 @@
+package buff
+
+import (
+	"atlas-saga-orchestrator/rest"
+	"fmt"
+	"github.com/Chronicle20/atlas-rest/requests"
+)
+
+const (
+	buffsForCharacter = "characters/%d/buffs"
+)
+
+func getBaseRequest() string {
+	return requests.RootUrl("BUFFS")
+}
+
+func requestByCharacterId(characterId uint32) requests.Request[[]RestModel] {
+	return rest.MakeGetRequest[[]RestModel](fmt.Sprintf(getBaseRequest()+buffsForCharacter, characterId))
+}
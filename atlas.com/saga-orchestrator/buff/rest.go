@@ -0,0 +1,60 @@
+package buff
+
+import (
+	"strconv"
+	"time"
+)
+
+type RestModel struct {
+	Id        string           `json:"-"`
+	SourceId  int32            `json:"sourceId"`
+	Duration  int32            `json:"duration"`
+	Changes   []StatChangeRest `json:"changes"`
+	CreatedAt time.Time        `json:"createdAt"`
+	ExpiresAt time.Time        `json:"expiresAt"`
+}
+
+type StatChangeRest struct {
+	Type   string `json:"type"`
+	Amount int32  `json:"amount"`
+}
+
+func (r RestModel) GetName() string {
+	return "buffs"
+}
+
+func (r RestModel) GetID() string {
+	return r.Id
+}
+
+func (r *RestModel) SetID(id string) error {
+	r.Id = id
+	return nil
+}
+
+func Extract(rm RestModel) (Model, error) {
+	sourceId := rm.SourceId
+	if sourceId == 0 && rm.Id != "" {
+		id, err := strconv.ParseInt(rm.Id, 10, 32)
+		if err != nil {
+			return Model{}, err
+		}
+		sourceId = int32(id)
+	}
+
+	changes := make([]StatChange, 0, len(rm.Changes))
+	for _, c := range rm.Changes {
+		changes = append(changes, StatChange{
+			statType: c.Type,
+			amount:   c.Amount,
+		})
+	}
+
+	return Model{
+		sourceId:  sourceId,
+		duration:  rm.Duration,
+		changes:   changes,
+		createdAt: rm.CreatedAt,
+		expiresAt: rm.ExpiresAt,
+	}, nil
+}
@@ -1,6 +1,7 @@
 package mock
 
 import (
+	"atlas-saga-orchestrator/character"
 	"atlas-saga-orchestrator/kafka/message"
 	character2 "atlas-saga-orchestrator/kafka/message/character"
 	"github.com/Chronicle20/atlas-constants/channel"
@@ -14,19 +15,38 @@ import (
 
 // ProcessorMock is a mock implementation of the character.Processor interface
 type ProcessorMock struct {
-	WarpRandomAndEmitFunc      func(transactionId uuid.UUID, characterId uint32, field field.Model) error
-	WarpRandomFunc             func(mb *message.Buffer) func(transactionId uuid.UUID, characterId uint32, field field.Model) error
-	WarpToPortalAndEmitFunc    func(transactionId uuid.UUID, characterId uint32, field field.Model, pp model.Provider[uint32]) error
-	WarpToPortalFunc           func(mb *message.Buffer) func(transactionId uuid.UUID, characterId uint32, field field.Model, pp model.Provider[uint32]) error
-	AwardExperienceAndEmitFunc func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, distributions []character2.ExperienceDistributions) error
-	AwardExperienceFunc        func(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, distributions []character2.ExperienceDistributions) error
-	AwardLevelAndEmitFunc      func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, amount byte) error
-	AwardLevelFunc             func(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, amount byte) error
-	AwardMesosAndEmitFunc      func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, actorId uint32, actorType string, amount int32) error
-	AwardMesosFunc             func(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, actorId uint32, actorType string, amount int32) error
-	ChangeJobAndEmitFunc       func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, jobId job.Id) error
-	ChangeJobFunc              func(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, jobId job.Id) error
-	RequestCreateCharacterFunc func(transactionId uuid.UUID, accountId uint32, worldId byte, name string, level byte, strength uint16, dexterity uint16, intelligence uint16, luck uint16, hp uint16, mp uint16, jobId job.Id, gender byte, face uint32, hair uint32, skin byte, mapId _map.Id) error
+	WarpRandomAndEmitFunc         func(transactionId uuid.UUID, characterId uint32, field field.Model) error
+	WarpRandomFunc                func(mb *message.Buffer) func(transactionId uuid.UUID, characterId uint32, field field.Model) error
+	WarpToPortalAndEmitFunc       func(transactionId uuid.UUID, characterId uint32, field field.Model, pp model.Provider[uint32]) error
+	WarpToPortalFunc              func(mb *message.Buffer) func(transactionId uuid.UUID, characterId uint32, field field.Model, pp model.Provider[uint32]) error
+	AwardExperienceAndEmitFunc    func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, distributions []character2.ExperienceDistributions) error
+	AwardExperienceFunc           func(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, distributions []character2.ExperienceDistributions) error
+	AwardLevelAndEmitFunc         func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, amount byte) error
+	AwardLevelFunc                func(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, amount byte) error
+	AwardMesosAndEmitFunc         func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, actorId uint32, actorType string, amount int32) error
+	AwardMesosFunc                func(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, actorId uint32, actorType string, amount int32) error
+	AwardFameAndEmitFunc          func(transactionId uuid.UUID, worldId world.Id, characterId uint32, actorId uint32, actorType string, amount int8) error
+	AwardFameFunc                 func(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, actorId uint32, actorType string, amount int8) error
+	ChangeJobAndEmitFunc          func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, jobId job.Id) error
+	ChangeJobFunc                 func(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, jobId job.Id) error
+	ChangeAccountAndEmitFunc      func(transactionId uuid.UUID, worldId world.Id, characterId uint32, accountId uint32) error
+	ChangeAccountFunc             func(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, accountId uint32) error
+	RollbackToSnapshotAndEmitFunc func(transactionId uuid.UUID, worldId world.Id, characterId uint32, snapshotId uint32) error
+	RollbackToSnapshotFunc        func(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, snapshotId uint32) error
+	DeductExperienceAndEmitFunc   func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, amount uint32) error
+	DeductExperienceFunc          func(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, amount uint32) error
+	GetByIdFunc                   func(characterId uint32) (character.Model, error)
+	RestoreStatsAndEmitFunc       func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, m character.Model) error
+	RestoreStatsFunc              func(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, m character.Model) error
+	GetResourcesFunc              func(characterId uint32) ([]character.Resource, error)
+	ChangeResourceAndEmitFunc     func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, resource string, value int32) error
+	ChangeResourceFunc            func(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, resource string, value int32) error
+	LockExperienceAndEmitFunc     func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, reason string) error
+	LockExperienceFunc            func(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, reason string) error
+	UnlockExperienceAndEmitFunc   func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id) error
+	UnlockExperienceFunc          func(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id) error
+	RequestCreateCharacterFunc    func(transactionId uuid.UUID, accountId uint32, worldId byte, name string, level byte, strength uint16, dexterity uint16, intelligence uint16, luck uint16, hp uint16, mp uint16, jobId job.Id, gender byte, face uint32, hair uint32, skin byte, mapId _map.Id) error
+	RequestDeleteCharacterFunc    func(transactionId uuid.UUID, accountId uint32, worldId world.Id, characterId uint32) error
 }
 
 // WarpRandomAndEmit is a mock implementation of the character.Processor.WarpRandomAndEmit method
@@ -119,6 +139,24 @@ func (m *ProcessorMock) AwardMesos(mb *message.Buffer) func(transactionId uuid.U
 	}
 }
 
+// AwardFameAndEmit is a mock implementation of the character.Processor.AwardFameAndEmit method
+func (m *ProcessorMock) AwardFameAndEmit(transactionId uuid.UUID, worldId world.Id, characterId uint32, actorId uint32, actorType string, amount int8) error {
+	if m.AwardFameAndEmitFunc != nil {
+		return m.AwardFameAndEmitFunc(transactionId, worldId, characterId, actorId, actorType, amount)
+	}
+	return nil
+}
+
+// AwardFame is a mock implementation of the character.Processor.AwardFame method
+func (m *ProcessorMock) AwardFame(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, actorId uint32, actorType string, amount int8) error {
+	if m.AwardFameFunc != nil {
+		return m.AwardFameFunc(mb)
+	}
+	return func(transactionId uuid.UUID, worldId world.Id, characterId uint32, actorId uint32, actorType string, amount int8) error {
+		return nil
+	}
+}
+
 // ChangeJobAndEmit is a mock implementation of the character.Processor.ChangeJobAndEmit method
 func (m *ProcessorMock) ChangeJobAndEmit(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, jobId job.Id) error {
 	if m.ChangeJobAndEmitFunc != nil {
@@ -144,3 +182,153 @@ func (m *ProcessorMock) RequestCreateCharacter(transactionId uuid.UUID, accountI
 	}
 	return nil
 }
+
+// RequestDeleteCharacter is a mock implementation of the character.Processor.RequestDeleteCharacter method
+func (m *ProcessorMock) RequestDeleteCharacter(transactionId uuid.UUID, accountId uint32, worldId world.Id, characterId uint32) error {
+	if m.RequestDeleteCharacterFunc != nil {
+		return m.RequestDeleteCharacterFunc(transactionId, accountId, worldId, characterId)
+	}
+	return nil
+}
+
+// ChangeAccountAndEmit is a mock implementation of the character.Processor.ChangeAccountAndEmit method
+func (m *ProcessorMock) ChangeAccountAndEmit(transactionId uuid.UUID, worldId world.Id, characterId uint32, accountId uint32) error {
+	if m.ChangeAccountAndEmitFunc != nil {
+		return m.ChangeAccountAndEmitFunc(transactionId, worldId, characterId, accountId)
+	}
+	return nil
+}
+
+// ChangeAccount is a mock implementation of the character.Processor.ChangeAccount method
+func (m *ProcessorMock) ChangeAccount(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, accountId uint32) error {
+	if m.ChangeAccountFunc != nil {
+		return m.ChangeAccountFunc(mb)
+	}
+	return func(transactionId uuid.UUID, worldId world.Id, characterId uint32, accountId uint32) error {
+		return nil
+	}
+}
+
+// RollbackToSnapshotAndEmit is a mock implementation of the character.Processor.RollbackToSnapshotAndEmit method
+func (m *ProcessorMock) RollbackToSnapshotAndEmit(transactionId uuid.UUID, worldId world.Id, characterId uint32, snapshotId uint32) error {
+	if m.RollbackToSnapshotAndEmitFunc != nil {
+		return m.RollbackToSnapshotAndEmitFunc(transactionId, worldId, characterId, snapshotId)
+	}
+	return nil
+}
+
+// RollbackToSnapshot is a mock implementation of the character.Processor.RollbackToSnapshot method
+func (m *ProcessorMock) RollbackToSnapshot(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, snapshotId uint32) error {
+	if m.RollbackToSnapshotFunc != nil {
+		return m.RollbackToSnapshotFunc(mb)
+	}
+	return func(transactionId uuid.UUID, worldId world.Id, characterId uint32, snapshotId uint32) error {
+		return nil
+	}
+}
+
+// DeductExperienceAndEmit is a mock implementation of the character.Processor.DeductExperienceAndEmit method
+func (m *ProcessorMock) DeductExperienceAndEmit(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, amount uint32) error {
+	if m.DeductExperienceAndEmitFunc != nil {
+		return m.DeductExperienceAndEmitFunc(transactionId, worldId, characterId, channelId, amount)
+	}
+	return nil
+}
+
+// DeductExperience is a mock implementation of the character.Processor.DeductExperience method
+func (m *ProcessorMock) DeductExperience(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, amount uint32) error {
+	if m.DeductExperienceFunc != nil {
+		return m.DeductExperienceFunc(mb)
+	}
+	return func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, amount uint32) error {
+		return nil
+	}
+}
+
+// GetById is a mock implementation of the character.Processor.GetById method
+func (m *ProcessorMock) GetById(characterId uint32) (character.Model, error) {
+	if m.GetByIdFunc != nil {
+		return m.GetByIdFunc(characterId)
+	}
+	return character.NewModelBuilder(characterId).Build(), nil
+}
+
+// RestoreStatsAndEmit is a mock implementation of the character.Processor.RestoreStatsAndEmit method
+func (m *ProcessorMock) RestoreStatsAndEmit(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, c character.Model) error {
+	if m.RestoreStatsAndEmitFunc != nil {
+		return m.RestoreStatsAndEmitFunc(transactionId, worldId, characterId, channelId, c)
+	}
+	return nil
+}
+
+// RestoreStats is a mock implementation of the character.Processor.RestoreStats method
+func (m *ProcessorMock) RestoreStats(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, c character.Model) error {
+	if m.RestoreStatsFunc != nil {
+		return m.RestoreStatsFunc(mb)
+	}
+	return func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, c character.Model) error {
+		return nil
+	}
+}
+
+// GetResources is a mock implementation of the character.Processor.GetResources method
+func (m *ProcessorMock) GetResources(characterId uint32) ([]character.Resource, error) {
+	if m.GetResourcesFunc != nil {
+		return m.GetResourcesFunc(characterId)
+	}
+	return []character.Resource{}, nil
+}
+
+// ChangeResourceAndEmit is a mock implementation of the character.Processor.ChangeResourceAndEmit method
+func (m *ProcessorMock) ChangeResourceAndEmit(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, resource string, value int32) error {
+	if m.ChangeResourceAndEmitFunc != nil {
+		return m.ChangeResourceAndEmitFunc(transactionId, worldId, characterId, channelId, resource, value)
+	}
+	return nil
+}
+
+// ChangeResource is a mock implementation of the character.Processor.ChangeResource method
+func (m *ProcessorMock) ChangeResource(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, resource string, value int32) error {
+	if m.ChangeResourceFunc != nil {
+		return m.ChangeResourceFunc(mb)
+	}
+	return func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, resource string, value int32) error {
+		return nil
+	}
+}
+
+// LockExperienceAndEmit is a mock implementation of the character.Processor.LockExperienceAndEmit method
+func (m *ProcessorMock) LockExperienceAndEmit(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, reason string) error {
+	if m.LockExperienceAndEmitFunc != nil {
+		return m.LockExperienceAndEmitFunc(transactionId, worldId, characterId, channelId, reason)
+	}
+	return nil
+}
+
+// LockExperience is a mock implementation of the character.Processor.LockExperience method
+func (m *ProcessorMock) LockExperience(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, reason string) error {
+	if m.LockExperienceFunc != nil {
+		return m.LockExperienceFunc(mb)
+	}
+	return func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, reason string) error {
+		return nil
+	}
+}
+
+// UnlockExperienceAndEmit is a mock implementation of the character.Processor.UnlockExperienceAndEmit method
+func (m *ProcessorMock) UnlockExperienceAndEmit(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id) error {
+	if m.UnlockExperienceAndEmitFunc != nil {
+		return m.UnlockExperienceAndEmitFunc(transactionId, worldId, characterId, channelId)
+	}
+	return nil
+}
+
+// UnlockExperience is a mock implementation of the character.Processor.UnlockExperience method
+func (m *ProcessorMock) UnlockExperience(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id) error {
+	if m.UnlockExperienceFunc != nil {
+		return m.UnlockExperienceFunc(mb)
+	}
+	return func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id) error {
+		return nil
+	}
+}
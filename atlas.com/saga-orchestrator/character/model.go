@@ -0,0 +1,205 @@
+package character
+
+import "github.com/Chronicle20/atlas-constants/job"
+
+// Resource is the value of one of a character's alternate resources, such as energy charge or a combo counter
+type Resource struct {
+	resourceType string
+	value        int32
+}
+
+func (r Resource) Type() string {
+	return r.resourceType
+}
+
+func (r Resource) Value() int32 {
+	return r.value
+}
+
+func NewResource(resourceType string, value int32) Resource {
+	return Resource{
+		resourceType: resourceType,
+		value:        value,
+	}
+}
+
+// Model is a character's progression and stats, being the state a snapshot captures and a restore reinstates
+type Model struct {
+	id           uint32
+	jobId        job.Id
+	level        byte
+	experience   uint32
+	meso         uint32
+	strength     uint16
+	dexterity    uint16
+	intelligence uint16
+	luck         uint16
+	hp           uint16
+	maxHp        uint16
+	mp           uint16
+	maxMp        uint16
+	ap           uint16
+}
+
+func (m Model) Id() uint32 {
+	return m.id
+}
+
+func (m Model) JobId() job.Id {
+	return m.jobId
+}
+
+func (m Model) Level() byte {
+	return m.level
+}
+
+func (m Model) Experience() uint32 {
+	return m.experience
+}
+
+func (m Model) Meso() uint32 {
+	return m.meso
+}
+
+func (m Model) Strength() uint16 {
+	return m.strength
+}
+
+func (m Model) Dexterity() uint16 {
+	return m.dexterity
+}
+
+func (m Model) Intelligence() uint16 {
+	return m.intelligence
+}
+
+func (m Model) Luck() uint16 {
+	return m.luck
+}
+
+func (m Model) Hp() uint16 {
+	return m.hp
+}
+
+func (m Model) MaxHp() uint16 {
+	return m.maxHp
+}
+
+func (m Model) Mp() uint16 {
+	return m.mp
+}
+
+func (m Model) MaxMp() uint16 {
+	return m.maxMp
+}
+
+func (m Model) Ap() uint16 {
+	return m.ap
+}
+
+type ModelBuilder struct {
+	id           uint32
+	jobId        job.Id
+	level        byte
+	experience   uint32
+	meso         uint32
+	strength     uint16
+	dexterity    uint16
+	intelligence uint16
+	luck         uint16
+	hp           uint16
+	maxHp        uint16
+	mp           uint16
+	maxMp        uint16
+	ap           uint16
+}
+
+func NewModelBuilder(id uint32) *ModelBuilder {
+	return &ModelBuilder{
+		id: id,
+	}
+}
+
+func (b *ModelBuilder) SetJobId(jobId job.Id) *ModelBuilder {
+	b.jobId = jobId
+	return b
+}
+
+func (b *ModelBuilder) SetLevel(level byte) *ModelBuilder {
+	b.level = level
+	return b
+}
+
+func (b *ModelBuilder) SetExperience(experience uint32) *ModelBuilder {
+	b.experience = experience
+	return b
+}
+
+func (b *ModelBuilder) SetMeso(meso uint32) *ModelBuilder {
+	b.meso = meso
+	return b
+}
+
+func (b *ModelBuilder) SetStrength(strength uint16) *ModelBuilder {
+	b.strength = strength
+	return b
+}
+
+func (b *ModelBuilder) SetDexterity(dexterity uint16) *ModelBuilder {
+	b.dexterity = dexterity
+	return b
+}
+
+func (b *ModelBuilder) SetIntelligence(intelligence uint16) *ModelBuilder {
+	b.intelligence = intelligence
+	return b
+}
+
+func (b *ModelBuilder) SetLuck(luck uint16) *ModelBuilder {
+	b.luck = luck
+	return b
+}
+
+func (b *ModelBuilder) SetHp(hp uint16) *ModelBuilder {
+	b.hp = hp
+	return b
+}
+
+func (b *ModelBuilder) SetMaxHp(maxHp uint16) *ModelBuilder {
+	b.maxHp = maxHp
+	return b
+}
+
+func (b *ModelBuilder) SetMp(mp uint16) *ModelBuilder {
+	b.mp = mp
+	return b
+}
+
+func (b *ModelBuilder) SetMaxMp(maxMp uint16) *ModelBuilder {
+	b.maxMp = maxMp
+	return b
+}
+
+func (b *ModelBuilder) SetAp(ap uint16) *ModelBuilder {
+	b.ap = ap
+	return b
+}
+
+func (b *ModelBuilder) Build() Model {
+	return Model{
+		id:           b.id,
+		jobId:        b.jobId,
+		level:        b.level,
+		experience:   b.experience,
+		meso:         b.meso,
+		strength:     b.strength,
+		dexterity:    b.dexterity,
+		intelligence: b.intelligence,
+		luck:         b.luck,
+		hp:           b.hp,
+		maxHp:        b.maxHp,
+		mp:           b.mp,
+		maxMp:        b.maxMp,
+		ap:           b.ap,
+	}
+}
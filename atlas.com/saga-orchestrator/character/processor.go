@@ -12,6 +12,7 @@ import (
 	_map "github.com/Chronicle20/atlas-constants/map"
 	"github.com/Chronicle20/atlas-constants/world"
 	"github.com/Chronicle20/atlas-model/model"
+	"github.com/Chronicle20/atlas-rest/requests"
 	tenant "github.com/Chronicle20/atlas-tenant"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
@@ -28,9 +29,28 @@ type Processor interface {
 	AwardLevel(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, amount byte) error
 	AwardMesosAndEmit(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, actorId uint32, actorType string, amount int32) error
 	AwardMesos(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, actorId uint32, actorType string, amount int32) error
+	AwardFameAndEmit(transactionId uuid.UUID, worldId world.Id, characterId uint32, actorId uint32, actorType string, amount int8) error
+	AwardFame(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, actorId uint32, actorType string, amount int8) error
 	ChangeJobAndEmit(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, jobId job.Id) error
 	ChangeJob(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, jobId job.Id) error
+	ChangeAccountAndEmit(transactionId uuid.UUID, worldId world.Id, characterId uint32, accountId uint32) error
+	ChangeAccount(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, accountId uint32) error
+	RollbackToSnapshotAndEmit(transactionId uuid.UUID, worldId world.Id, characterId uint32, snapshotId uint32) error
+	RollbackToSnapshot(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, snapshotId uint32) error
+	DeductExperienceAndEmit(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, amount uint32) error
+	DeductExperience(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, amount uint32) error
+	GetById(characterId uint32) (Model, error)
+	RestoreStatsAndEmit(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, m Model) error
+	RestoreStats(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, m Model) error
+	GetResources(characterId uint32) ([]Resource, error)
+	ChangeResourceAndEmit(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, resource string, value int32) error
+	ChangeResource(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, resource string, value int32) error
+	LockExperienceAndEmit(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, reason string) error
+	LockExperience(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, reason string) error
+	UnlockExperienceAndEmit(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id) error
+	UnlockExperience(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id) error
 	RequestCreateCharacter(transactionId uuid.UUID, accountId uint32, worldId byte, name string, level byte, strength uint16, dexterity uint16, intelligence uint16, luck uint16, hp uint16, mp uint16, jobId job.Id, gender byte, face uint32, hair uint32, skin byte, mapId _map.Id) error
+	RequestDeleteCharacter(transactionId uuid.UUID, accountId uint32, worldId world.Id, characterId uint32) error
 }
 
 type ProcessorImpl struct {
@@ -115,6 +135,18 @@ func (p *ProcessorImpl) AwardMesos(mb *message.Buffer) func(transactionId uuid.U
 	}
 }
 
+func (p *ProcessorImpl) AwardFameAndEmit(transactionId uuid.UUID, worldId world.Id, characterId uint32, actorId uint32, actorType string, amount int8) error {
+	return message.Emit(p.p)(func(mb *message.Buffer) error {
+		return p.AwardFame(mb)(transactionId, worldId, characterId, actorId, actorType, amount)
+	})
+}
+
+func (p *ProcessorImpl) AwardFame(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, actorId uint32, actorType string, amount int8) error {
+	return func(transactionId uuid.UUID, worldId world.Id, characterId uint32, actorId uint32, actorType string, amount int8) error {
+		return mb.Put(character2.EnvCommandTopic, AwardFameProvider(transactionId, worldId, characterId, actorId, actorType, amount))
+	}
+}
+
 func (p *ProcessorImpl) ChangeJobAndEmit(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, jobId job.Id) error {
 	return message.Emit(p.p)(func(mb *message.Buffer) error {
 		return p.ChangeJob(mb)(transactionId, worldId, characterId, channelId, jobId)
@@ -127,8 +159,108 @@ func (p *ProcessorImpl) ChangeJob(mb *message.Buffer) func(transactionId uuid.UU
 	}
 }
 
+func (p *ProcessorImpl) ChangeAccountAndEmit(transactionId uuid.UUID, worldId world.Id, characterId uint32, accountId uint32) error {
+	return message.Emit(p.p)(func(mb *message.Buffer) error {
+		return p.ChangeAccount(mb)(transactionId, worldId, characterId, accountId)
+	})
+}
+
+func (p *ProcessorImpl) ChangeAccount(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, accountId uint32) error {
+	return func(transactionId uuid.UUID, worldId world.Id, characterId uint32, accountId uint32) error {
+		return mb.Put(character2.EnvCommandTopic, ChangeAccountProvider(transactionId, worldId, characterId, accountId))
+	}
+}
+
+func (p *ProcessorImpl) RollbackToSnapshotAndEmit(transactionId uuid.UUID, worldId world.Id, characterId uint32, snapshotId uint32) error {
+	return message.Emit(p.p)(func(mb *message.Buffer) error {
+		return p.RollbackToSnapshot(mb)(transactionId, worldId, characterId, snapshotId)
+	})
+}
+
+func (p *ProcessorImpl) RollbackToSnapshot(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, snapshotId uint32) error {
+	return func(transactionId uuid.UUID, worldId world.Id, characterId uint32, snapshotId uint32) error {
+		return mb.Put(character2.EnvCommandTopic, RollbackToSnapshotProvider(transactionId, worldId, characterId, snapshotId))
+	}
+}
+
+func (p *ProcessorImpl) DeductExperienceAndEmit(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, amount uint32) error {
+	return message.Emit(p.p)(func(mb *message.Buffer) error {
+		return p.DeductExperience(mb)(transactionId, worldId, characterId, channelId, amount)
+	})
+}
+
+func (p *ProcessorImpl) DeductExperience(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, amount uint32) error {
+	return func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, amount uint32) error {
+		return mb.Put(character2.EnvCommandTopic, DeductExperienceProvider(transactionId, worldId, characterId, channelId, amount))
+	}
+}
+
 func (p *ProcessorImpl) RequestCreateCharacter(transactionId uuid.UUID, accountId uint32, worldId byte, name string, level byte, strength uint16, dexterity uint16, intelligence uint16, luck uint16, hp uint16, mp uint16, jobId job.Id, gender byte, face uint32, hair uint32, skin byte, mapId _map.Id) error {
 	return message.Emit(p.p)(func(mb *message.Buffer) error {
 		return mb.Put(character2.EnvCommandTopic, RequestCreateCharacterProvider(transactionId, accountId, worldId, name, level, strength, dexterity, intelligence, luck, hp, mp, jobId, gender, face, hair, skin, mapId))
 	})
 }
+
+// RequestDeleteCharacter requests the character service delete a character of the account, as when rolling back the
+// onboarding saga which created it
+func (p *ProcessorImpl) RequestDeleteCharacter(transactionId uuid.UUID, accountId uint32, worldId world.Id, characterId uint32) error {
+	return message.Emit(p.p)(func(mb *message.Buffer) error {
+		return mb.Put(character2.EnvCommandTopic, RequestDeleteCharacterProvider(transactionId, accountId, worldId, characterId))
+	})
+}
+
+func (p *ProcessorImpl) GetById(characterId uint32) (Model, error) {
+	return requests.Provider[RestModel, Model](p.l, p.ctx)(requestById(characterId), Extract)()
+}
+
+func (p *ProcessorImpl) RestoreStatsAndEmit(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, m Model) error {
+	return message.Emit(p.p)(func(mb *message.Buffer) error {
+		return p.RestoreStats(mb)(transactionId, worldId, characterId, channelId, m)
+	})
+}
+
+func (p *ProcessorImpl) RestoreStats(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, m Model) error {
+	return func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, m Model) error {
+		return mb.Put(character2.EnvCommandTopic, RestoreStatsProvider(transactionId, worldId, characterId, channelId, m))
+	}
+}
+
+func (p *ProcessorImpl) GetResources(characterId uint32) ([]Resource, error) {
+	return requests.SliceProvider[ResourceRestModel, Resource](p.l, p.ctx)(requestResourcesByCharacterId(characterId), ExtractResource, model.Filters[Resource]())()
+}
+
+func (p *ProcessorImpl) ChangeResourceAndEmit(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, resource string, value int32) error {
+	return message.Emit(p.p)(func(mb *message.Buffer) error {
+		return p.ChangeResource(mb)(transactionId, worldId, characterId, channelId, resource, value)
+	})
+}
+
+func (p *ProcessorImpl) ChangeResource(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, resource string, value int32) error {
+	return func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, resource string, value int32) error {
+		return mb.Put(character2.EnvCommandTopic, ChangeResourceProvider(transactionId, worldId, characterId, channelId, resource, value))
+	}
+}
+
+func (p *ProcessorImpl) LockExperienceAndEmit(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, reason string) error {
+	return message.Emit(p.p)(func(mb *message.Buffer) error {
+		return p.LockExperience(mb)(transactionId, worldId, characterId, channelId, reason)
+	})
+}
+
+func (p *ProcessorImpl) LockExperience(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, reason string) error {
+	return func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, reason string) error {
+		return mb.Put(character2.EnvCommandTopic, LockExperienceProvider(transactionId, worldId, characterId, channelId, reason))
+	}
+}
+
+func (p *ProcessorImpl) UnlockExperienceAndEmit(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id) error {
+	return message.Emit(p.p)(func(mb *message.Buffer) error {
+		return p.UnlockExperience(mb)(transactionId, worldId, characterId, channelId)
+	})
+}
+
+func (p *ProcessorImpl) UnlockExperience(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id) error {
+	return func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id) error {
+		return mb.Put(character2.EnvCommandTopic, UnlockExperienceProvider(transactionId, worldId, characterId, channelId))
+	}
+}
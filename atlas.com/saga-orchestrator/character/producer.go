@@ -75,6 +75,22 @@ func AwardMesosProvider(transactionId uuid.UUID, worldId world.Id, characterId u
 	return producer.SingleMessageProvider(key, value)
 }
 
+func AwardFameProvider(transactionId uuid.UUID, worldId world.Id, characterId uint32, actorId uint32, actorType string, amount int8) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(characterId))
+	value := &character2.Command[character2.RequestChangeFameBody]{
+		TransactionId: transactionId,
+		WorldId:       worldId,
+		CharacterId:   characterId,
+		Type:          character2.CommandRequestChangeFame,
+		Body: character2.RequestChangeFameBody{
+			ActorId:   actorId,
+			ActorType: actorType,
+			Amount:    amount,
+		},
+	}
+	return producer.SingleMessageProvider(key, value)
+}
+
 func ChangeJobProvider(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, jobId job.Id) model.Provider[[]kafka.Message] {
 	key := producer.CreateKey(int(characterId))
 	value := &character2.Command[character2.ChangeJobCommandBody]{
@@ -90,6 +106,49 @@ func ChangeJobProvider(transactionId uuid.UUID, worldId world.Id, characterId ui
 	return producer.SingleMessageProvider(key, value)
 }
 
+func ChangeAccountProvider(transactionId uuid.UUID, worldId world.Id, characterId uint32, accountId uint32) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(characterId))
+	value := &character2.Command[character2.ChangeAccountCommandBody]{
+		TransactionId: transactionId,
+		WorldId:       worldId,
+		CharacterId:   characterId,
+		Type:          character2.CommandChangeAccount,
+		Body: character2.ChangeAccountCommandBody{
+			AccountId: accountId,
+		},
+	}
+	return producer.SingleMessageProvider(key, value)
+}
+
+func RollbackToSnapshotProvider(transactionId uuid.UUID, worldId world.Id, characterId uint32, snapshotId uint32) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(characterId))
+	value := &character2.Command[character2.RollbackToSnapshotCommandBody]{
+		TransactionId: transactionId,
+		WorldId:       worldId,
+		CharacterId:   characterId,
+		Type:          character2.CommandRollbackToSnapshot,
+		Body: character2.RollbackToSnapshotCommandBody{
+			SnapshotId: snapshotId,
+		},
+	}
+	return producer.SingleMessageProvider(key, value)
+}
+
+func DeductExperienceProvider(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, amount uint32) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(characterId))
+	value := &character2.Command[character2.DeductExperienceCommandBody]{
+		TransactionId: transactionId,
+		WorldId:       worldId,
+		CharacterId:   characterId,
+		Type:          character2.CommandDeductExperience,
+		Body: character2.DeductExperienceCommandBody{
+			ChannelId: channelId,
+			Amount:    amount,
+		},
+	}
+	return producer.SingleMessageProvider(key, value)
+}
+
 func RequestCreateCharacterProvider(transactionId uuid.UUID, accountId uint32, worldId byte, name string, level byte, strength uint16, dexterity uint16, intelligence uint16, luck uint16, hp uint16, mp uint16, jobId job.Id, gender byte, face uint32, hair uint32, skin byte, mapId _map.Id) model.Provider[[]kafka.Message] {
 	key := producer.CreateKey(int(accountId))
 	value := &character2.Command[character2.CreateCharacterCommandBody]{
@@ -118,3 +177,88 @@ func RequestCreateCharacterProvider(transactionId uuid.UUID, accountId uint32, w
 	}
 	return producer.SingleMessageProvider(key, value)
 }
+
+func RequestDeleteCharacterProvider(transactionId uuid.UUID, accountId uint32, worldId world.Id, characterId uint32) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(accountId))
+	value := &character2.Command[character2.DeleteCharacterCommandBody]{
+		TransactionId: transactionId,
+		WorldId:       worldId,
+		CharacterId:   characterId,
+		Type:          character2.CommandDeleteCharacter,
+		Body: character2.DeleteCharacterCommandBody{
+			AccountId: accountId,
+		},
+	}
+	return producer.SingleMessageProvider(key, value)
+}
+
+func ChangeResourceProvider(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, resource string, resourceValue int32) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(characterId))
+	value := &character2.Command[character2.ChangeResourceCommandBody]{
+		TransactionId: transactionId,
+		WorldId:       worldId,
+		CharacterId:   characterId,
+		Type:          character2.CommandChangeResource,
+		Body: character2.ChangeResourceCommandBody{
+			ChannelId: channelId,
+			Resource:  resource,
+			Value:     resourceValue,
+		},
+	}
+	return producer.SingleMessageProvider(key, value)
+}
+
+func LockExperienceProvider(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, reason string) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(characterId))
+	value := &character2.Command[character2.LockExperienceCommandBody]{
+		TransactionId: transactionId,
+		WorldId:       worldId,
+		CharacterId:   characterId,
+		Type:          character2.CommandLockExperience,
+		Body: character2.LockExperienceCommandBody{
+			ChannelId: channelId,
+			Reason:    reason,
+		},
+	}
+	return producer.SingleMessageProvider(key, value)
+}
+
+func UnlockExperienceProvider(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(characterId))
+	value := &character2.Command[character2.UnlockExperienceCommandBody]{
+		TransactionId: transactionId,
+		WorldId:       worldId,
+		CharacterId:   characterId,
+		Type:          character2.CommandUnlockExperience,
+		Body: character2.UnlockExperienceCommandBody{
+			ChannelId: channelId,
+		},
+	}
+	return producer.SingleMessageProvider(key, value)
+}
+
+func RestoreStatsProvider(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, m Model) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(characterId))
+	value := &character2.Command[character2.RestoreStatsCommandBody]{
+		TransactionId: transactionId,
+		WorldId:       worldId,
+		CharacterId:   characterId,
+		Type:          character2.CommandRestoreStats,
+		Body: character2.RestoreStatsCommandBody{
+			ChannelId:    channelId,
+			Level:        m.Level(),
+			Experience:   m.Experience(),
+			Meso:         m.Meso(),
+			Strength:     m.Strength(),
+			Dexterity:    m.Dexterity(),
+			Intelligence: m.Intelligence(),
+			Luck:         m.Luck(),
+			Hp:           m.Hp(),
+			MaxHp:        m.MaxHp(),
+			Mp:           m.Mp(),
+			MaxMp:        m.MaxMp(),
+			Ap:           m.Ap(),
+		},
+	}
+	return producer.SingleMessageProvider(key, value)
+}
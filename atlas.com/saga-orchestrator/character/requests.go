@@ -0,0 +1,24 @@
+package character
+
+import (
+	"atlas-saga-orchestrator/rest"
+	"fmt"
+	"github.com/Chronicle20/atlas-rest/requests"
+)
+
+const (
+	characterById         = "characters/%d"
+	resourcesForCharacter = "characters/%d/resources"
+)
+
+func getBaseRequest() string {
+	return requests.RootUrl("CHARACTERS")
+}
+
+func requestResourcesByCharacterId(characterId uint32) requests.Request[[]ResourceRestModel] {
+	return rest.MakeGetRequest[[]ResourceRestModel](fmt.Sprintf(getBaseRequest()+resourcesForCharacter, characterId))
+}
+
+func requestById(characterId uint32) requests.Request[RestModel] {
+	return rest.MakeGetRequest[RestModel](fmt.Sprintf(getBaseRequest()+characterById, characterId))
+}
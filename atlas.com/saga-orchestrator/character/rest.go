@@ -0,0 +1,88 @@
+package character
+
+import (
+	"strconv"
+
+	"github.com/Chronicle20/atlas-constants/job"
+)
+
+// ResourceRestModel is the value of one of a character's alternate resources, identified by its type
+type ResourceRestModel struct {
+	Id    string `json:"-"`
+	Value int32  `json:"value"`
+}
+
+func (r ResourceRestModel) GetName() string {
+	return "resources"
+}
+
+func (r ResourceRestModel) GetID() string {
+	return r.Id
+}
+
+func (r *ResourceRestModel) SetID(id string) error {
+	r.Id = id
+	return nil
+}
+
+func ExtractResource(rm ResourceRestModel) (Resource, error) {
+	return Resource{
+		resourceType: rm.Id,
+		value:        rm.Value,
+	}, nil
+}
+
+// RestModel is a character's progression and stats
+type RestModel struct {
+	Id           string `json:"-"`
+	JobId        job.Id `json:"jobId"`
+	Level        byte   `json:"level"`
+	Experience   uint32 `json:"experience"`
+	Meso         uint32 `json:"meso"`
+	Strength     uint16 `json:"strength"`
+	Dexterity    uint16 `json:"dexterity"`
+	Intelligence uint16 `json:"intelligence"`
+	Luck         uint16 `json:"luck"`
+	Hp           uint16 `json:"hp"`
+	MaxHp        uint16 `json:"maxHp"`
+	Mp           uint16 `json:"mp"`
+	MaxMp        uint16 `json:"maxMp"`
+	Ap           uint16 `json:"ap"`
+}
+
+func (r RestModel) GetName() string {
+	return "characters"
+}
+
+func (r RestModel) GetID() string {
+	return r.Id
+}
+
+func (r *RestModel) SetID(id string) error {
+	r.Id = id
+	return nil
+}
+
+func Extract(rm RestModel) (Model, error) {
+	id, err := strconv.Atoi(rm.Id)
+	if err != nil {
+		return Model{}, err
+	}
+
+	return Model{
+		id:           uint32(id),
+		jobId:        rm.JobId,
+		level:        rm.Level,
+		experience:   rm.Experience,
+		meso:         rm.Meso,
+		strength:     rm.Strength,
+		dexterity:    rm.Dexterity,
+		intelligence: rm.Intelligence,
+		luck:         rm.Luck,
+		hp:           rm.Hp,
+		maxHp:        rm.MaxHp,
+		mp:           rm.Mp,
+		maxMp:        rm.MaxMp,
+		ap:           rm.Ap,
+	}, nil
+}
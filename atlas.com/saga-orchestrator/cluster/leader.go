@@ -0,0 +1,133 @@
+package cluster
+
+import (
+	"atlas-saga-orchestrator/metrics"
+	"atlas-saga-orchestrator/tasks"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Leader returns the ID of the replica leading the role, as the holder of its lease. Without a lease, this replica leads
+// every role.
+func (m *Membership) Leader(role string) (string, error) {
+	m.mutex.RLock()
+	lease, self := m.lease, m.config.ReplicaId
+	m.mutex.RUnlock()
+	if lease == nil {
+		return self, nil
+	}
+	return lease.Holder(role)
+}
+
+// Leads returns whether this replica led the role as of its latest acquisition. A replica yet to settle leads none.
+func (m *Membership) Leads(role string) bool {
+	m.mutex.RLock()
+	lease, settled := m.lease, m.settled
+	m.mutex.RUnlock()
+	if !settled {
+		return false
+	}
+	if lease == nil {
+		return true
+	}
+	return lease.Holds(role)
+}
+
+// Acquire acquires the leadership of the role from the lease, or confirms it remains held, returning whether this
+// replica leads it. Only the replica holding a role's lease leads it, so no two replicas lead a role at once, however
+// they observe the membership. A replica yet to settle acquires none. Without a lease, this replica leads every role.
+func (m *Membership) Acquire(role string) (bool, error) {
+	m.mutex.RLock()
+	lease, settled := m.lease, m.settled
+	m.mutex.RUnlock()
+	if !settled {
+		return false, nil
+	}
+	if lease == nil {
+		return true, nil
+	}
+	return lease.Acquire(role)
+}
+
+var roles = map[string]bool{}
+var rolesMutex sync.RWMutex
+var rolesOnce sync.Once
+
+// Roles returns the names of the roles registered by singleton tasks, in order
+func Roles() []string {
+	rolesMutex.RLock()
+	defer rolesMutex.RUnlock()
+	r := make([]string, 0, len(roles))
+	for role := range roles {
+		r = append(r, role)
+	}
+	sort.Strings(r)
+	return r
+}
+
+func registerRole(role string) {
+	rolesOnce.Do(func() {
+		metrics.GetRegistry().RegisterGauge("saga_role_leader", "Whether this replica leads the role of a singleton background task (1) or not (0).", func() []metrics.Sample {
+			m := GetMembership()
+			samples := make([]metrics.Sample, 0)
+			for _, role := range Roles() {
+				v := 0.0
+				if m.Leads(role) {
+					v = 1
+				}
+				samples = append(samples, metrics.Sample{Labels: map[string]string{"role": role, "replica_id": m.Self()}, Value: v})
+			}
+			return samples
+		})
+	})
+	rolesMutex.Lock()
+	defer rolesMutex.Unlock()
+	roles[role] = true
+}
+
+// SingletonTask runs a task on only the replica leading its role, so background work spanning replicas is not
+// duplicated. Leadership is acquired from the lease before each run, so should the leader be lost, the task runs on the
+// replica acquiring its lease from its next interval.
+type SingletonTask struct {
+	l       logrus.FieldLogger
+	role    string
+	task    tasks.Task
+	mutex   sync.Mutex
+	leading bool
+}
+
+// Singleton wraps the task, so it runs on only the replica leading the role
+func Singleton(l logrus.FieldLogger, role string) func(t tasks.Task) *SingletonTask {
+	return func(t tasks.Task) *SingletonTask {
+		registerRole(role)
+		return &SingletonTask{l: l.WithField("role", role), role: role, task: t}
+	}
+}
+
+func (t *SingletonTask) Run() {
+	leads, err := GetMembership().Acquire(t.role)
+	if err != nil {
+		t.l.WithError(err).Warn("Unable to acquire leadership of role.")
+	}
+	t.mutex.Lock()
+	if leads != t.leading {
+		if leads {
+			t.l.Infof("Replica [%s] acquired leadership of role.", GetMembership().Self())
+		} else {
+			leader, _ := GetMembership().Leader(t.role)
+			t.l.Infof("Replica [%s] relinquished leadership of role to [%s].", GetMembership().Self(), leader)
+		}
+		t.leading = leads
+	}
+	t.mutex.Unlock()
+	if leads {
+		t.task.Run()
+	}
+}
+
+func (t *SingletonTask) SleepTime() time.Duration {
+	return t.task.SleepTime()
+}
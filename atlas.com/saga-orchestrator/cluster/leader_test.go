@@ -0,0 +1,180 @@
+package cluster
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingTask struct {
+	runs int
+}
+
+func (t *countingTask) Run() {
+	t.runs++
+}
+
+func (t *countingTask) SleepTime() time.Duration {
+	return time.Second
+}
+
+// testLease leases roles shared between the replicas of a test, as a database would, failing acquisitions while err is
+// set
+type testLease struct {
+	mutex   *sync.Mutex
+	holders map[string]string
+	self    string
+	err     error
+}
+
+func newTestLeases(replicaIds ...string) map[string]*testLease {
+	mutex := &sync.Mutex{}
+	holders := make(map[string]string)
+	r := make(map[string]*testLease)
+	for _, id := range replicaIds {
+		r[id] = &testLease{mutex: mutex, holders: holders, self: id}
+	}
+	return r
+}
+
+func (l *testLease) Acquire(role string) (bool, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.err != nil {
+		// A lost connection releases the roles held on it
+		for r, h := range l.holders {
+			if h == l.self {
+				delete(l.holders, r)
+			}
+		}
+		return false, l.err
+	}
+	if _, ok := l.holders[role]; !ok {
+		l.holders[role] = l.self
+	}
+	return l.holders[role] == l.self, nil
+}
+
+func (l *testLease) Holds(role string) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.holders[role] == l.self
+}
+
+func (l *testLease) Holder(role string) (string, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.holders[role], nil
+}
+
+func (l *testLease) Release() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	for r, h := range l.holders {
+		if h == l.self {
+			delete(l.holders, r)
+		}
+	}
+	return nil
+}
+
+// TestSingleton tests that a singleton task runs on only the replica holding the lease of its role, and that leadership
+// passes to another replica once the leader releases or loses the lease
+func TestSingleton(t *testing.T) {
+	defer InitMembership(Config{})
+	defer InitLease(nil)
+	l, _ := test.NewNullLogger()
+
+	// Without a lease, a replica leads every role
+	InitMembership(Config{})
+	inner := &countingTask{}
+	task := Singleton(l, "test_role")(inner)
+	task.Run()
+	assert.Equal(t, 1, inner.runs)
+	assert.Equal(t, time.Second, task.SleepTime())
+	assert.Contains(t, Roles(), "test_role")
+
+	// A replica yet to settle leads no role
+	leases := newTestLeases("a", "b")
+	InitLease(leases["a"])
+	InitMembership(Config{ReplicaId: "a", HeartbeatInterval: time.Second, MemberTtl: 3 * time.Second})
+	m := GetMembership()
+	inner = &countingTask{}
+	task = Singleton(l, "test_role")(inner)
+	task.Run()
+	assert.Equal(t, 0, inner.runs)
+	assert.False(t, m.Leads("test_role"))
+
+	m.Announced(time.Now())
+	m.Settle(time.Now().Add(time.Second))
+	task.Run()
+	assert.Equal(t, 1, inner.runs)
+	assert.True(t, m.Leads("test_role"))
+	leader, err := m.Leader("test_role")
+	require.NoError(t, err)
+	assert.Equal(t, "a", leader)
+
+	// Replicas joining do not take the role, however they observe the membership
+	m.Observe("b", time.Now())
+	task.Run()
+	assert.Equal(t, 2, inner.runs)
+	acquired, err := leases["b"].Acquire("test_role")
+	require.NoError(t, err)
+	assert.False(t, acquired)
+
+	// A leader losing its connection loses the role, which another replica acquires
+	leases["a"].err = errors.New("connection lost")
+	task.Run()
+	assert.Equal(t, 2, inner.runs)
+	assert.False(t, m.Leads("test_role"))
+	acquired, err = leases["b"].Acquire("test_role")
+	require.NoError(t, err)
+	assert.True(t, acquired)
+	leases["a"].err = nil
+	task.Run()
+	assert.Equal(t, 2, inner.runs)
+	leader, err = m.Leader("test_role")
+	require.NoError(t, err)
+	assert.Equal(t, "b", leader)
+
+	// The role returns once released
+	require.NoError(t, leases["b"].Release())
+	task.Run()
+	assert.Equal(t, 3, inner.runs)
+}
+
+// TestGetStatus tests reporting the leader of each role
+func TestGetStatus(t *testing.T) {
+	defer InitMembership(Config{})
+	defer InitLease(nil)
+	l, _ := test.NewNullLogger()
+	Singleton(l, "status_role")(&countingTask{})
+
+	leases := newTestLeases("a", "b")
+	InitLease(leases["a"])
+	InitMembership(Config{ReplicaId: "a", HeartbeatInterval: time.Second, MemberTtl: 3 * time.Second})
+	GetMembership().Announced(time.Now())
+	GetMembership().Settle(time.Now().Add(time.Second))
+	GetMembership().Observe("b", time.Now())
+	_, err := leases["b"].Acquire("status_role")
+	require.NoError(t, err)
+	s := GetStatus()
+	assert.Equal(t, "a", s.ReplicaId)
+	assert.Equal(t, []string{"a", "b"}, s.Members)
+
+	var found bool
+	for _, r := range s.Roles {
+		if r.Role != "status_role" {
+			continue
+		}
+		found = true
+		assert.Equal(t, "b", r.Leader)
+		assert.False(t, r.Leading)
+	}
+	require.True(t, found)
+}
@@ -0,0 +1,144 @@
+package cluster
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+// leaseTimeout is the longest a statement acquiring or inspecting a lease may take
+const leaseTimeout = 5 * time.Second
+
+// leaseNamespace is the first key of the advisory locks leasing roles, so they do not collide with the locks of other
+// services sharing the database
+const leaseNamespace = "atlas-saga-orchestrator"
+
+// Lease grants the leadership of each role to a single replica at a time
+type Lease interface {
+	// Acquire acquires the role, or confirms it remains held, returning whether this replica holds it
+	Acquire(role string) (bool, error)
+
+	// Holds returns whether this replica held the role as of its latest acquisition
+	Holds(role string) bool
+
+	// Holder returns the ID of the replica holding the role, empty when none does
+	Holder(role string) (string, error)
+
+	// Release releases every role held by this replica
+	Release() error
+}
+
+// InitLease sets the lease the leadership of roles is acquired from. Several replicas are only run with a lease, so a
+// replica without one runs alone, and leads every role.
+func InitLease(l Lease) {
+	m := GetMembership()
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.lease = l
+}
+
+// leaseKey returns the key of the advisory lock leasing the name
+func leaseKey(name string) int32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(name))
+	return int32(h.Sum32())
+}
+
+// PostgresLease leases roles with PostgreSQL session advisory locks, held on a connection of its own for as long as the
+// replica leads. Should the replica or its connection be lost, PostgreSQL releases its locks, so another replica
+// acquires its roles.
+type PostgresLease struct {
+	mutex    sync.Mutex
+	db       *sql.DB
+	holderId string
+	conn     *sql.Conn
+	held     map[string]bool
+}
+
+// NewPostgresLease creates a lease of roles from the database, held by the replica
+func NewPostgresLease(db *sql.DB, replicaId string) *PostgresLease {
+	return &PostgresLease{db: db, holderId: replicaId, held: make(map[string]bool)}
+}
+
+// connect returns the connection the locks are held on, opening it should there be none. The connection is named for
+// the replica, so the holder of each lock is identified.
+func (l *PostgresLease) connect(ctx context.Context) (*sql.Conn, error) {
+	if l.conn != nil {
+		if err := l.conn.PingContext(ctx); err == nil {
+			return l.conn, nil
+		}
+		// The locks held on a lost connection were released with it
+		l.drop()
+	}
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = conn.ExecContext(ctx, "SELECT set_config('application_name', $1, false)", l.holderId); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	l.conn = conn
+	return conn, nil
+}
+
+// drop closes the connection the locks are held on, releasing them
+func (l *PostgresLease) drop() error {
+	l.held = make(map[string]bool)
+	if l.conn == nil {
+		return nil
+	}
+	err := l.conn.Close()
+	l.conn = nil
+	return err
+}
+
+func (l *PostgresLease) Acquire(role string) (bool, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	ctx, cancel := context.WithTimeout(context.Background(), leaseTimeout)
+	defer cancel()
+	conn, err := l.connect(ctx)
+	if err != nil {
+		return false, err
+	}
+	if l.held[role] {
+		return true, nil
+	}
+	// Advisory locks are reentrant, so the lock is acquired only while not held, and released once with the connection
+	var acquired bool
+	if err = conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1, $2)", leaseKey(leaseNamespace), leaseKey(role)).Scan(&acquired); err != nil {
+		return false, errors.Join(err, l.drop())
+	}
+	l.held[role] = acquired
+	return acquired, nil
+}
+
+func (l *PostgresLease) Holds(role string) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.held[role]
+}
+
+func (l *PostgresLease) Holder(role string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), leaseTimeout)
+	defer cancel()
+	// The lock's keys are reported as the unsigned object IDs of their bits
+	var holder string
+	err := l.db.QueryRowContext(ctx, "SELECT a.application_name FROM pg_locks l JOIN pg_stat_activity a ON a.pid = l.pid "+
+		"WHERE l.locktype = 'advisory' AND l.granted AND l.objsubid = 2 AND l.classid = $1::bigint::oid AND l.objid = $2::bigint::oid",
+		int64(uint32(leaseKey(leaseNamespace))), int64(uint32(leaseKey(role)))).Scan(&holder)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return holder, err
+}
+
+func (l *PostgresLease) Release() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.drop()
+}
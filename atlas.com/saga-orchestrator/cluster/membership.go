@@ -0,0 +1,245 @@
+package cluster
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Config configures the membership of the orchestrator's replicas
+type Config struct {
+	ReplicaId         string        // ReplicaId identifies this replica. When empty, the orchestrator runs as a single replica.
+	HeartbeatInterval time.Duration // HeartbeatInterval at which the replica announces itself
+	MemberTtl         time.Duration // MemberTtl after which a replica which has not announced itself is considered departed
+}
+
+// DefaultConfig is the membership configuration used when none is configured
+var DefaultConfig = Config{HeartbeatInterval: 5 * time.Second, MemberTtl: 15 * time.Second}
+
+// ConfigFromEnv loads the membership configuration from the environment
+func ConfigFromEnv() (Config, error) {
+	c := DefaultConfig
+	c.ReplicaId = os.Getenv("SAGA_REPLICA_ID")
+	if v, ok := os.LookupEnv("SAGA_CLUSTER_HEARTBEAT_INTERVAL"); ok && v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return Config{}, fmt.Errorf("invalid SAGA_CLUSTER_HEARTBEAT_INTERVAL '%s'", v)
+		}
+		c.HeartbeatInterval = d
+	}
+	if v, ok := os.LookupEnv("SAGA_CLUSTER_MEMBER_TTL"); ok && v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return Config{}, fmt.Errorf("invalid SAGA_CLUSTER_MEMBER_TTL '%s'", v)
+		}
+		c.MemberTtl = d
+	}
+	if c.MemberTtl <= c.HeartbeatInterval {
+		return Config{}, fmt.Errorf("SAGA_CLUSTER_MEMBER_TTL must exceed SAGA_CLUSTER_HEARTBEAT_INTERVAL")
+	}
+	return c, nil
+}
+
+// Membership tracks the replicas of the orchestrator, assigning ownership of each saga to a single replica by
+// consistent hashing of its transaction ID. Replicas join as their heartbeats are observed, and depart when they leave
+// or their heartbeats lapse, rebalancing ownership. A replica starting announces itself only once it has observed the
+// heartbeats of the others for a full heartbeat interval, and owns no saga until it has settled, a further heartbeat
+// interval after first announcing itself, by which time the others have observed it and released the sagas it owns. So
+// no saga is owned by two replicas at once.
+type Membership struct {
+	mutex      sync.RWMutex
+	config     Config
+	seen       map[string]time.Time
+	ring       Ring
+	announceAt time.Time
+	announced  bool
+	settleAt   time.Time
+	settled    bool
+	lease      Lease
+	rebalances []func(members []string)
+}
+
+// Singleton instance of the membership
+var instance *Membership
+var once sync.Once
+
+// GetMembership returns the singleton instance of the membership. Until initialized, the orchestrator runs as a single
+// replica owning every saga.
+func GetMembership() *Membership {
+	once.Do(func() {
+		instance = newMembership(Config{})
+	})
+	return instance
+}
+
+// InitMembership initializes the membership with this replica as its only member
+func InitMembership(c Config) {
+	m := GetMembership()
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	n := newMembership(c)
+	m.config, m.seen, m.ring = n.config, n.seen, n.ring
+	m.announceAt, m.announced, m.settleAt, m.settled = n.announceAt, n.announced, n.settleAt, n.settled
+}
+
+func newMembership(c Config) *Membership {
+	m := &Membership{config: c, seen: make(map[string]time.Time)}
+	m.ring = NewRing([]string{c.ReplicaId}, DefaultVirtualNodes)
+	m.announceAt = time.Now().Add(c.HeartbeatInterval)
+	m.settled = c.ReplicaId == ""
+	return m
+}
+
+// Enabled returns whether the orchestrator runs as one of several replicas
+func (m *Membership) Enabled() bool {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.config.ReplicaId != ""
+}
+
+// Self returns the ID of this replica
+func (m *Membership) Self() string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.config.ReplicaId
+}
+
+// Config returns the configuration of the membership
+func (m *Membership) Config() Config {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.config
+}
+
+// Members returns the IDs of the replicas, including this one, in order
+func (m *Membership) Members() []string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.ring.Members()
+}
+
+// Owner returns the ID of the replica owning the saga
+func (m *Membership) Owner(transactionId uuid.UUID) string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	o, _ := m.ring.Owner(transactionId.String())
+	return o
+}
+
+// Owns returns whether this replica owns the saga. A single replica owns every saga, and one yet to settle owns none.
+func (m *Membership) Owns(transactionId uuid.UUID) bool {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	if m.config.ReplicaId == "" {
+		return true
+	}
+	if !m.settled {
+		return false
+	}
+	o, _ := m.ring.Owner(transactionId.String())
+	return o == m.config.ReplicaId
+}
+
+// Settled returns whether this replica has settled, so owns its share of the sagas
+func (m *Membership) Settled() bool {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.settled
+}
+
+// Announces returns whether this replica announces itself, once a full heartbeat interval has passed since it started,
+// by which time the heartbeat of every other replica has been observed
+func (m *Membership) Announces(now time.Time) bool {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.settled || !now.Before(m.announceAt)
+}
+
+// Announced records that this replica announced itself to the others. It settles a full heartbeat interval after first
+// doing so, by which time the others have observed it.
+func (m *Membership) Announced(at time.Time) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.announced {
+		return
+	}
+	m.announced = true
+	m.settleAt = at.Add(m.config.HeartbeatInterval)
+}
+
+// Settle settles this replica once a full heartbeat interval has passed since it first announced itself, by which time
+// the others have observed it and released the sagas it owns. Ownership is rebalanced as the replica settles, so it
+// acquires its share.
+func (m *Membership) Settle(now time.Time) {
+	m.mutex.Lock()
+	if m.settled || !m.announced || now.Before(m.settleAt) {
+		m.mutex.Unlock()
+		return
+	}
+	m.settled = true
+	m.update(true)
+}
+
+// OnRebalance registers a function called with the members once ownership is rebalanced as replicas join or depart
+func (m *Membership) OnRebalance(f func(members []string)) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.rebalances = append(m.rebalances, f)
+}
+
+// Observe records a heartbeat of the replica, which joins the membership if not already a member
+func (m *Membership) Observe(replicaId string, at time.Time) {
+	m.mutex.Lock()
+	if replicaId == "" || replicaId == m.config.ReplicaId {
+		m.mutex.Unlock()
+		return
+	}
+	_, member := m.seen[replicaId]
+	m.seen[replicaId] = at
+	m.update(!member)
+}
+
+// Leave departs the replica from the membership
+func (m *Membership) Leave(replicaId string) {
+	m.mutex.Lock()
+	_, member := m.seen[replicaId]
+	delete(m.seen, replicaId)
+	m.update(member)
+}
+
+// Expire departs the replicas whose heartbeats have lapsed
+func (m *Membership) Expire(now time.Time) {
+	m.mutex.Lock()
+	changed := false
+	for id, at := range m.seen {
+		if now.Sub(at) > m.config.MemberTtl {
+			delete(m.seen, id)
+			changed = true
+		}
+	}
+	m.update(changed)
+}
+
+// update rebuilds the ring when the members have changed, and releases the lock held by the caller before notifying
+// of the rebalance
+func (m *Membership) update(changed bool) {
+	if !changed {
+		m.mutex.Unlock()
+		return
+	}
+	members := []string{m.config.ReplicaId}
+	for id := range m.seen {
+		members = append(members, id)
+	}
+	m.ring = NewRing(members, DefaultVirtualNodes)
+	members = m.ring.Members()
+	rebalances := append([]func([]string){}, m.rebalances...)
+	m.mutex.Unlock()
+
+	for _, f := range rebalances {
+		f(members)
+	}
+}
@@ -0,0 +1,145 @@
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestConfigFromEnv tests loading the membership configuration from the environment
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("SAGA_REPLICA_ID", "orchestrator-0")
+	t.Setenv("SAGA_CLUSTER_HEARTBEAT_INTERVAL", "2s")
+	t.Setenv("SAGA_CLUSTER_MEMBER_TTL", "6s")
+	c, err := ConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, Config{ReplicaId: "orchestrator-0", HeartbeatInterval: 2 * time.Second, MemberTtl: 6 * time.Second}, c)
+
+	t.Setenv("SAGA_CLUSTER_MEMBER_TTL", "1s")
+	_, err = ConfigFromEnv()
+	assert.Error(t, err)
+	t.Setenv("SAGA_CLUSTER_HEARTBEAT_INTERVAL", "often")
+	_, err = ConfigFromEnv()
+	assert.Error(t, err)
+}
+
+// TestMembership tests that ownership is rebalanced as replicas join, leave, and let their heartbeats lapse
+func TestMembership(t *testing.T) {
+	defer InitMembership(Config{})
+
+	// A single replica owns every saga, and consumes in the service's group
+	InitMembership(Config{})
+	m := GetMembership()
+	assert.False(t, m.Enabled())
+	assert.True(t, m.Owns(uuid.New()))
+	assert.Equal(t, "Saga Orchestrator Service", ConsumerGroupId("Saga Orchestrator Service"))
+
+	InitMembership(Config{ReplicaId: "a", HeartbeatInterval: time.Second, MemberTtl: 3 * time.Second})
+	assert.True(t, m.Enabled())
+	assert.Equal(t, "Saga Orchestrator Service - a", ConsumerGroupId("Saga Orchestrator Service"))
+	rebalances := make([][]string, 0)
+	m.OnRebalance(func(members []string) {
+		rebalances = append(rebalances, members)
+	})
+
+	// A replica starting announces itself once a heartbeat interval has passed, having observed the others, and owns no
+	// saga until a further heartbeat interval has passed, the others having observed it
+	now := time.Now()
+	m.Observe("b", now)
+	assert.False(t, m.Announces(now))
+	assert.True(t, m.Announces(now.Add(time.Second)))
+	m.Settle(now.Add(time.Second))
+	assert.False(t, m.Settled())
+	m.Announced(now.Add(time.Second))
+	m.Announced(now.Add(2 * time.Second))
+	assert.False(t, m.Settled())
+	assert.False(t, m.Owns(uuid.New()))
+	m.Settle(now.Add(time.Second))
+	assert.False(t, m.Settled())
+	m.Settle(now.Add(2 * time.Second))
+	assert.True(t, m.Settled())
+	assert.Equal(t, [][]string{{"a", "b"}, {"a", "b"}}, rebalances)
+	rebalances = rebalances[:0]
+
+	m.Observe("b", now.Add(time.Second))
+	m.Observe("a", now)
+	assert.Empty(t, rebalances)
+	assert.Equal(t, []string{"a", "b"}, m.Members())
+
+	// Each saga is owned by exactly one replica
+	owned := 0
+	for i := 0; i < 100; i++ {
+		id := uuid.New()
+		if m.Owns(id) {
+			owned++
+			assert.Equal(t, "a", m.Owner(id))
+		} else {
+			assert.Equal(t, "b", m.Owner(id))
+		}
+	}
+	assert.Greater(t, owned, 0)
+	assert.Less(t, owned, 100)
+
+	m.Observe("c", now)
+	m.Expire(now.Add(2 * time.Second))
+	assert.Len(t, rebalances, 1)
+	m.Observe("b", now.Add(3*time.Second))
+	m.Expire(now.Add(4*time.Second + time.Millisecond))
+	assert.Equal(t, []string{"a", "b"}, rebalances[1])
+	m.Leave("b")
+	m.Leave("b")
+	assert.Equal(t, [][]string{{"a", "b", "c"}, {"a", "b"}, {"a"}}, rebalances)
+	assert.True(t, m.Owns(uuid.New()))
+}
+
+// TestJoinExclusive tests that no saga is owned by two replicas at once as a replica joins another, the replica joining
+// acquiring its share only once the other has observed it and released them
+func TestJoinExclusive(t *testing.T) {
+	interval := time.Second
+	config := func(replicaId string) Config {
+		return Config{ReplicaId: replicaId, HeartbeatInterval: interval, MemberTtl: 3 * interval}
+	}
+	ids := make([]uuid.UUID, 200)
+	for i := range ids {
+		ids[i] = uuid.New()
+	}
+
+	start := time.Now()
+	a := newMembership(config("a"))
+	a.Announced(start)
+	a.Settle(start.Add(interval))
+	require.True(t, a.Settled())
+	b := newMembership(config("b"))
+
+	// Each replica heartbeats every interval, b's announcements reaching a half an interval after they are made
+	delay := interval / 2
+	var inflight []time.Time
+	for step := 0; step <= 40; step++ {
+		now := start.Add(time.Duration(step) * interval / 4)
+		if step%4 == 0 {
+			b.Observe("a", now)
+		}
+		if step%4 == 2 {
+			b.Settle(now)
+			if b.Announces(now) {
+				b.Announced(now)
+				inflight = append(inflight, now.Add(delay))
+			}
+		}
+		for len(inflight) > 0 && !now.Before(inflight[0]) {
+			a.Observe("b", inflight[0])
+			inflight = inflight[1:]
+		}
+		for _, id := range ids {
+			require.False(t, a.Owns(id) && b.Owns(id), "saga [%s] owned by both replicas at step [%d]", id, step)
+		}
+	}
+
+	require.True(t, b.Settled())
+	for _, id := range ids {
+		assert.NotEqual(t, a.Owns(id), b.Owns(id))
+	}
+}
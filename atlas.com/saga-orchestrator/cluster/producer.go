@@ -0,0 +1,26 @@
+package cluster
+
+import (
+	"atlas-saga-orchestrator/kafka/message/cluster"
+	"context"
+
+	"github.com/Chronicle20/atlas-kafka/producer"
+	"github.com/Chronicle20/atlas-kafka/topic"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/segmentio/kafka-go"
+	"github.com/sirupsen/logrus"
+)
+
+func MembershipEventProvider(replicaId string, eventType string) model.Provider[[]kafka.Message] {
+	value := &cluster.MembershipEvent{
+		ReplicaId: replicaId,
+		Type:      eventType,
+	}
+	return producer.SingleMessageProvider([]byte(replicaId), value)
+}
+
+// announce produces a membership event of this replica. Membership spans tenants, so the event carries no tenant.
+func announce(l logrus.FieldLogger, ctx context.Context, eventType string) error {
+	mp := producer.Produce(l)(producer.WriterProvider(topic.EnvProvider(l)(cluster.EnvEventTopicMembership)))(producer.SpanHeaderDecorator(ctx))
+	return mp(MembershipEventProvider(GetMembership().Self(), eventType))
+}
@@ -0,0 +1,47 @@
+package cluster
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Chronicle20/atlas-rest/server"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+// RoleStatus reports the replica leading a role
+type RoleStatus struct {
+	Role    string `json:"role"`    // Role of a singleton background task
+	Leader  string `json:"leader"`  // ID of the replica leading the role, empty when none does or it is unknown
+	Leading bool   `json:"leading"` // Whether this replica leads the role
+}
+
+// Status reports the membership as observed by this replica
+type Status struct {
+	ReplicaId string       `json:"replicaId"` // ID of this replica, empty when running as a single replica
+	Members   []string     `json:"members"`   // IDs of the replicas observed, including this one
+	Roles     []RoleStatus `json:"roles"`     // Leaders of the roles of singleton background tasks
+}
+
+// GetStatus returns the membership as observed by this replica
+func GetStatus() Status {
+	m := GetMembership()
+	s := Status{ReplicaId: m.Self(), Members: m.Members(), Roles: make([]RoleStatus, 0)}
+	for _, role := range Roles() {
+		leader, _ := m.Leader(role)
+		s.Roles = append(s.Roles, RoleStatus{Role: role, Leader: leader, Leading: m.Leads(role)})
+	}
+	return s
+}
+
+// InitResource registers the cluster status route with the router. Membership spans tenants, so no tenant is required.
+func InitResource() server.RouteInitializer {
+	return func(r *mux.Router, l logrus.FieldLogger) {
+		r.HandleFunc("/cluster", func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if err := json.NewEncoder(w).Encode(GetStatus()); err != nil {
+				l.WithError(err).Error("Unable to write cluster status.")
+			}
+		}).Methods(http.MethodGet)
+	}
+}
@@ -0,0 +1,76 @@
+package cluster
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"sort"
+	"strconv"
+)
+
+// DefaultVirtualNodes is the number of points each member occupies on the ring, spreading keys evenly between members
+const DefaultVirtualNodes = 64
+
+// Ring assigns keys to members by consistent hashing, so a change of membership moves only the keys owned by the
+// members joining or leaving
+type Ring struct {
+	points  []uint32
+	owners  map[uint32]string
+	members []string
+}
+
+// NewRing creates a ring of the members, each occupying the number of virtual nodes
+func NewRing(members []string, virtualNodes int) Ring {
+	if virtualNodes <= 0 {
+		virtualNodes = DefaultVirtualNodes
+	}
+	r := Ring{owners: make(map[uint32]string), members: make([]string, 0, len(members))}
+	seen := make(map[string]bool, len(members))
+	for _, m := range members {
+		if m == "" || seen[m] {
+			continue
+		}
+		seen[m] = true
+		r.members = append(r.members, m)
+	}
+	sort.Strings(r.members)
+
+	for _, m := range r.members {
+		for i := 0; i < virtualNodes; i++ {
+			p := hash(m + "#" + strconv.Itoa(i))
+			// Members are placed in order, so a collision is resolved the same way by every replica
+			if _, ok := r.owners[p]; ok {
+				continue
+			}
+			r.owners[p] = m
+			r.points = append(r.points, p)
+		}
+	}
+	sort.Slice(r.points, func(i, j int) bool { return r.points[i] < r.points[j] })
+	return r
+}
+
+// Owner returns the member owning the key, being the first at or after the key's point on the ring. Returns false when
+// the ring has no members.
+func (r Ring) Owner(key string) (string, bool) {
+	if len(r.points) == 0 {
+		return "", false
+	}
+	h := hash(key)
+	i := sort.Search(len(r.points), func(i int) bool { return r.points[i] >= h })
+	if i == len(r.points) {
+		i = 0
+	}
+	return r.owners[r.points[i]], true
+}
+
+// Members returns the members of the ring, in order
+func (r Ring) Members() []string {
+	return append([]string{}, r.members...)
+}
+
+// hash returns the point of the key on the ring. Members' virtual nodes differ only slightly in name, so a hash which
+// disperses similar keys is used.
+func hash(key string) uint32 {
+	h := sha256.Sum256([]byte(key))
+	return binary.BigEndian.Uint32(h[:4])
+}
@@ -0,0 +1,46 @@
+package cluster
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRing tests that keys are spread between members, and that a member joining moves only the keys it takes
+func TestRing(t *testing.T) {
+	_, ok := NewRing(nil, DefaultVirtualNodes).Owner("key")
+	assert.False(t, ok)
+
+	r := NewRing([]string{"b", "a", "c", "a", ""}, DefaultVirtualNodes)
+	assert.Equal(t, []string{"a", "b", "c"}, r.Members())
+
+	keys := make([]string, 0, 3000)
+	for i := 0; i < 3000; i++ {
+		keys = append(keys, uuid.New().String())
+	}
+	owners := make(map[string]string, len(keys))
+	counts := make(map[string]int)
+	for _, k := range keys {
+		o, ok := r.Owner(k)
+		require.True(t, ok)
+		owners[k] = o
+		counts[o]++
+	}
+	for _, m := range r.Members() {
+		assert.Greater(t, counts[m], 300, fmt.Sprintf("member %s owns too few keys", m))
+	}
+
+	// Replicas agree on ownership regardless of the order they observe members in
+	same := NewRing([]string{"c", "b", "a"}, DefaultVirtualNodes)
+	joined := NewRing([]string{"a", "b", "c", "d"}, DefaultVirtualNodes)
+	for _, k := range keys {
+		o, _ := same.Owner(k)
+		assert.Equal(t, owners[k], o)
+		if j, _ := joined.Owner(k); j != owners[k] {
+			assert.Equal(t, "d", j)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package cluster
+
+import (
+	"atlas-saga-orchestrator/kafka/message/cluster"
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// HeartbeatTask announces this replica to the others, and departs those whose heartbeats have lapsed. A replica starting
+// only observes the others for a heartbeat interval before announcing itself, and settles a heartbeat interval after
+// first doing so, so the others release the sagas it would own before it acquires them.
+type HeartbeatTask struct {
+	l   logrus.FieldLogger
+	ctx context.Context
+}
+
+// NewHeartbeatTask creates a task announcing this replica at the configured heartbeat interval
+func NewHeartbeatTask(l logrus.FieldLogger, ctx context.Context) *HeartbeatTask {
+	return &HeartbeatTask{l: l, ctx: ctx}
+}
+
+func (t *HeartbeatTask) Run() {
+	m := GetMembership()
+	now := time.Now()
+	if !m.Settled() {
+		m.Settle(now)
+		if m.Settled() {
+			t.l.Infof("Replica [%s] settled with replicas %v.", m.Self(), m.Members())
+		}
+	}
+	if m.Announces(now) {
+		if err := announce(t.l, t.ctx, cluster.MembershipEventHeartbeat); err != nil {
+			t.l.WithError(err).Warn("Unable to announce replica.")
+		} else {
+			m.Announced(now)
+		}
+	}
+	m.Expire(time.Now())
+}
+
+func (t *HeartbeatTask) SleepTime() time.Duration {
+	return GetMembership().Config().HeartbeatInterval
+}
+
+// Leave announces the departure of this replica, so the others rebalance without awaiting its heartbeats lapsing
+func Leave(l logrus.FieldLogger, ctx context.Context) func() {
+	return func() {
+		if err := announce(l, ctx, cluster.MembershipEventLeft); err != nil {
+			l.WithError(err).Warn("Unable to announce departure of replica.")
+		}
+	}
+}
+
+// ConsumerGroupId returns the consumer group the replica consumes saga commands and status events in. Replicas each
+// consume every message in their own group, processing only those of the sagas they own. A single replica consumes in
+// the service's group, as before.
+func ConsumerGroupId(groupId string) string {
+	if !GetMembership().Enabled() {
+		return groupId
+	}
+	return groupId + " - " + GetMembership().Self()
+}
@@ -2,16 +2,57 @@ package mock
 
 import (
 	"atlas-saga-orchestrator/compartment"
+	"github.com/Chronicle20/atlas-constants/inventory"
+	"github.com/Chronicle20/atlas-model/model"
 	"github.com/google/uuid"
+	"time"
 )
 
 // ProcessorMock is a mock implementation of the compartment.Processor interface
 type ProcessorMock struct {
+	GetByTypeFunc                func(characterId uint32, inventoryType inventory.Type) (compartment.Model, error)
+	ByTypeProviderFunc           func(characterId uint32, inventoryType inventory.Type) model.Provider[compartment.Model]
+	GetHoldersFunc               func(templateId uint32, after uint32, size uint32) ([]compartment.Holder, error)
 	RequestCreateItemFunc        func(transactionId uuid.UUID, characterId uint32, templateId uint32, quantity uint32) error
+	RequestRestoreItemFunc       func(transactionId uuid.UUID, characterId uint32, templateId uint32, quantity uint32, expiration time.Time, ownerId uint32, flag uint16, rechargeable uint64) error
 	RequestDestroyItemFunc       func(transactionId uuid.UUID, characterId uint32, templateId uint32, quantity uint32) error
 	RequestEquipAssetFunc        func(transactionId uuid.UUID, characterId uint32, inventoryType byte, source int16, destination int16) error
 	RequestUnequipAssetFunc      func(transactionId uuid.UUID, characterId uint32, inventoryType byte, source int16, destination int16) error
+	RequestMoveAssetFunc         func(transactionId uuid.UUID, characterId uint32, inventoryType byte, source int16, destination int16) error
 	RequestCreateAndEquipAssetFunc func(transactionId uuid.UUID, payload compartment.CreateAndEquipAssetPayload) error
+	RequestRestoreSnapshotFunc     func(transactionId uuid.UUID, characterId uint32, inventoryType byte, snapshotId uint32) error
+	RequestChangeDurabilityFunc    func(transactionId uuid.UUID, characterId uint32, percent int8) error
+	RequestChangeExpirationFunc    func(transactionId uuid.UUID, characterId uint32, inventoryType byte, slot int16, expiration time.Time) error
+	RequestSetUpgradeSlotsFunc     func(transactionId uuid.UUID, characterId uint32, slot int16, slots uint16, hammersApplied uint32) error
+	RequestReserveAssetFunc        func(transactionId uuid.UUID, characterId uint32, inventoryType byte, slot int16, templateId uint32, quantity uint32) error
+	RequestCancelReservationFunc   func(transactionId uuid.UUID, characterId uint32, inventoryType byte, slot int16) error
+	RequestConsumeAssetFunc        func(transactionId uuid.UUID, characterId uint32, inventoryType byte, slot int16) error
+}
+
+// GetByType is a mock implementation of the compartment.Processor.GetByType method
+func (m *ProcessorMock) GetByType(characterId uint32, inventoryType inventory.Type) (compartment.Model, error) {
+	if m.GetByTypeFunc != nil {
+		return m.GetByTypeFunc(characterId, inventoryType)
+	}
+	return compartment.Model{}, nil
+}
+
+// ByTypeProvider is a mock implementation of the compartment.Processor.ByTypeProvider method
+func (m *ProcessorMock) ByTypeProvider(characterId uint32, inventoryType inventory.Type) model.Provider[compartment.Model] {
+	if m.ByTypeProviderFunc != nil {
+		return m.ByTypeProviderFunc(characterId, inventoryType)
+	}
+	return func() (compartment.Model, error) {
+		return m.GetByType(characterId, inventoryType)
+	}
+}
+
+// GetHolders is a mock implementation of the compartment.Processor.GetHolders method
+func (m *ProcessorMock) GetHolders(templateId uint32, after uint32, size uint32) ([]compartment.Holder, error) {
+	if m.GetHoldersFunc != nil {
+		return m.GetHoldersFunc(templateId, after, size)
+	}
+	return nil, nil
 }
 
 // RequestCreateItem is a mock implementation of the compartment.Processor.RequestCreateItem method
@@ -22,6 +63,14 @@ func (m *ProcessorMock) RequestCreateItem(transactionId uuid.UUID, characterId u
 	return nil
 }
 
+// RequestRestoreItem is a mock implementation of the compartment.Processor.RequestRestoreItem method
+func (m *ProcessorMock) RequestRestoreItem(transactionId uuid.UUID, characterId uint32, templateId uint32, quantity uint32, expiration time.Time, ownerId uint32, flag uint16, rechargeable uint64) error {
+	if m.RequestRestoreItemFunc != nil {
+		return m.RequestRestoreItemFunc(transactionId, characterId, templateId, quantity, expiration, ownerId, flag, rechargeable)
+	}
+	return nil
+}
+
 // RequestDestroyItem is a mock implementation of the compartment.Processor.RequestDestroyItem method
 func (m *ProcessorMock) RequestDestroyItem(transactionId uuid.UUID, characterId uint32, templateId uint32, quantity uint32) error {
 	if m.RequestDestroyItemFunc != nil {
@@ -46,6 +95,14 @@ func (m *ProcessorMock) RequestUnequipAsset(transactionId uuid.UUID, characterId
 	return nil
 }
 
+// RequestMoveAsset is a mock implementation of the compartment.Processor.RequestMoveAsset method
+func (m *ProcessorMock) RequestMoveAsset(transactionId uuid.UUID, characterId uint32, inventoryType byte, source int16, destination int16) error {
+	if m.RequestMoveAssetFunc != nil {
+		return m.RequestMoveAssetFunc(transactionId, characterId, inventoryType, source, destination)
+	}
+	return nil
+}
+
 // RequestCreateAndEquipAsset is a mock implementation of the compartment.Processor.RequestCreateAndEquipAsset method
 func (m *ProcessorMock) RequestCreateAndEquipAsset(transactionId uuid.UUID, payload compartment.CreateAndEquipAssetPayload) error {
 	if m.RequestCreateAndEquipAssetFunc != nil {
@@ -53,3 +110,59 @@ func (m *ProcessorMock) RequestCreateAndEquipAsset(transactionId uuid.UUID, payl
 	}
 	return nil
 }
+
+// RequestRestoreSnapshot is a mock implementation of the compartment.Processor.RequestRestoreSnapshot method
+func (m *ProcessorMock) RequestRestoreSnapshot(transactionId uuid.UUID, characterId uint32, inventoryType byte, snapshotId uint32) error {
+	if m.RequestRestoreSnapshotFunc != nil {
+		return m.RequestRestoreSnapshotFunc(transactionId, characterId, inventoryType, snapshotId)
+	}
+	return nil
+}
+
+// RequestChangeDurability is a mock implementation of the compartment.Processor.RequestChangeDurability method
+func (m *ProcessorMock) RequestChangeDurability(transactionId uuid.UUID, characterId uint32, percent int8) error {
+	if m.RequestChangeDurabilityFunc != nil {
+		return m.RequestChangeDurabilityFunc(transactionId, characterId, percent)
+	}
+	return nil
+}
+
+// RequestChangeExpiration is a mock implementation of the compartment.Processor.RequestChangeExpiration method
+func (m *ProcessorMock) RequestChangeExpiration(transactionId uuid.UUID, characterId uint32, inventoryType byte, slot int16, expiration time.Time) error {
+	if m.RequestChangeExpirationFunc != nil {
+		return m.RequestChangeExpirationFunc(transactionId, characterId, inventoryType, slot, expiration)
+	}
+	return nil
+}
+
+// RequestSetUpgradeSlots is a mock implementation of the compartment.Processor.RequestSetUpgradeSlots method
+func (m *ProcessorMock) RequestSetUpgradeSlots(transactionId uuid.UUID, characterId uint32, slot int16, slots uint16, hammersApplied uint32) error {
+	if m.RequestSetUpgradeSlotsFunc != nil {
+		return m.RequestSetUpgradeSlotsFunc(transactionId, characterId, slot, slots, hammersApplied)
+	}
+	return nil
+}
+
+// RequestReserveAsset is a mock implementation of the compartment.Processor.RequestReserveAsset method
+func (m *ProcessorMock) RequestReserveAsset(transactionId uuid.UUID, characterId uint32, inventoryType byte, slot int16, templateId uint32, quantity uint32) error {
+	if m.RequestReserveAssetFunc != nil {
+		return m.RequestReserveAssetFunc(transactionId, characterId, inventoryType, slot, templateId, quantity)
+	}
+	return nil
+}
+
+// RequestCancelReservation is a mock implementation of the compartment.Processor.RequestCancelReservation method
+func (m *ProcessorMock) RequestCancelReservation(transactionId uuid.UUID, characterId uint32, inventoryType byte, slot int16) error {
+	if m.RequestCancelReservationFunc != nil {
+		return m.RequestCancelReservationFunc(transactionId, characterId, inventoryType, slot)
+	}
+	return nil
+}
+
+// RequestConsumeAsset is a mock implementation of the compartment.Processor.RequestConsumeAsset method
+func (m *ProcessorMock) RequestConsumeAsset(transactionId uuid.UUID, characterId uint32, inventoryType byte, slot int16) error {
+	if m.RequestConsumeAssetFunc != nil {
+		return m.RequestConsumeAssetFunc(transactionId, characterId, inventoryType, slot)
+	}
+	return nil
+}
@@ -113,3 +113,24 @@ func (b *ModelBuilder) Build() Model {
 		assets:        b.assets,
 	}
 }
+
+// Holder is a character holding an item, and the quantity of it they hold across their compartments
+type Holder struct {
+	characterId uint32
+	quantity    uint32
+}
+
+func (h Holder) CharacterId() uint32 {
+	return h.characterId
+}
+
+func (h Holder) Quantity() uint32 {
+	return h.quantity
+}
+
+func NewHolder(characterId uint32, quantity uint32) Holder {
+	return Holder{
+		characterId: characterId,
+		quantity:    quantity,
+	}
+}
@@ -7,8 +7,11 @@ import (
 	"errors"
 	"github.com/Chronicle20/atlas-constants/inventory"
 	"github.com/Chronicle20/atlas-constants/item"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/Chronicle20/atlas-rest/requests"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
+	"time"
 )
 
 // ItemPayload represents an individual item in a transaction
@@ -24,11 +27,23 @@ type CreateAndEquipAssetPayload struct {
 }
 
 type Processor interface {
+	GetByType(characterId uint32, inventoryType inventory.Type) (Model, error)
+	ByTypeProvider(characterId uint32, inventoryType inventory.Type) model.Provider[Model]
+	GetHolders(templateId uint32, after uint32, size uint32) ([]Holder, error)
 	RequestCreateItem(transactionId uuid.UUID, characterId uint32, templateId uint32, quantity uint32) error
+	RequestRestoreItem(transactionId uuid.UUID, characterId uint32, templateId uint32, quantity uint32, expiration time.Time, ownerId uint32, flag uint16, rechargeable uint64) error
 	RequestDestroyItem(transactionId uuid.UUID, characterId uint32, templateId uint32, quantity uint32) error
 	RequestEquipAsset(transactionId uuid.UUID, characterId uint32, inventoryType byte, source int16, destination int16) error
 	RequestUnequipAsset(transactionId uuid.UUID, characterId uint32, inventoryType byte, source int16, destination int16) error
+	RequestMoveAsset(transactionId uuid.UUID, characterId uint32, inventoryType byte, source int16, destination int16) error
 	RequestCreateAndEquipAsset(transactionId uuid.UUID, payload CreateAndEquipAssetPayload) error
+	RequestRestoreSnapshot(transactionId uuid.UUID, characterId uint32, inventoryType byte, snapshotId uint32) error
+	RequestChangeDurability(transactionId uuid.UUID, characterId uint32, percent int8) error
+	RequestChangeExpiration(transactionId uuid.UUID, characterId uint32, inventoryType byte, slot int16, expiration time.Time) error
+	RequestSetUpgradeSlots(transactionId uuid.UUID, characterId uint32, slot int16, slots uint16, hammersApplied uint32) error
+	RequestReserveAsset(transactionId uuid.UUID, characterId uint32, inventoryType byte, slot int16, templateId uint32, quantity uint32) error
+	RequestCancelReservation(transactionId uuid.UUID, characterId uint32, inventoryType byte, slot int16) error
+	RequestConsumeAsset(transactionId uuid.UUID, characterId uint32, inventoryType byte, slot int16) error
 }
 
 type ProcessorImpl struct {
@@ -44,6 +59,20 @@ func NewProcessor(l logrus.FieldLogger, ctx context.Context) Processor {
 	return p
 }
 
+func (p *ProcessorImpl) GetByType(characterId uint32, inventoryType inventory.Type) (Model, error) {
+	return p.ByTypeProvider(characterId, inventoryType)()
+}
+
+func (p *ProcessorImpl) ByTypeProvider(characterId uint32, inventoryType inventory.Type) model.Provider[Model] {
+	return requests.Provider[RestModel, Model](p.l, p.ctx)(requestByType(characterId, inventoryType), Extract)
+}
+
+// GetHolders returns the characters holding the item, in ascending order of character id, being at most size of those
+// whose id is greater than after
+func (p *ProcessorImpl) GetHolders(templateId uint32, after uint32, size uint32) ([]Holder, error) {
+	return requests.SliceProvider[HolderRestModel, Holder](p.l, p.ctx)(requestHolders(templateId, after, size), ExtractHolder, model.Filters[Holder]())()
+}
+
 func (p *ProcessorImpl) RequestCreateItem(transactionId uuid.UUID, characterId uint32, templateId uint32, quantity uint32) error {
 	inventoryType, ok := inventory.TypeFromItemId(item.Id(templateId))
 	if !ok {
@@ -52,6 +81,15 @@ func (p *ProcessorImpl) RequestCreateItem(transactionId uuid.UUID, characterId u
 	return producer.ProviderImpl(p.l)(p.ctx)(compartment.EnvCommandTopic)(RequestCreateAssetCommandProvider(transactionId, characterId, inventoryType, templateId, quantity))
 }
 
+// RequestRestoreItem requests an asset be created with the reference data captured from the asset being restored
+func (p *ProcessorImpl) RequestRestoreItem(transactionId uuid.UUID, characterId uint32, templateId uint32, quantity uint32, expiration time.Time, ownerId uint32, flag uint16, rechargeable uint64) error {
+	inventoryType, ok := inventory.TypeFromItemId(item.Id(templateId))
+	if !ok {
+		return errors.New("invalid templateId")
+	}
+	return producer.ProviderImpl(p.l)(p.ctx)(compartment.EnvCommandTopic)(RequestRestoreAssetCommandProvider(transactionId, characterId, inventoryType, templateId, quantity, expiration, ownerId, flag, rechargeable))
+}
+
 func (p *ProcessorImpl) RequestDestroyItem(transactionId uuid.UUID, characterId uint32, templateId uint32, quantity uint32) error {
 	inventoryType, ok := inventory.TypeFromItemId(item.Id(templateId))
 	if !ok {
@@ -76,6 +114,49 @@ func (p *ProcessorImpl) RequestUnequipAsset(transactionId uuid.UUID, characterId
 	return producer.ProviderImpl(p.l)(p.ctx)(compartment.EnvCommandTopic)(RequestUnequipAssetCommandProvider(transactionId, characterId, inventoryType, source, destination))
 }
 
+func (p *ProcessorImpl) RequestMoveAsset(transactionId uuid.UUID, characterId uint32, inventoryType byte, source int16, destination int16) error {
+	return producer.ProviderImpl(p.l)(p.ctx)(compartment.EnvCommandTopic)(RequestMoveAssetCommandProvider(transactionId, characterId, inventoryType, source, destination))
+}
+
+// RequestRestoreSnapshot requests a compartment be restored to the contents captured by a snapshot
+func (p *ProcessorImpl) RequestRestoreSnapshot(transactionId uuid.UUID, characterId uint32, inventoryType byte, snapshotId uint32) error {
+	return producer.ProviderImpl(p.l)(p.ctx)(compartment.EnvCommandTopic)(RequestRestoreSnapshotCommandProvider(transactionId, characterId, inventoryType, snapshotId))
+}
+
+// RequestChangeDurability requests the durability of a character's equipped items be changed by a percentage of their
+// maximum durability
+func (p *ProcessorImpl) RequestChangeDurability(transactionId uuid.UUID, characterId uint32, percent int8) error {
+	return producer.ProviderImpl(p.l)(p.ctx)(compartment.EnvCommandTopic)(RequestChangeDurabilityCommandProvider(transactionId, characterId, percent))
+}
+
+// RequestChangeExpiration requests the expiration of the asset in a slot of a character's compartment be changed
+func (p *ProcessorImpl) RequestChangeExpiration(transactionId uuid.UUID, characterId uint32, inventoryType byte, slot int16, expiration time.Time) error {
+	return producer.ProviderImpl(p.l)(p.ctx)(compartment.EnvCommandTopic)(RequestChangeExpirationCommandProvider(transactionId, characterId, inventoryType, slot, expiration))
+}
+
+// RequestSetUpgradeSlots requests the upgrade slots of an equipable asset, and the number of hammers applied to it, be set
+func (p *ProcessorImpl) RequestSetUpgradeSlots(transactionId uuid.UUID, characterId uint32, slot int16, slots uint16, hammersApplied uint32) error {
+	return producer.ProviderImpl(p.l)(p.ctx)(compartment.EnvCommandTopic)(RequestSetUpgradeSlotsCommandProvider(transactionId, characterId, slot, slots, hammersApplied))
+}
+
+// RequestReserveAsset requests the asset in a slot of a character's compartment be reserved for the transaction, so it
+// cannot be traded, dropped or otherwise moved until the reservation is cancelled or consumed
+func (p *ProcessorImpl) RequestReserveAsset(transactionId uuid.UUID, characterId uint32, inventoryType byte, slot int16, templateId uint32, quantity uint32) error {
+	return producer.ProviderImpl(p.l)(p.ctx)(compartment.EnvCommandTopic)(RequestReserveAssetCommandProvider(transactionId, characterId, inventoryType, slot, templateId, quantity))
+}
+
+// RequestCancelReservation requests the transaction's reservation of the asset in a slot of a character's compartment be
+// cancelled, freeing the asset
+func (p *ProcessorImpl) RequestCancelReservation(transactionId uuid.UUID, characterId uint32, inventoryType byte, slot int16) error {
+	return producer.ProviderImpl(p.l)(p.ctx)(compartment.EnvCommandTopic)(RequestCancelReservationCommandProvider(transactionId, characterId, inventoryType, slot))
+}
+
+// RequestConsumeAsset requests the asset in a slot of a character's compartment, reserved for the transaction, be
+// consumed
+func (p *ProcessorImpl) RequestConsumeAsset(transactionId uuid.UUID, characterId uint32, inventoryType byte, slot int16) error {
+	return producer.ProviderImpl(p.l)(p.ctx)(compartment.EnvCommandTopic)(RequestConsumeAssetCommandProvider(transactionId, characterId, inventoryType, slot))
+}
+
 func (p *ProcessorImpl) RequestCreateAndEquipAsset(transactionId uuid.UUID, payload CreateAndEquipAssetPayload) error {
 	// This method internally uses the same award_asset semantics as RequestCreateItem
 	// The subsequent equip_asset step will be dynamically created by the compartment consumer
@@ -29,6 +29,25 @@ func RequestCreateAssetCommandProvider(transactionId uuid.UUID, characterId uint
 	return producer.SingleMessageProvider(key, value)
 }
 
+func RequestRestoreAssetCommandProvider(transactionId uuid.UUID, characterId uint32, inventoryType inventory.Type, templateId uint32, quantity uint32, expiration time.Time, ownerId uint32, flag uint16, rechargeable uint64) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(characterId))
+	value := &compartment.Command[compartment.CreateAssetCommandBody]{
+		TransactionId: transactionId,
+		CharacterId:   characterId,
+		InventoryType: byte(inventoryType),
+		Type:          compartment.CommandCreateAsset,
+		Body: compartment.CreateAssetCommandBody{
+			TemplateId:   templateId,
+			Quantity:     quantity,
+			Expiration:   expiration,
+			OwnerId:      ownerId,
+			Flag:         flag,
+			Rechargeable: rechargeable,
+		},
+	}
+	return producer.SingleMessageProvider(key, value)
+}
+
 func RequestDestroyAssetCommandProvider(transactionId uuid.UUID, characterId uint32, inventoryType inventory.Type, slot int16, quantity uint32) model.Provider[[]kafka.Message] {
 	key := producer.CreateKey(int(characterId))
 	value := &compartment.Command[compartment.DestroyCommandBody]{
@@ -59,6 +78,129 @@ func RequestEquipAssetCommandProvider(transactionId uuid.UUID, characterId uint3
 	return producer.SingleMessageProvider(key, value)
 }
 
+func RequestMoveAssetCommandProvider(transactionId uuid.UUID, characterId uint32, inventoryType byte, source int16, destination int16) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(characterId))
+	value := &compartment.Command[compartment.MoveCommandBody]{
+		TransactionId: transactionId,
+		CharacterId:   characterId,
+		InventoryType: inventoryType,
+		Type:          compartment.CommandMove,
+		Body: compartment.MoveCommandBody{
+			Source:      source,
+			Destination: destination,
+		},
+	}
+	return producer.SingleMessageProvider(key, value)
+}
+
+func RequestRestoreSnapshotCommandProvider(transactionId uuid.UUID, characterId uint32, inventoryType byte, snapshotId uint32) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(characterId))
+	value := &compartment.Command[compartment.RestoreSnapshotCommandBody]{
+		TransactionId: transactionId,
+		CharacterId:   characterId,
+		InventoryType: inventoryType,
+		Type:          compartment.CommandRestoreSnapshot,
+		Body: compartment.RestoreSnapshotCommandBody{
+			SnapshotId: snapshotId,
+		},
+	}
+	return producer.SingleMessageProvider(key, value)
+}
+
+func RequestChangeDurabilityCommandProvider(transactionId uuid.UUID, characterId uint32, percent int8) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(characterId))
+	value := &compartment.Command[compartment.ChangeDurabilityCommandBody]{
+		TransactionId: transactionId,
+		CharacterId:   characterId,
+		InventoryType: byte(inventory.TypeValueEquip),
+		Type:          compartment.CommandChangeDurability,
+		Body: compartment.ChangeDurabilityCommandBody{
+			Percent: percent,
+		},
+	}
+	return producer.SingleMessageProvider(key, value)
+}
+
+func RequestChangeExpirationCommandProvider(transactionId uuid.UUID, characterId uint32, inventoryType byte, slot int16, expiration time.Time) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(characterId))
+	value := &compartment.Command[compartment.ChangeExpirationCommandBody]{
+		TransactionId: transactionId,
+		CharacterId:   characterId,
+		InventoryType: inventoryType,
+		Type:          compartment.CommandChangeExpiration,
+		Body: compartment.ChangeExpirationCommandBody{
+			Slot:       slot,
+			Expiration: expiration,
+		},
+	}
+	return producer.SingleMessageProvider(key, value)
+}
+
+func RequestSetUpgradeSlotsCommandProvider(transactionId uuid.UUID, characterId uint32, slot int16, slots uint16, hammersApplied uint32) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(characterId))
+	value := &compartment.Command[compartment.SetUpgradeSlotsCommandBody]{
+		TransactionId: transactionId,
+		CharacterId:   characterId,
+		InventoryType: byte(inventory.TypeValueEquip),
+		Type:          compartment.CommandSetUpgradeSlots,
+		Body: compartment.SetUpgradeSlotsCommandBody{
+			Slot:           slot,
+			Slots:          slots,
+			HammersApplied: hammersApplied,
+		},
+	}
+	return producer.SingleMessageProvider(key, value)
+}
+
+func RequestReserveAssetCommandProvider(transactionId uuid.UUID, characterId uint32, inventoryType byte, slot int16, templateId uint32, quantity uint32) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(characterId))
+	value := &compartment.Command[compartment.RequestReserveCommandBody]{
+		TransactionId: transactionId,
+		CharacterId:   characterId,
+		InventoryType: inventoryType,
+		Type:          compartment.CommandRequestReserve,
+		Body: compartment.RequestReserveCommandBody{
+			TransactionId: transactionId,
+			Items: []compartment.ItemBody{{
+				Source:   slot,
+				ItemId:   templateId,
+				Quantity: int16(quantity),
+			}},
+		},
+	}
+	return producer.SingleMessageProvider(key, value)
+}
+
+func RequestCancelReservationCommandProvider(transactionId uuid.UUID, characterId uint32, inventoryType byte, slot int16) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(characterId))
+	value := &compartment.Command[compartment.CancelReservationCommandBody]{
+		TransactionId: transactionId,
+		CharacterId:   characterId,
+		InventoryType: inventoryType,
+		Type:          compartment.CommandCancelReservation,
+		Body: compartment.CancelReservationCommandBody{
+			TransactionId: transactionId,
+			Slot:          slot,
+		},
+	}
+	return producer.SingleMessageProvider(key, value)
+}
+
+func RequestConsumeAssetCommandProvider(transactionId uuid.UUID, characterId uint32, inventoryType byte, slot int16) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(characterId))
+	value := &compartment.Command[compartment.ConsumeCommandBody]{
+		TransactionId: transactionId,
+		CharacterId:   characterId,
+		InventoryType: inventoryType,
+		Type:          compartment.CommandConsume,
+		Body: compartment.ConsumeCommandBody{
+			TransactionId: transactionId,
+			Slot:          slot,
+		},
+	}
+	return producer.SingleMessageProvider(key, value)
+}
+
 func RequestUnequipAssetCommandProvider(transactionId uuid.UUID, characterId uint32, inventoryType byte, source int16, destination int16) model.Provider[[]kafka.Message] {
 	key := producer.CreateKey(int(characterId))
 	value := &compartment.Command[compartment.UnequipCommandBody]{
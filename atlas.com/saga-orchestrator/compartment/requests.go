@@ -0,0 +1,25 @@
+package compartment
+
+import (
+	"atlas-saga-orchestrator/rest"
+	"fmt"
+	"github.com/Chronicle20/atlas-constants/inventory"
+	"github.com/Chronicle20/atlas-rest/requests"
+)
+
+const (
+	compartmentByType = "characters/%d/inventory/compartments?type=%d&include=assets"
+	holdersOfItem     = "items/%d/holders?page[after]=%d&page[size]=%d"
+)
+
+func getBaseRequest() string {
+	return requests.RootUrl("INVENTORY")
+}
+
+func requestByType(characterId uint32, inventoryType inventory.Type) requests.Request[RestModel] {
+	return rest.MakeGetRequest[RestModel](fmt.Sprintf(getBaseRequest()+compartmentByType, characterId, inventoryType))
+}
+
+func requestHolders(templateId uint32, after uint32, size uint32) requests.Request[[]HolderRestModel] {
+	return rest.MakeGetRequest[[]HolderRestModel](fmt.Sprintf(getBaseRequest()+holdersOfItem, templateId, after, size))
+}
@@ -0,0 +1,213 @@
+package compartment
+
+import (
+	"atlas-saga-orchestrator/asset"
+	"encoding/json"
+	"github.com/Chronicle20/atlas-constants/inventory"
+	"github.com/google/uuid"
+	"github.com/jtumidanski/api2go/jsonapi"
+	"strconv"
+	"time"
+)
+
+const assetsRelationship = "assets"
+
+type RestModel struct {
+	Id            uuid.UUID        `json:"-"`
+	InventoryType byte             `json:"type"`
+	Capacity      uint32           `json:"capacity"`
+	Assets        []AssetRestModel `json:"-"`
+}
+
+func (r RestModel) GetName() string {
+	return "compartments"
+}
+
+func (r RestModel) GetID() string {
+	return r.Id.String()
+}
+
+func (r *RestModel) SetID(strId string) error {
+	id, err := uuid.Parse(strId)
+	if err != nil {
+		return err
+	}
+	r.Id = id
+	return nil
+}
+
+func (r RestModel) GetReferences() []jsonapi.Reference {
+	return []jsonapi.Reference{
+		{
+			Type:         AssetRestModel{}.GetName(),
+			Name:         assetsRelationship,
+			Relationship: jsonapi.ToManyRelationship,
+		},
+	}
+}
+
+func (r RestModel) GetReferencedIDs() []jsonapi.ReferenceID {
+	var result []jsonapi.ReferenceID
+	for _, a := range r.Assets {
+		result = append(result, jsonapi.ReferenceID{
+			ID:           a.GetID(),
+			Type:         a.GetName(),
+			Name:         assetsRelationship,
+			Relationship: jsonapi.ToManyRelationship,
+		})
+	}
+	return result
+}
+
+func (r RestModel) GetReferencedStructs() []jsonapi.MarshalIdentifier {
+	var result []jsonapi.MarshalIdentifier
+	for _, a := range r.Assets {
+		result = append(result, a)
+	}
+	return result
+}
+
+func (r *RestModel) SetToManyReferenceIDs(name string, IDs []string) error {
+	if name != assetsRelationship {
+		return nil
+	}
+	r.Assets = make([]AssetRestModel, 0, len(IDs))
+	for _, id := range IDs {
+		a := AssetRestModel{}
+		if err := a.SetID(id); err != nil {
+			return err
+		}
+		r.Assets = append(r.Assets, a)
+	}
+	return nil
+}
+
+func (r *RestModel) SetReferencedStructs(references map[string]map[string]jsonapi.Data) error {
+	included, ok := references[AssetRestModel{}.GetName()]
+	if !ok {
+		return nil
+	}
+	for i, a := range r.Assets {
+		d, ok := included[a.GetID()]
+		if !ok {
+			continue
+		}
+		if err := json.Unmarshal(d.Attributes, &r.Assets[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type AssetRestModel struct {
+	Id            uint32          `json:"-"`
+	Slot          int16           `json:"slot"`
+	TemplateId    uint32          `json:"templateId"`
+	Expiration    time.Time       `json:"expiration"`
+	ReferenceId   uint32          `json:"referenceId"`
+	ReferenceType string          `json:"referenceType"`
+	ReferenceData json.RawMessage `json:"referenceData,omitempty"`
+}
+
+// equipableRestData is the subset of an equipable asset's reference data used in orchestration
+type equipableRestData struct {
+	Slots          uint16 `json:"slots"`
+	HammersApplied uint32 `json:"hammersApplied"`
+}
+
+// stackableRestData is the subset of a consumable, setup or etc asset's reference data used in orchestration
+type stackableRestData struct {
+	Quantity uint32 `json:"quantity"`
+}
+
+// petRestData is the subset of a pet asset's reference data used in orchestration
+type petRestData struct {
+	Slot int8 `json:"slot"`
+}
+
+func (r AssetRestModel) GetName() string {
+	return "assets"
+}
+
+func (r AssetRestModel) GetID() string {
+	return strconv.Itoa(int(r.Id))
+}
+
+func (r *AssetRestModel) SetID(strId string) error {
+	id, err := strconv.Atoi(strId)
+	if err != nil {
+		return err
+	}
+	r.Id = uint32(id)
+	return nil
+}
+
+func Extract(rm RestModel) (Model, error) {
+	b := NewBuilder(rm.Id, 0, inventory.Type(rm.InventoryType), rm.Capacity)
+	for _, a := range rm.Assets {
+		ab := asset.NewBuilder[any](a.Id, rm.Id, a.TemplateId, a.ReferenceId, asset.ReferenceType(a.ReferenceType)).
+			SetSlot(a.Slot).
+			SetExpiration(a.Expiration)
+		if len(a.ReferenceData) > 0 {
+			switch asset.ReferenceType(a.ReferenceType) {
+			case asset.ReferenceTypeEquipable:
+				var rd equipableRestData
+				if err := json.Unmarshal(a.ReferenceData, &rd); err != nil {
+					return Model{}, err
+				}
+				ab.SetReferenceData(asset.NewEquipableReferenceDataBuilder().
+					SetSlots(rd.Slots).
+					SetHammersApplied(rd.HammersApplied).
+					Build())
+			case asset.ReferenceTypeConsumable, asset.ReferenceTypeSetup, asset.ReferenceTypeEtc:
+				var rd stackableRestData
+				if err := json.Unmarshal(a.ReferenceData, &rd); err != nil {
+					return Model{}, err
+				}
+				switch asset.ReferenceType(a.ReferenceType) {
+				case asset.ReferenceTypeConsumable:
+					ab.SetReferenceData(asset.NewConsumableReferenceDataBuilder().SetQuantity(rd.Quantity).Build())
+				case asset.ReferenceTypeSetup:
+					ab.SetReferenceData(asset.NewSetupReferenceDataBuilder().SetQuantity(rd.Quantity).Build())
+				default:
+					ab.SetReferenceData(asset.NewEtcReferenceDataBuilder().SetQuantity(rd.Quantity).Build())
+				}
+			case asset.ReferenceTypePet:
+				var rd petRestData
+				if err := json.Unmarshal(a.ReferenceData, &rd); err != nil {
+					return Model{}, err
+				}
+				ab.SetReferenceData(asset.NewPetReferenceDataBuilder().SetSlot(rd.Slot).Build())
+			}
+		}
+		b.AddAsset(ab.Build())
+	}
+	return b.Build(), nil
+}
+
+// HolderRestModel is a character holding an item, identified by the character
+type HolderRestModel struct {
+	Id       string `json:"-"`
+	Quantity uint32 `json:"quantity"`
+}
+
+func (r HolderRestModel) GetName() string {
+	return "holders"
+}
+
+func (r HolderRestModel) GetID() string {
+	return r.Id
+}
+
+func (r *HolderRestModel) SetID(id string) error {
+	r.Id = id
+	return nil
+}
+
+func ExtractHolder(rm HolderRestModel) (Holder, error) {
+	characterId, err := strconv.ParseUint(rm.Id, 10, 32)
+	if err != nil {
+		return Holder{}, err
+	}
+	return NewHolder(uint32(characterId), rm.Quantity), nil
+}
@@ -0,0 +1,72 @@
+package mock
+
+import (
+	"atlas-saga-orchestrator/coupon"
+	"atlas-saga-orchestrator/kafka/message"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/google/uuid"
+)
+
+// ProcessorMock is a mock implementation of the coupon.Processor interface
+type ProcessorMock struct {
+	GetByCodeFunc      func(code string) (coupon.Model, error)
+	ByCodeProviderFunc func(code string) model.Provider[coupon.Model]
+	ConsumeAndEmitFunc func(transactionId uuid.UUID, accountId uint32, characterId uint32, code string) error
+	ConsumeFunc        func(mb *message.Buffer) func(transactionId uuid.UUID, accountId uint32, characterId uint32, code string) error
+	ReleaseAndEmitFunc func(transactionId uuid.UUID, accountId uint32, characterId uint32, code string) error
+	ReleaseFunc        func(mb *message.Buffer) func(transactionId uuid.UUID, accountId uint32, characterId uint32, code string) error
+}
+
+// GetByCode is a mock implementation of the coupon.Processor.GetByCode method
+func (m *ProcessorMock) GetByCode(code string) (coupon.Model, error) {
+	if m.GetByCodeFunc != nil {
+		return m.GetByCodeFunc(code)
+	}
+	return coupon.Model{}, nil
+}
+
+// ByCodeProvider is a mock implementation of the coupon.Processor.ByCodeProvider method
+func (m *ProcessorMock) ByCodeProvider(code string) model.Provider[coupon.Model] {
+	if m.ByCodeProviderFunc != nil {
+		return m.ByCodeProviderFunc(code)
+	}
+	return func() (coupon.Model, error) {
+		return m.GetByCode(code)
+	}
+}
+
+// ConsumeAndEmit is a mock implementation of the coupon.Processor.ConsumeAndEmit method
+func (m *ProcessorMock) ConsumeAndEmit(transactionId uuid.UUID, accountId uint32, characterId uint32, code string) error {
+	if m.ConsumeAndEmitFunc != nil {
+		return m.ConsumeAndEmitFunc(transactionId, accountId, characterId, code)
+	}
+	return nil
+}
+
+// Consume is a mock implementation of the coupon.Processor.Consume method
+func (m *ProcessorMock) Consume(mb *message.Buffer) func(transactionId uuid.UUID, accountId uint32, characterId uint32, code string) error {
+	if m.ConsumeFunc != nil {
+		return m.ConsumeFunc(mb)
+	}
+	return func(transactionId uuid.UUID, accountId uint32, characterId uint32, code string) error {
+		return nil
+	}
+}
+
+// ReleaseAndEmit is a mock implementation of the coupon.Processor.ReleaseAndEmit method
+func (m *ProcessorMock) ReleaseAndEmit(transactionId uuid.UUID, accountId uint32, characterId uint32, code string) error {
+	if m.ReleaseAndEmitFunc != nil {
+		return m.ReleaseAndEmitFunc(transactionId, accountId, characterId, code)
+	}
+	return nil
+}
+
+// Release is a mock implementation of the coupon.Processor.Release method
+func (m *ProcessorMock) Release(mb *message.Buffer) func(transactionId uuid.UUID, accountId uint32, characterId uint32, code string) error {
+	if m.ReleaseFunc != nil {
+		return m.ReleaseFunc(mb)
+	}
+	return func(transactionId uuid.UUID, accountId uint32, characterId uint32, code string) error {
+		return nil
+	}
+}
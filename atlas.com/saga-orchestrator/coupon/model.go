@@ -0,0 +1,133 @@
+package coupon
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	StatusAvailable = "AVAILABLE"
+	StatusConsumed  = "CONSUMED"
+)
+
+var (
+	ErrNotAvailable    = errors.New("coupon is not available")
+	ErrExpired         = errors.New("coupon has expired")
+	ErrAccountMismatch = errors.New("coupon is not issued to the account")
+)
+
+type Model struct {
+	code      string
+	status    string
+	accountId uint32
+	expiresAt time.Time
+	mesos     int32
+	items     []Item
+}
+
+func (m Model) Code() string {
+	return m.code
+}
+
+func (m Model) Status() string {
+	return m.status
+}
+
+// AccountId is the account the coupon is issued to, or 0 if it may be redeemed by any account
+func (m Model) AccountId() uint32 {
+	return m.accountId
+}
+
+func (m Model) ExpiresAt() time.Time {
+	return m.expiresAt
+}
+
+func (m Model) Mesos() int32 {
+	return m.mesos
+}
+
+func (m Model) Items() []Item {
+	return m.items
+}
+
+// Redeemable returns an error describing why the coupon cannot be redeemed by the account at the given time, if it cannot
+func (m Model) Redeemable(accountId uint32, now time.Time) error {
+	if m.status != StatusAvailable {
+		return ErrNotAvailable
+	}
+	if !m.expiresAt.IsZero() && !now.Before(m.expiresAt) {
+		return ErrExpired
+	}
+	if m.accountId != 0 && m.accountId != accountId {
+		return ErrAccountMismatch
+	}
+	return nil
+}
+
+type Item struct {
+	templateId uint32
+	quantity   uint32
+}
+
+func (i Item) TemplateId() uint32 {
+	return i.templateId
+}
+
+func (i Item) Quantity() uint32 {
+	return i.quantity
+}
+
+func NewItem(templateId uint32, quantity uint32) Item {
+	return Item{
+		templateId: templateId,
+		quantity:   quantity,
+	}
+}
+
+type ModelBuilder struct {
+	code      string
+	status    string
+	accountId uint32
+	expiresAt time.Time
+	mesos     int32
+	items     []Item
+}
+
+func NewBuilder(code string, status string) *ModelBuilder {
+	return &ModelBuilder{
+		code:   code,
+		status: status,
+		items:  make([]Item, 0),
+	}
+}
+
+func (b *ModelBuilder) SetAccountId(accountId uint32) *ModelBuilder {
+	b.accountId = accountId
+	return b
+}
+
+func (b *ModelBuilder) SetExpiresAt(expiresAt time.Time) *ModelBuilder {
+	b.expiresAt = expiresAt
+	return b
+}
+
+func (b *ModelBuilder) SetMesos(mesos int32) *ModelBuilder {
+	b.mesos = mesos
+	return b
+}
+
+func (b *ModelBuilder) AddItem(i Item) *ModelBuilder {
+	b.items = append(b.items, i)
+	return b
+}
+
+func (b *ModelBuilder) Build() Model {
+	return Model{
+		code:      b.code,
+		status:    b.status,
+		accountId: b.accountId,
+		expiresAt: b.expiresAt,
+		mesos:     b.mesos,
+		items:     b.items,
+	}
+}
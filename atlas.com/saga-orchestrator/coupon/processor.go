@@ -0,0 +1,70 @@
+package coupon
+
+import (
+	"atlas-saga-orchestrator/kafka/message"
+	coupon2 "atlas-saga-orchestrator/kafka/message/coupon"
+	"atlas-saga-orchestrator/kafka/producer"
+	"context"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/Chronicle20/atlas-rest/requests"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type Processor interface {
+	GetByCode(code string) (Model, error)
+	ByCodeProvider(code string) model.Provider[Model]
+	ConsumeAndEmit(transactionId uuid.UUID, accountId uint32, characterId uint32, code string) error
+	Consume(mb *message.Buffer) func(transactionId uuid.UUID, accountId uint32, characterId uint32, code string) error
+	ReleaseAndEmit(transactionId uuid.UUID, accountId uint32, characterId uint32, code string) error
+	Release(mb *message.Buffer) func(transactionId uuid.UUID, accountId uint32, characterId uint32, code string) error
+}
+
+type ProcessorImpl struct {
+	l   logrus.FieldLogger
+	ctx context.Context
+	p   producer.Provider
+}
+
+func NewProcessor(l logrus.FieldLogger, ctx context.Context) Processor {
+	return &ProcessorImpl{
+		l:   l,
+		ctx: ctx,
+		p:   producer.ProviderImpl(l)(ctx),
+	}
+}
+
+func (p *ProcessorImpl) GetByCode(code string) (Model, error) {
+	return p.ByCodeProvider(code)()
+}
+
+func (p *ProcessorImpl) ByCodeProvider(code string) model.Provider[Model] {
+	return requests.Provider[RestModel, Model](p.l, p.ctx)(requestByCode(code), Extract)
+}
+
+// ConsumeAndEmit requests the coupon be marked consumed. The transaction identifies the redemption, so a redelivered
+// command does not consume the coupon twice.
+func (p *ProcessorImpl) ConsumeAndEmit(transactionId uuid.UUID, accountId uint32, characterId uint32, code string) error {
+	return message.Emit(p.p)(func(mb *message.Buffer) error {
+		return p.Consume(mb)(transactionId, accountId, characterId, code)
+	})
+}
+
+func (p *ProcessorImpl) Consume(mb *message.Buffer) func(transactionId uuid.UUID, accountId uint32, characterId uint32, code string) error {
+	return func(transactionId uuid.UUID, accountId uint32, characterId uint32, code string) error {
+		return mb.Put(coupon2.EnvCommandTopic, ConsumeProvider(transactionId, accountId, characterId, code))
+	}
+}
+
+// ReleaseAndEmit requests the consumption of the coupon by the transaction be reversed, so it may be redeemed again
+func (p *ProcessorImpl) ReleaseAndEmit(transactionId uuid.UUID, accountId uint32, characterId uint32, code string) error {
+	return message.Emit(p.p)(func(mb *message.Buffer) error {
+		return p.Release(mb)(transactionId, accountId, characterId, code)
+	})
+}
+
+func (p *ProcessorImpl) Release(mb *message.Buffer) func(transactionId uuid.UUID, accountId uint32, characterId uint32, code string) error {
+	return func(transactionId uuid.UUID, accountId uint32, characterId uint32, code string) error {
+		return mb.Put(coupon2.EnvCommandTopic, ReleaseProvider(transactionId, accountId, characterId, code))
+	}
+}
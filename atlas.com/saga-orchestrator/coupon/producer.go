@@ -0,0 +1,37 @@
+package coupon
+
+import (
+	coupon2 "atlas-saga-orchestrator/kafka/message/coupon"
+	"github.com/Chronicle20/atlas-kafka/producer"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+)
+
+func ConsumeProvider(transactionId uuid.UUID, accountId uint32, characterId uint32, code string) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(accountId))
+	value := &coupon2.Command[coupon2.ConsumeCommandBody]{
+		TransactionId: transactionId,
+		AccountId:     accountId,
+		CharacterId:   characterId,
+		Type:          coupon2.CommandTypeConsume,
+		Body: coupon2.ConsumeCommandBody{
+			Code: code,
+		},
+	}
+	return producer.SingleMessageProvider(key, value)
+}
+
+func ReleaseProvider(transactionId uuid.UUID, accountId uint32, characterId uint32, code string) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(accountId))
+	value := &coupon2.Command[coupon2.ReleaseCommandBody]{
+		TransactionId: transactionId,
+		AccountId:     accountId,
+		CharacterId:   characterId,
+		Type:          coupon2.CommandTypeRelease,
+		Body: coupon2.ReleaseCommandBody{
+			Code: code,
+		},
+	}
+	return producer.SingleMessageProvider(key, value)
+}
@@ -0,0 +1,20 @@
+package coupon
+
+import (
+	"atlas-saga-orchestrator/rest"
+	"fmt"
+	"github.com/Chronicle20/atlas-rest/requests"
+	"net/url"
+)
+
+const (
+	couponByCode = "coupons/%s"
+)
+
+func getBaseRequest() string {
+	return requests.RootUrl("COUPONS")
+}
+
+func requestByCode(code string) requests.Request[RestModel] {
+	return rest.MakeGetRequest[RestModel](fmt.Sprintf(getBaseRequest()+couponByCode, url.PathEscape(code)))
+}
@@ -0,0 +1,43 @@
+package coupon
+
+import (
+	"time"
+)
+
+type RestModel struct {
+	Id        string          `json:"-"`
+	Status    string          `json:"status"`
+	AccountId uint32          `json:"accountId"`
+	ExpiresAt time.Time       `json:"expiresAt"`
+	Mesos     int32           `json:"mesos"`
+	Items     []ItemRestModel `json:"items"`
+}
+
+type ItemRestModel struct {
+	TemplateId uint32 `json:"templateId"`
+	Quantity   uint32 `json:"quantity"`
+}
+
+func (r RestModel) GetName() string {
+	return "coupons"
+}
+
+func (r RestModel) GetID() string {
+	return r.Id
+}
+
+func (r *RestModel) SetID(id string) error {
+	r.Id = id
+	return nil
+}
+
+func Extract(rm RestModel) (Model, error) {
+	b := NewBuilder(rm.Id, rm.Status).
+		SetAccountId(rm.AccountId).
+		SetExpiresAt(rm.ExpiresAt).
+		SetMesos(rm.Mesos)
+	for _, i := range rm.Items {
+		b.AddItem(NewItem(i.TemplateId, i.Quantity))
+	}
+	return b.Build(), nil
+}
@@ -0,0 +1,30 @@
+package mock
+
+import (
+	"atlas-saga-orchestrator/data/equipment"
+	"github.com/Chronicle20/atlas-model/model"
+)
+
+// ProcessorMock is a mock implementation of the equipment.Processor interface
+type ProcessorMock struct {
+	ByIdProviderFunc func(templateId uint32) model.Provider[equipment.Model]
+	GetByIdFunc      func(templateId uint32) (equipment.Model, error)
+}
+
+// ByIdProvider is a mock implementation of the equipment.Processor.ByIdProvider method
+func (m *ProcessorMock) ByIdProvider(templateId uint32) model.Provider[equipment.Model] {
+	if m.ByIdProviderFunc != nil {
+		return m.ByIdProviderFunc(templateId)
+	}
+	return func() (equipment.Model, error) {
+		return m.GetById(templateId)
+	}
+}
+
+// GetById is a mock implementation of the equipment.Processor.GetById method
+func (m *ProcessorMock) GetById(templateId uint32) (equipment.Model, error) {
+	if m.GetByIdFunc != nil {
+		return m.GetByIdFunc(templateId)
+	}
+	return equipment.NewBuilder(templateId).Build(), nil
+}
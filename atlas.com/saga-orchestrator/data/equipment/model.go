@@ -0,0 +1,61 @@
+package equipment
+
+import "github.com/Chronicle20/atlas-constants/job"
+
+// Job requirement bits of equipment, by job category. Equipment without any bit set may be worn by every job.
+const (
+	ReqJobWarrior  uint16 = 1 << 0
+	ReqJobMagician uint16 = 1 << 1
+	ReqJobBowman   uint16 = 1 << 2
+	ReqJobThief    uint16 = 1 << 3
+	ReqJobPirate   uint16 = 1 << 4
+)
+
+type Model struct {
+	id     uint32
+	reqJob uint16
+}
+
+func (m Model) Id() uint32 {
+	return m.id
+}
+
+func (m Model) ReqJob() uint16 {
+	return m.reqJob
+}
+
+// WearableBy returns whether a character of the job meets the equipment's job requirement. Jobs are categorized by their
+// hundreds digit, beginners being of no category, and so only able to wear equipment without a requirement.
+func (m Model) WearableBy(jobId job.Id) bool {
+	if m.reqJob == 0 {
+		return true
+	}
+	category := (uint16(jobId) / 100) % 10
+	if category == 0 || category > 5 {
+		return false
+	}
+	return m.reqJob&(1<<(category-1)) != 0
+}
+
+type ModelBuilder struct {
+	id     uint32
+	reqJob uint16
+}
+
+func NewBuilder(id uint32) *ModelBuilder {
+	return &ModelBuilder{
+		id: id,
+	}
+}
+
+func (b *ModelBuilder) SetReqJob(reqJob uint16) *ModelBuilder {
+	b.reqJob = reqJob
+	return b
+}
+
+func (b *ModelBuilder) Build() Model {
+	return Model{
+		id:     b.id,
+		reqJob: b.reqJob,
+	}
+}
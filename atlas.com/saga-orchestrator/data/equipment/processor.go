@@ -0,0 +1,34 @@
+package equipment
+
+import (
+	"context"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/Chronicle20/atlas-rest/requests"
+	"github.com/sirupsen/logrus"
+)
+
+type Processor interface {
+	ByIdProvider(templateId uint32) model.Provider[Model]
+	GetById(templateId uint32) (Model, error)
+}
+
+type ProcessorImpl struct {
+	l   logrus.FieldLogger
+	ctx context.Context
+}
+
+func NewProcessor(l logrus.FieldLogger, ctx context.Context) Processor {
+	p := &ProcessorImpl{
+		l:   l,
+		ctx: ctx,
+	}
+	return p
+}
+
+func (p *ProcessorImpl) ByIdProvider(templateId uint32) model.Provider[Model] {
+	return requests.Provider[RestModel, Model](p.l, p.ctx)(requestById(templateId), Extract)
+}
+
+func (p *ProcessorImpl) GetById(templateId uint32) (Model, error) {
+	return p.ByIdProvider(templateId)()
+}
@@ -0,0 +1,19 @@
+package equipment
+
+import (
+	"atlas-saga-orchestrator/rest"
+	"fmt"
+	"github.com/Chronicle20/atlas-rest/requests"
+)
+
+const (
+	equipmentById = "data/equipment/%d"
+)
+
+func getBaseRequest() string {
+	return requests.RootUrl("DATA")
+}
+
+func requestById(templateId uint32) requests.Request[RestModel] {
+	return rest.MakeGetRequest[RestModel](fmt.Sprintf(getBaseRequest()+equipmentById, templateId))
+}
@@ -0,0 +1,33 @@
+package equipment
+
+import "strconv"
+
+type RestModel struct {
+	Id     string `json:"-"`
+	ReqJob uint16 `json:"reqJob"`
+}
+
+func (r RestModel) GetName() string {
+	return "statistics"
+}
+
+func (r RestModel) GetID() string {
+	return r.Id
+}
+
+func (r *RestModel) SetID(id string) error {
+	r.Id = id
+	return nil
+}
+
+func Extract(rm RestModel) (Model, error) {
+	id, err := strconv.Atoi(rm.Id)
+	if err != nil {
+		return Model{}, err
+	}
+
+	return Model{
+		id:     uint32(id),
+		reqJob: rm.ReqJob,
+	}, nil
+}
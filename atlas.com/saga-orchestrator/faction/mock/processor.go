@@ -0,0 +1,31 @@
+package mock
+
+import (
+	"atlas-saga-orchestrator/kafka/message"
+	"github.com/Chronicle20/atlas-constants/world"
+	"github.com/google/uuid"
+)
+
+// ProcessorMock is a mock implementation of the faction.Processor interface
+type ProcessorMock struct {
+	ChangePointsAndEmitFunc func(transactionId uuid.UUID, worldId world.Id, characterId uint32, factionId uint32, amount int32) error
+	ChangePointsFunc        func(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, factionId uint32, amount int32) error
+}
+
+// ChangePointsAndEmit is a mock implementation of the faction.Processor.ChangePointsAndEmit method
+func (m *ProcessorMock) ChangePointsAndEmit(transactionId uuid.UUID, worldId world.Id, characterId uint32, factionId uint32, amount int32) error {
+	if m.ChangePointsAndEmitFunc != nil {
+		return m.ChangePointsAndEmitFunc(transactionId, worldId, characterId, factionId, amount)
+	}
+	return nil
+}
+
+// ChangePoints is a mock implementation of the faction.Processor.ChangePoints method
+func (m *ProcessorMock) ChangePoints(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, factionId uint32, amount int32) error {
+	if m.ChangePointsFunc != nil {
+		return m.ChangePointsFunc(mb)
+	}
+	return func(transactionId uuid.UUID, worldId world.Id, characterId uint32, factionId uint32, amount int32) error {
+		return nil
+	}
+}
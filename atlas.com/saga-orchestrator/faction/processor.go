@@ -0,0 +1,44 @@
+package faction
+
+import (
+	"atlas-saga-orchestrator/kafka/message"
+	faction2 "atlas-saga-orchestrator/kafka/message/faction"
+	"atlas-saga-orchestrator/kafka/producer"
+	"context"
+	"github.com/Chronicle20/atlas-constants/world"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type Processor interface {
+	ChangePointsAndEmit(transactionId uuid.UUID, worldId world.Id, characterId uint32, factionId uint32, amount int32) error
+	ChangePoints(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, factionId uint32, amount int32) error
+}
+
+type ProcessorImpl struct {
+	l   logrus.FieldLogger
+	ctx context.Context
+	p   producer.Provider
+}
+
+func NewProcessor(l logrus.FieldLogger, ctx context.Context) Processor {
+	return &ProcessorImpl{
+		l:   l,
+		ctx: ctx,
+		p:   producer.ProviderImpl(l)(ctx),
+	}
+}
+
+// ChangePointsAndEmit requests the character's points with the faction be changed by the amount, which is negative to
+// deduct points
+func (p *ProcessorImpl) ChangePointsAndEmit(transactionId uuid.UUID, worldId world.Id, characterId uint32, factionId uint32, amount int32) error {
+	return message.Emit(p.p)(func(mb *message.Buffer) error {
+		return p.ChangePoints(mb)(transactionId, worldId, characterId, factionId, amount)
+	})
+}
+
+func (p *ProcessorImpl) ChangePoints(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, factionId uint32, amount int32) error {
+	return func(transactionId uuid.UUID, worldId world.Id, characterId uint32, factionId uint32, amount int32) error {
+		return mb.Put(faction2.EnvCommandTopic, ChangePointsProvider(transactionId, worldId, characterId, factionId, amount))
+	}
+}
@@ -0,0 +1,25 @@
+package faction
+
+import (
+	faction2 "atlas-saga-orchestrator/kafka/message/faction"
+	"github.com/Chronicle20/atlas-constants/world"
+	"github.com/Chronicle20/atlas-kafka/producer"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+)
+
+func ChangePointsProvider(transactionId uuid.UUID, worldId world.Id, characterId uint32, factionId uint32, amount int32) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(characterId))
+	value := &faction2.Command[faction2.ChangePointsCommandBody]{
+		TransactionId: transactionId,
+		WorldId:       worldId,
+		CharacterId:   characterId,
+		Type:          faction2.CommandTypeChangePoints,
+		Body: faction2.ChangePointsCommandBody{
+			FactionId: factionId,
+			Amount:    amount,
+		},
+	}
+	return producer.SingleMessageProvider(key, value)
+}
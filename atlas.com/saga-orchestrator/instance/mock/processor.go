@@ -0,0 +1,63 @@
+package mock
+
+import (
+	"atlas-saga-orchestrator/instance"
+	"atlas-saga-orchestrator/kafka/message"
+	"time"
+
+	"github.com/Chronicle20/atlas-constants/world"
+	"github.com/google/uuid"
+)
+
+// ProcessorMock is a mock implementation of the instance.Processor interface
+type ProcessorMock struct {
+	GetCooldownsFunc         func(characterId uint32) ([]instance.Cooldown, error)
+	ResetCooldownAndEmitFunc func(transactionId uuid.UUID, worldId world.Id, characterId uint32, instanceId uint32) error
+	ResetCooldownFunc        func(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, instanceId uint32) error
+	SetCooldownAndEmitFunc   func(transactionId uuid.UUID, worldId world.Id, characterId uint32, instanceId uint32, expiresAt time.Time) error
+	SetCooldownFunc          func(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, instanceId uint32, expiresAt time.Time) error
+}
+
+// GetCooldowns is a mock implementation of the instance.Processor.GetCooldowns method
+func (m *ProcessorMock) GetCooldowns(characterId uint32) ([]instance.Cooldown, error) {
+	if m.GetCooldownsFunc != nil {
+		return m.GetCooldownsFunc(characterId)
+	}
+	return []instance.Cooldown{}, nil
+}
+
+// ResetCooldownAndEmit is a mock implementation of the instance.Processor.ResetCooldownAndEmit method
+func (m *ProcessorMock) ResetCooldownAndEmit(transactionId uuid.UUID, worldId world.Id, characterId uint32, instanceId uint32) error {
+	if m.ResetCooldownAndEmitFunc != nil {
+		return m.ResetCooldownAndEmitFunc(transactionId, worldId, characterId, instanceId)
+	}
+	return nil
+}
+
+// ResetCooldown is a mock implementation of the instance.Processor.ResetCooldown method
+func (m *ProcessorMock) ResetCooldown(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, instanceId uint32) error {
+	if m.ResetCooldownFunc != nil {
+		return m.ResetCooldownFunc(mb)
+	}
+	return func(transactionId uuid.UUID, worldId world.Id, characterId uint32, instanceId uint32) error {
+		return nil
+	}
+}
+
+// SetCooldownAndEmit is a mock implementation of the instance.Processor.SetCooldownAndEmit method
+func (m *ProcessorMock) SetCooldownAndEmit(transactionId uuid.UUID, worldId world.Id, characterId uint32, instanceId uint32, expiresAt time.Time) error {
+	if m.SetCooldownAndEmitFunc != nil {
+		return m.SetCooldownAndEmitFunc(transactionId, worldId, characterId, instanceId, expiresAt)
+	}
+	return nil
+}
+
+// SetCooldown is a mock implementation of the instance.Processor.SetCooldown method
+func (m *ProcessorMock) SetCooldown(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, instanceId uint32, expiresAt time.Time) error {
+	if m.SetCooldownFunc != nil {
+		return m.SetCooldownFunc(mb)
+	}
+	return func(transactionId uuid.UUID, worldId world.Id, characterId uint32, instanceId uint32, expiresAt time.Time) error {
+		return nil
+	}
+}
@@ -0,0 +1,24 @@
+package instance
+
+import "time"
+
+// Cooldown is the time until which a character may not enter an instance, such as a dungeon or party quest, again
+type Cooldown struct {
+	instanceId uint32
+	expiresAt  time.Time
+}
+
+func (c Cooldown) InstanceId() uint32 {
+	return c.instanceId
+}
+
+func (c Cooldown) ExpiresAt() time.Time {
+	return c.expiresAt
+}
+
+func NewCooldown(instanceId uint32, expiresAt time.Time) Cooldown {
+	return Cooldown{
+		instanceId: instanceId,
+		expiresAt:  expiresAt,
+	}
+}
@@ -0,0 +1,68 @@
+package instance
+
+import (
+	"atlas-saga-orchestrator/kafka/message"
+	instance2 "atlas-saga-orchestrator/kafka/message/instance"
+	"atlas-saga-orchestrator/kafka/producer"
+	"context"
+	"time"
+
+	"github.com/Chronicle20/atlas-constants/world"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/Chronicle20/atlas-rest/requests"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type Processor interface {
+	GetCooldowns(characterId uint32) ([]Cooldown, error)
+	ResetCooldownAndEmit(transactionId uuid.UUID, worldId world.Id, characterId uint32, instanceId uint32) error
+	ResetCooldown(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, instanceId uint32) error
+	SetCooldownAndEmit(transactionId uuid.UUID, worldId world.Id, characterId uint32, instanceId uint32, expiresAt time.Time) error
+	SetCooldown(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, instanceId uint32, expiresAt time.Time) error
+}
+
+type ProcessorImpl struct {
+	l   logrus.FieldLogger
+	ctx context.Context
+	p   producer.Provider
+}
+
+func NewProcessor(l logrus.FieldLogger, ctx context.Context) Processor {
+	return &ProcessorImpl{
+		l:   l,
+		ctx: ctx,
+		p:   producer.ProviderImpl(l)(ctx),
+	}
+}
+
+// GetCooldowns returns the character's cooldowns of instances, including those which have expired
+func (p *ProcessorImpl) GetCooldowns(characterId uint32) ([]Cooldown, error) {
+	return requests.SliceProvider[CooldownRestModel, Cooldown](p.l, p.ctx)(requestCooldownsByCharacterId(characterId), ExtractCooldown, model.Filters[Cooldown]())()
+}
+
+// ResetCooldownAndEmit requests the character's cooldown of the instance be cleared, so the character may enter it again
+func (p *ProcessorImpl) ResetCooldownAndEmit(transactionId uuid.UUID, worldId world.Id, characterId uint32, instanceId uint32) error {
+	return message.Emit(p.p)(func(mb *message.Buffer) error {
+		return p.ResetCooldown(mb)(transactionId, worldId, characterId, instanceId)
+	})
+}
+
+func (p *ProcessorImpl) ResetCooldown(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, instanceId uint32) error {
+	return func(transactionId uuid.UUID, worldId world.Id, characterId uint32, instanceId uint32) error {
+		return mb.Put(instance2.EnvCommandTopic, ResetCooldownProvider(transactionId, worldId, characterId, instanceId))
+	}
+}
+
+// SetCooldownAndEmit requests the character's cooldown of the instance be set to expire at the given time
+func (p *ProcessorImpl) SetCooldownAndEmit(transactionId uuid.UUID, worldId world.Id, characterId uint32, instanceId uint32, expiresAt time.Time) error {
+	return message.Emit(p.p)(func(mb *message.Buffer) error {
+		return p.SetCooldown(mb)(transactionId, worldId, characterId, instanceId, expiresAt)
+	})
+}
+
+func (p *ProcessorImpl) SetCooldown(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, instanceId uint32, expiresAt time.Time) error {
+	return func(transactionId uuid.UUID, worldId world.Id, characterId uint32, instanceId uint32, expiresAt time.Time) error {
+		return mb.Put(instance2.EnvCommandTopic, SetCooldownProvider(transactionId, worldId, characterId, instanceId, expiresAt))
+	}
+}
@@ -0,0 +1,41 @@
+package instance
+
+import (
+	instance2 "atlas-saga-orchestrator/kafka/message/instance"
+	"time"
+
+	"github.com/Chronicle20/atlas-constants/world"
+	"github.com/Chronicle20/atlas-kafka/producer"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+)
+
+func ResetCooldownProvider(transactionId uuid.UUID, worldId world.Id, characterId uint32, instanceId uint32) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(characterId))
+	value := &instance2.Command[instance2.ResetCooldownCommandBody]{
+		TransactionId: transactionId,
+		WorldId:       worldId,
+		CharacterId:   characterId,
+		Type:          instance2.CommandTypeResetCooldown,
+		Body: instance2.ResetCooldownCommandBody{
+			InstanceId: instanceId,
+		},
+	}
+	return producer.SingleMessageProvider(key, value)
+}
+
+func SetCooldownProvider(transactionId uuid.UUID, worldId world.Id, characterId uint32, instanceId uint32, expiresAt time.Time) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(characterId))
+	value := &instance2.Command[instance2.SetCooldownCommandBody]{
+		TransactionId: transactionId,
+		WorldId:       worldId,
+		CharacterId:   characterId,
+		Type:          instance2.CommandTypeSetCooldown,
+		Body: instance2.SetCooldownCommandBody{
+			InstanceId: instanceId,
+			ExpiresAt:  expiresAt,
+		},
+	}
+	return producer.SingleMessageProvider(key, value)
+}
@@ -0,0 +1,19 @@
+package instance
+
+import (
+	"atlas-saga-orchestrator/rest"
+	"fmt"
+	"github.com/Chronicle20/atlas-rest/requests"
+)
+
+const (
+	cooldownsForCharacter = "characters/%d/cooldowns"
+)
+
+func getBaseRequest() string {
+	return requests.RootUrl("INSTANCES")
+}
+
+func requestCooldownsByCharacterId(characterId uint32) requests.Request[[]CooldownRestModel] {
+	return rest.MakeGetRequest[[]CooldownRestModel](fmt.Sprintf(getBaseRequest()+cooldownsForCharacter, characterId))
+}
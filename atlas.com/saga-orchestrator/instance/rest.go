@@ -0,0 +1,33 @@
+package instance
+
+import (
+	"strconv"
+	"time"
+)
+
+// CooldownRestModel is a character's cooldown of an instance, identified by the instance
+type CooldownRestModel struct {
+	Id        string    `json:"-"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+func (r CooldownRestModel) GetName() string {
+	return "cooldowns"
+}
+
+func (r CooldownRestModel) GetID() string {
+	return r.Id
+}
+
+func (r *CooldownRestModel) SetID(id string) error {
+	r.Id = id
+	return nil
+}
+
+func ExtractCooldown(rm CooldownRestModel) (Cooldown, error) {
+	id, err := strconv.ParseUint(rm.Id, 10, 32)
+	if err != nil {
+		return Cooldown{}, err
+	}
+	return NewCooldown(uint32(id), rm.ExpiresAt), nil
+}
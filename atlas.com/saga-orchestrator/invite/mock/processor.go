@@ -0,0 +1,36 @@
+package mock
+
+import (
+	"github.com/google/uuid"
+)
+
+// ProcessorMock is a mock implementation of the invite.Processor interface
+type ProcessorMock struct {
+	CreateFunc func(transactionId uuid.UUID, inviteType string, actorId uint32, worldId byte, referenceId uint32, targetId uint32) error
+	AcceptFunc func(transactionId uuid.UUID, inviteType string, worldId byte, referenceId uint32, targetId uint32) error
+	RejectFunc func(transactionId uuid.UUID, inviteType string, worldId byte, originatorId uint32, targetId uint32) error
+}
+
+// Create is a mock implementation of the invite.Processor.Create method
+func (m *ProcessorMock) Create(transactionId uuid.UUID, inviteType string, actorId uint32, worldId byte, referenceId uint32, targetId uint32) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(transactionId, inviteType, actorId, worldId, referenceId, targetId)
+	}
+	return nil
+}
+
+// Accept is a mock implementation of the invite.Processor.Accept method
+func (m *ProcessorMock) Accept(transactionId uuid.UUID, inviteType string, worldId byte, referenceId uint32, targetId uint32) error {
+	if m.AcceptFunc != nil {
+		return m.AcceptFunc(transactionId, inviteType, worldId, referenceId, targetId)
+	}
+	return nil
+}
+
+// Reject is a mock implementation of the invite.Processor.Reject method
+func (m *ProcessorMock) Reject(transactionId uuid.UUID, inviteType string, worldId byte, originatorId uint32, targetId uint32) error {
+	if m.RejectFunc != nil {
+		return m.RejectFunc(transactionId, inviteType, worldId, originatorId, targetId)
+	}
+	return nil
+}
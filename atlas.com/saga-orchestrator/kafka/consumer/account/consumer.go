@@ -0,0 +1,62 @@
+package account
+
+import (
+	consumer2 "atlas-saga-orchestrator/kafka/consumer"
+	"atlas-saga-orchestrator/kafka/header"
+	account2 "atlas-saga-orchestrator/kafka/message/account"
+	"atlas-saga-orchestrator/saga"
+	"context"
+	"github.com/Chronicle20/atlas-kafka/consumer"
+	"github.com/Chronicle20/atlas-kafka/handler"
+	"github.com/Chronicle20/atlas-kafka/message"
+	"github.com/Chronicle20/atlas-kafka/topic"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/segmentio/kafka-go"
+	"github.com/sirupsen/logrus"
+)
+
+func InitConsumers(l logrus.FieldLogger) func(func(config consumer.Config, decorators ...model.Decorator[consumer.Config])) func(consumerGroupId string) {
+	return func(rf func(config consumer.Config, decorators ...model.Decorator[consumer.Config])) func(consumerGroupId string) {
+		return func(consumerGroupId string) {
+			rf(consumer2.NewConfig(l)("account_status_event")(account2.EnvStatusEventTopic)(consumerGroupId), consumer.SetHeaderParsers(consumer.SpanHeaderParser, consumer.TenantHeaderParser, header.SagaHeaderParser), consumer.SetStartOffset(kafka.LastOffset))
+		}
+	}
+}
+
+func InitHandlers(l logrus.FieldLogger) func(rf func(topic string, handler handler.Handler) (string, error)) {
+	return func(rf func(topic string, handler handler.Handler) (string, error)) {
+		var t string
+		t, _ = topic.EnvProvider(l)(account2.EnvStatusEventTopic)()
+		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleCharacterSlotsChangedEvent)))
+		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handlePremiumTimeChangedEvent)))
+		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleAccountErrorEvent)))
+	}
+}
+
+func handleCharacterSlotsChangedEvent(l logrus.FieldLogger, ctx context.Context, e account2.StatusEvent[account2.StatusEventCharacterSlotsChangedBody]) {
+	if e.Type != account2.StatusEventTypeCharacterSlotsChanged {
+		return
+	}
+	_ = saga.NewProcessor(l, ctx).StepCompletedWithEvent(e.TransactionId, e)
+}
+
+func handlePremiumTimeChangedEvent(l logrus.FieldLogger, ctx context.Context, e account2.StatusEvent[account2.StatusEventPremiumTimeChangedBody]) {
+	if e.Type != account2.StatusEventTypePremiumTimeChanged {
+		return
+	}
+	_ = saga.NewProcessor(l, ctx).StepCompletedWithEvent(e.TransactionId, e)
+}
+
+func handleAccountErrorEvent(l logrus.FieldLogger, ctx context.Context, e account2.StatusEvent[account2.StatusEventErrorBody]) {
+	if e.Type != account2.StatusEventTypeError {
+		return
+	}
+
+	l.WithFields(logrus.Fields{
+		"transaction_id": e.TransactionId.String(),
+		"account_id":     e.AccountId,
+		"error_type":     e.Body.Error,
+	}).Error("Account operation failed, marking saga step as failed")
+
+	_ = saga.NewProcessor(l, ctx).StepFailedBy(saga.ServiceAccount, e.TransactionId, e.Body.Error, "")
+}
@@ -2,12 +2,11 @@ package asset
 
 import (
 	consumer2 "atlas-saga-orchestrator/kafka/consumer"
+	"atlas-saga-orchestrator/kafka/header"
 	asset2 "atlas-saga-orchestrator/kafka/message/asset"
 	"atlas-saga-orchestrator/saga"
 	"context"
 	"fmt"
-	"github.com/Chronicle20/atlas-constants/inventory"
-	"github.com/Chronicle20/atlas-constants/item"
 	"github.com/Chronicle20/atlas-kafka/consumer"
 	"github.com/Chronicle20/atlas-kafka/handler"
 	"github.com/Chronicle20/atlas-kafka/message"
@@ -21,7 +20,7 @@ import (
 func InitConsumers(l logrus.FieldLogger) func(func(config consumer.Config, decorators ...model.Decorator[consumer.Config])) func(consumerGroupId string) {
 	return func(rf func(config consumer.Config, decorators ...model.Decorator[consumer.Config])) func(consumerGroupId string) {
 		return func(consumerGroupId string) {
-			rf(consumer2.NewConfig(l)("asset_status_event")(asset2.EnvEventTopicStatus)(consumerGroupId), consumer.SetHeaderParsers(consumer.SpanHeaderParser, consumer.TenantHeaderParser), consumer.SetStartOffset(kafka.LastOffset))
+			rf(consumer2.NewConfig(l)("asset_status_event")(asset2.EnvEventTopicStatus)(consumerGroupId), consumer.SetHeaderParsers(consumer.SpanHeaderParser, consumer.TenantHeaderParser, header.SagaHeaderParser), consumer.SetStartOffset(kafka.LastOffset))
 		}
 	}
 }
@@ -50,7 +49,7 @@ func handleAssetCreatedEvent(l logrus.FieldLogger, ctx context.Context, e asset2
 			"transaction_id": e.TransactionId.String(),
 			"character_id":   e.CharacterId,
 		}).Debug("Unable to locate saga for asset created event.")
-		_ = sagaProcessor.StepCompleted(e.TransactionId, true)
+		_ = sagaProcessor.StepCompletedWithEvent(e.TransactionId, e)
 		return
 	}
 
@@ -61,13 +60,13 @@ func handleAssetCreatedEvent(l logrus.FieldLogger, ctx context.Context, e asset2
 			"transaction_id": e.TransactionId.String(),
 			"character_id":   e.CharacterId,
 		}).Debug("No current step found for asset created event.")
-		_ = sagaProcessor.StepCompleted(e.TransactionId, true)
+		_ = sagaProcessor.StepCompletedWithEvent(e.TransactionId, e)
 		return
 	}
 
 	// Check if this is a CreateAndEquipAsset step
 	if currentStep.Action == saga.CreateAndEquipAsset {
-		// Extract the payload to get the character ID and inventory type
+		// Extract the payload to get the character ID
 		createPayload, ok := currentStep.Payload.(saga.CreateAndEquipAssetPayload)
 		if !ok {
 			l.WithFields(logrus.Fields{
@@ -95,22 +94,18 @@ func handleAssetCreatedEvent(l logrus.FieldLogger, ctx context.Context, e asset2
 		// Format: auto_equip_step_<timestamp> where timestamp is Unix nanoseconds
 		autoEquipStepId := fmt.Sprintf("auto_equip_step_%d", time.Now().UnixNano())
 
-		// Create the EquipAsset step
-		// Note: Using reasonable defaults for slot information since asset event doesn't provide it
-		// The item is typically created in the first available slot (assumption: slot 5)
+		// Create the EquipAssetByTemplate step, which resolves the inventory slot holding the created asset when it runs
 		// Equipment slot -1 is typically used for equipment
-		it, _ := inventory.TypeFromItemId(item.Id(e.TemplateId))
-		equipPayload := saga.EquipAssetPayload{
-			CharacterId:   createPayload.CharacterId,
-			InventoryType: uint32(it),
-			Source:        e.Slot,
-			Destination:   -1, // Assumption: equip to slot -1
+		equipPayload := saga.EquipAssetByTemplatePayload{
+			CharacterId: createPayload.CharacterId,
+			TemplateId:  e.TemplateId,
+			Destination: -1, // Assumption: equip to slot -1
 		}
 
 		equipStep := saga.Step[any]{
 			StepId:    autoEquipStepId,
 			Status:    saga.Pending,
-			Action:    saga.EquipAsset,
+			Action:    saga.EquipAssetByTemplate,
 			Payload:   equipPayload,
 			CreatedAt: time.Now(),
 			UpdatedAt: time.Now(),
@@ -134,27 +129,26 @@ func handleAssetCreatedEvent(l logrus.FieldLogger, ctx context.Context, e asset2
 			"transaction_id":     e.TransactionId.String(),
 			"character_id":       e.CharacterId,
 			"auto_equip_step_id": autoEquipStepId,
-			"inventory_type":     equipPayload.InventoryType,
-			"source_slot":        equipPayload.Source,
+			"template_id":        equipPayload.TemplateId,
 			"destination_slot":   equipPayload.Destination,
 			"original_step_id":   currentStep.StepId,
 		}).Info("Successfully added auto-equip step for CreateAndEquipAsset action to be executed next.")
 	}
 
 	// Complete the current step (either regular creation or CreateAndEquipAsset)
-	_ = sagaProcessor.StepCompleted(e.TransactionId, true)
+	_ = sagaProcessor.StepCompletedWithEvent(e.TransactionId, e)
 }
 
 func handleAssetQuantityUpdatedEvent(l logrus.FieldLogger, ctx context.Context, e asset2.StatusEvent[asset2.QuantityChangedEventBody]) {
 	if e.Type != asset2.StatusEventTypeQuantityChanged {
 		return
 	}
-	_ = saga.NewProcessor(l, ctx).StepCompleted(e.TransactionId, true)
+	_ = saga.NewProcessor(l, ctx).StepCompletedWithEvent(e.TransactionId, e)
 }
 
 func handleAssetMovedEvent(l logrus.FieldLogger, ctx context.Context, e asset2.StatusEvent[asset2.MovedStatusEventBody]) {
 	if e.Type != asset2.StatusEventTypeMoved {
 		return
 	}
-	_ = saga.NewProcessor(l, ctx).StepCompleted(e.TransactionId, true)
+	_ = saga.NewProcessor(l, ctx).StepCompletedWithEvent(e.TransactionId, e)
 }
@@ -0,0 +1,39 @@
+package buff
+
+import (
+	consumer2 "atlas-saga-orchestrator/kafka/consumer"
+	"atlas-saga-orchestrator/kafka/header"
+	buff2 "atlas-saga-orchestrator/kafka/message/buff"
+	"atlas-saga-orchestrator/saga"
+	"context"
+	"github.com/Chronicle20/atlas-kafka/consumer"
+	"github.com/Chronicle20/atlas-kafka/handler"
+	"github.com/Chronicle20/atlas-kafka/message"
+	"github.com/Chronicle20/atlas-kafka/topic"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/segmentio/kafka-go"
+	"github.com/sirupsen/logrus"
+)
+
+func InitConsumers(l logrus.FieldLogger) func(func(config consumer.Config, decorators ...model.Decorator[consumer.Config])) func(consumerGroupId string) {
+	return func(rf func(config consumer.Config, decorators ...model.Decorator[consumer.Config])) func(consumerGroupId string) {
+		return func(consumerGroupId string) {
+			rf(consumer2.NewConfig(l)("buff_status_event")(buff2.EnvStatusEventTopic)(consumerGroupId), consumer.SetHeaderParsers(consumer.SpanHeaderParser, consumer.TenantHeaderParser, header.SagaHeaderParser), consumer.SetStartOffset(kafka.LastOffset))
+		}
+	}
+}
+
+func InitHandlers(l logrus.FieldLogger) func(rf func(topic string, handler handler.Handler) (string, error)) {
+	return func(rf func(topic string, handler handler.Handler) (string, error)) {
+		var t string
+		t, _ = topic.EnvProvider(l)(buff2.EnvStatusEventTopic)()
+		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleBuffCancelledAllEvent)))
+	}
+}
+
+func handleBuffCancelledAllEvent(l logrus.FieldLogger, ctx context.Context, e buff2.StatusEvent[buff2.CancelledAllStatusEventBody]) {
+	if e.Type != buff2.StatusEventTypeCancelledAll {
+		return
+	}
+	_ = saga.NewProcessor(l, ctx).StepCompletedWithEvent(e.TransactionId, e)
+}
@@ -2,6 +2,7 @@ package character
 
 import (
 	consumer2 "atlas-saga-orchestrator/kafka/consumer"
+	"atlas-saga-orchestrator/kafka/header"
 	character2 "atlas-saga-orchestrator/kafka/message/character"
 	"atlas-saga-orchestrator/saga"
 	"context"
@@ -17,7 +18,7 @@ import (
 func InitConsumers(l logrus.FieldLogger) func(func(config consumer.Config, decorators ...model.Decorator[consumer.Config])) func(consumerGroupId string) {
 	return func(rf func(config consumer.Config, decorators ...model.Decorator[consumer.Config])) func(consumerGroupId string) {
 		return func(consumerGroupId string) {
-			rf(consumer2.NewConfig(l)("character_status_event")(character2.EnvEventTopicCharacterStatus)(consumerGroupId), consumer.SetHeaderParsers(consumer.SpanHeaderParser, consumer.TenantHeaderParser), consumer.SetStartOffset(kafka.LastOffset))
+			rf(consumer2.NewConfig(l)("character_status_event")(character2.EnvEventTopicCharacterStatus)(consumerGroupId), consumer.SetHeaderParsers(consumer.SpanHeaderParser, consumer.TenantHeaderParser, header.SagaHeaderParser), consumer.SetStartOffset(kafka.LastOffset))
 		}
 	}
 }
@@ -28,12 +29,21 @@ func InitHandlers(l logrus.FieldLogger) func(rf func(topic string, handler handl
 		t, _ = topic.EnvProvider(l)(character2.EnvEventTopicCharacterStatus)()
 		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleCharacterMapChangedEvent)))
 		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleCharacterExperienceChangedEvent)))
+		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleCharacterExperienceDeductedEvent)))
 		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleCharacterLevelChangedEvent)))
 		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleCharacterMesoChangedEvent)))
+		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleCharacterFameChangedEvent)))
 		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleCharacterJobChangedEvent)))
+		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleCharacterResourceChangedEvent)))
+		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleCharacterExperienceLockedEvent)))
+		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleCharacterExperienceUnlockedEvent)))
+		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleCharacterStatsRestoredEvent)))
+		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleCharacterAccountChangedEvent)))
+		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleCharacterRolledBackEvent)))
 		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleCharacterCreatedEvent)))
 		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleCharacterCreationFailedEvent)))
 		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleCharacterErrorEvent)))
+		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleCharacterLoginEvent)))
 	}
 }
 
@@ -41,35 +51,99 @@ func handleCharacterMapChangedEvent(l logrus.FieldLogger, ctx context.Context, e
 	if e.Type != character2.StatusEventTypeMapChanged {
 		return
 	}
-	_ = saga.NewProcessor(l, ctx).StepCompleted(e.TransactionId, true)
+	_ = saga.NewProcessor(l, ctx).StepCompletedWithEvent(e.TransactionId, e)
+	_ = saga.NewProcessor(l, ctx).MapEntered(e.CharacterId, e.Body.TargetMapId)
 }
 
 func handleCharacterExperienceChangedEvent(l logrus.FieldLogger, ctx context.Context, e character2.StatusEvent[character2.ExperienceChangedStatusEventBody]) {
 	if e.Type != character2.StatusEventTypeExperienceChanged {
 		return
 	}
-	_ = saga.NewProcessor(l, ctx).StepCompleted(e.TransactionId, true)
+	_ = saga.NewProcessor(l, ctx).StepCompletedWithEvent(e.TransactionId, e)
+}
+
+func handleCharacterExperienceDeductedEvent(l logrus.FieldLogger, ctx context.Context, e character2.StatusEvent[character2.ExperienceDeductedStatusEventBody]) {
+	if e.Type != character2.StatusEventTypeExperienceDeducted {
+		return
+	}
+	_ = saga.NewProcessor(l, ctx).StepCompletedWithEvent(e.TransactionId, e)
 }
 
 func handleCharacterLevelChangedEvent(l logrus.FieldLogger, ctx context.Context, e character2.StatusEvent[character2.LevelChangedStatusEventBody]) {
 	if e.Type != character2.StatusEventTypeLevelChanged {
 		return
 	}
-	_ = saga.NewProcessor(l, ctx).StepCompleted(e.TransactionId, true)
+	_ = saga.NewProcessor(l, ctx).StepCompletedWithEvent(e.TransactionId, e)
 }
 
 func handleCharacterMesoChangedEvent(l logrus.FieldLogger, ctx context.Context, e character2.StatusEvent[character2.MesoChangedStatusEventBody]) {
 	if e.Type != character2.StatusEventTypeMesoChanged {
 		return
 	}
-	_ = saga.NewProcessor(l, ctx).StepCompleted(e.TransactionId, true)
+	_ = saga.NewProcessor(l, ctx).StepCompletedWithEvent(e.TransactionId, e)
+}
+
+func handleCharacterFameChangedEvent(l logrus.FieldLogger, ctx context.Context, e character2.StatusEvent[character2.FameChangedStatusEventBody]) {
+	if e.Type != character2.StatusEventTypeFameChanged {
+		return
+	}
+	_ = saga.NewProcessor(l, ctx).StepCompletedWithEvent(e.TransactionId, e)
+}
+
+func handleCharacterAccountChangedEvent(l logrus.FieldLogger, ctx context.Context, e character2.StatusEvent[character2.AccountChangedStatusEventBody]) {
+	if e.Type != character2.StatusEventTypeAccountChanged {
+		return
+	}
+	_ = saga.NewProcessor(l, ctx).StepCompletedWithEvent(e.TransactionId, e)
+}
+
+func handleCharacterRolledBackEvent(l logrus.FieldLogger, ctx context.Context, e character2.StatusEvent[character2.RolledBackStatusEventBody]) {
+	if e.Type != character2.StatusEventTypeRolledBack {
+		return
+	}
+	_ = saga.NewProcessor(l, ctx).StepCompletedWithEvent(e.TransactionId, e)
 }
 
 func handleCharacterJobChangedEvent(l logrus.FieldLogger, ctx context.Context, e character2.StatusEvent[character2.JobChangedStatusEventBody]) {
 	if e.Type != character2.StatusEventTypeJobChanged {
 		return
 	}
-	_ = saga.NewProcessor(l, ctx).StepCompleted(e.TransactionId, true)
+	_ = saga.NewProcessor(l, ctx).StepCompletedWithEvent(e.TransactionId, e)
+}
+
+func handleCharacterResourceChangedEvent(l logrus.FieldLogger, ctx context.Context, e character2.StatusEvent[character2.ResourceChangedStatusEventBody]) {
+	if e.Type != character2.StatusEventTypeResourceChanged {
+		return
+	}
+	_ = saga.NewProcessor(l, ctx).StepCompletedWithEvent(e.TransactionId, e)
+}
+
+func handleCharacterExperienceLockedEvent(l logrus.FieldLogger, ctx context.Context, e character2.StatusEvent[character2.ExperienceLockedStatusEventBody]) {
+	if e.Type != character2.StatusEventTypeExperienceLocked {
+		return
+	}
+	_ = saga.NewProcessor(l, ctx).StepCompletedWithEvent(e.TransactionId, e)
+}
+
+func handleCharacterExperienceUnlockedEvent(l logrus.FieldLogger, ctx context.Context, e character2.StatusEvent[character2.ExperienceUnlockedStatusEventBody]) {
+	if e.Type != character2.StatusEventTypeExperienceUnlocked {
+		return
+	}
+	_ = saga.NewProcessor(l, ctx).StepCompletedWithEvent(e.TransactionId, e)
+}
+
+func handleCharacterStatsRestoredEvent(l logrus.FieldLogger, ctx context.Context, e character2.StatusEvent[character2.StatsRestoredStatusEventBody]) {
+	if e.Type != character2.StatusEventTypeStatsRestored {
+		return
+	}
+	_ = saga.NewProcessor(l, ctx).StepCompletedWithEvent(e.TransactionId, e)
+}
+
+func handleCharacterLoginEvent(l logrus.FieldLogger, ctx context.Context, e character2.StatusEvent[character2.StatusEventLoginBody]) {
+	if e.Type != character2.StatusEventTypeLogin {
+		return
+	}
+	_ = saga.NewProcessor(l, ctx).CharacterLoggedIn(e.CharacterId)
 }
 
 func handleCharacterCreatedEvent(l logrus.FieldLogger, ctx context.Context, e character2.StatusEvent[character2.StatusEventCreatedBody]) {
@@ -84,7 +158,7 @@ func handleCharacterCreatedEvent(l logrus.FieldLogger, ctx context.Context, e ch
 		"world_id":       e.WorldId,
 	}).Debug("Character created successfully, marking saga step as completed")
 	
-	_ = saga.NewProcessor(l, ctx).StepCompleted(e.TransactionId, true)
+	_ = saga.NewProcessor(l, ctx).StepCompletedWithEvent(e.TransactionId, e)
 }
 
 func handleCharacterCreationFailedEvent(l logrus.FieldLogger, ctx context.Context, e character2.StatusEvent[character2.StatusEventCreationFailedBody]) {
@@ -99,7 +173,7 @@ func handleCharacterCreationFailedEvent(l logrus.FieldLogger, ctx context.Contex
 		"world_id":       e.WorldId,
 	}).Error("Character creation failed, marking saga step as failed")
 	
-	_ = saga.NewProcessor(l, ctx).StepCompleted(e.TransactionId, false)
+	_ = saga.NewProcessor(l, ctx).StepFailedBy(saga.ServiceCharacter, e.TransactionId, character2.StatusEventTypeCreationFailed, e.Body.Message)
 }
 
 func handleCharacterErrorEvent(l logrus.FieldLogger, ctx context.Context, e character2.StatusEvent[character2.StatusEventErrorBody[interface{}]]) {
@@ -114,5 +188,5 @@ func handleCharacterErrorEvent(l logrus.FieldLogger, ctx context.Context, e char
 		"world_id":       e.WorldId,
 	}).Error("Character operation error occurred, marking saga step as failed")
 	
-	_ = saga.NewProcessor(l, ctx).StepCompleted(e.TransactionId, false)
+	_ = saga.NewProcessor(l, ctx).StepFailedBy(saga.ServiceCharacter, e.TransactionId, e.Body.Error, "")
 }
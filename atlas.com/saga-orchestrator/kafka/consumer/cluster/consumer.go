@@ -0,0 +1,50 @@
+package cluster
+
+import (
+	cluster2 "atlas-saga-orchestrator/cluster"
+	consumer2 "atlas-saga-orchestrator/kafka/consumer"
+	"atlas-saga-orchestrator/kafka/message/cluster"
+	"context"
+	"time"
+
+	"github.com/Chronicle20/atlas-kafka/consumer"
+	"github.com/Chronicle20/atlas-kafka/handler"
+	"github.com/Chronicle20/atlas-kafka/message"
+	"github.com/Chronicle20/atlas-kafka/topic"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/segmentio/kafka-go"
+	"github.com/sirupsen/logrus"
+)
+
+// InitConsumers registers the membership consumer. Every replica observes every announcement, so each consumes in its
+// own group, from the latest announcement.
+func InitConsumers(l logrus.FieldLogger) func(func(config consumer.Config, decorators ...model.Decorator[consumer.Config])) func(consumerGroupId string) {
+	return func(rf func(config consumer.Config, decorators ...model.Decorator[consumer.Config])) func(consumerGroupId string) {
+		return func(consumerGroupId string) {
+			rf(consumer2.NewConfig(l)("saga_orchestrator_membership_event")(cluster.EnvEventTopicMembership)(consumerGroupId), consumer.SetHeaderParsers(consumer.SpanHeaderParser), consumer.SetStartOffset(kafka.LastOffset))
+		}
+	}
+}
+
+// InitHandlers registers the membership handler. Membership spans tenants, so announcements carry no tenant.
+func InitHandlers(l logrus.FieldLogger) func(rf func(topic string, handler handler.Handler) (string, error)) {
+	return func(rf func(topic string, handler handler.Handler) (string, error)) {
+		var t string
+		t, _ = topic.EnvProvider(l)(cluster.EnvEventTopicMembership)()
+		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleMembershipEvent)))
+	}
+}
+
+func handleMembershipEvent(l logrus.FieldLogger, _ context.Context, e cluster.MembershipEvent) {
+	m := cluster2.GetMembership()
+	if e.ReplicaId == m.Self() {
+		return
+	}
+	switch e.Type {
+	case cluster.MembershipEventHeartbeat:
+		m.Observe(e.ReplicaId, time.Now())
+	case cluster.MembershipEventLeft:
+		l.Infof("Replica [%s] left.", e.ReplicaId)
+		m.Leave(e.ReplicaId)
+	}
+}
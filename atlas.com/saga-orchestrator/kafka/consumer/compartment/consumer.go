@@ -2,6 +2,7 @@ package compartment
 
 import (
 	consumer2 "atlas-saga-orchestrator/kafka/consumer"
+	"atlas-saga-orchestrator/kafka/header"
 	"atlas-saga-orchestrator/kafka/message/compartment"
 	"atlas-saga-orchestrator/saga"
 	"context"
@@ -17,7 +18,7 @@ import (
 func InitConsumers(l logrus.FieldLogger) func(func(config consumer.Config, decorators ...model.Decorator[consumer.Config])) func(consumerGroupId string) {
 	return func(rf func(config consumer.Config, decorators ...model.Decorator[consumer.Config])) func(consumerGroupId string) {
 		return func(consumerGroupId string) {
-			rf(consumer2.NewConfig(l)("compartment_status_event")(compartment.EnvEventTopicStatus)(consumerGroupId), consumer.SetHeaderParsers(consumer.SpanHeaderParser, consumer.TenantHeaderParser), consumer.SetStartOffset(kafka.LastOffset))
+			rf(consumer2.NewConfig(l)("compartment_status_event")(compartment.EnvEventTopicStatus)(consumerGroupId), consumer.SetHeaderParsers(consumer.SpanHeaderParser, consumer.TenantHeaderParser, header.SagaHeaderParser), consumer.SetStartOffset(kafka.LastOffset))
 		}
 	}
 }
@@ -29,6 +30,12 @@ func InitHandlers(l logrus.FieldLogger) func(rf func(topic string, handler handl
 		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleCompartmentCreatedEvent)))
 		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleCompartmentCreationFailedEvent)))
 		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleCompartmentDeletedEvent)))
+		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleCompartmentSnapshotRestoredEvent)))
+		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleCompartmentDurabilityChangedEvent)))
+		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleCompartmentExpirationChangedEvent)))
+		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleCompartmentUpgradeSlotsChangedEvent)))
+		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleCompartmentReservedEvent)))
+		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleCompartmentReservationCancelledEvent)))
 		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleCompartmentErrorEvent)))
 	}
 }
@@ -39,7 +46,7 @@ func handleCompartmentCreatedEvent(l logrus.FieldLogger, ctx context.Context, e
 	}
 
 	// Complete the current step for regular compartment creation
-	_ = saga.NewProcessor(l, ctx).StepCompleted(e.TransactionId, true)
+	_ = saga.NewProcessor(l, ctx).StepCompletedWithEvent(e.TransactionId, e)
 }
 
 func handleCompartmentCreationFailedEvent(l logrus.FieldLogger, ctx context.Context, e compartment.StatusEvent[compartment.CreationFailedStatusEventBody]) {
@@ -54,16 +61,58 @@ func handleCompartmentCreationFailedEvent(l logrus.FieldLogger, ctx context.Cont
 		"error_message":  e.Body.Message,
 	}).Error("Asset creation failed, marking saga step as failed")
 
-	// Mark the saga step as failed
+	// Mark the saga step as failed, retaining the reported error with the step's attempt history
 	sagaProcessor := saga.NewProcessor(l, ctx)
-	_ = sagaProcessor.StepCompleted(e.TransactionId, false)
+	_ = sagaProcessor.StepFailedBy(saga.ServiceCompartment, e.TransactionId, e.Body.ErrorCode, e.Body.Message)
 }
 
 func handleCompartmentDeletedEvent(l logrus.FieldLogger, ctx context.Context, e compartment.StatusEvent[compartment.DeletedStatusEventBody]) {
 	if e.Type != compartment.StatusEventTypeDeleted {
 		return
 	}
-	_ = saga.NewProcessor(l, ctx).StepCompleted(e.TransactionId, true)
+	_ = saga.NewProcessor(l, ctx).StepCompletedWithEvent(e.TransactionId, e)
+}
+
+func handleCompartmentSnapshotRestoredEvent(l logrus.FieldLogger, ctx context.Context, e compartment.StatusEvent[compartment.SnapshotRestoredEventBody]) {
+	if e.Type != compartment.StatusEventTypeSnapshotRestored {
+		return
+	}
+	_ = saga.NewProcessor(l, ctx).StepCompletedWithEvent(e.TransactionId, e)
+}
+
+func handleCompartmentDurabilityChangedEvent(l logrus.FieldLogger, ctx context.Context, e compartment.StatusEvent[compartment.DurabilityChangedEventBody]) {
+	if e.Type != compartment.StatusEventTypeDurabilityChanged {
+		return
+	}
+	_ = saga.NewProcessor(l, ctx).StepCompletedWithEvent(e.TransactionId, e)
+}
+
+func handleCompartmentExpirationChangedEvent(l logrus.FieldLogger, ctx context.Context, e compartment.StatusEvent[compartment.ExpirationChangedEventBody]) {
+	if e.Type != compartment.StatusEventTypeExpirationChanged {
+		return
+	}
+	_ = saga.NewProcessor(l, ctx).StepCompletedWithEvent(e.TransactionId, e)
+}
+
+func handleCompartmentUpgradeSlotsChangedEvent(l logrus.FieldLogger, ctx context.Context, e compartment.StatusEvent[compartment.UpgradeSlotsChangedEventBody]) {
+	if e.Type != compartment.StatusEventTypeUpgradeSlotsChanged {
+		return
+	}
+	_ = saga.NewProcessor(l, ctx).StepCompletedWithEvent(e.TransactionId, e)
+}
+
+func handleCompartmentReservedEvent(l logrus.FieldLogger, ctx context.Context, e compartment.StatusEvent[compartment.ReservedEventBody]) {
+	if e.Type != compartment.StatusEventTypeReserved {
+		return
+	}
+	_ = saga.NewProcessor(l, ctx).StepCompletedWithEvent(e.TransactionId, e)
+}
+
+func handleCompartmentReservationCancelledEvent(l logrus.FieldLogger, ctx context.Context, e compartment.StatusEvent[compartment.ReservationCancelledEventBody]) {
+	if e.Type != compartment.StatusEventTypeReservationCancelled {
+		return
+	}
+	_ = saga.NewProcessor(l, ctx).StepCompletedWithEvent(e.TransactionId, e)
 }
 
 func handleCompartmentErrorEvent(l logrus.FieldLogger, ctx context.Context, e compartment.StatusEvent[compartment.ErrorEventBody]) {
@@ -77,5 +126,5 @@ func handleCompartmentErrorEvent(l logrus.FieldLogger, ctx context.Context, e co
 		"character_id":   e.CharacterId,
 	}).Error("Compartment operation failed")
 
-	_ = saga.NewProcessor(l, ctx).StepCompleted(e.TransactionId, false)
+	_ = saga.NewProcessor(l, ctx).StepFailedBy(saga.ServiceCompartment, e.TransactionId, e.Body.ErrorCode, "")
 }
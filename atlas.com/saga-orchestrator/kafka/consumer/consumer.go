@@ -2,13 +2,18 @@ package consumer
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/Chronicle20/atlas-kafka/consumer"
 	"github.com/Chronicle20/atlas-kafka/handler"
 	"github.com/Chronicle20/atlas-kafka/topic"
 	"github.com/Chronicle20/atlas-model/model"
+	"github.com/Chronicle20/atlas-tenant"
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
 	"github.com/sirupsen/logrus"
 	"os"
+	"runtime/debug"
 	"sync"
 )
 
@@ -23,6 +28,76 @@ func NewConfig(l logrus.FieldLogger) func(name string) func(token string) func(g
 	}
 }
 
+// TenantRequired decorates handler registration, so messages lacking tenant headers are dropped rather than processed,
+// as every saga belongs to a tenant
+func TenantRequired(l logrus.FieldLogger) func(rf func(topic string, handler handler.Handler) (string, error)) func(topic string, handler handler.Handler) (string, error) {
+	return func(rf func(topic string, handler handler.Handler) (string, error)) func(topic string, handler handler.Handler) (string, error) {
+		return func(topic string, h handler.Handler) (string, error) {
+			return rf(topic, func(l2 logrus.FieldLogger, ctx context.Context, msg kafka.Message) (bool, error) {
+				if _, err := tenant.FromContext(ctx)(); err != nil {
+					l.WithError(err).WithField("topic", topic).Warn("Dropping message without tenant headers.")
+					return true, nil
+				}
+				return h(l2, ctx, msg)
+			})
+		}
+	}
+}
+
+// Quarantiner quarantines the saga of a message whose handler panicked
+type Quarantiner func(l logrus.FieldLogger, ctx context.Context, transactionId uuid.UUID, recovered any, stack []byte)
+
+// PanicIsolated decorates handler registration, so a handler panicking is recovered rather than stalling the consumer
+// for every tenant. The saga of the message, identified by its transactionId, is quarantined, and the message is not
+// redelivered, as it would panic again.
+func PanicIsolated(l logrus.FieldLogger, quarantine Quarantiner) func(rf func(topic string, handler handler.Handler) (string, error)) func(topic string, handler handler.Handler) (string, error) {
+	return func(rf func(topic string, handler handler.Handler) (string, error)) func(topic string, handler handler.Handler) (string, error) {
+		return func(topic string, h handler.Handler) (string, error) {
+			return rf(topic, func(l2 logrus.FieldLogger, ctx context.Context, msg kafka.Message) (ok bool, err error) {
+				defer func() {
+					r := recover()
+					if r == nil {
+						return
+					}
+					ok, err = true, nil
+					stack := debug.Stack()
+					var m struct {
+						TransactionId uuid.UUID `json:"transactionId"`
+					}
+					if json.Unmarshal(msg.Value, &m) != nil || m.TransactionId == uuid.Nil || quarantine == nil {
+						l.WithField("topic", topic).Errorf("Recovered from panic handling message: %v\n%s", r, stack)
+						return
+					}
+					quarantine(l2, ctx, m.TransactionId, r, stack)
+				}()
+				return h(l2, ctx, msg)
+			})
+		}
+	}
+}
+
+// ShadowObserver observes the processing of a message of the saga identified by its transactionId, returning a function
+// called once it is processed
+type ShadowObserver func(l logrus.FieldLogger, ctx context.Context, transactionId uuid.UUID) func()
+
+// Shadowed decorates handler registration, so the processing of each message by a replica running in shadow mode is
+// observed. Messages identifying no saga are observed with a nil transactionId.
+func Shadowed(l logrus.FieldLogger, observe ShadowObserver) func(rf func(topic string, handler handler.Handler) (string, error)) func(topic string, handler handler.Handler) (string, error) {
+	return func(rf func(topic string, handler handler.Handler) (string, error)) func(topic string, handler handler.Handler) (string, error) {
+		return func(topic string, h handler.Handler) (string, error) {
+			return rf(topic, func(l2 logrus.FieldLogger, ctx context.Context, msg kafka.Message) (bool, error) {
+				var m struct {
+					TransactionId uuid.UUID `json:"transactionId"`
+				}
+				_ = json.Unmarshal(msg.Value, &m)
+				done := observe(l2, ctx, m.TransactionId)
+				defer done()
+				return h(l2, ctx, msg)
+			})
+		}
+	}
+}
+
 func LookupBrokers() []string {
 	return []string{os.Getenv("BOOTSTRAP_SERVERS")}
 }
@@ -0,0 +1,55 @@
+package coupon
+
+import (
+	consumer2 "atlas-saga-orchestrator/kafka/consumer"
+	"atlas-saga-orchestrator/kafka/header"
+	coupon2 "atlas-saga-orchestrator/kafka/message/coupon"
+	"atlas-saga-orchestrator/saga"
+	"context"
+	"github.com/Chronicle20/atlas-kafka/consumer"
+	"github.com/Chronicle20/atlas-kafka/handler"
+	"github.com/Chronicle20/atlas-kafka/message"
+	"github.com/Chronicle20/atlas-kafka/topic"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/segmentio/kafka-go"
+	"github.com/sirupsen/logrus"
+)
+
+func InitConsumers(l logrus.FieldLogger) func(func(config consumer.Config, decorators ...model.Decorator[consumer.Config])) func(consumerGroupId string) {
+	return func(rf func(config consumer.Config, decorators ...model.Decorator[consumer.Config])) func(consumerGroupId string) {
+		return func(consumerGroupId string) {
+			rf(consumer2.NewConfig(l)("coupon_status_event")(coupon2.EnvStatusEventTopic)(consumerGroupId), consumer.SetHeaderParsers(consumer.SpanHeaderParser, consumer.TenantHeaderParser, header.SagaHeaderParser), consumer.SetStartOffset(kafka.LastOffset))
+		}
+	}
+}
+
+func InitHandlers(l logrus.FieldLogger) func(rf func(topic string, handler handler.Handler) (string, error)) {
+	return func(rf func(topic string, handler handler.Handler) (string, error)) {
+		var t string
+		t, _ = topic.EnvProvider(l)(coupon2.EnvStatusEventTopic)()
+		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleCouponConsumedEvent)))
+		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleCouponErrorEvent)))
+	}
+}
+
+func handleCouponConsumedEvent(l logrus.FieldLogger, ctx context.Context, e coupon2.StatusEvent[coupon2.StatusEventConsumedBody]) {
+	if e.Type != coupon2.StatusEventTypeConsumed {
+		return
+	}
+	_ = saga.NewProcessor(l, ctx).StepCompletedWithEvent(e.TransactionId, e)
+}
+
+func handleCouponErrorEvent(l logrus.FieldLogger, ctx context.Context, e coupon2.StatusEvent[coupon2.StatusEventErrorBody]) {
+	if e.Type != coupon2.StatusEventTypeError {
+		return
+	}
+
+	l.WithFields(logrus.Fields{
+		"transaction_id": e.TransactionId.String(),
+		"account_id":     e.AccountId,
+		"character_id":   e.CharacterId,
+		"error_type":     e.Body.Error,
+	}).Error("Coupon operation failed, marking saga step as failed")
+
+	_ = saga.NewProcessor(l, ctx).StepFailedBy(saga.ServiceCoupon, e.TransactionId, e.Body.Error, "")
+}
@@ -0,0 +1,222 @@
+package consumer
+
+import (
+	"atlas-saga-orchestrator/kafka/header"
+	"atlas-saga-orchestrator/metrics"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/bits"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Chronicle20/atlas-kafka/handler"
+	"github.com/Chronicle20/atlas-kafka/topic"
+	"github.com/segmentio/kafka-go"
+	"github.com/sirupsen/logrus"
+)
+
+// maxDedupHandlers is the most handlers of a topic whose duplicates are suppressed. Handlers registered beyond it
+// process every message.
+const maxDedupHandlers = 64
+
+// DedupConfig configures the windows within which a message already processed is suppressed as a duplicate when it is
+// delivered again, such as by Kafka after a rebalance
+type DedupConfig struct {
+	Default time.Duration            // Default window of topics without their own. When 0, their duplicates are processed.
+	Windows map[string]time.Duration // Windows of topics, by topic name
+}
+
+// DedupConfigFromEnv loads the deduplication configuration from the environment. Windows are configured by the token of
+// their topic (e.g. EVENT_TOPIC_CHARACTER_STATUS=30s), which is resolved to the topic's name.
+func DedupConfigFromEnv(l logrus.FieldLogger) (DedupConfig, error) {
+	c := DedupConfig{Windows: make(map[string]time.Duration)}
+	if v, ok := os.LookupEnv("SAGA_DEDUP_WINDOW"); ok && v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return DedupConfig{}, fmt.Errorf("invalid SAGA_DEDUP_WINDOW '%s'", v)
+		}
+		c.Default = d
+	}
+	v, ok := os.LookupEnv("SAGA_DEDUP_WINDOWS")
+	if !ok || v == "" {
+		return c, nil
+	}
+	for _, pair := range strings.Split(v, ",") {
+		token, window, found := strings.Cut(strings.TrimSpace(pair), "=")
+		if !found || token == "" {
+			return DedupConfig{}, fmt.Errorf("invalid SAGA_DEDUP_WINDOWS entry '%s'", pair)
+		}
+		d, err := time.ParseDuration(window)
+		if err != nil || d < 0 {
+			return DedupConfig{}, fmt.Errorf("invalid SAGA_DEDUP_WINDOWS window '%s' of '%s'", window, token)
+		}
+		name, _ := topic.EnvProvider(l)(token)()
+		if name == "" {
+			return DedupConfig{}, fmt.Errorf("SAGA_DEDUP_WINDOWS topic '%s' is not configured", token)
+		}
+		c.Windows[name] = d
+	}
+	return c, nil
+}
+
+// Window returns the window within which duplicates of the topic's messages are suppressed
+func (c DedupConfig) Window(topic string) time.Duration {
+	if w, ok := c.Windows[topic]; ok {
+		return w
+	}
+	return c.Default
+}
+
+var duplicates *metrics.Counter
+var duplicatesOnce sync.Once
+
+// getDuplicates returns the counter of messages suppressed as duplicates, by topic and event type
+func getDuplicates() *metrics.Counter {
+	duplicatesOnce.Do(func() {
+		duplicates = metrics.GetRegistry().RegisterCounter("saga_duplicate_events_suppressed_total", "Number of messages suppressed as duplicates of a message processed within the topic's deduplication window, by topic and event type.")
+	})
+	return duplicates
+}
+
+// dedupEntry records which of a topic's handlers processed a message, and until when duplicates of it are suppressed
+type dedupEntry struct {
+	key       string
+	expiresAt time.Time
+	processed uint64
+}
+
+// dedupWindow holds the messages of a topic processed within its window. Every message of a topic is delivered to each
+// of its handlers, so a message is a duplicate only to the handlers which have processed it.
+type dedupWindow struct {
+	mutex    sync.Mutex
+	window   time.Duration
+	handlers int
+	entries  map[string]*dedupEntry
+	order    []*dedupEntry
+}
+
+func newDedupWindow(window time.Duration) *dedupWindow {
+	return &dedupWindow{window: window, entries: make(map[string]*dedupEntry)}
+}
+
+// register assigns the next handler of the topic its index
+func (w *dedupWindow) register() int {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	i := w.handlers
+	w.handlers++
+	return i
+}
+
+// expire removes the entries whose window has passed. Every entry of a topic shares its window, so entries expire in
+// the order they were recorded.
+func (w *dedupWindow) expire(now time.Time) {
+	n := 0
+	for n < len(w.order) && !now.Before(w.order[n].expiresAt) {
+		delete(w.entries, w.order[n].key)
+		n++
+	}
+	w.order = w.order[n:]
+}
+
+// duplicate returns whether the handler has processed the message within the window, and whether it is the first of
+// the handlers having done so, which counts the duplicate
+func (w *dedupWindow) duplicate(key string, handler int, now time.Time) (bool, bool) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.expire(now)
+	e, ok := w.entries[key]
+	if !ok || e.processed&(1<<handler) == 0 {
+		return false, false
+	}
+	return true, bits.TrailingZeros64(e.processed) == handler
+}
+
+// record records the handler as having processed the message
+func (w *dedupWindow) record(key string, handler int, now time.Time) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	e, ok := w.entries[key]
+	if !ok {
+		e = &dedupEntry{key: key, expiresAt: now.Add(w.window)}
+		w.entries[key] = e
+		w.order = append(w.order, e)
+	}
+	e.processed |= 1 << handler
+}
+
+// dedupKey identifies a message of a saga by its transaction ID, event type, the saga step it was produced for and a hash
+// of its content, so an event produced again, such as by a downstream service retrying, is identified alike wherever it
+// is read from, while identical events of different steps, such as those of two identical awards, are not. Messages
+// identifying no saga are identified by their partition and offset, so only a redelivery of the message is identified
+// alike. It also returns the message's event type.
+func dedupKey(msg kafka.Message) (string, string) {
+	var m struct {
+		TransactionId string `json:"transactionId"`
+		Type          string `json:"type"`
+	}
+	_ = json.Unmarshal(msg.Value, &m)
+	if m.TransactionId == "" {
+		return fmt.Sprintf("%d:%d", msg.Partition, msg.Offset), m.Type
+	}
+	stepId := ""
+	for _, h := range msg.Headers {
+		if h.Key == header.StepId {
+			stepId = string(h.Value)
+		}
+	}
+	sum := sha256.Sum256(msg.Value)
+	return fmt.Sprintf("%s:%s:%s:%s", m.TransactionId, m.Type, stepId, hex.EncodeToString(sum[:])), m.Type
+}
+
+// Deduplicated decorates handler registration, so a message redelivered after a handler processed it within its
+// topic's window is suppressed rather than processed again, such as a status event which would otherwise complete a
+// step twice. Messages whose processing failed are processed again. Topics without a window process every message.
+func Deduplicated(l logrus.FieldLogger, c DedupConfig) func(rf func(topic string, handler handler.Handler) (string, error)) func(topic string, handler handler.Handler) (string, error) {
+	windows := make(map[string]*dedupWindow)
+	var mutex sync.Mutex
+	// Initialize the counter, so it is reported before any duplicate is suppressed
+	getDuplicates()
+
+	return func(rf func(topic string, handler handler.Handler) (string, error)) func(topic string, handler handler.Handler) (string, error) {
+		return func(topic string, h handler.Handler) (string, error) {
+			window := c.Window(topic)
+			if window <= 0 {
+				return rf(topic, h)
+			}
+			mutex.Lock()
+			w, ok := windows[topic]
+			if !ok {
+				w = newDedupWindow(window)
+				windows[topic] = w
+			}
+			mutex.Unlock()
+			i := w.register()
+			if i >= maxDedupHandlers {
+				l.WithField("topic", topic).Warnf("Duplicates of messages are not suppressed for handlers beyond [%d].", maxDedupHandlers)
+				return rf(topic, h)
+			}
+
+			return rf(topic, func(l2 logrus.FieldLogger, ctx context.Context, msg kafka.Message) (bool, error) {
+				key, eventType := dedupKey(msg)
+				if dup, first := w.duplicate(key, i, time.Now()); dup {
+					if first {
+						getDuplicates().Inc(map[string]string{"topic": topic, "type": eventType})
+						l2.WithField("topic", topic).Debugf("Suppressed duplicate [%s] message.", eventType)
+					}
+					return true, nil
+				}
+				ok, err := h(l2, ctx, msg)
+				if err == nil {
+					w.record(key, i, time.Now())
+				}
+				return ok, err
+			})
+		}
+	}
+}
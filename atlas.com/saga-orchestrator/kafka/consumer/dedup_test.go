@@ -0,0 +1,130 @@
+package consumer
+
+import (
+	"atlas-saga-orchestrator/kafka/header"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Chronicle20/atlas-kafka/handler"
+	"github.com/segmentio/kafka-go"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDedupConfigFromEnv tests that windows are configured by the token of their topic, falling back to the default
+func TestDedupConfigFromEnv(t *testing.T) {
+	l, _ := test.NewNullLogger()
+	t.Setenv("EVENT_TOPIC_CHARACTER_STATUS", "character.status")
+	t.Setenv("SAGA_DEDUP_WINDOW", "10s")
+	t.Setenv("SAGA_DEDUP_WINDOWS", "EVENT_TOPIC_CHARACTER_STATUS=1m")
+
+	c, err := DedupConfigFromEnv(l)
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, c.Window("character.status"))
+	assert.Equal(t, 10*time.Second, c.Window("compartment.status"))
+
+	t.Setenv("SAGA_DEDUP_WINDOWS", "EVENT_TOPIC_CHARACTER_STATUS")
+	_, err = DedupConfigFromEnv(l)
+	assert.Error(t, err)
+	t.Setenv("SAGA_DEDUP_WINDOWS", "EVENT_TOPIC_CHARACTER_STATUS=soon")
+	_, err = DedupConfigFromEnv(l)
+	assert.Error(t, err)
+}
+
+// TestDeduplicated tests that a message redelivered after a handler processed it within the window is suppressed for
+// that handler alone, and counted once, as is the same event read from another offset, while identical events of
+// different steps are processed
+func TestDeduplicated(t *testing.T) {
+	l, _ := test.NewNullLogger()
+	handlers := make(map[string][]handler.Handler)
+	rf := Deduplicated(l, DedupConfig{Windows: map[string]time.Duration{"status": time.Minute}})(func(topic string, h handler.Handler) (string, error) {
+		handlers[topic] = append(handlers[topic], h)
+		return topic, nil
+	})
+	count := func() float64 {
+		for _, s := range getDuplicates().Samples() {
+			if s.Labels["topic"] == "status" && s.Labels["type"] == "COMPLETED" {
+				return s.Value
+			}
+		}
+		return 0
+	}
+	deliver := func(topic string, offset int64, stepId string, value string) {
+		for _, h := range handlers[topic] {
+			_, _ = h(l, context.Background(), kafka.Message{Topic: topic, Partition: 1, Offset: offset, Value: []byte(value),
+				Headers: []kafka.Header{{Key: header.StepId, Value: []byte(stepId)}}})
+		}
+	}
+
+	completed := 0
+	fail := true
+	_, _ = rf("status", func(l logrus.FieldLogger, ctx context.Context, msg kafka.Message) (bool, error) {
+		completed++
+		return true, nil
+	})
+	_, _ = rf("status", func(l logrus.FieldLogger, ctx context.Context, msg kafka.Message) (bool, error) {
+		if fail {
+			return false, errors.New("saga not found")
+		}
+		return true, nil
+	})
+	others := 0
+	_, _ = rf("other", func(l logrus.FieldLogger, ctx context.Context, msg kafka.Message) (bool, error) {
+		others++
+		return true, nil
+	})
+
+	event := `{"transactionId":"4ef5b6a5-8f0a-4b8b-9f6e-3c1d1f6f1b2a","type":"COMPLETED","body":{"amount":100}}`
+	before := count()
+	deliver("status", 10, "mesos", event)
+	deliver("status", 10, "mesos", event)
+	assert.Equal(t, 1, completed)
+	assert.Equal(t, before+1, count())
+
+	// The handler which failed processes the redelivery, while the other suppresses it
+	fail = false
+	deliver("status", 10, "mesos", event)
+	assert.Equal(t, 1, completed)
+	assert.Equal(t, before+2, count())
+
+	// The same event produced again is a duplicate, though read from another offset
+	deliver("status", 11, "mesos", event)
+	assert.Equal(t, 1, completed)
+	assert.Equal(t, before+3, count())
+
+	// Identical events of different steps, such as those of two identical awards, are not duplicates
+	deliver("status", 12, "bonus", event)
+	assert.Equal(t, 2, completed)
+	assert.Equal(t, before+3, count())
+
+	// Events identifying no saga are duplicates only when redelivered from the same offset
+	unidentified := `{"type":"COMPLETED","body":{"amount":100}}`
+	deliver("status", 13, "", unidentified)
+	deliver("status", 14, "", unidentified)
+	assert.Equal(t, 4, completed)
+	deliver("status", 14, "", unidentified)
+	assert.Equal(t, 4, completed)
+
+	// Topics without a window process every message
+	deliver("other", 10, "mesos", event)
+	deliver("other", 10, "mesos", event)
+	assert.Equal(t, 2, others)
+}
+
+// TestDedupWindowExpire tests that a message is no longer a duplicate once its window has passed
+func TestDedupWindowExpire(t *testing.T) {
+	w := newDedupWindow(time.Minute)
+	now := time.Now()
+	w.record("key", 0, now)
+	dup, first := w.duplicate("key", 0, now.Add(30*time.Second))
+	assert.True(t, dup)
+	assert.True(t, first)
+	dup, _ = w.duplicate("key", 0, now.Add(time.Minute))
+	assert.False(t, dup)
+	assert.Empty(t, w.entries)
+	assert.Empty(t, w.order)
+}
@@ -0,0 +1,54 @@
+package faction
+
+import (
+	consumer2 "atlas-saga-orchestrator/kafka/consumer"
+	"atlas-saga-orchestrator/kafka/header"
+	faction2 "atlas-saga-orchestrator/kafka/message/faction"
+	"atlas-saga-orchestrator/saga"
+	"context"
+	"github.com/Chronicle20/atlas-kafka/consumer"
+	"github.com/Chronicle20/atlas-kafka/handler"
+	"github.com/Chronicle20/atlas-kafka/message"
+	"github.com/Chronicle20/atlas-kafka/topic"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/segmentio/kafka-go"
+	"github.com/sirupsen/logrus"
+)
+
+func InitConsumers(l logrus.FieldLogger) func(func(config consumer.Config, decorators ...model.Decorator[consumer.Config])) func(consumerGroupId string) {
+	return func(rf func(config consumer.Config, decorators ...model.Decorator[consumer.Config])) func(consumerGroupId string) {
+		return func(consumerGroupId string) {
+			rf(consumer2.NewConfig(l)("faction_status_event")(faction2.EnvStatusEventTopic)(consumerGroupId), consumer.SetHeaderParsers(consumer.SpanHeaderParser, consumer.TenantHeaderParser, header.SagaHeaderParser), consumer.SetStartOffset(kafka.LastOffset))
+		}
+	}
+}
+
+func InitHandlers(l logrus.FieldLogger) func(rf func(topic string, handler handler.Handler) (string, error)) {
+	return func(rf func(topic string, handler handler.Handler) (string, error)) {
+		var t string
+		t, _ = topic.EnvProvider(l)(faction2.EnvStatusEventTopic)()
+		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handlePointsChangedEvent)))
+		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleFactionErrorEvent)))
+	}
+}
+
+func handlePointsChangedEvent(l logrus.FieldLogger, ctx context.Context, e faction2.StatusEvent[faction2.StatusEventPointsChangedBody]) {
+	if e.Type != faction2.StatusEventTypePointsChanged {
+		return
+	}
+	_ = saga.NewProcessor(l, ctx).StepCompletedWithEvent(e.TransactionId, e)
+}
+
+func handleFactionErrorEvent(l logrus.FieldLogger, ctx context.Context, e faction2.StatusEvent[faction2.StatusEventErrorBody]) {
+	if e.Type != faction2.StatusEventTypeError {
+		return
+	}
+
+	l.WithFields(logrus.Fields{
+		"transaction_id": e.TransactionId.String(),
+		"character_id":   e.CharacterId,
+		"error_type":     e.Body.Error,
+	}).Error("Faction operation failed, marking saga step as failed")
+
+	_ = saga.NewProcessor(l, ctx).StepFailedBy(saga.ServiceFaction, e.TransactionId, e.Body.Error, "")
+}
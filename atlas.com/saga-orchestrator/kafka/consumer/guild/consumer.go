@@ -2,6 +2,7 @@ package guild
 
 import (
 	consumer2 "atlas-saga-orchestrator/kafka/consumer"
+	"atlas-saga-orchestrator/kafka/header"
 	guild2 "atlas-saga-orchestrator/kafka/message/guild"
 	"atlas-saga-orchestrator/saga"
 	"context"
@@ -10,6 +11,7 @@ import (
 	"github.com/Chronicle20/atlas-kafka/message"
 	"github.com/Chronicle20/atlas-kafka/topic"
 	"github.com/Chronicle20/atlas-model/model"
+	"github.com/google/uuid"
 	"github.com/segmentio/kafka-go"
 	"github.com/sirupsen/logrus"
 )
@@ -17,7 +19,7 @@ import (
 func InitConsumers(l logrus.FieldLogger) func(func(config consumer.Config, decorators ...model.Decorator[consumer.Config])) func(consumerGroupId string) {
 	return func(rf func(config consumer.Config, decorators ...model.Decorator[consumer.Config])) func(consumerGroupId string) {
 		return func(consumerGroupId string) {
-			rf(consumer2.NewConfig(l)("guild_status_event")(guild2.EnvStatusEventTopic)(consumerGroupId), consumer.SetHeaderParsers(consumer.SpanHeaderParser, consumer.TenantHeaderParser), consumer.SetStartOffset(kafka.LastOffset))
+			rf(consumer2.NewConfig(l)("guild_status_event")(guild2.EnvStatusEventTopic)(consumerGroupId), consumer.SetHeaderParsers(consumer.SpanHeaderParser, consumer.TenantHeaderParser, header.SagaHeaderParser), consumer.SetStartOffset(kafka.LastOffset))
 		}
 	}
 }
@@ -31,6 +33,7 @@ func InitHandlers(l logrus.FieldLogger) func(rf func(topic string, handler handl
 		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleGuildDisbandedEvent)))
 		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleGuildEmblemUpdatedEvent)))
 		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleGuildCapacityUpdatedEvent)))
+		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleGuildErrorEvent)))
 	}
 }
 
@@ -38,33 +41,52 @@ func handleGuildRequestAgreementEvent(l logrus.FieldLogger, ctx context.Context,
 	if e.Type != guild2.StatusEventTypeRequestAgreement {
 		return
 	}
-	_ = saga.NewProcessor(l, ctx).StepCompleted(e.TransactionId, true)
+	_ = saga.NewProcessor(l, ctx).StepCompletedWithEvent(e.TransactionId, e)
 }
 
 func handleGuildCreatedEvent(l logrus.FieldLogger, ctx context.Context, e guild2.StatusEvent[guild2.StatusEventCreatedBody]) {
 	if e.Type != guild2.StatusEventTypeCreated {
 		return
 	}
-	_ = saga.NewProcessor(l, ctx).StepCompleted(e.TransactionId, true)
+	_ = saga.NewProcessor(l, ctx).StepCompletedWithEvent(e.TransactionId, e)
 }
 
 func handleGuildDisbandedEvent(l logrus.FieldLogger, ctx context.Context, e guild2.StatusEvent[guild2.StatusEventDisbandedBody]) {
 	if e.Type != guild2.StatusEventTypeDisbanded {
 		return
 	}
-	_ = saga.NewProcessor(l, ctx).StepCompleted(e.TransactionId, true)
+	_ = saga.NewProcessor(l, ctx).StepCompletedWithEvent(e.TransactionId, e)
 }
 
 func handleGuildEmblemUpdatedEvent(l logrus.FieldLogger, ctx context.Context, e guild2.StatusEvent[guild2.StatusEventEmblemUpdatedBody]) {
 	if e.Type != guild2.StatusEventTypeEmblemUpdated {
 		return
 	}
-	_ = saga.NewProcessor(l, ctx).StepCompleted(e.TransactionId, true)
+	_ = saga.NewProcessor(l, ctx).StepCompletedWithEvent(e.TransactionId, e)
 }
 
 func handleGuildCapacityUpdatedEvent(l logrus.FieldLogger, ctx context.Context, e guild2.StatusEvent[guild2.StatusEventCapacityUpdatedBody]) {
 	if e.Type != guild2.StatusEventTypeCapacityUpdated {
 		return
 	}
-	_ = saga.NewProcessor(l, ctx).StepCompleted(e.TransactionId, true)
-}
\ No newline at end of file
+	_ = saga.NewProcessor(l, ctx).StepCompletedWithEvent(e.TransactionId, e)
+}
+
+func handleGuildErrorEvent(l logrus.FieldLogger, ctx context.Context, e guild2.StatusEvent[guild2.StatusEventErrorBody]) {
+	if e.Type != guild2.StatusEventTypeError {
+		return
+	}
+	// Errors of guild operations requested outside of a saga carry no transaction
+	if e.TransactionId == uuid.Nil {
+		return
+	}
+
+	l.WithFields(logrus.Fields{
+		"transaction_id": e.TransactionId.String(),
+		"guild_id":       e.GuildId,
+		"actor_id":       e.Body.ActorId,
+		"error_type":     e.Body.Error,
+	}).Error("Guild operation failed, marking saga step as failed")
+
+	_ = saga.NewProcessor(l, ctx).StepFailedBy(saga.ServiceGuild, e.TransactionId, e.Body.Error, "")
+}
@@ -0,0 +1,54 @@
+package instance
+
+import (
+	consumer2 "atlas-saga-orchestrator/kafka/consumer"
+	"atlas-saga-orchestrator/kafka/header"
+	instance2 "atlas-saga-orchestrator/kafka/message/instance"
+	"atlas-saga-orchestrator/saga"
+	"context"
+	"github.com/Chronicle20/atlas-kafka/consumer"
+	"github.com/Chronicle20/atlas-kafka/handler"
+	"github.com/Chronicle20/atlas-kafka/message"
+	"github.com/Chronicle20/atlas-kafka/topic"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/segmentio/kafka-go"
+	"github.com/sirupsen/logrus"
+)
+
+func InitConsumers(l logrus.FieldLogger) func(func(config consumer.Config, decorators ...model.Decorator[consumer.Config])) func(consumerGroupId string) {
+	return func(rf func(config consumer.Config, decorators ...model.Decorator[consumer.Config])) func(consumerGroupId string) {
+		return func(consumerGroupId string) {
+			rf(consumer2.NewConfig(l)("instance_status_event")(instance2.EnvStatusEventTopic)(consumerGroupId), consumer.SetHeaderParsers(consumer.SpanHeaderParser, consumer.TenantHeaderParser, header.SagaHeaderParser), consumer.SetStartOffset(kafka.LastOffset))
+		}
+	}
+}
+
+func InitHandlers(l logrus.FieldLogger) func(rf func(topic string, handler handler.Handler) (string, error)) {
+	return func(rf func(topic string, handler handler.Handler) (string, error)) {
+		var t string
+		t, _ = topic.EnvProvider(l)(instance2.EnvStatusEventTopic)()
+		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleCooldownResetEvent)))
+		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleInstanceErrorEvent)))
+	}
+}
+
+func handleCooldownResetEvent(l logrus.FieldLogger, ctx context.Context, e instance2.StatusEvent[instance2.StatusEventCooldownResetBody]) {
+	if e.Type != instance2.StatusEventTypeCooldownReset {
+		return
+	}
+	_ = saga.NewProcessor(l, ctx).StepCompletedWithEvent(e.TransactionId, e)
+}
+
+func handleInstanceErrorEvent(l logrus.FieldLogger, ctx context.Context, e instance2.StatusEvent[instance2.StatusEventErrorBody]) {
+	if e.Type != instance2.StatusEventTypeError {
+		return
+	}
+
+	l.WithFields(logrus.Fields{
+		"transaction_id": e.TransactionId.String(),
+		"character_id":   e.CharacterId,
+		"error_type":     e.Body.Error,
+	}).Error("Instance operation failed, marking saga step as failed")
+
+	_ = saga.NewProcessor(l, ctx).StepFailedBy(saga.ServiceInstance, e.TransactionId, e.Body.Error, "")
+}
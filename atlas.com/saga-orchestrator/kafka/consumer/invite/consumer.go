@@ -2,6 +2,7 @@ package invite
 
 import (
 	consumer2 "atlas-saga-orchestrator/kafka/consumer"
+	"atlas-saga-orchestrator/kafka/header"
 	"atlas-saga-orchestrator/kafka/message/invite"
 	"atlas-saga-orchestrator/saga"
 	"context"
@@ -17,7 +18,7 @@ import (
 func InitConsumers(l logrus.FieldLogger) func(func(config consumer.Config, decorators ...model.Decorator[consumer.Config])) func(consumerGroupId string) {
 	return func(rf func(config consumer.Config, decorators ...model.Decorator[consumer.Config])) func(consumerGroupId string) {
 		return func(consumerGroupId string) {
-			rf(consumer2.NewConfig(l)("invite_status_event")(invite.EnvEventStatusTopic)(consumerGroupId), consumer.SetHeaderParsers(consumer.SpanHeaderParser, consumer.TenantHeaderParser), consumer.SetStartOffset(kafka.LastOffset))
+			rf(consumer2.NewConfig(l)("invite_status_event")(invite.EnvEventStatusTopic)(consumerGroupId), consumer.SetHeaderParsers(consumer.SpanHeaderParser, consumer.TenantHeaderParser, header.SagaHeaderParser), consumer.SetStartOffset(kafka.LastOffset))
 		}
 	}
 }
@@ -45,7 +46,12 @@ func handleCreatedStatusEvent(l logrus.FieldLogger, ctx context.Context, e invit
 		"target_id":      e.Body.TargetId,
 	}).Debug("Received invite created event.")
 
-	_ = saga.NewProcessor(l, ctx).StepCompleted(e.TransactionId, true)
+	// A step awaiting its invitation being answered is completed by the answer instead
+	p := saga.NewProcessor(l, ctx)
+	if s, err := p.GetById(e.TransactionId); err == nil && s.AwaitingInviteAnswer() {
+		return
+	}
+	_ = p.StepCompletedWithEvent(e.TransactionId, e)
 }
 
 func handleAcceptedStatusEvent(l logrus.FieldLogger, ctx context.Context, e invite.StatusEvent[invite.AcceptedEventBody]) {
@@ -61,7 +67,7 @@ func handleAcceptedStatusEvent(l logrus.FieldLogger, ctx context.Context, e invi
 		"target_id":      e.Body.TargetId,
 	}).Debug("Received invite accepted event.")
 
-	_ = saga.NewProcessor(l, ctx).StepCompleted(e.TransactionId, true)
+	_ = saga.NewProcessor(l, ctx).StepCompletedWithEvent(e.TransactionId, e)
 }
 
 func handleRejectedStatusEvent(l logrus.FieldLogger, ctx context.Context, e invite.StatusEvent[invite.RejectedEventBody]) {
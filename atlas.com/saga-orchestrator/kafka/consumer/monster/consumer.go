@@ -0,0 +1,54 @@
+package monster
+
+import (
+	consumer2 "atlas-saga-orchestrator/kafka/consumer"
+	"atlas-saga-orchestrator/kafka/message/monster"
+	"atlas-saga-orchestrator/saga"
+	"context"
+	"github.com/Chronicle20/atlas-kafka/consumer"
+	"github.com/Chronicle20/atlas-kafka/handler"
+	"github.com/Chronicle20/atlas-kafka/message"
+	"github.com/Chronicle20/atlas-kafka/topic"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/segmentio/kafka-go"
+	"github.com/sirupsen/logrus"
+)
+
+func InitConsumers(l logrus.FieldLogger) func(func(config consumer.Config, decorators ...model.Decorator[consumer.Config])) func(consumerGroupId string) {
+	return func(rf func(config consumer.Config, decorators ...model.Decorator[consumer.Config])) func(consumerGroupId string) {
+		return func(consumerGroupId string) {
+			rf(consumer2.NewConfig(l)("monster_status_event")(monster.EnvEventTopicMonsterStatus)(consumerGroupId), consumer.SetHeaderParsers(consumer.SpanHeaderParser, consumer.TenantHeaderParser), consumer.SetStartOffset(kafka.LastOffset))
+		}
+	}
+}
+
+func InitHandlers(l logrus.FieldLogger) func(rf func(topic string, handler handler.Handler) (string, error)) {
+	return func(rf func(topic string, handler handler.Handler) (string, error)) {
+		var t string
+		t, _ = topic.EnvProvider(l)(monster.EnvEventTopicMonsterStatus)()
+		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleKilledStatusEvent)))
+	}
+}
+
+// handleKilledStatusEvent counts a monster kill towards the await_kill_count steps awaiting kills in its field. Monster
+// deaths are not caused by sagas, so carry no transaction.
+func handleKilledStatusEvent(l logrus.FieldLogger, ctx context.Context, e monster.StatusEvent[monster.StatusEventKilledBody]) {
+	if e.Type != monster.EventMonsterStatusKilled {
+		return
+	}
+
+	contributors := make([]uint32, 0, len(e.Body.DamageEntries)+1)
+	contributors = append(contributors, e.Body.ActorId)
+	for _, d := range e.Body.DamageEntries {
+		contributors = append(contributors, d.CharacterId)
+	}
+
+	_ = saga.NewProcessor(l, ctx).MonsterKilled(saga.MonsterKill{
+		WorldId:      e.WorldId,
+		ChannelId:    e.ChannelId,
+		MapId:        e.MapId,
+		Instance:     e.Instance,
+		MonsterId:    e.MonsterId,
+		Contributors: contributors,
+	})
+}
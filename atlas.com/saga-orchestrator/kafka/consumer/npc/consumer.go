@@ -0,0 +1,71 @@
+package npc
+
+import (
+	consumer2 "atlas-saga-orchestrator/kafka/consumer"
+	"atlas-saga-orchestrator/kafka/header"
+	npc2 "atlas-saga-orchestrator/kafka/message/npc"
+	"atlas-saga-orchestrator/saga"
+	"context"
+	"github.com/Chronicle20/atlas-kafka/consumer"
+	"github.com/Chronicle20/atlas-kafka/handler"
+	"github.com/Chronicle20/atlas-kafka/message"
+	"github.com/Chronicle20/atlas-kafka/topic"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/segmentio/kafka-go"
+	"github.com/sirupsen/logrus"
+)
+
+func InitConsumers(l logrus.FieldLogger) func(func(config consumer.Config, decorators ...model.Decorator[consumer.Config])) func(consumerGroupId string) {
+	return func(rf func(config consumer.Config, decorators ...model.Decorator[consumer.Config])) func(consumerGroupId string) {
+		return func(consumerGroupId string) {
+			rf(consumer2.NewConfig(l)("npc_status_event")(npc2.EnvEventStatusTopic)(consumerGroupId), consumer.SetHeaderParsers(consumer.SpanHeaderParser, consumer.TenantHeaderParser, header.SagaHeaderParser), consumer.SetStartOffset(kafka.LastOffset))
+		}
+	}
+}
+
+func InitHandlers(l logrus.FieldLogger) func(rf func(topic string, handler handler.Handler) (string, error)) {
+	return func(rf func(topic string, handler handler.Handler) (string, error)) {
+		var t string
+		t, _ = topic.EnvProvider(l)(npc2.EnvEventStatusTopic)()
+		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleEscortSpawnedEvent)))
+		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleEscortArrivedEvent)))
+		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleEscortFailedEvent)))
+		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleErrorEvent)))
+	}
+}
+
+func handleEscortSpawnedEvent(l logrus.FieldLogger, ctx context.Context, e npc2.StatusEvent[npc2.StatusEventEscortSpawnedBody]) {
+	if e.Type != npc2.StatusEventTypeEscortSpawned {
+		return
+	}
+	_ = saga.NewProcessor(l, ctx).StepCompletedWithEvent(e.TransactionId, e)
+}
+
+func handleEscortArrivedEvent(l logrus.FieldLogger, ctx context.Context, e npc2.StatusEvent[npc2.StatusEventEscortArrivedBody]) {
+	if e.Type != npc2.StatusEventTypeEscortArrived {
+		return
+	}
+	_ = saga.NewProcessor(l, ctx).EscortArrived(e.TransactionId, e)
+}
+
+func handleEscortFailedEvent(l logrus.FieldLogger, ctx context.Context, e npc2.StatusEvent[npc2.StatusEventEscortFailedBody]) {
+	if e.Type != npc2.StatusEventTypeEscortFailed {
+		return
+	}
+	_ = saga.NewProcessor(l, ctx).EscortFailed(e.TransactionId, e.Body.Reason)
+}
+
+func handleErrorEvent(l logrus.FieldLogger, ctx context.Context, e npc2.StatusEvent[npc2.StatusEventErrorBody]) {
+	if e.Type != npc2.StatusEventTypeError {
+		return
+	}
+
+	l.WithFields(logrus.Fields{
+		"transaction_id": e.TransactionId.String(),
+		"npc_id":         e.NpcId,
+		"map_id":         e.MapId,
+		"error_type":     e.Body.Error,
+	}).Error("NPC command failed, marking saga step as failed")
+
+	_ = saga.NewProcessor(l, ctx).StepFailedBy(saga.ServiceNpc, e.TransactionId, e.Body.Error, "")
+}
@@ -0,0 +1,63 @@
+package reactor
+
+import (
+	consumer2 "atlas-saga-orchestrator/kafka/consumer"
+	"atlas-saga-orchestrator/kafka/header"
+	reactor2 "atlas-saga-orchestrator/kafka/message/reactor"
+	"atlas-saga-orchestrator/saga"
+	"context"
+	"github.com/Chronicle20/atlas-kafka/consumer"
+	"github.com/Chronicle20/atlas-kafka/handler"
+	"github.com/Chronicle20/atlas-kafka/message"
+	"github.com/Chronicle20/atlas-kafka/topic"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/segmentio/kafka-go"
+	"github.com/sirupsen/logrus"
+)
+
+func InitConsumers(l logrus.FieldLogger) func(func(config consumer.Config, decorators ...model.Decorator[consumer.Config])) func(consumerGroupId string) {
+	return func(rf func(config consumer.Config, decorators ...model.Decorator[consumer.Config])) func(consumerGroupId string) {
+		return func(consumerGroupId string) {
+			rf(consumer2.NewConfig(l)("reactor_status_event")(reactor2.EnvEventStatusTopic)(consumerGroupId), consumer.SetHeaderParsers(consumer.SpanHeaderParser, consumer.TenantHeaderParser, header.SagaHeaderParser), consumer.SetStartOffset(kafka.LastOffset))
+		}
+	}
+}
+
+func InitHandlers(l logrus.FieldLogger) func(rf func(topic string, handler handler.Handler) (string, error)) {
+	return func(rf func(topic string, handler handler.Handler) (string, error)) {
+		var t string
+		t, _ = topic.EnvProvider(l)(reactor2.EnvEventStatusTopic)()
+		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleHitEvent)))
+		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleStateChangedEvent)))
+		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleErrorEvent)))
+	}
+}
+
+func handleHitEvent(l logrus.FieldLogger, ctx context.Context, e reactor2.StatusEvent[reactor2.StatusEventHitBody]) {
+	if e.Type != reactor2.StatusEventTypeHit {
+		return
+	}
+	_ = saga.NewProcessor(l, ctx).StepCompletedWithEvent(e.TransactionId, e)
+}
+
+func handleStateChangedEvent(l logrus.FieldLogger, ctx context.Context, e reactor2.StatusEvent[reactor2.StatusEventStateChangedBody]) {
+	if e.Type != reactor2.StatusEventTypeStateChanged {
+		return
+	}
+	_ = saga.NewProcessor(l, ctx).StepCompletedWithEvent(e.TransactionId, e)
+}
+
+func handleErrorEvent(l logrus.FieldLogger, ctx context.Context, e reactor2.StatusEvent[reactor2.StatusEventErrorBody]) {
+	if e.Type != reactor2.StatusEventTypeError {
+		return
+	}
+
+	l.WithFields(logrus.Fields{
+		"transaction_id": e.TransactionId.String(),
+		"reactor_id":     e.ReactorId,
+		"map_id":         e.MapId,
+		"error_type":     e.Body.Error,
+	}).Error("Reactor command failed, marking saga step as failed")
+
+	_ = saga.NewProcessor(l, ctx).StepFailedBy(saga.ServiceReactor, e.TransactionId, e.Body.Error, "")
+}
@@ -1,10 +1,13 @@
 package saga
 
 import (
+	"atlas-saga-orchestrator/cluster"
 	consumer2 "atlas-saga-orchestrator/kafka/consumer"
+	"atlas-saga-orchestrator/kafka/header"
 	"atlas-saga-orchestrator/kafka/message/saga"
 	saga2 "atlas-saga-orchestrator/saga"
 	"context"
+	"errors"
 	"github.com/Chronicle20/atlas-kafka/consumer"
 	"github.com/Chronicle20/atlas-kafka/handler"
 	"github.com/Chronicle20/atlas-kafka/message"
@@ -16,7 +19,8 @@ import (
 func InitConsumers(l logrus.FieldLogger) func(func(config consumer.Config, decorators ...model.Decorator[consumer.Config])) func(consumerGroupId string) {
 	return func(rf func(config consumer.Config, decorators ...model.Decorator[consumer.Config])) func(consumerGroupId string) {
 		return func(consumerGroupId string) {
-			rf(consumer2.NewConfig(l)("saga_command")(saga.EnvCommandTopic)(consumerGroupId), consumer.SetHeaderParsers(consumer.SpanHeaderParser, consumer.TenantHeaderParser))
+			rf(consumer2.NewConfig(l)("saga_command")(saga.EnvCommandTopic)(consumerGroupId), consumer.SetHeaderParsers(consumer.SpanHeaderParser, consumer.TenantHeaderParser, header.SagaHeaderParser))
+			rf(consumer2.NewConfig(l)("saga_step_due_command")(saga.EnvCommandTopicStepDue)(consumerGroupId), consumer.SetHeaderParsers(consumer.SpanHeaderParser, consumer.TenantHeaderParser))
 		}
 	}
 }
@@ -26,6 +30,8 @@ func InitHandlers(l logrus.FieldLogger) func(rf func(topic string, handler handl
 		var t string
 		t, _ = topic.EnvProvider(l)(saga.EnvCommandTopic)()
 		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleSagaCommand)))
+		t, _ = topic.EnvProvider(l)(saga.EnvCommandTopicStepDue)()
+		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleStepDueCommand)))
 	}
 }
 
@@ -37,10 +43,20 @@ func handleSagaCommand(l logrus.FieldLogger, ctx context.Context, c saga2.Saga)
 		"steps_count":    len(c.Steps),
 	})
 
+	// Replicas each consume every command, executing only those of the sagas they own
+	if !cluster.GetMembership().Owns(c.TransactionId) {
+		logger.Debugf("Saga owned by replica [%s].", cluster.GetMembership().Owner(c.TransactionId))
+		return
+	}
+
 	logger.Info("Handling saga command")
 
 	processor := saga2.NewProcessor(logger, ctx)
 	err := processor.Put(c)
+	if errors.Is(err, saga2.ErrSagaQueued) {
+		logger.Info("Saga queued until it can be started")
+		return
+	}
 	if err != nil {
 		logger.WithError(err).Error("Failed to insert saga into cache")
 		return
@@ -48,3 +64,15 @@ func handleSagaCommand(l logrus.FieldLogger, ctx context.Context, c saga2.Saga)
 
 	logger.Info("Successfully inserted saga into cache")
 }
+
+// handleStepDueCommand acts on the step of a saga found due by the replica leading the role finding such steps, should
+// this replica own the saga
+func handleStepDueCommand(l logrus.FieldLogger, ctx context.Context, c saga.StepDueCommand) {
+	if !cluster.GetMembership().Owns(c.TransactionId) {
+		return
+	}
+	saga2.StepDue(l.WithFields(logrus.Fields{
+		"transaction_id": c.TransactionId.String(),
+		"step_id":        c.StepId,
+	}), ctx, c.TransactionId, c.StepId, c.Type)
+}
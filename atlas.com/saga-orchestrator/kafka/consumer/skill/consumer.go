@@ -2,6 +2,7 @@ package skill
 
 import (
 	consumer2 "atlas-saga-orchestrator/kafka/consumer"
+	"atlas-saga-orchestrator/kafka/header"
 	skill2 "atlas-saga-orchestrator/kafka/message/skill"
 	"atlas-saga-orchestrator/saga"
 	"context"
@@ -17,7 +18,7 @@ import (
 func InitConsumers(l logrus.FieldLogger) func(func(config consumer.Config, decorators ...model.Decorator[consumer.Config])) func(consumerGroupId string) {
 	return func(rf func(config consumer.Config, decorators ...model.Decorator[consumer.Config])) func(consumerGroupId string) {
 		return func(consumerGroupId string) {
-			rf(consumer2.NewConfig(l)("skill_status_event")(skill2.EnvStatusEventTopic)(consumerGroupId), consumer.SetHeaderParsers(consumer.SpanHeaderParser, consumer.TenantHeaderParser), consumer.SetStartOffset(kafka.LastOffset))
+			rf(consumer2.NewConfig(l)("skill_status_event")(skill2.EnvStatusEventTopic)(consumerGroupId), consumer.SetHeaderParsers(consumer.SpanHeaderParser, consumer.TenantHeaderParser, header.SagaHeaderParser), consumer.SetStartOffset(kafka.LastOffset))
 		}
 	}
 }
@@ -28,6 +29,7 @@ func InitHandlers(l logrus.FieldLogger) func(rf func(topic string, handler handl
 		t, _ = topic.EnvProvider(l)(skill2.EnvStatusEventTopic)()
 		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleSkillCreatedEvent)))
 		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleSkillUpdatedEvent)))
+		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleSkillCooldownsResetEvent)))
 	}
 }
 
@@ -35,12 +37,19 @@ func handleSkillCreatedEvent(l logrus.FieldLogger, ctx context.Context, e skill2
 	if e.Type != skill2.StatusEventTypeCreated {
 		return
 	}
-	_ = saga.NewProcessor(l, ctx).StepCompleted(e.TransactionId, true)
+	_ = saga.NewProcessor(l, ctx).StepCompletedWithEvent(e.TransactionId, e)
 }
 
 func handleSkillUpdatedEvent(l logrus.FieldLogger, ctx context.Context, e skill2.StatusEvent[skill2.StatusEventUpdatedBody]) {
 	if e.Type != skill2.StatusEventTypeUpdated {
 		return
 	}
-	_ = saga.NewProcessor(l, ctx).StepCompleted(e.TransactionId, true)
-}
\ No newline at end of file
+	_ = saga.NewProcessor(l, ctx).StepCompletedWithEvent(e.TransactionId, e)
+}
+
+func handleSkillCooldownsResetEvent(l logrus.FieldLogger, ctx context.Context, e skill2.StatusEvent[skill2.StatusEventCooldownsResetBody]) {
+	if e.Type != skill2.StatusEventTypeCooldownsReset {
+		return
+	}
+	_ = saga.NewProcessor(l, ctx).StepCompletedWithEvent(e.TransactionId, e)
+}
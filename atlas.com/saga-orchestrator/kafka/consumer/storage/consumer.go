@@ -0,0 +1,55 @@
+package storage
+
+import (
+	consumer2 "atlas-saga-orchestrator/kafka/consumer"
+	"atlas-saga-orchestrator/kafka/header"
+	storage2 "atlas-saga-orchestrator/kafka/message/storage"
+	"atlas-saga-orchestrator/saga"
+	"context"
+	"github.com/Chronicle20/atlas-kafka/consumer"
+	"github.com/Chronicle20/atlas-kafka/handler"
+	"github.com/Chronicle20/atlas-kafka/message"
+	"github.com/Chronicle20/atlas-kafka/topic"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/segmentio/kafka-go"
+	"github.com/sirupsen/logrus"
+)
+
+func InitConsumers(l logrus.FieldLogger) func(func(config consumer.Config, decorators ...model.Decorator[consumer.Config])) func(consumerGroupId string) {
+	return func(rf func(config consumer.Config, decorators ...model.Decorator[consumer.Config])) func(consumerGroupId string) {
+		return func(consumerGroupId string) {
+			rf(consumer2.NewConfig(l)("storage_status_event")(storage2.EnvStatusEventTopic)(consumerGroupId), consumer.SetHeaderParsers(consumer.SpanHeaderParser, consumer.TenantHeaderParser, header.SagaHeaderParser), consumer.SetStartOffset(kafka.LastOffset))
+		}
+	}
+}
+
+func InitHandlers(l logrus.FieldLogger) func(rf func(topic string, handler handler.Handler) (string, error)) {
+	return func(rf func(topic string, handler handler.Handler) (string, error)) {
+		var t string
+		t, _ = topic.EnvProvider(l)(storage2.EnvStatusEventTopic)()
+		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleCapacityChangedEvent)))
+		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleStorageErrorEvent)))
+	}
+}
+
+func handleCapacityChangedEvent(l logrus.FieldLogger, ctx context.Context, e storage2.StatusEvent[storage2.StatusEventCapacityChangedBody]) {
+	if e.Type != storage2.StatusEventTypeCapacityChanged {
+		return
+	}
+	_ = saga.NewProcessor(l, ctx).StepCompletedWithEvent(e.TransactionId, e)
+}
+
+func handleStorageErrorEvent(l logrus.FieldLogger, ctx context.Context, e storage2.StatusEvent[storage2.StatusEventErrorBody]) {
+	if e.Type != storage2.StatusEventTypeError {
+		return
+	}
+
+	l.WithFields(logrus.Fields{
+		"transaction_id": e.TransactionId.String(),
+		"world_id":       e.WorldId,
+		"account_id":     e.AccountId,
+		"error_type":     e.Body.Error,
+	}).Error("Storage operation failed, marking saga step as failed")
+
+	_ = saga.NewProcessor(l, ctx).StepFailedBy(saga.ServiceStorage, e.TransactionId, e.Body.Error, "")
+}
@@ -0,0 +1,78 @@
+package worldstate
+
+import (
+	consumer2 "atlas-saga-orchestrator/kafka/consumer"
+	"atlas-saga-orchestrator/kafka/header"
+	worldstate2 "atlas-saga-orchestrator/kafka/message/worldstate"
+	"atlas-saga-orchestrator/saga"
+	"context"
+	"github.com/Chronicle20/atlas-kafka/consumer"
+	"github.com/Chronicle20/atlas-kafka/handler"
+	"github.com/Chronicle20/atlas-kafka/message"
+	"github.com/Chronicle20/atlas-kafka/topic"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/segmentio/kafka-go"
+	"github.com/sirupsen/logrus"
+)
+
+func InitConsumers(l logrus.FieldLogger) func(func(config consumer.Config, decorators ...model.Decorator[consumer.Config])) func(consumerGroupId string) {
+	return func(rf func(config consumer.Config, decorators ...model.Decorator[consumer.Config])) func(consumerGroupId string) {
+		return func(consumerGroupId string) {
+			rf(consumer2.NewConfig(l)("world_state_status_event")(worldstate2.EnvStatusEventTopic)(consumerGroupId), consumer.SetHeaderParsers(consumer.SpanHeaderParser, consumer.TenantHeaderParser, header.SagaHeaderParser), consumer.SetStartOffset(kafka.LastOffset))
+		}
+	}
+}
+
+func InitHandlers(l logrus.FieldLogger) func(rf func(topic string, handler handler.Handler) (string, error)) {
+	return func(rf func(topic string, handler handler.Handler) (string, error)) {
+		var t string
+		t, _ = topic.EnvProvider(l)(worldstate2.EnvStatusEventTopic)()
+		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleShopStockAdjustedEvent)))
+		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleEventFlagSetEvent)))
+		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleEventBuffAppliedEvent)))
+		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleEventBuffRemovedEvent)))
+		_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleErrorEvent)))
+	}
+}
+
+func handleShopStockAdjustedEvent(l logrus.FieldLogger, ctx context.Context, e worldstate2.StatusEvent[worldstate2.StatusEventShopStockAdjustedBody]) {
+	if e.Type != worldstate2.StatusEventTypeShopStockAdjusted {
+		return
+	}
+	_ = saga.NewProcessor(l, ctx).StepCompletedWithEvent(e.TransactionId, e)
+}
+
+func handleEventFlagSetEvent(l logrus.FieldLogger, ctx context.Context, e worldstate2.StatusEvent[worldstate2.StatusEventEventFlagSetBody]) {
+	if e.Type != worldstate2.StatusEventTypeEventFlagSet {
+		return
+	}
+	_ = saga.NewProcessor(l, ctx).StepCompletedWithEvent(e.TransactionId, e)
+}
+
+func handleEventBuffAppliedEvent(l logrus.FieldLogger, ctx context.Context, e worldstate2.StatusEvent[worldstate2.StatusEventEventBuffAppliedBody]) {
+	if e.Type != worldstate2.StatusEventTypeEventBuffApplied {
+		return
+	}
+	_ = saga.NewProcessor(l, ctx).StepCompletedWithEvent(e.TransactionId, e)
+}
+
+func handleEventBuffRemovedEvent(l logrus.FieldLogger, ctx context.Context, e worldstate2.StatusEvent[worldstate2.StatusEventEventBuffRemovedBody]) {
+	if e.Type != worldstate2.StatusEventTypeEventBuffRemoved {
+		return
+	}
+	_ = saga.NewProcessor(l, ctx).StepCompletedWithEvent(e.TransactionId, e)
+}
+
+func handleErrorEvent(l logrus.FieldLogger, ctx context.Context, e worldstate2.StatusEvent[worldstate2.StatusEventErrorBody]) {
+	if e.Type != worldstate2.StatusEventTypeError {
+		return
+	}
+
+	l.WithFields(logrus.Fields{
+		"transaction_id": e.TransactionId.String(),
+		"world_id":       e.WorldId,
+		"error_type":     e.Body.Error,
+	}).Error("World state mutation failed, marking saga step as failed")
+
+	_ = saga.NewProcessor(l, ctx).StepFailedBy(saga.ServiceWorldState, e.TransactionId, e.Body.Error, "")
+}
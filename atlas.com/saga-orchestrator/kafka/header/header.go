@@ -0,0 +1,126 @@
+package header
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Chronicle20/atlas-kafka/producer"
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+)
+
+// Headers identifying the saga step a message was produced for, alongside the tenant and span headers
+const (
+	TransactionId = "SAGA_TRANSACTION_ID"
+	SagaType      = "SAGA_TYPE"
+	StepId        = "SAGA_STEP_ID"
+)
+
+// Saga is the metadata of the saga step a message was produced for
+type Saga struct {
+	TransactionId uuid.UUID
+	SagaType      string
+	StepId        string
+}
+
+// Headers returns the metadata as message headers
+func (s Saga) Headers() map[string]string {
+	return map[string]string{
+		TransactionId: s.TransactionId.String(),
+		SagaType:      s.SagaType,
+		StepId:        s.StepId,
+	}
+}
+
+// carrier holds the metadata of the saga step being dispatched, which processors created from the same context read
+// when producing. Processors capture their context when created, so the metadata cannot be threaded through it.
+type carrier struct {
+	mutex sync.Mutex
+	saga  Saga
+	set   bool
+}
+
+type carrierKey struct{}
+
+type inboundKey struct{}
+
+// WithCarrier returns a context able to carry the metadata of the saga step being dispatched. A context already
+// carrying metadata is returned unchanged, so nested processors share it.
+func WithCarrier(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(carrierKey{}).(*carrier); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, carrierKey{}, &carrier{})
+}
+
+// Set records the saga step being dispatched on the context's carrier, returning a function restoring the prior
+// metadata. Contexts without a carrier are unaffected.
+func Set(ctx context.Context, s Saga) func() {
+	c, ok := ctx.Value(carrierKey{}).(*carrier)
+	if !ok {
+		return func() {}
+	}
+	c.mutex.Lock()
+	prior, priorSet := c.saga, c.set
+	c.saga, c.set = s, true
+	c.mutex.Unlock()
+	return func() {
+		c.mutex.Lock()
+		c.saga, c.set = prior, priorSet
+		c.mutex.Unlock()
+	}
+}
+
+// FromContext returns the metadata of the saga step being dispatched, if any
+func FromContext(ctx context.Context) (Saga, bool) {
+	c, ok := ctx.Value(carrierKey{}).(*carrier)
+	if !ok {
+		return Saga{}, false
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.saga, c.set
+}
+
+// SagaHeaderDecorator decorates produced messages with the metadata of the saga step being dispatched
+func SagaHeaderDecorator(ctx context.Context) producer.HeaderDecorator {
+	return func() (map[string]string, error) {
+		s, ok := FromContext(ctx)
+		if !ok {
+			return map[string]string{}, nil
+		}
+		return s.Headers(), nil
+	}
+}
+
+// SagaHeaderParser propagates the saga metadata of consumed messages into their context. Messages without the
+// headers, such as those produced before they were introduced, are unaffected.
+func SagaHeaderParser(ctx context.Context, headers []kafka.Header) context.Context {
+	var s Saga
+	found := false
+	for _, h := range headers {
+		switch h.Key {
+		case TransactionId:
+			if id, err := uuid.Parse(string(h.Value)); err == nil {
+				s.TransactionId = id
+				found = true
+			}
+		case SagaType:
+			s.SagaType = string(h.Value)
+			found = true
+		case StepId:
+			s.StepId = string(h.Value)
+			found = true
+		}
+	}
+	if !found {
+		return ctx
+	}
+	return context.WithValue(ctx, inboundKey{}, s)
+}
+
+// InboundFromContext returns the saga metadata of the message being consumed, if it carried any
+func InboundFromContext(ctx context.Context) (Saga, bool) {
+	s, ok := ctx.Value(inboundKey{}).(Saga)
+	return s, ok
+}
@@ -0,0 +1,60 @@
+package header
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSagaHeaderDecorator tests that produced messages carry the metadata of the saga step being dispatched
+func TestSagaHeaderDecorator(t *testing.T) {
+	ctx := WithCarrier(context.Background())
+	assert.Equal(t, ctx, WithCarrier(ctx))
+
+	d := SagaHeaderDecorator(ctx)
+	hs, err := d()
+	assert.NoError(t, err)
+	assert.Empty(t, hs)
+
+	outer := Saga{TransactionId: uuid.New(), SagaType: "quest_reward", StepId: "award"}
+	restoreOuter := Set(ctx, outer)
+	inner := Saga{TransactionId: uuid.New(), SagaType: "quest_reward", StepId: "award_mesos"}
+	restoreInner := Set(ctx, inner)
+
+	hs, _ = d()
+	assert.Equal(t, map[string]string{TransactionId: inner.TransactionId.String(), SagaType: "quest_reward", StepId: "award_mesos"}, hs)
+
+	// Restoring returns to the metadata of the enclosing dispatch
+	restoreInner()
+	hs, _ = d()
+	assert.Equal(t, "award", hs[StepId])
+	restoreOuter()
+	_, ok := FromContext(ctx)
+	assert.False(t, ok)
+
+	// Contexts without a carrier are unaffected
+	Set(context.Background(), outer)()
+	_, ok = FromContext(context.Background())
+	assert.False(t, ok)
+}
+
+// TestSagaHeaderParser tests that the saga metadata of consumed messages is propagated into their context
+func TestSagaHeaderParser(t *testing.T) {
+	id := uuid.New()
+	ctx := SagaHeaderParser(context.Background(), []kafka.Header{
+		{Key: "TENANT_ID", Value: []byte(uuid.New().String())},
+		{Key: TransactionId, Value: []byte(id.String())},
+		{Key: SagaType, Value: []byte("death_penalty")},
+		{Key: StepId, Value: []byte("exp")},
+	})
+	s, ok := InboundFromContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, Saga{TransactionId: id, SagaType: "death_penalty", StepId: "exp"}, s)
+
+	ctx = SagaHeaderParser(context.Background(), []kafka.Header{{Key: "TENANT_ID", Value: []byte(uuid.New().String())}})
+	_, ok = InboundFromContext(ctx)
+	assert.False(t, ok)
+}
@@ -0,0 +1,60 @@
+package account
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	EnvCommandTopic                 = "COMMAND_TOPIC_ACCOUNT"
+	CommandTypeChangeCharacterSlots = "CHANGE_CHARACTER_SLOTS"
+	CommandTypeChangePremiumTime    = "CHANGE_PREMIUM_TIME"
+)
+
+type Command[E any] struct {
+	TransactionId uuid.UUID `json:"transactionId"`
+	AccountId     uint32    `json:"accountId"`
+	Type          string    `json:"type"`
+	Body          E         `json:"body"`
+}
+
+type ChangeCharacterSlotsCommandBody struct {
+	WorldId byte  `json:"worldId"`
+	Amount  int16 `json:"amount"`
+}
+
+type ChangePremiumTimeCommandBody struct {
+	Seconds int64 `json:"seconds"`
+}
+
+const (
+	EnvStatusEventTopic                  = "EVENT_TOPIC_ACCOUNT_STATUS"
+	StatusEventTypeCharacterSlotsChanged = "CHARACTER_SLOTS_CHANGED"
+	StatusEventTypePremiumTimeChanged    = "PREMIUM_TIME_CHANGED"
+	StatusEventTypeError                 = "ERROR"
+
+	StatusEventErrorTypeSlotLimitReached = "SLOT_LIMIT_REACHED"
+	StatusEventErrorTypeNotFound         = "NOT_FOUND"
+)
+
+type StatusEvent[E any] struct {
+	TransactionId uuid.UUID `json:"transactionId"`
+	AccountId     uint32    `json:"accountId"`
+	Type          string    `json:"type"`
+	Body          E         `json:"body"`
+}
+
+type StatusEventCharacterSlotsChangedBody struct {
+	WorldId        byte `json:"worldId"`
+	CharacterSlots byte `json:"characterSlots"`
+}
+
+type StatusEventPremiumTimeChangedBody struct {
+	Seconds   int64     `json:"seconds"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+type StatusEventErrorBody struct {
+	Error string `json:"error"`
+}
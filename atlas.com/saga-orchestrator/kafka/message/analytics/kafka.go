@@ -0,0 +1,22 @@
+package analytics
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	EnvEventTopic = "EVENT_TOPIC_SAGA_ANALYTICS"
+)
+
+// Event is a structured analytics event describing an outcome of a saga (e.g., reward granted, quest completed)
+type Event struct {
+	TransactionId uuid.UUID         `json:"transactionId"`
+	SagaType      string            `json:"sagaType"`
+	InitiatedBy   string            `json:"initiatedBy"`
+	StepId        string            `json:"stepId"`
+	Labels        map[string]string `json:"labels,omitempty"`
+	Name          string            `json:"name"`
+	Properties    map[string]any    `json:"properties,omitempty"`
+	OccurredAt    time.Time         `json:"occurredAt"`
+}
@@ -0,0 +1,63 @@
+package buff
+
+import (
+	"github.com/Chronicle20/atlas-constants/channel"
+	"github.com/Chronicle20/atlas-constants/world"
+	"github.com/google/uuid"
+)
+
+const (
+	EnvCommandTopic      = "COMMAND_TOPIC_CHARACTER_BUFF"
+	CommandTypeApply     = "APPLY"
+	CommandTypeCancelAll = "CANCEL_ALL"
+)
+
+type Command[E any] struct {
+	TransactionId uuid.UUID  `json:"transactionId"`
+	WorldId       world.Id   `json:"worldId"`
+	ChannelId     channel.Id `json:"channelId"`
+	CharacterId   uint32     `json:"characterId"`
+	Type          string     `json:"type"`
+	Body          E          `json:"body"`
+}
+
+type ApplyCommandBody struct {
+	FromId   uint32       `json:"fromId"`
+	SourceId int32        `json:"sourceId"`
+	Duration int32        `json:"duration"`
+	Changes  []StatChange `json:"changes"`
+}
+
+type StatChange struct {
+	Type   string `json:"type"`
+	Amount int32  `json:"amount"`
+}
+
+type CancelAllCommandBody struct {
+}
+
+const (
+	EnvStatusEventTopic         = "EVENT_TOPIC_CHARACTER_BUFF_STATUS"
+	StatusEventTypeApplied      = "APPLIED"
+	StatusEventTypeExpired      = "EXPIRED"
+	StatusEventTypeCancelledAll = "CANCELLED_ALL"
+)
+
+type StatusEvent[E any] struct {
+	TransactionId uuid.UUID `json:"transactionId"`
+	WorldId       world.Id  `json:"worldId"`
+	CharacterId   uint32    `json:"characterId"`
+	Type          string    `json:"type"`
+	Body          E         `json:"body"`
+}
+
+type AppliedStatusEventBody struct {
+	FromId   uint32       `json:"fromId"`
+	SourceId int32        `json:"sourceId"`
+	Duration int32        `json:"duration"`
+	Changes  []StatChange `json:"changes"`
+}
+
+type CancelledAllStatusEventBody struct {
+	SourceIds []int32 `json:"sourceIds"`
+}
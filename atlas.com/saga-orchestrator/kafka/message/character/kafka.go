@@ -11,6 +11,7 @@ import (
 const (
 	EnvCommandTopic            = "COMMAND_TOPIC_CHARACTER"
 	CommandCreateCharacter     = "CREATE_CHARACTER"
+	CommandDeleteCharacter     = "DELETE_CHARACTER"
 	CommandChangeMap           = "CHANGE_MAP"
 	CommandChangeJob           = "CHANGE_JOB"
 	CommandAwardExperience     = "AWARD_EXPERIENCE"
@@ -22,6 +23,19 @@ const (
 	CommandRequestDistributeSp = "REQUEST_DISTRIBUTE_SP"
 	CommandChangeHP            = "CHANGE_HP"
 	CommandChangeMP            = "CHANGE_MP"
+	CommandChangeAccount       = "CHANGE_ACCOUNT"
+	CommandRollbackToSnapshot  = "ROLLBACK_TO_SNAPSHOT"
+	CommandDeductExperience    = "DEDUCT_EXPERIENCE"
+	CommandChangeResource      = "CHANGE_RESOURCE"
+	CommandLockExperience      = "LOCK_EXPERIENCE"
+	CommandUnlockExperience    = "UNLOCK_EXPERIENCE"
+	CommandRestoreStats        = "RESTORE_STATS"
+)
+
+// Alternate resources of class-specific systems, changed by CHANGE_RESOURCE commands
+const (
+	ResourceEnergyCharge = "ENERGY_CHARGE"
+	ResourceComboCounter = "COMBO_COUNTER"
 )
 
 const (
@@ -60,6 +74,55 @@ type ChangeJobCommandBody struct {
 	JobId     job.Id     `json:"jobId"`
 }
 
+type DeleteCharacterCommandBody struct {
+	AccountId uint32 `json:"accountId"`
+}
+
+type ChangeResourceCommandBody struct {
+	ChannelId channel.Id `json:"channelId"`
+	Resource  string     `json:"resource"`
+	Value     int32      `json:"value"`
+}
+
+type LockExperienceCommandBody struct {
+	ChannelId channel.Id `json:"channelId"`
+	Reason    string     `json:"reason,omitempty"`
+}
+
+type UnlockExperienceCommandBody struct {
+	ChannelId channel.Id `json:"channelId"`
+}
+
+// RestoreStatsCommandBody sets a character's progression and stats to those captured by a snapshot
+type RestoreStatsCommandBody struct {
+	ChannelId    channel.Id `json:"channelId"`
+	Level        byte       `json:"level"`
+	Experience   uint32     `json:"experience"`
+	Meso         uint32     `json:"meso"`
+	Strength     uint16     `json:"strength"`
+	Dexterity    uint16     `json:"dexterity"`
+	Intelligence uint16     `json:"intelligence"`
+	Luck         uint16     `json:"luck"`
+	Hp           uint16     `json:"hp"`
+	MaxHp        uint16     `json:"maxHp"`
+	Mp           uint16     `json:"mp"`
+	MaxMp        uint16     `json:"maxMp"`
+	Ap           uint16     `json:"ap"`
+}
+
+type ChangeAccountCommandBody struct {
+	AccountId uint32 `json:"accountId"`
+}
+
+type RollbackToSnapshotCommandBody struct {
+	SnapshotId uint32 `json:"snapshotId"`
+}
+
+type DeductExperienceCommandBody struct {
+	ChannelId channel.Id `json:"channelId"`
+	Amount    uint32     `json:"amount"`
+}
+
 type AwardExperienceCommandBody struct {
 	ChannelId     channel.Id                `json:"channelId"`
 	Distributions []ExperienceDistributions `json:"distributions"`
@@ -138,20 +201,27 @@ type CreateCharacterCommandBody struct {
 }
 
 const (
-	EnvEventTopicCharacterStatus     = "EVENT_TOPIC_CHARACTER_STATUS"
-	StatusEventTypeCreated           = "CREATED"
-	StatusEventTypeLogin             = "LOGIN"
-	StatusEventTypeLogout            = "LOGOUT"
-	StatusEventTypeChannelChanged    = "CHANNEL_CHANGED"
-	StatusEventTypeMapChanged        = "MAP_CHANGED"
-	StatusEventTypeJobChanged        = "JOB_CHANGED"
-	StatusEventTypeExperienceChanged = "EXPERIENCE_CHANGED"
-	StatusEventTypeLevelChanged      = "LEVEL_CHANGED"
-	StatusEventTypeMesoChanged       = "MESO_CHANGED"
-	StatusEventTypeFameChanged       = "FAME_CHANGED"
-	StatusEventTypeStatChanged       = "STAT_CHANGED"
-	StatusEventTypeDeleted           = "DELETED"
-	StatusEventTypeCreationFailed    = "CREATION_FAILED"
+	EnvEventTopicCharacterStatus      = "EVENT_TOPIC_CHARACTER_STATUS"
+	StatusEventTypeCreated            = "CREATED"
+	StatusEventTypeLogin              = "LOGIN"
+	StatusEventTypeLogout             = "LOGOUT"
+	StatusEventTypeChannelChanged     = "CHANNEL_CHANGED"
+	StatusEventTypeMapChanged         = "MAP_CHANGED"
+	StatusEventTypeJobChanged         = "JOB_CHANGED"
+	StatusEventTypeExperienceChanged  = "EXPERIENCE_CHANGED"
+	StatusEventTypeLevelChanged       = "LEVEL_CHANGED"
+	StatusEventTypeMesoChanged        = "MESO_CHANGED"
+	StatusEventTypeFameChanged        = "FAME_CHANGED"
+	StatusEventTypeStatChanged        = "STAT_CHANGED"
+	StatusEventTypeDeleted            = "DELETED"
+	StatusEventTypeCreationFailed     = "CREATION_FAILED"
+	StatusEventTypeAccountChanged     = "ACCOUNT_CHANGED"
+	StatusEventTypeRolledBack         = "ROLLED_BACK"
+	StatusEventTypeExperienceDeducted = "EXPERIENCE_DEDUCTED"
+	StatusEventTypeResourceChanged    = "RESOURCE_CHANGED"
+	StatusEventTypeExperienceLocked   = "EXPERIENCE_LOCKED"
+	StatusEventTypeExperienceUnlocked = "EXPERIENCE_UNLOCKED"
+	StatusEventTypeStatsRestored      = "STATS_RESTORED"
 
 	StatusEventTypeError              = "ERROR"
 	StatusEventErrorTypeNotEnoughMeso = "NOT_ENOUGH_MESO"
@@ -202,12 +272,45 @@ type JobChangedStatusEventBody struct {
 	JobId     job.Id     `json:"jobId"`
 }
 
+type ResourceChangedStatusEventBody struct {
+	ChannelId channel.Id `json:"channelId"`
+	Resource  string     `json:"resource"`
+	Value     int32      `json:"value"`
+}
+
+type ExperienceLockedStatusEventBody struct {
+	ChannelId channel.Id `json:"channelId"`
+}
+
+type ExperienceUnlockedStatusEventBody struct {
+	ChannelId channel.Id `json:"channelId"`
+}
+
+type StatsRestoredStatusEventBody struct {
+	ChannelId channel.Id `json:"channelId"`
+}
+
+type AccountChangedStatusEventBody struct {
+	OldAccountId uint32 `json:"oldAccountId"`
+	AccountId    uint32 `json:"accountId"`
+}
+
+type RolledBackStatusEventBody struct {
+	SnapshotId uint32 `json:"snapshotId"`
+}
+
 type ExperienceChangedStatusEventBody struct {
 	ChannelId     channel.Id                `json:"channelId"`
 	Current       uint32                    `json:"current"`
 	Distributions []ExperienceDistributions `json:"distributions"`
 }
 
+type ExperienceDeductedStatusEventBody struct {
+	ChannelId channel.Id `json:"channelId"`
+	Amount    uint32     `json:"amount"`
+	Current   uint32     `json:"current"`
+}
+
 type LevelChangedStatusEventBody struct {
 	ChannelId channel.Id `json:"channelId"`
 	Amount    byte       `json:"amount"`
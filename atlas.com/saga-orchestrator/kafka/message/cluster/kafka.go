@@ -0,0 +1,14 @@
+package cluster
+
+const (
+	EnvEventTopicMembership  = "EVENT_TOPIC_SAGA_ORCHESTRATOR_MEMBERSHIP"
+	MembershipEventHeartbeat = "HEARTBEAT"
+	MembershipEventLeft      = "LEFT"
+)
+
+// MembershipEvent announces a replica of the orchestrator to the others. Replicas announce themselves periodically
+// while running, and their departure when shutting down.
+type MembershipEvent struct {
+	ReplicaId string `json:"replicaId"`
+	Type      string `json:"type"`
+}
@@ -22,6 +22,10 @@ const (
 	CommandSort               = "SORT"
 	CommandAccept             = "ACCEPT"
 	CommandRelease            = "RELEASE"
+	CommandRestoreSnapshot    = "RESTORE_SNAPSHOT"
+	CommandChangeDurability   = "CHANGE_DURABILITY"
+	CommandChangeExpiration   = "CHANGE_EXPIRATION"
+	CommandSetUpgradeSlots    = "SET_UPGRADE_SLOTS"
 	CommandTypeCreate         = "CREATE"
 	CommandTypeDelete         = "DELETE"
 	CommandTypeEquip          = "EQUIP"
@@ -123,6 +127,25 @@ type RechargeCommandBody struct {
 	Quantity uint32 `json:"quantity"`
 }
 
+type RestoreSnapshotCommandBody struct {
+	SnapshotId uint32 `json:"snapshotId"`
+}
+
+type ChangeDurabilityCommandBody struct {
+	Percent int8 `json:"percent"`
+}
+
+type ChangeExpirationCommandBody struct {
+	Slot       int16     `json:"slot"`
+	Expiration time.Time `json:"expiration"`
+}
+
+type SetUpgradeSlotsCommandBody struct {
+	Slot           int16  `json:"slot"`
+	Slots          uint16 `json:"slots"`
+	HammersApplied uint32 `json:"hammersApplied"`
+}
+
 type MergeCommandBody struct {
 }
 
@@ -151,6 +174,10 @@ const (
 	StatusEventTypeAccepted             = "ACCEPTED"
 	StatusEventTypeReleased             = "RELEASED"
 	StatusEventTypeCreationFailed       = "CREATION_FAILED"
+	StatusEventTypeSnapshotRestored     = "SNAPSHOT_RESTORED"
+	StatusEventTypeDurabilityChanged    = "DURABILITY_CHANGED"
+	StatusEventTypeExpirationChanged    = "EXPIRATION_CHANGED"
+	StatusEventTypeUpgradeSlotsChanged  = "UPGRADE_SLOTS_CHANGED"
 	StatusEventTypeError                = "ERROR"
 
 	AcceptCommandFailed  = "ACCEPT_COMMAND_FAILED"
@@ -217,6 +244,25 @@ type ReleasedEventBody struct {
 	TransactionId uuid.UUID `json:"transactionId"`
 }
 
+type SnapshotRestoredEventBody struct {
+	SnapshotId uint32 `json:"snapshotId"`
+}
+
+type DurabilityChangedEventBody struct {
+	Percent int8 `json:"percent"`
+}
+
+type ExpirationChangedEventBody struct {
+	Slot       int16     `json:"slot"`
+	Expiration time.Time `json:"expiration"`
+}
+
+type UpgradeSlotsChangedEventBody struct {
+	Slot           int16  `json:"slot"`
+	Slots          uint16 `json:"slots"`
+	HammersApplied uint32 `json:"hammersApplied"`
+}
+
 type ErrorEventBody struct {
 	ErrorCode     string    `json:"errorCode"`
 	TransactionId uuid.UUID `json:"transactionId"`
@@ -0,0 +1,57 @@
+package coupon
+
+import (
+	"github.com/google/uuid"
+)
+
+const (
+	EnvCommandTopic    = "COMMAND_TOPIC_COUPON"
+	CommandTypeConsume = "CONSUME"
+	CommandTypeRelease = "RELEASE"
+)
+
+type Command[E any] struct {
+	TransactionId uuid.UUID `json:"transactionId"`
+	AccountId     uint32    `json:"accountId"`
+	CharacterId   uint32    `json:"characterId"`
+	Type          string    `json:"type"`
+	Body          E         `json:"body"`
+}
+
+type ConsumeCommandBody struct {
+	Code string `json:"code"`
+}
+
+type ReleaseCommandBody struct {
+	Code string `json:"code"`
+}
+
+const (
+	EnvStatusEventTopic     = "EVENT_TOPIC_COUPON_STATUS"
+	StatusEventTypeConsumed = "CONSUMED"
+	StatusEventTypeReleased = "RELEASED"
+	StatusEventTypeError    = "ERROR"
+
+	StatusEventErrorTypeAlreadyConsumed = "ALREADY_CONSUMED"
+	StatusEventErrorTypeExpired         = "EXPIRED"
+	StatusEventErrorTypeNotFound        = "NOT_FOUND"
+)
+
+type StatusEvent[E any] struct {
+	TransactionId uuid.UUID `json:"transactionId"`
+	AccountId     uint32    `json:"accountId"`
+	CharacterId   uint32    `json:"characterId"`
+	Code          string    `json:"code"`
+	Type          string    `json:"type"`
+	Body          E         `json:"body"`
+}
+
+type StatusEventConsumedBody struct {
+}
+
+type StatusEventReleasedBody struct {
+}
+
+type StatusEventErrorBody struct {
+	Error string `json:"error"`
+}
@@ -0,0 +1,51 @@
+package faction
+
+import (
+	"github.com/Chronicle20/atlas-constants/world"
+	"github.com/google/uuid"
+)
+
+const (
+	EnvCommandTopic         = "COMMAND_TOPIC_FACTION"
+	CommandTypeChangePoints = "CHANGE_POINTS"
+)
+
+type Command[E any] struct {
+	TransactionId uuid.UUID `json:"transactionId"`
+	WorldId       world.Id  `json:"worldId"`
+	CharacterId   uint32    `json:"characterId"`
+	Type          string    `json:"type"`
+	Body          E         `json:"body"`
+}
+
+type ChangePointsCommandBody struct {
+	FactionId uint32 `json:"factionId"`
+	Amount    int32  `json:"amount"`
+}
+
+const (
+	EnvStatusEventTopic          = "EVENT_TOPIC_FACTION_STATUS"
+	StatusEventTypePointsChanged = "POINTS_CHANGED"
+	StatusEventTypeError         = "ERROR"
+
+	StatusEventErrorTypeNotFound        = "NOT_FOUND"
+	StatusEventErrorTypeOpposingFaction = "OPPOSING_FACTION"
+)
+
+type StatusEvent[E any] struct {
+	TransactionId uuid.UUID `json:"transactionId"`
+	WorldId       world.Id  `json:"worldId"`
+	CharacterId   uint32    `json:"characterId"`
+	Type          string    `json:"type"`
+	Body          E         `json:"body"`
+}
+
+type StatusEventPointsChangedBody struct {
+	FactionId uint32 `json:"factionId"`
+	Amount    int32  `json:"amount"`
+	Points    int32  `json:"points"`
+}
+
+type StatusEventErrorBody struct {
+	Error string `json:"error"`
+}
@@ -0,0 +1,61 @@
+package instance
+
+import (
+	"time"
+
+	"github.com/Chronicle20/atlas-constants/world"
+	"github.com/google/uuid"
+)
+
+const (
+	EnvCommandTopic          = "COMMAND_TOPIC_INSTANCE"
+	CommandTypeResetCooldown = "RESET_COOLDOWN"
+	CommandTypeSetCooldown   = "SET_COOLDOWN"
+)
+
+type Command[E any] struct {
+	TransactionId uuid.UUID `json:"transactionId"`
+	WorldId       world.Id  `json:"worldId"`
+	CharacterId   uint32    `json:"characterId"`
+	Type          string    `json:"type"`
+	Body          E         `json:"body"`
+}
+
+type ResetCooldownCommandBody struct {
+	InstanceId uint32 `json:"instanceId"`
+}
+
+type SetCooldownCommandBody struct {
+	InstanceId uint32    `json:"instanceId"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+}
+
+const (
+	EnvStatusEventTopic          = "EVENT_TOPIC_INSTANCE_STATUS"
+	StatusEventTypeCooldownReset = "COOLDOWN_RESET"
+	StatusEventTypeCooldownSet   = "COOLDOWN_SET"
+	StatusEventTypeError         = "ERROR"
+
+	StatusEventErrorTypeNotFound = "NOT_FOUND"
+)
+
+type StatusEvent[E any] struct {
+	TransactionId uuid.UUID `json:"transactionId"`
+	WorldId       world.Id  `json:"worldId"`
+	CharacterId   uint32    `json:"characterId"`
+	Type          string    `json:"type"`
+	Body          E         `json:"body"`
+}
+
+type StatusEventCooldownResetBody struct {
+	InstanceId uint32 `json:"instanceId"`
+}
+
+type StatusEventCooldownSetBody struct {
+	InstanceId uint32    `json:"instanceId"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+}
+
+type StatusEventErrorBody struct {
+	Error string `json:"error"`
+}
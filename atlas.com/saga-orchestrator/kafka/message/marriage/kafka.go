@@ -0,0 +1,59 @@
+package marriage
+
+import (
+	"github.com/google/uuid"
+)
+
+const (
+	EnvCommandTopic    = "COMMAND_TOPIC_MARRIAGE"
+	CommandTypeDivorce = "DIVORCE"
+	CommandTypeRestore = "RESTORE"
+)
+
+type Command[E any] struct {
+	TransactionId uuid.UUID `json:"transactionId"`
+	CharacterId   uint32    `json:"characterId"`
+	Type          string    `json:"type"`
+	Body          E         `json:"body"`
+}
+
+type DivorceCommandBody struct {
+	MarriageId uint32 `json:"marriageId"`
+	PartnerId  uint32 `json:"partnerId"`
+}
+
+type RestoreCommandBody struct {
+	MarriageId uint32 `json:"marriageId"`
+	PartnerId  uint32 `json:"partnerId"`
+}
+
+const (
+	EnvStatusEventTopic     = "EVENT_TOPIC_MARRIAGE_STATUS"
+	StatusEventTypeDivorced = "DIVORCED"
+	StatusEventTypeRestored = "RESTORED"
+	StatusEventTypeError    = "ERROR"
+
+	StatusEventErrorTypeNotMarried = "NOT_MARRIED"
+	StatusEventErrorTypeNotFound   = "NOT_FOUND"
+)
+
+type StatusEvent[E any] struct {
+	TransactionId uuid.UUID `json:"transactionId"`
+	CharacterId   uint32    `json:"characterId"`
+	Type          string    `json:"type"`
+	Body          E         `json:"body"`
+}
+
+type StatusEventDivorcedBody struct {
+	MarriageId uint32 `json:"marriageId"`
+	PartnerId  uint32 `json:"partnerId"`
+}
+
+type StatusEventRestoredBody struct {
+	MarriageId uint32 `json:"marriageId"`
+	PartnerId  uint32 `json:"partnerId"`
+}
+
+type StatusEventErrorBody struct {
+	Error string `json:"error"`
+}
@@ -2,12 +2,17 @@ package message
 
 import (
 	"atlas-saga-orchestrator/kafka/producer"
+	"errors"
+	"fmt"
 	"sync"
 
 	"github.com/Chronicle20/atlas-model/model"
 	"github.com/segmentio/kafka-go"
 )
 
+// ErrProduce is returned when buffered messages cannot be produced to Kafka, as opposed to when they cannot be built
+var ErrProduce = errors.New("unable to produce messages")
+
 type Buffer struct {
 	mu     sync.Mutex
 	buffer map[string][]kafka.Message
@@ -51,7 +56,7 @@ func Emit(p producer.Provider) func(f func(buf *Buffer) error) error {
 		for t, ms := range b.GetAll() {
 			err = p(t)(model.FixedProvider(ms))
 			if err != nil {
-				return err
+				return fmt.Errorf("%w to [%s]: %w", ErrProduce, t, err)
 			}
 		}
 		return nil
@@ -68,7 +73,7 @@ func EmitWithResult[M any, B any](p producer.Provider) func(func(*Buffer) func(B
 			}
 			for t, ms := range buf.GetAll() {
 				if err = p(t)(model.FixedProvider(ms)); err != nil {
-					return result, err
+					return result, fmt.Errorf("%w to [%s]: %w", ErrProduce, t, err)
 				}
 			}
 			return result, nil
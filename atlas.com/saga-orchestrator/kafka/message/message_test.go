@@ -0,0 +1,35 @@
+package message
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Chronicle20/atlas-kafka/producer"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmitProduceError(t *testing.T) {
+	unavailable := errors.New("leader not available")
+	p := func(token string) producer.MessageProducer {
+		return func(provider model.Provider[[]kafka.Message]) error {
+			return unavailable
+		}
+	}
+	put := func(buf *Buffer) error {
+		return buf.Put("COMMAND_TOPIC_CHARACTER", model.FixedProvider([]kafka.Message{{Value: []byte("{}")}}))
+	}
+
+	// Messages which cannot be produced are distinguished from those which cannot be built
+	err := Emit(p)(put)
+	assert.ErrorIs(t, err, ErrProduce)
+	assert.ErrorIs(t, err, unavailable)
+
+	invalid := errors.New("invalid body")
+	err = Emit(p)(func(buf *Buffer) error {
+		return buf.Put("COMMAND_TOPIC_CHARACTER", func() ([]kafka.Message, error) { return nil, invalid })
+	})
+	assert.ErrorIs(t, err, invalid)
+	assert.NotErrorIs(t, err, ErrProduce)
+}
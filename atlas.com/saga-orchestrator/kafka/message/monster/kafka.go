@@ -0,0 +1,33 @@
+package monster
+
+import (
+	"github.com/google/uuid"
+)
+
+const (
+	EnvEventTopicMonsterStatus = "EVENT_TOPIC_MONSTER_STATUS"
+	EventMonsterStatusKilled   = "KILLED"
+)
+
+type StatusEvent[E any] struct {
+	WorldId   byte      `json:"worldId"`
+	ChannelId byte      `json:"channelId"`
+	MapId     uint32    `json:"mapId"`
+	Instance  uuid.UUID `json:"instance"`
+	UniqueId  uint32    `json:"uniqueId"`
+	MonsterId uint32    `json:"monsterId"`
+	Type      string    `json:"type"`
+	Body      E         `json:"body"`
+}
+
+type StatusEventKilledBody struct {
+	X             int16         `json:"x"`
+	Y             int16         `json:"y"`
+	ActorId       uint32        `json:"actorId"`
+	DamageEntries []DamageEntry `json:"damageEntries"`
+}
+
+type DamageEntry struct {
+	CharacterId uint32 `json:"characterId"`
+	Damage      uint32 `json:"damage"`
+}
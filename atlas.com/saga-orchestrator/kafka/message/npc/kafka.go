@@ -0,0 +1,94 @@
+package npc
+
+import (
+	"github.com/Chronicle20/atlas-constants/channel"
+	_map "github.com/Chronicle20/atlas-constants/map"
+	"github.com/Chronicle20/atlas-constants/world"
+	"github.com/google/uuid"
+)
+
+const (
+	EnvCommandTopic          = "COMMAND_TOPIC_NPC"
+	CommandTypeSpawnEscort   = "SPAWN_ESCORT"
+	CommandTypeDespawnEscort = "DESPAWN_ESCORT"
+)
+
+type Command[E any] struct {
+	TransactionId uuid.UUID  `json:"transactionId"`
+	WorldId       world.Id   `json:"worldId"`
+	ChannelId     channel.Id `json:"channelId"`
+	MapId         _map.Id    `json:"mapId"`
+	Instance      uuid.UUID  `json:"instance"`
+	Type          string     `json:"type"`
+	Body          E          `json:"body"`
+}
+
+type SpawnEscortCommandBody struct {
+	NpcId            uint32  `json:"npcId"`
+	CharacterId      uint32  `json:"characterId"`
+	DestinationMapId _map.Id `json:"destinationMapId"`
+}
+
+type DespawnEscortCommandBody struct {
+	NpcId       uint32 `json:"npcId"`
+	CharacterId uint32 `json:"characterId"`
+}
+
+const (
+	EnvEventStatusTopic          = "EVENT_TOPIC_NPC_STATUS"
+	StatusEventTypeEscortSpawned = "ESCORT_SPAWNED"
+	StatusEventTypeEscortArrived = "ESCORT_ARRIVED"
+	StatusEventTypeEscortFailed  = "ESCORT_FAILED"
+	StatusEventTypeError         = "ERROR"
+
+	StatusEventErrorTypeNotFound = "NOT_FOUND"
+)
+
+const (
+	EnvConversationCommandTopic   = "COMMAND_TOPIC_NPC_CONVERSATION"
+	ConversationCommandTypeResume = "RESUME"
+)
+
+// ConversationCommand is a command to the NPC conversation service, concerning the character's conversation with the NPC
+type ConversationCommand[E any] struct {
+	TransactionId uuid.UUID  `json:"transactionId"`
+	WorldId       world.Id   `json:"worldId"`
+	ChannelId     channel.Id `json:"channelId"`
+	CharacterId   uint32     `json:"characterId"`
+	NpcId         uint32     `json:"npcId"`
+	Type          string     `json:"type"`
+	Body          E          `json:"body"`
+}
+
+type ResumeConversationCommandBody struct {
+	State   string         `json:"state"`
+	Outcome map[string]any `json:"outcome,omitempty"`
+}
+
+type StatusEvent[E any] struct {
+	TransactionId uuid.UUID  `json:"transactionId"`
+	WorldId       world.Id   `json:"worldId"`
+	ChannelId     channel.Id `json:"channelId"`
+	MapId         _map.Id    `json:"mapId"`
+	Instance      uuid.UUID  `json:"instance"`
+	NpcId         uint32     `json:"npcId"`
+	CharacterId   uint32     `json:"characterId"`
+	Type          string     `json:"type"`
+	Body          E          `json:"body"`
+}
+
+type StatusEventEscortSpawnedBody struct {
+	ObjectId uint32 `json:"objectId"`
+}
+
+type StatusEventEscortArrivedBody struct {
+	MapId _map.Id `json:"mapId"`
+}
+
+type StatusEventEscortFailedBody struct {
+	Reason string `json:"reason"`
+}
+
+type StatusEventErrorBody struct {
+	Error string `json:"error"`
+}
@@ -0,0 +1,70 @@
+package reactor
+
+import (
+	"github.com/Chronicle20/atlas-constants/channel"
+	_map "github.com/Chronicle20/atlas-constants/map"
+	"github.com/Chronicle20/atlas-constants/world"
+	"github.com/google/uuid"
+)
+
+const (
+	EnvCommandTopic        = "COMMAND_TOPIC_REACTOR"
+	CommandTypeHit         = "HIT"
+	CommandTypeChangeState = "CHANGE_STATE"
+)
+
+type Command[E any] struct {
+	TransactionId uuid.UUID  `json:"transactionId"`
+	WorldId       world.Id   `json:"worldId"`
+	ChannelId     channel.Id `json:"channelId"`
+	MapId         _map.Id    `json:"mapId"`
+	Instance      uuid.UUID  `json:"instance"`
+	Type          string     `json:"type"`
+	Body          E          `json:"body"`
+}
+
+type HitCommandBody struct {
+	ReactorId   uint32 `json:"reactorId"`
+	CharacterId uint32 `json:"characterId"`
+	Stance      uint16 `json:"stance"`
+	SkillId     uint32 `json:"skillId"`
+}
+
+type ChangeStateCommandBody struct {
+	ReactorId uint32 `json:"reactorId"`
+	State     int8   `json:"state"`
+}
+
+const (
+	EnvEventStatusTopic         = "EVENT_TOPIC_REACTOR_STATUS"
+	StatusEventTypeHit          = "HIT"
+	StatusEventTypeStateChanged = "STATE_CHANGED"
+	StatusEventTypeError        = "ERROR"
+
+	StatusEventErrorTypeNotFound = "NOT_FOUND"
+)
+
+type StatusEvent[E any] struct {
+	TransactionId uuid.UUID  `json:"transactionId"`
+	WorldId       world.Id   `json:"worldId"`
+	ChannelId     channel.Id `json:"channelId"`
+	MapId         _map.Id    `json:"mapId"`
+	Instance      uuid.UUID  `json:"instance"`
+	ReactorId     uint32     `json:"reactorId"`
+	Type          string     `json:"type"`
+	Body          E          `json:"body"`
+}
+
+type StatusEventHitBody struct {
+	State     int8 `json:"state"`
+	Destroyed bool `json:"destroyed"`
+}
+
+type StatusEventStateChangedBody struct {
+	State    int8 `json:"state"`
+	Previous int8 `json:"previous"`
+}
+
+type StatusEventErrorBody struct {
+	Error string `json:"error"`
+}
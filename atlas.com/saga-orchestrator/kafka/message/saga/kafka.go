@@ -1,6 +1,8 @@
 package saga
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
@@ -9,8 +11,23 @@ const (
 )
 
 const (
-	EnvStatusEventTopic      = "EVENT_TOPIC_SAGA_STATUS"
-	StatusEventTypeCompleted = "COMPLETED"
+	EnvCommandTopicStepDue = "COMMAND_TOPIC_SAGA_STEP_DUE"
+	StepDueTypeTimedOut    = "TIMED_OUT"
+	StepDueTypeRedelivery  = "REDELIVERY"
+)
+
+// StepDueCommand has the replica owning a saga act on its current step, which the replica leading the role finding
+// such steps found due, whether timed out or parked for a redelivery now due
+type StepDueCommand struct {
+	TransactionId uuid.UUID `json:"transactionId"`
+	StepId        string    `json:"stepId"`
+	Type          string    `json:"type"`
+}
+
+const (
+	EnvStatusEventTopic        = "EVENT_TOPIC_SAGA_STATUS"
+	StatusEventTypeCompleted   = "COMPLETED"
+	StatusEventTypeCompensated = "COMPENSATED"
 )
 
 type StatusEvent[E any] struct {
@@ -20,4 +37,78 @@ type StatusEvent[E any] struct {
 }
 
 type StatusEventCompletedBody struct {
+	ChildTransactionId *uuid.UUID `json:"childTransactionId,omitempty"`
+}
+
+// StatusEventCompensatedBody is the receipt of a saga's compensation, enumerating what was rolled back and what could
+// not be
+type StatusEventCompensatedBody struct {
+	FailedStepId string                   `json:"failedStepId"`
+	ErrorCode    string                   `json:"errorCode,omitempty"`
+	RolledBack   bool                     `json:"rolledBack"`
+	Steps        []CompensatedStepOutcome `json:"steps"`
+	IssuedAt     time.Time                `json:"issuedAt"`
+}
+
+type CompensatedStepOutcome struct {
+	StepId  string `json:"stepId"`
+	Action  string `json:"action"`
+	Outcome string `json:"outcome"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+const (
+	EnvDeadLetterTopic = "EVENT_TOPIC_SAGA_DEAD_LETTER"
+)
+
+const (
+	EnvProjectionEventTopic          = "EVENT_TOPIC_SAGA_PROJECTION"
+	ProjectionEventTypeStarted       = "STARTED"
+	ProjectionEventTypeStepCompleted = "STEP_COMPLETED"
+	ProjectionEventTypeStepFailed    = "STEP_FAILED"
+	ProjectionEventTypeCompleted     = "COMPLETED"
+	ProjectionEventTypeCompensated   = "COMPENSATED"
+)
+
+// Statuses of a saga as projected
+const (
+	ProjectionStatusActive      = "ACTIVE"
+	ProjectionStatusCompleted   = "COMPLETED"
+	ProjectionStatusCompensated = "COMPENSATED"
+)
+
+// ProjectionEvent is the state of a saga as of a change to it, flattened for read models such as the query service. Each
+// event carries the whole state of the saga, so a read model may replace what it holds for the transaction with the
+// latest event, rather than applying changes.
+type ProjectionEvent struct {
+	TransactionId uuid.UUID         `json:"transactionId"`
+	TenantId      uuid.UUID         `json:"tenantId"`
+	Type          string            `json:"type"`
+	SagaType      string            `json:"sagaType"`
+	InitiatedBy   string            `json:"initiatedBy"`
+	Labels        map[string]string `json:"labels,omitempty"`
+	Status        string            `json:"status"`
+	CharacterIds  []uint32          `json:"characterIds"`
+	StepId        string            `json:"stepId,omitempty"`
+	ErrorCode     string            `json:"errorCode,omitempty"`
+	RolledBack    *bool             `json:"rolledBack,omitempty"`
+	Steps         []ProjectedStep   `json:"steps"`
+	OccurredAt    time.Time         `json:"occurredAt"`
+}
+
+type ProjectedStep struct {
+	StepId      string    `json:"stepId"`
+	Action      string    `json:"action"`
+	Status      string    `json:"status"`
+	CharacterId uint32    `json:"characterId,omitempty"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// DeadLetter is a status event which was not processed, as it was received for a tenant other than that of its saga
+type DeadLetter struct {
+	TransactionId uuid.UUID `json:"transactionId"`
+	TenantId      uuid.UUID `json:"tenantId"`
+	Reason        string    `json:"reason"`
+	Event         any       `json:"event,omitempty"`
+	ErrorCode     string    `json:"errorCode,omitempty"`
 }
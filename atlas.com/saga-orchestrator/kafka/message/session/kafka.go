@@ -0,0 +1,63 @@
+package session
+
+import (
+	"time"
+
+	"github.com/Chronicle20/atlas-constants/world"
+	"github.com/google/uuid"
+)
+
+const (
+	EnvCommandTopic                   = "COMMAND_TOPIC_SESSION"
+	CommandTypeRegisterDeferredEffect = "REGISTER_DEFERRED_EFFECT"
+	CommandTypeCancelDeferredEffect   = "CANCEL_DEFERRED_EFFECT"
+
+	DeferredEffectTitleBuff = "TITLE_BUFF"
+)
+
+type Command[E any] struct {
+	TransactionId uuid.UUID `json:"transactionId"`
+	WorldId       world.Id  `json:"worldId"`
+	CharacterId   uint32    `json:"characterId"`
+	Type          string    `json:"type"`
+	Body          E         `json:"body"`
+}
+
+type RegisterDeferredEffectCommandBody struct {
+	EffectId  uuid.UUID  `json:"effectId"`
+	Effect    string     `json:"effect"`
+	TitleId   uint32     `json:"titleId"`
+	Duration  uint32     `json:"duration"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+type CancelDeferredEffectCommandBody struct {
+	EffectId uuid.UUID `json:"effectId"`
+}
+
+const (
+	EnvStatusEventTopic                  = "EVENT_TOPIC_SESSION_STATUS"
+	StatusEventTypeDeferredEffectApplied = "DEFERRED_EFFECT_APPLIED"
+	StatusEventTypeError                 = "ERROR"
+
+	StatusEventErrorTypeNotFound = "NOT_FOUND"
+	StatusEventErrorTypeExpired  = "EXPIRED"
+)
+
+type StatusEvent[E any] struct {
+	TransactionId uuid.UUID `json:"transactionId"`
+	WorldId       world.Id  `json:"worldId"`
+	CharacterId   uint32    `json:"characterId"`
+	Type          string    `json:"type"`
+	Body          E         `json:"body"`
+}
+
+type StatusEventDeferredEffectAppliedBody struct {
+	EffectId  uuid.UUID `json:"effectId"`
+	AppliedAt time.Time `json:"appliedAt"`
+}
+
+type StatusEventErrorBody struct {
+	EffectId uuid.UUID `json:"effectId"`
+	Error    string    `json:"error"`
+}
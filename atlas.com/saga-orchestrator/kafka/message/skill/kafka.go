@@ -6,9 +6,11 @@ import (
 )
 
 const (
-	EnvCommandTopic          = "COMMAND_TOPIC_SKILL"
-	CommandTypeRequestCreate = "REQUEST_CREATE"
-	CommandTypeRequestUpdate = "REQUEST_UPDATE"
+	EnvCommandTopic           = "COMMAND_TOPIC_SKILL"
+	CommandTypeRequestCreate  = "REQUEST_CREATE"
+	CommandTypeRequestUpdate  = "REQUEST_UPDATE"
+	CommandTypeResetCooldowns = "RESET_COOLDOWNS"
+	CommandTypeRequestDelete  = "REQUEST_DELETE"
 )
 
 type Command[E any] struct {
@@ -32,10 +34,19 @@ type RequestUpdateBody struct {
 	Expiration  time.Time `json:"expiration"`
 }
 
+type RequestDeleteBody struct {
+	SkillId uint32 `json:"skillId"`
+}
+
+type ResetCooldownsBody struct {
+	SkillIds []uint32 `json:"skillIds"`
+}
+
 const (
-	EnvStatusEventTopic    = "EVENT_TOPIC_SKILL_STATUS"
-	StatusEventTypeCreated = "CREATED"
-	StatusEventTypeUpdated = "UPDATED"
+	EnvStatusEventTopic           = "EVENT_TOPIC_SKILL_STATUS"
+	StatusEventTypeCreated        = "CREATED"
+	StatusEventTypeUpdated        = "UPDATED"
+	StatusEventTypeCooldownsReset = "COOLDOWNS_RESET"
 )
 
 type StatusEvent[E any] struct {
@@ -57,3 +68,7 @@ type StatusEventUpdatedBody struct {
 	MasterLevel byte      `json:"masterLevel"`
 	Expiration  time.Time `json:"expiration"`
 }
+
+type StatusEventCooldownsResetBody struct {
+	SkillIds []uint32 `json:"skillIds"`
+}
@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"github.com/google/uuid"
+)
+
+const (
+	EnvCommandTopic           = "COMMAND_TOPIC_STORAGE"
+	CommandTypeChangeCapacity = "CHANGE_CAPACITY"
+)
+
+type Command[E any] struct {
+	TransactionId uuid.UUID `json:"transactionId"`
+	WorldId       byte      `json:"worldId"`
+	AccountId     uint32    `json:"accountId"`
+	Type          string    `json:"type"`
+	Body          E         `json:"body"`
+}
+
+type ChangeCapacityCommandBody struct {
+	Amount int16 `json:"amount"`
+}
+
+const (
+	EnvStatusEventTopic            = "EVENT_TOPIC_STORAGE_STATUS"
+	StatusEventTypeCapacityChanged = "CAPACITY_CHANGED"
+	StatusEventTypeError           = "ERROR"
+
+	StatusEventErrorTypeCapacityLimitReached = "CAPACITY_LIMIT_REACHED"
+	StatusEventErrorTypeNotFound             = "NOT_FOUND"
+)
+
+type StatusEvent[E any] struct {
+	TransactionId uuid.UUID `json:"transactionId"`
+	WorldId       byte      `json:"worldId"`
+	AccountId     uint32    `json:"accountId"`
+	Type          string    `json:"type"`
+	Body          E         `json:"body"`
+}
+
+type StatusEventCapacityChangedBody struct {
+	Capacity uint32 `json:"capacity"`
+}
+
+type StatusEventErrorBody struct {
+	Error string `json:"error"`
+}
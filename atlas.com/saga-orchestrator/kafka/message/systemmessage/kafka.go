@@ -0,0 +1,36 @@
+package systemmessage
+
+import (
+	"github.com/Chronicle20/atlas-constants/channel"
+	"github.com/Chronicle20/atlas-constants/world"
+	"github.com/google/uuid"
+)
+
+const (
+	EnvCommandTopic        = "COMMAND_TOPIC_SYSTEM_MESSAGE"
+	CommandTypeSendMessage = "SEND_MESSAGE"
+)
+
+// Constants for the types of message shown to a character
+const (
+	MessageTypeNotice        = "NOTICE"
+	MessageTypePopUp         = "POP_UP"
+	MessageTypePinkText      = "PINK_TEXT"
+	MessageTypeBlueText      = "BLUE_TEXT"
+	MessageTypeLightBlueText = "LIGHT_BLUE_TEXT"
+)
+
+// Command is a command to the channel service, concerning the messages shown to the character
+type Command[E any] struct {
+	TransactionId uuid.UUID  `json:"transactionId"`
+	WorldId       world.Id   `json:"worldId"`
+	ChannelId     channel.Id `json:"channelId"`
+	CharacterId   uint32     `json:"characterId"`
+	Type          string     `json:"type"`
+	Body          E          `json:"body"`
+}
+
+type SendMessageBody struct {
+	MessageType string `json:"messageType"`
+	Message     string `json:"message"`
+}
@@ -0,0 +1,85 @@
+package worldstate
+
+import (
+	"github.com/Chronicle20/atlas-constants/world"
+	"github.com/google/uuid"
+)
+
+const (
+	EnvCommandTopic            = "COMMAND_TOPIC_WORLD_STATE"
+	CommandTypeAdjustShopStock = "ADJUST_SHOP_STOCK"
+	CommandTypeSetEventFlag    = "SET_EVENT_FLAG"
+	CommandTypeApplyEventBuff  = "APPLY_EVENT_BUFF"
+	CommandTypeRemoveEventBuff = "REMOVE_EVENT_BUFF"
+)
+
+type Command[E any] struct {
+	TransactionId uuid.UUID `json:"transactionId"`
+	WorldId       world.Id  `json:"worldId"`
+	Type          string    `json:"type"`
+	Body          E         `json:"body"`
+}
+
+type AdjustShopStockBody struct {
+	NpcId      uint32 `json:"npcId"`
+	TemplateId uint32 `json:"templateId"`
+	Amount     int32  `json:"amount"`
+}
+
+type SetEventFlagBody struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type ApplyEventBuffBody struct {
+	BuffId string  `json:"buffId"`
+	Type   string  `json:"type"`
+	Rate   float64 `json:"rate"`
+}
+
+type RemoveEventBuffBody struct {
+	BuffId string `json:"buffId"`
+}
+
+const (
+	EnvStatusEventTopic              = "EVENT_TOPIC_WORLD_STATE_STATUS"
+	StatusEventTypeShopStockAdjusted = "SHOP_STOCK_ADJUSTED"
+	StatusEventTypeEventFlagSet      = "EVENT_FLAG_SET"
+	StatusEventTypeEventBuffApplied  = "EVENT_BUFF_APPLIED"
+	StatusEventTypeEventBuffRemoved  = "EVENT_BUFF_REMOVED"
+	StatusEventTypeError             = "ERROR"
+
+	StatusEventErrorTypeOutOfStock = "OUT_OF_STOCK"
+)
+
+type StatusEvent[E any] struct {
+	TransactionId uuid.UUID `json:"transactionId"`
+	WorldId       world.Id  `json:"worldId"`
+	Type          string    `json:"type"`
+	Body          E         `json:"body"`
+}
+
+type StatusEventShopStockAdjustedBody struct {
+	NpcId      uint32 `json:"npcId"`
+	TemplateId uint32 `json:"templateId"`
+	Stock      uint32 `json:"stock"`
+}
+
+type StatusEventEventFlagSetBody struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type StatusEventEventBuffAppliedBody struct {
+	BuffId string  `json:"buffId"`
+	Type   string  `json:"type"`
+	Rate   float64 `json:"rate"`
+}
+
+type StatusEventEventBuffRemovedBody struct {
+	BuffId string `json:"buffId"`
+}
+
+type StatusEventErrorBody struct {
+	Error string `json:"error"`
+}
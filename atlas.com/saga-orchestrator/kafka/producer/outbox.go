@@ -0,0 +1,132 @@
+package producer
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/segmentio/kafka-go"
+	"github.com/sirupsen/logrus"
+)
+
+// Entry is a message collected by a batch, to be produced to the topic identified by the token
+type Entry struct {
+	Token   string
+	Message kafka.Message
+}
+
+// Batch collects the messages produced while it is open, so they can be written all-or-nothing once the work producing
+// them succeeds, rather than as each is produced
+type Batch struct {
+	mutex   sync.Mutex
+	entries []Entry
+}
+
+// Entries returns the messages collected by the batch, in the order they were produced
+func (b *Batch) Entries() []Entry {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return append([]Entry{}, b.entries...)
+}
+
+func (b *Batch) collect(token string, provider model.Provider[[]kafka.Message]) error {
+	ms, err := provider()
+	if err != nil {
+		return err
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	for _, m := range ms {
+		b.entries = append(b.entries, Entry{Token: token, Message: m})
+	}
+	return nil
+}
+
+// outbox holds the batches open on a context. Processors capture their context when created, so batches cannot be
+// threaded through it.
+type outbox struct {
+	mutex   sync.Mutex
+	batches []*Batch
+}
+
+type outboxKey struct{}
+
+type providerKey struct{}
+
+// WithOutbox returns a context able to collect produced messages into batches. A context already able to is returned
+// unchanged, so nested processors share its batches.
+func WithOutbox(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(outboxKey{}).(*outbox); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, outboxKey{}, &outbox{})
+}
+
+// Begin opens a batch on the context's outbox, collecting the messages produced from it until the returned function
+// closes it. Batches may be nested, the innermost collecting. Contexts without an outbox produce as they did.
+func Begin(ctx context.Context) (*Batch, func()) {
+	b := &Batch{}
+	o, ok := ctx.Value(outboxKey{}).(*outbox)
+	if !ok {
+		return b, func() {}
+	}
+	o.mutex.Lock()
+	o.batches = append(o.batches, b)
+	o.mutex.Unlock()
+	return b, func() {
+		o.mutex.Lock()
+		defer o.mutex.Unlock()
+		for i := len(o.batches) - 1; i >= 0; i-- {
+			if o.batches[i] == b {
+				o.batches = append(o.batches[:i], o.batches[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// collecting returns the innermost batch open on the context, if any
+func collecting(ctx context.Context) (*Batch, bool) {
+	o, ok := ctx.Value(outboxKey{}).(*outbox)
+	if !ok {
+		return nil, false
+	}
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	if len(o.batches) == 0 {
+		return nil, false
+	}
+	return o.batches[len(o.batches)-1], true
+}
+
+// WithProvider returns a context whose messages are produced by the provider rather than written to Kafka, as tests
+// observing or failing production do
+func WithProvider(ctx context.Context, p Provider) context.Context {
+	return context.WithValue(ctx, providerKey{}, p)
+}
+
+// Write produces the entries in order, bypassing any batch open on the context. Consecutive entries for the same topic
+// are produced together. Returns the number of entries written before any failure, which remain to be written.
+func Write(l logrus.FieldLogger) func(ctx context.Context) func(entries []Entry) (int, error) {
+	return func(ctx context.Context) func(entries []Entry) (int, error) {
+		pp := writer(l)(ctx)
+		return func(entries []Entry) (int, error) {
+			written := 0
+			for written < len(entries) {
+				end := written + 1
+				for end < len(entries) && entries[end].Token == entries[written].Token {
+					end++
+				}
+				ms := make([]kafka.Message, 0, end-written)
+				for _, e := range entries[written:end] {
+					ms = append(ms, e.Message)
+				}
+				if err := pp(entries[written].Token)(model.FixedProvider(ms)); err != nil {
+					return written, err
+				}
+				written = end
+			}
+			return written, nil
+		}
+	}
+}
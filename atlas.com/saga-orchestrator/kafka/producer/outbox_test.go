@@ -0,0 +1,77 @@
+package producer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Chronicle20/atlas-kafka/producer"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/segmentio/kafka-go"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOutbox tests that messages produced while a batch is open are collected rather than written, and written in order
+// once the batch is
+func TestOutbox(t *testing.T) {
+	l, _ := test.NewNullLogger()
+	written := make([]string, 0)
+	fail := ""
+	ctx := WithProvider(WithOutbox(context.Background()), func(token string) producer.MessageProducer {
+		return func(provider model.Provider[[]kafka.Message]) error {
+			if token == fail {
+				return errors.New("broker unavailable")
+			}
+			ms, err := provider()
+			if err != nil {
+				return err
+			}
+			for _, m := range ms {
+				written = append(written, token+":"+string(m.Value))
+			}
+			return nil
+		}
+	})
+	assert.Equal(t, ctx, WithOutbox(ctx))
+	produce := func(token string, value string) error {
+		return ProviderImpl(l)(ctx)(token)(model.FixedProvider([]kafka.Message{{Value: []byte(value)}}))
+	}
+
+	// Without a batch, messages are written as they are produced
+	require.NoError(t, produce("A", "1"))
+	assert.Equal(t, []string{"A:1"}, written)
+	written = written[:0]
+
+	outer, endOuter := Begin(ctx)
+	require.NoError(t, produce("A", "1"))
+	inner, endInner := Begin(ctx)
+	require.NoError(t, produce("B", "2"))
+	endInner()
+	require.NoError(t, produce("A", "3"))
+	require.NoError(t, produce("B", "4"))
+	endOuter()
+	assert.Empty(t, written)
+	require.Len(t, inner.Entries(), 1)
+
+	entries := outer.Entries()
+	require.Len(t, entries, 3)
+	n, err := Write(l)(ctx)(entries)
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, []string{"A:1", "A:3", "B:4"}, written)
+
+	// Writing stops at the first failure, reporting the entries written before it
+	written = written[:0]
+	fail = "B"
+	n, err = Write(l)(ctx)(entries)
+	assert.Error(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []string{"A:1", "A:3"}, written)
+
+	// Contexts without an outbox produce as they did
+	b, end := Begin(context.Background())
+	end()
+	assert.Empty(t, b.Entries())
+}
@@ -1,20 +1,71 @@
 package producer
 
 import (
+	"atlas-saga-orchestrator/kafka/header"
 	"context"
 	"github.com/Chronicle20/atlas-kafka/producer"
 	"github.com/Chronicle20/atlas-kafka/topic"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/segmentio/kafka-go"
 	"github.com/sirupsen/logrus"
+	"sync/atomic"
 )
 
 type Provider func(token string) producer.MessageProducer
 
+// ProviderImpl produces messages to the topic identified by the token. Messages produced while a batch is open on the
+// context are collected by it, rather than written.
 func ProviderImpl(l logrus.FieldLogger) func(ctx context.Context) func(token string) producer.MessageProducer {
 	return func(ctx context.Context) func(token string) producer.MessageProducer {
+		pp := writer(l)(ctx)
+		return func(token string) producer.MessageProducer {
+			mp := pp(token)
+			return func(provider model.Provider[[]kafka.Message]) error {
+				if b, ok := collecting(ctx); ok {
+					return b.collect(token, provider)
+				}
+				return mp(provider)
+			}
+		}
+	}
+}
+
+// discarding is whether messages are discarded rather than written
+var discarding atomic.Bool
+
+// Discard has the messages produced from then on discarded rather than written to Kafka, so a replica running in shadow
+// mode processes events without commanding the services the active replica does
+func Discard() {
+	discarding.Store(true)
+}
+
+func writer(l logrus.FieldLogger) func(ctx context.Context) Provider {
+	return func(ctx context.Context) Provider {
+		if p, ok := ctx.Value(providerKey{}).(Provider); ok {
+			return p
+		}
+		if discarding.Load() {
+			return discard(l)
+		}
 		sd := producer.SpanHeaderDecorator(ctx)
 		td := producer.TenantHeaderDecorator(ctx)
+		hd := header.SagaHeaderDecorator(ctx)
 		return func(token string) producer.MessageProducer {
-			return producer.Produce(l)(producer.WriterProvider(topic.EnvProvider(l)(token)))(sd, td)
+			return producer.Produce(l)(producer.WriterProvider(topic.EnvProvider(l)(token)))(sd, td, hd)
+		}
+	}
+}
+
+// discard returns a provider discarding the messages produced to the topic identified by the token
+func discard(l logrus.FieldLogger) Provider {
+	return func(token string) producer.MessageProducer {
+		return func(provider model.Provider[[]kafka.Message]) error {
+			ms, err := provider()
+			if err != nil {
+				return err
+			}
+			l.Debugf("Discarded [%d] messages to [%s].", len(ms), token)
+			return nil
 		}
 	}
 }
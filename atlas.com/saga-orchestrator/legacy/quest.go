@@ -0,0 +1,191 @@
+package legacy
+
+import (
+	"atlas-saga-orchestrator/saga"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrInvalidDefinition is returned when a legacy quest reward definition cannot be converted to a saga template
+var ErrInvalidDefinition = errors.New("invalid legacy quest reward definition")
+
+// Kinds of reward of legacy quest reward definitions
+const (
+	RewardItem  = "item"
+	RewardExp   = "exp"
+	RewardMeso  = "meso"
+	RewardMesos = "mesos"
+)
+
+// QuestRewardDefinition is a legacy quest reward definition, as issued as direct commands by older content services.
+// Each reward is a tuple of its kind and amounts: ["item", templateId, quantity], ["exp", amount] or ["meso", amount].
+type QuestRewardDefinition struct {
+	QuestId uint32            `json:"questId"`         // QuestId the rewards are granted for
+	NpcId   uint32            `json:"npcId,omitempty"` // NpcId granting the rewards, the actor of meso rewards
+	Rewards []json.RawMessage `json:"rewards"`         // Rewards granted, as tuples
+}
+
+// TemplateName returns the name of the saga template generated for the quest
+func TemplateName(questId uint32) string {
+	return fmt.Sprintf("quest_reward_%d", questId)
+}
+
+// ImportQuestRewards converts a JSON array of legacy quest reward definitions to saga templates, one quest_reward
+// template per quest named by TemplateName. Steps reference the characterId, worldId and channelId of the character
+// being rewarded as variables.
+func ImportQuestRewards(r io.Reader) (saga.ChainTemplates, error) {
+	var defs []QuestRewardDefinition
+	if err := json.NewDecoder(r).Decode(&defs); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidDefinition, err.Error())
+	}
+
+	templates := make(saga.ChainTemplates, len(defs))
+	for _, d := range defs {
+		name := TemplateName(d.QuestId)
+		if _, ok := templates[name]; ok {
+			return nil, fmt.Errorf("%w: quest [%d] is defined more than once", ErrInvalidDefinition, d.QuestId)
+		}
+		t, err := questRewardTemplate(d)
+		if err != nil {
+			return nil, err
+		}
+		templates[name] = t
+	}
+	return templates, nil
+}
+
+func questRewardTemplate(d QuestRewardDefinition) (saga.ChainTemplate, error) {
+	if len(d.Rewards) == 0 {
+		return saga.ChainTemplate{}, fmt.Errorf("%w: quest [%d] has no rewards", ErrInvalidDefinition, d.QuestId)
+	}
+
+	steps := make([]saga.Step[any], 0, len(d.Rewards))
+	for i, raw := range d.Rewards {
+		action, payload, err := rewardStep(d, raw)
+		if err != nil {
+			return saga.ChainTemplate{}, fmt.Errorf("%w: quest [%d] reward [%d] %s", ErrInvalidDefinition, d.QuestId, i, err.Error())
+		}
+		st, err := templateStep(fmt.Sprintf("%s_%d", action, i), action, payload)
+		if err != nil {
+			return saga.ChainTemplate{}, fmt.Errorf("%w: quest [%d] reward [%d] %s", ErrInvalidDefinition, d.QuestId, i, err.Error())
+		}
+		steps = append(steps, st)
+	}
+	return saga.ChainTemplate{SagaType: saga.QuestReward, Steps: steps}, nil
+}
+
+// rewardStep returns the action and payload template granting a reward tuple
+func rewardStep(d QuestRewardDefinition, raw json.RawMessage) (saga.Action, map[string]any, error) {
+	var tuple []json.RawMessage
+	if err := json.Unmarshal(raw, &tuple); err != nil || len(tuple) == 0 {
+		return "", nil, errors.New("is not a tuple")
+	}
+	var kind string
+	if err := json.Unmarshal(tuple[0], &kind); err != nil {
+		return "", nil, errors.New("has no kind")
+	}
+
+	switch kind {
+	case RewardItem:
+		if len(tuple) != 3 {
+			return "", nil, errors.New("must be [\"item\", templateId, quantity]")
+		}
+		var templateId, quantity uint32
+		if json.Unmarshal(tuple[1], &templateId) != nil || json.Unmarshal(tuple[2], &quantity) != nil || templateId == 0 || quantity == 0 {
+			return "", nil, errors.New("must have a positive templateId and quantity")
+		}
+		return saga.AwardAsset, map[string]any{
+			"characterId": "{{ .variables.characterId }}",
+			"item":        map[string]any{"templateId": templateId, "quantity": quantity},
+		}, nil
+	case RewardExp:
+		if len(tuple) != 2 {
+			return "", nil, errors.New("must be [\"exp\", amount]")
+		}
+		var amount uint32
+		if json.Unmarshal(tuple[1], &amount) != nil || amount == 0 {
+			return "", nil, errors.New("must have a positive amount")
+		}
+		return saga.AwardExperience, map[string]any{
+			"characterId":   "{{ .variables.characterId }}",
+			"worldId":       "{{ .variables.worldId }}",
+			"channelId":     "{{ .variables.channelId }}",
+			"distributions": []any{map[string]any{"experienceType": "WHITE", "amount": amount}},
+		}, nil
+	case RewardMeso, RewardMesos:
+		if len(tuple) != 2 {
+			return "", nil, errors.New("must be [\"meso\", amount]")
+		}
+		var amount int32
+		if json.Unmarshal(tuple[1], &amount) != nil || amount == 0 {
+			return "", nil, errors.New("must have a non-zero amount")
+		}
+		return saga.AwardMesos, map[string]any{
+			"characterId": "{{ .variables.characterId }}",
+			"worldId":     "{{ .variables.worldId }}",
+			"channelId":   "{{ .variables.channelId }}",
+			"actorId":     d.NpcId,
+			"actorType":   "NPC",
+			"amount":      amount,
+		}, nil
+	default:
+		return "", nil, fmt.Errorf("has unknown kind '%s'", kind)
+	}
+}
+
+// templateStep returns a pending step of the action with the payload template, decoded as any other step so the
+// template is retained to be rendered when the step is dispatched
+func templateStep(stepId string, action saga.Action, payload map[string]any) (saga.Step[any], error) {
+	bs, err := json.Marshal(map[string]any{
+		"stepId":  stepId,
+		"status":  saga.Pending,
+		"action":  action,
+		"payload": payload,
+	})
+	if err != nil {
+		return saga.Step[any]{}, err
+	}
+	var st saga.Step[any]
+	if err = json.Unmarshal(bs, &st); err != nil {
+		return saga.Step[any]{}, err
+	}
+	return st, nil
+}
+
+// templateDefinition is a saga template as written to a SAGA_CHAIN_TEMPLATES file
+type templateDefinition struct {
+	SagaType saga.Type        `json:"sagaType"`
+	Steps    []stepDefinition `json:"steps"`
+}
+
+// stepDefinition is a template step as written to a SAGA_CHAIN_TEMPLATES file, its payload being the payload template
+type stepDefinition struct {
+	StepId  string          `json:"stepId"`
+	Action  saga.Action     `json:"action"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// WriteTemplates writes the saga templates in the format of a SAGA_CHAIN_TEMPLATES file
+func WriteTemplates(w io.Writer, templates saga.ChainTemplates) error {
+	defs := make(map[string]templateDefinition, len(templates))
+	for name, t := range templates {
+		steps := make([]stepDefinition, 0, len(t.Steps))
+		for _, st := range t.Steps {
+			payload := st.PayloadTemplate
+			if len(payload) == 0 {
+				bs, err := json.Marshal(st.Payload)
+				if err != nil {
+					return err
+				}
+				payload = bs
+			}
+			steps = append(steps, stepDefinition{StepId: st.StepId, Action: st.Action, Payload: payload})
+		}
+		defs[name] = templateDefinition{SagaType: t.SagaType, Steps: steps}
+	}
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	return e.Encode(defs)
+}
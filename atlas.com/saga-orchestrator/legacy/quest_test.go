@@ -0,0 +1,75 @@
+package legacy
+
+import (
+	"atlas-saga-orchestrator/saga"
+	"bytes"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+const testDefinitions = `[
+  {"questId": 1001, "npcId": 9010000, "rewards": [["item", 2000000, 10], ["exp", 500], ["meso", 1000]]},
+  {"questId": 1002, "rewards": [["exp", 20]]}
+]`
+
+// TestImportQuestRewards tests converting legacy quest reward definitions to saga templates
+func TestImportQuestRewards(t *testing.T) {
+	templates, err := ImportQuestRewards(strings.NewReader(testDefinitions))
+	require.NoError(t, err)
+	require.Len(t, templates, 2)
+
+	qt := templates[TemplateName(1001)]
+	assert.Equal(t, saga.QuestReward, qt.SagaType)
+	require.Len(t, qt.Steps, 3)
+	assert.Equal(t, []saga.Action{saga.AwardAsset, saga.AwardExperience, saga.AwardMesos}, []saga.Action{qt.Steps[0].Action, qt.Steps[1].Action, qt.Steps[2].Action})
+	for _, st := range qt.Steps {
+		assert.Equal(t, saga.Pending, st.Status)
+		assert.NotEmpty(t, st.PayloadTemplate)
+	}
+
+	// Templates render to the payloads the legacy flow would have commanded
+	s := saga.Saga{
+		TransactionId: uuid.New(),
+		SagaType:      saga.QuestReward,
+		Variables:     saga.Variables{"characterId": float64(12345), "worldId": float64(0), "channelId": float64(1)},
+		Steps:         qt.Steps,
+	}
+	p, err := saga.RenderPayload(s, qt.Steps[0])
+	require.NoError(t, err)
+	assert.Equal(t, saga.AwardItemActionPayload{CharacterId: 12345, Item: saga.ItemPayload{TemplateId: 2000000, Quantity: 10}}, p)
+	p, err = saga.RenderPayload(s, qt.Steps[1])
+	require.NoError(t, err)
+	assert.Equal(t, uint32(500), p.(saga.AwardExperiencePayload).Distributions[0].Amount)
+	p, err = saga.RenderPayload(s, qt.Steps[2])
+	require.NoError(t, err)
+	assert.Equal(t, saga.AwardMesosPayload{CharacterId: 12345, WorldId: 0, ChannelId: 1, ActorId: 9010000, ActorType: "NPC", Amount: 1000}, p)
+
+	// Written templates load as chain templates
+	var out bytes.Buffer
+	require.NoError(t, WriteTemplates(&out, templates))
+	var loaded saga.ChainTemplates
+	require.NoError(t, json.Unmarshal(out.Bytes(), &loaded))
+	require.Len(t, loaded[TemplateName(1001)].Steps, 3)
+	assert.JSONEq(t, string(qt.Steps[2].PayloadTemplate), string(loaded[TemplateName(1001)].Steps[2].PayloadTemplate))
+}
+
+// TestImportQuestRewardsInvalid tests that invalid legacy definitions are rejected
+func TestImportQuestRewardsInvalid(t *testing.T) {
+	for name, defs := range map[string]string{
+		"unknown kind":    `[{"questId": 1, "rewards": [["fame", 1]]}]`,
+		"missing amounts": `[{"questId": 1, "rewards": [["item", 2000000]]}]`,
+		"zero quantity":   `[{"questId": 1, "rewards": [["item", 2000000, 0]]}]`,
+		"no rewards":      `[{"questId": 1, "rewards": []}]`,
+		"duplicate quest": `[{"questId": 1, "rewards": [["exp", 1]]}, {"questId": 1, "rewards": [["exp", 2]]}]`,
+		"not a list":      `{"questId": 1}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ImportQuestRewards(strings.NewReader(defs))
+			assert.ErrorIs(t, err, ErrInvalidDefinition)
+		})
+	}
+}
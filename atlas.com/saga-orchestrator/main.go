@@ -1,19 +1,47 @@
 package main
 
 import (
+	"atlas-saga-orchestrator/cluster"
+	consumer2 "atlas-saga-orchestrator/kafka/consumer"
+	"atlas-saga-orchestrator/kafka/consumer/account"
 	"atlas-saga-orchestrator/kafka/consumer/asset"
+	"atlas-saga-orchestrator/kafka/consumer/buff"
 	"atlas-saga-orchestrator/kafka/consumer/character"
+	cluster2 "atlas-saga-orchestrator/kafka/consumer/cluster"
 	"atlas-saga-orchestrator/kafka/consumer/compartment"
+	"atlas-saga-orchestrator/kafka/consumer/coupon"
+	"atlas-saga-orchestrator/kafka/consumer/faction"
 	"atlas-saga-orchestrator/kafka/consumer/guild"
+	"atlas-saga-orchestrator/kafka/consumer/instance"
+	"atlas-saga-orchestrator/kafka/consumer/invite"
+	"atlas-saga-orchestrator/kafka/consumer/marriage"
+	"atlas-saga-orchestrator/kafka/consumer/monster"
+	"atlas-saga-orchestrator/kafka/consumer/npc"
+	"atlas-saga-orchestrator/kafka/consumer/reactor"
 	saga2 "atlas-saga-orchestrator/kafka/consumer/saga"
+	"atlas-saga-orchestrator/kafka/consumer/session"
 	"atlas-saga-orchestrator/kafka/consumer/skill"
+	"atlas-saga-orchestrator/kafka/consumer/storage"
+	"atlas-saga-orchestrator/kafka/consumer/worldstate"
+	"atlas-saga-orchestrator/kafka/producer"
+	"atlas-saga-orchestrator/legacy"
 	"atlas-saga-orchestrator/logger"
+	"atlas-saga-orchestrator/metrics"
+	"atlas-saga-orchestrator/objectstore"
 	"atlas-saga-orchestrator/saga"
+	v2 "atlas-saga-orchestrator/saga/v2"
 	"atlas-saga-orchestrator/service"
+	"atlas-saga-orchestrator/tasks"
 	"atlas-saga-orchestrator/tracing"
+	"context"
+	"flag"
 	"github.com/Chronicle20/atlas-kafka/consumer"
 	"github.com/Chronicle20/atlas-rest/server"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"net/http"
 	"os"
+	"time"
 )
 
 const serviceName = "atlas-saga-orchestrator"
@@ -39,8 +67,31 @@ func GetServer() Server {
 	}
 }
 
+func GetServerV2() Server {
+	return Server{
+		baseUrl: "",
+		prefix:  "/api/v2/",
+	}
+}
+
 func main() {
+	importLegacyQuests := flag.String("import-legacy-quests", "", "convert a file of legacy quest reward definitions to saga templates, written to stdout, and exit")
+	generateFixtures := flag.String("generate-fixtures", "", "write canonical JSON examples of every registered action and saga type to a directory, and exit")
+	flag.Parse()
+
 	l := logger.CreateLogger(serviceName)
+	if *importLegacyQuests != "" {
+		if err := importQuestRewards(*importLegacyQuests); err != nil {
+			l.WithError(err).Fatal("Unable to import legacy quest reward definitions.")
+		}
+		return
+	}
+	if *generateFixtures != "" {
+		if err := saga.WriteFixtures(*generateFixtures, saga.GenerateFixtures()); err != nil {
+			l.WithError(err).Fatal("Unable to generate saga fixtures.")
+		}
+		return
+	}
 	l.Infoln("Starting main service.")
 
 	tdm := service.GetTeardownManager()
@@ -50,19 +101,254 @@ func main() {
 		l.WithError(err).Fatal("Unable to initialize tracer.")
 	}
 
+	bc, err := saga.BudgetConfigFromEnv()
+	if err != nil {
+		l.WithError(err).Fatal("Unable to load saga budget configuration.")
+	}
+	saga.InitBudget(bc)
+
+	pc, err := saga.ThresholdPolicyConfigFromEnv()
+	if err != nil {
+		l.WithError(err).Fatal("Unable to load saga review policy configuration.")
+	}
+	saga.InitRewardPolicy(saga.NewThresholdPolicy(pc))
+
+	cc, err := saga.ConflictConfigFromEnv()
+	if err != nil {
+		l.WithError(err).Fatal("Unable to load saga asset conflict configuration.")
+	}
+	saga.InitConflictConfig(cc)
+
+	hc, err := saga.HttpRequestConfigFromEnv()
+	if err != nil {
+		l.WithError(err).Fatal("Unable to load saga http request configuration.")
+	}
+	saga.InitHttpRequestConfig(hc)
+
+	mc, err := saga.TenantMismatchConfigFromEnv()
+	if err != nil {
+		l.WithError(err).Fatal("Unable to load saga tenant mismatch configuration.")
+	}
+	saga.InitTenantMismatchConfig(mc)
+
+	ct, err := saga.ChainTemplatesFromEnv()
+	if err != nil {
+		l.WithError(err).Fatal("Unable to load saga chain templates.")
+	}
+	saga.InitChainTemplates(ct)
+
+	ic, err := saga.InviteConfigFromEnv()
+	if err != nil {
+		l.WithError(err).Fatal("Unable to load saga invite configuration.")
+	}
+	saga.InitInviteConfig(ic)
+
+	fc, err := saga.FlagConfigFromEnv()
+	if err != nil {
+		l.WithError(err).Fatal("Unable to load saga action rollout configuration.")
+	}
+	saga.InitFlagConfig(fc)
+
+	oc, err := saga.OfflineConfigFromEnv()
+	if err != nil {
+		l.WithError(err).Fatal("Unable to load saga offline policy configuration.")
+	}
+	saga.InitOfflineConfig(oc)
+
+	prc, err := saga.ProjectionConfigFromEnv()
+	if err != nil {
+		l.WithError(err).Fatal("Unable to load saga projection configuration.")
+	}
+	saga.InitProjectionConfig(prc)
+
+	rc, err := saga.RetryConfigFromEnv()
+	if err != nil {
+		l.WithError(err).Fatal("Unable to load saga dispatch retry configuration.")
+	}
+	saga.InitRetryConfig(rc)
+
+	fcc, err := saga.ClassificationConfigFromEnv()
+	if err != nil {
+		l.WithError(err).Fatal("Unable to load saga failure classification configuration.")
+	}
+	saga.InitClassificationConfig(fcc)
+
+	// A shadow replica processes the events the active replicas do, discarding whatever it would produce
+	shc, err := saga.ShadowConfigFromEnv()
+	if err != nil {
+		l.WithError(err).Fatal("Unable to load saga shadow mode configuration.")
+	}
+	saga.InitShadowConfig(shc)
+	if shc.Enabled {
+		l.Warnln("Running in shadow mode. Commands and events will be discarded.")
+		producer.Discard()
+	}
+
+	clc, err := cluster.ConfigFromEnv()
+	if err != nil {
+		l.WithError(err).Fatal("Unable to load saga orchestrator cluster configuration.")
+	}
+	if shc.Enabled {
+		// A shadow replica owns every saga, so never joins the active replicas in sharing them
+		clc.ReplicaId = ""
+	}
+
+	// Sagas are written behind to PostgreSQL when configured, so those in flight survive a restart
+	dbc, err := saga.PersistenceConfigFromEnv()
+	if err != nil {
+		l.WithError(err).Fatal("Unable to load saga persistence configuration.")
+	}
+	if clc.ReplicaId != "" && !dbc.Enabled() {
+		// Leadership of singleton tasks is leased from the database, without which every replica would lead every role
+		l.Fatalf("Running as replica [%s] requires SAGA_PERSISTENCE to be [%s].", clc.ReplicaId, saga.PersistencePostgres)
+	}
+	cluster.InitMembership(clc)
+	saga.InitOwnership(l)
+	if cluster.GetMembership().Enabled() {
+		l.Infof("Running as replica [%s].", clc.ReplicaId)
+		tasks.Register(l, tdm.Context())(cluster.NewHeartbeatTask(l, tdm.Context()))
+		tdm.TeardownFunc(cluster.Leave(l, context.Background()))
+	}
+	groupId := cluster.ConsumerGroupId(consumerGroupId)
+	if shc.Enabled {
+		groupId = consumerGroupId + " - Shadow"
+	}
+
+	if dbc.Enabled() {
+		db, err := gorm.Open(postgres.Open(dbc.DataSourceName()), &gorm.Config{})
+		if err != nil {
+			l.WithError(err).Fatal("Unable to connect to saga database.")
+		}
+		sqlDb, err := db.DB()
+		if err != nil {
+			l.WithError(err).Fatal("Unable to connect to saga database.")
+		}
+		repo, err := saga.NewPostgresRepository(l, db)
+		if err != nil {
+			l.WithError(err).Fatal("Unable to initialize saga database.")
+		}
+		if shc.Enabled {
+			// A shadow replica reads the active replicas' sagas, never writing its own over them
+			saga.InitArchive(repo)
+			tdm.TeardownFunc(func() { _ = sqlDb.Close() })
+		} else {
+			saga.InitRepository(l, repo)
+			w := saga.NewPersistenceWriter(l, dbc.FlushInterval)
+			tasks.Register(l, tdm.Context())(w)
+			// Leadership of singleton tasks is leased from the database, so no two replicas lead a role at once
+			lease := cluster.NewPostgresLease(sqlDb, clc.ReplicaId)
+			cluster.InitLease(lease)
+			// Sagas awaiting being written are written, and roles released to other replicas, before the database is closed
+			tdm.TeardownFunc(func() {
+				w.Run()
+				_ = lease.Release()
+				_ = sqlDb.Close()
+			})
+		}
+	}
+
+	// Parked steps are redelivered, and timed out steps failed, by the replica leading each role
+	tasks.Register(l, tdm.Context())(cluster.Singleton(l, saga.RoleRedeliveryScheduler)(saga.NewRetryTask(l, time.Second)))
+	tasks.Register(l, tdm.Context())(cluster.Singleton(l, saga.RoleStepTimeoutReaper)(saga.NewStepTimeoutTask(l, time.Second)))
+
+	sc, err := saga.StaleConfigFromEnv()
+	if err != nil {
+		l.WithError(err).Fatal("Unable to load stale saga configuration.")
+	}
+	if sc.Threshold > 0 {
+		tasks.Register(l, tdm.Context())(cluster.Singleton(l, saga.RoleStaleSagaReporter)(saga.NewStaleReporter(l, sc)))
+	}
+
+	// Each replica reconciles the sagas it completed
+	rcc, err := saga.ReconcileConfigFromEnv()
+	if err != nil {
+		l.WithError(err).Fatal("Unable to load saga reconciliation configuration.")
+	}
+	saga.InitReconcileConfig(rcc)
+	if rcc.Interval > 0 {
+		tasks.Register(l, tdm.Context())(saga.NewReconciler(l, rcc))
+	}
+
+	// Each replica compacts its own cache
+	cpc, err := saga.CompactionConfigFromEnv()
+	if err != nil {
+		l.WithError(err).Fatal("Unable to load saga cache compaction configuration.")
+	}
+	tasks.Register(l, tdm.Context())(saga.NewCompactor(l, cpc))
+
+	// Each replica exports the sagas it finished. A shadow replica's are exported by the active replicas.
+	ec, err := saga.ExportConfigFromEnv()
+	if err != nil {
+		l.WithError(err).Fatal("Unable to load saga export configuration.")
+	}
+	saga.InitExportConfig(ec)
+	if ec.Enabled() && !shc.Enabled {
+		ex := saga.NewExporter(l, ec, objectstore.NewS3Store(ec.Store, &http.Client{Timeout: 30 * time.Second}))
+		tasks.Register(l, tdm.Context())(ex)
+		tdm.TeardownFunc(ex.Run)
+	}
+
+	// Sagas held before a restart are available before events referencing them are received. A replica of several warms
+	// the sagas it owns once it settles.
+	if err = saga.WarmCache(l); err != nil {
+		l.WithError(err).Error("Unable to warm saga cache from archive.")
+	}
+
 	cmf := consumer.GetManager().AddConsumer(l, tdm.Context(), tdm.WaitGroup())
-	asset.InitConsumers(l)(cmf)(consumerGroupId)
-	character.InitConsumers(l)(cmf)(consumerGroupId)
-	compartment.InitConsumers(l)(cmf)(consumerGroupId)
-	guild.InitConsumers(l)(cmf)(consumerGroupId)
-	saga2.InitConsumers(l)(cmf)(consumerGroupId)
-	skill.InitConsumers(l)(cmf)(consumerGroupId)
-	asset.InitHandlers(l)(consumer.GetManager().RegisterHandler)
-	character.InitHandlers(l)(consumer.GetManager().RegisterHandler)
-	compartment.InitHandlers(l)(consumer.GetManager().RegisterHandler)
-	guild.InitHandlers(l)(consumer.GetManager().RegisterHandler)
-	saga2.InitHandlers(l)(consumer.GetManager().RegisterHandler)
-	skill.InitHandlers(l)(consumer.GetManager().RegisterHandler)
+	account.InitConsumers(l)(cmf)(groupId)
+	asset.InitConsumers(l)(cmf)(groupId)
+	buff.InitConsumers(l)(cmf)(groupId)
+	character.InitConsumers(l)(cmf)(groupId)
+	compartment.InitConsumers(l)(cmf)(groupId)
+	coupon.InitConsumers(l)(cmf)(groupId)
+	faction.InitConsumers(l)(cmf)(groupId)
+	instance.InitConsumers(l)(cmf)(groupId)
+	guild.InitConsumers(l)(cmf)(groupId)
+	invite.InitConsumers(l)(cmf)(groupId)
+	marriage.InitConsumers(l)(cmf)(groupId)
+	monster.InitConsumers(l)(cmf)(groupId)
+	npc.InitConsumers(l)(cmf)(groupId)
+	reactor.InitConsumers(l)(cmf)(groupId)
+	saga2.InitConsumers(l)(cmf)(groupId)
+	session.InitConsumers(l)(cmf)(groupId)
+	skill.InitConsumers(l)(cmf)(groupId)
+	storage.InitConsumers(l)(cmf)(groupId)
+	worldstate.InitConsumers(l)(cmf)(groupId)
+	if cluster.GetMembership().Enabled() {
+		cluster2.InitConsumers(l)(cmf)(groupId)
+	}
+	dc, err := consumer2.DedupConfigFromEnv(l)
+	if err != nil {
+		l.WithError(err).Fatal("Unable to load consumer deduplication configuration.")
+	}
+	register := consumer.GetManager().RegisterHandler
+	if shc.Enabled {
+		register = consumer2.Shadowed(l, saga.ShadowObserve)(register)
+	}
+	rf := consumer2.TenantRequired(l)(consumer2.PanicIsolated(l, saga.Quarantine)(consumer2.Deduplicated(l, dc)(register)))
+	account.InitHandlers(l)(rf)
+	asset.InitHandlers(l)(rf)
+	buff.InitHandlers(l)(rf)
+	character.InitHandlers(l)(rf)
+	compartment.InitHandlers(l)(rf)
+	coupon.InitHandlers(l)(rf)
+	faction.InitHandlers(l)(rf)
+	instance.InitHandlers(l)(rf)
+	guild.InitHandlers(l)(rf)
+	invite.InitHandlers(l)(rf)
+	marriage.InitHandlers(l)(rf)
+	monster.InitHandlers(l)(rf)
+	npc.InitHandlers(l)(rf)
+	reactor.InitHandlers(l)(rf)
+	saga2.InitHandlers(l)(rf)
+	session.InitHandlers(l)(rf)
+	skill.InitHandlers(l)(rf)
+	storage.InitHandlers(l)(rf)
+	worldstate.InitHandlers(l)(rf)
+	if cluster.GetMembership().Enabled() {
+		// Membership spans tenants, so announcements carry no tenant
+		cluster2.InitHandlers(l)(consumer2.PanicIsolated(l, nil)(consumer.GetManager().RegisterHandler))
+	}
 
 	// Create the service with the router
 	server.New(l).
@@ -71,6 +357,13 @@ func main() {
 		SetBasePath(GetServer().GetPrefix()).
 		SetPort(os.Getenv("REST_PORT")).
 		AddRouteInitializer(saga.InitResource(GetServer())).
+		AddRouteInitializer(v2.InitResource(GetServerV2())).
+		AddRouteInitializer(metrics.InitResource()).
+		AddRouteInitializer(cluster.InitResource()).
+		AddRouteInitializer(saga.InitUsageResource()).
+		AddRouteInitializer(saga.InitActionResource()).
+		AddRouteInitializer(saga.InitReconciliationResource()).
+		AddRouteInitializer(saga.InitCacheResource()).
 		Run()
 
 	tdm.TeardownFunc(tracing.Teardown(l)(tc))
@@ -78,3 +371,19 @@ func main() {
 	tdm.Wait()
 	l.Infoln("Service shutdown.")
 }
+
+// importQuestRewards converts the legacy quest reward definitions at the path to saga templates, written to stdout in
+// the format of a SAGA_CHAIN_TEMPLATES file
+func importQuestRewards(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	templates, err := legacy.ImportQuestRewards(f)
+	if err != nil {
+		return err
+	}
+	return legacy.WriteTemplates(os.Stdout, templates)
+}
@@ -0,0 +1,72 @@
+package mock
+
+import (
+	"atlas-saga-orchestrator/kafka/message"
+	"atlas-saga-orchestrator/marriage"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/google/uuid"
+)
+
+// ProcessorMock is a mock implementation of the marriage.Processor interface
+type ProcessorMock struct {
+	GetByCharacterIdFunc      func(characterId uint32) (marriage.Model, error)
+	ByCharacterIdProviderFunc func(characterId uint32) model.Provider[marriage.Model]
+	DivorceAndEmitFunc        func(transactionId uuid.UUID, characterId uint32, marriageId uint32, partnerId uint32) error
+	DivorceFunc               func(mb *message.Buffer) func(transactionId uuid.UUID, characterId uint32, marriageId uint32, partnerId uint32) error
+	RestoreAndEmitFunc        func(transactionId uuid.UUID, characterId uint32, marriageId uint32, partnerId uint32) error
+	RestoreFunc               func(mb *message.Buffer) func(transactionId uuid.UUID, characterId uint32, marriageId uint32, partnerId uint32) error
+}
+
+// GetByCharacterId is a mock implementation of the marriage.Processor.GetByCharacterId method
+func (m *ProcessorMock) GetByCharacterId(characterId uint32) (marriage.Model, error) {
+	if m.GetByCharacterIdFunc != nil {
+		return m.GetByCharacterIdFunc(characterId)
+	}
+	return marriage.Model{}, nil
+}
+
+// ByCharacterIdProvider is a mock implementation of the marriage.Processor.ByCharacterIdProvider method
+func (m *ProcessorMock) ByCharacterIdProvider(characterId uint32) model.Provider[marriage.Model] {
+	if m.ByCharacterIdProviderFunc != nil {
+		return m.ByCharacterIdProviderFunc(characterId)
+	}
+	return func() (marriage.Model, error) {
+		return m.GetByCharacterId(characterId)
+	}
+}
+
+// DivorceAndEmit is a mock implementation of the marriage.Processor.DivorceAndEmit method
+func (m *ProcessorMock) DivorceAndEmit(transactionId uuid.UUID, characterId uint32, marriageId uint32, partnerId uint32) error {
+	if m.DivorceAndEmitFunc != nil {
+		return m.DivorceAndEmitFunc(transactionId, characterId, marriageId, partnerId)
+	}
+	return nil
+}
+
+// Divorce is a mock implementation of the marriage.Processor.Divorce method
+func (m *ProcessorMock) Divorce(mb *message.Buffer) func(transactionId uuid.UUID, characterId uint32, marriageId uint32, partnerId uint32) error {
+	if m.DivorceFunc != nil {
+		return m.DivorceFunc(mb)
+	}
+	return func(transactionId uuid.UUID, characterId uint32, marriageId uint32, partnerId uint32) error {
+		return nil
+	}
+}
+
+// RestoreAndEmit is a mock implementation of the marriage.Processor.RestoreAndEmit method
+func (m *ProcessorMock) RestoreAndEmit(transactionId uuid.UUID, characterId uint32, marriageId uint32, partnerId uint32) error {
+	if m.RestoreAndEmitFunc != nil {
+		return m.RestoreAndEmitFunc(transactionId, characterId, marriageId, partnerId)
+	}
+	return nil
+}
+
+// Restore is a mock implementation of the marriage.Processor.Restore method
+func (m *ProcessorMock) Restore(mb *message.Buffer) func(transactionId uuid.UUID, characterId uint32, marriageId uint32, partnerId uint32) error {
+	if m.RestoreFunc != nil {
+		return m.RestoreFunc(mb)
+	}
+	return func(transactionId uuid.UUID, characterId uint32, marriageId uint32, partnerId uint32) error {
+		return nil
+	}
+}
@@ -0,0 +1,111 @@
+package marriage
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	StatusEngaged  = "ENGAGED"
+	StatusMarried  = "MARRIED"
+	StatusDivorced = "DIVORCED"
+)
+
+var (
+	ErrNotMarried      = errors.New("character is not married")
+	ErrPartnerMismatch = errors.New("character is married to another partner")
+)
+
+type Model struct {
+	id             uint32
+	status         string
+	characterId    uint32
+	partnerId      uint32
+	ringTemplateId uint32
+	marriedAt      time.Time
+}
+
+func (m Model) Id() uint32 {
+	return m.id
+}
+
+func (m Model) Status() string {
+	return m.status
+}
+
+func (m Model) CharacterId() uint32 {
+	return m.characterId
+}
+
+// PartnerId is the character the character is married to
+func (m Model) PartnerId() uint32 {
+	return m.partnerId
+}
+
+// RingTemplateId is the template of the wedding rings exchanged by the couple, or 0 if none were
+func (m Model) RingTemplateId() uint32 {
+	return m.ringTemplateId
+}
+
+func (m Model) MarriedAt() time.Time {
+	return m.marriedAt
+}
+
+// Divorceable returns an error describing why the marriage cannot be dissolved, if it cannot. When a partner is given,
+// the character must be married to them.
+func (m Model) Divorceable(partnerId uint32) error {
+	if m.status != StatusMarried {
+		return ErrNotMarried
+	}
+	if partnerId != 0 && m.partnerId != partnerId {
+		return ErrPartnerMismatch
+	}
+	return nil
+}
+
+type ModelBuilder struct {
+	id             uint32
+	status         string
+	characterId    uint32
+	partnerId      uint32
+	ringTemplateId uint32
+	marriedAt      time.Time
+}
+
+func NewBuilder(id uint32, status string) *ModelBuilder {
+	return &ModelBuilder{
+		id:     id,
+		status: status,
+	}
+}
+
+func (b *ModelBuilder) SetCharacterId(characterId uint32) *ModelBuilder {
+	b.characterId = characterId
+	return b
+}
+
+func (b *ModelBuilder) SetPartnerId(partnerId uint32) *ModelBuilder {
+	b.partnerId = partnerId
+	return b
+}
+
+func (b *ModelBuilder) SetRingTemplateId(ringTemplateId uint32) *ModelBuilder {
+	b.ringTemplateId = ringTemplateId
+	return b
+}
+
+func (b *ModelBuilder) SetMarriedAt(marriedAt time.Time) *ModelBuilder {
+	b.marriedAt = marriedAt
+	return b
+}
+
+func (b *ModelBuilder) Build() Model {
+	return Model{
+		id:             b.id,
+		status:         b.status,
+		characterId:    b.characterId,
+		partnerId:      b.partnerId,
+		ringTemplateId: b.ringTemplateId,
+		marriedAt:      b.marriedAt,
+	}
+}
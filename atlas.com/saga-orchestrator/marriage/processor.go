@@ -0,0 +1,70 @@
+package marriage
+
+import (
+	"atlas-saga-orchestrator/kafka/message"
+	marriage2 "atlas-saga-orchestrator/kafka/message/marriage"
+	"atlas-saga-orchestrator/kafka/producer"
+	"context"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/Chronicle20/atlas-rest/requests"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type Processor interface {
+	GetByCharacterId(characterId uint32) (Model, error)
+	ByCharacterIdProvider(characterId uint32) model.Provider[Model]
+	DivorceAndEmit(transactionId uuid.UUID, characterId uint32, marriageId uint32, partnerId uint32) error
+	Divorce(mb *message.Buffer) func(transactionId uuid.UUID, characterId uint32, marriageId uint32, partnerId uint32) error
+	RestoreAndEmit(transactionId uuid.UUID, characterId uint32, marriageId uint32, partnerId uint32) error
+	Restore(mb *message.Buffer) func(transactionId uuid.UUID, characterId uint32, marriageId uint32, partnerId uint32) error
+}
+
+type ProcessorImpl struct {
+	l   logrus.FieldLogger
+	ctx context.Context
+	p   producer.Provider
+}
+
+func NewProcessor(l logrus.FieldLogger, ctx context.Context) Processor {
+	return &ProcessorImpl{
+		l:   l,
+		ctx: ctx,
+		p:   producer.ProviderImpl(l)(ctx),
+	}
+}
+
+// GetByCharacterId returns the marriage record of the character
+func (p *ProcessorImpl) GetByCharacterId(characterId uint32) (Model, error) {
+	return p.ByCharacterIdProvider(characterId)()
+}
+
+func (p *ProcessorImpl) ByCharacterIdProvider(characterId uint32) model.Provider[Model] {
+	return requests.Provider[RestModel, Model](p.l, p.ctx)(requestByCharacterId(characterId), Extract)
+}
+
+// DivorceAndEmit requests the marriage be dissolved, updating the records of both partners
+func (p *ProcessorImpl) DivorceAndEmit(transactionId uuid.UUID, characterId uint32, marriageId uint32, partnerId uint32) error {
+	return message.Emit(p.p)(func(mb *message.Buffer) error {
+		return p.Divorce(mb)(transactionId, characterId, marriageId, partnerId)
+	})
+}
+
+func (p *ProcessorImpl) Divorce(mb *message.Buffer) func(transactionId uuid.UUID, characterId uint32, marriageId uint32, partnerId uint32) error {
+	return func(transactionId uuid.UUID, characterId uint32, marriageId uint32, partnerId uint32) error {
+		return mb.Put(marriage2.EnvCommandTopic, DivorceProvider(transactionId, characterId, marriageId, partnerId))
+	}
+}
+
+// RestoreAndEmit requests a marriage dissolved by the transaction be restored, as it was before the divorce
+func (p *ProcessorImpl) RestoreAndEmit(transactionId uuid.UUID, characterId uint32, marriageId uint32, partnerId uint32) error {
+	return message.Emit(p.p)(func(mb *message.Buffer) error {
+		return p.Restore(mb)(transactionId, characterId, marriageId, partnerId)
+	})
+}
+
+func (p *ProcessorImpl) Restore(mb *message.Buffer) func(transactionId uuid.UUID, characterId uint32, marriageId uint32, partnerId uint32) error {
+	return func(transactionId uuid.UUID, characterId uint32, marriageId uint32, partnerId uint32) error {
+		return mb.Put(marriage2.EnvCommandTopic, RestoreProvider(transactionId, characterId, marriageId, partnerId))
+	}
+}
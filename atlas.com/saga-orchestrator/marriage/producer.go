@@ -0,0 +1,37 @@
+package marriage
+
+import (
+	marriage2 "atlas-saga-orchestrator/kafka/message/marriage"
+	"github.com/Chronicle20/atlas-kafka/producer"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+)
+
+func DivorceProvider(transactionId uuid.UUID, characterId uint32, marriageId uint32, partnerId uint32) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(marriageId))
+	value := &marriage2.Command[marriage2.DivorceCommandBody]{
+		TransactionId: transactionId,
+		CharacterId:   characterId,
+		Type:          marriage2.CommandTypeDivorce,
+		Body: marriage2.DivorceCommandBody{
+			MarriageId: marriageId,
+			PartnerId:  partnerId,
+		},
+	}
+	return producer.SingleMessageProvider(key, value)
+}
+
+func RestoreProvider(transactionId uuid.UUID, characterId uint32, marriageId uint32, partnerId uint32) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(marriageId))
+	value := &marriage2.Command[marriage2.RestoreCommandBody]{
+		TransactionId: transactionId,
+		CharacterId:   characterId,
+		Type:          marriage2.CommandTypeRestore,
+		Body: marriage2.RestoreCommandBody{
+			MarriageId: marriageId,
+			PartnerId:  partnerId,
+		},
+	}
+	return producer.SingleMessageProvider(key, value)
+}
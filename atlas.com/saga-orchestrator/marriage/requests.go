@@ -0,0 +1,19 @@
+package marriage
+
+import (
+	"atlas-saga-orchestrator/rest"
+	"fmt"
+	"github.com/Chronicle20/atlas-rest/requests"
+)
+
+const (
+	marriageByCharacter = "characters/%d/marriage"
+)
+
+func getBaseRequest() string {
+	return requests.RootUrl("MARRIAGES")
+}
+
+func requestByCharacterId(characterId uint32) requests.Request[RestModel] {
+	return rest.MakeGetRequest[RestModel](fmt.Sprintf(getBaseRequest()+marriageByCharacter, characterId))
+}
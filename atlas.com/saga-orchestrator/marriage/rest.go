@@ -0,0 +1,41 @@
+package marriage
+
+import (
+	"strconv"
+	"time"
+)
+
+type RestModel struct {
+	Id             string    `json:"-"`
+	Status         string    `json:"status"`
+	CharacterId    uint32    `json:"characterId"`
+	PartnerId      uint32    `json:"partnerId"`
+	RingTemplateId uint32    `json:"ringTemplateId"`
+	MarriedAt      time.Time `json:"marriedAt"`
+}
+
+func (r RestModel) GetName() string {
+	return "marriages"
+}
+
+func (r RestModel) GetID() string {
+	return r.Id
+}
+
+func (r *RestModel) SetID(id string) error {
+	r.Id = id
+	return nil
+}
+
+func Extract(rm RestModel) (Model, error) {
+	id, err := strconv.ParseUint(rm.Id, 10, 32)
+	if err != nil {
+		return Model{}, err
+	}
+	return NewBuilder(uint32(id), rm.Status).
+		SetCharacterId(rm.CharacterId).
+		SetPartnerId(rm.PartnerId).
+		SetRingTemplateId(rm.RingTemplateId).
+		SetMarriedAt(rm.MarriedAt).
+		Build(), nil
+}
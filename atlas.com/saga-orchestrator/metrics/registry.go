@@ -0,0 +1,172 @@
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Chronicle20/atlas-rest/server"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+// Sample is a value of a metric, distinguished from the metric's other samples by its labels
+type Sample struct {
+	Labels map[string]string
+	Value  float64
+}
+
+// GaugeFunc provides the samples of a gauge as they stand when the metrics are scraped
+type GaugeFunc func() []Sample
+
+type gauge struct {
+	name string
+	help string
+	f    GaugeFunc
+}
+
+// Counter is a metric which only increases, such as a number of events, of which a rate may be derived
+type Counter struct {
+	name    string
+	help    string
+	mutex   sync.Mutex
+	samples map[string]Sample
+}
+
+// Inc increments the sample of the counter with the labels
+func (c *Counter) Inc(labels map[string]string) {
+	c.Add(labels, 1)
+}
+
+// Add adds the value, which must not be negative, to the sample of the counter with the labels
+func (c *Counter) Add(labels map[string]string, value float64) {
+	if value < 0 {
+		return
+	}
+	key := formatLabels(labels)
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	s, ok := c.samples[key]
+	if !ok {
+		s = Sample{Labels: labels}
+	}
+	s.Value += value
+	c.samples[key] = s
+}
+
+// Samples returns the samples of the counter as they stand
+func (c *Counter) Samples() []Sample {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	keys := make([]string, 0, len(c.samples))
+	for k := range c.samples {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	r := make([]Sample, 0, len(keys))
+	for _, k := range keys {
+		r = append(r, c.samples[k])
+	}
+	return r
+}
+
+// Registry holds the metrics exposed by the service
+type Registry struct {
+	mutex    sync.RWMutex
+	gauges   map[string]gauge
+	counters map[string]*Counter
+}
+
+// Singleton instance of the registry
+var instance *Registry
+var once sync.Once
+
+// GetRegistry returns the singleton instance of the registry
+func GetRegistry() *Registry {
+	once.Do(func() {
+		instance = &Registry{gauges: make(map[string]gauge), counters: make(map[string]*Counter)}
+	})
+	return instance
+}
+
+// RegisterGauge registers a gauge, replacing any registered under the same name
+func (r *Registry) RegisterGauge(name string, help string, f GaugeFunc) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.gauges[name] = gauge{name: name, help: help, f: f}
+}
+
+// RegisterCounter registers a counter, returning the counter already registered under the name, if any, so its count
+// is kept
+func (r *Registry) RegisterCounter(name string, help string) *Counter {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if c, ok := r.counters[name]; ok {
+		return c
+	}
+	c := &Counter{name: name, help: help, samples: make(map[string]Sample)}
+	r.counters[name] = c
+	return c
+}
+
+// family is a metric as written, with the samples it has when the metrics are scraped
+type family struct {
+	name    string
+	help    string
+	kind    string
+	samples func() []Sample
+}
+
+// Write writes the registered metrics in the Prometheus text exposition format
+func (r *Registry) Write(sb *strings.Builder) {
+	r.mutex.RLock()
+	families := make([]family, 0, len(r.gauges)+len(r.counters))
+	for _, g := range r.gauges {
+		families = append(families, family{name: g.name, help: g.help, kind: "gauge", samples: g.f})
+	}
+	for _, c := range r.counters {
+		families = append(families, family{name: c.name, help: c.help, kind: "counter", samples: c.Samples})
+	}
+	r.mutex.RUnlock()
+	sort.Slice(families, func(i, j int) bool { return families[i].name < families[j].name })
+
+	for _, f := range families {
+		_, _ = fmt.Fprintf(sb, "# HELP %s %s\n# TYPE %s %s\n", f.name, f.help, f.name, f.kind)
+		for _, s := range f.samples() {
+			_, _ = fmt.Fprintf(sb, "%s%s %v\n", f.name, formatLabels(s.Labels), s.Value)
+		}
+	}
+}
+
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		v := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(labels[k])
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, k, v))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// InitResource registers the metrics route with the router. Metrics span tenants, so no tenant is required.
+func InitResource() server.RouteInitializer {
+	return func(r *mux.Router, l logrus.FieldLogger) {
+		r.HandleFunc("/metrics", func(w http.ResponseWriter, _ *http.Request) {
+			sb := &strings.Builder{}
+			GetRegistry().Write(sb)
+			w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+			if _, err := w.Write([]byte(sb.String())); err != nil {
+				l.WithError(err).Error("Unable to write metrics.")
+			}
+		}).Methods(http.MethodGet)
+	}
+}
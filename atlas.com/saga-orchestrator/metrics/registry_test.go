@@ -0,0 +1,32 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWrite tests writing gauges and counters in the Prometheus text exposition format
+func TestWrite(t *testing.T) {
+	r := &Registry{gauges: make(map[string]gauge), counters: make(map[string]*Counter)}
+	r.RegisterGauge("queue_depth", "Depth of the queue.", func() []Sample {
+		return []Sample{{Labels: map[string]string{"tenant": "a", "kind": `x"y`}, Value: 3}}
+	})
+	r.RegisterGauge("active", "Active things.", func() []Sample {
+		return []Sample{{Value: 1.5}}
+	})
+
+	c := r.RegisterCounter("hits_total", "Hits.")
+	c.Inc(map[string]string{"result": "miss"})
+	c.Inc(map[string]string{"result": "hit"})
+	c.Add(map[string]string{"result": "hit"}, 2)
+	c.Add(map[string]string{"result": "hit"}, -1)
+	assert.Same(t, c, r.RegisterCounter("hits_total", "Hits."))
+
+	sb := &strings.Builder{}
+	r.Write(sb)
+	assert.Equal(t, "# HELP active Active things.\n# TYPE active gauge\nactive 1.5\n"+
+		"# HELP hits_total Hits.\n# TYPE hits_total counter\nhits_total{result=\"hit\"} 3\nhits_total{result=\"miss\"} 1\n"+
+		"# HELP queue_depth Depth of the queue.\n# TYPE queue_depth gauge\nqueue_depth{kind=\"x\\\"y\",tenant=\"a\"} 3\n", sb.String())
+}
@@ -0,0 +1,74 @@
+package mock
+
+import (
+	"atlas-saga-orchestrator/kafka/message"
+	"github.com/Chronicle20/atlas-constants/channel"
+	"github.com/Chronicle20/atlas-constants/field"
+	_map "github.com/Chronicle20/atlas-constants/map"
+	"github.com/Chronicle20/atlas-constants/world"
+	"github.com/google/uuid"
+)
+
+// ProcessorMock is a mock implementation of the npc.Processor interface
+type ProcessorMock struct {
+	SpawnEscortAndEmitFunc        func(transactionId uuid.UUID, f field.Model, npcId uint32, characterId uint32, destinationMapId _map.Id) error
+	SpawnEscortFunc               func(mb *message.Buffer) func(transactionId uuid.UUID, f field.Model, npcId uint32, characterId uint32, destinationMapId _map.Id) error
+	DespawnEscortAndEmitFunc      func(transactionId uuid.UUID, f field.Model, npcId uint32, characterId uint32) error
+	DespawnEscortFunc             func(mb *message.Buffer) func(transactionId uuid.UUID, f field.Model, npcId uint32, characterId uint32) error
+	ResumeConversationAndEmitFunc func(transactionId uuid.UUID, worldId world.Id, channelId channel.Id, characterId uint32, npcId uint32, state string, outcome map[string]any) error
+	ResumeConversationFunc        func(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, channelId channel.Id, characterId uint32, npcId uint32, state string, outcome map[string]any) error
+}
+
+// SpawnEscortAndEmit is a mock implementation of the npc.Processor.SpawnEscortAndEmit method
+func (m *ProcessorMock) SpawnEscortAndEmit(transactionId uuid.UUID, f field.Model, npcId uint32, characterId uint32, destinationMapId _map.Id) error {
+	if m.SpawnEscortAndEmitFunc != nil {
+		return m.SpawnEscortAndEmitFunc(transactionId, f, npcId, characterId, destinationMapId)
+	}
+	return nil
+}
+
+// SpawnEscort is a mock implementation of the npc.Processor.SpawnEscort method
+func (m *ProcessorMock) SpawnEscort(mb *message.Buffer) func(transactionId uuid.UUID, f field.Model, npcId uint32, characterId uint32, destinationMapId _map.Id) error {
+	if m.SpawnEscortFunc != nil {
+		return m.SpawnEscortFunc(mb)
+	}
+	return func(transactionId uuid.UUID, f field.Model, npcId uint32, characterId uint32, destinationMapId _map.Id) error {
+		return nil
+	}
+}
+
+// DespawnEscortAndEmit is a mock implementation of the npc.Processor.DespawnEscortAndEmit method
+func (m *ProcessorMock) DespawnEscortAndEmit(transactionId uuid.UUID, f field.Model, npcId uint32, characterId uint32) error {
+	if m.DespawnEscortAndEmitFunc != nil {
+		return m.DespawnEscortAndEmitFunc(transactionId, f, npcId, characterId)
+	}
+	return nil
+}
+
+// DespawnEscort is a mock implementation of the npc.Processor.DespawnEscort method
+func (m *ProcessorMock) DespawnEscort(mb *message.Buffer) func(transactionId uuid.UUID, f field.Model, npcId uint32, characterId uint32) error {
+	if m.DespawnEscortFunc != nil {
+		return m.DespawnEscortFunc(mb)
+	}
+	return func(transactionId uuid.UUID, f field.Model, npcId uint32, characterId uint32) error {
+		return nil
+	}
+}
+
+// ResumeConversationAndEmit is a mock implementation of the npc.Processor.ResumeConversationAndEmit method
+func (m *ProcessorMock) ResumeConversationAndEmit(transactionId uuid.UUID, worldId world.Id, channelId channel.Id, characterId uint32, npcId uint32, state string, outcome map[string]any) error {
+	if m.ResumeConversationAndEmitFunc != nil {
+		return m.ResumeConversationAndEmitFunc(transactionId, worldId, channelId, characterId, npcId, state, outcome)
+	}
+	return nil
+}
+
+// ResumeConversation is a mock implementation of the npc.Processor.ResumeConversation method
+func (m *ProcessorMock) ResumeConversation(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, channelId channel.Id, characterId uint32, npcId uint32, state string, outcome map[string]any) error {
+	if m.ResumeConversationFunc != nil {
+		return m.ResumeConversationFunc(mb)
+	}
+	return func(transactionId uuid.UUID, worldId world.Id, channelId channel.Id, characterId uint32, npcId uint32, state string, outcome map[string]any) error {
+		return nil
+	}
+}
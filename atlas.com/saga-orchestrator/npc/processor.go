@@ -0,0 +1,79 @@
+package npc
+
+import (
+	"atlas-saga-orchestrator/kafka/message"
+	npc2 "atlas-saga-orchestrator/kafka/message/npc"
+	"atlas-saga-orchestrator/kafka/producer"
+	"context"
+	"github.com/Chronicle20/atlas-constants/channel"
+	"github.com/Chronicle20/atlas-constants/field"
+	_map "github.com/Chronicle20/atlas-constants/map"
+	"github.com/Chronicle20/atlas-constants/world"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type Processor interface {
+	SpawnEscortAndEmit(transactionId uuid.UUID, f field.Model, npcId uint32, characterId uint32, destinationMapId _map.Id) error
+	SpawnEscort(mb *message.Buffer) func(transactionId uuid.UUID, f field.Model, npcId uint32, characterId uint32, destinationMapId _map.Id) error
+	DespawnEscortAndEmit(transactionId uuid.UUID, f field.Model, npcId uint32, characterId uint32) error
+	DespawnEscort(mb *message.Buffer) func(transactionId uuid.UUID, f field.Model, npcId uint32, characterId uint32) error
+	ResumeConversationAndEmit(transactionId uuid.UUID, worldId world.Id, channelId channel.Id, characterId uint32, npcId uint32, state string, outcome map[string]any) error
+	ResumeConversation(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, channelId channel.Id, characterId uint32, npcId uint32, state string, outcome map[string]any) error
+}
+
+type ProcessorImpl struct {
+	l   logrus.FieldLogger
+	ctx context.Context
+	p   producer.Provider
+}
+
+func NewProcessor(l logrus.FieldLogger, ctx context.Context) Processor {
+	return &ProcessorImpl{
+		l:   l,
+		ctx: ctx,
+		p:   producer.ProviderImpl(l)(ctx),
+	}
+}
+
+// SpawnEscortAndEmit requests the NPC be spawned in the field as the character's escort, following them until it
+// reaches the destination map. An escort spawned for a transaction which already has one replaces it.
+func (p *ProcessorImpl) SpawnEscortAndEmit(transactionId uuid.UUID, f field.Model, npcId uint32, characterId uint32, destinationMapId _map.Id) error {
+	return message.Emit(p.p)(func(mb *message.Buffer) error {
+		return p.SpawnEscort(mb)(transactionId, f, npcId, characterId, destinationMapId)
+	})
+}
+
+func (p *ProcessorImpl) SpawnEscort(mb *message.Buffer) func(transactionId uuid.UUID, f field.Model, npcId uint32, characterId uint32, destinationMapId _map.Id) error {
+	return func(transactionId uuid.UUID, f field.Model, npcId uint32, characterId uint32, destinationMapId _map.Id) error {
+		return mb.Put(npc2.EnvCommandTopic, SpawnEscortProvider(transactionId, f, npcId, characterId, destinationMapId))
+	}
+}
+
+// DespawnEscortAndEmit requests the escort spawned for the transaction be removed, wherever it has followed the
+// character to
+func (p *ProcessorImpl) DespawnEscortAndEmit(transactionId uuid.UUID, f field.Model, npcId uint32, characterId uint32) error {
+	return message.Emit(p.p)(func(mb *message.Buffer) error {
+		return p.DespawnEscort(mb)(transactionId, f, npcId, characterId)
+	})
+}
+
+func (p *ProcessorImpl) DespawnEscort(mb *message.Buffer) func(transactionId uuid.UUID, f field.Model, npcId uint32, characterId uint32) error {
+	return func(transactionId uuid.UUID, f field.Model, npcId uint32, characterId uint32) error {
+		return mb.Put(npc2.EnvCommandTopic, DespawnEscortProvider(transactionId, f, npcId, characterId))
+	}
+}
+
+// ResumeConversationAndEmit signals the NPC conversation service to resume the character's conversation with the NPC,
+// suspended while a saga progressed, at the dialogue state, with the outcome of the saga
+func (p *ProcessorImpl) ResumeConversationAndEmit(transactionId uuid.UUID, worldId world.Id, channelId channel.Id, characterId uint32, npcId uint32, state string, outcome map[string]any) error {
+	return message.Emit(p.p)(func(mb *message.Buffer) error {
+		return p.ResumeConversation(mb)(transactionId, worldId, channelId, characterId, npcId, state, outcome)
+	})
+}
+
+func (p *ProcessorImpl) ResumeConversation(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, channelId channel.Id, characterId uint32, npcId uint32, state string, outcome map[string]any) error {
+	return func(transactionId uuid.UUID, worldId world.Id, channelId channel.Id, characterId uint32, npcId uint32, state string, outcome map[string]any) error {
+		return mb.Put(npc2.EnvConversationCommandTopic, ResumeConversationProvider(transactionId, worldId, channelId, characterId, npcId, state, outcome))
+	}
+}
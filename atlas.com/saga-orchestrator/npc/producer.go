@@ -0,0 +1,65 @@
+package npc
+
+import (
+	npc2 "atlas-saga-orchestrator/kafka/message/npc"
+	"github.com/Chronicle20/atlas-constants/channel"
+	"github.com/Chronicle20/atlas-constants/field"
+	_map "github.com/Chronicle20/atlas-constants/map"
+	"github.com/Chronicle20/atlas-constants/world"
+	"github.com/Chronicle20/atlas-kafka/producer"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+)
+
+func SpawnEscortProvider(transactionId uuid.UUID, f field.Model, npcId uint32, characterId uint32, destinationMapId _map.Id) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(characterId))
+	value := &npc2.Command[npc2.SpawnEscortCommandBody]{
+		TransactionId: transactionId,
+		WorldId:       f.WorldId(),
+		ChannelId:     f.ChannelId(),
+		MapId:         f.MapId(),
+		Instance:      f.Instance(),
+		Type:          npc2.CommandTypeSpawnEscort,
+		Body: npc2.SpawnEscortCommandBody{
+			NpcId:            npcId,
+			CharacterId:      characterId,
+			DestinationMapId: destinationMapId,
+		},
+	}
+	return producer.SingleMessageProvider(key, value)
+}
+
+func DespawnEscortProvider(transactionId uuid.UUID, f field.Model, npcId uint32, characterId uint32) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(characterId))
+	value := &npc2.Command[npc2.DespawnEscortCommandBody]{
+		TransactionId: transactionId,
+		WorldId:       f.WorldId(),
+		ChannelId:     f.ChannelId(),
+		MapId:         f.MapId(),
+		Instance:      f.Instance(),
+		Type:          npc2.CommandTypeDespawnEscort,
+		Body: npc2.DespawnEscortCommandBody{
+			NpcId:       npcId,
+			CharacterId: characterId,
+		},
+	}
+	return producer.SingleMessageProvider(key, value)
+}
+
+func ResumeConversationProvider(transactionId uuid.UUID, worldId world.Id, channelId channel.Id, characterId uint32, npcId uint32, state string, outcome map[string]any) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(characterId))
+	value := &npc2.ConversationCommand[npc2.ResumeConversationCommandBody]{
+		TransactionId: transactionId,
+		WorldId:       worldId,
+		ChannelId:     channelId,
+		CharacterId:   characterId,
+		NpcId:         npcId,
+		Type:          npc2.ConversationCommandTypeResume,
+		Body: npc2.ResumeConversationCommandBody{
+			State:   state,
+			Outcome: outcome,
+		},
+	}
+	return producer.SingleMessageProvider(key, value)
+}
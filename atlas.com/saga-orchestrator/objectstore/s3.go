@@ -0,0 +1,133 @@
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Store is storage objects are written to
+type Store interface {
+	// Put writes the object under the key, replacing any object already held under it
+	Put(ctx context.Context, key string, body []byte, contentType string) error
+}
+
+// Config configures an S3-compatible object store
+type Config struct {
+	Endpoint        string // Endpoint of the store (e.g. https://s3.us-east-1.amazonaws.com, or that of a MinIO deployment)
+	Region          string // Region requests are signed for
+	Bucket          string // Bucket objects are written to
+	AccessKeyId     string // AccessKeyId of the credentials requests are signed with
+	SecretAccessKey string // SecretAccessKey of the credentials requests are signed with
+}
+
+// S3Store writes objects to a bucket of an S3-compatible store, addressing the bucket by path so stores without virtual
+// hosted buckets are supported. Requests are signed with AWS Signature Version 4.
+type S3Store struct {
+	config Config
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewS3Store creates a store writing to the configured bucket with the client
+func NewS3Store(c Config, client *http.Client) *S3Store {
+	return &S3Store{config: c, client: client, now: time.Now}
+}
+
+// Put writes the object to the bucket under the key
+func (s *S3Store) Put(ctx context.Context, key string, body []byte, contentType string) error {
+	u := strings.TrimRight(s.config.Endpoint, "/") + "/" + uriEncode(s.config.Bucket, false) + "/" + uriEncode(key, true)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	s.sign(req, sha256Hex(body), s.now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unable to put object [%s]: status [%d]: %s", key, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// sign signs the request, whose body has the hash, with AWS Signature Version 4, over the host and each header set
+func (s *S3Store) sign(req *http.Request, payloadHash string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for k, v := range req.Header {
+		headers[strings.ToLower(k)] = strings.TrimSpace(strings.Join(v, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	canonicalHeaders := strings.Builder{}
+	for _, k := range names {
+		canonicalHeaders.WriteString(k + ":" + headers[k] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + s.config.Region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSha256([]byte("AWS4"+s.config.SecretAccessKey), date)
+	key = hmacSha256(key, s.config.Region)
+	key = hmacSha256(key, "s3")
+	key = hmacSha256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSha256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", s.config.AccessKeyId, scope, signedHeaders, signature))
+}
+
+func sha256Hex(b []byte) string {
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:])
+}
+
+func hmacSha256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// uriEncode percent-encodes every byte of the value other than unreserved characters, and, when a path, the slashes
+// separating its segments, as signing requires
+func uriEncode(v string, path bool) string {
+	b := strings.Builder{}
+	for i := 0; i < len(v); i++ {
+		c := v[i]
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c == '-' || c == '_' || c == '.' || c == '~' || (path && c == '/') {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
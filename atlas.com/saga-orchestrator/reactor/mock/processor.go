@@ -0,0 +1,51 @@
+package mock
+
+import (
+	"atlas-saga-orchestrator/kafka/message"
+	"github.com/Chronicle20/atlas-constants/field"
+	"github.com/google/uuid"
+)
+
+// ProcessorMock is a mock implementation of the reactor.Processor interface
+type ProcessorMock struct {
+	HitAndEmitFunc         func(transactionId uuid.UUID, f field.Model, reactorId uint32, characterId uint32, stance uint16, skillId uint32) error
+	HitFunc                func(mb *message.Buffer) func(transactionId uuid.UUID, f field.Model, reactorId uint32, characterId uint32, stance uint16, skillId uint32) error
+	ChangeStateAndEmitFunc func(transactionId uuid.UUID, f field.Model, reactorId uint32, state int8) error
+	ChangeStateFunc        func(mb *message.Buffer) func(transactionId uuid.UUID, f field.Model, reactorId uint32, state int8) error
+}
+
+// HitAndEmit is a mock implementation of the reactor.Processor.HitAndEmit method
+func (m *ProcessorMock) HitAndEmit(transactionId uuid.UUID, f field.Model, reactorId uint32, characterId uint32, stance uint16, skillId uint32) error {
+	if m.HitAndEmitFunc != nil {
+		return m.HitAndEmitFunc(transactionId, f, reactorId, characterId, stance, skillId)
+	}
+	return nil
+}
+
+// Hit is a mock implementation of the reactor.Processor.Hit method
+func (m *ProcessorMock) Hit(mb *message.Buffer) func(transactionId uuid.UUID, f field.Model, reactorId uint32, characterId uint32, stance uint16, skillId uint32) error {
+	if m.HitFunc != nil {
+		return m.HitFunc(mb)
+	}
+	return func(transactionId uuid.UUID, f field.Model, reactorId uint32, characterId uint32, stance uint16, skillId uint32) error {
+		return nil
+	}
+}
+
+// ChangeStateAndEmit is a mock implementation of the reactor.Processor.ChangeStateAndEmit method
+func (m *ProcessorMock) ChangeStateAndEmit(transactionId uuid.UUID, f field.Model, reactorId uint32, state int8) error {
+	if m.ChangeStateAndEmitFunc != nil {
+		return m.ChangeStateAndEmitFunc(transactionId, f, reactorId, state)
+	}
+	return nil
+}
+
+// ChangeState is a mock implementation of the reactor.Processor.ChangeState method
+func (m *ProcessorMock) ChangeState(mb *message.Buffer) func(transactionId uuid.UUID, f field.Model, reactorId uint32, state int8) error {
+	if m.ChangeStateFunc != nil {
+		return m.ChangeStateFunc(mb)
+	}
+	return func(transactionId uuid.UUID, f field.Model, reactorId uint32, state int8) error {
+		return nil
+	}
+}
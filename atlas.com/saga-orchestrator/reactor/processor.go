@@ -0,0 +1,59 @@
+package reactor
+
+import (
+	"atlas-saga-orchestrator/kafka/message"
+	reactor2 "atlas-saga-orchestrator/kafka/message/reactor"
+	"atlas-saga-orchestrator/kafka/producer"
+	"context"
+	"github.com/Chronicle20/atlas-constants/field"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type Processor interface {
+	HitAndEmit(transactionId uuid.UUID, f field.Model, reactorId uint32, characterId uint32, stance uint16, skillId uint32) error
+	Hit(mb *message.Buffer) func(transactionId uuid.UUID, f field.Model, reactorId uint32, characterId uint32, stance uint16, skillId uint32) error
+	ChangeStateAndEmit(transactionId uuid.UUID, f field.Model, reactorId uint32, state int8) error
+	ChangeState(mb *message.Buffer) func(transactionId uuid.UUID, f field.Model, reactorId uint32, state int8) error
+}
+
+type ProcessorImpl struct {
+	l   logrus.FieldLogger
+	ctx context.Context
+	p   producer.Provider
+}
+
+func NewProcessor(l logrus.FieldLogger, ctx context.Context) Processor {
+	return &ProcessorImpl{
+		l:   l,
+		ctx: ctx,
+		p:   producer.ProviderImpl(l)(ctx),
+	}
+}
+
+// HitAndEmit requests the reactor be hit by the character, as though struck in the field, advancing it through its
+// scripted states
+func (p *ProcessorImpl) HitAndEmit(transactionId uuid.UUID, f field.Model, reactorId uint32, characterId uint32, stance uint16, skillId uint32) error {
+	return message.Emit(p.p)(func(mb *message.Buffer) error {
+		return p.Hit(mb)(transactionId, f, reactorId, characterId, stance, skillId)
+	})
+}
+
+func (p *ProcessorImpl) Hit(mb *message.Buffer) func(transactionId uuid.UUID, f field.Model, reactorId uint32, characterId uint32, stance uint16, skillId uint32) error {
+	return func(transactionId uuid.UUID, f field.Model, reactorId uint32, characterId uint32, stance uint16, skillId uint32) error {
+		return mb.Put(reactor2.EnvCommandTopic, HitProvider(transactionId, f, reactorId, characterId, stance, skillId))
+	}
+}
+
+// ChangeStateAndEmit requests the reactor transition directly to the state
+func (p *ProcessorImpl) ChangeStateAndEmit(transactionId uuid.UUID, f field.Model, reactorId uint32, state int8) error {
+	return message.Emit(p.p)(func(mb *message.Buffer) error {
+		return p.ChangeState(mb)(transactionId, f, reactorId, state)
+	})
+}
+
+func (p *ProcessorImpl) ChangeState(mb *message.Buffer) func(transactionId uuid.UUID, f field.Model, reactorId uint32, state int8) error {
+	return func(transactionId uuid.UUID, f field.Model, reactorId uint32, state int8) error {
+		return mb.Put(reactor2.EnvCommandTopic, ChangeStateProvider(transactionId, f, reactorId, state))
+	}
+}
@@ -0,0 +1,46 @@
+package reactor
+
+import (
+	reactor2 "atlas-saga-orchestrator/kafka/message/reactor"
+	"github.com/Chronicle20/atlas-constants/field"
+	"github.com/Chronicle20/atlas-kafka/producer"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+)
+
+func HitProvider(transactionId uuid.UUID, f field.Model, reactorId uint32, characterId uint32, stance uint16, skillId uint32) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(reactorId))
+	value := &reactor2.Command[reactor2.HitCommandBody]{
+		TransactionId: transactionId,
+		WorldId:       f.WorldId(),
+		ChannelId:     f.ChannelId(),
+		MapId:         f.MapId(),
+		Instance:      f.Instance(),
+		Type:          reactor2.CommandTypeHit,
+		Body: reactor2.HitCommandBody{
+			ReactorId:   reactorId,
+			CharacterId: characterId,
+			Stance:      stance,
+			SkillId:     skillId,
+		},
+	}
+	return producer.SingleMessageProvider(key, value)
+}
+
+func ChangeStateProvider(transactionId uuid.UUID, f field.Model, reactorId uint32, state int8) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(reactorId))
+	value := &reactor2.Command[reactor2.ChangeStateCommandBody]{
+		TransactionId: transactionId,
+		WorldId:       f.WorldId(),
+		ChannelId:     f.ChannelId(),
+		MapId:         f.MapId(),
+		Instance:      f.Instance(),
+		Type:          reactor2.CommandTypeChangeState,
+		Body: reactor2.ChangeStateCommandBody{
+			ReactorId: reactorId,
+			State:     state,
+		},
+	}
+	return producer.SingleMessageProvider(key, value)
+}
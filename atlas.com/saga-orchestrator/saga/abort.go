@@ -0,0 +1,58 @@
+package saga
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrorCodeSagaAborted is the error code recorded against the latest attempt of the step a saga is aborted at. As with
+// a timed out step, the attempt is abandoned without its outcome being known, so its action is reversed as though it
+// took effect.
+const ErrorCodeSagaAborted = "SAGA_ABORTED"
+
+// ErrSagaNotAbortable is returned when aborting a saga which is already compensating or has no step remaining
+var ErrSagaNotAbortable = errors.New("saga is not abortable")
+
+// Abort forcibly fails the current step of a saga, such as one awaiting a service which will never respond, so the saga
+// is compensated. The step's error handlers are bypassed, and a hold on the saga is released.
+// The operator and reason are recorded as a note on the saga.
+func (p *ProcessorImpl) Abort(transactionId uuid.UUID, operator string, reason string) error {
+	s, err := p.GetById(transactionId)
+	if err != nil {
+		return err
+	}
+	idx := s.FindEarliestPendingStepIndex()
+	if s.Failing() || idx == -1 {
+		return ErrSagaNotAbortable
+	}
+
+	comment := "Aborted."
+	if reason != "" {
+		comment = "Aborted: " + reason
+	}
+	s.Notes = append(append([]Note{}, s.Notes...), Note{Author: operator, Comment: comment, CreatedAt: time.Now()})
+	s.Hold = ""
+	s.HoldReason = ""
+	s.Steps = append([]Step[any]{}, s.Steps...)
+	// Steps held before being dispatched have no attempt to record the abort against
+	_ = s.AbandonStepAttempt(idx, ErrorCodeSagaAborted, comment)
+	GetTimerRegistry().Cancel(p.t.Id(), s.TransactionId)
+	GetRetryQueue().Remove(p.t.Id(), s.TransactionId)
+	GetCache().Put(p.t.Id(), s)
+
+	p.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        s.Steps[idx].StepId,
+		"operator":       operator,
+		"tenant_id":      p.t.Id().String(),
+	}).Warnf("Saga aborted: %s", reason)
+
+	if err = p.MarkEarliestPendingStep(transactionId, Failed); err != nil {
+		return err
+	}
+	return p.Step(transactionId)
+}
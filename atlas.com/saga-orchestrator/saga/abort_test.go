@@ -0,0 +1,102 @@
+package saga
+
+import (
+	"atlas-saga-orchestrator/character/mock"
+	mock2 "atlas-saga-orchestrator/validation/mock"
+	"testing"
+	"time"
+
+	"github.com/Chronicle20/atlas-constants/channel"
+	"github.com/Chronicle20/atlas-constants/world"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAbort tests that aborting a saga fails its current step, bypassing its error handlers, and compensates it as a
+// step which may have taken effect
+func TestAbort(t *testing.T) {
+	te, ctx := setupContext()
+
+	var amounts []int32
+	charP := &mock.ProcessorMock{
+		AwardMesosAndEmitFunc: func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, actorId uint32, actorType string, amount int32) error {
+			amounts = append(amounts, amount)
+			return nil
+		},
+	}
+	processor, _ := setupTestProcessor(ctx, charP, nil, &mock2.ProcessorMock{})
+
+	deduct := func(amount uint32) DeductMesosPayload {
+		return DeductMesosPayload{CharacterId: 12345, ActorType: "SYSTEM", Amount: amount}
+	}
+
+	t.Run("pending saga is compensated", func(t *testing.T) {
+		amounts = nil
+		s := NewBuilder().
+			SetSagaType(InventoryTransaction).
+			AddStep("first", Completed, DeductMesos, deduct(100)).
+			AddStep("second", Pending, DeductMesos, deduct(200)).
+			Build()
+		s.Steps[1].OnError = []ErrorHandler{{ErrorCode: ErrorCodeSagaAborted, Reaction: ErrorReactionSkip}}
+		done, unsubscribe := GetNotifier().Subscribe(te.Id(), s.TransactionId)
+		defer unsubscribe()
+		require.NoError(t, processor.Put(s))
+		defer GetCache().Remove(te.Id(), s.TransactionId)
+		require.Equal(t, []int32{-200}, amounts)
+
+		require.NoError(t, processor.Abort(s.TransactionId, "gm-alice", "service lost the request"))
+		select {
+		case s = <-done:
+		case <-time.After(time.Second):
+			t.Fatal("aborted saga did not fail")
+		}
+		// The aborted deduction may have taken effect, so is refunded
+		assert.Equal(t, []int32{-200, 200}, amounts)
+		require.NotNil(t, s.Receipt)
+		assert.Equal(t, "second", s.Receipt.FailedStepId)
+		assert.Equal(t, ErrorCodeSagaAborted, s.Receipt.ErrorCode)
+		require.NotEmpty(t, s.Steps[1].Attempts)
+		assert.Equal(t, ErrorCodeSagaAborted, s.Steps[1].Attempts[len(s.Steps[1].Attempts)-1].ErrorCode)
+		assert.True(t, s.Steps[1].Attempts[len(s.Steps[1].Attempts)-1].Abandoned)
+		require.Len(t, s.Notes, 1)
+		assert.Equal(t, "gm-alice", s.Notes[0].Author)
+		assert.Equal(t, "Aborted: service lost the request", s.Notes[0].Comment)
+	})
+
+	t.Run("held saga is released", func(t *testing.T) {
+		s := NewBuilder().
+			SetSagaType(TutorialCompletion).
+			AddStep("gate", Pending, AwaitEvent, AwaitEventPayload{CharacterId: 12345, Event: AwaitedEventMapEntry, MapId: 10000}).
+			Build()
+		s.Hold = PendingApproval
+		done, unsubscribe := GetNotifier().Subscribe(te.Id(), s.TransactionId)
+		defer unsubscribe()
+		GetCache().Put(te.Id(), s)
+		defer GetCache().Remove(te.Id(), s.TransactionId)
+
+		require.NoError(t, processor.Abort(s.TransactionId, "gm-alice", ""))
+		select {
+		case s = <-done:
+		case <-time.After(time.Second):
+			t.Fatal("aborted saga did not fail")
+		}
+		assert.False(t, s.Held())
+		require.NotNil(t, s.Receipt)
+		assert.Equal(t, "gate", s.Receipt.FailedStepId)
+		require.Len(t, s.Notes, 1)
+		assert.Equal(t, "Aborted.", s.Notes[0].Comment)
+	})
+
+	t.Run("compensating saga is not abortable", func(t *testing.T) {
+		s := NewBuilder().
+			SetSagaType(InventoryTransaction).
+			AddStep("first", Completed, DeductMesos, deduct(100)).
+			AddStep("second", Failed, DeductMesos, deduct(200)).
+			Build()
+		GetCache().Put(te.Id(), s)
+		defer GetCache().Remove(te.Id(), s.TransactionId)
+
+		assert.ErrorIs(t, processor.Abort(s.TransactionId, "gm-alice", ""), ErrSagaNotAbortable)
+	})
+}
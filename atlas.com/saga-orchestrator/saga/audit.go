@@ -0,0 +1,169 @@
+package saga
+
+import (
+	"atlas-saga-orchestrator/compartment"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Chronicle20/atlas-constants/inventory"
+	"github.com/Chronicle20/atlas-constants/item"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrInvalidAudit is returned when a saga declares an audit_inventory step which is not its last
+var ErrInvalidAudit = errors.New("invalid inventory audit")
+
+// InventoryDiscrepancy records an item whose quantity held after a saga differs from what its steps should have left
+type InventoryDiscrepancy struct {
+	TemplateId    uint32 `json:"templateId"`    // TemplateId of the item
+	Baseline      uint32 `json:"baseline"`      // Quantity held when the saga was started
+	ExpectedDelta int64  `json:"expectedDelta"` // Net change in quantity made by the saga's completed steps
+	ActualDelta   int64  `json:"actualDelta"`   // Net change in quantity held since the saga was started
+}
+
+// ReconciliationReport records the outcome of auditing a character's inventory after a saga, rather than trusting the
+// events of its steps
+type ReconciliationReport struct {
+	Reconciled    bool                   `json:"reconciled"`           // Whether every audited item changed as expected
+	Discrepancies []InventoryDiscrepancy `json:"discrepancies"`        // Items which did not change as expected
+	Unverified    []uint32               `json:"unverified,omitempty"` // Items without a baseline, such as those awarded by payload templates, which could not be audited
+	AuditedAt     time.Time              `json:"auditedAt"`            // Timestamp of the audit
+}
+
+// ValidateAudit checks an audit_inventory step, if any, is the saga's last, so it audits every step before it
+func (s Saga) ValidateAudit() error {
+	for i, st := range s.Steps {
+		if st.Action == AuditInventory && i != len(s.Steps)-1 {
+			return fmt.Errorf("%w: step '%s' must be the last step", ErrInvalidAudit, st.StepId)
+		}
+	}
+	return nil
+}
+
+// inventoryDeltas returns the net change in quantity of each item the steps make to the character's inventory
+func inventoryDeltas(steps []Step[any], characterId uint32) map[uint32]int64 {
+	r := make(map[uint32]int64)
+	add := func(cid uint32, templateId uint32, quantity int64) {
+		if cid == characterId && templateId != 0 {
+			r[templateId] += quantity
+		}
+	}
+	for _, st := range steps {
+		switch payload := st.Payload.(type) {
+		case AwardItemActionPayload:
+			add(payload.CharacterId, payload.Item.TemplateId, int64(payload.Item.Quantity))
+		case AwardAssetIfPayload:
+			add(payload.CharacterId, payload.Item.TemplateId, int64(payload.Item.Quantity))
+		case CreateAndEquipAssetPayload:
+			add(payload.CharacterId, payload.Item.TemplateId, 1)
+		case DestroyAssetPayload:
+			add(payload.CharacterId, payload.TemplateId, -int64(payload.Quantity))
+		case VerifyAndConsumeTicketPayload:
+			quantity := payload.Quantity
+			if quantity == 0 {
+				quantity = 1
+			}
+			add(payload.CharacterId, payload.TemplateId, -int64(quantity))
+		}
+	}
+	return r
+}
+
+// heldQuantities returns the quantity of each of the items the character holds, retrieving each compartment once
+func heldQuantities(compP compartment.Processor, characterId uint32, templateIds []uint32) (map[uint32]uint32, error) {
+	r := make(map[uint32]uint32)
+	compartments := make(map[inventory.Type]compartment.Model)
+	for _, templateId := range templateIds {
+		it, ok := inventory.TypeFromItemId(item.Id(templateId))
+		if !ok {
+			continue
+		}
+		c, ok := compartments[it]
+		if !ok {
+			var err error
+			c, err = compP.GetByType(characterId, it)
+			if err != nil {
+				return nil, err
+			}
+			compartments[it] = c
+		}
+		r[templateId] = 0
+		for _, a := range c.Assets() {
+			if a.TemplateId() == templateId {
+				r[templateId] += a.Quantity()
+			}
+		}
+	}
+	return r, nil
+}
+
+func templateIds(deltas map[uint32]int64) []uint32 {
+	r := make([]uint32, 0, len(deltas))
+	for templateId := range deltas {
+		r = append(r, templateId)
+	}
+	sort.Slice(r, func(i, j int) bool { return r[i] < r[j] })
+	return r
+}
+
+// recordAuditBaseline records the quantities the character holds of the items the saga's steps change on its
+// audit_inventory step, if any, so the audit may compare against them once the steps have run. A baseline which cannot
+// be retrieved is not recorded, leaving the items unverified, as the audit does not affect the saga's outcome.
+func (p *ProcessorImpl) recordAuditBaseline(s *Saga) {
+	idx := len(s.Steps) - 1
+	if idx < 0 || s.Steps[idx].Action != AuditInventory {
+		return
+	}
+	payload, ok := s.Steps[idx].Payload.(AuditInventoryPayload)
+	if !ok || payload.Baseline != nil {
+		return
+	}
+
+	baseline, err := heldQuantities(p.compP, payload.CharacterId, templateIds(inventoryDeltas(s.Steps[:idx], payload.CharacterId)))
+	if err != nil {
+		p.l.WithFields(logrus.Fields{
+			"transaction_id": s.TransactionId.String(),
+			"saga_type":      s.SagaType,
+			"step_id":        s.Steps[idx].StepId,
+			"character_id":   payload.CharacterId,
+			"tenant_id":      p.t.Id().String(),
+		}).WithError(err).Warn("Unable to record inventory audit baseline.")
+		return
+	}
+	payload.Baseline = baseline
+	s.Steps[idx].Payload = payload
+}
+
+// auditedItems returns the items audited, being those in the baseline, whose steps may have been skipped, and those the
+// completed steps changed
+func auditedItems(baseline map[uint32]uint32, deltas map[uint32]int64) []uint32 {
+	items := make(map[uint32]int64, len(deltas))
+	for templateId := range baseline {
+		items[templateId] = 0
+	}
+	for templateId, delta := range deltas {
+		items[templateId] = delta
+	}
+	return templateIds(items)
+}
+
+// NewReconciliationReport compares the quantities the character holds against the baseline and the net change made by
+// the completed steps
+func NewReconciliationReport(baseline map[uint32]uint32, deltas map[uint32]int64, held map[uint32]uint32) ReconciliationReport {
+	r := ReconciliationReport{Reconciled: true, Discrepancies: make([]InventoryDiscrepancy, 0), AuditedAt: time.Now()}
+	for _, templateId := range auditedItems(baseline, deltas) {
+		b, ok := baseline[templateId]
+		if !ok {
+			r.Unverified = append(r.Unverified, templateId)
+			continue
+		}
+		actual := int64(held[templateId]) - int64(b)
+		if actual != deltas[templateId] {
+			r.Reconciled = false
+			r.Discrepancies = append(r.Discrepancies, InventoryDiscrepancy{TemplateId: templateId, Baseline: b, ExpectedDelta: deltas[templateId], ActualDelta: actual})
+		}
+	}
+	return r
+}
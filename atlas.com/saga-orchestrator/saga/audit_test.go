@@ -0,0 +1,126 @@
+package saga
+
+import (
+	"atlas-saga-orchestrator/asset"
+	"atlas-saga-orchestrator/compartment"
+	mock2 "atlas-saga-orchestrator/compartment/mock"
+	"github.com/Chronicle20/atlas-constants/inventory"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+// etcCompartment builds an etc compartment holding the given quantities of items, by template
+func etcCompartment(held map[uint32]uint32) compartment.Model {
+	id := uuid.New()
+	b := compartment.NewBuilder(id, 12345, inventory.TypeValueETC, 24)
+	assetId := uint32(1)
+	for templateId, quantity := range held {
+		b.AddAsset(asset.NewBuilder[any](assetId, id, templateId, assetId, asset.ReferenceTypeEtc).
+			SetSlot(int16(assetId)).
+			SetReferenceData(asset.NewEtcReferenceDataBuilder().SetQuantity(quantity).Build()).
+			Build())
+		assetId++
+	}
+	return b.Build()
+}
+
+// TestValidateAudit tests that an inventory audit must be the saga's last step
+func TestValidateAudit(t *testing.T) {
+	s := NewBuilder().
+		SetSagaType(QuestReward).
+		AddStep("audit", Pending, AuditInventory, AuditInventoryPayload{CharacterId: 12345}).
+		AddStep("give_item", Pending, AwardAsset, AwardItemActionPayload{CharacterId: 12345, Item: ItemPayload{TemplateId: 4000000, Quantity: 5}}).
+		Build()
+	assert.ErrorIs(t, s.ValidateAudit(), ErrInvalidAudit)
+
+	s.Steps = []Step[any]{s.Steps[1], s.Steps[0]}
+	assert.NoError(t, s.ValidateAudit())
+}
+
+// TestAuditInventory tests that the audit compares the net change the saga's steps should have made to the character's
+// inventory against what the character holds, reporting discrepancies without failing the saga
+func TestAuditInventory(t *testing.T) {
+	tests := []struct {
+		name          string
+		after         map[uint32]uint32
+		reconciled    bool
+		discrepancies []InventoryDiscrepancy
+	}{
+		{
+			name:       "Inventory changed as expected",
+			after:      map[uint32]uint32{4000000: 7, 4000001: 1},
+			reconciled: true,
+		},
+		{
+			name:          "Award did not materialize",
+			after:         map[uint32]uint32{4000000: 2, 4000001: 1},
+			discrepancies: []InventoryDiscrepancy{{TemplateId: 4000000, Baseline: 2, ExpectedDelta: 5, ActualDelta: 0}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			te, ctx := setupContext()
+			held := map[uint32]uint32{4000000: 2, 4000001: 4}
+			compP := &mock2.ProcessorMock{
+				GetByTypeFunc: func(characterId uint32, inventoryType inventory.Type) (compartment.Model, error) {
+					assert.Equal(t, inventory.TypeValueETC, inventoryType)
+					return etcCompartment(held), nil
+				},
+				RequestCreateItemFunc: func(transactionId uuid.UUID, characterId uint32, templateId uint32, quantity uint32) error {
+					return nil
+				},
+				RequestDestroyItemFunc: func(transactionId uuid.UUID, characterId uint32, templateId uint32, quantity uint32) error {
+					return nil
+				},
+			}
+			processor, _ := setupTestProcessor(ctx, nil, compP)
+
+			s := NewBuilder().
+				SetSagaType(QuestReward).
+				AddStep("give_item", Pending, AwardAsset, AwardItemActionPayload{CharacterId: 12345, Item: ItemPayload{TemplateId: 4000000, Quantity: 5}}).
+				AddStep("take_item", Pending, DestroyAsset, DestroyAssetPayload{CharacterId: 12345, TemplateId: 4000001, Quantity: 3}).
+				AddStep("audit", Pending, AuditInventory, AuditInventoryPayload{CharacterId: 12345}).
+				Build()
+			defer GetCache().Remove(te.Id(), s.TransactionId)
+			require.NoError(t, processor.Put(s))
+
+			cs, err := processor.GetById(s.TransactionId)
+			require.NoError(t, err)
+			assert.Equal(t, map[uint32]uint32{4000000: 2, 4000001: 4}, cs.Steps[2].Payload.(AuditInventoryPayload).Baseline)
+
+			c, unsubscribe := GetNotifier().Subscribe(te.Id(), s.TransactionId)
+			defer unsubscribe()
+			held = tc.after
+			require.NoError(t, processor.StepCompleted(s.TransactionId, true))
+			require.NoError(t, processor.StepCompleted(s.TransactionId, true))
+
+			select {
+			case cs = <-c:
+			case <-time.After(time.Second):
+				t.Fatal("saga did not complete")
+			}
+			assert.False(t, cs.Failing())
+			assert.Equal(t, Completed, cs.Steps[2].Status)
+			r := cs.Steps[2].Payload.(AuditInventoryPayload).Report
+			require.NotNil(t, r)
+			assert.Equal(t, tc.reconciled, r.Reconciled)
+			if tc.discrepancies == nil {
+				assert.Empty(t, r.Discrepancies)
+			} else {
+				assert.Equal(t, tc.discrepancies, r.Discrepancies)
+			}
+			assert.Empty(t, r.Unverified)
+		})
+	}
+}
+
+// TestNewReconciliationReport tests that items without a baseline are reported as unverified
+func TestNewReconciliationReport(t *testing.T) {
+	r := NewReconciliationReport(map[uint32]uint32{4000000: 2}, map[uint32]int64{4000000: 5, 4000001: 1}, map[uint32]uint32{4000000: 7, 4000001: 1})
+	assert.True(t, r.Reconciled)
+	assert.Equal(t, []uint32{4000001}, r.Unverified)
+}
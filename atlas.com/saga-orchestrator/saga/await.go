@@ -0,0 +1,43 @@
+package saga
+
+import (
+	_map "github.com/Chronicle20/atlas-constants/map"
+	"github.com/sirupsen/logrus"
+)
+
+// Constants for the events an await_event step may await
+const (
+	AwaitedEventMapEntry = "map_entry" // The character entering the step's map, however they came to
+)
+
+// MapEntered completes each of the tenant's await_event steps which are current and await the character entering the
+// map. Sagas which are compensating or held do not progress, so their steps are left awaiting.
+func (p *ProcessorImpl) MapEntered(characterId uint32, mapId _map.Id) error {
+	for _, s := range GetCache().GetAll(p.t.Id()) {
+		if s.Failing() || s.Held() {
+			continue
+		}
+		st, ok := s.GetCurrentStep()
+		if !ok || st.Action != AwaitEvent {
+			continue
+		}
+		payload, ok := st.Payload.(AwaitEventPayload)
+		if !ok || payload.Event != AwaitedEventMapEntry || payload.CharacterId != characterId || payload.MapId != mapId {
+			continue
+		}
+
+		fl := p.l.WithFields(logrus.Fields{
+			"transaction_id": s.TransactionId.String(),
+			"saga_type":      s.SagaType,
+			"step_id":        st.StepId,
+			"character_id":   characterId,
+			"map_id":         mapId,
+			"tenant_id":      p.t.Id().String(),
+		})
+		fl.Debug("Character entered awaited map. Completing step.")
+		if err := p.StepCompleted(s.TransactionId, true); err != nil {
+			fl.WithError(err).Error("Unable to complete step awaiting map entry.")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package saga
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAwaitMapEntry tests that an await_event step completes once the character enters the awaited map, ignoring the
+// entries of other characters and into other maps
+func TestAwaitMapEntry(t *testing.T) {
+	te, ctx := setupContext()
+	processor, _ := setupTestProcessor(ctx, nil, nil)
+
+	s := NewBuilder().
+		SetSagaType(TutorialCompletion).
+		AddStep("gate", Pending, AwaitEvent, AwaitEventPayload{CharacterId: 12345, Event: AwaitedEventMapEntry, MapId: 10000}).
+		AddStep("done", Pending, SetVariable, SetVariablePayload{Name: "done", Value: true}).
+		Build()
+	done, unsubscribe := GetNotifier().Subscribe(te.Id(), s.TransactionId)
+	defer unsubscribe()
+	require.NoError(t, processor.Put(s))
+	defer GetCache().Remove(te.Id(), s.TransactionId)
+
+	require.NoError(t, processor.MapEntered(67890, 10000))
+	require.NoError(t, processor.MapEntered(12345, 20000))
+	s, err := processor.GetById(s.TransactionId)
+	require.NoError(t, err)
+	assert.Equal(t, Pending, s.Steps[0].Status)
+
+	require.NoError(t, processor.MapEntered(12345, 10000))
+	select {
+	case s = <-done:
+	case <-time.After(time.Second):
+		t.Fatal("saga did not complete")
+	}
+	assert.Equal(t, Completed, s.Steps[0].Status)
+	assert.Equal(t, true, s.Variables["done"])
+}
@@ -0,0 +1,187 @@
+package saga
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// ErrInvalidBranch is returned when a step declares branches which cannot be selected between
+var ErrInvalidBranch = errors.New("invalid branch")
+
+// Branch declares steps which execute when the step declaring it, the decision step, completes and the branch is
+// selected. Branches are considered in order, and the first whose condition holds is selected. Only the steps of the
+// selected branch are added to the saga, so only they participate in compensation.
+type Branch struct {
+	Name   string      `json:"name"`             // Name of the branch, recorded on the decision step when selected
+	When   string      `json:"when,omitempty"`   // JSONPath expression evaluated as payload templates are (e.g. $.steps.draw.resolved.mesos). Omitted by a final default branch.
+	Equals any         `json:"equals,omitempty"` // Value the expression must equal for the branch to be selected. When omitted, the expression must be truthy.
+	Steps  []Step[any] `json:"steps,omitempty"`  // Steps executed directly after the decision step when the branch is selected
+}
+
+// ValidateBranches validates the branches declared by the saga's steps, and those nested within them. Branches must be
+// named uniquely, conditions must be JSONPath expressions, only the last branch may omit its condition, and branch
+// step IDs may not collide with the saga's own.
+func (s Saga) ValidateBranches() error {
+	ids := make(map[string]bool, len(s.Steps))
+	for _, st := range s.Steps {
+		ids[st.StepId] = true
+	}
+	for _, st := range s.Steps {
+		if err := validateBranches(st, ids); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateBranches(st Step[any], ids map[string]bool) error {
+	names := make(map[string]bool, len(st.Branches))
+	for i, b := range st.Branches {
+		if b.Name == "" || names[b.Name] {
+			return fmt.Errorf("%w: step '%s' branch [%d] must have a unique name", ErrInvalidBranch, st.StepId, i)
+		}
+		names[b.Name] = true
+		if b.When == "" && i != len(st.Branches)-1 {
+			return fmt.Errorf("%w: step '%s' branch '%s' without a condition must be last", ErrInvalidBranch, st.StepId, b.Name)
+		}
+		if b.When != "" && !strings.HasPrefix(b.When, jsonPathPrefix) {
+			return fmt.Errorf("%w: step '%s' branch '%s' condition '%s' is not a JSONPath expression", ErrInvalidBranch, st.StepId, b.Name, b.When)
+		}
+
+		scope := make(map[string]bool, len(ids)+len(b.Steps))
+		for k := range ids {
+			scope[k] = true
+		}
+		for _, bs := range b.Steps {
+			if bs.StepId == "" || scope[bs.StepId] {
+				return fmt.Errorf("%w: step '%s' branch '%s' step ID '%s' is not unique", ErrInvalidBranch, st.StepId, b.Name, bs.StepId)
+			}
+			if bs.Action == "" {
+				return fmt.Errorf("%w: step '%s' branch '%s' step '%s' has no action", ErrInvalidBranch, st.StepId, b.Name, bs.StepId)
+			}
+			scope[bs.StepId] = true
+		}
+		for _, bs := range b.Steps {
+			if err := validateBranches(bs, scope); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// SelectBranch evaluates the branches of the step at the index against the saga, as it stands once the step has
+// completed, returning the first whose condition holds. Conditions referencing values which do not exist are evaluated
+// against null, so a branch may test for an outcome which was not recorded.
+func (s Saga) SelectBranch(index int) (Branch, bool, error) {
+	if index < 0 || index >= len(s.Steps) {
+		return Branch{}, false, fmt.Errorf("step index %d out of range", index)
+	}
+	st := s.Steps[index]
+	if len(st.Branches) == 0 {
+		return Branch{}, false, nil
+	}
+
+	// The decision step is evaluated along with the steps preceding it, exposing the outcome it recorded
+	d := s
+	d.Steps = s.Steps[:index+1]
+	ctx, err := templateContext(d, "")
+	if err != nil {
+		return Branch{}, false, err
+	}
+
+	for _, b := range st.Branches {
+		if b.When == "" {
+			return b, true, nil
+		}
+		v, err := evaluateJSONPath(b.When, ctx)
+		if err != nil {
+			v = nil
+		}
+		if b.Equals == nil {
+			if truthy(v) {
+				return b, true, nil
+			}
+			continue
+		}
+		eq, err := jsonEqual(v, b.Equals)
+		if err != nil {
+			return Branch{}, false, fmt.Errorf("%w: step '%s' branch '%s': %s", ErrInvalidBranch, st.StepId, b.Name, err.Error())
+		}
+		if eq {
+			return b, true, nil
+		}
+	}
+	return Branch{}, false, nil
+}
+
+// ApplyBranch records the branch selected on the step at the index, and inserts the branch's steps directly after it
+func (s *Saga) ApplyBranch(index int, b Branch) error {
+	now := time.Now()
+	inserted := make([]Step[any], 0, len(b.Steps))
+	for _, bs := range b.Steps {
+		bs.Status = Pending
+		bs.Attempts = nil
+		bs.CreatedAt = now
+		bs.UpdatedAt = now
+		inserted = append(inserted, bs)
+	}
+
+	steps := make([]Step[any], 0, len(s.Steps)+len(inserted))
+	steps = append(steps, s.Steps[:index+1]...)
+	steps = append(steps, inserted...)
+	steps = append(steps, s.Steps[index+1:]...)
+	steps[index].Branch = b.Name
+	steps[index].UpdatedAt = now
+	s.Steps = steps
+	return s.ValidateStateConsistency()
+}
+
+// truthy returns whether a value decoded from JSON is considered true by a branch condition without a value to equal
+func truthy(v any) bool {
+	switch t := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return t
+	case float64:
+		return t != 0
+	case string:
+		return t != ""
+	case []any:
+		return len(t) > 0
+	case map[string]any:
+		return len(t) > 0
+	}
+	return true
+}
+
+// jsonEqual returns whether two values are equal once represented as they decode from JSON, so an int declared in
+// process equals the float64 it decodes as
+func jsonEqual(a any, b any) (bool, error) {
+	na, err := normalizeJSON(a)
+	if err != nil {
+		return false, err
+	}
+	nb, err := normalizeJSON(b)
+	if err != nil {
+		return false, err
+	}
+	return reflect.DeepEqual(na, nb), nil
+}
+
+func normalizeJSON(v any) (any, error) {
+	bs, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var r any
+	if err = json.Unmarshal(bs, &r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
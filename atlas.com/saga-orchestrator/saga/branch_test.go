@@ -0,0 +1,225 @@
+package saga
+
+import (
+	"atlas-saga-orchestrator/validation"
+	mock "atlas-saga-orchestrator/validation/mock"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+// TestValidateBranches tests validating the branches declared by steps
+func TestValidateBranches(t *testing.T) {
+	step := func(id string) Step[any] {
+		return Step[any]{StepId: id, Status: Pending, Action: SetVariable, Payload: SetVariablePayload{Name: "x", Value: 1}}
+	}
+	build := func(branches ...Branch) Saga {
+		b := NewBuilder().SetSagaType(QuestReward).AddStep("decide", Pending, SetVariable, SetVariablePayload{Name: "roll", Value: 1})
+		for _, br := range branches {
+			b.AddBranch(br)
+		}
+		return b.AddStep("after", Pending, SetVariable, SetVariablePayload{Name: "done", Value: true}).Build()
+	}
+
+	tests := []struct {
+		name     string
+		branches []Branch
+		errorMsg string
+	}{
+		{
+			name: "Success - conditions with a default",
+			branches: []Branch{
+				{Name: "high", When: "$.variables.roll", Equals: 6, Steps: []Step[any]{step("jackpot")}},
+				{Name: "other", Steps: []Step[any]{step("consolation")}},
+			},
+		},
+		{
+			name: "Success - branches may reuse step IDs",
+			branches: []Branch{
+				{Name: "high", When: "$.variables.roll", Steps: []Step[any]{step("award")}},
+				{Name: "other", Steps: []Step[any]{step("award")}},
+			},
+		},
+		{
+			name:     "Error - unnamed branch",
+			branches: []Branch{{When: "$.variables.roll"}},
+			errorMsg: "unique name",
+		},
+		{
+			name:     "Error - duplicate branch name",
+			branches: []Branch{{Name: "a", When: "$.variables.roll"}, {Name: "a"}},
+			errorMsg: "unique name",
+		},
+		{
+			name:     "Error - default branch not last",
+			branches: []Branch{{Name: "other"}, {Name: "high", When: "$.variables.roll"}},
+			errorMsg: "must be last",
+		},
+		{
+			name:     "Error - condition not a JSONPath expression",
+			branches: []Branch{{Name: "high", When: "variables.roll"}},
+			errorMsg: "not a JSONPath expression",
+		},
+		{
+			name:     "Error - branch step collides with saga step",
+			branches: []Branch{{Name: "high", When: "$.variables.roll", Steps: []Step[any]{step("after")}}},
+			errorMsg: "not unique",
+		},
+		{
+			name: "Error - nested branch step collides with enclosing branch step",
+			branches: []Branch{{Name: "high", When: "$.variables.roll", Steps: []Step[any]{
+				{StepId: "nested", Status: Pending, Action: SetVariable, Payload: SetVariablePayload{Name: "x", Value: 1}, Branches: []Branch{
+					{Name: "inner", Steps: []Step[any]{step("nested")}},
+				}},
+			}}},
+			errorMsg: "not unique",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := build(tt.branches...).ValidateBranches()
+			if tt.errorMsg == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, ErrInvalidBranch)
+			assert.Contains(t, err.Error(), tt.errorMsg)
+		})
+	}
+}
+
+// TestSelectBranch tests selecting between branches on the outcome recorded by a decision step
+func TestSelectBranch(t *testing.T) {
+	branches := []Branch{
+		{Name: "jackpot", When: "$.steps.draw.resolved.mesos", Equals: 1000},
+		{Name: "item", When: "$.steps.draw.resolved.item"},
+		{Name: "nothing"},
+	}
+	build := func(resolved *PrizeEntry) Saga {
+		return NewBuilder().
+			SetSagaType(QuestReward).
+			AddStep("draw", Completed, ResolvePrizeTable, ResolvePrizeTablePayload{CharacterId: 12345, Resolved: resolved}).
+			AddBranch(branches[0]).
+			AddBranch(branches[1]).
+			AddBranch(branches[2]).
+			Build()
+	}
+
+	tests := []struct {
+		name     string
+		resolved *PrizeEntry
+		expected string
+	}{
+		{name: "Value equal", resolved: &PrizeEntry{Weight: 1, Mesos: 1000}, expected: "jackpot"},
+		{name: "Value truthy", resolved: &PrizeEntry{Weight: 1, Mesos: 10, Item: &ItemPayload{TemplateId: 2000000, Quantity: 1}}, expected: "item"},
+		{name: "Value missing", resolved: &PrizeEntry{Weight: 1, Mesos: 10}, expected: "nothing"},
+		{name: "Outcome not recorded", expected: "nothing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, ok, err := build(tt.resolved).SelectBranch(0)
+			require.NoError(t, err)
+			require.True(t, ok)
+			assert.Equal(t, tt.expected, b.Name)
+		})
+	}
+
+	// Without a default branch, no branch may be selected
+	s := NewBuilder().
+		SetSagaType(QuestReward).
+		AddStep("draw", Completed, ResolvePrizeTable, ResolvePrizeTablePayload{CharacterId: 12345}).
+		AddBranch(branches[0]).
+		Build()
+	_, ok, err := s.SelectBranch(0)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+// TestBranchExecution tests that only the steps of the selected branch execute, directly after the decision step, and
+// that branches survive a round trip through JSON
+func TestBranchExecution(t *testing.T) {
+	te, ctx := setupContext()
+
+	validP := &mock.ProcessorMock{}
+	passed := true
+	validP.ValidateCharacterStateFunc = func(characterId uint32, conditions []validation.ConditionInput) (validation.ValidationResult, error) {
+		result := validation.NewValidationResult(characterId)
+		result.AddConditionResult(validation.ConditionResult{Passed: passed, Description: "Fame >= 30"})
+		return result, nil
+	}
+	processor, _ := setupTestProcessor(ctx, nil, nil, validP)
+
+	build := func() Saga {
+		s := NewBuilder().
+			SetSagaType(QuestReward).
+			AddStep("check", Pending, ValidateCharacterState, ValidateCharacterStatePayload{
+				CharacterId: 12345,
+				Conditions:  []validation.ConditionInput{{Type: "fame", Operator: ">=", Value: 30}},
+			}).
+			AddBranch(Branch{Name: "eligible", When: "$.steps.check.passed", Equals: true, Steps: []Step[any]{
+				{StepId: "reward", Status: Pending, Action: SetVariable, Payload: SetVariablePayload{Name: "reward", Value: "eligible"}},
+			}}).
+			AddBranch(Branch{Name: "ineligible", Steps: []Step[any]{
+				{StepId: "reward", Status: Pending, Action: SetVariable, Payload: SetVariablePayload{Name: "reward", Value: "ineligible"}},
+				{StepId: "consolation", Status: Pending, Action: SetVariable, Payload: SetVariablePayload{Name: "consolation", Value: true}},
+			}}).
+			AddStep("done", Pending, SetVariable, SetVariablePayload{Name: "done", Value: true}).
+			Build()
+
+		// Branches are declared through the REST and Kafka interfaces as JSON
+		bs, err := json.Marshal(s)
+		require.NoError(t, err)
+		var r Saga
+		require.NoError(t, json.Unmarshal(bs, &r))
+		return r
+	}
+	run := func(s Saga) Saga {
+		done, unsubscribe := GetNotifier().Subscribe(te.Id(), s.TransactionId)
+		defer unsubscribe()
+		require.NoError(t, processor.Put(s))
+		defer GetCache().Remove(te.Id(), s.TransactionId)
+		select {
+		case s = <-done:
+		case <-time.After(time.Second):
+			t.Fatal("saga did not complete")
+		}
+		return s
+	}
+	stepIds := func(s Saga) []string {
+		r := make([]string, 0, len(s.Steps))
+		for _, st := range s.Steps {
+			r = append(r, st.StepId)
+		}
+		return r
+	}
+
+	t.Run("validation passed", func(t *testing.T) {
+		passed = true
+		s := run(build())
+		assert.Equal(t, []string{"check", "reward", "done"}, stepIds(s))
+		assert.Equal(t, "eligible", s.Steps[0].Branch)
+		assert.Equal(t, "eligible", s.Variables["reward"])
+		assert.Equal(t, true, s.Variables["done"])
+	})
+
+	t.Run("validation failed", func(t *testing.T) {
+		passed = false
+		s := run(build())
+		assert.Equal(t, []string{"check", "reward", "consolation", "done"}, stepIds(s))
+		assert.Equal(t, "ineligible", s.Steps[0].Branch)
+		assert.Equal(t, "ineligible", s.Variables["reward"])
+		assert.Equal(t, true, s.Variables["consolation"])
+		assert.Equal(t, true, s.Variables["done"])
+	})
+
+	t.Run("invalid branches are rejected", func(t *testing.T) {
+		s := build()
+		s.Steps[0].Branches[0].When = "passed"
+		assert.True(t, errors.Is(processor.Put(s), ErrInvalidBranch))
+	})
+}
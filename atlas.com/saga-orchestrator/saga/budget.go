@@ -0,0 +1,268 @@
+package saga
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrBudgetExceeded is returned when executing a saga would exceed the tenant or initiator budget for the window
+var ErrBudgetExceeded = errors.New("saga budget exceeded")
+
+// ErrSagaQueued is returned when a saga is queued until it can be started, such as when enough budget is available
+var ErrSagaQueued = errors.New("saga queued")
+
+const (
+	// DefaultBudgetWindow is the window over which budgets are enforced when none is configured
+	DefaultBudgetWindow = time.Hour
+
+	// DefaultStepCost is the cost of a step whose action has no configured weight
+	DefaultStepCost = uint64(1)
+)
+
+// BudgetConfig configures the cost of steps and the budgets enforced over a window. A limit of 0 is unlimited.
+type BudgetConfig struct {
+	Window         time.Duration     // Window over which costs are accumulated
+	TenantLimit    uint64            // TenantLimit is the maximum cost of sagas per tenant within the window
+	InitiatorLimit uint64            // InitiatorLimit is the maximum cost of sagas per initiator within the window
+	Weights        map[Action]uint64 // Weights is the cost of a step by action, otherwise DefaultStepCost
+	Queue          bool              // Queue sagas which exceed the budget until it is available, rather than rejecting them
+}
+
+// BudgetConfigFromEnv loads the budget configuration from the environment
+func BudgetConfigFromEnv() (BudgetConfig, error) {
+	c := BudgetConfig{Window: DefaultBudgetWindow, Weights: make(map[Action]uint64)}
+
+	if v, ok := os.LookupEnv("SAGA_BUDGET_WINDOW"); ok && v != "" {
+		w, err := time.ParseDuration(v)
+		if err != nil || w <= 0 {
+			return BudgetConfig{}, fmt.Errorf("invalid SAGA_BUDGET_WINDOW '%s'", v)
+		}
+		c.Window = w
+	}
+
+	var err error
+	if c.TenantLimit, err = parseUintEnv("SAGA_BUDGET_TENANT_LIMIT"); err != nil {
+		return BudgetConfig{}, err
+	}
+	if c.InitiatorLimit, err = parseUintEnv("SAGA_BUDGET_INITIATOR_LIMIT"); err != nil {
+		return BudgetConfig{}, err
+	}
+
+	// Weights are expressed as a comma-separated list of action=cost pairs (e.g. award_mesos=5,award_asset=2)
+	if v := os.Getenv("SAGA_BUDGET_ACTION_COSTS"); v != "" {
+		for _, entry := range strings.Split(v, ",") {
+			action, cost, ok := strings.Cut(strings.TrimSpace(entry), "=")
+			if !ok || action == "" {
+				return BudgetConfig{}, fmt.Errorf("invalid SAGA_BUDGET_ACTION_COSTS entry '%s'", entry)
+			}
+			w, err := strconv.ParseUint(cost, 10, 64)
+			if err != nil {
+				return BudgetConfig{}, fmt.Errorf("invalid SAGA_BUDGET_ACTION_COSTS entry '%s'", entry)
+			}
+			c.Weights[Action(action)] = w
+		}
+	}
+
+	switch v := os.Getenv("SAGA_BUDGET_EXCEEDED"); v {
+	case "", "reject":
+	case "queue":
+		c.Queue = true
+	default:
+		return BudgetConfig{}, fmt.Errorf("invalid SAGA_BUDGET_EXCEEDED '%s', expected reject or queue", v)
+	}
+
+	return c, nil
+}
+
+func parseUintEnv(key string) (uint64, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s '%s'", key, v)
+	}
+	return n, nil
+}
+
+// Budget is an interface for accounting the cost of sagas against per-tenant and per-initiator budgets
+type Budget interface {
+	// Cost returns the cost of executing the steps of a saga
+	Cost(s Saga) uint64
+
+	// Reserve charges the cost of a saga against its budgets. When a budget would be exceeded nothing is charged, and
+	// ErrBudgetExceeded is returned alongside how long until enough budget is available, or 0 if it never will be.
+	Reserve(tenantId uuid.UUID, s Saga) (time.Duration, error)
+
+	// Queue returns whether sagas which exceed their budget are queued rather than rejected
+	Queue() bool
+}
+
+// charge is the cost of a saga accounted at a point in time
+type charge struct {
+	at   time.Time
+	cost uint64
+}
+
+// budgetKey identifies a budget, being a tenant or an initiator within a tenant
+type budgetKey struct {
+	tenantId    uuid.UUID
+	initiator   bool
+	initiatedBy string
+}
+
+// InMemoryBudget is an in-memory, sliding window implementation of the Budget interface
+type InMemoryBudget struct {
+	config BudgetConfig
+
+	// charges is a map of budgets to the charges made against them within the window, oldest first
+	charges map[budgetKey][]charge
+
+	// now returns the current time
+	now func() time.Time
+
+	// mutex is used to synchronize access to the charges
+	mutex sync.Mutex
+}
+
+// Singleton instance of the budget, which is unlimited until initialized
+var budgetInstance Budget = NewBudget(BudgetConfig{})
+
+// NewBudget creates a new in-memory budget
+func NewBudget(config BudgetConfig) *InMemoryBudget {
+	if config.Window <= 0 {
+		config.Window = DefaultBudgetWindow
+	}
+	return &InMemoryBudget{
+		config:  config,
+		charges: make(map[budgetKey][]charge),
+		now:     time.Now,
+	}
+}
+
+// InitBudget replaces the singleton budget with one enforcing the configuration, discarding prior charges
+func InitBudget(config BudgetConfig) {
+	budgetInstance = NewBudget(config)
+}
+
+// GetBudget returns the singleton instance of the budget
+func GetBudget() Budget {
+	return budgetInstance
+}
+
+// Cost returns the sum of the weights of the saga's steps
+func (b *InMemoryBudget) Cost(s Saga) uint64 {
+	var total uint64
+	for _, st := range s.Steps {
+		if w, ok := b.config.Weights[st.Action]; ok {
+			total += w
+		} else {
+			total += DefaultStepCost
+		}
+	}
+	return total
+}
+
+// Queue returns whether sagas which exceed their budget are queued rather than rejected
+func (b *InMemoryBudget) Queue() bool {
+	return b.config.Queue
+}
+
+// Reserve charges the cost of a saga against the tenant and initiator budgets
+func (b *InMemoryBudget) Reserve(tenantId uuid.UUID, s Saga) (time.Duration, error) {
+	if b.config.TenantLimit == 0 && b.config.InitiatorLimit == 0 {
+		return 0, nil
+	}
+
+	cost := b.Cost(s)
+	limits := []struct {
+		key   budgetKey
+		limit uint64
+		name  string
+	}{
+		{budgetKey{tenantId: tenantId}, b.config.TenantLimit, "tenant"},
+		{budgetKey{tenantId: tenantId, initiator: true, initiatedBy: s.InitiatedBy}, b.config.InitiatorLimit, fmt.Sprintf("initiator [%s]", s.InitiatedBy)},
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	now := b.now()
+	var wait time.Duration
+	never := false
+	var exceeded []string
+	for _, l := range limits {
+		if l.limit == 0 {
+			continue
+		}
+		b.prune(l.key, now)
+		w, ok := b.available(l.key, l.limit, cost, now)
+		if ok {
+			continue
+		}
+		exceeded = append(exceeded, l.name)
+		if w == 0 {
+			never = true
+		} else if w > wait {
+			wait = w
+		}
+	}
+	if len(exceeded) > 0 {
+		if never {
+			wait = 0
+		}
+		return wait, fmt.Errorf("%w: cost [%d] exceeds %s budget", ErrBudgetExceeded, cost, strings.Join(exceeded, " and "))
+	}
+
+	for _, l := range limits {
+		if l.limit == 0 {
+			continue
+		}
+		b.charges[l.key] = append(b.charges[l.key], charge{at: now, cost: cost})
+	}
+	return 0, nil
+}
+
+// prune discards charges which have fallen outside the window. The caller must hold the lock.
+func (b *InMemoryBudget) prune(k budgetKey, now time.Time) {
+	cs := b.charges[k]
+	i := 0
+	for i < len(cs) && !cs[i].at.Add(b.config.Window).After(now) {
+		i++
+	}
+	if i == len(cs) {
+		delete(b.charges, k)
+		return
+	}
+	b.charges[k] = cs[i:]
+}
+
+// available returns whether the cost fits within the budget, otherwise how long until enough charges leave the window
+// for it to fit, or 0 if it never will. The caller must hold the lock.
+func (b *InMemoryBudget) available(k budgetKey, limit uint64, cost uint64, now time.Time) (time.Duration, bool) {
+	if cost > limit {
+		return 0, false
+	}
+	var used uint64
+	for _, c := range b.charges[k] {
+		used += c.cost
+	}
+	if used+cost <= limit {
+		return 0, true
+	}
+	for _, c := range b.charges[k] {
+		used -= c.cost
+		if used+cost <= limit {
+			return c.at.Add(b.config.Window).Sub(now), false
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,132 @@
+package saga
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func budgetSaga(initiatedBy string, actions ...Action) Saga {
+	s := Saga{TransactionId: uuid.New(), SagaType: QuestReward, InitiatedBy: initiatedBy}
+	for i, a := range actions {
+		s.Steps = append(s.Steps, Step[any]{StepId: fmt.Sprintf("%s_%d", a, i+1), Status: Pending, Action: a})
+	}
+	return s
+}
+
+// TestBudgetCost tests that steps are weighted by action, defaulting to a cost of one
+func TestBudgetCost(t *testing.T) {
+	b := NewBudget(BudgetConfig{Weights: map[Action]uint64{AwardMesos: 5}})
+	assert.Equal(t, uint64(7), b.Cost(budgetSaga("npc", AwardMesos, AwardAsset, AwardExperience)))
+}
+
+// TestBudgetReserve tests enforcement of tenant and initiator budgets over a sliding window
+func TestBudgetReserve(t *testing.T) {
+	tenantId := uuid.New()
+	now := time.Now()
+
+	t.Run("initiator budget is enforced per initiator", func(t *testing.T) {
+		b := NewBudget(BudgetConfig{Window: time.Minute, InitiatorLimit: 2})
+		b.now = func() time.Time { return now }
+
+		_, err := b.Reserve(tenantId, budgetSaga("npc-1", AwardMesos, AwardAsset))
+		require.NoError(t, err)
+		_, err = b.Reserve(tenantId, budgetSaga("npc-2", AwardMesos))
+		require.NoError(t, err)
+
+		wait, err := b.Reserve(tenantId, budgetSaga("npc-1", AwardMesos))
+		assert.ErrorIs(t, err, ErrBudgetExceeded)
+		assert.Contains(t, err.Error(), "initiator [npc-1]")
+		assert.Equal(t, time.Minute, wait)
+	})
+
+	t.Run("tenant budget is shared by initiators", func(t *testing.T) {
+		b := NewBudget(BudgetConfig{Window: time.Minute, TenantLimit: 2})
+		b.now = func() time.Time { return now }
+
+		_, err := b.Reserve(tenantId, budgetSaga("npc-1", AwardMesos))
+		require.NoError(t, err)
+		b.now = func() time.Time { return now.Add(10 * time.Second) }
+		_, err = b.Reserve(tenantId, budgetSaga("npc-2", AwardMesos))
+		require.NoError(t, err)
+
+		// The saga fits once the first charge leaves the window
+		wait, err := b.Reserve(tenantId, budgetSaga("npc-3", AwardMesos))
+		assert.ErrorIs(t, err, ErrBudgetExceeded)
+		assert.Contains(t, err.Error(), "tenant")
+		assert.Equal(t, 50*time.Second, wait)
+
+		// Other tenants are unaffected
+		_, err = b.Reserve(uuid.New(), budgetSaga("npc-3", AwardMesos))
+		assert.NoError(t, err)
+
+		b.now = func() time.Time { return now.Add(time.Minute) }
+		_, err = b.Reserve(tenantId, budgetSaga("npc-3", AwardMesos))
+		assert.NoError(t, err)
+	})
+
+	t.Run("saga costing more than the budget is never accommodated", func(t *testing.T) {
+		b := NewBudget(BudgetConfig{TenantLimit: 2})
+		wait, err := b.Reserve(tenantId, budgetSaga("npc-1", AwardMesos, AwardMesos, AwardMesos))
+		assert.ErrorIs(t, err, ErrBudgetExceeded)
+		assert.Zero(t, wait)
+	})
+}
+
+// TestBudgetConfigFromEnv tests loading the budget configuration from the environment
+func TestBudgetConfigFromEnv(t *testing.T) {
+	t.Setenv("SAGA_BUDGET_WINDOW", "10m")
+	t.Setenv("SAGA_BUDGET_TENANT_LIMIT", "1000")
+	t.Setenv("SAGA_BUDGET_INITIATOR_LIMIT", "100")
+	t.Setenv("SAGA_BUDGET_ACTION_COSTS", "award_mesos=5, award_asset=2")
+	t.Setenv("SAGA_BUDGET_EXCEEDED", "queue")
+
+	c, err := BudgetConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, 10*time.Minute, c.Window)
+	assert.Equal(t, uint64(1000), c.TenantLimit)
+	assert.Equal(t, uint64(100), c.InitiatorLimit)
+	assert.Equal(t, map[Action]uint64{AwardMesos: 5, AwardAsset: 2}, c.Weights)
+	assert.True(t, c.Queue)
+
+	t.Setenv("SAGA_BUDGET_ACTION_COSTS", "award_mesos")
+	_, err = BudgetConfigFromEnv()
+	assert.Error(t, err)
+}
+
+// TestPutBudgetExceeded tests that sagas exceeding their budget are rejected, or queued until budget is available
+func TestPutBudgetExceeded(t *testing.T) {
+	te, ctx := setupContext()
+	processor, _ := setupTestProcessor(ctx, nil, nil)
+	defer InitBudget(BudgetConfig{})
+
+	InitBudget(BudgetConfig{InitiatorLimit: 1})
+	s := budgetSaga("npc-1", AwardMesos, AwardMesos)
+	err := processor.Put(s)
+	assert.ErrorIs(t, err, ErrBudgetExceeded)
+	_, ok := GetCache().GetById(te.Id(), s.TransactionId)
+	assert.False(t, ok)
+
+	InitBudget(BudgetConfig{Window: 50 * time.Millisecond, InitiatorLimit: 1, Queue: true})
+	_, err = GetBudget().Reserve(te.Id(), budgetSaga("npc-1", AwardMesos))
+	require.NoError(t, err)
+	s = budgetSaga("npc-1", SetVariable)
+	s.Steps[0].Payload = SetVariablePayload{Name: "done", Value: true}
+	c, unsubscribe := GetNotifier().Subscribe(te.Id(), s.TransactionId)
+	defer unsubscribe()
+	err = processor.Put(s)
+	assert.ErrorIs(t, err, ErrSagaQueued)
+	_, ok = GetCache().GetById(te.Id(), s.TransactionId)
+	assert.False(t, ok)
+
+	// The queued saga starts, and completes, once the earlier charge leaves the window
+	select {
+	case cs := <-c:
+		assert.False(t, cs.Failing())
+	case <-time.After(time.Second):
+		t.Fatal("queued saga was not started")
+	}
+}
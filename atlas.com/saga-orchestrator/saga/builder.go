@@ -1,6 +1,7 @@
 package saga
 
 import (
+	"encoding/json"
 	"github.com/google/uuid"
 	"time"
 )
@@ -10,6 +11,11 @@ type Builder struct {
 	transactionId uuid.UUID
 	sagaType      Type
 	initiatedBy   string
+	labels        map[string]string
+	variables     Variables
+	approval      bool
+	onComplete    *OnComplete
+	coalesce      bool
 	steps         []Step[any]
 }
 
@@ -39,6 +45,43 @@ func (b *Builder) SetInitiatedBy(initiatedBy string) *Builder {
 	return b
 }
 
+// SetLabel sets a label on the saga
+func (b *Builder) SetLabel(key string, value string) *Builder {
+	if b.labels == nil {
+		b.labels = make(map[string]string)
+	}
+	b.labels[key] = value
+	return b
+}
+
+// SetVariable sets a variable on the saga, which step payload templates may reference
+func (b *Builder) SetVariable(key string, value any) *Builder {
+	if b.variables == nil {
+		b.variables = make(Variables)
+	}
+	b.variables[key] = value
+	return b
+}
+
+// SetRequiresApproval holds the saga until it is approved by an operator other than its initiator
+func (b *Builder) SetRequiresApproval() *Builder {
+	b.approval = true
+	return b
+}
+
+// SetOnComplete initiates a follow-up saga from the named chain template when the saga completes, with the params as
+// its variables
+func (b *Builder) SetOnComplete(template string, params map[string]any) *Builder {
+	b.onComplete = &OnComplete{Template: template, Params: params}
+	return b
+}
+
+// SetCoalesceAwards coalesces identical award_asset steps of stackable items when the saga is created
+func (b *Builder) SetCoalesceAwards() *Builder {
+	b.coalesce = true
+	return b
+}
+
 // AddStep adds a step to the saga
 func (b *Builder) AddStep(stepId string, status Status, action Action, payload any) *Builder {
 	now := time.Now()
@@ -54,12 +97,86 @@ func (b *Builder) AddStep(stepId string, status Status, action Action, payload a
 	return b
 }
 
+// AddTemplateStep adds a step whose payload contains template expressions, rendered when the step is dispatched
+func (b *Builder) AddTemplateStep(stepId string, status Status, action Action, template json.RawMessage) *Builder {
+	now := time.Now()
+	step := Step[any]{
+		StepId:          stepId,
+		Status:          status,
+		Action:          action,
+		PayloadTemplate: template,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	b.steps = append(b.steps, step)
+	return b
+}
+
+// AddErrorHandler declares a reaction to an error code on the most recently added step
+func (b *Builder) AddErrorHandler(handler ErrorHandler) *Builder {
+	if len(b.steps) == 0 {
+		return b
+	}
+	b.steps[len(b.steps)-1].OnError = append(b.steps[len(b.steps)-1].OnError, handler)
+	return b
+}
+
+// AddCapture declares a variable captured from the event completing the most recently added step, by JSONPath into
+// the event
+func (b *Builder) AddCapture(name string, path string) *Builder {
+	if len(b.steps) == 0 {
+		return b
+	}
+	st := &b.steps[len(b.steps)-1]
+	if st.Capture == nil {
+		st.Capture = make(map[string]string)
+	}
+	st.Capture[name] = path
+	return b
+}
+
+// AddDependency declares the most recently added step depends on the steps with the given IDs, so is only dispatched
+// once they have completed
+func (b *Builder) AddDependency(stepIds ...string) *Builder {
+	if len(b.steps) == 0 {
+		return b
+	}
+	st := &b.steps[len(b.steps)-1]
+	st.DependsOn = append(st.DependsOn, stepIds...)
+	return b
+}
+
+// SetTimeout sets the seconds the event completing the most recently added step may take to arrive once the step is
+// dispatched, after which the step fails
+func (b *Builder) SetTimeout(seconds uint32) *Builder {
+	if len(b.steps) == 0 {
+		return b
+	}
+	b.steps[len(b.steps)-1].Timeout = seconds
+	return b
+}
+
+// AddBranch declares a branch on the most recently added step, executed when the step completes and the branch is the
+// first of its branches whose condition holds
+func (b *Builder) AddBranch(branch Branch) *Builder {
+	if len(b.steps) == 0 {
+		return b
+	}
+	b.steps[len(b.steps)-1].Branches = append(b.steps[len(b.steps)-1].Branches, branch)
+	return b
+}
+
 // Build constructs and returns a new Saga instance
 func (b *Builder) Build() Saga {
 	return Saga{
-		TransactionId: b.transactionId,
-		SagaType:      b.sagaType,
-		InitiatedBy:   b.initiatedBy,
-		Steps:         b.steps,
+		TransactionId:    b.transactionId,
+		SagaType:         b.sagaType,
+		InitiatedBy:      b.initiatedBy,
+		Labels:           b.labels,
+		Variables:        b.variables,
+		RequiresApproval: b.approval,
+		OnComplete:       b.onComplete,
+		CoalesceAwards:   b.coalesce,
+		Steps:            b.steps,
 	}
 }
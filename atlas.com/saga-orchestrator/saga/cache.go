@@ -13,11 +13,20 @@ type Cache interface {
 	// GetById returns a saga by its transaction ID for a tenant
 	GetById(tenantId uuid.UUID, transactionId uuid.UUID) (Saga, bool)
 
+	// GetByLabel returns all sagas for a tenant carrying the label
+	GetByLabel(tenantId uuid.UUID, key string, value string) []Saga
+
 	// Put adds or updates a saga in the cache for a tenant
 	Put(tenantId uuid.UUID, saga Saga)
 
 	// Remove removes a saga from the cache for a tenant
 	Remove(tenantId uuid.UUID, transactionId uuid.UUID) bool
+
+	// TenantsOf returns the tenants which have a saga with the transaction ID
+	TenantsOf(transactionId uuid.UUID) []uuid.UUID
+
+	// Tenants returns the tenants which have sagas
+	Tenants() []uuid.UUID
 }
 
 // InMemoryCache is an in-memory implementation of the Cache interface
@@ -25,19 +34,40 @@ type InMemoryCache struct {
 	// tenantSagas is a map of tenant IDs to maps of transaction IDs to sagas
 	tenantSagas map[uuid.UUID]map[uuid.UUID]Saga
 
+	// tenantLabels is a map of tenant IDs to maps of labels to the transaction IDs of sagas carrying them
+	tenantLabels map[uuid.UUID]map[label]map[uuid.UUID]struct{}
+
 	// mutex is used to synchronize access to the cache
 	mutex sync.RWMutex
 }
 
+// label is a key and value pair used to index sagas
+type label struct {
+	key   string
+	value string
+}
+
 // Singleton instance of the cache
 var instance *InMemoryCache
 var once sync.Once
 
-// GetCache returns the singleton instance of the cache
+// GetCache returns the singleton instance of the cache, writing the sagas put into it through to the repository unless
+// it is the cache's
 func GetCache() Cache {
+	repositoryMutex.RLock()
+	defer repositoryMutex.RUnlock()
+	if _, ok := repository.(CacheRepository); ok {
+		return cache()
+	}
+	return persistentCache{Cache: cache(), l: repositoryLogger, r: repository}
+}
+
+// cache returns the singleton in-memory cache
+func cache() *InMemoryCache {
 	once.Do(func() {
 		instance = &InMemoryCache{
-			tenantSagas: make(map[uuid.UUID]map[uuid.UUID]Saga),
+			tenantSagas:  make(map[uuid.UUID]map[uuid.UUID]Saga),
+			tenantLabels: make(map[uuid.UUID]map[label]map[uuid.UUID]struct{}),
 		}
 	})
 	return instance
@@ -46,7 +76,8 @@ func GetCache() Cache {
 // ResetCache resets the singleton cache instance for testing
 func ResetCache() {
 	instance = &InMemoryCache{
-		tenantSagas: make(map[uuid.UUID]map[uuid.UUID]Saga),
+		tenantSagas:  make(map[uuid.UUID]map[uuid.UUID]Saga),
+		tenantLabels: make(map[uuid.UUID]map[label]map[uuid.UUID]struct{}),
 	}
 }
 
@@ -86,6 +117,52 @@ func (c *InMemoryCache) GetById(tenantId uuid.UUID, transactionId uuid.UUID) (Sa
 	return saga, exists
 }
 
+// GetByLabel returns all sagas for a tenant carrying the label
+func (c *InMemoryCache) GetByLabel(tenantId uuid.UUID, key string, value string) []Saga {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	// Look up the transaction IDs indexed under the label
+	ids := c.tenantLabels[tenantId][label{key: key, value: value}]
+
+	result := make([]Saga, 0, len(ids))
+	for id := range ids {
+		if saga, exists := c.tenantSagas[tenantId][id]; exists {
+			result = append(result, saga)
+		}
+	}
+
+	return result
+}
+
+// TenantsOf returns the tenants which have a saga with the transaction ID
+func (c *InMemoryCache) TenantsOf(transactionId uuid.UUID) []uuid.UUID {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	result := make([]uuid.UUID, 0)
+	for tenantId, sagas := range c.tenantSagas {
+		if _, exists := sagas[transactionId]; exists {
+			result = append(result, tenantId)
+		}
+	}
+	return result
+}
+
+// Tenants returns the tenants which have sagas
+func (c *InMemoryCache) Tenants() []uuid.UUID {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	result := make([]uuid.UUID, 0, len(c.tenantSagas))
+	for tenantId, sagas := range c.tenantSagas {
+		if len(sagas) > 0 {
+			result = append(result, tenantId)
+		}
+	}
+	return result
+}
+
 // Put adds or updates a saga in the cache for a tenant
 func (c *InMemoryCache) Put(tenantId uuid.UUID, saga Saga) {
 	c.mutex.Lock()
@@ -96,10 +173,44 @@ func (c *InMemoryCache) Put(tenantId uuid.UUID, saga Saga) {
 		c.tenantSagas[tenantId] = make(map[uuid.UUID]Saga)
 	}
 
+	// Re-index the labels of the saga, in case they have changed
+	if existing, exists := c.tenantSagas[tenantId][saga.TransactionId]; exists {
+		c.unindex(tenantId, existing)
+	}
+	c.index(tenantId, saga)
+
 	// Add or update the saga
 	c.tenantSagas[tenantId][saga.TransactionId] = saga
 }
 
+// index records the saga under each of its labels. The caller must hold the write lock.
+func (c *InMemoryCache) index(tenantId uuid.UUID, saga Saga) {
+	if len(saga.Labels) == 0 {
+		return
+	}
+	if _, exists := c.tenantLabels[tenantId]; !exists {
+		c.tenantLabels[tenantId] = make(map[label]map[uuid.UUID]struct{})
+	}
+	for k, v := range saga.Labels {
+		l := label{key: k, value: v}
+		if _, exists := c.tenantLabels[tenantId][l]; !exists {
+			c.tenantLabels[tenantId][l] = make(map[uuid.UUID]struct{})
+		}
+		c.tenantLabels[tenantId][l][saga.TransactionId] = struct{}{}
+	}
+}
+
+// unindex removes the saga from each of its labels. The caller must hold the write lock.
+func (c *InMemoryCache) unindex(tenantId uuid.UUID, saga Saga) {
+	for k, v := range saga.Labels {
+		l := label{key: k, value: v}
+		delete(c.tenantLabels[tenantId][l], saga.TransactionId)
+		if len(c.tenantLabels[tenantId][l]) == 0 {
+			delete(c.tenantLabels[tenantId], l)
+		}
+	}
+}
+
 // Remove removes a saga from the cache for a tenant
 func (c *InMemoryCache) Remove(tenantId uuid.UUID, transactionId uuid.UUID) bool {
 	c.mutex.Lock()
@@ -112,12 +223,13 @@ func (c *InMemoryCache) Remove(tenantId uuid.UUID, transactionId uuid.UUID) bool
 	}
 
 	// Check if the saga exists
-	_, exists = sagas[transactionId]
+	saga, exists := sagas[transactionId]
 	if !exists {
 		return false
 	}
 
-	// Remove the saga
+	// Remove the saga and its labels
+	c.unindex(tenantId, saga)
 	delete(sagas, transactionId)
 	return true
 }
@@ -0,0 +1,150 @@
+package saga
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrChainTemplate is returned when a saga's onComplete references a template which is not configured, or whose
+// params cannot be rendered
+var ErrChainTemplate = errors.New("invalid onComplete chain template")
+
+// OnComplete declares a follow-up saga, initiated from a template when the saga completes
+type OnComplete struct {
+	Template      string         `json:"template"`                // Name of the chain template initiating the follow-up saga (e.g., tutorial_intro)
+	Params        map[string]any `json:"params,omitempty"`        // Variables of the follow-up saga. Strings may be templates rendered against the completed saga.
+	TransactionId *uuid.UUID     `json:"transactionId,omitempty"` // TransactionId of the follow-up saga, recorded once it is initiated
+}
+
+// Initiated returns whether the follow-up saga has been initiated
+func (o OnComplete) Initiated() bool {
+	return o.TransactionId != nil
+}
+
+// ChainTemplate is a saga definition initiated as the follow-up of another. Step payloads reference the params given
+// by the completed saga as variables (e.g., "{{ .variables.characterId }}").
+type ChainTemplate struct {
+	SagaType   Type        `json:"sagaType"`             // Type of the follow-up saga
+	Steps      []Step[any] `json:"steps"`                // Steps of the follow-up saga
+	OnComplete *OnComplete `json:"onComplete,omitempty"` // Follow-up of the follow-up saga, if any
+}
+
+// ChainTemplates are the configured chain templates by name
+type ChainTemplates map[string]ChainTemplate
+
+// ChainTemplatesFromEnv loads the chain templates from the JSON file named by SAGA_CHAIN_TEMPLATES, if any
+func ChainTemplatesFromEnv() (ChainTemplates, error) {
+	path := os.Getenv("SAGA_CHAIN_TEMPLATES")
+	if path == "" {
+		return ChainTemplates{}, nil
+	}
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read SAGA_CHAIN_TEMPLATES '%s': %w", path, err)
+	}
+	var r ChainTemplates
+	if err = json.Unmarshal(bs, &r); err != nil {
+		return nil, fmt.Errorf("invalid SAGA_CHAIN_TEMPLATES '%s': %w", path, err)
+	}
+	for name, t := range r {
+		if t.SagaType == "" || len(t.Steps) == 0 {
+			return nil, fmt.Errorf("invalid SAGA_CHAIN_TEMPLATES '%s': template '%s' requires a sagaType and steps", path, name)
+		}
+	}
+	return r, nil
+}
+
+// Singleton chain templates, of which there are none until initialized
+var chainTemplates = ChainTemplates{}
+
+// InitChainTemplates replaces the singleton chain templates
+func InitChainTemplates(templates ChainTemplates) {
+	chainTemplates = templates
+}
+
+// GetChainTemplates returns the singleton chain templates
+func GetChainTemplates() ChainTemplates {
+	return chainTemplates
+}
+
+// ValidateOnComplete checks the saga's onComplete, if any, references a configured template
+func (s Saga) ValidateOnComplete() error {
+	if s.OnComplete == nil {
+		return nil
+	}
+	if s.OnComplete.Initiated() {
+		return fmt.Errorf("%w: transactionId is recorded once the follow-up saga is initiated", ErrChainTemplate)
+	}
+	if _, ok := GetChainTemplates()[s.OnComplete.Template]; !ok {
+		return fmt.Errorf("%w: template '%s' is not configured", ErrChainTemplate, s.OnComplete.Template)
+	}
+	return nil
+}
+
+// NewChainedSaga returns the follow-up saga declared by the completed saga's onComplete. It is initiated by the same
+// initiator and carries the same labels, and its variables are the params, rendered against the completed saga so they
+// may reference its variables and step results (e.g., "$.variables.characterId").
+func NewChainedSaga(s Saga) (Saga, error) {
+	if s.OnComplete == nil {
+		return Saga{}, fmt.Errorf("%w: saga [%s] declares no onComplete", ErrChainTemplate, s.TransactionId.String())
+	}
+	t, ok := GetChainTemplates()[s.OnComplete.Template]
+	if !ok {
+		return Saga{}, fmt.Errorf("%w: template '%s' is not configured", ErrChainTemplate, s.OnComplete.Template)
+	}
+
+	ctx, err := templateContext(s, "")
+	if err != nil {
+		return Saga{}, fmt.Errorf("%w: %s", ErrChainTemplate, err.Error())
+	}
+	variables := make(Variables, len(s.OnComplete.Params))
+	for name, param := range s.OnComplete.Params {
+		v, err := renderValue(param, ctx)
+		if err != nil {
+			return Saga{}, fmt.Errorf("%w: unable to render param '%s': %s", ErrChainTemplate, name, err.Error())
+		}
+		if variables[name], err = NormalizeVariable(name, v); err != nil {
+			return Saga{}, err
+		}
+	}
+
+	var labels map[string]string
+	if len(s.Labels) > 0 {
+		labels = make(map[string]string, len(s.Labels))
+		for k, v := range s.Labels {
+			labels[k] = v
+		}
+	}
+
+	now := time.Now()
+	steps := make([]Step[any], 0, len(t.Steps))
+	for _, st := range t.Steps {
+		st.Status = Pending
+		st.CreatedAt = now
+		st.UpdatedAt = now
+		st.Attempts = nil
+		steps = append(steps, st)
+	}
+
+	var onComplete *OnComplete
+	if t.OnComplete != nil {
+		oc := *t.OnComplete
+		oc.TransactionId = nil
+		onComplete = &oc
+	}
+
+	return Saga{
+		TransactionId: uuid.New(),
+		SagaType:      t.SagaType,
+		InitiatedBy:   s.InitiatedBy,
+		Labels:        labels,
+		Variables:     variables,
+		Steps:         steps,
+		OnComplete:    onComplete,
+	}, nil
+}
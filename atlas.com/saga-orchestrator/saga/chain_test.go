@@ -0,0 +1,106 @@
+package saga
+
+import (
+	"atlas-saga-orchestrator/character/mock"
+	"github.com/Chronicle20/atlas-constants/channel"
+	"github.com/Chronicle20/atlas-constants/world"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testChainTemplates = `{
+  "tutorial_intro": {
+    "sagaType": "quest_reward",
+    "steps": [
+      {"stepId": "welcome", "action": "award_mesos", "payload": {"characterId": "{{ .variables.characterId }}", "actorType": "NPC", "amount": 100}}
+    ]
+  }
+}`
+
+// TestChainTemplatesFromEnv tests loading the chain templates from the file named in the environment
+func TestChainTemplatesFromEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "templates.json")
+	require.NoError(t, os.WriteFile(path, []byte(testChainTemplates), 0o600))
+	t.Setenv("SAGA_CHAIN_TEMPLATES", path)
+
+	c, err := ChainTemplatesFromEnv()
+	require.NoError(t, err)
+	require.Contains(t, c, "tutorial_intro")
+	assert.Equal(t, QuestReward, c["tutorial_intro"].SagaType)
+	require.Len(t, c["tutorial_intro"].Steps, 1)
+	assert.NotEmpty(t, c["tutorial_intro"].Steps[0].PayloadTemplate)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"empty": {"sagaType": "quest_reward"}}`), 0o600))
+	_, err = ChainTemplatesFromEnv()
+	assert.Error(t, err)
+}
+
+// TestOnCompleteChaining tests that completing a saga initiates its follow-up, recording the follow-up's transaction
+func TestOnCompleteChaining(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "templates.json")
+	require.NoError(t, os.WriteFile(path, []byte(testChainTemplates), 0o600))
+	t.Setenv("SAGA_CHAIN_TEMPLATES", path)
+	c, err := ChainTemplatesFromEnv()
+	require.NoError(t, err)
+	defer InitChainTemplates(ChainTemplates{})
+	InitChainTemplates(c)
+
+	te, ctx := setupContext()
+	awarded := make(map[uint32]int32)
+	charP := &mock.ProcessorMock{
+		AwardMesosAndEmitFunc: func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, actorId uint32, actorType string, amount int32) error {
+			awarded[characterId] += amount
+			return nil
+		},
+	}
+	processor, _ := setupTestProcessor(ctx, charP, nil)
+
+	t.Run("unknown templates are rejected", func(t *testing.T) {
+		s := NewBuilder().
+			SetSagaType(InventoryTransaction).
+			SetInitiatedBy("login").
+			SetOnComplete("missing", nil).
+			AddStep("award", Pending, AwardMesos, AwardMesosPayload{CharacterId: 12345, ActorType: "NPC", Amount: 1}).
+			Build()
+		assert.ErrorIs(t, processor.Put(s), ErrChainTemplate)
+		_, err := processor.GetById(s.TransactionId)
+		assert.Error(t, err)
+	})
+
+	t.Run("completion initiates the follow-up", func(t *testing.T) {
+		s := NewBuilder().
+			SetSagaType(InventoryTransaction).
+			SetInitiatedBy("login").
+			SetVariable("characterId", 12345).
+			SetOnComplete("tutorial_intro", map[string]any{"characterId": "$.variables.characterId"}).
+			AddStep("award", Pending, AwardMesos, AwardMesosPayload{CharacterId: 12345, ActorType: "NPC", Amount: 1}).
+			Build()
+		done, unsubscribe := GetNotifier().Subscribe(te.Id(), s.TransactionId)
+		defer unsubscribe()
+
+		require.NoError(t, processor.Put(s))
+		require.NoError(t, processor.StepCompleted(s.TransactionId, true))
+
+		var final Saga
+		select {
+		case final = <-done:
+		case <-time.After(time.Second):
+			t.Fatal("saga did not complete")
+		}
+		require.NotNil(t, final.OnComplete)
+		require.True(t, final.OnComplete.Initiated())
+
+		child, err := processor.GetById(*final.OnComplete.TransactionId)
+		require.NoError(t, err)
+		defer GetCache().Remove(te.Id(), child.TransactionId)
+		assert.Equal(t, QuestReward, child.SagaType)
+		assert.Equal(t, "login", child.InitiatedBy)
+		assert.Equal(t, float64(12345), child.Variables["characterId"])
+		assert.Equal(t, int32(101), awarded[12345])
+	})
+}
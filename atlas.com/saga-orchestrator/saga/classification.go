@@ -0,0 +1,187 @@
+package saga
+
+import (
+	"atlas-saga-orchestrator/metrics"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Services reporting failure events, by which their error codes are classified
+const (
+	ServiceAccount     = "account"
+	ServiceCharacter   = "character"
+	ServiceCompartment = "compartment"
+	ServiceCoupon      = "coupon"
+	ServiceFaction     = "faction"
+	ServiceGuild       = "guild"
+	ServiceInstance    = "instance"
+	ServiceMarriage    = "marriage"
+	ServiceNpc         = "npc"
+	ServiceReactor     = "reactor"
+	ServiceSession     = "session"
+	ServiceStorage     = "storage"
+	ServiceWorldState  = "world-state"
+)
+
+// AnyService is the service of classifications applying to the error codes of every service
+const AnyService = "*"
+
+// FailureClass is the class of an error code reported by a failure event
+type FailureClass string
+
+const (
+	// FailureClassBusiness is a rejection by a business rule (e.g. not enough mesos), which retrying cannot overcome. The
+	// step fails, compensating the saga.
+	FailureClassBusiness FailureClass = "business"
+	// FailureClassTransient is an infrastructure error (e.g. a database being unavailable), which retrying may overcome.
+	// The step is redelivered under the retry policy, failing once it exhausts its attempts.
+	FailureClassTransient FailureClass = "transient"
+)
+
+// ClassificationConfig configures the classification of the error codes reported by failure events
+type ClassificationConfig struct {
+	Codes   map[string]map[string]FailureClass // Codes are the classes of error codes, by service then error code
+	Default FailureClass                       // Default class of error codes which are not classified
+}
+
+// DefaultClassificationConfig is the classification configuration used when none is configured. Error codes which are
+// not classified are business rejections, so only those known to be transient are retried.
+var DefaultClassificationConfig = ClassificationConfig{
+	Codes: map[string]map[string]FailureClass{
+		AnyService: {
+			"TIMEOUT":             FailureClassTransient,
+			"SERVICE_UNAVAILABLE": FailureClassTransient,
+			"DATABASE_ERROR":      FailureClassTransient,
+		},
+	},
+	Default: FailureClassBusiness,
+}
+
+// ClassificationConfigFromEnv loads the classification configuration from the environment, extending the default
+func ClassificationConfigFromEnv() (ClassificationConfig, error) {
+	c := ClassificationConfig{Codes: make(map[string]map[string]FailureClass), Default: DefaultClassificationConfig.Default}
+	for service, codes := range DefaultClassificationConfig.Codes {
+		for code, class := range codes {
+			c.classify(service, code, class)
+		}
+	}
+
+	if v := os.Getenv("SAGA_FAILURE_DEFAULT_CLASS"); v != "" {
+		class, err := parseFailureClass(v)
+		if err != nil {
+			return ClassificationConfig{}, fmt.Errorf("invalid SAGA_FAILURE_DEFAULT_CLASS '%s'", v)
+		}
+		c.Default = class
+	}
+
+	// Classifications are expressed as a comma-separated list of service:errorCode=class entries, the service being * to
+	// classify the error code of every service (e.g. compartment:LOCK_TIMEOUT=transient,*:NOT_FOUND=business)
+	v := os.Getenv("SAGA_FAILURE_CLASSIFICATION")
+	if v == "" {
+		return c, nil
+	}
+	for _, entry := range strings.Split(v, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			return ClassificationConfig{}, fmt.Errorf("invalid SAGA_FAILURE_CLASSIFICATION entry '%s'", entry)
+		}
+		service, code, ok := strings.Cut(key, ":")
+		if !ok || service == "" || code == "" {
+			return ClassificationConfig{}, fmt.Errorf("invalid SAGA_FAILURE_CLASSIFICATION entry '%s'", entry)
+		}
+		class, err := parseFailureClass(value)
+		if err != nil {
+			return ClassificationConfig{}, fmt.Errorf("invalid SAGA_FAILURE_CLASSIFICATION class '%s' of '%s'", value, key)
+		}
+		c.classify(service, code, class)
+	}
+	return c, nil
+}
+
+func parseFailureClass(v string) (FailureClass, error) {
+	switch FailureClass(v) {
+	case FailureClassBusiness, FailureClassTransient:
+		return FailureClass(v), nil
+	}
+	return "", fmt.Errorf("unknown failure class '%s'", v)
+}
+
+func (c *ClassificationConfig) classify(service string, code string, class FailureClass) {
+	if _, ok := c.Codes[service]; !ok {
+		c.Codes[service] = make(map[string]FailureClass)
+	}
+	c.Codes[service][code] = class
+}
+
+// Classify returns the class of the error code reported by the service. Classifications of the service take precedence
+// over those of every service.
+func (c ClassificationConfig) Classify(service string, errorCode string) FailureClass {
+	if class, ok := c.Codes[service][errorCode]; ok {
+		return class
+	}
+	if class, ok := c.Codes[AnyService][errorCode]; ok {
+		return class
+	}
+	if c.Default == "" {
+		return FailureClassBusiness
+	}
+	return c.Default
+}
+
+// Singleton classification configuration
+var classificationConfig = DefaultClassificationConfig
+
+// InitClassificationConfig replaces the singleton classification configuration
+func InitClassificationConfig(config ClassificationConfig) {
+	classificationConfig = config
+}
+
+// GetClassificationConfig returns the singleton classification configuration
+func GetClassificationConfig() ClassificationConfig {
+	return classificationConfig
+}
+
+var failures *metrics.Counter
+var failuresOnce sync.Once
+
+// getFailures returns the counter of failure events, by tenant, service and class
+func getFailures() *metrics.Counter {
+	failuresOnce.Do(func() {
+		failures = metrics.GetRegistry().RegisterCounter("saga_step_failures_total", "Number of failure events reported for saga steps, by reporting service and failure class.")
+	})
+	return failures
+}
+
+// transientFailures returns the number of consecutive attempts of the step, up to and including its latest, whose errors
+// reported by the service are transient
+func transientFailures(st Step[any], service string) int {
+	c := GetClassificationConfig()
+	n := 0
+	for i := len(st.Attempts) - 1; i >= 0 && st.Attempts[i].ErrorCode != "" && c.Classify(service, st.Attempts[i].ErrorCode) == FailureClassTransient; i-- {
+		n++
+	}
+	return n
+}
+
+// parkFailedStep parks the step at the index, whose latest attempt failed with a transient error, for a jittered
+// redelivery under the retry policy. Returns false, having parked nothing, when the step has exhausted its attempts.
+func (p *ProcessorImpl) parkFailedStep(s Saga, idx int, service string, fl logrus.FieldLogger) bool {
+	c := GetRetryConfig()
+	st := s.Steps[idx]
+	failures := transientFailures(st, service)
+	if failures == 0 || failures >= c.MaxAttempts {
+		return false
+	}
+
+	dueAt := time.Now().Add(c.Delay(failures))
+	s.Steps[idx].Attempts[len(st.Attempts)-1].RetryAt = &dueAt
+	GetCache().Put(p.t.Id(), s)
+	GetRetryQueue().Park(RetryEntry{Tenant: p.t, TransactionId: s.TransactionId, InitiatedBy: s.InitiatedBy, StepId: st.StepId, DueAt: dueAt})
+	fl.Warnf("Saga step failed with a transient error. Redelivering after [%d] failed attempts at [%s].", failures, dueAt.Format(time.RFC3339))
+	return true
+}
@@ -0,0 +1,142 @@
+package saga
+
+import (
+	"atlas-saga-orchestrator/character/mock"
+	"testing"
+	"time"
+
+	"github.com/Chronicle20/atlas-constants/channel"
+	"github.com/Chronicle20/atlas-constants/world"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClassificationConfigFromEnv tests loading the failure classification configuration from the environment
+func TestClassificationConfigFromEnv(t *testing.T) {
+	c, err := ClassificationConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, FailureClassTransient, c.Classify(ServiceCharacter, "DATABASE_ERROR"))
+	assert.Equal(t, FailureClassBusiness, c.Classify(ServiceCharacter, "NOT_ENOUGH_MESO"))
+
+	t.Setenv("SAGA_FAILURE_CLASSIFICATION", "compartment:LOCK_TIMEOUT=transient, compartment:DATABASE_ERROR=business")
+	c, err = ClassificationConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, FailureClassTransient, c.Classify(ServiceCompartment, "LOCK_TIMEOUT"))
+	assert.Equal(t, FailureClassBusiness, c.Classify(ServiceCharacter, "LOCK_TIMEOUT"))
+	assert.Equal(t, FailureClassBusiness, c.Classify(ServiceCompartment, "DATABASE_ERROR"))
+	assert.Equal(t, FailureClassTransient, c.Classify(ServiceCharacter, "DATABASE_ERROR"))
+	assert.Equal(t, FailureClassTransient, DefaultClassificationConfig.Classify(ServiceCompartment, "DATABASE_ERROR"))
+
+	t.Setenv("SAGA_FAILURE_DEFAULT_CLASS", "transient")
+	c, err = ClassificationConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, FailureClassTransient, c.Classify(ServiceCharacter, "NOT_ENOUGH_MESO"))
+
+	for k, v := range map[string]string{
+		"SAGA_FAILURE_DEFAULT_CLASS":  "sometimes",
+		"SAGA_FAILURE_CLASSIFICATION": "LOCK_TIMEOUT=transient",
+	} {
+		t.Run(k, func(t *testing.T) {
+			t.Setenv(k, v)
+			_, err := ClassificationConfigFromEnv()
+			assert.Error(t, err)
+		})
+	}
+}
+
+// TestStepFailedClassification tests that business rejections compensate the saga immediately, while transient errors
+// redeliver the step under the retry policy until it exhausts its attempts
+func TestStepFailedClassification(t *testing.T) {
+	te, ctx := setupContext()
+	defer ResetRetryQueue()
+	defer InitRetryConfig(DefaultRetryConfig)
+	InitRetryConfig(RetryConfig{BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, MaxAttempts: 3})
+
+	dispatched := 0
+	charP := &mock.ProcessorMock{
+		AwardMesosAndEmitFunc: func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, actorId uint32, actorType string, amount int32) error {
+			dispatched++
+			return nil
+		},
+	}
+	processor, _ := setupTestProcessor(ctx, charP, nil)
+	start := func(t *testing.T) Saga {
+		dispatched = 0
+		s := NewBuilder().
+			SetSagaType(QuestReward).
+			AddStep("mesos", Pending, AwardMesos, AwardMesosPayload{CharacterId: 12345, ActorType: "NPC", Amount: 1000}).
+			Build()
+		require.NoError(t, processor.Put(s))
+		t.Cleanup(func() { GetCache().Remove(te.Id(), s.TransactionId) })
+		return s
+	}
+	failed := func(t *testing.T, done <-chan Saga) Saga {
+		select {
+		case s := <-done:
+			return s
+		case <-time.After(time.Second):
+			t.Fatal("saga did not fail")
+		}
+		return Saga{}
+	}
+	redeliverDue := func() {
+		time.Sleep(5 * time.Millisecond)
+		for _, e := range GetRetryQueue().Due(time.Now()) {
+			redeliver(processor.(*ProcessorImpl).l, processor, e)
+		}
+	}
+
+	t.Run("business rejections are compensated", func(t *testing.T) {
+		s := start(t)
+		done, unsubscribe := GetNotifier().Subscribe(te.Id(), s.TransactionId)
+		defer unsubscribe()
+		require.NoError(t, processor.StepFailedBy(ServiceCharacter, s.TransactionId, "NOT_ENOUGH_MESO", ""))
+		s = failed(t, done)
+		require.Len(t, s.Steps[0].Attempts, 1)
+		assert.Nil(t, s.Steps[0].Attempts[0].RetryAt)
+		assert.Empty(t, GetRetryQueue().Depth())
+		assert.Equal(t, 1, dispatched)
+	})
+
+	t.Run("transient errors are redelivered", func(t *testing.T) {
+		s := start(t)
+		require.NoError(t, processor.StepFailedBy(ServiceCharacter, s.TransactionId, "DATABASE_ERROR", "connection refused"))
+		s, err := processor.GetById(s.TransactionId)
+		require.NoError(t, err)
+		assert.Equal(t, Pending, s.Steps[0].Status)
+		require.Len(t, s.Steps[0].Attempts, 1)
+		assert.NotNil(t, s.Steps[0].Attempts[0].RetryAt)
+		assert.Equal(t, 1, GetRetryQueue().Depth()[te.Id()])
+
+		redeliverDue()
+		assert.Equal(t, 2, dispatched)
+		require.NoError(t, processor.StepCompleted(s.TransactionId, true))
+		_, err = processor.GetById(s.TransactionId)
+		assert.Error(t, err)
+	})
+
+	t.Run("transient errors fail once attempts are exhausted", func(t *testing.T) {
+		s := start(t)
+		done, unsubscribe := GetNotifier().Subscribe(te.Id(), s.TransactionId)
+		defer unsubscribe()
+		for i := 0; i < 2; i++ {
+			require.NoError(t, processor.StepFailedBy(ServiceCharacter, s.TransactionId, "DATABASE_ERROR", ""))
+			redeliverDue()
+		}
+		require.NoError(t, processor.StepFailedBy(ServiceCharacter, s.TransactionId, "DATABASE_ERROR", ""))
+		s = failed(t, done)
+		assert.Equal(t, 3, dispatched)
+		require.Len(t, s.Steps[0].Attempts, 3)
+		assert.Empty(t, GetRetryQueue().Depth())
+	})
+
+	t.Run("errors raised by the orchestrator are not classified", func(t *testing.T) {
+		s := start(t)
+		done, unsubscribe := GetNotifier().Subscribe(te.Id(), s.TransactionId)
+		defer unsubscribe()
+		require.NoError(t, processor.StepFailed(s.TransactionId, "DATABASE_ERROR", ""))
+		failed(t, done)
+		assert.Empty(t, GetRetryQueue().Depth())
+	})
+}
@@ -0,0 +1,551 @@
+package client
+
+import (
+	"atlas-saga-orchestrator/saga"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+)
+
+// Builder constructs sagas with one typed method per action, so the payload supplied always matches the action
+type Builder struct {
+	b     *saga.Builder
+	steps int
+}
+
+// NewBuilder creates a new Builder for a saga of the given type
+func NewBuilder(sagaType saga.Type, initiatedBy string) *Builder {
+	return &Builder{
+		b: saga.NewBuilder().SetSagaType(sagaType).SetInitiatedBy(initiatedBy),
+	}
+}
+
+// SetTransactionId sets the transaction ID for the saga. A random transaction ID is used otherwise.
+func (b *Builder) SetTransactionId(transactionId uuid.UUID) *Builder {
+	b.b.SetTransactionId(transactionId)
+	return b
+}
+
+// SetLabel sets a label on the saga, so cohorts of sagas (e.g. from a campaign or script version) can be queried together
+func (b *Builder) SetLabel(key string, value string) *Builder {
+	b.b.SetLabel(key, value)
+	return b
+}
+
+// SetVariable sets a variable on the saga, which step payload templates may reference as .variables.<key> or
+// $.variables.<key>
+func (b *Builder) SetVariable(key string, value any) *Builder {
+	b.b.SetVariable(key, value)
+	return b
+}
+
+// SetRequiresApproval holds the saga until a second operator approves it, as for high-value item restorations
+func (b *Builder) SetRequiresApproval() *Builder {
+	b.b.SetRequiresApproval()
+	return b
+}
+
+// OnComplete initiates a follow-up saga from the named chain template when the saga completes (e.g.,
+// character_creation followed by tutorial_intro). Params become the follow-up's variables, and strings among them may
+// be templates referencing the completed saga (e.g., "$.variables.characterId").
+func (b *Builder) OnComplete(template string, params map[string]any) *Builder {
+	b.b.SetOnComplete(template, params)
+	return b
+}
+
+// SetCoalesceAwards coalesces award_asset steps of the same stackable item to the same character into one step with
+// their quantities summed, reducing the commands issued for bulk reward definitions
+func (b *Builder) SetCoalesceAwards() *Builder {
+	b.b.SetCoalesceAwards()
+	return b
+}
+
+// addStep adds a pending step with a generated step ID
+func (b *Builder) addStep(action saga.Action, payload any) *Builder {
+	b.steps++
+	return b.AddStep(fmt.Sprintf("%s_%d", action, b.steps), action, payload)
+}
+
+// AddStep adds a pending step with an explicit step ID. Prefer the typed action methods where possible.
+func (b *Builder) AddStep(stepId string, action saga.Action, payload any) *Builder {
+	b.b.AddStep(stepId, saga.Pending, action, payload)
+	return b
+}
+
+// AddTemplateStep adds a pending step whose payload contains template expressions referencing the saga's variables
+// and the payloads of prior steps, rendered when the step is dispatched
+func (b *Builder) AddTemplateStep(stepId string, action saga.Action, template json.RawMessage) *Builder {
+	b.b.AddTemplateStep(stepId, saga.Pending, action, template)
+	return b
+}
+
+// OnError declares a reaction to an error code on the most recently added step
+func (b *Builder) OnError(handler saga.ErrorHandler) *Builder {
+	b.b.AddErrorHandler(handler)
+	return b
+}
+
+// Capture sets a saga variable from the event completing the most recently added step, by JSONPath into the event
+// (e.g. "$.characterId")
+func (b *Builder) Capture(name string, path string) *Builder {
+	b.b.AddCapture(name, path)
+	return b
+}
+
+// DependsOn declares the most recently added step depends on the steps with the given IDs, so is only dispatched once
+// they have completed, whatever order the steps were added in
+func (b *Builder) DependsOn(stepIds ...string) *Builder {
+	b.b.AddDependency(stepIds...)
+	return b
+}
+
+// Timeout sets the seconds the event completing the most recently added step may take to arrive once the step is
+// dispatched, after which the step fails and the saga is compensated, unless the step's error handlers declare otherwise
+func (b *Builder) Timeout(seconds uint32) *Builder {
+	b.b.SetTimeout(seconds)
+	return b
+}
+
+// Branch declares a branch on the most recently added step, whose steps, added by steps through a builder of their
+// own, execute only when the branch is the first whose condition holds once the step completes. The condition is a
+// JSONPath expression (e.g. "$.steps.check.passed"), selecting the branch when it equals equals, or when equals is nil
+// and it is truthy. A branch with an empty condition is the default, and must be declared last.
+func (b *Builder) Branch(name string, when string, equals any, steps func(b *Builder)) *Builder {
+	sub := &Builder{b: saga.NewBuilder(), steps: b.steps}
+	if steps != nil {
+		steps(sub)
+	}
+	b.steps = sub.steps
+	b.b.AddBranch(saga.Branch{Name: name, When: when, Equals: equals, Steps: sub.b.Build().Steps})
+	return b
+}
+
+// SetVariableStep adds a set_variable step
+func (b *Builder) SetVariableStep(p saga.SetVariablePayload) *Builder {
+	return b.addStep(saga.SetVariable, p)
+}
+
+// HttpRequest adds an http_request step
+func (b *Builder) HttpRequest(p saga.HttpRequestPayload) *Builder {
+	return b.addStep(saga.HttpRequest, p)
+}
+
+// CreateAccountCharacterSlot adds a create_account_character_slot step
+func (b *Builder) CreateAccountCharacterSlot(p saga.CreateAccountCharacterSlotPayload) *Builder {
+	return b.addStep(saga.CreateAccountCharacterSlot, p)
+}
+
+// EmitAnalyticsEvent adds an emit_analytics_event step
+func (b *Builder) EmitAnalyticsEvent(p saga.EmitAnalyticsEventPayload) *Builder {
+	return b.addStep(saga.EmitAnalyticsEvent, p)
+}
+
+// ModifyAssetExpiration adds a modify_asset_expiration step
+func (b *Builder) ModifyAssetExpiration(p saga.ModifyAssetExpirationPayload) *Builder {
+	return b.addStep(saga.ModifyAssetExpiration, p)
+}
+
+// ApplyHammer adds an apply_hammer step
+func (b *Builder) ApplyHammer(p saga.ApplyHammerPayload) *Builder {
+	return b.addStep(saga.ApplyHammer, p)
+}
+
+// DeductMesos adds a deduct_mesos step
+func (b *Builder) DeductMesos(p saga.DeductMesosPayload) *Builder {
+	return b.addStep(saga.DeductMesos, p)
+}
+
+// ForEach adds a for_each step
+func (b *Builder) ForEach(p saga.ForEachPayload) *Builder {
+	return b.addStep(saga.ForEach, p)
+}
+
+// VerifyAndConsumeTicket adds a verify_and_consume_ticket step
+func (b *Builder) VerifyAndConsumeTicket(p saga.VerifyAndConsumeTicketPayload) *Builder {
+	return b.addStep(saga.VerifyAndConsumeTicket, p)
+}
+
+// AwaitKillCount adds an await_kill_count step
+func (b *Builder) AwaitKillCount(p saga.AwaitKillCountPayload) *Builder {
+	return b.addStep(saga.AwaitKillCount, p)
+}
+
+// AdjustReactorState adds an adjust_reactor_state step
+func (b *Builder) AdjustReactorState(p saga.AdjustReactorStatePayload) *Builder {
+	return b.addStep(saga.AdjustReactorState, p)
+}
+
+// HitReactor adds a hit_reactor step
+func (b *Builder) HitReactor(p saga.HitReactorPayload) *Builder {
+	return b.addStep(saga.HitReactor, p)
+}
+
+// UpdateCharacterResource adds an update_character_resource step
+func (b *Builder) UpdateCharacterResource(p saga.UpdateCharacterResourcePayload) *Builder {
+	return b.addStep(saga.UpdateCharacterResource, p)
+}
+
+// AwardAssetIf adds an award_asset_if step
+func (b *Builder) AwardAssetIf(p saga.AwardAssetIfPayload) *Builder {
+	return b.addStep(saga.AwardAssetIf, p)
+}
+
+// GrantPremiumTime adds a grant_premium_time step
+func (b *Builder) GrantPremiumTime(p saga.GrantPremiumTimePayload) *Builder {
+	return b.addStep(saga.GrantPremiumTime, p)
+}
+
+// ValidateDivorce adds a validate_divorce step
+func (b *Builder) ValidateDivorce(p saga.ValidateDivorcePayload) *Builder {
+	return b.addStep(saga.ValidateDivorce, p)
+}
+
+// DissolveMarriage adds a dissolve_marriage step
+func (b *Builder) DissolveMarriage(p saga.DissolveMarriagePayload) *Builder {
+	return b.addStep(saga.DissolveMarriage, p)
+}
+
+// SealAsset adds a seal_asset step
+func (b *Builder) SealAsset(p saga.SealAssetPayload) *Builder {
+	return b.addStep(saga.SealAsset, p)
+}
+
+// UnsealAsset adds an unseal_asset step
+func (b *Builder) UnsealAsset(p saga.UnsealAssetPayload) *Builder {
+	return b.addStep(saga.UnsealAsset, p)
+}
+
+// ConfiscateAsset adds a confiscate_asset step
+func (b *Builder) ConfiscateAsset(p saga.ConfiscateAssetPayload) *Builder {
+	return b.addStep(saga.ConfiscateAsset, p)
+}
+
+// ResolveDispute adds a resolve_dispute step
+func (b *Builder) ResolveDispute(p saga.ResolveDisputePayload) *Builder {
+	return b.addStep(saga.ResolveDispute, p)
+}
+
+// IssueTransportTicket adds an issue_transport_ticket step
+func (b *Builder) IssueTransportTicket(p saga.IssueTransportTicketPayload) *Builder {
+	return b.addStep(saga.IssueTransportTicket, p)
+}
+
+// ScheduleWarp adds a schedule_warp step
+func (b *Builder) ScheduleWarp(p saga.ScheduleWarpPayload) *Builder {
+	return b.addStep(saga.ScheduleWarp, p)
+}
+
+// SpawnEscort adds a spawn_escort step
+func (b *Builder) SpawnEscort(p saga.SpawnEscortPayload) *Builder {
+	return b.addStep(saga.SpawnEscort, p)
+}
+
+// AwaitEscort adds an await_escort step
+func (b *Builder) AwaitEscort(p saga.AwaitEscortPayload) *Builder {
+	return b.addStep(saga.AwaitEscort, p)
+}
+
+// UpdateCharacterAlignment adds an update_character_alignment step
+func (b *Builder) UpdateCharacterAlignment(p saga.UpdateCharacterAlignmentPayload) *Builder {
+	return b.addStep(saga.UpdateCharacterAlignment, p)
+}
+
+// ResetInstanceCooldown adds a reset_instance_cooldown step
+func (b *Builder) ResetInstanceCooldown(p saga.ResetInstanceCooldownPayload) *Builder {
+	return b.addStep(saga.ResetInstanceCooldown, p)
+}
+
+// AuditInventory adds an audit_inventory step
+func (b *Builder) AuditInventory(p saga.AuditInventoryPayload) *Builder {
+	return b.addStep(saga.AuditInventory, p)
+}
+
+// ApplyTitleBuffOnLogin adds an apply_title_buff_on_login step
+func (b *Builder) ApplyTitleBuffOnLogin(p saga.ApplyTitleBuffOnLoginPayload) *Builder {
+	return b.addStep(saga.ApplyTitleBuffOnLogin, p)
+}
+
+// GrantMount adds a grant_mount step
+func (b *Builder) GrantMount(p saga.GrantMountPayload) *Builder {
+	return b.addStep(saga.GrantMount, p)
+}
+
+// ApplyWorldEventBuff adds an apply_world_event_buff step
+func (b *Builder) ApplyWorldEventBuff(p saga.ApplyWorldEventBuffPayload) *Builder {
+	return b.addStep(saga.ApplyWorldEventBuff, p)
+}
+
+// RemoveWorldEventBuff adds a remove_world_event_buff step
+func (b *Builder) RemoveWorldEventBuff(p saga.RemoveWorldEventBuffPayload) *Builder {
+	return b.addStep(saga.RemoveWorldEventBuff, p)
+}
+
+// SettleEventCurrency adds a settle_event_currency step
+func (b *Builder) SettleEventCurrency(p saga.SettleEventCurrencyPayload) *Builder {
+	return b.addStep(saga.SettleEventCurrency, p)
+}
+
+// CharacterExperienceLock adds a character_experience_lock step
+func (b *Builder) CharacterExperienceLock(p saga.CharacterExperienceLockPayload) *Builder {
+	return b.addStep(saga.CharacterExperienceLock, p)
+}
+
+// CharacterExperienceUnlock adds a character_experience_unlock step
+func (b *Builder) CharacterExperienceUnlock(p saga.CharacterExperienceUnlockPayload) *Builder {
+	return b.addStep(saga.CharacterExperienceUnlock, p)
+}
+
+// NpcConversationState adds a npc_conversation_state step
+func (b *Builder) NpcConversationState(p saga.NpcConversationStatePayload) *Builder {
+	return b.addStep(saga.NpcConversationState, p)
+}
+
+// EquipAssetByTemplate adds an equip_asset_by_template step
+func (b *Builder) EquipAssetByTemplate(p saga.EquipAssetByTemplatePayload) *Builder {
+	return b.addStep(saga.EquipAssetByTemplate, p)
+}
+
+// AwardPartyExperience adds an award_exp_to_party_members step
+func (b *Builder) AwardPartyExperience(p saga.AwardPartyExperiencePayload) *Builder {
+	return b.addStep(saga.AwardPartyExperience, p)
+}
+
+// ToggleCharacterAbility adds a toggle_character_ability step
+func (b *Builder) ToggleCharacterAbility(p saga.ToggleCharacterAbilityPayload) *Builder {
+	return b.addStep(saga.ToggleCharacterAbility, p)
+}
+
+// GrantStorageCapacity adds a grant_storage_capacity step
+func (b *Builder) GrantStorageCapacity(p saga.GrantStorageCapacityPayload) *Builder {
+	return b.addStep(saga.GrantStorageCapacity, p)
+}
+
+// StripEquipment adds a strip_equipment step
+func (b *Builder) StripEquipment(p saga.StripEquipmentPayload) *Builder {
+	return b.addStep(saga.StripEquipment, p)
+}
+
+// SnapshotCharacter adds a snapshot_character step
+func (b *Builder) SnapshotCharacter(p saga.SnapshotCharacterPayload) *Builder {
+	return b.addStep(saga.SnapshotCharacter, p)
+}
+
+// RestoreCharacter adds a restore_character step
+func (b *Builder) RestoreCharacter(p saga.RestoreCharacterPayload) *Builder {
+	return b.addStep(saga.RestoreCharacter, p)
+}
+
+// AwaitEvent adds an await_event step
+func (b *Builder) AwaitEvent(p saga.AwaitEventPayload) *Builder {
+	return b.addStep(saga.AwaitEvent, p)
+}
+
+// Notify adds a notify step
+func (b *Builder) Notify(p saga.NotifyPayload) *Builder {
+	return b.addStep(saga.Notify, p)
+}
+
+// PetEquip adds a pet_equip step
+func (b *Builder) PetEquip(p saga.PetEquipPayload) *Builder {
+	return b.addStep(saga.PetEquip, p)
+}
+
+// PetUnequip adds a pet_unequip step
+func (b *Builder) PetUnequip(p saga.PetUnequipPayload) *Builder {
+	return b.addStep(saga.PetUnequip, p)
+}
+
+// AwardAsset adds an award_asset step
+func (b *Builder) AwardAsset(p saga.AwardItemActionPayload) *Builder {
+	return b.addStep(saga.AwardAsset, p)
+}
+
+// AwardExperience adds an award_experience step
+func (b *Builder) AwardExperience(p saga.AwardExperiencePayload) *Builder {
+	return b.addStep(saga.AwardExperience, p)
+}
+
+// AwardLevel adds an award_level step
+func (b *Builder) AwardLevel(p saga.AwardLevelPayload) *Builder {
+	return b.addStep(saga.AwardLevel, p)
+}
+
+// AwardMesos adds an award_mesos step
+func (b *Builder) AwardMesos(p saga.AwardMesosPayload) *Builder {
+	return b.addStep(saga.AwardMesos, p)
+}
+
+// WarpToRandomPortal adds a warp_to_random_portal step
+func (b *Builder) WarpToRandomPortal(p saga.WarpToRandomPortalPayload) *Builder {
+	return b.addStep(saga.WarpToRandomPortal, p)
+}
+
+// WarpToPortal adds a warp_to_portal step
+func (b *Builder) WarpToPortal(p saga.WarpToPortalPayload) *Builder {
+	return b.addStep(saga.WarpToPortal, p)
+}
+
+// DestroyAsset adds a destroy_asset step
+func (b *Builder) DestroyAsset(p saga.DestroyAssetPayload) *Builder {
+	return b.addStep(saga.DestroyAsset, p)
+}
+
+// EquipAsset adds an equip_asset step
+func (b *Builder) EquipAsset(p saga.EquipAssetPayload) *Builder {
+	return b.addStep(saga.EquipAsset, p)
+}
+
+// UnequipAsset adds an unequip_asset step
+func (b *Builder) UnequipAsset(p saga.UnequipAssetPayload) *Builder {
+	return b.addStep(saga.UnequipAsset, p)
+}
+
+// ChangeJob adds a change_job step
+func (b *Builder) ChangeJob(p saga.ChangeJobPayload) *Builder {
+	return b.addStep(saga.ChangeJob, p)
+}
+
+// CreateSkill adds a create_skill step
+func (b *Builder) CreateSkill(p saga.CreateSkillPayload) *Builder {
+	return b.addStep(saga.CreateSkill, p)
+}
+
+// UpdateSkill adds an update_skill step
+func (b *Builder) UpdateSkill(p saga.UpdateSkillPayload) *Builder {
+	return b.addStep(saga.UpdateSkill, p)
+}
+
+// ModifyInventoryItemPosition adds a modify_inventory_item_position step
+func (b *Builder) ModifyInventoryItemPosition(p saga.ModifyInventoryItemPositionPayload) *Builder {
+	return b.addStep(saga.ModifyInventoryItemPosition, p)
+}
+
+// ApplyEquipmentPreset adds an apply_equipment_preset step
+func (b *Builder) ApplyEquipmentPreset(p saga.ApplyEquipmentPresetPayload) *Builder {
+	return b.addStep(saga.ApplyEquipmentPreset, p)
+}
+
+// TransferCharacter adds a transfer_character step
+func (b *Builder) TransferCharacter(p saga.TransferCharacterPayload) *Builder {
+	return b.addStep(saga.TransferCharacter, p)
+}
+
+// VerifyAccountMerge adds a verify_account_merge step
+func (b *Builder) VerifyAccountMerge(p saga.VerifyAccountMergePayload) *Builder {
+	return b.addStep(saga.VerifyAccountMerge, p)
+}
+
+// RestoreAsset adds a restore_asset step
+func (b *Builder) RestoreAsset(p saga.RestoreAssetPayload) *Builder {
+	return b.addStep(saga.RestoreAsset, p)
+}
+
+// RollbackCharacterToSnapshot adds a rollback_character_to_snapshot step
+func (b *Builder) RollbackCharacterToSnapshot(p saga.RollbackCharacterToSnapshotPayload) *Builder {
+	return b.addStep(saga.RollbackCharacterToSnapshot, p)
+}
+
+// RestoreInventorySnapshot adds a restore_inventory_snapshot step
+func (b *Builder) RestoreInventorySnapshot(p saga.RestoreInventorySnapshotPayload) *Builder {
+	return b.addStep(saga.RestoreInventorySnapshot, p)
+}
+
+// AdjustNpcShopStock adds an adjust_npc_shop_stock step
+func (b *Builder) AdjustNpcShopStock(p saga.AdjustNpcShopStockPayload) *Builder {
+	return b.addStep(saga.AdjustNpcShopStock, p)
+}
+
+// SetWorldEventFlag adds a set_world_event_flag step
+func (b *Builder) SetWorldEventFlag(p saga.SetWorldEventFlagPayload) *Builder {
+	return b.addStep(saga.SetWorldEventFlag, p)
+}
+
+// ApplyCharacterExpPenalty adds an apply_character_exp_penalty step
+func (b *Builder) ApplyCharacterExpPenalty(p saga.ApplyCharacterExpPenaltyPayload) *Builder {
+	return b.addStep(saga.ApplyCharacterExpPenalty, p)
+}
+
+// ApplyDurabilityPenalty adds an apply_durability_penalty step
+func (b *Builder) ApplyDurabilityPenalty(p saga.ApplyDurabilityPenaltyPayload) *Builder {
+	return b.addStep(saga.ApplyDurabilityPenalty, p)
+}
+
+// ValidateCoupon adds a validate_coupon step
+func (b *Builder) ValidateCoupon(p saga.ValidateCouponPayload) *Builder {
+	return b.addStep(saga.ValidateCoupon, p)
+}
+
+// ConsumeCoupon adds a consume_coupon step
+func (b *Builder) ConsumeCoupon(p saga.ConsumeCouponPayload) *Builder {
+	return b.addStep(saga.ConsumeCoupon, p)
+}
+
+// ResetSkillCooldowns adds a reset_skill_cooldowns step
+func (b *Builder) ResetSkillCooldowns(p saga.ResetSkillCooldownsPayload) *Builder {
+	return b.addStep(saga.ResetSkillCooldowns, p)
+}
+
+// ValidateCharacterState adds a validate_character_state step
+func (b *Builder) ValidateCharacterState(p saga.ValidateCharacterStatePayload) *Builder {
+	return b.addStep(saga.ValidateCharacterState, p)
+}
+
+// RequestGuildName adds a request_guild_name step
+func (b *Builder) RequestGuildName(p saga.RequestGuildNamePayload) *Builder {
+	return b.addStep(saga.RequestGuildName, p)
+}
+
+// RequestGuildEmblem adds a request_guild_emblem step
+func (b *Builder) RequestGuildEmblem(p saga.RequestGuildEmblemPayload) *Builder {
+	return b.addStep(saga.RequestGuildEmblem, p)
+}
+
+// RequestGuildDisband adds a request_guild_disband step
+func (b *Builder) RequestGuildDisband(p saga.RequestGuildDisbandPayload) *Builder {
+	return b.addStep(saga.RequestGuildDisband, p)
+}
+
+// RequestGuildCapacityIncrease adds a request_guild_capacity_increase step
+func (b *Builder) RequestGuildCapacityIncrease(p saga.RequestGuildCapacityIncreasePayload) *Builder {
+	return b.addStep(saga.RequestGuildCapacityIncrease, p)
+}
+
+// CreateInvite adds a create_invite step
+func (b *Builder) CreateInvite(p saga.CreateInvitePayload) *Builder {
+	return b.addStep(saga.CreateInvite, p)
+}
+
+// CreateCharacter adds a create_character step
+func (b *Builder) CreateCharacter(p saga.CharacterCreatePayload) *Builder {
+	return b.addStep(saga.CreateCharacter, p)
+}
+
+// CreateAndEquipAsset adds a create_and_equip_asset step
+func (b *Builder) CreateAndEquipAsset(p saga.CreateAndEquipAssetPayload) *Builder {
+	return b.addStep(saga.CreateAndEquipAsset, p)
+}
+
+// SetQuestTimer adds a set_quest_timer step
+func (b *Builder) SetQuestTimer(p saga.SetQuestTimerPayload) *Builder {
+	return b.addStep(saga.SetQuestTimer, p)
+}
+
+// AdjustPopularity adds an adjust_popularity step
+func (b *Builder) AdjustPopularity(p saga.AdjustPopularityPayload) *Builder {
+	return b.addStep(saga.AdjustPopularity, p)
+}
+
+// ResolvePrizeTable adds a resolve_prize_table step
+func (b *Builder) ResolvePrizeTable(p saga.ResolvePrizeTablePayload) *Builder {
+	return b.addStep(saga.ResolvePrizeTable, p)
+}
+
+// CharacterBuffCleanse adds a character_buff_cleanse step
+func (b *Builder) CharacterBuffCleanse(p saga.CharacterBuffCleansePayload) *Builder {
+	return b.addStep(saga.CharacterBuffCleanse, p)
+}
+
+// Build constructs and returns the saga
+func (b *Builder) Build() saga.Saga {
+	return b.b.Build()
+}
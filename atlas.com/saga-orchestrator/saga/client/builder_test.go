@@ -0,0 +1,395 @@
+package client
+
+import (
+	"atlas-saga-orchestrator/saga"
+	v2 "atlas-saga-orchestrator/saga/v2"
+	"atlas-saga-orchestrator/validation"
+	"github.com/Chronicle20/atlas-constants/field"
+	"github.com/Chronicle20/atlas-constants/inventory"
+	_map "github.com/Chronicle20/atlas-constants/map"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+// TestBuilder tests that typed step methods pair each payload with its action
+func TestBuilder(t *testing.T) {
+	transactionId := uuid.New()
+	s := NewBuilder(saga.QuestReward, "npc-9010000").
+		SetTransactionId(transactionId).
+		ValidateCharacterState(saga.ValidateCharacterStatePayload{
+			CharacterId: 12345,
+			Conditions:  []validation.ConditionInput{{Type: "meso", Operator: ">=", Value: 1000}},
+		}).
+		AwardMesos(saga.AwardMesosPayload{CharacterId: 12345, ActorType: "NPC", Amount: -1000}).
+		AwardAsset(saga.AwardItemActionPayload{CharacterId: 12345, Item: saga.ItemPayload{TemplateId: 2000000, Quantity: 1}}).
+		AwardMesos(saga.AwardMesosPayload{CharacterId: 12345, ActorType: "NPC", Amount: 10}).
+		Build()
+
+	assert.Equal(t, transactionId, s.TransactionId)
+	assert.Equal(t, saga.QuestReward, s.SagaType)
+	assert.Equal(t, "npc-9010000", s.InitiatedBy)
+	require.Len(t, s.Steps, 4)
+
+	expected := []struct {
+		stepId string
+		action saga.Action
+	}{
+		{"validate_character_state_1", saga.ValidateCharacterState},
+		{"award_mesos_2", saga.AwardMesos},
+		{"award_asset_3", saga.AwardAsset},
+		{"award_mesos_4", saga.AwardMesos},
+	}
+	for i, e := range expected {
+		assert.Equal(t, e.stepId, s.Steps[i].StepId)
+		assert.Equal(t, e.action, s.Steps[i].Action)
+		assert.Equal(t, saga.Pending, s.Steps[i].Status)
+	}
+	assert.NoError(t, s.ValidateStateConsistency())
+}
+
+// TestBuilderRoundTrip tests that sagas built by the client are decoded by the orchestrator with typed payloads
+func TestBuilderRoundTrip(t *testing.T) {
+	s := NewBuilder(saga.InventoryTransaction, "test").
+		AwardExperience(saga.AwardExperiencePayload{CharacterId: 1, Distributions: []saga.ExperienceDistributions{{ExperienceType: "WHITE", Amount: 100}}}).
+		AwardLevel(saga.AwardLevelPayload{CharacterId: 1, Amount: 1}).
+		WarpToPortal(saga.WarpToPortalPayload{CharacterId: 1, FieldId: "0:1:100000000:00000000-0000-0000-0000-000000000000", PortalId: 2}).
+		DestroyAsset(saga.DestroyAssetPayload{CharacterId: 1, TemplateId: 4000000, Quantity: 2}).
+		EquipAsset(saga.EquipAssetPayload{CharacterId: 1, InventoryType: 1, Source: 1, Destination: -11}).
+		RequestGuildName(saga.RequestGuildNamePayload{CharacterId: 1}).
+		CreateInvite(saga.CreateInvitePayload{InviteType: "GUILD", OriginatorId: 1, TargetId: 2}).
+		SetQuestTimer(saga.SetQuestTimerPayload{CharacterId: 1, QuestId: 2, Duration: 60}).
+		DependsOn("award_level_2").
+		Build()
+
+	rm, err := v2.Transform(s)
+	require.NoError(t, err)
+	es, err := v2.Extract(rm)
+	require.NoError(t, err)
+
+	require.Len(t, es.Steps, len(s.Steps))
+	for i := range s.Steps {
+		assert.Equal(t, s.Steps[i].Action, es.Steps[i].Action)
+		assert.Equal(t, s.Steps[i].Payload, es.Steps[i].Payload)
+		assert.Equal(t, s.Steps[i].DependsOn, es.Steps[i].DependsOn)
+	}
+}
+
+// TestMinigameReward tests that the minigame reward template validates and consumes the ticket before drawing a prize
+func TestMinigameReward(t *testing.T) {
+	prizes := []saga.PrizeEntry{
+		{Weight: 90},
+		{Weight: 10, Item: &saga.ItemPayload{TemplateId: 2000000, Quantity: 5}},
+	}
+	s := MinigameReward("npc-9000000", 12345, 0, 1, 4031017, prizes).Build()
+
+	assert.Equal(t, saga.MinigameReward, s.SagaType)
+	require.Len(t, s.Steps, 3)
+	assert.Equal(t, saga.ValidateCharacterState, s.Steps[0].Action)
+	assert.Equal(t, saga.DestroyAsset, s.Steps[1].Action)
+	assert.Equal(t, saga.ResolvePrizeTable, s.Steps[2].Action)
+
+	condition := s.Steps[0].Payload.(saga.ValidateCharacterStatePayload).Conditions[0]
+	assert.Equal(t, uint32(4031017), condition.ItemId)
+	assert.Equal(t, uint32(4031017), s.Steps[1].Payload.(saga.DestroyAssetPayload).TemplateId)
+	assert.Equal(t, prizes, s.Steps[2].Payload.(saga.ResolvePrizeTablePayload).Prizes)
+}
+
+// TestAccountMerge tests that the account merge template validates ownership before transferring and verifies last
+func TestAccountMerge(t *testing.T) {
+	s := AccountMerge("admin", 0, 100, 200, []uint32{1, 2}).Build()
+
+	assert.Equal(t, saga.AccountMerge, s.SagaType)
+	require.Len(t, s.Steps, 5)
+	assert.Equal(t, saga.ValidateCharacterState, s.Steps[0].Action)
+	assert.Equal(t, saga.ValidateCharacterState, s.Steps[1].Action)
+	assert.Equal(t, saga.TransferCharacter, s.Steps[2].Action)
+	assert.Equal(t, saga.TransferCharacter, s.Steps[3].Action)
+	assert.Equal(t, saga.VerifyAccountMerge, s.Steps[4].Action)
+
+	condition := s.Steps[0].Payload.(saga.ValidateCharacterStatePayload).Conditions[0]
+	assert.Equal(t, string(validation.AccountCondition), condition.Type)
+	assert.Equal(t, 100, condition.Value)
+	transfer := s.Steps[3].Payload.(saga.TransferCharacterPayload)
+	assert.Equal(t, uint32(2), transfer.CharacterId)
+	assert.Equal(t, uint32(200), transfer.TargetAccountId)
+	assert.Equal(t, []uint32{1, 2}, s.Steps[4].Payload.(saga.VerifyAccountMergePayload).CharacterIds)
+}
+
+// TestItemRestoration tests that the item restoration template is labelled for audit and requires approval
+func TestItemRestoration(t *testing.T) {
+	p := saga.RestoreAssetPayload{
+		CharacterId: 1,
+		TemplateId:  1302000,
+		Quantity:    1,
+		Flag:        1,
+		Evidence:    saga.LossEvidence{TicketId: "CS-1024", Reason: "failed trade"},
+	}
+	s := ItemRestoration("gm-alice", p).Build()
+
+	assert.Equal(t, saga.ItemRestoration, s.SagaType)
+	assert.Equal(t, "gm-alice", s.InitiatedBy)
+	assert.Equal(t, map[string]string{SupportTicketLabel: "CS-1024"}, s.Labels)
+	assert.True(t, s.RequiresApproval)
+	require.Len(t, s.Steps, 1)
+	assert.Equal(t, saga.RestoreAsset, s.Steps[0].Action)
+	assert.Equal(t, p, s.Steps[0].Payload.(saga.RestoreAssetPayload))
+}
+
+// TestCharacterRollback tests that the character rollback template restores inventory from the same snapshot
+func TestCharacterRollback(t *testing.T) {
+	s := CharacterRollback("gm-alice", 0, 1, 77).Build()
+
+	assert.Equal(t, saga.CharacterRollback, s.SagaType)
+	require.Len(t, s.Steps, 6)
+	assert.Equal(t, saga.RollbackCharacterToSnapshot, s.Steps[0].Action)
+	assert.Equal(t, uint32(77), s.Steps[0].Payload.(saga.RollbackCharacterToSnapshotPayload).SnapshotId)
+	for i, st := range s.Steps[1:] {
+		assert.Equal(t, saga.RestoreInventorySnapshot, st.Action)
+		restore := st.Payload.(saga.RestoreInventorySnapshotPayload)
+		assert.Equal(t, byte(i+1), restore.InventoryType)
+		assert.Equal(t, uint32(77), restore.SnapshotId)
+	}
+
+	s = CharacterRollback("gm-alice", 0, 1, 77, inventory.TypeValueUse).Build()
+	require.Len(t, s.Steps, 2)
+	assert.Equal(t, byte(inventory.TypeValueUse), s.Steps[1].Payload.(saga.RestoreInventorySnapshotPayload).InventoryType)
+}
+
+func TestCouponRedemption(t *testing.T) {
+	s := CouponRedemption("character-1", 1, 2, 0, 1, "SUMMER-2026").Build()
+
+	assert.Equal(t, saga.CouponRedemption, s.SagaType)
+	require.Len(t, s.Steps, 1)
+	assert.Equal(t, saga.ValidateCoupon, s.Steps[0].Action)
+	validate := s.Steps[0].Payload.(saga.ValidateCouponPayload)
+	assert.Equal(t, uint32(2), validate.AccountId)
+	assert.Equal(t, "SUMMER-2026", validate.Code)
+	assert.Nil(t, validate.Rewards)
+}
+
+func TestDeathPenalty(t *testing.T) {
+	s := DeathPenalty("channel-reaper", 0, 1, 12345, 1500, 10).Build()
+
+	assert.Equal(t, saga.DeathPenalty, s.SagaType)
+	require.Len(t, s.Steps, 3)
+	assert.Equal(t, saga.ApplyCharacterExpPenalty, s.Steps[0].Action)
+	assert.Equal(t, uint32(1500), s.Steps[0].Payload.(saga.ApplyCharacterExpPenaltyPayload).Amount)
+	assert.Equal(t, saga.ApplyDurabilityPenalty, s.Steps[1].Action)
+	assert.Equal(t, byte(10), s.Steps[1].Payload.(saga.ApplyDurabilityPenaltyPayload).Percent)
+	assert.Equal(t, saga.CharacterBuffCleanse, s.Steps[2].Action)
+
+	// Penalties of 0, as for a beginner, are omitted
+	s = DeathPenalty("channel-reaper", 0, 1, 12345, 0, 0).Build()
+	require.Len(t, s.Steps, 1)
+	assert.Equal(t, saga.CharacterBuffCleanse, s.Steps[0].Action)
+}
+
+func TestCharacterSlotPurchase(t *testing.T) {
+	s := CharacterSlotPurchase("cash-shop", 7, 1, 2, &saga.CharacterCreatePayload{Name: "Slotted", JobId: 0}).Build()
+
+	assert.Equal(t, saga.CharacterSlotPurchase, s.SagaType)
+	require.Len(t, s.Steps, 2)
+	assert.Equal(t, saga.CreateAccountCharacterSlotPayload{AccountId: 7, WorldId: 1, Amount: 2}, s.Steps[0].Payload)
+	assert.Equal(t, saga.CreateCharacter, s.Steps[1].Action)
+	p := s.Steps[1].Payload.(saga.CharacterCreatePayload)
+	assert.Equal(t, uint32(7), p.AccountId)
+	assert.Equal(t, byte(1), p.WorldId)
+
+	// The slots may be purchased alone
+	s = CharacterSlotPurchase("cash-shop", 7, 1, 1, nil).Build()
+	require.Len(t, s.Steps, 1)
+	assert.Equal(t, saga.CreateAccountCharacterSlot, s.Steps[0].Action)
+}
+
+func TestGuildEmblemPurchase(t *testing.T) {
+	s := GuildEmblemPurchase("npc-2010008", 0, 1, 12345, 2010008, 5000000).Build()
+
+	assert.Equal(t, saga.GuildEmblemPurchase, s.SagaType)
+	require.Len(t, s.Steps, 4)
+	assert.Equal(t, []saga.Action{saga.ValidateCharacterState, saga.DeductMesos, saga.RequestGuildEmblem, saga.EmitAnalyticsEvent},
+		[]saga.Action{s.Steps[0].Action, s.Steps[1].Action, s.Steps[2].Action, s.Steps[3].Action})
+	conditions := s.Steps[0].Payload.(saga.ValidateCharacterStatePayload).Conditions
+	require.Len(t, conditions, 2)
+	assert.Equal(t, string(validation.GuildLeaderCondition), conditions[0].Type)
+	assert.Equal(t, 5000000, conditions[1].Value)
+	assert.Equal(t, saga.DeductMesosPayload{CharacterId: 12345, WorldId: 0, ChannelId: 1, ActorId: 2010008, ActorType: "NPC", Amount: 5000000}, s.Steps[1].Payload)
+	assert.Equal(t, saga.RequestGuildEmblemPayload{CharacterId: 12345, WorldId: 0, ChannelId: 1}, s.Steps[2].Payload)
+
+	// A free emblem change is not charged
+	s = GuildEmblemPurchase("npc-2010008", 0, 1, 12345, 2010008, 0).Build()
+	require.Len(t, s.Steps, 3)
+	require.Len(t, s.Steps[0].Payload.(saga.ValidateCharacterStatePayload).Conditions, 1)
+	assert.Equal(t, saga.RequestGuildEmblem, s.Steps[1].Action)
+}
+
+func TestDivorce(t *testing.T) {
+	s := Divorce("npc-9201002", 0, 1, 12345, 500000, false).Build()
+
+	assert.Equal(t, saga.Divorce, s.SagaType)
+	require.Len(t, s.Steps, 1)
+	assert.Equal(t, saga.ValidateDivorce, s.Steps[0].Action)
+	validate := s.Steps[0].Payload.(saga.ValidateDivorcePayload)
+	assert.Equal(t, uint32(12345), validate.CharacterId)
+	assert.Equal(t, uint32(500000), validate.Fee)
+	assert.False(t, validate.KeepRings)
+	assert.Nil(t, validate.Marriage)
+}
+
+func TestDisputeHold(t *testing.T) {
+	assets := []saga.SealAssetPayload{
+		{CharacterId: 1, InventoryType: 1, Slot: 3, TemplateId: 1302000, Quantity: 1},
+		{CharacterId: 2, InventoryType: 4, Slot: 7, TemplateId: 4000000, Quantity: 50},
+	}
+	s := DisputeHold("gm-alice", "CS-2048", 0, 1, assets, []HeldMesos{{CharacterId: 1, Amount: 1000000}}).Build()
+
+	assert.Equal(t, saga.DisputeHold, s.SagaType)
+	assert.Equal(t, map[string]string{SupportTicketLabel: "CS-2048"}, s.Labels)
+	require.Len(t, s.Steps, 4)
+	assert.Equal(t, saga.SealAsset, s.Steps[0].Action)
+	assert.Equal(t, assets[1], s.Steps[1].Payload.(saga.SealAssetPayload))
+	assert.Equal(t, saga.DeductMesos, s.Steps[2].Action)
+	assert.Equal(t, uint32(1000000), s.Steps[2].Payload.(saga.DeductMesosPayload).Amount)
+
+	resolve := s.Steps[3]
+	assert.Equal(t, DisputeStepId, resolve.StepId)
+	assert.Equal(t, saga.ResolveDispute, resolve.Action)
+	assert.Equal(t, "CS-2048", resolve.Payload.(saga.ResolveDisputePayload).TicketId)
+	require.Len(t, resolve.Branches, 2)
+
+	release := resolve.Branches[0]
+	assert.Equal(t, ResolutionRelease, release.Name)
+	assert.Equal(t, ResolutionRelease, release.Equals)
+	require.Len(t, release.Steps, 3)
+	assert.Equal(t, saga.UnsealAsset, release.Steps[0].Action)
+	assert.Equal(t, int16(7), release.Steps[1].Payload.(saga.UnsealAssetPayload).Slot)
+	assert.Equal(t, int32(1000000), release.Steps[2].Payload.(saga.AwardMesosPayload).Amount)
+
+	confiscate := resolve.Branches[1]
+	assert.Equal(t, ResolutionConfiscate, confiscate.Name)
+	require.Len(t, confiscate.Steps, 2)
+	assert.Equal(t, saga.ConfiscateAssetPayload{CharacterId: 2, InventoryType: 4, Slot: 7}, confiscate.Steps[1].Payload)
+	assert.NoError(t, s.ValidateBranches())
+}
+
+func TestTransport(t *testing.T) {
+	fieldId := field.Id("0:1:200000100:00000000-0000-0000-0000-000000000000")
+	s := Taxi("npc-1002000", 0, 1, 12345, 1000, fieldId, 2).Build()
+	assert.Equal(t, saga.Transport, s.SagaType)
+	require.Len(t, s.Steps, 2)
+	assert.Equal(t, saga.DeductMesos, s.Steps[0].Action)
+	assert.Equal(t, uint32(1000), s.Steps[0].Payload.(saga.DeductMesosPayload).Amount)
+	assert.Equal(t, saga.ScheduleWarp, s.Steps[1].Action)
+	assert.True(t, s.Steps[1].Payload.(saga.ScheduleWarpPayload).DepartsAt.IsZero())
+	assert.Equal(t, uint32(1000), s.Steps[1].Payload.(saga.ScheduleWarpPayload).Fare)
+
+	departsAt := time.Now().Add(10 * time.Minute)
+	s = ScheduledTransport("npc-2041000", 0, 1, 12345, 5000, fieldId, 0, departsAt).Build()
+	require.Len(t, s.Steps, 2)
+	assert.Equal(t, departsAt, s.Steps[1].Payload.(saga.ScheduleWarpPayload).DepartsAt)
+
+	// A free ticket is issued without charging a fare
+	s = TransportTicket("npc-2012000", 0, 1, 12345, 0, 4031045).Build()
+	require.Len(t, s.Steps, 1)
+	assert.Equal(t, saga.IssueTransportTicketPayload{CharacterId: 12345, ChannelId: 1, TemplateId: 4031045}, s.Steps[0].Payload)
+}
+
+func TestEscortQuest(t *testing.T) {
+	fieldId := field.Id("0:1:100000000:00000000-0000-0000-0000-000000000000")
+	s := EscortQuest("npc-1012112", 12345, fieldId, 1012112, 100000001, 300, 2).
+		AwardMesos(saga.AwardMesosPayload{CharacterId: 12345, ActorType: "NPC", Amount: 1000}).
+		Build()
+
+	assert.Equal(t, saga.EscortQuest, s.SagaType)
+	require.Len(t, s.Steps, 3)
+	assert.Equal(t, saga.SpawnEscort, s.Steps[0].Action)
+	assert.Equal(t, _map.Id(100000001), s.Steps[0].Payload.(saga.SpawnEscortPayload).DestinationMapId)
+	assert.Equal(t, saga.AwaitEscortPayload{CharacterId: 12345, FieldId: fieldId, NpcId: 1012112, Timeout: 300}, s.Steps[1].Payload)
+	require.Len(t, s.Steps[1].OnError, 1)
+	assert.Equal(t, saga.ErrorCodeEscortDied, s.Steps[1].OnError[0].ErrorCode)
+	assert.Equal(t, 3, s.Steps[1].OnError[0].MaxAttempts)
+	assert.Equal(t, s.Steps[0].Payload, s.Steps[1].OnError[0].Steps[0].Payload)
+
+	// Without retries, the death of the escort fails the quest
+	s = EscortQuest("npc-1012112", 12345, fieldId, 1012112, 100000001, 0, 0).Build()
+	require.Len(t, s.Steps, 2)
+	assert.Empty(t, s.Steps[1].OnError)
+}
+
+// TestEventShopSettlement tests that the event shop settlement template settles the first page of currency holders
+func TestEventShopSettlement(t *testing.T) {
+	s := EventShopSettlement("event-halloween", 4001126, 2022179, 10, 25).Build()
+	assert.Equal(t, saga.EventShopSettlement, s.SagaType)
+	require.Len(t, s.Steps, 1)
+	assert.Equal(t, saga.SettleEventCurrency, s.Steps[0].Action)
+	assert.Equal(t, saga.SettleEventCurrencyPayload{CurrencyId: 4001126, RewardId: 2022179, Rate: 10, PageSize: 25}, s.Steps[0].Payload)
+	assert.NoError(t, s.ValidateType())
+}
+
+func TestJobReset(t *testing.T) {
+	s := JobReset("job-advancer", 0, 1, 12345, 0).Build()
+
+	assert.Equal(t, saga.JobReset, s.SagaType)
+	require.Len(t, s.Steps, 3)
+	assert.Equal(t, saga.SnapshotCharacter, s.Steps[0].Action)
+	assert.Equal(t, saga.ChangeJob, s.Steps[1].Action)
+	assert.Equal(t, saga.StripEquipment, s.Steps[2].Action)
+	assert.Equal(t, uint32(12345), s.Steps[2].Payload.(saga.StripEquipmentPayload).CharacterId)
+	assert.NoError(t, s.ValidateType())
+}
+
+func TestBranch(t *testing.T) {
+	s := NewBuilder(saga.QuestReward, "npc-9010000").
+		ResolvePrizeTable(saga.ResolvePrizeTablePayload{CharacterId: 12345, Prizes: []saga.PrizeEntry{{Weight: 1, Mesos: 1000}, {Weight: 9}}}).
+		Branch("won", "$.steps.resolve_prize_table_1.resolved.mesos", nil, func(b *Builder) {
+			b.SetVariableStep(saga.SetVariablePayload{Name: "won", Value: true})
+		}).
+		Branch("lost", "", nil, nil).
+		SetVariableStep(saga.SetVariablePayload{Name: "done", Value: true}).
+		Build()
+
+	require.Len(t, s.Steps, 2)
+	require.Len(t, s.Steps[0].Branches, 2)
+	won := s.Steps[0].Branches[0]
+	assert.Equal(t, "won", won.Name)
+	require.Len(t, won.Steps, 1)
+	assert.Equal(t, "set_variable_2", won.Steps[0].StepId)
+	assert.Empty(t, s.Steps[0].Branches[1].Steps)
+
+	// Step IDs generated within branches do not collide with those of the saga
+	assert.Equal(t, "set_variable_3", s.Steps[1].StepId)
+	assert.NoError(t, s.ValidateBranches())
+}
+
+func TestTutorialCompletion(t *testing.T) {
+	gates := []TutorialGate{
+		{MapId: 10000, Timeout: 120, Hint: "Head through the portal to the right.", Reminders: 2, Message: "Well done!", Items: []saga.ItemPayload{{TemplateId: 2000000, Quantity: 5}}, Experience: 20, FieldId: "0:1:20000", PortalId: 1},
+		{MapId: 20000, Message: "You have completed the tutorial."},
+	}
+	s := TutorialCompletion("tutorial", 0, 1, 12345, true, gates).Build()
+
+	assert.Equal(t, saga.TutorialCompletion, s.SagaType)
+	assert.Equal(t, true, s.Variables[FirstCompletionVariable])
+	require.Len(t, s.Steps, 5)
+	assert.Equal(t, "gate_1", s.Steps[0].StepId)
+	assert.Equal(t, saga.AwaitEvent, s.Steps[0].Action)
+	assert.Equal(t, uint32(120), s.Steps[0].Timeout)
+	require.Len(t, s.Steps[0].OnError, 1)
+	assert.Equal(t, saga.ErrorCodeStepTimeout, s.Steps[0].OnError[0].ErrorCode)
+	assert.Equal(t, 3, s.Steps[0].OnError[0].MaxAttempts)
+	require.Len(t, s.Steps[0].Branches, 2)
+	require.Len(t, s.Steps[0].Branches[0].Steps, 2)
+	assert.Equal(t, saga.AwardAsset, s.Steps[0].Branches[0].Steps[0].Action)
+	assert.Equal(t, saga.AwardExperience, s.Steps[0].Branches[0].Steps[1].Action)
+	assert.Empty(t, s.Steps[0].Branches[1].Steps)
+	assert.Equal(t, saga.Notify, s.Steps[1].Action)
+	assert.Equal(t, saga.WarpToPortal, s.Steps[2].Action)
+	assert.Equal(t, "gate_2", s.Steps[3].StepId)
+	assert.Empty(t, s.Steps[3].OnError)
+	assert.Empty(t, s.Steps[3].Branches)
+	assert.Equal(t, saga.Notify, s.Steps[4].Action)
+	assert.NoError(t, s.ValidateType())
+	assert.NoError(t, s.ValidateBranches())
+}
@@ -0,0 +1,60 @@
+package mock
+
+import (
+	"atlas-saga-orchestrator/saga"
+	"errors"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/google/uuid"
+	"time"
+)
+
+// ProcessorMock is a mock implementation of the client.Processor interface
+type ProcessorMock struct {
+	CreateFunc          func(s saga.Saga) (saga.Saga, error)
+	GetByIdFunc         func(transactionId uuid.UUID) (saga.Saga, error)
+	ByIdProviderFunc    func(transactionId uuid.UUID) model.Provider[saga.Saga]
+	InProgressFunc      func(transactionId uuid.UUID) (bool, error)
+	AwaitCompletionFunc func(transactionId uuid.UUID, timeout time.Duration) error
+}
+
+// Create is a mock implementation of the client.Processor.Create method
+func (m *ProcessorMock) Create(s saga.Saga) (saga.Saga, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(s)
+	}
+	return s, nil
+}
+
+// GetById is a mock implementation of the client.Processor.GetById method
+func (m *ProcessorMock) GetById(transactionId uuid.UUID) (saga.Saga, error) {
+	if m.GetByIdFunc != nil {
+		return m.GetByIdFunc(transactionId)
+	}
+	return saga.Saga{}, errors.New("saga not found")
+}
+
+// ByIdProvider is a mock implementation of the client.Processor.ByIdProvider method
+func (m *ProcessorMock) ByIdProvider(transactionId uuid.UUID) model.Provider[saga.Saga] {
+	if m.ByIdProviderFunc != nil {
+		return m.ByIdProviderFunc(transactionId)
+	}
+	return func() (saga.Saga, error) {
+		return m.GetById(transactionId)
+	}
+}
+
+// InProgress is a mock implementation of the client.Processor.InProgress method
+func (m *ProcessorMock) InProgress(transactionId uuid.UUID) (bool, error) {
+	if m.InProgressFunc != nil {
+		return m.InProgressFunc(transactionId)
+	}
+	return false, nil
+}
+
+// AwaitCompletion is a mock implementation of the client.Processor.AwaitCompletion method
+func (m *ProcessorMock) AwaitCompletion(transactionId uuid.UUID, timeout time.Duration) error {
+	if m.AwaitCompletionFunc != nil {
+		return m.AwaitCompletionFunc(transactionId, timeout)
+	}
+	return nil
+}
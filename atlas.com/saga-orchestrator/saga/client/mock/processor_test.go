@@ -0,0 +1,12 @@
+package mock
+
+import (
+	"atlas-saga-orchestrator/saga/client"
+	"testing"
+)
+
+// TestProcessorMockImplementsProcessor verifies that ProcessorMock implements the client.Processor interface
+func TestProcessorMockImplementsProcessor(t *testing.T) {
+	// This test will fail to compile if ProcessorMock doesn't implement client.Processor
+	var _ client.Processor = &ProcessorMock{}
+}
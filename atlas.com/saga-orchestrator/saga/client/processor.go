@@ -0,0 +1,120 @@
+package client
+
+import (
+	"atlas-saga-orchestrator/saga"
+	v2 "atlas-saga-orchestrator/saga/v2"
+	"context"
+	"errors"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/Chronicle20/atlas-rest/requests"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+// ErrAwaitTimeout is returned when a saga does not complete within the allotted time
+var ErrAwaitTimeout = errors.New("timed out awaiting saga completion")
+
+// awaitPollInterval is the interval at which saga progress is polled while awaiting completion
+const awaitPollInterval = 250 * time.Millisecond
+
+// Processor is the interface for initiating and tracking sagas through the saga orchestrator
+type Processor interface {
+	// Create submits a saga for execution, returning the saga as accepted by the orchestrator
+	Create(s saga.Saga) (saga.Saga, error)
+	GetById(transactionId uuid.UUID) (saga.Saga, error)
+	ByIdProvider(transactionId uuid.UUID) model.Provider[saga.Saga]
+	// InProgress reports whether the saga is still being executed by the orchestrator
+	InProgress(transactionId uuid.UUID) (bool, error)
+	// AwaitCompletion blocks until the saga is no longer in progress, or the timeout elapses
+	AwaitCompletion(transactionId uuid.UUID, timeout time.Duration) error
+}
+
+// ProcessorImpl is the implementation of the Processor interface
+type ProcessorImpl struct {
+	l   logrus.FieldLogger
+	ctx context.Context
+}
+
+// NewProcessor creates a new saga client processor
+func NewProcessor(l logrus.FieldLogger, ctx context.Context) Processor {
+	return &ProcessorImpl{
+		l:   l,
+		ctx: ctx,
+	}
+}
+
+// Create submits a saga for execution, returning the saga as accepted by the orchestrator
+func (p *ProcessorImpl) Create(s saga.Saga) (saga.Saga, error) {
+	rm, err := v2.Transform(s)
+	if err != nil {
+		return saga.Saga{}, err
+	}
+
+	resp, err := requestCreate(rm)(p.l, p.ctx)
+	if err != nil {
+		p.l.WithError(err).WithFields(logrus.Fields{
+			"transaction_id": s.TransactionId.String(),
+			"saga_type":      s.SagaType,
+		}).Error("Failed to create saga.")
+		return saga.Saga{}, err
+	}
+	return v2.Extract(resp)
+}
+
+// GetById returns a saga which is in progress by its transaction ID
+func (p *ProcessorImpl) GetById(transactionId uuid.UUID) (saga.Saga, error) {
+	return p.ByIdProvider(transactionId)()
+}
+
+func (p *ProcessorImpl) ByIdProvider(transactionId uuid.UUID) model.Provider[saga.Saga] {
+	return func() (saga.Saga, error) {
+		rm, err := requestById(transactionId)(p.l, p.ctx)
+		if err != nil {
+			return saga.Saga{}, err
+		}
+		return v2.Extract(rm)
+	}
+}
+
+// InProgress reports whether the saga is still being executed. Completed sagas are no longer retained by the orchestrator,
+// while compensated sagas are retained with their receipt.
+func (p *ProcessorImpl) InProgress(transactionId uuid.UUID) (bool, error) {
+	rm, err := requestById(transactionId)(p.l, p.ctx)
+	if errors.Is(err, requests.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return rm.Receipt == nil, nil
+}
+
+// AwaitCompletion blocks until the saga is no longer in progress, or the timeout elapses
+func (p *ProcessorImpl) AwaitCompletion(transactionId uuid.UUID, timeout time.Duration) error {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(awaitPollInterval)
+	defer ticker.Stop()
+
+	for {
+		inProgress, err := p.InProgress(transactionId)
+		if err != nil {
+			return err
+		}
+		if !inProgress {
+			return nil
+		}
+
+		select {
+		case <-p.ctx.Done():
+			return p.ctx.Err()
+		case <-deadline.C:
+			p.l.WithFields(logrus.Fields{
+				"transaction_id": transactionId.String(),
+			}).Debugf("Saga did not complete within [%s].", timeout)
+			return ErrAwaitTimeout
+		case <-ticker.C:
+		}
+	}
+}
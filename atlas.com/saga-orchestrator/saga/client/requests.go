@@ -0,0 +1,26 @@
+package client
+
+import (
+	"atlas-saga-orchestrator/rest"
+	v2 "atlas-saga-orchestrator/saga/v2"
+	"fmt"
+	"github.com/Chronicle20/atlas-rest/requests"
+	"github.com/google/uuid"
+)
+
+const (
+	sagasResource = "v2/sagas"
+	sagaById      = sagasResource + "/%s"
+)
+
+func getBaseRequest() string {
+	return requests.RootUrl("SAGA_ORCHESTRATOR")
+}
+
+func requestById(transactionId uuid.UUID) requests.Request[v2.RestModel] {
+	return rest.MakeGetRequest[v2.RestModel](fmt.Sprintf(getBaseRequest()+sagaById, transactionId.String()))
+}
+
+func requestCreate(body v2.RestModel) requests.Request[v2.RestModel] {
+	return rest.MakePostRequest[v2.RestModel](getBaseRequest()+sagasResource, body)
+}
@@ -0,0 +1,424 @@
+package client
+
+import (
+	"atlas-saga-orchestrator/saga"
+	"atlas-saga-orchestrator/validation"
+	"fmt"
+	"github.com/Chronicle20/atlas-constants/channel"
+	"github.com/Chronicle20/atlas-constants/field"
+	"github.com/Chronicle20/atlas-constants/inventory"
+	"github.com/Chronicle20/atlas-constants/job"
+	_map "github.com/Chronicle20/atlas-constants/map"
+	"github.com/Chronicle20/atlas-constants/world"
+	"time"
+)
+
+// SupportTicketLabel is the label identifying the support ticket a saga was initiated for
+const SupportTicketLabel = "support_ticket"
+
+// Constants for the resolutions of a dispute hold, naming the branches of its resolve_dispute step
+const (
+	DisputeStepId        = "resolve"    // StepId of the resolve_dispute step of a dispute hold
+	ResolutionRelease    = "release"    // The held assets are unsealed and the held mesos refunded
+	ResolutionConfiscate = "confiscate" // The held assets are consumed and the held mesos retained
+)
+
+// HeldMesos identifies mesos of a character held by a dispute
+type HeldMesos struct {
+	CharacterId uint32 // CharacterId whose mesos are held
+	Amount      uint32 // Amount of mesos held
+}
+
+// MinigameReward returns a builder for a minigame payout. The saga validates the character holds the ticket item,
+// consumes it, then draws a prize from the prize table and awards it. Further steps may be added before building.
+func MinigameReward(initiatedBy string, characterId uint32, worldId world.Id, channelId channel.Id, ticketId uint32, prizes []saga.PrizeEntry) *Builder {
+	return NewBuilder(saga.MinigameReward, initiatedBy).
+		ValidateCharacterState(saga.ValidateCharacterStatePayload{
+			CharacterId: characterId,
+			Conditions: []validation.ConditionInput{{
+				Type:     string(validation.ItemCondition),
+				Operator: string(validation.GreaterEqual),
+				Value:    1,
+				ItemId:   ticketId,
+			}},
+		}).
+		DestroyAsset(saga.DestroyAssetPayload{
+			CharacterId: characterId,
+			TemplateId:  ticketId,
+			Quantity:    1,
+		}).
+		ResolvePrizeTable(saga.ResolvePrizeTablePayload{
+			CharacterId: characterId,
+			WorldId:     worldId,
+			ChannelId:   channelId,
+			Prizes:      prizes,
+		})
+}
+
+// AccountMerge returns a builder for re-parenting characters of one account to another. The saga validates each
+// character is owned by the source account, transfers each character in turn, then verifies the target account owns
+// them all.
+func AccountMerge(initiatedBy string, worldId world.Id, sourceAccountId uint32, targetAccountId uint32, characterIds []uint32) *Builder {
+	b := NewBuilder(saga.AccountMerge, initiatedBy)
+	for _, id := range characterIds {
+		b.ValidateCharacterState(saga.ValidateCharacterStatePayload{
+			CharacterId: id,
+			Conditions: []validation.ConditionInput{{
+				Type:     string(validation.AccountCondition),
+				Operator: string(validation.Equals),
+				Value:    int(sourceAccountId),
+			}},
+		})
+	}
+	for _, id := range characterIds {
+		b.TransferCharacter(saga.TransferCharacterPayload{
+			CharacterId:     id,
+			WorldId:         worldId,
+			SourceAccountId: sourceAccountId,
+			TargetAccountId: targetAccountId,
+		})
+	}
+	return b.VerifyAccountMerge(saga.VerifyAccountMergePayload{
+		TargetAccountId: targetAccountId,
+		CharacterIds:    characterIds,
+	})
+}
+
+// CharacterRollback returns a builder for restoring a character to a prior snapshot, as after a dupe or exploit. The
+// saga rolls the character back, then restores each of the given inventory types from the same snapshot, so the
+// character's state and inventory are restored together. When no inventory types are given, all are restored.
+func CharacterRollback(initiatedBy string, worldId world.Id, characterId uint32, snapshotId uint32, inventoryTypes ...inventory.Type) *Builder {
+	if len(inventoryTypes) == 0 {
+		inventoryTypes = []inventory.Type{inventory.TypeValueEquip, inventory.TypeValueUse, inventory.TypeValueSetup, inventory.TypeValueETC, inventory.TypeValueCash}
+	}
+	b := NewBuilder(saga.CharacterRollback, initiatedBy).
+		RollbackCharacterToSnapshot(saga.RollbackCharacterToSnapshotPayload{
+			CharacterId: characterId,
+			WorldId:     worldId,
+			SnapshotId:  snapshotId,
+		})
+	for _, it := range inventoryTypes {
+		b.RestoreInventorySnapshot(saga.RestoreInventorySnapshotPayload{
+			CharacterId:   characterId,
+			InventoryType: byte(it),
+			SnapshotId:    snapshotId,
+		})
+	}
+	return b
+}
+
+// ItemRestoration returns a builder for restoring an asset a character lost, replacing ad-hoc GM commands. The saga
+// validates the loss evidence and restores the asset with its captured reference data. It is labelled with the support
+// ticket for audit, and held until a second operator approves it.
+func ItemRestoration(initiatedBy string, p saga.RestoreAssetPayload) *Builder {
+	return NewBuilder(saga.ItemRestoration, initiatedBy).
+		SetLabel(SupportTicketLabel, p.Evidence.TicketId).
+		SetRequiresApproval().
+		RestoreAsset(p)
+}
+
+// CouponRedemption returns a builder for redeeming a coupon code. The saga validates the code is redeemable by the
+// account, then consumes it and awards its attached rewards through steps added once validated. Should an award fail,
+// the coupon is released so it may be redeemed again.
+func CouponRedemption(initiatedBy string, characterId uint32, accountId uint32, worldId world.Id, channelId channel.Id, code string) *Builder {
+	return NewBuilder(saga.CouponRedemption, initiatedBy).
+		ValidateCoupon(saga.ValidateCouponPayload{
+			CharacterId: characterId,
+			AccountId:   accountId,
+			WorldId:     worldId,
+			ChannelId:   channelId,
+			Code:        code,
+		})
+}
+
+// DeathPenalty returns a builder for the penalties applied when a character dies, so the channel service's reaper is
+// able to delegate them and have every leg compensated should any fail. The saga deducts the experience lost, reduces
+// the durability of equipped items by the given percent, and cancels the character's buffs. Penalties of 0 are omitted.
+func DeathPenalty(initiatedBy string, worldId world.Id, channelId channel.Id, characterId uint32, expLoss uint32, durabilityLoss byte) *Builder {
+	b := NewBuilder(saga.DeathPenalty, initiatedBy)
+	if expLoss > 0 {
+		b.ApplyCharacterExpPenalty(saga.ApplyCharacterExpPenaltyPayload{
+			CharacterId: characterId,
+			WorldId:     worldId,
+			ChannelId:   channelId,
+			Amount:      expLoss,
+		})
+	}
+	if durabilityLoss > 0 {
+		b.ApplyDurabilityPenalty(saga.ApplyDurabilityPenaltyPayload{
+			CharacterId: characterId,
+			Percent:     durabilityLoss,
+		})
+	}
+	return b.CharacterBuffCleanse(saga.CharacterBuffCleansePayload{
+		CharacterId: characterId,
+		WorldId:     worldId,
+		ChannelId:   channelId,
+	})
+}
+
+// CharacterSlotPurchase returns a builder for a cash shop purchase of character slots, adding the slots to the account
+// in the world. When a character is given, it is created in the same saga, so the slots are removed again should its
+// creation fail.
+func CharacterSlotPurchase(initiatedBy string, accountId uint32, worldId world.Id, amount byte, character *saga.CharacterCreatePayload) *Builder {
+	b := NewBuilder(saga.CharacterSlotPurchase, initiatedBy).
+		CreateAccountCharacterSlot(saga.CreateAccountCharacterSlotPayload{
+			AccountId: accountId,
+			WorldId:   worldId,
+			Amount:    amount,
+		})
+	if character == nil {
+		return b
+	}
+	p := *character
+	p.AccountId = accountId
+	p.WorldId = byte(worldId)
+	return b.CreateCharacter(p)
+}
+
+// GuildEmblemPurchase returns a builder for a guild leader purchasing a new guild emblem from the guild NPC. The saga
+// validates the character leads their guild and holds the fee, deducts it, then requests the new emblem and records
+// the purchase once the emblem is updated. Should the emblem update fail, the fee is refunded. A fee of 0 is not
+// charged.
+func GuildEmblemPurchase(initiatedBy string, worldId world.Id, channelId channel.Id, characterId uint32, npcId uint32, fee uint32) *Builder {
+	conditions := []validation.ConditionInput{{
+		Type:     string(validation.GuildLeaderCondition),
+		Operator: string(validation.Equals),
+		Value:    1,
+	}}
+	if fee > 0 {
+		conditions = append(conditions, validation.ConditionInput{
+			Type:     string(validation.MesoCondition),
+			Operator: string(validation.GreaterEqual),
+			Value:    int(fee),
+		})
+	}
+	b := NewBuilder(saga.GuildEmblemPurchase, initiatedBy).
+		ValidateCharacterState(saga.ValidateCharacterStatePayload{
+			CharacterId: characterId,
+			Conditions:  conditions,
+		})
+	if fee > 0 {
+		b.DeductMesos(saga.DeductMesosPayload{
+			CharacterId: characterId,
+			WorldId:     worldId,
+			ChannelId:   channelId,
+			ActorId:     npcId,
+			ActorType:   "NPC",
+			Amount:      fee,
+		})
+	}
+	return b.RequestGuildEmblem(saga.RequestGuildEmblemPayload{
+		CharacterId: characterId,
+		WorldId:     byte(worldId),
+		ChannelId:   byte(channelId),
+	}).
+		EmitAnalyticsEvent(saga.EmitAnalyticsEventPayload{
+			Name:       "guild_emblem_purchased",
+			Properties: map[string]any{"characterId": characterId, "fee": fee},
+		})
+}
+
+// Divorce returns a builder for a character divorcing their partner at the wedding NPC. The saga validates the
+// character is married, then deducts the fee, dissolves the marriage, and destroys the couple's wedding rings through
+// steps added once validated. Should the marriage fail to be dissolved, the fee is refunded. A fee of 0 is not charged,
+// and the rings are kept when keepRings is set.
+func Divorce(initiatedBy string, worldId world.Id, channelId channel.Id, characterId uint32, fee uint32, keepRings bool) *Builder {
+	return NewBuilder(saga.Divorce, initiatedBy).
+		ValidateDivorce(saga.ValidateDivorcePayload{
+			CharacterId: characterId,
+			WorldId:     worldId,
+			ChannelId:   channelId,
+			Fee:         fee,
+			KeepRings:   keepRings,
+		})
+}
+
+// DisputeHold returns a builder for holding a character's assets and mesos while support resolves a dispute. The saga
+// seals each asset, so it cannot be traded or dropped, and escrows the mesos by deducting them, then is held until an
+// operator resolves the dispute. A release unseals the assets and refunds the mesos, while a confiscation consumes the
+// assets and retains the mesos.
+func DisputeHold(initiatedBy string, ticketId string, worldId world.Id, channelId channel.Id, assets []saga.SealAssetPayload, mesos []HeldMesos) *Builder {
+	b := NewBuilder(saga.DisputeHold, initiatedBy).SetLabel(SupportTicketLabel, ticketId)
+	for _, a := range assets {
+		b.SealAsset(a)
+	}
+	for _, m := range mesos {
+		b.DeductMesos(saga.DeductMesosPayload{CharacterId: m.CharacterId, WorldId: worldId, ChannelId: channelId, ActorType: "SYSTEM", Amount: m.Amount})
+	}
+
+	when := "$.steps." + DisputeStepId + ".resolution"
+	return b.AddStep(DisputeStepId, saga.ResolveDispute, saga.ResolveDisputePayload{TicketId: ticketId}).
+		Branch(ResolutionRelease, when, ResolutionRelease, func(rb *Builder) {
+			for _, a := range assets {
+				rb.UnsealAsset(saga.UnsealAssetPayload{CharacterId: a.CharacterId, InventoryType: a.InventoryType, Slot: a.Slot, TemplateId: a.TemplateId, Quantity: a.Quantity})
+			}
+			for _, m := range mesos {
+				rb.AwardMesos(saga.AwardMesosPayload{CharacterId: m.CharacterId, WorldId: worldId, ChannelId: channelId, ActorType: "SYSTEM", Amount: int32(m.Amount)})
+			}
+		}).
+		Branch(ResolutionConfiscate, when, ResolutionConfiscate, func(cb *Builder) {
+			for _, a := range assets {
+				cb.ConfiscateAsset(saga.ConfiscateAssetPayload{CharacterId: a.CharacterId, InventoryType: a.InventoryType, Slot: a.Slot})
+			}
+		})
+}
+
+// Taxi returns a builder for a taxi ride. The saga deducts the fare, then warps the character to the destination
+// immediately. Should the character not arrive, the fare is refunded. A fare of 0 is not charged.
+func Taxi(initiatedBy string, worldId world.Id, channelId channel.Id, characterId uint32, fare uint32, fieldId field.Id, portalId uint32) *Builder {
+	return ScheduledTransport(initiatedBy, worldId, channelId, characterId, fare, fieldId, portalId, time.Time{})
+}
+
+// ScheduledTransport returns a builder for boarding a transport departing at a set time (e.g. a ship). The saga deducts
+// the fare, then warps the character to the destination once the departure time is reached. Should the character not
+// arrive, the fare is refunded. A fare of 0 is not charged.
+func ScheduledTransport(initiatedBy string, worldId world.Id, channelId channel.Id, characterId uint32, fare uint32, fieldId field.Id, portalId uint32, departsAt time.Time) *Builder {
+	return chargeFare(NewBuilder(saga.Transport, initiatedBy), worldId, channelId, characterId, fare).
+		ScheduleWarp(saga.ScheduleWarpPayload{
+			CharacterId: characterId,
+			WorldId:     worldId,
+			ChannelId:   channelId,
+			FieldId:     fieldId,
+			PortalId:    portalId,
+			DepartsAt:   departsAt,
+			Fare:        fare,
+		})
+}
+
+// TransportTicket returns a builder for purchasing the ticket item of a transport (e.g. a ship ticket). The saga deducts
+// the fare, then issues the ticket. Should the ticket not be issued, the fare is refunded. A fare of 0 is not charged.
+func TransportTicket(initiatedBy string, worldId world.Id, channelId channel.Id, characterId uint32, fare uint32, templateId uint32) *Builder {
+	return chargeFare(NewBuilder(saga.Transport, initiatedBy), worldId, channelId, characterId, fare).
+		IssueTransportTicket(saga.IssueTransportTicketPayload{
+			CharacterId: characterId,
+			WorldId:     worldId,
+			ChannelId:   channelId,
+			TemplateId:  templateId,
+			Fare:        fare,
+		})
+}
+
+// chargeFare adds a deduct_mesos step charging the fare of a transport, unless it is 0
+func chargeFare(b *Builder, worldId world.Id, channelId channel.Id, characterId uint32, fare uint32) *Builder {
+	if fare == 0 {
+		return b
+	}
+	return b.DeductMesos(saga.DeductMesosPayload{CharacterId: characterId, WorldId: worldId, ChannelId: channelId, ActorType: "SYSTEM", Amount: fare})
+}
+
+// EscortQuest returns a builder for an escort quest. The saga spawns the escort in the field, then awaits its arrival at
+// the destination map within the timeout (in seconds, 0 awaiting indefinitely). Should the escort die, it is spawned
+// again and awaited anew, up to retries times. Rewards for the escort's arrival are added to the returned builder.
+func EscortQuest(initiatedBy string, characterId uint32, fieldId field.Id, npcId uint32, destinationMapId _map.Id, timeout uint32, retries int) *Builder {
+	spawn := saga.SpawnEscortPayload{CharacterId: characterId, FieldId: fieldId, NpcId: npcId, DestinationMapId: destinationMapId}
+	b := NewBuilder(saga.EscortQuest, initiatedBy).
+		SpawnEscort(spawn).
+		AwaitEscort(saga.AwaitEscortPayload{CharacterId: characterId, FieldId: fieldId, NpcId: npcId, Timeout: timeout})
+	if retries > 0 {
+		b.OnError(saga.ErrorHandler{
+			ErrorCode:   saga.ErrorCodeEscortDied,
+			Reaction:    saga.ErrorReactionRetry,
+			Steps:       []saga.Step[any]{{StepId: "respawn_escort", Action: saga.SpawnEscort, Payload: spawn}},
+			MaxAttempts: retries + 1,
+		})
+	}
+	return b
+}
+
+// EventShopSettlement returns a builder for settling an event shop once its event has ended. The saga lists the
+// characters holding the event's currency a page at a time, destroying the currency each holds and awarding one
+// consolation reward for each rate units of it, until every holder is settled. A page size of 0 settles
+// saga.DefaultSettlementPageSize holders per page.
+func EventShopSettlement(initiatedBy string, currencyId uint32, rewardId uint32, rate uint32, pageSize uint32) *Builder {
+	return NewBuilder(saga.EventShopSettlement, initiatedBy).
+		SettleEventCurrency(saga.SettleEventCurrencyPayload{
+			CurrencyId: currencyId,
+			RewardId:   rewardId,
+			Rate:       rate,
+			PageSize:   pageSize,
+		})
+}
+
+// JobReset returns a builder for resetting a character to a job, such as a beginner. The saga takes a snapshot of the
+// character, changes its job, then unequips the items the job may not wear. Should an item fail to be unequipped, those
+// unequipped are equipped again, and the character is restored to the snapshot.
+func JobReset(initiatedBy string, worldId world.Id, channelId channel.Id, characterId uint32, jobId job.Id) *Builder {
+	return NewBuilder(saga.JobReset, initiatedBy).
+		SnapshotCharacter(saga.SnapshotCharacterPayload{
+			CharacterId: characterId,
+			WorldId:     worldId,
+			ChannelId:   channelId,
+		}).
+		ChangeJob(saga.ChangeJobPayload{
+			CharacterId: characterId,
+			WorldId:     worldId,
+			ChannelId:   channelId,
+			JobId:       jobId,
+		}).
+		StripEquipment(saga.StripEquipmentPayload{
+			CharacterId: characterId,
+			JobId:       jobId,
+		})
+}
+
+// FirstCompletionVariable is the variable of a tutorial completion saga recording whether the character is completing
+// the tutorial for the first time, which the rewards of its gates are conditional on
+const FirstCompletionVariable = "firstCompletion"
+
+// TutorialGate is a gate of a scripted tutorial sequence, passed once the character enters its map
+type TutorialGate struct {
+	MapId      _map.Id            // Map the character enters to pass the gate
+	Timeout    uint32             // Seconds the character may take to enter the map, 0 awaiting indefinitely
+	Hint       string             // Message shown each time the timeout elapses, after which the map is awaited anew
+	Reminders  int                // Times the hint is shown before the saga fails, when the timeout elapses
+	Message    string             // Message shown once the gate is passed. Omitted when empty.
+	Items      []saga.ItemPayload // Items awarded once the gate is passed, on the character's first completion only
+	Experience uint32             // Experience awarded once the gate is passed, on the character's first completion only
+	FieldId    field.Id           // Field the character is warped to once the gate is passed. Omitted when empty.
+	PortalId   uint32             // Portal of the field the character is warped to
+}
+
+// TutorialCompletion returns a builder for driving a character through a scripted tutorial sequence. For each gate in
+// turn, the saga awaits the character entering the gate's map, showing its hint and awaiting anew should the timeout
+// elapse, then awards the gate's rewards when the character completes the tutorial for the first time, notifies the
+// character, and warps the character onwards.
+func TutorialCompletion(initiatedBy string, worldId world.Id, channelId channel.Id, characterId uint32, firstCompletion bool, gates []TutorialGate) *Builder {
+	b := NewBuilder(saga.TutorialCompletion, initiatedBy).SetVariable(FirstCompletionVariable, firstCompletion)
+	for i, g := range gates {
+		stepId := fmt.Sprintf("gate_%d", i+1)
+		b.AddStep(stepId, saga.AwaitEvent, saga.AwaitEventPayload{CharacterId: characterId, Event: saga.AwaitedEventMapEntry, MapId: g.MapId})
+		b.Timeout(g.Timeout)
+		if g.Timeout > 0 && g.Hint != "" && g.Reminders > 0 {
+			b.OnError(saga.ErrorHandler{
+				ErrorCode:   saga.ErrorCodeStepTimeout,
+				Reaction:    saga.ErrorReactionRetry,
+				Steps:       []saga.Step[any]{{StepId: stepId + "_hint", Action: saga.Notify, Payload: saga.NotifyPayload{CharacterId: characterId, WorldId: worldId, ChannelId: channelId, Message: g.Hint}}},
+				MaxAttempts: g.Reminders + 1,
+			})
+		}
+		if len(g.Items) > 0 || g.Experience > 0 {
+			b.Branch("rewarded", "$.variables."+FirstCompletionVariable, nil, func(rb *Builder) {
+				for _, it := range g.Items {
+					rb.AwardAsset(saga.AwardItemActionPayload{CharacterId: characterId, Item: it})
+				}
+				if g.Experience > 0 {
+					rb.AwardExperience(saga.AwardExperiencePayload{
+						CharacterId:   characterId,
+						WorldId:       worldId,
+						ChannelId:     channelId,
+						Distributions: []saga.ExperienceDistributions{{ExperienceType: "WHITE", Amount: g.Experience}},
+					})
+				}
+			}).Branch("unrewarded", "", nil, nil)
+		}
+
+		if g.Message != "" {
+			b.Notify(saga.NotifyPayload{CharacterId: characterId, WorldId: worldId, ChannelId: channelId, Message: g.Message})
+		}
+		if g.FieldId != "" {
+			b.WarpToPortal(saga.WarpToPortalPayload{CharacterId: characterId, FieldId: g.FieldId, PortalId: g.PortalId})
+		}
+	}
+	return b
+}
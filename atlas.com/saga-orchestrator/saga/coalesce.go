@@ -0,0 +1,71 @@
+package saga
+
+import (
+	"math"
+
+	"github.com/Chronicle20/atlas-constants/inventory"
+	"github.com/Chronicle20/atlas-constants/item"
+)
+
+// coalesceKey identifies award steps which may be coalesced
+type coalesceKey struct {
+	action      Action
+	characterId uint32
+	templateId  uint32
+}
+
+// Stackable returns whether items of the template stack in a single slot, so that awards of them may be coalesced.
+// Equipment and cash items, which are distinct assets even when of the same template, do not.
+func Stackable(templateId uint32) bool {
+	t, ok := inventory.TypeFromItemId(item.Id(templateId))
+	if !ok {
+		return false
+	}
+	return t == inventory.TypeValueUse || t == inventory.TypeValueSetup || t == inventory.TypeValueETC
+}
+
+// CoalesceAwardSteps coalesces pending award_asset steps awarding the same stackable item to the same character into
+// the first of them, with their quantities summed, when the saga opts in with coalesceAwards. Steps with payload
+// templates, captures, error handlers or branches are not coalesced, as they behave differently from the steps they would be
+// merged into, nor are steps declaring dependencies or depended on, as their order is constrained. Returns the number of
+// steps removed.
+func (s *Saga) CoalesceAwardSteps() int {
+	if !s.CoalesceAwards {
+		return 0
+	}
+
+	dependedOn := make(map[string]bool)
+	for _, st := range s.Steps {
+		for _, dep := range st.DependsOn {
+			dependedOn[dep] = true
+		}
+	}
+
+	firsts := make(map[coalesceKey]int)
+	steps := make([]Step[any], 0, len(s.Steps))
+	for _, st := range s.Steps {
+		payload, ok := st.Payload.(AwardItemActionPayload)
+		if !ok || (st.Action != AwardAsset && st.Action != AwardInventory) || st.Status != Pending ||
+			len(st.PayloadTemplate) > 0 || len(st.Capture) > 0 || len(st.OnError) > 0 || len(st.Branches) > 0 ||
+			len(st.DependsOn) > 0 || dependedOn[st.StepId] || !Stackable(payload.Item.TemplateId) {
+			steps = append(steps, st)
+			continue
+		}
+
+		key := coalesceKey{action: st.Action, characterId: payload.CharacterId, templateId: payload.Item.TemplateId}
+		if i, ok := firsts[key]; ok {
+			first := steps[i].Payload.(AwardItemActionPayload)
+			if uint64(first.Item.Quantity)+uint64(payload.Item.Quantity) <= math.MaxUint32 {
+				first.Item.Quantity += payload.Item.Quantity
+				steps[i].Payload = first
+				continue
+			}
+		}
+		firsts[key] = len(steps)
+		steps = append(steps, st)
+	}
+
+	removed := len(s.Steps) - len(steps)
+	s.Steps = steps
+	return removed
+}
@@ -0,0 +1,72 @@
+package saga
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// TestCoalesceAwardSteps tests coalescing identical award steps of stackable items
+func TestCoalesceAwardSteps(t *testing.T) {
+	award := func(characterId uint32, templateId uint32, quantity uint32) AwardItemActionPayload {
+		return AwardItemActionPayload{CharacterId: characterId, Item: ItemPayload{TemplateId: templateId, Quantity: quantity}}
+	}
+	build := func(coalesce bool) Saga {
+		b := NewBuilder().
+			SetSagaType(QuestReward).
+			SetInitiatedBy("npc-9010000").
+			AddStep("potions-1", Pending, AwardAsset, award(12345, 2000000, 10)).
+			AddStep("sword", Pending, AwardAsset, award(12345, 1302000, 1)).
+			AddStep("mesos", Pending, AwardMesos, AwardMesosPayload{CharacterId: 12345, ActorType: "NPC", Amount: 100}).
+			AddStep("potions-2", Pending, AwardAsset, award(12345, 2000000, 5)).
+			AddStep("sword-2", Pending, AwardAsset, award(12345, 1302000, 1)).
+			AddStep("other-character", Pending, AwardAsset, award(54321, 2000000, 5)).
+			AddStep("potions-3", Pending, AwardAsset, award(12345, 2000000, 1)).
+			AddStep("captured", Pending, AwardAsset, award(12345, 2000000, 1)).
+			AddCapture("assetId", "$.assetId")
+		if coalesce {
+			b.SetCoalesceAwards()
+		}
+		return b.Build()
+	}
+	stepIds := func(s Saga) []string {
+		r := make([]string, 0, len(s.Steps))
+		for _, st := range s.Steps {
+			r = append(r, st.StepId)
+		}
+		return r
+	}
+
+	// Sagas which do not opt in are unchanged
+	s := build(false)
+	assert.Equal(t, 0, s.CoalesceAwardSteps())
+	assert.Len(t, s.Steps, 8)
+
+	s = build(true)
+	assert.Equal(t, 2, s.CoalesceAwardSteps())
+	assert.Equal(t, []string{"potions-1", "sword", "mesos", "sword-2", "other-character", "captured"}, stepIds(s))
+	assert.Equal(t, uint32(16), s.Steps[0].Payload.(AwardItemActionPayload).Item.Quantity)
+	assert.Equal(t, uint32(5), s.Steps[4].Payload.(AwardItemActionPayload).Item.Quantity)
+	assert.NoError(t, s.ValidateStateConsistency())
+
+	// Quantities which would overflow are not coalesced
+	s = NewBuilder().
+		SetSagaType(QuestReward).
+		SetCoalesceAwards().
+		AddStep("a", Pending, AwardAsset, award(12345, 4000000, 4000000000)).
+		AddStep("b", Pending, AwardAsset, award(12345, 4000000, 4000000000)).
+		Build()
+	assert.Equal(t, 0, s.CoalesceAwardSteps())
+
+	// Steps whose order is constrained by dependencies are not coalesced
+	s = NewBuilder().
+		SetSagaType(QuestReward).
+		SetCoalesceAwards().
+		AddStep("a", Pending, AwardAsset, award(12345, 4000000, 1)).
+		AddStep("b", Pending, AwardAsset, award(12345, 4000000, 1)).
+		AddStep("c", Pending, AwardAsset, award(12345, 4000000, 1)).
+		AddDependency("mesos").
+		AddStep("mesos", Pending, AwardMesos, AwardMesosPayload{CharacterId: 12345, ActorType: "NPC", Amount: 100}).
+		AddDependency("b").
+		Build()
+	assert.Equal(t, 0, s.CoalesceAwardSteps())
+}
@@ -0,0 +1,261 @@
+package saga
+
+import (
+	"atlas-saga-orchestrator/metrics"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Chronicle20/atlas-rest/server"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+// Reasons finished sagas are evicted from the cache, by which evictions are counted
+const (
+	EvictionRetention = "retention"  // The saga finished longer ago than the retention
+	EvictionHighWater = "high_water" // The cache exceeded a high-water mark, so the saga was evicted before its retention
+)
+
+// CompactionConfig configures the eviction of finished sagas from the cache. Sagas with work remaining are never
+// evicted, as their progress is held in the cache.
+type CompactionConfig struct {
+	Interval       time.Duration // Interval at which the cache is compacted
+	Retention      time.Duration // Duration finished sagas are retained in the cache, so they may still be read
+	HighWaterSagas int           // Number of sagas cached, across tenants, above which finished sagas are evicted regardless of retention. When 0, there is no mark.
+	HighWaterBytes int64         // Serialized size of the sagas cached, across tenants, above which finished sagas are evicted regardless of retention. When 0, there is no mark.
+}
+
+// DefaultCompactionConfig is the compaction configuration used when none is configured
+var DefaultCompactionConfig = CompactionConfig{Interval: time.Minute, Retention: time.Hour}
+
+// CompactionConfigFromEnv loads the compaction configuration from the environment
+func CompactionConfigFromEnv() (CompactionConfig, error) {
+	c := DefaultCompactionConfig
+	if v, ok := os.LookupEnv("SAGA_COMPACTION_INTERVAL"); ok && v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return CompactionConfig{}, fmt.Errorf("invalid SAGA_COMPACTION_INTERVAL '%s'", v)
+		}
+		c.Interval = d
+	}
+	if v, ok := os.LookupEnv("SAGA_COMPACTION_RETENTION"); ok && v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return CompactionConfig{}, fmt.Errorf("invalid SAGA_COMPACTION_RETENTION '%s'", v)
+		}
+		c.Retention = d
+	}
+	if v, ok := os.LookupEnv("SAGA_CACHE_HIGH_WATER_SAGAS"); ok && v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return CompactionConfig{}, fmt.Errorf("invalid SAGA_CACHE_HIGH_WATER_SAGAS '%s'", v)
+		}
+		c.HighWaterSagas = n
+	}
+	if v, ok := os.LookupEnv("SAGA_CACHE_HIGH_WATER_BYTES"); ok && v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 0 {
+			return CompactionConfig{}, fmt.Errorf("invalid SAGA_CACHE_HIGH_WATER_BYTES '%s'", v)
+		}
+		c.HighWaterBytes = n
+	}
+	return c, nil
+}
+
+// CacheUsage is the size of the sagas of a single tenant held in the cache
+type CacheUsage struct {
+	TenantId uuid.UUID `json:"tenantId"` // Tenant of the sagas
+	Sagas    int       `json:"sagas"`    // Number of sagas cached
+	Active   int       `json:"active"`   // Number of cached sagas with work remaining, which are never evicted
+	Steps    int       `json:"steps"`    // Number of steps of the sagas cached
+	Bytes    int64     `json:"bytes"`    // Serialized size of the sagas cached, approximating the memory they hold
+}
+
+// sagaBytes returns the serialized size of the saga
+func sagaBytes(s Saga) int64 {
+	bs, err := json.Marshal(s)
+	if err != nil {
+		return 0
+	}
+	return int64(len(bs))
+}
+
+// finishedAt returns when the saga finished, being its last progress, or the issue of its compensation receipt
+func (s Saga) finishedAt() time.Time {
+	r := s.LastProgressed()
+	if s.Receipt != nil && s.Receipt.IssuedAt.After(r) {
+		r = s.Receipt.IssuedAt
+	}
+	return r
+}
+
+// MeasureCache returns the size of the sagas held in the cache, by tenant, ordered by tenant. When a tenant is given, only
+// its sagas are measured.
+func MeasureCache(tenantId *uuid.UUID) []CacheUsage {
+	tenants := GetCache().Tenants()
+	if tenantId != nil {
+		tenants = []uuid.UUID{*tenantId}
+	}
+	r := make([]CacheUsage, 0, len(tenants))
+	for _, t := range tenants {
+		u := CacheUsage{TenantId: t}
+		for _, s := range GetCache().GetAll(t) {
+			u.Sagas++
+			u.Steps += len(s.Steps)
+			u.Bytes += sagaBytes(s)
+			if s.active() {
+				u.Active++
+			}
+		}
+		r = append(r, u)
+	}
+	sort.Slice(r, func(i, j int) bool {
+		return r[i].TenantId.String() < r[j].TenantId.String()
+	})
+	return r
+}
+
+var evictions *metrics.Counter
+var evictionsOnce sync.Once
+
+// getEvictions returns the counter of finished sagas evicted from the cache, by tenant and reason
+func getEvictions() *metrics.Counter {
+	evictionsOnce.Do(func() {
+		evictions = metrics.GetRegistry().RegisterCounter("saga_cache_evictions_total", "Number of finished sagas evicted from the cache, by reason.")
+	})
+	return evictions
+}
+
+// cachedSaga is a saga of a tenant held in the cache, and its serialized size
+type cachedSaga struct {
+	tenantId uuid.UUID
+	saga     Saga
+	bytes    int64
+}
+
+// Compactor evicts finished sagas from the cache once their retention has passed, or earlier should the cache exceed a
+// high-water mark, so the cache does not grow without bound under sustained load. With an archive, evicted sagas remain
+// readable from it.
+type Compactor struct {
+	l      logrus.FieldLogger
+	config CompactionConfig
+	mutex  sync.Mutex
+	usage  []CacheUsage
+}
+
+// NewCompactor creates a task compacting the cache at the configured interval, and reporting its size, as of the last
+// compaction, as gauges
+func NewCompactor(l logrus.FieldLogger, c CompactionConfig) *Compactor {
+	r := &Compactor{l: l, config: c, usage: make([]CacheUsage, 0)}
+	gauge := func(name string, help string, value func(u CacheUsage) float64) {
+		metrics.GetRegistry().RegisterGauge(name, help, func() []metrics.Sample {
+			r.mutex.Lock()
+			defer r.mutex.Unlock()
+			samples := make([]metrics.Sample, 0, len(r.usage))
+			for _, u := range r.usage {
+				samples = append(samples, metrics.Sample{Labels: map[string]string{"tenant_id": u.TenantId.String()}, Value: value(u)})
+			}
+			return samples
+		})
+	}
+	gauge("saga_cache_sagas", "Number of sagas cached, as of the last compaction.", func(u CacheUsage) float64 { return float64(u.Sagas) })
+	gauge("saga_cache_steps", "Number of steps of the sagas cached, as of the last compaction.", func(u CacheUsage) float64 { return float64(u.Steps) })
+	gauge("saga_cache_bytes", "Serialized size of the sagas cached, as of the last compaction.", func(u CacheUsage) float64 { return float64(u.Bytes) })
+	getEvictions()
+	return r
+}
+
+func (r *Compactor) Run() {
+	r.compact(time.Now())
+	usage := MeasureCache(nil)
+	r.mutex.Lock()
+	r.usage = usage
+	r.mutex.Unlock()
+}
+
+// compact evicts the finished sagas whose retention has passed, then, while the cache exceeds a high-water mark, the
+// remaining finished sagas, those which finished earliest first. Returns the number of sagas evicted.
+func (r *Compactor) compact(now time.Time) int {
+	sagas := 0
+	bytes := int64(0)
+	finished := make([]cachedSaga, 0)
+	for _, t := range GetCache().Tenants() {
+		for _, s := range GetCache().GetAll(t) {
+			cs := cachedSaga{tenantId: t, saga: s, bytes: sagaBytes(s)}
+			sagas++
+			bytes += cs.bytes
+			if !s.active() {
+				finished = append(finished, cs)
+			}
+		}
+	}
+	sort.Slice(finished, func(i, j int) bool {
+		return finished[i].saga.finishedAt().Before(finished[j].saga.finishedAt())
+	})
+
+	exceeded := func() bool {
+		return (r.config.HighWaterSagas > 0 && sagas > r.config.HighWaterSagas) || (r.config.HighWaterBytes > 0 && bytes > r.config.HighWaterBytes)
+	}
+	evicted := 0
+	for _, cs := range finished {
+		reason := EvictionRetention
+		if now.Sub(cs.saga.finishedAt()) < r.config.Retention {
+			if !exceeded() {
+				break
+			}
+			reason = EvictionHighWater
+		}
+		if !GetCache().Remove(cs.tenantId, cs.saga.TransactionId) {
+			continue
+		}
+		sagas--
+		bytes -= cs.bytes
+		evicted++
+		getEvictions().Inc(map[string]string{"tenant_id": cs.tenantId.String(), "reason": reason})
+		r.l.WithFields(logrus.Fields{
+			"transaction_id": cs.saga.TransactionId.String(),
+			"saga_type":      cs.saga.SagaType,
+			"tenant_id":      cs.tenantId.String(),
+			"reason":         reason,
+		}).Debug("Evicted finished saga from cache.")
+	}
+
+	if exceeded() {
+		r.l.Warnf("Saga cache holds [%d] sagas of [%d] bytes, exceeding its high-water mark, with no finished sagas left to evict.", sagas, bytes)
+	}
+	return evicted
+}
+
+func (r *Compactor) SleepTime() time.Duration {
+	return r.config.Interval
+}
+
+// InitCacheResource registers the cache size report route with the router. The cache spans tenants, so no tenant is
+// required, though the report may be filtered to one with the tenantId query parameter.
+func InitCacheResource() server.RouteInitializer {
+	return func(r *mux.Router, l logrus.FieldLogger) {
+		r.HandleFunc("/cache", func(w http.ResponseWriter, r *http.Request) {
+			var tenantId *uuid.UUID
+			if v := r.URL.Query().Get("tenantId"); v != "" {
+				id, err := uuid.Parse(v)
+				if err != nil {
+					l.WithError(err).Errorf("Unable to properly parse tenantId from query.")
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				tenantId = &id
+			}
+			w.Header().Set("Content-Type", "application/json")
+			if err := json.NewEncoder(w).Encode(MeasureCache(tenantId)); err != nil {
+				l.WithError(err).Error("Unable to write cache usage.")
+			}
+		}).Methods(http.MethodGet)
+	}
+}
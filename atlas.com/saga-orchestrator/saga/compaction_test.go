@@ -0,0 +1,101 @@
+package saga
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCompactionConfigFromEnv tests loading the compaction configuration from the environment
+func TestCompactionConfigFromEnv(t *testing.T) {
+	c, err := CompactionConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, DefaultCompactionConfig, c)
+
+	t.Setenv("SAGA_COMPACTION_INTERVAL", "30s")
+	t.Setenv("SAGA_COMPACTION_RETENTION", "10m")
+	t.Setenv("SAGA_CACHE_HIGH_WATER_SAGAS", "10000")
+	t.Setenv("SAGA_CACHE_HIGH_WATER_BYTES", "1048576")
+	c, err = CompactionConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, CompactionConfig{Interval: 30 * time.Second, Retention: 10 * time.Minute, HighWaterSagas: 10000, HighWaterBytes: 1048576}, c)
+
+	t.Setenv("SAGA_CACHE_HIGH_WATER_SAGAS", "-1")
+	_, err = CompactionConfigFromEnv()
+	assert.Error(t, err)
+}
+
+// TestCompactor tests that finished sagas are evicted from the cache once their retention has passed, or earlier while
+// the cache exceeds a high-water mark, and that sagas with work remaining are never evicted
+func TestCompactor(t *testing.T) {
+	ResetCache()
+	defer ResetCache()
+	te, _ := setupContext()
+	now := time.Now()
+
+	build := func(updatedAt time.Time, statuses ...Status) Saga {
+		b := NewBuilder().SetTransactionId(uuid.New()).SetSagaType(QuestReward)
+		for i, status := range statuses {
+			b.AddStep("step-"+string(rune('a'+i)), status, AwardMesos, AwardMesosPayload{CharacterId: 12345, ActorType: "NPC", Amount: 1000})
+		}
+		s := b.Build()
+		for i := range s.Steps {
+			s.Steps[i].UpdatedAt = updatedAt
+		}
+		return s
+	}
+	expired := build(now.Add(-2*time.Hour), Completed, Completed)
+	older := build(now.Add(-20*time.Minute), Completed, Completed)
+	recent := build(now.Add(-time.Minute), Completed, Completed)
+	active := build(now.Add(-2*time.Hour), Completed, Pending)
+
+	cached := func() []uuid.UUID {
+		r := make([]uuid.UUID, 0)
+		for _, s := range []Saga{expired, older, recent, active} {
+			if _, ok := GetCache().GetById(te.Id(), s.TransactionId); ok {
+				r = append(r, s.TransactionId)
+			}
+		}
+		return r
+	}
+	put := func() {
+		for _, s := range []Saga{expired, older, recent, active} {
+			GetCache().Put(te.Id(), s)
+		}
+	}
+
+	t.Run("finished sagas are evicted once their retention has passed", func(t *testing.T) {
+		put()
+		l, _ := test.NewNullLogger()
+		r := NewCompactor(l, CompactionConfig{Interval: time.Minute, Retention: time.Hour})
+		assert.Equal(t, 1, r.compact(now))
+		assert.Equal(t, []uuid.UUID{older.TransactionId, recent.TransactionId, active.TransactionId}, cached())
+	})
+
+	t.Run("finished sagas are evicted early while a high-water mark is exceeded", func(t *testing.T) {
+		put()
+		l, hook := test.NewNullLogger()
+		r := NewCompactor(l, CompactionConfig{Interval: time.Minute, Retention: time.Hour, HighWaterSagas: 2})
+		assert.Equal(t, 2, r.compact(now))
+		assert.Equal(t, []uuid.UUID{recent.TransactionId, active.TransactionId}, cached())
+		assert.Empty(t, hook.AllEntries())
+	})
+
+	t.Run("sagas with work remaining are never evicted", func(t *testing.T) {
+		put()
+		l, hook := test.NewNullLogger()
+		r := NewCompactor(l, CompactionConfig{Interval: time.Minute, Retention: time.Hour, HighWaterBytes: 1})
+		assert.Equal(t, 3, r.compact(now))
+		assert.Equal(t, []uuid.UUID{active.TransactionId}, cached())
+		require.NotNil(t, hook.LastEntry())
+		assert.Contains(t, hook.LastEntry().Message, "exceeding its high-water mark")
+
+		r.Run()
+		require.Len(t, r.usage, 1)
+		assert.Equal(t, CacheUsage{TenantId: te.Id(), Sagas: 1, Active: 1, Steps: 2, Bytes: sagaBytes(active)}, r.usage[0])
+	})
+}
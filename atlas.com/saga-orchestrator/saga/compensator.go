@@ -1,17 +1,34 @@
 package saga
 
 import (
+	"atlas-saga-orchestrator/account"
+	"atlas-saga-orchestrator/buff"
 	"atlas-saga-orchestrator/character"
 	"atlas-saga-orchestrator/compartment"
+	"atlas-saga-orchestrator/coupon"
+	"atlas-saga-orchestrator/faction"
 	"atlas-saga-orchestrator/guild"
+	instance2 "atlas-saga-orchestrator/instance"
 	"atlas-saga-orchestrator/invite"
+	buff2 "atlas-saga-orchestrator/kafka/message/buff"
+	character2 "atlas-saga-orchestrator/kafka/message/character"
+	"atlas-saga-orchestrator/marriage"
+	"atlas-saga-orchestrator/npc"
+	"atlas-saga-orchestrator/reactor"
+	"atlas-saga-orchestrator/session"
 	"atlas-saga-orchestrator/skill"
+	"atlas-saga-orchestrator/storage"
 	"atlas-saga-orchestrator/validation"
+	"atlas-saga-orchestrator/worldstate"
 	"context"
 	"fmt"
+	"github.com/Chronicle20/atlas-constants/field"
+	"github.com/Chronicle20/atlas-constants/inventory"
+	"github.com/Chronicle20/atlas-constants/world"
 	tenant "github.com/Chronicle20/atlas-tenant"
 	"github.com/sirupsen/logrus"
 	"strings"
+	"time"
 )
 
 type Compensator interface {
@@ -21,12 +38,59 @@ type Compensator interface {
 	WithValidationProcessor(validation.Processor) Compensator
 	WithGuildProcessor(guild.Processor) Compensator
 	WithInviteProcessor(invite.Processor) Compensator
+	WithBuffProcessor(buff.Processor) Compensator
+	WithWorldStateProcessor(worldstate.Processor) Compensator
+	WithCouponProcessor(coupon.Processor) Compensator
+	WithReactorProcessor(reactor.Processor) Compensator
+	WithAccountProcessor(account.Processor) Compensator
+	WithMarriageProcessor(marriage.Processor) Compensator
+	WithFactionProcessor(faction.Processor) Compensator
+	WithInstanceProcessor(instance2.Processor) Compensator
+	WithSessionProcessor(session.Processor) Compensator
+	WithNpcProcessor(npc.Processor) Compensator
+	WithStorageProcessor(storage.Processor) Compensator
 
 	CompensateFailedStep(s Saga) error
 	compensateEquipAsset(s Saga, failedStep Step[any]) error
 	compensateUnequipAsset(s Saga, failedStep Step[any]) error
+	compensateModifyInventoryItemPosition(s Saga, failedStep Step[any]) error
+	compensateEquipmentPreset(s Saga, preset Step[any], failedStep Step[any]) error
+	compensateTransferCharacter(s Saga, failedStep Step[any]) error
 	compensateCreateCharacter(s Saga, failedStep Step[any]) error
 	compensateCreateAndEquipAsset(s Saga, failedStep Step[any]) error
+	compensateCharacterBuffCleanse(s Saga, failedStep Step[any]) error
+	compensateAdjustNpcShopStock(s Saga, failedStep Step[any]) error
+	compensateSetWorldEventFlag(s Saga, failedStep Step[any]) error
+	compensateConsumeCoupon(s Saga, failedStep Step[any]) error
+	compensateApplyCharacterExpPenalty(s Saga, failedStep Step[any]) error
+	compensateApplyDurabilityPenalty(s Saga, failedStep Step[any]) error
+	compensateCreateAccountCharacterSlot(s Saga, failedStep Step[any]) error
+	compensateModifyAssetExpiration(s Saga, failedStep Step[any]) error
+	compensateApplyHammer(s Saga, failedStep Step[any]) error
+	compensateDeductMesos(s Saga, failedStep Step[any]) error
+	compensateVerifyAndConsumeTicket(s Saga, failedStep Step[any]) error
+	compensateAdjustReactorState(s Saga, failedStep Step[any]) error
+	compensateUpdateCharacterResource(s Saga, failedStep Step[any]) error
+	compensateGrantPremiumTime(s Saga, failedStep Step[any]) error
+	compensateDissolveMarriage(s Saga, failedStep Step[any]) error
+	compensateSealAsset(s Saga, failedStep Step[any]) error
+	compensateUnsealAsset(s Saga, failedStep Step[any]) error
+	compensateIssueTransportTicket(s Saga, failedStep Step[any]) error
+	compensateScheduleWarp(s Saga, failedStep Step[any]) error
+	compensateSpawnEscort(s Saga, failedStep Step[any]) error
+	compensateAwaitEscort(s Saga, failedStep Step[any]) error
+	compensateUpdateCharacterAlignment(s Saga, failedStep Step[any]) error
+	compensateResetInstanceCooldown(s Saga, failedStep Step[any]) error
+	compensateApplyTitleBuffOnLogin(s Saga, failedStep Step[any]) error
+	compensateApplyWorldEventBuff(s Saga, failedStep Step[any]) error
+	compensateCharacterExperienceLock(s Saga, failedStep Step[any]) error
+	compensateCharacterExperienceUnlock(s Saga, failedStep Step[any]) error
+	compensateEquipAssetByTemplate(s Saga, failedStep Step[any]) error
+	compensateGrantStorageCapacity(s Saga, failedStep Step[any]) error
+	compensateChangeJob(s Saga, failedStep Step[any]) error
+	compensatePetEquip(s Saga, failedStep Step[any]) error
+	compensatePetUnequip(s Saga, failedStep Step[any]) error
+	compensateSetQuestTimer(s Saga, failedStep Step[any]) error
 }
 
 type CompensatorImpl struct {
@@ -39,6 +103,17 @@ type CompensatorImpl struct {
 	validP  validation.Processor
 	guildP  guild.Processor
 	inviteP invite.Processor
+	buffP   buff.Processor
+	worldP  worldstate.Processor
+	couponP coupon.Processor
+	reactP  reactor.Processor
+	acctP   account.Processor
+	marriP  marriage.Processor
+	factP   faction.Processor
+	instP   instance2.Processor
+	sessP   session.Processor
+	npcP    npc.Processor
+	storP   storage.Processor
 }
 
 func NewCompensator(l logrus.FieldLogger, ctx context.Context) Compensator {
@@ -52,6 +127,17 @@ func NewCompensator(l logrus.FieldLogger, ctx context.Context) Compensator {
 		validP:  validation.NewProcessor(l, ctx),
 		guildP:  guild.NewProcessor(l, ctx),
 		inviteP: invite.NewProcessor(l, ctx),
+		buffP:   buff.NewProcessor(l, ctx),
+		worldP:  worldstate.NewProcessor(l, ctx),
+		couponP: coupon.NewProcessor(l, ctx),
+		reactP:  reactor.NewProcessor(l, ctx),
+		acctP:   account.NewProcessor(l, ctx),
+		marriP:  marriage.NewProcessor(l, ctx),
+		factP:   faction.NewProcessor(l, ctx),
+		instP:   instance2.NewProcessor(l, ctx),
+		sessP:   session.NewProcessor(l, ctx),
+		npcP:    npc.NewProcessor(l, ctx),
+		storP:   storage.NewProcessor(l, ctx),
 	}
 }
 
@@ -66,6 +152,17 @@ func (c *CompensatorImpl) WithCharacterProcessor(charP character.Processor) Comp
 		validP:  c.validP,
 		guildP:  c.guildP,
 		inviteP: c.inviteP,
+		buffP:   c.buffP,
+		worldP:  c.worldP,
+		couponP: c.couponP,
+		reactP:  c.reactP,
+		acctP:   c.acctP,
+		marriP:  c.marriP,
+		factP:   c.factP,
+		instP:   c.instP,
+		sessP:   c.sessP,
+		npcP:    c.npcP,
+		storP:   c.storP,
 	}
 }
 
@@ -80,6 +177,17 @@ func (c *CompensatorImpl) WithCompartmentProcessor(compP compartment.Processor)
 		validP:  c.validP,
 		guildP:  c.guildP,
 		inviteP: c.inviteP,
+		buffP:   c.buffP,
+		worldP:  c.worldP,
+		couponP: c.couponP,
+		reactP:  c.reactP,
+		acctP:   c.acctP,
+		marriP:  c.marriP,
+		factP:   c.factP,
+		instP:   c.instP,
+		sessP:   c.sessP,
+		npcP:    c.npcP,
+		storP:   c.storP,
 	}
 }
 
@@ -94,6 +202,17 @@ func (c *CompensatorImpl) WithSkillProcessor(skillP skill.Processor) Compensator
 		validP:  c.validP,
 		guildP:  c.guildP,
 		inviteP: c.inviteP,
+		buffP:   c.buffP,
+		worldP:  c.worldP,
+		couponP: c.couponP,
+		reactP:  c.reactP,
+		acctP:   c.acctP,
+		marriP:  c.marriP,
+		factP:   c.factP,
+		instP:   c.instP,
+		sessP:   c.sessP,
+		npcP:    c.npcP,
+		storP:   c.storP,
 	}
 }
 
@@ -108,6 +227,17 @@ func (c *CompensatorImpl) WithValidationProcessor(validP validation.Processor) C
 		validP:  validP,
 		guildP:  c.guildP,
 		inviteP: c.inviteP,
+		buffP:   c.buffP,
+		worldP:  c.worldP,
+		couponP: c.couponP,
+		reactP:  c.reactP,
+		acctP:   c.acctP,
+		marriP:  c.marriP,
+		factP:   c.factP,
+		instP:   c.instP,
+		sessP:   c.sessP,
+		npcP:    c.npcP,
+		storP:   c.storP,
 	}
 }
 
@@ -122,6 +252,17 @@ func (c *CompensatorImpl) WithGuildProcessor(guildP guild.Processor) Compensator
 		validP:  c.validP,
 		guildP:  guildP,
 		inviteP: c.inviteP,
+		buffP:   c.buffP,
+		worldP:  c.worldP,
+		couponP: c.couponP,
+		reactP:  c.reactP,
+		acctP:   c.acctP,
+		marriP:  c.marriP,
+		factP:   c.factP,
+		instP:   c.instP,
+		sessP:   c.sessP,
+		npcP:    c.npcP,
+		storP:   c.storP,
 	}
 }
 
@@ -136,6 +277,292 @@ func (c *CompensatorImpl) WithInviteProcessor(inviteP invite.Processor) Compensa
 		validP:  c.validP,
 		guildP:  c.guildP,
 		inviteP: inviteP,
+		buffP:   c.buffP,
+		worldP:  c.worldP,
+		couponP: c.couponP,
+		reactP:  c.reactP,
+		acctP:   c.acctP,
+		marriP:  c.marriP,
+		factP:   c.factP,
+		instP:   c.instP,
+		sessP:   c.sessP,
+		npcP:    c.npcP,
+		storP:   c.storP,
+	}
+}
+
+func (c *CompensatorImpl) WithBuffProcessor(buffP buff.Processor) Compensator {
+	return &CompensatorImpl{
+		l:       c.l,
+		ctx:     c.ctx,
+		t:       c.t,
+		charP:   c.charP,
+		compP:   c.compP,
+		skillP:  c.skillP,
+		validP:  c.validP,
+		guildP:  c.guildP,
+		inviteP: c.inviteP,
+		buffP:   buffP,
+		worldP:  c.worldP,
+		couponP: c.couponP,
+		reactP:  c.reactP,
+		acctP:   c.acctP,
+		marriP:  c.marriP,
+		factP:   c.factP,
+		instP:   c.instP,
+		sessP:   c.sessP,
+		npcP:    c.npcP,
+		storP:   c.storP,
+	}
+}
+
+func (c *CompensatorImpl) WithWorldStateProcessor(worldP worldstate.Processor) Compensator {
+	return &CompensatorImpl{
+		l:       c.l,
+		ctx:     c.ctx,
+		t:       c.t,
+		charP:   c.charP,
+		compP:   c.compP,
+		skillP:  c.skillP,
+		validP:  c.validP,
+		guildP:  c.guildP,
+		inviteP: c.inviteP,
+		buffP:   c.buffP,
+		worldP:  worldP,
+		couponP: c.couponP,
+		reactP:  c.reactP,
+		acctP:   c.acctP,
+		marriP:  c.marriP,
+		factP:   c.factP,
+		instP:   c.instP,
+		sessP:   c.sessP,
+		npcP:    c.npcP,
+		storP:   c.storP,
+	}
+}
+
+func (c *CompensatorImpl) WithCouponProcessor(couponP coupon.Processor) Compensator {
+	return &CompensatorImpl{
+		l:       c.l,
+		ctx:     c.ctx,
+		t:       c.t,
+		charP:   c.charP,
+		compP:   c.compP,
+		skillP:  c.skillP,
+		validP:  c.validP,
+		guildP:  c.guildP,
+		inviteP: c.inviteP,
+		buffP:   c.buffP,
+		worldP:  c.worldP,
+		couponP: couponP,
+		reactP:  c.reactP,
+		acctP:   c.acctP,
+		marriP:  c.marriP,
+		factP:   c.factP,
+		instP:   c.instP,
+		sessP:   c.sessP,
+		npcP:    c.npcP,
+		storP:   c.storP,
+	}
+}
+
+func (c *CompensatorImpl) WithReactorProcessor(reactP reactor.Processor) Compensator {
+	return &CompensatorImpl{
+		l:       c.l,
+		ctx:     c.ctx,
+		t:       c.t,
+		charP:   c.charP,
+		compP:   c.compP,
+		skillP:  c.skillP,
+		validP:  c.validP,
+		guildP:  c.guildP,
+		inviteP: c.inviteP,
+		buffP:   c.buffP,
+		worldP:  c.worldP,
+		couponP: c.couponP,
+		reactP:  reactP,
+		acctP:   c.acctP,
+		marriP:  c.marriP,
+		factP:   c.factP,
+		instP:   c.instP,
+		sessP:   c.sessP,
+		npcP:    c.npcP,
+		storP:   c.storP,
+	}
+}
+
+func (c *CompensatorImpl) WithAccountProcessor(acctP account.Processor) Compensator {
+	return &CompensatorImpl{
+		l:       c.l,
+		ctx:     c.ctx,
+		t:       c.t,
+		charP:   c.charP,
+		compP:   c.compP,
+		skillP:  c.skillP,
+		validP:  c.validP,
+		guildP:  c.guildP,
+		inviteP: c.inviteP,
+		buffP:   c.buffP,
+		worldP:  c.worldP,
+		couponP: c.couponP,
+		reactP:  c.reactP,
+		acctP:   acctP,
+		marriP:  c.marriP,
+		factP:   c.factP,
+		instP:   c.instP,
+		sessP:   c.sessP,
+		npcP:    c.npcP,
+		storP:   c.storP,
+	}
+}
+
+func (c *CompensatorImpl) WithMarriageProcessor(marriP marriage.Processor) Compensator {
+	return &CompensatorImpl{
+		l:       c.l,
+		ctx:     c.ctx,
+		t:       c.t,
+		charP:   c.charP,
+		compP:   c.compP,
+		skillP:  c.skillP,
+		validP:  c.validP,
+		guildP:  c.guildP,
+		inviteP: c.inviteP,
+		buffP:   c.buffP,
+		worldP:  c.worldP,
+		couponP: c.couponP,
+		reactP:  c.reactP,
+		acctP:   c.acctP,
+		marriP:  marriP,
+		factP:   c.factP,
+		instP:   c.instP,
+		sessP:   c.sessP,
+		npcP:    c.npcP,
+		storP:   c.storP,
+	}
+}
+
+func (c *CompensatorImpl) WithFactionProcessor(factP faction.Processor) Compensator {
+	return &CompensatorImpl{
+		l:       c.l,
+		ctx:     c.ctx,
+		t:       c.t,
+		charP:   c.charP,
+		compP:   c.compP,
+		skillP:  c.skillP,
+		validP:  c.validP,
+		guildP:  c.guildP,
+		inviteP: c.inviteP,
+		buffP:   c.buffP,
+		worldP:  c.worldP,
+		couponP: c.couponP,
+		reactP:  c.reactP,
+		acctP:   c.acctP,
+		marriP:  c.marriP,
+		factP:   factP,
+		instP:   c.instP,
+		sessP:   c.sessP,
+		npcP:    c.npcP,
+		storP:   c.storP,
+	}
+}
+
+func (c *CompensatorImpl) WithInstanceProcessor(instP instance2.Processor) Compensator {
+	return &CompensatorImpl{
+		l:       c.l,
+		ctx:     c.ctx,
+		t:       c.t,
+		charP:   c.charP,
+		compP:   c.compP,
+		skillP:  c.skillP,
+		validP:  c.validP,
+		guildP:  c.guildP,
+		inviteP: c.inviteP,
+		buffP:   c.buffP,
+		worldP:  c.worldP,
+		couponP: c.couponP,
+		reactP:  c.reactP,
+		acctP:   c.acctP,
+		marriP:  c.marriP,
+		factP:   c.factP,
+		instP:   instP,
+		sessP:   c.sessP,
+		npcP:    c.npcP,
+		storP:   c.storP,
+	}
+}
+
+func (c *CompensatorImpl) WithSessionProcessor(sessP session.Processor) Compensator {
+	return &CompensatorImpl{
+		l:       c.l,
+		ctx:     c.ctx,
+		t:       c.t,
+		charP:   c.charP,
+		compP:   c.compP,
+		skillP:  c.skillP,
+		validP:  c.validP,
+		guildP:  c.guildP,
+		inviteP: c.inviteP,
+		buffP:   c.buffP,
+		worldP:  c.worldP,
+		couponP: c.couponP,
+		reactP:  c.reactP,
+		acctP:   c.acctP,
+		marriP:  c.marriP,
+		factP:   c.factP,
+		instP:   c.instP,
+		sessP:   sessP,
+		npcP:    c.npcP,
+		storP:   c.storP,
+	}
+}
+
+func (c *CompensatorImpl) WithNpcProcessor(npcP npc.Processor) Compensator {
+	return &CompensatorImpl{
+		l:       c.l,
+		ctx:     c.ctx,
+		t:       c.t,
+		charP:   c.charP,
+		compP:   c.compP,
+		skillP:  c.skillP,
+		validP:  c.validP,
+		guildP:  c.guildP,
+		inviteP: c.inviteP,
+		buffP:   c.buffP,
+		worldP:  c.worldP,
+		couponP: c.couponP,
+		reactP:  c.reactP,
+		acctP:   c.acctP,
+		marriP:  c.marriP,
+		factP:   c.factP,
+		instP:   c.instP,
+		sessP:   c.sessP,
+		npcP:    npcP,
+		storP:   c.storP,
+	}
+}
+
+func (c *CompensatorImpl) WithStorageProcessor(storP storage.Processor) Compensator {
+	return &CompensatorImpl{
+		l:       c.l,
+		ctx:     c.ctx,
+		t:       c.t,
+		charP:   c.charP,
+		compP:   c.compP,
+		skillP:  c.skillP,
+		validP:  c.validP,
+		guildP:  c.guildP,
+		inviteP: c.inviteP,
+		buffP:   c.buffP,
+		worldP:  c.worldP,
+		couponP: c.couponP,
+		reactP:  c.reactP,
+		acctP:   c.acctP,
+		marriP:  c.marriP,
+		factP:   c.factP,
+		instP:   c.instP,
+		sessP:   c.sessP,
+		npcP:    c.npcP,
+		storP:   storP,
 	}
 }
 
@@ -162,16 +589,120 @@ func (c *CompensatorImpl) CompensateFailedStep(s Saga) error {
 		"tenant_id":      c.t.Id().String(),
 	}).Debug("Compensating failed step.")
 
+	// Countdowns started by the saga's quest timers are stopped, so they do not expire against the compensated saga
+	c.stopQuestTimers(s)
+
+	// Steps of a saga which created a character are rolled back by deleting the character, along with their effects on it
+	if created, ok := findCreatedCharacterStep(s, failedStep.StepId); ok {
+		return c.compensateCreatedCharacter(s, created, failedStep)
+	}
+
+	// Characters the saga took a snapshot of are restored to it, reversing the progression and stats gained or lost since,
+	// before the failed step itself is compensated
+	if err := c.restoreCharacterSnapshots(s, failedStep); err != nil {
+		return err
+	}
+
+	// Steps added by an equipment preset or strip are rolled back together, restoring the prior loadout
+	if preset, ok := findEquipmentPresetStep(s, failedStep.StepId); ok {
+		return c.compensateEquipmentPreset(s, preset, failedStep)
+	}
+
+	// Steps added by a mount grant are rolled back together, removing both the skill and the item
+	if mount, ok := findMountStep(s, failedStep.StepId); ok {
+		return c.compensateGrantMount(s, mount, failedStep)
+	}
+
+	// Steps added by a party experience split are rolled back together, so either every member is awarded their share or none
+	if party, ok := findPartyExperienceStep(s, failedStep.StepId); ok {
+		return c.compensateAwardPartyExperience(s, party, failedStep)
+	}
+
+	// Steps added by an ability toggle are rolled back together, so the ability is toggled for every character or none
+	if toggle, ok := findAbilityToggleStep(s, failedStep.StepId); ok {
+		return c.compensateToggleCharacterAbility(s, toggle, failedStep)
+	}
+
 	// Perform compensation based on the action type
 	switch failedStep.Action {
 	case EquipAsset:
 		return c.compensateEquipAsset(s, failedStep)
 	case UnequipAsset:
 		return c.compensateUnequipAsset(s, failedStep)
+	case ModifyInventoryItemPosition:
+		return c.compensateModifyInventoryItemPosition(s, failedStep)
 	case CreateCharacter:
 		return c.compensateCreateCharacter(s, failedStep)
 	case CreateAndEquipAsset:
 		return c.compensateCreateAndEquipAsset(s, failedStep)
+	case CharacterBuffCleanse:
+		return c.compensateCharacterBuffCleanse(s, failedStep)
+	case TransferCharacter:
+		return c.compensateTransferCharacter(s, failedStep)
+	case AdjustNpcShopStock:
+		return c.compensateAdjustNpcShopStock(s, failedStep)
+	case SetWorldEventFlag:
+		return c.compensateSetWorldEventFlag(s, failedStep)
+	case ConsumeCoupon:
+		return c.compensateConsumeCoupon(s, failedStep)
+	case ApplyCharacterExpPenalty:
+		return c.compensateApplyCharacterExpPenalty(s, failedStep)
+	case ApplyDurabilityPenalty:
+		return c.compensateApplyDurabilityPenalty(s, failedStep)
+	case CreateAccountCharacterSlot:
+		return c.compensateCreateAccountCharacterSlot(s, failedStep)
+	case ModifyAssetExpiration:
+		return c.compensateModifyAssetExpiration(s, failedStep)
+	case ApplyHammer:
+		return c.compensateApplyHammer(s, failedStep)
+	case DeductMesos:
+		return c.compensateDeductMesos(s, failedStep)
+	case VerifyAndConsumeTicket:
+		return c.compensateVerifyAndConsumeTicket(s, failedStep)
+	case AdjustReactorState:
+		return c.compensateAdjustReactorState(s, failedStep)
+	case UpdateCharacterResource:
+		return c.compensateUpdateCharacterResource(s, failedStep)
+	case GrantPremiumTime:
+		return c.compensateGrantPremiumTime(s, failedStep)
+	case DissolveMarriage:
+		return c.compensateDissolveMarriage(s, failedStep)
+	case SealAsset:
+		return c.compensateSealAsset(s, failedStep)
+	case UnsealAsset:
+		return c.compensateUnsealAsset(s, failedStep)
+	case IssueTransportTicket:
+		return c.compensateIssueTransportTicket(s, failedStep)
+	case ScheduleWarp:
+		return c.compensateScheduleWarp(s, failedStep)
+	case SpawnEscort:
+		return c.compensateSpawnEscort(s, failedStep)
+	case AwaitEscort:
+		return c.compensateAwaitEscort(s, failedStep)
+	case UpdateCharacterAlignment:
+		return c.compensateUpdateCharacterAlignment(s, failedStep)
+	case ResetInstanceCooldown:
+		return c.compensateResetInstanceCooldown(s, failedStep)
+	case ApplyTitleBuffOnLogin:
+		return c.compensateApplyTitleBuffOnLogin(s, failedStep)
+	case ApplyWorldEventBuff:
+		return c.compensateApplyWorldEventBuff(s, failedStep)
+	case CharacterExperienceLock:
+		return c.compensateCharacterExperienceLock(s, failedStep)
+	case CharacterExperienceUnlock:
+		return c.compensateCharacterExperienceUnlock(s, failedStep)
+	case EquipAssetByTemplate:
+		return c.compensateEquipAssetByTemplate(s, failedStep)
+	case GrantStorageCapacity:
+		return c.compensateGrantStorageCapacity(s, failedStep)
+	case ChangeJob:
+		return c.compensateChangeJob(s, failedStep)
+	case PetEquip:
+		return c.compensatePetEquip(s, failedStep)
+	case PetUnequip:
+		return c.compensatePetUnequip(s, failedStep)
+	case SetQuestTimer:
+		return c.compensateSetQuestTimer(s, failedStep)
 	default:
 		c.l.WithFields(logrus.Fields{
 			"transaction_id": s.TransactionId.String(),
@@ -326,33 +857,38 @@ func (c *CompensatorImpl) compensateUnequipAsset(s Saga, failedStep Step[any]) e
 	return nil
 }
 
-// compensateCreateCharacter handles compensation for a failed CreateCharacter operation
-// Note: Character creation failures typically do not require compensation as the character
-// creation process is atomic. If partial creation occurred, the character service should
-// handle cleanup. This function exists for completeness and future extensibility.
-func (c *CompensatorImpl) compensateCreateCharacter(s Saga, failedStep Step[any]) error {
+// compensateModifyInventoryItemPosition handles compensation for a failed ModifyInventoryItemPosition operation
+// by moving the asset back from the destination to the source slot
+func (c *CompensatorImpl) compensateModifyInventoryItemPosition(s Saga, failedStep Step[any]) error {
 	// Extract the original payload
-	payload, ok := failedStep.Payload.(CharacterCreatePayload)
+	payload, ok := failedStep.Payload.(ModifyInventoryItemPositionPayload)
 	if !ok {
-		return fmt.Errorf("invalid payload for CreateCharacter compensation")
+		return fmt.Errorf("invalid payload for ModifyInventoryItemPosition compensation")
 	}
 
 	c.l.WithFields(logrus.Fields{
 		"transaction_id": s.TransactionId.String(),
 		"saga_type":      s.SagaType,
 		"step_id":        failedStep.StepId,
-		"account_id":     payload.AccountId,
-		"character_name": payload.Name,
-		"world_id":       payload.WorldId,
+		"character_id":   payload.CharacterId,
+		"source":         payload.Source,
+		"destination":    payload.Destination,
 		"tenant_id":      c.t.Id().String(),
-	}).Info("Compensating failed CreateCharacter operation - no rollback action available")
+	}).Info("Compensating failed ModifyInventoryItemPosition operation by moving the asset back")
 
-	// Note: Currently there is no character deletion command available
-	// in the character service, so we cannot perform actual rollback.
-	// The character service should handle cleanup of failed character creation internally.
-	// This compensation step simply acknowledges the failure and allows the saga to continue.
+	// Perform the reverse operation: move from destination back to source
+	err := c.compP.RequestMoveAsset(s.TransactionId, payload.CharacterId, byte(payload.InventoryType), payload.Destination, payload.Source)
+	if err != nil {
+		c.l.WithFields(logrus.Fields{
+			"transaction_id": s.TransactionId.String(),
+			"saga_type":      s.SagaType,
+			"step_id":        failedStep.StepId,
+			"tenant_id":      c.t.Id().String(),
+		}).WithError(err).Error("Failed to compensate ModifyInventoryItemPosition operation")
+		return err
+	}
 
-	// Mark the failed step as compensated by removing it from the saga
+	// Mark the failed step as compensated
 	failedStepIndex := s.FindFailedStepIndex()
 	if failedStepIndex != -1 {
 		if err := s.SetStepStatus(failedStepIndex, Pending); err != nil {
@@ -361,7 +897,7 @@ func (c *CompensatorImpl) compensateCreateCharacter(s Saga, failedStep Step[any]
 				"saga_type":      s.SagaType,
 				"step_index":     failedStepIndex,
 				"tenant_id":      c.t.Id().String(),
-			}).WithError(err).Error("Failed to mark CreateCharacter step as compensated")
+			}).WithError(err).Error("Failed to mark ModifyInventoryItemPosition step as compensated")
 			return err
 		}
 
@@ -371,7 +907,7 @@ func (c *CompensatorImpl) compensateCreateCharacter(s Saga, failedStep Step[any]
 				"transaction_id": s.TransactionId.String(),
 				"saga_type":      s.SagaType,
 				"tenant_id":      c.t.Id().String(),
-			}).WithError(err).Error("State consistency validation failed after CreateCharacter compensation")
+			}).WithError(err).Error("State consistency validation failed after ModifyInventoryItemPosition compensation")
 			return err
 		}
 
@@ -381,96 +917,541 @@ func (c *CompensatorImpl) compensateCreateCharacter(s Saga, failedStep Step[any]
 	return nil
 }
 
-// CompensateCreateAndEquipAsset handles compensation for a failed CreateAndEquipAsset operation
-// This compound action has two phases:
-// 1. Asset creation (handled by handleCreateAndEquipAsset)
-// 2. Dynamic equipment step creation (handled by compartment consumer)
-//
-// Compensation scenarios:
-// - Phase 1 failure: No compensation needed since nothing was created
-// - Phase 2 failure: Need to destroy the created asset since it was successfully created but failed to equip
-//
-// Note: This function is called when the CreateAndEquipAsset step itself fails,
-// not when the dynamically created EquipAsset step fails (that uses compensateEquipAsset)
-func (c *CompensatorImpl) compensateCreateAndEquipAsset(s Saga, failedStep Step[any]) error {
-	// Extract the original payload
-	payload, ok := failedStep.Payload.(CreateAndEquipAssetPayload)
-	if !ok {
-		return fmt.Errorf("invalid payload for CreateAndEquipAsset compensation")
+// findEquipmentPresetStep returns the ApplyEquipmentPreset or StripEquipment step which added the step identified, if any
+func findEquipmentPresetStep(s Saga, stepId string) (Step[any], bool) {
+	for _, st := range s.Steps {
+		if st.Action != ApplyEquipmentPreset && st.Action != StripEquipment {
+			continue
+		}
+		if strings.HasPrefix(stepId, st.StepId+"_unequip_") || strings.HasPrefix(stepId, st.StepId+"_equip_") {
+			return st, true
+		}
 	}
+	return Step[any]{}, false
+}
 
+// compensateEquipmentPreset handles compensation for a failed step of an equipment preset, by reversing the preset
+// steps which completed, most recent first, restoring the loadout captured before the preset was applied
+func (c *CompensatorImpl) compensateEquipmentPreset(s Saga, preset Step[any], failedStep Step[any]) error {
 	c.l.WithFields(logrus.Fields{
 		"transaction_id": s.TransactionId.String(),
 		"saga_type":      s.SagaType,
 		"step_id":        failedStep.StepId,
-		"character_id":   payload.CharacterId,
-		"template_id":    payload.Item.TemplateId,
-		"quantity":       payload.Item.Quantity,
+		"preset_step_id": preset.StepId,
 		"tenant_id":      c.t.Id().String(),
-	}).Info("Compensating failed CreateAndEquipAsset operation")
-
-	// For CreateAndEquipAsset, we need to determine if the asset was actually created
-	// If the failure happened during the asset creation phase, no compensation is needed
-	// If the failure happened during the equipment phase, we need to destroy the created asset
+	}).Info("Compensating failed equipment preset by restoring the prior loadout")
 
-	// Check if there are any auto-generated equip steps in this saga
-	// If an auto-equip step exists, it means the asset was successfully created
-	// and the failure occurred during the equipment phase
-	autoEquipStepExists := false
-	for _, step := range s.Steps {
-		if step.Action == EquipAsset && strings.HasPrefix(step.StepId, "auto_equip_step_") {
-			autoEquipStepExists = true
-			break
+	for i := len(s.Steps) - 1; i >= 0; i-- {
+		st := s.Steps[i]
+		if st.Status != Completed {
+			continue
+		}
+		if !strings.HasPrefix(st.StepId, preset.StepId+"_unequip_") && !strings.HasPrefix(st.StepId, preset.StepId+"_equip_") {
+			continue
 		}
-	}
-
-	if autoEquipStepExists {
-		// Asset was created but equipment failed - need to destroy the created asset
-		c.l.WithFields(logrus.Fields{
-			"transaction_id": s.TransactionId.String(),
-			"saga_type":      s.SagaType,
-			"step_id":        failedStep.StepId,
-			"character_id":   payload.CharacterId,
-			"template_id":    payload.Item.TemplateId,
-			"quantity":       payload.Item.Quantity,
-			"tenant_id":      c.t.Id().String(),
-		}).Info("Auto-equip step found - destroying created asset for compensation")
 
-		// Destroy the created asset
-		err := c.compP.RequestDestroyItem(s.TransactionId, payload.CharacterId, payload.Item.TemplateId, payload.Item.Quantity)
+		var err error
+		switch payload := st.Payload.(type) {
+		case EquipAssetPayload:
+			err = c.compP.RequestUnequipAsset(s.TransactionId, payload.CharacterId, byte(payload.InventoryType), payload.Destination, payload.Source)
+		case UnequipAssetPayload:
+			err = c.compP.RequestEquipAsset(s.TransactionId, payload.CharacterId, byte(payload.InventoryType), payload.Destination, payload.Source)
+		}
 		if err != nil {
 			c.l.WithFields(logrus.Fields{
 				"transaction_id": s.TransactionId.String(),
 				"saga_type":      s.SagaType,
-				"step_id":        failedStep.StepId,
-				"character_id":   payload.CharacterId,
-				"template_id":    payload.Item.TemplateId,
-				"quantity":       payload.Item.Quantity,
+				"step_id":        st.StepId,
 				"tenant_id":      c.t.Id().String(),
-			}).WithError(err).Error("Failed to destroy created asset during CreateAndEquipAsset compensation")
+			}).WithError(err).Error("Failed to reverse equipment preset step")
 			return err
 		}
-
-		c.l.WithFields(logrus.Fields{
-			"transaction_id": s.TransactionId.String(),
-			"saga_type":      s.SagaType,
-			"step_id":        failedStep.StepId,
-			"character_id":   payload.CharacterId,
-			"template_id":    payload.Item.TemplateId,
-			"quantity":       payload.Item.Quantity,
-			"tenant_id":      c.t.Id().String(),
-		}).Info("Successfully destroyed created asset during CreateAndEquipAsset compensation")
-	} else {
-		// No auto-equip step found - asset creation failed, no compensation needed
-		c.l.WithFields(logrus.Fields{
-			"transaction_id": s.TransactionId.String(),
-			"saga_type":      s.SagaType,
-			"step_id":        failedStep.StepId,
-			"character_id":   payload.CharacterId,
-			"template_id":    payload.Item.TemplateId,
-			"quantity":       payload.Item.Quantity,
-			"tenant_id":      c.t.Id().String(),
-		}).Info("No auto-equip step found - asset creation failed, no compensation needed")
+	}
+
+	// Mark the failed step as compensated
+	failedStepIndex := s.FindFailedStepIndex()
+	if failedStepIndex != -1 {
+		if err := s.SetStepStatus(failedStepIndex, Pending); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"step_index":     failedStepIndex,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("Failed to mark equipment preset step as compensated")
+			return err
+		}
+
+		// Validate state consistency before updating cache
+		if err := s.ValidateStateConsistency(); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("State consistency validation failed after equipment preset compensation")
+			return err
+		}
+
+		GetCache().Put(c.t.Id(), s)
+	}
+
+	return nil
+}
+
+// findMountStep returns the GrantMount step which added the step identified, if any
+func findMountStep(s Saga, stepId string) (Step[any], bool) {
+	for _, st := range s.Steps {
+		if st.Action != GrantMount {
+			continue
+		}
+		if stepId == st.StepId+"_skill" || stepId == st.StepId+"_item" {
+			return st, true
+		}
+	}
+	return Step[any]{}, false
+}
+
+// compensateGrantMount handles compensation for a failed step of a mount grant, by removing the skill or item granted by
+// the mount steps which completed, so the character is not left with one without the other
+func (c *CompensatorImpl) compensateGrantMount(s Saga, mount Step[any], failedStep Step[any]) error {
+	c.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        failedStep.StepId,
+		"mount_step_id":  mount.StepId,
+		"tenant_id":      c.t.Id().String(),
+	}).Info("Compensating failed mount grant by removing the skill and item granted")
+
+	for i := len(s.Steps) - 1; i >= 0; i-- {
+		st := s.Steps[i]
+		if st.Status != Completed {
+			continue
+		}
+		if st.StepId != mount.StepId+"_skill" && st.StepId != mount.StepId+"_item" {
+			continue
+		}
+
+		var err error
+		switch payload := st.Payload.(type) {
+		case CreateSkillPayload:
+			err = c.skillP.RequestDeleteAndEmit(s.TransactionId, payload.CharacterId, payload.SkillId)
+		case AwardItemActionPayload:
+			err = c.compP.RequestDestroyItem(s.TransactionId, payload.CharacterId, payload.Item.TemplateId, payload.Item.Quantity)
+		}
+		if err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"step_id":        st.StepId,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("Failed to reverse mount step")
+			return err
+		}
+	}
+
+	// Mark the failed step as compensated
+	failedStepIndex := s.FindFailedStepIndex()
+	if failedStepIndex != -1 {
+		if err := s.SetStepStatus(failedStepIndex, Pending); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"step_index":     failedStepIndex,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("Failed to mark mount step as compensated")
+			return err
+		}
+
+		// Validate state consistency before updating cache
+		if err := s.ValidateStateConsistency(); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("State consistency validation failed after mount compensation")
+			return err
+		}
+
+		GetCache().Put(c.t.Id(), s)
+	}
+
+	return nil
+}
+
+// findPartyExperienceStep returns the AwardPartyExperience step which added the step identified, if any
+func findPartyExperienceStep(s Saga, stepId string) (Step[any], bool) {
+	for _, st := range s.Steps {
+		if st.Action == AwardPartyExperience && strings.HasPrefix(stepId, st.StepId+"_member_") {
+			return st, true
+		}
+	}
+	return Step[any]{}, false
+}
+
+// compensateAwardPartyExperience handles compensation for a failed member step of a party experience split, by deducting
+// the shares awarded by the member steps which completed, so the pool is awarded to every member or to none
+func (c *CompensatorImpl) compensateAwardPartyExperience(s Saga, party Step[any], failedStep Step[any]) error {
+	c.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        failedStep.StepId,
+		"party_step_id":  party.StepId,
+		"tenant_id":      c.t.Id().String(),
+	}).Info("Compensating failed party experience split by deducting the shares awarded")
+
+	for i := len(s.Steps) - 1; i >= 0; i-- {
+		st := s.Steps[i]
+		if st.Status != Completed || !strings.HasPrefix(st.StepId, party.StepId+"_member_") {
+			continue
+		}
+		payload, ok := st.Payload.(AwardExperiencePayload)
+		if !ok {
+			continue
+		}
+		amount := uint32(0)
+		for _, d := range payload.Distributions {
+			amount += d.Amount
+		}
+		if err := c.charP.DeductExperienceAndEmit(s.TransactionId, payload.WorldId, payload.CharacterId, payload.ChannelId, amount); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"step_id":        st.StepId,
+				"character_id":   payload.CharacterId,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("Failed to deduct party member experience share")
+			return err
+		}
+	}
+
+	// Mark the failed step as compensated
+	failedStepIndex := s.FindFailedStepIndex()
+	if failedStepIndex != -1 {
+		if err := s.SetStepStatus(failedStepIndex, Pending); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"step_index":     failedStepIndex,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("Failed to mark party member experience step as compensated")
+			return err
+		}
+
+		// Validate state consistency before updating cache
+		if err := s.ValidateStateConsistency(); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("State consistency validation failed after party experience compensation")
+			return err
+		}
+
+		GetCache().Put(c.t.Id(), s)
+	}
+
+	return nil
+}
+
+// findAbilityToggleStep returns the ToggleCharacterAbility step which added the step identified, if any
+func findAbilityToggleStep(s Saga, stepId string) (Step[any], bool) {
+	for _, st := range s.Steps {
+		if st.Action == ToggleCharacterAbility && strings.HasPrefix(stepId, st.StepId+"_toggle_") {
+			return st, true
+		}
+	}
+	return Step[any]{}, false
+}
+
+// compensateToggleCharacterAbility handles compensation for a failed step of an ability toggle, by reversing the toggles
+// of the other characters which completed: deleting the skill of those it was enabled for, and restoring the level of
+// those it was disabled for
+func (c *CompensatorImpl) compensateToggleCharacterAbility(s Saga, toggle Step[any], failedStep Step[any]) error {
+	c.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        failedStep.StepId,
+		"toggle_step_id": toggle.StepId,
+		"tenant_id":      c.t.Id().String(),
+	}).Info("Compensating failed ability toggle by reversing the toggles applied")
+
+	level := byte(0)
+	if payload, ok := toggle.Payload.(ToggleCharacterAbilityPayload); ok {
+		level = payload.Level
+	}
+	for i := len(s.Steps) - 1; i >= 0; i-- {
+		st := s.Steps[i]
+		if st.Status != Completed || !strings.HasPrefix(st.StepId, toggle.StepId+"_toggle_") {
+			continue
+		}
+
+		var err error
+		switch payload := st.Payload.(type) {
+		case CreateSkillPayload:
+			err = c.skillP.RequestDeleteAndEmit(s.TransactionId, payload.CharacterId, payload.SkillId)
+		case UpdateSkillPayload:
+			err = c.skillP.RequestUpdateAndEmit(s.TransactionId, payload.CharacterId, payload.SkillId, level, level, payload.Expiration)
+		}
+		if err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"step_id":        st.StepId,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("Failed to reverse ability toggle")
+			return err
+		}
+	}
+
+	// Mark the failed step as compensated
+	failedStepIndex := s.FindFailedStepIndex()
+	if failedStepIndex != -1 {
+		if err := s.SetStepStatus(failedStepIndex, Pending); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"step_index":     failedStepIndex,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("Failed to mark ability toggle step as compensated")
+			return err
+		}
+
+		// Validate state consistency before updating cache
+		if err := s.ValidateStateConsistency(); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("State consistency validation failed after ability toggle compensation")
+			return err
+		}
+
+		GetCache().Put(c.t.Id(), s)
+	}
+
+	return nil
+}
+
+// findCreatedCharacterStep returns the completed CreateCharacter step which created a character before the step
+// identified failed, if any
+func findCreatedCharacterStep(s Saga, stepId string) (Step[any], bool) {
+	for _, st := range s.Steps {
+		if st.StepId == stepId {
+			break
+		}
+		if st.Action != CreateCharacter || st.Status != Completed {
+			continue
+		}
+		if payload, ok := st.Payload.(CharacterCreatePayload); ok && payload.CharacterId != 0 {
+			return st, true
+		}
+	}
+	return Step[any]{}, false
+}
+
+// compensateCreatedCharacter handles compensation for a failed step of a saga which created a character, by deleting
+// the character. The effects of the steps which completed against the character are removed with it, so the account is
+// not left with a partially onboarded character.
+func (c *CompensatorImpl) compensateCreatedCharacter(s Saga, created Step[any], failedStep Step[any]) error {
+	payload, ok := created.Payload.(CharacterCreatePayload)
+	if !ok {
+		return fmt.Errorf("invalid payload for CreateCharacter compensation")
+	}
+
+	c.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        failedStep.StepId,
+		"create_step_id": created.StepId,
+		"account_id":     payload.AccountId,
+		"character_id":   payload.CharacterId,
+		"character_name": payload.Name,
+		"world_id":       payload.WorldId,
+		"tenant_id":      c.t.Id().String(),
+	}).Info("Compensating failed character creation by deleting the character created")
+
+	if err := c.charP.RequestDeleteCharacter(s.TransactionId, payload.AccountId, world.Id(payload.WorldId), payload.CharacterId); err != nil {
+		c.l.WithFields(logrus.Fields{
+			"transaction_id": s.TransactionId.String(),
+			"saga_type":      s.SagaType,
+			"step_id":        failedStep.StepId,
+			"character_id":   payload.CharacterId,
+			"tenant_id":      c.t.Id().String(),
+		}).WithError(err).Error("Failed to delete created character")
+		return err
+	}
+
+	// Mark the failed step as compensated
+	failedStepIndex := s.FindFailedStepIndex()
+	if failedStepIndex != -1 {
+		if err := s.SetStepStatus(failedStepIndex, Pending); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"step_index":     failedStepIndex,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("Failed to mark step of created character as compensated")
+			return err
+		}
+
+		// Validate state consistency before updating cache
+		if err := s.ValidateStateConsistency(); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("State consistency validation failed after created character compensation")
+			return err
+		}
+
+		GetCache().Put(c.t.Id(), s)
+	}
+
+	return nil
+}
+
+// compensateCreateCharacter handles compensation for a failed CreateCharacter operation
+// Note: Character creation failures reported by the character service do not require compensation, as the character
+// creation process is atomic. A step which failed once its character was created, as when capturing from its CREATED
+// event failed, is compensated by deleting the character.
+func (c *CompensatorImpl) compensateCreateCharacter(s Saga, failedStep Step[any]) error {
+	// Extract the original payload
+	payload, ok := failedStep.Payload.(CharacterCreatePayload)
+	if !ok {
+		return fmt.Errorf("invalid payload for CreateCharacter compensation")
+	}
+	if payload.CharacterId != 0 {
+		return c.compensateCreatedCharacter(s, failedStep, failedStep)
+	}
+
+	c.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        failedStep.StepId,
+		"account_id":     payload.AccountId,
+		"character_name": payload.Name,
+		"world_id":       payload.WorldId,
+		"tenant_id":      c.t.Id().String(),
+	}).Info("Compensating failed CreateCharacter operation - no character was created")
+
+	// Mark the failed step as compensated
+	failedStepIndex := s.FindFailedStepIndex()
+	if failedStepIndex != -1 {
+		if err := s.SetStepStatus(failedStepIndex, Pending); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"step_index":     failedStepIndex,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("Failed to mark CreateCharacter step as compensated")
+			return err
+		}
+
+		// Validate state consistency before updating cache
+		if err := s.ValidateStateConsistency(); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("State consistency validation failed after CreateCharacter compensation")
+			return err
+		}
+
+		GetCache().Put(c.t.Id(), s)
+	}
+
+	return nil
+}
+
+// CompensateCreateAndEquipAsset handles compensation for a failed CreateAndEquipAsset operation
+// This compound action has two phases:
+// 1. Asset creation (handled by handleCreateAndEquipAsset)
+// 2. Dynamic equipment step creation (handled by compartment consumer)
+//
+// Compensation scenarios:
+// - Phase 1 failure: No compensation needed since nothing was created
+// - Phase 2 failure: Need to destroy the created asset since it was successfully created but failed to equip
+//
+// Note: This function is called when the CreateAndEquipAsset step itself fails,
+// not when the dynamically created EquipAsset step fails (that uses compensateEquipAsset)
+func (c *CompensatorImpl) compensateCreateAndEquipAsset(s Saga, failedStep Step[any]) error {
+	// Extract the original payload
+	payload, ok := failedStep.Payload.(CreateAndEquipAssetPayload)
+	if !ok {
+		return fmt.Errorf("invalid payload for CreateAndEquipAsset compensation")
+	}
+
+	c.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        failedStep.StepId,
+		"character_id":   payload.CharacterId,
+		"template_id":    payload.Item.TemplateId,
+		"quantity":       payload.Item.Quantity,
+		"tenant_id":      c.t.Id().String(),
+	}).Info("Compensating failed CreateAndEquipAsset operation")
+
+	// For CreateAndEquipAsset, we need to determine if the asset was actually created
+	// If the failure happened during the asset creation phase, no compensation is needed
+	// If the failure happened during the equipment phase, we need to destroy the created asset
+
+	// Check if there are any auto-generated equip steps in this saga
+	// If an auto-equip step exists, it means the asset was successfully created
+	// and the failure occurred during the equipment phase
+	autoEquipStepExists := false
+	for _, step := range s.Steps {
+		if (step.Action == EquipAsset || step.Action == EquipAssetByTemplate) && strings.HasPrefix(step.StepId, "auto_equip_step_") {
+			autoEquipStepExists = true
+			break
+		}
+	}
+
+	if autoEquipStepExists {
+		// Asset was created but equipment failed - need to destroy the created asset
+		c.l.WithFields(logrus.Fields{
+			"transaction_id": s.TransactionId.String(),
+			"saga_type":      s.SagaType,
+			"step_id":        failedStep.StepId,
+			"character_id":   payload.CharacterId,
+			"template_id":    payload.Item.TemplateId,
+			"quantity":       payload.Item.Quantity,
+			"tenant_id":      c.t.Id().String(),
+		}).Info("Auto-equip step found - destroying created asset for compensation")
+
+		// Destroy the created asset
+		err := c.compP.RequestDestroyItem(s.TransactionId, payload.CharacterId, payload.Item.TemplateId, payload.Item.Quantity)
+		if err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"step_id":        failedStep.StepId,
+				"character_id":   payload.CharacterId,
+				"template_id":    payload.Item.TemplateId,
+				"quantity":       payload.Item.Quantity,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("Failed to destroy created asset during CreateAndEquipAsset compensation")
+			return err
+		}
+
+		c.l.WithFields(logrus.Fields{
+			"transaction_id": s.TransactionId.String(),
+			"saga_type":      s.SagaType,
+			"step_id":        failedStep.StepId,
+			"character_id":   payload.CharacterId,
+			"template_id":    payload.Item.TemplateId,
+			"quantity":       payload.Item.Quantity,
+			"tenant_id":      c.t.Id().String(),
+		}).Info("Successfully destroyed created asset during CreateAndEquipAsset compensation")
+	} else {
+		// No auto-equip step found - asset creation failed, no compensation needed
+		c.l.WithFields(logrus.Fields{
+			"transaction_id": s.TransactionId.String(),
+			"saga_type":      s.SagaType,
+			"step_id":        failedStep.StepId,
+			"character_id":   payload.CharacterId,
+			"template_id":    payload.Item.TemplateId,
+			"quantity":       payload.Item.Quantity,
+			"tenant_id":      c.t.Id().String(),
+		}).Info("No auto-equip step found - asset creation failed, no compensation needed")
 	}
 
 	// Mark the failed step as compensated
@@ -492,7 +1473,2069 @@ func (c *CompensatorImpl) compensateCreateAndEquipAsset(s Saga, failedStep Step[
 				"transaction_id": s.TransactionId.String(),
 				"saga_type":      s.SagaType,
 				"tenant_id":      c.t.Id().String(),
-			}).WithError(err).Error("State consistency validation failed after CreateAndEquipAsset compensation")
+			}).WithError(err).Error("State consistency validation failed after CreateAndEquipAsset compensation")
+			return err
+		}
+
+		GetCache().Put(c.t.Id(), s)
+	}
+
+	return nil
+}
+
+// compensateCharacterBuffCleanse handles compensation for a failed CharacterBuffCleanse operation
+// by re-applying the buffs captured before the cleanse, for their remaining duration
+func (c *CompensatorImpl) compensateCharacterBuffCleanse(s Saga, failedStep Step[any]) error {
+	// Extract the original payload
+	payload, ok := failedStep.Payload.(CharacterBuffCleansePayload)
+	if !ok {
+		return fmt.Errorf("invalid payload for CharacterBuffCleanse compensation")
+	}
+
+	c.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        failedStep.StepId,
+		"character_id":   payload.CharacterId,
+		"captured":       len(payload.Captured),
+		"tenant_id":      c.t.Id().String(),
+	}).Info("Compensating failed CharacterBuffCleanse operation by re-applying captured buffs")
+
+	now := time.Now()
+	for _, b := range payload.Captured {
+		// Buffs which would have expired in the meantime cannot be restored
+		remaining := int32(b.ExpiresAt.Sub(now).Seconds())
+		if remaining <= 0 {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"step_id":        failedStep.StepId,
+				"character_id":   payload.CharacterId,
+				"source_id":      b.SourceId,
+				"tenant_id":      c.t.Id().String(),
+			}).Debug("Captured buff has expired, skipping re-application")
+			continue
+		}
+
+		changes := make([]buff2.StatChange, 0, len(b.Changes))
+		for _, ch := range b.Changes {
+			changes = append(changes, buff2.StatChange{Type: ch.Type, Amount: ch.Amount})
+		}
+
+		err := c.buffP.ApplyAndEmit(s.TransactionId, payload.WorldId, payload.ChannelId, payload.CharacterId, payload.CharacterId, b.SourceId, remaining, changes)
+		if err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"step_id":        failedStep.StepId,
+				"character_id":   payload.CharacterId,
+				"source_id":      b.SourceId,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("Failed to re-apply captured buff during CharacterBuffCleanse compensation")
+			return err
+		}
+	}
+
+	// Mark the failed step as compensated
+	failedStepIndex := s.FindFailedStepIndex()
+	if failedStepIndex != -1 {
+		if err := s.SetStepStatus(failedStepIndex, Pending); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"step_index":     failedStepIndex,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("Failed to mark CharacterBuffCleanse step as compensated")
+			return err
+		}
+
+		// Validate state consistency before updating cache
+		if err := s.ValidateStateConsistency(); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("State consistency validation failed after CharacterBuffCleanse compensation")
+			return err
+		}
+
+		GetCache().Put(c.t.Id(), s)
+	}
+
+	return nil
+}
+
+// compensateTransferCharacter handles compensation for a failed TransferCharacter operation
+// by re-parenting the character back to the source account
+func (c *CompensatorImpl) compensateTransferCharacter(s Saga, failedStep Step[any]) error {
+	// Extract the original payload
+	payload, ok := failedStep.Payload.(TransferCharacterPayload)
+	if !ok {
+		return fmt.Errorf("invalid payload for TransferCharacter compensation")
+	}
+
+	c.l.WithFields(logrus.Fields{
+		"transaction_id":    s.TransactionId.String(),
+		"saga_type":         s.SagaType,
+		"step_id":           failedStep.StepId,
+		"character_id":      payload.CharacterId,
+		"source_account_id": payload.SourceAccountId,
+		"target_account_id": payload.TargetAccountId,
+		"tenant_id":         c.t.Id().String(),
+	}).Info("Compensating failed TransferCharacter operation by returning the character to the source account")
+
+	// Perform the reverse operation: re-parent the character to the source account
+	err := c.charP.ChangeAccountAndEmit(s.TransactionId, payload.WorldId, payload.CharacterId, payload.SourceAccountId)
+	if err != nil {
+		c.l.WithFields(logrus.Fields{
+			"transaction_id": s.TransactionId.String(),
+			"saga_type":      s.SagaType,
+			"step_id":        failedStep.StepId,
+			"tenant_id":      c.t.Id().String(),
+		}).WithError(err).Error("Failed to compensate TransferCharacter operation")
+		return err
+	}
+
+	// Mark the failed step as compensated
+	failedStepIndex := s.FindFailedStepIndex()
+	if failedStepIndex != -1 {
+		if err := s.SetStepStatus(failedStepIndex, Pending); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"step_index":     failedStepIndex,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("Failed to mark TransferCharacter step as compensated")
+			return err
+		}
+
+		// Validate state consistency before updating cache
+		if err := s.ValidateStateConsistency(); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("State consistency validation failed after TransferCharacter compensation")
+			return err
+		}
+
+		GetCache().Put(c.t.Id(), s)
+	}
+
+	return nil
+}
+
+// compensateAdjustNpcShopStock handles compensation for a failed AdjustNpcShopStock operation
+// by adjusting the stock by the opposite amount, returning stock taken by a purchase
+func (c *CompensatorImpl) compensateAdjustNpcShopStock(s Saga, failedStep Step[any]) error {
+	// Extract the original payload
+	payload, ok := failedStep.Payload.(AdjustNpcShopStockPayload)
+	if !ok {
+		return fmt.Errorf("invalid payload for AdjustNpcShopStock compensation")
+	}
+
+	fl := c.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        failedStep.StepId,
+		"npc_id":         payload.NpcId,
+		"template_id":    payload.TemplateId,
+		"amount":         payload.Amount,
+		"tenant_id":      c.t.Id().String(),
+	})
+
+	// A rejected adjustment (e.g. out of stock) never took effect, so there is nothing to reverse
+	if failedStep.ReportedError() {
+		fl.Debug("AdjustNpcShopStock operation was rejected, no stock to return")
+	} else {
+		fl.Info("Compensating failed AdjustNpcShopStock operation by reversing the stock adjustment")
+
+		// Perform the reverse operation: adjust the stock by the opposite amount
+		err := c.worldP.AdjustShopStockAndEmit(s.TransactionId, payload.WorldId, payload.NpcId, payload.TemplateId, -payload.Amount)
+		if err != nil {
+			fl.WithError(err).Error("Failed to compensate AdjustNpcShopStock operation")
+			return err
+		}
+	}
+
+	// Mark the failed step as compensated
+	failedStepIndex := s.FindFailedStepIndex()
+	if failedStepIndex != -1 {
+		if err := s.SetStepStatus(failedStepIndex, Pending); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"step_index":     failedStepIndex,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("Failed to mark AdjustNpcShopStock step as compensated")
+			return err
+		}
+
+		// Validate state consistency before updating cache
+		if err := s.ValidateStateConsistency(); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("State consistency validation failed after AdjustNpcShopStock compensation")
+			return err
+		}
+
+		GetCache().Put(c.t.Id(), s)
+	}
+
+	return nil
+}
+
+// compensateSetWorldEventFlag handles compensation for a failed SetWorldEventFlag operation
+// by restoring the previous value of the flag, when one was given
+func (c *CompensatorImpl) compensateSetWorldEventFlag(s Saga, failedStep Step[any]) error {
+	// Extract the original payload
+	payload, ok := failedStep.Payload.(SetWorldEventFlagPayload)
+	if !ok {
+		return fmt.Errorf("invalid payload for SetWorldEventFlag compensation")
+	}
+
+	fl := c.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        failedStep.StepId,
+		"key":            payload.Key,
+		"tenant_id":      c.t.Id().String(),
+	})
+
+	if failedStep.ReportedError() || payload.Previous == nil {
+		fl.Debug("No previous value of the event flag to restore")
+	} else {
+		fl.Info("Compensating failed SetWorldEventFlag operation by restoring the previous value of the flag")
+
+		// Perform the reverse operation: restore the previous value of the flag
+		err := c.worldP.SetEventFlagAndEmit(s.TransactionId, payload.WorldId, payload.Key, *payload.Previous)
+		if err != nil {
+			fl.WithError(err).Error("Failed to compensate SetWorldEventFlag operation")
+			return err
+		}
+	}
+
+	// Mark the failed step as compensated
+	failedStepIndex := s.FindFailedStepIndex()
+	if failedStepIndex != -1 {
+		if err := s.SetStepStatus(failedStepIndex, Pending); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"step_index":     failedStepIndex,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("Failed to mark SetWorldEventFlag step as compensated")
+			return err
+		}
+
+		// Validate state consistency before updating cache
+		if err := s.ValidateStateConsistency(); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("State consistency validation failed after SetWorldEventFlag compensation")
+			return err
+		}
+
+		GetCache().Put(c.t.Id(), s)
+	}
+
+	return nil
+}
+
+// compensateConsumeCoupon handles compensation for a failed ConsumeCoupon operation
+// by releasing the coupon, so it may be redeemed again
+func (c *CompensatorImpl) compensateConsumeCoupon(s Saga, failedStep Step[any]) error {
+	// Extract the original payload
+	payload, ok := failedStep.Payload.(ConsumeCouponPayload)
+	if !ok {
+		return fmt.Errorf("invalid payload for ConsumeCoupon compensation")
+	}
+
+	fl := c.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        failedStep.StepId,
+		"account_id":     payload.AccountId,
+		"character_id":   payload.CharacterId,
+		"tenant_id":      c.t.Id().String(),
+	})
+
+	// A rejected consumption (e.g. already consumed by another redemption) never took effect, and the coupon must not be
+	// released on behalf of whoever did consume it
+	if failedStep.ReportedError() {
+		fl.Debug("ConsumeCoupon operation was rejected, no coupon to release")
+	} else {
+		fl.Info("Compensating failed ConsumeCoupon operation by releasing the coupon")
+
+		err := c.couponP.ReleaseAndEmit(s.TransactionId, payload.AccountId, payload.CharacterId, payload.Code)
+		if err != nil {
+			fl.WithError(err).Error("Failed to compensate ConsumeCoupon operation")
+			return err
+		}
+	}
+
+	// Mark the failed step as compensated
+	failedStepIndex := s.FindFailedStepIndex()
+	if failedStepIndex != -1 {
+		if err := s.SetStepStatus(failedStepIndex, Pending); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"step_index":     failedStepIndex,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("Failed to mark ConsumeCoupon step as compensated")
+			return err
+		}
+
+		// Validate state consistency before updating cache
+		if err := s.ValidateStateConsistency(); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("State consistency validation failed after ConsumeCoupon compensation")
+			return err
+		}
+
+		GetCache().Put(c.t.Id(), s)
+	}
+
+	return nil
+}
+
+// compensateApplyCharacterExpPenalty handles compensation for a failed ApplyCharacterExpPenalty operation
+// by awarding the deducted experience back to the character
+func (c *CompensatorImpl) compensateApplyCharacterExpPenalty(s Saga, failedStep Step[any]) error {
+	// Extract the original payload
+	payload, ok := failedStep.Payload.(ApplyCharacterExpPenaltyPayload)
+	if !ok {
+		return fmt.Errorf("invalid payload for ApplyCharacterExpPenalty compensation")
+	}
+
+	fl := c.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        failedStep.StepId,
+		"character_id":   payload.CharacterId,
+		"amount":         payload.Amount,
+		"tenant_id":      c.t.Id().String(),
+	})
+
+	// A rejected penalty never took effect, so there is nothing to restore
+	if failedStep.ReportedError() {
+		fl.Debug("ApplyCharacterExpPenalty operation was rejected, nothing to restore")
+	} else {
+		fl.Info("Compensating failed ApplyCharacterExpPenalty operation by awarding the deducted experience")
+
+		eds := []character2.ExperienceDistributions{{ExperienceType: character2.ExperienceDistributionTypeWhite, Amount: payload.Amount}}
+		err := c.charP.AwardExperienceAndEmit(s.TransactionId, payload.WorldId, payload.CharacterId, payload.ChannelId, eds)
+		if err != nil {
+			fl.WithError(err).Error("Failed to compensate ApplyCharacterExpPenalty operation")
+			return err
+		}
+	}
+
+	// Mark the failed step as compensated
+	failedStepIndex := s.FindFailedStepIndex()
+	if failedStepIndex != -1 {
+		if err := s.SetStepStatus(failedStepIndex, Pending); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"step_index":     failedStepIndex,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("Failed to mark ApplyCharacterExpPenalty step as compensated")
+			return err
+		}
+
+		// Validate state consistency before updating cache
+		if err := s.ValidateStateConsistency(); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("State consistency validation failed after ApplyCharacterExpPenalty compensation")
+			return err
+		}
+
+		GetCache().Put(c.t.Id(), s)
+	}
+
+	return nil
+}
+
+// compensateApplyDurabilityPenalty handles compensation for a failed ApplyDurabilityPenalty operation
+// by restoring the durability lost by the character's equipped items
+func (c *CompensatorImpl) compensateApplyDurabilityPenalty(s Saga, failedStep Step[any]) error {
+	// Extract the original payload
+	payload, ok := failedStep.Payload.(ApplyDurabilityPenaltyPayload)
+	if !ok {
+		return fmt.Errorf("invalid payload for ApplyDurabilityPenalty compensation")
+	}
+
+	fl := c.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        failedStep.StepId,
+		"character_id":   payload.CharacterId,
+		"percent":        payload.Percent,
+		"tenant_id":      c.t.Id().String(),
+	})
+
+	// A rejected penalty never took effect, so there is nothing to restore
+	if failedStep.ReportedError() {
+		fl.Debug("ApplyDurabilityPenalty operation was rejected, nothing to restore")
+	} else {
+		fl.Info("Compensating failed ApplyDurabilityPenalty operation by restoring the lost durability")
+
+		err := c.compP.RequestChangeDurability(s.TransactionId, payload.CharacterId, int8(payload.Percent))
+		if err != nil {
+			fl.WithError(err).Error("Failed to compensate ApplyDurabilityPenalty operation")
+			return err
+		}
+	}
+
+	// Mark the failed step as compensated
+	failedStepIndex := s.FindFailedStepIndex()
+	if failedStepIndex != -1 {
+		if err := s.SetStepStatus(failedStepIndex, Pending); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"step_index":     failedStepIndex,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("Failed to mark ApplyDurabilityPenalty step as compensated")
+			return err
+		}
+
+		// Validate state consistency before updating cache
+		if err := s.ValidateStateConsistency(); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("State consistency validation failed after ApplyDurabilityPenalty compensation")
+			return err
+		}
+
+		GetCache().Put(c.t.Id(), s)
+	}
+
+	return nil
+}
+
+// compensateCreateAccountCharacterSlot handles compensation for a failed CreateAccountCharacterSlot operation
+// by removing the added character slots
+func (c *CompensatorImpl) compensateCreateAccountCharacterSlot(s Saga, failedStep Step[any]) error {
+	// Extract the original payload
+	payload, ok := failedStep.Payload.(CreateAccountCharacterSlotPayload)
+	if !ok {
+		return fmt.Errorf("invalid payload for CreateAccountCharacterSlot compensation")
+	}
+
+	fl := c.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        failedStep.StepId,
+		"account_id":     payload.AccountId,
+		"world_id":       payload.WorldId,
+		"tenant_id":      c.t.Id().String(),
+	})
+
+	// A rejected change (e.g. the slot limit was reached) never added slots, and removing slots on its behalf would
+	// take away slots the account already owned
+	if failedStep.ReportedError() {
+		fl.Debug("CreateAccountCharacterSlot operation was rejected, no character slots to remove")
+	} else {
+		fl.Info("Compensating failed CreateAccountCharacterSlot operation by removing the added character slots")
+
+		err := c.acctP.ChangeCharacterSlotsAndEmit(s.TransactionId, payload.AccountId, payload.WorldId, -int16(payload.Amount))
+		if err != nil {
+			fl.WithError(err).Error("Failed to compensate CreateAccountCharacterSlot operation")
+			return err
+		}
+	}
+
+	// Mark the failed step as compensated
+	failedStepIndex := s.FindFailedStepIndex()
+	if failedStepIndex != -1 {
+		if err := s.SetStepStatus(failedStepIndex, Pending); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"step_index":     failedStepIndex,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("Failed to mark CreateAccountCharacterSlot step as compensated")
+			return err
+		}
+
+		// Validate state consistency before updating cache
+		if err := s.ValidateStateConsistency(); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("State consistency validation failed after CreateAccountCharacterSlot compensation")
+			return err
+		}
+
+		GetCache().Put(c.t.Id(), s)
+	}
+
+	return nil
+}
+
+// compensateModifyAssetExpiration handles compensation for a failed ModifyAssetExpiration operation
+// by restoring the asset's prior expiration
+func (c *CompensatorImpl) compensateModifyAssetExpiration(s Saga, failedStep Step[any]) error {
+	// Extract the original payload
+	payload, ok := failedStep.Payload.(ModifyAssetExpirationPayload)
+	if !ok {
+		return fmt.Errorf("invalid payload for ModifyAssetExpiration compensation")
+	}
+
+	fl := c.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        failedStep.StepId,
+		"character_id":   payload.CharacterId,
+		"inventory_type": payload.InventoryType,
+		"slot":           payload.Slot,
+		"tenant_id":      c.t.Id().String(),
+	})
+
+	// A rejected change never took effect, and a change which never recorded the prior expiration was never requested
+	if failedStep.ReportedError() || payload.Previous == nil {
+		fl.Debug("ModifyAssetExpiration operation did not take effect, nothing to restore")
+	} else {
+		fl.Info("Compensating failed ModifyAssetExpiration operation by restoring the prior expiration")
+
+		err := c.compP.RequestChangeExpiration(s.TransactionId, payload.CharacterId, payload.InventoryType, payload.Slot, *payload.Previous)
+		if err != nil {
+			fl.WithError(err).Error("Failed to compensate ModifyAssetExpiration operation")
+			return err
+		}
+	}
+
+	// Mark the failed step as compensated
+	failedStepIndex := s.FindFailedStepIndex()
+	if failedStepIndex != -1 {
+		if err := s.SetStepStatus(failedStepIndex, Pending); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"step_index":     failedStepIndex,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("Failed to mark ModifyAssetExpiration step as compensated")
+			return err
+		}
+
+		// Validate state consistency before updating cache
+		if err := s.ValidateStateConsistency(); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("State consistency validation failed after ModifyAssetExpiration compensation")
+			return err
+		}
+
+		GetCache().Put(c.t.Id(), s)
+	}
+
+	return nil
+}
+
+// compensateApplyHammer handles compensation for a failed ApplyHammer operation
+// by restoring the equip's upgrade slots from the snapshot taken before the hammer was applied
+func (c *CompensatorImpl) compensateApplyHammer(s Saga, failedStep Step[any]) error {
+	// Extract the original payload
+	payload, ok := failedStep.Payload.(ApplyHammerPayload)
+	if !ok {
+		return fmt.Errorf("invalid payload for ApplyHammer compensation")
+	}
+
+	fl := c.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        failedStep.StepId,
+		"character_id":   payload.CharacterId,
+		"slot":           payload.Slot,
+		"tenant_id":      c.t.Id().String(),
+	})
+
+	// A rejected hammer never took effect, and a hammer without a snapshot was never requested
+	if failedStep.ReportedError() || payload.Snapshot == nil {
+		fl.Debug("ApplyHammer operation did not take effect, nothing to restore")
+	} else {
+		fl.Info("Compensating failed ApplyHammer operation by restoring the equip's upgrade slots")
+
+		err := c.compP.RequestSetUpgradeSlots(s.TransactionId, payload.CharacterId, payload.Slot, payload.Snapshot.Slots, payload.Snapshot.HammersApplied)
+		if err != nil {
+			fl.WithError(err).Error("Failed to compensate ApplyHammer operation")
+			return err
+		}
+	}
+
+	// Mark the failed step as compensated
+	failedStepIndex := s.FindFailedStepIndex()
+	if failedStepIndex != -1 {
+		if err := s.SetStepStatus(failedStepIndex, Pending); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"step_index":     failedStepIndex,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("Failed to mark ApplyHammer step as compensated")
+			return err
+		}
+
+		// Validate state consistency before updating cache
+		if err := s.ValidateStateConsistency(); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("State consistency validation failed after ApplyHammer compensation")
+			return err
+		}
+
+		GetCache().Put(c.t.Id(), s)
+	}
+
+	return nil
+}
+
+// compensateDeductMesos handles compensation for a failed DeductMesos operation
+// by crediting the deducted mesos back to the character
+func (c *CompensatorImpl) compensateDeductMesos(s Saga, failedStep Step[any]) error {
+	// Extract the original payload
+	payload, ok := failedStep.Payload.(DeductMesosPayload)
+	if !ok {
+		return fmt.Errorf("invalid payload for DeductMesos compensation")
+	}
+
+	fl := c.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        failedStep.StepId,
+		"character_id":   payload.CharacterId,
+		"amount":         payload.Amount,
+		"tenant_id":      c.t.Id().String(),
+	})
+
+	// A rejected deduction (e.g. for lack of mesos) never took effect, so there is nothing to refund
+	if failedStep.ReportedError() {
+		fl.Debug("DeductMesos operation was rejected, nothing to refund")
+	} else {
+		fl.Info("Compensating failed DeductMesos operation by refunding the deducted mesos")
+
+		err := c.charP.AwardMesosAndEmit(s.TransactionId, payload.WorldId, payload.CharacterId, payload.ChannelId, payload.ActorId, payload.ActorType, int32(payload.Amount))
+		if err != nil {
+			fl.WithError(err).Error("Failed to compensate DeductMesos operation")
+			return err
+		}
+	}
+
+	// Mark the failed step as compensated
+	failedStepIndex := s.FindFailedStepIndex()
+	if failedStepIndex != -1 {
+		if err := s.SetStepStatus(failedStepIndex, Pending); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"step_index":     failedStepIndex,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("Failed to mark DeductMesos step as compensated")
+			return err
+		}
+
+		// Validate state consistency before updating cache
+		if err := s.ValidateStateConsistency(); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("State consistency validation failed after DeductMesos compensation")
+			return err
+		}
+
+		GetCache().Put(c.t.Id(), s)
+	}
+
+	return nil
+}
+
+// compensateVerifyAndConsumeTicket handles compensation for a VerifyAndConsumeTicket operation, returning the consumed
+// ticket should the instance it granted entry to fail to be provisioned
+func (c *CompensatorImpl) compensateVerifyAndConsumeTicket(s Saga, failedStep Step[any]) error {
+	// Extract the original payload
+	payload, ok := failedStep.Payload.(VerifyAndConsumeTicketPayload)
+	if !ok {
+		return fmt.Errorf("invalid payload for VerifyAndConsumeTicket compensation")
+	}
+
+	fl := c.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        failedStep.StepId,
+		"character_id":   payload.CharacterId,
+		"template_id":    payload.TemplateId,
+		"tenant_id":      c.t.Id().String(),
+	})
+
+	// A ticket which was not held, or whose consumption was rejected, was never consumed, so there is nothing to return
+	if payload.Proof == nil || failedStep.ReportedError() {
+		fl.Debug("VerifyAndConsumeTicket operation did not consume a ticket, nothing to return")
+	} else {
+		fl.Info("Compensating failed VerifyAndConsumeTicket operation by returning the consumed ticket")
+
+		err := c.compP.RequestCreateItem(s.TransactionId, payload.CharacterId, payload.Proof.TemplateId, payload.Proof.Quantity)
+		if err != nil {
+			fl.WithError(err).Error("Failed to compensate VerifyAndConsumeTicket operation")
+			return err
+		}
+	}
+
+	// Mark the failed step as compensated
+	failedStepIndex := s.FindFailedStepIndex()
+	if failedStepIndex != -1 {
+		if err := s.SetStepStatus(failedStepIndex, Pending); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"step_index":     failedStepIndex,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("Failed to mark VerifyAndConsumeTicket step as compensated")
+			return err
+		}
+
+		// Validate state consistency before updating cache
+		if err := s.ValidateStateConsistency(); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("State consistency validation failed after VerifyAndConsumeTicket compensation")
+			return err
+		}
+
+		GetCache().Put(c.t.Id(), s)
+	}
+
+	return nil
+}
+
+// compensateAdjustReactorState compensates a failed AdjustReactorState operation by restoring the reactor's previous
+// state, when given
+func (c *CompensatorImpl) compensateAdjustReactorState(s Saga, failedStep Step[any]) error {
+	// Extract the original payload
+	payload, ok := failedStep.Payload.(AdjustReactorStatePayload)
+	if !ok {
+		return fmt.Errorf("invalid payload for AdjustReactorState compensation")
+	}
+
+	fl := c.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        failedStep.StepId,
+		"reactor_id":     payload.ReactorId,
+		"tenant_id":      c.t.Id().String(),
+	})
+
+	f, ok := field.FromId(payload.FieldId)
+	if failedStep.ReportedError() || payload.Previous == nil || !ok {
+		fl.Debug("No previous state of the reactor to restore")
+	} else {
+		fl.Info("Compensating failed AdjustReactorState operation by restoring the previous state of the reactor")
+
+		// Perform the reverse operation: restore the previous state of the reactor
+		err := c.reactP.ChangeStateAndEmit(s.TransactionId, f, payload.ReactorId, *payload.Previous)
+		if err != nil {
+			fl.WithError(err).Error("Failed to compensate AdjustReactorState operation")
+			return err
+		}
+	}
+
+	// Mark the failed step as compensated
+	failedStepIndex := s.FindFailedStepIndex()
+	if failedStepIndex != -1 {
+		if err := s.SetStepStatus(failedStepIndex, Pending); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"step_index":     failedStepIndex,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("Failed to mark AdjustReactorState step as compensated")
+			return err
+		}
+
+		// Validate state consistency before updating cache
+		if err := s.ValidateStateConsistency(); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("State consistency validation failed after AdjustReactorState compensation")
+			return err
+		}
+
+		GetCache().Put(c.t.Id(), s)
+	}
+
+	return nil
+}
+
+// compensateUpdateCharacterResource handles compensation for a failed UpdateCharacterResource operation
+// by restoring the value the resource held before it was updated
+func (c *CompensatorImpl) compensateUpdateCharacterResource(s Saga, failedStep Step[any]) error {
+	// Extract the original payload
+	payload, ok := failedStep.Payload.(UpdateCharacterResourcePayload)
+	if !ok {
+		return fmt.Errorf("invalid payload for UpdateCharacterResource compensation")
+	}
+
+	fl := c.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        failedStep.StepId,
+		"character_id":   payload.CharacterId,
+		"resource":       payload.Resource,
+		"tenant_id":      c.t.Id().String(),
+	})
+
+	// A rejected change never took effect, and a change which never recorded the prior value was never requested
+	if failedStep.ReportedError() || payload.Previous == nil {
+		fl.Debug("UpdateCharacterResource operation did not take effect, nothing to restore")
+	} else {
+		fl.Info("Compensating failed UpdateCharacterResource operation by restoring the prior value")
+
+		err := c.charP.ChangeResourceAndEmit(s.TransactionId, payload.WorldId, payload.CharacterId, payload.ChannelId, payload.Resource, *payload.Previous)
+		if err != nil {
+			fl.WithError(err).Error("Failed to compensate UpdateCharacterResource operation")
+			return err
+		}
+	}
+
+	// Mark the failed step as compensated
+	failedStepIndex := s.FindFailedStepIndex()
+	if failedStepIndex != -1 {
+		if err := s.SetStepStatus(failedStepIndex, Pending); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"step_index":     failedStepIndex,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("Failed to mark UpdateCharacterResource step as compensated")
+			return err
+		}
+
+		// Validate state consistency before updating cache
+		if err := s.ValidateStateConsistency(); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("State consistency validation failed after UpdateCharacterResource compensation")
+			return err
+		}
+
+		GetCache().Put(c.t.Id(), s)
+	}
+
+	return nil
+}
+
+// compensateChangeJob handles compensation for a failed ChangeJob operation
+// by reverting the character to the job held before it was changed
+func (c *CompensatorImpl) compensateChangeJob(s Saga, failedStep Step[any]) error {
+	// Extract the original payload
+	payload, ok := failedStep.Payload.(ChangeJobPayload)
+	if !ok {
+		return fmt.Errorf("invalid payload for ChangeJob compensation")
+	}
+
+	fl := c.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        failedStep.StepId,
+		"character_id":   payload.CharacterId,
+		"job_id":         payload.JobId,
+		"tenant_id":      c.t.Id().String(),
+	})
+
+	// A rejected change never took effect, and a change which never recorded the prior job was never requested
+	if failedStep.ReportedError() || payload.Previous == nil {
+		fl.Debug("ChangeJob operation did not take effect, nothing to revert")
+	} else {
+		fl.Info("Compensating failed ChangeJob operation by reverting to the prior job")
+
+		err := c.charP.ChangeJobAndEmit(s.TransactionId, payload.WorldId, payload.CharacterId, payload.ChannelId, *payload.Previous)
+		if err != nil {
+			fl.WithError(err).Error("Failed to compensate ChangeJob operation")
+			return err
+		}
+	}
+
+	// Mark the failed step as compensated
+	failedStepIndex := s.FindFailedStepIndex()
+	if failedStepIndex != -1 {
+		if err := s.SetStepStatus(failedStepIndex, Pending); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"step_index":     failedStepIndex,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("Failed to mark ChangeJob step as compensated")
+			return err
+		}
+
+		// Validate state consistency before updating cache
+		if err := s.ValidateStateConsistency(); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("State consistency validation failed after ChangeJob compensation")
+			return err
+		}
+
+		GetCache().Put(c.t.Id(), s)
+	}
+
+	return nil
+}
+
+// compensateGrantPremiumTime handles compensation for a failed GrantPremiumTime operation
+// by deducting the credited premium time
+func (c *CompensatorImpl) compensateGrantPremiumTime(s Saga, failedStep Step[any]) error {
+	// Extract the original payload
+	payload, ok := failedStep.Payload.(GrantPremiumTimePayload)
+	if !ok {
+		return fmt.Errorf("invalid payload for GrantPremiumTime compensation")
+	}
+
+	fl := c.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        failedStep.StepId,
+		"account_id":     payload.AccountId,
+		"tenant_id":      c.t.Id().String(),
+	})
+
+	// A rejected change never credited time, and deducting time on its behalf would take away time the account already
+	// owned
+	if failedStep.ReportedError() {
+		fl.Debug("GrantPremiumTime operation was rejected, no premium time to deduct")
+	} else {
+		fl.Info("Compensating failed GrantPremiumTime operation by deducting the credited premium time")
+
+		err := c.acctP.ChangePremiumTimeAndEmit(s.TransactionId, payload.AccountId, -int64(payload.Seconds))
+		if err != nil {
+			fl.WithError(err).Error("Failed to compensate GrantPremiumTime operation")
+			return err
+		}
+	}
+
+	// Mark the failed step as compensated
+	failedStepIndex := s.FindFailedStepIndex()
+	if failedStepIndex != -1 {
+		if err := s.SetStepStatus(failedStepIndex, Pending); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"step_index":     failedStepIndex,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("Failed to mark GrantPremiumTime step as compensated")
+			return err
+		}
+
+		// Validate state consistency before updating cache
+		if err := s.ValidateStateConsistency(); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("State consistency validation failed after GrantPremiumTime compensation")
+			return err
+		}
+
+		GetCache().Put(c.t.Id(), s)
+	}
+
+	return nil
+}
+
+// compensateDissolveMarriage handles compensation for a failed DissolveMarriage operation
+// by restoring the marriage
+func (c *CompensatorImpl) compensateDissolveMarriage(s Saga, failedStep Step[any]) error {
+	// Extract the original payload
+	payload, ok := failedStep.Payload.(DissolveMarriagePayload)
+	if !ok {
+		return fmt.Errorf("invalid payload for DissolveMarriage compensation")
+	}
+
+	fl := c.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        failedStep.StepId,
+		"character_id":   payload.CharacterId,
+		"marriage_id":    payload.MarriageId,
+		"tenant_id":      c.t.Id().String(),
+	})
+
+	// A rejected divorce (e.g. the couple had already divorced) never dissolved the marriage, and restoring it would
+	// reverse a divorce made by another transaction
+	if failedStep.ReportedError() {
+		fl.Debug("DissolveMarriage operation was rejected, no marriage to restore")
+	} else {
+		fl.Info("Compensating failed DissolveMarriage operation by restoring the marriage")
+
+		err := c.marriP.RestoreAndEmit(s.TransactionId, payload.CharacterId, payload.MarriageId, payload.PartnerId)
+		if err != nil {
+			fl.WithError(err).Error("Failed to compensate DissolveMarriage operation")
+			return err
+		}
+	}
+
+	// Mark the failed step as compensated
+	failedStepIndex := s.FindFailedStepIndex()
+	if failedStepIndex != -1 {
+		if err := s.SetStepStatus(failedStepIndex, Pending); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"step_index":     failedStepIndex,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("Failed to mark DissolveMarriage step as compensated")
+			return err
+		}
+
+		// Validate state consistency before updating cache
+		if err := s.ValidateStateConsistency(); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("State consistency validation failed after DissolveMarriage compensation")
+			return err
+		}
+
+		GetCache().Put(c.t.Id(), s)
+	}
+
+	return nil
+}
+
+// compensateSealAsset handles compensation for a failed SealAsset operation
+func (c *CompensatorImpl) compensateSealAsset(s Saga, failedStep Step[any]) error {
+	// Extract the original payload
+	payload, ok := failedStep.Payload.(SealAssetPayload)
+	if !ok {
+		return fmt.Errorf("invalid payload for SealAsset compensation")
+	}
+
+	fl := c.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        failedStep.StepId,
+		"character_id":   payload.CharacterId,
+		"inventory_type": payload.InventoryType,
+		"slot":           payload.Slot,
+		"tenant_id":      c.t.Id().String(),
+	})
+
+	// A rejected seal (e.g. the asset was no longer in the slot) reserved nothing
+	if failedStep.ReportedError() {
+		fl.Debug("SealAsset operation was rejected, no reservation to cancel")
+	} else {
+		fl.Info("Compensating failed SealAsset operation by cancelling the reservation")
+
+		err := c.compP.RequestCancelReservation(s.TransactionId, payload.CharacterId, payload.InventoryType, payload.Slot)
+		if err != nil {
+			fl.WithError(err).Error("Failed to compensate SealAsset operation")
+			return err
+		}
+	}
+
+	// Mark the failed step as compensated
+	failedStepIndex := s.FindFailedStepIndex()
+	if failedStepIndex != -1 {
+		if err := s.SetStepStatus(failedStepIndex, Pending); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"step_index":     failedStepIndex,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("Failed to mark SealAsset step as compensated")
+			return err
+		}
+
+		// Validate state consistency before updating cache
+		if err := s.ValidateStateConsistency(); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("State consistency validation failed after SealAsset compensation")
+			return err
+		}
+
+		GetCache().Put(c.t.Id(), s)
+	}
+
+	return nil
+}
+
+// compensateUnsealAsset handles compensation for a failed UnsealAsset operation
+func (c *CompensatorImpl) compensateUnsealAsset(s Saga, failedStep Step[any]) error {
+	// Extract the original payload
+	payload, ok := failedStep.Payload.(UnsealAssetPayload)
+	if !ok {
+		return fmt.Errorf("invalid payload for UnsealAsset compensation")
+	}
+
+	fl := c.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        failedStep.StepId,
+		"character_id":   payload.CharacterId,
+		"inventory_type": payload.InventoryType,
+		"slot":           payload.Slot,
+		"tenant_id":      c.t.Id().String(),
+	})
+
+	// A rejected unseal cancelled no reservation, so the asset remains sealed
+	if failedStep.ReportedError() {
+		fl.Debug("UnsealAsset operation was rejected, no reservation to restore")
+	} else {
+		fl.Info("Compensating failed UnsealAsset operation by sealing the asset again")
+
+		err := c.compP.RequestReserveAsset(s.TransactionId, payload.CharacterId, payload.InventoryType, payload.Slot, payload.TemplateId, payload.Quantity)
+		if err != nil {
+			fl.WithError(err).Error("Failed to compensate UnsealAsset operation")
+			return err
+		}
+	}
+
+	// Mark the failed step as compensated
+	failedStepIndex := s.FindFailedStepIndex()
+	if failedStepIndex != -1 {
+		if err := s.SetStepStatus(failedStepIndex, Pending); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"step_index":     failedStepIndex,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("Failed to mark UnsealAsset step as compensated")
+			return err
+		}
+
+		// Validate state consistency before updating cache
+		if err := s.ValidateStateConsistency(); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("State consistency validation failed after UnsealAsset compensation")
+			return err
+		}
+
+		GetCache().Put(c.t.Id(), s)
+	}
+
+	return nil
+}
+
+// compensateIssueTransportTicket handles compensation for a failed IssueTransportTicket operation by refunding the fare paid for the transport,
+// as the ticket was not issued
+func (c *CompensatorImpl) compensateIssueTransportTicket(s Saga, failedStep Step[any]) error {
+	// Extract the original payload
+	payload, ok := failedStep.Payload.(IssueTransportTicketPayload)
+	if !ok {
+		return fmt.Errorf("invalid payload for IssueTransportTicket compensation")
+	}
+
+	fl := c.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        failedStep.StepId,
+		"character_id":   payload.CharacterId,
+		"fare":           payload.Fare,
+		"tenant_id":      c.t.Id().String(),
+	})
+
+	if payload.Fare == 0 {
+		fl.Debug("IssueTransportTicket operation had no fare, nothing to refund")
+	} else {
+		fl.Info("Compensating failed IssueTransportTicket operation by refunding the fare")
+
+		err := c.charP.AwardMesosAndEmit(s.TransactionId, payload.WorldId, payload.CharacterId, payload.ChannelId, 0, "SYSTEM", int32(payload.Fare))
+		if err != nil {
+			fl.WithError(err).Error("Failed to compensate IssueTransportTicket operation")
+			return err
+		}
+	}
+
+	// Mark the failed step as compensated
+	failedStepIndex := s.FindFailedStepIndex()
+	if failedStepIndex != -1 {
+		if err := s.SetStepStatus(failedStepIndex, Pending); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"step_index":     failedStepIndex,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("Failed to mark IssueTransportTicket step as compensated")
+			return err
+		}
+
+		// Validate state consistency before updating cache
+		if err := s.ValidateStateConsistency(); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("State consistency validation failed after IssueTransportTicket compensation")
+			return err
+		}
+
+		GetCache().Put(c.t.Id(), s)
+	}
+
+	return nil
+}
+
+// compensateScheduleWarp handles compensation for a failed ScheduleWarp operation by refunding the fare paid for the transport,
+// as the character did not arrive
+func (c *CompensatorImpl) compensateScheduleWarp(s Saga, failedStep Step[any]) error {
+	// Extract the original payload
+	payload, ok := failedStep.Payload.(ScheduleWarpPayload)
+	if !ok {
+		return fmt.Errorf("invalid payload for ScheduleWarp compensation")
+	}
+
+	fl := c.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        failedStep.StepId,
+		"character_id":   payload.CharacterId,
+		"fare":           payload.Fare,
+		"tenant_id":      c.t.Id().String(),
+	})
+
+	if payload.Fare == 0 {
+		fl.Debug("ScheduleWarp operation had no fare, nothing to refund")
+	} else {
+		fl.Info("Compensating failed ScheduleWarp operation by refunding the fare")
+
+		err := c.charP.AwardMesosAndEmit(s.TransactionId, payload.WorldId, payload.CharacterId, payload.ChannelId, 0, "SYSTEM", int32(payload.Fare))
+		if err != nil {
+			fl.WithError(err).Error("Failed to compensate ScheduleWarp operation")
+			return err
+		}
+	}
+
+	// Mark the failed step as compensated
+	failedStepIndex := s.FindFailedStepIndex()
+	if failedStepIndex != -1 {
+		if err := s.SetStepStatus(failedStepIndex, Pending); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"step_index":     failedStepIndex,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("Failed to mark ScheduleWarp step as compensated")
+			return err
+		}
+
+		// Validate state consistency before updating cache
+		if err := s.ValidateStateConsistency(); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("State consistency validation failed after ScheduleWarp compensation")
+			return err
+		}
+
+		GetCache().Put(c.t.Id(), s)
+	}
+
+	return nil
+}
+
+// compensateSpawnEscort handles compensation for a failed SpawnEscort operation by despawning the escort, should it have
+// been spawned
+func (c *CompensatorImpl) compensateSpawnEscort(s Saga, failedStep Step[any]) error {
+	// Extract the original payload
+	payload, ok := failedStep.Payload.(SpawnEscortPayload)
+	if !ok {
+		return fmt.Errorf("invalid payload for SpawnEscort compensation")
+	}
+
+	return c.despawnEscort(s, failedStep, payload.FieldId, payload.NpcId, payload.CharacterId)
+}
+
+// compensateAwaitEscort handles compensation for a failed AwaitEscort operation by despawning the escort, which would
+// otherwise remain following the character
+func (c *CompensatorImpl) compensateAwaitEscort(s Saga, failedStep Step[any]) error {
+	// Extract the original payload
+	payload, ok := failedStep.Payload.(AwaitEscortPayload)
+	if !ok {
+		return fmt.Errorf("invalid payload for AwaitEscort compensation")
+	}
+
+	return c.despawnEscort(s, failedStep, payload.FieldId, payload.NpcId, payload.CharacterId)
+}
+
+// despawnEscort despawns the escort spawned for the saga, unless the failed step was rejected, then marks the failed
+// step as compensated
+func (c *CompensatorImpl) despawnEscort(s Saga, failedStep Step[any], fieldId field.Id, npcId uint32, characterId uint32) error {
+	fl := c.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        failedStep.StepId,
+		"npc_id":         npcId,
+		"character_id":   characterId,
+		"tenant_id":      c.t.Id().String(),
+	})
+
+	if failedStep.Action == SpawnEscort && failedStep.ReportedError() {
+		fl.Debug("SpawnEscort operation was rejected, nothing to despawn")
+	} else {
+		f, ok := field.FromId(fieldId)
+		if !ok {
+			return fmt.Errorf("invalid field id '%s' for %s compensation", fieldId, failedStep.Action)
+		}
+
+		fl.Info("Compensating failed escort by despawning the escort")
+
+		err := c.npcP.DespawnEscortAndEmit(s.TransactionId, f, npcId, characterId)
+		if err != nil {
+			fl.WithError(err).Error("Failed to despawn escort")
+			return err
+		}
+	}
+
+	// Mark the failed step as compensated
+	failedStepIndex := s.FindFailedStepIndex()
+	if failedStepIndex != -1 {
+		if err := s.SetStepStatus(failedStepIndex, Pending); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"step_index":     failedStepIndex,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("Failed to mark escort step as compensated")
+			return err
+		}
+
+		// Validate state consistency after compensation
+		if err := s.ValidateStateConsistency(); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("State consistency validation failed after escort compensation")
+			return err
+		}
+
+		GetCache().Put(c.t.Id(), s)
+	}
+
+	return nil
+}
+
+// compensateUpdateCharacterAlignment handles compensation for a failed UpdateCharacterAlignment operation by reversing the
+// change of faction points
+func (c *CompensatorImpl) compensateUpdateCharacterAlignment(s Saga, failedStep Step[any]) error {
+	// Extract the original payload
+	payload, ok := failedStep.Payload.(UpdateCharacterAlignmentPayload)
+	if !ok {
+		return fmt.Errorf("invalid payload for UpdateCharacterAlignment compensation")
+	}
+
+	fl := c.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        failedStep.StepId,
+		"character_id":   payload.CharacterId,
+		"faction_id":     payload.FactionId,
+		"tenant_id":      c.t.Id().String(),
+	})
+
+	// A rejected change never applied, and reversing it would shift the alignment the character already held
+	if failedStep.ReportedError() {
+		fl.Debug("UpdateCharacterAlignment operation was rejected, no faction points to reverse")
+	} else {
+		fl.Info("Compensating failed UpdateCharacterAlignment operation by reversing the change of faction points")
+
+		err := c.factP.ChangePointsAndEmit(s.TransactionId, payload.WorldId, payload.CharacterId, payload.FactionId, -payload.Amount)
+		if err != nil {
+			fl.WithError(err).Error("Failed to compensate UpdateCharacterAlignment operation")
+			return err
+		}
+	}
+
+	// Mark the failed step as compensated
+	failedStepIndex := s.FindFailedStepIndex()
+	if failedStepIndex != -1 {
+		if err := s.SetStepStatus(failedStepIndex, Pending); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"step_index":     failedStepIndex,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("Failed to mark UpdateCharacterAlignment step as compensated")
+			return err
+		}
+
+		// Validate state consistency after compensation
+		if err := s.ValidateStateConsistency(); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("State consistency validation failed after UpdateCharacterAlignment compensation")
+			return err
+		}
+
+		GetCache().Put(c.t.Id(), s)
+	}
+
+	return nil
+}
+
+// compensateResetInstanceCooldown handles compensation for a failed ResetInstanceCooldown operation by re-applying the
+// cooldown the character had before it was reset
+func (c *CompensatorImpl) compensateResetInstanceCooldown(s Saga, failedStep Step[any]) error {
+	// Extract the original payload
+	payload, ok := failedStep.Payload.(ResetInstanceCooldownPayload)
+	if !ok {
+		return fmt.Errorf("invalid payload for ResetInstanceCooldown compensation")
+	}
+
+	fl := c.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        failedStep.StepId,
+		"character_id":   payload.CharacterId,
+		"instance_id":    payload.InstanceId,
+		"tenant_id":      c.t.Id().String(),
+	})
+
+	// A rejected reset never took effect, and a character without a cooldown had none to re-apply
+	if failedStep.ReportedError() || payload.Previous == nil {
+		fl.Debug("ResetInstanceCooldown operation did not clear a cooldown, nothing to re-apply")
+	} else {
+		fl.Info("Compensating failed ResetInstanceCooldown operation by re-applying the prior cooldown")
+
+		err := c.instP.SetCooldownAndEmit(s.TransactionId, payload.WorldId, payload.CharacterId, payload.InstanceId, *payload.Previous)
+		if err != nil {
+			fl.WithError(err).Error("Failed to compensate ResetInstanceCooldown operation")
+			return err
+		}
+	}
+
+	// Mark the failed step as compensated
+	failedStepIndex := s.FindFailedStepIndex()
+	if failedStepIndex != -1 {
+		if err := s.SetStepStatus(failedStepIndex, Pending); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"step_index":     failedStepIndex,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("Failed to mark ResetInstanceCooldown step as compensated")
+			return err
+		}
+
+		// Validate state consistency after compensation
+		if err := s.ValidateStateConsistency(); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("State consistency validation failed after ResetInstanceCooldown compensation")
+			return err
+		}
+
+		GetCache().Put(c.t.Id(), s)
+	}
+
+	return nil
+}
+
+// compensateApplyTitleBuffOnLogin handles compensation for a failed ApplyTitleBuffOnLogin operation by cancelling the
+// deferred effect, so it is not applied should the character later log in
+func (c *CompensatorImpl) compensateApplyTitleBuffOnLogin(s Saga, failedStep Step[any]) error {
+	// Extract the original payload
+	payload, ok := failedStep.Payload.(ApplyTitleBuffOnLoginPayload)
+	if !ok {
+		return fmt.Errorf("invalid payload for ApplyTitleBuffOnLogin compensation")
+	}
+
+	fl := c.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        failedStep.StepId,
+		"character_id":   payload.CharacterId,
+		"title_id":       payload.TitleId,
+		"tenant_id":      c.t.Id().String(),
+	})
+
+	// A rejected or expired effect is not pending, and an effect never identified was never registered
+	if failedStep.ReportedError() || payload.EffectId == nil {
+		fl.Debug("ApplyTitleBuffOnLogin operation did not leave an effect pending, nothing to cancel")
+	} else {
+		fl.Info("Compensating failed ApplyTitleBuffOnLogin operation by cancelling the deferred effect")
+
+		err := c.sessP.CancelDeferredEffectAndEmit(s.TransactionId, payload.WorldId, payload.CharacterId, *payload.EffectId)
+		if err != nil {
+			fl.WithError(err).Error("Failed to compensate ApplyTitleBuffOnLogin operation")
+			return err
+		}
+	}
+
+	// Mark the failed step as compensated
+	failedStepIndex := s.FindFailedStepIndex()
+	if failedStepIndex != -1 {
+		if err := s.SetStepStatus(failedStepIndex, Pending); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"step_index":     failedStepIndex,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("Failed to mark ApplyTitleBuffOnLogin step as compensated")
+			return err
+		}
+
+		// Validate state consistency after compensation
+		if err := s.ValidateStateConsistency(); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("State consistency validation failed after ApplyTitleBuffOnLogin compensation")
+			return err
+		}
+
+		GetCache().Put(c.t.Id(), s)
+	}
+
+	return nil
+}
+
+// compensateApplyWorldEventBuff handles compensation for a failed ApplyWorldEventBuff operation by removing the buff,
+// should it have been applied
+func (c *CompensatorImpl) compensateApplyWorldEventBuff(s Saga, failedStep Step[any]) error {
+	// Extract the original payload
+	payload, ok := failedStep.Payload.(ApplyWorldEventBuffPayload)
+	if !ok {
+		return fmt.Errorf("invalid payload for ApplyWorldEventBuff compensation")
+	}
+
+	fl := c.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        failedStep.StepId,
+		"buff_id":        payload.BuffId,
+		"tenant_id":      c.t.Id().String(),
+	})
+
+	if failedStep.ReportedError() {
+		fl.Debug("World event buff was not applied, nothing to remove")
+	} else {
+		fl.Info("Compensating failed ApplyWorldEventBuff operation by removing the buff")
+
+		// Perform the reverse operation: remove the buff
+		err := c.worldP.RemoveEventBuffAndEmit(s.TransactionId, payload.WorldId, payload.BuffId)
+		if err != nil {
+			fl.WithError(err).Error("Failed to compensate ApplyWorldEventBuff operation")
+			return err
+		}
+	}
+
+	// Mark the failed step as compensated
+	failedStepIndex := s.FindFailedStepIndex()
+	if failedStepIndex != -1 {
+		if err := s.SetStepStatus(failedStepIndex, Pending); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"step_index":     failedStepIndex,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("Failed to mark ApplyWorldEventBuff step as compensated")
+			return err
+		}
+
+		// Validate state consistency before updating cache
+		if err := s.ValidateStateConsistency(); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("State consistency validation failed after ApplyWorldEventBuff compensation")
+			return err
+		}
+
+		GetCache().Put(c.t.Id(), s)
+	}
+
+	return nil
+}
+
+// compensateCharacterExperienceLock handles compensation for a failed CharacterExperienceLock operation
+// by unlocking the character's experience again
+func (c *CompensatorImpl) compensateCharacterExperienceLock(s Saga, failedStep Step[any]) error {
+	// Extract the original payload
+	payload, ok := failedStep.Payload.(CharacterExperienceLockPayload)
+	if !ok {
+		return fmt.Errorf("invalid payload for CharacterExperienceLock compensation")
+	}
+
+	fl := c.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        failedStep.StepId,
+		"character_id":   payload.CharacterId,
+		"tenant_id":      c.t.Id().String(),
+	})
+
+	// A rejected lock never took effect
+	if failedStep.ReportedError() {
+		fl.Debug("CharacterExperienceLock operation did not take effect, nothing to reverse")
+	} else {
+		fl.Info("Compensating failed CharacterExperienceLock operation by unlocking experience gain")
+
+		err := c.charP.UnlockExperienceAndEmit(s.TransactionId, payload.WorldId, payload.CharacterId, payload.ChannelId)
+		if err != nil {
+			fl.WithError(err).Error("Failed to compensate CharacterExperienceLock operation")
+			return err
+		}
+	}
+
+	// Mark the failed step as compensated
+	failedStepIndex := s.FindFailedStepIndex()
+	if failedStepIndex != -1 {
+		if err := s.SetStepStatus(failedStepIndex, Pending); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"step_index":     failedStepIndex,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("Failed to mark CharacterExperienceLock step as compensated")
+			return err
+		}
+
+		// Validate state consistency before updating cache
+		if err := s.ValidateStateConsistency(); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("State consistency validation failed after CharacterExperienceLock compensation")
+			return err
+		}
+
+		GetCache().Put(c.t.Id(), s)
+	}
+
+	return nil
+}
+
+// compensateCharacterExperienceUnlock handles compensation for a failed CharacterExperienceUnlock operation
+// by locking the character's experience again
+func (c *CompensatorImpl) compensateCharacterExperienceUnlock(s Saga, failedStep Step[any]) error {
+	// Extract the original payload
+	payload, ok := failedStep.Payload.(CharacterExperienceUnlockPayload)
+	if !ok {
+		return fmt.Errorf("invalid payload for CharacterExperienceUnlock compensation")
+	}
+
+	fl := c.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        failedStep.StepId,
+		"character_id":   payload.CharacterId,
+		"tenant_id":      c.t.Id().String(),
+	})
+
+	// A rejected unlock never took effect
+	if failedStep.ReportedError() {
+		fl.Debug("CharacterExperienceUnlock operation did not take effect, nothing to reverse")
+	} else {
+		fl.Info("Compensating failed CharacterExperienceUnlock operation by locking experience gain")
+
+		err := c.charP.LockExperienceAndEmit(s.TransactionId, payload.WorldId, payload.CharacterId, payload.ChannelId, "")
+		if err != nil {
+			fl.WithError(err).Error("Failed to compensate CharacterExperienceUnlock operation")
+			return err
+		}
+	}
+
+	// Mark the failed step as compensated
+	failedStepIndex := s.FindFailedStepIndex()
+	if failedStepIndex != -1 {
+		if err := s.SetStepStatus(failedStepIndex, Pending); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"step_index":     failedStepIndex,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("Failed to mark CharacterExperienceUnlock step as compensated")
+			return err
+		}
+
+		// Validate state consistency before updating cache
+		if err := s.ValidateStateConsistency(); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("State consistency validation failed after CharacterExperienceUnlock compensation")
+			return err
+		}
+
+		GetCache().Put(c.t.Id(), s)
+	}
+
+	return nil
+}
+
+// compensateEquipAssetByTemplate handles compensation for a failed EquipAssetByTemplate operation
+// by unequipping the asset back to the inventory slot it was found in
+func (c *CompensatorImpl) compensateEquipAssetByTemplate(s Saga, failedStep Step[any]) error {
+	// Extract the original payload
+	payload, ok := failedStep.Payload.(EquipAssetByTemplatePayload)
+	if !ok {
+		return fmt.Errorf("invalid payload for EquipAssetByTemplate compensation")
+	}
+
+	fl := c.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        failedStep.StepId,
+		"character_id":   payload.CharacterId,
+		"template_id":    payload.TemplateId,
+		"source":         payload.Source,
+		"destination":    payload.Destination,
+		"tenant_id":      c.t.Id().String(),
+	})
+
+	// An equip which was rejected, or never requested as no slot held the asset, has nothing to unequip
+	if failedStep.ReportedError() || payload.Source == 0 {
+		fl.Debug("No equipped asset to unequip")
+	} else {
+		fl.Info("Compensating failed EquipAssetByTemplate operation with UnequipAsset")
+
+		// Perform the reverse operation: unequip from destination back to source
+		err := c.compP.RequestUnequipAsset(s.TransactionId, payload.CharacterId, byte(inventory.TypeValueEquip), payload.Destination, payload.Source)
+		if err != nil {
+			fl.WithError(err).Error("Failed to compensate EquipAssetByTemplate operation")
+			return err
+		}
+	}
+
+	// Mark the failed step as compensated
+	failedStepIndex := s.FindFailedStepIndex()
+	if failedStepIndex != -1 {
+		if err := s.SetStepStatus(failedStepIndex, Pending); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"step_index":     failedStepIndex,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("Failed to mark EquipAssetByTemplate step as compensated")
+			return err
+		}
+
+		// Validate state consistency before updating cache
+		if err := s.ValidateStateConsistency(); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("State consistency validation failed after EquipAssetByTemplate compensation")
+			return err
+		}
+
+		GetCache().Put(c.t.Id(), s)
+	}
+
+	return nil
+}
+
+// compensateGrantStorageCapacity handles compensation for a failed GrantStorageCapacity operation
+// by removing the added storage slots
+func (c *CompensatorImpl) compensateGrantStorageCapacity(s Saga, failedStep Step[any]) error {
+	// Extract the original payload
+	payload, ok := failedStep.Payload.(GrantStorageCapacityPayload)
+	if !ok {
+		return fmt.Errorf("invalid payload for GrantStorageCapacity compensation")
+	}
+
+	fl := c.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        failedStep.StepId,
+		"account_id":     payload.AccountId,
+		"world_id":       payload.WorldId,
+		"tenant_id":      c.t.Id().String(),
+	})
+
+	// A rejected change (e.g. the capacity limit was reached) never added slots, and removing slots on its behalf would
+	// take away capacity the account already owned
+	if failedStep.ReportedError() {
+		fl.Debug("GrantStorageCapacity operation was rejected, no storage slots to remove")
+	} else {
+		fl.Info("Compensating failed GrantStorageCapacity operation by removing the added storage slots")
+
+		err := c.storP.ChangeCapacityAndEmit(s.TransactionId, payload.WorldId, payload.AccountId, -int16(payload.Amount))
+		if err != nil {
+			fl.WithError(err).Error("Failed to compensate GrantStorageCapacity operation")
+			return err
+		}
+	}
+
+	// Mark the failed step as compensated
+	failedStepIndex := s.FindFailedStepIndex()
+	if failedStepIndex != -1 {
+		if err := s.SetStepStatus(failedStepIndex, Pending); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"step_index":     failedStepIndex,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("Failed to mark GrantStorageCapacity step as compensated")
+			return err
+		}
+
+		// Validate state consistency before updating cache
+		if err := s.ValidateStateConsistency(); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("State consistency validation failed after GrantStorageCapacity compensation")
+			return err
+		}
+
+		GetCache().Put(c.t.Id(), s)
+	}
+
+	return nil
+}
+
+// restoreCharacterSnapshots restores each character the saga took a snapshot of, and has not since restored, to its
+// latest snapshot. A failed restore_character step is not restored again, as its restore is what failed.
+func (c *CompensatorImpl) restoreCharacterSnapshots(s Saga, failedStep Step[any]) error {
+	for _, i := range pendingCharacterSnapshots(s, failedStep) {
+		snapshot := s.Steps[i]
+		payload := snapshot.Payload.(SnapshotCharacterPayload)
+		fl := c.l.WithFields(logrus.Fields{
+			"transaction_id":   s.TransactionId.String(),
+			"saga_type":        s.SagaType,
+			"step_id":          failedStep.StepId,
+			"snapshot_step_id": snapshot.StepId,
+			"character_id":     payload.CharacterId,
+			"tenant_id":        c.t.Id().String(),
+		})
+		fl.Info("Compensating failed step by restoring character to snapshot.")
+
+		if err := c.charP.RestoreStatsAndEmit(s.TransactionId, payload.WorldId, payload.CharacterId, payload.ChannelId, payload.Snapshot.Model(payload.CharacterId)); err != nil {
+			fl.WithError(err).Error("Failed to restore character to snapshot.")
+			return err
+		}
+	}
+	return nil
+}
+
+// compensatePetEquip handles compensation for a failed PetEquip operation
+// by unequipping the pet equipment back to the inventory slot it was found in
+func (c *CompensatorImpl) compensatePetEquip(s Saga, failedStep Step[any]) error {
+	// Extract the original payload
+	payload, ok := failedStep.Payload.(PetEquipPayload)
+	if !ok {
+		return fmt.Errorf("invalid payload for PetEquip compensation")
+	}
+
+	fl := c.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        failedStep.StepId,
+		"character_id":   payload.CharacterId,
+		"pet_id":         payload.PetId,
+		"template_id":    payload.TemplateId,
+		"source":         payload.Source,
+		"destination":    payload.Destination,
+		"tenant_id":      c.t.Id().String(),
+	})
+
+	// An equip which was rejected, or never requested as the pet or equipment was not found, has nothing to unequip
+	if failedStep.ReportedError() || payload.Source == 0 {
+		fl.Debug("No equipped pet equipment to unequip")
+	} else {
+		fl.Info("Compensating failed PetEquip operation with UnequipAsset")
+
+		// Perform the reverse operation: unequip from destination back to source
+		err := c.compP.RequestUnequipAsset(s.TransactionId, payload.CharacterId, byte(inventory.TypeValueEquip), payload.Destination, payload.Source)
+		if err != nil {
+			fl.WithError(err).Error("Failed to compensate PetEquip operation")
+			return err
+		}
+	}
+
+	// Mark the failed step as compensated
+	failedStepIndex := s.FindFailedStepIndex()
+	if failedStepIndex != -1 {
+		if err := s.SetStepStatus(failedStepIndex, Pending); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"step_index":     failedStepIndex,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("Failed to mark PetEquip step as compensated")
+			return err
+		}
+
+		// Validate state consistency before updating cache
+		if err := s.ValidateStateConsistency(); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("State consistency validation failed after PetEquip compensation")
+			return err
+		}
+
+		GetCache().Put(c.t.Id(), s)
+	}
+
+	return nil
+}
+
+// compensatePetUnequip handles compensation for a failed PetUnequip operation
+// by equipping the pet equipment back to the slot it was unequipped from
+func (c *CompensatorImpl) compensatePetUnequip(s Saga, failedStep Step[any]) error {
+	// Extract the original payload
+	payload, ok := failedStep.Payload.(PetUnequipPayload)
+	if !ok {
+		return fmt.Errorf("invalid payload for PetUnequip compensation")
+	}
+
+	fl := c.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        failedStep.StepId,
+		"character_id":   payload.CharacterId,
+		"pet_id":         payload.PetId,
+		"source":         payload.Source,
+		"destination":    payload.Destination,
+		"tenant_id":      c.t.Id().String(),
+	})
+
+	// An unequip which was rejected, or never requested as no pet equipment was found, has nothing to equip
+	if failedStep.ReportedError() || payload.TemplateId == 0 {
+		fl.Debug("No unequipped pet equipment to equip")
+	} else {
+		fl.Info("Compensating failed PetUnequip operation with EquipAsset")
+
+		// Perform the reverse operation: equip from destination back to source
+		err := c.compP.RequestEquipAsset(s.TransactionId, payload.CharacterId, byte(inventory.TypeValueEquip), payload.Destination, payload.Source)
+		if err != nil {
+			fl.WithError(err).Error("Failed to compensate PetUnequip operation")
+			return err
+		}
+	}
+
+	// Mark the failed step as compensated
+	failedStepIndex := s.FindFailedStepIndex()
+	if failedStepIndex != -1 {
+		if err := s.SetStepStatus(failedStepIndex, Pending); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"step_index":     failedStepIndex,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("Failed to mark PetUnequip step as compensated")
+			return err
+		}
+
+		// Validate state consistency before updating cache
+		if err := s.ValidateStateConsistency(); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("State consistency validation failed after PetUnequip compensation")
+			return err
+		}
+
+		GetCache().Put(c.t.Id(), s)
+	}
+
+	return nil
+}
+
+// stopQuestTimers stops the countdowns started by the completed SetQuestTimer steps of a saga
+func (c *CompensatorImpl) stopQuestTimers(s Saga) {
+	for _, st := range s.Steps {
+		if st.Action != SetQuestTimer || st.Status != Completed {
+			continue
+		}
+		if GetTimerRegistry().Stop(c.t.Id(), s.TransactionId, st.StepId) {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"step_id":        st.StepId,
+				"tenant_id":      c.t.Id().String(),
+			}).Info("Stopped quest timer of compensated saga.")
+		}
+	}
+}
+
+// compensateSetQuestTimer handles compensation for a failed SetQuestTimer operation
+// by stopping the countdown, should it have been started
+func (c *CompensatorImpl) compensateSetQuestTimer(s Saga, failedStep Step[any]) error {
+	fl := c.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        failedStep.StepId,
+		"tenant_id":      c.t.Id().String(),
+	})
+
+	if GetTimerRegistry().Stop(c.t.Id(), s.TransactionId, failedStep.StepId) {
+		fl.Info("Compensating failed SetQuestTimer operation by stopping the countdown")
+	} else {
+		fl.Debug("No quest timer countdown to stop")
+	}
+
+	// Mark the failed step as compensated
+	failedStepIndex := s.FindFailedStepIndex()
+	if failedStepIndex != -1 {
+		if err := s.SetStepStatus(failedStepIndex, Pending); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"step_index":     failedStepIndex,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("Failed to mark SetQuestTimer step as compensated")
+			return err
+		}
+
+		// Validate state consistency before updating cache
+		if err := s.ValidateStateConsistency(); err != nil {
+			c.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"tenant_id":      c.t.Id().String(),
+			}).WithError(err).Error("State consistency validation failed after SetQuestTimer compensation")
 			return err
 		}
 
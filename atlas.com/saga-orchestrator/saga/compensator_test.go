@@ -1,14 +1,35 @@
 package saga
 
 import (
+	mock6 "atlas-saga-orchestrator/account/mock"
+	"atlas-saga-orchestrator/buff/mock"
+	"atlas-saga-orchestrator/character"
+	mock3 "atlas-saga-orchestrator/character/mock"
+	mock2 "atlas-saga-orchestrator/compartment/mock"
+	mock5 "atlas-saga-orchestrator/coupon/mock"
+	mock9 "atlas-saga-orchestrator/faction/mock"
+	mock10 "atlas-saga-orchestrator/instance/mock"
+	buff2 "atlas-saga-orchestrator/kafka/message/buff"
+	character2 "atlas-saga-orchestrator/kafka/message/character"
+	mock8 "atlas-saga-orchestrator/marriage/mock"
+	mock7 "atlas-saga-orchestrator/reactor/mock"
+	mock11 "atlas-saga-orchestrator/session/mock"
+	mock12 "atlas-saga-orchestrator/storage/mock"
+	mock4 "atlas-saga-orchestrator/worldstate/mock"
 	"context"
+	"encoding/json"
+	"errors"
+	"github.com/Chronicle20/atlas-constants/channel"
+	"github.com/Chronicle20/atlas-constants/field"
 	"github.com/Chronicle20/atlas-constants/job"
 	_map "github.com/Chronicle20/atlas-constants/map"
+	"github.com/Chronicle20/atlas-constants/world"
 	tenant "github.com/Chronicle20/atlas-tenant"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"testing"
 	"time"
 )
@@ -105,3 +126,2603 @@ func TestCompensateCreateCharacter(t *testing.T) {
 		})
 	}
 }
+
+// TestCompensateCreatedCharacter tests that a saga which created a character, failing a later step, is compensated by
+// deleting the character recorded from its CREATED event
+func TestCompensateCreatedCharacter(t *testing.T) {
+	te, ctx := setupContext()
+
+	var deleted []uint32
+	charP := &mock3.ProcessorMock{
+		AwardMesosAndEmitFunc: func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, actorId uint32, actorType string, amount int32) error {
+			return nil
+		},
+		RequestDeleteCharacterFunc: func(transactionId uuid.UUID, accountId uint32, worldId world.Id, characterId uint32) error {
+			assert.Equal(t, uint32(12345), accountId)
+			assert.Equal(t, world.Id(1), worldId)
+			deleted = append(deleted, characterId)
+			return nil
+		},
+	}
+	processor, _ := setupTestProcessor(ctx, charP, nil)
+
+	s := Saga{
+		TransactionId: uuid.New(),
+		SagaType:      CharacterCreation,
+		InitiatedBy:   "login",
+		Steps: []Step[any]{
+			{StepId: "create", Status: Pending, Action: CreateCharacter, Payload: CharacterCreatePayload{AccountId: 12345, WorldId: 1, Name: "Atlas"}, Capture: map[string]string{"characterId": "$.characterId"}},
+			{StepId: "award", Status: Pending, Action: AwardMesos, PayloadTemplate: json.RawMessage(`{"characterId": "$.variables.characterId", "actorType": "SYSTEM", "amount": 100}`)},
+		},
+	}
+	GetCache().Put(te.Id(), s)
+	defer GetCache().Remove(te.Id(), s.TransactionId)
+
+	e := character2.StatusEvent[character2.StatusEventCreatedBody]{TransactionId: s.TransactionId, WorldId: 1, CharacterId: 54321, Type: character2.StatusEventTypeCreated, Body: character2.StatusEventCreatedBody{Name: "Atlas"}}
+	require.NoError(t, processor.StepCompletedWithEvent(s.TransactionId, e))
+	rs, err := processor.GetById(s.TransactionId)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(54321), rs.Steps[0].Payload.(CharacterCreatePayload).CharacterId)
+	assert.Empty(t, deleted)
+
+	_ = processor.StepFailed(s.TransactionId, "UNKNOWN_ERROR", "character service error")
+	assert.Equal(t, []uint32{54321}, deleted)
+
+	t.Run("failed creations delete nothing", func(t *testing.T) {
+		deleted = nil
+		failed := Saga{
+			TransactionId: uuid.New(),
+			SagaType:      CharacterCreation,
+			Steps:         []Step[any]{{StepId: "create", Status: Failed, Action: CreateCharacter, Payload: CharacterCreatePayload{AccountId: 12345, WorldId: 1, Name: "Atlas"}}},
+		}
+		require.NoError(t, NewCompensator(logrus.New(), ctx).WithCharacterProcessor(charP).CompensateFailedStep(failed))
+		assert.Empty(t, deleted)
+	})
+
+	t.Run("receipt reverts the steps of the created character", func(t *testing.T) {
+		created := s
+		created.Steps = []Step[any]{
+			{StepId: "create", Status: Completed, Action: CreateCharacter, Payload: CharacterCreatePayload{AccountId: 12345, WorldId: 1, Name: "Atlas", CharacterId: 54321}},
+			{StepId: "mesos", Status: Completed, Action: AwardMesos, Payload: AwardMesosPayload{CharacterId: 54321, Amount: 100}},
+			{StepId: "exp", Status: Failed, Action: AwardExperience, Payload: AwardExperiencePayload{CharacterId: 54321}},
+		}
+		r, ok := NewCompensationReceipt(created, 1, nil)
+		require.True(t, ok)
+		assert.True(t, r.RolledBack)
+		require.Len(t, r.Steps, 3)
+		for _, e := range r.Steps {
+			assert.Equal(t, ReceiptReverted, e.Outcome, e.StepId)
+		}
+	})
+}
+
+// TestCompensateCharacterBuffCleanse tests the compensateCharacterBuffCleanse function
+func TestCompensateCharacterBuffCleanse(t *testing.T) {
+	captured := []CapturedBuff{
+		{SourceId: 2001002, Duration: 300, Changes: []BuffStatChange{{Type: "MAGIC_GUARD", Amount: 15}}, ExpiresAt: time.Now().Add(2 * time.Minute)},
+		{SourceId: 2001003, Duration: 300, Changes: []BuffStatChange{{Type: "MAGIC_DEFENSE", Amount: 20}}, ExpiresAt: time.Now().Add(-time.Minute)},
+	}
+
+	tests := []struct {
+		name          string
+		payload       any
+		mockError     error
+		expectApplied []int32
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name:          "Success case - unexpired buffs re-applied",
+			payload:       CharacterBuffCleansePayload{CharacterId: 12345, ChannelId: 1, Captured: captured},
+			expectApplied: []int32{2001002},
+		},
+		{
+			name:    "Success case - nothing captured",
+			payload: CharacterBuffCleansePayload{CharacterId: 12345, ChannelId: 1},
+		},
+		{
+			name:          "Error case - re-application fails",
+			payload:       CharacterBuffCleansePayload{CharacterId: 12345, ChannelId: 1, Captured: captured},
+			mockError:     errors.New("kafka unavailable"),
+			expectApplied: []int32{2001002},
+			expectError:   true,
+			errorContains: "kafka unavailable",
+		},
+		{
+			name:          "Error case - invalid payload type",
+			payload:       "invalid-payload",
+			expectError:   true,
+			errorContains: "invalid payload for CharacterBuffCleanse compensation",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			ctx := context.Background()
+			te, _ := tenant.Create(uuid.New(), "GMS", 83, 1)
+			tctx := tenant.WithContext(ctx, te)
+
+			applied := make([]int32, 0)
+			buffP := &mock.ProcessorMock{}
+			buffP.ApplyAndEmitFunc = func(transactionId uuid.UUID, worldId world.Id, channelId channel.Id, characterId uint32, fromId uint32, sourceId int32, duration int32, changes []buff2.StatChange) error {
+				applied = append(applied, sourceId)
+				assert.Equal(t, uint32(12345), characterId)
+				assert.LessOrEqual(t, duration, int32(120))
+				assert.Greater(t, duration, int32(0))
+				assert.Equal(t, []buff2.StatChange{{Type: "MAGIC_GUARD", Amount: 15}}, changes)
+				return tt.mockError
+			}
+
+			// Create test saga with failed step
+			saga := Saga{
+				TransactionId: uuid.New(),
+				SagaType:      InventoryTransaction,
+				InitiatedBy:   "compensation-test",
+				Steps: []Step[any]{
+					{
+						StepId:    "cleanse-step",
+						Status:    Failed,
+						Action:    CharacterBuffCleanse,
+						Payload:   tt.payload,
+						CreatedAt: time.Now(),
+						UpdatedAt: time.Now(),
+					},
+				},
+			}
+
+			// Execute
+			err := NewCompensator(logger, tctx).WithBuffProcessor(buffP).compensateCharacterBuffCleanse(saga, saga.Steps[0])
+
+			// Verify
+			if tt.expectError {
+				assert.Error(t, err)
+				if tt.errorContains != "" {
+					assert.Contains(t, err.Error(), tt.errorContains)
+				}
+			} else {
+				assert.NoError(t, err)
+				// Verify that the step status was reset to pending
+				assert.Equal(t, Pending, saga.Steps[0].Status)
+			}
+			if tt.expectApplied != nil {
+				assert.Equal(t, tt.expectApplied, applied)
+			}
+		})
+	}
+}
+
+// TestCompensateModifyInventoryItemPosition tests the compensateModifyInventoryItemPosition function
+func TestCompensateModifyInventoryItemPosition(t *testing.T) {
+	tests := []struct {
+		name          string
+		payload       any
+		mockError     error
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name:    "Success case - asset moved back",
+			payload: ModifyInventoryItemPositionPayload{CharacterId: 12345, InventoryType: 2, Source: 3, Destination: 1},
+		},
+		{
+			name:          "Error case - move back fails",
+			payload:       ModifyInventoryItemPositionPayload{CharacterId: 12345, InventoryType: 2, Source: 3, Destination: 1},
+			mockError:     errors.New("compartment service error"),
+			expectError:   true,
+			errorContains: "compartment service error",
+		},
+		{
+			name:          "Error case - invalid payload type",
+			payload:       "invalid-payload",
+			expectError:   true,
+			errorContains: "invalid payload for ModifyInventoryItemPosition compensation",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			ctx := context.Background()
+			te, _ := tenant.Create(uuid.New(), "GMS", 83, 1)
+			tctx := tenant.WithContext(ctx, te)
+
+			compP := &mock2.ProcessorMock{
+				RequestMoveAssetFunc: func(transactionId uuid.UUID, characterId uint32, inventoryType byte, source int16, destination int16) error {
+					// The reverse move swaps source and destination
+					assert.Equal(t, int16(1), source)
+					assert.Equal(t, int16(3), destination)
+					return tt.mockError
+				},
+			}
+
+			// Create test saga with failed step
+			saga := Saga{
+				TransactionId: uuid.New(),
+				SagaType:      InventoryTransaction,
+				InitiatedBy:   "compensation-test",
+				Steps: []Step[any]{
+					{
+						StepId:    "move-step",
+						Status:    Failed,
+						Action:    ModifyInventoryItemPosition,
+						Payload:   tt.payload,
+						CreatedAt: time.Now(),
+						UpdatedAt: time.Now(),
+					},
+				},
+			}
+
+			// Execute
+			err := NewCompensator(logger, tctx).WithCompartmentProcessor(compP).compensateModifyInventoryItemPosition(saga, saga.Steps[0])
+
+			// Verify
+			if tt.expectError {
+				assert.Error(t, err)
+				if tt.errorContains != "" {
+					assert.Contains(t, err.Error(), tt.errorContains)
+				}
+			} else {
+				assert.NoError(t, err)
+				// Verify that the step status was reset to pending
+				assert.Equal(t, Pending, saga.Steps[0].Status)
+			}
+		})
+	}
+}
+
+// TestCompensateTransferCharacter tests the compensateTransferCharacter function
+func TestCompensateTransferCharacter(t *testing.T) {
+	tests := []struct {
+		name          string
+		payload       any
+		mockError     error
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name:    "Success case - character returned to source account",
+			payload: TransferCharacterPayload{CharacterId: 12345, WorldId: 1, SourceAccountId: 100, TargetAccountId: 200},
+		},
+		{
+			name:          "Error case - change account fails",
+			payload:       TransferCharacterPayload{CharacterId: 12345, WorldId: 1, SourceAccountId: 100, TargetAccountId: 200},
+			mockError:     errors.New("character service error"),
+			expectError:   true,
+			errorContains: "character service error",
+		},
+		{
+			name:          "Error case - invalid payload type",
+			payload:       "invalid-payload",
+			expectError:   true,
+			errorContains: "invalid payload for TransferCharacter compensation",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			ctx := context.Background()
+			te, _ := tenant.Create(uuid.New(), "GMS", 83, 1)
+			tctx := tenant.WithContext(ctx, te)
+
+			charP := &mock3.ProcessorMock{
+				ChangeAccountAndEmitFunc: func(transactionId uuid.UUID, worldId world.Id, characterId uint32, accountId uint32) error {
+					assert.Equal(t, uint32(12345), characterId)
+					assert.Equal(t, uint32(100), accountId)
+					return tt.mockError
+				},
+			}
+
+			// Create test saga with failed step
+			saga := Saga{
+				TransactionId: uuid.New(),
+				SagaType:      AccountMerge,
+				InitiatedBy:   "compensation-test",
+				Steps: []Step[any]{
+					{
+						StepId:    "transfer-step",
+						Status:    Failed,
+						Action:    TransferCharacter,
+						Payload:   tt.payload,
+						CreatedAt: time.Now(),
+						UpdatedAt: time.Now(),
+					},
+				},
+			}
+
+			// Execute
+			err := NewCompensator(logger, tctx).WithCharacterProcessor(charP).compensateTransferCharacter(saga, saga.Steps[0])
+
+			// Verify
+			if tt.expectError {
+				assert.Error(t, err)
+				if tt.errorContains != "" {
+					assert.Contains(t, err.Error(), tt.errorContains)
+				}
+			} else {
+				assert.NoError(t, err)
+				// Verify that the step status was reset to pending
+				assert.Equal(t, Pending, saga.Steps[0].Status)
+			}
+		})
+	}
+}
+
+// TestCompensateAdjustNpcShopStock tests the compensateAdjustNpcShopStock function
+func TestCompensateAdjustNpcShopStock(t *testing.T) {
+	tests := []struct {
+		name          string
+		payload       any
+		attempts      []StepAttempt
+		mockError     error
+		expectReverse bool
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name:          "Success case - purchased stock returned",
+			payload:       AdjustNpcShopStockPayload{WorldId: 1, NpcId: 9201000, TemplateId: 2000000, Amount: -1},
+			attempts:      []StepAttempt{{Attempt: 1}},
+			expectReverse: true,
+		},
+		{
+			name:     "Success case - rejected adjustment is not reversed",
+			payload:  AdjustNpcShopStockPayload{WorldId: 1, NpcId: 9201000, TemplateId: 2000000, Amount: -1},
+			attempts: []StepAttempt{{Attempt: 1, ErrorCode: "OUT_OF_STOCK"}},
+		},
+		{
+			name:          "Error case - adjustment fails",
+			payload:       AdjustNpcShopStockPayload{WorldId: 1, NpcId: 9201000, TemplateId: 2000000, Amount: -1},
+			mockError:     errors.New("world state service error"),
+			expectReverse: true,
+			expectError:   true,
+			errorContains: "world state service error",
+		},
+		{
+			name:          "Error case - invalid payload type",
+			payload:       "invalid-payload",
+			expectError:   true,
+			errorContains: "invalid payload for AdjustNpcShopStock compensation",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			ctx := context.Background()
+			te, _ := tenant.Create(uuid.New(), "GMS", 83, 1)
+			tctx := tenant.WithContext(ctx, te)
+
+			reversed := false
+			worldP := &mock4.ProcessorMock{
+				AdjustShopStockAndEmitFunc: func(transactionId uuid.UUID, worldId world.Id, npcId uint32, templateId uint32, amount int32) error {
+					reversed = true
+					assert.Equal(t, uint32(9201000), npcId)
+					assert.Equal(t, uint32(2000000), templateId)
+					assert.Equal(t, int32(1), amount)
+					return tt.mockError
+				},
+			}
+
+			// Create test saga with failed step
+			saga := Saga{
+				TransactionId: uuid.New(),
+				SagaType:      InventoryTransaction,
+				InitiatedBy:   "compensation-test",
+				Steps: []Step[any]{
+					{
+						StepId:    "stock-step",
+						Status:    Failed,
+						Action:    AdjustNpcShopStock,
+						Payload:   tt.payload,
+						Attempts:  tt.attempts,
+						CreatedAt: time.Now(),
+						UpdatedAt: time.Now(),
+					},
+				},
+			}
+
+			// Execute
+			err := NewCompensator(logger, tctx).WithWorldStateProcessor(worldP).compensateAdjustNpcShopStock(saga, saga.Steps[0])
+
+			// Verify
+			assert.Equal(t, tt.expectReverse, reversed)
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestCompensateSetWorldEventFlag tests the compensateSetWorldEventFlag function
+func TestCompensateSetWorldEventFlag(t *testing.T) {
+	previous := "off"
+	tests := []struct {
+		name          string
+		payload       SetWorldEventFlagPayload
+		expectRestore bool
+	}{
+		{
+			name:          "Success case - previous value restored",
+			payload:       SetWorldEventFlagPayload{WorldId: 1, Key: "halloween2025", Value: "on", Previous: &previous},
+			expectRestore: true,
+		},
+		{
+			name:    "Success case - no previous value given",
+			payload: SetWorldEventFlagPayload{WorldId: 1, Key: "halloween2025", Value: "on"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			ctx := context.Background()
+			te, _ := tenant.Create(uuid.New(), "GMS", 83, 1)
+			tctx := tenant.WithContext(ctx, te)
+
+			restored := false
+			worldP := &mock4.ProcessorMock{
+				SetEventFlagAndEmitFunc: func(transactionId uuid.UUID, worldId world.Id, key string, value string) error {
+					restored = true
+					assert.Equal(t, "halloween2025", key)
+					assert.Equal(t, previous, value)
+					return nil
+				},
+			}
+
+			saga := Saga{
+				TransactionId: uuid.New(),
+				SagaType:      InventoryTransaction,
+				InitiatedBy:   "compensation-test",
+				Steps: []Step[any]{
+					{
+						StepId:  "flag-step",
+						Status:  Failed,
+						Action:  SetWorldEventFlag,
+						Payload: tt.payload,
+					},
+				},
+			}
+
+			// Execute
+			err := NewCompensator(logger, tctx).WithWorldStateProcessor(worldP).compensateSetWorldEventFlag(saga, saga.Steps[0])
+
+			// Verify
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectRestore, restored)
+		})
+	}
+}
+
+// TestCompensateApplyWorldEventBuff tests the compensateApplyWorldEventBuff function
+func TestCompensateApplyWorldEventBuff(t *testing.T) {
+	tests := []struct {
+		name         string
+		attempts     []StepAttempt
+		expectRemove bool
+	}{
+		{
+			name:         "Success case - applied buff removed",
+			attempts:     []StepAttempt{{Attempt: 1}},
+			expectRemove: true,
+		},
+		{
+			name:     "Success case - rejected buff not removed",
+			attempts: []StepAttempt{{Attempt: 1, ErrorCode: "UNKNOWN_BUFF_TYPE"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			ctx := context.Background()
+			te, _ := tenant.Create(uuid.New(), "GMS", 83, 1)
+			tctx := tenant.WithContext(ctx, te)
+
+			removed := false
+			worldP := &mock4.ProcessorMock{
+				RemoveEventBuffAndEmitFunc: func(transactionId uuid.UUID, worldId world.Id, buffId string) error {
+					removed = true
+					assert.Equal(t, world.Id(1), worldId)
+					assert.Equal(t, "double_exp", buffId)
+					return nil
+				},
+			}
+
+			saga := Saga{
+				TransactionId: uuid.New(),
+				SagaType:      InventoryTransaction,
+				InitiatedBy:   "compensation-test",
+				Steps: []Step[any]{
+					{
+						StepId:   "buff-step",
+						Status:   Failed,
+						Action:   ApplyWorldEventBuff,
+						Payload:  ApplyWorldEventBuffPayload{WorldId: 1, BuffId: "double_exp", Type: "exp", Rate: 2, Duration: 3600},
+						Attempts: tt.attempts,
+					},
+				},
+			}
+
+			// Execute
+			err := NewCompensator(logger, tctx).WithWorldStateProcessor(worldP).compensateApplyWorldEventBuff(saga, saga.Steps[0])
+
+			// Verify
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectRemove, removed)
+		})
+	}
+}
+
+// TestCompensateConsumeCoupon tests the compensateConsumeCoupon function
+func TestCompensateConsumeCoupon(t *testing.T) {
+	tests := []struct {
+		name          string
+		payload       any
+		attempts      []StepAttempt
+		mockError     error
+		expectRelease bool
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name:          "Success case - consumed coupon released",
+			payload:       ConsumeCouponPayload{CharacterId: 12345, AccountId: 7, Code: "SUMMER-2026"},
+			attempts:      []StepAttempt{{Attempt: 1}},
+			expectRelease: true,
+		},
+		{
+			name:     "Success case - rejected consumption is not released",
+			payload:  ConsumeCouponPayload{CharacterId: 12345, AccountId: 7, Code: "SUMMER-2026"},
+			attempts: []StepAttempt{{Attempt: 1, ErrorCode: "ALREADY_CONSUMED"}},
+		},
+		{
+			name:          "Error case - release fails",
+			payload:       ConsumeCouponPayload{CharacterId: 12345, AccountId: 7, Code: "SUMMER-2026"},
+			mockError:     errors.New("coupon service error"),
+			expectRelease: true,
+			expectError:   true,
+			errorContains: "coupon service error",
+		},
+		{
+			name:          "Error case - invalid payload type",
+			payload:       "invalid-payload",
+			expectError:   true,
+			errorContains: "invalid payload for ConsumeCoupon compensation",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			ctx := context.Background()
+			te, _ := tenant.Create(uuid.New(), "GMS", 83, 1)
+			tctx := tenant.WithContext(ctx, te)
+
+			transactionId := uuid.New()
+			released := false
+			couponP := &mock5.ProcessorMock{
+				ReleaseAndEmitFunc: func(tId uuid.UUID, accountId uint32, characterId uint32, code string) error {
+					released = true
+					assert.Equal(t, transactionId, tId)
+					assert.Equal(t, uint32(7), accountId)
+					assert.Equal(t, "SUMMER-2026", code)
+					return tt.mockError
+				},
+			}
+
+			// Create test saga with failed step
+			saga := Saga{
+				TransactionId: transactionId,
+				SagaType:      CouponRedemption,
+				InitiatedBy:   "compensation-test",
+				Steps: []Step[any]{
+					{
+						StepId:    "consume-step",
+						Status:    Failed,
+						Action:    ConsumeCoupon,
+						Payload:   tt.payload,
+						Attempts:  tt.attempts,
+						CreatedAt: time.Now(),
+						UpdatedAt: time.Now(),
+					},
+				},
+			}
+
+			// Execute
+			err := NewCompensator(logger, tctx).WithCouponProcessor(couponP).compensateConsumeCoupon(saga, saga.Steps[0])
+
+			// Verify
+			assert.Equal(t, tt.expectRelease, released)
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestCompensateCreateAccountCharacterSlot tests the compensateCreateAccountCharacterSlot function
+func TestCompensateCreateAccountCharacterSlot(t *testing.T) {
+	tests := []struct {
+		name          string
+		payload       any
+		attempts      []StepAttempt
+		mockError     error
+		expectRemove  bool
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name:         "Success case - added slots removed",
+			payload:      CreateAccountCharacterSlotPayload{AccountId: 7, WorldId: 1, Amount: 2},
+			attempts:     []StepAttempt{{Attempt: 1}},
+			expectRemove: true,
+		},
+		{
+			name:     "Success case - rejected change is not reversed",
+			payload:  CreateAccountCharacterSlotPayload{AccountId: 7, WorldId: 1, Amount: 2},
+			attempts: []StepAttempt{{Attempt: 1, ErrorCode: "SLOT_LIMIT_REACHED"}},
+		},
+		{
+			name:          "Error case - removal fails",
+			payload:       CreateAccountCharacterSlotPayload{AccountId: 7, WorldId: 1, Amount: 2},
+			mockError:     errors.New("account service error"),
+			expectRemove:  true,
+			expectError:   true,
+			errorContains: "account service error",
+		},
+		{
+			name:          "Error case - invalid payload type",
+			payload:       "invalid-payload",
+			expectError:   true,
+			errorContains: "invalid payload for CreateAccountCharacterSlot compensation",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			ctx := context.Background()
+			te, _ := tenant.Create(uuid.New(), "GMS", 83, 1)
+			tctx := tenant.WithContext(ctx, te)
+
+			transactionId := uuid.New()
+			removed := false
+			acctP := &mock6.ProcessorMock{
+				ChangeCharacterSlotsAndEmitFunc: func(tId uuid.UUID, accountId uint32, worldId world.Id, amount int16) error {
+					removed = true
+					assert.Equal(t, transactionId, tId)
+					assert.Equal(t, uint32(7), accountId)
+					assert.Equal(t, world.Id(1), worldId)
+					assert.Equal(t, int16(-2), amount)
+					return tt.mockError
+				},
+			}
+
+			// Create test saga with failed step
+			saga := Saga{
+				TransactionId: transactionId,
+				SagaType:      CharacterSlotPurchase,
+				InitiatedBy:   "compensation-test",
+				Steps: []Step[any]{
+					{
+						StepId:    "slot-step",
+						Status:    Failed,
+						Action:    CreateAccountCharacterSlot,
+						Payload:   tt.payload,
+						Attempts:  tt.attempts,
+						CreatedAt: time.Now(),
+						UpdatedAt: time.Now(),
+					},
+				},
+			}
+
+			// Execute
+			err := NewCompensator(logger, tctx).WithAccountProcessor(acctP).compensateCreateAccountCharacterSlot(saga, saga.Steps[0])
+
+			// Verify
+			assert.Equal(t, tt.expectRemove, removed)
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestCompensateGrantStorageCapacity tests the compensateGrantStorageCapacity function
+func TestCompensateGrantStorageCapacity(t *testing.T) {
+	tests := []struct {
+		name          string
+		payload       any
+		attempts      []StepAttempt
+		mockError     error
+		expectRemove  bool
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name:         "Success case - added slots removed",
+			payload:      GrantStorageCapacityPayload{AccountId: 7, WorldId: 1, Amount: 4},
+			attempts:     []StepAttempt{{Attempt: 1}},
+			expectRemove: true,
+		},
+		{
+			name:     "Success case - rejected change is not reversed",
+			payload:  GrantStorageCapacityPayload{AccountId: 7, WorldId: 1, Amount: 4},
+			attempts: []StepAttempt{{Attempt: 1, ErrorCode: "CAPACITY_LIMIT_REACHED"}},
+		},
+		{
+			name:          "Error case - removal fails",
+			payload:       GrantStorageCapacityPayload{AccountId: 7, WorldId: 1, Amount: 4},
+			mockError:     errors.New("storage service error"),
+			expectRemove:  true,
+			expectError:   true,
+			errorContains: "storage service error",
+		},
+		{
+			name:          "Error case - invalid payload type",
+			payload:       "invalid-payload",
+			expectError:   true,
+			errorContains: "invalid payload for GrantStorageCapacity compensation",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			ctx := context.Background()
+			te, _ := tenant.Create(uuid.New(), "GMS", 83, 1)
+			tctx := tenant.WithContext(ctx, te)
+
+			transactionId := uuid.New()
+			removed := false
+			storP := &mock12.ProcessorMock{
+				ChangeCapacityAndEmitFunc: func(tId uuid.UUID, worldId world.Id, accountId uint32, amount int16) error {
+					removed = true
+					assert.Equal(t, transactionId, tId)
+					assert.Equal(t, world.Id(1), worldId)
+					assert.Equal(t, uint32(7), accountId)
+					assert.Equal(t, int16(-4), amount)
+					return tt.mockError
+				},
+			}
+
+			// Create test saga with failed step
+			saga := Saga{
+				TransactionId: transactionId,
+				SagaType:      InventoryTransaction,
+				InitiatedBy:   "compensation-test",
+				Steps: []Step[any]{
+					{
+						StepId:    "storage-step",
+						Status:    Failed,
+						Action:    GrantStorageCapacity,
+						Payload:   tt.payload,
+						Attempts:  tt.attempts,
+						CreatedAt: time.Now(),
+						UpdatedAt: time.Now(),
+					},
+				},
+			}
+
+			// Execute
+			err := NewCompensator(logger, tctx).WithStorageProcessor(storP).compensateGrantStorageCapacity(saga, saga.Steps[0])
+
+			// Verify
+			assert.Equal(t, tt.expectRemove, removed)
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestCompensateModifyAssetExpiration tests the compensateModifyAssetExpiration function
+func TestCompensateModifyAssetExpiration(t *testing.T) {
+	previous := time.Now().Add(24 * time.Hour)
+
+	tests := []struct {
+		name          string
+		payload       any
+		attempts      []StepAttempt
+		mockError     error
+		expectRestore bool
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name:          "Success case - prior expiration restored",
+			payload:       ModifyAssetExpirationPayload{CharacterId: 12345, InventoryType: 1, Slot: 3, ExtendBy: 3600, Previous: &previous},
+			attempts:      []StepAttempt{{Attempt: 1}},
+			expectRestore: true,
+		},
+		{
+			name:     "Success case - rejected change is not reversed",
+			payload:  ModifyAssetExpirationPayload{CharacterId: 12345, InventoryType: 1, Slot: 3, ExtendBy: 3600, Previous: &previous},
+			attempts: []StepAttempt{{Attempt: 1, ErrorCode: "ASSET_NOT_FOUND"}},
+		},
+		{
+			name:    "Success case - change never requested",
+			payload: ModifyAssetExpirationPayload{CharacterId: 12345, InventoryType: 1, Slot: 3, ExtendBy: 3600},
+		},
+		{
+			name:          "Error case - restore fails",
+			payload:       ModifyAssetExpirationPayload{CharacterId: 12345, InventoryType: 1, Slot: 3, ExtendBy: 3600, Previous: &previous},
+			mockError:     errors.New("compartment service error"),
+			expectRestore: true,
+			expectError:   true,
+			errorContains: "compartment service error",
+		},
+		{
+			name:          "Error case - invalid payload type",
+			payload:       "invalid-payload",
+			expectError:   true,
+			errorContains: "invalid payload for ModifyAssetExpiration compensation",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			ctx := context.Background()
+			te, _ := tenant.Create(uuid.New(), "GMS", 83, 1)
+			tctx := tenant.WithContext(ctx, te)
+
+			transactionId := uuid.New()
+			restored := false
+			compP := &mock2.ProcessorMock{
+				RequestChangeExpirationFunc: func(tId uuid.UUID, characterId uint32, inventoryType byte, slot int16, expiration time.Time) error {
+					restored = true
+					assert.Equal(t, transactionId, tId)
+					assert.Equal(t, uint32(12345), characterId)
+					assert.Equal(t, byte(1), inventoryType)
+					assert.Equal(t, int16(3), slot)
+					assert.True(t, previous.Equal(expiration))
+					return tt.mockError
+				},
+			}
+
+			// Create test saga with failed step
+			saga := Saga{
+				TransactionId: transactionId,
+				SagaType:      InventoryTransaction,
+				InitiatedBy:   "compensation-test",
+				Steps: []Step[any]{
+					{
+						StepId:    "expiration-step",
+						Status:    Failed,
+						Action:    ModifyAssetExpiration,
+						Payload:   tt.payload,
+						Attempts:  tt.attempts,
+						CreatedAt: time.Now(),
+						UpdatedAt: time.Now(),
+					},
+				},
+			}
+
+			// Execute
+			err := NewCompensator(logger, tctx).WithCompartmentProcessor(compP).compensateModifyAssetExpiration(saga, saga.Steps[0])
+
+			// Verify
+			assert.Equal(t, tt.expectRestore, restored)
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestCompensateApplyHammer tests the compensateApplyHammer function
+func TestCompensateApplyHammer(t *testing.T) {
+	snapshot := &UpgradeSlotsPayload{Slots: 3, HammersApplied: 1}
+
+	tests := []struct {
+		name          string
+		payload       any
+		attempts      []StepAttempt
+		mockError     error
+		expectRestore bool
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name:          "Success case - upgrade slots restored",
+			payload:       ApplyHammerPayload{CharacterId: 12345, Slot: 1, Slots: 1, Snapshot: snapshot},
+			attempts:      []StepAttempt{{Attempt: 1}},
+			expectRestore: true,
+		},
+		{
+			name:     "Success case - rejected hammer is not reversed",
+			payload:  ApplyHammerPayload{CharacterId: 12345, Slot: 1, Slots: 1, Snapshot: snapshot},
+			attempts: []StepAttempt{{Attempt: 1, ErrorCode: "ASSET_NOT_FOUND"}},
+		},
+		{
+			name:    "Success case - hammer never requested",
+			payload: ApplyHammerPayload{CharacterId: 12345, Slot: 1, Slots: 1},
+		},
+		{
+			name:          "Error case - restore fails",
+			payload:       ApplyHammerPayload{CharacterId: 12345, Slot: 1, Slots: 1, Snapshot: snapshot},
+			mockError:     errors.New("compartment service error"),
+			expectRestore: true,
+			expectError:   true,
+			errorContains: "compartment service error",
+		},
+		{
+			name:          "Error case - invalid payload type",
+			payload:       "invalid-payload",
+			expectError:   true,
+			errorContains: "invalid payload for ApplyHammer compensation",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			ctx := context.Background()
+			te, _ := tenant.Create(uuid.New(), "GMS", 83, 1)
+			tctx := tenant.WithContext(ctx, te)
+
+			transactionId := uuid.New()
+			restored := false
+			compP := &mock2.ProcessorMock{
+				RequestSetUpgradeSlotsFunc: func(tId uuid.UUID, characterId uint32, slot int16, slots uint16, hammersApplied uint32) error {
+					restored = true
+					assert.Equal(t, transactionId, tId)
+					assert.Equal(t, uint32(12345), characterId)
+					assert.Equal(t, int16(1), slot)
+					assert.Equal(t, uint16(3), slots)
+					assert.Equal(t, uint32(1), hammersApplied)
+					return tt.mockError
+				},
+			}
+
+			// Create test saga with failed step
+			saga := Saga{
+				TransactionId: transactionId,
+				SagaType:      InventoryTransaction,
+				InitiatedBy:   "compensation-test",
+				Steps: []Step[any]{
+					{
+						StepId:    "hammer-step",
+						Status:    Failed,
+						Action:    ApplyHammer,
+						Payload:   tt.payload,
+						Attempts:  tt.attempts,
+						CreatedAt: time.Now(),
+						UpdatedAt: time.Now(),
+					},
+				},
+			}
+
+			// Execute
+			err := NewCompensator(logger, tctx).WithCompartmentProcessor(compP).compensateApplyHammer(saga, saga.Steps[0])
+
+			// Verify
+			assert.Equal(t, tt.expectRestore, restored)
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestCompensateDeductMesos tests the compensateDeductMesos function
+func TestCompensateDeductMesos(t *testing.T) {
+	tests := []struct {
+		name          string
+		payload       any
+		attempts      []StepAttempt
+		mockError     error
+		expectRefund  bool
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name:         "Success case - fee refunded",
+			payload:      DeductMesosPayload{CharacterId: 12345, WorldId: 0, ChannelId: 1, ActorId: 2010008, ActorType: "NPC", Amount: 5000000},
+			attempts:     []StepAttempt{{Attempt: 1}},
+			expectRefund: true,
+		},
+		{
+			name:     "Success case - rejected deduction is not refunded",
+			payload:  DeductMesosPayload{CharacterId: 12345, WorldId: 0, ChannelId: 1, ActorId: 2010008, ActorType: "NPC", Amount: 5000000},
+			attempts: []StepAttempt{{Attempt: 1, ErrorCode: "NOT_ENOUGH_MESO"}},
+		},
+		{
+			name:         "Success case - timed out deduction is refunded",
+			payload:      DeductMesosPayload{CharacterId: 12345, WorldId: 0, ChannelId: 1, ActorId: 2010008, ActorType: "NPC", Amount: 5000000},
+			attempts:     []StepAttempt{{Attempt: 1, ErrorCode: ErrorCodeStepTimeout, Abandoned: true}},
+			expectRefund: true,
+		},
+		{
+			name:          "Error case - refund fails",
+			payload:       DeductMesosPayload{CharacterId: 12345, WorldId: 0, ChannelId: 1, ActorId: 2010008, ActorType: "NPC", Amount: 5000000},
+			mockError:     errors.New("character service error"),
+			expectRefund:  true,
+			expectError:   true,
+			errorContains: "character service error",
+		},
+		{
+			name:          "Error case - invalid payload type",
+			payload:       "invalid-payload",
+			expectError:   true,
+			errorContains: "invalid payload for DeductMesos compensation",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			ctx := context.Background()
+			te, _ := tenant.Create(uuid.New(), "GMS", 83, 1)
+			tctx := tenant.WithContext(ctx, te)
+
+			transactionId := uuid.New()
+			refunded := false
+			charP := &mock3.ProcessorMock{
+				AwardMesosAndEmitFunc: func(tId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, actorId uint32, actorType string, amount int32) error {
+					refunded = true
+					assert.Equal(t, transactionId, tId)
+					assert.Equal(t, uint32(12345), characterId)
+					assert.Equal(t, int32(5000000), amount)
+					return tt.mockError
+				},
+			}
+
+			// Create test saga with failed step
+			saga := Saga{
+				TransactionId: transactionId,
+				SagaType:      GuildEmblemPurchase,
+				InitiatedBy:   "compensation-test",
+				Steps: []Step[any]{
+					{
+						StepId:    "fee-step",
+						Status:    Failed,
+						Action:    DeductMesos,
+						Payload:   tt.payload,
+						Attempts:  tt.attempts,
+						CreatedAt: time.Now(),
+						UpdatedAt: time.Now(),
+					},
+				},
+			}
+
+			// Execute
+			err := NewCompensator(logger, tctx).WithCharacterProcessor(charP).compensateDeductMesos(saga, saga.Steps[0])
+
+			// Verify
+			assert.Equal(t, tt.expectRefund, refunded)
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestCompensateVerifyAndConsumeTicket tests the compensateVerifyAndConsumeTicket function
+func TestCompensateVerifyAndConsumeTicket(t *testing.T) {
+	proof := &TicketProof{AssetId: 7, Slot: 2, TemplateId: 4001007, Quantity: 1, ConsumedAt: time.Now()}
+
+	tests := []struct {
+		name          string
+		payload       any
+		attempts      []StepAttempt
+		mockError     error
+		expectReturn  bool
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name:         "Success case - consumed ticket returned",
+			payload:      VerifyAndConsumeTicketPayload{CharacterId: 12345, TemplateId: 4001007, Proof: proof},
+			attempts:     []StepAttempt{{Attempt: 1}},
+			expectReturn: true,
+		},
+		{
+			name:     "Success case - rejected consumption is not reversed",
+			payload:  VerifyAndConsumeTicketPayload{CharacterId: 12345, TemplateId: 4001007, Proof: proof},
+			attempts: []StepAttempt{{Attempt: 1, ErrorCode: "ITEM_NOT_FOUND"}},
+		},
+		{
+			name:    "Success case - ticket not held",
+			payload: VerifyAndConsumeTicketPayload{CharacterId: 12345, TemplateId: 4001007},
+		},
+		{
+			name:          "Error case - return fails",
+			payload:       VerifyAndConsumeTicketPayload{CharacterId: 12345, TemplateId: 4001007, Proof: proof},
+			mockError:     errors.New("compartment service error"),
+			expectReturn:  true,
+			expectError:   true,
+			errorContains: "compartment service error",
+		},
+		{
+			name:          "Error case - invalid payload type",
+			payload:       "invalid-payload",
+			expectError:   true,
+			errorContains: "invalid payload for VerifyAndConsumeTicket compensation",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			ctx := context.Background()
+			te, _ := tenant.Create(uuid.New(), "GMS", 83, 1)
+			tctx := tenant.WithContext(ctx, te)
+
+			transactionId := uuid.New()
+			returned := false
+			compP := &mock2.ProcessorMock{
+				RequestCreateItemFunc: func(tId uuid.UUID, characterId uint32, templateId uint32, quantity uint32) error {
+					returned = true
+					assert.Equal(t, transactionId, tId)
+					assert.Equal(t, uint32(12345), characterId)
+					assert.Equal(t, uint32(4001007), templateId)
+					assert.Equal(t, uint32(1), quantity)
+					return tt.mockError
+				},
+			}
+
+			// Create test saga with failed step
+			saga := Saga{
+				TransactionId: transactionId,
+				SagaType:      InventoryTransaction,
+				InitiatedBy:   "compensation-test",
+				Steps: []Step[any]{
+					{
+						StepId:    "ticket-step",
+						Status:    Failed,
+						Action:    VerifyAndConsumeTicket,
+						Payload:   tt.payload,
+						Attempts:  tt.attempts,
+						CreatedAt: time.Now(),
+						UpdatedAt: time.Now(),
+					},
+				},
+			}
+
+			// Execute
+			err := NewCompensator(logger, tctx).WithCompartmentProcessor(compP).compensateVerifyAndConsumeTicket(saga, saga.Steps[0])
+
+			// Verify
+			assert.Equal(t, tt.expectReturn, returned)
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestCompensateAdjustReactorState tests the compensateAdjustReactorState function
+func TestCompensateAdjustReactorState(t *testing.T) {
+	previous := int8(0)
+	fieldId := field.NewBuilder(world.Id(0), channel.Id(1), _map.Id(103000800)).SetInstance(uuid.New()).Build().Id()
+	tests := []struct {
+		name          string
+		payload       AdjustReactorStatePayload
+		attempts      []StepAttempt
+		expectRestore bool
+	}{
+		{
+			name:          "Success case - previous state restored",
+			payload:       AdjustReactorStatePayload{FieldId: fieldId, ReactorId: 7, State: 1, Previous: &previous},
+			expectRestore: true,
+		},
+		{
+			name:    "Success case - no previous state given",
+			payload: AdjustReactorStatePayload{FieldId: fieldId, ReactorId: 7, State: 1},
+		},
+		{
+			name:     "Success case - state change reported an error",
+			payload:  AdjustReactorStatePayload{FieldId: fieldId, ReactorId: 7, State: 1, Previous: &previous},
+			attempts: []StepAttempt{{Attempt: 1, ErrorCode: "NOT_FOUND"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			ctx := context.Background()
+			te, _ := tenant.Create(uuid.New(), "GMS", 83, 1)
+			tctx := tenant.WithContext(ctx, te)
+
+			restored := false
+			reactP := &mock7.ProcessorMock{
+				ChangeStateAndEmitFunc: func(transactionId uuid.UUID, f field.Model, reactorId uint32, state int8) error {
+					restored = true
+					assert.Equal(t, uint32(7), reactorId)
+					assert.Equal(t, previous, state)
+					return nil
+				},
+			}
+
+			saga := Saga{
+				TransactionId: uuid.New(),
+				SagaType:      QuestReward,
+				InitiatedBy:   "compensation-test",
+				Steps: []Step[any]{
+					{
+						StepId:   "door-step",
+						Status:   Failed,
+						Action:   AdjustReactorState,
+						Payload:  tt.payload,
+						Attempts: tt.attempts,
+					},
+				},
+			}
+
+			// Execute
+			err := NewCompensator(logger, tctx).WithReactorProcessor(reactP).compensateAdjustReactorState(saga, saga.Steps[0])
+
+			// Verify
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectRestore, restored)
+		})
+	}
+}
+
+// TestCompensateDeathPenalty tests the compensateApplyCharacterExpPenalty and compensateApplyDurabilityPenalty functions
+func TestCompensateDeathPenalty(t *testing.T) {
+	tests := []struct {
+		name          string
+		action        Action
+		payload       any
+		attempts      []StepAttempt
+		expectRestore bool
+	}{
+		{
+			name:          "Deducted experience is awarded back",
+			action:        ApplyCharacterExpPenalty,
+			payload:       ApplyCharacterExpPenaltyPayload{CharacterId: 12345, WorldId: 0, ChannelId: 1, Amount: 1500},
+			attempts:      []StepAttempt{{Attempt: 1}},
+			expectRestore: true,
+		},
+		{
+			name:     "Rejected experience penalty is not restored",
+			action:   ApplyCharacterExpPenalty,
+			payload:  ApplyCharacterExpPenaltyPayload{CharacterId: 12345, WorldId: 0, ChannelId: 1, Amount: 1500},
+			attempts: []StepAttempt{{Attempt: 1, ErrorCode: "UNKNOWN_ERROR"}},
+		},
+		{
+			name:          "Lost durability is restored",
+			action:        ApplyDurabilityPenalty,
+			payload:       ApplyDurabilityPenaltyPayload{CharacterId: 12345, Percent: 10},
+			attempts:      []StepAttempt{{Attempt: 1}},
+			expectRestore: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			ctx := context.Background()
+			te, _ := tenant.Create(uuid.New(), "GMS", 83, 1)
+			tctx := tenant.WithContext(ctx, te)
+
+			restored := false
+			charP := &mock3.ProcessorMock{
+				AwardExperienceAndEmitFunc: func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, distributions []character2.ExperienceDistributions) error {
+					restored = true
+					assert.Equal(t, uint32(12345), characterId)
+					assert.Equal(t, []character2.ExperienceDistributions{{ExperienceType: character2.ExperienceDistributionTypeWhite, Amount: 1500}}, distributions)
+					return nil
+				},
+			}
+			compP := &mock2.ProcessorMock{
+				RequestChangeDurabilityFunc: func(transactionId uuid.UUID, characterId uint32, percent int8) error {
+					restored = true
+					assert.Equal(t, uint32(12345), characterId)
+					assert.Equal(t, int8(10), percent)
+					return nil
+				},
+			}
+
+			// Create test saga with failed step
+			saga := Saga{
+				TransactionId: uuid.New(),
+				SagaType:      DeathPenalty,
+				InitiatedBy:   "compensation-test",
+				Steps: []Step[any]{
+					{
+						StepId:    "penalty-step",
+						Status:    Failed,
+						Action:    tt.action,
+						Payload:   tt.payload,
+						Attempts:  tt.attempts,
+						CreatedAt: time.Now(),
+						UpdatedAt: time.Now(),
+					},
+				},
+			}
+
+			// Execute
+			c := NewCompensator(logger, tctx).WithCharacterProcessor(charP).WithCompartmentProcessor(compP)
+			var err error
+			if tt.action == ApplyCharacterExpPenalty {
+				err = c.compensateApplyCharacterExpPenalty(saga, saga.Steps[0])
+			} else {
+				err = c.compensateApplyDurabilityPenalty(saga, saga.Steps[0])
+			}
+
+			// Verify
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectRestore, restored)
+		})
+	}
+}
+
+// TestCompensateUpdateCharacterResource tests the compensateUpdateCharacterResource function
+func TestCompensateUpdateCharacterResource(t *testing.T) {
+	previous := int32(2500)
+
+	tests := []struct {
+		name          string
+		payload       any
+		attempts      []StepAttempt
+		mockError     error
+		expectRestore bool
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name:          "Success case - prior value restored",
+			payload:       UpdateCharacterResourcePayload{CharacterId: 12345, Resource: character2.ResourceEnergyCharge, Amount: 5000, Previous: &previous},
+			attempts:      []StepAttempt{{Attempt: 1}},
+			expectRestore: true,
+		},
+		{
+			name:     "Success case - rejected change is not reversed",
+			payload:  UpdateCharacterResourcePayload{CharacterId: 12345, Resource: character2.ResourceEnergyCharge, Amount: 5000, Previous: &previous},
+			attempts: []StepAttempt{{Attempt: 1, ErrorCode: "UNKNOWN_RESOURCE"}},
+		},
+		{
+			name:    "Success case - change never requested",
+			payload: UpdateCharacterResourcePayload{CharacterId: 12345, Resource: character2.ResourceEnergyCharge, Amount: 5000},
+		},
+		{
+			name:          "Error case - restore fails",
+			payload:       UpdateCharacterResourcePayload{CharacterId: 12345, Resource: character2.ResourceEnergyCharge, Amount: 5000, Previous: &previous},
+			mockError:     errors.New("character service error"),
+			expectRestore: true,
+			expectError:   true,
+			errorContains: "character service error",
+		},
+		{
+			name:          "Error case - invalid payload type",
+			payload:       "invalid-payload",
+			expectError:   true,
+			errorContains: "invalid payload for UpdateCharacterResource compensation",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			ctx := context.Background()
+			te, _ := tenant.Create(uuid.New(), "GMS", 83, 1)
+			tctx := tenant.WithContext(ctx, te)
+
+			transactionId := uuid.New()
+			restored := false
+			charP := &mock3.ProcessorMock{
+				ChangeResourceAndEmitFunc: func(tId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, resource string, value int32) error {
+					restored = true
+					assert.Equal(t, transactionId, tId)
+					assert.Equal(t, uint32(12345), characterId)
+					assert.Equal(t, character2.ResourceEnergyCharge, resource)
+					assert.Equal(t, previous, value)
+					return tt.mockError
+				},
+			}
+
+			// Create test saga with failed step
+			saga := Saga{
+				TransactionId: transactionId,
+				SagaType:      QuestReward,
+				InitiatedBy:   "compensation-test",
+				Steps: []Step[any]{
+					{
+						StepId:    "resource-step",
+						Status:    Failed,
+						Action:    UpdateCharacterResource,
+						Payload:   tt.payload,
+						Attempts:  tt.attempts,
+						CreatedAt: time.Now(),
+						UpdatedAt: time.Now(),
+					},
+				},
+			}
+
+			// Execute
+			err := NewCompensator(logger, tctx).WithCharacterProcessor(charP).compensateUpdateCharacterResource(saga, saga.Steps[0])
+
+			// Verify
+			assert.Equal(t, tt.expectRestore, restored)
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestCompensateChangeJob tests the compensateChangeJob function
+func TestCompensateChangeJob(t *testing.T) {
+	previous := job.Id(100)
+
+	tests := []struct {
+		name          string
+		payload       any
+		attempts      []StepAttempt
+		mockError     error
+		expectRevert  bool
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name:         "Success case - prior job restored",
+			payload:      ChangeJobPayload{CharacterId: 12345, JobId: 110, Previous: &previous},
+			attempts:     []StepAttempt{{Attempt: 1}},
+			expectRevert: true,
+		},
+		{
+			name:     "Success case - rejected change is not reversed",
+			payload:  ChangeJobPayload{CharacterId: 12345, JobId: 110, Previous: &previous},
+			attempts: []StepAttempt{{Attempt: 1, ErrorCode: "INVALID_JOB"}},
+		},
+		{
+			name:    "Success case - change never requested",
+			payload: ChangeJobPayload{CharacterId: 12345, JobId: 110},
+		},
+		{
+			name:          "Error case - revert fails",
+			payload:       ChangeJobPayload{CharacterId: 12345, JobId: 110, Previous: &previous},
+			mockError:     errors.New("character service error"),
+			expectRevert:  true,
+			expectError:   true,
+			errorContains: "character service error",
+		},
+		{
+			name:          "Error case - invalid payload type",
+			payload:       "invalid-payload",
+			expectError:   true,
+			errorContains: "invalid payload for ChangeJob compensation",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			te, _ := tenant.Create(uuid.New(), "GMS", 83, 1)
+			tctx := tenant.WithContext(context.Background(), te)
+
+			transactionId := uuid.New()
+			reverted := false
+			charP := &mock3.ProcessorMock{
+				ChangeJobAndEmitFunc: func(tId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, jobId job.Id) error {
+					reverted = true
+					assert.Equal(t, transactionId, tId)
+					assert.Equal(t, uint32(12345), characterId)
+					assert.Equal(t, previous, jobId)
+					return tt.mockError
+				},
+			}
+
+			saga := Saga{
+				TransactionId: transactionId,
+				SagaType:      JobReset,
+				InitiatedBy:   "compensation-test",
+				Steps: []Step[any]{
+					{StepId: "job", Status: Failed, Action: ChangeJob, Payload: tt.payload, Attempts: tt.attempts, CreatedAt: time.Now(), UpdatedAt: time.Now()},
+				},
+			}
+
+			err := NewCompensator(logger, tctx).WithCharacterProcessor(charP).compensateChangeJob(saga, saga.Steps[0])
+
+			assert.Equal(t, tt.expectRevert, reverted)
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestCompensateCharacterExperienceLock tests the compensateCharacterExperienceLock and
+// compensateCharacterExperienceUnlock functions
+func TestCompensateCharacterExperienceLock(t *testing.T) {
+	tests := []struct {
+		name          string
+		action        Action
+		payload       any
+		attempts      []StepAttempt
+		expectLock    bool
+		expectUnlock  bool
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name:         "Success case - lock reversed by unlocking",
+			action:       CharacterExperienceLock,
+			payload:      CharacterExperienceLockPayload{CharacterId: 12345},
+			attempts:     []StepAttempt{{Attempt: 1}},
+			expectUnlock: true,
+		},
+		{
+			name:     "Success case - rejected lock is not reversed",
+			action:   CharacterExperienceLock,
+			payload:  CharacterExperienceLockPayload{CharacterId: 12345},
+			attempts: []StepAttempt{{Attempt: 1, ErrorCode: "CHARACTER_NOT_FOUND"}},
+		},
+		{
+			name:       "Success case - unlock reversed by locking",
+			action:     CharacterExperienceUnlock,
+			payload:    CharacterExperienceUnlockPayload{CharacterId: 12345},
+			attempts:   []StepAttempt{{Attempt: 1}},
+			expectLock: true,
+		},
+		{
+			name:          "Error case - invalid payload type",
+			action:        CharacterExperienceLock,
+			payload:       CharacterExperienceUnlockPayload{CharacterId: 12345},
+			expectError:   true,
+			errorContains: "invalid payload for CharacterExperienceLock compensation",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			ctx := context.Background()
+			te, _ := tenant.Create(uuid.New(), "GMS", 83, 1)
+			tctx := tenant.WithContext(ctx, te)
+
+			transactionId := uuid.New()
+			locked, unlocked := false, false
+			charP := &mock3.ProcessorMock{
+				LockExperienceAndEmitFunc: func(tId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, reason string) error {
+					locked = true
+					assert.Equal(t, transactionId, tId)
+					assert.Equal(t, uint32(12345), characterId)
+					return nil
+				},
+				UnlockExperienceAndEmitFunc: func(tId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id) error {
+					unlocked = true
+					assert.Equal(t, transactionId, tId)
+					assert.Equal(t, uint32(12345), characterId)
+					return nil
+				},
+			}
+
+			// Create test saga with failed step
+			saga := Saga{
+				TransactionId: transactionId,
+				SagaType:      QuestReward,
+				InitiatedBy:   "compensation-test",
+				Steps: []Step[any]{
+					{
+						StepId:    "experience-step",
+						Status:    Failed,
+						Action:    tt.action,
+						Payload:   tt.payload,
+						Attempts:  tt.attempts,
+						CreatedAt: time.Now(),
+						UpdatedAt: time.Now(),
+					},
+				},
+			}
+
+			// Execute
+			err := NewCompensator(logger, tctx).WithCharacterProcessor(charP).CompensateFailedStep(saga)
+
+			// Verify
+			assert.Equal(t, tt.expectLock, locked)
+			assert.Equal(t, tt.expectUnlock, unlocked)
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestCompensateGrantPremiumTime tests the compensateGrantPremiumTime function
+func TestCompensateGrantPremiumTime(t *testing.T) {
+	tests := []struct {
+		name          string
+		payload       any
+		attempts      []StepAttempt
+		mockError     error
+		expectDeduct  bool
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name:         "Success case - credited time deducted",
+			payload:      GrantPremiumTimePayload{AccountId: 7, Seconds: 3600},
+			attempts:     []StepAttempt{{Attempt: 1}},
+			expectDeduct: true,
+		},
+		{
+			name:     "Success case - rejected change is not reversed",
+			payload:  GrantPremiumTimePayload{AccountId: 7, Seconds: 3600},
+			attempts: []StepAttempt{{Attempt: 1, ErrorCode: "NOT_FOUND"}},
+		},
+		{
+			name:          "Error case - deduction fails",
+			payload:       GrantPremiumTimePayload{AccountId: 7, Seconds: 3600},
+			mockError:     errors.New("account service error"),
+			expectDeduct:  true,
+			expectError:   true,
+			errorContains: "account service error",
+		},
+		{
+			name:          "Error case - invalid payload type",
+			payload:       "invalid-payload",
+			expectError:   true,
+			errorContains: "invalid payload for GrantPremiumTime compensation",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			ctx := context.Background()
+			te, _ := tenant.Create(uuid.New(), "GMS", 83, 1)
+			tctx := tenant.WithContext(ctx, te)
+
+			transactionId := uuid.New()
+			deducted := false
+			acctP := &mock6.ProcessorMock{
+				ChangePremiumTimeAndEmitFunc: func(tId uuid.UUID, accountId uint32, seconds int64) error {
+					deducted = true
+					assert.Equal(t, transactionId, tId)
+					assert.Equal(t, uint32(7), accountId)
+					assert.Equal(t, int64(-3600), seconds)
+					return tt.mockError
+				},
+			}
+
+			// Create test saga with failed step
+			saga := Saga{
+				TransactionId: transactionId,
+				SagaType:      InventoryTransaction,
+				InitiatedBy:   "compensation-test",
+				Steps: []Step[any]{
+					{
+						StepId:    "vip-step",
+						Status:    Failed,
+						Action:    GrantPremiumTime,
+						Payload:   tt.payload,
+						Attempts:  tt.attempts,
+						CreatedAt: time.Now(),
+						UpdatedAt: time.Now(),
+					},
+				},
+			}
+
+			// Execute
+			err := NewCompensator(logger, tctx).WithAccountProcessor(acctP).compensateGrantPremiumTime(saga, saga.Steps[0])
+
+			// Verify
+			assert.Equal(t, tt.expectDeduct, deducted)
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestCompensateDissolveMarriage tests the compensateDissolveMarriage function
+func TestCompensateDissolveMarriage(t *testing.T) {
+	tests := []struct {
+		name          string
+		payload       any
+		attempts      []StepAttempt
+		mockError     error
+		expectRestore bool
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name:          "Success case - dissolved marriage restored",
+			payload:       DissolveMarriagePayload{CharacterId: 12345, MarriageId: 42, PartnerId: 67890},
+			attempts:      []StepAttempt{{Attempt: 1}},
+			expectRestore: true,
+		},
+		{
+			name:     "Success case - rejected divorce is not reversed",
+			payload:  DissolveMarriagePayload{CharacterId: 12345, MarriageId: 42, PartnerId: 67890},
+			attempts: []StepAttempt{{Attempt: 1, ErrorCode: "NOT_MARRIED"}},
+		},
+		{
+			name:          "Error case - restoration fails",
+			payload:       DissolveMarriagePayload{CharacterId: 12345, MarriageId: 42, PartnerId: 67890},
+			mockError:     errors.New("marriage service error"),
+			expectRestore: true,
+			expectError:   true,
+			errorContains: "marriage service error",
+		},
+		{
+			name:          "Error case - invalid payload type",
+			payload:       "invalid-payload",
+			expectError:   true,
+			errorContains: "invalid payload for DissolveMarriage compensation",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			ctx := context.Background()
+			te, _ := tenant.Create(uuid.New(), "GMS", 83, 1)
+			tctx := tenant.WithContext(ctx, te)
+
+			transactionId := uuid.New()
+			restored := false
+			marriP := &mock8.ProcessorMock{
+				RestoreAndEmitFunc: func(tId uuid.UUID, characterId uint32, marriageId uint32, partnerId uint32) error {
+					restored = true
+					assert.Equal(t, transactionId, tId)
+					assert.Equal(t, uint32(12345), characterId)
+					assert.Equal(t, uint32(42), marriageId)
+					assert.Equal(t, uint32(67890), partnerId)
+					return tt.mockError
+				},
+			}
+
+			// Create test saga with failed step
+			saga := Saga{
+				TransactionId: transactionId,
+				SagaType:      Divorce,
+				InitiatedBy:   "compensation-test",
+				Steps: []Step[any]{
+					{
+						StepId:    "dissolve-step",
+						Status:    Failed,
+						Action:    DissolveMarriage,
+						Payload:   tt.payload,
+						Attempts:  tt.attempts,
+						CreatedAt: time.Now(),
+						UpdatedAt: time.Now(),
+					},
+				},
+			}
+
+			// Execute
+			err := NewCompensator(logger, tctx).WithMarriageProcessor(marriP).compensateDissolveMarriage(saga, saga.Steps[0])
+
+			// Verify
+			assert.Equal(t, tt.expectRestore, restored)
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestCompensateSealAsset tests the compensateSealAsset and compensateUnsealAsset functions
+func TestCompensateSealAsset(t *testing.T) {
+	tests := []struct {
+		name          string
+		action        Action
+		payload       any
+		attempts      []StepAttempt
+		mockError     error
+		expectCancel  bool
+		expectReserve bool
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name:         "Success case - seal cancelled",
+			action:       SealAsset,
+			payload:      SealAssetPayload{CharacterId: 12345, InventoryType: 1, Slot: 3, TemplateId: 1302000, Quantity: 1},
+			attempts:     []StepAttempt{{Attempt: 1}},
+			expectCancel: true,
+		},
+		{
+			name:     "Success case - rejected seal is not cancelled",
+			action:   SealAsset,
+			payload:  SealAssetPayload{CharacterId: 12345, InventoryType: 1, Slot: 3, TemplateId: 1302000, Quantity: 1},
+			attempts: []StepAttempt{{Attempt: 1, ErrorCode: "ASSET_NOT_FOUND"}},
+		},
+		{
+			name:          "Success case - unseal sealed again",
+			action:        UnsealAsset,
+			payload:       UnsealAssetPayload{CharacterId: 12345, InventoryType: 1, Slot: 3, TemplateId: 1302000, Quantity: 1},
+			attempts:      []StepAttempt{{Attempt: 1}},
+			expectReserve: true,
+		},
+		{
+			name:          "Error case - cancellation fails",
+			action:        SealAsset,
+			payload:       SealAssetPayload{CharacterId: 12345, InventoryType: 1, Slot: 3, TemplateId: 1302000, Quantity: 1},
+			mockError:     errors.New("compartment service error"),
+			expectCancel:  true,
+			expectError:   true,
+			errorContains: "compartment service error",
+		},
+		{
+			name:          "Error case - invalid payload type",
+			action:        UnsealAsset,
+			payload:       "invalid-payload",
+			expectError:   true,
+			errorContains: "invalid payload for UnsealAsset compensation",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			ctx := context.Background()
+			te, _ := tenant.Create(uuid.New(), "GMS", 83, 1)
+			tctx := tenant.WithContext(ctx, te)
+
+			cancelled := false
+			reserved := false
+			compP := &mock2.ProcessorMock{
+				RequestCancelReservationFunc: func(transactionId uuid.UUID, characterId uint32, inventoryType byte, slot int16) error {
+					cancelled = true
+					assert.Equal(t, uint32(12345), characterId)
+					assert.Equal(t, byte(1), inventoryType)
+					assert.Equal(t, int16(3), slot)
+					return tt.mockError
+				},
+				RequestReserveAssetFunc: func(transactionId uuid.UUID, characterId uint32, inventoryType byte, slot int16, templateId uint32, quantity uint32) error {
+					reserved = true
+					assert.Equal(t, int16(3), slot)
+					assert.Equal(t, uint32(1302000), templateId)
+					return tt.mockError
+				},
+			}
+
+			// Create test saga with failed step
+			saga := Saga{
+				TransactionId: uuid.New(),
+				SagaType:      DisputeHold,
+				InitiatedBy:   "compensation-test",
+				Steps: []Step[any]{
+					{
+						StepId:    "seal-step",
+						Status:    Failed,
+						Action:    tt.action,
+						Payload:   tt.payload,
+						Attempts:  tt.attempts,
+						CreatedAt: time.Now(),
+						UpdatedAt: time.Now(),
+					},
+				},
+			}
+
+			// Execute
+			c := NewCompensator(logger, tctx).WithCompartmentProcessor(compP)
+			var err error
+			if tt.action == SealAsset {
+				err = c.compensateSealAsset(saga, saga.Steps[0])
+			} else {
+				err = c.compensateUnsealAsset(saga, saga.Steps[0])
+			}
+
+			// Verify
+			assert.Equal(t, tt.expectCancel, cancelled)
+			assert.Equal(t, tt.expectReserve, reserved)
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestCompensateScheduleWarp tests that failed transport steps refund their fare
+func TestCompensateScheduleWarp(t *testing.T) {
+	tests := []struct {
+		name          string
+		action        Action
+		payload       any
+		mockError     error
+		expectRefund  bool
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name:         "Success case - warp fare refunded",
+			action:       ScheduleWarp,
+			payload:      ScheduleWarpPayload{CharacterId: 12345, ChannelId: 1, PortalId: 2, Fare: 5000},
+			expectRefund: true,
+		},
+		{
+			name:         "Success case - ticket fare refunded",
+			action:       IssueTransportTicket,
+			payload:      IssueTransportTicketPayload{CharacterId: 12345, ChannelId: 1, TemplateId: 4031045, Fare: 5000},
+			expectRefund: true,
+		},
+		{
+			name:    "Success case - free warp has nothing to refund",
+			action:  ScheduleWarp,
+			payload: ScheduleWarpPayload{CharacterId: 12345, ChannelId: 1, PortalId: 2},
+		},
+		{
+			name:          "Error case - refund fails",
+			action:        ScheduleWarp,
+			payload:       ScheduleWarpPayload{CharacterId: 12345, ChannelId: 1, PortalId: 2, Fare: 5000},
+			mockError:     errors.New("character service error"),
+			expectRefund:  true,
+			expectError:   true,
+			errorContains: "character service error",
+		},
+		{
+			name:          "Error case - invalid payload type",
+			action:        IssueTransportTicket,
+			payload:       "invalid-payload",
+			expectError:   true,
+			errorContains: "invalid payload for IssueTransportTicket compensation",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			ctx := context.Background()
+			te, _ := tenant.Create(uuid.New(), "GMS", 83, 1)
+			tctx := tenant.WithContext(ctx, te)
+
+			refunded := false
+			charP := &mock3.ProcessorMock{
+				AwardMesosAndEmitFunc: func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, actorId uint32, actorType string, amount int32) error {
+					refunded = true
+					assert.Equal(t, uint32(12345), characterId)
+					assert.Equal(t, "SYSTEM", actorType)
+					assert.Equal(t, int32(5000), amount)
+					return tt.mockError
+				},
+			}
+
+			// Create test saga with failed step
+			saga := Saga{
+				TransactionId: uuid.New(),
+				SagaType:      Transport,
+				InitiatedBy:   "compensation-test",
+				Steps: []Step[any]{
+					{
+						StepId:    "transport-step",
+						Status:    Failed,
+						Action:    tt.action,
+						Payload:   tt.payload,
+						CreatedAt: time.Now(),
+						UpdatedAt: time.Now(),
+					},
+				},
+			}
+
+			// Execute
+			c := NewCompensator(logger, tctx).WithCharacterProcessor(charP)
+			var err error
+			if tt.action == ScheduleWarp {
+				err = c.compensateScheduleWarp(saga, saga.Steps[0])
+			} else {
+				err = c.compensateIssueTransportTicket(saga, saga.Steps[0])
+			}
+
+			// Verify
+			assert.Equal(t, tt.expectRefund, refunded)
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestCompensateUpdateCharacterAlignment tests the compensateUpdateCharacterAlignment function
+func TestCompensateUpdateCharacterAlignment(t *testing.T) {
+	tests := []struct {
+		name          string
+		payload       any
+		attempts      []StepAttempt
+		mockError     error
+		expectReverse bool
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name:          "Success case - granted points reversed",
+			payload:       UpdateCharacterAlignmentPayload{CharacterId: 12345, FactionId: 2, Amount: 50},
+			attempts:      []StepAttempt{{Attempt: 1}},
+			expectReverse: true,
+		},
+		{
+			name:     "Success case - rejected change is not reversed",
+			payload:  UpdateCharacterAlignmentPayload{CharacterId: 12345, FactionId: 2, Amount: 50},
+			attempts: []StepAttempt{{Attempt: 1, ErrorCode: "NOT_FOUND"}},
+		},
+		{
+			name:          "Error case - reversal fails",
+			payload:       UpdateCharacterAlignmentPayload{CharacterId: 12345, FactionId: 2, Amount: 50},
+			mockError:     errors.New("faction service error"),
+			expectReverse: true,
+			expectError:   true,
+			errorContains: "faction service error",
+		},
+		{
+			name:          "Error case - invalid payload type",
+			payload:       "invalid-payload",
+			expectError:   true,
+			errorContains: "invalid payload for UpdateCharacterAlignment compensation",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			ctx := context.Background()
+			te, _ := tenant.Create(uuid.New(), "GMS", 83, 1)
+			tctx := tenant.WithContext(ctx, te)
+
+			transactionId := uuid.New()
+			reversed := false
+			factP := &mock9.ProcessorMock{
+				ChangePointsAndEmitFunc: func(tId uuid.UUID, worldId world.Id, characterId uint32, factionId uint32, amount int32) error {
+					reversed = true
+					assert.Equal(t, transactionId, tId)
+					assert.Equal(t, uint32(12345), characterId)
+					assert.Equal(t, uint32(2), factionId)
+					assert.Equal(t, int32(-50), amount)
+					return tt.mockError
+				},
+			}
+
+			// Create test saga with failed step
+			saga := Saga{
+				TransactionId: transactionId,
+				SagaType:      QuestReward,
+				InitiatedBy:   "compensation-test",
+				Steps: []Step[any]{
+					{
+						StepId:    "alignment-step",
+						Status:    Failed,
+						Action:    UpdateCharacterAlignment,
+						Payload:   tt.payload,
+						Attempts:  tt.attempts,
+						CreatedAt: time.Now(),
+						UpdatedAt: time.Now(),
+					},
+				},
+			}
+
+			// Execute
+			err := NewCompensator(logger, tctx).WithFactionProcessor(factP).compensateUpdateCharacterAlignment(saga, saga.Steps[0])
+
+			// Verify
+			assert.Equal(t, tt.expectReverse, reversed)
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCompensateResetInstanceCooldown(t *testing.T) {
+	previous := time.Now().Add(time.Hour)
+	tests := []struct {
+		name          string
+		payload       any
+		attempts      []StepAttempt
+		mockError     error
+		expectReapply bool
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name:          "Success case - prior cooldown re-applied",
+			payload:       ResetInstanceCooldownPayload{CharacterId: 12345, InstanceId: 2, Previous: &previous},
+			attempts:      []StepAttempt{{Attempt: 1}},
+			expectReapply: true,
+		},
+		{
+			name:     "Success case - character without a cooldown has none re-applied",
+			payload:  ResetInstanceCooldownPayload{CharacterId: 12345, InstanceId: 2},
+			attempts: []StepAttempt{{Attempt: 1}},
+		},
+		{
+			name:     "Success case - rejected reset is not re-applied",
+			payload:  ResetInstanceCooldownPayload{CharacterId: 12345, InstanceId: 2, Previous: &previous},
+			attempts: []StepAttempt{{Attempt: 1, ErrorCode: "NOT_FOUND"}},
+		},
+		{
+			name:          "Error case - re-applying fails",
+			payload:       ResetInstanceCooldownPayload{CharacterId: 12345, InstanceId: 2, Previous: &previous},
+			mockError:     errors.New("instance service error"),
+			expectReapply: true,
+			expectError:   true,
+			errorContains: "instance service error",
+		},
+		{
+			name:          "Error case - invalid payload type",
+			payload:       "invalid-payload",
+			expectError:   true,
+			errorContains: "invalid payload for ResetInstanceCooldown compensation",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			ctx := context.Background()
+			te, _ := tenant.Create(uuid.New(), "GMS", 83, 1)
+			tctx := tenant.WithContext(ctx, te)
+
+			transactionId := uuid.New()
+			reapplied := false
+			instP := &mock10.ProcessorMock{
+				SetCooldownAndEmitFunc: func(tId uuid.UUID, worldId world.Id, characterId uint32, instanceId uint32, expiresAt time.Time) error {
+					reapplied = true
+					assert.Equal(t, transactionId, tId)
+					assert.Equal(t, uint32(12345), characterId)
+					assert.Equal(t, uint32(2), instanceId)
+					assert.True(t, previous.Equal(expiresAt))
+					return tt.mockError
+				},
+			}
+
+			// Create test saga with failed step
+			saga := Saga{
+				TransactionId: transactionId,
+				SagaType:      InventoryTransaction,
+				InitiatedBy:   "compensation-test",
+				Steps: []Step[any]{
+					{
+						StepId:    "cooldown-step",
+						Status:    Failed,
+						Action:    ResetInstanceCooldown,
+						Payload:   tt.payload,
+						Attempts:  tt.attempts,
+						CreatedAt: time.Now(),
+						UpdatedAt: time.Now(),
+					},
+				},
+			}
+
+			// Execute
+			err := NewCompensator(logger, tctx).WithInstanceProcessor(instP).compensateResetInstanceCooldown(saga, saga.Steps[0])
+
+			// Verify
+			assert.Equal(t, tt.expectReapply, reapplied)
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestCompensateApplyTitleBuffOnLogin tests the compensateApplyTitleBuffOnLogin function
+func TestCompensateApplyTitleBuffOnLogin(t *testing.T) {
+	effectId := uuid.New()
+	tests := []struct {
+		name          string
+		payload       any
+		attempts      []StepAttempt
+		mockError     error
+		expectCancel  bool
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name:         "Success case - pending effect cancelled",
+			payload:      ApplyTitleBuffOnLoginPayload{CharacterId: 12345, TitleId: 1142000, Duration: 3600, EffectId: &effectId},
+			attempts:     []StepAttempt{{Attempt: 1}},
+			expectCancel: true,
+		},
+		{
+			name:     "Success case - unregistered effect is not cancelled",
+			payload:  ApplyTitleBuffOnLoginPayload{CharacterId: 12345, TitleId: 1142000, Duration: 3600},
+			attempts: []StepAttempt{{Attempt: 1}},
+		},
+		{
+			name:     "Success case - expired effect is not cancelled",
+			payload:  ApplyTitleBuffOnLoginPayload{CharacterId: 12345, TitleId: 1142000, Duration: 3600, EffectId: &effectId},
+			attempts: []StepAttempt{{Attempt: 1, ErrorCode: "EXPIRED"}},
+		},
+		{
+			name:          "Error case - cancelling fails",
+			payload:       ApplyTitleBuffOnLoginPayload{CharacterId: 12345, TitleId: 1142000, Duration: 3600, EffectId: &effectId},
+			mockError:     errors.New("session service error"),
+			expectCancel:  true,
+			expectError:   true,
+			errorContains: "session service error",
+		},
+		{
+			name:          "Error case - invalid payload type",
+			payload:       "invalid-payload",
+			expectError:   true,
+			errorContains: "invalid payload for ApplyTitleBuffOnLogin compensation",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			ctx := context.Background()
+			te, _ := tenant.Create(uuid.New(), "GMS", 83, 1)
+			tctx := tenant.WithContext(ctx, te)
+
+			transactionId := uuid.New()
+			cancelled := false
+			sessP := &mock11.ProcessorMock{
+				CancelDeferredEffectAndEmitFunc: func(tId uuid.UUID, worldId world.Id, characterId uint32, eId uuid.UUID) error {
+					cancelled = true
+					assert.Equal(t, transactionId, tId)
+					assert.Equal(t, uint32(12345), characterId)
+					assert.Equal(t, effectId, eId)
+					return tt.mockError
+				},
+			}
+
+			// Create test saga with failed step
+			saga := Saga{
+				TransactionId: transactionId,
+				SagaType:      QuestReward,
+				InitiatedBy:   "compensation-test",
+				Steps: []Step[any]{
+					{
+						StepId:    "title-step",
+						Status:    Failed,
+						Action:    ApplyTitleBuffOnLogin,
+						Payload:   tt.payload,
+						Attempts:  tt.attempts,
+						CreatedAt: time.Now(),
+						UpdatedAt: time.Now(),
+					},
+				},
+			}
+
+			// Execute
+			err := NewCompensator(logger, tctx).WithSessionProcessor(sessP).compensateApplyTitleBuffOnLogin(saga, saga.Steps[0])
+
+			// Verify
+			assert.Equal(t, tt.expectCancel, cancelled)
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestCompensateEquipAssetByTemplate tests the compensateEquipAssetByTemplate function
+func TestCompensateEquipAssetByTemplate(t *testing.T) {
+	tests := []struct {
+		name          string
+		payload       any
+		attempts      []StepAttempt
+		mockError     error
+		expectUnequip bool
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name:          "Success case - asset unequipped to the slot it was found in",
+			payload:       EquipAssetByTemplatePayload{CharacterId: 12345, TemplateId: 1302000, Destination: -11, Source: 3},
+			attempts:      []StepAttempt{{Attempt: 1}},
+			expectUnequip: true,
+		},
+		{
+			name:     "Success case - rejected equip is not reversed",
+			payload:  EquipAssetByTemplatePayload{CharacterId: 12345, TemplateId: 1302000, Destination: -11, Source: 3},
+			attempts: []StepAttempt{{Attempt: 1, ErrorCode: "SLOT_OCCUPIED"}},
+		},
+		{
+			name:    "Success case - equip never requested",
+			payload: EquipAssetByTemplatePayload{CharacterId: 12345, TemplateId: 1302000, Destination: -11},
+		},
+		{
+			name:          "Error case - unequip fails",
+			payload:       EquipAssetByTemplatePayload{CharacterId: 12345, TemplateId: 1302000, Destination: -11, Source: 3},
+			mockError:     errors.New("compartment service error"),
+			expectUnequip: true,
+			expectError:   true,
+			errorContains: "compartment service error",
+		},
+		{
+			name:          "Error case - invalid payload type",
+			payload:       "invalid-payload",
+			expectError:   true,
+			errorContains: "invalid payload for EquipAssetByTemplate compensation",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			ctx := context.Background()
+			te, _ := tenant.Create(uuid.New(), "GMS", 83, 1)
+			tctx := tenant.WithContext(ctx, te)
+
+			unequipped := false
+			compP := &mock2.ProcessorMock{
+				RequestUnequipAssetFunc: func(transactionId uuid.UUID, characterId uint32, inventoryType byte, source int16, destination int16) error {
+					unequipped = true
+					assert.Equal(t, uint32(12345), characterId)
+					assert.Equal(t, int16(-11), source)
+					assert.Equal(t, int16(3), destination)
+					return tt.mockError
+				},
+			}
+
+			// Create test saga with failed step
+			saga := Saga{
+				TransactionId: uuid.New(),
+				SagaType:      CharacterCreation,
+				InitiatedBy:   "compensation-test",
+				Steps: []Step[any]{
+					{
+						StepId:    "equip-step",
+						Status:    Failed,
+						Action:    EquipAssetByTemplate,
+						Payload:   tt.payload,
+						Attempts:  tt.attempts,
+						CreatedAt: time.Now(),
+						UpdatedAt: time.Now(),
+					},
+				},
+			}
+
+			// Execute
+			err := NewCompensator(logger, tctx).WithCompartmentProcessor(compP).compensateEquipAssetByTemplate(saga, saga.Steps[0])
+
+			// Verify
+			assert.Equal(t, tt.expectUnequip, unequipped)
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestCompensatePetEquip tests that failed pet equips are reversed, and pet unequips re-equipped, unless rejected or
+// never requested
+func TestCompensatePetEquip(t *testing.T) {
+	tests := []struct {
+		name          string
+		step          Step[any]
+		expectEquip   bool
+		expectUnequip bool
+	}{
+		{
+			name:          "Success case - equip reversed",
+			step:          Step[any]{Action: PetEquip, Payload: PetEquipPayload{CharacterId: 12345, PetId: 7001, TemplateId: 1802000, Destination: -14, Source: 4}, Attempts: []StepAttempt{{Attempt: 1}}},
+			expectUnequip: true,
+		},
+		{
+			name: "Success case - rejected equip is not reversed",
+			step: Step[any]{Action: PetEquip, Payload: PetEquipPayload{CharacterId: 12345, PetId: 7001, TemplateId: 1802000, Destination: -14, Source: 4}, Attempts: []StepAttempt{{Attempt: 1, ErrorCode: "SLOT_OCCUPIED"}}},
+		},
+		{
+			name: "Success case - equip never requested",
+			step: Step[any]{Action: PetEquip, Payload: PetEquipPayload{CharacterId: 12345, PetId: 7001, TemplateId: 1802000, Destination: -14}},
+		},
+		{
+			name:        "Success case - unequip reversed",
+			step:        Step[any]{Action: PetUnequip, Payload: PetUnequipPayload{CharacterId: 12345, PetId: 7001, Source: -14, Destination: 4, TemplateId: 1802000}, Attempts: []StepAttempt{{Attempt: 1}}},
+			expectEquip: true,
+		},
+		{
+			name: "Success case - unequip never requested",
+			step: Step[any]{Action: PetUnequip, Payload: PetUnequipPayload{CharacterId: 12345, PetId: 7001, Source: -14, Destination: 4}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, _ := test.NewNullLogger()
+			te, _ := tenant.Create(uuid.New(), "GMS", 83, 1)
+			tctx := tenant.WithContext(context.Background(), te)
+
+			equipped, unequipped := false, false
+			compP := &mock2.ProcessorMock{
+				RequestEquipAssetFunc: func(transactionId uuid.UUID, characterId uint32, inventoryType byte, source int16, destination int16) error {
+					equipped = true
+					// The equipment is returned from the inventory slot to the pet's slot
+					assert.Equal(t, int16(4), source)
+					assert.Equal(t, int16(-14), destination)
+					return nil
+				},
+				RequestUnequipAssetFunc: func(transactionId uuid.UUID, characterId uint32, inventoryType byte, source int16, destination int16) error {
+					unequipped = true
+					assert.Equal(t, int16(-14), source)
+					assert.Equal(t, int16(4), destination)
+					return nil
+				},
+			}
+
+			step := tt.step
+			step.StepId = "pet-step"
+			step.Status = Failed
+			saga := Saga{TransactionId: uuid.New(), SagaType: InventoryTransaction, InitiatedBy: "compensation-test", Steps: []Step[any]{step}}
+
+			err := NewCompensator(logger, tctx).WithCompartmentProcessor(compP).CompensateFailedStep(saga)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectEquip, equipped)
+			assert.Equal(t, tt.expectUnequip, unequipped)
+		})
+	}
+}
+
+// TestCompensateRestoresCharacterSnapshot tests that a failure restores the characters the saga took a snapshot of, and
+// that the receipt reports the progression they gained since as reverted
+func TestCompensateRestoresCharacterSnapshot(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+	logger.SetLevel(logrus.DebugLevel)
+
+	te, _ := setupContext()
+	tctx := tenant.WithContext(context.Background(), te)
+
+	var restored []uint32
+	charP := &mock3.ProcessorMock{
+		RestoreStatsAndEmitFunc: func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, m character.Model) error {
+			assert.Equal(t, byte(30), m.Level())
+			restored = append(restored, characterId)
+			return nil
+		},
+	}
+
+	snapshot := &CharacterSnapshot{Level: 30, Experience: 1200, Meso: 50000}
+	transactionId := uuid.New()
+	saga := Saga{
+		TransactionId: transactionId,
+		SagaType:      JobReset,
+		InitiatedBy:   "compensation-test",
+		Steps: []Step[any]{
+			{StepId: "snapshot", Status: Completed, Action: SnapshotCharacter, Payload: SnapshotCharacterPayload{CharacterId: 12345, ChannelId: 1, Snapshot: snapshot}},
+			{StepId: "level", Status: Completed, Action: AwardLevel, Payload: AwardLevelPayload{CharacterId: 12345, Amount: 1}},
+			{StepId: "job", Status: Failed, Action: ChangeJob, Payload: ChangeJobPayload{CharacterId: 12345, JobId: job.Id(0)}},
+		},
+	}
+	GetCache().Put(te.Id(), saga)
+	defer GetCache().Remove(te.Id(), transactionId)
+
+	r, ok := NewCompensationReceipt(saga, 1, nil)
+	require.True(t, ok)
+	assert.Equal(t, ReceiptEntry{StepId: "level", Action: AwardLevel, Outcome: ReceiptReverted}, r.Steps[0])
+
+	assert.NoError(t, NewCompensator(logger, tctx).WithCharacterProcessor(charP).CompensateFailedStep(saga))
+	assert.Equal(t, []uint32{12345}, restored)
+
+	// A character already restored, or whose restore failed, is not restored again
+	restored = nil
+	saga.Steps = append([]Step[any]{}, saga.Steps...)
+	saga.Steps[2] = Step[any]{StepId: "restore", Status: Failed, Action: RestoreCharacter, Payload: RestoreCharacterPayload{CharacterId: 12345}}
+	assert.NoError(t, NewCompensator(logger, tctx).WithCharacterProcessor(charP).CompensateFailedStep(saga))
+	assert.Empty(t, restored)
+}
@@ -0,0 +1,116 @@
+package saga
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/Chronicle20/atlas-constants/inventory"
+	"github.com/google/uuid"
+)
+
+// ErrAssetConflict is returned when a saga references an asset which a pending step of another active saga references
+var ErrAssetConflict = errors.New("saga references an asset in use by an active saga")
+
+// ConflictConfig configures how sagas referencing assets in use by other active sagas are handled
+type ConflictConfig struct {
+	Queue bool // Queue conflicting sagas until the sagas they conflict with finish, rather than rejecting them
+}
+
+// ConflictConfigFromEnv loads the asset conflict configuration from the environment
+func ConflictConfigFromEnv() (ConflictConfig, error) {
+	switch v := os.Getenv("SAGA_ASSET_CONFLICT"); v {
+	case "", "reject":
+		return ConflictConfig{}, nil
+	case "queue":
+		return ConflictConfig{Queue: true}, nil
+	default:
+		return ConflictConfig{}, fmt.Errorf("invalid SAGA_ASSET_CONFLICT '%s', expected reject or queue", v)
+	}
+}
+
+// Singleton asset conflict configuration, which rejects conflicting sagas until initialized
+var conflictConfig ConflictConfig
+
+// InitConflictConfig replaces the singleton asset conflict configuration
+func InitConflictConfig(config ConflictConfig) {
+	conflictConfig = config
+}
+
+// GetConflictConfig returns the singleton asset conflict configuration
+func GetConflictConfig() ConflictConfig {
+	return conflictConfig
+}
+
+// AssetRef identifies an asset by the compartment slot it occupies
+type AssetRef struct {
+	CharacterId   uint32
+	InventoryType byte
+	Slot          int16
+}
+
+func (r AssetRef) String() string {
+	return fmt.Sprintf("[%d:%d:%d]", r.CharacterId, r.InventoryType, r.Slot)
+}
+
+// AssetRefs returns the assets referenced by the saga's pending steps. Completed steps have already emitted their
+// commands, so no longer contend for the assets they referenced.
+func (s *Saga) AssetRefs() []AssetRef {
+	refs := make([]AssetRef, 0)
+	for _, st := range s.Steps {
+		if st.Status != Pending {
+			continue
+		}
+		switch payload := st.Payload.(type) {
+		case EquipAssetPayload:
+			refs = append(refs, AssetRef{payload.CharacterId, byte(payload.InventoryType), payload.Source}, AssetRef{payload.CharacterId, byte(payload.InventoryType), payload.Destination})
+		case UnequipAssetPayload:
+			refs = append(refs, AssetRef{payload.CharacterId, byte(payload.InventoryType), payload.Source}, AssetRef{payload.CharacterId, byte(payload.InventoryType), payload.Destination})
+		case ModifyInventoryItemPositionPayload:
+			refs = append(refs, AssetRef{payload.CharacterId, byte(payload.InventoryType), payload.Source}, AssetRef{payload.CharacterId, byte(payload.InventoryType), payload.Destination})
+		case SealAssetPayload:
+			refs = append(refs, AssetRef{payload.CharacterId, payload.InventoryType, payload.Slot})
+		case UnsealAssetPayload:
+			refs = append(refs, AssetRef{payload.CharacterId, payload.InventoryType, payload.Slot})
+		case ConfiscateAssetPayload:
+			refs = append(refs, AssetRef{payload.CharacterId, payload.InventoryType, payload.Slot})
+		case ApplyEquipmentPresetPayload:
+			for _, i := range payload.Items {
+				refs = append(refs, AssetRef{payload.CharacterId, byte(inventory.TypeValueEquip), i.Slot})
+			}
+		}
+	}
+	return refs
+}
+
+// FindConflicts returns the active sagas of the tenant whose pending steps reference an asset the saga references,
+// alongside a description of the first conflict. Sagas which are failing are being compensated, and are not considered.
+func FindConflicts(tenantId uuid.UUID, s Saga) ([]Saga, string) {
+	refs := s.AssetRefs()
+	if len(refs) == 0 {
+		return nil, ""
+	}
+	wanted := make(map[AssetRef]struct{}, len(refs))
+	for _, r := range refs {
+		wanted[r] = struct{}{}
+	}
+
+	var conflicts []Saga
+	reason := ""
+	for _, o := range GetCache().GetAll(tenantId) {
+		if o.TransactionId == s.TransactionId || o.Failing() {
+			continue
+		}
+		for _, r := range o.AssetRefs() {
+			if _, ok := wanted[r]; !ok {
+				continue
+			}
+			if reason == "" {
+				reason = fmt.Sprintf("asset %s is referenced by saga [%s]", r, o.TransactionId.String())
+			}
+			conflicts = append(conflicts, o)
+			break
+		}
+	}
+	return conflicts, reason
+}
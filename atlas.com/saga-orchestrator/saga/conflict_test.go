@@ -0,0 +1,84 @@
+package saga
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func equipSaga(characterId uint32, source int16, destination int16) Saga {
+	return Saga{
+		TransactionId: uuid.New(),
+		SagaType:      InventoryTransaction,
+		InitiatedBy:   "gm-alice",
+		Steps: []Step[any]{{
+			StepId:  "equip",
+			Status:  Pending,
+			Action:  EquipAsset,
+			Payload: EquipAssetPayload{CharacterId: characterId, InventoryType: 1, Source: source, Destination: destination},
+		}},
+	}
+}
+
+// TestAssetRefs tests that only the assets referenced by pending steps are considered
+func TestAssetRefs(t *testing.T) {
+	s := equipSaga(1, 5, -11)
+	s.Steps = append(s.Steps, Step[any]{
+		StepId:  "move",
+		Status:  Completed,
+		Action:  ModifyInventoryItemPosition,
+		Payload: ModifyInventoryItemPositionPayload{CharacterId: 1, InventoryType: 2, Source: 1, Destination: 2},
+	})
+	assert.Equal(t, []AssetRef{{1, 1, 5}, {1, 1, -11}}, s.AssetRefs())
+}
+
+// TestPutAssetConflict tests that sagas referencing assets in use by an active saga are rejected, or queued until it finishes
+func TestPutAssetConflict(t *testing.T) {
+	te, ctx := setupContext()
+	processor, _ := setupTestProcessor(ctx, nil, nil)
+	defer InitConflictConfig(ConflictConfig{})
+
+	active := equipSaga(1, 5, -11)
+	GetCache().Put(te.Id(), active)
+	defer GetCache().Remove(te.Id(), active.TransactionId)
+
+	t.Run("conflicting saga is rejected", func(t *testing.T) {
+		s := equipSaga(1, 7, -11)
+		err := processor.Put(s)
+		assert.ErrorIs(t, err, ErrAssetConflict)
+		assert.Contains(t, err.Error(), active.TransactionId.String())
+		_, ok := GetCache().GetById(te.Id(), s.TransactionId)
+		assert.False(t, ok)
+	})
+
+	t.Run("sagas referencing other assets or characters are unaffected", func(t *testing.T) {
+		conflicts, _ := FindConflicts(te.Id(), equipSaga(1, 7, -12))
+		assert.Empty(t, conflicts)
+		conflicts, _ = FindConflicts(te.Id(), equipSaga(2, 5, -11))
+		assert.Empty(t, conflicts)
+		conflicts, _ = FindConflicts(uuid.New(), equipSaga(1, 5, -11))
+		assert.Empty(t, conflicts)
+	})
+
+	t.Run("conflicting saga is queued until the active saga finishes", func(t *testing.T) {
+		InitConflictConfig(ConflictConfig{Queue: true})
+
+		// Requiring approval holds the queued saga once started, so its step is not dispatched
+		s := equipSaga(1, 5, -12)
+		s.RequiresApproval = true
+		defer GetCache().Remove(te.Id(), s.TransactionId)
+		assert.ErrorIs(t, processor.Put(s), ErrSagaQueued)
+		_, ok := GetCache().GetById(te.Id(), s.TransactionId)
+		assert.False(t, ok)
+
+		GetCache().Remove(te.Id(), active.TransactionId)
+		GetNotifier().Notify(te.Id(), active)
+
+		require.Eventually(t, func() bool {
+			_, ok := GetCache().GetById(te.Id(), s.TransactionId)
+			return ok
+		}, time.Second, 10*time.Millisecond)
+	})
+}
@@ -0,0 +1,93 @@
+package saga
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrInvalidDependency is returned when a step depends on a step which is not one of the saga's own, or steps depend on
+// one another in a cycle
+var ErrInvalidDependency = errors.New("invalid step dependency")
+
+// ScheduleDependencies validates the dependsOn edges declared by the saga's steps, and orders the steps topologically,
+// so each follows the steps it depends on. Steps are executed one at a time, in order, so a step is only dispatched once
+// the steps it depends on have completed. Of the steps whose dependencies have been scheduled, the one declared first
+// is scheduled next, so steps already declared in an order honouring their dependencies remain in place.
+func (s *Saga) ScheduleDependencies() error {
+	index := make(map[string]int, len(s.Steps))
+	for i, st := range s.Steps {
+		index[st.StepId] = i
+	}
+
+	declared := false
+	for _, st := range s.Steps {
+		for _, dep := range st.DependsOn {
+			declared = true
+			if dep == st.StepId {
+				return fmt.Errorf("%w: step '%s' depends on itself", ErrInvalidDependency, st.StepId)
+			}
+			if _, ok := index[dep]; !ok {
+				return fmt.Errorf("%w: step '%s' depends on step '%s', which is not one of the saga's steps", ErrInvalidDependency, st.StepId, dep)
+			}
+		}
+		if err := validateNestedDependencies(st); err != nil {
+			return err
+		}
+	}
+	if !declared {
+		return nil
+	}
+
+	scheduled := make([]bool, len(s.Steps))
+	order := make([]Step[any], 0, len(s.Steps))
+	for len(order) < len(s.Steps) {
+		next := -1
+		for i, st := range s.Steps {
+			if scheduled[i] {
+				continue
+			}
+			if !slices.ContainsFunc(st.DependsOn, func(dep string) bool { return !scheduled[index[dep]] }) {
+				next = i
+				break
+			}
+		}
+		if next == -1 {
+			cycle := make([]string, 0)
+			for i, st := range s.Steps {
+				if !scheduled[i] {
+					cycle = append(cycle, st.StepId)
+				}
+			}
+			return fmt.Errorf("%w: steps %v depend on one another in a cycle", ErrInvalidDependency, cycle)
+		}
+		scheduled[next] = true
+		order = append(order, s.Steps[next])
+	}
+	s.Steps = order
+	return nil
+}
+
+// validateNestedDependencies checks no step nested within the step, being those of its branches, error handlers and
+// for_each sub-steps, declares dependencies, as they are added to the saga only once it is executing
+func validateNestedDependencies(st Step[any]) error {
+	nested := make([]Step[any], 0)
+	for _, b := range st.Branches {
+		nested = append(nested, b.Steps...)
+	}
+	for _, h := range st.OnError {
+		nested = append(nested, h.Steps...)
+	}
+	if payload, ok := st.Payload.(ForEachPayload); ok {
+		nested = append(nested, payload.Steps...)
+	}
+	for _, ns := range nested {
+		if len(ns.DependsOn) > 0 {
+			return fmt.Errorf("%w: step '%s' nested within step '%s' may not declare dependencies", ErrInvalidDependency, ns.StepId, st.StepId)
+		}
+		if err := validateNestedDependencies(ns); err != nil {
+			return err
+		}
+	}
+	return nil
+}
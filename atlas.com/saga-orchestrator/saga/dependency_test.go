@@ -0,0 +1,120 @@
+package saga
+
+import (
+	"atlas-saga-orchestrator/character/mock"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestScheduleDependencies tests that steps are ordered so each follows the steps it depends on, and that dependencies
+// which cannot be honoured are rejected
+func TestScheduleDependencies(t *testing.T) {
+	step := func(id string, dependsOn ...string) Step[any] {
+		return Step[any]{StepId: id, Status: Pending, Action: AwardMesos, Payload: AwardMesosPayload{CharacterId: 12345, Amount: 100}, DependsOn: dependsOn}
+	}
+
+	tests := []struct {
+		name          string
+		steps         []Step[any]
+		expected      []string
+		errorContains string
+	}{
+		{
+			name:     "Steps without dependencies keep their order",
+			steps:    []Step[any]{step("a"), step("b"), step("c")},
+			expected: []string{"a", "b", "c"},
+		},
+		{
+			name:     "Steps honouring their dependencies keep their order",
+			steps:    []Step[any]{step("a"), step("b", "a"), step("c", "a", "b")},
+			expected: []string{"a", "b", "c"},
+		},
+		{
+			name:     "Steps follow the steps they depend on",
+			steps:    []Step[any]{step("award", "charge"), step("notify", "award"), step("charge"), step("log")},
+			expected: []string{"charge", "award", "notify", "log"},
+		},
+		{
+			name:     "Diamonds are scheduled in declaration order",
+			steps:    []Step[any]{step("join", "left", "right"), step("right", "fork"), step("left", "fork"), step("fork")},
+			expected: []string{"fork", "right", "left", "join"},
+		},
+		{
+			name:          "Self dependency",
+			steps:         []Step[any]{step("a", "a")},
+			errorContains: "step 'a' depends on itself",
+		},
+		{
+			name:          "Unknown dependency",
+			steps:         []Step[any]{step("a", "missing")},
+			errorContains: "step 'a' depends on step 'missing', which is not one of the saga's steps",
+		},
+		{
+			name:          "Cycle",
+			steps:         []Step[any]{step("a"), step("b", "d"), step("c", "b"), step("d", "c")},
+			errorContains: "steps [b c d] depend on one another in a cycle",
+		},
+		{
+			name: "Nested dependency",
+			steps: []Step[any]{
+				step("a"),
+				{StepId: "b", Status: Pending, Action: SetVariable, Payload: SetVariablePayload{}, Branches: []Branch{{Name: "default", Steps: []Step[any]{step("c", "a")}}}},
+			},
+			errorContains: "step 'c' nested within step 'b' may not declare dependencies",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Saga{SagaType: QuestReward, Steps: tt.steps}
+			err := s.ScheduleDependencies()
+			if tt.errorContains != "" {
+				require.ErrorIs(t, err, ErrInvalidDependency)
+				assert.Contains(t, err.Error(), tt.errorContains)
+				return
+			}
+			require.NoError(t, err)
+			ids := make([]string, 0, len(s.Steps))
+			for _, st := range s.Steps {
+				ids = append(ids, st.StepId)
+			}
+			assert.Equal(t, tt.expected, ids)
+		})
+	}
+}
+
+// TestPutSchedulesDependencies tests that sagas are scheduled by their dependencies when created, and rejected when
+// their dependencies cannot be honoured
+func TestPutSchedulesDependencies(t *testing.T) {
+	te, ctx := setupContext()
+	processor, _ := setupTestProcessor(ctx, &mock.ProcessorMock{}, nil)
+
+	s := NewBuilder().
+		SetSagaType(QuestReward).
+		AddStep("job", Pending, ChangeJob, ChangeJobPayload{CharacterId: 12345, JobId: 100}).
+		AddDependency("level").
+		AddStep("level", Pending, AwardLevel, AwardLevelPayload{CharacterId: 12345, Amount: 9}).
+		Build()
+	defer GetCache().Remove(te.Id(), s.TransactionId)
+	require.NoError(t, processor.Put(s))
+
+	cs, err := processor.GetById(s.TransactionId)
+	require.NoError(t, err)
+	require.Len(t, cs.Steps, 2)
+	assert.Equal(t, "level", cs.Steps[0].StepId)
+	assert.Equal(t, "job", cs.Steps[1].StepId)
+	assert.Equal(t, []string{"level"}, cs.Steps[1].DependsOn)
+
+	cyclic := NewBuilder().
+		SetSagaType(QuestReward).
+		AddStep("job", Pending, ChangeJob, ChangeJobPayload{CharacterId: 12345, JobId: 100}).
+		AddDependency("level").
+		AddStep("level", Pending, AwardLevel, AwardLevelPayload{CharacterId: 12345, Amount: 9}).
+		AddDependency("job").
+		Build()
+	assert.ErrorIs(t, processor.Put(cyclic), ErrInvalidDependency)
+	_, err = processor.GetById(cyclic.TransactionId)
+	assert.Error(t, err)
+}
@@ -0,0 +1,85 @@
+package saga
+
+import (
+	"atlas-saga-orchestrator/cluster"
+	"atlas-saga-orchestrator/kafka/message/saga"
+	"atlas-saga-orchestrator/kafka/producer"
+	"context"
+	"time"
+
+	tenant "github.com/Chronicle20/atlas-tenant"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// dueAt returns when the saga's current step is next due to be acted on without an event arriving, being when it is
+// redelivered should it be parked, or otherwise when it times out. Sagas which are compensating have no such step.
+func (s Saga) dueAt() (time.Time, bool) {
+	if s.Failing() {
+		return time.Time{}, false
+	}
+	st, ok := s.GetCurrentStep()
+	if !ok || len(st.Attempts) == 0 {
+		return time.Time{}, false
+	}
+	if a := st.Attempts[len(st.Attempts)-1]; a.RetryAt != nil {
+		return *a.RetryAt, true
+	}
+	if s.Held() {
+		return time.Time{}, false
+	}
+	return st.Deadline()
+}
+
+// forwardDue has the replicas owning the archived sagas with a step due, as found by the function, act on them. The
+// replica leading several finds the steps due across them from the archive, while only the replica owning a saga acts on
+// it, so the sagas this replica owns are left to it. Returns the number of steps forwarded.
+func forwardDue(l logrus.FieldLogger, ctx context.Context, now time.Time, dueType string, due func(s Saga, now time.Time) (Step[any], bool)) (int, error) {
+	a, ok := GetArchive()
+	if !ok || !cluster.GetMembership().Enabled() {
+		return 0, nil
+	}
+	sagas, err := a.GetDue(now)
+	if err != nil {
+		return 0, err
+	}
+	forwarded := 0
+	for t, ss := range sagas {
+		pp := producer.ProviderImpl(l)(tenant.WithContext(ctx, t))(saga.EnvCommandTopicStepDue)
+		for _, s := range ss {
+			if cluster.GetMembership().Owns(s.TransactionId) {
+				continue
+			}
+			st, ok := due(s, now)
+			if !ok {
+				continue
+			}
+			if err = pp(StepDueCommandProvider(s.TransactionId, st.StepId, dueType)); err != nil {
+				l.WithError(err).WithFields(logrus.Fields{
+					"transaction_id": s.TransactionId.String(),
+					"step_id":        st.StepId,
+					"tenant_id":      t.Id().String(),
+				}).Error("Unable to forward due saga step to the replica owning it.")
+				continue
+			}
+			forwarded++
+		}
+	}
+	return forwarded, nil
+}
+
+// StepDue acts on the step of a saga owned by this replica, as found due by the replica leading the role finding such
+// steps. The saga's state is checked again, so a step which has since progressed is left alone.
+func StepDue(l logrus.FieldLogger, ctx context.Context, transactionId uuid.UUID, stepId string, dueType string) {
+	t := tenant.MustFromContext(ctx)
+	p := NewProcessor(l, ctx)
+	now := time.Now()
+	switch dueType {
+	case saga.StepDueTypeTimedOut:
+		expireStep(l, p, t.Id(), transactionId, stepId, now)
+	case saga.StepDueTypeRedelivery:
+		redeliverDue(l, p, t, transactionId, stepId, now)
+	default:
+		l.Warnf("Ignoring saga step due for unknown reason [%s].", dueType)
+	}
+}
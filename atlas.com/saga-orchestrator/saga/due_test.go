@@ -0,0 +1,140 @@
+package saga
+
+import (
+	"atlas-saga-orchestrator/cluster"
+	"atlas-saga-orchestrator/kafka/message/saga"
+	"atlas-saga-orchestrator/kafka/producer"
+	"encoding/json"
+	"testing"
+	"time"
+
+	producer2 "github.com/Chronicle20/atlas-kafka/producer"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dueSaga builds a saga whose current step was dispatched at the given time, with a 30 second timeout
+func dueSaga(t *testing.T, transactionId uuid.UUID, dispatchedAt time.Time) Saga {
+	s := NewBuilder().
+		SetTransactionId(transactionId).
+		SetSagaType(QuestReward).
+		AddStep("mesos", Pending, AwardMesos, AwardMesosPayload{CharacterId: 12345, ActorType: "NPC", Amount: 1000}).
+		SetTimeout(30).
+		Build()
+	_, err := s.RecordStepAttempt(0, dispatchedAt)
+	require.NoError(t, err)
+	return s
+}
+
+// TestSagaDueAt tests when the current step of a saga is next due, being its redelivery when parked, or otherwise its
+// timeout
+func TestSagaDueAt(t *testing.T) {
+	now := time.Now()
+	retryAt := now.Add(time.Second)
+
+	tests := []struct {
+		name   string
+		modify func(s *Saga)
+		dueAt  time.Time
+		ok     bool
+	}{
+		{name: "dispatched", modify: func(s *Saga) {}, dueAt: now.Add(30 * time.Second), ok: true},
+		{name: "not dispatched", modify: func(s *Saga) { s.Steps[0].Attempts = nil }},
+		{name: "parked", modify: func(s *Saga) { s.Steps[0].Attempts[0].RetryAt = &retryAt }, dueAt: retryAt, ok: true},
+		{name: "held", modify: func(s *Saga) { s.Hold = PendingApproval }},
+		{name: "held while parked", modify: func(s *Saga) {
+			s.Hold = PendingApproval
+			s.Steps[0].Attempts[0].RetryAt = &retryAt
+		}, dueAt: retryAt, ok: true},
+		{name: "compensating", modify: func(s *Saga) { s.Steps[0].Status = Failed }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := dueSaga(t, uuid.New(), now)
+			tt.modify(&s)
+			dueAt, ok := s.dueAt()
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.dueAt, dueAt)
+		})
+	}
+}
+
+// TestForwardDue tests that the replica leading several forwards the steps found due in the archive to the replicas
+// owning their sagas, leaving those it owns to itself
+func TestForwardDue(t *testing.T) {
+	te, ctx := setupContext()
+	l, _ := test.NewNullLogger()
+	defer cluster.InitMembership(cluster.Config{})
+	defer InitArchive(nil)
+
+	forwarded := make([]saga.StepDueCommand, 0)
+	ctx = producer.WithProvider(ctx, func(token string) producer2.MessageProducer {
+		return func(provider model.Provider[[]kafka.Message]) error {
+			ms, err := provider()
+			if err != nil {
+				return err
+			}
+			for _, m := range ms {
+				if token == saga.EnvCommandTopicStepDue {
+					var c saga.StepDueCommand
+					require.NoError(t, json.Unmarshal(m.Value, &c))
+					forwarded = append(forwarded, c)
+				}
+			}
+			return nil
+		}
+	})
+
+	now := time.Now()
+	owned := func(replicaId string) uuid.UUID {
+		for {
+			id := uuid.New()
+			if cluster.GetMembership().Owner(id) == replicaId {
+				return id
+			}
+		}
+	}
+	cluster.InitMembership(cluster.Config{ReplicaId: "a", HeartbeatInterval: time.Second, MemberTtl: 3 * time.Second})
+	cluster.GetMembership().Observe("b", now)
+	cluster.GetMembership().Announced(time.Now())
+	cluster.GetMembership().Settle(time.Now().Add(time.Second))
+
+	others := dueSaga(t, owned("b"), now.Add(-time.Minute))
+	own := dueSaga(t, owned("a"), now.Add(-time.Minute))
+	pending := dueSaga(t, owned("b"), now)
+	InitArchive(&testArchive{t: te, sagas: map[uuid.UUID]Saga{
+		others.TransactionId:  others,
+		own.TransactionId:     own,
+		pending.TransactionId: pending,
+	}})
+
+	t.Run("steps of other replicas are forwarded", func(t *testing.T) {
+		forwarded = forwarded[:0]
+		n, err := forwardDue(l, ctx, now, saga.StepDueTypeTimedOut, timedOut)
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+		require.Len(t, forwarded, 1)
+		assert.Equal(t, saga.StepDueCommand{TransactionId: others.TransactionId, StepId: "mesos", Type: saga.StepDueTypeTimedOut}, forwarded[0])
+	})
+
+	t.Run("steps not due by the function are not forwarded", func(t *testing.T) {
+		forwarded = forwarded[:0]
+		n, err := forwardDue(l, ctx, now, saga.StepDueTypeRedelivery, redeliveryDue)
+		require.NoError(t, err)
+		assert.Equal(t, 0, n)
+		assert.Empty(t, forwarded)
+	})
+
+	t.Run("a single replica forwards nothing", func(t *testing.T) {
+		forwarded = forwarded[:0]
+		cluster.InitMembership(cluster.Config{})
+		n, err := forwardDue(l, ctx, now, saga.StepDueTypeTimedOut, timedOut)
+		require.NoError(t, err)
+		assert.Equal(t, 0, n)
+		assert.Empty(t, forwarded)
+	})
+}
@@ -0,0 +1,82 @@
+package saga
+
+import (
+	"context"
+
+	tenant "github.com/Chronicle20/atlas-tenant"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// Error codes an await_escort step fails with, so that its error handlers may declare a fallback or retry. Other
+// failures of the escort are reported with the error code of the NPC service's reason.
+const (
+	ErrorCodeEscortDied    = "ESCORT_DIED"    // The escort was killed before arriving
+	ErrorCodeEscortTimeout = "ESCORT_TIMEOUT" // The escort did not arrive within the step's timeout
+)
+
+// awaitingEscort returns the saga whose current step awaits its escort's arrival. Sagas which are compensating, or await
+// anything else, such as their escort being spawned, are not returned.
+func awaitingEscort(p Processor, transactionId uuid.UUID) (Saga, Step[any], bool) {
+	s, err := p.GetById(transactionId)
+	if err != nil {
+		return Saga{}, Step[any]{}, false
+	}
+	st, ok := s.GetCurrentStep()
+	if !ok || s.Failing() || st.Action != AwaitEscort {
+		return Saga{}, Step[any]{}, false
+	}
+	return s, st, true
+}
+
+// EscortArrived completes the await_escort step of the saga whose escort arrived at its destination
+func (p *ProcessorImpl) EscortArrived(transactionId uuid.UUID, event any) error {
+	s, st, ok := awaitingEscort(p, transactionId)
+	if !ok {
+		return nil
+	}
+	p.l.WithFields(logrus.Fields{
+		"transaction_id": transactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        st.StepId,
+		"tenant_id":      p.t.Id().String(),
+	}).Debug("Escort arrived. Completing step.")
+	return p.StepCompletedWithEvent(transactionId, event)
+}
+
+// EscortFailed fails the await_escort step of the saga whose escort failed for the reason, reacting as declared by the
+// step's error handlers
+func (p *ProcessorImpl) EscortFailed(transactionId uuid.UUID, reason string) error {
+	s, st, ok := awaitingEscort(p, transactionId)
+	if !ok {
+		return nil
+	}
+	p.l.WithFields(logrus.Fields{
+		"transaction_id": transactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        st.StepId,
+		"tenant_id":      p.t.Id().String(),
+	}).Debugf("Escort failed with reason [%s]. Failing step.", reason)
+	return p.StepFailed(transactionId, reason, "escort failed")
+}
+
+// escortExpired fails an await_escort step whose escort did not arrive within its timeout, reacting as declared by the
+// step's error handlers. Steps which have since completed, or sagas which are compensating, are left alone.
+func escortExpired(l logrus.FieldLogger, ctx context.Context, transactionId uuid.UUID, stepId string) {
+	p := NewProcessor(l, ctx)
+	s, st, ok := awaitingEscort(p, transactionId)
+	if !ok || st.StepId != stepId {
+		return
+	}
+
+	fl := l.WithFields(logrus.Fields{
+		"transaction_id": transactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        stepId,
+		"tenant_id":      tenant.MustFromContext(ctx).Id().String(),
+	})
+	fl.Debug("Escort did not arrive before timeout. Failing step.")
+	if err := p.StepFailed(transactionId, ErrorCodeEscortTimeout, "escort did not arrive before timeout"); err != nil {
+		fl.WithError(err).Error("Unable to apply escort timeout.")
+	}
+}
@@ -0,0 +1,132 @@
+package saga
+
+import (
+	npc2 "atlas-saga-orchestrator/kafka/message/npc"
+	"atlas-saga-orchestrator/kafka/producer"
+	"encoding/json"
+	"github.com/Chronicle20/atlas-constants/field"
+	producer2 "github.com/Chronicle20/atlas-kafka/producer"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestAwaitEscort tests that an await_escort step completes on its escort's arrival, respawns the escort when retried
+// after it dies, and fails when its timeout elapses first
+func TestAwaitEscort(t *testing.T) {
+	te, ctx := setupContext()
+	defer ResetTimerRegistry()
+
+	var mutex sync.Mutex
+	commands := make([]string, 0)
+	ctx = producer.WithProvider(ctx, func(token string) producer2.MessageProducer {
+		return func(provider model.Provider[[]kafka.Message]) error {
+			ms, err := provider()
+			if err != nil {
+				return err
+			}
+			for _, m := range ms {
+				var c npc2.Command[json.RawMessage]
+				if token == npc2.EnvCommandTopic && json.Unmarshal(m.Value, &c) == nil {
+					mutex.Lock()
+					commands = append(commands, c.Type)
+					mutex.Unlock()
+				}
+			}
+			return nil
+		}
+	})
+	sent := func() []string {
+		mutex.Lock()
+		defer mutex.Unlock()
+		r := append([]string{}, commands...)
+		commands = commands[:0]
+		return r
+	}
+	processor, _ := setupTestProcessor(ctx, nil, nil)
+
+	fieldId := field.Id("0:1:100000000:00000000-0000-0000-0000-000000000000")
+	spawn := SpawnEscortPayload{CharacterId: 12345, FieldId: fieldId, NpcId: 1012112, DestinationMapId: 100000001}
+	await := AwaitEscortPayload{CharacterId: 12345, FieldId: fieldId, NpcId: 1012112, Timeout: 60}
+	build := func(spawned Status) Saga {
+		return NewBuilder().
+			SetSagaType(EscortQuest).
+			AddStep("spawn", spawned, SpawnEscort, spawn).
+			AddStep("escort", Pending, AwaitEscort, await).
+			AddErrorHandler(ErrorHandler{
+				ErrorCode: ErrorCodeEscortDied,
+				Reaction:  ErrorReactionRetry,
+				Steps:     []Step[any]{{StepId: "respawn", Action: SpawnEscort, Payload: spawn}},
+			}).
+			AddStep("done", Pending, SetVariable, SetVariablePayload{Name: "done", Value: true}).
+			Build()
+	}
+
+	t.Run("arrival completes the step", func(t *testing.T) {
+		s := build(Completed)
+		done, unsubscribe := GetNotifier().Subscribe(te.Id(), s.TransactionId)
+		defer unsubscribe()
+		require.NoError(t, processor.Put(s))
+		defer GetCache().Remove(te.Id(), s.TransactionId)
+
+		deadline, ok := GetTimerRegistry().Deadline(te.Id(), s.TransactionId, "escort")
+		require.True(t, ok)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, 5*time.Second)
+
+		require.NoError(t, processor.EscortArrived(s.TransactionId, npc2.StatusEvent[npc2.StatusEventEscortArrivedBody]{TransactionId: s.TransactionId}))
+		select {
+		case s = <-done:
+		case <-time.After(time.Second):
+			t.Fatal("saga did not complete")
+		}
+		assert.Equal(t, Completed, s.Steps[1].Status)
+		assert.Equal(t, true, s.Variables["done"])
+	})
+
+	t.Run("arrival is ignored while spawning", func(t *testing.T) {
+		s := build(Pending)
+		require.NoError(t, processor.Put(s))
+		defer GetCache().Remove(te.Id(), s.TransactionId)
+		assert.Equal(t, []string{npc2.CommandTypeSpawnEscort}, sent())
+
+		require.NoError(t, processor.EscortArrived(s.TransactionId, nil))
+		s, err := processor.GetById(s.TransactionId)
+		require.NoError(t, err)
+		assert.Equal(t, Pending, s.Steps[0].Status)
+		assert.Equal(t, Pending, s.Steps[1].Status)
+	})
+
+	t.Run("death respawns the escort", func(t *testing.T) {
+		s := build(Completed)
+		require.NoError(t, processor.Put(s))
+		defer GetCache().Remove(te.Id(), s.TransactionId)
+
+		require.NoError(t, processor.EscortFailed(s.TransactionId, ErrorCodeEscortDied))
+		s, err := processor.GetById(s.TransactionId)
+		require.NoError(t, err)
+		require.Len(t, s.Steps, 4)
+		assert.Equal(t, "respawn_retry1", s.Steps[1].StepId)
+		assert.Equal(t, []string{npc2.CommandTypeSpawnEscort}, sent())
+	})
+
+	t.Run("timeout despawns the escort", func(t *testing.T) {
+		s := build(Completed)
+		done, unsubscribe := GetNotifier().Subscribe(te.Id(), s.TransactionId)
+		defer unsubscribe()
+		require.NoError(t, processor.Put(s))
+		defer GetCache().Remove(te.Id(), s.TransactionId)
+
+		escortExpired(processor.(*ProcessorImpl).l, ctx, s.TransactionId, "escort")
+		select {
+		case s = <-done:
+		case <-time.After(time.Second):
+			t.Fatal("saga did not fail")
+		}
+		assert.Equal(t, ErrorCodeEscortTimeout, s.Steps[1].Attempts[len(s.Steps[1].Attempts)-1].ErrorCode)
+		assert.Equal(t, []string{npc2.CommandTypeDespawnEscort}, sent())
+	})
+}
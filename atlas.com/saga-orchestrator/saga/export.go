@@ -0,0 +1,267 @@
+package saga
+
+import (
+	"atlas-saga-orchestrator/metrics"
+	"atlas-saga-orchestrator/objectstore"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// Results of exporting a finished saga, by which exported sagas are counted
+const (
+	ExportExported = "exported" // The saga was written to the object store
+	ExportDropped  = "dropped"  // The saga was dropped, as more sagas awaited export than are held
+)
+
+// ExportContentType is the content type of the objects sagas are exported in
+const ExportContentType = "application/x-ndjson"
+
+// ExportConfig configures the export of finished sagas to object storage, for retention beyond the archive
+type ExportConfig struct {
+	Interval   time.Duration      // Interval at which finished sagas are exported
+	Prefix     string             // Prefix of the keys of exported objects
+	BatchSize  int                // Most sagas written to a single object
+	MaxPending int                // Most finished sagas held awaiting export, beyond which the oldest are dropped
+	Store      objectstore.Config // Store exported objects are written to. When it has no bucket, sagas are not exported.
+}
+
+// DefaultExportConfig is the export configuration used when none is configured
+var DefaultExportConfig = ExportConfig{Interval: 5 * time.Minute, Prefix: "sagas", BatchSize: 1000, MaxPending: 10000, Store: objectstore.Config{Region: "us-east-1"}}
+
+// ExportConfigFromEnv loads the export configuration from the environment. Credentials fall back to the standard AWS
+// environment variables.
+func ExportConfigFromEnv() (ExportConfig, error) {
+	c := DefaultExportConfig
+	c.Store.Bucket = os.Getenv("SAGA_EXPORT_BUCKET")
+	c.Store.AccessKeyId = os.Getenv("SAGA_EXPORT_ACCESS_KEY_ID")
+	if c.Store.AccessKeyId == "" {
+		c.Store.AccessKeyId = os.Getenv("AWS_ACCESS_KEY_ID")
+	}
+	c.Store.SecretAccessKey = os.Getenv("SAGA_EXPORT_SECRET_ACCESS_KEY")
+	if c.Store.SecretAccessKey == "" {
+		c.Store.SecretAccessKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+	}
+	if v := os.Getenv("SAGA_EXPORT_REGION"); v != "" {
+		c.Store.Region = v
+	}
+	c.Store.Endpoint = os.Getenv("SAGA_EXPORT_ENDPOINT")
+	if c.Store.Endpoint == "" {
+		c.Store.Endpoint = "https://s3." + c.Store.Region + ".amazonaws.com"
+	}
+	if v, ok := os.LookupEnv("SAGA_EXPORT_PREFIX"); ok {
+		c.Prefix = strings.Trim(v, "/")
+	}
+	if v, ok := os.LookupEnv("SAGA_EXPORT_INTERVAL"); ok && v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return ExportConfig{}, fmt.Errorf("invalid SAGA_EXPORT_INTERVAL '%s'", v)
+		}
+		c.Interval = d
+	}
+	if v, ok := os.LookupEnv("SAGA_EXPORT_BATCH_SIZE"); ok && v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return ExportConfig{}, fmt.Errorf("invalid SAGA_EXPORT_BATCH_SIZE '%s'", v)
+		}
+		c.BatchSize = n
+	}
+	if v, ok := os.LookupEnv("SAGA_EXPORT_MAX_PENDING"); ok && v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return ExportConfig{}, fmt.Errorf("invalid SAGA_EXPORT_MAX_PENDING '%s'", v)
+		}
+		c.MaxPending = n
+	}
+	if c.Enabled() && (c.Store.AccessKeyId == "" || c.Store.SecretAccessKey == "") {
+		return ExportConfig{}, fmt.Errorf("SAGA_EXPORT_BUCKET requires SAGA_EXPORT_ACCESS_KEY_ID and SAGA_EXPORT_SECRET_ACCESS_KEY")
+	}
+	return c, nil
+}
+
+// Enabled returns whether finished sagas are exported
+func (c ExportConfig) Enabled() bool {
+	return c.Store.Bucket != ""
+}
+
+var exportConfig = ExportConfig{}
+
+// InitExportConfig sets the singleton export configuration
+func InitExportConfig(c ExportConfig) {
+	exportConfig = c
+}
+
+// GetExportConfig returns the singleton export configuration
+func GetExportConfig() ExportConfig {
+	return exportConfig
+}
+
+// ExportRecord is a line of an exported object: the history of a finished saga, as it finished
+type ExportRecord struct {
+	TenantId   uuid.UUID `json:"tenantId"`   // Tenant of the saga
+	Outcome    string    `json:"outcome"`    // Outcome of the saga, being completed or compensated
+	FinishedAt time.Time `json:"finishedAt"` // Timestamp the saga finished
+	Saga       Saga      `json:"saga"`       // Saga, including each attempt of its steps and its compensation receipt, if any
+}
+
+// exportLog holds the finished sagas awaiting export
+type exportLog struct {
+	mutex   sync.Mutex
+	pending []ExportRecord
+}
+
+var elog *exportLog
+var elogOnce sync.Once
+
+// getExportLog returns the singleton export log
+func getExportLog() *exportLog {
+	elogOnce.Do(func() {
+		elog = &exportLog{pending: make([]ExportRecord, 0)}
+	})
+	return elog
+}
+
+var exported *metrics.Counter
+var exportedOnce sync.Once
+
+// getExported returns the counter of finished sagas exported, by tenant and result
+func getExported() *metrics.Counter {
+	exportedOnce.Do(func() {
+		exported = metrics.GetRegistry().RegisterCounter("saga_exported_total", "Number of finished sagas exported to object storage, by result.")
+	})
+	return exported
+}
+
+// RecordFinished records the finished saga of the tenant for export, when sagas are exported. A shadow replica exports
+// nothing, as the active replicas export the sagas they finished.
+func RecordFinished(tenantId uuid.UUID, s Saga, outcome string) {
+	if !GetExportConfig().Enabled() || GetShadowConfig().Enabled {
+		return
+	}
+	getExportLog().requeue([]ExportRecord{{TenantId: tenantId, Outcome: outcome, FinishedAt: time.Now(), Saga: s}}, GetExportConfig().MaxPending, false)
+}
+
+// requeue adds the records to those awaiting export, before them when they are being retried, so records are exported
+// in the order sagas finished. Should more than the most held await export, the oldest are dropped.
+func (el *exportLog) requeue(rs []ExportRecord, max int, retry bool) {
+	el.mutex.Lock()
+	defer el.mutex.Unlock()
+	if retry {
+		el.pending = append(append(make([]ExportRecord, 0, len(rs)+len(el.pending)), rs...), el.pending...)
+	} else {
+		el.pending = append(el.pending, rs...)
+	}
+	if n := len(el.pending) - max; max > 0 && n > 0 {
+		for _, r := range el.pending[:n] {
+			getExported().Inc(map[string]string{"tenant_id": r.TenantId.String(), "result": ExportDropped})
+		}
+		el.pending = el.pending[n:]
+	}
+}
+
+// take removes and returns the records awaiting export
+func (el *exportLog) take() []ExportRecord {
+	el.mutex.Lock()
+	defer el.mutex.Unlock()
+	r := el.pending
+	el.pending = make([]ExportRecord, 0)
+	return r
+}
+
+// Exporter writes finished sagas to object storage as newline-delimited JSON, so their histories are retained beyond
+// the archive for compliance. Each object holds the sagas of a single tenant, under
+// <prefix>/tenant=<tenantId>/date=<yyyy-mm-dd>/, dated by its export. Sagas are recorded by the replica finishing them, so
+// each replica exports its own.
+type Exporter struct {
+	l      logrus.FieldLogger
+	config ExportConfig
+	store  objectstore.Store
+}
+
+// NewExporter creates a task exporting finished sagas to the store at the configured interval
+func NewExporter(l logrus.FieldLogger, c ExportConfig, store objectstore.Store) *Exporter {
+	getExported()
+	return &Exporter{l: l, config: c, store: store}
+}
+
+func (r *Exporter) Run() {
+	r.export(time.Now())
+}
+
+// export writes the sagas awaiting export in batches, by tenant. Batches which cannot be written, and those after them,
+// are retried at the next interval. Returns the number of sagas exported.
+func (r *Exporter) export(now time.Time) int {
+	el := getExportLog()
+	rs := el.take()
+	if len(rs) == 0 {
+		return 0
+	}
+
+	byTenant := make(map[uuid.UUID][]ExportRecord)
+	tenants := make([]uuid.UUID, 0)
+	for _, rec := range rs {
+		if _, ok := byTenant[rec.TenantId]; !ok {
+			tenants = append(tenants, rec.TenantId)
+		}
+		byTenant[rec.TenantId] = append(byTenant[rec.TenantId], rec)
+	}
+	sort.Slice(tenants, func(i, j int) bool {
+		return tenants[i].String() < tenants[j].String()
+	})
+
+	count := 0
+	failed := make([]ExportRecord, 0)
+	for _, t := range tenants {
+		trs := byTenant[t]
+		for start := 0; start < len(trs); start += r.config.BatchSize {
+			batch := trs[start:min(start+r.config.BatchSize, len(trs))]
+			if len(failed) > 0 {
+				failed = append(failed, batch...)
+				continue
+			}
+			key, err := r.write(t, batch, now)
+			if err != nil {
+				r.l.WithError(err).WithField("tenant_id", t.String()).Errorf("Unable to export [%d] finished sagas. Retrying next interval.", len(batch))
+				failed = append(failed, batch...)
+				continue
+			}
+			count += len(batch)
+			getExported().Add(map[string]string{"tenant_id": t.String(), "result": ExportExported}, float64(len(batch)))
+			r.l.WithField("tenant_id", t.String()).Debugf("Exported [%d] finished sagas to [%s].", len(batch), key)
+		}
+	}
+	if len(failed) > 0 {
+		el.requeue(failed, r.config.MaxPending, true)
+	}
+	return count
+}
+
+// write writes the records of the tenant as an object, returning its key
+func (r *Exporter) write(tenantId uuid.UUID, rs []ExportRecord, now time.Time) (string, error) {
+	buf := bytes.Buffer{}
+	enc := json.NewEncoder(&buf)
+	for _, rec := range rs {
+		if err := enc.Encode(rec); err != nil {
+			return "", err
+		}
+	}
+	key := fmt.Sprintf("tenant=%s/date=%s/%s-%s.ndjson", tenantId, now.UTC().Format("2006-01-02"), now.UTC().Format("20060102T150405Z"), uuid.New())
+	if r.config.Prefix != "" {
+		key = r.config.Prefix + "/" + key
+	}
+	return key, r.store.Put(context.Background(), key, buf.Bytes(), ExportContentType)
+}
+
+func (r *Exporter) SleepTime() time.Duration {
+	return r.config.Interval
+}
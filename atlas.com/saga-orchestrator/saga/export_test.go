@@ -0,0 +1,172 @@
+package saga
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// storedObject is an object written to a testStore
+type storedObject struct {
+	key         string
+	body        []byte
+	contentType string
+}
+
+// testStore records the objects written to it, or fails writes while err is set
+type testStore struct {
+	objects []storedObject
+	err     error
+}
+
+func (s *testStore) Put(_ context.Context, key string, body []byte, contentType string) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.objects = append(s.objects, storedObject{key: key, body: body, contentType: contentType})
+	return nil
+}
+
+// records decodes the lines of the object
+func (o storedObject) records(t *testing.T) []ExportRecord {
+	r := make([]ExportRecord, 0)
+	sc := bufio.NewScanner(bytes.NewReader(o.body))
+	for sc.Scan() {
+		var rec ExportRecord
+		require.NoError(t, json.Unmarshal(sc.Bytes(), &rec))
+		r = append(r, rec)
+	}
+	return r
+}
+
+// TestExportConfigFromEnv tests loading the export configuration from the environment
+func TestExportConfigFromEnv(t *testing.T) {
+	c, err := ExportConfigFromEnv()
+	require.NoError(t, err)
+	assert.False(t, c.Enabled())
+
+	t.Setenv("SAGA_EXPORT_BUCKET", "saga-history")
+	_, err = ExportConfigFromEnv()
+	assert.Error(t, err)
+
+	t.Setenv("SAGA_EXPORT_ACCESS_KEY_ID", "key")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("SAGA_EXPORT_REGION", "eu-west-1")
+	t.Setenv("SAGA_EXPORT_PREFIX", "/compliance/sagas/")
+	t.Setenv("SAGA_EXPORT_BATCH_SIZE", "500")
+	c, err = ExportConfigFromEnv()
+	require.NoError(t, err)
+	assert.True(t, c.Enabled())
+	assert.Equal(t, "https://s3.eu-west-1.amazonaws.com", c.Store.Endpoint)
+	assert.Equal(t, "secret", c.Store.SecretAccessKey)
+	assert.Equal(t, "compliance/sagas", c.Prefix)
+	assert.Equal(t, 500, c.BatchSize)
+
+	t.Setenv("SAGA_EXPORT_MAX_PENDING", "0")
+	_, err = ExportConfigFromEnv()
+	assert.Error(t, err)
+}
+
+// TestExporter tests that finished sagas are written in batches, by tenant, as newline-delimited JSON, that batches which
+// cannot be written are retried, and that the oldest sagas are dropped should too many await export
+func TestExporter(t *testing.T) {
+	config := ExportConfig{Interval: time.Minute, Prefix: "sagas", BatchSize: 2, MaxPending: 4}
+	config.Store.Bucket = "saga-history"
+	InitExportConfig(config)
+	defer InitExportConfig(ExportConfig{})
+	getExportLog().take()
+	defer getExportLog().take()
+
+	tenantA := uuid.MustParse("00000000-0000-0000-0000-00000000000a")
+	tenantB := uuid.MustParse("00000000-0000-0000-0000-00000000000b")
+	finished := func(tenantId uuid.UUID) Saga {
+		s := NewBuilder().
+			SetSagaType(QuestReward).
+			AddStep("mesos", Completed, AwardMesos, AwardMesosPayload{CharacterId: 12345, ActorType: "NPC", Amount: 1000}).
+			Build()
+		RecordFinished(tenantId, s, OutcomeCompleted)
+		return s
+	}
+	now := time.Date(2025, time.October, 31, 12, 0, 0, 0, time.UTC)
+
+	t.Run("sagas are written in batches by tenant", func(t *testing.T) {
+		first := finished(tenantA)
+		finished(tenantB)
+		finished(tenantA)
+		finished(tenantA)
+
+		store := &testStore{}
+		l, _ := test.NewNullLogger()
+		assert.Equal(t, 4, NewExporter(l, config, store).export(now))
+		require.Len(t, store.objects, 3)
+		for i, tenantId := range []uuid.UUID{tenantA, tenantA, tenantB} {
+			assert.True(t, strings.HasPrefix(store.objects[i].key, "sagas/tenant="+tenantId.String()+"/date=2025-10-31/20251031T120000Z-"))
+			assert.True(t, strings.HasSuffix(store.objects[i].key, ".ndjson"))
+			assert.Equal(t, ExportContentType, store.objects[i].contentType)
+		}
+
+		rs := store.objects[0].records(t)
+		require.Len(t, rs, 2)
+		assert.Equal(t, tenantA, rs[0].TenantId)
+		assert.Equal(t, OutcomeCompleted, rs[0].Outcome)
+		assert.Equal(t, first.TransactionId, rs[0].Saga.TransactionId)
+		require.Len(t, rs[0].Saga.Steps, 1)
+		assert.Equal(t, AwardMesosPayload{CharacterId: 12345, ActorType: "NPC", Amount: 1000}, rs[0].Saga.Steps[0].Payload)
+		assert.Len(t, store.objects[1].records(t), 1)
+
+		assert.Equal(t, 0, NewExporter(l, config, store).export(now))
+		assert.Len(t, store.objects, 3)
+	})
+
+	t.Run("sagas which cannot be written are retried", func(t *testing.T) {
+		s := finished(tenantA)
+		store := &testStore{err: errors.New("unavailable")}
+		l, hook := test.NewNullLogger()
+		r := NewExporter(l, config, store)
+		assert.Equal(t, 0, r.export(now))
+		require.NotNil(t, hook.LastEntry())
+		assert.Contains(t, hook.LastEntry().Message, "Unable to export")
+
+		finished(tenantA)
+		store.err = nil
+		assert.Equal(t, 2, r.export(now))
+		require.Len(t, store.objects, 1)
+		assert.Equal(t, s.TransactionId, store.objects[0].records(t)[0].Saga.TransactionId)
+	})
+
+	t.Run("the oldest sagas are dropped should too many await export", func(t *testing.T) {
+		dropped := finished(tenantA)
+		for i := 0; i < 4; i++ {
+			finished(tenantA)
+		}
+		rs := getExportLog().take()
+		require.Len(t, rs, 4)
+		for _, rec := range rs {
+			assert.NotEqual(t, dropped.TransactionId, rec.Saga.TransactionId)
+		}
+	})
+
+	t.Run("nothing is recorded unless enabled", func(t *testing.T) {
+		InitExportConfig(ExportConfig{})
+		defer InitExportConfig(config)
+		finished(tenantA)
+		assert.Empty(t, getExportLog().take())
+	})
+
+	t.Run("shadow replicas record nothing", func(t *testing.T) {
+		InitShadowConfig(ShadowConfig{Enabled: true})
+		defer InitShadowConfig(ShadowConfig{})
+		finished(tenantA)
+		assert.Empty(t, getExportLog().take())
+	})
+}
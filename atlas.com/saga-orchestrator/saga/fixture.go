@@ -0,0 +1,223 @@
+package saga
+
+import (
+	"atlas-saga-orchestrator/kafka/message/saga"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// fixtureErrorCode is the error code the failed step of a fixture's failure event reports
+const fixtureErrorCode = "UNKNOWN_ERROR"
+
+// fixtureTime is the timestamp of every time in a fixture, so fixtures are generated identically each time
+var fixtureTime = time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// Fixture is a canonical example of a saga, and the status events the orchestrator emits once it completes or, its
+// step having failed, once it is compensated
+type Fixture struct {
+	Payload      any                                               `json:"payload,omitempty"` // Payload of the action, for fixtures of actions
+	Saga         Saga                                              `json:"saga"`              // Saga taking the action, or of the type
+	SuccessEvent saga.StatusEvent[saga.StatusEventCompletedBody]   `json:"successEvent"`      // COMPLETED status event of the saga
+	FailureEvent saga.StatusEvent[saga.StatusEventCompensatedBody] `json:"failureEvent"`      // COMPENSATED status event of the saga, once its last step failed
+}
+
+// Fixtures are the canonical examples of every registered action and saga type
+type Fixtures struct {
+	Actions map[Action]Fixture
+	Types   map[Type]Fixture
+}
+
+// GenerateFixtures generates a fixture for every action, a saga taking it as its only step, and for every registered saga
+// type, a saga honouring its contract. Payloads are populated with placeholder values for the fields each requires,
+// which conform to the action's schema, but are not checked against the rules its handler enforces.
+func GenerateFixtures() Fixtures {
+	f := Fixtures{Actions: make(map[Action]Fixture), Types: make(map[Type]Fixture)}
+	for _, action := range Actions {
+		payload := examplePayload(action)
+		fx := newFixture(InventoryTransaction, "action:"+string(action), []Step[any]{exampleStep(action, payload)})
+		fx.Payload = payload
+		f.Actions[action] = fx
+	}
+
+	typeRegistry.mu.RLock()
+	defer typeRegistry.mu.RUnlock()
+	for t, c := range typeRegistry.contracts {
+		steps := make([]Step[any], 0)
+		for _, action := range exampleActions(c) {
+			steps = append(steps, exampleStep(action, examplePayload(action)))
+		}
+		f.Types[t] = newFixture(t, "type:"+string(t), steps)
+	}
+	return f
+}
+
+// WriteFixtures writes each fixture as a JSON document to the directory, those of actions to actions/<action>.json and
+// those of saga types to types/<type>.json
+func WriteFixtures(dir string, f Fixtures) error {
+	write := func(sub string, name string, fx Fixture) error {
+		bs, err := json.MarshalIndent(fx, "", "  ")
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(dir, sub, name+".json"), append(bs, '\n'), 0o644)
+	}
+
+	for _, sub := range []string{"actions", "types"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o755); err != nil {
+			return err
+		}
+	}
+	for action, fx := range f.Actions {
+		if err := write("actions", string(action), fx); err != nil {
+			return err
+		}
+	}
+	for t, fx := range f.Types {
+		if err := write("types", string(t), fx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newFixture creates the fixture of a saga of the steps. Its transaction id is derived from the name of the fixture, so
+// is the same each time it is generated.
+func newFixture(t Type, name string, steps []Step[any]) Fixture {
+	s := Saga{
+		TransactionId: uuid.NewSHA1(uuid.NameSpaceOID, []byte(name)),
+		SagaType:      t,
+		InitiatedBy:   "fixture",
+		Steps:         steps,
+	}
+
+	failed := s
+	failed.Steps = slices.Clone(s.Steps)
+	for i := range failed.Steps {
+		failed.Steps[i].Status = Completed
+	}
+	last := &failed.Steps[len(failed.Steps)-1]
+	last.Status = Failed
+	last.Attempts = []StepAttempt{{Attempt: 1, DispatchedAt: fixtureTime, CommandKey: last.StepId + "#1", ErrorCode: fixtureErrorCode}}
+	r, _ := NewCompensationReceipt(failed, 0, nil)
+	r.IssuedAt = fixtureTime
+
+	return Fixture{
+		Saga: s,
+		SuccessEvent: saga.StatusEvent[saga.StatusEventCompletedBody]{
+			TransactionId: s.TransactionId,
+			Type:          saga.StatusEventTypeCompleted,
+		},
+		FailureEvent: compensatedStatusEvent(s.TransactionId, r),
+	}
+}
+
+// exampleActions returns the actions of the steps of an example saga honouring the contract: its first and last
+// actions, if constrained, otherwise award_asset, or the first action it permits
+func exampleActions(c TypeContract) []Action {
+	actions := make([]Action, 0)
+	if len(c.First) > 0 {
+		actions = append(actions, c.First[0])
+	}
+	if len(c.Last) > 0 && (len(actions) == 0 || actions[0] != c.Last[0]) {
+		actions = append(actions, c.Last[0])
+	}
+	if len(actions) > 0 {
+		return actions
+	}
+	if c.Permits(AwardAsset) {
+		return []Action{AwardAsset}
+	}
+	return []Action{c.Actions[0]}
+}
+
+// exampleStep creates a pending step of an example saga taking the action
+func exampleStep(action Action, payload any) Step[any] {
+	return Step[any]{StepId: string(action), Status: Pending, Action: action, Payload: payload, CreatedAt: fixtureTime, UpdatedAt: fixtureTime}
+}
+
+// examplePayload creates a payload of the action, populated with placeholder values
+func examplePayload(action Action) any {
+	t, ok := PayloadType(action)
+	if !ok {
+		return map[string]any{}
+	}
+	v := reflect.New(t).Elem()
+	populateExample(v, 0)
+	return v.Interface()
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	uuidType       = reflect.TypeOf(uuid.UUID{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+	stepType       = reflect.TypeOf(Step[any]{})
+)
+
+// populateExample sets the value, and those nested within it, to placeholder values: 1 for numbers, "example" for
+// strings, a single element for slices and maps. Fields omitted when empty, being optional or recorded as the action
+// runs, are left unset, as are nested steps, whose examples are those of their own actions.
+func populateExample(v reflect.Value, depth int) {
+	if depth > 4 {
+		return
+	}
+	switch v.Type() {
+	case timeType:
+		v.Set(reflect.ValueOf(fixtureTime))
+		return
+	case uuidType:
+		v.Set(reflect.ValueOf(uuid.NewSHA1(uuid.NameSpaceOID, []byte("example"))))
+		return
+	case rawMessageType:
+		v.Set(reflect.ValueOf(json.RawMessage(`{}`)))
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(1)
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(1)
+	case reflect.String:
+		v.SetString("example")
+	case reflect.Interface:
+		if v.NumMethod() == 0 {
+			v.Set(reflect.ValueOf("example"))
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			if tag := f.Tag.Get("json"); f.IsExported() && tag != "-" && !strings.Contains(tag, ",omitempty") {
+				populateExample(v.Field(i), depth+1)
+			}
+		}
+	case reflect.Slice:
+		s := reflect.MakeSlice(v.Type(), 0, 1)
+		if v.Type().Elem() != stepType {
+			e := reflect.New(v.Type().Elem()).Elem()
+			populateExample(e, depth+1)
+			s = reflect.Append(s, e)
+		}
+		v.Set(s)
+	case reflect.Map:
+		m := reflect.MakeMap(v.Type())
+		if v.Type().Key().Kind() == reflect.String {
+			k := reflect.New(v.Type().Key()).Elem()
+			k.SetString("example")
+			e := reflect.New(v.Type().Elem()).Elem()
+			populateExample(e, depth+1)
+			m.SetMapIndex(k, e)
+		}
+		v.Set(m)
+	}
+}
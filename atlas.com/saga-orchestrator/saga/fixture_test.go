@@ -0,0 +1,61 @@
+package saga
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGenerateFixtures tests that a fixture is generated for every registered action and saga type, whose sagas
+// deserialize to payloads of their actions' types, and honour the contracts of their types
+func TestGenerateFixtures(t *testing.T) {
+	f := GenerateFixtures()
+	assert.Len(t, f.Actions, len(Actions))
+	assert.Len(t, f.Types, len(typeRegistry.contracts))
+
+	for action, fx := range f.Actions {
+		bs, err := json.Marshal(fx.Saga)
+		require.NoError(t, err, action)
+		var s Saga
+		require.NoError(t, json.Unmarshal(bs, &s), action)
+		require.Len(t, s.Steps, 1, action)
+		assert.Equal(t, action, s.Steps[0].Action)
+		assert.IsType(t, fx.Payload, s.Steps[0].Payload, action)
+		assert.NotEqual(t, reflect.Map, reflect.TypeOf(fx.Payload).Kind(), action)
+		assert.Equal(t, fx.Saga.TransactionId, fx.FailureEvent.TransactionId, action)
+		assert.Equal(t, string(action), fx.FailureEvent.Body.FailedStepId, action)
+		assert.Equal(t, fixtureErrorCode, fx.FailureEvent.Body.ErrorCode, action)
+	}
+
+	for st, fx := range f.Types {
+		assert.Equal(t, st, fx.Saga.SagaType)
+		assert.NoError(t, fx.Saga.ValidateType(), st)
+	}
+
+	// Fixtures are generated identically each time
+	again := GenerateFixtures()
+	assert.Equal(t, f.Actions[AwardMesos].Saga.TransactionId, again.Actions[AwardMesos].Saga.TransactionId)
+	assert.Equal(t, f.Actions[AwardMesos].Payload, again.Actions[AwardMesos].Payload)
+}
+
+// TestWriteFixtures tests that fixtures are written as a document per action and saga type
+func TestWriteFixtures(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, WriteFixtures(dir, GenerateFixtures()))
+
+	bs, err := os.ReadFile(filepath.Join(dir, "actions", string(AwardMesos)+".json"))
+	require.NoError(t, err)
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal(bs, &doc))
+	assert.Contains(t, doc, "payload")
+	assert.Contains(t, doc, "successEvent")
+	assert.Contains(t, doc, "failureEvent")
+
+	_, err = os.Stat(filepath.Join(dir, "types", string(CharacterCreation)+".json"))
+	assert.NoError(t, err)
+}
@@ -0,0 +1,113 @@
+package saga
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ErrActionNotEnabled is returned when a saga has a pending step whose action is not enabled for its tenant
+var ErrActionNotEnabled = errors.New("action not enabled")
+
+// ErrorCodeActionNotEnabled is the error code a step fails with when dispatched with an action not enabled for its
+// tenant, so that its error handlers may declare a fallback (e.g. skip)
+const ErrorCodeActionNotEnabled = "ACTION_NOT_ENABLED"
+
+// Rollout declares the tenants a newly added action is enabled for, while the services consuming its commands are
+// deployed
+type Rollout struct {
+	Tenants    []uuid.UUID // Tenants the action is enabled for
+	Percentage uint32      // Percentage of the remaining tenants the action is enabled for
+}
+
+// FlagConfig configures the gradual rollout of actions. Actions without a rollout are enabled for every tenant.
+type FlagConfig struct {
+	Rollouts map[Action]Rollout
+}
+
+// FlagConfigFromEnv loads the action rollout configuration from the environment
+func FlagConfigFromEnv() (FlagConfig, error) {
+	c := FlagConfig{Rollouts: make(map[Action]Rollout)}
+
+	// Rollouts are expressed as a comma-separated list of action=rollout pairs, each rollout being a |-separated list of
+	// tenant IDs and a percentage (e.g. spawn_escort=10%,update_character_alignment=<tenantId>|<tenantId>)
+	v := os.Getenv("SAGA_ACTION_ROLLOUT")
+	if v == "" {
+		return c, nil
+	}
+	for _, entry := range strings.Split(v, ",") {
+		action, rollout, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || action == "" {
+			return FlagConfig{}, fmt.Errorf("invalid SAGA_ACTION_ROLLOUT entry '%s'", entry)
+		}
+		r := Rollout{Tenants: make([]uuid.UUID, 0)}
+		for _, part := range strings.Split(rollout, "|") {
+			if part == "" {
+				continue
+			}
+			if p, ok := strings.CutSuffix(part, "%"); ok {
+				n, err := strconv.ParseUint(p, 10, 32)
+				if err != nil || n > 100 {
+					return FlagConfig{}, fmt.Errorf("invalid SAGA_ACTION_ROLLOUT percentage '%s' of action '%s'", part, action)
+				}
+				r.Percentage = uint32(n)
+				continue
+			}
+			id, err := uuid.Parse(part)
+			if err != nil {
+				return FlagConfig{}, fmt.Errorf("invalid SAGA_ACTION_ROLLOUT tenant '%s' of action '%s'", part, action)
+			}
+			r.Tenants = append(r.Tenants, id)
+		}
+		c.Rollouts[Action(action)] = r
+	}
+	return c, nil
+}
+
+// Singleton action rollout configuration, which enables every action until initialized
+var flagConfig FlagConfig
+
+// InitFlagConfig replaces the singleton action rollout configuration
+func InitFlagConfig(config FlagConfig) {
+	flagConfig = config
+}
+
+// GetFlagConfig returns the singleton action rollout configuration
+func GetFlagConfig() FlagConfig {
+	return flagConfig
+}
+
+// Enabled returns whether the action is enabled for the tenant. Tenants are bucketed by a hash of the tenant and
+// action, so a tenant's sagas agree on whether an action is enabled, and raising the percentage only enables it for more
+// tenants.
+func (c FlagConfig) Enabled(tenantId uuid.UUID, action Action) bool {
+	r, ok := c.Rollouts[action]
+	if !ok || slices.Contains(r.Tenants, tenantId) {
+		return true
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(string(action) + ":" + tenantId.String()))
+	return h.Sum32()%100 < r.Percentage
+}
+
+// DisabledActions returns the actions of the saga's pending steps which are not enabled for the tenant. Steps declaring
+// an error handler for ACTION_NOT_ENABLED are left to it.
+func (s Saga) DisabledActions(tenantId uuid.UUID, c FlagConfig) []Action {
+	r := make([]Action, 0)
+	for i, st := range s.Steps {
+		if st.Status != Pending || c.Enabled(tenantId, st.Action) || slices.Contains(r, st.Action) {
+			continue
+		}
+		if _, ok := s.FindErrorHandler(i, ErrorCodeActionNotEnabled); ok {
+			continue
+		}
+		r = append(r, st.Action)
+	}
+	return r
+}
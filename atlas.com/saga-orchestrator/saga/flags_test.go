@@ -0,0 +1,96 @@
+package saga
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+// TestFlagConfigFromEnv tests loading the action rollout configuration from the environment
+func TestFlagConfigFromEnv(t *testing.T) {
+	tenantId := uuid.New()
+	t.Setenv("SAGA_ACTION_ROLLOUT", "spawn_escort=10%, update_character_alignment="+tenantId.String()+"|25%")
+
+	c, err := FlagConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, map[Action]Rollout{
+		SpawnEscort:              {Tenants: []uuid.UUID{}, Percentage: 10},
+		UpdateCharacterAlignment: {Tenants: []uuid.UUID{tenantId}, Percentage: 25},
+	}, c.Rollouts)
+
+	for _, v := range []string{"spawn_escort", "spawn_escort=101%", "spawn_escort=tenant"} {
+		t.Setenv("SAGA_ACTION_ROLLOUT", v)
+		_, err = FlagConfigFromEnv()
+		assert.Error(t, err, v)
+	}
+}
+
+// TestFlagConfigEnabled tests that actions are enabled for listed tenants, and a stable percentage of the remainder
+func TestFlagConfigEnabled(t *testing.T) {
+	tenantId := uuid.New()
+	c := FlagConfig{Rollouts: map[Action]Rollout{
+		SpawnEscort:              {Tenants: []uuid.UUID{tenantId}},
+		UpdateCharacterAlignment: {Percentage: 100},
+		AwaitEscort:              {Percentage: 50},
+	}}
+
+	assert.True(t, c.Enabled(tenantId, SpawnEscort))
+	assert.False(t, c.Enabled(uuid.New(), SpawnEscort))
+	assert.True(t, c.Enabled(uuid.New(), UpdateCharacterAlignment))
+	assert.True(t, c.Enabled(uuid.New(), AwardMesos))
+
+	enabled := 0
+	for i := 0; i < 1000; i++ {
+		id := uuid.New()
+		if c.Enabled(id, AwaitEscort) {
+			enabled++
+			assert.True(t, c.Enabled(id, AwaitEscort))
+		}
+	}
+	assert.InDelta(t, 500, enabled, 100)
+}
+
+// TestPutActionNotEnabled tests that sagas using actions not enabled for their tenant are rejected, unless their steps
+// declare a fallback, in which case the steps fail when dispatched
+func TestPutActionNotEnabled(t *testing.T) {
+	te, ctx := setupContext()
+	processor, _ := setupTestProcessor(ctx, nil, nil)
+	defer InitFlagConfig(FlagConfig{})
+	InitFlagConfig(FlagConfig{Rollouts: map[Action]Rollout{AwardMesos: {}}})
+
+	t.Run("saga is rejected", func(t *testing.T) {
+		s := NewBuilder().
+			SetSagaType(QuestReward).
+			AddStep("mesos", Pending, AwardMesos, AwardMesosPayload{CharacterId: 12345, ActorType: "NPC", Amount: 1000}).
+			Build()
+		err := processor.Put(s)
+		assert.ErrorIs(t, err, ErrActionNotEnabled)
+		assert.Contains(t, err.Error(), string(AwardMesos))
+		_, ok := GetCache().GetById(te.Id(), s.TransactionId)
+		assert.False(t, ok)
+	})
+
+	t.Run("step fails to its fallback", func(t *testing.T) {
+		s := NewBuilder().
+			SetSagaType(QuestReward).
+			AddStep("mesos", Pending, AwardMesos, AwardMesosPayload{CharacterId: 12345, ActorType: "NPC", Amount: 1000}).
+			AddErrorHandler(ErrorHandler{ErrorCode: ErrorCodeActionNotEnabled, Reaction: ErrorReactionSkip}).
+			AddStep("variable", Pending, SetVariable, SetVariablePayload{Name: "done", Value: true}).
+			Build()
+		c, unsubscribe := GetNotifier().Subscribe(te.Id(), s.TransactionId)
+		defer unsubscribe()
+		require.NoError(t, processor.Put(s))
+
+		select {
+		case cs := <-c:
+			assert.False(t, cs.Failing())
+			require.NotEmpty(t, cs.Steps[0].Attempts)
+			assert.Equal(t, ErrorCodeActionNotEnabled, cs.Steps[0].Attempts[len(cs.Steps[0].Attempts)-1].ErrorCode)
+			assert.Equal(t, Completed, cs.Steps[1].Status)
+		case <-time.After(time.Second):
+			t.Fatal("saga did not complete")
+		}
+	})
+}
@@ -0,0 +1,218 @@
+package saga
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// MaxForEachIterations is the number of items a for_each step may iterate, bounding the steps it expands into
+const MaxForEachIterations = 100
+
+// defaultForEachAs is the name sub-step payload templates reference the current item by, when not declared
+const defaultForEachAs = "item"
+
+// reservedTemplateNames are the members of the template document, which the current item may not shadow
+var reservedTemplateNames = map[string]bool{"transactionId": true, "initiatedBy": true, "labels": true, "variables": true, "steps": true, "index": true}
+
+// iterationPrefix returns the prefix of the step IDs expanded for an iteration of a for_each step
+func iterationPrefix(stepId string, index int) string {
+	return fmt.Sprintf("%s_%d_", stepId, index)
+}
+
+// expandForEach expands the sub-steps of a for_each step for each item of the list it iterates, rendering their
+// payload templates against the saga with the current item and its index. Step IDs are prefixed with the for_each step
+// ID and the index (e.g. destroy_each_0_destroy), so they are unique across iterations.
+func expandForEach(s Saga, st Step[any], payload ForEachPayload) ([]Step[any], []ForEachIteration, error) {
+	v, ok := s.Variables[payload.Items]
+	if !ok {
+		return nil, nil, fmt.Errorf("variable '%s' does not exist", payload.Items)
+	}
+	items, ok := v.([]any)
+	if !ok {
+		return nil, nil, fmt.Errorf("variable '%s' is not a list", payload.Items)
+	}
+	if len(items) > MaxForEachIterations {
+		return nil, nil, fmt.Errorf("variable '%s' has %d items, exceeding the limit of %d", payload.Items, len(items), MaxForEachIterations)
+	}
+	if payload.FailurePolicy != "" && payload.FailurePolicy != ForEachAbort && payload.FailurePolicy != ForEachContinue {
+		return nil, nil, fmt.Errorf("unknown failure policy '%s'", payload.FailurePolicy)
+	}
+	if payload.MaxFailures < 0 {
+		return nil, nil, fmt.Errorf("maxFailures must not be negative")
+	}
+	as := payload.As
+	if as == "" {
+		as = defaultForEachAs
+	}
+	if !variableNamePattern.MatchString(as) || reservedTemplateNames[as] {
+		return nil, nil, fmt.Errorf("item name '%s' must be an identifier other than %s", as, "transactionId, initiatedBy, labels, variables, steps and index")
+	}
+	if len(payload.Steps) == 0 {
+		return nil, nil, fmt.Errorf("no steps to repeat")
+	}
+	ids := make(map[string]bool, len(payload.Steps))
+	for _, sub := range payload.Steps {
+		if sub.StepId == "" || ids[sub.StepId] {
+			return nil, nil, fmt.Errorf("step ID '%s' is not unique", sub.StepId)
+		}
+		if sub.Action == "" {
+			return nil, nil, fmt.Errorf("step '%s' has no action", sub.StepId)
+		}
+		if len(sub.Branches) > 0 {
+			return nil, nil, fmt.Errorf("step '%s' may not declare branches", sub.StepId)
+		}
+		ids[sub.StepId] = true
+	}
+
+	ctx, err := templateContext(s, st.StepId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	steps := make([]Step[any], 0, len(items)*len(payload.Steps))
+	iterations := make([]ForEachIteration, 0, len(items))
+	for i, item := range items {
+		ictx := make(map[string]any, len(ctx)+2)
+		for k, e := range ctx {
+			ictx[k] = e
+		}
+		ictx[as] = item
+		ictx["index"] = i
+
+		it := ForEachIteration{Index: i, Item: item, StepIds: make([]string, 0, len(payload.Steps)), Status: Pending}
+		for _, sub := range payload.Steps {
+			es := sub
+			es.StepId = iterationPrefix(st.StepId, i) + sub.StepId
+			es.Status = Pending
+			es.Attempts = nil
+			if len(sub.PayloadTemplate) > 0 {
+				rendered, err := renderPayloadTemplate(sub.Action, sub.PayloadTemplate, ictx)
+				if err != nil {
+					return nil, nil, fmt.Errorf("step '%s': %w", es.StepId, err)
+				}
+				es.Payload = rendered
+				es.PayloadTemplate = nil
+			}
+			steps = append(steps, es)
+			it.StepIds = append(it.StepIds, es.StepId)
+		}
+		iterations = append(iterations, it)
+	}
+	return steps, iterations, nil
+}
+
+// findIteration returns the index of the for_each step, and of its iteration, which expanded the step. Steps added
+// dynamically by a sub-step share its prefix, so also belong to the iteration. When for_each steps are nested, the
+// innermost iteration is returned.
+func findIteration(s Saga, stepId string) (int, int, bool) {
+	pi, ii, longest := -1, -1, 0
+	for i, st := range s.Steps {
+		payload, ok := st.Payload.(ForEachPayload)
+		if !ok || st.Action != ForEach {
+			continue
+		}
+		for j, it := range payload.Iterations {
+			prefix := iterationPrefix(st.StepId, it.Index)
+			if strings.HasPrefix(stepId, prefix) && len(prefix) > longest {
+				pi, ii, longest = i, j, len(prefix)
+			}
+		}
+	}
+	return pi, ii, pi != -1
+}
+
+// setIteration returns the saga with an iteration of a for_each step replaced, leaving the receiver, which may be
+// shared with cached copies of the saga, unmodified
+func (s Saga) setIteration(stepIndex int, iterationIndex int, it ForEachIteration) Saga {
+	payload := s.Steps[stepIndex].Payload.(ForEachPayload)
+	payload.Iterations = append([]ForEachIteration{}, payload.Iterations...)
+	payload.Iterations[iterationIndex] = it
+	s.Steps = append([]Step[any]{}, s.Steps...)
+	s.Steps[stepIndex].Payload = payload
+	return s
+}
+
+// trackIteration completes the iteration the step belongs to, once every one of its steps has completed
+func (p *ProcessorImpl) trackIteration(transactionId uuid.UUID, stepId string) {
+	s, err := p.GetById(transactionId)
+	if err != nil {
+		return
+	}
+	pi, ii, ok := findIteration(s, stepId)
+	if !ok {
+		return
+	}
+	it := s.Steps[pi].Payload.(ForEachPayload).Iterations[ii]
+	prefix := iterationPrefix(s.Steps[pi].StepId, it.Index)
+	for _, st := range s.Steps {
+		if strings.HasPrefix(st.StepId, prefix) && st.Status != Completed {
+			return
+		}
+	}
+	it.Status = Completed
+	GetCache().Put(p.t.Id(), s.setIteration(pi, ii, it))
+}
+
+// failIteration records the failure of the current step against the iteration it belongs to. Under the continue
+// policy, the iteration's remaining steps are removed and the failure is absorbed, so the saga continues with the next
+// iteration, until more than maxFailures iterations have failed. Returns whether the failure was absorbed.
+func (p *ProcessorImpl) failIteration(s Saga) bool {
+	idx := s.FindEarliestPendingStepIndex()
+	if idx == -1 {
+		return false
+	}
+	st := s.Steps[idx]
+	pi, ii, ok := findIteration(s, st.StepId)
+	if !ok {
+		return false
+	}
+	payload := s.Steps[pi].Payload.(ForEachPayload)
+
+	it := payload.Iterations[ii]
+	it.Status = Failed
+	it.Error = fmt.Sprintf("step '%s' failed", st.StepId)
+	if n := len(st.Attempts); n > 0 && st.Attempts[n-1].ErrorCode != "" {
+		it.Error = fmt.Sprintf("step '%s' failed with [%s]: %s", st.StepId, st.Attempts[n-1].ErrorCode, st.Attempts[n-1].ErrorMessage)
+	}
+	s = s.setIteration(pi, ii, it)
+
+	failed := 0
+	for _, i := range s.Steps[pi].Payload.(ForEachPayload).Iterations {
+		if i.Status == Failed {
+			failed++
+		}
+	}
+	absorb := payload.FailurePolicy == ForEachContinue && (payload.MaxFailures == 0 || failed <= payload.MaxFailures)
+
+	fl := p.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        st.StepId,
+		"for_each_id":    s.Steps[pi].StepId,
+		"iteration":      it.Index,
+		"tenant_id":      p.t.Id().String(),
+	})
+	if absorb {
+		prefix := iterationPrefix(s.Steps[pi].StepId, it.Index)
+		steps := make([]Step[any], 0, len(s.Steps))
+		for _, e := range s.Steps {
+			if e.Status == Pending && strings.HasPrefix(e.StepId, prefix) {
+				continue
+			}
+			steps = append(steps, e)
+		}
+		s.Steps = steps
+		if err := s.ValidateStateConsistency(); err != nil {
+			fl.WithError(err).Error("State consistency validation failed after removing failed iteration steps.")
+			return false
+		}
+		fl.Debugf("Iteration failed. Continuing after [%d] failed iterations.", failed)
+	} else {
+		fl.Debugf("Iteration failed. Failing saga after [%d] failed iterations.", failed)
+	}
+	GetCache().Put(p.t.Id(), s)
+	return absorb
+}
@@ -0,0 +1,162 @@
+package saga
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestExpandForEach tests expanding the sub-steps of a for_each step for each item of a list variable
+func TestExpandForEach(t *testing.T) {
+	destroy := Step[any]{
+		StepId:          "destroy",
+		Status:          Pending,
+		Action:          DestroyAsset,
+		PayloadTemplate: json.RawMessage(`{"characterId": 12345, "templateId": "$.item.templateId", "quantity": "{{ .item.quantity }}"}`),
+	}
+	build := func(payload ForEachPayload) (Saga, Step[any]) {
+		s := NewBuilder().
+			SetSagaType(InventoryTransaction).
+			SetVariable("items", []any{
+				map[string]any{"templateId": 4000000, "quantity": 10},
+				map[string]any{"templateId": 4000001, "quantity": 5},
+			}).
+			SetVariable("count", 2).
+			AddStep("destroy_each", Pending, ForEach, payload).
+			Build()
+		require.NoError(t, s.NormalizeVariables())
+		return s, s.Steps[0]
+	}
+
+	s, st := build(ForEachPayload{Items: "items", Steps: []Step[any]{destroy}})
+	steps, iterations, err := expandForEach(s, st, st.Payload.(ForEachPayload))
+	require.NoError(t, err)
+	require.Len(t, steps, 2)
+	assert.Equal(t, "destroy_each_0_destroy", steps[0].StepId)
+	assert.Equal(t, DestroyAssetPayload{CharacterId: 12345, TemplateId: 4000000, Quantity: 10}, steps[0].Payload)
+	assert.Equal(t, "destroy_each_1_destroy", steps[1].StepId)
+	assert.Equal(t, DestroyAssetPayload{CharacterId: 12345, TemplateId: 4000001, Quantity: 5}, steps[1].Payload)
+	assert.Empty(t, steps[1].PayloadTemplate)
+	require.Len(t, iterations, 2)
+	assert.Equal(t, ForEachIteration{Index: 1, Item: map[string]any{"templateId": float64(4000001), "quantity": float64(5)}, StepIds: []string{"destroy_each_1_destroy"}, Status: Pending}, iterations[1])
+
+	tests := []struct {
+		name     string
+		payload  ForEachPayload
+		errorMsg string
+	}{
+		{name: "Error - variable missing", payload: ForEachPayload{Items: "other", Steps: []Step[any]{destroy}}, errorMsg: "does not exist"},
+		{name: "Error - variable not a list", payload: ForEachPayload{Items: "count", Steps: []Step[any]{destroy}}, errorMsg: "not a list"},
+		{name: "Error - no steps", payload: ForEachPayload{Items: "items"}, errorMsg: "no steps"},
+		{name: "Error - duplicate step ID", payload: ForEachPayload{Items: "items", Steps: []Step[any]{destroy, destroy}}, errorMsg: "not unique"},
+		{name: "Error - reserved item name", payload: ForEachPayload{Items: "items", As: "variables", Steps: []Step[any]{destroy}}, errorMsg: "must be an identifier"},
+		{name: "Error - unknown failure policy", payload: ForEachPayload{Items: "items", FailurePolicy: "ignore", Steps: []Step[any]{destroy}}, errorMsg: "unknown failure policy"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, st := build(tt.payload)
+			_, _, err := expandForEach(s, st, tt.payload)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errorMsg)
+		})
+	}
+}
+
+// TestForEachExecution tests that the iterations of a for_each step execute in turn, tracking their status, and that
+// failed iterations are handled according to the failure policy
+func TestForEachExecution(t *testing.T) {
+	te, ctx := setupContext()
+	processor, _ := setupTestProcessor(ctx, nil, nil)
+
+	// Items which are not identifiers fail their iteration's set_variable step
+	build := func(items []any, policy ForEachFailurePolicy, maxFailures int) Saga {
+		bs, err := json.Marshal(map[string]any{
+			"transactionId": uuid.New(),
+			"sagaType":      InventoryTransaction,
+			"initiatedBy":   "TEST",
+			"variables":     map[string]any{"names": items},
+			"steps": []map[string]any{
+				{"stepId": "each", "status": Pending, "action": ForEach, "payload": map[string]any{
+					"items":         "names",
+					"as":            "name",
+					"failurePolicy": policy,
+					"maxFailures":   maxFailures,
+					"steps": []map[string]any{
+						{"stepId": "set", "status": Pending, "action": SetVariable, "payload": map[string]any{"name": "{{ .name }}", "value": "$.index"}},
+					},
+				}},
+				{"stepId": "done", "status": Pending, "action": SetVariable, "payload": map[string]any{"name": "done", "value": true}},
+			},
+		})
+		require.NoError(t, err)
+		var s Saga
+		require.NoError(t, json.Unmarshal(bs, &s))
+		return s
+	}
+	run := func(t *testing.T, s Saga) Saga {
+		done, unsubscribe := GetNotifier().Subscribe(te.Id(), s.TransactionId)
+		defer unsubscribe()
+
+		// Sagas are progressed as they are put, so a rejected step is reported here
+		_ = processor.Put(s)
+		defer GetCache().Remove(te.Id(), s.TransactionId)
+		select {
+		case s = <-done:
+		case <-time.After(time.Second):
+			t.Fatal("saga did not finish")
+		}
+		return s
+	}
+	statuses := func(s Saga) []Status {
+		r := make([]Status, 0)
+		for _, it := range s.Steps[0].Payload.(ForEachPayload).Iterations {
+			r = append(r, it.Status)
+		}
+		return r
+	}
+	stepIds := func(s Saga) []string {
+		r := make([]string, 0, len(s.Steps))
+		for _, st := range s.Steps {
+			r = append(r, st.StepId)
+		}
+		return r
+	}
+
+	t.Run("all iterations complete", func(t *testing.T) {
+		s := run(t, build([]any{"a", "b", "c"}, "", 0))
+		assert.Equal(t, []string{"each", "each_0_set", "each_1_set", "each_2_set", "done"}, stepIds(s))
+		assert.Equal(t, []Status{Completed, Completed, Completed}, statuses(s))
+		assert.Equal(t, float64(2), s.Variables["c"])
+		assert.Equal(t, true, s.Variables["done"])
+	})
+
+	t.Run("no items", func(t *testing.T) {
+		s := run(t, build([]any{}, "", 0))
+		assert.Equal(t, []string{"each", "done"}, stepIds(s))
+	})
+
+	t.Run("abort fails the saga", func(t *testing.T) {
+		s := run(t, build([]any{"a", "b.c", "d"}, ForEachAbort, 0))
+		assert.Equal(t, []Status{Completed, Failed, Pending}, statuses(s))
+		assert.Contains(t, s.Steps[0].Payload.(ForEachPayload).Iterations[1].Error, "each_1_set")
+		assert.NotContains(t, s.Variables, "done")
+	})
+
+	t.Run("continue skips failed iterations", func(t *testing.T) {
+		s := run(t, build([]any{"a", "b.c", "d"}, ForEachContinue, 0))
+		assert.Equal(t, []string{"each", "each_0_set", "each_2_set", "done"}, stepIds(s))
+		assert.Equal(t, []Status{Completed, Failed, Completed}, statuses(s))
+		assert.Equal(t, float64(2), s.Variables["d"])
+		assert.Equal(t, true, s.Variables["done"])
+	})
+
+	t.Run("continue fails the saga beyond maxFailures", func(t *testing.T) {
+		s := run(t, build([]any{"a.b", "c", "d.e", "g"}, ForEachContinue, 1))
+		assert.Equal(t, []Status{Failed, Completed, Failed, Pending}, statuses(s))
+		assert.NotContains(t, s.Variables, "done")
+	})
+}
@@ -0,0 +1,6 @@
+package saga
+
+// ErrorCodeInsufficientMesos is the error code a deduct_mesos step fails with when the character has fewer mesos than
+// the deduction, so that its error handlers may declare a fallback. The deduction is never emitted, so it is not
+// refunded.
+const ErrorCodeInsufficientMesos = "INSUFFICIENT_MESOS"
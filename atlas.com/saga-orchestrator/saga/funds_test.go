@@ -0,0 +1,94 @@
+package saga
+
+import (
+	"atlas-saga-orchestrator/character/mock"
+	"atlas-saga-orchestrator/validation"
+	mock2 "atlas-saga-orchestrator/validation/mock"
+	"testing"
+	"time"
+
+	"github.com/Chronicle20/atlas-constants/channel"
+	"github.com/Chronicle20/atlas-constants/world"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDeductMesosInsufficient tests that a deduction from a character lacking the mesos fails the saga with an error
+// code, without deducting or refunding anything, unless its error handlers declare a fallback
+func TestDeductMesosInsufficient(t *testing.T) {
+	te, ctx := setupContext()
+
+	var amounts []int32
+	charP := &mock.ProcessorMock{
+		AwardMesosAndEmitFunc: func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, actorId uint32, actorType string, amount int32) error {
+			amounts = append(amounts, amount)
+			return nil
+		},
+	}
+	validP := &mock2.ProcessorMock{
+		ValidateCharacterStateFunc: func(characterId uint32, conditions []validation.ConditionInput) (validation.ValidationResult, error) {
+			result := validation.NewValidationResult(characterId)
+			result.AddConditionResult(validation.ConditionResult{
+				Passed:      false,
+				Description: "Meso >= 500",
+				Type:        validation.MesoCondition,
+				Operator:    validation.GreaterEqual,
+				Value:       500,
+				ActualValue: 100,
+			})
+			return result, nil
+		},
+	}
+	processor, _ := setupTestProcessor(ctx, charP, nil, validP)
+
+	t.Run("saga fails without refund", func(t *testing.T) {
+		amounts = nil
+		s := NewBuilder().
+			SetSagaType(InventoryTransaction).
+			AddStep("fare", Pending, DeductMesos, DeductMesosPayload{CharacterId: 12345, ActorType: "SYSTEM", Amount: 500}).
+			Build()
+		done, unsubscribe := GetNotifier().Subscribe(te.Id(), s.TransactionId)
+		defer unsubscribe()
+		var re RejectedError
+		require.ErrorAs(t, processor.Put(s), &re)
+		assert.Equal(t, ErrorCodeInsufficientMesos, re.Code)
+		defer GetCache().Remove(te.Id(), s.TransactionId)
+
+		select {
+		case s = <-done:
+		case <-time.After(time.Second):
+			t.Fatal("saga did not fail")
+		}
+		assert.Empty(t, amounts)
+		require.NotNil(t, s.Receipt)
+		assert.Equal(t, "fare", s.Receipt.FailedStepId)
+		assert.Equal(t, ErrorCodeInsufficientMesos, s.Receipt.ErrorCode)
+		payload := s.Steps[0].Payload.(DeductMesosPayload)
+		require.NotNil(t, payload.FundsCheck)
+		assert.False(t, payload.FundsCheck.Passed)
+	})
+
+	t.Run("error handler skips the deduction", func(t *testing.T) {
+		amounts = nil
+		s := NewBuilder().
+			SetSagaType(InventoryTransaction).
+			AddStep("fare", Pending, DeductMesos, DeductMesosPayload{CharacterId: 12345, ActorType: "SYSTEM", Amount: 500}).
+			Build()
+		s.Steps[0].OnError = []ErrorHandler{{ErrorCode: ErrorCodeInsufficientMesos, Reaction: ErrorReactionSkip}}
+		done, unsubscribe := GetNotifier().Subscribe(te.Id(), s.TransactionId)
+		defer unsubscribe()
+		var re RejectedError
+		require.ErrorAs(t, processor.Put(s), &re)
+		assert.Equal(t, ErrorCodeInsufficientMesos, re.Code)
+		defer GetCache().Remove(te.Id(), s.TransactionId)
+
+		select {
+		case s = <-done:
+		case <-time.After(time.Second):
+			t.Fatal("saga did not complete")
+		}
+		assert.Empty(t, amounts)
+		assert.Equal(t, SagaStatusCompleted, s.OverallStatus())
+	})
+}
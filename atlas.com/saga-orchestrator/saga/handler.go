@@ -1,20 +1,45 @@
 package saga
 
 import (
+	"atlas-saga-orchestrator/account"
+	"atlas-saga-orchestrator/analytics"
+	"atlas-saga-orchestrator/asset"
+	"atlas-saga-orchestrator/buff"
 	"atlas-saga-orchestrator/character"
 	"atlas-saga-orchestrator/compartment"
+	"atlas-saga-orchestrator/coupon"
+	"atlas-saga-orchestrator/data/equipment"
+	"atlas-saga-orchestrator/faction"
 	"atlas-saga-orchestrator/guild"
+	instance2 "atlas-saga-orchestrator/instance"
 	"atlas-saga-orchestrator/invite"
+	analytics2 "atlas-saga-orchestrator/kafka/message/analytics"
 	character2 "atlas-saga-orchestrator/kafka/message/character"
+	systemmessage2 "atlas-saga-orchestrator/kafka/message/systemmessage"
+	"atlas-saga-orchestrator/marriage"
+	"atlas-saga-orchestrator/npc"
+	"atlas-saga-orchestrator/reactor"
+	"atlas-saga-orchestrator/session"
 	"atlas-saga-orchestrator/skill"
+	"atlas-saga-orchestrator/storage"
+	"atlas-saga-orchestrator/systemmessage"
 	"atlas-saga-orchestrator/validation"
+	"atlas-saga-orchestrator/worldstate"
 	"context"
 	"errors"
 	"fmt"
 	"github.com/Chronicle20/atlas-constants/field"
+	"github.com/Chronicle20/atlas-constants/inventory"
+	"github.com/Chronicle20/atlas-constants/item"
+	"github.com/Chronicle20/atlas-constants/job"
 	"github.com/Chronicle20/atlas-model/model"
 	tenant "github.com/Chronicle20/atlas-tenant"
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
 )
 
 type Handler interface {
@@ -24,6 +49,20 @@ type Handler interface {
 	WithValidationProcessor(validation.Processor) Handler
 	WithGuildProcessor(guild.Processor) Handler
 	WithInviteProcessor(invite.Processor) Handler
+	WithBuffProcessor(buff.Processor) Handler
+	WithWorldStateProcessor(worldstate.Processor) Handler
+	WithCouponProcessor(coupon.Processor) Handler
+	WithReactorProcessor(reactor.Processor) Handler
+	WithAnalyticsProcessor(analytics.Processor) Handler
+	WithAccountProcessor(account.Processor) Handler
+	WithMarriageProcessor(marriage.Processor) Handler
+	WithFactionProcessor(faction.Processor) Handler
+	WithInstanceProcessor(instance2.Processor) Handler
+	WithSessionProcessor(session.Processor) Handler
+	WithNpcProcessor(npc.Processor) Handler
+	WithStorageProcessor(storage.Processor) Handler
+	WithEquipmentProcessor(equipment.Processor) Handler
+	WithSystemMessageProcessor(systemmessage.Processor) Handler
 
 	GetHandler(action Action) (ActionHandler, bool)
 	
@@ -38,9 +77,26 @@ type Handler interface {
 	handleDestroyAsset(s Saga, st Step[any]) error
 	handleEquipAsset(s Saga, st Step[any]) error
 	handleUnequipAsset(s Saga, st Step[any]) error
+	handleModifyInventoryItemPosition(s Saga, st Step[any]) error
+	handleApplyEquipmentPreset(s Saga, st Step[any]) error
+	handleTransferCharacter(s Saga, st Step[any]) error
+	handleVerifyAccountMerge(s Saga, st Step[any]) error
+	handleRestoreAsset(s Saga, st Step[any]) error
+	handleRollbackCharacterToSnapshot(s Saga, st Step[any]) error
+	handleRestoreInventorySnapshot(s Saga, st Step[any]) error
+	handleAdjustNpcShopStock(s Saga, st Step[any]) error
+	handleSetWorldEventFlag(s Saga, st Step[any]) error
+	handleValidateCoupon(s Saga, st Step[any]) error
+	handleConsumeCoupon(s Saga, st Step[any]) error
+	handleApplyCharacterExpPenalty(s Saga, st Step[any]) error
+	handleApplyDurabilityPenalty(s Saga, st Step[any]) error
+	handleSetVariable(s Saga, st Step[any]) error
+	handleHttpRequest(s Saga, st Step[any]) error
+	handleCreateAccountCharacterSlot(s Saga, st Step[any]) error
 	handleChangeJob(s Saga, st Step[any]) error
 	handleCreateSkill(s Saga, st Step[any]) error
 	handleUpdateSkill(s Saga, st Step[any]) error
+	handleResetSkillCooldowns(s Saga, st Step[any]) error
 	handleValidateCharacterState(s Saga, st Step[any]) error
 	handleRequestGuildName(s Saga, st Step[any]) error
 	handleRequestGuildEmblem(s Saga, st Step[any]) error
@@ -49,6 +105,54 @@ type Handler interface {
 	handleCreateInvite(s Saga, st Step[any]) error
 	handleCreateCharacter(s Saga, st Step[any]) error
 	handleCreateAndEquipAsset(s Saga, st Step[any]) error
+	handleSetQuestTimer(s Saga, st Step[any]) error
+	handleAdjustPopularity(s Saga, st Step[any]) error
+	handleResolvePrizeTable(s Saga, st Step[any]) error
+	handleCharacterBuffCleanse(s Saga, st Step[any]) error
+	handleEmitAnalyticsEvent(s Saga, st Step[any]) error
+	handleModifyAssetExpiration(s Saga, st Step[any]) error
+	handleApplyHammer(s Saga, st Step[any]) error
+	handleDeductMesos(s Saga, st Step[any]) error
+	handleForEach(s Saga, st Step[any]) error
+	handleVerifyAndConsumeTicket(s Saga, st Step[any]) error
+	handleAwaitKillCount(s Saga, st Step[any]) error
+	handleAdjustReactorState(s Saga, st Step[any]) error
+	handleHitReactor(s Saga, st Step[any]) error
+	handleUpdateCharacterResource(s Saga, st Step[any]) error
+	handleAwardAssetIf(s Saga, st Step[any]) error
+	handleGrantPremiumTime(s Saga, st Step[any]) error
+	handleValidateDivorce(s Saga, st Step[any]) error
+	handleDissolveMarriage(s Saga, st Step[any]) error
+	handleSealAsset(s Saga, st Step[any]) error
+	handleUnsealAsset(s Saga, st Step[any]) error
+	handleConfiscateAsset(s Saga, st Step[any]) error
+	handleResolveDispute(s Saga, st Step[any]) error
+	handleIssueTransportTicket(s Saga, st Step[any]) error
+	handleScheduleWarp(s Saga, st Step[any]) error
+	handleSpawnEscort(s Saga, st Step[any]) error
+	handleAwaitEscort(s Saga, st Step[any]) error
+	handleUpdateCharacterAlignment(s Saga, st Step[any]) error
+	handleResetInstanceCooldown(s Saga, st Step[any]) error
+	handleAuditInventory(s Saga, st Step[any]) error
+	handleApplyTitleBuffOnLogin(s Saga, st Step[any]) error
+	handleGrantMount(s Saga, st Step[any]) error
+	handleApplyWorldEventBuff(s Saga, st Step[any]) error
+	handleRemoveWorldEventBuff(s Saga, st Step[any]) error
+	handleSettleEventCurrency(s Saga, st Step[any]) error
+	handleCharacterExperienceLock(s Saga, st Step[any]) error
+	handleCharacterExperienceUnlock(s Saga, st Step[any]) error
+	handleNpcConversationState(s Saga, st Step[any]) error
+	handleEquipAssetByTemplate(s Saga, st Step[any]) error
+	handleAwardPartyExperience(s Saga, st Step[any]) error
+	handleToggleCharacterAbility(s Saga, st Step[any]) error
+	handleGrantStorageCapacity(s Saga, st Step[any]) error
+	handleStripEquipment(s Saga, st Step[any]) error
+	handleSnapshotCharacter(s Saga, st Step[any]) error
+	handleRestoreCharacter(s Saga, st Step[any]) error
+	handleAwaitEvent(s Saga, st Step[any]) error
+	handleNotify(s Saga, st Step[any]) error
+	handlePetEquip(s Saga, st Step[any]) error
+	handlePetUnequip(s Saga, st Step[any]) error
 }
 
 type HandlerImpl struct {
@@ -61,6 +165,20 @@ type HandlerImpl struct {
 	validP  validation.Processor
 	guildP  guild.Processor
 	inviteP invite.Processor
+	buffP   buff.Processor
+	worldP  worldstate.Processor
+	couponP coupon.Processor
+	reactP  reactor.Processor
+	analytP analytics.Processor
+	acctP   account.Processor
+	marriP  marriage.Processor
+	factP   faction.Processor
+	instP   instance2.Processor
+	sessP   session.Processor
+	npcP    npc.Processor
+	storP   storage.Processor
+	equipP  equipment.Processor
+	msgP    systemmessage.Processor
 }
 
 func NewHandler(l logrus.FieldLogger, ctx context.Context) Handler {
@@ -74,6 +192,20 @@ func NewHandler(l logrus.FieldLogger, ctx context.Context) Handler {
 		validP:  validation.NewProcessor(l, ctx),
 		guildP:  guild.NewProcessor(l, ctx),
 		inviteP: invite.NewProcessor(l, ctx),
+		buffP:   buff.NewProcessor(l, ctx),
+		worldP:  worldstate.NewProcessor(l, ctx),
+		couponP: coupon.NewProcessor(l, ctx),
+		reactP:  reactor.NewProcessor(l, ctx),
+		analytP: analytics.NewProcessor(l, ctx),
+		acctP:   account.NewProcessor(l, ctx),
+		marriP:  marriage.NewProcessor(l, ctx),
+		factP:   faction.NewProcessor(l, ctx),
+		instP:   instance2.NewProcessor(l, ctx),
+		sessP:   session.NewProcessor(l, ctx),
+		npcP:    npc.NewProcessor(l, ctx),
+		storP:   storage.NewProcessor(l, ctx),
+		equipP:  equipment.NewProcessor(l, ctx),
+		msgP:    systemmessage.NewProcessor(l, ctx),
 	}
 }
 
@@ -88,6 +220,20 @@ func (h *HandlerImpl) WithCharacterProcessor(charP character.Processor) Handler
 		validP:  h.validP,
 		guildP:  h.guildP,
 		inviteP: h.inviteP,
+		buffP:   h.buffP,
+		worldP:  h.worldP,
+		couponP: h.couponP,
+		reactP:  h.reactP,
+		analytP: h.analytP,
+		acctP:   h.acctP,
+		marriP:  h.marriP,
+		factP:   h.factP,
+		instP:   h.instP,
+		sessP:   h.sessP,
+		npcP:    h.npcP,
+		storP:   h.storP,
+		equipP:  h.equipP,
+		msgP:    h.msgP,
 	}
 }
 
@@ -102,6 +248,20 @@ func (h *HandlerImpl) WithCompartmentProcessor(compP compartment.Processor) Hand
 		validP:  h.validP,
 		guildP:  h.guildP,
 		inviteP: h.inviteP,
+		buffP:   h.buffP,
+		worldP:  h.worldP,
+		couponP: h.couponP,
+		reactP:  h.reactP,
+		analytP: h.analytP,
+		acctP:   h.acctP,
+		marriP:  h.marriP,
+		factP:   h.factP,
+		instP:   h.instP,
+		sessP:   h.sessP,
+		npcP:    h.npcP,
+		storP:   h.storP,
+		equipP:  h.equipP,
+		msgP:    h.msgP,
 	}
 }
 
@@ -116,6 +276,20 @@ func (h *HandlerImpl) WithSkillProcessor(skillP skill.Processor) Handler {
 		validP:  h.validP,
 		guildP:  h.guildP,
 		inviteP: h.inviteP,
+		buffP:   h.buffP,
+		worldP:  h.worldP,
+		couponP: h.couponP,
+		reactP:  h.reactP,
+		analytP: h.analytP,
+		acctP:   h.acctP,
+		marriP:  h.marriP,
+		factP:   h.factP,
+		instP:   h.instP,
+		sessP:   h.sessP,
+		npcP:    h.npcP,
+		storP:   h.storP,
+		equipP:  h.equipP,
+		msgP:    h.msgP,
 	}
 }
 
@@ -130,6 +304,20 @@ func (h *HandlerImpl) WithValidationProcessor(validP validation.Processor) Handl
 		validP:  validP,
 		guildP:  h.guildP,
 		inviteP: h.inviteP,
+		buffP:   h.buffP,
+		worldP:  h.worldP,
+		couponP: h.couponP,
+		reactP:  h.reactP,
+		analytP: h.analytP,
+		acctP:   h.acctP,
+		marriP:  h.marriP,
+		factP:   h.factP,
+		instP:   h.instP,
+		sessP:   h.sessP,
+		npcP:    h.npcP,
+		storP:   h.storP,
+		equipP:  h.equipP,
+		msgP:    h.msgP,
 	}
 }
 
@@ -144,6 +332,20 @@ func (h *HandlerImpl) WithGuildProcessor(guildP guild.Processor) Handler {
 		validP:  h.validP,
 		guildP:  guildP,
 		inviteP: h.inviteP,
+		buffP:   h.buffP,
+		worldP:  h.worldP,
+		couponP: h.couponP,
+		reactP:  h.reactP,
+		analytP: h.analytP,
+		acctP:   h.acctP,
+		marriP:  h.marriP,
+		factP:   h.factP,
+		instP:   h.instP,
+		sessP:   h.sessP,
+		npcP:    h.npcP,
+		storP:   h.storP,
+		equipP:  h.equipP,
+		msgP:    h.msgP,
 	}
 }
 
@@ -158,9 +360,434 @@ func (h *HandlerImpl) WithInviteProcessor(inviteP invite.Processor) Handler {
 		validP:  h.validP,
 		guildP:  h.guildP,
 		inviteP: inviteP,
+		buffP:   h.buffP,
+		worldP:  h.worldP,
+		couponP: h.couponP,
+		reactP:  h.reactP,
+		analytP: h.analytP,
+		acctP:   h.acctP,
+		marriP:  h.marriP,
+		factP:   h.factP,
+		instP:   h.instP,
+		sessP:   h.sessP,
+		npcP:    h.npcP,
+		storP:   h.storP,
+		equipP:  h.equipP,
+		msgP:    h.msgP,
 	}
 }
 
+func (h *HandlerImpl) WithBuffProcessor(buffP buff.Processor) Handler {
+	return &HandlerImpl{
+		l:       h.l,
+		ctx:     h.ctx,
+		t:       h.t,
+		charP:   h.charP,
+		compP:   h.compP,
+		skillP:  h.skillP,
+		validP:  h.validP,
+		guildP:  h.guildP,
+		inviteP: h.inviteP,
+		buffP:   buffP,
+		worldP:  h.worldP,
+		couponP: h.couponP,
+		reactP:  h.reactP,
+		analytP: h.analytP,
+		acctP:   h.acctP,
+		marriP:  h.marriP,
+		factP:   h.factP,
+		instP:   h.instP,
+		sessP:   h.sessP,
+		npcP:    h.npcP,
+		storP:   h.storP,
+		equipP:  h.equipP,
+		msgP:    h.msgP,
+	}
+}
+
+func (h *HandlerImpl) WithWorldStateProcessor(worldP worldstate.Processor) Handler {
+	return &HandlerImpl{
+		l:       h.l,
+		ctx:     h.ctx,
+		t:       h.t,
+		charP:   h.charP,
+		compP:   h.compP,
+		skillP:  h.skillP,
+		validP:  h.validP,
+		guildP:  h.guildP,
+		inviteP: h.inviteP,
+		buffP:   h.buffP,
+		worldP:  worldP,
+		couponP: h.couponP,
+		reactP:  h.reactP,
+		analytP: h.analytP,
+		acctP:   h.acctP,
+		marriP:  h.marriP,
+		factP:   h.factP,
+		instP:   h.instP,
+		sessP:   h.sessP,
+		npcP:    h.npcP,
+		storP:   h.storP,
+		equipP:  h.equipP,
+		msgP:    h.msgP,
+	}
+}
+
+func (h *HandlerImpl) WithCouponProcessor(couponP coupon.Processor) Handler {
+	return &HandlerImpl{
+		l:       h.l,
+		ctx:     h.ctx,
+		t:       h.t,
+		charP:   h.charP,
+		compP:   h.compP,
+		skillP:  h.skillP,
+		validP:  h.validP,
+		guildP:  h.guildP,
+		inviteP: h.inviteP,
+		buffP:   h.buffP,
+		worldP:  h.worldP,
+		couponP: couponP,
+		reactP:  h.reactP,
+		analytP: h.analytP,
+		acctP:   h.acctP,
+		marriP:  h.marriP,
+		factP:   h.factP,
+		instP:   h.instP,
+		sessP:   h.sessP,
+		npcP:    h.npcP,
+		storP:   h.storP,
+		equipP:  h.equipP,
+		msgP:    h.msgP,
+	}
+}
+
+func (h *HandlerImpl) WithReactorProcessor(reactP reactor.Processor) Handler {
+	return &HandlerImpl{
+		l:       h.l,
+		ctx:     h.ctx,
+		t:       h.t,
+		charP:   h.charP,
+		compP:   h.compP,
+		skillP:  h.skillP,
+		validP:  h.validP,
+		guildP:  h.guildP,
+		inviteP: h.inviteP,
+		buffP:   h.buffP,
+		worldP:  h.worldP,
+		couponP: h.couponP,
+		reactP:  reactP,
+		analytP: h.analytP,
+		acctP:   h.acctP,
+		marriP:  h.marriP,
+		factP:   h.factP,
+		instP:   h.instP,
+		sessP:   h.sessP,
+		npcP:    h.npcP,
+		storP:   h.storP,
+		equipP:  h.equipP,
+		msgP:    h.msgP,
+	}
+}
+
+func (h *HandlerImpl) WithAnalyticsProcessor(analytP analytics.Processor) Handler {
+	return &HandlerImpl{
+		l:       h.l,
+		ctx:     h.ctx,
+		t:       h.t,
+		charP:   h.charP,
+		compP:   h.compP,
+		skillP:  h.skillP,
+		validP:  h.validP,
+		guildP:  h.guildP,
+		inviteP: h.inviteP,
+		buffP:   h.buffP,
+		worldP:  h.worldP,
+		couponP: h.couponP,
+		reactP:  h.reactP,
+		analytP: analytP,
+		acctP:   h.acctP,
+		marriP:  h.marriP,
+		factP:   h.factP,
+		instP:   h.instP,
+		sessP:   h.sessP,
+		npcP:    h.npcP,
+		storP:   h.storP,
+		equipP:  h.equipP,
+		msgP:    h.msgP,
+	}
+}
+
+func (h *HandlerImpl) WithAccountProcessor(acctP account.Processor) Handler {
+	return &HandlerImpl{
+		l:       h.l,
+		ctx:     h.ctx,
+		t:       h.t,
+		charP:   h.charP,
+		compP:   h.compP,
+		skillP:  h.skillP,
+		validP:  h.validP,
+		guildP:  h.guildP,
+		inviteP: h.inviteP,
+		buffP:   h.buffP,
+		worldP:  h.worldP,
+		couponP: h.couponP,
+		reactP:  h.reactP,
+		analytP: h.analytP,
+		acctP:   acctP,
+		marriP:  h.marriP,
+		factP:   h.factP,
+		instP:   h.instP,
+		sessP:   h.sessP,
+		npcP:    h.npcP,
+		storP:   h.storP,
+		equipP:  h.equipP,
+		msgP:    h.msgP,
+	}
+}
+
+func (h *HandlerImpl) WithMarriageProcessor(marriP marriage.Processor) Handler {
+	return &HandlerImpl{
+		l:       h.l,
+		ctx:     h.ctx,
+		t:       h.t,
+		charP:   h.charP,
+		compP:   h.compP,
+		skillP:  h.skillP,
+		validP:  h.validP,
+		guildP:  h.guildP,
+		inviteP: h.inviteP,
+		buffP:   h.buffP,
+		worldP:  h.worldP,
+		couponP: h.couponP,
+		reactP:  h.reactP,
+		analytP: h.analytP,
+		acctP:   h.acctP,
+		marriP:  marriP,
+		factP:   h.factP,
+		instP:   h.instP,
+		sessP:   h.sessP,
+		npcP:    h.npcP,
+		storP:   h.storP,
+		equipP:  h.equipP,
+		msgP:    h.msgP,
+	}
+}
+
+func (h *HandlerImpl) WithFactionProcessor(factP faction.Processor) Handler {
+	return &HandlerImpl{
+		l:       h.l,
+		ctx:     h.ctx,
+		t:       h.t,
+		charP:   h.charP,
+		compP:   h.compP,
+		skillP:  h.skillP,
+		validP:  h.validP,
+		guildP:  h.guildP,
+		inviteP: h.inviteP,
+		buffP:   h.buffP,
+		worldP:  h.worldP,
+		couponP: h.couponP,
+		reactP:  h.reactP,
+		analytP: h.analytP,
+		acctP:   h.acctP,
+		marriP:  h.marriP,
+		factP:   factP,
+		instP:   h.instP,
+		sessP:   h.sessP,
+		npcP:    h.npcP,
+		storP:   h.storP,
+		equipP:  h.equipP,
+		msgP:    h.msgP,
+	}
+}
+
+func (h *HandlerImpl) WithInstanceProcessor(instP instance2.Processor) Handler {
+	return &HandlerImpl{
+		l:       h.l,
+		ctx:     h.ctx,
+		t:       h.t,
+		charP:   h.charP,
+		compP:   h.compP,
+		skillP:  h.skillP,
+		validP:  h.validP,
+		guildP:  h.guildP,
+		inviteP: h.inviteP,
+		buffP:   h.buffP,
+		worldP:  h.worldP,
+		couponP: h.couponP,
+		reactP:  h.reactP,
+		analytP: h.analytP,
+		acctP:   h.acctP,
+		marriP:  h.marriP,
+		factP:   h.factP,
+		instP:   instP,
+		sessP:   h.sessP,
+		npcP:    h.npcP,
+		storP:   h.storP,
+		equipP:  h.equipP,
+		msgP:    h.msgP,
+	}
+}
+
+func (h *HandlerImpl) WithSessionProcessor(sessP session.Processor) Handler {
+	return &HandlerImpl{
+		l:       h.l,
+		ctx:     h.ctx,
+		t:       h.t,
+		charP:   h.charP,
+		compP:   h.compP,
+		skillP:  h.skillP,
+		validP:  h.validP,
+		guildP:  h.guildP,
+		inviteP: h.inviteP,
+		buffP:   h.buffP,
+		worldP:  h.worldP,
+		couponP: h.couponP,
+		reactP:  h.reactP,
+		analytP: h.analytP,
+		acctP:   h.acctP,
+		marriP:  h.marriP,
+		factP:   h.factP,
+		instP:   h.instP,
+		sessP:   sessP,
+		npcP:    h.npcP,
+		storP:   h.storP,
+		equipP:  h.equipP,
+		msgP:    h.msgP,
+	}
+}
+
+func (h *HandlerImpl) WithNpcProcessor(npcP npc.Processor) Handler {
+	return &HandlerImpl{
+		l:       h.l,
+		ctx:     h.ctx,
+		t:       h.t,
+		charP:   h.charP,
+		compP:   h.compP,
+		skillP:  h.skillP,
+		validP:  h.validP,
+		guildP:  h.guildP,
+		inviteP: h.inviteP,
+		buffP:   h.buffP,
+		worldP:  h.worldP,
+		couponP: h.couponP,
+		reactP:  h.reactP,
+		analytP: h.analytP,
+		acctP:   h.acctP,
+		marriP:  h.marriP,
+		factP:   h.factP,
+		instP:   h.instP,
+		sessP:   h.sessP,
+		npcP:    npcP,
+		storP:   h.storP,
+		equipP:  h.equipP,
+		msgP:    h.msgP,
+	}
+}
+
+func (h *HandlerImpl) WithStorageProcessor(storP storage.Processor) Handler {
+	return &HandlerImpl{
+		l:       h.l,
+		ctx:     h.ctx,
+		t:       h.t,
+		charP:   h.charP,
+		compP:   h.compP,
+		skillP:  h.skillP,
+		validP:  h.validP,
+		guildP:  h.guildP,
+		inviteP: h.inviteP,
+		buffP:   h.buffP,
+		worldP:  h.worldP,
+		couponP: h.couponP,
+		reactP:  h.reactP,
+		analytP: h.analytP,
+		acctP:   h.acctP,
+		marriP:  h.marriP,
+		factP:   h.factP,
+		instP:   h.instP,
+		sessP:   h.sessP,
+		npcP:    h.npcP,
+		storP:   storP,
+		equipP:  h.equipP,
+		msgP:    h.msgP,
+	}
+}
+
+func (h *HandlerImpl) WithEquipmentProcessor(equipP equipment.Processor) Handler {
+	return &HandlerImpl{
+		l:       h.l,
+		ctx:     h.ctx,
+		t:       h.t,
+		charP:   h.charP,
+		compP:   h.compP,
+		skillP:  h.skillP,
+		validP:  h.validP,
+		guildP:  h.guildP,
+		inviteP: h.inviteP,
+		buffP:   h.buffP,
+		worldP:  h.worldP,
+		couponP: h.couponP,
+		reactP:  h.reactP,
+		analytP: h.analytP,
+		acctP:   h.acctP,
+		marriP:  h.marriP,
+		factP:   h.factP,
+		instP:   h.instP,
+		sessP:   h.sessP,
+		npcP:    h.npcP,
+		storP:   h.storP,
+		equipP:  equipP,
+		msgP:    h.msgP,
+	}
+}
+
+func (h *HandlerImpl) WithSystemMessageProcessor(msgP systemmessage.Processor) Handler {
+	return &HandlerImpl{
+		l:       h.l,
+		ctx:     h.ctx,
+		t:       h.t,
+		charP:   h.charP,
+		compP:   h.compP,
+		skillP:  h.skillP,
+		validP:  h.validP,
+		guildP:  h.guildP,
+		inviteP: h.inviteP,
+		buffP:   h.buffP,
+		worldP:  h.worldP,
+		couponP: h.couponP,
+		reactP:  h.reactP,
+		analytP: h.analytP,
+		acctP:   h.acctP,
+		marriP:  h.marriP,
+		factP:   h.factP,
+		instP:   h.instP,
+		sessP:   h.sessP,
+		npcP:    h.npcP,
+		storP:   h.storP,
+		equipP:  h.equipP,
+		msgP:    msgP,
+	}
+}
+
+// ErrActionRejected is returned by handlers which reject their step outright, such as when a precondition is not met.
+// The step is marked as failed so the saga is compensated, rather than awaiting a status event which will never arrive.
+var ErrActionRejected = errors.New("action rejected")
+
+// RejectedError is a rejection declaring the error code its step fails with, so the step's error handlers may react to
+// it, and compensation treats the step as not having taken effect. It wraps ErrActionRejected.
+type RejectedError struct {
+	Code   string // Code the step fails with
+	Reason string // Reason the step was rejected
+}
+
+func (e RejectedError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrActionRejected.Error(), e.Reason)
+}
+
+func (e RejectedError) Unwrap() error {
+	return ErrActionRejected
+}
+
 // ActionHandler is a function type for handling different saga action types
 type ActionHandler func(s Saga, st Step[any]) error
 
@@ -186,12 +813,134 @@ func (h *HandlerImpl) GetHandler(action Action) (ActionHandler, bool) {
 		return h.handleEquipAsset, true
 	case UnequipAsset:
 		return h.handleUnequipAsset, true
+	case ModifyInventoryItemPosition:
+		return h.handleModifyInventoryItemPosition, true
+	case ApplyEquipmentPreset:
+		return h.handleApplyEquipmentPreset, true
+	case TransferCharacter:
+		return h.handleTransferCharacter, true
+	case VerifyAccountMerge:
+		return h.handleVerifyAccountMerge, true
+	case RestoreAsset:
+		return h.handleRestoreAsset, true
+	case RollbackCharacterToSnapshot:
+		return h.handleRollbackCharacterToSnapshot, true
+	case RestoreInventorySnapshot:
+		return h.handleRestoreInventorySnapshot, true
+	case AdjustNpcShopStock:
+		return h.handleAdjustNpcShopStock, true
+	case SetWorldEventFlag:
+		return h.handleSetWorldEventFlag, true
+	case ValidateCoupon:
+		return h.handleValidateCoupon, true
+	case ConsumeCoupon:
+		return h.handleConsumeCoupon, true
+	case ApplyCharacterExpPenalty:
+		return h.handleApplyCharacterExpPenalty, true
+	case ApplyDurabilityPenalty:
+		return h.handleApplyDurabilityPenalty, true
+	case SetVariable:
+		return h.handleSetVariable, true
+	case HttpRequest:
+		return h.handleHttpRequest, true
+	case CreateAccountCharacterSlot:
+		return h.handleCreateAccountCharacterSlot, true
+	case EmitAnalyticsEvent:
+		return h.handleEmitAnalyticsEvent, true
+	case ModifyAssetExpiration:
+		return h.handleModifyAssetExpiration, true
+	case ApplyHammer:
+		return h.handleApplyHammer, true
+	case DeductMesos:
+		return h.handleDeductMesos, true
+	case ForEach:
+		return h.handleForEach, true
+	case VerifyAndConsumeTicket:
+		return h.handleVerifyAndConsumeTicket, true
+	case AwaitKillCount:
+		return h.handleAwaitKillCount, true
+	case AdjustReactorState:
+		return h.handleAdjustReactorState, true
+	case HitReactor:
+		return h.handleHitReactor, true
+	case UpdateCharacterResource:
+		return h.handleUpdateCharacterResource, true
+	case AwardAssetIf:
+		return h.handleAwardAssetIf, true
+	case GrantPremiumTime:
+		return h.handleGrantPremiumTime, true
+	case ValidateDivorce:
+		return h.handleValidateDivorce, true
+	case DissolveMarriage:
+		return h.handleDissolveMarriage, true
+	case SealAsset:
+		return h.handleSealAsset, true
+	case UnsealAsset:
+		return h.handleUnsealAsset, true
+	case ConfiscateAsset:
+		return h.handleConfiscateAsset, true
+	case ResolveDispute:
+		return h.handleResolveDispute, true
+	case IssueTransportTicket:
+		return h.handleIssueTransportTicket, true
+	case ScheduleWarp:
+		return h.handleScheduleWarp, true
+	case SpawnEscort:
+		return h.handleSpawnEscort, true
+	case AwaitEscort:
+		return h.handleAwaitEscort, true
+	case UpdateCharacterAlignment:
+		return h.handleUpdateCharacterAlignment, true
+	case ResetInstanceCooldown:
+		return h.handleResetInstanceCooldown, true
+	case AuditInventory:
+		return h.handleAuditInventory, true
+	case ApplyTitleBuffOnLogin:
+		return h.handleApplyTitleBuffOnLogin, true
+	case GrantMount:
+		return h.handleGrantMount, true
+	case ApplyWorldEventBuff:
+		return h.handleApplyWorldEventBuff, true
+	case RemoveWorldEventBuff:
+		return h.handleRemoveWorldEventBuff, true
+	case SettleEventCurrency:
+		return h.handleSettleEventCurrency, true
+	case CharacterExperienceLock:
+		return h.handleCharacterExperienceLock, true
+	case CharacterExperienceUnlock:
+		return h.handleCharacterExperienceUnlock, true
+	case NpcConversationState:
+		return h.handleNpcConversationState, true
+	case EquipAssetByTemplate:
+		return h.handleEquipAssetByTemplate, true
+	case AwardPartyExperience:
+		return h.handleAwardPartyExperience, true
+	case ToggleCharacterAbility:
+		return h.handleToggleCharacterAbility, true
+	case GrantStorageCapacity:
+		return h.handleGrantStorageCapacity, true
+	case StripEquipment:
+		return h.handleStripEquipment, true
+	case SnapshotCharacter:
+		return h.handleSnapshotCharacter, true
+	case RestoreCharacter:
+		return h.handleRestoreCharacter, true
+	case AwaitEvent:
+		return h.handleAwaitEvent, true
+	case Notify:
+		return h.handleNotify, true
+	case PetEquip:
+		return h.handlePetEquip, true
+	case PetUnequip:
+		return h.handlePetUnequip, true
 	case ChangeJob:
 		return h.handleChangeJob, true
 	case CreateSkill:
 		return h.handleCreateSkill, true
 	case UpdateSkill:
 		return h.handleUpdateSkill, true
+	case ResetSkillCooldowns:
+		return h.handleResetSkillCooldowns, true
 	case ValidateCharacterState:
 		return h.handleValidateCharacterState, true
 	case RequestGuildName:
@@ -208,11 +957,55 @@ func (h *HandlerImpl) GetHandler(action Action) (ActionHandler, bool) {
 		return h.handleCreateCharacter, true
 	case CreateAndEquipAsset:
 		return h.handleCreateAndEquipAsset, true
+	case SetQuestTimer:
+		return h.handleSetQuestTimer, true
+	case AdjustPopularity:
+		return h.handleAdjustPopularity, true
+	case ResolvePrizeTable:
+		return h.handleResolvePrizeTable, true
+	case CharacterBuffCleanse:
+		return h.handleCharacterBuffCleanse, true
 
 	}
 	return nil, false
 }
 
+// completesLocally reports whether an action's handler completes the step itself, without awaiting a status event
+func completesLocally(action Action) bool {
+	switch action {
+	case ValidateCharacterState, SetQuestTimer, ResolvePrizeTable, ApplyEquipmentPreset, VerifyAccountMerge, ValidateCoupon, SetVariable, EmitAnalyticsEvent, ForEach, ValidateDivorce, AuditInventory, GrantMount, SettleEventCurrency, NpcConversationState, AwardPartyExperience, ToggleCharacterAbility, StripEquipment, SnapshotCharacter, Notify:
+		return true
+	}
+	return false
+}
+
+// recordStepPayload replaces the payload of a step in the cached saga, retaining decisions made while executing it
+func (h *HandlerImpl) recordStepPayload(s Saga, st Step[any], payload any) error {
+	return h.recordStepOutcome(s, st, payload, nil)
+}
+
+// recordStepOutcome replaces the payload of a step in the cached saga, and sets the variables the step produced, for
+// the steps which follow
+func (h *HandlerImpl) recordStepOutcome(s Saga, st Step[any], payload any, vars Variables) error {
+	err := NewProcessor(h.l, h.ctx).AtomicUpdateSaga(s.TransactionId, func(s *Saga) error {
+		idx := s.FindStepIndex(st.StepId)
+		if idx == -1 {
+			return fmt.Errorf("step [%s] not found", st.StepId)
+		}
+		s.Steps = append([]Step[any]{}, s.Steps...)
+		s.Steps[idx].Payload = payload
+		s.Steps[idx].UpdatedAt = time.Now()
+		for name, value := range vars {
+			s.Variables = s.Variables.With(name, value)
+		}
+		return nil
+	})
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to record step payload.")
+	}
+	return err
+}
+
 // logActionError logs an error that occurred during action processing
 func (h *HandlerImpl) logActionError(s Saga, st Step[any], err error, errorMsg string) {
 	h.l.WithFields(logrus.Fields{
@@ -400,7 +1193,22 @@ func (h *HandlerImpl) handleChangeJob(s Saga, st Step[any]) error {
 		return errors.New("invalid payload")
 	}
 
-	err := h.charP.ChangeJobAndEmit(s.TransactionId, payload.WorldId, payload.CharacterId, payload.ChannelId, payload.JobId)
+	c, err := h.charP.GetById(payload.CharacterId)
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to retrieve character.")
+		return err
+	}
+
+	// Record the prior job on the step, so compensation is able to revert to it
+	previous := c.JobId()
+	payload.Previous = &previous
+	// Without the prior job compensation is unable to revert the change, so the job is left unchanged
+	err = h.recordStepPayload(s, st, payload)
+	if err != nil {
+		return err
+	}
+
+	err = h.charP.ChangeJobAndEmit(s.TransactionId, payload.WorldId, payload.CharacterId, payload.ChannelId, payload.JobId)
 
 	if err != nil {
 		h.logActionError(s, st, err, "Unable to change job.")
@@ -410,217 +1218,2595 @@ func (h *HandlerImpl) handleChangeJob(s Saga, st Step[any]) error {
 	return nil
 }
 
-// handleCreateSkill handles the CreateSkill action
-func (h *HandlerImpl) handleCreateSkill(s Saga, st Step[any]) error {
-	payload, ok := st.Payload.(CreateSkillPayload)
+// handleModifyInventoryItemPosition handles the ModifyInventoryItemPosition action
+func (h *HandlerImpl) handleModifyInventoryItemPosition(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(ModifyInventoryItemPositionPayload)
 	if !ok {
 		return errors.New("invalid payload")
 	}
 
-	err := h.skillP.RequestCreateAndEmit(s.TransactionId, payload.CharacterId, payload.SkillId, payload.Level, payload.MasterLevel, payload.Expiration)
+	err := h.compP.RequestMoveAsset(s.TransactionId, payload.CharacterId, byte(payload.InventoryType), payload.Source, payload.Destination)
 
 	if err != nil {
-		h.logActionError(s, st, err, "Unable to create skill.")
+		h.logActionError(s, st, err, "Unable to move asset.")
 		return err
 	}
 
 	return nil
 }
 
-// handleUpdateSkill handles the UpdateSkill action
-func (h *HandlerImpl) handleUpdateSkill(s Saga, st Step[any]) error {
-	payload, ok := st.Payload.(UpdateSkillPayload)
+// handleApplyEquipmentPreset handles the ApplyEquipmentPreset action
+func (h *HandlerImpl) handleApplyEquipmentPreset(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(ApplyEquipmentPresetPayload)
 	if !ok {
 		return errors.New("invalid payload")
 	}
 
-	err := h.skillP.RequestUpdateAndEmit(s.TransactionId, payload.CharacterId, payload.SkillId, payload.Level, payload.MasterLevel, payload.Expiration)
+	c, err := h.compP.GetByType(payload.CharacterId, inventory.TypeValueEquip)
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to retrieve equipment compartment.")
+		return err
+	}
 
+	captured, unequips, equips, err := planEquipmentPreset(c, payload.CharacterId, payload.Items)
 	if err != nil {
-		h.logActionError(s, st, err, "Unable to update skill.")
+		h.logActionError(s, st, err, "Unable to plan equipment preset.")
 		return err
 	}
 
+	// Record the prior loadout on the step, so a failure part way through is able to restore it
+	payload.Captured = captured
+	h.recordStepPayload(s, st, payload)
+
+	// Apply the preset through dynamically added steps, which run immediately after this one. The current set is
+	// unequipped before the preset is equipped.
+	steps := make([]Step[any], 0, len(unequips)+len(equips))
+	for i, u := range unequips {
+		steps = append(steps, Step[any]{
+			StepId:  fmt.Sprintf("%s_unequip_%d", st.StepId, i+1),
+			Status:  Pending,
+			Action:  UnequipAsset,
+			Payload: u,
+		})
+	}
+	for i, e := range equips {
+		steps = append(steps, Step[any]{
+			StepId:  fmt.Sprintf("%s_equip_%d", st.StepId, i+1),
+			Status:  Pending,
+			Action:  EquipAsset,
+			Payload: e,
+		})
+	}
+
+	// Steps are inserted directly after the current step, so they are added in reverse order.
+	p := NewProcessor(h.l, h.ctx)
+	for i := len(steps) - 1; i >= 0; i-- {
+		if err = p.AddStepAfterCurrent(s.TransactionId, steps[i]); err != nil {
+			h.logActionError(s, st, err, "Unable to add equipment preset step.")
+			return err
+		}
+	}
 	return nil
 }
 
-func TransformExperienceDistributions(source []ExperienceDistributions) []character2.ExperienceDistributions {
-	target := make([]character2.ExperienceDistributions, len(source))
+// planEquipmentPreset determines the loadout currently equipped, along with the ordered operations which unequip it into
+// free inventory slots and then equip the preset items.
+func planEquipmentPreset(c compartment.Model, characterId uint32, items []EquippedItem) ([]EquippedItem, []UnequipAssetPayload, []EquipAssetPayload, error) {
+	assets := append([]asset.Model[any]{}, c.Assets()...)
+	sort.Slice(assets, func(i, j int) bool {
+		return assets[i].Slot() < assets[j].Slot()
+	})
+
+	occupied := make(map[int16]bool)
+	held := make(map[uint32][]int16)
+	for _, a := range assets {
+		occupied[a.Slot()] = true
+		if a.Slot() > 0 {
+			held[a.TemplateId()] = append(held[a.TemplateId()], a.Slot())
+		}
+	}
 
-	for i, s := range source {
-		target[i] = character2.ExperienceDistributions{
-			ExperienceType: s.ExperienceType,
-			Amount:         s.Amount,
-			Attr1:          s.Attr1,
+	captured := make([]EquippedItem, 0)
+	unequips := make([]UnequipAssetPayload, 0)
+	free := int16(1)
+	for _, a := range assets {
+		if a.Slot() >= 0 {
+			continue
 		}
+		for free <= int16(c.Capacity()) && occupied[free] {
+			free++
+		}
+		if free > int16(c.Capacity()) {
+			return nil, nil, nil, fmt.Errorf("%w: insufficient inventory space to unequip the current loadout", ErrActionRejected)
+		}
+		occupied[free] = true
+		captured = append(captured, EquippedItem{TemplateId: a.TemplateId(), Slot: a.Slot()})
+		unequips = append(unequips, UnequipAssetPayload{
+			CharacterId:   characterId,
+			InventoryType: uint32(inventory.TypeValueEquip),
+			Source:        a.Slot(),
+			Destination:   free,
+		})
+		held[a.TemplateId()] = append(held[a.TemplateId()], free)
 	}
 
-	return target
+	equips := make([]EquipAssetPayload, 0, len(items))
+	assigned := make(map[int16]bool)
+	for _, i := range items {
+		if i.Slot >= 0 {
+			return nil, nil, nil, fmt.Errorf("%w: slot [%d] for item [%d] is not an equipped slot", ErrActionRejected, i.Slot, i.TemplateId)
+		}
+		if assigned[i.Slot] {
+			return nil, nil, nil, fmt.Errorf("%w: slot [%d] is assigned more than once", ErrActionRejected, i.Slot)
+		}
+		slots := held[i.TemplateId]
+		if len(slots) == 0 {
+			return nil, nil, nil, fmt.Errorf("%w: item [%d] is not held", ErrActionRejected, i.TemplateId)
+		}
+		assigned[i.Slot] = true
+		held[i.TemplateId] = slots[1:]
+		equips = append(equips, EquipAssetPayload{
+			CharacterId:   characterId,
+			InventoryType: uint32(inventory.TypeValueEquip),
+			Source:        slots[0],
+			Destination:   i.Slot,
+		})
+	}
+	return captured, unequips, equips, nil
 }
 
-// handleValidateCharacterState handles the ValidateCharacterState action
-func (h *HandlerImpl) handleValidateCharacterState(s Saga, st Step[any]) error {
-	// Extract the payload
-	payload, ok := st.Payload.(ValidateCharacterStatePayload)
+// handleTransferCharacter handles the TransferCharacter action
+func (h *HandlerImpl) handleTransferCharacter(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(TransferCharacterPayload)
 	if !ok {
 		return errors.New("invalid payload")
 	}
+	if payload.SourceAccountId == payload.TargetAccountId {
+		return fmt.Errorf("%w: source and target account are the same", ErrActionRejected)
+	}
+
+	err := h.charP.ChangeAccountAndEmit(s.TransactionId, payload.WorldId, payload.CharacterId, payload.TargetAccountId)
 
-	// Call the validation processor
-	result, err := h.validP.ValidateCharacterState(payload.CharacterId, payload.Conditions)
 	if err != nil {
-		h.logActionError(s, st, err, "Unable to validate character state.")
+		h.logActionError(s, st, err, "Unable to transfer character.")
 		return err
 	}
 
-	// Check if validation passed
-	if !result.Passed() {
-		// If validation failed, mark the step as failed
-		err := fmt.Errorf("character state validation failed: %v", result.Details())
-		h.logActionError(s, st, err, "Character state validation failed.")
+	return nil
+}
+
+// handleRestoreAsset handles the RestoreAsset action
+func (h *HandlerImpl) handleRestoreAsset(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(RestoreAssetPayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+	if err := payload.Evidence.Validate(); err != nil {
+		err = fmt.Errorf("%w: %v", ErrActionRejected, err)
+		h.logActionError(s, st, err, "Loss evidence is invalid.")
+		return err
+	}
+
+	err := h.compP.RequestRestoreItem(s.TransactionId, payload.CharacterId, payload.TemplateId, payload.Quantity, payload.Expiration, payload.OwnerId, payload.Flag, payload.Rechargeable)
+
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to restore asset.")
+		return err
+	}
+
+	return nil
+}
+
+// handleRollbackCharacterToSnapshot handles the RollbackCharacterToSnapshot action
+func (h *HandlerImpl) handleRollbackCharacterToSnapshot(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(RollbackCharacterToSnapshotPayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+
+	err := h.charP.RollbackToSnapshotAndEmit(s.TransactionId, payload.WorldId, payload.CharacterId, payload.SnapshotId)
+
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to rollback character to snapshot.")
+		return err
+	}
+
+	return nil
+}
+
+// handleRestoreInventorySnapshot handles the RestoreInventorySnapshot action
+func (h *HandlerImpl) handleRestoreInventorySnapshot(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(RestoreInventorySnapshotPayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+
+	err := h.compP.RequestRestoreSnapshot(s.TransactionId, payload.CharacterId, payload.InventoryType, payload.SnapshotId)
+
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to restore inventory snapshot.")
+		return err
+	}
+
+	return nil
+}
+
+// handleAdjustNpcShopStock handles the AdjustNpcShopStock action
+func (h *HandlerImpl) handleAdjustNpcShopStock(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(AdjustNpcShopStockPayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+	if payload.Amount == 0 {
+		return fmt.Errorf("%w: stock adjustment amount must not be 0", ErrActionRejected)
+	}
+
+	err := h.worldP.AdjustShopStockAndEmit(s.TransactionId, payload.WorldId, payload.NpcId, payload.TemplateId, payload.Amount)
+
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to adjust npc shop stock.")
+		return err
+	}
+
+	return nil
+}
+
+// handleSetWorldEventFlag handles the SetWorldEventFlag action
+func (h *HandlerImpl) handleSetWorldEventFlag(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(SetWorldEventFlagPayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+	if payload.Key == "" {
+		return fmt.Errorf("%w: event flag key must not be empty", ErrActionRejected)
+	}
+
+	err := h.worldP.SetEventFlagAndEmit(s.TransactionId, payload.WorldId, payload.Key, payload.Value)
+
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to set world event flag.")
+		return err
+	}
+
+	return nil
+}
+
+// handleValidateCoupon handles the ValidateCoupon action
+func (h *HandlerImpl) handleValidateCoupon(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(ValidateCouponPayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+	if payload.Code == "" {
+		return fmt.Errorf("%w: coupon code must not be empty", ErrActionRejected)
+	}
+
+	c, err := h.couponP.GetByCode(payload.Code)
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to retrieve coupon.")
+		return err
+	}
+	if err = c.Redeemable(payload.AccountId, time.Now()); err != nil {
+		err = fmt.Errorf("%w: coupon [%s] cannot be redeemed by account [%d]: %s", ErrActionRejected, payload.Code, payload.AccountId, err.Error())
+		h.logActionError(s, st, err, "Coupon validation failed.")
+		return err
+	}
+
+	rewards := CouponRewards{Mesos: c.Mesos(), Items: make([]ItemPayload, 0, len(c.Items()))}
+	for _, i := range c.Items() {
+		rewards.Items = append(rewards.Items, ItemPayload{TemplateId: i.TemplateId(), Quantity: i.Quantity()})
+	}
+	payload.Rewards = &rewards
+	h.recordStepPayload(s, st, payload)
+
+	// Consume the coupon, then award its rewards, through dynamically added steps which run immediately after this one.
+	// Validation only screens the request, as sagas redeeming the same code may validate it concurrently, on any replica.
+	// The coupon service consumes the code once, atomically, for the transaction first consuming it, so no reward is
+	// awarded before the consumption succeeds, and a concurrent redemption fails its consumption instead. Steps are
+	// inserted directly after the current step, so they are added in reverse order.
+	p := NewProcessor(h.l, h.ctx)
+	for i := len(rewards.Items) - 1; i >= 0; i-- {
+		err = p.AddStepAfterCurrent(s.TransactionId, Step[any]{
+			StepId: fmt.Sprintf("%s_item_%d", st.StepId, i+1),
+			Status: Pending,
+			Action: AwardAsset,
+			Payload: AwardItemActionPayload{
+				CharacterId: payload.CharacterId,
+				Item:        rewards.Items[i],
+			},
+		})
+		if err != nil {
+			h.logActionError(s, st, err, "Unable to add coupon item step.")
+			return err
+		}
+	}
+	if rewards.Mesos != 0 {
+		err = p.AddStepAfterCurrent(s.TransactionId, Step[any]{
+			StepId: fmt.Sprintf("%s_mesos", st.StepId),
+			Status: Pending,
+			Action: AwardMesos,
+			Payload: AwardMesosPayload{
+				CharacterId: payload.CharacterId,
+				WorldId:     payload.WorldId,
+				ChannelId:   payload.ChannelId,
+				ActorType:   "SYSTEM",
+				Amount:      rewards.Mesos,
+			},
+		})
+		if err != nil {
+			h.logActionError(s, st, err, "Unable to add coupon mesos step.")
+			return err
+		}
+	}
+	err = p.AddStepAfterCurrent(s.TransactionId, Step[any]{
+		StepId: fmt.Sprintf("%s_consume", st.StepId),
+		Status: Pending,
+		Action: ConsumeCoupon,
+		Payload: ConsumeCouponPayload{
+			CharacterId: payload.CharacterId,
+			AccountId:   payload.AccountId,
+			Code:        payload.Code,
+		},
+	})
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to add consume coupon step.")
+		return err
+	}
+	return nil
+}
+
+// handleConsumeCoupon handles the ConsumeCoupon action
+func (h *HandlerImpl) handleConsumeCoupon(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(ConsumeCouponPayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+
+	// The transaction identifies the redemption, so the coupon service does not consume the coupon twice should the
+	// command be redelivered
+	err := h.couponP.ConsumeAndEmit(s.TransactionId, payload.AccountId, payload.CharacterId, payload.Code)
+
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to consume coupon.")
+		return err
+	}
+
+	return nil
+}
+
+// handleApplyCharacterExpPenalty handles the ApplyCharacterExpPenalty action
+func (h *HandlerImpl) handleApplyCharacterExpPenalty(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(ApplyCharacterExpPenaltyPayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+	if payload.Amount == 0 {
+		return fmt.Errorf("%w: experience penalty amount must not be 0", ErrActionRejected)
+	}
+
+	err := h.charP.DeductExperienceAndEmit(s.TransactionId, payload.WorldId, payload.CharacterId, payload.ChannelId, payload.Amount)
+
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to apply experience penalty.")
+		return err
+	}
+
+	return nil
+}
+
+// handleApplyDurabilityPenalty handles the ApplyDurabilityPenalty action
+func (h *HandlerImpl) handleApplyDurabilityPenalty(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(ApplyDurabilityPenaltyPayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+	if payload.Percent == 0 || payload.Percent > 100 {
+		return fmt.Errorf("%w: durability penalty percent [%d] must be between 1 and 100", ErrActionRejected, payload.Percent)
+	}
+
+	err := h.compP.RequestChangeDurability(s.TransactionId, payload.CharacterId, -int8(payload.Percent))
+
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to apply durability penalty.")
+		return err
+	}
+
+	return nil
+}
+
+// handleSetVariable handles the SetVariable action
+func (h *HandlerImpl) handleSetVariable(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(SetVariablePayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+
+	value, err := NormalizeVariable(payload.Name, payload.Value)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrActionRejected, err.Error())
+	}
+	s.Variables = s.Variables.With(payload.Name, value)
+	GetCache().Put(h.t.Id(), s)
+
+	h.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        st.StepId,
+		"tenant_id":      h.t.Id().String(),
+	}).Debugf("Set saga variable [%s].", payload.Name)
+	return nil
+}
+
+// handleHttpRequest handles the HttpRequest action. The request, retries included, is made apart from the dispatch of
+// the step, which would otherwise be held for its duration, and completes the step once made, as a status event would.
+func (h *HandlerImpl) handleHttpRequest(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(HttpRequestPayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+	if _, err := GetHttpRequestConfig().target(payload); err != nil {
+		return fmt.Errorf("%w: %s", ErrActionRejected, err.Error())
+	}
+	if GetShadowConfig().Enabled {
+		// A shadow replica never calls out, so the step awaits the outcome of the active replicas' request
+		h.l.WithField("step_id", st.StepId).Debugf("Not requesting [%s] as a shadow replica.", payload.Url)
+		return nil
+	}
+
+	// The request outlives the context of the dispatch which started it
+	d := *h
+	d.ctx = tenant.WithContext(context.Background(), h.t)
+	go func() {
+		err := d.performHttpRequest(s, st, payload)
+		d.completeHttpRequest(s, st, err)
+	}()
+	return nil
+}
+
+// performHttpRequest makes the request of an http_request step, recording the response on the step and setting the
+// variables captured from it
+func (h *HandlerImpl) performHttpRequest(s Saga, st Step[any], payload HttpRequestPayload) error {
+	res, err := executeHttpRequest(h.ctx, GetHttpRequestConfig(), payload)
+	payload.Response = res
+	var vars Variables
+	if err == nil {
+		var body any
+		if res != nil {
+			body = res.Body
+		}
+		vars, err = captureVariables(nil, payload.Capture, body)
+	}
+	h.recordStepOutcome(s, st, payload, vars)
+
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to complete HTTP request.")
+		return err
+	}
+	return nil
+}
+
+// completeHttpRequest completes the http_request step once its request has been made, or fails it should the request
+// have failed. Steps which have since progressed or been attempted again, or sagas which are compensating, are left
+// alone.
+func (h *HandlerImpl) completeHttpRequest(s Saga, st Step[any], err error) {
+	p := NewProcessor(h.l, h.ctx)
+	cs, gerr := p.GetById(s.TransactionId)
+	if gerr != nil {
+		return
+	}
+	cst, ok := cs.GetCurrentStep()
+	if !ok || cs.Failing() || cst.StepId != st.StepId || len(cst.Attempts) != len(st.Attempts) {
+		return
+	}
+	if err = p.StepCompleted(s.TransactionId, err == nil); err != nil {
+		h.logActionError(s, st, err, "Unable to complete HTTP request step.")
+	}
+}
+
+// handleCreateAccountCharacterSlot handles the CreateAccountCharacterSlot action
+func (h *HandlerImpl) handleCreateAccountCharacterSlot(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(CreateAccountCharacterSlotPayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+	if payload.Amount == 0 {
+		return fmt.Errorf("%w: character slot amount must not be 0", ErrActionRejected)
+	}
+
+	err := h.acctP.ChangeCharacterSlotsAndEmit(s.TransactionId, payload.AccountId, payload.WorldId, int16(payload.Amount))
+
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to add character slots.")
+		return err
+	}
+
+	return nil
+}
+
+// handleVerifyAccountMerge handles the VerifyAccountMerge action
+func (h *HandlerImpl) handleVerifyAccountMerge(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(VerifyAccountMergePayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+
+	conditions := []validation.ConditionInput{
+		{Type: string(validation.AccountCondition), Operator: string(validation.Equals), Value: int(payload.TargetAccountId)},
+	}
+	mismatched := make([]uint32, 0)
+	for _, id := range payload.CharacterIds {
+		result, err := h.validP.ValidateCharacterState(id, conditions)
+		if err != nil {
+			h.logActionError(s, st, err, "Unable to verify character account.")
+			return err
+		}
+		if !result.Passed() {
+			mismatched = append(mismatched, id)
+		}
+	}
+
+	if len(mismatched) > 0 {
+		err := fmt.Errorf("%w: characters %v are not owned by account [%d]", ErrActionRejected, mismatched, payload.TargetAccountId)
+		h.logActionError(s, st, err, "Account merge verification failed.")
+		return err
+	}
+	return nil
+}
+
+// handleCreateSkill handles the CreateSkill action
+func (h *HandlerImpl) handleCreateSkill(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(CreateSkillPayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+
+	err := h.skillP.RequestCreateAndEmit(s.TransactionId, payload.CharacterId, payload.SkillId, payload.Level, payload.MasterLevel, payload.Expiration)
+
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to create skill.")
+		return err
+	}
+
+	return nil
+}
+
+// handleUpdateSkill handles the UpdateSkill action
+func (h *HandlerImpl) handleUpdateSkill(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(UpdateSkillPayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+
+	err := h.skillP.RequestUpdateAndEmit(s.TransactionId, payload.CharacterId, payload.SkillId, payload.Level, payload.MasterLevel, payload.Expiration)
+
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to update skill.")
+		return err
+	}
+
+	return nil
+}
+
+// handleResetSkillCooldowns handles the ResetSkillCooldowns action
+func (h *HandlerImpl) handleResetSkillCooldowns(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(ResetSkillCooldownsPayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+
+	err := h.skillP.ResetCooldownsAndEmit(s.TransactionId, payload.CharacterId, payload.SkillIds)
+
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to reset skill cooldowns.")
+		return err
+	}
+
+	return nil
+}
+
+func TransformExperienceDistributions(source []ExperienceDistributions) []character2.ExperienceDistributions {
+	target := make([]character2.ExperienceDistributions, len(source))
+
+	for i, s := range source {
+		target[i] = character2.ExperienceDistributions{
+			ExperienceType: s.ExperienceType,
+			Amount:         s.Amount,
+			Attr1:          s.Attr1,
+		}
+	}
+
+	return target
+}
+
+// handleValidateCharacterState handles the ValidateCharacterState action
+func (h *HandlerImpl) handleValidateCharacterState(s Saga, st Step[any]) error {
+	// Extract the payload
+	payload, ok := st.Payload.(ValidateCharacterStatePayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+
+	// Call the validation processor
+	result, err := h.validP.ValidateCharacterState(payload.CharacterId, payload.Conditions)
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to validate character state.")
+		return err
+	}
+
+	// A step declaring branches records the outcome for them to select on, rather than failing
+	if len(st.Branches) > 0 {
+		passed := result.Passed()
+		payload.Passed = &passed
+		h.recordStepPayload(s, st, payload)
+		return nil
+	}
+
+	// Check if validation passed
+	if !result.Passed() {
+		// If validation failed, mark the step as failed
+		err := fmt.Errorf("%w: character state validation failed: %v", ErrActionRejected, result.Details())
+		h.logActionError(s, st, err, "Character state validation failed.")
+		return err
+	}
+
+	return nil
+}
+
+// handleRequestGuildName handles the RequestGuildName action
+func (h *HandlerImpl) handleRequestGuildName(s Saga, st Step[any]) error {
+	// Extract the payload
+	payload, ok := st.Payload.(RequestGuildNamePayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+
+	// Call the guild processor
+	err := h.guildP.RequestName(s.TransactionId, payload.WorldId, payload.ChannelId, payload.CharacterId)
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to request guild name.")
+		return err
+	}
+
+	return nil
+}
+
+// handleRequestGuildEmblem handles the RequestGuildEmblem action
+func (h *HandlerImpl) handleRequestGuildEmblem(s Saga, st Step[any]) error {
+	// Extract the payload
+	payload, ok := st.Payload.(RequestGuildEmblemPayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+
+	// Call the guild processor
+	err := h.guildP.RequestEmblem(s.TransactionId, payload.WorldId, payload.ChannelId, payload.CharacterId)
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to request guild emblem.")
+		return err
+	}
+
+	return nil
+}
+
+// handleRequestGuildDisband handles the RequestGuildDisband action
+func (h *HandlerImpl) handleRequestGuildDisband(s Saga, st Step[any]) error {
+	// Extract the payload
+	payload, ok := st.Payload.(RequestGuildDisbandPayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+
+	// Call the guild processor
+	err := h.guildP.RequestDisband(s.TransactionId, payload.WorldId, payload.ChannelId, payload.CharacterId)
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to request guild disband.")
+		return err
+	}
+
+	return nil
+}
+
+// handleRequestGuildCapacityIncrease handles the RequestGuildCapacityIncrease action
+func (h *HandlerImpl) handleRequestGuildCapacityIncrease(s Saga, st Step[any]) error {
+	// Extract the payload
+	payload, ok := st.Payload.(RequestGuildCapacityIncreasePayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+
+	// Call the guild processor
+	err := h.guildP.RequestCapacityIncrease(s.TransactionId, payload.WorldId, payload.ChannelId, payload.CharacterId)
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to request guild capacity increase.")
+		return err
+	}
+
+	return nil
+}
+
+// handleCreateInvite handles the CreateInvite action
+func (h *HandlerImpl) handleCreateInvite(s Saga, st Step[any]) error {
+	// Extract the payload
+	payload, ok := st.Payload.(CreateInvitePayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+
+	// An invitation with a TTL awaits being answered, so record the TTL applied on the step before it is created
+	if payload.Ttl == 0 {
+		payload.Ttl = uint32(GetInviteConfig().Ttl / time.Second)
+	}
+	if payload.Ttl > 0 {
+		h.recordStepPayload(s, st, payload)
+	}
+
+	// Call the invite processor
+	err := h.inviteP.Create(s.TransactionId, payload.InviteType, payload.OriginatorId, payload.WorldId, payload.ReferenceId, payload.TargetId)
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to create invitation.")
+		return err
+	}
+
+	if payload.Ttl > 0 {
+		startDetachedTimer(h.l, h.t, s.TransactionId, st.StepId, time.Duration(payload.Ttl)*time.Second, inviteExpired)
+	}
+
+	return nil
+}
+
+// handleCreateCharacter handles the CreateCharacter action
+func (h *HandlerImpl) handleCreateCharacter(s Saga, st Step[any]) error {
+	// Extract the payload
+	payload, ok := st.Payload.(CharacterCreatePayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+
+	// Call the character processor
+	err := h.charP.RequestCreateCharacter(s.TransactionId, payload.AccountId, payload.WorldId, payload.Name, payload.Level, payload.Strength, payload.Dexterity, payload.Intelligence, payload.Luck, payload.Hp, payload.Mp, payload.JobId, payload.Gender, payload.Face, payload.Hair, payload.Skin, payload.MapId)
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to create character.")
+		return err
+	}
+
+	return nil
+}
+
+// handleCreateAndEquipAsset handles the CreateAndEquipAsset action
+// This is a compound action that first creates an asset (internally using award_asset semantics)
+// and then dynamically creates an equip_asset step when the creation succeeds
+func (h *HandlerImpl) handleCreateAndEquipAsset(s Saga, st Step[any]) error {
+	// Extract the payload
+	payload, ok := st.Payload.(CreateAndEquipAssetPayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+
+	// Step 1: Internal award_asset - Create the item using the same logic as handleAwardAsset
+	// Convert saga payload to compartment payload to avoid import cycle
+	compartmentPayload := compartment.CreateAndEquipAssetPayload{
+		CharacterId: payload.CharacterId,
+		Item: compartment.ItemPayload{
+			TemplateId: payload.Item.TemplateId,
+			Quantity:   payload.Item.Quantity,
+		},
+	}
+
+	err := h.compP.RequestCreateAndEquipAsset(s.TransactionId, compartmentPayload)
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to create asset for create_and_equip_asset.")
+		return err
+	}
+
+	// Note: Step 2 (dynamic equip_asset step creation) will be handled by the compartment consumer
+	// when it receives the StatusEventTypeCreated event from the compartment service.
+	// The consumer will detect this is a CreateAndEquipAsset step and add the equip_asset step.
+
+	return nil
+}
+
+// handleSetQuestTimer handles the SetQuestTimer action
+// The countdown is tracked by the orchestrator, so the step completes as soon as the countdown is started.
+// If the saga is still in progress when the countdown expires, the configured expiry behavior is applied.
+func (h *HandlerImpl) handleSetQuestTimer(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(SetQuestTimerPayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+
+	if payload.Duration == 0 {
+		return fmt.Errorf("%w: quest timer duration must be greater than zero", ErrActionRejected)
+	}
+	switch payload.OnExpiry {
+	case "", TimerExpiryCompensate:
+	case TimerExpiryFollowUpSaga:
+		if payload.FollowUp == nil {
+			return fmt.Errorf("%w: quest timer follow up saga not provided", ErrActionRejected)
+		}
+	default:
+		return fmt.Errorf("%w: unknown quest timer expiry behavior [%s]", ErrActionRejected, payload.OnExpiry)
+	}
+
+	startDetachedTimer(h.l, h.t, s.TransactionId, st.StepId, time.Duration(payload.Duration)*time.Second, func(l logrus.FieldLogger, ctx context.Context, transactionId uuid.UUID, stepId string) {
+		questTimerExpired(l, ctx, transactionId, stepId, payload)
+	})
+
+	h.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        st.StepId,
+		"character_id":   payload.CharacterId,
+		"quest_id":       payload.QuestId,
+		"tenant_id":      h.t.Id().String(),
+	}).Debugf("Started quest timer of [%d] seconds.", payload.Duration)
+
+	return nil
+}
+
+// questTimerExpired applies the expiry behavior of a quest timer to a saga which has not yet completed
+func questTimerExpired(l logrus.FieldLogger, ctx context.Context, transactionId uuid.UUID, stepId string, payload SetQuestTimerPayload) {
+	p := NewProcessor(l, ctx)
+	s, err := p.GetById(transactionId)
+	if err != nil {
+		return
+	}
+
+	fl := l.WithFields(logrus.Fields{
+		"transaction_id": transactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        stepId,
+		"quest_id":       payload.QuestId,
+		"tenant_id":      tenant.MustFromContext(ctx).Id().String(),
+	})
+
+	switch payload.OnExpiry {
+	case TimerExpiryFollowUpSaga:
+		fs := *payload.FollowUp
+		if fs.TransactionId == uuid.Nil {
+			fs.TransactionId = uuid.New()
+		}
+		fl.Debugf("Quest timer expired. Starting follow up saga [%s].", fs.TransactionId.String())
+		err = p.Put(fs)
+	default:
+		if s.Failing() {
+			return
+		}
+		// The saga's current step is not that of the timer, so is failed without awaiting its outcome, as a timed out step is
+		fl.Debug("Quest timer expired. Failing saga to trigger compensation.")
+		err = p.StepAbandoned(transactionId, ErrorCodeQuestTimerExpired, fmt.Sprintf("quest timer of step [%s] expired", stepId))
+	}
+	if err != nil {
+		fl.WithError(err).Error("Unable to apply quest timer expiry.")
+	}
+}
+
+// handleAdjustPopularity handles the AdjustPopularity action
+func (h *HandlerImpl) handleAdjustPopularity(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(AdjustPopularityPayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+	if payload.Amount != 1 && payload.Amount != -1 {
+		return fmt.Errorf("%w: fame amount must be 1 or -1, got %d", ErrActionRejected, payload.Amount)
+	}
+	if payload.CharacterId == payload.TargetId {
+		return fmt.Errorf("%w: character cannot give fame to themselves", ErrActionRejected)
+	}
+
+	// A character may give fame once per day, and to the same character once per month
+	conditions := []validation.ConditionInput{
+		{Type: string(validation.DailyFameCondition), Operator: string(validation.Equals), Value: 0},
+		{Type: string(validation.MonthlyFameTargetCondition), Operator: string(validation.Equals), Value: 0, ReferenceId: payload.TargetId},
+	}
+	result, err := h.validP.ValidateCharacterState(payload.CharacterId, conditions)
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to validate fame cooldown.")
+		return err
+	}
+
+	// Record the decision on the step, so it is retained with the saga for audit
+	payload.CooldownCheck = &CooldownCheckResult{
+		Passed:    result.Passed(),
+		Details:   result.Details(),
+		CheckedAt: time.Now(),
+	}
+	h.recordStepPayload(s, st, payload)
+
+	if !result.Passed() {
+		err = fmt.Errorf("%w: fame cooldown active: %v", ErrActionRejected, result.Details())
+		h.logActionError(s, st, err, "Fame cooldown validation failed.")
+		return err
+	}
+
+	err = h.charP.AwardFameAndEmit(s.TransactionId, payload.WorldId, payload.TargetId, payload.CharacterId, "CHARACTER", payload.Amount)
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to award fame.")
+		return err
+	}
+
+	return nil
+}
+
+// handleResolvePrizeTable handles the ResolvePrizeTable action
+func (h *HandlerImpl) handleResolvePrizeTable(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(ResolvePrizeTablePayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+
+	total := uint32(0)
+	for _, p := range payload.Prizes {
+		total += p.Weight
+	}
+	if total == 0 {
+		return fmt.Errorf("%w: prize table has no weighted entries", ErrActionRejected)
+	}
+
+	prize := resolvePrize(payload.Prizes, uint32(rand.Int63n(int64(total))))
+	payload.Resolved = &prize
+	h.recordStepPayload(s, st, payload)
+
+	h.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        st.StepId,
+		"character_id":   payload.CharacterId,
+		"tenant_id":      h.t.Id().String(),
+	}).Debugf("Resolved prize table entry with weight [%d] of [%d].", prize.Weight, total)
+
+	// Award the prize through dynamically added steps, which run immediately after this one. Steps are inserted
+	// directly after the current step, so they are added in reverse order.
+	p := NewProcessor(h.l, h.ctx)
+	if prize.Mesos != 0 {
+		err := p.AddStepAfterCurrent(s.TransactionId, Step[any]{
+			StepId: fmt.Sprintf("%s_mesos", st.StepId),
+			Status: Pending,
+			Action: AwardMesos,
+			Payload: AwardMesosPayload{
+				CharacterId: payload.CharacterId,
+				WorldId:     payload.WorldId,
+				ChannelId:   payload.ChannelId,
+				ActorType:   "SYSTEM",
+				Amount:      prize.Mesos,
+			},
+		})
+		if err != nil {
+			h.logActionError(s, st, err, "Unable to add prize mesos step.")
+			return err
+		}
+	}
+	if prize.Item != nil {
+		err := p.AddStepAfterCurrent(s.TransactionId, Step[any]{
+			StepId: fmt.Sprintf("%s_item", st.StepId),
+			Status: Pending,
+			Action: AwardAsset,
+			Payload: AwardItemActionPayload{
+				CharacterId: payload.CharacterId,
+				Item:        *prize.Item,
+			},
+		})
+		if err != nil {
+			h.logActionError(s, st, err, "Unable to add prize item step.")
+			return err
+		}
+	}
+	return nil
+}
+
+// handleCharacterBuffCleanse handles the CharacterBuffCleanse action
+func (h *HandlerImpl) handleCharacterBuffCleanse(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(CharacterBuffCleansePayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+
+	// Capture the active buffs before they are removed, so compensation is able to re-apply them
+	bs, err := h.buffP.GetByCharacterId(payload.CharacterId)
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to retrieve active buffs.")
+		return err
+	}
+	payload.Captured = make([]CapturedBuff, 0, len(bs))
+	for _, b := range bs {
+		changes := make([]BuffStatChange, 0, len(b.Changes()))
+		for _, c := range b.Changes() {
+			changes = append(changes, BuffStatChange{Type: c.Type(), Amount: c.Amount()})
+		}
+		payload.Captured = append(payload.Captured, CapturedBuff{
+			SourceId:  b.SourceId(),
+			Duration:  b.Duration(),
+			Changes:   changes,
+			ExpiresAt: b.ExpiresAt(),
+		})
+	}
+	h.recordStepPayload(s, st, payload)
+
+	err = h.buffP.CancelAllAndEmit(s.TransactionId, payload.WorldId, payload.ChannelId, payload.CharacterId)
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to cleanse buffs.")
+		return err
+	}
+
+	return nil
+}
+
+// resolvePrize returns the prize table entry in which the roll, in the range [0, total weight), falls
+func resolvePrize(prizes []PrizeEntry, roll uint32) PrizeEntry {
+	for _, p := range prizes {
+		if roll < p.Weight {
+			return p
+		}
+		roll -= p.Weight
+	}
+	return PrizeEntry{}
+}
+
+// handleEmitAnalyticsEvent handles the EmitAnalyticsEvent action. Analytics are best-effort, so an event which cannot be
+// published is logged rather than failing the saga.
+func (h *HandlerImpl) handleEmitAnalyticsEvent(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(EmitAnalyticsEventPayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+	fl := h.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        st.StepId,
+		"event":          payload.Name,
+		"tenant_id":      h.t.Id().String(),
+	})
+	if payload.Name == "" {
+		fl.Warn("Skipping analytics event without a name.")
+		return nil
+	}
+
+	err := h.analytP.PublishAndEmit(analytics2.Event{
+		TransactionId: s.TransactionId,
+		SagaType:      string(s.SagaType),
+		InitiatedBy:   s.InitiatedBy,
+		StepId:        st.StepId,
+		Labels:        s.Labels,
+		Name:          payload.Name,
+		Properties:    payload.Properties,
+		OccurredAt:    time.Now(),
+	})
+	if err != nil {
+		fl.WithError(err).Warn("Unable to publish analytics event.")
+	}
+	return nil
+}
+
+// handleModifyAssetExpiration handles the ModifyAssetExpiration action
+func (h *HandlerImpl) handleModifyAssetExpiration(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(ModifyAssetExpirationPayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+	if payload.Expiration.IsZero() == (payload.ExtendBy == 0) {
+		return fmt.Errorf("%w: exactly one of expiration or extendBy must be given", ErrActionRejected)
+	}
+
+	c, err := h.compP.GetByType(payload.CharacterId, inventory.Type(payload.InventoryType))
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to retrieve compartment.")
+		return err
+	}
+	a, ok := c.FindBySlot(payload.Slot)
+	if !ok {
+		return fmt.Errorf("%w: no asset in slot [%d] of inventory [%d]", ErrActionRejected, payload.Slot, payload.InventoryType)
+	}
+	if payload.TemplateId != 0 && a.TemplateId() != payload.TemplateId {
+		return fmt.Errorf("%w: asset in slot [%d] is [%d], not [%d]", ErrActionRejected, payload.Slot, a.TemplateId(), payload.TemplateId)
+	}
+
+	previous := a.Expiration()
+	expiration := payload.Expiration
+	if payload.ExtendBy > 0 {
+		if previous.IsZero() {
+			return fmt.Errorf("%w: asset in slot [%d] does not expire", ErrActionRejected, payload.Slot)
+		}
+		// An already expired rental is extended from now, rather than from when it expired
+		from := previous
+		if now := time.Now(); from.Before(now) {
+			from = now
+		}
+		expiration = from.Add(time.Duration(payload.ExtendBy) * time.Second)
+	}
+
+	// Record the prior expiration on the step, so compensation is able to restore it
+	payload.Previous = &previous
+	h.recordStepPayload(s, st, payload)
+
+	err = h.compP.RequestChangeExpiration(s.TransactionId, payload.CharacterId, payload.InventoryType, payload.Slot, expiration)
+
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to change asset expiration.")
+		return err
+	}
+
+	return nil
+}
+
+// handleApplyHammer handles the ApplyHammer action
+func (h *HandlerImpl) handleApplyHammer(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(ApplyHammerPayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+	if payload.Slots == 0 {
+		return fmt.Errorf("%w: hammer must add at least one upgrade slot", ErrActionRejected)
+	}
+
+	c, err := h.compP.GetByType(payload.CharacterId, inventory.TypeValueEquip)
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to retrieve equipment compartment.")
+		return err
+	}
+	a, ok := c.FindBySlot(payload.Slot)
+	if !ok {
+		return fmt.Errorf("%w: no equip in slot [%d]", ErrActionRejected, payload.Slot)
+	}
+	if payload.TemplateId != 0 && a.TemplateId() != payload.TemplateId {
+		return fmt.Errorf("%w: equip in slot [%d] is [%d], not [%d]", ErrActionRejected, payload.Slot, a.TemplateId(), payload.TemplateId)
+	}
+	rd, ok := a.ReferenceData().(asset.EquipableReferenceData)
+	if !a.IsEquipable() || !ok {
+		return fmt.Errorf("%w: asset in slot [%d] cannot be hammered", ErrActionRejected, payload.Slot)
+	}
+	if payload.MaxHammers > 0 && rd.HammersApplied() >= payload.MaxHammers {
+		return fmt.Errorf("%w: equip in slot [%d] already has [%d] hammers applied", ErrActionRejected, payload.Slot, rd.HammersApplied())
+	}
+	if uint32(rd.Slots())+uint32(payload.Slots) > math.MaxUint16 {
+		return fmt.Errorf("%w: equip in slot [%d] cannot hold [%d] more upgrade slots", ErrActionRejected, payload.Slot, payload.Slots)
+	}
+
+	// Record the equip's upgrade slots on the step, so compensation is able to restore them
+	payload.Snapshot = &UpgradeSlotsPayload{Slots: rd.Slots(), HammersApplied: rd.HammersApplied()}
+	h.recordStepPayload(s, st, payload)
+
+	err = h.compP.RequestSetUpgradeSlots(s.TransactionId, payload.CharacterId, payload.Slot, rd.Slots()+payload.Slots, rd.HammersApplied()+1)
+
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to apply hammer.")
+		return err
+	}
+
+	return nil
+}
+
+// handleDeductMesos handles the DeductMesos action
+func (h *HandlerImpl) handleDeductMesos(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(DeductMesosPayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+	if payload.Amount == 0 || payload.Amount > math.MaxInt32 {
+		return fmt.Errorf("%w: meso deduction [%d] must be between 1 and %d", ErrActionRejected, payload.Amount, math.MaxInt32)
+	}
+
+	// The character must have the mesos deducted, so a deduction never takes them below 0
+	conditions := []validation.ConditionInput{
+		{Type: string(validation.MesoCondition), Operator: string(validation.GreaterEqual), Value: int(payload.Amount)},
+	}
+	result, err := h.validP.ValidateCharacterState(payload.CharacterId, conditions)
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to validate character mesos.")
+		return err
+	}
+
+	// Record the decision on the step, so it is retained with the saga for audit
+	payload.FundsCheck = &FundsCheckResult{
+		Passed:    result.Passed(),
+		Details:   result.Details(),
+		CheckedAt: time.Now(),
+	}
+	h.recordStepPayload(s, st, payload)
+
+	if !result.Passed() {
+		err = RejectedError{Code: ErrorCodeInsufficientMesos, Reason: fmt.Sprintf("insufficient mesos: %v", result.Details())}
+		h.logActionError(s, st, err, "Character lacks the mesos deducted.")
+		return err
+	}
+
+	err = h.charP.AwardMesosAndEmit(s.TransactionId, payload.WorldId, payload.CharacterId, payload.ChannelId, payload.ActorId, payload.ActorType, -int32(payload.Amount))
+
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to deduct mesos.")
+		return err
+	}
+
+	return nil
+}
+
+// handleForEach handles the ForEach action
+func (h *HandlerImpl) handleForEach(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(ForEachPayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+
+	steps, iterations, err := expandForEach(s, st, payload)
+	if err != nil {
+		err = fmt.Errorf("%w: %s", ErrActionRejected, err.Error())
+		h.logActionError(s, st, err, "Unable to expand iterations.")
+		return err
+	}
+
+	// Record the iterations on the step, so their progress can be tracked
+	payload.Iterations = iterations
+	h.recordStepPayload(s, st, payload)
+
+	// Steps are inserted directly after the current step, so they are added in reverse order.
+	p := NewProcessor(h.l, h.ctx)
+	for i := len(steps) - 1; i >= 0; i-- {
+		if err = p.AddStepAfterCurrent(s.TransactionId, steps[i]); err != nil {
+			h.logActionError(s, st, err, "Unable to add iteration step.")
+			return err
+		}
+	}
+	return nil
+}
+
+// handleVerifyAndConsumeTicket handles the VerifyAndConsumeTicket action
+func (h *HandlerImpl) handleVerifyAndConsumeTicket(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(VerifyAndConsumeTicketPayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+	if payload.Quantity == 0 {
+		payload.Quantity = 1
+	}
+	it, ok := inventory.TypeFromItemId(item.Id(payload.TemplateId))
+	if !ok {
+		return fmt.Errorf("%w: [%d] is not an item", ErrActionRejected, payload.TemplateId)
+	}
+
+	c, err := h.compP.GetByType(payload.CharacterId, it)
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to retrieve compartment.")
+		return err
+	}
+	held := uint32(0)
+	for _, a := range c.Assets() {
+		if a.TemplateId() == payload.TemplateId {
+			held += a.Quantity()
+		}
+	}
+	a, ok := c.FindFirstByItemId(payload.TemplateId)
+	if !ok || held < payload.Quantity {
+		err = fmt.Errorf("%w: character holds [%d] of ticket [%d], requiring [%d]", ErrActionRejected, held, payload.TemplateId, payload.Quantity)
+		h.logActionError(s, st, err, "Ticket verification failed.")
+		return err
+	}
+
+	// Record the proof on the step, and in the saga's variables when requested, for the steps which follow
+	payload.Proof = &TicketProof{
+		AssetId:    a.Id(),
+		Slot:       a.Slot(),
+		TemplateId: payload.TemplateId,
+		Quantity:   payload.Quantity,
+		ConsumedAt: time.Now(),
+	}
+	var vars Variables
+	if payload.Variable != "" {
+		value, err := NormalizeVariable(payload.Variable, payload.Proof)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrActionRejected, err.Error())
+		}
+		vars = vars.With(payload.Variable, value)
+	}
+	h.recordStepOutcome(s, st, payload, vars)
+
+	err = h.compP.RequestDestroyItem(s.TransactionId, payload.CharacterId, payload.TemplateId, payload.Quantity)
+
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to consume ticket.")
+		return err
+	}
+
+	return nil
+}
+
+// handleAwaitKillCount handles the AwaitKillCount action. Kills are counted as monster status events arrive, so the step
+// completes once the target count is reached, or fails when its timeout elapses first.
+func (h *HandlerImpl) handleAwaitKillCount(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(AwaitKillCountPayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+	if payload.Count == 0 {
+		return fmt.Errorf("%w: kill count must be positive", ErrActionRejected)
+	}
+
+	if payload.Timeout > 0 {
+		startDetachedTimer(h.l, h.t, s.TransactionId, st.StepId, time.Duration(payload.Timeout)*time.Second, killCountExpired)
+	}
+
+	h.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        st.StepId,
+		"map_id":         payload.MapId,
+		"count":          payload.Count,
+		"tenant_id":      h.t.Id().String(),
+	}).Debug("Awaiting monster kills.")
+	return nil
+}
+
+// handleAdjustReactorState handles the AdjustReactorState action
+func (h *HandlerImpl) handleAdjustReactorState(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(AdjustReactorStatePayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+	f, ok := field.FromId(payload.FieldId)
+	if !ok {
+		return fmt.Errorf("%w: invalid field id '%s'", ErrActionRejected, payload.FieldId)
+	}
+
+	err := h.reactP.ChangeStateAndEmit(s.TransactionId, f, payload.ReactorId, payload.State)
+
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to change reactor state.")
+		return err
+	}
+
+	return nil
+}
+
+// handleHitReactor handles the HitReactor action
+func (h *HandlerImpl) handleHitReactor(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(HitReactorPayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+	f, ok := field.FromId(payload.FieldId)
+	if !ok {
+		return fmt.Errorf("%w: invalid field id '%s'", ErrActionRejected, payload.FieldId)
+	}
+
+	err := h.reactP.HitAndEmit(s.TransactionId, f, payload.ReactorId, payload.CharacterId, payload.Stance, payload.SkillId)
+
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to hit reactor.")
+		return err
+	}
+
+	return nil
+}
+
+// handleUpdateCharacterResource handles the UpdateCharacterResource action
+func (h *HandlerImpl) handleUpdateCharacterResource(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(UpdateCharacterResourcePayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+	switch payload.Resource {
+	case character2.ResourceEnergyCharge, character2.ResourceComboCounter:
+	default:
+		return fmt.Errorf("%w: unknown resource [%s]", ErrActionRejected, payload.Resource)
+	}
+	if (payload.Value == nil) == (payload.Amount == 0) {
+		return fmt.Errorf("%w: exactly one of value or amount must be given", ErrActionRejected)
+	}
+	if payload.Value != nil && *payload.Value < 0 {
+		return fmt.Errorf("%w: value [%d] must not be negative", ErrActionRejected, *payload.Value)
+	}
+
+	rs, err := h.charP.GetResources(payload.CharacterId)
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to retrieve character resources.")
+		return err
+	}
+	// A resource the character has never accumulated is empty
+	previous := int32(0)
+	for _, r := range rs {
+		if r.Type() == payload.Resource {
+			previous = r.Value()
+		}
+	}
+
+	value := previous + payload.Amount
+	if payload.Value != nil {
+		value = *payload.Value
+	}
+	// Resources are never negative, so an adjustment beyond the current value empties the resource
+	if value < 0 {
+		value = 0
+	}
+
+	// Record the prior value on the step, so compensation is able to restore it
+	payload.Previous = &previous
+	h.recordStepPayload(s, st, payload)
+
+	err = h.charP.ChangeResourceAndEmit(s.TransactionId, payload.WorldId, payload.CharacterId, payload.ChannelId, payload.Resource, value)
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to change character resource.")
+		return err
+	}
+
+	return nil
+}
+
+// handleAwardAssetIf handles the AwardAssetIf action, validating the character's state and, should the conditions hold,
+// awarding the asset as AwardAsset would
+func (h *HandlerImpl) handleAwardAssetIf(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(AwardAssetIfPayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+	if len(payload.Conditions) == 0 {
+		return fmt.Errorf("%w: at least one condition must be given", ErrActionRejected)
+	}
+
+	result, err := h.validP.ValidateCharacterState(payload.CharacterId, payload.Conditions)
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to validate character state.")
+		return err
+	}
+	if !result.Passed() {
+		err := fmt.Errorf("%w: character state validation failed: %v", ErrActionRejected, result.Details())
+		h.logActionError(s, st, err, "Character state validation failed.")
+		return err
+	}
+
+	award := st
+	award.Payload = payload.Award()
+	return h.handleAwardAsset(s, award)
+}
+
+// handleGrantPremiumTime handles the GrantPremiumTime action
+func (h *HandlerImpl) handleGrantPremiumTime(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(GrantPremiumTimePayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+	if payload.Seconds == 0 {
+		return fmt.Errorf("%w: premium time seconds must not be 0", ErrActionRejected)
+	}
+
+	err := h.acctP.ChangePremiumTimeAndEmit(s.TransactionId, payload.AccountId, int64(payload.Seconds))
+
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to grant premium time.")
+		return err
+	}
+
+	return nil
+}
+
+// handleValidateDivorce handles the ValidateDivorce action
+func (h *HandlerImpl) handleValidateDivorce(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(ValidateDivorcePayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+
+	m, err := h.marriP.GetByCharacterId(payload.CharacterId)
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to retrieve marriage.")
+		return err
+	}
+	if err = m.Divorceable(payload.PartnerId); err != nil {
+		err = fmt.Errorf("%w: character [%d] cannot divorce: %s", ErrActionRejected, payload.CharacterId, err.Error())
+		h.logActionError(s, st, err, "Divorce validation failed.")
+		return err
+	}
+
+	record := MarriageRecord{MarriageId: m.Id(), PartnerId: m.PartnerId(), RingTemplateId: m.RingTemplateId()}
+	payload.Marriage = &record
+	h.recordStepPayload(s, st, payload)
+
+	// Deduct the fee, dissolve the marriage, then destroy the couple's rings, through dynamically added steps which run
+	// immediately after this one. The rings cannot be restored, so are destroyed last. Steps are inserted directly after
+	// the current step, so they are added in reverse order.
+	steps := make([]Step[any], 0, 4)
+	if payload.Fee > 0 {
+		steps = append(steps, Step[any]{
+			StepId: fmt.Sprintf("%s_fee", st.StepId),
+			Status: Pending,
+			Action: DeductMesos,
+			Payload: DeductMesosPayload{
+				CharacterId: payload.CharacterId,
+				WorldId:     payload.WorldId,
+				ChannelId:   payload.ChannelId,
+				ActorType:   "SYSTEM",
+				Amount:      payload.Fee,
+			},
+		})
+	}
+	steps = append(steps, Step[any]{
+		StepId: fmt.Sprintf("%s_dissolve", st.StepId),
+		Status: Pending,
+		Action: DissolveMarriage,
+		Payload: DissolveMarriagePayload{
+			CharacterId: payload.CharacterId,
+			MarriageId:  record.MarriageId,
+			PartnerId:   record.PartnerId,
+		},
+	})
+	if !payload.KeepRings && record.RingTemplateId != 0 {
+		for i, characterId := range []uint32{payload.CharacterId, record.PartnerId} {
+			steps = append(steps, Step[any]{
+				StepId:  fmt.Sprintf("%s_ring_%d", st.StepId, i+1),
+				Status:  Pending,
+				Action:  DestroyAsset,
+				Payload: DestroyAssetPayload{CharacterId: characterId, TemplateId: record.RingTemplateId, Quantity: 1},
+			})
+		}
+	}
+
+	p := NewProcessor(h.l, h.ctx)
+	for i := len(steps) - 1; i >= 0; i-- {
+		if err = p.AddStepAfterCurrent(s.TransactionId, steps[i]); err != nil {
+			h.logActionError(s, st, err, "Unable to add divorce step.")
+			return err
+		}
+	}
+	return nil
+}
+
+// handleDissolveMarriage handles the DissolveMarriage action
+func (h *HandlerImpl) handleDissolveMarriage(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(DissolveMarriagePayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+	if payload.MarriageId == 0 {
+		return fmt.Errorf("%w: marriage must be identified", ErrActionRejected)
+	}
+
+	err := h.marriP.DivorceAndEmit(s.TransactionId, payload.CharacterId, payload.MarriageId, payload.PartnerId)
+
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to dissolve marriage.")
+		return err
+	}
+
+	return nil
+}
+
+// handleSealAsset handles the SealAsset action, reserving the asset for the saga
+func (h *HandlerImpl) handleSealAsset(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(SealAssetPayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+	if payload.TemplateId == 0 || payload.Quantity == 0 {
+		return fmt.Errorf("%w: templateId and quantity are required", ErrActionRejected)
+	}
+
+	err := h.compP.RequestReserveAsset(s.TransactionId, payload.CharacterId, payload.InventoryType, payload.Slot, payload.TemplateId, payload.Quantity)
+
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to seal asset.")
+		return err
+	}
+
+	return nil
+}
+
+// handleUnsealAsset handles the UnsealAsset action, cancelling the saga's reservation of the asset
+func (h *HandlerImpl) handleUnsealAsset(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(UnsealAssetPayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+
+	err := h.compP.RequestCancelReservation(s.TransactionId, payload.CharacterId, payload.InventoryType, payload.Slot)
+
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to unseal asset.")
+		return err
+	}
+
+	return nil
+}
+
+// handleConfiscateAsset handles the ConfiscateAsset action, consuming the asset reserved by the saga
+func (h *HandlerImpl) handleConfiscateAsset(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(ConfiscateAssetPayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+
+	err := h.compP.RequestConsumeAsset(s.TransactionId, payload.CharacterId, payload.InventoryType, payload.Slot)
+
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to confiscate asset.")
+		return err
+	}
+
+	return nil
+}
+
+// handleResolveDispute handles the ResolveDispute action, holding the saga until an operator resolves the dispute
+func (h *HandlerImpl) handleResolveDispute(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(ResolveDisputePayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+	if payload.TicketId == "" {
+		return fmt.Errorf("%w: ticketId is required", ErrActionRejected)
+	}
+
+	s.Hold = PendingResolution
+	s.HoldReason = fmt.Sprintf("dispute of support ticket [%s] awaits resolution", payload.TicketId)
+	GetCache().Put(h.t.Id(), s)
+
+	h.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        st.StepId,
+		"ticket_id":      payload.TicketId,
+		"tenant_id":      h.t.Id().String(),
+	}).Info("Holding saga pending resolution of dispute.")
+	return nil
+}
+
+// handleIssueTransportTicket handles the IssueTransportTicket action
+func (h *HandlerImpl) handleIssueTransportTicket(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(IssueTransportTicketPayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+	if payload.TemplateId == 0 {
+		return fmt.Errorf("%w: ticket templateId is required", ErrActionRejected)
+	}
+
+	err := h.compP.RequestCreateItem(s.TransactionId, payload.CharacterId, payload.TemplateId, 1)
+
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to issue transport ticket.")
+		return err
+	}
+
+	return nil
+}
+
+// handleScheduleWarp handles the ScheduleWarp action. A transport departing in the future is scheduled, and the step is
+// dispatched again once its departure time is reached, when the character is warped. The step completes when the
+// character arrives.
+func (h *HandlerImpl) handleScheduleWarp(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(ScheduleWarpPayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+
+	f, ok := field.FromId(payload.FieldId)
+	if !ok {
+		return fmt.Errorf("%w: invalid field id '%s'", ErrActionRejected, payload.FieldId)
+	}
+
+	if wait := time.Until(payload.DepartsAt); wait > 0 {
+		startDetachedTimer(h.l, h.t, s.TransactionId, st.StepId, wait, departureReached)
+
+		h.l.WithFields(logrus.Fields{
+			"transaction_id": s.TransactionId.String(),
+			"saga_type":      s.SagaType,
+			"step_id":        st.StepId,
+			"character_id":   payload.CharacterId,
+			"tenant_id":      h.t.Id().String(),
+		}).Debugf("Scheduled warp departing at [%s].", payload.DepartsAt.Format(time.RFC3339))
+		return nil
+	}
+
+	err := h.charP.WarpToPortalAndEmit(s.TransactionId, payload.CharacterId, f, model.FixedProvider(payload.PortalId))
+
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to warp to transport destination.")
+		return err
+	}
+
+	return nil
+}
+
+// handleSpawnEscort handles the SpawnEscort action
+func (h *HandlerImpl) handleSpawnEscort(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(SpawnEscortPayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+	f, ok := field.FromId(payload.FieldId)
+	if !ok {
+		return fmt.Errorf("%w: invalid field id '%s'", ErrActionRejected, payload.FieldId)
+	}
+	if payload.NpcId == 0 {
+		return fmt.Errorf("%w: escort must be identified", ErrActionRejected)
+	}
+
+	err := h.npcP.SpawnEscortAndEmit(s.TransactionId, f, payload.NpcId, payload.CharacterId, payload.DestinationMapId)
+
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to spawn escort.")
+		return err
+	}
+
+	return nil
+}
+
+// handleAwaitEscort handles the AwaitEscort action
+func (h *HandlerImpl) handleAwaitEscort(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(AwaitEscortPayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+
+	if payload.Timeout > 0 {
+		startDetachedTimer(h.l, h.t, s.TransactionId, st.StepId, time.Duration(payload.Timeout)*time.Second, escortExpired)
+	}
+
+	h.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        st.StepId,
+		"npc_id":         payload.NpcId,
+		"character_id":   payload.CharacterId,
+		"tenant_id":      h.t.Id().String(),
+	}).Debug("Awaiting escort arrival.")
+	return nil
+}
+
+// handleUpdateCharacterAlignment handles the UpdateCharacterAlignment action
+func (h *HandlerImpl) handleUpdateCharacterAlignment(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(UpdateCharacterAlignmentPayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+	if payload.FactionId == 0 {
+		return fmt.Errorf("%w: faction must be identified", ErrActionRejected)
+	}
+	if payload.Amount == 0 {
+		return fmt.Errorf("%w: faction points amount must not be 0", ErrActionRejected)
+	}
+
+	err := h.factP.ChangePointsAndEmit(s.TransactionId, payload.WorldId, payload.CharacterId, payload.FactionId, payload.Amount)
+
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to update character alignment.")
+		return err
+	}
+
+	return nil
+}
+
+// handleResetInstanceCooldown handles the ResetInstanceCooldown action, recording the cooldown being cleared so
+// compensation is able to re-apply it
+func (h *HandlerImpl) handleResetInstanceCooldown(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(ResetInstanceCooldownPayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+	if payload.InstanceId == 0 {
+		return fmt.Errorf("%w: instance must be identified", ErrActionRejected)
+	}
+
+	cs, err := h.instP.GetCooldowns(payload.CharacterId)
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to retrieve instance cooldowns.")
+		return err
+	}
+	// Expired cooldowns no longer prevent the character entering the instance, so there is nothing to re-apply
+	for _, c := range cs {
+		if c.InstanceId() == payload.InstanceId && c.ExpiresAt().After(time.Now()) {
+			previous := c.ExpiresAt()
+			payload.Previous = &previous
+			h.recordStepPayload(s, st, payload)
+		}
+	}
+
+	err = h.instP.ResetCooldownAndEmit(s.TransactionId, payload.WorldId, payload.CharacterId, payload.InstanceId)
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to reset instance cooldown.")
+		return err
+	}
+
+	return nil
+}
+
+// handleAuditInventory handles the AuditInventory action, reporting whether the net change the completed steps made to
+// the character's inventory materialized. Discrepancies are reported rather than failing the step, as the steps audited
+// have already taken effect.
+func (h *HandlerImpl) handleAuditInventory(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(AuditInventoryPayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+
+	completed := make([]Step[any], 0, len(s.Steps))
+	for _, cs := range s.Steps {
+		if cs.Status == Completed {
+			completed = append(completed, cs)
+		}
+	}
+	deltas := inventoryDeltas(completed, payload.CharacterId)
+	held, err := heldQuantities(h.compP, payload.CharacterId, auditedItems(payload.Baseline, deltas))
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to retrieve compartments to audit.")
+		return err
+	}
+	r := NewReconciliationReport(payload.Baseline, deltas, held)
+	payload.Report = &r
+	h.recordStepPayload(s, st, payload)
+
+	fl := h.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        st.StepId,
+		"character_id":   payload.CharacterId,
+		"tenant_id":      h.t.Id().String(),
+	})
+	if !r.Reconciled {
+		fl.Warnf("Inventory audit found [%d] discrepancies.", len(r.Discrepancies))
+	} else {
+		fl.Debugf("Inventory audit reconciled, [%d] items unverified.", len(r.Unverified))
+	}
+	return nil
+}
+
+// handleApplyTitleBuffOnLogin handles the ApplyTitleBuffOnLogin action. The effect is registered with the session
+// service, which applies it on the character's next login, so the step awaits its application.
+func (h *HandlerImpl) handleApplyTitleBuffOnLogin(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(ApplyTitleBuffOnLoginPayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+	if payload.TitleId == 0 {
+		return fmt.Errorf("%w: title must be identified", ErrActionRejected)
+	}
+	if payload.Duration == 0 {
+		return fmt.Errorf("%w: title buff duration must not be 0", ErrActionRejected)
+	}
+	if payload.ExpiresAt != nil && !payload.ExpiresAt.After(time.Now()) {
+		return fmt.Errorf("%w: title buff expired at [%s]", ErrActionRejected, payload.ExpiresAt.Format(time.RFC3339))
+	}
+
+	// The effect is identified before it is registered, so a redelivered registration is not applied twice, and
+	// compensation can cancel it
+	if payload.EffectId == nil {
+		effectId := uuid.New()
+		payload.EffectId = &effectId
+		h.recordStepPayload(s, st, payload)
+	}
+
+	err := h.sessP.RegisterTitleBuffAndEmit(s.TransactionId, payload.WorldId, payload.CharacterId, *payload.EffectId, payload.TitleId, payload.Duration, payload.ExpiresAt)
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to register title buff for login.")
+		return err
+	}
+
+	return nil
+}
+
+// handleGrantMount handles the GrantMount action
+func (h *HandlerImpl) handleGrantMount(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(GrantMountPayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+	if payload.SkillId == 0 || payload.ItemId == 0 {
+		return fmt.Errorf("%w: a mount requires both a skill and an item", ErrActionRejected)
+	}
+	level := payload.SkillLevel
+	if level == 0 {
+		level = 1
+	}
+
+	// Grant the skill, then the item, through dynamically added steps which run immediately after this one. Steps are
+	// inserted directly after the current step, so they are added in reverse order.
+	steps := []Step[any]{
+		{
+			StepId: fmt.Sprintf("%s_skill", st.StepId),
+			Status: Pending,
+			Action: CreateSkill,
+			Payload: CreateSkillPayload{
+				CharacterId: payload.CharacterId,
+				SkillId:     payload.SkillId,
+				Level:       level,
+				MasterLevel: level,
+				Expiration:  payload.Expiration,
+			},
+		},
+		{
+			StepId:  fmt.Sprintf("%s_item", st.StepId),
+			Status:  Pending,
+			Action:  AwardAsset,
+			Payload: AwardItemActionPayload{CharacterId: payload.CharacterId, Item: ItemPayload{TemplateId: payload.ItemId, Quantity: 1}},
+		},
+	}
+
+	p := NewProcessor(h.l, h.ctx)
+	for i := len(steps) - 1; i >= 0; i-- {
+		if err := p.AddStepAfterCurrent(s.TransactionId, steps[i]); err != nil {
+			h.logActionError(s, st, err, "Unable to add mount step.")
+			return err
+		}
+	}
+	return nil
+}
+
+// handleApplyWorldEventBuff handles the ApplyWorldEventBuff action. The buff's removal is scheduled by a linked
+// remove_world_event_buff step added after this one, which is dispatched once the buff's duration has elapsed.
+func (h *HandlerImpl) handleApplyWorldEventBuff(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(ApplyWorldEventBuffPayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+	if payload.BuffId == "" || payload.Type == "" {
+		return fmt.Errorf("%w: event buff must be identified", ErrActionRejected)
+	}
+	if payload.Rate <= 0 {
+		return fmt.Errorf("%w: event buff rate must be positive", ErrActionRejected)
+	}
+	if payload.Duration == 0 {
+		return fmt.Errorf("%w: event buff duration must be positive", ErrActionRejected)
+	}
+
+	// The removal is linked before the buff is applied, so an applied buff is never left without one. A redelivered
+	// step keeps the removal linked by its first dispatch.
+	removeStepId := fmt.Sprintf("%s_remove", st.StepId)
+	if s.FindStepIndex(removeStepId) == -1 {
+		err := NewProcessor(h.l, h.ctx).AddStepAfterCurrent(s.TransactionId, Step[any]{
+			StepId: removeStepId,
+			Status: Pending,
+			Action: RemoveWorldEventBuff,
+			Payload: RemoveWorldEventBuffPayload{
+				WorldId:  payload.WorldId,
+				BuffId:   payload.BuffId,
+				RemoveAt: time.Now().Add(time.Duration(payload.Duration) * time.Second),
+			},
+		})
+		if err != nil {
+			h.logActionError(s, st, err, "Unable to link world event buff removal.")
+			return err
+		}
+	}
+
+	err := h.worldP.ApplyEventBuffAndEmit(s.TransactionId, payload.WorldId, payload.BuffId, payload.Type, payload.Rate)
+
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to apply world event buff.")
+		return err
+	}
+
+	return nil
+}
+
+// handleRemoveWorldEventBuff handles the RemoveWorldEventBuff action. A removal due in the future is scheduled, and the
+// step is dispatched again once its removal time is reached, when the buff is removed.
+func (h *HandlerImpl) handleRemoveWorldEventBuff(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(RemoveWorldEventBuffPayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+	if payload.BuffId == "" {
+		return fmt.Errorf("%w: event buff must be identified", ErrActionRejected)
+	}
+
+	if wait := time.Until(payload.RemoveAt); wait > 0 {
+		startDetachedTimer(h.l, h.t, s.TransactionId, st.StepId, wait, eventBuffExpired)
+
+		h.l.WithFields(logrus.Fields{
+			"transaction_id": s.TransactionId.String(),
+			"saga_type":      s.SagaType,
+			"step_id":        st.StepId,
+			"buff_id":        payload.BuffId,
+			"tenant_id":      h.t.Id().String(),
+		}).Debugf("Scheduled world event buff removal at [%s].", payload.RemoveAt.Format(time.RFC3339))
+		return nil
+	}
+
+	err := h.worldP.RemoveEventBuffAndEmit(s.TransactionId, payload.WorldId, payload.BuffId)
+
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to remove world event buff.")
 		return err
 	}
 
 	return nil
 }
 
-// handleRequestGuildName handles the RequestGuildName action
-func (h *HandlerImpl) handleRequestGuildName(s Saga, st Step[any]) error {
-	// Extract the payload
-	payload, ok := st.Payload.(RequestGuildNamePayload)
+// handleSettleEventCurrency handles the SettleEventCurrency action. The page's holders are listed once, and recorded
+// on the step, so a step dispatched again settles the same holders. Settling each holder, and the next page, are
+// dynamically added steps which run immediately after this one.
+func (h *HandlerImpl) handleSettleEventCurrency(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(SettleEventCurrencyPayload)
 	if !ok {
 		return errors.New("invalid payload")
 	}
+	if payload.CurrencyId == 0 || payload.RewardId == 0 {
+		return fmt.Errorf("%w: settlement requires both a currency and a reward", ErrActionRejected)
+	}
+	if payload.PageSize > MaxSettlementPageSize {
+		return fmt.Errorf("%w: page size %d exceeds the limit of %d", ErrActionRejected, payload.PageSize, MaxSettlementPageSize)
+	}
 
-	// Call the guild processor
-	err := h.guildP.RequestName(s.TransactionId, payload.WorldId, payload.ChannelId, payload.CharacterId)
+	if payload.Holders == nil {
+		holders, err := h.compP.GetHolders(payload.CurrencyId, payload.After, settlementPageSize(payload))
+		if err != nil {
+			h.logActionError(s, st, err, "Unable to list holders of event currency.")
+			return err
+		}
+		payload.Holders = make([]SettledHolder, 0, len(holders))
+		for _, holder := range holders {
+			payload.Holders = append(payload.Holders, SettledHolder{CharacterId: holder.CharacterId(), Quantity: holder.Quantity()})
+		}
+		h.recordStepPayload(s, st, payload)
+	}
+
+	// Steps are inserted directly after the current step, so they are added in reverse order. Those added before the
+	// step was interrupted are not added again.
+	steps := expandSettlement(st.StepId, payload)
+	p := NewProcessor(h.l, h.ctx)
+	for i := len(steps) - 1; i >= 0; i-- {
+		if s.FindStepIndex(steps[i].StepId) != -1 {
+			continue
+		}
+		if err := p.AddStepAfterCurrent(s.TransactionId, steps[i]); err != nil {
+			h.logActionError(s, st, err, "Unable to add settlement step.")
+			return err
+		}
+	}
+
+	h.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        st.StepId,
+		"currency_id":    payload.CurrencyId,
+		"tenant_id":      h.t.Id().String(),
+	}).Debugf("Settling page [%d] of [%d] event currency holders.", settlementPage(payload), len(payload.Holders))
+	return nil
+}
+
+// handleCharacterExperienceLock handles the CharacterExperienceLock action
+func (h *HandlerImpl) handleCharacterExperienceLock(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(CharacterExperienceLockPayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+
+	err := h.charP.LockExperienceAndEmit(s.TransactionId, payload.WorldId, payload.CharacterId, payload.ChannelId, payload.Reason)
 	if err != nil {
-		h.logActionError(s, st, err, "Unable to request guild name.")
+		h.logActionError(s, st, err, "Unable to lock character experience.")
 		return err
 	}
 
 	return nil
 }
 
-// handleRequestGuildEmblem handles the RequestGuildEmblem action
-func (h *HandlerImpl) handleRequestGuildEmblem(s Saga, st Step[any]) error {
-	// Extract the payload
-	payload, ok := st.Payload.(RequestGuildEmblemPayload)
+// handleCharacterExperienceUnlock handles the CharacterExperienceUnlock action
+func (h *HandlerImpl) handleCharacterExperienceUnlock(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(CharacterExperienceUnlockPayload)
 	if !ok {
 		return errors.New("invalid payload")
 	}
 
-	// Call the guild processor
-	err := h.guildP.RequestEmblem(s.TransactionId, payload.WorldId, payload.ChannelId, payload.CharacterId)
+	err := h.charP.UnlockExperienceAndEmit(s.TransactionId, payload.WorldId, payload.CharacterId, payload.ChannelId)
 	if err != nil {
-		h.logActionError(s, st, err, "Unable to request guild emblem.")
+		h.logActionError(s, st, err, "Unable to unlock character experience.")
 		return err
 	}
 
 	return nil
 }
 
-// handleRequestGuildDisband handles the RequestGuildDisband action
-func (h *HandlerImpl) handleRequestGuildDisband(s Saga, st Step[any]) error {
-	// Extract the payload
-	payload, ok := st.Payload.(RequestGuildDisbandPayload)
+// handleNpcConversationState handles the NpcConversationState action, signalling the NPC conversation service to resume
+// the conversation with the saga's variables, and the step's outcome data, as the outcome of the saga
+func (h *HandlerImpl) handleNpcConversationState(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(NpcConversationStatePayload)
 	if !ok {
 		return errors.New("invalid payload")
 	}
+	if payload.NpcId == 0 {
+		return fmt.Errorf("%w: npcId is required", ErrActionRejected)
+	}
+	if payload.State == "" {
+		return fmt.Errorf("%w: state is required", ErrActionRejected)
+	}
 
-	// Call the guild processor
-	err := h.guildP.RequestDisband(s.TransactionId, payload.WorldId, payload.ChannelId, payload.CharacterId)
+	outcome := make(map[string]any, len(s.Variables)+len(payload.Outcome))
+	for k, v := range s.Variables {
+		outcome[k] = v
+	}
+	for k, v := range payload.Outcome {
+		outcome[k] = v
+	}
+
+	err := h.npcP.ResumeConversationAndEmit(s.TransactionId, payload.WorldId, payload.ChannelId, payload.CharacterId, payload.NpcId, payload.State, outcome)
 	if err != nil {
-		h.logActionError(s, st, err, "Unable to request guild disband.")
+		h.logActionError(s, st, err, "Unable to resume NPC conversation.")
 		return err
 	}
 
 	return nil
 }
 
-// handleRequestGuildCapacityIncrease handles the RequestGuildCapacityIncrease action
-func (h *HandlerImpl) handleRequestGuildCapacityIncrease(s Saga, st Step[any]) error {
-	// Extract the payload
-	payload, ok := st.Payload.(RequestGuildCapacityIncreasePayload)
+// handleEquipAssetByTemplate handles the EquipAssetByTemplate action, looking up the inventory slot holding an asset of
+// the template, rather than requiring the caller to know it, and equipping it from there
+func (h *HandlerImpl) handleEquipAssetByTemplate(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(EquipAssetByTemplatePayload)
 	if !ok {
 		return errors.New("invalid payload")
 	}
+	if payload.Destination >= 0 {
+		return fmt.Errorf("%w: slot [%d] is not an equipped slot", ErrActionRejected, payload.Destination)
+	}
+	it, ok := inventory.TypeFromItemId(item.Id(payload.TemplateId))
+	if !ok || it != inventory.TypeValueEquip {
+		return fmt.Errorf("%w: item [%d] is not equipment", ErrActionRejected, payload.TemplateId)
+	}
 
-	// Call the guild processor
-	err := h.guildP.RequestCapacityIncrease(s.TransactionId, payload.WorldId, payload.ChannelId, payload.CharacterId)
+	c, err := h.compP.GetByType(payload.CharacterId, it)
 	if err != nil {
-		h.logActionError(s, st, err, "Unable to request guild capacity increase.")
+		h.logActionError(s, st, err, "Unable to retrieve equipment compartment.")
+		return err
+	}
+	source, ok := findUnequippedSlot(c, payload.TemplateId)
+	if !ok {
+		return fmt.Errorf("%w: item [%d] is not held", ErrActionRejected, payload.TemplateId)
+	}
+
+	// Record the slot the asset was equipped from, so compensation is able to return it there
+	payload.Source = source
+	h.recordStepPayload(s, st, payload)
+
+	err = h.compP.RequestEquipAsset(s.TransactionId, payload.CharacterId, byte(it), source, payload.Destination)
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to equip asset.")
 		return err
 	}
+	return nil
+}
+
+// findUnequippedSlot returns the lowest inventory slot holding an unequipped asset of the template
+func findUnequippedSlot(c compartment.Model, templateId uint32) (int16, bool) {
+	slot := int16(0)
+	for _, a := range c.Assets() {
+		if a.TemplateId() != templateId || a.Slot() <= 0 {
+			continue
+		}
+		if slot == 0 || a.Slot() < slot {
+			slot = a.Slot()
+		}
+	}
+	return slot, slot != 0
+}
+
+// handleAwardPartyExperience handles the AwardPartyExperience action, splitting the pool across the members in
+// proportion to their levels, and awarding each share through a dynamically added award_experience step
+func (h *HandlerImpl) handleAwardPartyExperience(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(AwardPartyExperiencePayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+	if payload.Amount == 0 {
+		return fmt.Errorf("%w: experience pool must be positive", ErrActionRejected)
+	}
+	if len(payload.Members) == 0 {
+		return fmt.Errorf("%w: experience pool must be shared by at least one member", ErrActionRejected)
+	}
+	seen := make(map[uint32]bool)
+	for _, m := range payload.Members {
+		if m.CharacterId == 0 || m.Level == 0 {
+			return fmt.Errorf("%w: member [%d] must be identified and have a level", ErrActionRejected, m.CharacterId)
+		}
+		if seen[m.CharacterId] {
+			return fmt.Errorf("%w: member [%d] is listed more than once", ErrActionRejected, m.CharacterId)
+		}
+		seen[m.CharacterId] = true
+	}
 
+	// Record the shares on the step, so the split is visible and compensation is able to deduct them
+	payload.Shares = splitPartyExperience(payload.Amount, payload.Members)
+	h.recordStepPayload(s, st, payload)
+
+	// Steps are inserted directly after the current step, so they are added in reverse order. Members whose share rounds
+	// down to nothing are awarded nothing.
+	p := NewProcessor(h.l, h.ctx)
+	for i := len(payload.Shares) - 1; i >= 0; i-- {
+		share := payload.Shares[i]
+		if share.Amount == 0 {
+			continue
+		}
+		member := Step[any]{
+			StepId: fmt.Sprintf("%s_member_%d", st.StepId, i+1),
+			Status: Pending,
+			Action: AwardExperience,
+			Payload: AwardExperiencePayload{
+				CharacterId:   share.CharacterId,
+				WorldId:       payload.WorldId,
+				ChannelId:     payload.ChannelId,
+				Distributions: []ExperienceDistributions{{ExperienceType: character2.ExperienceDistributionTypeParty, Amount: share.Amount}},
+			},
+		}
+		if err := p.AddStepAfterCurrent(s.TransactionId, member); err != nil {
+			h.logActionError(s, st, err, "Unable to add party member experience step.")
+			return err
+		}
+	}
 	return nil
 }
 
-// handleCreateInvite handles the CreateInvite action
-func (h *HandlerImpl) handleCreateInvite(s Saga, st Step[any]) error {
-	// Extract the payload
-	payload, ok := st.Payload.(CreateInvitePayload)
+// splitPartyExperience splits the pool across the members in proportion to their levels. Shares are rounded down, and
+// the experience lost to rounding is awarded a point at a time to the members with the largest remainders, so the shares
+// always sum to the pool.
+func splitPartyExperience(amount uint32, members []PartyMember) []PartyExperienceShare {
+	total := uint64(0)
+	for _, m := range members {
+		total += uint64(m.Level)
+	}
+
+	shares := make([]PartyExperienceShare, len(members))
+	remainders := make([]uint64, len(members))
+	awarded := uint64(0)
+	for i, m := range members {
+		weighted := uint64(amount) * uint64(m.Level)
+		shares[i] = PartyExperienceShare{CharacterId: m.CharacterId, Amount: uint32(weighted / total)}
+		remainders[i] = weighted % total
+		awarded += weighted / total
+	}
+
+	order := make([]int, len(members))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return remainders[order[i]] > remainders[order[j]]
+	})
+	for _, i := range order[:uint64(amount)-awarded] {
+		shares[i].Amount++
+	}
+	return shares
+}
+
+// handleToggleCharacterAbility handles the ToggleCharacterAbility action, enabling the ability for each character by
+// creating its skill, and disabling it by reducing the skill to level 0, through dynamically added steps
+func (h *HandlerImpl) handleToggleCharacterAbility(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(ToggleCharacterAbilityPayload)
 	if !ok {
 		return errors.New("invalid payload")
 	}
+	if payload.AccountId == 0 {
+		return fmt.Errorf("%w: ability must be toggled for characters of an account", ErrActionRejected)
+	}
+	if payload.SkillId == 0 || payload.Level == 0 {
+		return fmt.Errorf("%w: ability must be identified and have a level", ErrActionRejected)
+	}
+	if len(payload.Toggles) == 0 {
+		return fmt.Errorf("%w: ability must be toggled for at least one character", ErrActionRejected)
+	}
+	seen := make(map[uint32]bool)
+	for _, t := range payload.Toggles {
+		if t.CharacterId == 0 {
+			return fmt.Errorf("%w: character must be identified", ErrActionRejected)
+		}
+		if seen[t.CharacterId] {
+			return fmt.Errorf("%w: character [%d] is toggled more than once", ErrActionRejected, t.CharacterId)
+		}
+		seen[t.CharacterId] = true
+	}
+
+	// Steps are inserted directly after the current step, so they are added in reverse order
+	p := NewProcessor(h.l, h.ctx)
+	for i := len(payload.Toggles) - 1; i >= 0; i-- {
+		t := payload.Toggles[i]
+		toggle := Step[any]{
+			StepId:  fmt.Sprintf("%s_toggle_%d", st.StepId, i+1),
+			Status:  Pending,
+			Action:  UpdateSkill,
+			Payload: UpdateSkillPayload{CharacterId: t.CharacterId, SkillId: payload.SkillId},
+		}
+		if t.Enabled {
+			toggle.Action = CreateSkill
+			toggle.Payload = CreateSkillPayload{CharacterId: t.CharacterId, SkillId: payload.SkillId, Level: payload.Level, MasterLevel: payload.Level}
+		}
+		if err := p.AddStepAfterCurrent(s.TransactionId, toggle); err != nil {
+			h.logActionError(s, st, err, "Unable to add ability toggle step.")
+			return err
+		}
+	}
+	return nil
+}
+
+// handleGrantStorageCapacity handles the GrantStorageCapacity action
+func (h *HandlerImpl) handleGrantStorageCapacity(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(GrantStorageCapacityPayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+	if payload.Amount == 0 {
+		return fmt.Errorf("%w: storage capacity amount must not be 0", ErrActionRejected)
+	}
+
+	err := h.storP.ChangeCapacityAndEmit(s.TransactionId, payload.WorldId, payload.AccountId, int16(payload.Amount))
 
-	// Call the invite processor
-	err := h.inviteP.Create(s.TransactionId, payload.InviteType, payload.OriginatorId, payload.WorldId, payload.ReferenceId, payload.TargetId)
 	if err != nil {
-		h.logActionError(s, st, err, "Unable to create invitation.")
+		h.logActionError(s, st, err, "Unable to increase storage capacity.")
 		return err
 	}
 
 	return nil
 }
 
-// handleCreateCharacter handles the CreateCharacter action
-func (h *HandlerImpl) handleCreateCharacter(s Saga, st Step[any]) error {
-	// Extract the payload
-	payload, ok := st.Payload.(CharacterCreatePayload)
+// handleStripEquipment handles the StripEquipment action
+func (h *HandlerImpl) handleStripEquipment(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(StripEquipmentPayload)
 	if !ok {
 		return errors.New("invalid payload")
 	}
 
-	// Call the character processor
-	err := h.charP.RequestCreateCharacter(s.TransactionId, payload.AccountId, payload.WorldId, payload.Name, payload.Level, payload.Strength, payload.Dexterity, payload.Intelligence, payload.Luck, payload.Hp, payload.Mp, payload.JobId, payload.Gender, payload.Face, payload.Hair, payload.Skin, payload.MapId)
+	c, err := h.compP.GetByType(payload.CharacterId, inventory.TypeValueEquip)
 	if err != nil {
-		h.logActionError(s, st, err, "Unable to create character.")
+		h.logActionError(s, st, err, "Unable to retrieve equipment compartment.")
+		return err
+	}
+
+	stripped, unequips, err := planStripEquipment(c, payload.CharacterId, payload.JobId, h.equipP)
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to plan equipment strip.")
 		return err
 	}
 
+	// Record the items stripped on the step, so a failure part way through is able to restore them
+	payload.Stripped = stripped
+	h.recordStepPayload(s, st, payload)
+
+	// Steps are inserted directly after the current step, so they are added in reverse order.
+	p := NewProcessor(h.l, h.ctx)
+	for i := len(unequips) - 1; i >= 0; i-- {
+		err = p.AddStepAfterCurrent(s.TransactionId, Step[any]{
+			StepId:  fmt.Sprintf("%s_unequip_%d", st.StepId, i+1),
+			Status:  Pending,
+			Action:  UnequipAsset,
+			Payload: unequips[i],
+		})
+		if err != nil {
+			h.logActionError(s, st, err, "Unable to add equipment strip step.")
+			return err
+		}
+	}
 	return nil
 }
 
-// handleCreateAndEquipAsset handles the CreateAndEquipAsset action
-// This is a compound action that first creates an asset (internally using award_asset semantics)
-// and then dynamically creates an equip_asset step when the creation succeeds
-func (h *HandlerImpl) handleCreateAndEquipAsset(s Saga, st Step[any]) error {
-	// Extract the payload
-	payload, ok := st.Payload.(CreateAndEquipAssetPayload)
+// planStripEquipment determines the equipped items whose job requirement the job does not meet, along with the ordered
+// operations which unequip them into free inventory slots.
+func planStripEquipment(c compartment.Model, characterId uint32, jobId job.Id, equipP equipment.Processor) ([]EquippedItem, []UnequipAssetPayload, error) {
+	assets := append([]asset.Model[any]{}, c.Assets()...)
+	sort.Slice(assets, func(i, j int) bool {
+		return assets[i].Slot() < assets[j].Slot()
+	})
+
+	occupied := make(map[int16]bool)
+	for _, a := range assets {
+		occupied[a.Slot()] = true
+	}
+
+	wearable := make(map[uint32]bool)
+	stripped := make([]EquippedItem, 0)
+	unequips := make([]UnequipAssetPayload, 0)
+	free := int16(1)
+	for _, a := range assets {
+		if a.Slot() >= 0 {
+			continue
+		}
+		ok, checked := wearable[a.TemplateId()]
+		if !checked {
+			e, err := equipP.GetById(a.TemplateId())
+			if err != nil {
+				return nil, nil, err
+			}
+			ok = e.WearableBy(jobId)
+			wearable[a.TemplateId()] = ok
+		}
+		if ok {
+			continue
+		}
+
+		for free <= int16(c.Capacity()) && occupied[free] {
+			free++
+		}
+		if free > int16(c.Capacity()) {
+			return nil, nil, fmt.Errorf("%w: insufficient inventory space to unequip the items incompatible with job [%d]", ErrActionRejected, jobId)
+		}
+		occupied[free] = true
+		stripped = append(stripped, EquippedItem{TemplateId: a.TemplateId(), Slot: a.Slot()})
+		unequips = append(unequips, UnequipAssetPayload{
+			CharacterId:   characterId,
+			InventoryType: uint32(inventory.TypeValueEquip),
+			Source:        a.Slot(),
+			Destination:   free,
+		})
+	}
+	return stripped, unequips, nil
+}
+
+// handleSnapshotCharacter handles the SnapshotCharacter action
+func (h *HandlerImpl) handleSnapshotCharacter(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(SnapshotCharacterPayload)
 	if !ok {
 		return errors.New("invalid payload")
 	}
 
-	// Step 1: Internal award_asset - Create the item using the same logic as handleAwardAsset
-	// Convert saga payload to compartment payload to avoid import cycle
-	compartmentPayload := compartment.CreateAndEquipAssetPayload{
-		CharacterId: payload.CharacterId,
-		Item: compartment.ItemPayload{
-			TemplateId: payload.Item.TemplateId,
-			Quantity:   payload.Item.Quantity,
-		},
+	c, err := h.charP.GetById(payload.CharacterId)
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to retrieve character.")
+		return err
 	}
 
-	err := h.compP.RequestCreateAndEquipAsset(s.TransactionId, compartmentPayload)
+	// Record the snapshot on the step, so a restore_character step or compensation is able to restore it
+	snapshot := NewCharacterSnapshot(c)
+	payload.Snapshot = &snapshot
+	h.recordStepPayload(s, st, payload)
+	return nil
+}
+
+// handleRestoreCharacter handles the RestoreCharacter action
+func (h *HandlerImpl) handleRestoreCharacter(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(RestoreCharacterPayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+
+	snapshot, ok := findCharacterSnapshot(s, payload.CharacterId, payload.SnapshotStepId)
+	if !ok {
+		return fmt.Errorf("%w: no snapshot of character [%d] has been taken", ErrActionRejected, payload.CharacterId)
+	}
+
+	err := h.charP.RestoreStatsAndEmit(s.TransactionId, payload.WorldId, payload.CharacterId, payload.ChannelId, snapshot.Snapshot.Model(payload.CharacterId))
 	if err != nil {
-		h.logActionError(s, st, err, "Unable to create asset for create_and_equip_asset.")
+		h.logActionError(s, st, err, "Unable to restore character snapshot.")
 		return err
 	}
 
-	// Note: Step 2 (dynamic equip_asset step creation) will be handled by the compartment consumer
-	// when it receives the StatusEventTypeCreated event from the compartment service.
-	// The consumer will detect this is a CreateAndEquipAsset step and add the equip_asset step.
+	return nil
+}
+
+// handleAwaitEvent handles the AwaitEvent action. The awaited event is matched as character status events arrive, so the
+// step completes once the character enters the map, or fails when the step's timeout elapses first.
+func (h *HandlerImpl) handleAwaitEvent(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(AwaitEventPayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+	if payload.Event != AwaitedEventMapEntry {
+		return fmt.Errorf("%w: event '%s' cannot be awaited", ErrActionRejected, payload.Event)
+	}
+	if payload.MapId == 0 {
+		return fmt.Errorf("%w: mapId is required to await a map entry", ErrActionRejected)
+	}
+
+	h.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        st.StepId,
+		"character_id":   payload.CharacterId,
+		"map_id":         payload.MapId,
+		"tenant_id":      h.t.Id().String(),
+	}).Debug("Awaiting character map entry.")
+	return nil
+}
+
+// handleNotify handles the Notify action, showing the message to the character. Messages are not acknowledged, so the
+// step completes once the message is sent.
+func (h *HandlerImpl) handleNotify(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(NotifyPayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+	if payload.Message == "" {
+		return fmt.Errorf("%w: message is required", ErrActionRejected)
+	}
+	messageType := payload.MessageType
+	if messageType == "" {
+		messageType = systemmessage2.MessageTypeNotice
+	}
+
+	err := h.msgP.SendMessageAndEmit(s.TransactionId, payload.WorldId, payload.ChannelId, payload.CharacterId, messageType, payload.Message)
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to notify character.")
+		return err
+	}
+
+	return nil
+}
+
+// handlePetEquip handles the PetEquip action, validating the pet is the character's and summoned, then equipping the
+// pet equipment from whichever inventory slot holds it, as EquipAssetByTemplate would
+func (h *HandlerImpl) handlePetEquip(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(PetEquipPayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+	if payload.Destination >= 0 {
+		return fmt.Errorf("%w: slot [%d] is not an equipped slot", ErrActionRejected, payload.Destination)
+	}
+	if !isPetEquipment(payload.TemplateId) {
+		return fmt.Errorf("%w: item [%d] is not pet equipment", ErrActionRejected, payload.TemplateId)
+	}
+	if err := h.validateSummonedPet(s, st, payload.CharacterId, payload.PetId); err != nil {
+		return err
+	}
+
+	c, err := h.compP.GetByType(payload.CharacterId, inventory.TypeValueEquip)
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to retrieve equipment compartment.")
+		return err
+	}
+	source, ok := findUnequippedSlot(c, payload.TemplateId)
+	if !ok {
+		return fmt.Errorf("%w: item [%d] is not held", ErrActionRejected, payload.TemplateId)
+	}
+
+	// Record the slot the equipment was equipped from, so compensation is able to return it there
+	payload.Source = source
+	h.recordStepPayload(s, st, payload)
+
+	err = h.compP.RequestEquipAsset(s.TransactionId, payload.CharacterId, byte(inventory.TypeValueEquip), source, payload.Destination)
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to equip pet equipment.")
+		return err
+	}
+	return nil
+}
+
+// isPetEquipment returns whether the template is pet equipment, including pet accessories such as an item pouch
+func isPetEquipment(templateId uint32) bool {
+	return templateId/100000 == 18
+}
+
+// validateSummonedPet validates the pet is held in the character's cash compartment and summoned, as pet equipment is
+// only worn by a summoned pet
+func (h *HandlerImpl) validateSummonedPet(s Saga, st Step[any], characterId uint32, petId uint32) error {
+	c, err := h.compP.GetByType(characterId, inventory.TypeValueCash)
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to retrieve cash compartment.")
+		return err
+	}
+	a, ok := c.FindByReferenceId(petId)
+	if !ok || !a.IsPet() {
+		return fmt.Errorf("%w: pet [%d] is not held by character [%d]", ErrActionRejected, petId, characterId)
+	}
+	if rd, ok := a.ReferenceData().(asset.PetReferenceData); !ok || rd.Slot() < 0 {
+		return fmt.Errorf("%w: pet [%d] is not summoned", ErrActionRejected, petId)
+	}
+	return nil
+}
+
+// handlePetUnequip handles the PetUnequip action, validating the pet is the character's and the slot holds pet
+// equipment, then unequipping it to the inventory slot
+func (h *HandlerImpl) handlePetUnequip(s Saga, st Step[any]) error {
+	payload, ok := st.Payload.(PetUnequipPayload)
+	if !ok {
+		return errors.New("invalid payload")
+	}
+	if payload.Source >= 0 {
+		return fmt.Errorf("%w: slot [%d] is not an equipped slot", ErrActionRejected, payload.Source)
+	}
+	if payload.Destination <= 0 {
+		return fmt.Errorf("%w: slot [%d] is not an inventory slot", ErrActionRejected, payload.Destination)
+	}
+	if err := h.validateSummonedPet(s, st, payload.CharacterId, payload.PetId); err != nil {
+		return err
+	}
+
+	c, err := h.compP.GetByType(payload.CharacterId, inventory.TypeValueEquip)
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to retrieve equipment compartment.")
+		return err
+	}
+	a, ok := c.FindBySlot(payload.Source)
+	if !ok || !isPetEquipment(a.TemplateId()) {
+		return fmt.Errorf("%w: slot [%d] does not hold pet equipment", ErrActionRejected, payload.Source)
+	}
 
+	// Record the equipment unequipped, so it is retained with the saga for audit
+	payload.TemplateId = a.TemplateId()
+	h.recordStepPayload(s, st, payload)
+
+	err = h.compP.RequestUnequipAsset(s.TransactionId, payload.CharacterId, byte(inventory.TypeValueEquip), payload.Source, payload.Destination)
+	if err != nil {
+		h.logActionError(s, st, err, "Unable to unequip pet equipment.")
+		return err
+	}
 	return nil
 }
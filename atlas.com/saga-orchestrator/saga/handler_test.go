@@ -1,15 +1,39 @@
 package saga
 
 import (
+	mock8 "atlas-saga-orchestrator/account/mock"
+	mock9 "atlas-saga-orchestrator/analytics/mock"
+	"atlas-saga-orchestrator/asset"
+	"atlas-saga-orchestrator/buff"
+	mock4 "atlas-saga-orchestrator/buff/mock"
+	"atlas-saga-orchestrator/character"
 	"atlas-saga-orchestrator/character/mock"
 	"atlas-saga-orchestrator/compartment"
 	mock2 "atlas-saga-orchestrator/compartment/mock"
+	"atlas-saga-orchestrator/coupon"
+	mock7 "atlas-saga-orchestrator/coupon/mock"
+	"atlas-saga-orchestrator/data/equipment"
+	mock17 "atlas-saga-orchestrator/data/equipment/mock"
+	mock13 "atlas-saga-orchestrator/faction/mock"
+	instance2 "atlas-saga-orchestrator/instance"
+	mock14 "atlas-saga-orchestrator/instance/mock"
+	analytics2 "atlas-saga-orchestrator/kafka/message/analytics"
 	character2 "atlas-saga-orchestrator/kafka/message/character"
+	"atlas-saga-orchestrator/marriage"
+	mock11 "atlas-saga-orchestrator/marriage/mock"
+	mock12 "atlas-saga-orchestrator/npc/mock"
+	mock10 "atlas-saga-orchestrator/reactor/mock"
+	mock15 "atlas-saga-orchestrator/session/mock"
+	mock5 "atlas-saga-orchestrator/skill/mock"
+	mock16 "atlas-saga-orchestrator/storage/mock"
+	mock18 "atlas-saga-orchestrator/systemmessage/mock"
 	"atlas-saga-orchestrator/validation"
 	mock3 "atlas-saga-orchestrator/validation/mock"
+	mock6 "atlas-saga-orchestrator/worldstate/mock"
 	"errors"
 	"github.com/Chronicle20/atlas-constants/channel"
 	"github.com/Chronicle20/atlas-constants/field"
+	"github.com/Chronicle20/atlas-constants/inventory"
 	"github.com/Chronicle20/atlas-constants/job"
 	_map "github.com/Chronicle20/atlas-constants/map"
 	"github.com/Chronicle20/atlas-constants/world"
@@ -18,6 +42,8 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math"
 	"testing"
 	"time"
 )
@@ -1166,6 +1192,89 @@ func TestHandleEquipAsset(t *testing.T) {
 	}
 }
 
+// TestHandleModifyInventoryItemPosition tests the handleModifyInventoryItemPosition function
+func TestHandleModifyInventoryItemPosition(t *testing.T) {
+	tests := []struct {
+		name          string
+		payload       ModifyInventoryItemPositionPayload
+		mockError     error
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name: "Success case",
+			payload: ModifyInventoryItemPositionPayload{
+				CharacterId:   12345,
+				InventoryType: 2,
+				Source:        3,
+				Destination:   1,
+			},
+			mockError:   nil,
+			expectError: false,
+		},
+		{
+			name: "Error case",
+			payload: ModifyInventoryItemPositionPayload{
+				CharacterId:   12345,
+				InventoryType: 2,
+				Source:        3,
+				Destination:   1,
+			},
+			mockError:     errors.New("compartment service error"),
+			expectError:   true,
+			errorContains: "compartment service error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			compP := &mock2.ProcessorMock{
+				RequestMoveAssetFunc: func(transactionId uuid.UUID, characterId uint32, inventoryType byte, source int16, destination int16) error {
+					assert.Equal(t, byte(tt.payload.InventoryType), inventoryType)
+					assert.Equal(t, tt.payload.Source, source)
+					assert.Equal(t, tt.payload.Destination, destination)
+					return tt.mockError
+				},
+			}
+
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			_, ctx := setupContext()
+
+			saga := Saga{
+				TransactionId: uuid.New(),
+				SagaType:      InventoryTransaction,
+				InitiatedBy:   "test",
+				Steps:         []Step[any]{},
+			}
+
+			step := Step[any]{
+				StepId:    "test-step",
+				Status:    Pending,
+				Action:    ModifyInventoryItemPosition,
+				Payload:   tt.payload,
+				CreatedAt: time.Now(),
+				UpdatedAt: time.Now(),
+			}
+
+			// Execute
+			err := NewHandler(logger, ctx).WithCompartmentProcessor(compP).handleModifyInventoryItemPosition(saga, step)
+
+			// Verify
+			if tt.expectError {
+				assert.Error(t, err)
+				if tt.errorContains != "" {
+					assert.Contains(t, err.Error(), tt.errorContains)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
 // TestHandleUnequipAsset tests the handleUnequipAsset function
 func TestHandleUnequipAsset(t *testing.T) {
 	tests := []struct {
@@ -1470,3 +1579,3350 @@ func TestHandleCreateAndEquipAsset_PayloadConversion(t *testing.T) {
 	assert.Equal(t, sagaPayload.Item.TemplateId, capturedPayload.Item.TemplateId)
 	assert.Equal(t, sagaPayload.Item.Quantity, capturedPayload.Item.Quantity)
 }
+
+// TestHandleAdjustPopularity tests the handleAdjustPopularity function
+func TestHandleAdjustPopularity(t *testing.T) {
+	cooldownActive := func() validation.ValidationResult {
+		result := validation.NewValidationResult(12345)
+		result.AddConditionResult(validation.ConditionResult{
+			Passed:      false,
+			Description: "Fame already given today",
+			Type:        validation.DailyFameCondition,
+			Operator:    validation.Equals,
+			ActualValue: 1,
+		})
+		return result
+	}
+
+	tests := []struct {
+		name          string
+		payload       AdjustPopularityPayload
+		mockResult    validation.ValidationResult
+		mockError     error
+		expectAward   bool
+		expectCheck   bool
+		expectPassed  bool
+		expectError   bool
+		expectReject  bool
+		errorContains string
+	}{
+		{
+			name:         "Success case - cooldown elapsed",
+			payload:      AdjustPopularityPayload{CharacterId: 12345, TargetId: 54321, Amount: 1},
+			mockResult:   validation.NewValidationResult(12345),
+			expectAward:  true,
+			expectCheck:  true,
+			expectPassed: true,
+		},
+		{
+			name:          "Failure case - cooldown active",
+			payload:       AdjustPopularityPayload{CharacterId: 12345, TargetId: 54321, Amount: -1},
+			mockResult:    cooldownActive(),
+			expectCheck:   true,
+			expectError:   true,
+			expectReject:  true,
+			errorContains: "fame cooldown active",
+		},
+		{
+			name:          "Error case - validation service error",
+			payload:       AdjustPopularityPayload{CharacterId: 12345, TargetId: 54321, Amount: 1},
+			mockError:     errors.New("validation service unavailable"),
+			expectError:   true,
+			errorContains: "validation service unavailable",
+		},
+		{
+			name:          "Error case - invalid amount",
+			payload:       AdjustPopularityPayload{CharacterId: 12345, TargetId: 54321, Amount: 2},
+			expectError:   true,
+			expectReject:  true,
+			errorContains: "fame amount must be 1 or -1",
+		},
+		{
+			name:          "Error case - self fame",
+			payload:       AdjustPopularityPayload{CharacterId: 12345, TargetId: 12345, Amount: 1},
+			expectError:   true,
+			expectReject:  true,
+			errorContains: "cannot give fame to themselves",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			charP := &mock.ProcessorMock{}
+			validP := &mock3.ProcessorMock{}
+
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			te, ctx := setupContext()
+			transactionId := uuid.New()
+
+			// Configure mocks
+			validP.ValidateCharacterStateFunc = func(characterId uint32, conditions []validation.ConditionInput) (validation.ValidationResult, error) {
+				assert.Equal(t, tt.payload.CharacterId, characterId)
+				assert.Len(t, conditions, 2)
+				assert.Equal(t, tt.payload.TargetId, conditions[1].ReferenceId)
+				// The cached saga changes while the cooldown is validated
+				if s, ok := GetCache().GetById(te.Id(), transactionId); ok {
+					s.Variables = s.Variables.With("validated", true)
+					GetCache().Put(te.Id(), s)
+				}
+				return tt.mockResult, tt.mockError
+			}
+			awarded := false
+			charP.AwardFameAndEmitFunc = func(transactionId uuid.UUID, worldId world.Id, characterId uint32, actorId uint32, actorType string, amount int8) error {
+				awarded = true
+				assert.Equal(t, tt.payload.TargetId, characterId)
+				assert.Equal(t, tt.payload.CharacterId, actorId)
+				assert.Equal(t, "CHARACTER", actorType)
+				assert.Equal(t, tt.payload.Amount, amount)
+				return nil
+			}
+
+			// Create test saga and step
+			step := Step[any]{
+				StepId:    "test-step",
+				Status:    Pending,
+				Action:    AdjustPopularity,
+				Payload:   tt.payload,
+				CreatedAt: time.Now(),
+				UpdatedAt: time.Now(),
+			}
+			saga := Saga{
+				TransactionId: transactionId,
+				SagaType:      QuestReward,
+				InitiatedBy:   "test",
+				Steps:         []Step[any]{step},
+			}
+			GetCache().Put(te.Id(), saga)
+			defer GetCache().Remove(te.Id(), saga.TransactionId)
+
+			// Execute
+			err := NewHandler(logger, ctx).WithCharacterProcessor(charP).WithValidationProcessor(validP).handleAdjustPopularity(saga, step)
+
+			// Verify
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectReject, errors.Is(err, ErrActionRejected))
+				if tt.errorContains != "" {
+					assert.Contains(t, err.Error(), tt.errorContains)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectAward, awarded)
+
+			// The cooldown decision is persisted with the saga
+			cached, ok := GetCache().GetById(te.Id(), saga.TransactionId)
+			assert.True(t, ok)
+			payload := cached.Steps[0].Payload.(AdjustPopularityPayload)
+			if !tt.expectCheck {
+				assert.Nil(t, payload.CooldownCheck)
+				return
+			}
+			if assert.NotNil(t, payload.CooldownCheck) {
+				assert.Equal(t, tt.expectPassed, payload.CooldownCheck.Passed)
+				assert.Len(t, payload.CooldownCheck.Details, len(tt.mockResult.Details()))
+			}
+			// Recording the decision retains the changes made to the saga meanwhile
+			assert.Equal(t, true, cached.Variables["validated"])
+		})
+	}
+}
+
+// TestHandleCharacterBuffCleanse tests the handleCharacterBuffCleanse function
+func TestHandleCharacterBuffCleanse(t *testing.T) {
+	expiresAt := time.Now().Add(time.Minute)
+	active := []buff.Model{
+		buff.NewBuilder(2001002, 300).AddChange(buff.NewStatChange("MAGIC_GUARD", 15)).SetExpiresAt(expiresAt).Build(),
+		buff.NewBuilder(-2022003, 60).AddChange(buff.NewStatChange("WEAPON_ATTACK", 5)).SetExpiresAt(expiresAt).Build(),
+	}
+
+	tests := []struct {
+		name          string
+		mockBuffs     []buff.Model
+		mockGetError  error
+		mockEmitError error
+		expectCleanse bool
+		expectCapture int
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name:          "Success case - active buffs captured",
+			mockBuffs:     active,
+			expectCleanse: true,
+			expectCapture: 2,
+		},
+		{
+			name:          "Success case - no active buffs",
+			mockBuffs:     []buff.Model{},
+			expectCleanse: true,
+		},
+		{
+			name:          "Error case - buff service unavailable",
+			mockGetError:  errors.New("buff service unavailable"),
+			expectError:   true,
+			errorContains: "buff service unavailable",
+		},
+		{
+			name:          "Error case - cleanse emit fails",
+			mockBuffs:     active,
+			mockEmitError: errors.New("kafka unavailable"),
+			expectCleanse: true,
+			expectCapture: 2,
+			expectError:   true,
+			errorContains: "kafka unavailable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buffP := &mock4.ProcessorMock{}
+
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			te, ctx := setupContext()
+
+			payload := CharacterBuffCleansePayload{CharacterId: 12345, WorldId: 0, ChannelId: 1}
+
+			// Configure mocks
+			buffP.GetByCharacterIdFunc = func(characterId uint32) ([]buff.Model, error) {
+				assert.Equal(t, payload.CharacterId, characterId)
+				return tt.mockBuffs, tt.mockGetError
+			}
+			cleansed := false
+			buffP.CancelAllAndEmitFunc = func(transactionId uuid.UUID, worldId world.Id, channelId channel.Id, characterId uint32) error {
+				cleansed = true
+				assert.Equal(t, payload.ChannelId, channelId)
+				assert.Equal(t, payload.CharacterId, characterId)
+				return tt.mockEmitError
+			}
+
+			// Create test saga and step
+			step := Step[any]{
+				StepId:    "test-step",
+				Status:    Pending,
+				Action:    CharacterBuffCleanse,
+				Payload:   payload,
+				CreatedAt: time.Now(),
+				UpdatedAt: time.Now(),
+			}
+			saga := Saga{
+				TransactionId: uuid.New(),
+				SagaType:      InventoryTransaction,
+				InitiatedBy:   "test",
+				Steps:         []Step[any]{step},
+			}
+			GetCache().Put(te.Id(), saga)
+			defer GetCache().Remove(te.Id(), saga.TransactionId)
+
+			// Execute
+			err := NewHandler(logger, ctx).WithBuffProcessor(buffP).handleCharacterBuffCleanse(saga, step)
+
+			// Verify
+			if tt.expectError {
+				assert.Error(t, err)
+				if tt.errorContains != "" {
+					assert.Contains(t, err.Error(), tt.errorContains)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectCleanse, cleansed)
+
+			// The captured buffs are persisted with the saga for compensation
+			cached, ok := GetCache().GetById(te.Id(), saga.TransactionId)
+			assert.True(t, ok)
+			captured := cached.Steps[0].Payload.(CharacterBuffCleansePayload).Captured
+			assert.Len(t, captured, tt.expectCapture)
+			if tt.expectCapture > 0 {
+				assert.Equal(t, int32(2001002), captured[0].SourceId)
+				assert.Equal(t, []BuffStatChange{{Type: "MAGIC_GUARD", Amount: 15}}, captured[0].Changes)
+				assert.Equal(t, expiresAt, captured[0].ExpiresAt)
+			}
+		})
+	}
+}
+
+// TestHandleResetSkillCooldowns tests the handleResetSkillCooldowns function
+func TestHandleResetSkillCooldowns(t *testing.T) {
+	tests := []struct {
+		name          string
+		payload       ResetSkillCooldownsPayload
+		mockError     error
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name:    "Success case - specific skills",
+			payload: ResetSkillCooldownsPayload{CharacterId: 12345, SkillIds: []uint32{2121004, 2121007}},
+		},
+		{
+			name:    "Success case - all skills",
+			payload: ResetSkillCooldownsPayload{CharacterId: 12345},
+		},
+		{
+			name:          "Error case - emit fails",
+			payload:       ResetSkillCooldownsPayload{CharacterId: 12345},
+			mockError:     errors.New("kafka unavailable"),
+			expectError:   true,
+			errorContains: "kafka unavailable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			skillP := &mock5.ProcessorMock{}
+
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			_, ctx := setupContext()
+
+			transactionId := uuid.New()
+			skillP.ResetCooldownsAndEmitFunc = func(tId uuid.UUID, characterId uint32, skillIds []uint32) error {
+				assert.Equal(t, transactionId, tId)
+				assert.Equal(t, tt.payload.CharacterId, characterId)
+				assert.Equal(t, tt.payload.SkillIds, skillIds)
+				return tt.mockError
+			}
+
+			step := Step[any]{
+				StepId:  "test-step",
+				Status:  Pending,
+				Action:  ResetSkillCooldowns,
+				Payload: tt.payload,
+			}
+			saga := Saga{
+				TransactionId: transactionId,
+				SagaType:      QuestReward,
+				InitiatedBy:   "test",
+				Steps:         []Step[any]{step},
+			}
+
+			// Execute
+			err := NewHandler(logger, ctx).WithSkillProcessor(skillP).handleResetSkillCooldowns(saga, step)
+
+			// Verify
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestHandleTransferCharacter tests the handleTransferCharacter function
+func TestHandleTransferCharacter(t *testing.T) {
+	tests := []struct {
+		name          string
+		payload       TransferCharacterPayload
+		mockError     error
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name:    "Success case",
+			payload: TransferCharacterPayload{CharacterId: 12345, WorldId: 1, SourceAccountId: 100, TargetAccountId: 200},
+		},
+		{
+			name:          "Error case - same account",
+			payload:       TransferCharacterPayload{CharacterId: 12345, WorldId: 1, SourceAccountId: 100, TargetAccountId: 100},
+			expectError:   true,
+			errorContains: "source and target account are the same",
+		},
+		{
+			name:          "Error case - emit fails",
+			payload:       TransferCharacterPayload{CharacterId: 12345, WorldId: 1, SourceAccountId: 100, TargetAccountId: 200},
+			mockError:     errors.New("kafka unavailable"),
+			expectError:   true,
+			errorContains: "kafka unavailable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			charP := &mock.ProcessorMock{}
+
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			_, ctx := setupContext()
+
+			transactionId := uuid.New()
+			charP.ChangeAccountAndEmitFunc = func(tId uuid.UUID, worldId world.Id, characterId uint32, accountId uint32) error {
+				assert.Equal(t, transactionId, tId)
+				assert.Equal(t, tt.payload.WorldId, worldId)
+				assert.Equal(t, tt.payload.CharacterId, characterId)
+				assert.Equal(t, tt.payload.TargetAccountId, accountId)
+				return tt.mockError
+			}
+
+			step := Step[any]{
+				StepId:  "test-step",
+				Status:  Pending,
+				Action:  TransferCharacter,
+				Payload: tt.payload,
+			}
+			saga := Saga{
+				TransactionId: transactionId,
+				SagaType:      AccountMerge,
+				InitiatedBy:   "test",
+				Steps:         []Step[any]{step},
+			}
+
+			// Execute
+			err := NewHandler(logger, ctx).WithCharacterProcessor(charP).handleTransferCharacter(saga, step)
+
+			// Verify
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestHandleVerifyAccountMerge tests the handleVerifyAccountMerge function
+func TestHandleVerifyAccountMerge(t *testing.T) {
+	tests := []struct {
+		name          string
+		mismatched    map[uint32]bool
+		mockError     error
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name: "Success case - all characters owned by target",
+		},
+		{
+			name:          "Failure case - character not re-parented",
+			mismatched:    map[uint32]bool{12346: true},
+			expectError:   true,
+			errorContains: "characters [12346] are not owned by account [200]",
+		},
+		{
+			name:          "Error case - validation service error",
+			mockError:     errors.New("validation service unavailable"),
+			expectError:   true,
+			errorContains: "validation service unavailable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validP := &mock3.ProcessorMock{}
+
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			_, ctx := setupContext()
+
+			validP.ValidateCharacterStateFunc = func(characterId uint32, conditions []validation.ConditionInput) (validation.ValidationResult, error) {
+				assert.Equal(t, []validation.ConditionInput{{Type: string(validation.AccountCondition), Operator: string(validation.Equals), Value: 200}}, conditions)
+				result := validation.NewValidationResult(characterId)
+				if tt.mismatched[characterId] {
+					result.AddConditionResult(validation.ConditionResult{Passed: false, Type: validation.AccountCondition})
+				}
+				return result, tt.mockError
+			}
+
+			step := Step[any]{
+				StepId:  "test-step",
+				Status:  Pending,
+				Action:  VerifyAccountMerge,
+				Payload: VerifyAccountMergePayload{TargetAccountId: 200, CharacterIds: []uint32{12345, 12346}},
+			}
+			saga := Saga{
+				TransactionId: uuid.New(),
+				SagaType:      AccountMerge,
+				InitiatedBy:   "test",
+				Steps:         []Step[any]{step},
+			}
+
+			// Execute
+			err := NewHandler(logger, ctx).WithValidationProcessor(validP).handleVerifyAccountMerge(saga, step)
+
+			// Verify
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestHandleRestoreAsset tests the handleRestoreAsset function
+func TestHandleRestoreAsset(t *testing.T) {
+	evidence := LossEvidence{TicketId: "CS-1024", Reason: "failed trade", LostAt: time.Now().Add(-time.Hour)}
+	expiration := time.Now().Add(7 * 24 * time.Hour).Truncate(time.Second)
+
+	tests := []struct {
+		name          string
+		evidence      LossEvidence
+		mockError     error
+		expectError   bool
+		expectEmit    bool
+		errorContains string
+	}{
+		{
+			name:       "Success case",
+			evidence:   evidence,
+			expectEmit: true,
+		},
+		{
+			name:          "Error case - missing ticket",
+			evidence:      LossEvidence{Reason: evidence.Reason, LostAt: evidence.LostAt},
+			expectError:   true,
+			errorContains: "ticket id",
+		},
+		{
+			name:          "Error case - loss in the future",
+			evidence:      LossEvidence{TicketId: evidence.TicketId, Reason: evidence.Reason, LostAt: time.Now().Add(time.Hour)},
+			expectError:   true,
+			errorContains: "in the future",
+		},
+		{
+			name:          "Error case - emit fails",
+			evidence:      evidence,
+			mockError:     errors.New("kafka unavailable"),
+			expectError:   true,
+			expectEmit:    true,
+			errorContains: "kafka unavailable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			_, ctx := setupContext()
+
+			transactionId := uuid.New()
+			payload := RestoreAssetPayload{
+				CharacterId:  12345,
+				TemplateId:   1302000,
+				Quantity:     1,
+				Expiration:   expiration,
+				OwnerId:      12345,
+				Flag:         1,
+				Rechargeable: 0,
+				Evidence:     tt.evidence,
+			}
+			emitted := false
+			compP := &mock2.ProcessorMock{
+				RequestRestoreItemFunc: func(tId uuid.UUID, characterId uint32, templateId uint32, quantity uint32, exp time.Time, ownerId uint32, flag uint16, rechargeable uint64) error {
+					emitted = true
+					assert.Equal(t, transactionId, tId)
+					assert.Equal(t, payload.CharacterId, characterId)
+					assert.Equal(t, payload.TemplateId, templateId)
+					assert.Equal(t, payload.Quantity, quantity)
+					assert.Equal(t, expiration, exp)
+					assert.Equal(t, payload.OwnerId, ownerId)
+					assert.Equal(t, payload.Flag, flag)
+					return tt.mockError
+				},
+			}
+
+			step := Step[any]{
+				StepId:  "test-step",
+				Status:  Pending,
+				Action:  RestoreAsset,
+				Payload: payload,
+			}
+			saga := Saga{
+				TransactionId: transactionId,
+				SagaType:      ItemRestoration,
+				InitiatedBy:   "gm-alice",
+				Steps:         []Step[any]{step},
+			}
+
+			// Execute
+			err := NewHandler(logger, ctx).WithCompartmentProcessor(compP).handleRestoreAsset(saga, step)
+
+			// Verify
+			assert.Equal(t, tt.expectEmit, emitted)
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+				if !tt.expectEmit {
+					assert.ErrorIs(t, err, ErrActionRejected)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestHandleRollbackCharacterToSnapshot tests the handleRollbackCharacterToSnapshot function
+func TestHandleRollbackCharacterToSnapshot(t *testing.T) {
+	tests := []struct {
+		name          string
+		mockError     error
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name: "Success case",
+		},
+		{
+			name:          "Error case - emit fails",
+			mockError:     errors.New("kafka unavailable"),
+			expectError:   true,
+			errorContains: "kafka unavailable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			_, ctx := setupContext()
+
+			transactionId := uuid.New()
+			payload := RollbackCharacterToSnapshotPayload{CharacterId: 12345, WorldId: 1, SnapshotId: 77}
+			charP := &mock.ProcessorMock{
+				RollbackToSnapshotAndEmitFunc: func(tId uuid.UUID, worldId world.Id, characterId uint32, snapshotId uint32) error {
+					assert.Equal(t, transactionId, tId)
+					assert.Equal(t, payload.WorldId, worldId)
+					assert.Equal(t, payload.CharacterId, characterId)
+					assert.Equal(t, payload.SnapshotId, snapshotId)
+					return tt.mockError
+				},
+			}
+
+			step := Step[any]{
+				StepId:  "test-step",
+				Status:  Pending,
+				Action:  RollbackCharacterToSnapshot,
+				Payload: payload,
+			}
+			saga := Saga{
+				TransactionId: transactionId,
+				SagaType:      CharacterRollback,
+				InitiatedBy:   "gm-alice",
+				Steps:         []Step[any]{step},
+			}
+
+			// Execute
+			err := NewHandler(logger, ctx).WithCharacterProcessor(charP).handleRollbackCharacterToSnapshot(saga, step)
+
+			// Verify
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestHandleRestoreInventorySnapshot tests the handleRestoreInventorySnapshot function
+func TestHandleRestoreInventorySnapshot(t *testing.T) {
+	tests := []struct {
+		name          string
+		mockError     error
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name: "Success case",
+		},
+		{
+			name:          "Error case - emit fails",
+			mockError:     errors.New("kafka unavailable"),
+			expectError:   true,
+			errorContains: "kafka unavailable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			_, ctx := setupContext()
+
+			transactionId := uuid.New()
+			payload := RestoreInventorySnapshotPayload{CharacterId: 12345, InventoryType: 2, SnapshotId: 77}
+			compP := &mock2.ProcessorMock{
+				RequestRestoreSnapshotFunc: func(tId uuid.UUID, characterId uint32, inventoryType byte, snapshotId uint32) error {
+					assert.Equal(t, transactionId, tId)
+					assert.Equal(t, payload.CharacterId, characterId)
+					assert.Equal(t, payload.InventoryType, inventoryType)
+					assert.Equal(t, payload.SnapshotId, snapshotId)
+					return tt.mockError
+				},
+			}
+
+			step := Step[any]{
+				StepId:  "test-step",
+				Status:  Pending,
+				Action:  RestoreInventorySnapshot,
+				Payload: payload,
+			}
+			saga := Saga{
+				TransactionId: transactionId,
+				SagaType:      CharacterRollback,
+				InitiatedBy:   "gm-alice",
+				Steps:         []Step[any]{step},
+			}
+
+			// Execute
+			err := NewHandler(logger, ctx).WithCompartmentProcessor(compP).handleRestoreInventorySnapshot(saga, step)
+
+			// Verify
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestHandleAdjustNpcShopStock tests the handleAdjustNpcShopStock function
+func TestHandleAdjustNpcShopStock(t *testing.T) {
+	tests := []struct {
+		name          string
+		amount        int32
+		mockError     error
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name:   "Success case - purchase decrements stock",
+			amount: -1,
+		},
+		{
+			name:          "Error case - zero adjustment",
+			amount:        0,
+			expectError:   true,
+			errorContains: "must not be 0",
+		},
+		{
+			name:          "Error case - emit fails",
+			amount:        -1,
+			mockError:     errors.New("kafka unavailable"),
+			expectError:   true,
+			errorContains: "kafka unavailable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			_, ctx := setupContext()
+
+			transactionId := uuid.New()
+			payload := AdjustNpcShopStockPayload{WorldId: 1, NpcId: 9201000, TemplateId: 2000000, Amount: tt.amount}
+			worldP := &mock6.ProcessorMock{
+				AdjustShopStockAndEmitFunc: func(tId uuid.UUID, worldId world.Id, npcId uint32, templateId uint32, amount int32) error {
+					assert.Equal(t, transactionId, tId)
+					assert.Equal(t, payload.WorldId, worldId)
+					assert.Equal(t, payload.NpcId, npcId)
+					assert.Equal(t, payload.TemplateId, templateId)
+					assert.Equal(t, payload.Amount, amount)
+					return tt.mockError
+				},
+			}
+
+			step := Step[any]{
+				StepId:  "test-step",
+				Status:  Pending,
+				Action:  AdjustNpcShopStock,
+				Payload: payload,
+			}
+			saga := Saga{
+				TransactionId: transactionId,
+				SagaType:      InventoryTransaction,
+				InitiatedBy:   "npc-9201000",
+				Steps:         []Step[any]{step},
+			}
+
+			// Execute
+			err := NewHandler(logger, ctx).WithWorldStateProcessor(worldP).handleAdjustNpcShopStock(saga, step)
+
+			// Verify
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestHandleSetWorldEventFlag tests the handleSetWorldEventFlag function
+func TestHandleSetWorldEventFlag(t *testing.T) {
+	tests := []struct {
+		name          string
+		key           string
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name: "Success case",
+			key:  "halloween2025",
+		},
+		{
+			name:          "Error case - empty key",
+			expectError:   true,
+			errorContains: "must not be empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			_, ctx := setupContext()
+
+			payload := SetWorldEventFlagPayload{WorldId: 1, Key: tt.key, Value: "on"}
+			worldP := &mock6.ProcessorMock{
+				SetEventFlagAndEmitFunc: func(tId uuid.UUID, worldId world.Id, key string, value string) error {
+					assert.Equal(t, payload.Key, key)
+					assert.Equal(t, payload.Value, value)
+					return nil
+				},
+			}
+
+			step := Step[any]{
+				StepId:  "test-step",
+				Status:  Pending,
+				Action:  SetWorldEventFlag,
+				Payload: payload,
+			}
+			saga := Saga{
+				TransactionId: uuid.New(),
+				SagaType:      InventoryTransaction,
+				InitiatedBy:   "event-script",
+				Steps:         []Step[any]{step},
+			}
+
+			// Execute
+			err := NewHandler(logger, ctx).WithWorldStateProcessor(worldP).handleSetWorldEventFlag(saga, step)
+
+			// Verify
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, ErrActionRejected)
+				assert.Contains(t, err.Error(), tt.errorContains)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestHandleValidateCoupon tests the handleValidateCoupon function
+func TestHandleValidateCoupon(t *testing.T) {
+	available := coupon.NewBuilder("SUMMER-2026", coupon.StatusAvailable).
+		SetMesos(5000).
+		AddItem(coupon.NewItem(2000000, 10)).
+		AddItem(coupon.NewItem(2049100, 1)).
+		Build()
+
+	tests := []struct {
+		name          string
+		mockCoupon    coupon.Model
+		mockError     error
+		inProgress    bool
+		expectSteps   []Action
+		expectError   bool
+		expectReject  bool
+		errorContains string
+	}{
+		{
+			name:        "Success case - consume and award steps added",
+			mockCoupon:  available,
+			expectSteps: []Action{ValidateCoupon, ConsumeCoupon, AwardMesos, AwardAsset, AwardAsset},
+		},
+		{
+			name:          "Error case - coupon already consumed",
+			mockCoupon:    coupon.NewBuilder("SUMMER-2026", coupon.StatusConsumed).Build(),
+			expectError:   true,
+			expectReject:  true,
+			errorContains: coupon.ErrNotAvailable.Error(),
+		},
+		{
+			name:          "Error case - coupon issued to another account",
+			mockCoupon:    coupon.NewBuilder("SUMMER-2026", coupon.StatusAvailable).SetAccountId(8).Build(),
+			expectError:   true,
+			expectReject:  true,
+			errorContains: coupon.ErrAccountMismatch.Error(),
+		},
+		{
+			name:          "Error case - coupon expired",
+			mockCoupon:    coupon.NewBuilder("SUMMER-2026", coupon.StatusAvailable).SetExpiresAt(time.Now().Add(-time.Hour)).Build(),
+			expectError:   true,
+			expectReject:  true,
+			errorContains: coupon.ErrExpired.Error(),
+		},
+		{
+			name:        "Success case - concurrent redemption left to the coupon service to consume once",
+			mockCoupon:  available,
+			inProgress:  true,
+			expectSteps: []Action{ValidateCoupon, ConsumeCoupon, AwardMesos, AwardAsset, AwardAsset},
+		},
+		{
+			name:          "Error case - coupon service unavailable",
+			mockError:     errors.New("coupon service unavailable"),
+			expectError:   true,
+			errorContains: "coupon service unavailable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			te, ctx := setupContext()
+
+			payload := ValidateCouponPayload{CharacterId: 12345, AccountId: 7, WorldId: 0, ChannelId: 1, Code: "SUMMER-2026"}
+			couponP := &mock7.ProcessorMock{
+				GetByCodeFunc: func(code string) (coupon.Model, error) {
+					assert.Equal(t, payload.Code, code)
+					return tt.mockCoupon, tt.mockError
+				},
+			}
+
+			if tt.inProgress {
+				other := Saga{
+					TransactionId: uuid.New(),
+					SagaType:      CouponRedemption,
+					InitiatedBy:   "character-12345",
+					Steps: []Step[any]{
+						{StepId: "validate", Status: Completed, Action: ValidateCoupon, Payload: payload},
+						{StepId: "validate_consume", Status: Pending, Action: ConsumeCoupon, Payload: ConsumeCouponPayload{CharacterId: 12345, AccountId: 7, Code: payload.Code}},
+					},
+				}
+				GetCache().Put(te.Id(), other)
+				defer GetCache().Remove(te.Id(), other.TransactionId)
+			}
+
+			step := Step[any]{
+				StepId:  "validate",
+				Status:  Pending,
+				Action:  ValidateCoupon,
+				Payload: payload,
+			}
+			saga := Saga{
+				TransactionId: uuid.New(),
+				SagaType:      CouponRedemption,
+				InitiatedBy:   "character-12345",
+				Steps:         []Step[any]{step},
+			}
+			GetCache().Put(te.Id(), saga)
+			defer GetCache().Remove(te.Id(), saga.TransactionId)
+
+			// Execute
+			err := NewHandler(logger, ctx).WithCouponProcessor(couponP).handleValidateCoupon(saga, step)
+
+			// Verify
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectReject, errors.Is(err, ErrActionRejected))
+				assert.Contains(t, err.Error(), tt.errorContains)
+				return
+			}
+			assert.NoError(t, err)
+
+			cached, ok := GetCache().GetById(te.Id(), saga.TransactionId)
+			assert.True(t, ok)
+			actions := make([]Action, 0, len(cached.Steps))
+			for _, st := range cached.Steps {
+				actions = append(actions, st.Action)
+			}
+			assert.Equal(t, tt.expectSteps, actions)
+
+			// The rewards are recorded with the validated step, and awarded to the redeeming character
+			rewards := cached.Steps[0].Payload.(ValidateCouponPayload).Rewards
+			assert.NotNil(t, rewards)
+			assert.Equal(t, int32(5000), rewards.Mesos)
+			assert.Equal(t, payload.Code, cached.Steps[1].Payload.(ConsumeCouponPayload).Code)
+			assert.Equal(t, int32(5000), cached.Steps[2].Payload.(AwardMesosPayload).Amount)
+			assert.Equal(t, uint32(2000000), cached.Steps[3].Payload.(AwardItemActionPayload).Item.TemplateId)
+			assert.Equal(t, uint32(2049100), cached.Steps[4].Payload.(AwardItemActionPayload).Item.TemplateId)
+		})
+	}
+}
+
+// TestHandleConsumeCoupon tests the handleConsumeCoupon function
+func TestHandleConsumeCoupon(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+	logger.SetLevel(logrus.DebugLevel)
+
+	_, ctx := setupContext()
+
+	transactionId := uuid.New()
+	payload := ConsumeCouponPayload{CharacterId: 12345, AccountId: 7, Code: "SUMMER-2026"}
+	consumed := false
+	couponP := &mock7.ProcessorMock{
+		ConsumeAndEmitFunc: func(tId uuid.UUID, accountId uint32, characterId uint32, code string) error {
+			consumed = true
+			// The transaction is the idempotency key of the redemption
+			assert.Equal(t, transactionId, tId)
+			assert.Equal(t, payload.AccountId, accountId)
+			assert.Equal(t, payload.CharacterId, characterId)
+			assert.Equal(t, payload.Code, code)
+			return nil
+		},
+	}
+
+	step := Step[any]{StepId: "consume", Status: Pending, Action: ConsumeCoupon, Payload: payload}
+	saga := Saga{TransactionId: transactionId, SagaType: CouponRedemption, InitiatedBy: "character-12345", Steps: []Step[any]{step}}
+
+	err := NewHandler(logger, ctx).WithCouponProcessor(couponP).handleConsumeCoupon(saga, step)
+	assert.NoError(t, err)
+	assert.True(t, consumed)
+}
+
+// TestHandleValidateDivorce tests the handleValidateDivorce function
+func TestHandleValidateDivorce(t *testing.T) {
+	married := marriage.NewBuilder(42, marriage.StatusMarried).
+		SetCharacterId(12345).
+		SetPartnerId(67890).
+		SetRingTemplateId(1112803).
+		Build()
+
+	tests := []struct {
+		name          string
+		payload       ValidateDivorcePayload
+		mockMarriage  marriage.Model
+		mockError     error
+		expectSteps   []Action
+		expectError   bool
+		expectReject  bool
+		errorContains string
+	}{
+		{
+			name:         "Success case - fee, dissolve and ring steps added",
+			payload:      ValidateDivorcePayload{CharacterId: 12345, WorldId: 0, ChannelId: 1, PartnerId: 67890, Fee: 500000},
+			mockMarriage: married,
+			expectSteps:  []Action{ValidateDivorce, DeductMesos, DissolveMarriage, DestroyAsset, DestroyAsset},
+		},
+		{
+			name:         "Success case - rings kept without a fee",
+			payload:      ValidateDivorcePayload{CharacterId: 12345, KeepRings: true},
+			mockMarriage: married,
+			expectSteps:  []Action{ValidateDivorce, DissolveMarriage},
+		},
+		{
+			name:          "Error case - character not married",
+			payload:       ValidateDivorcePayload{CharacterId: 12345},
+			mockMarriage:  marriage.NewBuilder(42, marriage.StatusDivorced).Build(),
+			expectError:   true,
+			expectReject:  true,
+			errorContains: marriage.ErrNotMarried.Error(),
+		},
+		{
+			name:          "Error case - married to another partner",
+			payload:       ValidateDivorcePayload{CharacterId: 12345, PartnerId: 11111},
+			mockMarriage:  married,
+			expectError:   true,
+			expectReject:  true,
+			errorContains: marriage.ErrPartnerMismatch.Error(),
+		},
+		{
+			name:          "Error case - marriage service unavailable",
+			payload:       ValidateDivorcePayload{CharacterId: 12345},
+			mockError:     errors.New("marriage service unavailable"),
+			expectError:   true,
+			errorContains: "marriage service unavailable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			te, ctx := setupContext()
+
+			marriP := &mock11.ProcessorMock{
+				GetByCharacterIdFunc: func(characterId uint32) (marriage.Model, error) {
+					assert.Equal(t, tt.payload.CharacterId, characterId)
+					return tt.mockMarriage, tt.mockError
+				},
+			}
+
+			step := Step[any]{StepId: "validate", Status: Pending, Action: ValidateDivorce, Payload: tt.payload}
+			saga := Saga{TransactionId: uuid.New(), SagaType: Divorce, InitiatedBy: "npc-9201002", Steps: []Step[any]{step}}
+			GetCache().Put(te.Id(), saga)
+			defer GetCache().Remove(te.Id(), saga.TransactionId)
+
+			// Execute
+			err := NewHandler(logger, ctx).WithMarriageProcessor(marriP).handleValidateDivorce(saga, step)
+
+			// Verify
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectReject, errors.Is(err, ErrActionRejected))
+				assert.Contains(t, err.Error(), tt.errorContains)
+				return
+			}
+			assert.NoError(t, err)
+
+			cached, ok := GetCache().GetById(te.Id(), saga.TransactionId)
+			assert.True(t, ok)
+			actions := make([]Action, 0, len(cached.Steps))
+			for _, st := range cached.Steps {
+				actions = append(actions, st.Action)
+			}
+			assert.Equal(t, tt.expectSteps, actions)
+
+			// The marriage is recorded with the validated step, and dissolved by the added step
+			assert.Equal(t, &MarriageRecord{MarriageId: 42, PartnerId: 67890, RingTemplateId: 1112803}, cached.Steps[0].Payload.(ValidateDivorcePayload).Marriage)
+			for _, st := range cached.Steps[1:] {
+				switch p := st.Payload.(type) {
+				case DeductMesosPayload:
+					assert.Equal(t, uint32(500000), p.Amount)
+				case DissolveMarriagePayload:
+					assert.Equal(t, DissolveMarriagePayload{CharacterId: 12345, MarriageId: 42, PartnerId: 67890}, p)
+				case DestroyAssetPayload:
+					assert.Equal(t, uint32(1112803), p.TemplateId)
+				}
+			}
+			if len(cached.Steps) == 5 {
+				assert.Equal(t, uint32(12345), cached.Steps[3].Payload.(DestroyAssetPayload).CharacterId)
+				assert.Equal(t, uint32(67890), cached.Steps[4].Payload.(DestroyAssetPayload).CharacterId)
+			}
+		})
+	}
+}
+
+// TestHandleDissolveMarriage tests the handleDissolveMarriage function
+func TestHandleDissolveMarriage(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+	logger.SetLevel(logrus.DebugLevel)
+
+	_, ctx := setupContext()
+
+	transactionId := uuid.New()
+	dissolved := 0
+	marriP := &mock11.ProcessorMock{
+		DivorceAndEmitFunc: func(tId uuid.UUID, characterId uint32, marriageId uint32, partnerId uint32) error {
+			dissolved++
+			assert.Equal(t, transactionId, tId)
+			assert.Equal(t, uint32(12345), characterId)
+			assert.Equal(t, uint32(42), marriageId)
+			assert.Equal(t, uint32(67890), partnerId)
+			return nil
+		},
+	}
+	h := NewHandler(logger, ctx).WithMarriageProcessor(marriP)
+
+	step := Step[any]{StepId: "dissolve", Status: Pending, Action: DissolveMarriage, Payload: DissolveMarriagePayload{CharacterId: 12345, MarriageId: 42, PartnerId: 67890}}
+	saga := Saga{TransactionId: transactionId, SagaType: Divorce, InitiatedBy: "npc-9201002", Steps: []Step[any]{step}}
+	assert.NoError(t, h.handleDissolveMarriage(saga, step))
+	assert.Equal(t, 1, dissolved)
+
+	// A marriage which is not identified is rejected without a command
+	step.Payload = DissolveMarriagePayload{CharacterId: 12345}
+	assert.ErrorIs(t, h.handleDissolveMarriage(saga, step), ErrActionRejected)
+	assert.Equal(t, 1, dissolved)
+}
+
+// TestHandleSealAsset tests the handleSealAsset, handleUnsealAsset and handleConfiscateAsset functions
+func TestHandleSealAsset(t *testing.T) {
+	tests := []struct {
+		name          string
+		action        Action
+		payload       any
+		mockError     error
+		expectCommand string
+		expectError   bool
+		expectReject  bool
+	}{
+		{
+			name:          "Success case - asset sealed",
+			action:        SealAsset,
+			payload:       SealAssetPayload{CharacterId: 12345, InventoryType: 1, Slot: 3, TemplateId: 1302000, Quantity: 1},
+			expectCommand: "reserve",
+		},
+		{
+			name:          "Success case - asset unsealed",
+			action:        UnsealAsset,
+			payload:       UnsealAssetPayload{CharacterId: 12345, InventoryType: 1, Slot: 3, TemplateId: 1302000, Quantity: 1},
+			expectCommand: "cancel",
+		},
+		{
+			name:          "Success case - asset confiscated",
+			action:        ConfiscateAsset,
+			payload:       ConfiscateAssetPayload{CharacterId: 12345, InventoryType: 1, Slot: 3},
+			expectCommand: "consume",
+		},
+		{
+			name:         "Error case - unidentified asset",
+			action:       SealAsset,
+			payload:      SealAssetPayload{CharacterId: 12345, InventoryType: 1, Slot: 3},
+			expectError:  true,
+			expectReject: true,
+		},
+		{
+			name:          "Error case - compartment service error",
+			action:        SealAsset,
+			payload:       SealAssetPayload{CharacterId: 12345, InventoryType: 1, Slot: 3, TemplateId: 1302000, Quantity: 1},
+			mockError:     errors.New("compartment service error"),
+			expectCommand: "reserve",
+			expectError:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			_, ctx := setupContext()
+
+			command := ""
+			compP := &mock2.ProcessorMock{
+				RequestReserveAssetFunc: func(transactionId uuid.UUID, characterId uint32, inventoryType byte, slot int16, templateId uint32, quantity uint32) error {
+					command = "reserve"
+					assert.Equal(t, uint32(1302000), templateId)
+					assert.Equal(t, uint32(1), quantity)
+					return tt.mockError
+				},
+				RequestCancelReservationFunc: func(transactionId uuid.UUID, characterId uint32, inventoryType byte, slot int16) error {
+					command = "cancel"
+					assert.Equal(t, int16(3), slot)
+					return tt.mockError
+				},
+				RequestConsumeAssetFunc: func(transactionId uuid.UUID, characterId uint32, inventoryType byte, slot int16) error {
+					command = "consume"
+					assert.Equal(t, int16(3), slot)
+					return tt.mockError
+				},
+			}
+			h := NewHandler(logger, ctx).WithCompartmentProcessor(compP)
+
+			step := Step[any]{StepId: "step", Status: Pending, Action: tt.action, Payload: tt.payload}
+			saga := Saga{TransactionId: uuid.New(), SagaType: DisputeHold, InitiatedBy: "gm-alice", Steps: []Step[any]{step}}
+
+			// Execute
+			handler, ok := h.GetHandler(tt.action)
+			assert.True(t, ok)
+			err := handler(saga, step)
+
+			// Verify
+			assert.Equal(t, tt.expectCommand, command)
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectReject, errors.Is(err, ErrActionRejected))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestHandleResolveDispute tests the handleResolveDispute function
+func TestHandleResolveDispute(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+	logger.SetLevel(logrus.DebugLevel)
+
+	te, ctx := setupContext()
+	h := NewHandler(logger, ctx)
+
+	step := Step[any]{StepId: "resolve", Status: Pending, Action: ResolveDispute, Payload: ResolveDisputePayload{TicketId: "CS-2048"}}
+	saga := Saga{TransactionId: uuid.New(), SagaType: DisputeHold, InitiatedBy: "gm-alice", Steps: []Step[any]{step}}
+	GetCache().Put(te.Id(), saga)
+	defer GetCache().Remove(te.Id(), saga.TransactionId)
+
+	assert.NoError(t, h.handleResolveDispute(saga, step))
+	cached, ok := GetCache().GetById(te.Id(), saga.TransactionId)
+	assert.True(t, ok)
+	assert.Equal(t, PendingResolution, cached.Hold)
+	assert.Contains(t, cached.HoldReason, "CS-2048")
+	assert.Equal(t, Pending, cached.Steps[0].Status)
+
+	// A dispute must identify its support ticket
+	step.Payload = ResolveDisputePayload{}
+	assert.ErrorIs(t, h.handleResolveDispute(saga, step), ErrActionRejected)
+}
+
+// TestHandleIssueTransportTicket tests the handleIssueTransportTicket function
+func TestHandleIssueTransportTicket(t *testing.T) {
+	tests := []struct {
+		name         string
+		payload      IssueTransportTicketPayload
+		mockError    error
+		expectCreate bool
+		expectError  bool
+		expectReject bool
+	}{
+		{
+			name:         "Success case - ticket issued",
+			payload:      IssueTransportTicketPayload{CharacterId: 12345, TemplateId: 4031045, Fare: 5000},
+			expectCreate: true,
+		},
+		{
+			name:         "Error case - ticket not identified",
+			payload:      IssueTransportTicketPayload{CharacterId: 12345, Fare: 5000},
+			expectError:  true,
+			expectReject: true,
+		},
+		{
+			name:         "Error case - compartment service error",
+			payload:      IssueTransportTicketPayload{CharacterId: 12345, TemplateId: 4031045, Fare: 5000},
+			mockError:    errors.New("compartment service error"),
+			expectCreate: true,
+			expectError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			_, ctx := setupContext()
+
+			created := false
+			compP := &mock2.ProcessorMock{
+				RequestCreateItemFunc: func(transactionId uuid.UUID, characterId uint32, templateId uint32, quantity uint32) error {
+					created = true
+					assert.Equal(t, uint32(4031045), templateId)
+					assert.Equal(t, uint32(1), quantity)
+					return tt.mockError
+				},
+			}
+			h := NewHandler(logger, ctx).WithCompartmentProcessor(compP)
+
+			step := Step[any]{StepId: "ticket", Status: Pending, Action: IssueTransportTicket, Payload: tt.payload}
+			saga := Saga{TransactionId: uuid.New(), SagaType: Transport, InitiatedBy: "npc-2012000", Steps: []Step[any]{step}}
+
+			// Execute
+			handler, ok := h.GetHandler(IssueTransportTicket)
+			assert.True(t, ok)
+			err := handler(saga, step)
+
+			// Verify
+			assert.Equal(t, tt.expectCreate, created)
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectReject, errors.Is(err, ErrActionRejected))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestHandleSpawnEscort tests the handleSpawnEscort function
+func TestHandleSpawnEscort(t *testing.T) {
+	fieldId := field.Id("0:1:100000000:00000000-0000-0000-0000-000000000000")
+	tests := []struct {
+		name         string
+		payload      SpawnEscortPayload
+		mockError    error
+		expectSpawn  bool
+		expectError  bool
+		expectReject bool
+	}{
+		{
+			name:        "Success case - escort spawned",
+			payload:     SpawnEscortPayload{CharacterId: 12345, FieldId: fieldId, NpcId: 1012112, DestinationMapId: 100000001},
+			expectSpawn: true,
+		},
+		{
+			name:         "Error case - escort not identified",
+			payload:      SpawnEscortPayload{CharacterId: 12345, FieldId: fieldId, DestinationMapId: 100000001},
+			expectError:  true,
+			expectReject: true,
+		},
+		{
+			name:         "Error case - invalid field",
+			payload:      SpawnEscortPayload{CharacterId: 12345, FieldId: "invalid", NpcId: 1012112, DestinationMapId: 100000001},
+			expectError:  true,
+			expectReject: true,
+		},
+		{
+			name:        "Error case - npc service error",
+			payload:     SpawnEscortPayload{CharacterId: 12345, FieldId: fieldId, NpcId: 1012112, DestinationMapId: 100000001},
+			mockError:   errors.New("npc service error"),
+			expectSpawn: true,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			_, ctx := setupContext()
+
+			spawned := false
+			npcP := &mock12.ProcessorMock{
+				SpawnEscortAndEmitFunc: func(transactionId uuid.UUID, f field.Model, npcId uint32, characterId uint32, destinationMapId _map.Id) error {
+					spawned = true
+					assert.Equal(t, _map.Id(100000000), f.MapId())
+					assert.Equal(t, uint32(1012112), npcId)
+					assert.Equal(t, _map.Id(100000001), destinationMapId)
+					return tt.mockError
+				},
+			}
+			h := NewHandler(logger, ctx).WithNpcProcessor(npcP)
+
+			step := Step[any]{StepId: "spawn", Status: Pending, Action: SpawnEscort, Payload: tt.payload}
+			saga := Saga{TransactionId: uuid.New(), SagaType: EscortQuest, InitiatedBy: "npc-1012112", Steps: []Step[any]{step}}
+
+			// Execute
+			handler, ok := h.GetHandler(SpawnEscort)
+			assert.True(t, ok)
+			err := handler(saga, step)
+
+			// Verify
+			assert.Equal(t, tt.expectSpawn, spawned)
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectReject, errors.Is(err, ErrActionRejected))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestHandleNpcConversationState tests the handleNpcConversationState function
+func TestHandleNpcConversationState(t *testing.T) {
+	tests := []struct {
+		name          string
+		payload       NpcConversationStatePayload
+		mockError     error
+		expectResume  bool
+		expectError   bool
+		expectReject  bool
+		expectOutcome map[string]any
+	}{
+		{
+			name:          "Success case - conversation resumed with the saga's outcome",
+			payload:       NpcConversationStatePayload{CharacterId: 12345, NpcId: 9010000, State: "reward_granted", Outcome: map[string]any{"itemId": 2000000, "tier": "gold"}},
+			expectResume:  true,
+			expectOutcome: map[string]any{"itemId": 2000000, "tier": "gold", "characterId": 12345},
+		},
+		{
+			name:         "Error case - missing npc",
+			payload:      NpcConversationStatePayload{CharacterId: 12345, State: "reward_granted"},
+			expectError:  true,
+			expectReject: true,
+		},
+		{
+			name:         "Error case - missing state",
+			payload:      NpcConversationStatePayload{CharacterId: 12345, NpcId: 9010000},
+			expectError:  true,
+			expectReject: true,
+		},
+		{
+			name:         "Error case - npc service error",
+			payload:      NpcConversationStatePayload{CharacterId: 12345, NpcId: 9010000, State: "reward_granted"},
+			mockError:    errors.New("npc service error"),
+			expectResume: true,
+			expectError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			_, ctx := setupContext()
+
+			resumed := false
+			npcP := &mock12.ProcessorMock{
+				ResumeConversationAndEmitFunc: func(transactionId uuid.UUID, worldId world.Id, channelId channel.Id, characterId uint32, npcId uint32, state string, outcome map[string]any) error {
+					resumed = true
+					assert.Equal(t, uint32(12345), characterId)
+					assert.Equal(t, uint32(9010000), npcId)
+					assert.Equal(t, "reward_granted", state)
+					if tt.expectOutcome != nil {
+						assert.Equal(t, tt.expectOutcome, outcome)
+					}
+					return tt.mockError
+				},
+			}
+			h := NewHandler(logger, ctx).WithNpcProcessor(npcP)
+
+			step := Step[any]{StepId: "resume", Status: Pending, Action: NpcConversationState, Payload: tt.payload}
+			saga := Saga{TransactionId: uuid.New(), SagaType: QuestReward, InitiatedBy: "npc-9010000", Variables: Variables{"characterId": 12345, "tier": "silver"}, Steps: []Step[any]{step}}
+
+			// Execute
+			handler, ok := h.GetHandler(NpcConversationState)
+			assert.True(t, ok)
+			err := handler(saga, step)
+
+			// Verify
+			assert.Equal(t, tt.expectResume, resumed)
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectReject, errors.Is(err, ErrActionRejected))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestHandleCreateAccountCharacterSlot tests the handleCreateAccountCharacterSlot function
+func TestHandleCreateAccountCharacterSlot(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+	logger.SetLevel(logrus.DebugLevel)
+
+	_, ctx := setupContext()
+
+	transactionId := uuid.New()
+	var changed []int16
+	acctP := &mock8.ProcessorMock{
+		ChangeCharacterSlotsAndEmitFunc: func(tId uuid.UUID, accountId uint32, worldId world.Id, amount int16) error {
+			assert.Equal(t, transactionId, tId)
+			assert.Equal(t, uint32(7), accountId)
+			assert.Equal(t, world.Id(1), worldId)
+			changed = append(changed, amount)
+			return nil
+		},
+	}
+	h := NewHandler(logger, ctx).WithAccountProcessor(acctP)
+
+	step := Step[any]{StepId: "slot", Status: Pending, Action: CreateAccountCharacterSlot, Payload: CreateAccountCharacterSlotPayload{AccountId: 7, WorldId: 1, Amount: 2}}
+	saga := Saga{TransactionId: transactionId, SagaType: CharacterSlotPurchase, InitiatedBy: "cash-shop", Steps: []Step[any]{step}}
+	assert.NoError(t, h.handleCreateAccountCharacterSlot(saga, step))
+	assert.Equal(t, []int16{2}, changed)
+
+	// A purchase of no slots is rejected without a command
+	step.Payload = CreateAccountCharacterSlotPayload{AccountId: 7, WorldId: 1}
+	assert.ErrorIs(t, h.handleCreateAccountCharacterSlot(saga, step), ErrActionRejected)
+	assert.Equal(t, []int16{2}, changed)
+}
+
+// TestHandleGrantPremiumTime tests the handleGrantPremiumTime function
+func TestHandleGrantPremiumTime(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+	logger.SetLevel(logrus.DebugLevel)
+
+	_, ctx := setupContext()
+
+	transactionId := uuid.New()
+	var changed []int64
+	acctP := &mock8.ProcessorMock{
+		ChangePremiumTimeAndEmitFunc: func(tId uuid.UUID, accountId uint32, seconds int64) error {
+			assert.Equal(t, transactionId, tId)
+			assert.Equal(t, uint32(7), accountId)
+			changed = append(changed, seconds)
+			return nil
+		},
+	}
+	h := NewHandler(logger, ctx).WithAccountProcessor(acctP)
+
+	step := Step[any]{StepId: "vip", Status: Pending, Action: GrantPremiumTime, Payload: GrantPremiumTimePayload{AccountId: 7, Seconds: 2592000}}
+	saga := Saga{TransactionId: transactionId, SagaType: InventoryTransaction, InitiatedBy: "cash-shop", Steps: []Step[any]{step}}
+	assert.NoError(t, h.handleGrantPremiumTime(saga, step))
+	assert.Equal(t, []int64{2592000}, changed)
+
+	// A grant of no time is rejected without a command
+	step.Payload = GrantPremiumTimePayload{AccountId: 7}
+	assert.ErrorIs(t, h.handleGrantPremiumTime(saga, step), ErrActionRejected)
+	assert.Equal(t, []int64{2592000}, changed)
+}
+
+// TestHandleGrantStorageCapacity tests the handleGrantStorageCapacity function
+func TestHandleGrantStorageCapacity(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+	logger.SetLevel(logrus.DebugLevel)
+
+	_, ctx := setupContext()
+
+	transactionId := uuid.New()
+	var changed []int16
+	storP := &mock16.ProcessorMock{
+		ChangeCapacityAndEmitFunc: func(tId uuid.UUID, worldId world.Id, accountId uint32, amount int16) error {
+			assert.Equal(t, transactionId, tId)
+			assert.Equal(t, world.Id(1), worldId)
+			assert.Equal(t, uint32(7), accountId)
+			changed = append(changed, amount)
+			return nil
+		},
+	}
+	h := NewHandler(logger, ctx).WithStorageProcessor(storP)
+
+	step := Step[any]{StepId: "storage", Status: Pending, Action: GrantStorageCapacity, Payload: GrantStorageCapacityPayload{AccountId: 7, WorldId: 1, Amount: 4}}
+	saga := Saga{TransactionId: transactionId, SagaType: InventoryTransaction, InitiatedBy: "cash-shop", Steps: []Step[any]{step}}
+	assert.NoError(t, h.handleGrantStorageCapacity(saga, step))
+	assert.Equal(t, []int16{4}, changed)
+
+	// A purchase of no slots is rejected without a command
+	step.Payload = GrantStorageCapacityPayload{AccountId: 7, WorldId: 1}
+	assert.ErrorIs(t, h.handleGrantStorageCapacity(saga, step), ErrActionRejected)
+	assert.Equal(t, []int16{4}, changed)
+}
+
+// TestHandleUpdateCharacterAlignment tests the handleUpdateCharacterAlignment function
+func TestHandleUpdateCharacterAlignment(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+	logger.SetLevel(logrus.DebugLevel)
+
+	_, ctx := setupContext()
+
+	transactionId := uuid.New()
+	var changed []int32
+	factP := &mock13.ProcessorMock{
+		ChangePointsAndEmitFunc: func(tId uuid.UUID, worldId world.Id, characterId uint32, factionId uint32, amount int32) error {
+			assert.Equal(t, transactionId, tId)
+			assert.Equal(t, uint32(12345), characterId)
+			assert.Equal(t, uint32(2), factionId)
+			changed = append(changed, amount)
+			return nil
+		},
+	}
+	h := NewHandler(logger, ctx).WithFactionProcessor(factP)
+
+	step := Step[any]{StepId: "alignment", Status: Pending, Action: UpdateCharacterAlignment, Payload: UpdateCharacterAlignmentPayload{CharacterId: 12345, FactionId: 2, Amount: -50}}
+	saga := Saga{TransactionId: transactionId, SagaType: QuestReward, InitiatedBy: "npc-2101013", Steps: []Step[any]{step}}
+	assert.NoError(t, h.handleUpdateCharacterAlignment(saga, step))
+	assert.Equal(t, []int32{-50}, changed)
+
+	// Changes of no points, or with no faction, are rejected without a command
+	step.Payload = UpdateCharacterAlignmentPayload{CharacterId: 12345, FactionId: 2}
+	assert.ErrorIs(t, h.handleUpdateCharacterAlignment(saga, step), ErrActionRejected)
+	step.Payload = UpdateCharacterAlignmentPayload{CharacterId: 12345, Amount: 50}
+	assert.ErrorIs(t, h.handleUpdateCharacterAlignment(saga, step), ErrActionRejected)
+	assert.Equal(t, []int32{-50}, changed)
+}
+
+func TestHandleResetInstanceCooldown(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+	logger.SetLevel(logrus.DebugLevel)
+
+	te, ctx := setupContext()
+
+	expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+	cooldowns := []instance2.Cooldown{
+		instance2.NewCooldown(1, time.Now().Add(-time.Hour)),
+		instance2.NewCooldown(2, expiresAt),
+	}
+	var reset []uint32
+	instP := &mock14.ProcessorMock{
+		GetCooldownsFunc: func(characterId uint32) ([]instance2.Cooldown, error) {
+			assert.Equal(t, uint32(12345), characterId)
+			return cooldowns, nil
+		},
+		ResetCooldownAndEmitFunc: func(transactionId uuid.UUID, worldId world.Id, characterId uint32, instanceId uint32) error {
+			assert.Equal(t, uint32(12345), characterId)
+			reset = append(reset, instanceId)
+			return nil
+		},
+	}
+	h := NewHandler(logger, ctx).WithInstanceProcessor(instP)
+
+	tests := []struct {
+		name       string
+		instanceId uint32
+		expectPrev *time.Time
+	}{
+		{name: "active cooldown is recorded", instanceId: 2, expectPrev: &expiresAt},
+		{name: "expired cooldown is not recorded", instanceId: 1},
+		{name: "missing cooldown is not recorded", instanceId: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reset = nil
+			step := Step[any]{StepId: "cooldown", Status: Pending, Action: ResetInstanceCooldown, Payload: ResetInstanceCooldownPayload{CharacterId: 12345, InstanceId: tt.instanceId}}
+			saga := Saga{TransactionId: uuid.New(), SagaType: InventoryTransaction, InitiatedBy: "gm-tools", Steps: []Step[any]{step}}
+			GetCache().Put(te.Id(), saga)
+			defer GetCache().Remove(te.Id(), saga.TransactionId)
+
+			assert.NoError(t, h.handleResetInstanceCooldown(saga, step))
+			assert.Equal(t, []uint32{tt.instanceId}, reset)
+
+			// The cleared cooldown is recorded for compensation
+			cached, ok := GetCache().GetById(te.Id(), saga.TransactionId)
+			assert.True(t, ok)
+			assert.Equal(t, tt.expectPrev, cached.Steps[0].Payload.(ResetInstanceCooldownPayload).Previous)
+		})
+	}
+
+	// Resets of no instance are rejected without a command
+	reset = nil
+	step := Step[any]{StepId: "cooldown", Status: Pending, Action: ResetInstanceCooldown, Payload: ResetInstanceCooldownPayload{CharacterId: 12345}}
+	saga := Saga{TransactionId: uuid.New(), SagaType: InventoryTransaction, InitiatedBy: "gm-tools", Steps: []Step[any]{step}}
+	assert.ErrorIs(t, h.handleResetInstanceCooldown(saga, step), ErrActionRejected)
+	assert.Empty(t, reset)
+}
+
+// TestHandleApplyTitleBuffOnLogin tests the handleApplyTitleBuffOnLogin function
+func TestHandleApplyTitleBuffOnLogin(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+	logger.SetLevel(logrus.DebugLevel)
+
+	te, ctx := setupContext()
+
+	var registered []uuid.UUID
+	sessP := &mock15.ProcessorMock{
+		RegisterTitleBuffAndEmitFunc: func(transactionId uuid.UUID, worldId world.Id, characterId uint32, effectId uuid.UUID, titleId uint32, duration uint32, expiresAt *time.Time) error {
+			assert.Equal(t, uint32(12345), characterId)
+			assert.Equal(t, uint32(1142000), titleId)
+			assert.Equal(t, uint32(3600), duration)
+			registered = append(registered, effectId)
+			return nil
+		},
+	}
+	h := NewHandler(logger, ctx).WithSessionProcessor(sessP)
+
+	step := Step[any]{StepId: "title", Status: Pending, Action: ApplyTitleBuffOnLogin, Payload: ApplyTitleBuffOnLoginPayload{CharacterId: 12345, TitleId: 1142000, Duration: 3600}}
+	saga := Saga{TransactionId: uuid.New(), SagaType: QuestReward, InitiatedBy: "event-service", Steps: []Step[any]{step}}
+	GetCache().Put(te.Id(), saga)
+	defer GetCache().Remove(te.Id(), saga.TransactionId)
+
+	assert.NoError(t, h.handleApplyTitleBuffOnLogin(saga, step))
+	require.Len(t, registered, 1)
+
+	// The effect is recorded for compensation, and a redelivered registration identifies the same effect
+	cached, ok := GetCache().GetById(te.Id(), saga.TransactionId)
+	require.True(t, ok)
+	payload := cached.Steps[0].Payload.(ApplyTitleBuffOnLoginPayload)
+	assert.Equal(t, &registered[0], payload.EffectId)
+	assert.NoError(t, h.handleApplyTitleBuffOnLogin(cached, cached.Steps[0]))
+	assert.Equal(t, []uuid.UUID{registered[0], registered[0]}, registered)
+
+	// Effects which cannot be applied are rejected without a command
+	expired := time.Now().Add(-time.Minute)
+	for _, p := range []ApplyTitleBuffOnLoginPayload{
+		{CharacterId: 12345, Duration: 3600},
+		{CharacterId: 12345, TitleId: 1142000},
+		{CharacterId: 12345, TitleId: 1142000, Duration: 3600, ExpiresAt: &expired},
+	} {
+		registered = nil
+		step := Step[any]{StepId: "title", Status: Pending, Action: ApplyTitleBuffOnLogin, Payload: p}
+		saga := Saga{TransactionId: uuid.New(), SagaType: QuestReward, InitiatedBy: "event-service", Steps: []Step[any]{step}}
+		assert.ErrorIs(t, h.handleApplyTitleBuffOnLogin(saga, step), ErrActionRejected)
+		assert.Empty(t, registered)
+	}
+}
+
+// TestHandleEmitAnalyticsEvent tests the handleEmitAnalyticsEvent function
+func TestHandleEmitAnalyticsEvent(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+	logger.SetLevel(logrus.DebugLevel)
+
+	_, ctx := setupContext()
+
+	var published []analytics2.Event
+	var publishErr error
+	analytP := &mock9.ProcessorMock{
+		PublishAndEmitFunc: func(e analytics2.Event) error {
+			published = append(published, e)
+			return publishErr
+		},
+	}
+	h := NewHandler(logger, ctx).WithAnalyticsProcessor(analytP)
+
+	step := Step[any]{StepId: "analytics", Status: Pending, Action: EmitAnalyticsEvent, Payload: EmitAnalyticsEventPayload{Name: "quest_completed", Properties: map[string]any{"questId": 1001}}}
+	saga := Saga{TransactionId: uuid.New(), SagaType: QuestReward, InitiatedBy: "npc-9010000", Labels: map[string]string{"event": "halloween2025"}, Steps: []Step[any]{step}}
+
+	assert.NoError(t, h.handleEmitAnalyticsEvent(saga, step))
+	assert.Len(t, published, 1)
+	assert.Equal(t, saga.TransactionId, published[0].TransactionId)
+	assert.Equal(t, "quest_reward", published[0].SagaType)
+	assert.Equal(t, "npc-9010000", published[0].InitiatedBy)
+	assert.Equal(t, "analytics", published[0].StepId)
+	assert.Equal(t, "halloween2025", published[0].Labels["event"])
+	assert.Equal(t, "quest_completed", published[0].Name)
+	assert.Equal(t, 1001, published[0].Properties["questId"])
+
+	// Analytics are best-effort, so failing to publish does not fail the step
+	publishErr = errors.New("kafka unavailable")
+	assert.NoError(t, h.handleEmitAnalyticsEvent(saga, step))
+	assert.Len(t, published, 2)
+
+	// Events without a name are skipped
+	step.Payload = EmitAnalyticsEventPayload{}
+	assert.NoError(t, h.handleEmitAnalyticsEvent(saga, step))
+	assert.Len(t, published, 2)
+	assert.True(t, completesLocally(EmitAnalyticsEvent))
+}
+
+// TestHandleApplyCharacterExpPenalty tests the handleApplyCharacterExpPenalty function
+func TestHandleApplyCharacterExpPenalty(t *testing.T) {
+	tests := []struct {
+		name          string
+		amount        uint32
+		mockError     error
+		expectDeduct  bool
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name:         "Success case",
+			amount:       1500,
+			expectDeduct: true,
+		},
+		{
+			name:          "Error case - zero amount",
+			expectError:   true,
+			errorContains: "must not be 0",
+		},
+		{
+			name:          "Error case - emit fails",
+			amount:        1500,
+			mockError:     errors.New("kafka unavailable"),
+			expectDeduct:  true,
+			expectError:   true,
+			errorContains: "kafka unavailable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			_, ctx := setupContext()
+
+			payload := ApplyCharacterExpPenaltyPayload{CharacterId: 12345, WorldId: 0, ChannelId: 1, Amount: tt.amount}
+			deducted := false
+			charP := &mock.ProcessorMock{
+				DeductExperienceAndEmitFunc: func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, amount uint32) error {
+					deducted = true
+					assert.Equal(t, payload.CharacterId, characterId)
+					assert.Equal(t, payload.ChannelId, channelId)
+					assert.Equal(t, payload.Amount, amount)
+					return tt.mockError
+				},
+			}
+
+			step := Step[any]{StepId: "test-step", Status: Pending, Action: ApplyCharacterExpPenalty, Payload: payload}
+			saga := Saga{TransactionId: uuid.New(), SagaType: DeathPenalty, InitiatedBy: "channel-reaper", Steps: []Step[any]{step}}
+
+			// Execute
+			err := NewHandler(logger, ctx).WithCharacterProcessor(charP).handleApplyCharacterExpPenalty(saga, step)
+
+			// Verify
+			assert.Equal(t, tt.expectDeduct, deducted)
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestHandleApplyDurabilityPenalty tests the handleApplyDurabilityPenalty function
+func TestHandleApplyDurabilityPenalty(t *testing.T) {
+	tests := []struct {
+		name          string
+		percent       byte
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name:    "Success case",
+			percent: 10,
+		},
+		{
+			name:          "Error case - zero percent",
+			expectError:   true,
+			errorContains: "must be between 1 and 100",
+		},
+		{
+			name:          "Error case - percent above 100",
+			percent:       101,
+			expectError:   true,
+			errorContains: "must be between 1 and 100",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			_, ctx := setupContext()
+
+			payload := ApplyDurabilityPenaltyPayload{CharacterId: 12345, Percent: tt.percent}
+			compP := &mock2.ProcessorMock{
+				RequestChangeDurabilityFunc: func(transactionId uuid.UUID, characterId uint32, percent int8) error {
+					assert.Equal(t, payload.CharacterId, characterId)
+					// Durability is reduced by the penalty
+					assert.Equal(t, -int8(tt.percent), percent)
+					return nil
+				},
+			}
+
+			step := Step[any]{StepId: "test-step", Status: Pending, Action: ApplyDurabilityPenalty, Payload: payload}
+			saga := Saga{TransactionId: uuid.New(), SagaType: DeathPenalty, InitiatedBy: "channel-reaper", Steps: []Step[any]{step}}
+
+			// Execute
+			err := NewHandler(logger, ctx).WithCompartmentProcessor(compP).handleApplyDurabilityPenalty(saga, step)
+
+			// Verify
+			if tt.expectError {
+				assert.ErrorIs(t, err, ErrActionRejected)
+				assert.Contains(t, err.Error(), tt.errorContains)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestHandleModifyAssetExpiration(t *testing.T) {
+	now := time.Now()
+	rented := now.Add(24 * time.Hour)
+	expired := now.Add(-24 * time.Hour)
+	set := now.Add(72 * time.Hour)
+
+	tests := []struct {
+		name          string
+		payload       ModifyAssetExpirationPayload
+		expiration    time.Time
+		expectAfter   time.Time
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name:        "Success case - rental extended from its expiration",
+			payload:     ModifyAssetExpirationPayload{Slot: 1, ExtendBy: 3600},
+			expiration:  rented,
+			expectAfter: rented.Add(time.Hour),
+		},
+		{
+			name:        "Success case - expired rental extended from now",
+			payload:     ModifyAssetExpirationPayload{Slot: 1, ExtendBy: 3600},
+			expiration:  expired,
+			expectAfter: now.Add(time.Hour),
+		},
+		{
+			name:        "Success case - expiration set",
+			payload:     ModifyAssetExpirationPayload{Slot: 1, TemplateId: 1302000, Expiration: set},
+			expiration:  rented,
+			expectAfter: set,
+		},
+		{
+			name:          "Error case - neither expiration nor extension",
+			payload:       ModifyAssetExpirationPayload{Slot: 1},
+			expectError:   true,
+			errorContains: "exactly one of expiration or extendBy",
+		},
+		{
+			name:          "Error case - both expiration and extension",
+			payload:       ModifyAssetExpirationPayload{Slot: 1, Expiration: set, ExtendBy: 3600},
+			expectError:   true,
+			errorContains: "exactly one of expiration or extendBy",
+		},
+		{
+			name:          "Error case - empty slot",
+			payload:       ModifyAssetExpirationPayload{Slot: 2, ExtendBy: 3600},
+			expiration:    rented,
+			expectError:   true,
+			errorContains: "no asset in slot [2]",
+		},
+		{
+			name:          "Error case - unexpected template",
+			payload:       ModifyAssetExpirationPayload{Slot: 1, TemplateId: 1302001, ExtendBy: 3600},
+			expiration:    rented,
+			expectError:   true,
+			errorContains: "not [1302001]",
+		},
+		{
+			name:          "Error case - permanent asset extended",
+			payload:       ModifyAssetExpirationPayload{Slot: 1, ExtendBy: 3600},
+			expectError:   true,
+			errorContains: "does not expire",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			te, ctx := setupContext()
+
+			payload := tt.payload
+			payload.CharacterId = 12345
+			payload.InventoryType = byte(inventory.TypeValueEquip)
+			changed := false
+			compP := &mock2.ProcessorMock{
+				GetByTypeFunc: func(characterId uint32, inventoryType inventory.Type) (compartment.Model, error) {
+					id := uuid.New()
+					a := asset.NewBuilder[any](1, id, 1302000, 1, asset.ReferenceTypeEquipable).SetSlot(1).SetExpiration(tt.expiration).Build()
+					return compartment.NewBuilder(id, characterId, inventoryType, 24).AddAsset(a).Build(), nil
+				},
+				RequestChangeExpirationFunc: func(transactionId uuid.UUID, characterId uint32, inventoryType byte, slot int16, expiration time.Time) error {
+					changed = true
+					assert.Equal(t, payload.CharacterId, characterId)
+					assert.Equal(t, payload.InventoryType, inventoryType)
+					assert.Equal(t, payload.Slot, slot)
+					assert.WithinDuration(t, tt.expectAfter, expiration, time.Second)
+					return nil
+				},
+			}
+
+			step := Step[any]{StepId: "test-step", Status: Pending, Action: ModifyAssetExpiration, Payload: payload}
+			saga := Saga{TransactionId: uuid.New(), SagaType: InventoryTransaction, InitiatedBy: "cash-shop", Steps: []Step[any]{step}}
+			GetCache().Put(te.Id(), saga)
+			defer GetCache().Remove(te.Id(), saga.TransactionId)
+
+			// Execute
+			err := NewHandler(logger, ctx).WithCompartmentProcessor(compP).handleModifyAssetExpiration(saga, step)
+
+			// Verify
+			if tt.expectError {
+				assert.ErrorIs(t, err, ErrActionRejected)
+				assert.Contains(t, err.Error(), tt.errorContains)
+				assert.False(t, changed)
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, changed)
+
+			// The prior expiration is recorded for compensation
+			cached, ok := GetCache().GetById(te.Id(), saga.TransactionId)
+			assert.True(t, ok)
+			recorded := cached.Steps[0].Payload.(ModifyAssetExpirationPayload)
+			if assert.NotNil(t, recorded.Previous) {
+				assert.True(t, tt.expiration.Equal(*recorded.Previous))
+			}
+		})
+	}
+}
+
+func TestHandleApplyHammer(t *testing.T) {
+	tests := []struct {
+		name          string
+		payload       ApplyHammerPayload
+		referenceType asset.ReferenceType
+		slots         uint16
+		hammers       uint32
+		expectSlots   uint16
+		expectHammers uint32
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name:          "Success case - slot added",
+			payload:       ApplyHammerPayload{Slot: 1, TemplateId: 1302000, Slots: 1, MaxHammers: 2},
+			slots:         3,
+			hammers:       1,
+			expectSlots:   4,
+			expectHammers: 2,
+		},
+		{
+			name:          "Success case - equipped item hammered without a limit",
+			payload:       ApplyHammerPayload{Slot: -11, Slots: 1},
+			hammers:       5,
+			expectSlots:   1,
+			expectHammers: 6,
+		},
+		{
+			name:          "Error case - no slots added",
+			payload:       ApplyHammerPayload{Slot: 1},
+			expectError:   true,
+			errorContains: "at least one upgrade slot",
+		},
+		{
+			name:          "Error case - empty slot",
+			payload:       ApplyHammerPayload{Slot: 2, Slots: 1},
+			expectError:   true,
+			errorContains: "no equip in slot [2]",
+		},
+		{
+			name:          "Error case - unexpected template",
+			payload:       ApplyHammerPayload{Slot: 1, TemplateId: 1302001, Slots: 1},
+			expectError:   true,
+			errorContains: "not [1302001]",
+		},
+		{
+			name:          "Error case - cash equip",
+			payload:       ApplyHammerPayload{Slot: 1, Slots: 1},
+			referenceType: asset.ReferenceTypeCashEquipable,
+			expectError:   true,
+			errorContains: "cannot be hammered",
+		},
+		{
+			name:          "Error case - hammer limit reached",
+			payload:       ApplyHammerPayload{Slot: 1, Slots: 1, MaxHammers: 2},
+			hammers:       2,
+			expectError:   true,
+			errorContains: "already has [2] hammers applied",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			te, ctx := setupContext()
+
+			payload := tt.payload
+			payload.CharacterId = 12345
+			referenceType := tt.referenceType
+			if referenceType == "" {
+				referenceType = asset.ReferenceTypeEquipable
+			}
+			applied := false
+			compP := &mock2.ProcessorMock{
+				GetByTypeFunc: func(characterId uint32, inventoryType inventory.Type) (compartment.Model, error) {
+					assert.Equal(t, inventory.TypeValueEquip, inventoryType)
+					id := uuid.New()
+					rd := asset.NewEquipableReferenceDataBuilder().SetSlots(tt.slots).SetHammersApplied(tt.hammers).Build()
+					a := asset.NewBuilder[any](1, id, 1302000, 1, referenceType).SetSlot(tt.payload.Slot).SetReferenceData(rd).Build()
+					if tt.payload.Slot == 2 {
+						a = asset.NewBuilder[any](1, id, 1302000, 1, referenceType).SetSlot(1).SetReferenceData(rd).Build()
+					}
+					return compartment.NewBuilder(id, characterId, inventoryType, 24).AddAsset(a).Build(), nil
+				},
+				RequestSetUpgradeSlotsFunc: func(transactionId uuid.UUID, characterId uint32, slot int16, slots uint16, hammersApplied uint32) error {
+					applied = true
+					assert.Equal(t, payload.CharacterId, characterId)
+					assert.Equal(t, payload.Slot, slot)
+					assert.Equal(t, tt.expectSlots, slots)
+					assert.Equal(t, tt.expectHammers, hammersApplied)
+					return nil
+				},
+			}
+
+			step := Step[any]{StepId: "test-step", Status: Pending, Action: ApplyHammer, Payload: payload}
+			saga := Saga{TransactionId: uuid.New(), SagaType: InventoryTransaction, InitiatedBy: "channel-hammer", Steps: []Step[any]{step}}
+			GetCache().Put(te.Id(), saga)
+			defer GetCache().Remove(te.Id(), saga.TransactionId)
+
+			// Execute
+			err := NewHandler(logger, ctx).WithCompartmentProcessor(compP).handleApplyHammer(saga, step)
+
+			// Verify
+			if tt.expectError {
+				assert.ErrorIs(t, err, ErrActionRejected)
+				assert.Contains(t, err.Error(), tt.errorContains)
+				assert.False(t, applied)
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, applied)
+
+			// The equip's upgrade slots are recorded for compensation
+			cached, ok := GetCache().GetById(te.Id(), saga.TransactionId)
+			assert.True(t, ok)
+			assert.Equal(t, &UpgradeSlotsPayload{Slots: tt.slots, HammersApplied: tt.hammers}, cached.Steps[0].Payload.(ApplyHammerPayload).Snapshot)
+		})
+	}
+}
+
+// TestHandleEquipAssetByTemplate tests that the asset of the template is equipped from the lowest inventory slot holding
+// it, which is recorded for compensation
+func TestHandleEquipAssetByTemplate(t *testing.T) {
+	tests := []struct {
+		name          string
+		payload       EquipAssetByTemplatePayload
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name:    "Success case - equipped from the lowest slot holding the template",
+			payload: EquipAssetByTemplatePayload{TemplateId: 1302000, Destination: -11},
+		},
+		{
+			name:          "Error case - template not held",
+			payload:       EquipAssetByTemplatePayload{TemplateId: 1302001, Destination: -11},
+			expectError:   true,
+			errorContains: "item [1302001] is not held",
+		},
+		{
+			name:          "Error case - template is not equipment",
+			payload:       EquipAssetByTemplatePayload{TemplateId: 2000000, Destination: -11},
+			expectError:   true,
+			errorContains: "item [2000000] is not equipment",
+		},
+		{
+			name:          "Error case - destination is not an equipped slot",
+			payload:       EquipAssetByTemplatePayload{TemplateId: 1302000, Destination: 5},
+			expectError:   true,
+			errorContains: "slot [5] is not an equipped slot",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			te, ctx := setupContext()
+
+			payload := tt.payload
+			payload.CharacterId = 12345
+			equipped := false
+			compP := &mock2.ProcessorMock{
+				GetByTypeFunc: func(characterId uint32, inventoryType inventory.Type) (compartment.Model, error) {
+					assert.Equal(t, inventory.TypeValueEquip, inventoryType)
+					id := uuid.New()
+					b := compartment.NewBuilder(id, characterId, inventoryType, 24)
+					for _, slot := range []int16{-11, 7, 3} {
+						b.AddAsset(asset.NewBuilder[any](uint32(slot+20), id, 1302000, 1, asset.ReferenceTypeEquipable).SetSlot(slot).Build())
+					}
+					return b.Build(), nil
+				},
+				RequestEquipAssetFunc: func(transactionId uuid.UUID, characterId uint32, inventoryType byte, source int16, destination int16) error {
+					equipped = true
+					assert.Equal(t, payload.CharacterId, characterId)
+					assert.Equal(t, byte(inventory.TypeValueEquip), inventoryType)
+					assert.Equal(t, int16(3), source)
+					assert.Equal(t, payload.Destination, destination)
+					return nil
+				},
+			}
+
+			step := Step[any]{StepId: "test-step", Status: Pending, Action: EquipAssetByTemplate, Payload: payload}
+			saga := Saga{TransactionId: uuid.New(), SagaType: CharacterCreation, InitiatedBy: "login", Steps: []Step[any]{step}}
+			GetCache().Put(te.Id(), saga)
+			defer GetCache().Remove(te.Id(), saga.TransactionId)
+
+			// Execute
+			err := NewHandler(logger, ctx).WithCompartmentProcessor(compP).handleEquipAssetByTemplate(saga, step)
+
+			// Verify
+			if tt.expectError {
+				assert.ErrorIs(t, err, ErrActionRejected)
+				assert.Contains(t, err.Error(), tt.errorContains)
+				assert.False(t, equipped)
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, equipped)
+
+			// The slot equipped from is recorded for compensation
+			cached, ok := GetCache().GetById(te.Id(), saga.TransactionId)
+			assert.True(t, ok)
+			assert.Equal(t, int16(3), cached.Steps[0].Payload.(EquipAssetByTemplatePayload).Source)
+		})
+	}
+}
+
+// petCompartments returns the compartments of a character with a pet, summoned unless its slot is negative, and pet
+// equipment held in inventory slot 4 and worn in slot -14
+func petCompartments(petSlot int8) func(characterId uint32, inventoryType inventory.Type) (compartment.Model, error) {
+	return func(characterId uint32, inventoryType inventory.Type) (compartment.Model, error) {
+		id := uuid.New()
+		b := compartment.NewBuilder(id, characterId, inventoryType, 24)
+		if inventoryType == inventory.TypeValueCash {
+			rd := asset.NewPetReferenceDataBuilder().SetSlot(petSlot).Build()
+			b.AddAsset(asset.NewBuilder[any](1, id, 5000000, 7001, asset.ReferenceTypePet).SetSlot(1).SetReferenceData(rd).Build())
+			return b.Build(), nil
+		}
+		for _, slot := range []int16{-14, 4} {
+			b.AddAsset(asset.NewBuilder[any](uint32(slot+20), id, 1802000, 1, asset.ReferenceTypeEquipable).SetSlot(slot).Build())
+		}
+		return b.Build(), nil
+	}
+}
+
+// TestHandlePetEquip tests that pet equipment is equipped to a summoned pet from the inventory slot holding it, which
+// is recorded for compensation
+func TestHandlePetEquip(t *testing.T) {
+	tests := []struct {
+		name          string
+		payload       PetEquipPayload
+		petSlot       int8
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name:    "Success case - equipped to a summoned pet",
+			payload: PetEquipPayload{PetId: 7001, TemplateId: 1802000, Destination: -14},
+		},
+		{
+			name:          "Error case - pet not summoned",
+			payload:       PetEquipPayload{PetId: 7001, TemplateId: 1802000, Destination: -14},
+			petSlot:       -1,
+			expectError:   true,
+			errorContains: "pet [7001] is not summoned",
+		},
+		{
+			name:          "Error case - pet not held",
+			payload:       PetEquipPayload{PetId: 7002, TemplateId: 1802000, Destination: -14},
+			expectError:   true,
+			errorContains: "pet [7002] is not held",
+		},
+		{
+			name:          "Error case - template is not pet equipment",
+			payload:       PetEquipPayload{PetId: 7001, TemplateId: 1302000, Destination: -14},
+			expectError:   true,
+			errorContains: "item [1302000] is not pet equipment",
+		},
+		{
+			name:          "Error case - destination is not an equipped slot",
+			payload:       PetEquipPayload{PetId: 7001, TemplateId: 1802000, Destination: 5},
+			expectError:   true,
+			errorContains: "slot [5] is not an equipped slot",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			te, ctx := setupContext()
+
+			payload := tt.payload
+			payload.CharacterId = 12345
+			equipped := false
+			compP := &mock2.ProcessorMock{
+				GetByTypeFunc: petCompartments(tt.petSlot),
+				RequestEquipAssetFunc: func(transactionId uuid.UUID, characterId uint32, inventoryType byte, source int16, destination int16) error {
+					equipped = true
+					assert.Equal(t, payload.CharacterId, characterId)
+					assert.Equal(t, byte(inventory.TypeValueEquip), inventoryType)
+					assert.Equal(t, int16(4), source)
+					assert.Equal(t, payload.Destination, destination)
+					return nil
+				},
+			}
+
+			step := Step[any]{StepId: "test-step", Status: Pending, Action: PetEquip, Payload: payload}
+			saga := Saga{TransactionId: uuid.New(), SagaType: InventoryTransaction, InitiatedBy: "pet", Steps: []Step[any]{step}}
+			GetCache().Put(te.Id(), saga)
+			defer GetCache().Remove(te.Id(), saga.TransactionId)
+
+			// Execute
+			err := NewHandler(logger, ctx).WithCompartmentProcessor(compP).handlePetEquip(saga, step)
+
+			// Verify
+			if tt.expectError {
+				assert.ErrorIs(t, err, ErrActionRejected)
+				assert.Contains(t, err.Error(), tt.errorContains)
+				assert.False(t, equipped)
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, equipped)
+
+			// The slot equipped from is recorded for compensation
+			cached, ok := GetCache().GetById(te.Id(), saga.TransactionId)
+			assert.True(t, ok)
+			assert.Equal(t, int16(4), cached.Steps[0].Payload.(PetEquipPayload).Source)
+		})
+	}
+}
+
+// TestHandlePetUnequip tests that pet equipment is unequipped from a summoned pet to the inventory slot, recording the
+// equipment unequipped
+func TestHandlePetUnequip(t *testing.T) {
+	tests := []struct {
+		name          string
+		payload       PetUnequipPayload
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name:    "Success case - unequipped from a summoned pet",
+			payload: PetUnequipPayload{PetId: 7001, Source: -14, Destination: 6},
+		},
+		{
+			name:          "Error case - slot does not hold pet equipment",
+			payload:       PetUnequipPayload{PetId: 7001, Source: -15, Destination: 6},
+			expectError:   true,
+			errorContains: "slot [-15] does not hold pet equipment",
+		},
+		{
+			name:          "Error case - destination is not an inventory slot",
+			payload:       PetUnequipPayload{PetId: 7001, Source: -14, Destination: -1},
+			expectError:   true,
+			errorContains: "slot [-1] is not an inventory slot",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			te, ctx := setupContext()
+
+			payload := tt.payload
+			payload.CharacterId = 12345
+			unequipped := false
+			compP := &mock2.ProcessorMock{
+				GetByTypeFunc: petCompartments(0),
+				RequestUnequipAssetFunc: func(transactionId uuid.UUID, characterId uint32, inventoryType byte, source int16, destination int16) error {
+					unequipped = true
+					assert.Equal(t, payload.Source, source)
+					assert.Equal(t, payload.Destination, destination)
+					return nil
+				},
+			}
+
+			step := Step[any]{StepId: "test-step", Status: Pending, Action: PetUnequip, Payload: payload}
+			saga := Saga{TransactionId: uuid.New(), SagaType: InventoryTransaction, InitiatedBy: "pet", Steps: []Step[any]{step}}
+			GetCache().Put(te.Id(), saga)
+			defer GetCache().Remove(te.Id(), saga.TransactionId)
+
+			// Execute
+			err := NewHandler(logger, ctx).WithCompartmentProcessor(compP).handlePetUnequip(saga, step)
+
+			// Verify
+			if tt.expectError {
+				assert.ErrorIs(t, err, ErrActionRejected)
+				assert.Contains(t, err.Error(), tt.errorContains)
+				assert.False(t, unequipped)
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, unequipped)
+
+			cached, ok := GetCache().GetById(te.Id(), saga.TransactionId)
+			assert.True(t, ok)
+			assert.Equal(t, uint32(1802000), cached.Steps[0].Payload.(PetUnequipPayload).TemplateId)
+		})
+	}
+}
+
+func TestHandleDeductMesos(t *testing.T) {
+	insufficient := func() validation.ValidationResult {
+		result := validation.NewValidationResult(12345)
+		result.AddConditionResult(validation.ConditionResult{
+			Passed:      false,
+			Description: "Meso >= 5000000",
+			Type:        validation.MesoCondition,
+			Operator:    validation.GreaterEqual,
+			Value:       5000000,
+			ActualValue: 1000,
+		})
+		return result
+	}
+
+	tests := []struct {
+		name          string
+		amount        uint32
+		mockResult    validation.ValidationResult
+		mockError     error
+		expectError   bool
+		expectReject  error
+		expectCode    string
+		errorContains string
+	}{
+		{
+			name:       "Success case",
+			amount:     5000000,
+			mockResult: validation.NewValidationResult(12345),
+		},
+		{
+			name:          "Error case - zero amount",
+			expectError:   true,
+			expectReject:  ErrActionRejected,
+			errorContains: "must be between 1 and",
+		},
+		{
+			name:          "Error case - amount overflows",
+			amount:        math.MaxInt32 + 1,
+			expectError:   true,
+			expectReject:  ErrActionRejected,
+			errorContains: "must be between 1 and",
+		},
+		{
+			name:          "Error case - insufficient mesos",
+			amount:        5000000,
+			mockResult:    insufficient(),
+			expectError:   true,
+			expectReject:  ErrActionRejected,
+			expectCode:    ErrorCodeInsufficientMesos,
+			errorContains: "insufficient mesos",
+		},
+		{
+			name:          "Error case - validation service error",
+			amount:        5000000,
+			mockError:     errors.New("validation service unavailable"),
+			expectError:   true,
+			errorContains: "validation service unavailable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			_, ctx := setupContext()
+
+			payload := DeductMesosPayload{CharacterId: 12345, WorldId: 0, ChannelId: 1, ActorId: 2010008, ActorType: "NPC", Amount: tt.amount}
+			deducted := false
+			charP := &mock.ProcessorMock{
+				AwardMesosAndEmitFunc: func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, actorId uint32, actorType string, amount int32) error {
+					deducted = true
+					assert.Equal(t, payload.CharacterId, characterId)
+					assert.Equal(t, payload.ActorId, actorId)
+					// The fee is emitted as a negative meso adjustment
+					assert.Equal(t, -int32(tt.amount), amount)
+					return nil
+				},
+			}
+
+			validP := &mock3.ProcessorMock{
+				ValidateCharacterStateFunc: func(characterId uint32, conditions []validation.ConditionInput) (validation.ValidationResult, error) {
+					// The character must have at least the mesos deducted
+					assert.Equal(t, payload.CharacterId, characterId)
+					assert.Equal(t, []validation.ConditionInput{{Type: string(validation.MesoCondition), Operator: string(validation.GreaterEqual), Value: int(tt.amount)}}, conditions)
+					return tt.mockResult, tt.mockError
+				},
+			}
+
+			step := Step[any]{StepId: "test-step", Status: Pending, Action: DeductMesos, Payload: payload}
+			saga := Saga{TransactionId: uuid.New(), SagaType: GuildEmblemPurchase, InitiatedBy: "npc-2010008", Steps: []Step[any]{step}}
+
+			// Execute
+			err := NewHandler(logger, ctx).WithCharacterProcessor(charP).WithValidationProcessor(validP).handleDeductMesos(saga, step)
+
+			// Verify
+			assert.Equal(t, !tt.expectError, deducted)
+			if tt.expectError {
+				assert.Error(t, err)
+				if tt.expectReject != nil {
+					assert.ErrorIs(t, err, tt.expectReject)
+				}
+				if tt.expectCode != "" {
+					var re RejectedError
+					require.ErrorAs(t, err, &re)
+					assert.Equal(t, tt.expectCode, re.Code)
+				}
+				assert.Contains(t, err.Error(), tt.errorContains)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestHandleVerifyAndConsumeTicket(t *testing.T) {
+	tests := []struct {
+		name          string
+		payload       VerifyAndConsumeTicketPayload
+		expectSlot    int16
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name:       "Success case - single ticket",
+			payload:    VerifyAndConsumeTicketPayload{TemplateId: 4001007},
+			expectSlot: 2,
+		},
+		{
+			name:       "Success case - tickets held across stacks",
+			payload:    VerifyAndConsumeTicketPayload{TemplateId: 4001007, Quantity: 4, Variable: "ticket"},
+			expectSlot: 2,
+		},
+		{
+			name:          "Error case - too few tickets",
+			payload:       VerifyAndConsumeTicketPayload{TemplateId: 4001007, Quantity: 5},
+			expectError:   true,
+			errorContains: "holds [4] of ticket [4001007], requiring [5]",
+		},
+		{
+			name:          "Error case - ticket not held",
+			payload:       VerifyAndConsumeTicketPayload{TemplateId: 4001008},
+			expectError:   true,
+			errorContains: "holds [0] of ticket [4001008]",
+		},
+		{
+			name:          "Error case - invalid variable",
+			payload:       VerifyAndConsumeTicketPayload{TemplateId: 4001007, Variable: "ticket.proof"},
+			expectError:   true,
+			errorContains: "must be an identifier",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			te, ctx := setupContext()
+
+			payload := tt.payload
+			payload.CharacterId = 12345
+			consumed := false
+			compP := &mock2.ProcessorMock{
+				GetByTypeFunc: func(characterId uint32, inventoryType inventory.Type) (compartment.Model, error) {
+					assert.Equal(t, inventory.TypeValueETC, inventoryType)
+					id := uuid.New()
+					stack := func(assetId uint32, slot int16, quantity uint32) asset.Model[any] {
+						return asset.NewBuilder[any](assetId, id, 4001007, assetId, asset.ReferenceTypeEtc).
+							SetSlot(slot).
+							SetReferenceData(asset.NewEtcReferenceDataBuilder().SetQuantity(quantity).Build()).
+							Build()
+					}
+					return compartment.NewBuilder(id, characterId, inventoryType, 24).AddAsset(stack(7, 2, 1)).AddAsset(stack(8, 5, 3)).Build(), nil
+				},
+				RequestDestroyItemFunc: func(transactionId uuid.UUID, characterId uint32, templateId uint32, quantity uint32) error {
+					consumed = true
+					assert.Equal(t, payload.CharacterId, characterId)
+					assert.Equal(t, payload.TemplateId, templateId)
+					assert.Equal(t, max(payload.Quantity, 1), quantity)
+					return nil
+				},
+			}
+
+			step := Step[any]{StepId: "test-step", Status: Pending, Action: VerifyAndConsumeTicket, Payload: payload}
+			saga := Saga{TransactionId: uuid.New(), SagaType: InventoryTransaction, InitiatedBy: "npc-9020000", Steps: []Step[any]{step}}
+			GetCache().Put(te.Id(), saga)
+			defer GetCache().Remove(te.Id(), saga.TransactionId)
+
+			// Execute
+			err := NewHandler(logger, ctx).WithCompartmentProcessor(compP).handleVerifyAndConsumeTicket(saga, step)
+
+			// Verify
+			if tt.expectError {
+				assert.ErrorIs(t, err, ErrActionRejected)
+				assert.Contains(t, err.Error(), tt.errorContains)
+				assert.False(t, consumed)
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, consumed)
+
+			// The proof of consumption is recorded for the steps which follow
+			cached, ok := GetCache().GetById(te.Id(), saga.TransactionId)
+			assert.True(t, ok)
+			proof := cached.Steps[0].Payload.(VerifyAndConsumeTicketPayload).Proof
+			if assert.NotNil(t, proof) {
+				assert.Equal(t, tt.expectSlot, proof.Slot)
+				assert.Equal(t, uint32(7), proof.AssetId)
+				assert.Equal(t, max(payload.Quantity, 1), proof.Quantity)
+			}
+			if payload.Variable != "" {
+				recorded, ok := cached.Variables[payload.Variable].(map[string]any)
+				if assert.True(t, ok) {
+					assert.Equal(t, float64(7), recorded["assetId"])
+				}
+			}
+		})
+	}
+}
+
+func TestHandleReactorActions(t *testing.T) {
+	instance := uuid.New()
+	fieldId := field.NewBuilder(world.Id(0), channel.Id(1), _map.Id(103000800)).SetInstance(instance).Build().Id()
+
+	tests := []struct {
+		name          string
+		action        Action
+		payload       any
+		mockError     error
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name:    "Success case - reactor state changed",
+			action:  AdjustReactorState,
+			payload: AdjustReactorStatePayload{FieldId: fieldId, ReactorId: 7, State: 1},
+		},
+		{
+			name:    "Success case - reactor hit",
+			action:  HitReactor,
+			payload: HitReactorPayload{CharacterId: 12345, FieldId: fieldId, ReactorId: 7, SkillId: 1001005},
+		},
+		{
+			name:          "Error case - invalid field",
+			action:        HitReactor,
+			payload:       HitReactorPayload{CharacterId: 12345, FieldId: "lobby", ReactorId: 7},
+			expectError:   true,
+			errorContains: "invalid field id",
+		},
+		{
+			name:          "Error case - command not emitted",
+			action:        AdjustReactorState,
+			payload:       AdjustReactorStatePayload{FieldId: fieldId, ReactorId: 7, State: 1},
+			mockError:     errors.New("kafka unavailable"),
+			expectError:   true,
+			errorContains: "kafka unavailable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			_, ctx := setupContext()
+
+			emitted := false
+			reactP := &mock10.ProcessorMock{
+				ChangeStateAndEmitFunc: func(tId uuid.UUID, f field.Model, reactorId uint32, state int8) error {
+					emitted = true
+					assert.Equal(t, instance, f.Instance())
+					assert.Equal(t, _map.Id(103000800), f.MapId())
+					assert.Equal(t, uint32(7), reactorId)
+					assert.Equal(t, int8(1), state)
+					return tt.mockError
+				},
+				HitAndEmitFunc: func(tId uuid.UUID, f field.Model, reactorId uint32, characterId uint32, stance uint16, skillId uint32) error {
+					emitted = true
+					assert.Equal(t, instance, f.Instance())
+					assert.Equal(t, uint32(12345), characterId)
+					assert.Equal(t, uint32(1001005), skillId)
+					return tt.mockError
+				},
+			}
+
+			step := Step[any]{
+				StepId:  "test-step",
+				Status:  Pending,
+				Action:  tt.action,
+				Payload: tt.payload,
+			}
+			saga := Saga{
+				TransactionId: uuid.New(),
+				SagaType:      QuestReward,
+				InitiatedBy:   "reactor-script",
+				Steps:         []Step[any]{step},
+			}
+
+			// Execute
+			h := NewHandler(logger, ctx).WithReactorProcessor(reactP)
+			handler, ok := h.GetHandler(tt.action)
+			assert.True(t, ok)
+			err := handler(saga, step)
+
+			// Verify
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, emitted)
+		})
+	}
+}
+
+func TestHandleSetVariable(t *testing.T) {
+	tests := []struct {
+		name          string
+		payload       SetVariablePayload
+		expected      any
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name:     "Success case",
+			payload:  SetVariablePayload{Name: "bonus", Value: 250},
+			expected: float64(250),
+		},
+		{
+			name:          "Error case - invalid name",
+			payload:       SetVariablePayload{Name: "bonus.mesos", Value: 250},
+			expectError:   true,
+			errorContains: "must be an identifier",
+		},
+		{
+			name:          "Error case - null value",
+			payload:       SetVariablePayload{Name: "bonus"},
+			expectError:   true,
+			errorContains: "must not be null",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			te, ctx := setupContext()
+
+			step := Step[any]{StepId: "test-step", Status: Pending, Action: SetVariable, Payload: tt.payload}
+			vars := Variables{"characterId": float64(12345)}
+			saga := Saga{TransactionId: uuid.New(), SagaType: QuestReward, InitiatedBy: "npc-9010000", Variables: vars, Steps: []Step[any]{step}}
+			GetCache().Put(te.Id(), saga)
+			defer GetCache().Remove(te.Id(), saga.TransactionId)
+
+			// Execute
+			err := NewHandler(logger, ctx).handleSetVariable(saga, step)
+
+			// Verify
+			if tt.expectError {
+				assert.ErrorIs(t, err, ErrActionRejected)
+				assert.Contains(t, err.Error(), tt.errorContains)
+				return
+			}
+			assert.NoError(t, err)
+			s, ok := GetCache().GetById(te.Id(), saga.TransactionId)
+			assert.True(t, ok)
+			assert.Equal(t, tt.expected, s.Variables[tt.payload.Name])
+			assert.Equal(t, float64(12345), s.Variables["characterId"])
+			// Variables shared with other copies of the saga are not modified
+			assert.NotContains(t, vars, tt.payload.Name)
+		})
+	}
+}
+
+func TestHandleCharacterExperienceLock(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+	_, ctx := setupContext()
+	transactionId := uuid.New()
+
+	locked, unlocked := false, false
+	charP := &mock.ProcessorMock{
+		LockExperienceAndEmitFunc: func(tId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, reason string) error {
+			locked = true
+			assert.Equal(t, transactionId, tId)
+			assert.Equal(t, uint32(12345), characterId)
+			assert.Equal(t, "level_locked_event", reason)
+			return nil
+		},
+		UnlockExperienceAndEmitFunc: func(tId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id) error {
+			unlocked = true
+			assert.Equal(t, uint32(12345), characterId)
+			return errors.New("character service error")
+		},
+	}
+	handler := NewHandler(logger, ctx).WithCharacterProcessor(charP)
+	s := Saga{TransactionId: transactionId, SagaType: QuestReward, InitiatedBy: "event"}
+
+	err := handler.handleCharacterExperienceLock(s, Step[any]{StepId: "lock", Status: Pending, Action: CharacterExperienceLock, Payload: CharacterExperienceLockPayload{CharacterId: 12345, Reason: "level_locked_event"}})
+	assert.NoError(t, err)
+	assert.True(t, locked)
+
+	err = handler.handleCharacterExperienceUnlock(s, Step[any]{StepId: "unlock", Status: Pending, Action: CharacterExperienceUnlock, Payload: CharacterExperienceUnlockPayload{CharacterId: 12345}})
+	assert.Error(t, err)
+	assert.True(t, unlocked)
+
+	err = handler.handleCharacterExperienceLock(s, Step[any]{StepId: "lock", Status: Pending, Action: CharacterExperienceLock, Payload: CharacterExperienceUnlockPayload{CharacterId: 12345}})
+	assert.Error(t, err)
+}
+
+func TestHandleUpdateCharacterResource(t *testing.T) {
+	value := func(v int32) *int32 {
+		return &v
+	}
+
+	tests := []struct {
+		name          string
+		payload       UpdateCharacterResourcePayload
+		current       []character.Resource
+		expectValue   int32
+		expectPrev    int32
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name:        "Success case - energy charge set",
+			payload:     UpdateCharacterResourcePayload{Resource: character2.ResourceEnergyCharge, Value: value(10000)},
+			current:     []character.Resource{character.NewResource(character2.ResourceEnergyCharge, 2500)},
+			expectValue: 10000,
+			expectPrev:  2500,
+		},
+		{
+			name:        "Success case - combo counter adjusted",
+			payload:     UpdateCharacterResourcePayload{Resource: character2.ResourceComboCounter, Amount: 5},
+			current:     []character.Resource{character.NewResource(character2.ResourceEnergyCharge, 2500), character.NewResource(character2.ResourceComboCounter, 10)},
+			expectValue: 15,
+			expectPrev:  10,
+		},
+		{
+			name:        "Success case - resource never accumulated is adjusted from empty",
+			payload:     UpdateCharacterResourcePayload{Resource: character2.ResourceComboCounter, Amount: 5},
+			expectValue: 5,
+		},
+		{
+			name:        "Success case - adjustment beyond the current value empties the resource",
+			payload:     UpdateCharacterResourcePayload{Resource: character2.ResourceComboCounter, Amount: -20},
+			current:     []character.Resource{character.NewResource(character2.ResourceComboCounter, 10)},
+			expectValue: 0,
+			expectPrev:  10,
+		},
+		{
+			name:          "Error case - unknown resource",
+			payload:       UpdateCharacterResourcePayload{Resource: "RAGE", Amount: 5},
+			expectError:   true,
+			errorContains: "unknown resource [RAGE]",
+		},
+		{
+			name:          "Error case - neither value nor amount",
+			payload:       UpdateCharacterResourcePayload{Resource: character2.ResourceEnergyCharge},
+			expectError:   true,
+			errorContains: "exactly one of value or amount",
+		},
+		{
+			name:          "Error case - both value and amount",
+			payload:       UpdateCharacterResourcePayload{Resource: character2.ResourceEnergyCharge, Value: value(100), Amount: 5},
+			expectError:   true,
+			errorContains: "exactly one of value or amount",
+		},
+		{
+			name:          "Error case - negative value",
+			payload:       UpdateCharacterResourcePayload{Resource: character2.ResourceEnergyCharge, Value: value(-1)},
+			expectError:   true,
+			errorContains: "must not be negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			te, ctx := setupContext()
+
+			payload := tt.payload
+			payload.CharacterId = 12345
+			changed := false
+			charP := &mock.ProcessorMock{
+				GetResourcesFunc: func(characterId uint32) ([]character.Resource, error) {
+					assert.Equal(t, payload.CharacterId, characterId)
+					return tt.current, nil
+				},
+				ChangeResourceAndEmitFunc: func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, resource string, value int32) error {
+					changed = true
+					assert.Equal(t, payload.CharacterId, characterId)
+					assert.Equal(t, payload.Resource, resource)
+					assert.Equal(t, tt.expectValue, value)
+					return nil
+				},
+			}
+
+			step := Step[any]{StepId: "test-step", Status: Pending, Action: UpdateCharacterResource, Payload: payload}
+			saga := Saga{TransactionId: uuid.New(), SagaType: QuestReward, InitiatedBy: "quest", Steps: []Step[any]{step}}
+			GetCache().Put(te.Id(), saga)
+			defer GetCache().Remove(te.Id(), saga.TransactionId)
+
+			// Execute
+			err := NewHandler(logger, ctx).WithCharacterProcessor(charP).handleUpdateCharacterResource(saga, step)
+
+			// Verify
+			if tt.expectError {
+				assert.ErrorIs(t, err, ErrActionRejected)
+				assert.Contains(t, err.Error(), tt.errorContains)
+				assert.False(t, changed)
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, changed)
+
+			// The prior value is recorded for compensation
+			cached, ok := GetCache().GetById(te.Id(), saga.TransactionId)
+			assert.True(t, ok)
+			recorded := cached.Steps[0].Payload.(UpdateCharacterResourcePayload)
+			if assert.NotNil(t, recorded.Previous) {
+				assert.Equal(t, tt.expectPrev, *recorded.Previous)
+			}
+		})
+	}
+}
+
+// TestHandleChangeJob tests that the character's job is changed, recording the job it held before for compensation
+func TestHandleChangeJob(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+	logger.SetLevel(logrus.DebugLevel)
+
+	te, ctx := setupContext()
+
+	var changed []job.Id
+	charP := &mock.ProcessorMock{
+		GetByIdFunc: func(characterId uint32) (character.Model, error) {
+			return character.NewModelBuilder(characterId).SetJobId(job.Id(100)).Build(), nil
+		},
+		ChangeJobAndEmitFunc: func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, jobId job.Id) error {
+			assert.Equal(t, uint32(12345), characterId)
+			changed = append(changed, jobId)
+			return nil
+		},
+	}
+
+	step := Step[any]{StepId: "job", Status: Pending, Action: ChangeJob, Payload: ChangeJobPayload{CharacterId: 12345, JobId: job.Id(110)}}
+	saga := Saga{TransactionId: uuid.New(), SagaType: JobReset, InitiatedBy: "job-advancement", Steps: []Step[any]{step}}
+	GetCache().Put(te.Id(), saga)
+	defer GetCache().Remove(te.Id(), saga.TransactionId)
+
+	assert.NoError(t, NewHandler(logger, ctx).WithCharacterProcessor(charP).handleChangeJob(saga, step))
+	assert.Equal(t, []job.Id{110}, changed)
+
+	cached, ok := GetCache().GetById(te.Id(), saga.TransactionId)
+	require.True(t, ok)
+	recorded := cached.Steps[0].Payload.(ChangeJobPayload)
+	if assert.NotNil(t, recorded.Previous) {
+		assert.Equal(t, job.Id(100), *recorded.Previous)
+	}
+
+	// A character which cannot be retrieved is not changed
+	changed = nil
+	charP.GetByIdFunc = func(characterId uint32) (character.Model, error) {
+		return character.Model{}, errors.New("character service error")
+	}
+	assert.Error(t, NewHandler(logger, ctx).WithCharacterProcessor(charP).handleChangeJob(saga, step))
+	assert.Empty(t, changed)
+
+	// A prior job which cannot be recorded leaves the job unchanged, as compensation could not revert it
+	charP.GetByIdFunc = func(characterId uint32) (character.Model, error) {
+		return character.NewModelBuilder(characterId).SetJobId(job.Id(100)).Build(), nil
+	}
+	GetCache().Remove(te.Id(), saga.TransactionId)
+	assert.Error(t, NewHandler(logger, ctx).WithCharacterProcessor(charP).handleChangeJob(saga, step))
+	assert.Empty(t, changed)
+}
+
+func TestHandleAwardAssetIf(t *testing.T) {
+	conditions := []validation.ConditionInput{{Type: "jobId", Operator: "=", Value: 100}}
+	failed := validation.NewValidationResult(12345)
+	failed.AddConditionResult(validation.ConditionResult{Passed: false, Description: "Job ID does not match", Type: "jobId", Operator: "=", Value: 100, ActualValue: 200})
+
+	tests := []struct {
+		name          string
+		conditions    []validation.ConditionInput
+		mockResult    validation.ValidationResult
+		mockError     error
+		expectAward   bool
+		expectReject  bool
+		errorContains string
+	}{
+		{
+			name:        "Success case - conditions hold and the asset is awarded",
+			conditions:  conditions,
+			mockResult:  validation.NewValidationResult(12345),
+			expectAward: true,
+		},
+		{
+			name:          "Failure case - conditions not met",
+			conditions:    conditions,
+			mockResult:    failed,
+			expectReject:  true,
+			errorContains: "character state validation failed",
+		},
+		{
+			name:          "Error case - no conditions",
+			expectReject:  true,
+			errorContains: "at least one condition",
+		},
+		{
+			name:          "Error case - validation service error",
+			conditions:    conditions,
+			mockError:     errors.New("validation service unavailable"),
+			errorContains: "validation service unavailable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			_, ctx := setupContext()
+
+			payload := AwardAssetIfPayload{CharacterId: 12345, Conditions: tt.conditions, Item: ItemPayload{TemplateId: 2000000, Quantity: 5}}
+			validP := &mock3.ProcessorMock{
+				ValidateCharacterStateFunc: func(characterId uint32, conditions []validation.ConditionInput) (validation.ValidationResult, error) {
+					assert.Equal(t, payload.CharacterId, characterId)
+					assert.Equal(t, payload.Conditions, conditions)
+					return tt.mockResult, tt.mockError
+				},
+			}
+			awarded := false
+			compP := &mock2.ProcessorMock{
+				RequestCreateItemFunc: func(transactionId uuid.UUID, characterId uint32, templateId uint32, quantity uint32) error {
+					awarded = true
+					assert.Equal(t, payload.CharacterId, characterId)
+					assert.Equal(t, payload.Item.TemplateId, templateId)
+					assert.Equal(t, payload.Item.Quantity, quantity)
+					return nil
+				},
+			}
+
+			step := Step[any]{StepId: "test-step", Status: Pending, Action: AwardAssetIf, Payload: payload}
+			saga := Saga{TransactionId: uuid.New(), SagaType: QuestReward, InitiatedBy: "quest", Steps: []Step[any]{step}}
+
+			// Execute
+			err := NewHandler(logger, ctx).WithValidationProcessor(validP).WithCompartmentProcessor(compP).handleAwardAssetIf(saga, step)
+
+			// Verify
+			assert.Equal(t, tt.expectAward, awarded)
+			if tt.errorContains == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errorContains)
+			assert.Equal(t, tt.expectReject, errors.Is(err, ErrActionRejected))
+		})
+	}
+}
+
+// TestHandleStripEquipment tests that the items a job may not wear are unequipped into free slots through added steps,
+// and that a failure of one re-equips those already unequipped
+func TestHandleStripEquipment(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+	logger.SetLevel(logrus.DebugLevel)
+
+	te, ctx := setupContext()
+
+	compP := &mock2.ProcessorMock{
+		GetByTypeFunc: func(characterId uint32, inventoryType inventory.Type) (compartment.Model, error) {
+			assert.Equal(t, inventory.TypeValueEquip, inventoryType)
+			return equipmentCompartment(4, map[int16]uint32{-11: 1302000, -5: 1040002, -1: 1002141, 1: 1060002}), nil
+		},
+	}
+	reqJobs := map[uint32]uint16{1302000: equipment.ReqJobWarrior, 1002141: equipment.ReqJobMagician | equipment.ReqJobThief}
+	equipP := &mock17.ProcessorMock{
+		GetByIdFunc: func(templateId uint32) (equipment.Model, error) {
+			return equipment.NewBuilder(templateId).SetReqJob(reqJobs[templateId]).Build(), nil
+		},
+	}
+	h := NewHandler(logger, ctx).WithCompartmentProcessor(compP).WithEquipmentProcessor(equipP)
+
+	transactionId := uuid.New()
+	step := Step[any]{StepId: "strip", Status: Pending, Action: StripEquipment, Payload: StripEquipmentPayload{CharacterId: 12345, JobId: job.Id(0)}}
+	saga := Saga{TransactionId: transactionId, SagaType: JobReset, InitiatedBy: "job-reset", Steps: []Step[any]{
+		{StepId: "change", Status: Completed, Action: ChangeJob, Payload: ChangeJobPayload{CharacterId: 12345, JobId: job.Id(0)}},
+		step,
+	}}
+	GetCache().Put(te.Id(), saga)
+	defer GetCache().Remove(te.Id(), transactionId)
+
+	// A beginner may wear neither the warrior's weapon nor the magician's hat, but may keep the overall
+	assert.NoError(t, h.handleStripEquipment(saga, step))
+	result, ok := GetCache().GetById(te.Id(), transactionId)
+	require.True(t, ok)
+	require.Len(t, result.Steps, 4)
+	assert.Equal(t, []EquippedItem{{TemplateId: 1302000, Slot: -11}, {TemplateId: 1002141, Slot: -1}}, result.Steps[1].Payload.(StripEquipmentPayload).Stripped)
+	assert.Equal(t, "strip_unequip_1", result.Steps[2].StepId)
+	assert.Equal(t, UnequipAssetPayload{CharacterId: 12345, InventoryType: uint32(inventory.TypeValueEquip), Source: -11, Destination: 2}, result.Steps[2].Payload)
+	assert.Equal(t, "strip_unequip_2", result.Steps[3].StepId)
+	assert.Equal(t, UnequipAssetPayload{CharacterId: 12345, InventoryType: uint32(inventory.TypeValueEquip), Source: -1, Destination: 3}, result.Steps[3].Payload)
+
+	// The second item fails to be unequipped, so the first is equipped again
+	var moves [][2]int16
+	compP.RequestEquipAssetFunc = func(transactionId uuid.UUID, characterId uint32, inventoryType byte, source int16, destination int16) error {
+		moves = append(moves, [2]int16{source, destination})
+		return nil
+	}
+	result.Steps[1].Status = Completed
+	result.Steps[2].Status = Completed
+	result.Steps[3].Status = Failed
+	assert.NoError(t, NewCompensator(logger, ctx).WithCompartmentProcessor(compP).CompensateFailedStep(result))
+	assert.Equal(t, [][2]int16{{2, -11}}, moves)
+
+	// A thief keeps the hat, and may no longer wear the weapon
+	stripped, _, err := planStripEquipment(equipmentCompartment(4, map[int16]uint32{-11: 1302000, -1: 1002141}), 12345, job.Id(400), equipP)
+	assert.NoError(t, err)
+	assert.Equal(t, []EquippedItem{{TemplateId: 1302000, Slot: -11}}, stripped)
+
+	// Items are not stripped without space to unequip them into
+	_, _, err = planStripEquipment(equipmentCompartment(1, map[int16]uint32{-11: 1302000, 1: 1060002}), 12345, job.Id(0), equipP)
+	assert.ErrorIs(t, err, ErrActionRejected)
+}
+
+// TestHandleSnapshotAndRestoreCharacter tests that a snapshot records the character's progression and stats on the step,
+// and that a restore reinstates the snapshot taken
+func TestHandleSnapshotAndRestoreCharacter(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+	logger.SetLevel(logrus.DebugLevel)
+
+	te, ctx := setupContext()
+
+	var restored []character.Model
+	charP := &mock.ProcessorMock{
+		GetByIdFunc: func(characterId uint32) (character.Model, error) {
+			return character.NewModelBuilder(characterId).SetLevel(30).SetExperience(1200).SetMeso(50000).SetStrength(35).SetMaxHp(900).SetAp(5).Build(), nil
+		},
+		RestoreStatsAndEmitFunc: func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, m character.Model) error {
+			assert.Equal(t, uint32(12345), characterId)
+			restored = append(restored, m)
+			return nil
+		},
+	}
+	h := NewHandler(logger, ctx).WithCharacterProcessor(charP)
+
+	transactionId := uuid.New()
+	snapshot := Step[any]{StepId: "snapshot", Status: Pending, Action: SnapshotCharacter, Payload: SnapshotCharacterPayload{CharacterId: 12345, WorldId: 0, ChannelId: 1}}
+	restore := Step[any]{StepId: "restore", Status: Pending, Action: RestoreCharacter, Payload: RestoreCharacterPayload{CharacterId: 12345, WorldId: 0, ChannelId: 1}}
+	saga := Saga{TransactionId: transactionId, SagaType: JobReset, InitiatedBy: "job-reset", Steps: []Step[any]{snapshot, restore}}
+	GetCache().Put(te.Id(), saga)
+	defer GetCache().Remove(te.Id(), transactionId)
+
+	// A restore without a snapshot taken is rejected without a command
+	assert.ErrorIs(t, h.handleRestoreCharacter(saga, restore), ErrActionRejected)
+
+	assert.NoError(t, h.handleSnapshotCharacter(saga, snapshot))
+	result, ok := GetCache().GetById(te.Id(), transactionId)
+	require.True(t, ok)
+	captured := result.Steps[0].Payload.(SnapshotCharacterPayload).Snapshot
+	require.NotNil(t, captured)
+	assert.Equal(t, CharacterSnapshot{Level: 30, Experience: 1200, Meso: 50000, Strength: 35, MaxHp: 900, Ap: 5}, *captured)
+
+	result.Steps[0].Status = Completed
+	assert.NoError(t, h.handleRestoreCharacter(result, restore))
+	require.Len(t, restored, 1)
+	assert.Equal(t, byte(30), restored[0].Level())
+	assert.Equal(t, uint32(50000), restored[0].Meso())
+	assert.Equal(t, uint16(900), restored[0].MaxHp())
+
+	// A restore of another character's snapshot, or of a step which is not a snapshot, is rejected
+	restore.Payload = RestoreCharacterPayload{CharacterId: 54321}
+	assert.ErrorIs(t, h.handleRestoreCharacter(result, restore), ErrActionRejected)
+	restore.Payload = RestoreCharacterPayload{CharacterId: 12345, SnapshotStepId: "restore"}
+	assert.ErrorIs(t, h.handleRestoreCharacter(result, restore), ErrActionRejected)
+}
+
+// TestHandleNotify tests the handleNotify function
+func TestHandleNotify(t *testing.T) {
+	tests := []struct {
+		name         string
+		payload      NotifyPayload
+		mockError    error
+		expectSent   bool
+		expectType   string
+		expectError  bool
+		expectReject bool
+	}{
+		{
+			name:       "Success case - notice shown by default",
+			payload:    NotifyPayload{CharacterId: 12345, WorldId: 0, ChannelId: 1, Message: "Talk to Heena to begin."},
+			expectSent: true,
+			expectType: "NOTICE",
+		},
+		{
+			name:       "Success case - message type shown",
+			payload:    NotifyPayload{CharacterId: 12345, WorldId: 0, ChannelId: 1, MessageType: "PINK_TEXT", Message: "Talk to Heena to begin."},
+			expectSent: true,
+			expectType: "PINK_TEXT",
+		},
+		{
+			name:         "Error case - missing message",
+			payload:      NotifyPayload{CharacterId: 12345, WorldId: 0, ChannelId: 1},
+			expectError:  true,
+			expectReject: true,
+		},
+		{
+			name:        "Error case - channel service error",
+			payload:     NotifyPayload{CharacterId: 12345, WorldId: 0, ChannelId: 1, Message: "Talk to Heena to begin."},
+			mockError:   errors.New("channel service error"),
+			expectSent:  true,
+			expectType:  "NOTICE",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			_, ctx := setupContext()
+
+			sent := false
+			msgP := &mock18.ProcessorMock{
+				SendMessageAndEmitFunc: func(transactionId uuid.UUID, worldId world.Id, channelId channel.Id, characterId uint32, messageType string, msg string) error {
+					sent = true
+					assert.Equal(t, uint32(12345), characterId)
+					assert.Equal(t, channel.Id(1), channelId)
+					assert.Equal(t, tt.expectType, messageType)
+					assert.Equal(t, tt.payload.Message, msg)
+					return tt.mockError
+				},
+			}
+			h := NewHandler(logger, ctx).WithSystemMessageProcessor(msgP)
+
+			step := Step[any]{StepId: "notify", Status: Pending, Action: Notify, Payload: tt.payload}
+			saga := Saga{TransactionId: uuid.New(), SagaType: TutorialCompletion, InitiatedBy: "tutorial", Steps: []Step[any]{step}}
+
+			// Execute
+			handler, ok := h.GetHandler(Notify)
+			assert.True(t, ok)
+			err := handler(saga, step)
+
+			// Verify
+			assert.Equal(t, tt.expectSent, sent)
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectReject, errors.Is(err, ErrActionRejected))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestHandleAwaitEvent tests the handleAwaitEvent function, which rejects events which cannot be awaited
+func TestHandleAwaitEvent(t *testing.T) {
+	tests := []struct {
+		name         string
+		payload      AwaitEventPayload
+		expectReject bool
+	}{
+		{name: "Success case - map entry awaited", payload: AwaitEventPayload{CharacterId: 12345, Event: AwaitedEventMapEntry, MapId: 10000}},
+		{name: "Error case - unknown event", payload: AwaitEventPayload{CharacterId: 12345, Event: "level_up", MapId: 10000}, expectReject: true},
+		{name: "Error case - missing map", payload: AwaitEventPayload{CharacterId: 12345, Event: AwaitedEventMapEntry}, expectReject: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, _ := test.NewNullLogger()
+			_, ctx := setupContext()
+			h := NewHandler(logger, ctx)
+
+			step := Step[any]{StepId: "gate", Status: Pending, Action: AwaitEvent, Payload: tt.payload}
+			saga := Saga{TransactionId: uuid.New(), SagaType: TutorialCompletion, InitiatedBy: "tutorial", Steps: []Step[any]{step}}
+
+			handler, ok := h.GetHandler(AwaitEvent)
+			assert.True(t, ok)
+			err := handler(saga, step)
+			if tt.expectReject {
+				assert.ErrorIs(t, err, ErrActionRejected)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestRecordStepPayload tests that recording a step's payload updates the cached saga in place, retaining changes made
+// to it since the handler was dispatched, and does not cache a saga which is not
+func TestRecordStepPayload(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+	te, ctx := setupContext()
+	h := NewHandler(logger, ctx).(*HandlerImpl)
+
+	step := Step[any]{StepId: "prize", Status: Pending, Action: ResolvePrizeTable, Payload: ResolvePrizeTablePayload{CharacterId: 12345}}
+	saga := Saga{TransactionId: uuid.New(), SagaType: QuestReward, InitiatedBy: "test", Steps: []Step[any]{step}}
+	GetCache().Put(te.Id(), saga)
+	defer GetCache().Remove(te.Id(), saga.TransactionId)
+
+	// The cached saga changes after the handler was dispatched with its copy
+	changed := saga
+	changed.Variables = changed.Variables.With("stage", "resolved")
+	GetCache().Put(te.Id(), changed)
+
+	prize := PrizeEntry{Weight: 1, Mesos: 100}
+	h.recordStepOutcome(saga, step, ResolvePrizeTablePayload{CharacterId: 12345, Resolved: &prize}, Variables{"prize": float64(100)})
+
+	cached, ok := GetCache().GetById(te.Id(), saga.TransactionId)
+	require.True(t, ok)
+	assert.Equal(t, "resolved", cached.Variables["stage"])
+	assert.Equal(t, float64(100), cached.Variables["prize"])
+	assert.Equal(t, &prize, cached.Steps[0].Payload.(ResolvePrizeTablePayload).Resolved)
+
+	uncached := Saga{TransactionId: uuid.New(), SagaType: QuestReward, InitiatedBy: "test", Steps: []Step[any]{step}}
+	h.recordStepPayload(uncached, step, step.Payload)
+	_, ok = GetCache().GetById(te.Id(), uncached.TransactionId)
+	assert.False(t, ok)
+}
@@ -0,0 +1,269 @@
+package saga
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrHttpRequestNotAllowed is returned when an http_request step targets a host which is not allow-listed
+var ErrHttpRequestNotAllowed = errors.New("http request host not allowed")
+
+const (
+	// DefaultHttpRequestTimeout is the timeout of each attempt of an http_request step when none is configured
+	DefaultHttpRequestTimeout = 10 * time.Second
+
+	// DefaultHttpRequestBackoff is the delay between attempts of an http_request step when none is declared
+	DefaultHttpRequestBackoff = 500 * time.Millisecond
+
+	// DefaultHttpRequestMaxAttempts is the most attempts an http_request step may declare when no maximum is configured
+	DefaultHttpRequestMaxAttempts = 5
+
+	// DefaultHttpRequestMaxTimeout is the longest timeout or backoff an http_request step may declare when no maximum is
+	// configured
+	DefaultHttpRequestMaxTimeout = 30 * time.Second
+
+	// maxHttpResponseBytes is the most of a response body read and recorded on the step
+	maxHttpResponseBytes = 1 << 20
+
+	// maxHttpRedirects is the most redirects followed by each attempt of an http_request step
+	maxHttpRedirects = 10
+)
+
+// HttpRequestConfig configures the hosts http_request steps may call, and how long each attempt may take
+type HttpRequestConfig struct {
+	AllowedHosts []string      // AllowedHosts are the hosts (host or host:port) which may be called. When empty, none may.
+	Timeout      time.Duration // Timeout of each attempt, unless the step declares its own
+	MaxAttempts  uint32        // MaxAttempts is the most attempts a step may declare. Steps declaring more are limited to it.
+	MaxTimeout   time.Duration // MaxTimeout is the longest timeout, or backoff between attempts, a step may declare. Steps declaring longer are limited to it.
+}
+
+// HttpRequestConfigFromEnv loads the http_request configuration from the environment
+func HttpRequestConfigFromEnv() (HttpRequestConfig, error) {
+	c := HttpRequestConfig{Timeout: DefaultHttpRequestTimeout, MaxAttempts: DefaultHttpRequestMaxAttempts, MaxTimeout: DefaultHttpRequestMaxTimeout}
+
+	if v := os.Getenv("SAGA_HTTP_ALLOWED_HOSTS"); v != "" {
+		for _, h := range strings.Split(v, ",") {
+			h = strings.ToLower(strings.TrimSpace(h))
+			if h == "" {
+				return HttpRequestConfig{}, fmt.Errorf("invalid SAGA_HTTP_ALLOWED_HOSTS '%s'", v)
+			}
+			c.AllowedHosts = append(c.AllowedHosts, h)
+		}
+	}
+
+	if v, ok := os.LookupEnv("SAGA_HTTP_TIMEOUT"); ok && v != "" {
+		t, err := time.ParseDuration(v)
+		if err != nil || t <= 0 {
+			return HttpRequestConfig{}, fmt.Errorf("invalid SAGA_HTTP_TIMEOUT '%s'", v)
+		}
+		c.Timeout = t
+	}
+
+	if v, ok := os.LookupEnv("SAGA_HTTP_MAX_ATTEMPTS"); ok && v != "" {
+		n, err := strconv.ParseUint(v, 10, 32)
+		if err != nil || n == 0 {
+			return HttpRequestConfig{}, fmt.Errorf("invalid SAGA_HTTP_MAX_ATTEMPTS '%s'", v)
+		}
+		c.MaxAttempts = uint32(n)
+	}
+
+	if v, ok := os.LookupEnv("SAGA_HTTP_MAX_TIMEOUT"); ok && v != "" {
+		t, err := time.ParseDuration(v)
+		if err != nil || t <= 0 {
+			return HttpRequestConfig{}, fmt.Errorf("invalid SAGA_HTTP_MAX_TIMEOUT '%s'", v)
+		}
+		c.MaxTimeout = t
+	}
+
+	return c, nil
+}
+
+// Singleton http_request configuration, which allows no hosts until initialized
+var httpRequestConfig = HttpRequestConfig{Timeout: DefaultHttpRequestTimeout, MaxAttempts: DefaultHttpRequestMaxAttempts, MaxTimeout: DefaultHttpRequestMaxTimeout}
+
+// InitHttpRequestConfig replaces the singleton http_request configuration
+func InitHttpRequestConfig(config HttpRequestConfig) {
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultHttpRequestTimeout
+	}
+	if config.MaxAttempts == 0 {
+		config.MaxAttempts = DefaultHttpRequestMaxAttempts
+	}
+	if config.MaxTimeout <= 0 {
+		config.MaxTimeout = DefaultHttpRequestMaxTimeout
+	}
+	httpRequestConfig = config
+}
+
+// GetHttpRequestConfig returns the singleton http_request configuration
+func GetHttpRequestConfig() HttpRequestConfig {
+	return httpRequestConfig
+}
+
+// Allowed returns whether the URL may be called, being http or https to an allow-listed host. A host listed without a
+// port is allowed on any port.
+func (c HttpRequestConfig) Allowed(u *url.URL) bool {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return false
+	}
+	host := strings.ToLower(u.Host)
+	hostname := strings.ToLower(u.Hostname())
+	for _, h := range c.AllowedHosts {
+		if h == host || h == hostname {
+			return true
+		}
+	}
+	return false
+}
+
+// target returns the URL an http_request step calls, should it be allowed
+func (c HttpRequestConfig) target(p HttpRequestPayload) (*url.URL, error) {
+	u, err := url.Parse(p.Url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid url '%s': %w", p.Url, err)
+	}
+	if !c.Allowed(u) {
+		return nil, fmt.Errorf("%w: '%s'", ErrHttpRequestNotAllowed, u.Host)
+	}
+	return u, nil
+}
+
+// client returns the client performing http_request steps' requests. Redirects are followed only to allow-listed
+// hosts, so a called host cannot redirect the request to one which is not.
+func (c HttpRequestConfig) client() *http.Client {
+	return &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if !c.Allowed(req.URL) {
+				return fmt.Errorf("%w: redirect to '%s'", ErrHttpRequestNotAllowed, req.URL.Host)
+			}
+			if len(via) >= maxHttpRedirects {
+				return fmt.Errorf("stopped after [%d] redirects", maxHttpRedirects)
+			}
+			return nil
+		},
+	}
+}
+
+// executeHttpRequest performs the request of an http_request step, retrying transport errors, 429 and 5xx responses
+// up to the step's maximum attempts. The last response received is returned alongside any error.
+func executeHttpRequest(ctx context.Context, c HttpRequestConfig, p HttpRequestPayload) (*HttpResponse, error) {
+	method := strings.ToUpper(p.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+	u, err := c.target(p)
+	if err != nil {
+		return nil, err
+	}
+
+	var body []byte
+	if p.Body != nil {
+		if body, err = json.Marshal(p.Body); err != nil {
+			return nil, fmt.Errorf("invalid body: %w", err)
+		}
+	}
+
+	// Steps may not declare attempts, timeouts or backoffs beyond the configured maximums, so a callout cannot hold its
+	// saga indefinitely
+	timeout := c.Timeout
+	if p.TimeoutMs > 0 {
+		timeout = time.Duration(p.TimeoutMs) * time.Millisecond
+	}
+	if c.MaxTimeout > 0 {
+		timeout = min(timeout, c.MaxTimeout)
+	}
+	backoff := DefaultHttpRequestBackoff
+	if p.BackoffMs > 0 {
+		backoff = time.Duration(p.BackoffMs) * time.Millisecond
+	}
+	if c.MaxTimeout > 0 {
+		backoff = min(backoff, c.MaxTimeout)
+	}
+	attempts := max(p.MaxAttempts, 1)
+	if c.MaxAttempts > 0 {
+		attempts = min(attempts, c.MaxAttempts)
+	}
+
+	client := c.client()
+	var res *HttpResponse
+	for attempt := uint32(1); ; attempt++ {
+		var retryable bool
+		res, retryable, err = attemptHttpRequest(ctx, client, timeout, method, u.String(), p.Headers, body)
+		if res != nil {
+			res.Attempts = attempt
+		}
+		if err == nil && !p.expected(res.Status) {
+			err = fmt.Errorf("unexpected status [%d]", res.Status)
+		}
+		if err == nil || !retryable || attempt >= attempts {
+			return res, err
+		}
+		select {
+		case <-ctx.Done():
+			return res, ctx.Err()
+		case <-time.After(backoff):
+		}
+	}
+}
+
+// attemptHttpRequest performs a single attempt of a request, returning whether a failed attempt may be retried
+func attemptHttpRequest(ctx context.Context, client *http.Client, timeout time.Duration, method string, url string, headers map[string]string, body []byte) (*HttpResponse, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, false, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, !errors.Is(err, ErrHttpRequestNotAllowed), err
+	}
+	defer resp.Body.Close()
+
+	bs, err := io.ReadAll(io.LimitReader(resp.Body, maxHttpResponseBytes))
+	if err != nil {
+		return nil, true, err
+	}
+	res := &HttpResponse{Status: resp.StatusCode}
+	if len(bs) > 0 {
+		var v any
+		if json.Unmarshal(bs, &v) == nil {
+			res.Body = v
+		} else {
+			res.Body = string(bs)
+		}
+	}
+	retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+	return res, retryable, nil
+}
+
+// expected returns whether a response status is one the step expects, by default any 2xx status
+func (p HttpRequestPayload) expected(status int) bool {
+	if len(p.ExpectedStatus) == 0 {
+		return status >= 200 && status < 300
+	}
+	for _, s := range p.ExpectedStatus {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
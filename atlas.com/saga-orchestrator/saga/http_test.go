@@ -0,0 +1,264 @@
+package saga
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestHttpRequestConfigFromEnv tests loading the http_request configuration from the environment
+func TestHttpRequestConfigFromEnv(t *testing.T) {
+	t.Setenv("SAGA_HTTP_ALLOWED_HOSTS", "Billing.internal, webhooks.internal:8443")
+	t.Setenv("SAGA_HTTP_TIMEOUT", "3s")
+	t.Setenv("SAGA_HTTP_MAX_ATTEMPTS", "4")
+	t.Setenv("SAGA_HTTP_MAX_TIMEOUT", "15s")
+
+	c, err := HttpRequestConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"billing.internal", "webhooks.internal:8443"}, c.AllowedHosts)
+	assert.Equal(t, 3*time.Second, c.Timeout)
+	assert.Equal(t, uint32(4), c.MaxAttempts)
+	assert.Equal(t, 15*time.Second, c.MaxTimeout)
+
+	allowed := func(raw string) bool {
+		u, err := url.Parse(raw)
+		require.NoError(t, err)
+		return c.Allowed(u)
+	}
+	assert.True(t, allowed("https://billing.internal/orders"))
+	assert.True(t, allowed("http://billing.internal:8080/orders"))
+	assert.True(t, allowed("https://webhooks.internal:8443/hook"))
+	assert.False(t, allowed("https://webhooks.internal/hook"))
+	assert.False(t, allowed("ftp://billing.internal/orders"))
+	assert.False(t, allowed("https://billing.internal.attacker.com/orders"))
+
+	t.Setenv("SAGA_HTTP_MAX_ATTEMPTS", "0")
+	_, err = HttpRequestConfigFromEnv()
+	assert.Error(t, err)
+
+	t.Setenv("SAGA_HTTP_TIMEOUT", "soon")
+	_, err = HttpRequestConfigFromEnv()
+	assert.Error(t, err)
+}
+
+// TestHandleHttpRequest tests calling allow-listed endpoints, retrying failures and capturing the response
+func TestHandleHttpRequest(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Redirects within the allowed host are followed by the same attempt
+		if r.URL.Path == "/moved" {
+			http.Redirect(w, r, "/orders", http.StatusFound)
+			return
+		}
+		n := calls.Add(1)
+		switch r.URL.Path {
+		case "/flaky":
+			if n == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+			return
+		case "/down":
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		case "/escape":
+			http.Redirect(w, r, "http://example.com/orders", http.StatusFound)
+			return
+		}
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"id": "order-1", "method": r.Method, "characterId": body["characterId"]}})
+	}))
+	defer server.Close()
+
+	su, _ := url.Parse(server.URL)
+	defer InitHttpRequestConfig(HttpRequestConfig{})
+	InitHttpRequestConfig(HttpRequestConfig{AllowedHosts: []string{su.Host}, Timeout: time.Second, MaxAttempts: 3})
+
+	tests := []struct {
+		name          string
+		payload       HttpRequestPayload
+		expectError   bool
+		errorContains string
+		calls         int32
+	}{
+		{
+			name:    "Success case",
+			payload: HttpRequestPayload{Method: "post", Url: server.URL + "/orders", Body: map[string]any{"characterId": 12345}, Capture: map[string]string{"orderId": "$.data.id"}},
+			calls:   1,
+		},
+		{
+			name:    "Success case - retried",
+			payload: HttpRequestPayload{Method: "POST", Url: server.URL + "/flaky", MaxAttempts: 2, BackoffMs: 1, Capture: map[string]string{"orderId": "$.data.id"}},
+			calls:   2,
+		},
+		{
+			name:          "Error case - unexpected status",
+			payload:       HttpRequestPayload{Url: server.URL + "/missing", MaxAttempts: 3},
+			expectError:   true,
+			errorContains: "unexpected status [404]",
+			calls:         1,
+		},
+		{
+			name:          "Error case - attempts limited to the configured maximum",
+			payload:       HttpRequestPayload{Url: server.URL + "/down", MaxAttempts: 100, BackoffMs: 1},
+			expectError:   true,
+			errorContains: "unexpected status [503]",
+			calls:         3,
+		},
+		{
+			name:    "Success case - redirected to allowed host",
+			payload: HttpRequestPayload{Url: server.URL + "/moved", Capture: map[string]string{"orderId": "$.data.id"}},
+			calls:   1,
+		},
+		{
+			name:          "Error case - redirected to host not allowed",
+			payload:       HttpRequestPayload{Url: server.URL + "/escape", MaxAttempts: 3},
+			expectError:   true,
+			errorContains: "redirect to 'example.com'",
+			calls:         1,
+		},
+		{
+			name:          "Error case - host not allowed",
+			payload:       HttpRequestPayload{Url: "http://example.com/orders"},
+			expectError:   true,
+			errorContains: "not allowed",
+		},
+		{
+			name:          "Error case - capture not in response",
+			payload:       HttpRequestPayload{Url: server.URL + "/orders", Capture: map[string]string{"orderId": "$.id"}},
+			expectError:   true,
+			errorContains: "unable to capture 'orderId'",
+			calls:         1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			te, ctx := setupContext()
+			calls.Store(0)
+
+			step := Step[any]{StepId: "test-step", Status: Pending, Action: HttpRequest, Payload: tt.payload}
+			saga := Saga{TransactionId: uuid.New(), SagaType: QuestReward, InitiatedBy: "npc-9010000", Steps: []Step[any]{step}}
+			GetCache().Put(te.Id(), saga)
+			defer GetCache().Remove(te.Id(), saga.TransactionId)
+
+			// Execute
+			err := NewHandler(logger, ctx).(*HandlerImpl).performHttpRequest(saga, step, tt.payload)
+
+			// Verify
+			assert.Equal(t, tt.calls, calls.Load())
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+				return
+			}
+			require.NoError(t, err)
+			s, ok := GetCache().GetById(te.Id(), saga.TransactionId)
+			require.True(t, ok)
+			assert.Equal(t, "order-1", s.Variables["orderId"])
+			res := s.Steps[0].Payload.(HttpRequestPayload).Response
+			require.NotNil(t, res)
+			assert.Equal(t, http.StatusCreated, res.Status)
+			assert.Equal(t, uint32(tt.calls), res.Attempts)
+		})
+	}
+}
+
+// TestHttpRequestStep tests that an http_request step is dispatched without awaiting its request, and completes or fails
+// once the request has been made
+func TestHttpRequestStep(t *testing.T) {
+	release := make(chan struct{})
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		<-release
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": "order-1"})
+	}))
+	defer server.Close()
+
+	su, _ := url.Parse(server.URL)
+	defer InitHttpRequestConfig(HttpRequestConfig{})
+	InitHttpRequestConfig(HttpRequestConfig{AllowedHosts: []string{su.Host}, Timeout: time.Second})
+
+	tests := []struct {
+		name          string
+		payload       HttpRequestPayload
+		expectStatus  SagaStatus
+		expectOrderId any
+	}{
+		{name: "Success case - completed once responded", payload: HttpRequestPayload{Method: "POST", Url: server.URL + "/orders", Capture: map[string]string{"orderId": "$.id"}}, expectStatus: SagaStatusCompleted, expectOrderId: "order-1"},
+		{name: "Error case - failed once responded", payload: HttpRequestPayload{Url: server.URL + "/missing"}, expectStatus: SagaStatusCompensated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			te, ctx := setupContext()
+			processor, _ := setupTestProcessor(ctx, nil, nil)
+
+			s := NewBuilder().SetSagaType(QuestReward).AddStep("callout", Pending, HttpRequest, tt.payload).Build()
+			done, unsubscribe := GetNotifier().Subscribe(te.Id(), s.TransactionId)
+			defer unsubscribe()
+			require.NoError(t, processor.Put(s))
+			defer GetCache().Remove(te.Id(), s.TransactionId)
+
+			// The step awaits the response rather than dispatch blocking on it
+			cs, err := processor.GetById(s.TransactionId)
+			require.NoError(t, err)
+			assert.Equal(t, Pending, cs.Steps[0].Status)
+			release <- struct{}{}
+
+			select {
+			case s = <-done:
+			case <-time.After(time.Second):
+				t.Fatal("http_request step did not finish")
+			}
+			assert.Equal(t, tt.expectStatus, s.OverallStatus())
+			assert.Equal(t, tt.expectOrderId, s.Variables["orderId"])
+		})
+	}
+
+	t.Run("Error case - host not allowed is rejected on dispatch", func(t *testing.T) {
+		logger, _ := test.NewNullLogger()
+		_, ctx := setupContext()
+		step := Step[any]{StepId: "callout", Status: Pending, Action: HttpRequest, Payload: HttpRequestPayload{Url: "http://example.com/orders"}}
+		s := Saga{TransactionId: uuid.New(), SagaType: QuestReward, InitiatedBy: "test", Steps: []Step[any]{step}}
+		err := NewHandler(logger, ctx).handleHttpRequest(s, step)
+		assert.ErrorIs(t, err, ErrActionRejected)
+		assert.Contains(t, err.Error(), "not allowed")
+	})
+
+	t.Run("Success case - shadow replica does not call out", func(t *testing.T) {
+		logger, _ := test.NewNullLogger()
+		_, ctx := setupContext()
+		InitShadowConfig(ShadowConfig{Enabled: true})
+		defer InitShadowConfig(ShadowConfig{})
+		before := calls.Load()
+		step := Step[any]{StepId: "callout", Status: Pending, Action: HttpRequest, Payload: HttpRequestPayload{Url: server.URL + "/orders"}}
+		s := Saga{TransactionId: uuid.New(), SagaType: QuestReward, InitiatedBy: "test", Steps: []Step[any]{step}}
+		require.NoError(t, NewHandler(logger, ctx).handleHttpRequest(s, step))
+		time.Sleep(50 * time.Millisecond)
+		assert.Equal(t, before, calls.Load())
+	})
+}
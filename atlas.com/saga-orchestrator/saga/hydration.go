@@ -0,0 +1,163 @@
+package saga
+
+import (
+	"atlas-saga-orchestrator/cluster"
+	"atlas-saga-orchestrator/metrics"
+	"sync"
+	"time"
+
+	tenant "github.com/Chronicle20/atlas-tenant"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// Results of hydrating a saga, by which hydrations are counted
+const (
+	HydrationHydrated = "hydrated" // The saga was archived with work remaining, and was hydrated into the cache
+	HydrationFinished = "finished" // The saga was archived, but had finished, so was left archived
+	HydrationMiss     = "miss"     // The saga was not archived
+	HydrationError    = "error"    // The archive could not be read
+	HydrationWarmed   = "warmed"   // The saga was hydrated into the cache as it was warmed
+)
+
+// Archive is the persistence sagas are kept in beyond the cache. Sagas it holds which are not in the cache, such as
+// those evicted or held by an instance which has since restarted, are hydrated into the cache lazily, as events
+// referencing them are received, or as the cache is warmed. Archives persist sagas with Serialize, and load them with
+// Deserialize, so sagas persisted by earlier versions of the orchestrator are migrated as they are loaded.
+type Archive interface {
+	// GetById returns an archived saga by its transaction ID for a tenant
+	GetById(tenantId uuid.UUID, transactionId uuid.UUID) (Saga, bool, error)
+
+	// GetActive returns the archived sagas with work remaining, by tenant
+	GetActive() (map[tenant.Model][]Saga, error)
+
+	// GetDue returns the archived sagas whose current step is due by the given time, whether timed out or parked for a
+	// redelivery, by tenant
+	GetDue(now time.Time) (map[tenant.Model][]Saga, error)
+}
+
+var archive Archive
+var archiveMutex sync.RWMutex
+
+// InitArchive sets the archive sagas are hydrated from. Without an archive, sagas not in the cache do not exist.
+func InitArchive(a Archive) {
+	archiveMutex.Lock()
+	defer archiveMutex.Unlock()
+	archive = a
+}
+
+// GetArchive returns the archive sagas are hydrated from, if any
+func GetArchive() (Archive, bool) {
+	archiveMutex.RLock()
+	defer archiveMutex.RUnlock()
+	return archive, archive != nil
+}
+
+var hydrations *metrics.Counter
+var hydrationsOnce sync.Once
+
+// getHydrations returns the counter of hydrations, by tenant, initiator and result. Sagas which could not be read have
+// no initiator.
+func getHydrations() *metrics.Counter {
+	hydrationsOnce.Do(func() {
+		hydrations = metrics.GetRegistry().RegisterCounter("saga_hydrations_total", "Number of sagas looked up in the archive, by initiator and result, having not been in the cache.")
+	})
+	return hydrations
+}
+
+func countHydration(tenantId uuid.UUID, initiatedBy string, result string) {
+	getHydrations().Inc(map[string]string{"tenant_id": tenantId.String(), "initiated_by": initiatedBy, "result": result})
+}
+
+// active returns whether the saga has work remaining, whether steps to execute or a failure to compensate
+func (s Saga) active() bool {
+	if s.Failing() {
+		return true
+	}
+	_, ok := s.GetCurrentStep()
+	return ok
+}
+
+// hydrate puts an archived saga, not in the cache, into it, so an event referencing it is processed rather than
+// dropped. Sagas which have finished are left archived.
+func (p *ProcessorImpl) hydrate(transactionId uuid.UUID) (Saga, bool) {
+	a, ok := GetArchive()
+	if !ok || !cluster.GetMembership().Owns(transactionId) {
+		return Saga{}, false
+	}
+	fl := p.l.WithFields(logrus.Fields{
+		"transaction_id": transactionId.String(),
+		"tenant_id":      p.t.Id().String(),
+	})
+
+	s, ok, err := a.GetById(p.t.Id(), transactionId)
+	if err != nil {
+		fl.WithError(err).Error("Unable to read saga from archive.")
+		countHydration(p.t.Id(), "", HydrationError)
+		return Saga{}, false
+	}
+	if !ok {
+		countHydration(p.t.Id(), "", HydrationMiss)
+		return Saga{}, false
+	}
+	if !s.active() {
+		fl.Debug("Archived saga has finished, and is not hydrated.")
+		countHydration(p.t.Id(), s.InitiatedBy, HydrationFinished)
+		return Saga{}, false
+	}
+
+	// A concurrent lookup may have hydrated the saga, which may since have progressed
+	if c, ok := GetCache().GetById(p.t.Id(), transactionId); ok {
+		return c, true
+	}
+	restoreSaga(p.l, p.t, s)
+	countHydration(p.t.Id(), s.InitiatedBy, HydrationHydrated)
+	fl.Infof("Hydrated saga [%s] from archive.", s.SagaType)
+	return s, true
+}
+
+// restoreSaga puts a saga read from the archive into the cache, starting its countdowns again, and parking its current
+// step again should it have been awaiting redelivery
+func restoreSaga(l logrus.FieldLogger, t tenant.Model, s Saga) {
+	GetCache().Put(t.Id(), s)
+	rearmTimers(l, t, s)
+	st, ok := s.GetCurrentStep()
+	if !ok || len(st.Attempts) == 0 {
+		return
+	}
+	a := st.Attempts[len(st.Attempts)-1]
+	if a.ErrorCode == ErrorCodeDispatchFailed && a.RetryAt != nil {
+		GetRetryQueue().Park(RetryEntry{Tenant: t, TransactionId: s.TransactionId, InitiatedBy: s.InitiatedBy, StepId: st.StepId, DueAt: *a.RetryAt})
+	}
+}
+
+// WarmCache hydrates the archived sagas owned by this replica with work remaining into the cache, so those held before
+// a restart or rebalance are available before events referencing them are received. Sagas already in the cache are
+// kept.
+func WarmCache(l logrus.FieldLogger) error {
+	a, ok := GetArchive()
+	if !ok {
+		return nil
+	}
+	start := time.Now()
+	active, err := a.GetActive()
+	if err != nil {
+		return err
+	}
+	warmed := 0
+	for t, sagas := range active {
+		for _, s := range sagas {
+			if !cluster.GetMembership().Owns(s.TransactionId) || !s.active() {
+				continue
+			}
+			if _, ok := GetCache().GetById(t.Id(), s.TransactionId); ok {
+				continue
+			}
+			restoreSaga(l, t, s)
+			countHydration(t.Id(), s.InitiatedBy, HydrationWarmed)
+			warmed++
+		}
+	}
+	l.Infof("Warmed cache with [%d] archived sagas in [%s].", warmed, time.Since(start))
+	return nil
+}
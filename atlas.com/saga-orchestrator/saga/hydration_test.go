@@ -0,0 +1,160 @@
+package saga
+
+import (
+	"errors"
+	tenant "github.com/Chronicle20/atlas-tenant"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type testArchive struct {
+	t     tenant.Model
+	sagas map[uuid.UUID]Saga
+	err   error
+}
+
+func (a *testArchive) GetById(tenantId uuid.UUID, transactionId uuid.UUID) (Saga, bool, error) {
+	if a.err != nil {
+		return Saga{}, false, a.err
+	}
+	if tenantId != a.t.Id() {
+		return Saga{}, false, nil
+	}
+	s, ok := a.sagas[transactionId]
+	return s, ok, nil
+}
+
+func (a *testArchive) GetActive() (map[tenant.Model][]Saga, error) {
+	if a.err != nil {
+		return nil, a.err
+	}
+	r := make([]Saga, 0, len(a.sagas))
+	for _, s := range a.sagas {
+		r = append(r, s)
+	}
+	return map[tenant.Model][]Saga{a.t: r}, nil
+}
+
+func (a *testArchive) GetDue(now time.Time) (map[tenant.Model][]Saga, error) {
+	if a.err != nil {
+		return nil, a.err
+	}
+	r := make([]Saga, 0)
+	for _, s := range a.sagas {
+		if at, ok := s.dueAt(); ok && !now.Before(at) {
+			r = append(r, s)
+		}
+	}
+	return map[tenant.Model][]Saga{a.t: r}, nil
+}
+
+// TestHydration tests that events referencing archived sagas not in the cache are processed once the saga is hydrated,
+// and that hydrations are counted by result
+func TestHydration(t *testing.T) {
+	te, ctx := setupContext()
+	defer InitArchive(nil)
+	defer ResetRetryQueue()
+	processor, _ := setupTestProcessor(ctx, nil, nil)
+
+	count := func(result string) float64 {
+		for _, s := range getHydrations().Samples() {
+			if s.Labels["tenant_id"] == te.Id().String() && s.Labels["result"] == result {
+				return s.Value
+			}
+		}
+		return 0
+	}
+	awaiting := NewBuilder().
+		SetSagaType(QuestReward).
+		AddStep("mesos", Pending, AwardMesos, AwardMesosPayload{CharacterId: 12345, ActorType: "NPC", Amount: 1000}).
+		Build()
+	finished := NewBuilder().
+		SetSagaType(QuestReward).
+		AddStep("mesos", Completed, AwardMesos, AwardMesosPayload{CharacterId: 12345, ActorType: "NPC", Amount: 1000}).
+		Build()
+	a := &testArchive{t: te, sagas: map[uuid.UUID]Saga{awaiting.TransactionId: awaiting, finished.TransactionId: finished}}
+
+	// Without an archive, sagas not in the cache do not exist
+	_, err := processor.GetById(awaiting.TransactionId)
+	assert.Error(t, err)
+	assert.Equal(t, float64(0), count(HydrationMiss))
+
+	InitArchive(a)
+	t.Run("status event of an archived saga is processed", func(t *testing.T) {
+		done, unsubscribe := GetNotifier().Subscribe(te.Id(), awaiting.TransactionId)
+		defer unsubscribe()
+		before := count(HydrationHydrated)
+
+		require.NoError(t, processor.StepCompleted(awaiting.TransactionId, true))
+		select {
+		case s := <-done:
+			assert.Equal(t, Completed, s.Steps[0].Status)
+		case <-time.After(time.Second):
+			t.Fatal("saga did not complete")
+		}
+		assert.Equal(t, before+1, count(HydrationHydrated))
+	})
+
+	t.Run("finished sagas are left archived", func(t *testing.T) {
+		before := count(HydrationFinished)
+		_, err := processor.GetById(finished.TransactionId)
+		assert.Error(t, err)
+		assert.Equal(t, before+1, count(HydrationFinished))
+		_, ok := GetCache().GetById(te.Id(), finished.TransactionId)
+		assert.False(t, ok)
+	})
+
+	t.Run("misses and errors are counted", func(t *testing.T) {
+		misses, errs := count(HydrationMiss), count(HydrationError)
+		_, err := processor.GetById(uuid.New())
+		assert.Error(t, err)
+		a.err = errors.New("archive unavailable")
+		defer func() { a.err = nil }()
+		_, err = processor.GetById(awaiting.TransactionId)
+		assert.Error(t, err)
+		assert.Equal(t, misses+1, count(HydrationMiss))
+		assert.Equal(t, errs+1, count(HydrationError))
+	})
+}
+
+// TestWarmCache tests warming the cache with the archived sagas with work remaining
+func TestWarmCache(t *testing.T) {
+	te, _ := setupContext()
+	defer InitArchive(nil)
+	defer ResetRetryQueue()
+	l, _ := test.NewNullLogger()
+
+	// Without an archive, there is nothing to warm
+	require.NoError(t, WarmCache(l))
+
+	parked := NewBuilder().
+		SetSagaType(QuestReward).
+		AddStep("mesos", Pending, AwardMesos, AwardMesosPayload{CharacterId: 12345, ActorType: "NPC", Amount: 1000}).
+		Build()
+	_, err := parked.RecordStepAttempt(0, time.Now())
+	require.NoError(t, err)
+	require.NoError(t, parked.RecordStepAttemptError(0, ErrorCodeDispatchFailed, "leader not available"))
+	dueAt := time.Now().Add(time.Minute)
+	parked.Steps[0].Attempts[0].RetryAt = &dueAt
+	finished := NewBuilder().
+		SetSagaType(QuestReward).
+		AddStep("mesos", Completed, AwardMesos, AwardMesosPayload{CharacterId: 12345, ActorType: "NPC", Amount: 1000}).
+		Build()
+	InitArchive(&testArchive{t: te, sagas: map[uuid.UUID]Saga{parked.TransactionId: parked, finished.TransactionId: finished}})
+
+	require.NoError(t, WarmCache(l))
+	defer GetCache().Remove(te.Id(), parked.TransactionId)
+	_, ok := GetCache().GetById(te.Id(), parked.TransactionId)
+	assert.True(t, ok)
+	_, ok = GetCache().GetById(te.Id(), finished.TransactionId)
+	assert.False(t, ok)
+
+	// Steps awaiting redelivery are parked again
+	assert.Equal(t, 1, GetRetryQueue().Depth()[te.Id()])
+	assert.Empty(t, GetRetryQueue().Due(time.Now()))
+	require.Len(t, GetRetryQueue().Due(dueAt), 1)
+}
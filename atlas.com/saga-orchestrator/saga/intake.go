@@ -0,0 +1,231 @@
+package saga
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Codes of the problems found with fields of saga creation requests
+const (
+	FieldErrorMissing     = "missing"     // A required field is absent
+	FieldErrorUnsupported = "unsupported" // A step's action is not supported by this version of the orchestrator
+	FieldErrorInvalid     = "invalid"     // A field is not of the expected type, or the request is not a JSON:API document
+	FieldErrorNegative    = "negative"    // A field which must not be negative is
+)
+
+// FieldError is a JSON:API error object describing a problem with a field of a saga creation request
+type FieldError struct {
+	Status string            `json:"status"` // HTTP status of the response, as a string
+	Code   string            `json:"code"`   // Code of the problem (e.g. missing)
+	Title  string            `json:"title"`  // Summary of the problem
+	Detail string            `json:"detail"` // Explanation of the problem with the field
+	Source FieldErrorPointer `json:"source"` // Field of the request the problem is with
+}
+
+// FieldErrorPointer locates the field of a request a problem is with
+type FieldErrorPointer struct {
+	Pointer string `json:"pointer"` // JSON pointer to the field (e.g. /data/attributes/steps/0/payload/characterId)
+}
+
+// intakeRequest is the part of a saga creation request checked before it is decoded
+type intakeRequest struct {
+	Data *struct {
+		Attributes *struct {
+			SagaType Type `json:"sagaType"`
+			Steps    []struct {
+				StepId          string          `json:"stepId"`
+				Action          Action          `json:"action"`
+				Payload         json.RawMessage `json:"payload"`
+				PayloadTemplate json.RawMessage `json:"payloadTemplate"`
+			} `json:"steps"`
+		} `json:"attributes"`
+	} `json:"data"`
+}
+
+// ValidateIntake returns middleware validating saga creation requests before they are decoded, responding 400 with an
+// error object for each problem found with their fields, so a malformed step payload is reported against the field at
+// fault rather than failing deep in the processor. Requests with steps whose actions are not supported are instead
+// rejected with 422 (see WriteUnsupportedActions). Valid requests are passed to the next handler.
+func ValidateIntake(l logrus.FieldLogger, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_ = r.Body.Close()
+
+		fes, unsupported := validateIntake(body)
+		if len(unsupported) > 0 {
+			WriteUnsupportedActions(l, w, unsupportedActionErrors(fes), unsupported)
+			return
+		}
+		if len(fes) > 0 {
+			l.Debugf("Rejecting saga creation request with [%d] invalid fields.", len(fes))
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			w.WriteHeader(http.StatusBadRequest)
+			if err = json.NewEncoder(w).Encode(map[string][]FieldError{"errors": fes}); err != nil {
+				l.WithError(err).Error("Unable to write validation errors.")
+			}
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		next(w, r)
+	}
+}
+
+// ValidateIntakeRequest returns the problems found with the fields of the saga creation request body
+func ValidateIntakeRequest(body []byte) []FieldError {
+	fes, _ := validateIntake(body)
+	return fes
+}
+
+// validateIntake returns the problems found with the fields of the saga creation request body, and the actions of its
+// steps which are not supported
+func validateIntake(body []byte) ([]FieldError, []Action) {
+	var req intakeRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return []FieldError{fieldError("", FieldErrorInvalid, fmt.Sprintf("request is not a valid JSON:API document: %s", err))}, nil
+	}
+	if req.Data == nil {
+		return []FieldError{fieldError("/data", FieldErrorMissing, "request has no primary data")}, nil
+	}
+	if req.Data.Attributes == nil {
+		return []FieldError{fieldError("/data/attributes", FieldErrorMissing, "saga has no attributes")}, nil
+	}
+
+	a := req.Data.Attributes
+	fes := make([]FieldError, 0)
+	actions := make([]Action, 0, len(a.Steps))
+	if a.SagaType == "" {
+		fes = append(fes, fieldError("/data/attributes/sagaType", FieldErrorMissing, "saga has no sagaType"))
+	}
+	if len(a.Steps) == 0 {
+		fes = append(fes, fieldError("/data/attributes/steps", FieldErrorMissing, "saga has no steps"))
+	}
+	for i, st := range a.Steps {
+		ptr := "/data/attributes/steps/" + strconv.Itoa(i)
+		if st.StepId == "" {
+			fes = append(fes, fieldError(ptr+"/stepId", FieldErrorMissing, "step has no stepId"))
+		}
+		if st.Action == "" {
+			fes = append(fes, fieldError(ptr+"/action", FieldErrorMissing, "step has no action"))
+			continue
+		}
+		actions = append(actions, st.Action)
+		if !slices.Contains(Actions, st.Action) {
+			fes = append(fes, UnsupportedActionError(ptr+"/action", st.StepId, st.Action))
+			continue
+		}
+		if len(st.PayloadTemplate) > 0 || (st.Action != ForEach && IsPayloadTemplate(st.Payload)) {
+			continue
+		}
+		fes = append(fes, validatePayload(ptr+"/payload", st.Action, st.Payload)...)
+	}
+	return fes, UnsupportedActions(actions)
+}
+
+// unsupportedActionErrors returns the problems which are steps whose actions are not supported
+func unsupportedActionErrors(fes []FieldError) []FieldError {
+	r := make([]FieldError, 0)
+	for _, fe := range fes {
+		if fe.Code == FieldErrorUnsupported {
+			r = append(r, fe)
+		}
+	}
+	return r
+}
+
+// validatePayload returns the problems found decoding the step payload as that of the action
+func validatePayload(ptr string, action Action, raw json.RawMessage) []FieldError {
+	if len(raw) == 0 {
+		raw = json.RawMessage("null")
+	}
+	data, err := json.Marshal(struct {
+		Action  Action          `json:"action"`
+		Payload json.RawMessage `json:"payload"`
+	}{Action: action, Payload: raw})
+	if err != nil {
+		return []FieldError{fieldError(ptr, FieldErrorInvalid, err.Error())}
+	}
+
+	var st Step[any]
+	if err = json.Unmarshal(data, &st); err != nil {
+		var te *json.UnmarshalTypeError
+		if !errors.As(err, &te) {
+			return []FieldError{fieldError(ptr, FieldErrorInvalid, fmt.Sprintf("payload is not valid for action [%s]", action))}
+		}
+		fp := ptr
+		if te.Field != "" {
+			fp += "/" + strings.ReplaceAll(te.Field, ".", "/")
+		}
+		switch te.Type.Kind() {
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if strings.HasPrefix(te.Value, "number -") {
+				return []FieldError{fieldError(fp, FieldErrorNegative, "must not be negative")}
+			}
+		}
+		return []FieldError{fieldError(fp, FieldErrorInvalid, fmt.Sprintf("must be a %s, not a %s", te.Type.String(), te.Value))}
+	}
+
+	var obj any
+	_ = json.Unmarshal(raw, &obj)
+	return validateFields(ptr, reflect.ValueOf(st.Payload), obj)
+}
+
+// validateFields walks the decoded value alongside the JSON it was decoded from, returning the character IDs which are
+// absent and quantities which are negative
+func validateFields(ptr string, v reflect.Value, raw any) []FieldError {
+	fes := make([]FieldError, 0)
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			fes = append(fes, validateFields(ptr, v.Elem(), raw)...)
+		}
+	case reflect.Slice:
+		items, _ := raw.([]any)
+		for i := 0; i < v.Len() && i < len(items); i++ {
+			fes = append(fes, validateFields(ptr+"/"+strconv.Itoa(i), v.Index(i), items[i])...)
+		}
+	case reflect.Struct:
+		obj, _ := raw.(map[string]any)
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if !f.IsExported() || name == "" || name == "-" {
+				continue
+			}
+			fv, present := obj[name]
+			switch {
+			case name == "characterId" && !present && !strings.Contains(opts, "omitempty"):
+				fes = append(fes, fieldError(ptr+"/"+name, FieldErrorMissing, "characterId is required"))
+			case name == "quantity" && v.Field(i).CanInt() && v.Field(i).Int() < 0:
+				fes = append(fes, fieldError(ptr+"/"+name, FieldErrorNegative, "must not be negative"))
+			case present:
+				fes = append(fes, validateFields(ptr+"/"+name, v.Field(i), fv)...)
+			}
+		}
+	}
+	return fes
+}
+
+func fieldError(pointer string, code string, detail string) FieldError {
+	title := "Invalid field"
+	switch code {
+	case FieldErrorMissing:
+		title = "Missing field"
+	case FieldErrorNegative:
+		title = "Negative value"
+	}
+	return FieldError{Status: strconv.Itoa(http.StatusBadRequest), Code: code, Title: title, Detail: detail, Source: FieldErrorPointer{Pointer: pointer}}
+}
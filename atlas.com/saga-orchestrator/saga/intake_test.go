@@ -0,0 +1,145 @@
+package saga
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestValidateIntakeRequest tests that the problems with the fields of saga creation requests are reported against the
+// fields at fault
+func TestValidateIntakeRequest(t *testing.T) {
+	request := func(steps string) []byte {
+		return []byte(`{"data":{"type":"sagas","attributes":{"sagaType":"quest_reward","initiatedBy":"quest-service","steps":[` + steps + `]}}}`)
+	}
+	type problem struct {
+		pointer string
+		code    string
+	}
+	problems := func(fes []FieldError) []problem {
+		r := make([]problem, 0, len(fes))
+		for _, fe := range fes {
+			r = append(r, problem{pointer: fe.Source.Pointer, code: fe.Code})
+			if fe.Code == FieldErrorUnsupported {
+				assert.Equal(t, "422", fe.Status)
+			} else {
+				assert.Equal(t, "400", fe.Status)
+			}
+		}
+		return r
+	}
+
+	tests := []struct {
+		name     string
+		body     []byte
+		expected []problem
+	}{
+		{
+			name:     "valid",
+			body:     request(`{"stepId":"mesos","action":"award_mesos","payload":{"characterId":12345,"actorType":"NPC","amount":-1000}},{"stepId":"item","action":"award_asset","payload":{"characterId":12345,"item":{"templateId":2000000,"quantity":1}}}`),
+			expected: []problem{},
+		},
+		{
+			name:     "templated payloads are not validated",
+			body:     request(`{"stepId":"mesos","action":"award_mesos","payload":{"characterId":"{{vars.characterId}}","amount":1000}}`),
+			expected: []problem{},
+		},
+		{
+			name:     "not a document",
+			body:     []byte(`{"data":`),
+			expected: []problem{{pointer: "", code: FieldErrorInvalid}},
+		},
+		{
+			name:     "no attributes",
+			body:     []byte(`{"data":{"type":"sagas"}}`),
+			expected: []problem{{pointer: "/data/attributes", code: FieldErrorMissing}},
+		},
+		{
+			name:     "no saga type or steps",
+			body:     []byte(`{"data":{"type":"sagas","attributes":{"initiatedBy":"quest-service"}}}`),
+			expected: []problem{{pointer: "/data/attributes/sagaType", code: FieldErrorMissing}, {pointer: "/data/attributes/steps", code: FieldErrorMissing}},
+		},
+		{
+			name: "missing character and step IDs, and unsupported actions",
+			body: request(`{"stepId":"mesos","action":"award_mesos","payload":{"amount":1000}},{"action":"award_gold","payload":{}},{"stepId":"timer","payload":{}}`),
+			expected: []problem{
+				{pointer: "/data/attributes/steps/0/payload/characterId", code: FieldErrorMissing},
+				{pointer: "/data/attributes/steps/1/stepId", code: FieldErrorMissing},
+				{pointer: "/data/attributes/steps/1/action", code: FieldErrorUnsupported},
+				{pointer: "/data/attributes/steps/2/action", code: FieldErrorMissing},
+			},
+		},
+		{
+			name:     "negative quantity",
+			body:     request(`{"stepId":"item","action":"award_asset","payload":{"characterId":12345,"item":{"templateId":2000000,"quantity":-5}}}`),
+			expected: []problem{{pointer: "/data/attributes/steps/0/payload/item/quantity", code: FieldErrorNegative}},
+		},
+		{
+			name:     "mistyped field",
+			body:     request(`{"stepId":"mesos","action":"award_mesos","payload":{"characterId":"12345","amount":1000}}`),
+			expected: []problem{{pointer: "/data/attributes/steps/0/payload/characterId", code: FieldErrorInvalid}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, problems(ValidateIntakeRequest(tt.body)))
+		})
+	}
+}
+
+// TestValidateIntake tests that invalid saga creation requests are answered with their problems, and those with
+// unsupported actions with the supported-action manifest, while valid requests are passed on intact
+func TestValidateIntake(t *testing.T) {
+	l, _ := test.NewNullLogger()
+	var received string
+	h := ValidateIntake(l, func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		received = string(b)
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	valid := `{"data":{"type":"sagas","attributes":{"sagaType":"quest_reward","steps":[{"stepId":"mesos","action":"award_mesos","payload":{"characterId":12345,"amount":1000}}]}}}`
+	rr := httptest.NewRecorder()
+	h(rr, httptest.NewRequest(http.MethodPost, "/sagas", strings.NewReader(valid)))
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Equal(t, valid, received)
+
+	received = ""
+	rr = httptest.NewRecorder()
+	h(rr, httptest.NewRequest(http.MethodPost, "/sagas", strings.NewReader(strings.Replace(valid, `"characterId":12345,`, "", 1))))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Empty(t, received)
+	assert.Equal(t, "application/vnd.api+json", rr.Header().Get("Content-Type"))
+
+	var doc struct {
+		Errors []FieldError `json:"errors"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &doc))
+	require.Len(t, doc.Errors, 1)
+	assert.Equal(t, FieldErrorMissing, doc.Errors[0].Code)
+	assert.Equal(t, "/data/attributes/steps/0/payload/characterId", doc.Errors[0].Source.Pointer)
+
+	rr = httptest.NewRecorder()
+	h(rr, httptest.NewRequest(http.MethodPost, "/sagas", strings.NewReader(strings.Replace(valid, `"payload":{"characterId":12345,"amount":1000}}`, `"payload":{"amount":1000}},{"stepId":"pet","action":"award_pet","payload":{}},{"stepId":"pet2","action":"award_pet","payload":{}}`, 1))))
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+	assert.Empty(t, received)
+
+	var unsupported struct {
+		Errors []FieldError           `json:"errors"`
+		Meta   UnsupportedActionsMeta `json:"meta"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &unsupported))
+	require.Len(t, unsupported.Errors, 2)
+	assert.Equal(t, FieldErrorUnsupported, unsupported.Errors[0].Code)
+	assert.Equal(t, "/data/attributes/steps/1/action", unsupported.Errors[0].Source.Pointer)
+	assert.Equal(t, "/data/attributes/steps/2/action", unsupported.Errors[1].Source.Pointer)
+	assert.Equal(t, []Action{"award_pet"}, unsupported.Meta.UnsupportedActions)
+	assert.Equal(t, SupportedActions(), unsupported.Meta.SupportedActions)
+}
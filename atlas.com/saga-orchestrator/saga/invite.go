@@ -0,0 +1,83 @@
+package saga
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	tenant "github.com/Chronicle20/atlas-tenant"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrorCodeInviteExpired is the error code a create_invite step fails with when its invitation is not answered within
+// its TTL, so that its error handlers may declare a fallback
+const ErrorCodeInviteExpired = "INVITE_EXPIRED"
+
+// InviteConfig configures how long invitations created by create_invite steps may remain unanswered
+type InviteConfig struct {
+	Ttl time.Duration // Ttl of invitations whose step declares none. When 0, they do not expire.
+}
+
+// InviteConfigFromEnv loads the invite configuration from the environment
+func InviteConfigFromEnv() (InviteConfig, error) {
+	c := InviteConfig{}
+	if v, ok := os.LookupEnv("SAGA_INVITE_TTL"); ok && v != "" {
+		t, err := time.ParseDuration(v)
+		if err != nil || t < time.Second {
+			return InviteConfig{}, fmt.Errorf("invalid SAGA_INVITE_TTL '%s'", v)
+		}
+		c.Ttl = t
+	}
+	return c, nil
+}
+
+// Singleton invite configuration, under which invitations do not expire until initialized
+var inviteConfig = InviteConfig{}
+
+// InitInviteConfig replaces the singleton invite configuration
+func InitInviteConfig(config InviteConfig) {
+	inviteConfig = config
+}
+
+// GetInviteConfig returns the singleton invite configuration
+func GetInviteConfig() InviteConfig {
+	return inviteConfig
+}
+
+// AwaitingInviteAnswer returns whether the saga's current step is a create_invite step with a TTL, which awaits its
+// invitation being accepted or rejected rather than created
+func (s Saga) AwaitingInviteAnswer() bool {
+	st, ok := s.GetCurrentStep()
+	if !ok || st.Action != CreateInvite {
+		return false
+	}
+	payload, ok := st.Payload.(CreateInvitePayload)
+	return ok && payload.Ttl > 0
+}
+
+// inviteExpired fails a create_invite step whose invitation was not answered within its TTL, reacting as declared by
+// the step's error handlers. Steps which have since been answered, or sagas which are compensating, are left alone.
+func inviteExpired(l logrus.FieldLogger, ctx context.Context, transactionId uuid.UUID, stepId string) {
+	p := NewProcessor(l, ctx)
+	s, err := p.GetById(transactionId)
+	if err != nil {
+		return
+	}
+	st, ok := s.GetCurrentStep()
+	if !ok || s.Failing() || st.StepId != stepId {
+		return
+	}
+
+	fl := l.WithFields(logrus.Fields{
+		"transaction_id": transactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        stepId,
+		"tenant_id":      tenant.MustFromContext(ctx).Id().String(),
+	})
+	fl.Debug("Invitation expired unanswered. Failing step.")
+	if err = p.StepFailed(transactionId, ErrorCodeInviteExpired, "invitation expired unanswered"); err != nil {
+		fl.WithError(err).Error("Unable to apply invitation expiry.")
+	}
+}
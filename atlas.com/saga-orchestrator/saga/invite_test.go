@@ -0,0 +1,124 @@
+package saga
+
+import (
+	mock2 "atlas-saga-orchestrator/invite/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+// TestInviteConfigFromEnv tests loading the invite TTL from the environment
+func TestInviteConfigFromEnv(t *testing.T) {
+	t.Setenv("SAGA_INVITE_TTL", "2m")
+	c, err := InviteConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Minute, c.Ttl)
+
+	for _, v := range []string{"soon", "500ms", "-1m"} {
+		t.Setenv("SAGA_INVITE_TTL", v)
+		_, err = InviteConfigFromEnv()
+		assert.Error(t, err, v)
+	}
+}
+
+// TestInviteExpiry tests that an unanswered invitation fails its step once its TTL elapses, taking the fallback its
+// error handlers declare
+func TestInviteExpiry(t *testing.T) {
+	te, ctx := setupContext()
+	defer ResetTimerRegistry()
+	defer InitInviteConfig(InviteConfig{})
+	InitInviteConfig(InviteConfig{Ttl: time.Minute})
+
+	processor, _ := setupTestProcessor(ctx, nil, nil)
+	processor = processor.WithInviteProcessor(&mock2.ProcessorMock{})
+	invite := CreateInvitePayload{InviteType: "PARTY", OriginatorId: 1, TargetId: 2, ReferenceId: 3}
+
+	t.Run("invitations without a TTL do not await an answer", func(t *testing.T) {
+		InitInviteConfig(InviteConfig{})
+		defer InitInviteConfig(InviteConfig{Ttl: time.Minute})
+
+		s := NewBuilder().SetSagaType(InventoryTransaction).AddStep("invite", Pending, CreateInvite, invite).Build()
+		require.NoError(t, processor.Put(s))
+		defer GetCache().Remove(te.Id(), s.TransactionId)
+
+		s, err := processor.GetById(s.TransactionId)
+		require.NoError(t, err)
+		assert.False(t, s.AwaitingInviteAnswer())
+		_, ok := GetTimerRegistry().Deadline(te.Id(), s.TransactionId, "invite")
+		assert.False(t, ok)
+	})
+
+	t.Run("expiry fails the step", func(t *testing.T) {
+		s := NewBuilder().SetSagaType(InventoryTransaction).AddStep("invite", Pending, CreateInvite, invite).Build()
+		done, unsubscribe := GetNotifier().Subscribe(te.Id(), s.TransactionId)
+		defer unsubscribe()
+		require.NoError(t, processor.Put(s))
+		defer GetCache().Remove(te.Id(), s.TransactionId)
+
+		s, err := processor.GetById(s.TransactionId)
+		require.NoError(t, err)
+		assert.True(t, s.AwaitingInviteAnswer())
+		assert.Equal(t, uint32(60), s.Steps[0].Payload.(CreateInvitePayload).Ttl)
+		deadline, ok := GetTimerRegistry().Deadline(te.Id(), s.TransactionId, "invite")
+		require.True(t, ok)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, 5*time.Second)
+
+		// The countdown elapses
+		inviteExpired(processor.(*ProcessorImpl).l, ctx, s.TransactionId, "invite")
+		select {
+		case s = <-done:
+		case <-time.After(time.Second):
+			t.Fatal("saga did not fail")
+		}
+		assert.Equal(t, ErrorCodeInviteExpired, s.Steps[0].Attempts[len(s.Steps[0].Attempts)-1].ErrorCode)
+	})
+
+	t.Run("expiry takes the declared fallback", func(t *testing.T) {
+		invite.Ttl = 30
+		s := NewBuilder().
+			SetSagaType(InventoryTransaction).
+			AddStep("invite", Pending, CreateInvite, invite).
+			AddErrorHandler(ErrorHandler{ErrorCode: ErrorCodeInviteExpired, Reaction: ErrorReactionSkip}).
+			AddStep("invite-other", Pending, CreateInvite, CreateInvitePayload{InviteType: "PARTY", OriginatorId: 1, TargetId: 4, ReferenceId: 3}).
+			Build()
+		require.NoError(t, processor.Put(s))
+		defer GetCache().Remove(te.Id(), s.TransactionId)
+
+		deadline, ok := GetTimerRegistry().Deadline(te.Id(), s.TransactionId, "invite")
+		require.True(t, ok)
+		assert.WithinDuration(t, time.Now().Add(30*time.Second), deadline, 5*time.Second)
+
+		inviteExpired(processor.(*ProcessorImpl).l, ctx, s.TransactionId, "invite")
+		s, err := processor.GetById(s.TransactionId)
+		require.NoError(t, err)
+		assert.Equal(t, Completed, s.Steps[0].Status)
+		st, ok := s.GetCurrentStep()
+		require.True(t, ok)
+		assert.Equal(t, "invite-other", st.StepId)
+
+		// A countdown of a step which has since been answered is ignored
+		inviteExpired(processor.(*ProcessorImpl).l, ctx, s.TransactionId, "invite")
+		s, err = processor.GetById(s.TransactionId)
+		require.NoError(t, err)
+		assert.Equal(t, Pending, s.Steps[1].Status)
+	})
+
+	t.Run("answered invitations complete the step", func(t *testing.T) {
+		s := NewBuilder().SetSagaType(InventoryTransaction).AddStep("invite", Pending, CreateInvite, invite).Build()
+		done, unsubscribe := GetNotifier().Subscribe(te.Id(), s.TransactionId)
+		defer unsubscribe()
+		require.NoError(t, processor.Put(s))
+
+		require.NoError(t, processor.StepCompletedWithEvent(s.TransactionId, nil))
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("saga did not complete")
+		}
+		_, ok := GetTimerRegistry().Deadline(te.Id(), s.TransactionId, "invite")
+		assert.False(t, ok)
+		_, err := processor.GetById(s.TransactionId)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,56 @@
+package saga
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/google/uuid"
+)
+
+// ErrTenantMismatch is returned when a status event references a saga which belongs to a tenant other than the event's
+var ErrTenantMismatch = errors.New("status event tenant does not match saga tenant")
+
+// TenantMismatchConfig configures how status events received for a tenant other than that of their saga are handled
+type TenantMismatchConfig struct {
+	DeadLetter bool // DeadLetter mismatched events to the dead letter topic, rather than only rejecting them
+}
+
+// TenantMismatchConfigFromEnv loads the tenant mismatch configuration from the environment
+func TenantMismatchConfigFromEnv() (TenantMismatchConfig, error) {
+	switch v := os.Getenv("SAGA_TENANT_MISMATCH"); v {
+	case "", "reject":
+		return TenantMismatchConfig{}, nil
+	case "dead_letter":
+		return TenantMismatchConfig{DeadLetter: true}, nil
+	default:
+		return TenantMismatchConfig{}, fmt.Errorf("invalid SAGA_TENANT_MISMATCH '%s', expected reject or dead_letter", v)
+	}
+}
+
+// Singleton tenant mismatch configuration, which rejects mismatched events until initialized
+var tenantMismatchConfig TenantMismatchConfig
+
+// InitTenantMismatchConfig replaces the singleton tenant mismatch configuration
+func InitTenantMismatchConfig(config TenantMismatchConfig) {
+	tenantMismatchConfig = config
+}
+
+// GetTenantMismatchConfig returns the singleton tenant mismatch configuration
+func GetTenantMismatchConfig() TenantMismatchConfig {
+	return tenantMismatchConfig
+}
+
+// ValidateEventTenant checks a status event received for a tenant references a saga of that tenant. Events for sagas
+// which are not active for any tenant, such as those which have already completed, are valid, as they advance nothing.
+func ValidateEventTenant(tenantId uuid.UUID, transactionId uuid.UUID) error {
+	if _, ok := GetCache().GetById(tenantId, transactionId); ok {
+		return nil
+	}
+	for _, o := range GetCache().TenantsOf(transactionId) {
+		if o != tenantId {
+			return fmt.Errorf("%w: saga [%s] belongs to tenant [%s], not [%s]", ErrTenantMismatch, transactionId.String(), o.String(), tenantId.String())
+		}
+	}
+	return nil
+}
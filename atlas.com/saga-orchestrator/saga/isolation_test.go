@@ -0,0 +1,97 @@
+package saga
+
+import (
+	"atlas-saga-orchestrator/character/mock"
+	"github.com/Chronicle20/atlas-constants/channel"
+	"github.com/Chronicle20/atlas-constants/world"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+// TestTenantMismatchConfigFromEnv tests loading the tenant mismatch configuration from the environment
+func TestTenantMismatchConfigFromEnv(t *testing.T) {
+	t.Setenv("SAGA_TENANT_MISMATCH", "dead_letter")
+	c, err := TenantMismatchConfigFromEnv()
+	require.NoError(t, err)
+	assert.True(t, c.DeadLetter)
+
+	t.Setenv("SAGA_TENANT_MISMATCH", "ignore")
+	_, err = TenantMismatchConfigFromEnv()
+	assert.Error(t, err)
+}
+
+// TestTenantIsolation tests that status events only advance sagas of the tenant they were received for
+func TestTenantIsolation(t *testing.T) {
+	teA, ctxA := setupContext()
+	teB, ctxB := setupContext()
+
+	awarded := 0
+	charP := &mock.ProcessorMock{
+		AwardMesosAndEmitFunc: func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, actorId uint32, actorType string, amount int32) error {
+			awarded++
+			return nil
+		},
+	}
+	processorA, _ := setupTestProcessor(ctxA, charP, nil)
+	processorB, _ := setupTestProcessor(ctxB, charP, nil)
+
+	newSaga := func(transactionId uuid.UUID) Saga {
+		return Saga{
+			TransactionId: transactionId,
+			SagaType:      QuestReward,
+			InitiatedBy:   "npc-9010000",
+			Steps: []Step[any]{
+				{StepId: "award", Status: Pending, Action: AwardMesos, Payload: AwardMesosPayload{CharacterId: 12345, ActorType: "NPC", Amount: 100}},
+				{StepId: "bonus", Status: Pending, Action: AwardMesos, Payload: AwardMesosPayload{CharacterId: 12345, ActorType: "NPC", Amount: 50}},
+			},
+		}
+	}
+	statuses := func(p Processor, transactionId uuid.UUID) []Status {
+		s, err := p.GetById(transactionId)
+		require.NoError(t, err)
+		r := make([]Status, 0, len(s.Steps))
+		for _, st := range s.Steps {
+			r = append(r, st.Status)
+		}
+		return r
+	}
+
+	t.Run("events for a saga of another tenant are rejected", func(t *testing.T) {
+		s := newSaga(uuid.New())
+		GetCache().Put(teB.Id(), s)
+		defer GetCache().Remove(teB.Id(), s.TransactionId)
+
+		assert.ErrorIs(t, processorA.StepCompleted(s.TransactionId, true), ErrTenantMismatch)
+		assert.ErrorIs(t, processorA.StepCompletedWithEvent(s.TransactionId, struct{}{}), ErrTenantMismatch)
+		assert.ErrorIs(t, processorA.StepFailed(s.TransactionId, "UNKNOWN", ""), ErrTenantMismatch)
+		assert.Equal(t, []Status{Pending, Pending}, statuses(processorB, s.TransactionId))
+		assert.Equal(t, 0, awarded)
+
+		// Rejections are dead-lettered when configured, and still do not advance the saga
+		defer InitTenantMismatchConfig(TenantMismatchConfig{})
+		InitTenantMismatchConfig(TenantMismatchConfig{DeadLetter: true})
+		assert.ErrorIs(t, processorA.StepCompleted(s.TransactionId, true), ErrTenantMismatch)
+		assert.Equal(t, []Status{Pending, Pending}, statuses(processorB, s.TransactionId))
+	})
+
+	t.Run("colliding transaction ids only advance the tenant's saga", func(t *testing.T) {
+		awarded = 0
+		id := uuid.New()
+		GetCache().Put(teA.Id(), newSaga(id))
+		defer GetCache().Remove(teA.Id(), id)
+		GetCache().Put(teB.Id(), newSaga(id))
+		defer GetCache().Remove(teB.Id(), id)
+
+		require.NoError(t, processorA.StepCompleted(id, true))
+		assert.Equal(t, []Status{Completed, Pending}, statuses(processorA, id))
+		assert.Equal(t, []Status{Pending, Pending}, statuses(processorB, id))
+		assert.Equal(t, 1, awarded)
+	})
+
+	t.Run("events for sagas which are not active are ignored", func(t *testing.T) {
+		assert.NoError(t, processorA.StepCompleted(uuid.New(), true))
+		assert.NoError(t, processorA.StepFailed(uuid.New(), "UNKNOWN", ""))
+	})
+}
@@ -0,0 +1,106 @@
+package saga
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// JSONSchemaDialect is the dialect of the JSON schemas generated for step payloads
+const JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// PayloadType returns the type of the payload of a step taking the action, as typed when the step is deserialized
+func PayloadType(action Action) (reflect.Type, bool) {
+	// A step of the action deserializes an empty payload to the type the action is defined with
+	var st Step[any]
+	if err := json.Unmarshal([]byte(`{"action":"`+string(action)+`","payload":{}}`), &st); err != nil || st.Payload == nil {
+		return nil, false
+	}
+	return reflect.TypeOf(st.Payload), true
+}
+
+// GenerateJSONSchema generates the JSON schema of a type from its fields and their json tags. Fields without omitempty
+// are required. Types nested within themselves, such as the steps of error handlers, are described as objects only.
+func GenerateJSONSchema(t reflect.Type) map[string]any {
+	s := jsonSchema(t, make(map[reflect.Type]bool))
+	s["$schema"] = JSONSchemaDialect
+	return s
+}
+
+func jsonSchema(t reflect.Type, visiting map[reflect.Type]bool) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t {
+	case timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case uuidType:
+		return map[string]any{"type": "string", "format": "uuid"}
+	case rawMessageType:
+		return map[string]any{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 && t.Kind() == reflect.Slice {
+			return map[string]any{"type": "string", "contentEncoding": "base64"}
+		}
+		return map[string]any{"type": "array", "items": jsonSchema(t.Elem(), visiting)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": jsonSchema(t.Elem(), visiting)}
+	case reflect.Struct:
+		if visiting[t] {
+			return map[string]any{"type": "object"}
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+
+		properties := make(map[string]any)
+		required := make([]string, 0)
+		addStructProperties(t, visiting, properties, &required)
+		s := map[string]any{"type": "object", "properties": properties}
+		if len(required) > 0 {
+			s["required"] = required
+		}
+		return s
+	}
+	// Interfaces hold a value of any type
+	return map[string]any{}
+}
+
+// addStructProperties adds the properties of the exported fields of a struct, including those of embedded structs, as
+// they are marshaled
+func addStructProperties(t reflect.Type, visiting map[reflect.Type]bool, properties map[string]any, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			addStructProperties(f.Type, visiting, properties, required)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		properties[name] = jsonSchema(f.Type, visiting)
+		if !strings.Contains(opts, "omitempty") {
+			*required = append(*required, name)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package saga
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	tenant "github.com/Chronicle20/atlas-tenant"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrorCodeKillCountTimeout is the error code an await_kill_count step fails with when its target count is not reached
+// within its timeout, so that its error handlers may declare a fallback
+const ErrorCodeKillCountTimeout = "KILL_COUNT_TIMEOUT"
+
+// MonsterKill describes a monster killed in a field, as reported by a monster status event
+type MonsterKill struct {
+	WorldId      byte
+	ChannelId    byte
+	MapId        uint32
+	Instance     uuid.UUID
+	MonsterId    uint32
+	Contributors []uint32 // Characters which killed or damaged the monster
+}
+
+// Counts returns whether the kill counts towards the await_kill_count step with the payload
+func (k MonsterKill) Counts(payload AwaitKillCountPayload) bool {
+	if k.WorldId != payload.WorldId || k.ChannelId != payload.ChannelId || k.MapId != payload.MapId || k.Instance != payload.Instance {
+		return false
+	}
+	if len(payload.MonsterIds) > 0 && !slices.Contains(payload.MonsterIds, k.MonsterId) {
+		return false
+	}
+	return payload.CharacterId == 0 || slices.Contains(k.Contributors, payload.CharacterId)
+}
+
+// MonsterKilled counts a monster kill towards each of the tenant's sagas whose current step awaits kills it matches,
+// completing those steps whose target count is reached
+func (p *ProcessorImpl) MonsterKilled(kill MonsterKill) error {
+	for _, s := range GetCache().GetAll(p.t.Id()) {
+		if s.Failing() {
+			continue
+		}
+		st, ok := s.GetCurrentStep()
+		if !ok || st.Action != AwaitKillCount {
+			continue
+		}
+		payload, ok := st.Payload.(AwaitKillCountPayload)
+		if !ok || !kill.Counts(payload) {
+			continue
+		}
+		idx := s.FindStepIndex(st.StepId)
+		if idx == -1 {
+			continue
+		}
+
+		payload.Killed++
+		s.Steps = append([]Step[any]{}, s.Steps...)
+		s.Steps[idx].Payload = payload
+		s.Steps[idx].UpdatedAt = time.Now()
+		GetCache().Put(p.t.Id(), s)
+
+		fl := p.l.WithFields(logrus.Fields{
+			"transaction_id": s.TransactionId.String(),
+			"saga_type":      s.SagaType,
+			"step_id":        st.StepId,
+			"monster_id":     kill.MonsterId,
+			"tenant_id":      p.t.Id().String(),
+		})
+		if payload.Killed < payload.Count {
+			fl.Debugf("Counted monster kill [%d/%d].", payload.Killed, payload.Count)
+			continue
+		}
+		fl.Debugf("Kill count [%d] reached. Completing step.", payload.Count)
+		if err := p.StepCompleted(s.TransactionId, true); err != nil {
+			fl.WithError(err).Error("Unable to complete step awaiting kills.")
+		}
+	}
+	return nil
+}
+
+// killCountExpired fails an await_kill_count step whose target count was not reached within its timeout, reacting as
+// declared by the step's error handlers. Steps which have since completed, or sagas which are compensating, are left
+// alone.
+func killCountExpired(l logrus.FieldLogger, ctx context.Context, transactionId uuid.UUID, stepId string) {
+	p := NewProcessor(l, ctx)
+	s, err := p.GetById(transactionId)
+	if err != nil {
+		return
+	}
+	st, ok := s.GetCurrentStep()
+	if !ok || s.Failing() || st.StepId != stepId {
+		return
+	}
+
+	fl := l.WithFields(logrus.Fields{
+		"transaction_id": transactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        stepId,
+		"tenant_id":      tenant.MustFromContext(ctx).Id().String(),
+	})
+	fl.Debug("Kill count not reached before timeout. Failing step.")
+	if err = p.StepFailed(transactionId, ErrorCodeKillCountTimeout, "kill count not reached before timeout"); err != nil {
+		fl.WithError(err).Error("Unable to apply kill count timeout.")
+	}
+}
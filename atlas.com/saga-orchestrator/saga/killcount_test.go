@@ -0,0 +1,107 @@
+package saga
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+// TestMonsterKillCounts tests filtering the kills which count towards an await_kill_count step
+func TestMonsterKillCounts(t *testing.T) {
+	instance := uuid.New()
+	payload := AwaitKillCountPayload{WorldId: 1, ChannelId: 2, MapId: 103000800, Instance: instance, Count: 10}
+	kill := MonsterKill{WorldId: 1, ChannelId: 2, MapId: 103000800, Instance: instance, MonsterId: 9300001, Contributors: []uint32{12345, 67890}}
+
+	tests := []struct {
+		name     string
+		modify   func(p *AwaitKillCountPayload, k *MonsterKill)
+		expected bool
+	}{
+		{name: "Same field", modify: func(p *AwaitKillCountPayload, k *MonsterKill) {}, expected: true},
+		{name: "Other channel", modify: func(p *AwaitKillCountPayload, k *MonsterKill) { k.ChannelId = 3 }, expected: false},
+		{name: "Other map", modify: func(p *AwaitKillCountPayload, k *MonsterKill) { k.MapId = 103000801 }, expected: false},
+		{name: "Other instance", modify: func(p *AwaitKillCountPayload, k *MonsterKill) { k.Instance = uuid.New() }, expected: false},
+		{name: "Listed monster", modify: func(p *AwaitKillCountPayload, k *MonsterKill) { p.MonsterIds = []uint32{9300000, 9300001} }, expected: true},
+		{name: "Unlisted monster", modify: func(p *AwaitKillCountPayload, k *MonsterKill) { p.MonsterIds = []uint32{9300000} }, expected: false},
+		{name: "Character contributed", modify: func(p *AwaitKillCountPayload, k *MonsterKill) { p.CharacterId = 67890 }, expected: true},
+		{name: "Character did not contribute", modify: func(p *AwaitKillCountPayload, k *MonsterKill) { p.CharacterId = 11111 }, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, k := payload, kill
+			tt.modify(&p, &k)
+			assert.Equal(t, tt.expected, k.Counts(p))
+		})
+	}
+}
+
+// TestAwaitKillCount tests that an await_kill_count step completes once enough matching kills are counted, and fails
+// when its timeout elapses first
+func TestAwaitKillCount(t *testing.T) {
+	te, ctx := setupContext()
+	defer ResetTimerRegistry()
+
+	processor, _ := setupTestProcessor(ctx, nil, nil)
+	payload := AwaitKillCountPayload{WorldId: 1, ChannelId: 2, MapId: 103000800, MonsterIds: []uint32{9300001}, Count: 2, Timeout: 60}
+	kill := MonsterKill{WorldId: 1, ChannelId: 2, MapId: 103000800, MonsterId: 9300001}
+
+	t.Run("kills complete the step", func(t *testing.T) {
+		s := NewBuilder().
+			SetSagaType(QuestReward).
+			AddStep("kills", Pending, AwaitKillCount, payload).
+			AddStep("done", Pending, SetVariable, SetVariablePayload{Name: "done", Value: true}).
+			Build()
+		done, unsubscribe := GetNotifier().Subscribe(te.Id(), s.TransactionId)
+		defer unsubscribe()
+		require.NoError(t, processor.Put(s))
+		defer GetCache().Remove(te.Id(), s.TransactionId)
+
+		deadline, ok := GetTimerRegistry().Deadline(te.Id(), s.TransactionId, "kills")
+		require.True(t, ok)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, 5*time.Second)
+
+		// Kills of other monsters are not counted
+		require.NoError(t, processor.MonsterKilled(MonsterKill{WorldId: 1, ChannelId: 2, MapId: 103000800, MonsterId: 9300002}))
+		require.NoError(t, processor.MonsterKilled(kill))
+		s, err := processor.GetById(s.TransactionId)
+		require.NoError(t, err)
+		assert.Equal(t, Pending, s.Steps[0].Status)
+		assert.Equal(t, uint32(1), s.Steps[0].Payload.(AwaitKillCountPayload).Killed)
+
+		require.NoError(t, processor.MonsterKilled(kill))
+		select {
+		case s = <-done:
+		case <-time.After(time.Second):
+			t.Fatal("saga did not complete")
+		}
+		assert.Equal(t, Completed, s.Steps[0].Status)
+		assert.Equal(t, uint32(2), s.Steps[0].Payload.(AwaitKillCountPayload).Killed)
+		assert.Equal(t, true, s.Variables["done"])
+	})
+
+	t.Run("timeout fails the step", func(t *testing.T) {
+		s := NewBuilder().SetSagaType(QuestReward).AddStep("kills", Pending, AwaitKillCount, payload).Build()
+		done, unsubscribe := GetNotifier().Subscribe(te.Id(), s.TransactionId)
+		defer unsubscribe()
+		require.NoError(t, processor.Put(s))
+		defer GetCache().Remove(te.Id(), s.TransactionId)
+
+		require.NoError(t, processor.MonsterKilled(kill))
+		killCountExpired(processor.(*ProcessorImpl).l, ctx, s.TransactionId, "kills")
+		select {
+		case s = <-done:
+		case <-time.After(time.Second):
+			t.Fatal("saga did not fail")
+		}
+		assert.Equal(t, ErrorCodeKillCountTimeout, s.Steps[0].Attempts[len(s.Steps[0].Attempts)-1].ErrorCode)
+	})
+
+	t.Run("invalid count is rejected", func(t *testing.T) {
+		s := NewBuilder().SetSagaType(QuestReward).AddStep("kills", Pending, AwaitKillCount, AwaitKillCountPayload{MapId: 103000800}).Build()
+		assert.ErrorIs(t, processor.Put(s), ErrActionRejected)
+		GetCache().Remove(te.Id(), s.TransactionId)
+	})
+}
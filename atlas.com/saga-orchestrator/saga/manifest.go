@@ -0,0 +1,293 @@
+package saga
+
+import (
+	account2 "atlas-saga-orchestrator/kafka/message/account"
+	asset2 "atlas-saga-orchestrator/kafka/message/asset"
+	buff2 "atlas-saga-orchestrator/kafka/message/buff"
+	character2 "atlas-saga-orchestrator/kafka/message/character"
+	compartment2 "atlas-saga-orchestrator/kafka/message/compartment"
+	coupon2 "atlas-saga-orchestrator/kafka/message/coupon"
+	faction2 "atlas-saga-orchestrator/kafka/message/faction"
+	guild2 "atlas-saga-orchestrator/kafka/message/guild"
+	instance2 "atlas-saga-orchestrator/kafka/message/instance"
+	invite2 "atlas-saga-orchestrator/kafka/message/invite"
+	marriage2 "atlas-saga-orchestrator/kafka/message/marriage"
+	monster2 "atlas-saga-orchestrator/kafka/message/monster"
+	npc2 "atlas-saga-orchestrator/kafka/message/npc"
+	reactor2 "atlas-saga-orchestrator/kafka/message/reactor"
+	session2 "atlas-saga-orchestrator/kafka/message/session"
+	skill2 "atlas-saga-orchestrator/kafka/message/skill"
+	storage2 "atlas-saga-orchestrator/kafka/message/storage"
+	worldstate2 "atlas-saga-orchestrator/kafka/message/worldstate"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/Chronicle20/atlas-rest/server"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrUnsupportedAction is returned when a saga has a step whose action this version of the orchestrator does not support,
+// such as one added by a newer version which a content service adopted ahead of the orchestrator's rollout
+var ErrUnsupportedAction = errors.New("unsupported action")
+
+// Outcomes of a correlation event for the step awaiting it
+const (
+	CorrelationCompletes = "completes" // The event completes the step
+	CorrelationFails     = "fails"     // The event fails the step, with the error code it reports, if any
+)
+
+// CorrelationEvent is a status event the orchestrator correlates with the step awaiting it by its transaction ID
+type CorrelationEvent struct {
+	Topic   string `json:"topic"`   // Topic is the token of the topic the event is consumed from, configured in the environment
+	Type    string `json:"type"`    // Type of the event
+	Outcome string `json:"outcome"` // Outcome of the event for the step
+}
+
+func completes(topic string, eventType string) CorrelationEvent {
+	return CorrelationEvent{Topic: topic, Type: eventType, Outcome: CorrelationCompletes}
+}
+
+func fails(topic string, eventType string) CorrelationEvent {
+	return CorrelationEvent{Topic: topic, Type: eventType, Outcome: CorrelationFails}
+}
+
+var (
+	characterError   = fails(character2.EnvEventTopicCharacterStatus, character2.StatusEventTypeError)
+	compartmentError = fails(compartment2.EnvEventTopicStatus, compartment2.StatusEventTypeError)
+	assetCreated     = []CorrelationEvent{
+		completes(asset2.EnvEventTopicStatus, asset2.StatusEventTypeCreated),
+		completes(asset2.EnvEventTopicStatus, asset2.StatusEventTypeQuantityChanged),
+		fails(compartment2.EnvEventTopicStatus, compartment2.StatusEventTypeCreationFailed),
+		compartmentError,
+	}
+	assetDestroyed = []CorrelationEvent{
+		completes(compartment2.EnvEventTopicStatus, compartment2.StatusEventTypeDeleted),
+		completes(asset2.EnvEventTopicStatus, asset2.StatusEventTypeQuantityChanged),
+		compartmentError,
+	}
+	assetMoved = []CorrelationEvent{completes(asset2.EnvEventTopicStatus, asset2.StatusEventTypeMoved), compartmentError}
+	mapChanged = []CorrelationEvent{completes(character2.EnvEventTopicCharacterStatus, character2.StatusEventTypeMapChanged), characterError}
+)
+
+// characterEvent returns the correlation events of an action completed by a character status event of the type
+func characterEvent(eventType string) []CorrelationEvent {
+	return []CorrelationEvent{completes(character2.EnvEventTopicCharacterStatus, eventType), characterError}
+}
+
+// compartmentEvent returns the correlation events of an action completed by a compartment status event of the type
+func compartmentEvent(eventType string) []CorrelationEvent {
+	return []CorrelationEvent{completes(compartment2.EnvEventTopicStatus, eventType), compartmentError}
+}
+
+// serviceEvent returns the correlation events of an action completed by a status event of the type, and failed by an
+// ERROR event, on the service's status topic
+func serviceEvent(topic string, eventType string) []CorrelationEvent {
+	return []CorrelationEvent{completes(topic, eventType), fails(topic, "ERROR")}
+}
+
+// correlationEvents are the status events correlated with the steps taking each action. Actions absent are completed by
+// the orchestrator itself, such as those calling a service's REST API or adding steps to the saga.
+var correlationEvents = map[Action][]CorrelationEvent{
+	AwardInventory:               assetCreated,
+	AwardAsset:                   assetCreated,
+	AwardAssetIf:                 assetCreated,
+	RestoreAsset:                 assetCreated,
+	IssueTransportTicket:         assetCreated,
+	CreateAndEquipAsset:          assetCreated,
+	AwardExperience:              characterEvent(character2.StatusEventTypeExperienceChanged),
+	AwardLevel:                   characterEvent(character2.StatusEventTypeLevelChanged),
+	AwardMesos:                   characterEvent(character2.StatusEventTypeMesoChanged),
+	DeductMesos:                  characterEvent(character2.StatusEventTypeMesoChanged),
+	AdjustPopularity:             characterEvent(character2.StatusEventTypeFameChanged),
+	ChangeJob:                    characterEvent(character2.StatusEventTypeJobChanged),
+	TransferCharacter:            characterEvent(character2.StatusEventTypeAccountChanged),
+	RollbackCharacterToSnapshot:  characterEvent(character2.StatusEventTypeRolledBack),
+	ApplyCharacterExpPenalty:     characterEvent(character2.StatusEventTypeExperienceDeducted),
+	UpdateCharacterResource:      characterEvent(character2.StatusEventTypeResourceChanged),
+	RestoreCharacter:             characterEvent(character2.StatusEventTypeStatsRestored),
+	CharacterExperienceLock:      characterEvent(character2.StatusEventTypeExperienceLocked),
+	CharacterExperienceUnlock:    characterEvent(character2.StatusEventTypeExperienceUnlocked),
+	WarpToRandomPortal:           mapChanged,
+	WarpToPortal:                 mapChanged,
+	ScheduleWarp:                 mapChanged,
+	CreateCharacter:              append(characterEvent(character2.StatusEventTypeCreated), fails(character2.EnvEventTopicCharacterStatus, character2.StatusEventTypeCreationFailed)),
+	DestroyAsset:                 assetDestroyed,
+	VerifyAndConsumeTicket:       assetDestroyed,
+	ConfiscateAsset:              compartmentEvent(compartment2.StatusEventTypeDeleted),
+	EquipAsset:                   assetMoved,
+	UnequipAsset:                 assetMoved,
+	EquipAssetByTemplate:         assetMoved,
+	PetEquip:                     assetMoved,
+	PetUnequip:                   assetMoved,
+	ModifyInventoryItemPosition:  assetMoved,
+	RestoreInventorySnapshot:     compartmentEvent(compartment2.StatusEventTypeSnapshotRestored),
+	ApplyDurabilityPenalty:       compartmentEvent(compartment2.StatusEventTypeDurabilityChanged),
+	ModifyAssetExpiration:        compartmentEvent(compartment2.StatusEventTypeExpirationChanged),
+	ApplyHammer:                  compartmentEvent(compartment2.StatusEventTypeUpgradeSlotsChanged),
+	SealAsset:                    compartmentEvent(compartment2.StatusEventTypeReserved),
+	UnsealAsset:                  compartmentEvent(compartment2.StatusEventTypeReservationCancelled),
+	CreateSkill:                  {completes(skill2.EnvStatusEventTopic, skill2.StatusEventTypeCreated)},
+	UpdateSkill:                  {completes(skill2.EnvStatusEventTopic, skill2.StatusEventTypeUpdated)},
+	ResetSkillCooldowns:          {completes(skill2.EnvStatusEventTopic, skill2.StatusEventTypeCooldownsReset)},
+	CharacterBuffCleanse:         {completes(buff2.EnvStatusEventTopic, buff2.StatusEventTypeCancelledAll)},
+	RequestGuildName:             serviceEvent(guild2.EnvStatusEventTopic, guild2.StatusEventTypeRequestAgreement),
+	RequestGuildEmblem:           serviceEvent(guild2.EnvStatusEventTopic, guild2.StatusEventTypeEmblemUpdated),
+	RequestGuildDisband:          serviceEvent(guild2.EnvStatusEventTopic, guild2.StatusEventTypeDisbanded),
+	RequestGuildCapacityIncrease: serviceEvent(guild2.EnvStatusEventTopic, guild2.StatusEventTypeCapacityUpdated),
+	CreateInvite: {
+		completes(invite2.EnvEventStatusTopic, invite2.EventInviteStatusTypeCreated),
+		completes(invite2.EnvEventStatusTopic, invite2.EventInviteStatusTypeAccepted),
+		fails(invite2.EnvEventStatusTopic, invite2.EventInviteStatusTypeRejected),
+	},
+	AdjustNpcShopStock:         serviceEvent(worldstate2.EnvStatusEventTopic, worldstate2.StatusEventTypeShopStockAdjusted),
+	SetWorldEventFlag:          serviceEvent(worldstate2.EnvStatusEventTopic, worldstate2.StatusEventTypeEventFlagSet),
+	ApplyWorldEventBuff:        serviceEvent(worldstate2.EnvStatusEventTopic, worldstate2.StatusEventTypeEventBuffApplied),
+	RemoveWorldEventBuff:       serviceEvent(worldstate2.EnvStatusEventTopic, worldstate2.StatusEventTypeEventBuffRemoved),
+	ConsumeCoupon:              serviceEvent(coupon2.EnvStatusEventTopic, coupon2.StatusEventTypeConsumed),
+	CreateAccountCharacterSlot: serviceEvent(account2.EnvStatusEventTopic, account2.StatusEventTypeCharacterSlotsChanged),
+	GrantPremiumTime:           serviceEvent(account2.EnvStatusEventTopic, account2.StatusEventTypePremiumTimeChanged),
+	GrantStorageCapacity:       serviceEvent(storage2.EnvStatusEventTopic, storage2.StatusEventTypeCapacityChanged),
+	DissolveMarriage:           serviceEvent(marriage2.EnvStatusEventTopic, marriage2.StatusEventTypeDivorced),
+	AdjustReactorState:         serviceEvent(reactor2.EnvEventStatusTopic, reactor2.StatusEventTypeStateChanged),
+	HitReactor:                 serviceEvent(reactor2.EnvEventStatusTopic, reactor2.StatusEventTypeHit),
+	SpawnEscort:                serviceEvent(npc2.EnvEventStatusTopic, npc2.StatusEventTypeEscortSpawned),
+	AwaitEscort: {
+		completes(npc2.EnvEventStatusTopic, npc2.StatusEventTypeEscortArrived),
+		fails(npc2.EnvEventStatusTopic, npc2.StatusEventTypeEscortFailed),
+	},
+	UpdateCharacterAlignment: serviceEvent(faction2.EnvStatusEventTopic, faction2.StatusEventTypePointsChanged),
+	ResetInstanceCooldown:    serviceEvent(instance2.EnvStatusEventTopic, instance2.StatusEventTypeCooldownReset),
+	ApplyTitleBuffOnLogin:    serviceEvent(session2.EnvStatusEventTopic, session2.StatusEventTypeDeferredEffectApplied),
+	AwaitKillCount:           {completes(monster2.EnvEventTopicMonsterStatus, monster2.EventMonsterStatusKilled)},
+	AwaitEvent:               {completes(character2.EnvEventTopicCharacterStatus, character2.StatusEventTypeMapChanged)},
+}
+
+// compensableActions are the actions whose steps are compensated should they fail, reversing whatever of their effect was
+// applied. Actions adding steps to the saga are included when the steps they add are compensated together.
+var compensableActions = []Action{
+	EquipAsset, UnequipAsset, ModifyInventoryItemPosition, CreateCharacter, CreateAndEquipAsset, CharacterBuffCleanse,
+	TransferCharacter, AdjustNpcShopStock, SetWorldEventFlag, ConsumeCoupon, ApplyCharacterExpPenalty,
+	ApplyDurabilityPenalty, CreateAccountCharacterSlot, ModifyAssetExpiration, ApplyHammer, DeductMesos,
+	VerifyAndConsumeTicket, AdjustReactorState, UpdateCharacterResource, GrantPremiumTime, DissolveMarriage, SealAsset,
+	UnsealAsset, IssueTransportTicket, ScheduleWarp, SpawnEscort, AwaitEscort, UpdateCharacterAlignment,
+	ResetInstanceCooldown, ApplyTitleBuffOnLogin, ApplyWorldEventBuff, CharacterExperienceLock, CharacterExperienceUnlock,
+	EquipAssetByTemplate, GrantStorageCapacity,
+	ApplyEquipmentPreset, StripEquipment, GrantMount, AwardPartyExperience, ToggleCharacterAbility, ChangeJob,
+	PetEquip, PetUnequip, SetQuestTimer,
+}
+
+// ActionDescriptor describes how the orchestrator executes the steps taking an action
+type ActionDescriptor struct {
+	Action            Action             `json:"action"`            // Action described
+	PayloadSchema     map[string]any     `json:"payloadSchema"`     // PayloadSchema is the JSON schema of the step's payload
+	Compensable       bool               `json:"compensable"`       // Compensable is whether the step is compensated should it fail
+	CorrelationEvents []CorrelationEvent `json:"correlationEvents"` // CorrelationEvents are the status events completing or failing the step. When empty, the orchestrator completes the step itself.
+}
+
+// ActionManifest is the manifest of the actions supported by this version of the orchestrator, so content services may
+// check a saga will be accepted before creating it, and build its steps without reading the orchestrator's source
+type ActionManifest struct {
+	Actions []ActionDescriptor `json:"actions"` // Actions supported, in name order
+}
+
+var actionManifest ActionManifest
+var actionManifestOnce sync.Once
+
+// GetActionManifest returns the manifest of the actions supported by this version of the orchestrator, generated from
+// the payload types, compensations and correlation events registered for each
+func GetActionManifest() ActionManifest {
+	actionManifestOnce.Do(func() {
+		actions := SupportedActions()
+		ds := make([]ActionDescriptor, 0, len(actions))
+		for _, a := range actions {
+			d := ActionDescriptor{
+				Action:            a,
+				PayloadSchema:     map[string]any{"$schema": JSONSchemaDialect},
+				Compensable:       slices.Contains(compensableActions, a),
+				CorrelationEvents: make([]CorrelationEvent, 0),
+			}
+			if t, ok := PayloadType(a); ok {
+				d.PayloadSchema = GenerateJSONSchema(t)
+			}
+			d.CorrelationEvents = append(d.CorrelationEvents, correlationEvents[a]...)
+			ds = append(ds, d)
+		}
+		actionManifest = ActionManifest{Actions: ds}
+	})
+	return actionManifest
+}
+
+// SupportedActions returns the actions supported by this version of the orchestrator, in name order
+func SupportedActions() []Action {
+	actions := slices.Clone(Actions)
+	slices.Sort(actions)
+	return actions
+}
+
+// UnsupportedActions returns the actions which this version of the orchestrator does not support, once each, in the
+// order given
+func UnsupportedActions(actions []Action) []Action {
+	r := make([]Action, 0)
+	for _, a := range actions {
+		if a != "" && !slices.Contains(Actions, a) && !slices.Contains(r, a) {
+			r = append(r, a)
+		}
+	}
+	return r
+}
+
+// UnsupportedActionError is a JSON:API error object describing a step whose action is not supported. The pointer locates
+// the step's action in the request, if known.
+func UnsupportedActionError(pointer string, stepId string, action Action) FieldError {
+	return FieldError{
+		Status: strconv.Itoa(http.StatusUnprocessableEntity),
+		Code:   FieldErrorUnsupported,
+		Title:  "Unsupported action",
+		Detail: fmt.Sprintf("action [%s] of step [%s] is not supported by this orchestrator", action, stepId),
+		Source: FieldErrorPointer{Pointer: pointer},
+	}
+}
+
+// UnsupportedActionsMeta is the meta of a response rejecting a saga with unsupported actions, listing them alongside the
+// orchestrator's manifest, so the initiator may tell which steps to hold back until the orchestrator is upgraded
+type UnsupportedActionsMeta struct {
+	UnsupportedActions []Action `json:"unsupportedActions"` // Actions of the saga which are not supported
+	SupportedActions   []Action `json:"supportedActions"`   // Actions which are supported, in name order
+}
+
+// WriteUnsupportedActions responds 422 with the error objects of the steps whose actions are not supported, and meta
+// listing the unsupported actions and the supported-action manifest
+func WriteUnsupportedActions(l logrus.FieldLogger, w http.ResponseWriter, fes []FieldError, unsupported []Action) {
+	l.Debugf("Rejecting saga with [%d] unsupported actions.", len(unsupported))
+	w.Header().Set("Content-Type", "application/vnd.api+json")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	doc := struct {
+		Errors []FieldError           `json:"errors"`
+		Meta   UnsupportedActionsMeta `json:"meta"`
+	}{
+		Errors: fes,
+		Meta:   UnsupportedActionsMeta{UnsupportedActions: unsupported, SupportedActions: SupportedActions()},
+	}
+	if err := json.NewEncoder(w).Encode(doc); err != nil {
+		l.WithError(err).Error("Unable to write unsupported actions.")
+	}
+}
+
+// InitActionResource registers the supported-action manifest route with the router. Supported actions do not vary by
+// tenant, so no tenant is required.
+func InitActionResource() server.RouteInitializer {
+	return func(r *mux.Router, l logrus.FieldLogger) {
+		r.HandleFunc("/actions", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if err := json.NewEncoder(w).Encode(GetActionManifest()); err != nil {
+				l.WithError(err).Error("Unable to write action manifest.")
+			}
+		}).Methods(http.MethodGet)
+	}
+}
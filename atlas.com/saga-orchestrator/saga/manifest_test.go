@@ -0,0 +1,137 @@
+package saga
+
+import (
+	"atlas-saga-orchestrator/kafka/producer"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	producer2 "github.com/Chronicle20/atlas-kafka/producer"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/segmentio/kafka-go"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUnsupportedActions tests that the actions not supported are reported once each, in the order given
+func TestUnsupportedActions(t *testing.T) {
+	assert.Empty(t, UnsupportedActions([]Action{AwardMesos, AwardAsset, ""}))
+	assert.Equal(t, []Action{"award_pet", "hatch_egg"}, UnsupportedActions([]Action{AwardMesos, "award_pet", "hatch_egg", "award_pet"}))
+}
+
+// TestActionResource tests that the manifest describes every supported action, in name order
+func TestActionResource(t *testing.T) {
+	l, _ := test.NewNullLogger()
+	router := mux.NewRouter()
+	InitActionResource()(router, l)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/actions", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var m ActionManifest
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &m))
+	require.Len(t, m.Actions, len(Actions))
+	actions := make([]Action, 0, len(m.Actions))
+	for _, d := range m.Actions {
+		actions = append(actions, d.Action)
+	}
+	assert.True(t, slices.IsSorted(actions))
+
+	i := slices.IndexFunc(m.Actions, func(d ActionDescriptor) bool { return d.Action == DeductMesos })
+	require.NotEqual(t, -1, i)
+	d := m.Actions[i]
+	assert.True(t, d.Compensable)
+	assert.Equal(t, JSONSchemaDialect, d.PayloadSchema["$schema"])
+	assert.Equal(t, "object", d.PayloadSchema["type"])
+	assert.Contains(t, d.PayloadSchema["properties"], "amount")
+	assert.Contains(t, d.PayloadSchema["required"], "characterId")
+	assert.Equal(t, []CorrelationEvent{
+		{Topic: "EVENT_TOPIC_CHARACTER_STATUS", Type: "MESO_CHANGED", Outcome: CorrelationCompletes},
+		{Topic: "EVENT_TOPIC_CHARACTER_STATUS", Type: "ERROR", Outcome: CorrelationFails},
+	}, d.CorrelationEvents)
+}
+
+// TestActionManifestPayloadSchemas tests that the payload of every action is described by a schema of its own
+func TestActionManifestPayloadSchemas(t *testing.T) {
+	for _, d := range GetActionManifest().Actions {
+		t.Run(string(d.Action), func(t *testing.T) {
+			assert.Equal(t, "object", d.PayloadSchema["type"])
+			assert.NotNil(t, d.CorrelationEvents)
+		})
+	}
+}
+
+// TestGenerateJSONSchema tests that schemas describe fields by their json tags, requiring those without omitempty, and
+// describe types nested within themselves as objects only
+func TestGenerateJSONSchema(t *testing.T) {
+	type node struct {
+		Name     string            `json:"name"`
+		Children []node            `json:"children,omitempty"`
+		Labels   map[string]string `json:"labels,omitempty"`
+		At       *time.Time        `json:"at,omitempty"`
+		Id       uuid.UUID         `json:"id"`
+		Count    uint32            `json:"count"`
+		Ignored  string            `json:"-"`
+	}
+
+	s := GenerateJSONSchema(reflect.TypeOf(node{}))
+	assert.Equal(t, map[string]any{
+		"$schema": JSONSchemaDialect,
+		"type":    "object",
+		"properties": map[string]any{
+			"name":     map[string]any{"type": "string"},
+			"children": map[string]any{"type": "array", "items": map[string]any{"type": "object"}},
+			"labels":   map[string]any{"type": "object", "additionalProperties": map[string]any{"type": "string"}},
+			"at":       map[string]any{"type": "string", "format": "date-time"},
+			"id":       map[string]any{"type": "string", "format": "uuid"},
+			"count":    map[string]any{"type": "integer", "minimum": 0},
+		},
+		"required": []string{"name", "id", "count"},
+	}, s)
+}
+
+// TestActionManifestCompensation tests that the actions the manifest describes as compensable are those the compensator
+// has compensation for. Actions adding steps to the saga compensate the steps they add instead.
+func TestActionManifestCompensation(t *testing.T) {
+	_, ctx := setupContext()
+	ctx = producer.WithProvider(ctx, func(token string) producer2.MessageProducer {
+		return func(provider model.Provider[[]kafka.Message]) error {
+			return nil
+		}
+	})
+	expanding := []Action{ApplyEquipmentPreset, StripEquipment, GrantMount, AwardPartyExperience, ToggleCharacterAbility}
+
+	for _, a := range Actions {
+		if slices.Contains(expanding, a) {
+			continue
+		}
+		t.Run(string(a), func(t *testing.T) {
+			var payload any
+			if pt, ok := PayloadType(a); ok {
+				payload = reflect.Zero(pt).Interface()
+			}
+			s := NewBuilder().SetSagaType(InventoryTransaction).AddStep("failed", Failed, a, payload).Build()
+
+			l, hook := test.NewNullLogger()
+			l.SetLevel(logrus.DebugLevel)
+			func() {
+				// Compensation of a zero payload may fail, or panic, once under way
+				defer func() { _ = recover() }()
+				_ = NewCompensator(l, ctx).CompensateFailedStep(s)
+			}()
+			fallback := slices.ContainsFunc(hook.AllEntries(), func(e *logrus.Entry) bool {
+				return e.Message == "No compensation logic available for action type."
+			})
+			assert.Equal(t, !fallback, slices.Contains(compensableActions, a))
+		})
+	}
+}
@@ -3,6 +3,7 @@ package saga
 import (
 	"atlas-saga-orchestrator/validation"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/Chronicle20/atlas-constants/channel"
 	"github.com/Chronicle20/atlas-constants/field"
@@ -10,6 +11,7 @@ import (
 	_map "github.com/Chronicle20/atlas-constants/map"
 	"github.com/Chronicle20/atlas-constants/world"
 	"github.com/google/uuid"
+	"strings"
 	"time"
 )
 
@@ -18,18 +20,133 @@ type Type string
 
 // Constants for different saga types
 const (
-	InventoryTransaction Type = "inventory_transaction"
-	QuestReward          Type = "quest_reward"
-	TradeTransaction     Type = "trade_transaction"
-	CharacterCreation    Type = "character_creation"
+	InventoryTransaction  Type = "inventory_transaction"
+	QuestReward           Type = "quest_reward"
+	TradeTransaction      Type = "trade_transaction"
+	GuildManagement       Type = "guild_management"
+	CharacterCreation     Type = "character_creation"
+	MinigameReward        Type = "minigame_reward"
+	AccountMerge          Type = "account_merge"
+	ItemRestoration       Type = "item_restoration"
+	CharacterRollback     Type = "character_rollback"
+	CouponRedemption      Type = "coupon_redemption"
+	DeathPenalty          Type = "death_penalty"
+	CharacterSlotPurchase Type = "character_slot_purchase"
+	GuildEmblemPurchase   Type = "guild_emblem_purchase"
+	Divorce               Type = "divorce"
+	DisputeHold           Type = "dispute_hold"
+	Transport             Type = "transport"
+	EscortQuest           Type = "escort_quest"
+	EventShopSettlement   Type = "event_shop_settlement"
+	JobReset              Type = "job_reset"
+	TutorialCompletion    Type = "tutorial_completion"
 )
 
 // Saga represents the entire saga transaction.
 type Saga struct {
-	TransactionId uuid.UUID   `json:"transactionId"` // Unique ID for the transaction
-	SagaType      Type        `json:"sagaType"`      // Type of the saga (e.g., inventory_transaction)
-	InitiatedBy   string      `json:"initiatedBy"`   // Who initiated the saga (e.g., NPC ID, user)
-	Steps         []Step[any] `json:"steps"`         // List of steps in the saga
+	TransactionId    uuid.UUID            `json:"transactionId"`              // Unique ID for the transaction
+	SagaType         Type                 `json:"sagaType"`                   // Type of the saga (e.g., inventory_transaction)
+	InitiatedBy      string               `json:"initiatedBy"`                // Who initiated the saga (e.g., NPC ID, user)
+	Labels           map[string]string    `json:"labels,omitempty"`           // Arbitrary labels for querying cohorts of sagas (e.g., event=halloween2025)
+	Variables        Variables            `json:"variables,omitempty"`        // Values referenced by step payload templates (e.g., characterId=12345)
+	Steps            []Step[any]          `json:"steps"`                      // List of steps in the saga
+	RequiresApproval bool                 `json:"requiresApproval,omitempty"` // Whether a second operator must approve the saga before it starts
+	Hold             Hold                 `json:"hold,omitempty"`             // Hold pausing the saga until an operator reviews it, if any
+	HoldReason       string               `json:"holdReason,omitempty"`       // Reason the saga is held
+	Reviews          []Review             `json:"reviews,omitempty"`          // Operator decisions on holds of the saga, recorded for audit
+	Notes            []Note               `json:"notes,omitempty"`            // Comments left by operators handling the saga, in order
+	OnComplete       *OnComplete          `json:"onComplete,omitempty"`       // Follow-up saga initiated from a template when the saga completes, if any
+	CoalesceAwards   bool                 `json:"coalesceAwards,omitempty"`   // Whether identical award_asset steps of stackable items are coalesced when the saga is created
+	Receipt          *CompensationReceipt `json:"receipt,omitempty"`          // Receipt of the saga's compensation, once compensated
+}
+
+// Hold is the reason a saga is paused until an operator approves, rejects or resolves it, or, when awaiting login,
+// until the character its current step affects logs in
+type Hold string
+
+// Constants for the holds of a saga
+const (
+	PendingReview      Hold = "pending_review"      // An award step was flagged by a reward policy
+	PendingApproval    Hold = "pending_approval"    // The saga requires approval by an operator other than its initiator
+	PendingResolution  Hold = "pending_resolution"  // A resolve_dispute step awaits an operator's resolution of the dispute
+	AwaitingLogin      Hold = "awaiting_login"      // A step affecting an offline character is queued until the character logs in
+	ManualIntervention Hold = "manual_intervention" // Progressing the saga panicked, so it is quarantined until an operator reviews it
+)
+
+// Review records an operator's decision on a held saga
+type Review struct {
+	Hold       Hold      `json:"hold"`                 // Hold which was reviewed
+	StepId     string    `json:"stepId,omitempty"`     // StepId held, if the hold concerned a single step
+	Reason     string    `json:"reason,omitempty"`     // Reason the saga was held
+	Approved   bool      `json:"approved"`             // Whether the saga was approved to continue
+	Resolution string    `json:"resolution,omitempty"` // Resolution chosen, if the hold awaited the resolution of a dispute
+	Reviewer   string    `json:"reviewer"`             // Reviewer identifies the operator who made the decision
+	Comment    string    `json:"comment,omitempty"`    // Comment left by the reviewer
+	ReviewedAt time.Time `json:"reviewedAt"`           // Timestamp of the decision
+}
+
+// Note is a comment left on a saga by an operator, such as while coordinating the handling of a stuck saga
+type Note struct {
+	Author    string    `json:"author"`    // Author identifies the operator who left the note
+	Comment   string    `json:"comment"`   // Comment left by the author
+	CreatedAt time.Time `json:"createdAt"` // Timestamp of the note
+}
+
+// Held returns whether the saga is paused awaiting review
+func (s *Saga) Held() bool {
+	return s.Hold != ""
+}
+
+// Approved returns whether the saga has been approved to start, when it requires approval
+func (s *Saga) Approved() bool {
+	for _, r := range s.Reviews {
+		if r.Approved && r.Hold == PendingApproval {
+			return true
+		}
+	}
+	return false
+}
+
+// Reviewed returns whether a step has been approved by a reviewer, so it is not held again
+func (s *Saga) Reviewed(stepId string) bool {
+	for _, r := range s.Reviews {
+		if r.Approved && r.StepId == stepId {
+			return true
+		}
+	}
+	return false
+}
+
+// LabelSeparator separates the key and value of a label when expressed as a single string (e.g., event:halloween2025)
+const LabelSeparator = ":"
+
+// ParseLabel parses a label expressed as key:value
+func ParseLabel(label string) (string, string, error) {
+	key, value, ok := strings.Cut(label, LabelSeparator)
+	if !ok || key == "" {
+		return "", "", fmt.Errorf("label '%s' must be of the form key%svalue", label, LabelSeparator)
+	}
+	return key, value, nil
+}
+
+// ValidateLabels checks label keys are non-empty and do not contain the label separator
+func (s *Saga) ValidateLabels() error {
+	for k := range s.Labels {
+		if k == "" || strings.Contains(k, LabelSeparator) {
+			return fmt.Errorf("invalid label key '%s'", k)
+		}
+	}
+	return nil
+}
+
+// HasLabels returns whether the saga carries every one of the given labels
+func (s *Saga) HasLabels(labels map[string]string) bool {
+	for k, v := range labels {
+		if lv, ok := s.Labels[k]; !ok || lv != v {
+			return false
+		}
+	}
+	return true
 }
 
 func (s *Saga) Failing() bool {
@@ -76,6 +193,90 @@ func (s *Saga) FindEarliestPendingStepIndex() int {
 	return earliestPendingIndex
 }
 
+// FindStepIndex returns the index of the step with the given step ID
+// Returns -1 if no such step is found
+func (s *Saga) FindStepIndex(stepId string) int {
+	for i := 0; i < len(s.Steps); i++ {
+		if s.Steps[i].StepId == stepId {
+			return i
+		}
+	}
+	return -1
+}
+
+// FindErrorHandler returns the handler declared by the step at the given index for the error code
+func (s *Saga) FindErrorHandler(index int, errorCode string) (ErrorHandler, bool) {
+	if index < 0 || index >= len(s.Steps) || errorCode == "" {
+		return ErrorHandler{}, false
+	}
+	for _, h := range s.Steps[index].OnError {
+		if h.ErrorCode == errorCode {
+			return h, true
+		}
+	}
+	return ErrorHandler{}, false
+}
+
+// RecordStepAttempt appends a dispatch attempt to the step at the given index, returning the recorded attempt
+func (s *Saga) RecordStepAttempt(index int, dispatchedAt time.Time) (StepAttempt, error) {
+	if index < 0 || index >= len(s.Steps) {
+		return StepAttempt{}, fmt.Errorf("invalid step index: %d", index)
+	}
+	st := &s.Steps[index]
+	a := StepAttempt{
+		Attempt:      len(st.Attempts) + 1,
+		DispatchedAt: dispatchedAt,
+	}
+	a.CommandKey = fmt.Sprintf("%s#%d", st.StepId, a.Attempt)
+	st.Attempts = append(append([]StepAttempt{}, st.Attempts...), a)
+	return a, nil
+}
+
+// RecordStepAttemptError records the error reported by a failure event against the latest attempt of the step at the given index
+func (s *Saga) RecordStepAttemptError(index int, errorCode string, errorMessage string) error {
+	if index < 0 || index >= len(s.Steps) {
+		return fmt.Errorf("invalid step index: %d", index)
+	}
+	st := &s.Steps[index]
+	if len(st.Attempts) == 0 {
+		return fmt.Errorf("step %s has not been attempted", st.StepId)
+	}
+	st.Attempts = append([]StepAttempt{}, st.Attempts...)
+	st.Attempts[len(st.Attempts)-1].ErrorCode = errorCode
+	st.Attempts[len(st.Attempts)-1].ErrorMessage = errorMessage
+	return nil
+}
+
+// AbandonStepAttempt records the error against the latest attempt of the step at the given index as the orchestrator
+// giving up awaiting its outcome, rather than a failure event reporting it
+func (s *Saga) AbandonStepAttempt(index int, errorCode string, errorMessage string) error {
+	if err := s.RecordStepAttemptError(index, errorCode, errorMessage); err != nil {
+		return err
+	}
+	st := &s.Steps[index]
+	st.Attempts[len(st.Attempts)-1].Abandoned = true
+	return nil
+}
+
+// ReportedError returns whether a failure event reported an error against the step's latest attempt, in which case its
+// action did not take effect. Attempts the orchestrator abandoned may have taken effect, so have no error reported.
+func (st Step[T]) ReportedError() bool {
+	if len(st.Attempts) == 0 {
+		return false
+	}
+	a := st.Attempts[len(st.Attempts)-1]
+	return a.ErrorCode != "" && !a.Abandoned
+}
+
+// ErrorCode returns the error code recorded against the step's latest attempt, whether reported by a failure event or
+// recorded by the orchestrator abandoning it
+func (st Step[T]) ErrorCode() string {
+	if len(st.Attempts) == 0 {
+		return ""
+	}
+	return st.Attempts[len(st.Attempts)-1].ErrorCode
+}
+
 // SetStepStatus sets the status of a step at the given index with validation
 func (s *Saga) SetStepStatus(index int, status Status) error {
 	if index < 0 || index >= len(s.Steps) {
@@ -270,16 +471,215 @@ const (
 	CreateInvite                 Action = "create_invite"
 	CreateCharacter              Action = "create_character"
 	CreateAndEquipAsset          Action = "create_and_equip_asset"
+	SetQuestTimer                Action = "set_quest_timer"
+	AdjustPopularity             Action = "adjust_popularity"
+	ResolvePrizeTable            Action = "resolve_prize_table"
+	CharacterBuffCleanse         Action = "character_buff_cleanse"
+	ResetSkillCooldowns          Action = "reset_skill_cooldowns"
+	ModifyInventoryItemPosition  Action = "modify_inventory_item_position"
+	ApplyEquipmentPreset         Action = "apply_equipment_preset"
+	TransferCharacter            Action = "transfer_character"
+	VerifyAccountMerge           Action = "verify_account_merge"
+	RestoreAsset                 Action = "restore_asset"
+	RollbackCharacterToSnapshot  Action = "rollback_character_to_snapshot"
+	RestoreInventorySnapshot     Action = "restore_inventory_snapshot"
+	AdjustNpcShopStock           Action = "adjust_npc_shop_stock"
+	SetWorldEventFlag            Action = "set_world_event_flag"
+	ValidateCoupon               Action = "validate_coupon"
+	ConsumeCoupon                Action = "consume_coupon"
+	ApplyCharacterExpPenalty     Action = "apply_character_exp_penalty"
+	ApplyDurabilityPenalty       Action = "apply_durability_penalty"
+	SetVariable                  Action = "set_variable"
+	HttpRequest                  Action = "http_request"
+	CreateAccountCharacterSlot   Action = "create_account_character_slot"
+	EmitAnalyticsEvent           Action = "emit_analytics_event"
+	ModifyAssetExpiration        Action = "modify_asset_expiration"
+	ApplyHammer                  Action = "apply_hammer"
+	DeductMesos                  Action = "deduct_mesos"
+	ForEach                      Action = "for_each"
+	VerifyAndConsumeTicket       Action = "verify_and_consume_ticket"
+	AwaitKillCount               Action = "await_kill_count"
+	AdjustReactorState           Action = "adjust_reactor_state"
+	HitReactor                   Action = "hit_reactor"
+	UpdateCharacterResource      Action = "update_character_resource"
+	AwardAssetIf                 Action = "award_asset_if"
+	GrantPremiumTime             Action = "grant_premium_time"
+	ValidateDivorce              Action = "validate_divorce"
+	DissolveMarriage             Action = "dissolve_marriage"
+	SealAsset                    Action = "seal_asset"
+	UnsealAsset                  Action = "unseal_asset"
+	ConfiscateAsset              Action = "confiscate_asset"
+	ResolveDispute               Action = "resolve_dispute"
+	IssueTransportTicket         Action = "issue_transport_ticket"
+	ScheduleWarp                 Action = "schedule_warp"
+	SpawnEscort                  Action = "spawn_escort"
+	AwaitEscort                  Action = "await_escort"
+	UpdateCharacterAlignment     Action = "update_character_alignment"
+	ResetInstanceCooldown        Action = "reset_instance_cooldown"
+	AuditInventory               Action = "audit_inventory"
+	ApplyTitleBuffOnLogin        Action = "apply_title_buff_on_login"
+	GrantMount                   Action = "grant_mount"
+	ApplyWorldEventBuff          Action = "apply_world_event_buff"
+	RemoveWorldEventBuff         Action = "remove_world_event_buff"
+	SettleEventCurrency          Action = "settle_event_currency"
+	CharacterExperienceLock      Action = "character_experience_lock"
+	CharacterExperienceUnlock    Action = "character_experience_unlock"
+	NpcConversationState         Action = "npc_conversation_state"
+	EquipAssetByTemplate         Action = "equip_asset_by_template"
+	AwardPartyExperience         Action = "award_exp_to_party_members"
+	ToggleCharacterAbility       Action = "toggle_character_ability"
+	GrantStorageCapacity         Action = "grant_storage_capacity"
+	StripEquipment               Action = "strip_equipment"
+	SnapshotCharacter            Action = "snapshot_character"
+	RestoreCharacter             Action = "restore_character"
+	AwaitEvent                   Action = "await_event"
+	Notify                       Action = "notify"
+	PetEquip                     Action = "pet_equip"
+	PetUnequip                   Action = "pet_unequip"
 )
 
+// Actions are every action a step may take
+var Actions = []Action{
+	AwardInventory,
+	AwardAsset,
+	AwardExperience,
+	AwardLevel,
+	AwardMesos,
+	WarpToRandomPortal,
+	WarpToPortal,
+	DestroyAsset,
+	EquipAsset,
+	UnequipAsset,
+	ChangeJob,
+	CreateSkill,
+	UpdateSkill,
+	ValidateCharacterState,
+	RequestGuildName,
+	RequestGuildEmblem,
+	RequestGuildDisband,
+	RequestGuildCapacityIncrease,
+	CreateInvite,
+	CreateCharacter,
+	CreateAndEquipAsset,
+	SetQuestTimer,
+	AdjustPopularity,
+	ResolvePrizeTable,
+	CharacterBuffCleanse,
+	ResetSkillCooldowns,
+	ModifyInventoryItemPosition,
+	ApplyEquipmentPreset,
+	TransferCharacter,
+	VerifyAccountMerge,
+	RestoreAsset,
+	RollbackCharacterToSnapshot,
+	RestoreInventorySnapshot,
+	AdjustNpcShopStock,
+	SetWorldEventFlag,
+	ValidateCoupon,
+	ConsumeCoupon,
+	ApplyCharacterExpPenalty,
+	ApplyDurabilityPenalty,
+	SetVariable,
+	HttpRequest,
+	CreateAccountCharacterSlot,
+	EmitAnalyticsEvent,
+	ModifyAssetExpiration,
+	ApplyHammer,
+	DeductMesos,
+	ForEach,
+	VerifyAndConsumeTicket,
+	AwaitKillCount,
+	AdjustReactorState,
+	HitReactor,
+	UpdateCharacterResource,
+	AwardAssetIf,
+	GrantPremiumTime,
+	ValidateDivorce,
+	DissolveMarriage,
+	SealAsset,
+	UnsealAsset,
+	ConfiscateAsset,
+	ResolveDispute,
+	IssueTransportTicket,
+	ScheduleWarp,
+	SpawnEscort,
+	AwaitEscort,
+	UpdateCharacterAlignment,
+	ResetInstanceCooldown,
+	AuditInventory,
+	ApplyTitleBuffOnLogin,
+	GrantMount,
+	ApplyWorldEventBuff,
+	RemoveWorldEventBuff,
+	SettleEventCurrency,
+	CharacterExperienceLock,
+	CharacterExperienceUnlock,
+	NpcConversationState,
+	EquipAssetByTemplate,
+	AwardPartyExperience,
+	ToggleCharacterAbility,
+	GrantStorageCapacity,
+	StripEquipment,
+	SnapshotCharacter,
+	RestoreCharacter,
+	AwaitEvent,
+	Notify,
+	PetEquip,
+	PetUnequip,
+}
+
 // Step represents a single step within a saga.
 type Step[T any] struct {
-	StepId    string    `json:"stepId"`    // Unique ID for the step
-	Status    Status    `json:"status"`    // Status of the step (e.g., pending, completed, failed)
-	Action    Action    `json:"action"`    // The Action to be taken (e.g., validate_inventory, deduct_inventory)
-	Payload   T         `json:"payload"`   // Data required for the action (specific to the action type)
-	CreatedAt time.Time `json:"createdAt"` // Timestamp of when the step was created
-	UpdatedAt time.Time `json:"updatedAt"` // Timestamp of the last update to the step
+	StepId    string            `json:"stepId"`              // Unique ID for the step
+	Status    Status            `json:"status"`              // Status of the step (e.g., pending, completed, failed)
+	Action    Action            `json:"action"`              // The Action to be taken (e.g., validate_inventory, deduct_inventory)
+	Payload   T                 `json:"payload"`             // Data required for the action (specific to the action type)
+	CreatedAt time.Time         `json:"createdAt"`           // Timestamp of when the step was created
+	UpdatedAt time.Time         `json:"updatedAt"`           // Timestamp of the last update to the step
+	Attempts  []StepAttempt     `json:"attempts,omitempty"`  // History of each dispatch of the step's action
+	OnError   []ErrorHandler    `json:"onError,omitempty"`   // Reactions to specific error codes reported when the step fails
+	Capture   map[string]string `json:"capture,omitempty"`   // Variables set from the event completing the step, by JSONPath into the event (e.g., characterId=$.characterId)
+	Branches  []Branch          `json:"branches,omitempty"`  // Alternative steps, one branch of which is selected to execute once the step completes
+	Branch    string            `json:"branch,omitempty"`    // Name of the branch selected once the step completed, if any
+	Outbox    []OutboxMessage   `json:"outbox,omitempty"`    // Commands of the step remaining to be produced, should producing them have been interrupted
+	DependsOn []string          `json:"dependsOn,omitempty"` // Step IDs of the saga's steps which must complete before the step is dispatched
+	Timeout   uint32            `json:"timeout,omitempty"`   // Seconds the event completing the step may take to arrive once dispatched, after which the step fails. When 0, the step awaits indefinitely.
+
+	PayloadTemplate json.RawMessage `json:"payloadTemplate,omitempty"` // Payload containing template expressions, rendered into Payload when the step is dispatched
+}
+
+// ErrorReaction is the reaction of a step to a specific error code reported by a failure event.
+type ErrorReaction string
+
+const (
+	// ErrorReactionFail fails the step, compensating the saga. This is the reaction to any undeclared error code.
+	ErrorReactionFail ErrorReaction = "fail"
+	// ErrorReactionRetry runs the handler's steps, then retries the failed step
+	ErrorReactionRetry ErrorReaction = "retry"
+	// ErrorReactionSkip completes the step as though it succeeded, continuing the saga
+	ErrorReactionSkip ErrorReaction = "skip"
+)
+
+// DefaultErrorHandlerMaxAttempts is the number of attempts permitted for a step retried by an error handler, when not declared
+const DefaultErrorHandlerMaxAttempts = 3
+
+// ErrorHandler declares how a step reacts when a failure event reports the given error code.
+type ErrorHandler struct {
+	ErrorCode   string        `json:"errorCode"`             // Error code reported by the failure event (e.g. INVENTORY_FULL)
+	Reaction    ErrorReaction `json:"reaction"`              // Reaction to the error code
+	Steps       []Step[any]   `json:"steps,omitempty"`       // Steps run before the step is retried
+	MaxAttempts int           `json:"maxAttempts,omitempty"` // Attempts permitted before the step fails instead of retrying (defaults to 3)
+}
+
+// StepAttempt records a single dispatch of a step's action, and the error reported downstream should it fail.
+type StepAttempt struct {
+	Attempt      int        `json:"attempt"`                // Attempt number, starting at 1
+	DispatchedAt time.Time  `json:"dispatchedAt"`           // Timestamp of when the action was dispatched
+	CommandKey   string     `json:"commandKey"`             // Key identifying the emitted command ({stepId}#{attempt})
+	ErrorCode    string     `json:"errorCode,omitempty"`    // Error code reported by the failure event, if any
+	ErrorMessage string     `json:"errorMessage,omitempty"` // Error message reported by the failure event, if any
+	RetryAt      *time.Time `json:"retryAt,omitempty"`      // When the attempt could not be dispatched, when it is redelivered
+	Abandoned    bool       `json:"abandoned,omitempty"`    // Whether the orchestrator gave up awaiting the attempt's outcome, as when it timed out or its saga was aborted
 }
 
 // AwardItemActionPayload represents the data needed to execute a specific action in a step.
@@ -348,158 +748,935 @@ type EquipAssetPayload struct {
 	Destination   int16  `json:"destination"`   // Destination equipped slot (negative values for equipped slots)
 }
 
-// UnequipAssetPayload represents the payload required to unequip an asset from an equipped slot back to a standard inventory slot.
-type UnequipAssetPayload struct {
+// TransferCharacterPayload represents the payload required to re-parent a character from one account to another.
+type TransferCharacterPayload struct {
+	CharacterId     uint32   `json:"characterId"`     // CharacterId associated with the action
+	WorldId         world.Id `json:"worldId"`         // WorldId of the character
+	SourceAccountId uint32   `json:"sourceAccountId"` // SourceAccountId currently owning the character
+	TargetAccountId uint32   `json:"targetAccountId"` // TargetAccountId to re-parent the character to
+}
+
+// VerifyAccountMergePayload represents the payload required to verify characters are owned by the account they were merged into.
+type VerifyAccountMergePayload struct {
+	TargetAccountId uint32   `json:"targetAccountId"` // TargetAccountId the characters are expected to be owned by
+	CharacterIds    []uint32 `json:"characterIds"`    // CharacterIds of the merged characters
+}
+
+// LossEvidence represents the support metadata justifying the restoration of a lost asset.
+type LossEvidence struct {
+	TicketId   string    `json:"ticketId"`             // TicketId of the support ticket reporting the loss
+	Reason     string    `json:"reason"`               // Reason the asset was lost (e.g., "failed trade", "server rollback")
+	LostAt     time.Time `json:"lostAt"`               // LostAt is when the asset was lost
+	ReportedBy string    `json:"reportedBy,omitempty"` // ReportedBy is who reported the loss
+	Source     string    `json:"source,omitempty"`     // Source of the captured reference data (e.g., an audit log entry)
+}
+
+// Validate checks the evidence identifies a ticket and reason, and a loss which has already happened
+func (e LossEvidence) Validate() error {
+	if e.TicketId == "" {
+		return errors.New("loss evidence requires a ticket id")
+	}
+	if e.Reason == "" {
+		return errors.New("loss evidence requires a reason")
+	}
+	if e.LostAt.IsZero() {
+		return errors.New("loss evidence requires the time of loss")
+	}
+	if e.LostAt.After(time.Now()) {
+		return errors.New("loss evidence time of loss is in the future")
+	}
+	return nil
+}
+
+// RestoreAssetPayload represents the payload required to restore a lost asset with the reference data captured from it.
+type RestoreAssetPayload struct {
+	CharacterId  uint32       `json:"characterId"`            // CharacterId associated with the action
+	TemplateId   uint32       `json:"templateId"`             // TemplateId of the lost asset
+	Quantity     uint32       `json:"quantity"`               // Quantity of the lost asset
+	Expiration   time.Time    `json:"expiration,omitempty"`   // Expiration of the lost asset, if any
+	OwnerId      uint32       `json:"ownerId,omitempty"`      // OwnerId the lost asset was tagged with, if any
+	Flag         uint16       `json:"flag,omitempty"`         // Flag of the lost asset (e.g., lock, untradeable)
+	Rechargeable uint64       `json:"rechargeable,omitempty"` // Rechargeable quantity of the lost asset, if any
+	Evidence     LossEvidence `json:"evidence"`               // Evidence of the loss, validated before the asset is restored
+}
+
+// RollbackCharacterToSnapshotPayload represents the payload required to restore a character to a prior snapshot (e.g., after a dupe or exploit).
+type RollbackCharacterToSnapshotPayload struct {
+	CharacterId uint32   `json:"characterId"` // CharacterId associated with the action
+	WorldId     world.Id `json:"worldId"`     // WorldId of the character
+	SnapshotId  uint32   `json:"snapshotId"`  // SnapshotId of the backup to restore the character to
+}
+
+// RestoreInventorySnapshotPayload represents the payload required to restore a character's compartment to a prior snapshot.
+type RestoreInventorySnapshotPayload struct {
 	CharacterId   uint32 `json:"characterId"`   // CharacterId associated with the action
-	InventoryType uint32 `json:"inventoryType"` // Type of inventory (e.g., equipment, consumables)
-	Source        int16  `json:"source"`        // Source equipped slot (negative values for equipped slots)
-	Destination   int16  `json:"destination"`   // Destination inventory slot (standard inventory slot)
+	InventoryType byte   `json:"inventoryType"` // Type of inventory to restore (e.g., equipment, consumables)
+	SnapshotId    uint32 `json:"snapshotId"`    // SnapshotId of the backup to restore the compartment to
 }
 
-// ChangeJobPayload represents the payload required to change a character's job.
-type ChangeJobPayload struct {
+// AdjustNpcShopStockPayload represents the payload required to adjust the stock of an item in a limited-quantity NPC shop.
+type AdjustNpcShopStockPayload struct {
+	WorldId    world.Id `json:"worldId"`    // WorldId of the shop
+	NpcId      uint32   `json:"npcId"`      // NpcId of the shop
+	TemplateId uint32   `json:"templateId"` // TemplateId of the stocked item
+	Amount     int32    `json:"amount"`     // Amount to adjust the stock by (negative to decrement on purchase, positive to restock)
+}
+
+// SetWorldEventFlagPayload represents the payload required to set an event flag in the world state.
+type SetWorldEventFlagPayload struct {
+	WorldId  world.Id `json:"worldId"`            // WorldId the flag is set in
+	Key      string   `json:"key"`                // Key of the flag
+	Value    string   `json:"value"`              // Value to set the flag to
+	Previous *string  `json:"previous,omitempty"` // Previous value of the flag, restored on compensation when given
+}
+
+// ValidateCouponPayload represents the payload required to validate a coupon code is redeemable by an account.
+type ValidateCouponPayload struct {
+	CharacterId uint32         `json:"characterId"`       // CharacterId redeeming the coupon
+	AccountId   uint32         `json:"accountId"`         // AccountId of the character
+	WorldId     world.Id       `json:"worldId"`           // WorldId of the character
+	ChannelId   channel.Id     `json:"channelId"`         // ChannelId of the character
+	Code        string         `json:"code"`              // Code of the coupon
+	Rewards     *CouponRewards `json:"rewards,omitempty"` // Rewards attached to the coupon, recorded once validated
+}
+
+// CouponRewards represents the rewards attached to a coupon.
+type CouponRewards struct {
+	Mesos int32         `json:"mesos,omitempty"` // Mesos awarded
+	Items []ItemPayload `json:"items,omitempty"` // Items awarded
+}
+
+// ConsumeCouponPayload represents the payload required to mark a coupon code as consumed.
+type ConsumeCouponPayload struct {
+	CharacterId uint32 `json:"characterId"` // CharacterId redeeming the coupon
+	AccountId   uint32 `json:"accountId"`   // AccountId of the character
+	Code        string `json:"code"`        // Code of the coupon
+}
+
+// ApplyCharacterExpPenaltyPayload represents the payload required to deduct experience from a character, as on death.
+type ApplyCharacterExpPenaltyPayload struct {
 	CharacterId uint32     `json:"characterId"` // CharacterId associated with the action
 	WorldId     world.Id   `json:"worldId"`     // WorldId associated with the action
 	ChannelId   channel.Id `json:"channelId"`   // ChannelId associated with the action
-	JobId       job.Id     `json:"jobId"`       // JobId to change to
+	Amount      uint32     `json:"amount"`      // Amount of experience to deduct, at most the character's current experience
 }
 
-// CreateSkillPayload represents the payload required to create a skill for a character.
-type CreateSkillPayload struct {
-	CharacterId uint32    `json:"characterId"` // CharacterId associated with the action
-	SkillId     uint32    `json:"skillId"`     // SkillId to create
-	Level       byte      `json:"level"`       // Skill level
-	MasterLevel byte      `json:"masterLevel"` // Skill master level
-	Expiration  time.Time `json:"expiration"`  // Skill expiration time
+// ApplyDurabilityPenaltyPayload represents the payload required to reduce the durability of a character's equipped items, as on death.
+type ApplyDurabilityPenaltyPayload struct {
+	CharacterId uint32 `json:"characterId"` // CharacterId associated with the action
+	Percent     byte   `json:"percent"`     // Percent of their maximum durability the equipped items lose (1-100)
 }
 
-// UpdateSkillPayload represents the payload required to update a skill for a character.
-type UpdateSkillPayload struct {
-	CharacterId uint32    `json:"characterId"` // CharacterId associated with the action
-	SkillId     uint32    `json:"skillId"`     // SkillId to update
-	Level       byte      `json:"level"`       // New skill level
-	MasterLevel byte      `json:"masterLevel"` // New skill master level
-	Expiration  time.Time `json:"expiration"`  // New skill expiration time
+// SetVariablePayload represents the payload required to set a saga variable for the steps which follow.
+type SetVariablePayload struct {
+	Name  string `json:"name"`  // Name of the variable, an identifier
+	Value any    `json:"value"` // Value of the variable, a string, number, boolean, or list or object of them
 }
 
-// ValidateCharacterStatePayload represents the payload required to validate a character's state.
-type ValidateCharacterStatePayload struct {
+// CreateAccountCharacterSlotPayload represents the payload required to add character slots to an account, as on a cash
+// shop purchase.
+type CreateAccountCharacterSlotPayload struct {
+	AccountId uint32   `json:"accountId"` // AccountId purchasing the slots
+	WorldId   world.Id `json:"worldId"`   // WorldId the slots are added in
+	Amount    byte     `json:"amount"`    // Amount of slots to add
+}
+
+// EmitAnalyticsEventPayload represents the payload required to publish a structured analytics event describing an
+// outcome of the saga (e.g., reward granted, quest completed).
+type EmitAnalyticsEventPayload struct {
+	Name       string         `json:"name"`                 // Name of the event (e.g., quest_completed)
+	Properties map[string]any `json:"properties,omitempty"` // Properties of the event (e.g., questId)
+}
+
+// ModifyAssetExpirationPayload represents the payload required to change the expiration of an asset, as when extending
+// a rental item. Exactly one of Expiration or ExtendBy is given.
+type ModifyAssetExpirationPayload struct {
+	CharacterId   uint32     `json:"characterId"`          // CharacterId owning the asset
+	InventoryType byte       `json:"inventoryType"`        // Type of inventory holding the asset
+	Slot          int16      `json:"slot"`                 // Slot of the asset
+	TemplateId    uint32     `json:"templateId,omitempty"` // TemplateId the asset is expected to be, if given
+	Expiration    time.Time  `json:"expiration,omitempty"` // Expiration to set
+	ExtendBy      uint32     `json:"extendBy,omitempty"`   // Seconds to extend the current expiration by
+	Previous      *time.Time `json:"previous,omitempty"`   // Previous expiration, recorded when the action runs
+}
+
+// ApplyHammerPayload represents the payload required to apply a hammer to an equipable asset, adding upgrade slots to it.
+type ApplyHammerPayload struct {
+	CharacterId uint32               `json:"characterId"`          // CharacterId owning the equip
+	Slot        int16                `json:"slot"`                 // Slot of the equip (negative values for equipped slots)
+	TemplateId  uint32               `json:"templateId,omitempty"` // TemplateId the equip is expected to be, if given
+	Slots       uint16               `json:"slots"`                // Upgrade slots the hammer adds
+	MaxHammers  uint32               `json:"maxHammers,omitempty"` // Most hammers which may be applied to the equip, unlimited when 0
+	Snapshot    *UpgradeSlotsPayload `json:"snapshot,omitempty"`   // Upgrade slots of the equip before the hammer, recorded when the action runs
+}
+
+// UpgradeSlotsPayload represents the upgrade slots of an equipable asset and the number of hammers applied to it.
+type UpgradeSlotsPayload struct {
+	Slots          uint16 `json:"slots"`          // Upgrade slots remaining
+	HammersApplied uint32 `json:"hammersApplied"` // Hammers applied
+}
+
+// DeductMesosPayload represents the payload required to charge a character a fee in mesos.
+type DeductMesosPayload struct {
+	CharacterId uint32            `json:"characterId"`          // CharacterId associated with the action
+	WorldId     world.Id          `json:"worldId"`              // WorldId associated with the action
+	ChannelId   channel.Id        `json:"channelId"`            // ChannelId associated with the action
+	ActorId     uint32            `json:"actorId"`              // ActorId identifies who is taking the mesos
+	ActorType   string            `json:"actorType"`            // ActorType identifies the type of actor (e.g., "SYSTEM", "NPC")
+	Amount      uint32            `json:"amount"`               // Amount of mesos to deduct
+	FundsCheck  *FundsCheckResult `json:"fundsCheck,omitempty"` // Outcome of the validation of the character's mesos, recorded for audit
+}
+
+// FundsCheckResult records the outcome of validating a character has the mesos a step deducts, before deducting them.
+type FundsCheckResult struct {
+	Passed    bool      `json:"passed"`    // Whether the character had the mesos deducted
+	Details   []string  `json:"details"`   // Details of each evaluated condition
+	CheckedAt time.Time `json:"checkedAt"` // Timestamp of when the mesos were checked
+}
+
+// ForEachFailurePolicy is how a for_each step reacts to one of its iterations failing
+type ForEachFailurePolicy string
+
+const (
+	// ForEachAbort fails the saga when an iteration fails, compensating every completed iteration. This is the default.
+	ForEachAbort ForEachFailurePolicy = "abort"
+	// ForEachContinue records the iteration as failed and continues with the next, failing the saga only once more
+	// than maxFailures iterations have failed
+	ForEachContinue ForEachFailurePolicy = "continue"
+)
+
+// ForEachPayload represents the payload required to repeat sub-steps for each item of a list held in a saga variable
+// (e.g., items to destroy). The sub-steps are expanded into the saga when the step is dispatched.
+type ForEachPayload struct {
+	Items         string               `json:"items"`                   // Name of the variable holding the list iterated
+	As            string               `json:"as,omitempty"`            // Name sub-step payload templates reference the current item by (defaults to item)
+	Steps         []Step[any]          `json:"steps"`                   // Sub-steps executed, in order, for each item
+	FailurePolicy ForEachFailurePolicy `json:"failurePolicy,omitempty"` // Reaction to an iteration failing (defaults to abort)
+	MaxFailures   int                  `json:"maxFailures,omitempty"`   // Iterations permitted to fail under the continue policy before the saga fails. When 0, any number may fail.
+	Iterations    []ForEachIteration   `json:"iterations,omitempty"`    // Iterations expanded from the list, recorded with their status
+}
+
+// ForEachIteration records a single iteration of a for_each step
+type ForEachIteration struct {
+	Index   int      `json:"index"`           // Index of the item in the list, starting at 0
+	Item    any      `json:"item"`            // Item of the iteration
+	StepIds []string `json:"stepIds"`         // Step IDs the sub-steps were expanded as
+	Status  Status   `json:"status"`          // Status of the iteration, completed once all of its steps have
+	Error   string   `json:"error,omitempty"` // Error which failed the iteration, if any
+}
+
+// VerifyAndConsumeTicketPayload represents the payload required to verify a character holds an entry ticket (e.g. for
+// a party quest) and consume it, recording proof of the consumption for the steps which provision the instance.
+type VerifyAndConsumeTicketPayload struct {
+	CharacterId uint32       `json:"characterId"`        // CharacterId presenting the ticket
+	TemplateId  uint32       `json:"templateId"`         // TemplateId of the ticket item
+	Quantity    uint32       `json:"quantity,omitempty"` // Quantity of tickets consumed (defaults to 1)
+	Variable    string       `json:"variable,omitempty"` // Saga variable the proof is also set as, if any
+	Proof       *TicketProof `json:"proof,omitempty"`    // Proof of the consumption, recorded once verified
+}
+
+// TicketProof records the consumption of an entry ticket
+type TicketProof struct {
+	AssetId    uint32    `json:"assetId"`    // AssetId of the ticket consumed
+	Slot       int16     `json:"slot"`       // Slot the ticket was held in
+	TemplateId uint32    `json:"templateId"` // TemplateId of the ticket
+	Quantity   uint32    `json:"quantity"`   // Quantity of tickets consumed
+	ConsumedAt time.Time `json:"consumedAt"` // Timestamp of when the ticket was consumed
+}
+
+// AwaitKillCountPayload represents the payload required to await monsters being killed in a field, completing once the
+// target count is reached.
+type AwaitKillCountPayload struct {
+	CharacterId uint32    `json:"characterId,omitempty"` // When set, only kills the character contributed to, by killing or damaging the monster, are counted
+	WorldId     byte      `json:"worldId"`               // WorldId of the field
+	ChannelId   byte      `json:"channelId"`             // ChannelId of the field
+	MapId       uint32    `json:"mapId"`                 // MapId of the field
+	Instance    uuid.UUID `json:"instance,omitempty"`    // Instance of the field, for instanced maps (e.g. party quests)
+	MonsterIds  []uint32  `json:"monsterIds,omitempty"`  // When set, only kills of these monsters are counted
+	Count       uint32    `json:"count"`                 // Number of kills which complete the step
+	Timeout     uint32    `json:"timeout,omitempty"`     // Seconds the kills may take, after which the step fails. When 0, the step awaits indefinitely.
+	Killed      uint32    `json:"killed,omitempty"`      // Kills counted so far, recorded by the orchestrator
+}
+
+// AdjustReactorStatePayload represents the payload required to transition a reactor in a field directly to a state
+// (e.g. opening a door once items are turned in).
+type AdjustReactorStatePayload struct {
+	FieldId   field.Id `json:"fieldId"`            // FieldId of the field, and instance, the reactor is spawned in
+	ReactorId uint32   `json:"reactorId"`          // ReactorId of the spawned reactor
+	State     int8     `json:"state"`              // State to transition the reactor to
+	Previous  *int8    `json:"previous,omitempty"` // Previous state of the reactor, restored on compensation when given
+}
+
+// HitReactorPayload represents the payload required to hit a reactor in a field on behalf of a character, advancing it
+// through its scripted states as though struck.
+type HitReactorPayload struct {
+	CharacterId uint32   `json:"characterId"`       // CharacterId the hit is attributed to
+	FieldId     field.Id `json:"fieldId"`           // FieldId of the field, and instance, the reactor is spawned in
+	ReactorId   uint32   `json:"reactorId"`         // ReactorId of the spawned reactor
+	Stance      uint16   `json:"stance,omitempty"`  // Stance of the character when hitting
+	SkillId     uint32   `json:"skillId,omitempty"` // SkillId the reactor is hit with, for reactors which require one
+}
+
+// UpdateCharacterResourcePayload represents the payload required to set or adjust one of a character's alternate resources,
+// such as the energy charge or combo counter of class-specific systems.
+type UpdateCharacterResourcePayload struct {
+	CharacterId uint32     `json:"characterId"`        // CharacterId associated with the action
+	WorldId     world.Id   `json:"worldId"`            // WorldId associated with the action
+	ChannelId   channel.Id `json:"channelId"`          // ChannelId associated with the action
+	Resource    string     `json:"resource"`           // Resource to update, ENERGY_CHARGE or COMBO_COUNTER
+	Value       *int32     `json:"value,omitempty"`    // Value to set the resource to
+	Amount      int32      `json:"amount,omitempty"`   // Amount to adjust the resource by
+	Previous    *int32     `json:"previous,omitempty"` // Previous value, recorded when the action runs
+}
+
+// AwardAssetIfPayload represents the payload required to award an asset to a character only should the character's state
+// satisfy the conditions, validated and awarded in a single step.
+type AwardAssetIfPayload struct {
 	CharacterId uint32                      `json:"characterId"` // CharacterId associated with the action
-	Conditions  []validation.ConditionInput `json:"conditions"`  // Conditions to validate
+	Conditions  []validation.ConditionInput `json:"conditions"`  // Conditions which must hold for the asset to be awarded
+	Item        ItemPayload                 `json:"item"`        // Item awarded should the conditions hold
 }
 
-// RequestGuildNamePayload represents the payload required to request a guild name.
-type RequestGuildNamePayload struct {
-	CharacterId uint32 `json:"characterId"` // CharacterId associated with the action
-	WorldId     byte   `json:"worldId"`     // WorldId associated with the action
-	ChannelId   byte   `json:"channelId"`   // ChannelId associated with the action
+// Award returns the award made should the conditions hold
+func (p AwardAssetIfPayload) Award() AwardItemActionPayload {
+	return AwardItemActionPayload{CharacterId: p.CharacterId, Item: p.Item}
 }
 
-// RequestGuildEmblemPayload represents the payload required to request a guild emblem change.
-type RequestGuildEmblemPayload struct {
-	CharacterId uint32 `json:"characterId"` // CharacterId associated with the action
-	WorldId     byte   `json:"worldId"`     // WorldId associated with the action
-	ChannelId   byte   `json:"channelId"`   // ChannelId associated with the action
+// GrantPremiumTimePayload represents the payload required to credit premium (VIP) time to an account, as on a cash
+// purchase or a compensation gift.
+type GrantPremiumTimePayload struct {
+	AccountId uint32 `json:"accountId"` // AccountId credited the time
+	Seconds   uint32 `json:"seconds"`   // Seconds of premium time to credit
 }
 
-// RequestGuildDisbandPayload represents the payload required to request a guild disband.
-type RequestGuildDisbandPayload struct {
-	CharacterId uint32 `json:"characterId"` // CharacterId associated with the action
-	WorldId     byte   `json:"worldId"`     // WorldId associated with the action
-	ChannelId   byte   `json:"channelId"`   // ChannelId associated with the action
+// ValidateDivorcePayload represents the payload required to validate a character's marriage may be dissolved, adding the
+// steps of the divorce once validated.
+type ValidateDivorcePayload struct {
+	CharacterId uint32          `json:"characterId"`         // CharacterId requesting the divorce
+	WorldId     world.Id        `json:"worldId"`             // WorldId of the character
+	ChannelId   channel.Id      `json:"channelId"`           // ChannelId of the character
+	PartnerId   uint32          `json:"partnerId,omitempty"` // PartnerId the character must be married to, or 0 for whoever they are married to
+	Fee         uint32          `json:"fee,omitempty"`       // Fee in mesos deducted from the character for the divorce
+	KeepRings   bool            `json:"keepRings,omitempty"` // Whether the couple keep their wedding rings, rather than having them destroyed
+	Marriage    *MarriageRecord `json:"marriage,omitempty"`  // Marriage being dissolved, recorded once validated
 }
 
-// RequestGuildCapacityIncreasePayload represents the payload required to request a guild capacity increase.
-type RequestGuildCapacityIncreasePayload struct {
-	CharacterId uint32 `json:"characterId"` // CharacterId associated with the action
-	WorldId     byte   `json:"worldId"`     // WorldId associated with the action
-	ChannelId   byte   `json:"channelId"`   // ChannelId associated with the action
+// MarriageRecord represents a marriage validated for dissolution.
+type MarriageRecord struct {
+	MarriageId     uint32 `json:"marriageId"`               // MarriageId of the marriage
+	PartnerId      uint32 `json:"partnerId"`                // PartnerId the character is married to
+	RingTemplateId uint32 `json:"ringTemplateId,omitempty"` // RingTemplateId of the couple's wedding rings, if any
 }
 
-// CreateInvitePayload represents the payload required to create an invitation.
-type CreateInvitePayload struct {
-	InviteType   string `json:"inviteType"`   // Type of invitation (e.g., "GUILD", "PARTY", "BUDDY")
-	OriginatorId uint32 `json:"originatorId"` // ID of the character sending the invitation
-	TargetId     uint32 `json:"targetId"`     // ID of the character receiving the invitation
-	ReferenceId  uint32 `json:"referenceId"`  // ID of the entity being invited to (e.g., guild ID, party ID)
-	WorldId      byte   `json:"worldId"`      // WorldId associated with the action
+// DissolveMarriagePayload represents the payload required to dissolve a marriage, updating the records of both partners.
+type DissolveMarriagePayload struct {
+	CharacterId uint32 `json:"characterId"` // CharacterId requesting the divorce
+	MarriageId  uint32 `json:"marriageId"`  // MarriageId of the marriage to dissolve
+	PartnerId   uint32 `json:"partnerId"`   // PartnerId the character is married to
 }
 
-// CharacterCreatePayload represents the payload required to create a character.
-// Note: this does not include any character attributes, as those are determined by the character service.
-type CharacterCreatePayload struct {
-	AccountId    uint32  `json:"accountId"` // AccountId associated with the action
-	WorldId      byte    `json:"worldId"`   // WorldId associated with the action
-	Name         string  `json:"name"`      // Name of the character to create
-	Gender       byte    `json:"gender"`
-	Level        byte    `json:"level"`
-	Strength     uint16  `json:"strength"`
-	Dexterity    uint16  `json:"dexterity"`
-	Intelligence uint16  `json:"intelligence"`
-	Luck         uint16  `json:"luck"`
-	JobId        job.Id  `json:"jobId"` // JobId to create the character with
-	Hp           uint16  `json:"hp"`
-	Mp           uint16  `json:"mp"`
-	Face         uint32  `json:"face"`   // Face of the character
-	Hair         uint32  `json:"hair"`   // Hair of the character
-	Skin         byte    `json:"skin"`   // Skin of the character
-	Top          uint32  `json:"top"`    // Top of the character
-	Bottom       uint32  `json:"bottom"` // Bottom of the character
-	Shoes        uint32  `json:"shoes"`  // Shoes of the character
-	Weapon       uint32  `json:"weapon"` // Weapon of the character
-	MapId        _map.Id `json:"mapId"`  // Starting map ID for the character
+// SealAssetPayload represents the payload required to seal an asset in a character's compartment, reserving it for the
+// saga so it cannot be traded, dropped or moved, such as while a dispute is resolved
+type SealAssetPayload struct {
+	CharacterId   uint32 `json:"characterId"`   // CharacterId holding the asset
+	InventoryType byte   `json:"inventoryType"` // InventoryType of the compartment holding the asset
+	Slot          int16  `json:"slot"`          // Slot occupied by the asset
+	TemplateId    uint32 `json:"templateId"`    // TemplateId of the asset
+	Quantity      uint32 `json:"quantity"`      // Quantity of the asset sealed
 }
 
-// CreateAndEquipAssetPayload represents the payload required to create and equip an asset.
-type CreateAndEquipAssetPayload struct {
-	CharacterId uint32      `json:"characterId"` // CharacterId associated with the action
-	Item        ItemPayload `json:"item"`        // Item to create and equip
+// UnsealAssetPayload represents the payload required to unseal an asset sealed by the saga, cancelling its reservation
+type UnsealAssetPayload struct {
+	CharacterId   uint32 `json:"characterId"`   // CharacterId holding the asset
+	InventoryType byte   `json:"inventoryType"` // InventoryType of the compartment holding the asset
+	Slot          int16  `json:"slot"`          // Slot occupied by the asset
+	TemplateId    uint32 `json:"templateId"`    // TemplateId of the asset, with which it is sealed again on compensation
+	Quantity      uint32 `json:"quantity"`      // Quantity of the asset, with which it is sealed again on compensation
 }
 
-type ExperienceDistributions struct {
-	ExperienceType string `json:"experienceType"`
-	Amount         uint32 `json:"amount"`
-	Attr1          uint32 `json:"attr1"`
+// ConfiscateAssetPayload represents the payload required to confiscate an asset sealed by the saga, consuming it
+type ConfiscateAssetPayload struct {
+	CharacterId   uint32 `json:"characterId"`   // CharacterId holding the asset
+	InventoryType byte   `json:"inventoryType"` // InventoryType of the compartment holding the asset
+	Slot          int16  `json:"slot"`          // Slot occupied by the asset
 }
 
-// Custom UnmarshalJSON for Step[T] to handle the generics
-func (s *Step[T]) UnmarshalJSON(data []byte) error {
-	type Alias Step[T] // Alias to avoid recursion
-	aux := &struct {
-		Payload json.RawMessage `json:"payload"`
-		*Alias
-	}{
-		Alias: (*Alias)(s),
-	}
+// ResolveDisputePayload represents the payload of a step holding a saga until an operator resolves the dispute it
+// concerns. The resolution is recorded on the payload, so the step's branches may select between the outcomes.
+type ResolveDisputePayload struct {
+	TicketId   string `json:"ticketId"`             // TicketId of the support ticket the dispute concerns
+	Resolution string `json:"resolution,omitempty"` // Resolution chosen by the operator, recorded once resolved
+	ResolvedBy string `json:"resolvedBy,omitempty"` // ResolvedBy identifies the operator who resolved the dispute
+}
 
-	// Unmarshal the generic part (excluding Payload first)
-	if err := json.Unmarshal(data, &aux); err != nil {
-		return err
-	}
+// IssueTransportTicketPayload represents the payload required to issue a character the ticket item for a transport (e.g.
+// a ship ticket), the fare for which was deducted by a prior step
+type IssueTransportTicketPayload struct {
+	CharacterId uint32     `json:"characterId"` // CharacterId associated with the action
+	WorldId     world.Id   `json:"worldId"`     // WorldId associated with the action
+	ChannelId   channel.Id `json:"channelId"`   // ChannelId associated with the action
+	TemplateId  uint32     `json:"templateId"`  // TemplateId of the ticket item
+	Fare        uint32     `json:"fare"`        // Fare paid for the ticket, refunded should it not be issued
+}
 
-	// Now handle the Payload field based on the Action type (you can customize this)
-	switch s.Action {
-	case AwardInventory, AwardAsset: // Handle both action types the same way
-		var payload AwardItemActionPayload
-		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
-			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
-		}
-		s.Payload = any(payload).(T)
-	case AwardExperience:
-		var payload AwardExperiencePayload
-		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
-			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
-		}
-		s.Payload = any(payload).(T)
-	case AwardLevel:
-		var payload AwardLevelPayload
-		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
-			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
-		}
-		s.Payload = any(payload).(T)
-	case AwardMesos:
-		var payload AwardMesosPayload
-		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+// ScheduleWarpPayload represents the payload required to warp a character to a portal at a transport's departure time
+// (e.g. a taxi or ship), the fare for which was deducted by a prior step
+type ScheduleWarpPayload struct {
+	CharacterId uint32     `json:"characterId"`         // CharacterId associated with the action
+	WorldId     world.Id   `json:"worldId"`             // WorldId associated with the action
+	ChannelId   channel.Id `json:"channelId"`           // ChannelId associated with the action
+	FieldId     field.Id   `json:"fieldId"`             // FieldId of the transport's destination
+	PortalId    uint32     `json:"portalId"`            // PortalId at which the character arrives
+	DepartsAt   time.Time  `json:"departsAt,omitempty"` // Departure time of the transport. When zero or past, the character departs immediately.
+	Fare        uint32     `json:"fare"`                // Fare paid for the transport, refunded should the character not arrive
+}
+
+// SpawnEscortPayload represents the payload required to spawn an NPC escorted by a character, which follows them until it
+// reaches its destination map.
+type SpawnEscortPayload struct {
+	CharacterId      uint32   `json:"characterId"`      // CharacterId of the character escorting the NPC
+	FieldId          field.Id `json:"fieldId"`          // FieldId of the field, and instance, the escort is spawned in
+	NpcId            uint32   `json:"npcId"`            // NpcId of the escort
+	DestinationMapId _map.Id  `json:"destinationMapId"` // MapId at which the escort arrives
+}
+
+// AwaitEscortPayload represents the payload required to await an escort spawned by a prior step arriving at its
+// destination, failing should the escort fail or not arrive within the timeout.
+type AwaitEscortPayload struct {
+	CharacterId uint32   `json:"characterId"`       // CharacterId of the character escorting the NPC
+	FieldId     field.Id `json:"fieldId"`           // FieldId of the field, and instance, the escort was spawned in
+	NpcId       uint32   `json:"npcId"`             // NpcId of the escort
+	Timeout     uint32   `json:"timeout,omitempty"` // Seconds the escort may take to arrive, after which the step fails. When 0, the step awaits indefinitely.
+}
+
+// UpdateCharacterAlignmentPayload represents the payload required to grant or deduct a character's points with a faction,
+// shifting their alignment (e.g. as the reward of a faction quest).
+type UpdateCharacterAlignmentPayload struct {
+	CharacterId uint32   `json:"characterId"` // CharacterId associated with the action
+	WorldId     world.Id `json:"worldId"`     // WorldId associated with the action
+	FactionId   uint32   `json:"factionId"`   // FactionId the points are held with
+	Amount      int32    `json:"amount"`      // Points to grant, or deduct when negative
+}
+
+// ResetInstanceCooldownPayload represents the payload required to clear a character's cooldown of an instance, such as a
+// dungeon or party quest, so they may enter it again (e.g. by a GM tool or cash item).
+type ResetInstanceCooldownPayload struct {
+	CharacterId uint32     `json:"characterId"`        // CharacterId associated with the action
+	WorldId     world.Id   `json:"worldId"`            // WorldId associated with the action
+	InstanceId  uint32     `json:"instanceId"`         // InstanceId of the dungeon or party quest
+	Previous    *time.Time `json:"previous,omitempty"` // Expiration of the cooldown cleared, recorded when the action runs
+}
+
+// AuditInventoryPayload represents the payload of a saga's optional terminal step auditing that the net change its steps
+// made to a character's inventory materialized, rather than trusting the events of its steps.
+type AuditInventoryPayload struct {
+	CharacterId uint32                `json:"characterId"`        // CharacterId whose inventory is audited
+	Baseline    map[uint32]uint32     `json:"baseline,omitempty"` // Quantities of the audited items held when the saga was started, by template, recorded by the orchestrator
+	Report      *ReconciliationReport `json:"report,omitempty"`   // Outcome of the audit, recorded once the step completes
+}
+
+// ApplyTitleBuffOnLoginPayload represents the payload required to grant the buff of a title to a character on their next
+// login, as for a reward granted while they are offline.
+type ApplyTitleBuffOnLoginPayload struct {
+	CharacterId uint32     `json:"characterId"`         // CharacterId associated with the action
+	WorldId     world.Id   `json:"worldId"`             // WorldId associated with the action
+	TitleId     uint32     `json:"titleId"`             // TitleId of the title whose buff is applied
+	Duration    uint32     `json:"duration"`            // Duration of the buff once applied, in seconds
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"` // Time after which the effect is dropped should the character not have logged in, if any
+	EffectId    *uuid.UUID `json:"effectId,omitempty"`  // EffectId of the deferred effect registered, recorded when the action runs
+}
+
+// GrantMountPayload represents the payload required to grant a character a mount, being a riding skill and the mount
+// item it requires. Both are granted through dynamically added steps, and removed together should either fail.
+type GrantMountPayload struct {
+	CharacterId uint32    `json:"characterId"`          // CharacterId associated with the action
+	SkillId     uint32    `json:"skillId"`              // SkillId of the riding skill
+	SkillLevel  byte      `json:"skillLevel,omitempty"` // Level of the riding skill (default 1)
+	ItemId      uint32    `json:"itemId"`               // ItemId of the mount
+	Expiration  time.Time `json:"expiration,omitempty"` // Expiration of the riding skill, if any
+}
+
+// ApplyWorldEventBuffPayload represents the payload required to enable a server-wide buff in a world (e.g. a 2x experience
+// event) for a duration, after which it is removed by a linked remove_world_event_buff step.
+type ApplyWorldEventBuffPayload struct {
+	WorldId  world.Id `json:"worldId"`  // WorldId in which the buff is enabled
+	BuffId   string   `json:"buffId"`   // BuffId identifying the buff, by which it is removed
+	Type     string   `json:"type"`     // Type of multiplier the buff applies (e.g. "exp", "meso", "drop")
+	Rate     float64  `json:"rate"`     // Rate of the multiplier (e.g. 2 for double experience)
+	Duration uint32   `json:"duration"` // Duration in seconds the buff is enabled for
+}
+
+// RemoveWorldEventBuffPayload represents the payload required to remove a server-wide buff from a world, once its
+// removal time is reached
+type RemoveWorldEventBuffPayload struct {
+	WorldId  world.Id  `json:"worldId"`            // WorldId from which the buff is removed
+	BuffId   string    `json:"buffId"`             // BuffId identifying the buff
+	RemoveAt time.Time `json:"removeAt,omitempty"` // Time at which the buff is removed. When zero or past, it is removed immediately.
+}
+
+// SettleEventCurrencyPayload represents the payload required to settle a page of the characters holding an event's
+// currency once the event has ended, destroying the currency each holds and awarding consolation rewards in its place.
+// The step links a step settling the next page, until every holder is settled.
+type SettleEventCurrencyPayload struct {
+	CurrencyId uint32          `json:"currencyId"`         // TemplateId of the event currency item
+	RewardId   uint32          `json:"rewardId"`           // TemplateId of the consolation reward item
+	Rate       uint32          `json:"rate,omitempty"`     // Units of currency converted into one reward. Defaults to 1.
+	PageSize   uint32          `json:"pageSize,omitempty"` // Holders settled by each page. Defaults to DefaultSettlementPageSize.
+	Page       uint32          `json:"page,omitempty"`     // Page settled by the step, numbered from 1. Defaults to 1.
+	After      uint32          `json:"after,omitempty"`    // CharacterId after which the page's holders are listed, the last settled by the previous page
+	Holders    []SettledHolder `json:"holders,omitempty"`  // Holders of the page, recorded once listed
+}
+
+// SettledHolder represents a character settled by a settle_event_currency step
+type SettledHolder struct {
+	CharacterId uint32 `json:"characterId"` // CharacterId of the holder
+	Quantity    uint32 `json:"quantity"`    // Quantity of currency held
+}
+
+// CharacterExperienceLockPayload represents the payload required to freeze a character's experience gain, as when a
+// level-locked event holds its participants at their level.
+type CharacterExperienceLockPayload struct {
+	CharacterId uint32     `json:"characterId"`      // CharacterId associated with the action
+	WorldId     world.Id   `json:"worldId"`          // WorldId associated with the action
+	ChannelId   channel.Id `json:"channelId"`        // ChannelId associated with the action
+	Reason      string     `json:"reason,omitempty"` // Reason the experience gain is frozen, such as the event imposing it
+}
+
+// CharacterExperienceUnlockPayload represents the payload required to resume a character's experience gain, frozen by
+// a character_experience_lock step.
+type CharacterExperienceUnlockPayload struct {
+	CharacterId uint32     `json:"characterId"` // CharacterId associated with the action
+	WorldId     world.Id   `json:"worldId"`     // WorldId associated with the action
+	ChannelId   channel.Id `json:"channelId"`   // ChannelId associated with the action
+}
+
+// NpcConversationStatePayload represents the payload required to signal the NPC conversation service to resume a
+// conversation suspended while the saga progressed, at the dialogue state reached once the saga's work is done.
+type NpcConversationStatePayload struct {
+	CharacterId uint32         `json:"characterId"`       // CharacterId whose conversation is resumed
+	WorldId     world.Id       `json:"worldId"`           // WorldId associated with the action
+	ChannelId   channel.Id     `json:"channelId"`         // ChannelId associated with the action
+	NpcId       uint32         `json:"npcId"`             // NpcId the character is conversing with
+	State       string         `json:"state"`             // State of the conversation's dialogue to resume at
+	Outcome     map[string]any `json:"outcome,omitempty"` // Outcome data passed to the conversation, alongside the saga's variables
+}
+
+// EquipAssetByTemplatePayload represents the payload required to equip an asset of a template, from whichever inventory
+// slot currently holds it.
+type EquipAssetByTemplatePayload struct {
+	CharacterId uint32 `json:"characterId"`      // CharacterId associated with the action
+	TemplateId  uint32 `json:"templateId"`       // TemplateId of the asset to equip
+	Destination int16  `json:"destination"`      // Destination equipped slot (negative values for equipped slots)
+	Source      int16  `json:"source,omitempty"` // Source inventory slot the asset was found in, recorded when the step executes
+}
+
+// AwardPartyExperiencePayload represents the payload required to split a pool of experience across the members of a
+// party, in proportion to their levels. Each member's share is awarded through a dynamically added award_experience step,
+// and every share awarded is deducted again should any fail.
+type AwardPartyExperiencePayload struct {
+	WorldId   world.Id               `json:"worldId"`          // WorldId associated with the action
+	ChannelId channel.Id             `json:"channelId"`        // ChannelId associated with the action
+	Amount    uint32                 `json:"amount"`           // Experience pool split across the members
+	Members   []PartyMember          `json:"members"`          // Members of the party sharing the pool
+	Shares    []PartyExperienceShare `json:"shares,omitempty"` // Share of the pool of each member, recorded when the step executes
+}
+
+// PartyMember is a member of a party sharing a pool of experience, weighted by their level
+type PartyMember struct {
+	CharacterId uint32 `json:"characterId"` // CharacterId of the member
+	Level       byte   `json:"level"`       // Level of the member, by which their share is weighted
+}
+
+// PartyExperienceShare is the share of a pool of experience awarded to a party member
+type PartyExperienceShare struct {
+	CharacterId uint32 `json:"characterId"` // CharacterId of the member
+	Amount      uint32 `json:"amount"`      // Experience awarded to the member
+}
+
+// ToggleCharacterAbilityPayload represents the payload required to enable or disable a passive ability, such as a
+// beginner's blessing or a link skill, for characters of the same account. Each character's toggle is applied through a
+// dynamically added step, and every toggle applied is reversed should any fail.
+type ToggleCharacterAbilityPayload struct {
+	AccountId uint32          `json:"accountId"` // AccountId of the account the characters belong to
+	SkillId   uint32          `json:"skillId"`   // SkillId of the skill granting the ability
+	Level     byte            `json:"level"`     // Level of the ability, granted to characters it is enabled for, and restored to those it is disabled for should the saga fail
+	Toggles   []AbilityToggle `json:"toggles"`   // Characters the ability is enabled or disabled for
+}
+
+// AbilityToggle enables or disables an ability for a character
+type AbilityToggle struct {
+	CharacterId uint32 `json:"characterId"` // CharacterId of the character
+	Enabled     bool   `json:"enabled"`     // Whether the ability is enabled, rather than disabled, for the character
+}
+
+// GrantStorageCapacityPayload represents the payload required to increase the capacity of an account's storage in a
+// world, as on a cash shop purchase.
+type GrantStorageCapacityPayload struct {
+	AccountId uint32   `json:"accountId"` // AccountId owning the storage
+	WorldId   world.Id `json:"worldId"`   // WorldId of the storage
+	Amount    byte     `json:"amount"`    // Amount of slots to add
+}
+
+// StripEquipmentPayload represents the payload required to unequip the items a character may no longer wear following a job change (e.g. job resets).
+type StripEquipmentPayload struct {
+	CharacterId uint32         `json:"characterId"`        // CharacterId associated with the action
+	JobId       job.Id         `json:"jobId"`              // JobId the character's equipped items must be compatible with
+	Stripped    []EquippedItem `json:"stripped,omitempty"` // Items found incompatible with the job, unequipped by the steps added, retained for rollback
+}
+
+// SnapshotCharacterPayload represents the payload required to capture a character's progression and stats into the saga, so they may be restored should the saga fail.
+type SnapshotCharacterPayload struct {
+	CharacterId uint32             `json:"characterId"`        // CharacterId associated with the action
+	WorldId     world.Id           `json:"worldId"`            // WorldId associated with the action
+	ChannelId   channel.Id         `json:"channelId"`          // ChannelId the character is restored through
+	Snapshot    *CharacterSnapshot `json:"snapshot,omitempty"` // Progression and stats captured, once the step has completed
+}
+
+// CharacterSnapshot represents a character's progression and stats captured by a snapshot_character step.
+type CharacterSnapshot struct {
+	Level        byte   `json:"level"`
+	Experience   uint32 `json:"experience"`
+	Meso         uint32 `json:"meso"`
+	Strength     uint16 `json:"strength"`
+	Dexterity    uint16 `json:"dexterity"`
+	Intelligence uint16 `json:"intelligence"`
+	Luck         uint16 `json:"luck"`
+	Hp           uint16 `json:"hp"`
+	MaxHp        uint16 `json:"maxHp"`
+	Mp           uint16 `json:"mp"`
+	MaxMp        uint16 `json:"maxMp"`
+	Ap           uint16 `json:"ap"`
+}
+
+// RestoreCharacterPayload represents the payload required to restore a character's progression and stats to a snapshot taken earlier in the saga.
+type RestoreCharacterPayload struct {
+	CharacterId    uint32     `json:"characterId"`              // CharacterId associated with the action
+	WorldId        world.Id   `json:"worldId"`                  // WorldId associated with the action
+	ChannelId      channel.Id `json:"channelId"`                // ChannelId associated with the action
+	SnapshotStepId string     `json:"snapshotStepId,omitempty"` // StepId of the snapshot_character step to restore. When empty, the character's latest snapshot is restored.
+}
+
+// AwaitEventPayload represents the payload required to await an event concerning a character which no step of the saga
+// causes, such as the character entering a map of their own accord, completing once the event arrives.
+type AwaitEventPayload struct {
+	CharacterId uint32  `json:"characterId"`     // CharacterId the awaited event concerns
+	Event       string  `json:"event"`           // Event awaited, being map_entry
+	MapId       _map.Id `json:"mapId,omitempty"` // MapId the character must enter, for a map_entry event
+}
+
+// NotifyPayload represents the payload required to show a message to a character, such as a hint towards the next gate
+// of a scripted sequence.
+type NotifyPayload struct {
+	CharacterId uint32     `json:"characterId"`           // CharacterId shown the message
+	WorldId     world.Id   `json:"worldId"`               // WorldId associated with the action
+	ChannelId   channel.Id `json:"channelId"`             // ChannelId associated with the action
+	MessageType string     `json:"messageType,omitempty"` // Type of message shown (e.g. PINK_TEXT). When empty, a notice is shown.
+	Message     string     `json:"message"`               // Message shown to the character
+}
+
+// PetEquipPayload represents the payload required to equip pet equipment (e.g. a pet collar or item pouch) held by a
+// character to one of their summoned pets.
+type PetEquipPayload struct {
+	CharacterId uint32 `json:"characterId"`      // CharacterId associated with the action
+	PetId       uint32 `json:"petId"`            // PetId of the summoned pet the equipment is for
+	TemplateId  uint32 `json:"templateId"`       // TemplateId of the pet equipment to equip
+	Destination int16  `json:"destination"`      // Destination equipped slot of the pet's equipment (negative)
+	Source      int16  `json:"source,omitempty"` // Source inventory slot the equipment was found in, recorded when the step executes
+}
+
+// PetUnequipPayload represents the payload required to unequip pet equipment from one of a character's pets to an
+// inventory slot.
+type PetUnequipPayload struct {
+	CharacterId uint32 `json:"characterId"`          // CharacterId associated with the action
+	PetId       uint32 `json:"petId"`                // PetId of the pet the equipment is unequipped from
+	Source      int16  `json:"source"`               // Source equipped slot of the pet's equipment (negative)
+	Destination int16  `json:"destination"`          // Destination inventory slot
+	TemplateId  uint32 `json:"templateId,omitempty"` // TemplateId of the pet equipment found in the slot, recorded when the step executes
+}
+
+// HttpRequestPayload represents the payload required to call an allow-listed REST endpoint of a service which does not consume commands.
+type HttpRequestPayload struct {
+	Method         string            `json:"method"`                   // HTTP method (e.g., GET, POST), GET by default
+	Url            string            `json:"url"`                      // URL to call, whose host must be allow-listed
+	Headers        map[string]string `json:"headers,omitempty"`        // Headers sent with the request
+	Body           any               `json:"body,omitempty"`           // Body sent as JSON, if any
+	ExpectedStatus []int             `json:"expectedStatus,omitempty"` // Statuses which complete the step, any 2xx status by default
+	Capture        map[string]string `json:"capture,omitempty"`        // Variables set from the response body, by JSONPath into the body (e.g., orderId=$.data.id)
+	TimeoutMs      uint32            `json:"timeoutMs,omitempty"`      // Timeout of each attempt, otherwise the configured timeout
+	MaxAttempts    uint32            `json:"maxAttempts,omitempty"`    // Attempts made should the request fail with a transport error, 429 or 5xx status, 1 by default
+	BackoffMs      uint32            `json:"backoffMs,omitempty"`      // Delay between attempts
+	Response       *HttpResponse     `json:"response,omitempty"`       // Last response received, recorded for audit and later steps
+}
+
+// HttpResponse represents a response received by an http_request step.
+type HttpResponse struct {
+	Status   int    `json:"status"`         // Status of the response
+	Body     any    `json:"body,omitempty"` // Body of the response, decoded when it is JSON
+	Attempts uint32 `json:"attempts"`       // Attempts made to receive the response
+}
+
+// ApplyEquipmentPresetPayload represents the payload required to replace a character's equipped items with a preset loadout.
+type ApplyEquipmentPresetPayload struct {
+	CharacterId uint32         `json:"characterId"`        // CharacterId associated with the action
+	Items       []EquippedItem `json:"items"`              // Items of the preset, equipped in order
+	Captured    []EquippedItem `json:"captured,omitempty"` // Loadout equipped before the preset was applied, retained for rollback
+}
+
+// EquippedItem represents an item by template in an equipped slot.
+type EquippedItem struct {
+	TemplateId uint32 `json:"templateId"` // TemplateId of the item
+	Slot       int16  `json:"slot"`       // Equipped slot (negative values for equipped slots)
+}
+
+// ModifyInventoryItemPositionPayload represents the payload required to move an asset between slots of the same inventory compartment.
+type ModifyInventoryItemPositionPayload struct {
+	CharacterId   uint32 `json:"characterId"`   // CharacterId associated with the action
+	InventoryType uint32 `json:"inventoryType"` // Type of inventory (e.g., equipment, consumables)
+	Source        int16  `json:"source"`        // Source inventory slot
+	Destination   int16  `json:"destination"`   // Destination inventory slot
+}
+
+// UnequipAssetPayload represents the payload required to unequip an asset from an equipped slot back to a standard inventory slot.
+type UnequipAssetPayload struct {
+	CharacterId   uint32 `json:"characterId"`   // CharacterId associated with the action
+	InventoryType uint32 `json:"inventoryType"` // Type of inventory (e.g., equipment, consumables)
+	Source        int16  `json:"source"`        // Source equipped slot (negative values for equipped slots)
+	Destination   int16  `json:"destination"`   // Destination inventory slot (standard inventory slot)
+}
+
+// ChangeJobPayload represents the payload required to change a character's job.
+type ChangeJobPayload struct {
+	CharacterId uint32     `json:"characterId"`        // CharacterId associated with the action
+	WorldId     world.Id   `json:"worldId"`            // WorldId associated with the action
+	ChannelId   channel.Id `json:"channelId"`          // ChannelId associated with the action
+	JobId       job.Id     `json:"jobId"`              // JobId to change to
+	Previous    *job.Id    `json:"previous,omitempty"` // Previous job, recorded when the action runs
+}
+
+// CreateSkillPayload represents the payload required to create a skill for a character.
+type CreateSkillPayload struct {
+	CharacterId uint32    `json:"characterId"` // CharacterId associated with the action
+	SkillId     uint32    `json:"skillId"`     // SkillId to create
+	Level       byte      `json:"level"`       // Skill level
+	MasterLevel byte      `json:"masterLevel"` // Skill master level
+	Expiration  time.Time `json:"expiration"`  // Skill expiration time
+}
+
+// UpdateSkillPayload represents the payload required to update a skill for a character.
+type UpdateSkillPayload struct {
+	CharacterId uint32    `json:"characterId"` // CharacterId associated with the action
+	SkillId     uint32    `json:"skillId"`     // SkillId to update
+	Level       byte      `json:"level"`       // New skill level
+	MasterLevel byte      `json:"masterLevel"` // New skill master level
+	Expiration  time.Time `json:"expiration"`  // New skill expiration time
+}
+
+// ResetSkillCooldownsPayload represents the payload required to reset a character's skill cooldowns.
+type ResetSkillCooldownsPayload struct {
+	CharacterId uint32   `json:"characterId"`        // CharacterId associated with the action
+	SkillIds    []uint32 `json:"skillIds,omitempty"` // SkillIds to reset. All cooldowns are reset when empty
+}
+
+// ValidateCharacterStatePayload represents the payload required to validate a character's state.
+type ValidateCharacterStatePayload struct {
+	CharacterId uint32                      `json:"characterId"`      // CharacterId associated with the action
+	Conditions  []validation.ConditionInput `json:"conditions"`       // Conditions to validate
+	Passed      *bool                       `json:"passed,omitempty"` // Whether the conditions held, recorded for the step's branches to select on
+}
+
+// RequestGuildNamePayload represents the payload required to request a guild name.
+type RequestGuildNamePayload struct {
+	CharacterId uint32 `json:"characterId"` // CharacterId associated with the action
+	WorldId     byte   `json:"worldId"`     // WorldId associated with the action
+	ChannelId   byte   `json:"channelId"`   // ChannelId associated with the action
+}
+
+// RequestGuildEmblemPayload represents the payload required to request a guild emblem change.
+type RequestGuildEmblemPayload struct {
+	CharacterId uint32 `json:"characterId"` // CharacterId associated with the action
+	WorldId     byte   `json:"worldId"`     // WorldId associated with the action
+	ChannelId   byte   `json:"channelId"`   // ChannelId associated with the action
+}
+
+// RequestGuildDisbandPayload represents the payload required to request a guild disband.
+type RequestGuildDisbandPayload struct {
+	CharacterId uint32 `json:"characterId"` // CharacterId associated with the action
+	WorldId     byte   `json:"worldId"`     // WorldId associated with the action
+	ChannelId   byte   `json:"channelId"`   // ChannelId associated with the action
+}
+
+// RequestGuildCapacityIncreasePayload represents the payload required to request a guild capacity increase.
+type RequestGuildCapacityIncreasePayload struct {
+	CharacterId uint32 `json:"characterId"` // CharacterId associated with the action
+	WorldId     byte   `json:"worldId"`     // WorldId associated with the action
+	ChannelId   byte   `json:"channelId"`   // ChannelId associated with the action
+}
+
+// CreateInvitePayload represents the payload required to create an invitation.
+type CreateInvitePayload struct {
+	InviteType   string `json:"inviteType"`    // Type of invitation (e.g., "GUILD", "PARTY", "BUDDY")
+	OriginatorId uint32 `json:"originatorId"`  // ID of the character sending the invitation
+	TargetId     uint32 `json:"targetId"`      // ID of the character receiving the invitation
+	ReferenceId  uint32 `json:"referenceId"`   // ID of the entity being invited to (e.g., guild ID, party ID)
+	WorldId      byte   `json:"worldId"`       // WorldId associated with the action
+	Ttl          uint32 `json:"ttl,omitempty"` // Seconds the invitation may remain unanswered, otherwise the configured TTL
+}
+
+// CharacterCreatePayload represents the payload required to create a character.
+// Note: this does not include any character attributes, as those are determined by the character service.
+type CharacterCreatePayload struct {
+	AccountId    uint32  `json:"accountId"` // AccountId associated with the action
+	WorldId      byte    `json:"worldId"`   // WorldId associated with the action
+	Name         string  `json:"name"`      // Name of the character to create
+	Gender       byte    `json:"gender"`
+	Level        byte    `json:"level"`
+	Strength     uint16  `json:"strength"`
+	Dexterity    uint16  `json:"dexterity"`
+	Intelligence uint16  `json:"intelligence"`
+	Luck         uint16  `json:"luck"`
+	JobId        job.Id  `json:"jobId"` // JobId to create the character with
+	Hp           uint16  `json:"hp"`
+	Mp           uint16  `json:"mp"`
+	Face         uint32  `json:"face"`                  // Face of the character
+	Hair         uint32  `json:"hair"`                  // Hair of the character
+	Skin         byte    `json:"skin"`                  // Skin of the character
+	Top          uint32  `json:"top"`                   // Top of the character
+	Bottom       uint32  `json:"bottom"`                // Bottom of the character
+	Shoes        uint32  `json:"shoes"`                 // Shoes of the character
+	Weapon       uint32  `json:"weapon"`                // Weapon of the character
+	MapId        _map.Id `json:"mapId"`                 // Starting map ID for the character
+	CharacterId  uint32  `json:"characterId,omitempty"` // CharacterId of the created character, recorded when the CREATED event completes the step
+}
+
+// CreateAndEquipAssetPayload represents the payload required to create and equip an asset.
+type CreateAndEquipAssetPayload struct {
+	CharacterId uint32      `json:"characterId"` // CharacterId associated with the action
+	Item        ItemPayload `json:"item"`        // Item to create and equip
+}
+
+// TimerExpiryBehavior the behavior applied when a saga countdown expires before the saga completes
+type TimerExpiryBehavior string
+
+const (
+	TimerExpiryCompensate   TimerExpiryBehavior = "compensate"     // Fail the saga's current step, triggering compensation
+	TimerExpiryFollowUpSaga TimerExpiryBehavior = "follow_up_saga" // Start the follow-up saga
+)
+
+// SetQuestTimerPayload represents the payload required to start a server-side countdown tied to the saga.
+type SetQuestTimerPayload struct {
+	CharacterId uint32              `json:"characterId"`        // CharacterId associated with the action
+	QuestId     uint32              `json:"questId"`            // QuestId the countdown is tracked against
+	Duration    uint32              `json:"duration"`           // Duration of the countdown in seconds
+	OnExpiry    TimerExpiryBehavior `json:"onExpiry"`           // Behavior when the countdown expires (defaults to compensate)
+	FollowUp    *Saga               `json:"followUp,omitempty"` // Saga to start when OnExpiry is follow_up_saga
+}
+
+// AdjustPopularityPayload represents the payload required for one character to give fame to another.
+type AdjustPopularityPayload struct {
+	CharacterId   uint32               `json:"characterId"`             // CharacterId of the character giving fame
+	WorldId       world.Id             `json:"worldId"`                 // WorldId associated with the action
+	ChannelId     channel.Id           `json:"channelId"`               // ChannelId associated with the action
+	TargetId      uint32               `json:"targetId"`                // TargetId of the character receiving fame
+	Amount        int8                 `json:"amount"`                  // Amount of fame to give (1 or -1)
+	CooldownCheck *CooldownCheckResult `json:"cooldownCheck,omitempty"` // Outcome of the cooldown validation, recorded for audit
+}
+
+// CooldownCheckResult records the outcome of a cooldown validation performed while executing a step.
+type CooldownCheckResult struct {
+	Passed    bool      `json:"passed"`    // Whether the cooldown permitted the action
+	Details   []string  `json:"details"`   // Details of each evaluated condition
+	CheckedAt time.Time `json:"checkedAt"` // Timestamp of when the cooldown was checked
+}
+
+// ResolvePrizeTablePayload represents the payload required to draw a prize from a weighted prize table and award it.
+type ResolvePrizeTablePayload struct {
+	CharacterId uint32       `json:"characterId"`        // CharacterId associated with the action
+	WorldId     world.Id     `json:"worldId"`            // WorldId associated with the action
+	ChannelId   channel.Id   `json:"channelId"`          // ChannelId associated with the action
+	Prizes      []PrizeEntry `json:"prizes"`             // Prize table to draw from
+	Resolved    *PrizeEntry  `json:"resolved,omitempty"` // Prize drawn from the table, recorded for audit
+}
+
+// PrizeEntry represents a single entry of a prize table. An entry with neither an item nor mesos awards nothing.
+type PrizeEntry struct {
+	Weight uint32       `json:"weight"`          // Relative chance of the entry being drawn
+	Item   *ItemPayload `json:"item,omitempty"`  // Item awarded when the entry is drawn
+	Mesos  int32        `json:"mesos,omitempty"` // Mesos awarded when the entry is drawn
+}
+
+// CharacterBuffCleansePayload represents the payload required to remove all active buffs and debuffs from a character.
+type CharacterBuffCleansePayload struct {
+	CharacterId uint32         `json:"characterId"`        // CharacterId associated with the action
+	WorldId     world.Id       `json:"worldId"`            // WorldId associated with the action
+	ChannelId   channel.Id     `json:"channelId"`          // ChannelId associated with the action
+	Captured    []CapturedBuff `json:"captured,omitempty"` // Buffs active when the cleanse was requested, retained for compensation
+}
+
+// CapturedBuff represents a buff which was active on a character before it was cleansed.
+type CapturedBuff struct {
+	SourceId  int32            `json:"sourceId"`  // SourceId of the skill or item which granted the buff
+	Duration  int32            `json:"duration"`  // Duration of the buff in seconds, as originally applied
+	Changes   []BuffStatChange `json:"changes"`   // Stat changes granted by the buff
+	ExpiresAt time.Time        `json:"expiresAt"` // Timestamp of when the buff would have expired
+}
+
+// BuffStatChange represents a single stat change granted by a buff.
+type BuffStatChange struct {
+	Type   string `json:"type"`   // Type of the stat affected
+	Amount int32  `json:"amount"` // Amount the stat is changed by
+}
+
+type ExperienceDistributions struct {
+	ExperienceType string `json:"experienceType"`
+	Amount         uint32 `json:"amount"`
+	Attr1          uint32 `json:"attr1"`
+}
+
+// Custom UnmarshalJSON for Step[T] to handle the generics
+func (s *Step[T]) UnmarshalJSON(data []byte) error {
+	type Alias Step[T] // Alias to avoid recursion
+	aux := &struct {
+		Payload json.RawMessage `json:"payload"`
+		*Alias
+	}{
+		Alias: (*Alias)(s),
+	}
+
+	// Unmarshal the generic part (excluding Payload first)
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	// Payloads containing template expressions cannot be typed until they are rendered when the step is dispatched. The
+	// sub-steps of a for_each step are instead rendered as they are expanded.
+	if s.Action != ForEach && IsPayloadTemplate(aux.Payload) {
+		s.PayloadTemplate = aux.Payload
+		return nil
+	}
+
+	// Now handle the Payload field based on the Action type (you can customize this)
+	switch s.Action {
+	case AwardInventory, AwardAsset: // Handle both action types the same way
+		var payload AwardItemActionPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case AwardExperience:
+		var payload AwardExperiencePayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case AwardLevel:
+		var payload AwardLevelPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case AwardMesos:
+		var payload AwardMesosPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
 			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
 		}
 		s.Payload = any(payload).(T)
@@ -551,6 +1728,36 @@ func (s *Step[T]) UnmarshalJSON(data []byte) error {
 			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
 		}
 		s.Payload = any(payload).(T)
+	case ValidateCharacterState:
+		var payload ValidateCharacterStatePayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case RequestGuildName:
+		var payload RequestGuildNamePayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case RequestGuildEmblem:
+		var payload RequestGuildEmblemPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case RequestGuildDisband:
+		var payload RequestGuildDisbandPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case RequestGuildCapacityIncrease:
+		var payload RequestGuildCapacityIncreasePayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
 	case CreateInvite:
 		var payload CreateInvitePayload
 		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
@@ -569,6 +1776,396 @@ func (s *Step[T]) UnmarshalJSON(data []byte) error {
 			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
 		}
 		s.Payload = any(payload).(T)
+	case SetQuestTimer:
+		var payload SetQuestTimerPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case AdjustPopularity:
+		var payload AdjustPopularityPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case ResolvePrizeTable:
+		var payload ResolvePrizeTablePayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case CharacterBuffCleanse:
+		var payload CharacterBuffCleansePayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case ResetSkillCooldowns:
+		var payload ResetSkillCooldownsPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case ModifyInventoryItemPosition:
+		var payload ModifyInventoryItemPositionPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case ApplyEquipmentPreset:
+		var payload ApplyEquipmentPresetPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case TransferCharacter:
+		var payload TransferCharacterPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case VerifyAccountMerge:
+		var payload VerifyAccountMergePayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case RestoreAsset:
+		var payload RestoreAssetPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case RollbackCharacterToSnapshot:
+		var payload RollbackCharacterToSnapshotPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case RestoreInventorySnapshot:
+		var payload RestoreInventorySnapshotPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case AdjustNpcShopStock:
+		var payload AdjustNpcShopStockPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case SetWorldEventFlag:
+		var payload SetWorldEventFlagPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case ApplyCharacterExpPenalty:
+		var payload ApplyCharacterExpPenaltyPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case ApplyDurabilityPenalty:
+		var payload ApplyDurabilityPenaltyPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case SetVariable:
+		var payload SetVariablePayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case HttpRequest:
+		var payload HttpRequestPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case CreateAccountCharacterSlot:
+		var payload CreateAccountCharacterSlotPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case EmitAnalyticsEvent:
+		var payload EmitAnalyticsEventPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case ModifyAssetExpiration:
+		var payload ModifyAssetExpirationPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case ApplyHammer:
+		var payload ApplyHammerPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case DeductMesos:
+		var payload DeductMesosPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case ForEach:
+		var payload ForEachPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case VerifyAndConsumeTicket:
+		var payload VerifyAndConsumeTicketPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case AwaitKillCount:
+		var payload AwaitKillCountPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case AdjustReactorState:
+		var payload AdjustReactorStatePayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case HitReactor:
+		var payload HitReactorPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case UpdateCharacterResource:
+		var payload UpdateCharacterResourcePayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case AwardAssetIf:
+		var payload AwardAssetIfPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case GrantPremiumTime:
+		var payload GrantPremiumTimePayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case ValidateDivorce:
+		var payload ValidateDivorcePayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case DissolveMarriage:
+		var payload DissolveMarriagePayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case SealAsset:
+		var payload SealAssetPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case UnsealAsset:
+		var payload UnsealAssetPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case ConfiscateAsset:
+		var payload ConfiscateAssetPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case ResolveDispute:
+		var payload ResolveDisputePayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case IssueTransportTicket:
+		var payload IssueTransportTicketPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case ScheduleWarp:
+		var payload ScheduleWarpPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case SpawnEscort:
+		var payload SpawnEscortPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case AwaitEscort:
+		var payload AwaitEscortPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case UpdateCharacterAlignment:
+		var payload UpdateCharacterAlignmentPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case ResetInstanceCooldown:
+		var payload ResetInstanceCooldownPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case AuditInventory:
+		var payload AuditInventoryPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case ApplyTitleBuffOnLogin:
+		var payload ApplyTitleBuffOnLoginPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case GrantMount:
+		var payload GrantMountPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case ApplyWorldEventBuff:
+		var payload ApplyWorldEventBuffPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case RemoveWorldEventBuff:
+		var payload RemoveWorldEventBuffPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case SettleEventCurrency:
+		var payload SettleEventCurrencyPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case CharacterExperienceLock:
+		var payload CharacterExperienceLockPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case CharacterExperienceUnlock:
+		var payload CharacterExperienceUnlockPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case NpcConversationState:
+		var payload NpcConversationStatePayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case EquipAssetByTemplate:
+		var payload EquipAssetByTemplatePayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case AwardPartyExperience:
+		var payload AwardPartyExperiencePayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case ToggleCharacterAbility:
+		var payload ToggleCharacterAbilityPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case GrantStorageCapacity:
+		var payload GrantStorageCapacityPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case StripEquipment:
+		var payload StripEquipmentPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case SnapshotCharacter:
+		var payload SnapshotCharacterPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case RestoreCharacter:
+		var payload RestoreCharacterPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case AwaitEvent:
+		var payload AwaitEventPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case Notify:
+		var payload NotifyPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case PetEquip:
+		var payload PetEquipPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case PetUnequip:
+		var payload PetUnequipPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case ValidateCoupon:
+		var payload ValidateCouponPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
+	case ConsumeCoupon:
+		var payload ConsumeCouponPayload
+		if err := json.Unmarshal(aux.Payload, &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload for action %s: %w", s.Action, err)
+		}
+		s.Payload = any(payload).(T)
 	default:
 		return fmt.Errorf("unknown action: %s", s.Action)
 	}
@@ -1150,4 +1150,45 @@ func TestSaga_CreateAndEquipAssetStateConsistency(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestStepErrorHandlerSerialization(t *testing.T) {
+	step := Step[any]{
+		StepId:  "award-item",
+		Status:  Pending,
+		Action:  AwardAsset,
+		Payload: AwardItemActionPayload{CharacterId: 12345, Item: ItemPayload{TemplateId: 2000000, Quantity: 1}},
+		OnError: []ErrorHandler{{
+			ErrorCode: "INVENTORY_FULL",
+			Reaction:  ErrorReactionRetry,
+			Steps: []Step[any]{
+				{StepId: "free-slot", Action: DestroyAsset, Payload: DestroyAssetPayload{CharacterId: 12345, TemplateId: 4000000, Quantity: 1}},
+			},
+		}},
+	}
+
+	data, err := json.Marshal(step)
+	if err != nil {
+		t.Fatalf("Failed to marshal step: %v", err)
+	}
+
+	var decoded Step[any]
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal step: %v", err)
+	}
+
+	s := Saga{Steps: []Step[any]{decoded}}
+	h, ok := s.FindErrorHandler(0, "INVENTORY_FULL")
+	if !ok {
+		t.Fatal("Expected error handler for INVENTORY_FULL")
+	}
+	if h.Reaction != ErrorReactionRetry || len(h.Steps) != 1 {
+		t.Errorf("Unexpected error handler: %+v", h)
+	}
+	if _, ok := h.Steps[0].Payload.(DestroyAssetPayload); !ok {
+		t.Errorf("Expected error handler step payload to be DestroyAssetPayload, got %T", h.Steps[0].Payload)
+	}
+	if _, ok := s.FindErrorHandler(0, "NAME_TAKEN"); ok {
+		t.Error("Expected no error handler for NAME_TAKEN")
+	}
+}
@@ -0,0 +1,82 @@
+package saga
+
+import (
+	"github.com/google/uuid"
+	"sync"
+)
+
+// Notifier is an interface for broadcasting sagas which reach a terminal state to waiting callers
+type Notifier interface {
+	// Subscribe registers interest in a saga reaching a terminal state. The returned function must be called to unsubscribe.
+	Subscribe(tenantId uuid.UUID, transactionId uuid.UUID) (<-chan Saga, func())
+
+	// Notify delivers the terminal state of a saga to all subscribers
+	Notify(tenantId uuid.UUID, saga Saga)
+}
+
+// InMemoryNotifier is an in-memory implementation of the Notifier interface
+type InMemoryNotifier struct {
+	// subscribers is a map of tenant IDs to maps of transaction IDs to subscriber channels
+	subscribers map[uuid.UUID]map[uuid.UUID]map[uint64]chan Saga
+
+	// sequence identifies individual subscriptions
+	sequence uint64
+
+	// mutex is used to synchronize access to the subscribers
+	mutex sync.Mutex
+}
+
+// Singleton instance of the notifier
+var notifierInstance *InMemoryNotifier
+var notifierOnce sync.Once
+
+// GetNotifier returns the singleton instance of the notifier
+func GetNotifier() Notifier {
+	notifierOnce.Do(func() {
+		notifierInstance = &InMemoryNotifier{
+			subscribers: make(map[uuid.UUID]map[uuid.UUID]map[uint64]chan Saga),
+		}
+	})
+	return notifierInstance
+}
+
+// Subscribe registers interest in a saga reaching a terminal state
+func (n *InMemoryNotifier) Subscribe(tenantId uuid.UUID, transactionId uuid.UUID) (<-chan Saga, func()) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	if _, exists := n.subscribers[tenantId]; !exists {
+		n.subscribers[tenantId] = make(map[uuid.UUID]map[uint64]chan Saga)
+	}
+	if _, exists := n.subscribers[tenantId][transactionId]; !exists {
+		n.subscribers[tenantId][transactionId] = make(map[uint64]chan Saga)
+	}
+
+	n.sequence++
+	id := n.sequence
+	c := make(chan Saga, 1)
+	n.subscribers[tenantId][transactionId][id] = c
+
+	return c, func() {
+		n.mutex.Lock()
+		defer n.mutex.Unlock()
+
+		delete(n.subscribers[tenantId][transactionId], id)
+		if len(n.subscribers[tenantId][transactionId]) == 0 {
+			delete(n.subscribers[tenantId], transactionId)
+		}
+	}
+}
+
+// Notify delivers the terminal state of a saga to all subscribers without blocking
+func (n *InMemoryNotifier) Notify(tenantId uuid.UUID, saga Saga) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	for _, c := range n.subscribers[tenantId][saga.TransactionId] {
+		select {
+		case c <- saga:
+		default:
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package saga
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+// TestNotifier tests that terminal sagas are delivered only to subscribers of the same tenant and transaction
+func TestNotifier(t *testing.T) {
+	tenantId := uuid.New()
+	transactionId := uuid.New()
+
+	c, unsubscribe := GetNotifier().Subscribe(tenantId, transactionId)
+	other, unsubscribeOther := GetNotifier().Subscribe(uuid.New(), transactionId)
+	defer unsubscribeOther()
+
+	GetNotifier().Notify(tenantId, Saga{TransactionId: transactionId, SagaType: QuestReward})
+
+	select {
+	case s := <-c:
+		assert.Equal(t, transactionId, s.TransactionId)
+	default:
+		t.Fatal("subscriber was not notified")
+	}
+	assert.Len(t, other, 0)
+
+	unsubscribe()
+	GetNotifier().Notify(tenantId, Saga{TransactionId: transactionId})
+	assert.Len(t, c, 0)
+}
+
+// TestAwaitTerminal tests waiting for a saga to complete or fail
+func TestAwaitTerminal(t *testing.T) {
+	te, ctx := setupContext()
+	processor, _ := setupTestProcessor(ctx, nil, nil)
+
+	pendingSaga := func() Saga {
+		return Saga{
+			TransactionId: uuid.New(),
+			SagaType:      QuestReward,
+			InitiatedBy:   "test",
+			Steps: []Step[any]{
+				{StepId: "step-1", Status: Pending, Action: AwardMesos, Payload: AwardMesosPayload{CharacterId: 1, Amount: 10}},
+			},
+		}
+	}
+
+	t.Run("unknown saga returns an error", func(t *testing.T) {
+		_, err := processor.AwaitTerminal(ctx, uuid.New(), time.Second)
+		assert.Error(t, err)
+	})
+
+	t.Run("failing saga returns immediately", func(t *testing.T) {
+		s := pendingSaga()
+		s.Steps[0].Status = Failed
+		GetCache().Put(te.Id(), s)
+		defer GetCache().Remove(te.Id(), s.TransactionId)
+
+		start := time.Now()
+		rs, err := processor.AwaitTerminal(ctx, s.TransactionId, 5*time.Second)
+		require.NoError(t, err)
+		assert.True(t, rs.Failing())
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("timeout returns the current state", func(t *testing.T) {
+		s := pendingSaga()
+		GetCache().Put(te.Id(), s)
+		defer GetCache().Remove(te.Id(), s.TransactionId)
+
+		rs, err := processor.AwaitTerminal(ctx, s.TransactionId, 20*time.Millisecond)
+		require.NoError(t, err)
+		assert.Equal(t, Pending, rs.Steps[0].Status)
+	})
+
+	t.Run("cancelled request returns the current state", func(t *testing.T) {
+		s := pendingSaga()
+		GetCache().Put(te.Id(), s)
+		defer GetCache().Remove(te.Id(), s.TransactionId)
+
+		rctx, cancel := context.WithCancel(ctx)
+		time.AfterFunc(20*time.Millisecond, cancel)
+
+		start := time.Now()
+		rs, err := processor.AwaitTerminal(rctx, s.TransactionId, 5*time.Second)
+		require.NoError(t, err)
+		assert.Equal(t, Pending, rs.Steps[0].Status)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("completion is delivered to the waiter", func(t *testing.T) {
+		s := pendingSaga()
+		GetCache().Put(te.Id(), s)
+
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			cs := s
+			cs.Steps = []Step[any]{s.Steps[0]}
+			cs.Steps[0].Status = Completed
+			GetCache().Remove(te.Id(), s.TransactionId)
+			GetNotifier().Notify(te.Id(), cs)
+		}()
+
+		rs, err := processor.AwaitTerminal(ctx, s.TransactionId, 5*time.Second)
+		require.NoError(t, err)
+		assert.Equal(t, Completed, rs.Steps[0].Status)
+	})
+}
@@ -0,0 +1,155 @@
+package saga
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrorCodeCharacterOffline is the error code a step fails with when dispatched while the character it affects is
+// offline, and its action's offline policy is to fail
+const ErrorCodeCharacterOffline = "CHARACTER_OFFLINE"
+
+// OfflinePolicy declares how a step affecting a character is dispatched while the character is offline
+type OfflinePolicy string
+
+// Constants for offline policies
+const (
+	OfflinePersistent OfflinePolicy = "persistent" // The step is dispatched, acting on the character's persistent state
+	OfflineQueue      OfflinePolicy = "queue"      // The saga is held until the character logs in
+	OfflineFail       OfflinePolicy = "fail"       // The step fails with CHARACTER_OFFLINE
+)
+
+// OfflineConfig configures the offline policies of actions. Actions without a policy act on persistent state.
+type OfflineConfig struct {
+	Policies map[Action]OfflinePolicy
+}
+
+// OfflineConfigFromEnv loads the offline policy configuration from the environment
+func OfflineConfigFromEnv() (OfflineConfig, error) {
+	c := OfflineConfig{Policies: make(map[Action]OfflinePolicy)}
+
+	// Policies are expressed as a comma-separated list of action=policy pairs (e.g. award_mesos=queue,change_job=fail)
+	v := os.Getenv("SAGA_OFFLINE_POLICIES")
+	if v == "" {
+		return c, nil
+	}
+	for _, entry := range strings.Split(v, ",") {
+		action, policy, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || action == "" {
+			return OfflineConfig{}, fmt.Errorf("invalid SAGA_OFFLINE_POLICIES entry '%s'", entry)
+		}
+		switch p := OfflinePolicy(policy); p {
+		case OfflinePersistent, OfflineQueue, OfflineFail:
+			c.Policies[Action(action)] = p
+		default:
+			return OfflineConfig{}, fmt.Errorf("invalid SAGA_OFFLINE_POLICIES policy '%s' of action '%s'", policy, action)
+		}
+	}
+	return c, nil
+}
+
+// Singleton offline policy configuration, which dispatches every step regardless of presence until initialized
+var offlineConfig OfflineConfig
+
+// InitOfflineConfig replaces the singleton offline policy configuration
+func InitOfflineConfig(config OfflineConfig) {
+	offlineConfig = config
+}
+
+// GetOfflineConfig returns the singleton offline policy configuration
+func GetOfflineConfig() OfflineConfig {
+	return offlineConfig
+}
+
+// Policy returns the offline policy of the action
+func (c OfflineConfig) Policy(action Action) OfflinePolicy {
+	if p, ok := c.Policies[action]; ok {
+		return p
+	}
+	return OfflinePersistent
+}
+
+// stepCharacterId returns the character the step affects, being the CharacterId of its payload, if any
+func stepCharacterId(st Step[any]) (uint32, bool) {
+	v := reflect.ValueOf(st.Payload)
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return 0, false
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return 0, false
+	}
+	f := v.FieldByName("CharacterId")
+	if !f.IsValid() || f.Kind() != reflect.Uint32 || f.Uint() == 0 {
+		return 0, false
+	}
+	return uint32(f.Uint()), true
+}
+
+// offlinePolicy returns the offline policy to apply to the step, and the character it affects, when that character is
+// offline. Presence is only looked up for actions whose policy is not to act on persistent state. A presence which
+// cannot be looked up is treated as online, so the step is dispatched as it would be without a policy.
+func (p *ProcessorImpl) offlinePolicy(s Saga, st Step[any]) (OfflinePolicy, uint32, bool) {
+	policy := GetOfflineConfig().Policy(st.Action)
+	if policy == OfflinePersistent {
+		return "", 0, false
+	}
+	characterId, ok := stepCharacterId(st)
+	if !ok {
+		return "", 0, false
+	}
+	online, err := p.sessP.IsLoggedIn(characterId)
+	if err != nil {
+		p.l.WithFields(logrus.Fields{
+			"transaction_id": s.TransactionId.String(),
+			"saga_type":      s.SagaType,
+			"step_id":        st.StepId,
+			"character_id":   characterId,
+			"tenant_id":      p.t.Id().String(),
+		}).WithError(err).Warn("Unable to look up presence of character. Dispatching step.")
+		return "", 0, false
+	}
+	if online {
+		return "", 0, false
+	}
+	return policy, characterId, true
+}
+
+// CharacterLoggedIn resumes the sagas held until the character logs in, dispatching their current steps
+func (p *ProcessorImpl) CharacterLoggedIn(characterId uint32) error {
+	for _, s := range GetCache().GetAll(p.t.Id()) {
+		if s.Hold != AwaitingLogin || s.Failing() {
+			continue
+		}
+		st, ok := s.GetCurrentStep()
+		if !ok {
+			continue
+		}
+		if id, ok := stepCharacterId(st); !ok || id != characterId {
+			continue
+		}
+
+		s.Hold = ""
+		s.HoldReason = ""
+		GetCache().Put(p.t.Id(), s)
+
+		fl := p.l.WithFields(logrus.Fields{
+			"transaction_id": s.TransactionId.String(),
+			"saga_type":      s.SagaType,
+			"step_id":        st.StepId,
+			"character_id":   characterId,
+			"tenant_id":      p.t.Id().String(),
+		})
+		fl.Debug("Character logged in. Resuming saga.")
+		if err := p.Step(s.TransactionId); err != nil {
+			fl.WithError(err).Error("Unable to resume saga awaiting login.")
+		}
+	}
+	return nil
+}
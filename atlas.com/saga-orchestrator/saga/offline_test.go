@@ -0,0 +1,125 @@
+package saga
+
+import (
+	"atlas-saga-orchestrator/character/mock"
+	mock15 "atlas-saga-orchestrator/session/mock"
+	"testing"
+	"time"
+
+	"github.com/Chronicle20/atlas-constants/channel"
+	"github.com/Chronicle20/atlas-constants/world"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOfflineConfigFromEnv tests loading the offline policy configuration from the environment
+func TestOfflineConfigFromEnv(t *testing.T) {
+	c, err := OfflineConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, OfflinePersistent, c.Policy(AwardMesos))
+
+	t.Setenv("SAGA_OFFLINE_POLICIES", "award_mesos=queue, change_job=fail")
+	c, err = OfflineConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, OfflineQueue, c.Policy(AwardMesos))
+	assert.Equal(t, OfflineFail, c.Policy(ChangeJob))
+	assert.Equal(t, OfflinePersistent, c.Policy(AwardAsset))
+
+	for _, v := range []string{"award_mesos", "=queue", "award_mesos=later"} {
+		t.Setenv("SAGA_OFFLINE_POLICIES", v)
+		_, err = OfflineConfigFromEnv()
+		assert.Error(t, err, v)
+	}
+}
+
+// TestStepCharacterId tests that the character a step affects is found on its payload
+func TestStepCharacterId(t *testing.T) {
+	id, ok := stepCharacterId(Step[any]{Payload: AwardMesosPayload{CharacterId: 12345}})
+	assert.True(t, ok)
+	assert.Equal(t, uint32(12345), id)
+
+	_, ok = stepCharacterId(Step[any]{Payload: AwardMesosPayload{}})
+	assert.False(t, ok)
+
+	_, ok = stepCharacterId(Step[any]{Payload: map[string]any{"characterId": 12345}})
+	assert.False(t, ok)
+}
+
+// TestOfflinePolicy tests that steps affecting an offline character are held until the character logs in, or failed,
+// as their action's offline policy declares
+func TestOfflinePolicy(t *testing.T) {
+	InitOfflineConfig(OfflineConfig{Policies: map[Action]OfflinePolicy{AwardMesos: OfflineQueue, ChangeJob: OfflineFail}})
+	defer InitOfflineConfig(OfflineConfig{})
+
+	te, ctx := setupContext()
+	dispatched := 0
+	charP := &mock.ProcessorMock{
+		AwardMesosAndEmitFunc: func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, actorId uint32, actorType string, amount int32) error {
+			dispatched++
+			return nil
+		},
+	}
+	online := map[uint32]bool{}
+	sessP := &mock15.ProcessorMock{
+		IsLoggedInFunc: func(characterId uint32) (bool, error) {
+			return online[characterId], nil
+		},
+	}
+	base, _ := setupTestProcessor(ctx, charP, nil)
+	processor := base.WithSessionProcessor(sessP)
+
+	t.Run("queued steps are held until the character logs in", func(t *testing.T) {
+		s := NewBuilder().
+			SetSagaType(QuestReward).
+			AddStep("mesos", Pending, AwardMesos, AwardMesosPayload{CharacterId: 12345, ActorType: "SYSTEM", Amount: 1000}).
+			AddStep("variable", Pending, SetVariable, SetVariablePayload{Name: "done", Value: true}).
+			Build()
+		defer GetCache().Remove(te.Id(), s.TransactionId)
+		require.NoError(t, processor.Put(s))
+
+		cs, err := processor.GetById(s.TransactionId)
+		require.NoError(t, err)
+		assert.Equal(t, AwaitingLogin, cs.Hold)
+		assert.Equal(t, "character [12345] is offline", cs.HoldReason)
+		assert.Empty(t, cs.Steps[0].Attempts)
+		assert.Equal(t, 0, dispatched)
+
+		// Logins of other characters leave the saga held
+		online[54321] = true
+		require.NoError(t, processor.CharacterLoggedIn(54321))
+		cs, err = processor.GetById(s.TransactionId)
+		require.NoError(t, err)
+		assert.Equal(t, AwaitingLogin, cs.Hold)
+
+		online[12345] = true
+		require.NoError(t, processor.CharacterLoggedIn(12345))
+		cs, err = processor.GetById(s.TransactionId)
+		require.NoError(t, err)
+		assert.False(t, cs.Held())
+		assert.Len(t, cs.Steps[0].Attempts, 1)
+		assert.Equal(t, 1, dispatched)
+	})
+
+	t.Run("failing steps fail with CHARACTER_OFFLINE", func(t *testing.T) {
+		s := NewBuilder().
+			SetSagaType(QuestReward).
+			AddStep("job", Pending, ChangeJob, ChangeJobPayload{CharacterId: 67890, JobId: 100}).
+			Build()
+		defer GetCache().Remove(te.Id(), s.TransactionId)
+
+		c, unsubscribe := GetNotifier().Subscribe(te.Id(), s.TransactionId)
+		defer unsubscribe()
+		require.NoError(t, processor.Put(s))
+
+		var cs Saga
+		select {
+		case cs = <-c:
+		case <-time.After(time.Second):
+			t.Fatal("saga did not end")
+		}
+		require.Len(t, cs.Steps[0].Attempts, 1)
+		assert.Equal(t, ErrorCodeCharacterOffline, cs.Steps[0].Attempts[0].ErrorCode)
+		assert.False(t, cs.Held())
+	})
+}
@@ -0,0 +1,126 @@
+package saga
+
+import (
+	"atlas-saga-orchestrator/kafka/message"
+	"atlas-saga-orchestrator/kafka/producer"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+	"github.com/sirupsen/logrus"
+)
+
+// OutboxMessage is a command of a step remaining to be produced. Steps producing several commands, such as compound
+// actions and fan-out, record them before producing any, so a step interrupted part way produces only those remaining
+// when redelivered, rather than instructing downstream services again.
+type OutboxMessage struct {
+	Topic string          `json:"topic"`         // Token of the topic the command is produced to (e.g. COMMAND_TOPIC_BUFF)
+	Key   []byte          `json:"key,omitempty"` // Key of the command
+	Value json.RawMessage `json:"value"`         // Body of the command
+}
+
+func toOutbox(entries []producer.Entry) []OutboxMessage {
+	r := make([]OutboxMessage, 0, len(entries))
+	for _, e := range entries {
+		r = append(r, OutboxMessage{Topic: e.Token, Key: e.Message.Key, Value: e.Message.Value})
+	}
+	return r
+}
+
+func fromOutbox(ms []OutboxMessage) []producer.Entry {
+	r := make([]producer.Entry, 0, len(ms))
+	for _, m := range ms {
+		r = append(r, producer.Entry{Token: m.Topic, Message: kafka.Message{Key: m.Key, Value: m.Value}})
+	}
+	return r
+}
+
+// dispatch executes the handler of the step, collecting the commands it produces and producing them only once it
+// succeeds. A handler failing part way produces none of its commands.
+func (p *ProcessorImpl) dispatch(s Saga, st Step[any], handler ActionHandler) error {
+	b, end := producer.Begin(p.ctx)
+	err := func() error {
+		// A panicking handler must not leave the batch collecting the commands produced after it
+		defer end()
+		return handler(s, st)
+	}()
+	if err != nil {
+		if n := len(b.Entries()); n > 0 {
+			p.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"step_id":        st.StepId,
+				"tenant_id":      p.t.Id().String(),
+			}).Debugf("Discarding [%d] commands of failed saga step.", n)
+		}
+		return err
+	}
+	ms := toOutbox(b.Entries())
+	if len(ms) > 1 {
+		p.setOutbox(s.TransactionId, st.StepId, ms)
+		if err = persist(p.l, p.t.Id(), s.TransactionId); err != nil {
+			return err
+		}
+	}
+	return p.flushOutbox(s.TransactionId, st.StepId, ms, len(ms) > 1)
+}
+
+// compensate compensates the failed step of the saga, producing the commands reversing it only once compensation
+// succeeds, so a compensation failing part way reverses nothing. Returns the number of commands produced.
+func (p *ProcessorImpl) compensate(s Saga) (int, error) {
+	b, end := producer.Begin(p.ctx)
+	err := func() error {
+		defer end()
+		return p.comp.CompensateFailedStep(s)
+	}()
+	if err != nil {
+		return 0, err
+	}
+	written, err := producer.Write(p.l)(p.ctx)(b.Entries())
+	if err != nil {
+		return written, fmt.Errorf("%w for compensation: %w", message.ErrProduce, err)
+	}
+	return written, nil
+}
+
+// flushOutbox produces the commands of the step in order. Steps producing several commands record them on the step
+// before any are produced, and those remaining until all are, so a step parked for redelivery resumes where producing
+// them was interrupted.
+func (p *ProcessorImpl) flushOutbox(transactionId uuid.UUID, stepId string, ms []OutboxMessage, record bool) error {
+	if len(ms) == 0 {
+		return nil
+	}
+
+	written, err := producer.Write(p.l)(p.ctx)(fromOutbox(ms))
+	if err != nil {
+		if record {
+			p.setOutbox(transactionId, stepId, ms[written:])
+		}
+		p.l.WithFields(logrus.Fields{
+			"transaction_id": transactionId.String(),
+			"step_id":        stepId,
+			"tenant_id":      p.t.Id().String(),
+		}).WithError(err).Warnf("Produced [%d] of [%d] commands of saga step.", written, len(ms))
+		return fmt.Errorf("%w for step [%s]: %w", message.ErrProduce, stepId, err)
+	}
+	if record {
+		p.setOutbox(transactionId, stepId, nil)
+	}
+	return nil
+}
+
+// setOutbox records the commands of the step remaining to be produced
+func (p *ProcessorImpl) setOutbox(transactionId uuid.UUID, stepId string, ms []OutboxMessage) {
+	s, err := p.GetById(transactionId)
+	if err != nil {
+		return
+	}
+	idx := s.FindStepIndex(stepId)
+	if idx == -1 {
+		return
+	}
+	s.Steps = append([]Step[any]{}, s.Steps...)
+	s.Steps[idx].Outbox = append([]OutboxMessage(nil), ms...)
+	GetCache().Put(p.t.Id(), s)
+}
@@ -0,0 +1,126 @@
+package saga
+
+import (
+	"atlas-saga-orchestrator/character/mock"
+	"atlas-saga-orchestrator/kafka/producer"
+	"errors"
+	"github.com/Chronicle20/atlas-constants/channel"
+	"github.com/Chronicle20/atlas-constants/world"
+	producer2 "github.com/Chronicle20/atlas-kafka/producer"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+// TestTransactionalDispatch tests that the commands of a step producing several are produced all-or-nothing, and that
+// a step interrupted part way produces only those remaining when redelivered
+func TestTransactionalDispatch(t *testing.T) {
+	te, ctx := setupContext()
+	defer ResetRetryQueue()
+	defer InitRetryConfig(DefaultRetryConfig)
+	InitRetryConfig(RetryConfig{BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, MaxAttempts: 3})
+
+	written := make([]string, 0)
+	unavailable := ""
+	ctx = producer.WithProvider(producer.WithOutbox(ctx), func(token string) producer2.MessageProducer {
+		return func(provider model.Provider[[]kafka.Message]) error {
+			if token == unavailable {
+				return errors.New("leader not available")
+			}
+			ms, err := provider()
+			if err != nil {
+				return err
+			}
+			for _, m := range ms {
+				written = append(written, token+":"+string(m.Value))
+			}
+			return nil
+		}
+	})
+
+	// The handler produces a command to each of two topics, as a compound action does
+	l, _ := test.NewNullLogger()
+	dispatched := 0
+	var handlerErr error
+	charP := &mock.ProcessorMock{
+		AwardMesosAndEmitFunc: func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, actorId uint32, actorType string, amount int32) error {
+			dispatched++
+			for _, token := range []string{"COMMAND_TOPIC_CHARACTER", "EVENT_TOPIC_ANALYTICS"} {
+				if err := producer.ProviderImpl(l)(ctx)(token)(model.FixedProvider([]kafka.Message{{Value: []byte(`{"amount":1000}`)}})); err != nil {
+					return err
+				}
+			}
+			return handlerErr
+		},
+	}
+	processor, _ := setupTestProcessor(ctx, charP, nil)
+	build := func() Saga {
+		return NewBuilder().
+			SetSagaType(QuestReward).
+			AddStep("mesos", Pending, AwardMesos, AwardMesosPayload{CharacterId: 12345, ActorType: "NPC", Amount: 1000}).
+			Build()
+	}
+	reset := func() {
+		written = written[:0]
+		dispatched = 0
+		unavailable = ""
+		handlerErr = nil
+	}
+
+	t.Run("commands produced once the handler succeeds", func(t *testing.T) {
+		reset()
+		s := build()
+		require.NoError(t, processor.Put(s))
+		defer GetCache().Remove(te.Id(), s.TransactionId)
+
+		assert.Equal(t, []string{`COMMAND_TOPIC_CHARACTER:{"amount":1000}`, `EVENT_TOPIC_ANALYTICS:{"amount":1000}`}, written)
+		s, err := processor.GetById(s.TransactionId)
+		require.NoError(t, err)
+		assert.Empty(t, s.Steps[0].Outbox)
+		assert.Equal(t, Pending, s.Steps[0].Status)
+	})
+
+	t.Run("commands of a failed handler are discarded", func(t *testing.T) {
+		reset()
+		handlerErr = errors.New("character not found")
+		s := build()
+		assert.Error(t, processor.Put(s))
+		defer GetCache().Remove(te.Id(), s.TransactionId)
+		assert.Empty(t, written)
+	})
+
+	t.Run("interrupted step produces the remaining commands when redelivered", func(t *testing.T) {
+		reset()
+		unavailable = "EVENT_TOPIC_ANALYTICS"
+		s := build()
+		require.NoError(t, processor.Put(s))
+		defer GetCache().Remove(te.Id(), s.TransactionId)
+
+		assert.Equal(t, []string{`COMMAND_TOPIC_CHARACTER:{"amount":1000}`}, written)
+		s, err := processor.GetById(s.TransactionId)
+		require.NoError(t, err)
+		require.Len(t, s.Steps[0].Outbox, 1)
+		assert.Equal(t, "EVENT_TOPIC_ANALYTICS", s.Steps[0].Outbox[0].Topic)
+		assert.Equal(t, ErrorCodeDispatchFailed, s.Steps[0].Attempts[0].ErrorCode)
+		assert.Equal(t, 1, GetRetryQueue().Depth()[te.Id()])
+
+		unavailable = ""
+		time.Sleep(5 * time.Millisecond)
+		for _, e := range GetRetryQueue().Due(time.Now()) {
+			redeliver(processor.(*ProcessorImpl).l, processor, e)
+		}
+		assert.Equal(t, 1, dispatched)
+		assert.Equal(t, []string{`COMMAND_TOPIC_CHARACTER:{"amount":1000}`, `EVENT_TOPIC_ANALYTICS:{"amount":1000}`}, written)
+		s, err = processor.GetById(s.TransactionId)
+		require.NoError(t, err)
+		assert.Empty(t, s.Steps[0].Outbox)
+		require.Len(t, s.Steps[0].Attempts, 2)
+		assert.Empty(t, s.Steps[0].Attempts[1].ErrorCode)
+		assert.Equal(t, Pending, s.Steps[0].Status)
+	})
+}
@@ -0,0 +1,58 @@
+package saga
+
+import (
+	"atlas-saga-orchestrator/cluster"
+	"atlas-saga-orchestrator/kafka/message/saga"
+	"atlas-saga-orchestrator/kafka/producer"
+
+	"github.com/sirupsen/logrus"
+)
+
+// forward produces a saga owned by another replica as a command, so the replica owning it, by consistent hashing of
+// its transaction ID, executes it. Replicas each consume every command, executing only those of the sagas they own.
+func (p *ProcessorImpl) forward(s Saga) error {
+	p.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"owner":          cluster.GetMembership().Owner(s.TransactionId),
+		"tenant_id":      p.t.Id().String(),
+	}).Debug("Forwarding saga to the replica owning it.")
+	return producer.ProviderImpl(p.l)(p.ctx)(saga.EnvCommandTopic)(CommandProvider(s))
+}
+
+// InitOwnership rebalances the sagas held by this replica as replicas join or depart. Sagas are kept in the archive
+// beyond the cache, so those no longer owned are released, and those now owned hydrated. Without an archive, sagas
+// cannot move between replicas, so those held are executed until they finish, and only new sagas are rebalanced.
+func InitOwnership(l logrus.FieldLogger) {
+	cluster.GetMembership().OnRebalance(func(members []string) {
+		l.Infof("Rebalancing saga ownership between [%d] replicas %v.", len(members), members)
+		if _, ok := GetArchive(); !ok {
+			return
+		}
+		released := releaseUnowned()
+		if released > 0 {
+			l.Infof("Released [%d] sagas now owned by other replicas.", released)
+		}
+		if err := WarmCache(l); err != nil {
+			l.WithError(err).Error("Unable to hydrate sagas now owned.")
+		}
+	})
+}
+
+// releaseUnowned removes the sagas no longer owned by this replica from the cache, along with their timers and parked
+// redeliveries, returning the number released
+func releaseUnowned() int {
+	released := 0
+	for _, tenantId := range GetCache().Tenants() {
+		for _, s := range GetCache().GetAll(tenantId) {
+			if cluster.GetMembership().Owns(s.TransactionId) {
+				continue
+			}
+			GetCache().Remove(tenantId, s.TransactionId)
+			GetTimerRegistry().Cancel(tenantId, s.TransactionId)
+			GetRetryQueue().Remove(tenantId, s.TransactionId)
+			released++
+		}
+	}
+	return released
+}
@@ -0,0 +1,132 @@
+package saga
+
+import (
+	"atlas-saga-orchestrator/character/mock"
+	"atlas-saga-orchestrator/cluster"
+	"atlas-saga-orchestrator/kafka/message/saga"
+	"atlas-saga-orchestrator/kafka/producer"
+	"encoding/json"
+	producer2 "github.com/Chronicle20/atlas-kafka/producer"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+// TestOwnership tests that sagas are executed only by the replica owning them, being forwarded by others, and that
+// sagas are rebalanced between replicas as they join
+func TestOwnership(t *testing.T) {
+	te, ctx := setupContext()
+	defer cluster.InitMembership(cluster.Config{})
+	defer InitArchive(nil)
+	cluster.InitMembership(cluster.Config{ReplicaId: "a", HeartbeatInterval: time.Second, MemberTtl: 3 * time.Second})
+	cluster.GetMembership().Observe("b", time.Now())
+	cluster.GetMembership().Announced(time.Now())
+	cluster.GetMembership().Settle(time.Now().Add(time.Second))
+
+	forwarded := make([]Saga, 0)
+	ctx = producer.WithProvider(ctx, func(token string) producer2.MessageProducer {
+		return func(provider model.Provider[[]kafka.Message]) error {
+			ms, err := provider()
+			if err != nil {
+				return err
+			}
+			for _, m := range ms {
+				if token == saga.EnvCommandTopic {
+					var s Saga
+					require.NoError(t, json.Unmarshal(m.Value, &s))
+					forwarded = append(forwarded, s)
+				}
+			}
+			return nil
+		}
+	})
+	processor, _ := setupTestProcessor(ctx, &mock.ProcessorMock{}, nil)
+
+	owned := func(replicaId string) uuid.UUID {
+		for {
+			id := uuid.New()
+			if cluster.GetMembership().Owner(id) == replicaId {
+				return id
+			}
+		}
+	}
+	build := func(transactionId uuid.UUID) Saga {
+		return NewBuilder().
+			SetTransactionId(transactionId).
+			SetSagaType(QuestReward).
+			AddStep("set", Pending, SetVariable, SetVariablePayload{Name: "done", Value: true}).
+			AddStep("mesos", Pending, AwardMesos, AwardMesosPayload{CharacterId: 12345, ActorType: "NPC", Amount: 1000}).
+			Build()
+	}
+
+	t.Run("sagas owned by another replica are forwarded", func(t *testing.T) {
+		s := build(owned("b"))
+		require.NoError(t, processor.Put(s))
+		_, ok := GetCache().GetById(te.Id(), s.TransactionId)
+		assert.False(t, ok)
+		require.Len(t, forwarded, 1)
+		assert.Equal(t, s.TransactionId, forwarded[0].TransactionId)
+		assert.Equal(t, Pending, forwarded[0].Steps[0].Status)
+	})
+
+	t.Run("owned sagas are executed", func(t *testing.T) {
+		forwarded = forwarded[:0]
+		s := build(owned("a"))
+		require.NoError(t, processor.Put(s))
+		defer GetCache().Remove(te.Id(), s.TransactionId)
+		assert.Empty(t, forwarded)
+		s, err := processor.GetById(s.TransactionId)
+		require.NoError(t, err)
+		assert.Equal(t, Completed, s.Steps[0].Status)
+	})
+
+	t.Run("sagas are rebalanced through the archive as replicas join and leave", func(t *testing.T) {
+		l, _ := test.NewNullLogger()
+		InitOwnership(l)
+
+		// Find sagas owned by each replica as replica c joins, then replica b leaves
+		owners := func(id uuid.UUID) []string {
+			r := make([]string, 0, 3)
+			for _, members := range [][]string{{"a", "b"}, {"a", "b", "c"}, {"a", "c"}} {
+				o, _ := cluster.NewRing(members, cluster.DefaultVirtualNodes).Owner(id.String())
+				r = append(r, o)
+			}
+			return r
+		}
+		find := func(expected ...string) Saga {
+			for {
+				id := uuid.New()
+				if o := owners(id); o[0] == expected[0] && o[1] == expected[1] && o[2] == expected[2] {
+					return build(id)
+				}
+			}
+		}
+		kept := find("a", "a", "a")
+		moving := find("a", "c", "c")
+		gained := find("b", "b", "a")
+		GetCache().Put(te.Id(), kept)
+		GetCache().Put(te.Id(), moving)
+		defer GetCache().Remove(te.Id(), kept.TransactionId)
+		defer GetCache().Remove(te.Id(), gained.TransactionId)
+		InitArchive(&testArchive{t: te, sagas: map[uuid.UUID]Saga{kept.TransactionId: kept, moving.TransactionId: moving, gained.TransactionId: gained}})
+
+		cached := func(s Saga) bool {
+			_, ok := GetCache().GetById(te.Id(), s.TransactionId)
+			return ok
+		}
+		cluster.GetMembership().Observe("c", time.Now())
+		assert.True(t, cached(kept))
+		assert.False(t, cached(moving))
+		assert.False(t, cached(gained))
+
+		cluster.GetMembership().Leave("b")
+		assert.True(t, cached(kept))
+		assert.False(t, cached(moving))
+		assert.True(t, cached(gained))
+	})
+}
@@ -0,0 +1,204 @@
+package saga
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultReviewWindow is the window over which awards to a character are accumulated when none is configured
+const DefaultReviewWindow = time.Hour
+
+// RewardPolicy is an interface for checks invoked before award steps are executed, which may hold sagas for review
+type RewardPolicy interface {
+	// Evaluate returns a reason, and true, when the award step should be held for review
+	Evaluate(tenantId uuid.UUID, s Saga, st Step[any]) (string, bool)
+
+	// Record accounts an award step which has been executed
+	Record(tenantId uuid.UUID, s Saga, st Step[any])
+}
+
+// IsAward returns whether an action grants a reward which is subject to the reward policy
+func IsAward(action Action) bool {
+	switch action {
+	case AwardMesos, AwardAsset, AwardInventory, AwardAssetIf:
+		return true
+	}
+	return false
+}
+
+// ThresholdPolicyConfig configures the awards a character may receive within a window before being held. A threshold
+// of 0 is unlimited.
+type ThresholdPolicyConfig struct {
+	Window         time.Duration     // Window over which awards to a character are accumulated
+	MesoThreshold  uint64            // MesoThreshold is the most mesos a character may be awarded within the window
+	ItemThresholds map[uint32]uint64 // ItemThresholds is the most of an item template a character may be awarded within the window
+}
+
+// ThresholdPolicyConfigFromEnv loads the threshold policy configuration from the environment
+func ThresholdPolicyConfigFromEnv() (ThresholdPolicyConfig, error) {
+	c := ThresholdPolicyConfig{Window: DefaultReviewWindow, ItemThresholds: make(map[uint32]uint64)}
+
+	if v := os.Getenv("SAGA_REVIEW_WINDOW"); v != "" {
+		w, err := time.ParseDuration(v)
+		if err != nil || w <= 0 {
+			return ThresholdPolicyConfig{}, fmt.Errorf("invalid SAGA_REVIEW_WINDOW '%s'", v)
+		}
+		c.Window = w
+	}
+
+	var err error
+	if c.MesoThreshold, err = parseUintEnv("SAGA_REVIEW_MESO_THRESHOLD"); err != nil {
+		return ThresholdPolicyConfig{}, err
+	}
+
+	// Item thresholds are expressed as a comma-separated list of templateId=quantity pairs (e.g. 2049100=5)
+	if v := os.Getenv("SAGA_REVIEW_ITEM_THRESHOLDS"); v != "" {
+		for _, entry := range strings.Split(v, ",") {
+			templateId, quantity, ok := strings.Cut(strings.TrimSpace(entry), "=")
+			if !ok {
+				return ThresholdPolicyConfig{}, fmt.Errorf("invalid SAGA_REVIEW_ITEM_THRESHOLDS entry '%s'", entry)
+			}
+			id, err := strconv.ParseUint(templateId, 10, 32)
+			if err != nil {
+				return ThresholdPolicyConfig{}, fmt.Errorf("invalid SAGA_REVIEW_ITEM_THRESHOLDS entry '%s'", entry)
+			}
+			q, err := strconv.ParseUint(quantity, 10, 64)
+			if err != nil {
+				return ThresholdPolicyConfig{}, fmt.Errorf("invalid SAGA_REVIEW_ITEM_THRESHOLDS entry '%s'", entry)
+			}
+			c.ItemThresholds[uint32(id)] = q
+		}
+	}
+
+	return c, nil
+}
+
+// award is an amount awarded to a character at a point in time
+type award struct {
+	at     time.Time
+	amount uint64
+}
+
+// awardKey identifies what is awarded to a character. A template of 0 denotes mesos.
+type awardKey struct {
+	tenantId    uuid.UUID
+	characterId uint32
+	templateId  uint32
+}
+
+// ThresholdPolicy is a RewardPolicy which holds awards pushing a character over a threshold within a sliding window
+type ThresholdPolicy struct {
+	config ThresholdPolicyConfig
+
+	// awards is a map of what is awarded to characters to the awards made within the window, oldest first
+	awards map[awardKey][]award
+
+	// now returns the current time
+	now func() time.Time
+
+	// mutex is used to synchronize access to the awards
+	mutex sync.Mutex
+}
+
+// NewThresholdPolicy creates a new threshold policy
+func NewThresholdPolicy(config ThresholdPolicyConfig) *ThresholdPolicy {
+	if config.Window <= 0 {
+		config.Window = DefaultReviewWindow
+	}
+	return &ThresholdPolicy{
+		config: config,
+		awards: make(map[awardKey][]award),
+		now:    time.Now,
+	}
+}
+
+// Singleton instance of the reward policy, which holds nothing until initialized
+var rewardPolicyInstance RewardPolicy = NewThresholdPolicy(ThresholdPolicyConfig{})
+
+// InitRewardPolicy replaces the singleton reward policy
+func InitRewardPolicy(p RewardPolicy) {
+	rewardPolicyInstance = p
+}
+
+// GetRewardPolicy returns the singleton instance of the reward policy
+func GetRewardPolicy() RewardPolicy {
+	return rewardPolicyInstance
+}
+
+// awarded returns what an award step grants, and the threshold which applies to it
+func (p *ThresholdPolicy) awarded(tenantId uuid.UUID, st Step[any]) (awardKey, uint64, uint64, bool) {
+	switch payload := st.Payload.(type) {
+	case AwardAssetIfPayload:
+		return p.awarded(tenantId, Step[any]{Payload: payload.Award()})
+	case AwardMesosPayload:
+		if payload.Amount <= 0 || p.config.MesoThreshold == 0 {
+			return awardKey{}, 0, 0, false
+		}
+		return awardKey{tenantId: tenantId, characterId: payload.CharacterId}, uint64(payload.Amount), p.config.MesoThreshold, true
+	case AwardItemActionPayload:
+		threshold, ok := p.config.ItemThresholds[payload.Item.TemplateId]
+		if !ok || threshold == 0 {
+			return awardKey{}, 0, 0, false
+		}
+		return awardKey{tenantId: tenantId, characterId: payload.CharacterId, templateId: payload.Item.TemplateId}, uint64(payload.Item.Quantity), threshold, true
+	}
+	return awardKey{}, 0, 0, false
+}
+
+// Evaluate holds the award step when it would push the character over the threshold within the window
+func (p *ThresholdPolicy) Evaluate(tenantId uuid.UUID, _ Saga, st Step[any]) (string, bool) {
+	k, amount, threshold, ok := p.awarded(tenantId, st)
+	if !ok {
+		return "", false
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	total := amount
+	for _, a := range p.prune(k) {
+		total += a.amount
+	}
+	if total <= threshold {
+		return "", false
+	}
+	if k.templateId == 0 {
+		return fmt.Sprintf("character [%d] would be awarded [%d] mesos within [%s], exceeding [%d]", k.characterId, total, p.config.Window, threshold), true
+	}
+	return fmt.Sprintf("character [%d] would be awarded [%d] of item [%d] within [%s], exceeding [%d]", k.characterId, total, k.templateId, p.config.Window, threshold), true
+}
+
+// Record accounts the award step against the character
+func (p *ThresholdPolicy) Record(tenantId uuid.UUID, _ Saga, st Step[any]) {
+	k, amount, _, ok := p.awarded(tenantId, st)
+	if !ok {
+		return
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.awards[k] = append(p.prune(k), award{at: p.now(), amount: amount})
+}
+
+// prune discards awards which have fallen outside the window, returning those remaining. The caller must hold the lock.
+func (p *ThresholdPolicy) prune(k awardKey) []award {
+	now := p.now()
+	as := p.awards[k]
+	i := 0
+	for i < len(as) && !as[i].at.Add(p.config.Window).After(now) {
+		i++
+	}
+	if i == len(as) {
+		delete(p.awards, k)
+		return nil
+	}
+	p.awards[k] = as[i:]
+	return p.awards[k]
+}
@@ -0,0 +1,154 @@
+package saga
+
+import (
+	"atlas-saga-orchestrator/character/mock"
+	"github.com/Chronicle20/atlas-constants/channel"
+	"github.com/Chronicle20/atlas-constants/world"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func awardMesosStep(characterId uint32, amount int32) Step[any] {
+	return Step[any]{StepId: "award-mesos", Status: Pending, Action: AwardMesos, Payload: AwardMesosPayload{CharacterId: characterId, ActorType: "NPC", Amount: amount}}
+}
+
+// TestThresholdPolicy tests that awards pushing a character over a threshold within the window are held
+func TestThresholdPolicy(t *testing.T) {
+	tenantId := uuid.New()
+	now := time.Now()
+	p := NewThresholdPolicy(ThresholdPolicyConfig{Window: time.Hour, MesoThreshold: 1000, ItemThresholds: map[uint32]uint64{2049100: 2}})
+	p.now = func() time.Time { return now }
+
+	t.Run("mesos are accumulated per character", func(t *testing.T) {
+		p.Record(tenantId, Saga{}, awardMesosStep(1, 900))
+
+		_, hold := p.Evaluate(tenantId, Saga{}, awardMesosStep(1, 100))
+		assert.False(t, hold)
+		reason, hold := p.Evaluate(tenantId, Saga{}, awardMesosStep(1, 101))
+		assert.True(t, hold)
+		assert.Contains(t, reason, "[1001] mesos")
+
+		// Other characters, tenants, and deductions are unaffected
+		_, hold = p.Evaluate(tenantId, Saga{}, awardMesosStep(2, 101))
+		assert.False(t, hold)
+		_, hold = p.Evaluate(uuid.New(), Saga{}, awardMesosStep(1, 101))
+		assert.False(t, hold)
+		_, hold = p.Evaluate(tenantId, Saga{}, awardMesosStep(1, -5000))
+		assert.False(t, hold)
+	})
+
+	t.Run("only configured items are held", func(t *testing.T) {
+		rare := Step[any]{StepId: "award-asset", Action: AwardAsset, Payload: AwardItemActionPayload{CharacterId: 1, Item: ItemPayload{TemplateId: 2049100, Quantity: 3}}}
+		reason, hold := p.Evaluate(tenantId, Saga{}, rare)
+		assert.True(t, hold)
+		assert.Contains(t, reason, "item [2049100]")
+
+		common := Step[any]{StepId: "award-asset", Action: AwardAsset, Payload: AwardItemActionPayload{CharacterId: 1, Item: ItemPayload{TemplateId: 2000000, Quantity: 300}}}
+		_, hold = p.Evaluate(tenantId, Saga{}, common)
+		assert.False(t, hold)
+
+		// Conditional awards are held as their award would be
+		conditional := Step[any]{StepId: "award-asset-if", Action: AwardAssetIf, Payload: AwardAssetIfPayload{CharacterId: 1, Item: ItemPayload{TemplateId: 2049100, Quantity: 3}}}
+		assert.True(t, IsAward(conditional.Action))
+		_, hold = p.Evaluate(tenantId, Saga{}, conditional)
+		assert.True(t, hold)
+	})
+
+	t.Run("awards leave the window", func(t *testing.T) {
+		p.now = func() time.Time { return now.Add(time.Hour) }
+		_, hold := p.Evaluate(tenantId, Saga{}, awardMesosStep(1, 1000))
+		assert.False(t, hold)
+	})
+}
+
+// TestThresholdPolicyConfigFromEnv tests loading the threshold policy configuration from the environment
+func TestThresholdPolicyConfigFromEnv(t *testing.T) {
+	t.Setenv("SAGA_REVIEW_WINDOW", "30m")
+	t.Setenv("SAGA_REVIEW_MESO_THRESHOLD", "10000000")
+	t.Setenv("SAGA_REVIEW_ITEM_THRESHOLDS", "2049100=5,2340000=1")
+
+	c, err := ThresholdPolicyConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Minute, c.Window)
+	assert.Equal(t, uint64(10000000), c.MesoThreshold)
+	assert.Equal(t, map[uint32]uint64{2049100: 5, 2340000: 1}, c.ItemThresholds)
+
+	t.Setenv("SAGA_REVIEW_ITEM_THRESHOLDS", "2049100")
+	_, err = ThresholdPolicyConfigFromEnv()
+	assert.Error(t, err)
+}
+
+// TestReviewHeldSaga tests that flagged award steps hold the saga until it is approved or rejected
+func TestReviewHeldSaga(t *testing.T) {
+	te, ctx := setupContext()
+	defer InitRewardPolicy(NewThresholdPolicy(ThresholdPolicyConfig{}))
+	InitRewardPolicy(NewThresholdPolicy(ThresholdPolicyConfig{MesoThreshold: 1000}))
+
+	awarded := 0
+	charP := &mock.ProcessorMock{
+		AwardMesosAndEmitFunc: func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, actorId uint32, actorType string, amount int32) error {
+			awarded++
+			return nil
+		},
+	}
+	processor, _ := setupTestProcessor(ctx, charP, nil)
+
+	heldSaga := func() Saga {
+		s := Saga{
+			TransactionId: uuid.New(),
+			SagaType:      QuestReward,
+			InitiatedBy:   "npc-9010000",
+			Steps:         []Step[any]{awardMesosStep(12345, 5000)},
+		}
+		require.NoError(t, processor.Put(s))
+		hs, err := processor.GetById(s.TransactionId)
+		require.NoError(t, err)
+		require.Equal(t, PendingReview, hs.Hold)
+		assert.Contains(t, hs.HoldReason, "mesos")
+		assert.Equal(t, 0, awarded)
+		return hs
+	}
+
+	t.Run("approved saga continues", func(t *testing.T) {
+		s := heldSaga()
+		defer GetCache().Remove(te.Id(), s.TransactionId)
+
+		require.NoError(t, processor.Review(s.TransactionId, true, "gm-alice", "event payout"))
+		assert.Equal(t, 1, awarded)
+
+		rs, err := processor.GetById(s.TransactionId)
+		require.NoError(t, err)
+		assert.False(t, rs.Held())
+		require.Len(t, rs.Reviews, 1)
+		assert.Equal(t, PendingReview, rs.Reviews[0].Hold)
+		assert.Equal(t, "award-mesos", rs.Reviews[0].StepId)
+		assert.True(t, rs.Reviews[0].Approved)
+		assert.Equal(t, "gm-alice", rs.Reviews[0].Reviewer)
+
+		// A saga which is no longer held cannot be reviewed again
+		assert.ErrorIs(t, processor.Review(s.TransactionId, true, "gm-alice", ""), ErrSagaNotHeld)
+	})
+
+	t.Run("rejected saga fails", func(t *testing.T) {
+		awarded = 0
+		s := heldSaga()
+		defer GetCache().Remove(te.Id(), s.TransactionId)
+		c, unsubscribe := GetNotifier().Subscribe(te.Id(), s.TransactionId)
+		defer unsubscribe()
+
+		_ = processor.Review(s.TransactionId, false, "gm-alice", "")
+		assert.Equal(t, 0, awarded)
+
+		// Failing the held step notifies waiters the saga has reached a terminal state
+		select {
+		case fs := <-c:
+			require.Len(t, fs.Reviews, 1)
+			assert.False(t, fs.Reviews[0].Approved)
+		case <-time.After(time.Second):
+			t.Fatal("rejected saga did not fail")
+		}
+	})
+}
@@ -0,0 +1,143 @@
+package saga
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	tenant "github.com/Chronicle20/atlas-tenant"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// postgresTimeout is the longest a statement against the database may take
+const postgresTimeout = 5 * time.Second
+
+// entity is a saga persisted in the sagas table, as a document serialized with Serialize alongside the tenant it was
+// processed for, and when its current step is next due
+type entity struct {
+	TenantId      uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	Region        string     `gorm:"not null"`
+	MajorVersion  int        `gorm:"type:integer;not null"`
+	MinorVersion  int        `gorm:"type:integer;not null"`
+	TransactionId uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	SagaType      string     `gorm:"not null"`
+	Active        bool       `gorm:"not null;index:sagas_active,where:active"`
+	DueAt         *time.Time `gorm:"index:sagas_due,where:active"`
+	Document      []byte     `gorm:"type:jsonb;not null"`
+	UpdatedAt     time.Time  `gorm:"not null"`
+}
+
+func (entity) TableName() string {
+	return "sagas"
+}
+
+// Migrate creates or updates the schema sagas are persisted in
+func Migrate(db *gorm.DB) error {
+	return db.AutoMigrate(&entity{})
+}
+
+// PostgresRepository is the repository of sagas persisted in PostgreSQL, as documents serialized with Serialize, so sagas
+// persisted by earlier versions of the orchestrator are migrated as they are loaded
+type PostgresRepository struct {
+	l  logrus.FieldLogger
+	db *gorm.DB
+}
+
+// NewPostgresRepository creates a repository of the sagas persisted in the database, creating its schema if need be
+func NewPostgresRepository(l logrus.FieldLogger, db *gorm.DB) (*PostgresRepository, error) {
+	if err := Migrate(db); err != nil {
+		return nil, fmt.Errorf("unable to migrate saga schema: %w", err)
+	}
+	return &PostgresRepository{l: l, db: db}, nil
+}
+
+// Save persists the state of a saga for a tenant, replacing that persisted before
+func (r *PostgresRepository) Save(t tenant.Model, s Saga) error {
+	doc, err := Serialize(s)
+	if err != nil {
+		return err
+	}
+	var dueAt *time.Time
+	if at, ok := s.dueAt(); ok {
+		dueAt = &at
+	}
+	e := entity{
+		TenantId:      t.Id(),
+		Region:        t.Region(),
+		MajorVersion:  int(t.MajorVersion()),
+		MinorVersion:  int(t.MinorVersion()),
+		TransactionId: s.TransactionId,
+		SagaType:      string(s.SagaType),
+		Active:        s.active(),
+		DueAt:         dueAt,
+		Document:      doc,
+		UpdatedAt:     time.Now(),
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), postgresTimeout)
+	defer cancel()
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tenant_id"}, {Name: "transaction_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"active", "due_at", "document", "updated_at"}),
+	}).Create(&e).Error
+}
+
+// GetById returns a persisted saga by its transaction ID for a tenant
+func (r *PostgresRepository) GetById(tenantId uuid.UUID, transactionId uuid.UUID) (Saga, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), postgresTimeout)
+	defer cancel()
+	var e entity
+	err := r.db.WithContext(ctx).Where("tenant_id = ? AND transaction_id = ?", tenantId, transactionId).First(&e).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return Saga{}, false, nil
+	}
+	if err != nil {
+		return Saga{}, false, err
+	}
+	s, err := Deserialize(e.Document)
+	if err != nil {
+		return Saga{}, false, err
+	}
+	return s, true, nil
+}
+
+// GetActive returns the persisted sagas with work remaining, by tenant
+func (r *PostgresRepository) GetActive() (map[tenant.Model][]Saga, error) {
+	return r.find("active = ?", true)
+}
+
+// GetDue returns the persisted sagas whose current step is due by the given time, by tenant
+func (r *PostgresRepository) GetDue(now time.Time) (map[tenant.Model][]Saga, error) {
+	return r.find("active = ? AND due_at <= ?", true, now)
+}
+
+// find returns the persisted sagas matching the condition, by tenant. A saga which cannot be read is logged and skipped,
+// so it does not keep the others from being restored or acted on.
+func (r *PostgresRepository) find(query string, args ...any) (map[tenant.Model][]Saga, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), postgresTimeout)
+	defer cancel()
+	var es []entity
+	if err := r.db.WithContext(ctx).Where(query, args...).Find(&es).Error; err != nil {
+		return nil, err
+	}
+
+	result := make(map[tenant.Model][]Saga)
+	for _, e := range es {
+		f := logrus.Fields{"transaction_id": e.TransactionId.String(), "tenant_id": e.TenantId.String()}
+		t, err := tenant.Create(e.TenantId, e.Region, uint16(e.MajorVersion), uint16(e.MinorVersion))
+		if err != nil {
+			r.l.WithError(err).WithFields(f).Error("Unable to read tenant of persisted saga, skipping it.")
+			continue
+		}
+		s, err := Deserialize(e.Document)
+		if err != nil {
+			r.l.WithError(err).WithFields(f).Error("Unable to deserialize persisted saga, skipping it.")
+			continue
+		}
+		result[t] = append(result[t], s)
+	}
+	return result, nil
+}
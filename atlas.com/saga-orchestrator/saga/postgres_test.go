@@ -0,0 +1,172 @@
+package saga
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	tenant "github.com/Chronicle20/atlas-tenant"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// EnvTestDatabase names the PostgreSQL database the repository is tested against
+const EnvTestDatabase = "SAGA_TEST_DATABASE_DSN"
+
+// testDatabase connects to the PostgreSQL database named by SAGA_TEST_DATABASE_DSN, in a schema of its own dropped once
+// the test completes. Without a database the test is skipped, other than in CI, where it fails.
+func testDatabase(t *testing.T) *gorm.DB {
+	dsn := os.Getenv(EnvTestDatabase)
+	if dsn == "" {
+		if os.Getenv("CI") != "" {
+			t.Fatalf("%s must name a PostgreSQL database in CI.", EnvTestDatabase)
+		}
+		t.Skipf("%s is not set.", EnvTestDatabase)
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDb, err := db.DB()
+	require.NoError(t, err)
+	// The schema is selected for the session, so every statement is made on the one connection
+	sqlDb.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDb.Close() })
+
+	schema := "saga_test_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+	require.NoError(t, db.Exec("CREATE SCHEMA "+schema).Error)
+	t.Cleanup(func() { _ = db.Exec("DROP SCHEMA " + schema + " CASCADE").Error })
+	require.NoError(t, db.Exec("SET search_path TO "+schema).Error)
+	return db
+}
+
+// TestPostgresRepository tests that sagas saved to PostgreSQL are read back by transaction ID, and those with work
+// remaining by tenant
+func TestPostgresRepository(t *testing.T) {
+	db := testDatabase(t)
+	l, _ := test.NewNullLogger()
+	r, err := NewPostgresRepository(l, db)
+	require.NoError(t, err)
+
+	te, _ := tenant.Create(uuid.New(), "GMS", 83, 1)
+	active := NewBuilder().
+		SetSagaType(QuestReward).
+		SetInitiatedBy("quest-service").
+		AddStep("mesos", Completed, AwardMesos, AwardMesosPayload{CharacterId: 12345, ActorType: "NPC", Amount: 1000}).
+		AddStep("bonus", Pending, AwardMesos, AwardMesosPayload{CharacterId: 12345, ActorType: "NPC", Amount: 500}).
+		Build()
+	_, err = active.RecordStepAttempt(1, time.Now())
+	require.NoError(t, err)
+	finished := NewBuilder().
+		SetSagaType(QuestReward).
+		AddStep("mesos", Completed, AwardMesos, AwardMesosPayload{CharacterId: 12345, ActorType: "NPC", Amount: 1000}).
+		Build()
+	require.NoError(t, r.Save(te, active))
+	require.NoError(t, r.Save(te, finished))
+
+	s, ok, err := r.GetById(te.Id(), active.TransactionId)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, active.TransactionId, s.TransactionId)
+	assert.Equal(t, "quest-service", s.InitiatedBy)
+	assert.Equal(t, AwardMesosPayload{CharacterId: 12345, ActorType: "NPC", Amount: 500}, s.Steps[1].Payload)
+	require.Len(t, s.Steps[1].Attempts, 1)
+
+	_, ok, err = r.GetById(uuid.New(), active.TransactionId)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	byTenant, err := r.GetActive()
+	require.NoError(t, err)
+	require.Len(t, byTenant[te], 1)
+	assert.Equal(t, active.TransactionId, byTenant[te][0].TransactionId)
+
+	// Sagas are due once their current step is, such as when parked for a redelivery which is due
+	due, err := r.GetDue(time.Now())
+	require.NoError(t, err)
+	assert.Empty(t, due)
+	retryAt := time.Now().Add(-time.Second)
+	active.Steps[1].Attempts[0].RetryAt = &retryAt
+	require.NoError(t, r.Save(te, active))
+	due, err = r.GetDue(time.Now())
+	require.NoError(t, err)
+	require.Len(t, due[te], 1)
+	assert.Equal(t, active.TransactionId, due[te][0].TransactionId)
+
+	// Saving a saga again replaces it, so a saga which has since finished is no longer active
+	active.Steps[1].Status = Completed
+	require.NoError(t, r.Save(te, active))
+	byTenant, err = r.GetActive()
+	require.NoError(t, err)
+	assert.Empty(t, byTenant)
+
+	// The schema is migrated whenever a repository is created
+	_, err = NewPostgresRepository(l, db)
+	require.NoError(t, err)
+	_, ok, err = r.GetById(te.Id(), finished.TransactionId)
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+// TestPostgresSchema tests that sagas are persisted as jsonb documents, and that only those with work remaining are
+// indexed
+func TestPostgresSchema(t *testing.T) {
+	db := testDatabase(t)
+	l, _ := test.NewNullLogger()
+	r, err := NewPostgresRepository(l, db)
+	require.NoError(t, err)
+
+	te, _ := tenant.Create(uuid.New(), "GMS", 83, 1)
+	s := NewBuilder().
+		SetSagaType(QuestReward).
+		AddStep("mesos", Pending, AwardMesos, AwardMesosPayload{CharacterId: 12345, ActorType: "NPC", Amount: 1000}).
+		Build()
+	require.NoError(t, r.Save(te, s))
+
+	var transactionId string
+	require.NoError(t, db.Raw("SELECT document->>'transactionId' FROM sagas WHERE transaction_id = ?", s.TransactionId).Scan(&transactionId).Error)
+	assert.Equal(t, s.TransactionId.String(), transactionId)
+
+	for _, index := range []string{"sagas_active", "sagas_due"} {
+		var def string
+		require.NoError(t, db.Raw("SELECT indexdef FROM pg_indexes WHERE schemaname = current_schema() AND indexname = ?", index).Scan(&def).Error)
+		assert.Contains(t, def, "WHERE active", index)
+	}
+}
+
+// TestPostgresRepositoryUnreadable tests that a persisted saga which cannot be read is skipped, leaving the others to be
+// restored
+func TestPostgresRepositoryUnreadable(t *testing.T) {
+	db := testDatabase(t)
+	l, hook := test.NewNullLogger()
+	r, err := NewPostgresRepository(l, db)
+	require.NoError(t, err)
+
+	te, _ := tenant.Create(uuid.New(), "GMS", 83, 1)
+	s := NewBuilder().
+		SetSagaType(QuestReward).
+		AddStep("mesos", Pending, AwardMesos, AwardMesosPayload{CharacterId: 12345, ActorType: "NPC", Amount: 1000}).
+		Build()
+	require.NoError(t, r.Save(te, s))
+	// A saga persisted by a newer version of the orchestrator cannot be read
+	require.NoError(t, db.Create(&entity{
+		TenantId:      te.Id(),
+		Region:        te.Region(),
+		MajorVersion:  int(te.MajorVersion()),
+		MinorVersion:  int(te.MinorVersion()),
+		TransactionId: uuid.New(),
+		SagaType:      string(QuestReward),
+		Active:        true,
+		Document:      []byte(`{"schemaVersion": 999}`),
+		UpdatedAt:     time.Now(),
+	}).Error)
+
+	byTenant, err := r.GetActive()
+	require.NoError(t, err)
+	require.Len(t, byTenant[te], 1)
+	assert.Equal(t, s.TransactionId, byTenant[te][0].TransactionId)
+	require.NotEmpty(t, hook.AllEntries())
+}
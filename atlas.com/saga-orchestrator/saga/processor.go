@@ -1,19 +1,27 @@
 package saga
 
 import (
+	"atlas-saga-orchestrator/buff"
 	"atlas-saga-orchestrator/character"
+	"atlas-saga-orchestrator/cluster"
 	"atlas-saga-orchestrator/compartment"
 	"atlas-saga-orchestrator/guild"
 	"atlas-saga-orchestrator/invite"
+	"atlas-saga-orchestrator/kafka/header"
+	character2 "atlas-saga-orchestrator/kafka/message/character"
 	"atlas-saga-orchestrator/kafka/message/saga"
 	"atlas-saga-orchestrator/kafka/producer"
+	"atlas-saga-orchestrator/session"
 	"atlas-saga-orchestrator/skill"
 	"atlas-saga-orchestrator/validation"
 	"context"
 	"errors"
 	"fmt"
+	"hash/fnv"
+	"sync"
 	"time"
 
+	_map "github.com/Chronicle20/atlas-constants/map"
 	"github.com/Chronicle20/atlas-model/model"
 	tenant "github.com/Chronicle20/atlas-tenant"
 	"github.com/google/uuid"
@@ -28,22 +36,56 @@ type Processor interface {
 	WithValidationProcessor(validation.Processor) Processor
 	WithGuildProcessor(guild.Processor) Processor
 	WithInviteProcessor(invite.Processor) Processor
+	WithBuffProcessor(buff.Processor) Processor
+	WithSessionProcessor(session.Processor) Processor
 
 	GetAll() ([]Saga, error)
 	AllProvider() model.Provider[[]Saga]
+	GetByLabels(labels map[string]string) ([]Saga, error)
+	ByLabelsProvider(labels map[string]string) model.Provider[[]Saga]
+	Query(q Query) (Page, error)
 	GetById(transactionId uuid.UUID) (Saga, error)
 	ByIdProvider(transactionId uuid.UUID) model.Provider[Saga]
 
 	Put(saga Saga) error
+	AtomicUpdateSaga(transactionId uuid.UUID, updateFunc func(*Saga) error) error
 	MarkFurthestCompletedStepFailed(transactionId uuid.UUID) error
 	MarkEarliestPendingStep(transactionId uuid.UUID, status Status) error
 	MarkEarliestPendingStepCompleted(transactionId uuid.UUID) error
 	StepCompleted(transactionId uuid.UUID, success bool) error
+	StepCompletedWithEvent(transactionId uuid.UUID, event any) error
+	StepFailed(transactionId uuid.UUID, errorCode string, errorMessage string) error
+	StepFailedBy(service string, transactionId uuid.UUID, errorCode string, errorMessage string) error
+	StepAbandoned(transactionId uuid.UUID, errorCode string, errorMessage string) error
 	AddStep(transactionId uuid.UUID, step Step[any]) error
 	AddStepAfterCurrent(transactionId uuid.UUID, step Step[any]) error
 	Step(transactionId uuid.UUID) error
+	AwaitTerminal(ctx context.Context, transactionId uuid.UUID, timeout time.Duration) (Saga, error)
+	Review(transactionId uuid.UUID, approved bool, reviewer string, comment string) error
+	Resolve(transactionId uuid.UUID, resolution string, resolver string, comment string) error
+	AddNote(transactionId uuid.UUID, author string, comment string) (Note, error)
+	Abort(transactionId uuid.UUID, operator string, reason string) error
+	MonsterKilled(kill MonsterKill) error
+	MapEntered(characterId uint32, mapId _map.Id) error
+	EscortArrived(transactionId uuid.UUID, event any) error
+	EscortFailed(transactionId uuid.UUID, reason string) error
+	CharacterLoggedIn(characterId uint32) error
+	Quarantine(transactionId uuid.UUID, recovered any, stack []byte) error
 }
 
+// ErrSagaNotHeld is returned when reviewing a saga which is not held
+var ErrSagaNotHeld = errors.New("saga is not held")
+
+// ErrSelfApproval is returned when the initiator of a saga requiring approval attempts to approve it
+var ErrSelfApproval = errors.New("saga must be approved by an operator other than its initiator")
+
+// ErrResolutionRequired is returned when approving or rejecting a saga held pending the resolution of a dispute, which
+// ends only in one of its resolutions
+var ErrResolutionRequired = errors.New("saga must be resolved rather than reviewed")
+
+// ErrInvalidResolution is returned when resolving a dispute with a resolution it does not offer
+var ErrInvalidResolution = errors.New("invalid resolution")
+
 // ProcessorImpl is the implementation of the Processor interface
 type ProcessorImpl struct {
 	l       logrus.FieldLogger
@@ -57,15 +99,22 @@ type ProcessorImpl struct {
 	validP  validation.Processor
 	guildP  guild.Processor
 	inviteP invite.Processor
+	buffP   buff.Processor
+	sessP   session.Processor
 }
 
 // NewProcessor creates a new saga processor
 func NewProcessor(logger logrus.FieldLogger, ctx context.Context) Processor {
+	// Commands produced while dispatching a step carry its saga metadata as headers, and are produced all-or-nothing
+	ctx = header.WithCarrier(ctx)
+	ctx = producer.WithOutbox(ctx)
+	t := tenant.MustFromContext(ctx)
+	rememberTenant(t)
 
 	return &ProcessorImpl{
 		l:       logger,
 		ctx:     ctx,
-		t:       tenant.MustFromContext(ctx),
+		t:       t,
 		comp:    NewCompensator(logger, ctx),
 		handle:  NewHandler(logger, ctx),
 		charP:   character.NewProcessor(logger, ctx),
@@ -74,6 +123,8 @@ func NewProcessor(logger logrus.FieldLogger, ctx context.Context) Processor {
 		validP:  validation.NewProcessor(logger, ctx),
 		guildP:  guild.NewProcessor(logger, ctx),
 		inviteP: invite.NewProcessor(logger, ctx),
+		buffP:   buff.NewProcessor(logger, ctx),
+		sessP:   session.NewProcessor(logger, ctx),
 	}
 }
 
@@ -90,6 +141,8 @@ func (p *ProcessorImpl) WithCharacterProcessor(charP character.Processor) Proces
 		validP:  p.validP,
 		guildP:  p.guildP,
 		inviteP: p.inviteP,
+		buffP:   p.buffP,
+		sessP:   p.sessP,
 	}
 }
 
@@ -106,6 +159,8 @@ func (p *ProcessorImpl) WithCompartmentProcessor(compP compartment.Processor) Pr
 		validP:  p.validP,
 		guildP:  p.guildP,
 		inviteP: p.inviteP,
+		buffP:   p.buffP,
+		sessP:   p.sessP,
 	}
 }
 
@@ -122,6 +177,8 @@ func (p *ProcessorImpl) WithSkillProcessor(skillP skill.Processor) Processor {
 		validP:  p.validP,
 		guildP:  p.guildP,
 		inviteP: p.inviteP,
+		buffP:   p.buffP,
+		sessP:   p.sessP,
 	}
 }
 
@@ -138,6 +195,8 @@ func (p *ProcessorImpl) WithValidationProcessor(validP validation.Processor) Pro
 		validP:  validP,
 		guildP:  p.guildP,
 		inviteP: p.inviteP,
+		buffP:   p.buffP,
+		sessP:   p.sessP,
 	}
 }
 
@@ -154,6 +213,8 @@ func (p *ProcessorImpl) WithGuildProcessor(guildP guild.Processor) Processor {
 		validP:  p.validP,
 		guildP:  guildP,
 		inviteP: p.inviteP,
+		buffP:   p.buffP,
+		sessP:   p.sessP,
 	}
 }
 
@@ -170,6 +231,44 @@ func (p *ProcessorImpl) WithInviteProcessor(inviteP invite.Processor) Processor
 		validP:  p.validP,
 		guildP:  p.guildP,
 		inviteP: inviteP,
+		buffP:   p.buffP,
+		sessP:   p.sessP,
+	}
+}
+
+func (p *ProcessorImpl) WithBuffProcessor(buffP buff.Processor) Processor {
+	return &ProcessorImpl{
+		l:       p.l,
+		ctx:     p.ctx,
+		t:       p.t,
+		comp:    p.comp.WithBuffProcessor(buffP),
+		handle:  p.handle.WithBuffProcessor(buffP),
+		charP:   p.charP,
+		compP:   p.compP,
+		skillP:  p.skillP,
+		validP:  p.validP,
+		guildP:  p.guildP,
+		inviteP: p.inviteP,
+		buffP:   buffP,
+		sessP:   p.sessP,
+	}
+}
+
+func (p *ProcessorImpl) WithSessionProcessor(sessP session.Processor) Processor {
+	return &ProcessorImpl{
+		l:       p.l,
+		ctx:     p.ctx,
+		t:       p.t,
+		comp:    p.comp.WithSessionProcessor(sessP),
+		handle:  p.handle.WithSessionProcessor(sessP),
+		charP:   p.charP,
+		compP:   p.compP,
+		skillP:  p.skillP,
+		validP:  p.validP,
+		guildP:  p.guildP,
+		inviteP: p.inviteP,
+		buffP:   p.buffP,
+		sessP:   sessP,
 	}
 }
 
@@ -184,6 +283,48 @@ func (p *ProcessorImpl) AllProvider() model.Provider[[]Saga] {
 	}
 }
 
+// GetByLabels returns all sagas for the current tenant carrying every one of the labels
+func (p *ProcessorImpl) GetByLabels(labels map[string]string) ([]Saga, error) {
+	return p.ByLabelsProvider(labels)()
+}
+
+// Query returns the page of the sagas for the current tenant which the query selects
+func (p *ProcessorImpl) Query(q Query) (Page, error) {
+	sagas, err := p.GetByLabels(q.Labels)
+	if err != nil {
+		return Page{}, err
+	}
+	selected := make([]Saga, 0, len(sagas))
+	for _, s := range sagas {
+		if q.Matches(s) {
+			selected = append(selected, s)
+		}
+	}
+	return paginate(q, selected), nil
+}
+
+func (p *ProcessorImpl) ByLabelsProvider(labels map[string]string) model.Provider[[]Saga] {
+	return func() ([]Saga, error) {
+		if len(labels) == 0 {
+			return GetCache().GetAll(p.t.Id()), nil
+		}
+
+		// Narrow by a single label through the index, then filter by the remainder
+		var candidates []Saga
+		for k, v := range labels {
+			candidates = GetCache().GetByLabel(p.t.Id(), k, v)
+			break
+		}
+		results := make([]Saga, 0, len(candidates))
+		for _, s := range candidates {
+			if s.HasLabels(labels) {
+				results = append(results, s)
+			}
+		}
+		return results, nil
+	}
+}
+
 // GetById returns a saga by its transaction ID for the current tenant
 func (p *ProcessorImpl) GetById(transactionId uuid.UUID) (Saga, error) {
 	return p.ByIdProvider(transactionId)()
@@ -193,7 +334,10 @@ func (p *ProcessorImpl) ByIdProvider(transactionId uuid.UUID) model.Provider[Sag
 	return func() (Saga, error) {
 		m, ok := GetCache().GetById(p.t.Id(), transactionId)
 		if !ok {
-			return Saga{}, errors.New("saga not found")
+			// Sagas not in the cache may be archived, such as those held before a restart
+			if m, ok = p.hydrate(transactionId); !ok {
+				return Saga{}, errors.New("saga not found")
+			}
 		}
 		return m, nil
 	}
@@ -207,6 +351,86 @@ func (p *ProcessorImpl) Put(saga Saga) error {
 		"tenant_id":      p.t.Id().String(),
 	}).Debug("Inserting saga into cache")
 
+	if err := saga.ValidateLabels(); err != nil {
+		p.l.WithFields(logrus.Fields{
+			"transaction_id": saga.TransactionId.String(),
+			"saga_type":      saga.SagaType,
+			"tenant_id":      p.t.Id().String(),
+		}).WithError(err).Error("Label validation failed before inserting saga")
+		return err
+	}
+
+	if err := saga.ValidateType(); err != nil {
+		p.l.WithFields(logrus.Fields{
+			"transaction_id": saga.TransactionId.String(),
+			"saga_type":      saga.SagaType,
+			"tenant_id":      p.t.Id().String(),
+		}).WithError(err).Error("Saga type validation failed before inserting saga")
+		return err
+	}
+
+	if err := saga.NormalizeVariables(); err != nil {
+		p.l.WithFields(logrus.Fields{
+			"transaction_id": saga.TransactionId.String(),
+			"saga_type":      saga.SagaType,
+			"tenant_id":      p.t.Id().String(),
+		}).WithError(err).Error("Variable validation failed before inserting saga")
+		return err
+	}
+
+	if removed := saga.CoalesceAwardSteps(); removed > 0 {
+		p.l.WithFields(logrus.Fields{
+			"transaction_id": saga.TransactionId.String(),
+			"saga_type":      saga.SagaType,
+			"tenant_id":      p.t.Id().String(),
+		}).Debugf("Coalesced [%d] award steps.", removed)
+	}
+
+	if err := saga.ValidateBranches(); err != nil {
+		p.l.WithFields(logrus.Fields{
+			"transaction_id": saga.TransactionId.String(),
+			"saga_type":      saga.SagaType,
+			"tenant_id":      p.t.Id().String(),
+		}).WithError(err).Error("Branch validation failed before inserting saga")
+		return err
+	}
+
+	if err := saga.ScheduleDependencies(); err != nil {
+		p.l.WithFields(logrus.Fields{
+			"transaction_id": saga.TransactionId.String(),
+			"saga_type":      saga.SagaType,
+			"tenant_id":      p.t.Id().String(),
+		}).WithError(err).Error("Dependency validation failed before inserting saga")
+		return err
+	}
+
+	if err := saga.ValidateTimers(); err != nil {
+		p.l.WithFields(logrus.Fields{
+			"transaction_id": saga.TransactionId.String(),
+			"saga_type":      saga.SagaType,
+			"tenant_id":      p.t.Id().String(),
+		}).WithError(err).Error("Timer validation failed before inserting saga")
+		return err
+	}
+
+	if err := saga.ValidateAudit(); err != nil {
+		p.l.WithFields(logrus.Fields{
+			"transaction_id": saga.TransactionId.String(),
+			"saga_type":      saga.SagaType,
+			"tenant_id":      p.t.Id().String(),
+		}).WithError(err).Error("Audit validation failed before inserting saga")
+		return err
+	}
+
+	if err := saga.ValidateOnComplete(); err != nil {
+		p.l.WithFields(logrus.Fields{
+			"transaction_id": saga.TransactionId.String(),
+			"saga_type":      saga.SagaType,
+			"tenant_id":      p.t.Id().String(),
+		}).WithError(err).Error("OnComplete validation failed before inserting saga")
+		return err
+	}
+
 	// Validate state consistency before inserting
 	if err := saga.ValidateStateConsistency(); err != nil {
 		p.l.WithFields(logrus.Fields{
@@ -217,7 +441,43 @@ func (p *ProcessorImpl) Put(saga Saga) error {
 		return err
 	}
 
+	// Actions being rolled out are not enabled for every tenant, so sagas using them fail fast rather than awaiting
+	// services which may not be deployed
+	if disabled := saga.DisabledActions(p.t.Id(), GetFlagConfig()); len(disabled) > 0 {
+		err := fmt.Errorf("%w for tenant: %v", ErrActionNotEnabled, disabled)
+		p.l.WithFields(logrus.Fields{
+			"transaction_id": saga.TransactionId.String(),
+			"saga_type":      saga.SagaType,
+			"tenant_id":      p.t.Id().String(),
+		}).WithError(err).Warn("Rejecting saga with actions not enabled for tenant.")
+		return err
+	}
+
+	// Sagas are executed by the replica owning them, so replicas never execute the same saga concurrently
+	if !cluster.GetMembership().Owns(saga.TransactionId) {
+		return p.forward(saga)
+	}
+
+	if err := p.checkConflicts(saga); err != nil {
+		return err
+	}
+
+	if err := p.reserveBudget(saga); err != nil {
+		return err
+	}
+
+	// Sagas requiring approval are held until a second operator approves them
+	if saga.RequiresApproval && !saga.Approved() {
+		saga.Hold = PendingApproval
+		saga.HoldReason = fmt.Sprintf("saga initiated by [%s] requires approval", saga.InitiatedBy)
+	}
+
+	// An inventory audit compares against what the character held before any step was dispatched
+	p.recordAuditBaseline(&saga)
+
 	GetCache().Put(p.t.Id(), saga)
+	countStarted(p.t.Id(), saga)
+	p.projectStarted(saga)
 
 	p.l.WithFields(logrus.Fields{
 		"transaction_id": saga.TransactionId.String(),
@@ -228,15 +488,133 @@ func (p *ProcessorImpl) Put(saga Saga) error {
 	return p.Step(saga.TransactionId)
 }
 
-// AtomicUpdateSaga performs an atomic update of saga state with consistency validation
+// checkConflicts detects active sagas referencing the same assets as the saga, which would otherwise emit compartment
+// commands racing downstream. A conflicting saga is rejected, or when configured, queued until those it conflicts with
+// finish.
+func (p *ProcessorImpl) checkConflicts(saga Saga) error {
+	conflicts, reason := FindConflicts(p.t.Id(), saga)
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	err := fmt.Errorf("%w: %s", ErrAssetConflict, reason)
+	fl := p.l.WithFields(logrus.Fields{
+		"transaction_id": saga.TransactionId.String(),
+		"saga_type":      saga.SagaType,
+		"conflicts":      len(conflicts),
+		"tenant_id":      p.t.Id().String(),
+	})
+	if !GetConflictConfig().Queue {
+		fl.WithError(err).Warn("Rejecting saga which conflicts with an active saga.")
+		return err
+	}
+
+	// Subscribe before confirming the conflicting sagas are still active, so none can finish unobserved
+	cs := make([]<-chan Saga, 0, len(conflicts))
+	unsubscribes := make([]func(), 0, len(conflicts))
+	finished := false
+	for _, c := range conflicts {
+		ch, unsubscribe := GetNotifier().Subscribe(p.t.Id(), c.TransactionId)
+		cs = append(cs, ch)
+		unsubscribes = append(unsubscribes, unsubscribe)
+		if _, ok := GetCache().GetById(p.t.Id(), c.TransactionId); !ok {
+			finished = true
+		}
+	}
+
+	// The queued saga outlives the context of the request which submitted it
+	l := p.l
+	ctx := tenant.WithContext(context.Background(), p.t)
+	go func() {
+		if !finished {
+			awaitAny(cs)
+		}
+		for _, unsubscribe := range unsubscribes {
+			unsubscribe()
+		}
+		if err := NewProcessor(l, ctx).Put(saga); err != nil && !errors.Is(err, ErrSagaQueued) {
+			l.WithError(err).Errorf("Unable to start queued saga [%s].", saga.TransactionId.String())
+		}
+	}()
+	fl.WithError(err).Info("Queueing saga until the sagas it conflicts with finish.")
+	return ErrSagaQueued
+}
+
+// awaitAny blocks until any of the channels receives
+func awaitAny(cs []<-chan Saga) {
+	done := make(chan struct{})
+	defer close(done)
+	ready := make(chan struct{}, len(cs))
+	for _, c := range cs {
+		go func(c <-chan Saga) {
+			select {
+			case <-c:
+				ready <- struct{}{}
+			case <-done:
+			}
+		}(c)
+	}
+	<-ready
+}
+
+// reserveBudget charges the cost of the saga against its budgets. A saga exceeding its budget is rejected, or when
+// configured, queued until enough budget is available.
+func (p *ProcessorImpl) reserveBudget(saga Saga) error {
+	wait, err := GetBudget().Reserve(p.t.Id(), saga)
+	if err == nil {
+		return nil
+	}
+
+	fl := p.l.WithFields(logrus.Fields{
+		"transaction_id": saga.TransactionId.String(),
+		"saga_type":      saga.SagaType,
+		"initiated_by":   saga.InitiatedBy,
+		"cost":           GetBudget().Cost(saga),
+		"tenant_id":      p.t.Id().String(),
+	})
+	if wait == 0 || !GetBudget().Queue() {
+		fl.WithError(err).Warn("Rejecting saga which exceeds its budget.")
+		return err
+	}
+
+	// The queued saga outlives the context of the request which submitted it
+	l := p.l
+	ctx := tenant.WithContext(context.Background(), p.t)
+	time.AfterFunc(wait, func() {
+		if err := NewProcessor(l, ctx).Put(saga); err != nil && !errors.Is(err, ErrSagaQueued) {
+			l.WithError(err).Errorf("Unable to start queued saga [%s].", saga.TransactionId.String())
+		}
+	})
+	fl.WithError(err).Infof("Queueing saga which exceeds its budget for [%s].", wait)
+	return ErrSagaQueued
+}
+
+// updateLocks serialize the updates of each saga made through AtomicUpdateSaga, striped by transaction ID, so updates
+// of a saga made concurrently, such as the outcomes of its steps, are each applied to the saga as the others left it
+var updateLocks [256]sync.Mutex
+
+// updateLock returns the lock serializing the updates of the saga
+func updateLock(transactionId uuid.UUID) *sync.Mutex {
+	h := fnv.New32a()
+	_, _ = h.Write(transactionId[:])
+	return &updateLocks[h.Sum32()%uint32(len(updateLocks))]
+}
+
+// AtomicUpdateSaga performs an atomic update of saga state with consistency validation. Updates of a saga made through it
+// are serialized, so none overwrites another made concurrently.
 func (p *ProcessorImpl) AtomicUpdateSaga(transactionId uuid.UUID, updateFunc func(*Saga) error) error {
+	lock := updateLock(transactionId)
+	lock.Lock()
+	defer lock.Unlock()
+
 	s, err := p.GetById(transactionId)
 	if err != nil {
 		return err
 	}
 
-	// Create a copy for safe modification
+	// Create a copy for safe modification, so the cached saga is unaffected should the update fail
 	sagaCopy := s
+	sagaCopy.Steps = append([]Step[any]{}, s.Steps...)
 
 	// Apply the update function
 	if err := updateFunc(&sagaCopy); err != nil {
@@ -289,7 +667,7 @@ func (p *ProcessorImpl) SafeSetStepStatus(s *Saga, stepIndex int, status Status,
 func (p *ProcessorImpl) StepCompleted(transactionId uuid.UUID, success bool) error {
 	s, err := p.GetById(transactionId)
 	if err != nil {
-		return nil
+		return p.rejectMismatchedEvent(transactionId, nil, "")
 	}
 
 	if s.Failing() {
@@ -298,19 +676,258 @@ func (p *ProcessorImpl) StepCompleted(transactionId uuid.UUID, success bool) err
 			return err
 		}
 	} else {
+		// Failures of for_each iterations may be absorbed, as declared by the for_each step's failure policy
+		if !success && p.failIteration(s) {
+			return p.Step(transactionId)
+		}
+
 		status := Failed
 		if success {
 			status = Completed
+			if err = p.selectBranch(s); err != nil {
+				status = Failed
+			}
 		}
 
+		stepId := ""
+		if idx := s.FindEarliestPendingStepIndex(); idx != -1 {
+			stepId = s.Steps[idx].StepId
+		}
 		err = p.MarkEarliestPendingStep(transactionId, status)
 		if err != nil {
 			return err
 		}
+		if status == Completed {
+			p.trackIteration(transactionId, stepId)
+		}
 	}
 	return p.Step(transactionId)
 }
 
+// selectBranch selects which of the branches declared by the current step executes, now that it has completed, and
+// inserts the selected branch's steps directly after it. Steps of the branches not selected never execute.
+func (p *ProcessorImpl) selectBranch(s Saga) error {
+	idx := s.FindEarliestPendingStepIndex()
+	if idx == -1 || len(s.Steps[idx].Branches) == 0 {
+		return nil
+	}
+
+	fl := p.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        s.Steps[idx].StepId,
+		"tenant_id":      p.t.Id().String(),
+	})
+	b, ok, err := s.SelectBranch(idx)
+	if err != nil {
+		fl.WithError(err).Error("Unable to select branch.")
+		return err
+	}
+	if !ok {
+		fl.Debug("No branch selected.")
+		return nil
+	}
+	if err = s.ApplyBranch(idx, b); err != nil {
+		fl.WithError(err).Error("State consistency validation failed after inserting branch steps.")
+		return err
+	}
+	GetCache().Put(p.t.Id(), s)
+	fl.Debugf("Selected branch [%s] of [%d] steps.", b.Name, len(b.Steps))
+	return nil
+}
+
+// rejectMismatchedEvent handles a status event referencing a saga which is not active for the tenant. Events referencing
+// a saga of another tenant are rejected, and when configured, dead-lettered, while others are ignored as before.
+func (p *ProcessorImpl) rejectMismatchedEvent(transactionId uuid.UUID, event any, errorCode string) error {
+	err := ValidateEventTenant(p.t.Id(), transactionId)
+	if err == nil {
+		return nil
+	}
+
+	p.l.WithError(err).WithFields(logrus.Fields{
+		"transaction_id": transactionId.String(),
+		"tenant_id":      p.t.Id().String(),
+	}).Error("Rejecting status event received for a saga of another tenant.")
+
+	if GetTenantMismatchConfig().DeadLetter {
+		perr := producer.ProviderImpl(p.l)(p.ctx)(saga.EnvDeadLetterTopic)(DeadLetterProvider(transactionId, p.t.Id(), err.Error(), event, errorCode))
+		if perr != nil {
+			p.l.WithError(perr).WithFields(logrus.Fields{
+				"transaction_id": transactionId.String(),
+				"tenant_id":      p.t.Id().String(),
+			}).Error("Failed to dead-letter status event.")
+		}
+	}
+	return err
+}
+
+// StepCompletedWithEvent completes the current step successfully, first setting the variables it captures from the
+// event which completed it. A step whose captures cannot be resolved from the event fails.
+func (p *ProcessorImpl) StepCompletedWithEvent(transactionId uuid.UUID, event any) error {
+	s, err := p.GetById(transactionId)
+	if err != nil {
+		return p.rejectMismatchedEvent(transactionId, event, "")
+	}
+
+	if e, ok := event.(character2.StatusEvent[character2.StatusEventCreatedBody]); ok && !s.Failing() {
+		s = p.recordCreatedCharacter(s, e.CharacterId)
+	}
+
+	// Events completing compensations are not captured
+	if st, ok := s.GetCurrentStep(); ok && !s.Failing() && len(st.Capture) > 0 {
+		vars, err := CaptureVariables(s.Variables, st, event)
+		if err != nil {
+			p.l.WithError(err).WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"step_id":        st.StepId,
+				"tenant_id":      p.t.Id().String(),
+			}).Error("Unable to capture saga variables from event.")
+			return p.StepCompleted(transactionId, false)
+		}
+		s.Variables = vars
+		GetCache().Put(p.t.Id(), s)
+	}
+	return p.StepCompleted(transactionId, true)
+}
+
+// CreatedCharacterVariable is the saga variable the id of the character created by a create_character step is written to,
+// so later steps of onboarding sagas can reference the character without the caller knowing it in advance
+const CreatedCharacterVariable = "characterId"
+
+// recordCreatedCharacter records the id of the character created by the saga's current step against its payload, so
+// the character can be deleted should the saga later be compensated, and writes it to the CreatedCharacterVariable.
+// Capture rules of the step are evaluated afterward, so may overwrite the variable.
+func (p *ProcessorImpl) recordCreatedCharacter(s Saga, characterId uint32) Saga {
+	st, ok := s.GetCurrentStep()
+	if !ok || st.Action != CreateCharacter {
+		return s
+	}
+	payload, ok := st.Payload.(CharacterCreatePayload)
+	if !ok {
+		return s
+	}
+	payload.CharacterId = characterId
+
+	idx := s.FindStepIndex(st.StepId)
+	s.Steps = append([]Step[any]{}, s.Steps...)
+	s.Steps[idx].Payload = payload
+	s.Steps[idx].UpdatedAt = time.Now()
+	s.Variables = s.Variables.With(CreatedCharacterVariable, float64(characterId))
+	GetCache().Put(p.t.Id(), s)
+	return s
+}
+
+// StepFailed records an error raised by the orchestrator itself against the current step's latest attempt, then reacts to
+// it as declared by the step's error handlers. Error codes without a handler fail the step.
+func (p *ProcessorImpl) StepFailed(transactionId uuid.UUID, errorCode string, errorMessage string) error {
+	return p.StepFailedBy("", transactionId, errorCode, errorMessage)
+}
+
+// StepFailedBy records the error reported by a failure event of the service against the current step's latest attempt,
+// then reacts to it as declared by the step's error handlers. Error codes without a handler are classified by the
+// service's registry: business rejections fail the step, while transient errors redeliver it under the retry policy.
+func (p *ProcessorImpl) StepFailedBy(service string, transactionId uuid.UUID, errorCode string, errorMessage string) error {
+	return p.stepFailed(service, transactionId, errorCode, errorMessage, false)
+}
+
+// StepAbandoned records an error raised by the orchestrator giving up awaiting the outcome of the current step's latest
+// attempt, such as on it timing out, then reacts to it as declared by the step's error handlers. As the step's action
+// may have taken effect, it is reversed should the saga be compensated.
+func (p *ProcessorImpl) StepAbandoned(transactionId uuid.UUID, errorCode string, errorMessage string) error {
+	return p.stepFailed("", transactionId, errorCode, errorMessage, true)
+}
+
+func (p *ProcessorImpl) stepFailed(service string, transactionId uuid.UUID, errorCode string, errorMessage string, abandoned bool) error {
+	s, err := p.GetById(transactionId)
+	if err != nil {
+		return p.rejectMismatchedEvent(transactionId, nil, errorCode)
+	}
+
+	idx := s.FindEarliestPendingStepIndex()
+	if s.Failing() || idx == -1 {
+		return p.StepCompleted(transactionId, false)
+	}
+	record := s.RecordStepAttemptError
+	if abandoned {
+		record = s.AbandonStepAttempt
+	}
+	if err = record(idx, errorCode, errorMessage); err == nil {
+		GetCache().Put(p.t.Id(), s)
+	}
+
+	fl := p.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        s.Steps[idx].StepId,
+		"error_code":     errorCode,
+		"tenant_id":      p.t.Id().String(),
+	})
+	h, ok := s.FindErrorHandler(idx, errorCode)
+	if !ok {
+		if service == "" {
+			return p.StepCompleted(transactionId, false)
+		}
+		class := GetClassificationConfig().Classify(service, errorCode)
+		getFailures().Inc(map[string]string{"tenant_id": p.t.Id().String(), "service": service, "class": string(class)})
+		if class == FailureClassTransient && p.parkFailedStep(s, idx, service, fl.WithField("service", service)) {
+			return nil
+		}
+		return p.StepCompleted(transactionId, false)
+	}
+
+	switch h.Reaction {
+	case ErrorReactionSkip:
+		fl.Debug("Skipping failed step as declared by its error handler.")
+		return p.StepCompleted(transactionId, true)
+	case ErrorReactionRetry:
+		maxAttempts := h.MaxAttempts
+		if maxAttempts <= 0 {
+			maxAttempts = DefaultErrorHandlerMaxAttempts
+		}
+		if len(s.Steps[idx].Attempts) >= maxAttempts {
+			fl.Debugf("Step exhausted [%d] attempts. Failing step.", maxAttempts)
+			return p.StepCompleted(transactionId, false)
+		}
+		fl.Debugf("Retrying failed step after [%d] error handler steps, as declared by its error handler.", len(h.Steps))
+		return p.retryStep(s, idx, h)
+	}
+	return p.StepCompleted(transactionId, false)
+}
+
+// retryStep inserts the error handler's steps before the failed step, which remains pending, and progresses the saga
+func (p *ProcessorImpl) retryStep(s Saga, index int, h ErrorHandler) error {
+	attempt := len(s.Steps[index].Attempts)
+	now := time.Now()
+	inserted := make([]Step[any], 0, len(h.Steps))
+	for _, hs := range h.Steps {
+		hs.StepId = fmt.Sprintf("%s_retry%d", hs.StepId, attempt)
+		hs.Status = Pending
+		hs.Attempts = nil
+		hs.CreatedAt = now
+		hs.UpdatedAt = now
+		inserted = append(inserted, hs)
+	}
+
+	steps := make([]Step[any], 0, len(s.Steps)+len(inserted))
+	steps = append(steps, s.Steps[:index]...)
+	steps = append(steps, inserted...)
+	steps = append(steps, s.Steps[index:]...)
+	s.Steps = steps
+
+	if err := s.ValidateStateConsistency(); err != nil {
+		p.l.WithFields(logrus.Fields{
+			"transaction_id": s.TransactionId.String(),
+			"saga_type":      s.SagaType,
+			"tenant_id":      p.t.Id().String(),
+		}).WithError(err).Error("State consistency validation failed after inserting error handler steps.")
+		return p.StepCompleted(s.TransactionId, false)
+	}
+
+	GetCache().Put(p.t.Id(), s)
+	return p.Step(s.TransactionId)
+}
+
 // MarkFurthestCompletedStepFailed marks the furthest completed step as failed
 func (p *ProcessorImpl) MarkFurthestCompletedStepFailed(transactionId uuid.UUID) error {
 	s, err := p.GetById(transactionId)
@@ -417,6 +1034,7 @@ func (p *ProcessorImpl) MarkEarliestPendingStep(transactionId uuid.UUID, status
 
 	// Update the saga in the cache
 	GetCache().Put(p.t.Id(), s)
+	p.projectStep(s, s.Steps[earliestPendingIndex].StepId, status)
 
 	p.l.WithFields(logrus.Fields{
 		"transaction_id": s.TransactionId.String(),
@@ -595,7 +1213,14 @@ func (p *ProcessorImpl) AddStepAfterCurrent(transactionId uuid.UUID, step Step[a
 	return nil
 }
 
-func (p *ProcessorImpl) Step(transactionId uuid.UUID) error {
+// Step progresses the saga, dispatching its current step, compensating its failed step, or completing it. A panic
+// progressing the saga quarantines it, rather than unwinding its caller.
+func (p *ProcessorImpl) Step(transactionId uuid.UUID) (err error) {
+	defer p.isolate(transactionId, &err)
+	return p.step(transactionId)
+}
+
+func (p *ProcessorImpl) step(transactionId uuid.UUID) error {
 	s, err := p.GetById(transactionId)
 	if err != nil {
 		p.l.WithFields(logrus.Fields{
@@ -611,7 +1236,28 @@ func (p *ProcessorImpl) Step(transactionId uuid.UUID) error {
 			"saga_type":      s.SagaType,
 			"tenant_id":      p.t.Id().String(),
 		}).Debug("Reverting saga step.")
-		return p.comp.CompensateFailedStep(s)
+		stepId := ""
+		if idx := s.FindFailedStepIndex(); idx != -1 {
+			stepId = s.Steps[idx].StepId
+		}
+		// Compensation updates the steps of the saga in place, so the receipt is issued for the saga as it failed
+		failing := s
+		failing.Steps = append([]Step[any]{}, s.Steps...)
+		restore := p.setSagaHeaders(s, stepId)
+		produced, err := p.compensate(s)
+		restore()
+		p.issueReceipt(failing, produced, err)
+		return err
+	}
+
+	if s.Held() {
+		p.l.WithFields(logrus.Fields{
+			"transaction_id": s.TransactionId.String(),
+			"saga_type":      s.SagaType,
+			"hold":           s.Hold,
+			"tenant_id":      p.t.Id().String(),
+		}).Debug("Saga is held pending review.")
+		return nil
 	}
 
 	st, ok := s.GetCurrentStep()
@@ -622,9 +1268,24 @@ func (p *ProcessorImpl) Step(transactionId uuid.UUID) error {
 			"tenant_id":      p.t.Id().String(),
 		}).Debug("No steps remaining to progress.")
 		GetCache().Remove(p.t.Id(), s.TransactionId)
+		GetTimerRegistry().Cancel(p.t.Id(), s.TransactionId)
+		GetRetryQueue().Remove(p.t.Id(), s.TransactionId)
+		s = p.initiateOnComplete(s)
+		GetNotifier().Notify(p.t.Id(), s)
+		countFinished(p.t.Id(), s, OutcomeCompleted)
+		SampleCompleted(p.t, s)
+		RecordFinished(p.t.Id(), s, OutcomeCompleted)
+		p.projectFinished(s, "")
 
 		// Emit saga completion event
-		err := producer.ProviderImpl(p.l)(p.ctx)(saga.EnvStatusEventTopic)(CompletedStatusEventProvider(s.TransactionId))
+		var childTransactionId *uuid.UUID
+		if s.OnComplete != nil {
+			childTransactionId = s.OnComplete.TransactionId
+		}
+		restore := p.setSagaHeaders(s, "")
+		event := CompletedStatusEventProvider(s.TransactionId, childTransactionId)
+		p.publishToSubscribers(s, event)
+		err := producer.ProviderImpl(p.l)(p.ctx)(saga.EnvStatusEventTopic)(event)
 		if err != nil {
 			p.l.WithError(err).WithFields(logrus.Fields{
 				"transaction_id": s.TransactionId.String(),
@@ -632,6 +1293,7 @@ func (p *ProcessorImpl) Step(transactionId uuid.UUID) error {
 				"tenant_id":      p.t.Id().String(),
 			}).Error("Failed to emit saga completion event.")
 		}
+		restore()
 
 		return nil
 	}
@@ -648,6 +1310,356 @@ func (p *ProcessorImpl) Step(transactionId uuid.UUID) error {
 		return fmt.Errorf("unknown action type: %s", st.Action)
 	}
 
-	// Execute the handler
-	return handler(s, st)
+	// Render the payload template against the saga's variables and the results of the steps before it
+	if len(st.PayloadTemplate) > 0 {
+		payload, err := RenderPayload(s, st)
+		if err != nil {
+			p.l.WithError(err).WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"step_id":        st.StepId,
+				"tenant_id":      p.t.Id().String(),
+			}).Error("Unable to render step payload template.")
+			_ = p.StepCompleted(s.TransactionId, false)
+			return err
+		}
+		idx := s.FindEarliestPendingStepIndex()
+		s.Steps = append([]Step[any]{}, s.Steps...)
+		s.Steps[idx].Payload = payload
+		GetCache().Put(p.t.Id(), s)
+		st = s.Steps[idx]
+	}
+
+	// Hold suspicious awards for review before they are dispatched
+	if IsAward(st.Action) && !s.Reviewed(st.StepId) {
+		if reason, hold := GetRewardPolicy().Evaluate(p.t.Id(), s, st); hold {
+			s.Hold = PendingReview
+			s.HoldReason = reason
+			GetCache().Put(p.t.Id(), s)
+			p.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"initiated_by":   s.InitiatedBy,
+				"step_id":        st.StepId,
+				"tenant_id":      p.t.Id().String(),
+			}).Warnf("Holding saga for review: %s.", reason)
+			return nil
+		}
+	}
+
+	// Steps affecting an offline character are queued until the character logs in, or failed, as their action's offline
+	// policy declares
+	policy, characterId, offline := p.offlinePolicy(s, st)
+	if offline && policy == OfflineQueue {
+		s.Hold = AwaitingLogin
+		s.HoldReason = fmt.Sprintf("character [%d] is offline", characterId)
+		GetCache().Put(p.t.Id(), s)
+		p.l.WithFields(logrus.Fields{
+			"transaction_id": s.TransactionId.String(),
+			"saga_type":      s.SagaType,
+			"step_id":        st.StepId,
+			"character_id":   characterId,
+			"tenant_id":      p.t.Id().String(),
+		}).Debug("Holding saga until character logs in.")
+		return nil
+	}
+
+	// Record the dispatch, so each attempt of the step can be traced
+	idx := s.FindEarliestPendingStepIndex()
+	if a, err := s.RecordStepAttempt(idx, time.Now()); err == nil {
+		GetCache().Put(p.t.Id(), s)
+		countDispatched(p.t.Id(), s, st.Action)
+		st = s.Steps[idx]
+		p.l.WithFields(logrus.Fields{
+			"transaction_id": s.TransactionId.String(),
+			"saga_type":      s.SagaType,
+			"step_id":        st.StepId,
+			"command_key":    a.CommandKey,
+			"tenant_id":      p.t.Id().String(),
+		}).Debugf("Dispatching attempt [%d] of saga step.", a.Attempt)
+	}
+
+	// Steps added since the saga was created may use actions not enabled for the tenant, which fail without their
+	// commands being produced
+	if !GetFlagConfig().Enabled(p.t.Id(), st.Action) {
+		p.l.WithFields(logrus.Fields{
+			"transaction_id": s.TransactionId.String(),
+			"saga_type":      s.SagaType,
+			"step_id":        st.StepId,
+			"tenant_id":      p.t.Id().String(),
+		}).Warnf("Action [%s] is not enabled for tenant. Failing step.", st.Action)
+		return p.StepFailed(s.TransactionId, ErrorCodeActionNotEnabled, fmt.Sprintf("action [%s] is not enabled", st.Action))
+	}
+
+	if offline && policy == OfflineFail {
+		p.l.WithFields(logrus.Fields{
+			"transaction_id": s.TransactionId.String(),
+			"saga_type":      s.SagaType,
+			"step_id":        st.StepId,
+			"character_id":   characterId,
+			"tenant_id":      p.t.Id().String(),
+		}).Warn("Character is offline. Failing step.")
+		return p.StepFailed(s.TransactionId, ErrorCodeCharacterOffline, fmt.Sprintf("character [%d] is offline", characterId))
+	}
+
+	// The dispatch is written before the step is, so a replica restarting does not dispatch it again. A step which cannot
+	// be written is parked for redelivery as one whose commands could not be produced.
+	if err = persist(p.l, p.t.Id(), s.TransactionId); err != nil {
+		if p.handleDispatchError(s, st, err) {
+			return nil
+		}
+		return err
+	}
+
+	// Execute the handler, or resume producing the commands of a step interrupted part way
+	restore := p.setSagaHeaders(s, st.StepId)
+	if len(st.Outbox) > 0 {
+		err = p.flushOutbox(s.TransactionId, st.StepId, st.Outbox, true)
+	} else {
+		err = p.dispatch(s, st, handler)
+	}
+	restore()
+	if err != nil {
+		// Rejected steps will never receive a status event, so fail them to trigger compensation. Rejections declaring an
+		// error code fail with it, so their error handlers may react and compensation treats them as not taken effect.
+		var re RejectedError
+		if errors.As(err, &re) {
+			_ = p.StepFailed(s.TransactionId, re.Code, re.Reason)
+		} else if errors.Is(err, ErrActionRejected) {
+			_ = p.StepCompleted(s.TransactionId, false)
+		}
+		// Commands which could not be produced are parked for redelivery, rather than leaving the step awaiting an event
+		// which will never arrive
+		if p.handleDispatchError(s, st, err) {
+			return nil
+		}
+		return err
+	}
+
+	if IsAward(st.Action) {
+		GetRewardPolicy().Record(p.t.Id(), s, st)
+	}
+
+	// Actions which complete locally will not receive a status event, so progress immediately
+	if completesLocally(st.Action) {
+		return p.StepCompleted(s.TransactionId, true)
+	}
+	return nil
+}
+
+// initiateOnComplete initiates the follow-up saga declared by the completed saga's onComplete, if any, returning the
+// completed saga with the follow-up's transaction recorded. The completed saga is not affected should the follow-up
+// fail to initiate.
+func (p *ProcessorImpl) initiateOnComplete(s Saga) Saga {
+	if s.OnComplete == nil || s.OnComplete.Initiated() {
+		return s
+	}
+	fl := p.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"template":       s.OnComplete.Template,
+		"tenant_id":      p.t.Id().String(),
+	})
+
+	child, err := NewChainedSaga(s)
+	if err != nil {
+		fl.WithError(err).Error("Unable to create follow-up saga.")
+		return s
+	}
+	// A follow-up whose first step fails to dispatch has still been initiated, and compensates as any other saga
+	if err = p.Put(child); err != nil && !errors.Is(err, ErrSagaQueued) {
+		if _, ok := GetCache().GetById(p.t.Id(), child.TransactionId); !ok {
+			fl.WithError(err).Errorf("Unable to initiate follow-up saga [%s].", child.TransactionId.String())
+			return s
+		}
+	}
+	fl.Infof("Initiated follow-up saga [%s].", child.TransactionId.String())
+
+	oc := *s.OnComplete
+	oc.TransactionId = &child.TransactionId
+	s.OnComplete = &oc
+	return s
+}
+
+// setSagaHeaders records the saga step being dispatched, so commands produced for it carry its metadata as headers,
+// returning a function restoring the prior metadata
+func (p *ProcessorImpl) setSagaHeaders(s Saga, stepId string) func() {
+	return header.Set(p.ctx, header.Saga{TransactionId: s.TransactionId, SagaType: string(s.SagaType), StepId: stepId})
+}
+
+// Review records an operator's decision on a held saga. An approved saga continues from the held step, while a
+// rejected saga fails the held step, compensating the steps already completed.
+func (p *ProcessorImpl) Review(transactionId uuid.UUID, approved bool, reviewer string, comment string) error {
+	s, err := p.GetById(transactionId)
+	if err != nil {
+		return err
+	}
+	if !s.Held() {
+		return ErrSagaNotHeld
+	}
+	if s.Hold == PendingResolution {
+		return ErrResolutionRequired
+	}
+	if approved && s.Hold == PendingApproval && reviewer == s.InitiatedBy {
+		return ErrSelfApproval
+	}
+
+	r := Review{
+		Hold:       s.Hold,
+		Reason:     s.HoldReason,
+		Approved:   approved,
+		Reviewer:   reviewer,
+		Comment:    comment,
+		ReviewedAt: time.Now(),
+	}
+	// Reviews of held award steps are recorded against the step, so it is not held again
+	if st, ok := s.GetCurrentStep(); ok && s.Hold == PendingReview {
+		r.StepId = st.StepId
+	}
+	s.Reviews = append(s.Reviews, r)
+	s.Hold = ""
+	s.HoldReason = ""
+	GetCache().Put(p.t.Id(), s)
+
+	p.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"hold":           r.Hold,
+		"step_id":        r.StepId,
+		"reviewer":       reviewer,
+		"approved":       approved,
+		"tenant_id":      p.t.Id().String(),
+	}).Info("Saga reviewed.")
+
+	if !approved {
+		return p.StepCompleted(transactionId, false)
+	}
+	return p.Step(transactionId)
+}
+
+// Resolve records an operator's resolution of the dispute a saga is held pending, completing the held resolve_dispute
+// step. The resolution is recorded on the step's payload, so its branches select the outcome, and must name one of
+// them when the step declares branches.
+func (p *ProcessorImpl) Resolve(transactionId uuid.UUID, resolution string, resolver string, comment string) error {
+	s, err := p.GetById(transactionId)
+	if err != nil {
+		return err
+	}
+	if s.Hold != PendingResolution {
+		return ErrSagaNotHeld
+	}
+	idx := s.FindEarliestPendingStepIndex()
+	if idx == -1 {
+		return ErrSagaNotHeld
+	}
+	st := s.Steps[idx]
+	payload, ok := st.Payload.(ResolveDisputePayload)
+	if !ok {
+		return ErrSagaNotHeld
+	}
+	if resolution == "" {
+		return fmt.Errorf("%w: resolution is required", ErrInvalidResolution)
+	}
+	if len(st.Branches) > 0 {
+		offered := false
+		for _, b := range st.Branches {
+			offered = offered || b.Name == resolution
+		}
+		if !offered {
+			return fmt.Errorf("%w: dispute does not offer resolution '%s'", ErrInvalidResolution, resolution)
+		}
+	}
+
+	s.Reviews = append(s.Reviews, Review{
+		Hold:       s.Hold,
+		StepId:     st.StepId,
+		Reason:     s.HoldReason,
+		Approved:   true,
+		Resolution: resolution,
+		Reviewer:   resolver,
+		Comment:    comment,
+		ReviewedAt: time.Now(),
+	})
+	s.Hold = ""
+	s.HoldReason = ""
+	payload.Resolution = resolution
+	payload.ResolvedBy = resolver
+	s.Steps = append([]Step[any]{}, s.Steps...)
+	s.Steps[idx].Payload = payload
+	GetCache().Put(p.t.Id(), s)
+
+	p.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        st.StepId,
+		"ticket_id":      payload.TicketId,
+		"resolver":       resolver,
+		"resolution":     resolution,
+		"tenant_id":      p.t.Id().String(),
+	}).Info("Saga dispute resolved.")
+
+	return p.StepCompleted(transactionId, true)
+}
+
+// AddNote records an operator's comment on a saga, leaving its execution unaffected
+func (p *ProcessorImpl) AddNote(transactionId uuid.UUID, author string, comment string) (Note, error) {
+	s, err := p.GetById(transactionId)
+	if err != nil {
+		return Note{}, err
+	}
+
+	n := Note{Author: author, Comment: comment, CreatedAt: time.Now()}
+	s.Notes = append(append([]Note{}, s.Notes...), n)
+	GetCache().Put(p.t.Id(), s)
+
+	p.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"author":         author,
+		"tenant_id":      p.t.Id().String(),
+	}).Infof("Note added to saga: %s", comment)
+	return n, nil
+}
+
+// AwaitTerminal blocks until the saga completes or fails, or the timeout elapses, returning the latest state of the saga.
+// Completed sagas are removed from the cache, so only sagas in progress at the time of the call can be awaited. The wait
+// ends early when ctx is done, such as when the client of the request awaiting the saga disconnects.
+func (p *ProcessorImpl) AwaitTerminal(ctx context.Context, transactionId uuid.UUID, timeout time.Duration) (Saga, error) {
+	// Subscribe before inspecting the saga so a transition between the two cannot be missed
+	c, unsubscribe := GetNotifier().Subscribe(p.t.Id(), transactionId)
+	defer unsubscribe()
+
+	s, err := p.GetById(transactionId)
+	if err != nil {
+		return Saga{}, err
+	}
+	if s.Failing() || s.Receipt != nil || timeout <= 0 {
+		return s, nil
+	}
+
+	t := time.NewTimer(timeout)
+	defer t.Stop()
+
+	select {
+	case ts := <-c:
+		return ts, nil
+	case <-t.C:
+		p.l.WithFields(logrus.Fields{
+			"transaction_id": transactionId.String(),
+			"saga_type":      s.SagaType,
+			"tenant_id":      p.t.Id().String(),
+		}).Debugf("Saga did not reach a terminal state within [%s].", timeout)
+	case <-ctx.Done():
+	}
+
+	if cs, err := p.GetById(transactionId); err == nil {
+		return cs, nil
+	}
+	// The saga completed as the wait ended
+	select {
+	case ts := <-c:
+		return ts, nil
+	default:
+		return s, nil
+	}
 }
@@ -1,16 +1,21 @@
 package saga
 
 import (
+	"atlas-saga-orchestrator/asset"
 	"atlas-saga-orchestrator/character"
 	"atlas-saga-orchestrator/character/mock"
 	"atlas-saga-orchestrator/compartment"
 	mock2 "atlas-saga-orchestrator/compartment/mock"
+	"atlas-saga-orchestrator/kafka/header"
+	character2 "atlas-saga-orchestrator/kafka/message/character"
+	mock4 "atlas-saga-orchestrator/skill/mock"
 	"atlas-saga-orchestrator/validation"
 	mock3 "atlas-saga-orchestrator/validation/mock"
 	"context"
 	"errors"
 	"fmt"
 	"github.com/Chronicle20/atlas-constants/channel"
+	"github.com/Chronicle20/atlas-constants/inventory"
 	"github.com/Chronicle20/atlas-constants/job"
 	_map "github.com/Chronicle20/atlas-constants/map"
 	"github.com/Chronicle20/atlas-constants/world"
@@ -19,6 +24,9 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"runtime"
+	"sync"
 	"testing"
 	"time"
 )
@@ -425,3 +433,888 @@ func TestTransformExperienceDistributions(t *testing.T) {
 		assert.Equal(t, s.Attr1, target[i].Attr1)
 	}
 }
+
+// TestMinigameRewardSaga tests a minigame payout end-to-end: the ticket is validated and consumed, a prize is drawn,
+// and steps awarding the prize are added dynamically
+func TestMinigameRewardSaga(t *testing.T) {
+	charP := &mock.ProcessorMock{}
+	compP := &mock2.ProcessorMock{}
+	validP := &mock3.ProcessorMock{}
+
+	te, ctx := setupContext()
+	processor, _ := setupTestProcessor(ctx, charP, compP, validP)
+
+	validP.ValidateCharacterStateFunc = func(characterId uint32, conditions []validation.ConditionInput) (validation.ValidationResult, error) {
+		return validation.NewValidationResult(characterId), nil
+	}
+	var destroyed, created []uint32
+	compP.RequestDestroyItemFunc = func(transactionId uuid.UUID, characterId uint32, templateId uint32, quantity uint32) error {
+		destroyed = append(destroyed, templateId)
+		return nil
+	}
+	compP.RequestCreateItemFunc = func(transactionId uuid.UUID, characterId uint32, templateId uint32, quantity uint32) error {
+		created = append(created, templateId)
+		return nil
+	}
+
+	transactionId := uuid.New()
+	saga := Saga{
+		TransactionId: transactionId,
+		SagaType:      MinigameReward,
+		InitiatedBy:   "integration-test",
+		Steps: []Step[any]{
+			{StepId: "validate-ticket", Status: Pending, Action: ValidateCharacterState, Payload: ValidateCharacterStatePayload{
+				CharacterId: 12345,
+				Conditions:  []validation.ConditionInput{{Type: "item", Operator: ">=", Value: 1, ItemId: 4031017}},
+			}},
+			{StepId: "consume-ticket", Status: Pending, Action: DestroyAsset, Payload: DestroyAssetPayload{CharacterId: 12345, TemplateId: 4031017, Quantity: 1}},
+			{StepId: "draw-prize", Status: Pending, Action: ResolvePrizeTable, Payload: ResolvePrizeTablePayload{
+				CharacterId: 12345,
+				Prizes:      []PrizeEntry{{Weight: 1, Item: &ItemPayload{TemplateId: 2000000, Quantity: 5}, Mesos: 100}},
+			}},
+		},
+	}
+	GetCache().Put(te.Id(), saga)
+	defer GetCache().Remove(te.Id(), transactionId)
+
+	// Validation completes locally, so the ticket is consumed immediately
+	assert.NoError(t, processor.Step(transactionId))
+	assert.Equal(t, []uint32{4031017}, destroyed)
+
+	// Ticket consumed, so the prize is drawn and the first award step executed
+	assert.NoError(t, processor.StepCompleted(transactionId, true))
+	assert.Equal(t, []uint32{2000000}, created)
+
+	result, err := processor.GetById(transactionId)
+	assert.NoError(t, err)
+	assert.Len(t, result.Steps, 5)
+	assert.Equal(t, "draw-prize", result.Steps[2].StepId)
+	assert.Equal(t, Completed, result.Steps[2].Status)
+	assert.NotNil(t, result.Steps[2].Payload.(ResolvePrizeTablePayload).Resolved)
+	assert.Equal(t, "draw-prize_item", result.Steps[3].StepId)
+	assert.Equal(t, Pending, result.Steps[3].Status)
+	assert.Equal(t, "draw-prize_mesos", result.Steps[4].StepId)
+	assert.Equal(t, AwardMesos, result.Steps[4].Action)
+}
+
+// TestResolvePrize tests drawing entries from a weighted prize table
+func TestResolvePrize(t *testing.T) {
+	prizes := []PrizeEntry{
+		{Weight: 70},
+		{Weight: 25, Mesos: 1000},
+		{Weight: 5, Item: &ItemPayload{TemplateId: 2000000, Quantity: 1}},
+	}
+	assert.Equal(t, prizes[0], resolvePrize(prizes, 0))
+	assert.Equal(t, prizes[0], resolvePrize(prizes, 69))
+	assert.Equal(t, prizes[1], resolvePrize(prizes, 70))
+	assert.Equal(t, prizes[1], resolvePrize(prizes, 94))
+	assert.Equal(t, prizes[2], resolvePrize(prizes, 95))
+	assert.Equal(t, PrizeEntry{}, resolvePrize(prizes, 100))
+}
+
+// TestStepAttemptHistory tests that each dispatch of a step is recorded, along with the error reported by a failure event
+func TestStepAttemptHistory(t *testing.T) {
+	charP := &mock.ProcessorMock{}
+	compP := &mock2.ProcessorMock{}
+
+	te, ctx := setupContext()
+	processor, _ := setupTestProcessor(ctx, charP, compP)
+
+	transactionId := uuid.New()
+	saga := Saga{
+		TransactionId: transactionId,
+		SagaType:      InventoryTransaction,
+		InitiatedBy:   "integration-test",
+		Steps: []Step[any]{
+			{StepId: "award-item", Status: Pending, Action: AwardAsset, Payload: AwardItemActionPayload{CharacterId: 12345, Item: ItemPayload{TemplateId: 2000000, Quantity: 1}}},
+		},
+	}
+	GetCache().Put(te.Id(), saga)
+	defer GetCache().Remove(te.Id(), transactionId)
+
+	// Dispatch the step twice, as happens when a saga command is redelivered
+	assert.NoError(t, processor.Step(transactionId))
+	assert.NoError(t, processor.Step(transactionId))
+
+	result, err := processor.GetById(transactionId)
+	assert.NoError(t, err)
+	attempts := result.Steps[0].Attempts
+	assert.Len(t, attempts, 2)
+	assert.Equal(t, 1, attempts[0].Attempt)
+	assert.Equal(t, "award-item#1", attempts[0].CommandKey)
+	assert.Equal(t, "award-item#2", attempts[1].CommandKey)
+	assert.False(t, attempts[1].DispatchedAt.IsZero())
+
+	// The error reported downstream is retained against the latest attempt
+	assert.NoError(t, processor.StepFailed(transactionId, "INVENTORY_FULL", "no free slot"))
+
+	result, err = processor.GetById(transactionId)
+	assert.NoError(t, err)
+	attempts = result.Steps[0].Attempts
+	assert.Empty(t, attempts[0].ErrorCode)
+	assert.Equal(t, "INVENTORY_FULL", attempts[1].ErrorCode)
+	assert.Equal(t, "no free slot", attempts[1].ErrorMessage)
+}
+
+// TestStepFailedErrorHandlers tests that steps react to declared error codes rather than always compensating
+func TestStepFailedErrorHandlers(t *testing.T) {
+	onError := []ErrorHandler{
+		{
+			ErrorCode:   "INVENTORY_FULL",
+			Reaction:    ErrorReactionRetry,
+			MaxAttempts: 2,
+			Steps: []Step[any]{
+				{StepId: "free-slot", Action: DestroyAsset, Payload: DestroyAssetPayload{CharacterId: 12345, TemplateId: 4000000, Quantity: 1}},
+			},
+		},
+		{ErrorCode: "ALREADY_OWNED", Reaction: ErrorReactionSkip},
+		{ErrorCode: "NAME_TAKEN", Reaction: ErrorReactionFail},
+	}
+	newSaga := func() Saga {
+		return Saga{
+			TransactionId: uuid.New(),
+			SagaType:      InventoryTransaction,
+			InitiatedBy:   "integration-test",
+			Steps: []Step[any]{
+				{StepId: "award-item", Status: Pending, Action: AwardAsset, Payload: AwardItemActionPayload{CharacterId: 12345, Item: ItemPayload{TemplateId: 2000000, Quantity: 1}}, OnError: onError},
+				{StepId: "award-mesos", Status: Pending, Action: AwardMesos, Payload: AwardMesosPayload{CharacterId: 12345, Amount: 100}},
+			},
+		}
+	}
+
+	t.Run("retry runs the handler steps then retries the step", func(t *testing.T) {
+		compP := &mock2.ProcessorMock{}
+		var calls []string
+		compP.RequestCreateItemFunc = func(transactionId uuid.UUID, characterId uint32, templateId uint32, quantity uint32) error {
+			calls = append(calls, "create")
+			return nil
+		}
+		compP.RequestDestroyItemFunc = func(transactionId uuid.UUID, characterId uint32, templateId uint32, quantity uint32) error {
+			calls = append(calls, "destroy")
+			return nil
+		}
+		te, ctx := setupContext()
+		processor, _ := setupTestProcessor(ctx, &mock.ProcessorMock{}, compP)
+
+		s := newSaga()
+		GetCache().Put(te.Id(), s)
+		defer GetCache().Remove(te.Id(), s.TransactionId)
+
+		assert.NoError(t, processor.Step(s.TransactionId))
+		assert.NoError(t, processor.StepFailed(s.TransactionId, "INVENTORY_FULL", ""))
+		assert.Equal(t, []string{"create", "destroy"}, calls)
+
+		result, err := processor.GetById(s.TransactionId)
+		assert.NoError(t, err)
+		assert.Len(t, result.Steps, 3)
+		assert.Equal(t, "free-slot_retry1", result.Steps[0].StepId)
+		assert.Equal(t, "award-item", result.Steps[1].StepId)
+		assert.Equal(t, Pending, result.Steps[1].Status)
+
+		// The inserted step completes, so the failed step is retried
+		assert.NoError(t, processor.StepCompleted(s.TransactionId, true))
+		assert.Equal(t, []string{"create", "destroy", "create"}, calls)
+
+		// Attempts are exhausted, so the step fails
+		assert.NoError(t, processor.StepFailed(s.TransactionId, "INVENTORY_FULL", ""))
+		assert.Equal(t, []string{"create", "destroy", "create"}, calls)
+		result, err = processor.GetById(s.TransactionId)
+		assert.NoError(t, err)
+		assert.Len(t, result.Steps, 3)
+		assert.Len(t, result.Steps[1].Attempts, 2)
+	})
+
+	t.Run("skip continues the saga", func(t *testing.T) {
+		charP := &mock.ProcessorMock{}
+		awarded := false
+		charP.AwardMesosAndEmitFunc = func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, actorId uint32, actorType string, amount int32) error {
+			awarded = true
+			return nil
+		}
+		te, ctx := setupContext()
+		processor, _ := setupTestProcessor(ctx, charP, &mock2.ProcessorMock{})
+
+		s := newSaga()
+		GetCache().Put(te.Id(), s)
+		defer GetCache().Remove(te.Id(), s.TransactionId)
+
+		assert.NoError(t, processor.Step(s.TransactionId))
+		assert.NoError(t, processor.StepFailed(s.TransactionId, "ALREADY_OWNED", ""))
+		assert.True(t, awarded)
+
+		result, err := processor.GetById(s.TransactionId)
+		assert.NoError(t, err)
+		assert.Equal(t, Completed, result.Steps[0].Status)
+	})
+
+	for _, errorCode := range []string{"NAME_TAKEN", "UNKNOWN"} {
+		t.Run(errorCode+" fails the step", func(t *testing.T) {
+			charP := &mock.ProcessorMock{}
+			awarded := false
+			charP.AwardMesosAndEmitFunc = func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, actorId uint32, actorType string, amount int32) error {
+				awarded = true
+				return nil
+			}
+			te, ctx := setupContext()
+			processor, hook := setupTestProcessor(ctx, charP, &mock2.ProcessorMock{})
+
+			s := newSaga()
+			GetCache().Put(te.Id(), s)
+			defer GetCache().Remove(te.Id(), s.TransactionId)
+
+			assert.NoError(t, processor.Step(s.TransactionId))
+			assert.NoError(t, processor.StepFailed(s.TransactionId, errorCode, ""))
+			assert.False(t, awarded)
+
+			compensated := false
+			for _, e := range hook.AllEntries() {
+				if e.Message == "Compensating failed step." {
+					compensated = true
+				}
+			}
+			assert.True(t, compensated)
+		})
+	}
+}
+
+// equipmentCompartment builds an equipment compartment holding the template IDs in the slots provided
+func equipmentCompartment(capacity uint32, slots map[int16]uint32) compartment.Model {
+	id := uuid.New()
+	b := compartment.NewBuilder(id, 12345, inventory.TypeValueEquip, capacity)
+	assetId := uint32(1)
+	for slot, templateId := range slots {
+		b.AddAsset(asset.NewBuilder[any](assetId, id, templateId, assetId, asset.ReferenceTypeEquipable).SetSlot(slot).Build())
+		assetId++
+	}
+	return b.Build()
+}
+
+// TestEquipmentPresetSaga tests that a preset expands into ordered unequip and equip steps, and that a failure restores the prior loadout
+func TestEquipmentPresetSaga(t *testing.T) {
+	charP := &mock.ProcessorMock{}
+	compP := &mock2.ProcessorMock{}
+
+	te, ctx := setupContext()
+	processor, _ := setupTestProcessor(ctx, charP, compP)
+
+	compP.GetByTypeFunc = func(characterId uint32, inventoryType inventory.Type) (compartment.Model, error) {
+		assert.Equal(t, inventory.TypeValueEquip, inventoryType)
+		return equipmentCompartment(4, map[int16]uint32{-11: 1302000, -5: 1040002, 1: 1302007, 2: 1060002}), nil
+	}
+	var moves []string
+	compP.RequestUnequipAssetFunc = func(transactionId uuid.UUID, characterId uint32, inventoryType byte, source int16, destination int16) error {
+		moves = append(moves, fmt.Sprintf("unequip %d>%d", source, destination))
+		return nil
+	}
+	compP.RequestEquipAssetFunc = func(transactionId uuid.UUID, characterId uint32, inventoryType byte, source int16, destination int16) error {
+		moves = append(moves, fmt.Sprintf("equip %d>%d", source, destination))
+		return nil
+	}
+
+	transactionId := uuid.New()
+	saga := Saga{
+		TransactionId: transactionId,
+		SagaType:      InventoryTransaction,
+		InitiatedBy:   "integration-test",
+		Steps: []Step[any]{
+			{StepId: "preset", Status: Pending, Action: ApplyEquipmentPreset, Payload: ApplyEquipmentPresetPayload{
+				CharacterId: 12345,
+				Items:       []EquippedItem{{TemplateId: 1302007, Slot: -11}, {TemplateId: 1060002, Slot: -6}, {TemplateId: 1040002, Slot: -5}},
+			}},
+		},
+	}
+	GetCache().Put(te.Id(), saga)
+	defer GetCache().Remove(te.Id(), transactionId)
+
+	// The preset completes locally, so the first unequip is dispatched immediately
+	assert.NoError(t, processor.Step(transactionId))
+
+	result, err := processor.GetById(transactionId)
+	assert.NoError(t, err)
+	expected := []string{"preset", "preset_unequip_1", "preset_unequip_2", "preset_equip_1", "preset_equip_2", "preset_equip_3"}
+	if assert.Len(t, result.Steps, len(expected)) {
+		for i, id := range expected {
+			assert.Equal(t, id, result.Steps[i].StepId)
+		}
+	}
+	assert.Equal(t, []EquippedItem{{TemplateId: 1302000, Slot: -11}, {TemplateId: 1040002, Slot: -5}}, result.Steps[0].Payload.(ApplyEquipmentPresetPayload).Captured)
+
+	// Unequip the current set, then equip the first preset item
+	assert.NoError(t, processor.StepCompleted(transactionId, true))
+	assert.NoError(t, processor.StepCompleted(transactionId, true))
+	assert.NoError(t, processor.StepCompleted(transactionId, true))
+	assert.Equal(t, []string{"unequip -11>3", "unequip -5>4", "equip 1>-11", "equip 2>-6"}, moves)
+
+	// The second preset item fails to equip, so the completed preset steps are reversed
+	moves = nil
+	_ = processor.StepCompleted(transactionId, false)
+	assert.Equal(t, []string{"unequip -11>1", "equip 4>-5", "equip 3>-11"}, moves)
+}
+
+// TestGrantMountSaga tests that a mount grant expands into skill and item steps, and that a failure of either removes both
+func TestGrantMountSaga(t *testing.T) {
+	compP := &mock2.ProcessorMock{}
+	skillP := &mock4.ProcessorMock{}
+
+	te, ctx := setupContext()
+	base, _ := setupTestProcessor(ctx, nil, compP)
+	processor := base.WithSkillProcessor(skillP)
+
+	var calls []string
+	skillP.RequestCreateAndEmitFunc = func(transactionId uuid.UUID, characterId uint32, skillId uint32, level byte, masterLevel byte, expiration time.Time) error {
+		calls = append(calls, fmt.Sprintf("create skill %d level %d", skillId, level))
+		return nil
+	}
+	skillP.RequestDeleteAndEmitFunc = func(transactionId uuid.UUID, characterId uint32, skillId uint32) error {
+		calls = append(calls, fmt.Sprintf("delete skill %d", skillId))
+		return nil
+	}
+	compP.RequestCreateItemFunc = func(transactionId uuid.UUID, characterId uint32, templateId uint32, quantity uint32) error {
+		calls = append(calls, fmt.Sprintf("create item %d", templateId))
+		return nil
+	}
+	compP.RequestDestroyItemFunc = func(transactionId uuid.UUID, characterId uint32, templateId uint32, quantity uint32) error {
+		calls = append(calls, fmt.Sprintf("destroy item %d", templateId))
+		return nil
+	}
+
+	transactionId := uuid.New()
+	saga := Saga{
+		TransactionId: transactionId,
+		SagaType:      QuestReward,
+		InitiatedBy:   "integration-test",
+		Steps: []Step[any]{
+			{StepId: "mount", Status: Pending, Action: GrantMount, Payload: GrantMountPayload{CharacterId: 12345, SkillId: 1004, ItemId: 1902000}},
+		},
+	}
+	GetCache().Put(te.Id(), saga)
+	defer GetCache().Remove(te.Id(), transactionId)
+
+	// The grant completes locally, so the skill is requested immediately
+	require.NoError(t, processor.Step(transactionId))
+
+	result, err := processor.GetById(transactionId)
+	require.NoError(t, err)
+	expected := []Action{GrantMount, CreateSkill, AwardAsset}
+	if assert.Len(t, result.Steps, len(expected)) {
+		for i, a := range expected {
+			assert.Equal(t, a, result.Steps[i].Action)
+		}
+		assert.Equal(t, "mount_skill", result.Steps[1].StepId)
+		assert.Equal(t, "mount_item", result.Steps[2].StepId)
+	}
+
+	// The item fails to be created, so the skill granted is removed
+	require.NoError(t, processor.StepCompleted(transactionId, true))
+	_ = processor.StepCompleted(transactionId, false)
+	assert.Equal(t, []string{"create skill 1004 level 1", "create item 1902000", "delete skill 1004"}, calls)
+
+	result, err = processor.GetById(transactionId)
+	require.NoError(t, err)
+	require.NotNil(t, result.Receipt)
+	assert.True(t, result.Receipt.RolledBack)
+	assert.Equal(t, []ReceiptEntry{
+		{StepId: "mount_skill", Action: CreateSkill, Outcome: ReceiptReverted},
+		{StepId: "mount_item", Action: AwardAsset, Outcome: ReceiptNotApplied},
+	}, result.Receipt.Steps)
+}
+
+// TestAwardPartyExperienceSaga tests that a party experience split expands into a step awarding each member their share,
+// and that a failure of any deducts the shares already awarded
+func TestAwardPartyExperienceSaga(t *testing.T) {
+	charP := &mock.ProcessorMock{}
+
+	te, ctx := setupContext()
+	processor, _ := setupTestProcessor(ctx, charP, nil)
+
+	var calls []string
+	charP.AwardExperienceAndEmitFunc = func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, distributions []character2.ExperienceDistributions) error {
+		calls = append(calls, fmt.Sprintf("award %d %s %d", characterId, distributions[0].ExperienceType, distributions[0].Amount))
+		return nil
+	}
+	charP.DeductExperienceAndEmitFunc = func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, amount uint32) error {
+		calls = append(calls, fmt.Sprintf("deduct %d %d", characterId, amount))
+		return nil
+	}
+
+	transactionId := uuid.New()
+	saga := Saga{
+		TransactionId: transactionId,
+		SagaType:      QuestReward,
+		InitiatedBy:   "integration-test",
+		Steps: []Step[any]{
+			{StepId: "party", Status: Pending, Action: AwardPartyExperience, Payload: AwardPartyExperiencePayload{Amount: 1000, Members: []PartyMember{{CharacterId: 1, Level: 30}, {CharacterId: 2, Level: 70}}}},
+		},
+	}
+	GetCache().Put(te.Id(), saga)
+	defer GetCache().Remove(te.Id(), transactionId)
+
+	// The split completes locally, so the first member is awarded immediately
+	require.NoError(t, processor.Step(transactionId))
+
+	result, err := processor.GetById(transactionId)
+	require.NoError(t, err)
+	require.Len(t, result.Steps, 3)
+	assert.Equal(t, []PartyExperienceShare{{CharacterId: 1, Amount: 300}, {CharacterId: 2, Amount: 700}}, result.Steps[0].Payload.(AwardPartyExperiencePayload).Shares)
+	assert.Equal(t, "party_member_1", result.Steps[1].StepId)
+	assert.Equal(t, "party_member_2", result.Steps[2].StepId)
+
+	// The second member's award fails, so the first member's share is deducted
+	require.NoError(t, processor.StepCompleted(transactionId, true))
+	_ = processor.StepCompleted(transactionId, false)
+	assert.Equal(t, []string{"award 1 PARTY 300", "award 2 PARTY 700", "deduct 1 300"}, calls)
+
+	result, err = processor.GetById(transactionId)
+	require.NoError(t, err)
+	require.NotNil(t, result.Receipt)
+	assert.True(t, result.Receipt.RolledBack)
+	assert.Equal(t, []ReceiptEntry{
+		{StepId: "party_member_1", Action: AwardExperience, Outcome: ReceiptReverted},
+		{StepId: "party_member_2", Action: AwardExperience, Outcome: ReceiptNotApplied},
+	}, result.Receipt.Steps)
+}
+
+// TestSplitPartyExperience tests that a pool is split in proportion to the members' levels, with the experience lost to
+// rounding awarded to the members with the largest remainders
+func TestSplitPartyExperience(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  uint32
+		members []PartyMember
+		expect  []uint32
+	}{
+		{name: "Single member", amount: 999, members: []PartyMember{{CharacterId: 1, Level: 50}}, expect: []uint32{999}},
+		{name: "Equal levels", amount: 1000, members: []PartyMember{{CharacterId: 1, Level: 10}, {CharacterId: 2, Level: 10}, {CharacterId: 3, Level: 10}}, expect: []uint32{334, 333, 333}},
+		{name: "Weighted by level", amount: 100, members: []PartyMember{{CharacterId: 1, Level: 10}, {CharacterId: 2, Level: 20}, {CharacterId: 3, Level: 40}}, expect: []uint32{14, 29, 57}},
+		{name: "Pool smaller than the party", amount: 1, members: []PartyMember{{CharacterId: 1, Level: 10}, {CharacterId: 2, Level: 200}}, expect: []uint32{0, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shares := splitPartyExperience(tt.amount, tt.members)
+			require.Len(t, shares, len(tt.expect))
+			total := uint32(0)
+			for i, s := range shares {
+				assert.Equal(t, tt.members[i].CharacterId, s.CharacterId)
+				assert.Equal(t, tt.expect[i], s.Amount)
+				total += s.Amount
+			}
+			assert.Equal(t, tt.amount, total)
+		})
+	}
+}
+
+// TestToggleCharacterAbilitySaga tests that an ability toggle expands into a step per character, enabling the ability by
+// creating its skill and disabling it by reducing the skill to level 0, and that a failure of any reverses the others
+func TestToggleCharacterAbilitySaga(t *testing.T) {
+	skillP := &mock4.ProcessorMock{}
+
+	te, ctx := setupContext()
+	base, _ := setupTestProcessor(ctx, nil, nil)
+	processor := base.WithSkillProcessor(skillP)
+
+	var calls []string
+	skillP.RequestCreateAndEmitFunc = func(transactionId uuid.UUID, characterId uint32, skillId uint32, level byte, masterLevel byte, expiration time.Time) error {
+		calls = append(calls, fmt.Sprintf("create %d skill %d level %d", characterId, skillId, level))
+		return nil
+	}
+	skillP.RequestUpdateAndEmitFunc = func(transactionId uuid.UUID, characterId uint32, skillId uint32, level byte, masterLevel byte, expiration time.Time) error {
+		calls = append(calls, fmt.Sprintf("update %d skill %d level %d", characterId, skillId, level))
+		return nil
+	}
+	skillP.RequestDeleteAndEmitFunc = func(transactionId uuid.UUID, characterId uint32, skillId uint32) error {
+		calls = append(calls, fmt.Sprintf("delete %d skill %d", characterId, skillId))
+		return nil
+	}
+
+	transactionId := uuid.New()
+	saga := Saga{
+		TransactionId: transactionId,
+		SagaType:      InventoryTransaction,
+		InitiatedBy:   "integration-test",
+		Steps: []Step[any]{
+			{StepId: "link", Status: Pending, Action: ToggleCharacterAbility, Payload: ToggleCharacterAbilityPayload{
+				AccountId: 1000,
+				SkillId:   20000014,
+				Level:     1,
+				Toggles:   []AbilityToggle{{CharacterId: 1, Enabled: false}, {CharacterId: 2, Enabled: true}, {CharacterId: 3, Enabled: true}},
+			}},
+		},
+	}
+	GetCache().Put(te.Id(), saga)
+	defer GetCache().Remove(te.Id(), transactionId)
+
+	// The toggle completes locally, so the first character's ability is disabled immediately
+	require.NoError(t, processor.Step(transactionId))
+
+	result, err := processor.GetById(transactionId)
+	require.NoError(t, err)
+	expected := []Action{ToggleCharacterAbility, UpdateSkill, CreateSkill, CreateSkill}
+	if assert.Len(t, result.Steps, len(expected)) {
+		for i, a := range expected {
+			assert.Equal(t, a, result.Steps[i].Action)
+			if i > 0 {
+				assert.Equal(t, fmt.Sprintf("link_toggle_%d", i), result.Steps[i].StepId)
+			}
+		}
+	}
+
+	// The third character's ability fails to be enabled, so the first is restored and the second removed
+	require.NoError(t, processor.StepCompleted(transactionId, true))
+	require.NoError(t, processor.StepCompleted(transactionId, true))
+	_ = processor.StepCompleted(transactionId, false)
+	assert.Equal(t, []string{
+		"update 1 skill 20000014 level 0",
+		"create 2 skill 20000014 level 1",
+		"create 3 skill 20000014 level 1",
+		"delete 2 skill 20000014",
+		"update 1 skill 20000014 level 1",
+	}, calls)
+
+	result, err = processor.GetById(transactionId)
+	require.NoError(t, err)
+	require.NotNil(t, result.Receipt)
+	assert.True(t, result.Receipt.RolledBack)
+	assert.Equal(t, []ReceiptEntry{
+		{StepId: "link_toggle_1", Action: UpdateSkill, Outcome: ReceiptReverted},
+		{StepId: "link_toggle_2", Action: CreateSkill, Outcome: ReceiptReverted},
+		{StepId: "link_toggle_3", Action: CreateSkill, Outcome: ReceiptNotApplied},
+	}, result.Receipt.Steps)
+
+	t.Run("characters toggled more than once are rejected", func(t *testing.T) {
+		h := NewHandler(logrus.New(), ctx)
+		err := h.handleToggleCharacterAbility(saga, Step[any]{StepId: "link", Action: ToggleCharacterAbility, Payload: ToggleCharacterAbilityPayload{
+			AccountId: 1000,
+			SkillId:   20000014,
+			Level:     1,
+			Toggles:   []AbilityToggle{{CharacterId: 1, Enabled: true}, {CharacterId: 1, Enabled: false}},
+		}})
+		assert.ErrorIs(t, err, ErrActionRejected)
+	})
+}
+
+// TestPlanEquipmentPreset tests that presets which cannot be applied are rejected
+func TestPlanEquipmentPreset(t *testing.T) {
+	tests := []struct {
+		name          string
+		slots         map[int16]uint32
+		items         []EquippedItem
+		errorContains string
+	}{
+		{
+			name:          "Item not held",
+			slots:         map[int16]uint32{-11: 1302000},
+			items:         []EquippedItem{{TemplateId: 1302007, Slot: -11}},
+			errorContains: "item [1302007] is not held",
+		},
+		{
+			name:          "Insufficient inventory space",
+			slots:         map[int16]uint32{-11: 1302000, 1: 1302007},
+			items:         []EquippedItem{{TemplateId: 1302007, Slot: -11}},
+			errorContains: "insufficient inventory space",
+		},
+		{
+			name:          "Slot not equipped",
+			slots:         map[int16]uint32{1: 1302007},
+			items:         []EquippedItem{{TemplateId: 1302007, Slot: 1}},
+			errorContains: "not an equipped slot",
+		},
+		{
+			name:          "Slot assigned twice",
+			slots:         map[int16]uint32{1: 1302007, 2: 1302007},
+			items:         []EquippedItem{{TemplateId: 1302007, Slot: -11}, {TemplateId: 1302007, Slot: -11}},
+			errorContains: "assigned more than once",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, _, err := planEquipmentPreset(equipmentCompartment(1, tt.slots), 12345, tt.items)
+			assert.ErrorIs(t, err, ErrActionRejected)
+			assert.Contains(t, err.Error(), tt.errorContains)
+		})
+	}
+}
+
+// TestGetByLabels tests that sagas are queried by every requested label, and are no longer found once removed
+func TestGetByLabels(t *testing.T) {
+	te, ctx := setupContext()
+	processor, _ := setupTestProcessor(ctx, nil, nil)
+
+	labelled := func(labels map[string]string) Saga {
+		return Saga{
+			TransactionId: uuid.New(),
+			SagaType:      QuestReward,
+			InitiatedBy:   "test",
+			Labels:        labels,
+			Steps: []Step[any]{
+				{StepId: "step-1", Status: Pending, Action: AwardMesos, Payload: AwardMesosPayload{CharacterId: 1, Amount: 10}},
+			},
+		}
+	}
+	halloweenV1 := labelled(map[string]string{"event": "halloween2025", "script": "v1"})
+	halloweenV2 := labelled(map[string]string{"event": "halloween2025", "script": "v2"})
+	unlabelled := labelled(nil)
+	for _, s := range []Saga{halloweenV1, halloweenV2, unlabelled} {
+		GetCache().Put(te.Id(), s)
+		defer GetCache().Remove(te.Id(), s.TransactionId)
+	}
+
+	ids := func(sagas []Saga) []uuid.UUID {
+		results := make([]uuid.UUID, 0, len(sagas))
+		for _, s := range sagas {
+			results = append(results, s.TransactionId)
+		}
+		return results
+	}
+
+	results, err := processor.GetByLabels(map[string]string{"event": "halloween2025"})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []uuid.UUID{halloweenV1.TransactionId, halloweenV2.TransactionId}, ids(results))
+
+	results, err = processor.GetByLabels(map[string]string{"event": "halloween2025", "script": "v2"})
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{halloweenV2.TransactionId}, ids(results))
+
+	results, err = processor.GetByLabels(map[string]string{"event": "christmas2025"})
+	require.NoError(t, err)
+	assert.Empty(t, results)
+
+	GetCache().Remove(te.Id(), halloweenV1.TransactionId)
+	results, err = processor.GetByLabels(map[string]string{"event": "halloween2025"})
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{halloweenV2.TransactionId}, ids(results))
+
+	// Sagas with malformed labels are rejected
+	err = processor.Put(labelled(map[string]string{"event:halloween2025": ""}))
+	assert.Error(t, err)
+}
+
+// TestParseLabel tests parsing labels expressed as key:value
+func TestParseLabel(t *testing.T) {
+	key, value, err := ParseLabel("event:halloween2025")
+	require.NoError(t, err)
+	assert.Equal(t, "event", key)
+	assert.Equal(t, "halloween2025", value)
+
+	_, _, err = ParseLabel("halloween2025")
+	assert.Error(t, err)
+	_, _, err = ParseLabel(":halloween2025")
+	assert.Error(t, err)
+}
+
+// TestRequiresApproval tests that sagas requiring approval do not start until approved by a second operator
+func TestRequiresApproval(t *testing.T) {
+	te, ctx := setupContext()
+
+	awarded := 0
+	charP := &mock.ProcessorMock{
+		AwardMesosAndEmitFunc: func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, actorId uint32, actorType string, amount int32) error {
+			awarded++
+			return nil
+		},
+	}
+	processor, _ := setupTestProcessor(ctx, charP, nil)
+
+	s := NewBuilder().
+		SetSagaType(InventoryTransaction).
+		SetInitiatedBy("gm-alice").
+		SetRequiresApproval().
+		AddStep("restore-mesos", Pending, AwardMesos, AwardMesosPayload{CharacterId: 12345, ActorType: "SYSTEM", Amount: 100000}).
+		Build()
+	require.NoError(t, processor.Put(s))
+	defer GetCache().Remove(te.Id(), s.TransactionId)
+
+	hs, err := processor.GetById(s.TransactionId)
+	require.NoError(t, err)
+	assert.Equal(t, PendingApproval, hs.Hold)
+	assert.Equal(t, 0, awarded)
+
+	// The initiator cannot approve their own saga
+	assert.ErrorIs(t, processor.Review(s.TransactionId, true, "gm-alice", ""), ErrSelfApproval)
+	assert.Equal(t, 0, awarded)
+
+	require.NoError(t, processor.Review(s.TransactionId, true, "gm-bob", "ticket verified"))
+	assert.Equal(t, 1, awarded)
+
+	as, err := processor.GetById(s.TransactionId)
+	require.NoError(t, err)
+	assert.False(t, as.Held())
+	require.Len(t, as.Reviews, 1)
+	assert.Equal(t, PendingApproval, as.Reviews[0].Hold)
+	assert.Empty(t, as.Reviews[0].StepId)
+	assert.Equal(t, "gm-bob", as.Reviews[0].Reviewer)
+	assert.Equal(t, "ticket verified", as.Reviews[0].Comment)
+}
+
+// TestAddNote tests that operators' notes are recorded on a saga in order, without affecting its execution
+func TestAddNote(t *testing.T) {
+	te, ctx := setupContext()
+	processor, _ := setupTestProcessor(ctx, &mock.ProcessorMock{}, nil)
+
+	s := NewBuilder().
+		SetSagaType(InventoryTransaction).
+		SetInitiatedBy("gm-alice").
+		SetRequiresApproval().
+		AddStep("restore-mesos", Pending, AwardMesos, AwardMesosPayload{CharacterId: 12345, ActorType: "SYSTEM", Amount: 100000}).
+		Build()
+	require.NoError(t, processor.Put(s))
+	defer GetCache().Remove(te.Id(), s.TransactionId)
+
+	_, err := processor.AddNote(s.TransactionId, "gm-alice", "awaiting ticket verification")
+	require.NoError(t, err)
+	n, err := processor.AddNote(s.TransactionId, "gm-bob", "ticket verified, approving shortly")
+	require.NoError(t, err)
+	assert.Equal(t, "gm-bob", n.Author)
+	assert.False(t, n.CreatedAt.IsZero())
+
+	ns, err := processor.GetById(s.TransactionId)
+	require.NoError(t, err)
+	require.Len(t, ns.Notes, 2)
+	assert.Equal(t, "gm-alice", ns.Notes[0].Author)
+	assert.Equal(t, "awaiting ticket verification", ns.Notes[0].Comment)
+	assert.Equal(t, "ticket verified, approving shortly", ns.Notes[1].Comment)
+	assert.Equal(t, PendingApproval, ns.Hold)
+	assert.Equal(t, Pending, ns.Steps[0].Status)
+
+	_, err = processor.AddNote(uuid.New(), "gm-alice", "unknown")
+	assert.Error(t, err)
+}
+
+// TestResolve tests that a saga held pending the resolution of a dispute continues with the branch the operator's
+// resolution selects, and cannot be approved or rejected instead
+func TestResolve(t *testing.T) {
+	te, ctx := setupContext()
+
+	reserved := make([]int16, 0)
+	consumed := make([]int16, 0)
+	compP := &mock2.ProcessorMock{
+		RequestReserveAssetFunc: func(transactionId uuid.UUID, characterId uint32, inventoryType byte, slot int16, templateId uint32, quantity uint32) error {
+			reserved = append(reserved, slot)
+			return nil
+		},
+		RequestConsumeAssetFunc: func(transactionId uuid.UUID, characterId uint32, inventoryType byte, slot int16) error {
+			consumed = append(consumed, slot)
+			return nil
+		},
+	}
+	processor, _ := setupTestProcessor(ctx, &mock.ProcessorMock{}, compP)
+
+	when := "$.steps.resolve.resolution"
+	b := NewBuilder().
+		SetSagaType(DisputeHold).
+		SetInitiatedBy("gm-alice").
+		AddStep("seal", Pending, SealAsset, SealAssetPayload{CharacterId: 12345, InventoryType: 1, Slot: 3, TemplateId: 1302000, Quantity: 1}).
+		AddStep("resolve", Pending, ResolveDispute, ResolveDisputePayload{TicketId: "CS-2048"})
+	b.AddBranch(Branch{Name: "release", When: when, Equals: "release", Steps: []Step[any]{{StepId: "unseal", Action: UnsealAsset, Payload: UnsealAssetPayload{CharacterId: 12345, InventoryType: 1, Slot: 3, TemplateId: 1302000, Quantity: 1}}}})
+	b.AddBranch(Branch{Name: "confiscate", When: when, Equals: "confiscate", Steps: []Step[any]{{StepId: "confiscate", Action: ConfiscateAsset, Payload: ConfiscateAssetPayload{CharacterId: 12345, InventoryType: 1, Slot: 3}}}})
+	s := b.Build()
+	require.NoError(t, processor.Put(s))
+	defer GetCache().Remove(te.Id(), s.TransactionId)
+	assert.Equal(t, []int16{3}, reserved)
+
+	// Once sealed, the saga is held pending resolution
+	require.NoError(t, processor.StepCompleted(s.TransactionId, true))
+	hs, err := processor.GetById(s.TransactionId)
+	require.NoError(t, err)
+	assert.Equal(t, PendingResolution, hs.Hold)
+	assert.Contains(t, hs.HoldReason, "CS-2048")
+
+	// Disputes end only in one of their resolutions
+	assert.ErrorIs(t, processor.Review(s.TransactionId, true, "gm-bob", ""), ErrResolutionRequired)
+	assert.ErrorIs(t, processor.Review(s.TransactionId, false, "gm-bob", ""), ErrResolutionRequired)
+	assert.ErrorIs(t, processor.Resolve(s.TransactionId, "forgive", "gm-bob", ""), ErrInvalidResolution)
+	assert.ErrorIs(t, processor.Resolve(s.TransactionId, "", "gm-bob", ""), ErrInvalidResolution)
+
+	require.NoError(t, processor.Resolve(s.TransactionId, "confiscate", "gm-bob", "duplicated item confirmed"))
+	assert.Equal(t, []int16{3}, consumed)
+
+	rs, err := processor.GetById(s.TransactionId)
+	require.NoError(t, err)
+	assert.False(t, rs.Held())
+	require.Len(t, rs.Steps, 3)
+	assert.Equal(t, "confiscate", rs.Steps[1].Branch)
+	assert.Equal(t, ResolveDisputePayload{TicketId: "CS-2048", Resolution: "confiscate", ResolvedBy: "gm-bob"}, rs.Steps[1].Payload)
+	assert.Equal(t, ConfiscateAsset, rs.Steps[2].Action)
+	require.Len(t, rs.Reviews, 1)
+	assert.Equal(t, PendingResolution, rs.Reviews[0].Hold)
+	assert.Equal(t, "resolve", rs.Reviews[0].StepId)
+	assert.Equal(t, "confiscate", rs.Reviews[0].Resolution)
+	assert.Equal(t, "duplicated item confirmed", rs.Reviews[0].Comment)
+
+	// Once resolved, the saga is no longer held
+	assert.ErrorIs(t, processor.Resolve(s.TransactionId, "release", "gm-bob", ""), ErrSagaNotHeld)
+}
+
+// TestStepSagaHeaders tests that commands produced while dispatching a step carry its saga metadata
+func TestStepSagaHeaders(t *testing.T) {
+	te, ctx := setupContext()
+	ctx = header.WithCarrier(ctx)
+
+	var dispatched header.Saga
+	charP := &mock.ProcessorMock{
+		AwardMesosAndEmitFunc: func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, actorId uint32, actorType string, amount int32) error {
+			dispatched, _ = header.FromContext(ctx)
+			return nil
+		},
+	}
+	processor, _ := setupTestProcessor(ctx, charP, nil)
+
+	s := Saga{
+		TransactionId: uuid.New(),
+		SagaType:      QuestReward,
+		InitiatedBy:   "npc-9010000",
+		Steps:         []Step[any]{{StepId: "award", Status: Pending, Action: AwardMesos, Payload: AwardMesosPayload{CharacterId: 12345, ActorType: "NPC", Amount: 100}}},
+	}
+	require.NoError(t, processor.Put(s))
+	defer GetCache().Remove(te.Id(), s.TransactionId)
+
+	assert.Equal(t, header.Saga{TransactionId: s.TransactionId, SagaType: string(QuestReward), StepId: "award"}, dispatched)
+
+	// The metadata only applies while the step is dispatched
+	_, ok := header.FromContext(ctx)
+	assert.False(t, ok)
+}
+
+// TestAtomicUpdateSaga tests that updates of a saga made concurrently are each applied, rather than overwriting one
+// another
+func TestAtomicUpdateSaga(t *testing.T) {
+	te, ctx := setupContext()
+	processor, _ := setupTestProcessor(ctx, nil, nil)
+
+	s := NewBuilder().
+		SetSagaType(QuestReward).
+		AddStep("mesos", Pending, AwardMesos, AwardMesosPayload{CharacterId: 12345, ActorType: "NPC", Amount: 1000}).
+		Build()
+	GetCache().Put(te.Id(), s)
+	defer GetCache().Remove(te.Id(), s.TransactionId)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			assert.NoError(t, processor.AtomicUpdateSaga(s.TransactionId, func(s *Saga) error {
+				vars := s.Variables.With(fmt.Sprintf("v%d", i), i)
+				// Yield between reading the saga and writing it, as a handler awaiting a downstream service would
+				runtime.Gosched()
+				s.Variables = vars
+				return nil
+			}))
+		}(i)
+	}
+	wg.Wait()
+
+	cs, err := processor.GetById(s.TransactionId)
+	require.NoError(t, err)
+	assert.Len(t, cs.Variables, 50)
+
+	// A failed update leaves the saga as it was
+	assert.Error(t, processor.AtomicUpdateSaga(s.TransactionId, func(s *Saga) error {
+		s.Steps[0].Status = Completed
+		return errors.New("update failed")
+	}))
+	cs, err = processor.GetById(s.TransactionId)
+	require.NoError(t, err)
+	assert.Equal(t, Pending, cs.Steps[0].Status)
+}
@@ -8,12 +8,69 @@ import (
 	"github.com/segmentio/kafka-go"
 )
 
-func CompletedStatusEventProvider(transactionId uuid.UUID) model.Provider[[]kafka.Message] {
+func CompletedStatusEventProvider(transactionId uuid.UUID, childTransactionId *uuid.UUID) model.Provider[[]kafka.Message] {
 	key := producer.CreateKey(int(transactionId.ID()))
 	value := &saga.StatusEvent[saga.StatusEventCompletedBody]{
 		TransactionId: transactionId,
 		Type:          saga.StatusEventTypeCompleted,
-		Body:          saga.StatusEventCompletedBody{},
+		Body:          saga.StatusEventCompletedBody{ChildTransactionId: childTransactionId},
 	}
 	return producer.SingleMessageProvider(key, value)
 }
+
+func CompensatedStatusEventProvider(transactionId uuid.UUID, r CompensationReceipt) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(transactionId.ID()))
+	value := compensatedStatusEvent(transactionId, r)
+	return producer.SingleMessageProvider(key, &value)
+}
+
+// compensatedStatusEvent creates the COMPENSATED status event of the saga, bearing its compensation receipt
+func compensatedStatusEvent(transactionId uuid.UUID, r CompensationReceipt) saga.StatusEvent[saga.StatusEventCompensatedBody] {
+	steps := make([]saga.CompensatedStepOutcome, 0, len(r.Steps))
+	for _, e := range r.Steps {
+		steps = append(steps, saga.CompensatedStepOutcome{StepId: e.StepId, Action: string(e.Action), Outcome: string(e.Outcome), Reason: e.Reason})
+	}
+	return saga.StatusEvent[saga.StatusEventCompensatedBody]{
+		TransactionId: transactionId,
+		Type:          saga.StatusEventTypeCompensated,
+		Body: saga.StatusEventCompensatedBody{
+			FailedStepId: r.FailedStepId,
+			ErrorCode:    r.ErrorCode,
+			RolledBack:   r.RolledBack,
+			Steps:        steps,
+			IssuedAt:     r.IssuedAt,
+		},
+	}
+}
+
+func DeadLetterProvider(transactionId uuid.UUID, tenantId uuid.UUID, reason string, event any, errorCode string) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(transactionId.ID()))
+	value := &saga.DeadLetter{
+		TransactionId: transactionId,
+		TenantId:      tenantId,
+		Reason:        reason,
+		Event:         event,
+		ErrorCode:     errorCode,
+	}
+	return producer.SingleMessageProvider(key, value)
+}
+
+// ProjectionEventProvider provides the projection event, keyed by its transaction, so the events of a saga are received
+// in order
+func ProjectionEventProvider(e saga.ProjectionEvent) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(e.TransactionId.ID()))
+	return producer.SingleMessageProvider(key, &e)
+}
+
+// StepDueCommandProvider provides the command having the replica owning the saga act on its step found due
+func StepDueCommandProvider(transactionId uuid.UUID, stepId string, dueType string) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(transactionId.ID()))
+	value := &saga.StepDueCommand{TransactionId: transactionId, StepId: stepId, Type: dueType}
+	return producer.SingleMessageProvider(key, value)
+}
+
+// CommandProvider provides the saga as a command, as received by the replica owning it
+func CommandProvider(s Saga) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(s.TransactionId.ID()))
+	return producer.SingleMessageProvider(key, s)
+}
@@ -0,0 +1,144 @@
+package saga
+
+import (
+	"atlas-saga-orchestrator/kafka/message/saga"
+	"atlas-saga-orchestrator/kafka/producer"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// ProjectionConfig configures the projection of sagas' state to read models
+type ProjectionConfig struct {
+	Enabled bool // Emit a projection event each time a saga starts, a step of it completes or fails, or it finishes
+}
+
+// ProjectionConfigFromEnv loads the projection configuration from the environment
+func ProjectionConfigFromEnv() (ProjectionConfig, error) {
+	v, ok := os.LookupEnv("SAGA_PROJECTION_ENABLED")
+	if !ok || v == "" {
+		return ProjectionConfig{}, nil
+	}
+	enabled, err := strconv.ParseBool(v)
+	if err != nil {
+		return ProjectionConfig{}, fmt.Errorf("invalid SAGA_PROJECTION_ENABLED '%s'", v)
+	}
+	return ProjectionConfig{Enabled: enabled}, nil
+}
+
+// Singleton projection configuration, which projects nothing until initialized
+var projectionConfig ProjectionConfig
+
+// InitProjectionConfig replaces the singleton projection configuration
+func InitProjectionConfig(config ProjectionConfig) {
+	projectionConfig = config
+}
+
+// GetProjectionConfig returns the singleton projection configuration
+func GetProjectionConfig() ProjectionConfig {
+	return projectionConfig
+}
+
+// projectedStatus returns the status of the saga as projected: compensated once its receipt is issued, completed once
+// no steps remain, otherwise active, including while its failure is compensated
+func projectedStatus(s Saga) string {
+	if s.Receipt != nil {
+		return saga.ProjectionStatusCompensated
+	}
+	if !s.active() {
+		return saga.ProjectionStatusCompleted
+	}
+	return saga.ProjectionStatusActive
+}
+
+// NewProjectionEvent creates the projection event of the saga of the tenant, as of a change of the type. The step which
+// changed is given for step events.
+func NewProjectionEvent(tenantId uuid.UUID, s Saga, eventType string, stepId string, occurredAt time.Time) saga.ProjectionEvent {
+	e := saga.ProjectionEvent{
+		TransactionId: s.TransactionId,
+		TenantId:      tenantId,
+		Type:          eventType,
+		SagaType:      string(s.SagaType),
+		InitiatedBy:   s.InitiatedBy,
+		Labels:        s.Labels,
+		Status:        projectedStatus(s),
+		CharacterIds:  make([]uint32, 0),
+		StepId:        stepId,
+		Steps:         make([]saga.ProjectedStep, 0, len(s.Steps)),
+		OccurredAt:    occurredAt,
+	}
+	if s.Receipt != nil {
+		rolledBack := s.Receipt.RolledBack
+		e.RolledBack = &rolledBack
+		e.ErrorCode = s.Receipt.ErrorCode
+	}
+
+	// Characters are listed in the order the saga first affects them, so a read model may index its history by each
+	seen := make(map[uint32]struct{})
+	for _, st := range s.Steps {
+		characterId, _ := stepCharacterId(st)
+		if _, ok := seen[characterId]; characterId != 0 && !ok {
+			seen[characterId] = struct{}{}
+			e.CharacterIds = append(e.CharacterIds, characterId)
+		}
+		if st.StepId == stepId && s.Receipt == nil {
+			e.ErrorCode = st.ErrorCode()
+		}
+		e.Steps = append(e.Steps, saga.ProjectedStep{
+			StepId:      st.StepId,
+			Action:      string(st.Action),
+			Status:      string(st.Status),
+			CharacterId: characterId,
+			UpdatedAt:   st.UpdatedAt,
+		})
+	}
+	return e
+}
+
+// projectStarted projects the saga, as it was started
+func (p *ProcessorImpl) projectStarted(s Saga) {
+	p.project(s, saga.ProjectionEventTypeStarted, "")
+}
+
+// projectStep projects the saga, as the step completed or failed
+func (p *ProcessorImpl) projectStep(s Saga, stepId string, status Status) {
+	switch status {
+	case Completed:
+		p.project(s, saga.ProjectionEventTypeStepCompleted, stepId)
+	case Failed:
+		p.project(s, saga.ProjectionEventTypeStepFailed, stepId)
+	}
+}
+
+// projectFinished projects the saga, as it completed, or once compensated, as its receipt was issued for the failed step
+func (p *ProcessorImpl) projectFinished(s Saga, failedStepId string) {
+	if s.Receipt != nil {
+		p.project(s, saga.ProjectionEventTypeCompensated, failedStepId)
+		return
+	}
+	p.project(s, saga.ProjectionEventTypeCompleted, "")
+}
+
+// project emits the projection event of the saga, as of a change of the type, when projection is enabled. Read models
+// are eventually consistent with the orchestrator, so a projection event which cannot be emitted is logged rather than
+// failing the change.
+func (p *ProcessorImpl) project(s Saga, eventType string, stepId string) {
+	if !GetProjectionConfig().Enabled {
+		return
+	}
+	restore := p.setSagaHeaders(s, stepId)
+	defer restore()
+	e := NewProjectionEvent(p.t.Id(), s, eventType, stepId, time.Now())
+	if err := producer.ProviderImpl(p.l)(p.ctx)(saga.EnvProjectionEventTopic)(ProjectionEventProvider(e)); err != nil {
+		p.l.WithError(err).WithFields(logrus.Fields{
+			"transaction_id": s.TransactionId.String(),
+			"saga_type":      s.SagaType,
+			"step_id":        stepId,
+			"tenant_id":      p.t.Id().String(),
+		}).Errorf("Unable to emit [%s] projection event.", eventType)
+	}
+}
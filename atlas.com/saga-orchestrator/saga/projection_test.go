@@ -0,0 +1,145 @@
+package saga
+
+import (
+	"atlas-saga-orchestrator/character/mock"
+	"atlas-saga-orchestrator/kafka/message/saga"
+	"atlas-saga-orchestrator/kafka/producer"
+	"encoding/json"
+	"testing"
+
+	"github.com/Chronicle20/atlas-constants/channel"
+	"github.com/Chronicle20/atlas-constants/world"
+	producer2 "github.com/Chronicle20/atlas-kafka/producer"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestProjectionConfigFromEnv tests loading the projection configuration from the environment
+func TestProjectionConfigFromEnv(t *testing.T) {
+	c, err := ProjectionConfigFromEnv()
+	require.NoError(t, err)
+	assert.False(t, c.Enabled)
+
+	t.Setenv("SAGA_PROJECTION_ENABLED", "true")
+	c, err = ProjectionConfigFromEnv()
+	require.NoError(t, err)
+	assert.True(t, c.Enabled)
+
+	t.Setenv("SAGA_PROJECTION_ENABLED", "sometimes")
+	_, err = ProjectionConfigFromEnv()
+	assert.Error(t, err)
+}
+
+// TestProjection tests that a projection event carrying the whole state of the saga is emitted as it starts, as each
+// step completes or fails, and as it completes or is compensated
+func TestProjection(t *testing.T) {
+	InitProjectionConfig(ProjectionConfig{Enabled: true})
+	defer InitProjectionConfig(ProjectionConfig{})
+
+	te, ctx := setupContext()
+	events := make([]saga.ProjectionEvent, 0)
+	ctx = producer.WithProvider(ctx, func(token string) producer2.MessageProducer {
+		return func(provider model.Provider[[]kafka.Message]) error {
+			ms, err := provider()
+			if err != nil {
+				return err
+			}
+			for _, m := range ms {
+				var e saga.ProjectionEvent
+				if token == saga.EnvProjectionEventTopic && json.Unmarshal(m.Value, &e) == nil {
+					events = append(events, e)
+				}
+			}
+			return nil
+		}
+	})
+	charP := &mock.ProcessorMock{
+		AwardMesosAndEmitFunc: func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, actorId uint32, actorType string, amount int32) error {
+			return nil
+		},
+	}
+	processor, _ := setupTestProcessor(ctx, charP, nil)
+
+	types := func() []string {
+		r := make([]string, 0, len(events))
+		for _, e := range events {
+			r = append(r, e.Type)
+		}
+		return r
+	}
+
+	t.Run("completed sagas", func(t *testing.T) {
+		events = events[:0]
+		s := NewBuilder().
+			SetSagaType(QuestReward).
+			SetInitiatedBy("quest-service").
+			AddStep("mesos", Pending, AwardMesos, AwardMesosPayload{CharacterId: 12345, ActorType: "NPC", Amount: 1000}).
+			Build()
+		require.NoError(t, processor.Put(s))
+		defer GetCache().Remove(te.Id(), s.TransactionId)
+		require.NoError(t, processor.StepCompleted(s.TransactionId, true))
+
+		assert.Equal(t, []string{saga.ProjectionEventTypeStarted, saga.ProjectionEventTypeStepCompleted, saga.ProjectionEventTypeCompleted}, types())
+		assert.Equal(t, saga.ProjectionStatusActive, events[0].Status)
+		assert.Equal(t, "mesos", events[1].StepId)
+
+		e := events[2]
+		assert.Equal(t, s.TransactionId, e.TransactionId)
+		assert.Equal(t, te.Id(), e.TenantId)
+		assert.Equal(t, string(QuestReward), e.SagaType)
+		assert.Equal(t, "quest-service", e.InitiatedBy)
+		assert.Equal(t, saga.ProjectionStatusCompleted, e.Status)
+		assert.Equal(t, []uint32{12345}, e.CharacterIds)
+		require.Len(t, e.Steps, 1)
+		assert.Equal(t, "mesos", e.Steps[0].StepId)
+		assert.Equal(t, string(AwardMesos), e.Steps[0].Action)
+		assert.Equal(t, string(Completed), e.Steps[0].Status)
+		assert.Equal(t, uint32(12345), e.Steps[0].CharacterId)
+		assert.Nil(t, e.RolledBack)
+	})
+
+	t.Run("compensated sagas", func(t *testing.T) {
+		events = events[:0]
+		s := NewBuilder().
+			SetSagaType(QuestReward).
+			AddStep("mesos", Pending, AwardMesos, AwardMesosPayload{CharacterId: 12345, ActorType: "NPC", Amount: 1000}).
+			AddStep("bonus", Pending, AwardMesos, AwardMesosPayload{CharacterId: 67890, ActorType: "NPC", Amount: 500}).
+			Build()
+		require.NoError(t, processor.Put(s))
+		defer GetCache().Remove(te.Id(), s.TransactionId)
+		require.NoError(t, processor.StepCompleted(s.TransactionId, true))
+		require.NoError(t, processor.StepFailed(s.TransactionId, "NOT_ENOUGH_MESO", "not enough mesos"))
+
+		assert.Equal(t, []string{saga.ProjectionEventTypeStarted, saga.ProjectionEventTypeStepCompleted, saga.ProjectionEventTypeStepFailed, saga.ProjectionEventTypeCompensated}, types())
+
+		failed := events[2]
+		assert.Equal(t, "bonus", failed.StepId)
+		assert.Equal(t, "NOT_ENOUGH_MESO", failed.ErrorCode)
+		assert.Equal(t, saga.ProjectionStatusActive, failed.Status)
+		assert.Equal(t, string(Failed), failed.Steps[1].Status)
+
+		e := events[3]
+		assert.Equal(t, saga.ProjectionStatusCompensated, e.Status)
+		assert.Equal(t, "bonus", e.StepId)
+		assert.Equal(t, "NOT_ENOUGH_MESO", e.ErrorCode)
+		assert.Equal(t, []uint32{12345, 67890}, e.CharacterIds)
+		require.NotNil(t, e.RolledBack)
+		assert.False(t, *e.RolledBack)
+	})
+
+	t.Run("nothing is projected unless enabled", func(t *testing.T) {
+		InitProjectionConfig(ProjectionConfig{})
+		events = events[:0]
+		s := NewBuilder().
+			SetSagaType(QuestReward).
+			AddStep("mesos", Pending, AwardMesos, AwardMesosPayload{CharacterId: 12345, ActorType: "NPC", Amount: 1000}).
+			Build()
+		require.NoError(t, processor.Put(s))
+		defer GetCache().Remove(te.Id(), s.TransactionId)
+		require.NoError(t, processor.StepCompleted(s.TransactionId, true))
+		assert.Empty(t, events)
+	})
+}
@@ -0,0 +1,62 @@
+package saga
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrStepPanicked is returned when a saga is quarantined, as progressing it panicked
+var ErrStepPanicked = errors.New("saga step panicked")
+
+// QuarantineAuthor is the author of the note recording the panic a saga was quarantined for
+const QuarantineAuthor = "orchestrator"
+
+// Quarantine quarantines the saga of the tenant of the context, as processing an event of it panicked. It is the
+// recovery of consumers isolating panics.
+func Quarantine(l logrus.FieldLogger, ctx context.Context, transactionId uuid.UUID, recovered any, stack []byte) {
+	_ = NewProcessor(l, ctx).Quarantine(transactionId, recovered, stack)
+}
+
+// isolate recovers a panic progressing the saga, quarantining it rather than letting the panic unwind the consumer or
+// timer progressing it. Must be deferred directly.
+func (p *ProcessorImpl) isolate(transactionId uuid.UUID, err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	*err = p.Quarantine(transactionId, r, debug.Stack())
+}
+
+// Quarantine holds the saga for manual intervention, as progressing it panicked, recording the panic and its stack as a
+// note on the saga. An operator approving the saga retries its current step, while rejecting it fails the step,
+// compensating the saga. Returns ErrStepPanicked once quarantined.
+func (p *ProcessorImpl) Quarantine(transactionId uuid.UUID, recovered any, stack []byte) error {
+	fl := p.l.WithFields(logrus.Fields{
+		"transaction_id": transactionId.String(),
+		"tenant_id":      p.t.Id().String(),
+	})
+
+	s, err := p.GetById(transactionId)
+	if err != nil {
+		fl.WithError(err).Errorf("Recovered from panic processing saga which could not be quarantined: %v\n%s", recovered, stack)
+		return err
+	}
+
+	reason := fmt.Sprintf("panicked: %v", recovered)
+	if st, ok := s.GetCurrentStep(); ok {
+		reason = fmt.Sprintf("step [%s] panicked: %v", st.StepId, recovered)
+	}
+	s.Hold = ManualIntervention
+	s.HoldReason = reason
+	s.Notes = append(append([]Note{}, s.Notes...), Note{Author: QuarantineAuthor, Comment: fmt.Sprintf("%s\n\n%s", reason, stack), CreatedAt: time.Now()})
+	GetCache().Put(p.t.Id(), s)
+
+	fl.WithField("saga_type", s.SagaType).Errorf("Recovered from panic processing saga. Holding saga for manual intervention: %s\n%s", reason, stack)
+	return fmt.Errorf("%w: %v", ErrStepPanicked, recovered)
+}
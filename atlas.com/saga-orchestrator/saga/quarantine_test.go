@@ -0,0 +1,62 @@
+package saga
+
+import (
+	"atlas-saga-orchestrator/character/mock"
+	"testing"
+
+	"github.com/Chronicle20/atlas-constants/channel"
+	"github.com/Chronicle20/atlas-constants/world"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestQuarantine tests that a step whose handler panics holds its saga for manual intervention, recording the panic,
+// and that approving the saga retries the step
+func TestQuarantine(t *testing.T) {
+	te, ctx := setupContext()
+	dispatched := 0
+	charP := &mock.ProcessorMock{
+		AwardMesosAndEmitFunc: func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, actorId uint32, actorType string, amount int32) error {
+			dispatched++
+			if dispatched == 1 {
+				var m map[string]int
+				m["mesos"] = int(amount)
+			}
+			return nil
+		},
+	}
+	processor, _ := setupTestProcessor(ctx, charP, nil)
+
+	s := NewBuilder().
+		SetSagaType(QuestReward).
+		AddStep("mesos", Pending, AwardMesos, AwardMesosPayload{CharacterId: 12345, ActorType: "SYSTEM", Amount: 1000}).
+		Build()
+	defer GetCache().Remove(te.Id(), s.TransactionId)
+	GetCache().Put(te.Id(), s)
+
+	err := processor.Step(s.TransactionId)
+	assert.ErrorIs(t, err, ErrStepPanicked)
+
+	cs, err := processor.GetById(s.TransactionId)
+	require.NoError(t, err)
+	assert.Equal(t, ManualIntervention, cs.Hold)
+	assert.Contains(t, cs.HoldReason, "step [mesos] panicked: assignment to entry in nil map")
+	require.Len(t, cs.Notes, 1)
+	assert.Equal(t, QuarantineAuthor, cs.Notes[0].Author)
+	assert.Contains(t, cs.Notes[0].Comment, "quarantine_test.go")
+	assert.Equal(t, Pending, cs.Steps[0].Status)
+
+	// Quarantined sagas are not progressed until reviewed
+	require.NoError(t, processor.Step(s.TransactionId))
+	assert.Equal(t, 1, dispatched)
+
+	require.NoError(t, processor.Review(s.TransactionId, true, "operator", "fixed the mesos handler"))
+	assert.Equal(t, 2, dispatched)
+
+	t.Run("sagas which cannot be found are not quarantined", func(t *testing.T) {
+		err := processor.Quarantine(uuid.New(), "boom", nil)
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, ErrStepPanicked)
+	})
+}
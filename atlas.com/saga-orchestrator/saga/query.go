@@ -0,0 +1,153 @@
+package saga
+
+import (
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jtumidanski/api2go/jsonapi"
+)
+
+// SagaStatus is the status of a saga as a whole, by which sagas are listed
+type SagaStatus string
+
+// Constants for the statuses of a saga
+const (
+	SagaStatusPending     SagaStatus = "pending"     // Steps of the saga remain to be executed
+	SagaStatusHeld        SagaStatus = "held"        // The saga is paused until an operator reviews it, or its character logs in
+	SagaStatusFailing     SagaStatus = "failing"     // A step of the saga failed, and its failure remains to be compensated
+	SagaStatusCompleted   SagaStatus = "completed"   // Every step of the saga completed
+	SagaStatusCompensated SagaStatus = "compensated" // A step of the saga failed, and its failure was compensated
+)
+
+// SagaStatuses are the statuses a saga may have
+var SagaStatuses = []SagaStatus{SagaStatusPending, SagaStatusHeld, SagaStatusFailing, SagaStatusCompleted, SagaStatusCompensated}
+
+// OverallStatus returns the status of the saga as a whole
+func (s Saga) OverallStatus() SagaStatus {
+	if s.Failing() {
+		return SagaStatusFailing
+	}
+	if s.Receipt != nil {
+		return SagaStatusCompensated
+	}
+	if s.Held() {
+		return SagaStatusHeld
+	}
+	if _, ok := s.GetCurrentStep(); ok {
+		return SagaStatusPending
+	}
+	return SagaStatusCompleted
+}
+
+// Page sizes of saga listings
+const (
+	DefaultPageSize = 50  // Sagas listed per page, when not requested
+	MaxPageSize     = 500 // Sagas which may be listed per page
+)
+
+// Query selects the sagas listed, and the page of them returned. Criteria which are not given select every saga.
+type Query struct {
+	Status      SagaStatus        // Status of the sagas as a whole
+	SagaType    Type              // Type of the sagas
+	CharacterId uint32            // Character a step of the sagas acts on
+	Labels      map[string]string // Labels the sagas carry
+	Page        int               // Page of the sagas returned, starting at 1
+	PageSize    int               // Sagas returned per page
+}
+
+// Matches returns whether the saga meets the query's criteria, labels aside, which are selected through the label index
+func (q Query) Matches(s Saga) bool {
+	if q.Status != "" && s.OverallStatus() != q.Status {
+		return false
+	}
+	if q.SagaType != "" && s.SagaType != q.SagaType {
+		return false
+	}
+	if q.CharacterId != 0 {
+		for _, st := range s.Steps {
+			if id, ok := stepCharacterId(st); ok && id == q.CharacterId {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// Page is a page of the sagas a query selected
+type Page struct {
+	Sagas  []Saga // Sagas of the page
+	Number int    // Number of the page, starting at 1
+	Size   int    // Sagas per page
+	Total  int    // Sagas the query selected, across every page
+}
+
+// Pages returns the number of pages of the sagas the query selected, there being at least one
+func (p Page) Pages() int {
+	if p.Total == 0 {
+		return 1
+	}
+	return (p.Total + p.Size - 1) / p.Size
+}
+
+// startedAt returns when the saga started, being when its first step was created
+func startedAt(s Saga) time.Time {
+	if len(s.Steps) == 0 {
+		return time.Time{}
+	}
+	return s.Steps[0].CreatedAt
+}
+
+// paginate orders the sagas the query selected by when they started, then by transaction ID, and returns the page of
+// them requested. A page beyond the last is empty.
+func paginate(q Query, sagas []Saga) Page {
+	sort.Slice(sagas, func(i, j int) bool {
+		si, sj := startedAt(sagas[i]), startedAt(sagas[j])
+		if !si.Equal(sj) {
+			return si.Before(sj)
+		}
+		return sagas[i].TransactionId.String() < sagas[j].TransactionId.String()
+	})
+
+	p := Page{Sagas: []Saga{}, Number: q.Page, Size: q.PageSize, Total: len(sagas)}
+	start := (q.Page - 1) * q.PageSize
+	if start < len(sagas) {
+		end := min(start+q.PageSize, len(sagas))
+		p.Sagas = sagas[start:end]
+	}
+	return p
+}
+
+// Links returns the JSON:API pagination links of the page, relative to the URL it was requested at
+func (p Page) Links(u *url.URL) jsonapi.Links {
+	link := func(number int) jsonapi.Link {
+		v := u.Query()
+		v.Set("page", strconv.Itoa(number))
+		v.Set("pageSize", strconv.Itoa(p.Size))
+		return jsonapi.Link{Href: u.Path + "?" + v.Encode()}
+	}
+	links := jsonapi.Links{
+		"self":  link(p.Number),
+		"first": link(1),
+		"last":  link(p.Pages()),
+	}
+	if p.Number > 1 {
+		links["prev"] = link(min(p.Number-1, p.Pages()))
+	}
+	if p.Number < p.Pages() {
+		links["next"] = link(p.Number + 1)
+	}
+	return links
+}
+
+// Meta returns the JSON:API meta of the page, describing its position among the pages
+func (p Page) Meta() map[string]interface{} {
+	return map[string]interface{}{
+		"page":     p.Number,
+		"pageSize": p.Size,
+		"pages":    p.Pages(),
+		"total":    p.Total,
+	}
+}
@@ -0,0 +1,115 @@
+package saga
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOverallStatus tests the status of sagas as a whole, by which they are listed
+func TestOverallStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		saga   Saga
+		status SagaStatus
+	}{
+		{name: "pending", saga: Saga{Steps: []Step[any]{{StepId: "a", Status: Completed}, {StepId: "b", Status: Pending}}}, status: SagaStatusPending},
+		{name: "held", saga: Saga{Hold: PendingReview, Steps: []Step[any]{{StepId: "a", Status: Pending}}}, status: SagaStatusHeld},
+		{name: "failing", saga: Saga{Steps: []Step[any]{{StepId: "a", Status: Failed}, {StepId: "b", Status: Pending}}}, status: SagaStatusFailing},
+		{name: "completed", saga: Saga{Steps: []Step[any]{{StepId: "a", Status: Completed}}}, status: SagaStatusCompleted},
+		{name: "compensated", saga: Saga{Receipt: &CompensationReceipt{}, Steps: []Step[any]{{StepId: "a", Status: Pending}}}, status: SagaStatusCompensated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.status, tt.saga.OverallStatus())
+		})
+	}
+}
+
+// TestParseQuery tests that saga listings are parsed from their query parameters, rejecting invalid values
+func TestParseQuery(t *testing.T) {
+	q, err := ParseQuery(httptest.NewRequest(http.MethodGet, "/sagas?status=failing&sagaType=inventory_transaction&characterId=123&page=2&label=shop:henesys", nil))
+	require.NoError(t, err)
+	assert.Equal(t, Query{Status: SagaStatusFailing, SagaType: InventoryTransaction, CharacterId: 123, Labels: map[string]string{"shop": "henesys"}, Page: 2, PageSize: DefaultPageSize}, q)
+
+	q, err = ParseQuery(httptest.NewRequest(http.MethodGet, "/sagas", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 1, q.Page)
+	assert.Equal(t, DefaultPageSize, q.PageSize)
+
+	for _, query := range []string{"status=stuck", "characterId=abc", "page=0", "pageSize=0", "pageSize=501"} {
+		_, err = ParseQuery(httptest.NewRequest(http.MethodGet, "/sagas?"+query, nil))
+		assert.Error(t, err, query)
+	}
+}
+
+// TestQuery tests that sagas are filtered by status, type and character, and returned a page at a time in the order
+// they started
+func TestQuery(t *testing.T) {
+	te, ctx := setupContext()
+	processor, _ := setupTestProcessor(ctx, nil, nil)
+
+	started := time.Now().Add(-time.Hour)
+	newSaga := func(sagaType Type, characterId uint32, status Status, offset time.Duration) Saga {
+		s := NewBuilder().
+			SetSagaType(sagaType).
+			AddStep("mesos", status, AwardMesos, AwardMesosPayload{CharacterId: characterId, ActorType: "NPC", Amount: 1000}).
+			Build()
+		s.Steps[0].CreatedAt = started.Add(offset)
+		GetCache().Put(te.Id(), s)
+		return s
+	}
+	first := newSaga(InventoryTransaction, 123, Failed, 0)
+	second := newSaga(InventoryTransaction, 123, Failed, time.Minute)
+	third := newSaga(InventoryTransaction, 123, Failed, 2*time.Minute)
+	otherCharacter := newSaga(InventoryTransaction, 456, Failed, 3*time.Minute)
+	otherType := newSaga(QuestReward, 123, Failed, 4*time.Minute)
+	pending := newSaga(InventoryTransaction, 123, Pending, 5*time.Minute)
+	for _, s := range []Saga{first, second, third, otherCharacter, otherType, pending} {
+		defer GetCache().Remove(te.Id(), s.TransactionId)
+	}
+
+	q := Query{Status: SagaStatusFailing, SagaType: InventoryTransaction, CharacterId: 123, Page: 1, PageSize: 2}
+	page, err := processor.Query(q)
+	require.NoError(t, err)
+	assert.Equal(t, 3, page.Total)
+	assert.Equal(t, 2, page.Pages())
+	require.Len(t, page.Sagas, 2)
+	assert.Equal(t, first.TransactionId, page.Sagas[0].TransactionId)
+	assert.Equal(t, second.TransactionId, page.Sagas[1].TransactionId)
+
+	q.Page = 2
+	page, err = processor.Query(q)
+	require.NoError(t, err)
+	require.Len(t, page.Sagas, 1)
+	assert.Equal(t, third.TransactionId, page.Sagas[0].TransactionId)
+
+	q.Page = 3
+	page, err = processor.Query(q)
+	require.NoError(t, err)
+	assert.Empty(t, page.Sagas)
+	assert.Equal(t, 3, page.Total)
+}
+
+// TestPageLinks tests the JSON:API pagination links of a page, which keep the query they were requested with
+func TestPageLinks(t *testing.T) {
+	u, err := url.Parse("/api/sagas?status=failing&page=2")
+	require.NoError(t, err)
+
+	links := Page{Number: 2, Size: 10, Total: 25}.Links(u)
+	assert.Equal(t, "/api/sagas?page=2&pageSize=10&status=failing", links["self"].Href)
+	assert.Equal(t, "/api/sagas?page=1&pageSize=10&status=failing", links["first"].Href)
+	assert.Equal(t, "/api/sagas?page=1&pageSize=10&status=failing", links["prev"].Href)
+	assert.Equal(t, "/api/sagas?page=3&pageSize=10&status=failing", links["next"].Href)
+	assert.Equal(t, "/api/sagas?page=3&pageSize=10&status=failing", links["last"].Href)
+
+	links = Page{Number: 1, Size: 10, Total: 0}.Links(u)
+	assert.NotContains(t, links, "prev")
+	assert.NotContains(t, links, "next")
+	assert.Equal(t, "/api/sagas?page=1&pageSize=10&status=failing", links["last"].Href)
+}
@@ -0,0 +1,153 @@
+package saga
+
+import (
+	"atlas-saga-orchestrator/kafka/message/saga"
+	"atlas-saga-orchestrator/kafka/producer"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Outcome is what became of the effect of a step once its saga was compensated
+type Outcome string
+
+// Constants for the outcomes of steps in a compensation receipt
+const (
+	ReceiptReverted    Outcome = "reverted"     // The step took effect, and its effect was rolled back
+	ReceiptNotApplied  Outcome = "not_applied"  // The step failed without taking effect, so there was nothing to roll back
+	ReceiptNotReverted Outcome = "not_reverted" // The step took effect, or may have, and its effect remains
+)
+
+// ReceiptEntry records the outcome of a step of a compensated saga
+type ReceiptEntry struct {
+	StepId  string  `json:"stepId"`           // StepId of the step
+	Action  Action  `json:"action"`           // Action of the step
+	Outcome Outcome `json:"outcome"`          // Outcome of the step
+	Reason  string  `json:"reason,omitempty"` // Reason the step was not applied or not reverted, if known
+}
+
+// CompensationReceipt enumerates what was rolled back when a saga was compensated, and what could not be, so initiators
+// can inform players accurately
+type CompensationReceipt struct {
+	FailedStepId string         `json:"failedStepId"`        // StepId of the step whose failure was compensated
+	ErrorCode    string         `json:"errorCode,omitempty"` // Error code reported by the failed step, if any
+	RolledBack   bool           `json:"rolledBack"`          // Whether every effect of the saga was rolled back, or never applied
+	Steps        []ReceiptEntry `json:"steps"`               // Outcome of the failed step, and of each step completed before it
+	IssuedAt     time.Time      `json:"issuedAt"`            // Timestamp of the compensation
+}
+
+// hasEffect reports whether an action's step affects state beyond the saga, so must be accounted for by a receipt.
+// Character snapshots only capture state. Equipment presets and strips, mounts, party experience splits, ability toggles and event currency settlements are accounted for by
+// the steps they add, escorts by the steps spawning them, and inventory audits only report on the steps before them.
+func hasEffect(action Action) bool {
+	switch action {
+	case ValidateCharacterState, SnapshotCharacter, ResolvePrizeTable, ApplyEquipmentPreset, StripEquipment, VerifyAccountMerge, ValidateCoupon, ValidateDivorce, ResolveDispute, AwaitEscort, AwaitEvent, SetVariable, EmitAnalyticsEvent, Notify, ForEach, AuditInventory, GrantMount, SettleEventCurrency, AwardPartyExperience, ToggleCharacterAbility:
+		return false
+	}
+	return true
+}
+
+// NewCompensationReceipt creates the receipt of compensating the failed step of the saga, given the number of commands
+// its compensation produced and the error compensating it, if any. Steps completed before the failed step are not
+// compensated, so remain in effect, other than those added by the same mount grant, party experience split or ability
+// toggle, which are reversed with it, those of a character the saga created, which are removed when the character is
+// deleted, and the progression, stats and meso of a character restored to a snapshot.
+func NewCompensationReceipt(s Saga, produced int, err error) (CompensationReceipt, bool) {
+	idx := s.FindFailedStepIndex()
+	if idx == -1 {
+		return CompensationReceipt{}, false
+	}
+	failed := s.Steps[idx]
+	r := CompensationReceipt{FailedStepId: failed.StepId, RolledBack: true, Steps: make([]ReceiptEntry, 0), IssuedAt: time.Now()}
+	r.ErrorCode = failed.ErrorCode()
+
+	mount, grantsMount := findMountStep(s, failed.StepId)
+	party, splitsParty := findPartyExperienceStep(s, failed.StepId)
+	toggle, togglesAbility := findAbilityToggleStep(s, failed.StepId)
+	created, createdCharacter := findCreatedCharacterStep(s, failed.StepId)
+	for i, st := range s.Steps {
+		if i == idx || st.Status != Completed || !hasEffect(st.Action) {
+			continue
+		}
+		if err == nil && restoredBySnapshot(s, failed, i) {
+			r.Steps = append(r.Steps, ReceiptEntry{StepId: st.StepId, Action: st.Action, Outcome: ReceiptReverted})
+			continue
+		}
+		if createdCharacter && err == nil && (st.StepId == created.StepId || affectsCharacter(st, created.Payload.(CharacterCreatePayload).CharacterId)) {
+			r.Steps = append(r.Steps, ReceiptEntry{StepId: st.StepId, Action: st.Action, Outcome: ReceiptReverted})
+			continue
+		}
+		if m, ok := findMountStep(s, st.StepId); ok && grantsMount && m.StepId == mount.StepId && err == nil {
+			r.Steps = append(r.Steps, ReceiptEntry{StepId: st.StepId, Action: st.Action, Outcome: ReceiptReverted})
+			continue
+		}
+		if p, ok := findPartyExperienceStep(s, st.StepId); ok && splitsParty && p.StepId == party.StepId && err == nil {
+			r.Steps = append(r.Steps, ReceiptEntry{StepId: st.StepId, Action: st.Action, Outcome: ReceiptReverted})
+			continue
+		}
+		if t, ok := findAbilityToggleStep(s, st.StepId); ok && togglesAbility && t.StepId == toggle.StepId && err == nil {
+			r.Steps = append(r.Steps, ReceiptEntry{StepId: st.StepId, Action: st.Action, Outcome: ReceiptReverted})
+			continue
+		}
+		r.Steps = append(r.Steps, ReceiptEntry{StepId: st.StepId, Action: st.Action, Outcome: ReceiptNotReverted, Reason: "completed before the failure"})
+	}
+
+	e := ReceiptEntry{StepId: failed.StepId, Action: failed.Action, Outcome: ReceiptNotApplied, Reason: r.ErrorCode}
+	if err != nil {
+		e.Outcome = ReceiptNotReverted
+		e.Reason = err.Error()
+	} else if produced > 0 || createdCharacter {
+		e.Outcome = ReceiptReverted
+		e.Reason = ""
+	}
+	r.Steps = append(r.Steps, e)
+
+	for _, e := range r.Steps {
+		if e.Outcome == ReceiptNotReverted {
+			r.RolledBack = false
+		}
+	}
+	return r, true
+}
+
+// affectsCharacter returns whether the step acts on the character identified
+func affectsCharacter(st Step[any], characterId uint32) bool {
+	id, ok := stepCharacterId(st)
+	return ok && id == characterId
+}
+
+// issueReceipt records the receipt of compensating the saga on it, delivers it to callers awaiting the saga, and emits it
+// as the saga's COMPENSATED status event
+func (p *ProcessorImpl) issueReceipt(s Saga, produced int, err error) {
+	r, ok := NewCompensationReceipt(s, produced, err)
+	if !ok {
+		return
+	}
+	fl := p.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        r.FailedStepId,
+		"tenant_id":      p.t.Id().String(),
+	})
+
+	// Compensation updates the cached saga, so the receipt is recorded on its latest state
+	if cs, cerr := p.GetById(s.TransactionId); cerr == nil {
+		s = cs
+	}
+	s.Receipt = &r
+	GetCache().Put(p.t.Id(), s)
+	GetNotifier().Notify(p.t.Id(), s)
+	countFinished(p.t.Id(), s, OutcomeCompensated)
+	RecordFinished(p.t.Id(), s, OutcomeCompensated)
+	p.projectFinished(s, r.FailedStepId)
+
+	restore := p.setSagaHeaders(s, "")
+	defer restore()
+	event := CompensatedStatusEventProvider(s.TransactionId, r)
+	p.publishToSubscribers(s, event)
+	if perr := producer.ProviderImpl(p.l)(p.ctx)(saga.EnvStatusEventTopic)(event); perr != nil {
+		fl.WithError(perr).Error("Unable to emit compensation receipt.")
+		return
+	}
+	fl.Debugf("Issued compensation receipt, rolled back [%t].", r.RolledBack)
+}
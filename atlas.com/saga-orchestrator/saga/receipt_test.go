@@ -0,0 +1,151 @@
+package saga
+
+import (
+	"atlas-saga-orchestrator/character/mock"
+	"atlas-saga-orchestrator/kafka/message/saga"
+	"atlas-saga-orchestrator/kafka/producer"
+	"encoding/json"
+	"errors"
+	"github.com/Chronicle20/atlas-constants/channel"
+	"github.com/Chronicle20/atlas-constants/world"
+	producer2 "github.com/Chronicle20/atlas-kafka/producer"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+// TestNewCompensationReceipt tests that the receipt of a compensation accounts for the failed step, and for the steps
+// completed before it which remain in effect
+func TestNewCompensationReceipt(t *testing.T) {
+	build := func(failed Step[any]) Saga {
+		return Saga{
+			TransactionId: uuid.New(),
+			SagaType:      QuestReward,
+			Steps: []Step[any]{
+				{StepId: "check", Status: Completed, Action: ValidateCharacterState},
+				{StepId: "mesos", Status: Completed, Action: AwardMesos},
+				failed,
+				{StepId: "exp", Status: Pending, Action: AwardExperience},
+			},
+		}
+	}
+	failed := Step[any]{StepId: "premium", Status: Failed, Action: GrantPremiumTime}
+	rejected := failed
+	rejected.Attempts = []StepAttempt{{Attempt: 1, DispatchedAt: time.Now(), ErrorCode: "ACCOUNT_NOT_FOUND"}}
+
+	tests := []struct {
+		name     string
+		saga     Saga
+		produced int
+		err      error
+		outcome  Outcome
+		reason   string
+	}{
+		{name: "compensated step is reverted", saga: build(failed), produced: 1, outcome: ReceiptReverted},
+		{name: "rejected step was not applied", saga: build(rejected), outcome: ReceiptNotApplied, reason: "ACCOUNT_NOT_FOUND"},
+		{name: "failed compensation is not reverted", saga: build(failed), err: errors.New("account not found"), outcome: ReceiptNotReverted, reason: "account not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, ok := NewCompensationReceipt(tt.saga, tt.produced, tt.err)
+			require.True(t, ok)
+			assert.Equal(t, "premium", r.FailedStepId)
+			assert.False(t, r.RolledBack)
+			require.Len(t, r.Steps, 2)
+			assert.Equal(t, ReceiptEntry{StepId: "mesos", Action: AwardMesos, Outcome: ReceiptNotReverted, Reason: "completed before the failure"}, r.Steps[0])
+			assert.Equal(t, ReceiptEntry{StepId: "premium", Action: GrantPremiumTime, Outcome: tt.outcome, Reason: tt.reason}, r.Steps[1])
+		})
+	}
+
+	t.Run("failure of the first effect is rolled back", func(t *testing.T) {
+		s := build(failed)
+		s.Steps[1].Status = Pending
+		r, ok := NewCompensationReceipt(s, 1, nil)
+		require.True(t, ok)
+		assert.True(t, r.RolledBack)
+		require.Len(t, r.Steps, 1)
+	})
+
+	t.Run("saga without a failed step has no receipt", func(t *testing.T) {
+		s := build(failed)
+		s.Steps[2].Status = Completed
+		_, ok := NewCompensationReceipt(s, 0, nil)
+		assert.False(t, ok)
+	})
+}
+
+// TestCompensationReceipt tests that compensating a saga records its receipt on the saga, delivers it to waiters, and
+// emits it as a COMPENSATED status event
+func TestCompensationReceipt(t *testing.T) {
+	te, ctx := setupContext()
+
+	events := make([]saga.StatusEvent[saga.StatusEventCompensatedBody], 0)
+	ctx = producer.WithProvider(ctx, func(token string) producer2.MessageProducer {
+		return func(provider model.Provider[[]kafka.Message]) error {
+			ms, err := provider()
+			if err != nil {
+				return err
+			}
+			for _, m := range ms {
+				var e saga.StatusEvent[saga.StatusEventCompensatedBody]
+				if token == saga.EnvStatusEventTopic && json.Unmarshal(m.Value, &e) == nil && e.Type == saga.StatusEventTypeCompensated {
+					events = append(events, e)
+				}
+			}
+			return nil
+		}
+	})
+	charP := &mock.ProcessorMock{
+		AwardMesosAndEmitFunc: func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, actorId uint32, actorType string, amount int32) error {
+			return nil
+		},
+	}
+	processor, _ := setupTestProcessor(ctx, charP, nil)
+
+	s := NewBuilder().
+		SetSagaType(QuestReward).
+		AddStep("mesos", Pending, AwardMesos, AwardMesosPayload{CharacterId: 12345, ActorType: "NPC", Amount: 1000}).
+		AddStep("premium", Pending, GrantPremiumTime, GrantPremiumTimePayload{AccountId: 1000, Seconds: 3600}).
+		Build()
+	require.NoError(t, processor.Put(s))
+	defer GetCache().Remove(te.Id(), s.TransactionId)
+	require.NoError(t, processor.StepCompleted(s.TransactionId, true))
+
+	c, unsubscribe := GetNotifier().Subscribe(te.Id(), s.TransactionId)
+	defer unsubscribe()
+	require.NoError(t, processor.StepCompleted(s.TransactionId, false))
+
+	expected := []ReceiptEntry{
+		{StepId: "mesos", Action: AwardMesos, Outcome: ReceiptNotReverted, Reason: "completed before the failure"},
+		{StepId: "premium", Action: GrantPremiumTime, Outcome: ReceiptReverted},
+	}
+	s, err := processor.GetById(s.TransactionId)
+	require.NoError(t, err)
+	require.NotNil(t, s.Receipt)
+	assert.Equal(t, "premium", s.Receipt.FailedStepId)
+	assert.False(t, s.Receipt.RolledBack)
+	assert.Equal(t, expected, s.Receipt.Steps)
+
+	select {
+	case ns := <-c:
+		require.NotNil(t, ns.Receipt)
+		assert.Equal(t, expected, ns.Receipt.Steps)
+	default:
+		t.Fatal("receipt was not delivered to the waiter")
+	}
+
+	require.Len(t, events, 1)
+	assert.Equal(t, s.TransactionId, events[0].TransactionId)
+	assert.Equal(t, "premium", events[0].Body.FailedStepId)
+	assert.False(t, events[0].Body.RolledBack)
+	require.Len(t, events[0].Body.Steps, 2)
+	assert.Equal(t, saga.CompensatedStepOutcome{StepId: "premium", Action: string(GrantPremiumTime), Outcome: string(ReceiptReverted)}, events[0].Body.Steps[1])
+
+	rs, err := processor.AwaitTerminal(ctx, s.TransactionId, 5*time.Second)
+	require.NoError(t, err)
+	assert.NotNil(t, rs.Receipt)
+}
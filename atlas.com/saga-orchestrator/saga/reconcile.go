@@ -0,0 +1,371 @@
+package saga
+
+import (
+	"atlas-saga-orchestrator/metrics"
+	"atlas-saga-orchestrator/validation"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Chronicle20/atlas-rest/server"
+	tenant "github.com/Chronicle20/atlas-tenant"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+// Outcomes of reconciling a completed saga, by which reconciled sagas are counted
+const (
+	ReconcileConsistent = "consistent" // Every intended effect of the saga was found downstream
+	ReconcileDivergent  = "divergent"  // An intended effect of the saga was not found downstream
+	ReconcileUnverified = "unverified" // Downstream state could not be retrieved
+)
+
+// maxReconcileSamples bounds the completed sagas awaiting reconciliation, and maxDivergences the divergences reported,
+// so neither grows without bound should reconciliation fall behind
+const (
+	maxReconcileSamples = 1000
+	maxDivergences      = 100
+)
+
+// ReconcileConfig configures the reconciliation of completed sagas against downstream state
+type ReconcileConfig struct {
+	Interval      time.Duration // Interval at which sampled sagas are reconciled. When 0, sagas are not reconciled.
+	SamplePercent uint32        // Percentage of completed sagas sampled for reconciliation
+	Delay         time.Duration // Delay after completion before a saga is reconciled, so downstream reads reflect its commands
+}
+
+// DefaultReconcileConfig is the reconciliation configuration used when none is configured
+var DefaultReconcileConfig = ReconcileConfig{SamplePercent: 10, Delay: 30 * time.Second}
+
+// ReconcileConfigFromEnv loads the reconciliation configuration from the environment
+func ReconcileConfigFromEnv() (ReconcileConfig, error) {
+	c := DefaultReconcileConfig
+	if v, ok := os.LookupEnv("SAGA_RECONCILE_INTERVAL"); ok && v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return ReconcileConfig{}, fmt.Errorf("invalid SAGA_RECONCILE_INTERVAL '%s'", v)
+		}
+		c.Interval = d
+	}
+	if v, ok := os.LookupEnv("SAGA_RECONCILE_SAMPLE_PERCENT"); ok && v != "" {
+		n, err := strconv.ParseUint(v, 10, 32)
+		if err != nil || n > 100 {
+			return ReconcileConfig{}, fmt.Errorf("invalid SAGA_RECONCILE_SAMPLE_PERCENT '%s'", v)
+		}
+		c.SamplePercent = uint32(n)
+	}
+	if v, ok := os.LookupEnv("SAGA_RECONCILE_DELAY"); ok && v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return ReconcileConfig{}, fmt.Errorf("invalid SAGA_RECONCILE_DELAY '%s'", v)
+		}
+		c.Delay = d
+	}
+	return c, nil
+}
+
+var reconcileConfig = ReconcileConfig{}
+
+// InitReconcileConfig sets the singleton reconciliation configuration
+func InitReconcileConfig(c ReconcileConfig) {
+	reconcileConfig = c
+}
+
+// GetReconcileConfig returns the singleton reconciliation configuration
+func GetReconcileConfig() ReconcileConfig {
+	return reconcileConfig
+}
+
+// Sampled returns whether the saga is sampled for reconciliation. Sagas are bucketed by a hash of their transaction ID,
+// so a saga is sampled consistently.
+func (c ReconcileConfig) Sampled(transactionId uuid.UUID) bool {
+	if c.Interval == 0 {
+		return false
+	}
+	h := fnv.New32a()
+	_, _ = h.Write(transactionId[:])
+	return h.Sum32()%100 < c.SamplePercent
+}
+
+// reconcileCheck is a condition a character's state must meet for an intended effect of a saga's step to have been
+// applied downstream
+type reconcileCheck struct {
+	StepId      string
+	Action      Action
+	CharacterId uint32
+	Condition   validation.ConditionInput
+}
+
+// reconcileChecks returns the checks of the intended effects of the saga's completed steps which later steps do not
+// undo: the latest job each character changed to, levels awarded, and the presence of items the saga left characters
+// with more of. Items are checked for presence only, as what was held before the saga is not known.
+func reconcileChecks(s Saga) []reconcileCheck {
+	completed := make([]Step[any], 0, len(s.Steps))
+	for _, st := range s.Steps {
+		if st.Status == Completed {
+			completed = append(completed, st)
+		}
+	}
+
+	r := make([]reconcileCheck, 0)
+	jobs := make(map[uint32]int)
+	awarded := make(map[uint32]map[uint32]bool)
+	for _, st := range completed {
+		switch payload := st.Payload.(type) {
+		case ChangeJobPayload:
+			c := reconcileCheck{StepId: st.StepId, Action: st.Action, CharacterId: payload.CharacterId, Condition: validation.ConditionInput{Type: string(validation.JobCondition), Operator: string(validation.Equals), Value: int(payload.JobId)}}
+			if i, ok := jobs[payload.CharacterId]; ok {
+				r[i] = c
+				continue
+			}
+			jobs[payload.CharacterId] = len(r)
+			r = append(r, c)
+		case AwardLevelPayload:
+			// A character awarded levels is at least that many levels above the first
+			r = append(r, reconcileCheck{StepId: st.StepId, Action: st.Action, CharacterId: payload.CharacterId, Condition: validation.ConditionInput{Type: string(validation.LevelCondition), Operator: string(validation.GreaterEqual), Value: int(payload.Amount) + 1}})
+		case AwardItemActionPayload, AwardAssetIfPayload, CreateAndEquipAssetPayload:
+			characterId, templateId := awardedItem(payload)
+			if awarded[characterId] == nil {
+				awarded[characterId] = make(map[uint32]bool)
+			}
+			if awarded[characterId][templateId] {
+				continue
+			}
+			awarded[characterId][templateId] = true
+			if inventoryDeltas(completed, characterId)[templateId] <= 0 {
+				continue
+			}
+			r = append(r, reconcileCheck{StepId: st.StepId, Action: st.Action, CharacterId: characterId, Condition: validation.ConditionInput{Type: string(validation.ItemCondition), Operator: string(validation.GreaterEqual), Value: 1, ItemId: templateId}})
+		}
+	}
+	return r
+}
+
+// awardedItem returns the character and item awarded by the payload of an award step
+func awardedItem(payload any) (uint32, uint32) {
+	switch p := payload.(type) {
+	case AwardItemActionPayload:
+		return p.CharacterId, p.Item.TemplateId
+	case AwardAssetIfPayload:
+		return p.CharacterId, p.Item.TemplateId
+	case CreateAndEquipAssetPayload:
+		return p.CharacterId, p.Item.TemplateId
+	}
+	return 0, 0
+}
+
+// Divergence records an intended effect of a completed saga which was not found downstream, such as from a command
+// which was lost or misapplied
+type Divergence struct {
+	TenantId      uuid.UUID                 `json:"tenantId"`      // Tenant of the saga
+	TransactionId uuid.UUID                 `json:"transactionId"` // TransactionId of the saga
+	SagaType      Type                      `json:"sagaType"`      // Type of the saga
+	StepId        string                    `json:"stepId"`        // StepId of the step whose effect diverged
+	Action        Action                    `json:"action"`        // Action of the step
+	CharacterId   uint32                    `json:"characterId"`   // Character whose state diverged
+	Condition     validation.ConditionInput `json:"condition"`     // Condition the character's state was expected to meet
+	Actual        int                       `json:"actual"`        // Value the downstream service reported
+	DetectedAt    time.Time                 `json:"detectedAt"`    // Timestamp the divergence was detected
+}
+
+// reconcileSample is a completed saga awaiting reconciliation
+type reconcileSample struct {
+	Tenant      tenant.Model
+	Saga        Saga
+	CompletedAt time.Time
+}
+
+// reconcileLog holds the completed sagas sampled for reconciliation, and the divergences most recently found
+type reconcileLog struct {
+	mutex       sync.Mutex
+	samples     []reconcileSample
+	divergences []Divergence
+}
+
+var rlog *reconcileLog
+var rlogOnce sync.Once
+
+// getReconcileLog returns the singleton reconciliation log
+func getReconcileLog() *reconcileLog {
+	rlogOnce.Do(func() {
+		rlog = &reconcileLog{samples: make([]reconcileSample, 0), divergences: make([]Divergence, 0)}
+	})
+	return rlog
+}
+
+// SampleCompleted records the completed saga for reconciliation, when it is sampled and has effects which can be
+// checked. Should reconciliation fall behind, the oldest samples are dropped.
+func SampleCompleted(t tenant.Model, s Saga) {
+	if !GetReconcileConfig().Sampled(s.TransactionId) || len(reconcileChecks(s)) == 0 {
+		return
+	}
+	rl := getReconcileLog()
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+	if len(rl.samples) >= maxReconcileSamples {
+		rl.samples = rl.samples[1:]
+	}
+	rl.samples = append(rl.samples, reconcileSample{Tenant: t, Saga: s, CompletedAt: time.Now()})
+}
+
+// due removes and returns the samples completed before the cutoff
+func (rl *reconcileLog) due(cutoff time.Time) []reconcileSample {
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+	i := 0
+	for i < len(rl.samples) && rl.samples[i].CompletedAt.Before(cutoff) {
+		i++
+	}
+	r := append([]reconcileSample{}, rl.samples[:i]...)
+	rl.samples = rl.samples[i:]
+	return r
+}
+
+func (rl *reconcileLog) report(ds []Divergence) {
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+	rl.divergences = append(rl.divergences, ds...)
+	if n := len(rl.divergences) - maxDivergences; n > 0 {
+		rl.divergences = rl.divergences[n:]
+	}
+}
+
+// GetDivergences returns the divergences most recently found, oldest first. When a tenant is given, only the
+// divergences of its sagas are included.
+func GetDivergences(tenantId *uuid.UUID) []Divergence {
+	rl := getReconcileLog()
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+	r := make([]Divergence, 0, len(rl.divergences))
+	for _, d := range rl.divergences {
+		if tenantId == nil || d.TenantId == *tenantId {
+			r = append(r, d)
+		}
+	}
+	return r
+}
+
+// Reconciler cross-checks the intended effects of sampled completed sagas against downstream services, reporting
+// divergences. Samples are recorded by the replica completing the saga, so each replica reconciles its own.
+type Reconciler struct {
+	l           logrus.FieldLogger
+	config      ReconcileConfig
+	validP      func(l logrus.FieldLogger, ctx context.Context) validation.Processor
+	reconciled  *metrics.Counter
+	divergences *metrics.Counter
+}
+
+// NewReconciler creates a task reconciling sampled sagas at the configured interval
+func NewReconciler(l logrus.FieldLogger, c ReconcileConfig) *Reconciler {
+	return &Reconciler{
+		l:      l,
+		config: c,
+		validP: func(l logrus.FieldLogger, ctx context.Context) validation.Processor {
+			return validation.NewProcessor(l, ctx)
+		},
+		reconciled:  metrics.GetRegistry().RegisterCounter("saga_reconciled_total", "Number of completed sagas reconciled against downstream state, by type and outcome."),
+		divergences: metrics.GetRegistry().RegisterCounter("saga_divergences_total", "Number of intended effects of completed sagas not found downstream, by action."),
+	}
+}
+
+func (r *Reconciler) Run() {
+	for _, rs := range getReconcileLog().due(time.Now().Add(-r.config.Delay)) {
+		r.reconcile(rs)
+	}
+}
+
+// reconcile checks the intended effects of a sampled saga, by character
+func (r *Reconciler) reconcile(rs reconcileSample) {
+	fl := r.l.WithFields(logrus.Fields{
+		"transaction_id": rs.Saga.TransactionId.String(),
+		"saga_type":      rs.Saga.SagaType,
+		"tenant_id":      rs.Tenant.Id().String(),
+	})
+	validP := r.validP(r.l, tenant.WithContext(context.Background(), rs.Tenant))
+
+	checks := reconcileChecks(rs.Saga)
+	byCharacter := make(map[uint32][]reconcileCheck)
+	order := make([]uint32, 0)
+	for _, c := range checks {
+		if _, ok := byCharacter[c.CharacterId]; !ok {
+			order = append(order, c.CharacterId)
+		}
+		byCharacter[c.CharacterId] = append(byCharacter[c.CharacterId], c)
+	}
+
+	outcome := ReconcileConsistent
+	ds := make([]Divergence, 0)
+	for _, characterId := range order {
+		cs := byCharacter[characterId]
+		conditions := make([]validation.ConditionInput, 0, len(cs))
+		for _, c := range cs {
+			conditions = append(conditions, c.Condition)
+		}
+		vr, err := validP.ValidateCharacterState(characterId, conditions)
+		if err != nil {
+			fl.WithError(err).Warnf("Unable to reconcile saga against the state of character [%d].", characterId)
+			outcome = ReconcileUnverified
+			continue
+		}
+		for _, c := range cs {
+			for _, cr := range vr.Results() {
+				if string(cr.Type) != c.Condition.Type || cr.ItemId != c.Condition.ItemId || cr.Passed {
+					continue
+				}
+				ds = append(ds, Divergence{
+					TenantId:      rs.Tenant.Id(),
+					TransactionId: rs.Saga.TransactionId,
+					SagaType:      rs.Saga.SagaType,
+					StepId:        c.StepId,
+					Action:        c.Action,
+					CharacterId:   characterId,
+					Condition:     c.Condition,
+					Actual:        cr.ActualValue,
+					DetectedAt:    time.Now(),
+				})
+			}
+		}
+	}
+
+	for _, d := range ds {
+		outcome = ReconcileDivergent
+		r.divergences.Inc(map[string]string{"tenant_id": d.TenantId.String(), "action": string(d.Action)})
+		fl.WithField("step_id", d.StepId).Warnf("Saga diverged from the state of character [%d]: expected [%s] %s [%d], found [%d].", d.CharacterId, d.Condition.Type, d.Condition.Operator, d.Condition.Value, d.Actual)
+	}
+	getReconcileLog().report(ds)
+	r.reconciled.Inc(map[string]string{"tenant_id": rs.Tenant.Id().String(), "saga_type": string(rs.Saga.SagaType), "outcome": outcome})
+}
+
+func (r *Reconciler) SleepTime() time.Duration {
+	return r.config.Interval
+}
+
+// InitReconciliationResource registers the divergence report route with the router. Divergences span tenants, so no
+// tenant is required, though they may be filtered to one with the tenantId query parameter.
+func InitReconciliationResource() server.RouteInitializer {
+	return func(r *mux.Router, l logrus.FieldLogger) {
+		r.HandleFunc("/reconciliation/divergences", func(w http.ResponseWriter, r *http.Request) {
+			var tenantId *uuid.UUID
+			if v := r.URL.Query().Get("tenantId"); v != "" {
+				id, err := uuid.Parse(v)
+				if err != nil {
+					l.WithError(err).Errorf("Unable to properly parse tenantId from query.")
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				tenantId = &id
+			}
+			w.Header().Set("Content-Type", "application/json")
+			if err := json.NewEncoder(w).Encode(GetDivergences(tenantId)); err != nil {
+				l.WithError(err).Error("Unable to write divergences.")
+			}
+		}).Methods(http.MethodGet)
+	}
+}
@@ -0,0 +1,139 @@
+package saga
+
+import (
+	"atlas-saga-orchestrator/validation"
+	mock3 "atlas-saga-orchestrator/validation/mock"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReconcileConfigFromEnv tests loading the reconciliation configuration from the environment
+func TestReconcileConfigFromEnv(t *testing.T) {
+	c, err := ReconcileConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, DefaultReconcileConfig, c)
+
+	t.Setenv("SAGA_RECONCILE_INTERVAL", "1m")
+	t.Setenv("SAGA_RECONCILE_SAMPLE_PERCENT", "25")
+	t.Setenv("SAGA_RECONCILE_DELAY", "5s")
+	c, err = ReconcileConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, ReconcileConfig{Interval: time.Minute, SamplePercent: 25, Delay: 5 * time.Second}, c)
+
+	t.Setenv("SAGA_RECONCILE_SAMPLE_PERCENT", "101")
+	_, err = ReconcileConfigFromEnv()
+	assert.Error(t, err)
+}
+
+// TestReconcileChecks tests that only the intended effects of completed steps which later steps do not undo are
+// checked
+func TestReconcileChecks(t *testing.T) {
+	s := NewBuilder().
+		SetSagaType(QuestReward).
+		AddStep("job_1", Completed, ChangeJob, ChangeJobPayload{CharacterId: 12345, JobId: 100}).
+		AddStep("give_kept", Completed, AwardAsset, AwardItemActionPayload{CharacterId: 12345, Item: ItemPayload{TemplateId: 4000000, Quantity: 5}}).
+		AddStep("give_taken", Completed, AwardAsset, AwardItemActionPayload{CharacterId: 12345, Item: ItemPayload{TemplateId: 4000001, Quantity: 1}}).
+		AddStep("take", Completed, DestroyAsset, DestroyAssetPayload{CharacterId: 12345, TemplateId: 4000001, Quantity: 1}).
+		AddStep("level", Completed, AwardLevel, AwardLevelPayload{CharacterId: 12345, Amount: 2}).
+		AddStep("job_2", Completed, ChangeJob, ChangeJobPayload{CharacterId: 12345, JobId: 110}).
+		AddStep("skipped", Pending, AwardAsset, AwardItemActionPayload{CharacterId: 12345, Item: ItemPayload{TemplateId: 4000002, Quantity: 1}}).
+		Build()
+
+	checks := reconcileChecks(s)
+	require.Len(t, checks, 3)
+	assert.Equal(t, "job_2", checks[0].StepId)
+	assert.Equal(t, validation.ConditionInput{Type: "jobId", Operator: "=", Value: 110}, checks[0].Condition)
+	assert.Equal(t, "give_kept", checks[1].StepId)
+	assert.Equal(t, validation.ConditionInput{Type: "item", Operator: ">=", Value: 1, ItemId: 4000000}, checks[1].Condition)
+	assert.Equal(t, "level", checks[2].StepId)
+	assert.Equal(t, validation.ConditionInput{Type: "level", Operator: ">=", Value: 3}, checks[2].Condition)
+}
+
+// TestReconciler tests that sampled completed sagas are reconciled once due, reporting the intended effects not found
+// downstream
+func TestReconciler(t *testing.T) {
+	te, _ := setupContext()
+	defer InitReconcileConfig(ReconcileConfig{})
+
+	build := func() Saga {
+		return NewBuilder().
+			SetSagaType(QuestReward).
+			AddStep("give_item", Completed, AwardAsset, AwardItemActionPayload{CharacterId: 12345, Item: ItemPayload{TemplateId: 4000000, Quantity: 5}}).
+			AddStep("job", Completed, ChangeJob, ChangeJobPayload{CharacterId: 12345, JobId: 100}).
+			Build()
+	}
+
+	t.Run("sagas are not sampled without reconciliation", func(t *testing.T) {
+		InitReconcileConfig(ReconcileConfig{SamplePercent: 100})
+		SampleCompleted(te, build())
+		assert.Empty(t, getReconcileLog().due(time.Now().Add(time.Hour)))
+	})
+
+	InitReconcileConfig(ReconcileConfig{Interval: time.Minute, SamplePercent: 100})
+	diverged := build()
+	SampleCompleted(te, diverged)
+	unverified := build()
+	SampleCompleted(te, unverified)
+
+	l, hook := test.NewNullLogger()
+	r := NewReconciler(l, ReconcileConfig{Interval: time.Minute, Delay: time.Hour})
+	r.validP = func(l logrus.FieldLogger, ctx context.Context) validation.Processor {
+		return &mock3.ProcessorMock{ValidateCharacterStateFunc: func(characterId uint32, conditions []validation.ConditionInput) (validation.ValidationResult, error) {
+			vr := validation.NewValidationResult(characterId)
+			if len(hook.AllEntries()) > 0 {
+				return vr, errors.New("validation service unavailable")
+			}
+			assert.Len(t, conditions, 2)
+			vr.AddConditionResult(validation.ConditionResult{Passed: false, Type: validation.ItemCondition, ItemId: 4000000, ActualValue: 0})
+			vr.AddConditionResult(validation.ConditionResult{Passed: true, Type: validation.JobCondition, ActualValue: 100})
+			return vr, nil
+		}}
+	}
+
+	// Sagas are not reconciled until the delay has passed
+	r.Run()
+	assert.Empty(t, GetDivergences(nil))
+
+	r.config.Delay = 0
+	r.Run()
+	tenantId := te.Id()
+	divergences := GetDivergences(&tenantId)
+	require.Len(t, divergences, 1)
+	assert.Equal(t, diverged.TransactionId, divergences[0].TransactionId)
+	assert.Equal(t, "give_item", divergences[0].StepId)
+	assert.Equal(t, uint32(4000000), divergences[0].Condition.ItemId)
+	assert.Equal(t, 0, divergences[0].Actual)
+	assert.Len(t, hook.AllEntries(), 2)
+
+	other := uuid.New()
+	assert.Empty(t, GetDivergences(&other))
+
+	t.Run("divergences are reported by the REST endpoint", func(t *testing.T) {
+		router := mux.NewRouter()
+		InitReconciliationResource()(router, l)
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/reconciliation/divergences?tenantId="+tenantId.String(), nil))
+		require.Equal(t, http.StatusOK, rr.Code)
+		var actual []Divergence
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &actual))
+		require.Len(t, actual, 1)
+		assert.Equal(t, diverged.TransactionId, actual[0].TransactionId)
+
+		rr = httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/reconciliation/divergences?tenantId=tenant", nil))
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
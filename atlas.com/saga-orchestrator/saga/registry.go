@@ -0,0 +1,147 @@
+package saga
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// ErrInvalidSagaType is returned when a saga's type is not registered, or its steps violate the type's contract
+var ErrInvalidSagaType = errors.New("invalid saga type")
+
+// TypeContract declares the steps a saga of a type may be defined with. An empty list permits any action.
+type TypeContract struct {
+	Actions []Action // Actions the saga's steps may take, including those of branches, error handlers and for_each sub-steps
+	First   []Action // Actions the saga's first step may take
+	Last    []Action // Actions the saga's last step may take
+}
+
+// neutralActions neither take effect beyond the saga nor characterize it, so are permitted by every contract
+var neutralActions = []Action{ValidateCharacterState, SetVariable, EmitAnalyticsEvent}
+
+// Permits returns whether a step of a saga of the type may take the action
+func (c TypeContract) Permits(action Action) bool {
+	return len(c.Actions) == 0 || slices.Contains(c.Actions, action) || slices.Contains(neutralActions, action)
+}
+
+var typeRegistry = struct {
+	mu        sync.RWMutex
+	contracts map[Type]TypeContract
+}{contracts: map[Type]TypeContract{
+	InventoryTransaction: {},
+	QuestReward:          {},
+	TradeTransaction:     {},
+	GuildManagement:      {},
+	MinigameReward:       {},
+	ItemRestoration:      {},
+	CharacterCreation: {
+		Actions: []Action{CreateCharacter, AwardAsset, AwardInventory, AwardMesos, AwardExperience, AwardLevel, CreateAndEquipAsset, EquipAsset, EquipAssetByTemplate, ApplyEquipmentPreset, CreateSkill, UpdateSkill, ChangeJob, WarpToPortal, WarpToRandomPortal},
+		First:   []Action{CreateCharacter},
+	},
+	AccountMerge: {
+		Actions: []Action{TransferCharacter, VerifyAccountMerge},
+		Last:    []Action{VerifyAccountMerge},
+	},
+	CharacterRollback: {
+		Actions: []Action{SnapshotCharacter, RollbackCharacterToSnapshot, RestoreInventorySnapshot, RestoreCharacter},
+		First:   []Action{SnapshotCharacter, RollbackCharacterToSnapshot},
+	},
+	CouponRedemption: {
+		First: []Action{ValidateCoupon},
+	},
+	DeathPenalty: {
+		Actions: []Action{ApplyCharacterExpPenalty, ApplyDurabilityPenalty, CharacterBuffCleanse},
+	},
+	CharacterSlotPurchase: {
+		Actions: []Action{CreateAccountCharacterSlot, CreateCharacter},
+		First:   []Action{CreateAccountCharacterSlot},
+	},
+	GuildEmblemPurchase: {
+		Actions: []Action{DeductMesos, RequestGuildEmblem},
+	},
+	Divorce: {
+		Actions: []Action{ValidateDivorce, DeductMesos, DissolveMarriage, DestroyAsset},
+		First:   []Action{ValidateDivorce},
+	},
+	DisputeHold: {
+		Actions: []Action{SealAsset, DeductMesos, ResolveDispute, UnsealAsset, AwardMesos, ConfiscateAsset},
+		Last:    []Action{ResolveDispute},
+	},
+	Transport: {
+		Actions: []Action{DeductMesos, AwardMesos, ScheduleWarp, IssueTransportTicket},
+		Last:    []Action{ScheduleWarp, IssueTransportTicket},
+	},
+	EscortQuest: {
+		First: []Action{SpawnEscort},
+	},
+	EventShopSettlement: {
+		Actions: []Action{SettleEventCurrency, DestroyAsset, AwardAsset},
+		First:   []Action{SettleEventCurrency},
+	},
+	TutorialCompletion: {
+		Actions: []Action{AwaitEvent, Notify, AwardAsset, AwardExperience, WarpToPortal},
+		First:   []Action{AwaitEvent, Notify},
+	},
+	JobReset: {
+		Actions: []Action{SnapshotCharacter, ChangeJob, StripEquipment, RestoreCharacter},
+		First:   []Action{SnapshotCharacter, ChangeJob},
+	},
+}}
+
+// RegisterType registers the contract of a saga type, replacing any registered before
+func RegisterType(t Type, c TypeContract) {
+	typeRegistry.mu.Lock()
+	defer typeRegistry.mu.Unlock()
+	typeRegistry.contracts[t] = c
+}
+
+// GetTypeContract returns the contract of a saga type, if registered
+func GetTypeContract(t Type) (TypeContract, bool) {
+	typeRegistry.mu.RLock()
+	defer typeRegistry.mu.RUnlock()
+	c, ok := typeRegistry.contracts[t]
+	return c, ok
+}
+
+// ValidateType checks the saga's type is registered, and that its steps honour the type's contract
+func (s Saga) ValidateType() error {
+	c, ok := GetTypeContract(s.SagaType)
+	if !ok {
+		return fmt.Errorf("%w: '%s' is not registered", ErrInvalidSagaType, s.SagaType)
+	}
+	if len(s.Steps) > 0 {
+		if first := s.Steps[0]; len(c.First) > 0 && !slices.Contains(c.First, first.Action) {
+			return fmt.Errorf("%w: %s sagas must begin with one of %v, not step '%s' (%s)", ErrInvalidSagaType, s.SagaType, c.First, first.StepId, first.Action)
+		}
+		if last := s.Steps[len(s.Steps)-1]; len(c.Last) > 0 && !slices.Contains(c.Last, last.Action) {
+			return fmt.Errorf("%w: %s sagas must end with one of %v, not step '%s' (%s)", ErrInvalidSagaType, s.SagaType, c.Last, last.StepId, last.Action)
+		}
+	}
+	return validateStepActions(s.SagaType, c, s.Steps)
+}
+
+// validateStepActions checks the steps, and those nested within them, take only actions the contract permits
+func validateStepActions(t Type, c TypeContract, steps []Step[any]) error {
+	for _, st := range steps {
+		if !c.Permits(st.Action) {
+			return fmt.Errorf("%w: %s sagas may not take action %s (step '%s')", ErrInvalidSagaType, t, st.Action, st.StepId)
+		}
+		for _, b := range st.Branches {
+			if err := validateStepActions(t, c, b.Steps); err != nil {
+				return err
+			}
+		}
+		for _, h := range st.OnError {
+			if err := validateStepActions(t, c, h.Steps); err != nil {
+				return err
+			}
+		}
+		if payload, ok := st.Payload.(ForEachPayload); ok {
+			if err := validateStepActions(t, c, payload.Steps); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package saga
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestValidateType tests that sagas are rejected when their type is not registered, or their steps violate its contract
+func TestValidateType(t *testing.T) {
+	create := Step[any]{StepId: "create", Status: Pending, Action: CreateCharacter, Payload: CharacterCreatePayload{Name: "Atlas"}}
+	destroy := Step[any]{StepId: "destroy", Status: Pending, Action: DestroyAsset, Payload: DestroyAssetPayload{CharacterId: 12345, TemplateId: 4000000, Quantity: 1}}
+	equip := Step[any]{StepId: "equip", Status: Pending, Action: CreateAndEquipAsset, Payload: CreateAndEquipAssetPayload{CharacterId: 12345, Item: ItemPayload{TemplateId: 1302000, Quantity: 1}}}
+	analytics := Step[any]{StepId: "analytics", Status: Pending, Action: EmitAnalyticsEvent, Payload: EmitAnalyticsEventPayload{Name: "character_created"}}
+
+	tests := []struct {
+		name    string
+		s       Saga
+		invalid bool
+	}{
+		{
+			name: "Steps honouring the contract",
+			s:    Saga{SagaType: CharacterCreation, Steps: []Step[any]{create, equip, analytics}},
+		},
+		{
+			name: "Unrestricted type",
+			s:    Saga{SagaType: InventoryTransaction, Steps: []Step[any]{destroy}},
+		},
+		{
+			name:    "Unregistered type",
+			s:       Saga{SagaType: "unknown", Steps: []Step[any]{destroy}},
+			invalid: true,
+		},
+		{
+			name:    "Action not permitted",
+			s:       Saga{SagaType: CharacterCreation, Steps: []Step[any]{create, destroy}},
+			invalid: true,
+		},
+		{
+			name:    "Wrong first step",
+			s:       Saga{SagaType: CharacterCreation, Steps: []Step[any]{equip, create}},
+			invalid: true,
+		},
+		{
+			name: "Wrong last step",
+			s: Saga{SagaType: Transport, Steps: []Step[any]{
+				{StepId: "warp", Status: Pending, Action: ScheduleWarp, Payload: ScheduleWarpPayload{CharacterId: 12345}},
+				{StepId: "fare", Status: Pending, Action: DeductMesos, Payload: DeductMesosPayload{CharacterId: 12345, Amount: 100}},
+			}},
+			invalid: true,
+		},
+		{
+			name: "Action not permitted within a branch",
+			s: Saga{SagaType: CharacterCreation, Steps: []Step[any]{
+				{StepId: "create", Status: Pending, Action: CreateCharacter, Payload: CharacterCreatePayload{Name: "Atlas"}, Branches: []Branch{{Name: "default", Steps: []Step[any]{destroy}}}},
+			}},
+			invalid: true,
+		},
+		{
+			name: "Action not permitted within an error handler",
+			s: Saga{SagaType: CharacterCreation, Steps: []Step[any]{
+				{StepId: "create", Status: Pending, Action: CreateCharacter, Payload: CharacterCreatePayload{Name: "Atlas"}, OnError: []ErrorHandler{{ErrorCode: "INVENTORY_FULL", Reaction: ErrorReactionRetry, Steps: []Step[any]{destroy}}}},
+			}},
+			invalid: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.s.ValidateType()
+			if tt.invalid {
+				assert.ErrorIs(t, err, ErrInvalidSagaType)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestRegisterType tests that registering a type admits sagas of it, under its contract
+func TestRegisterType(t *testing.T) {
+	const seasonal Type = "seasonal_event"
+	s := Saga{SagaType: seasonal, Steps: []Step[any]{{StepId: "mesos", Status: Pending, Action: AwardMesos, Payload: AwardMesosPayload{CharacterId: 12345, Amount: 100}}}}
+	assert.ErrorIs(t, s.ValidateType(), ErrInvalidSagaType)
+
+	RegisterType(seasonal, TypeContract{Actions: []Action{AwardMesos}})
+	assert.NoError(t, s.ValidateType())
+
+	RegisterType(seasonal, TypeContract{Actions: []Action{AwardAsset}})
+	assert.ErrorIs(t, s.ValidateType(), ErrInvalidSagaType)
+}
@@ -0,0 +1,367 @@
+package saga
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	tenant "github.com/Chronicle20/atlas-tenant"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// Persistence backends sagas may be kept in
+const (
+	PersistenceMemory   = "memory"   // Sagas are kept in the in-memory cache only, and are lost when the replica restarts
+	PersistencePostgres = "postgres" // Sagas are written behind to PostgreSQL, and recovered from it when the replica starts
+)
+
+// ErrPersist is returned when a saga cannot be written to the repository before its step is dispatched
+var ErrPersist = errors.New("unable to persist saga")
+
+// DefaultPersistenceFlushInterval is the interval at which the sagas put into the cache are written behind to the
+// repository when none is configured
+const DefaultPersistenceFlushInterval = 250 * time.Millisecond
+
+// Repository is the store sagas are kept in. Repositories are archives, so sagas they hold which are not in the cache
+// are hydrated into it as events referencing them are received, and recovered as the cache is warmed.
+type Repository interface {
+	Archive
+
+	// Save persists the state of a saga for a tenant
+	Save(t tenant.Model, s Saga) error
+}
+
+// PersistenceConfig configures the store sagas are kept in
+type PersistenceConfig struct {
+	Backend       string        // Backend sagas are kept in, memory by default
+	FlushInterval time.Duration // FlushInterval at which the sagas put into the cache are written behind to the repository
+	Host          string
+	Port          int
+	User          string
+	Password      string
+	Name          string // Name of the database
+	SslMode       string
+}
+
+// Enabled returns whether sagas are persisted beyond the in-memory cache
+func (c PersistenceConfig) Enabled() bool {
+	return c.Backend == PersistencePostgres
+}
+
+// DataSourceName returns the connection string of the database
+func (c PersistenceConfig) DataSourceName() string {
+	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s", c.Host, c.Port, c.User, c.Password, c.Name, c.SslMode)
+}
+
+// PersistenceConfigFromEnv loads the persistence configuration from the environment
+func PersistenceConfigFromEnv() (PersistenceConfig, error) {
+	c := PersistenceConfig{Backend: PersistenceMemory, FlushInterval: DefaultPersistenceFlushInterval, Host: "localhost", Port: 5432, SslMode: "disable"}
+
+	if v := os.Getenv("SAGA_PERSISTENCE"); v != "" {
+		if v != PersistenceMemory && v != PersistencePostgres {
+			return PersistenceConfig{}, fmt.Errorf("invalid SAGA_PERSISTENCE '%s'", v)
+		}
+		c.Backend = v
+	}
+	if !c.Enabled() {
+		return c, nil
+	}
+
+	if v := os.Getenv("SAGA_PERSISTENCE_FLUSH_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return PersistenceConfig{}, fmt.Errorf("invalid SAGA_PERSISTENCE_FLUSH_INTERVAL '%s'", v)
+		}
+		c.FlushInterval = d
+	}
+	if v := os.Getenv("DB_HOST"); v != "" {
+		c.Host = v
+	}
+	if v := os.Getenv("DB_PORT"); v != "" {
+		p, err := strconv.Atoi(v)
+		if err != nil || p <= 0 {
+			return PersistenceConfig{}, fmt.Errorf("invalid DB_PORT '%s'", v)
+		}
+		c.Port = p
+	}
+	if v := os.Getenv("DB_SSL_MODE"); v != "" {
+		c.SslMode = v
+	}
+	c.User = os.Getenv("DB_USER")
+	c.Password = os.Getenv("DB_PASSWORD")
+	c.Name = os.Getenv("DB_NAME")
+	if c.User == "" || c.Name == "" {
+		return PersistenceConfig{}, fmt.Errorf("DB_USER and DB_NAME are required when SAGA_PERSISTENCE is '%s'", c.Backend)
+	}
+	return c, nil
+}
+
+// tenants are the tenants whose sagas have been processed, by ID. The cache is keyed by tenant ID alone, so the rest of
+// each tenant is remembered for the sagas written through to the repository, and recovered from it.
+var tenants sync.Map
+
+// rememberTenant records the tenant, so its sagas may be persisted
+func rememberTenant(t tenant.Model) {
+	tenants.Store(t.Id(), t)
+}
+
+// tenantById returns the remembered tenant of the ID
+func tenantById(tenantId uuid.UUID) (tenant.Model, bool) {
+	v, ok := tenants.Load(tenantId)
+	if !ok {
+		return tenant.Model{}, false
+	}
+	return v.(tenant.Model), true
+}
+
+// CacheRepository is the repository of the in-memory cache, which holds sagas only as long as the replica runs
+type CacheRepository struct{}
+
+// GetById returns a cached saga by its transaction ID for a tenant
+func (CacheRepository) GetById(tenantId uuid.UUID, transactionId uuid.UUID) (Saga, bool, error) {
+	s, ok := cache().GetById(tenantId, transactionId)
+	return s, ok, nil
+}
+
+// GetActive returns the cached sagas with work remaining, by tenant
+func (CacheRepository) GetActive() (map[tenant.Model][]Saga, error) {
+	r := make(map[tenant.Model][]Saga)
+	for _, tenantId := range cache().Tenants() {
+		t, ok := tenantById(tenantId)
+		if !ok {
+			continue
+		}
+		for _, s := range cache().GetAll(tenantId) {
+			if s.active() {
+				r[t] = append(r[t], s)
+			}
+		}
+	}
+	return r, nil
+}
+
+// GetDue returns the cached sagas whose current step is due by the given time, by tenant
+func (r CacheRepository) GetDue(now time.Time) (map[tenant.Model][]Saga, error) {
+	active, err := r.GetActive()
+	if err != nil {
+		return nil, err
+	}
+	due := make(map[tenant.Model][]Saga)
+	for t, sagas := range active {
+		for _, s := range sagas {
+			if at, ok := s.dueAt(); ok && !now.Before(at) {
+				due[t] = append(due[t], s)
+			}
+		}
+	}
+	return due, nil
+}
+
+// Save puts the saga into the cache for a tenant
+func (CacheRepository) Save(t tenant.Model, s Saga) error {
+	rememberTenant(t)
+	cache().Put(t.Id(), s)
+	return nil
+}
+
+var repository Repository = CacheRepository{}
+var repositoryLogger logrus.FieldLogger
+var repositoryMutex sync.RWMutex
+
+// InitRepository sets the repository sagas are kept in. A repository other than the cache's has each saga put into the
+// cache written behind to it, and is the archive sagas are hydrated and recovered from.
+func InitRepository(l logrus.FieldLogger, r Repository) {
+	repositoryMutex.Lock()
+	repository = r
+	repositoryLogger = l
+	repositoryMutex.Unlock()
+
+	if _, ok := r.(CacheRepository); ok {
+		InitArchive(nil)
+		return
+	}
+	InitArchive(r)
+}
+
+// GetRepository returns the repository sagas are kept in, the cache's unless another is set
+func GetRepository() Repository {
+	repositoryMutex.RLock()
+	defer repositoryMutex.RUnlock()
+	return repository
+}
+
+// persistentCache is the cache writing the sagas put into it behind to the repository, so they survive a restart. Sagas
+// removed from the cache remain in the repository, which is the archive they are read from once evicted.
+type persistentCache struct {
+	Cache
+	l logrus.FieldLogger
+	r Repository
+}
+
+// Put adds or updates a saga in the cache for a tenant, and queues it to be written behind to the repository. Sagas
+// are written before their steps are dispatched (see persist), so changes made as events arrive do not await the
+// database.
+func (c persistentCache) Put(tenantId uuid.UUID, s Saga) {
+	c.Cache.Put(tenantId, s)
+
+	t, ok := tenantById(tenantId)
+	if !ok {
+		c.l.WithFields(logrus.Fields{
+			"transaction_id": s.TransactionId.String(),
+			"saga_type":      s.SagaType,
+			"tenant_id":      tenantId.String(),
+		}).Error("Unable to persist saga of unknown tenant.")
+		return
+	}
+	getWriteBehind().enqueue(t, s)
+}
+
+// Remove removes a saga from the cache for a tenant, first writing it should it await being written, so a saga no
+// longer cached, such as one finished or released to another replica, is read from the archive as it was last put
+func (c persistentCache) Remove(tenantId uuid.UUID, transactionId uuid.UUID) bool {
+	_, _ = getWriteBehind().flush(c.l, c.r, &writeKey{tenantId: tenantId, transactionId: transactionId})
+	return c.Cache.Remove(tenantId, transactionId)
+}
+
+// writeKey identifies a saga of a tenant awaiting being written
+type writeKey struct {
+	tenantId      uuid.UUID
+	transactionId uuid.UUID
+}
+
+// pendingWrite is the latest state of a saga awaiting being written, and the tenant it is written for
+type pendingWrite struct {
+	t tenant.Model
+	s Saga
+}
+
+// writeBehind holds the sagas put into the cache awaiting being written to the repository. A saga put again before being
+// written is written once, in its latest state. Writes are serialized, so an earlier state of a saga is never written
+// over a later one.
+type writeBehind struct {
+	mutex   sync.Mutex
+	writing sync.Mutex
+	pending map[writeKey]pendingWrite
+}
+
+var wb *writeBehind
+var wbOnce sync.Once
+
+// getWriteBehind returns the singleton write-behind queue
+func getWriteBehind() *writeBehind {
+	wbOnce.Do(func() {
+		wb = &writeBehind{pending: make(map[writeKey]pendingWrite)}
+	})
+	return wb
+}
+
+// ResetWriteBehind discards the sagas awaiting being written for testing
+func ResetWriteBehind() {
+	w := getWriteBehind()
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.pending = make(map[writeKey]pendingWrite)
+}
+
+// enqueue queues the saga of the tenant to be written, replacing any earlier state of it awaiting being written
+func (w *writeBehind) enqueue(t tenant.Model, s Saga) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.pending[writeKey{tenantId: t.Id(), transactionId: s.TransactionId}] = pendingWrite{t: t, s: s}
+}
+
+// take removes the sagas awaiting being written, only that identified when given
+func (w *writeBehind) take(k *writeKey) []pendingWrite {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if k != nil {
+		p, ok := w.pending[*k]
+		if !ok {
+			return nil
+		}
+		delete(w.pending, *k)
+		return []pendingWrite{p}
+	}
+	r := make([]pendingWrite, 0, len(w.pending))
+	for _, p := range w.pending {
+		r = append(r, p)
+	}
+	w.pending = make(map[writeKey]pendingWrite)
+	return r
+}
+
+// requeue queues a saga which could not be written to be written again, unless it has since been put again
+func (w *writeBehind) requeue(p pendingWrite) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	k := writeKey{tenantId: p.t.Id(), transactionId: p.s.TransactionId}
+	if _, ok := w.pending[k]; !ok {
+		w.pending[k] = p
+	}
+}
+
+// flush writes the sagas awaiting being written to the repository, only that identified when given, returning the
+// number written and the failures to write. Failures are logged rather than failing the saga, which is written again at
+// the next flush.
+func (w *writeBehind) flush(l logrus.FieldLogger, r Repository, k *writeKey) (int, error) {
+	w.writing.Lock()
+	defer w.writing.Unlock()
+	written := 0
+	var errs []error
+	for _, p := range w.take(k) {
+		if err := r.Save(p.t, p.s); err != nil {
+			l.WithError(err).WithFields(logrus.Fields{
+				"transaction_id": p.s.TransactionId.String(),
+				"saga_type":      p.s.SagaType,
+				"tenant_id":      p.t.Id().String(),
+			}).Error("Unable to persist saga.")
+			w.requeue(p)
+			errs = append(errs, err)
+			continue
+		}
+		written++
+	}
+	return written, errors.Join(errs...)
+}
+
+// persist writes the saga of the tenant to the repository now, should it await being written. Steps are dispatched only
+// once the state recording their dispatch is written, so a replica restarting after dispatching a step restores it as
+// dispatched, rather than dispatching it again.
+func persist(l logrus.FieldLogger, tenantId uuid.UUID, transactionId uuid.UUID) error {
+	r := GetRepository()
+	if _, ok := r.(CacheRepository); ok {
+		return nil
+	}
+	if _, err := getWriteBehind().flush(l, r, &writeKey{tenantId: tenantId, transactionId: transactionId}); err != nil {
+		return fmt.Errorf("%w [%s]: %w", ErrPersist, transactionId, err)
+	}
+	return nil
+}
+
+// PersistenceWriter writes the sagas put into the cache behind to the repository every interval, and as the replica
+// shuts down
+type PersistenceWriter struct {
+	l        logrus.FieldLogger
+	interval time.Duration
+}
+
+// NewPersistenceWriter creates a task writing the sagas put into the cache to the repository at the interval
+func NewPersistenceWriter(l logrus.FieldLogger, interval time.Duration) *PersistenceWriter {
+	return &PersistenceWriter{l: l, interval: interval}
+}
+
+func (w *PersistenceWriter) Run() {
+	r := GetRepository()
+	if _, ok := r.(CacheRepository); ok {
+		return
+	}
+	_, _ = getWriteBehind().flush(w.l, r, nil)
+}
+
+func (w *PersistenceWriter) SleepTime() time.Duration {
+	return w.interval
+}
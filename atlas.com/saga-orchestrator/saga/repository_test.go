@@ -0,0 +1,247 @@
+package saga
+
+import (
+	"atlas-saga-orchestrator/character/mock"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Chronicle20/atlas-constants/channel"
+	"github.com/Chronicle20/atlas-constants/world"
+	tenant "github.com/Chronicle20/atlas-tenant"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testRepository records the latest state of the sagas saved to it, or fails saves while err is set
+type testRepository struct {
+	mutex   sync.Mutex
+	tenants map[uuid.UUID]tenant.Model
+	sagas   map[uuid.UUID]Saga
+	saves   int
+	err     error
+}
+
+func newTestRepository() *testRepository {
+	return &testRepository{tenants: make(map[uuid.UUID]tenant.Model), sagas: make(map[uuid.UUID]Saga)}
+}
+
+func (r *testRepository) Save(t tenant.Model, s Saga) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.err != nil {
+		return r.err
+	}
+	r.tenants[s.TransactionId] = t
+	r.sagas[s.TransactionId] = s
+	r.saves++
+	return nil
+}
+
+func (r *testRepository) GetById(tenantId uuid.UUID, transactionId uuid.UUID) (Saga, bool, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	s, ok := r.sagas[transactionId]
+	if !ok || r.tenants[transactionId].Id() != tenantId {
+		return Saga{}, false, nil
+	}
+	return s, true, nil
+}
+
+func (r *testRepository) GetActive() (map[tenant.Model][]Saga, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	result := make(map[tenant.Model][]Saga)
+	for id, s := range r.sagas {
+		if s.active() {
+			result[r.tenants[id]] = append(result[r.tenants[id]], s)
+		}
+	}
+	return result, nil
+}
+
+func (r *testRepository) GetDue(now time.Time) (map[tenant.Model][]Saga, error) {
+	active, _ := r.GetActive()
+	result := make(map[tenant.Model][]Saga)
+	for t, sagas := range active {
+		for _, s := range sagas {
+			if at, ok := s.dueAt(); ok && !now.Before(at) {
+				result[t] = append(result[t], s)
+			}
+		}
+	}
+	return result, nil
+}
+
+// TestPersistenceConfigFromEnv tests loading the persistence configuration from the environment
+func TestPersistenceConfigFromEnv(t *testing.T) {
+	c, err := PersistenceConfigFromEnv()
+	require.NoError(t, err)
+	assert.False(t, c.Enabled())
+
+	t.Setenv("SAGA_PERSISTENCE", "postgres")
+	_, err = PersistenceConfigFromEnv()
+	assert.Error(t, err)
+
+	t.Setenv("DB_USER", "atlas")
+	t.Setenv("DB_PASSWORD", "secret")
+	t.Setenv("DB_NAME", "atlas-saga-orchestrator")
+	t.Setenv("DB_HOST", "postgres")
+	c, err = PersistenceConfigFromEnv()
+	require.NoError(t, err)
+	assert.True(t, c.Enabled())
+	assert.Equal(t, DefaultPersistenceFlushInterval, c.FlushInterval)
+	assert.Equal(t, "host=postgres port=5432 user=atlas password=secret dbname=atlas-saga-orchestrator sslmode=disable", c.DataSourceName())
+
+	t.Setenv("SAGA_PERSISTENCE_FLUSH_INTERVAL", "1s")
+	c, err = PersistenceConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, c.FlushInterval)
+
+	for k, v := range map[string]string{
+		"SAGA_PERSISTENCE":                "sqlite",
+		"SAGA_PERSISTENCE_FLUSH_INTERVAL": "0s",
+		"DB_PORT":                         "default",
+	} {
+		t.Run(k, func(t *testing.T) {
+			t.Setenv(k, v)
+			_, err := PersistenceConfigFromEnv()
+			assert.Error(t, err)
+		})
+	}
+}
+
+// TestPersistentCache tests that sagas put into the cache are written behind to the repository, and before their steps
+// are dispatched, and that those with work remaining are recovered from it once the cache is lost, as it is by a restart
+func TestPersistentCache(t *testing.T) {
+	te, ctx := setupContext()
+	l, _ := test.NewNullLogger()
+	r := newTestRepository()
+	InitRepository(l, r)
+	defer InitRepository(l, CacheRepository{})
+	defer ResetRetryQueue()
+	defer ResetWriteBehind()
+	flush := func() int {
+		n, _ := getWriteBehind().flush(l, r, nil)
+		return n
+	}
+
+	// The attempts of each saga's current step persisted as it is dispatched
+	persisted := make(map[uuid.UUID]int)
+	charP := &mock.ProcessorMock{
+		AwardMesosAndEmitFunc: func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, actorId uint32, actorType string, amount int32) error {
+			persisted[transactionId] = -1
+			if ps, ok, _ := r.GetById(te.Id(), transactionId); ok {
+				if st, ok := ps.GetCurrentStep(); ok {
+					persisted[transactionId] = len(st.Attempts)
+				}
+			}
+			return nil
+		},
+	}
+	processor, _ := setupTestProcessor(ctx, charP, nil)
+	start := func(t *testing.T) Saga {
+		s := NewBuilder().
+			SetSagaType(QuestReward).
+			AddStep("mesos", Pending, AwardMesos, AwardMesosPayload{CharacterId: 12345, ActorType: "NPC", Amount: 1000}).
+			AddStep("bonus", Pending, AwardMesos, AwardMesosPayload{CharacterId: 12345, ActorType: "NPC", Amount: 500}).
+			Build()
+		require.NoError(t, processor.Put(s))
+		return s
+	}
+
+	t.Run("sagas are written before their steps are dispatched", func(t *testing.T) {
+		s := start(t)
+		assert.Equal(t, 1, persisted[s.TransactionId])
+		ps, ok, err := r.GetById(te.Id(), s.TransactionId)
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.Equal(t, te, r.tenants[s.TransactionId])
+		require.Len(t, ps.Steps[0].Attempts, 1)
+		a, ok := GetArchive()
+		require.True(t, ok)
+		assert.Equal(t, r, a)
+	})
+
+	t.Run("sagas are written behind", func(t *testing.T) {
+		s := start(t)
+		saves := r.saves
+		cs, ok := GetCache().GetById(te.Id(), s.TransactionId)
+		require.True(t, ok)
+		cs.InitiatedBy = "first"
+		GetCache().Put(te.Id(), cs)
+		cs.InitiatedBy = "second"
+		GetCache().Put(te.Id(), cs)
+		assert.Equal(t, saves, r.saves)
+
+		// The states a saga is put in before being flushed are written once, as the latest of them
+		assert.Equal(t, 1, flush())
+		assert.Equal(t, saves+1, r.saves)
+		assert.Equal(t, 0, flush())
+		ps, ok, err := r.GetById(te.Id(), s.TransactionId)
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.Equal(t, "second", ps.InitiatedBy)
+	})
+
+	t.Run("sagas with work remaining are recovered", func(t *testing.T) {
+		s := start(t)
+		require.NoError(t, processor.StepCompleted(s.TransactionId, true))
+		flush()
+
+		ResetCache()
+		_, err := processor.GetById(s.TransactionId)
+		require.NoError(t, err)
+
+		ResetCache()
+		require.NoError(t, WarmCache(l))
+		rs, ok := GetCache().GetById(te.Id(), s.TransactionId)
+		require.True(t, ok)
+		assert.Equal(t, Completed, rs.Steps[0].Status)
+		assert.Equal(t, Pending, rs.Steps[1].Status)
+	})
+
+	t.Run("finished sagas remain readable", func(t *testing.T) {
+		s := start(t)
+		done, unsubscribe := GetNotifier().Subscribe(te.Id(), s.TransactionId)
+		defer unsubscribe()
+		require.NoError(t, processor.StepCompleted(s.TransactionId, true))
+		require.NoError(t, processor.StepCompleted(s.TransactionId, true))
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("saga did not complete")
+		}
+		// Sagas leaving the cache are written as they are removed, without awaiting a flush
+		_, ok := GetCache().GetById(te.Id(), s.TransactionId)
+		assert.False(t, ok)
+		ps, ok, err := r.GetById(te.Id(), s.TransactionId)
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.False(t, ps.active())
+	})
+
+	t.Run("failures to persist park the step rather than failing the saga", func(t *testing.T) {
+		flush()
+		r.err = errors.New("database unavailable")
+		s := start(t)
+		_, dispatched := persisted[s.TransactionId]
+		assert.False(t, dispatched)
+		cs, ok := GetCache().GetById(te.Id(), s.TransactionId)
+		require.True(t, ok)
+		require.Len(t, cs.Steps[0].Attempts, 1)
+		assert.Equal(t, ErrorCodeDispatchFailed, cs.Steps[0].Attempts[0].ErrorCode)
+		assert.NotNil(t, cs.Steps[0].Attempts[0].RetryAt)
+		assert.Equal(t, 0, flush())
+
+		// Writes which failed are retried by the next flush
+		r.err = nil
+		assert.Equal(t, 1, flush())
+		_, ok, err := r.GetById(te.Id(), s.TransactionId)
+		require.NoError(t, err)
+		assert.True(t, ok)
+	})
+}
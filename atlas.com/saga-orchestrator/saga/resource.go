@@ -2,44 +2,156 @@ package saga
 
 import (
 	"atlas-saga-orchestrator/rest"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/Chronicle20/atlas-model/model"
 	"github.com/Chronicle20/atlas-rest/server"
+	tenant "github.com/Chronicle20/atlas-tenant"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/jtumidanski/api2go/jsonapi"
 	"github.com/sirupsen/logrus"
 	"net/http"
+	"strconv"
+	"time"
 )
 
+// MaxWait is the longest a request may wait for a saga to reach a terminal state
+const MaxWait = 60 * time.Second
+
+// ParseWait parses the optional wait query parameter (e.g. wait=30s), which is capped at MaxWait
+func ParseWait(r *http.Request) (time.Duration, error) {
+	v := r.URL.Query().Get("wait")
+	if v == "" {
+		return 0, nil
+	}
+	wait, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, err
+	}
+	if wait < 0 {
+		return 0, fmt.Errorf("wait must not be negative")
+	}
+	if wait > MaxWait {
+		wait = MaxWait
+	}
+	return wait, nil
+}
+
+// ParseLabels parses the optional, repeatable label query parameter (e.g. label=event:halloween2025)
+func ParseLabels(r *http.Request) (map[string]string, error) {
+	labels := make(map[string]string)
+	for _, v := range r.URL.Query()["label"] {
+		key, value, err := ParseLabel(v)
+		if err != nil {
+			return nil, err
+		}
+		labels[key] = value
+	}
+	return labels, nil
+}
+
+// ParseQuery parses the optional status, sagaType, characterId, label, page and pageSize query parameters of a saga
+// listing (e.g. status=failing&sagaType=inventory_transaction&characterId=123&page=2)
+func ParseQuery(r *http.Request) (Query, error) {
+	labels, err := ParseLabels(r)
+	if err != nil {
+		return Query{}, err
+	}
+	v := r.URL.Query()
+	q := Query{SagaType: Type(v.Get("sagaType")), Labels: labels, Page: 1, PageSize: DefaultPageSize}
+
+	if s := v.Get("status"); s != "" {
+		q.Status = SagaStatus(s)
+		valid := false
+		for _, ss := range SagaStatuses {
+			valid = valid || ss == q.Status
+		}
+		if !valid {
+			return Query{}, fmt.Errorf("unknown status '%s'", s)
+		}
+	}
+	if s := v.Get("characterId"); s != "" {
+		id, err := strconv.ParseUint(s, 10, 32)
+		if err != nil {
+			return Query{}, fmt.Errorf("invalid characterId '%s'", s)
+		}
+		q.CharacterId = uint32(id)
+	}
+	if s := v.Get("page"); s != "" {
+		page, err := strconv.Atoi(s)
+		if err != nil || page < 1 {
+			return Query{}, fmt.Errorf("invalid page '%s'", s)
+		}
+		q.Page = page
+	}
+	if s := v.Get("pageSize"); s != "" {
+		size, err := strconv.Atoi(s)
+		if err != nil || size < 1 || size > MaxPageSize {
+			return Query{}, fmt.Errorf("invalid pageSize '%s', must be between 1 and %d", s, MaxPageSize)
+		}
+		q.PageSize = size
+	}
+	return q, nil
+}
+
 // InitResource registers the routes with the router
 func InitResource(si jsonapi.ServerInformation) server.RouteInitializer {
 	return func(r *mux.Router, l logrus.FieldLogger) {
 		r.HandleFunc("/sagas", rest.RegisterHandler(l)(si)("get_all_sagas", getAllSagasHandler)).Methods(http.MethodGet)
-		r.HandleFunc("/sagas", rest.RegisterInputHandler[RestModel](l)(si)("create_saga", createSagaHandler)).Methods(http.MethodPost)
+		r.HandleFunc("/sagas", ShadowGuarded(l, ValidateIntake(l, rest.RegisterInputHandler[RestModel](l)(si)("create_saga", createSagaHandler)))).Methods(http.MethodPost)
 		r.HandleFunc("/sagas/{transactionId}", rest.RegisterHandler(l)(si)("get_saga_by_id", getSagaByIdHandler)).Methods(http.MethodGet)
+		r.HandleFunc("/sagas/{transactionId}/approve", ShadowGuarded(l, rest.RegisterInputHandler[ReviewRestModel](l)(si)("approve_saga", reviewSagaHandler(true)))).Methods(http.MethodPost)
+		r.HandleFunc("/sagas/{transactionId}/reject", ShadowGuarded(l, rest.RegisterInputHandler[ReviewRestModel](l)(si)("reject_saga", reviewSagaHandler(false)))).Methods(http.MethodPost)
+		r.HandleFunc("/sagas/{transactionId}/resolve", ShadowGuarded(l, rest.RegisterInputHandler[ResolutionRestModel](l)(si)("resolve_saga", resolveSagaHandler))).Methods(http.MethodPost)
+		r.HandleFunc("/sagas/{transactionId}/notes", ShadowGuarded(l, rest.RegisterInputHandler[NoteRestModel](l)(si)("add_saga_note", addSagaNoteHandler))).Methods(http.MethodPost)
+		r.HandleFunc("/sagas/{transactionId}/abort", ShadowGuarded(l, rest.RegisterInputHandler[AbortRestModel](l)(si)("abort_saga", abortSagaHandler))).Methods(http.MethodPost)
+		r.HandleFunc("/subscriptions", rest.RegisterHandler(l)(si)("get_all_subscriptions", getAllSubscriptionsHandler)).Methods(http.MethodGet)
+		r.HandleFunc("/subscriptions", ShadowGuarded(l, rest.RegisterInputHandler[SubscriptionRestModel](l)(si)("create_subscription", createSubscriptionHandler))).Methods(http.MethodPost)
+		r.HandleFunc("/subscriptions/{subscriptionId}", ShadowGuarded(l, rest.RegisterHandler(l)(si)("delete_subscription", deleteSubscriptionHandler))).Methods(http.MethodDelete)
 	}
 }
 
-// getAllSagasHandler returns a handler for the GET /sagas endpoint
+// getAllSagasHandler returns a handler for the GET /sagas endpoint, listing a page of the sagas selected by the query
 func getAllSagasHandler(d *rest.HandlerDependency, c *rest.HandlerContext) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		// Get all sagas
-		sms, err := NewProcessor(d.Logger(), d.Context()).GetAll()
+		q, err := ParseQuery(r)
+		if err != nil {
+			d.Logger().WithError(err).Errorf("Unable to properly parse saga query.")
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		// Get the page of sagas the query selects
+		page, err := NewProcessor(d.Logger(), d.Context()).Query(q)
 		if err != nil {
 			d.Logger().WithError(err).Error("Failed to retrieve sagas")
 			w.WriteHeader(http.StatusInternalServerError)
+			return
 		}
 
-		rms, err := model.SliceMap(Transform)(model.FixedProvider(sms))(model.ParallelMap())()
+		rms, err := model.SliceMap(Transform)(model.FixedProvider(page.Sagas))(model.ParallelMap())()
 		if err != nil {
 			d.Logger().WithError(err).Error("Failed to retrieve sagas")
 			w.WriteHeader(http.StatusInternalServerError)
+			return
 		}
 
-		// Marshal response
-		query := r.URL.Query()
-		queryParams := jsonapi.ParseQueryFields(&query)
-		server.MarshalResponse[[]RestModel](d.Logger())(w)(c.ServerInformation())(queryParams)(rms)
+		// Marshal response, with the pagination links and meta
+		doc, err := jsonapi.MarshalToStruct(rms, c.ServerInformation())
+		if err != nil {
+			d.Logger().WithError(err).Error("Failed to marshal sagas")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		doc.Links = page.Links(r.URL)
+		doc.Meta = page.Meta()
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		w.WriteHeader(http.StatusOK)
+		if err = json.NewEncoder(w).Encode(doc); err != nil {
+			d.Logger().WithError(err).Error("Unable to write sagas.")
+		}
 	}
 }
 
@@ -47,17 +159,26 @@ func getAllSagasHandler(d *rest.HandlerDependency, c *rest.HandlerContext) http.
 func getSagaByIdHandler(d *rest.HandlerDependency, c *rest.HandlerContext) http.HandlerFunc {
 	return rest.ParseTransactionId(d.Logger(), func(transactionId uuid.UUID) http.HandlerFunc {
 		return func(w http.ResponseWriter, r *http.Request) {
-			// Get the saga
-			saga, err := NewProcessor(d.Logger(), d.Context()).GetById(transactionId)
+			wait, err := ParseWait(r)
 			if err != nil {
-				d.Logger().WithError(err).Error("Failed to retrieve sagas")
-				w.WriteHeader(http.StatusInternalServerError)
+				d.Logger().WithError(err).Errorf("Unable to properly parse wait from query.")
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			// Get the saga, waiting for it to reach a terminal state if requested
+			saga, err := NewProcessor(d.Logger(), d.Context()).AwaitTerminal(r.Context(), transactionId, wait)
+			if err != nil {
+				d.Logger().WithError(err).Debugf("Unable to locate saga [%s].", transactionId.String())
+				w.WriteHeader(http.StatusNotFound)
+				return
 			}
 
 			rms, err := model.Map(Transform)(model.FixedProvider(saga))()
 			if err != nil {
 				d.Logger().WithError(err).Error("Failed to retrieve sagas")
 				w.WriteHeader(http.StatusInternalServerError)
+				return
 			}
 
 			// Marshal response
@@ -86,6 +207,52 @@ func createSagaHandler(d *rest.HandlerDependency, c *rest.HandlerContext, im Res
 
 		// Create the saga
 		err = NewProcessor(d.Logger(), d.Context()).Put(saga)
+		if errors.Is(err, ErrSagaQueued) {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		if errors.Is(err, ErrBudgetExceeded) {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		if errors.Is(err, ErrAssetConflict) {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		if errors.Is(err, ErrInvalidVariable) {
+			d.Logger().WithError(err).Error("Saga has invalid variables")
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, ErrInvalidSagaType) {
+			d.Logger().WithError(err).Error("Saga violates the contract of its type")
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, ErrInvalidDependency) {
+			d.Logger().WithError(err).Error("Saga has invalid step dependencies")
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, ErrInvalidTimer) {
+			d.Logger().WithError(err).Error("Saga has a quest timer which could never expire")
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, ErrInvalidAudit) {
+			d.Logger().WithError(err).Error("Saga has an invalid inventory audit")
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, ErrChainTemplate) {
+			d.Logger().WithError(err).Error("Saga has an invalid onComplete")
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, ErrActionNotEnabled) {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			return
+		}
 		if err != nil {
 			d.Logger().WithError(err).Error("Failed to create saga")
 			w.WriteHeader(http.StatusInternalServerError)
@@ -114,3 +281,189 @@ func createSagaHandler(d *rest.HandlerDependency, c *rest.HandlerContext, im Res
 		server.MarshalResponse[RestModel](d.Logger())(w)(c.ServerInformation())(queryParams)(rm)
 	}
 }
+
+// reviewSagaHandler returns a handler for the POST /sagas/{transactionId}/approve and /reject endpoints
+func reviewSagaHandler(approved bool) rest.InputHandler[ReviewRestModel] {
+	return func(d *rest.HandlerDependency, c *rest.HandlerContext, im ReviewRestModel) http.HandlerFunc {
+		return rest.ParseTransactionId(d.Logger(), func(transactionId uuid.UUID) http.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request) {
+				if im.Reviewer == "" {
+					d.Logger().Errorf("Reviewer is required to review saga [%s].", transactionId.String())
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+
+				p := NewProcessor(d.Logger(), d.Context())
+				if _, err := p.GetById(transactionId); err != nil {
+					d.Logger().WithError(err).Debugf("Unable to locate saga [%s].", transactionId.String())
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+
+				err := p.Review(transactionId, approved, im.Reviewer, im.Comment)
+				if errors.Is(err, ErrSagaNotHeld) || errors.Is(err, ErrResolutionRequired) {
+					w.WriteHeader(http.StatusConflict)
+					return
+				}
+				if errors.Is(err, ErrSelfApproval) {
+					w.WriteHeader(http.StatusForbidden)
+					return
+				}
+				if err != nil {
+					d.Logger().WithError(err).Errorf("Unable to review saga [%s].", transactionId.String())
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+				w.WriteHeader(http.StatusNoContent)
+			}
+		})
+	}
+}
+
+// resolveSagaHandler returns a handler for the POST /sagas/{transactionId}/resolve endpoint
+func resolveSagaHandler(d *rest.HandlerDependency, c *rest.HandlerContext, im ResolutionRestModel) http.HandlerFunc {
+	return rest.ParseTransactionId(d.Logger(), func(transactionId uuid.UUID) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if im.Resolver == "" {
+				d.Logger().Errorf("Resolver is required to resolve saga [%s].", transactionId.String())
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			p := NewProcessor(d.Logger(), d.Context())
+			if _, err := p.GetById(transactionId); err != nil {
+				d.Logger().WithError(err).Debugf("Unable to locate saga [%s].", transactionId.String())
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			err := p.Resolve(transactionId, im.Resolution, im.Resolver, im.Comment)
+			if errors.Is(err, ErrInvalidResolution) {
+				d.Logger().WithError(err).Errorf("Unable to resolve saga [%s].", transactionId.String())
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			if errors.Is(err, ErrSagaNotHeld) {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			if err != nil {
+				d.Logger().WithError(err).Errorf("Unable to resolve saga [%s].", transactionId.String())
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+}
+
+// addSagaNoteHandler returns a handler for the POST /sagas/{transactionId}/notes endpoint
+func addSagaNoteHandler(d *rest.HandlerDependency, c *rest.HandlerContext, im NoteRestModel) http.HandlerFunc {
+	return rest.ParseTransactionId(d.Logger(), func(transactionId uuid.UUID) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if im.Author == "" || im.Comment == "" {
+				d.Logger().Errorf("Author and comment are required to note saga [%s].", transactionId.String())
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			if _, err := NewProcessor(d.Logger(), d.Context()).AddNote(transactionId, im.Author, im.Comment); err != nil {
+				d.Logger().WithError(err).Debugf("Unable to locate saga [%s].", transactionId.String())
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+}
+
+// abortSagaHandler returns a handler for the POST /sagas/{transactionId}/abort endpoint
+func abortSagaHandler(d *rest.HandlerDependency, c *rest.HandlerContext, im AbortRestModel) http.HandlerFunc {
+	return rest.ParseTransactionId(d.Logger(), func(transactionId uuid.UUID) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if im.Operator == "" {
+				d.Logger().Errorf("Operator is required to abort saga [%s].", transactionId.String())
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			p := NewProcessor(d.Logger(), d.Context())
+			if _, err := p.GetById(transactionId); err != nil {
+				d.Logger().WithError(err).Debugf("Unable to locate saga [%s].", transactionId.String())
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			err := p.Abort(transactionId, im.Operator, im.Reason)
+			if errors.Is(err, ErrSagaNotAbortable) {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			if err != nil {
+				d.Logger().WithError(err).Errorf("Unable to abort saga [%s].", transactionId.String())
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+}
+
+// getAllSubscriptionsHandler returns a handler for the GET /subscriptions endpoint
+func getAllSubscriptionsHandler(d *rest.HandlerDependency, c *rest.HandlerContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		subs := GetSubscriptionRegistry().GetAll(tenant.MustFromContext(d.Context()).Id())
+		rms, err := model.SliceMap(TransformSubscription)(model.FixedProvider(subs))(model.ParallelMap())()
+		if err != nil {
+			d.Logger().WithError(err).Error("Failed to retrieve subscriptions")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		query := r.URL.Query()
+		queryParams := jsonapi.ParseQueryFields(&query)
+		server.MarshalResponse[[]SubscriptionRestModel](d.Logger())(w)(c.ServerInformation())(queryParams)(rms)
+	}
+}
+
+// createSubscriptionHandler returns a handler for the POST /subscriptions endpoint
+func createSubscriptionHandler(d *rest.HandlerDependency, c *rest.HandlerContext, im SubscriptionRestModel) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		sub := ExtractSubscription(im)
+		if err := sub.Validate(); err != nil {
+			d.Logger().WithError(err).Errorf("Unable to register subscription of [%s].", sub.InitiatedBy)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		sub = GetSubscriptionRegistry().Add(tenant.MustFromContext(d.Context()).Id(), sub)
+		d.Logger().Debugf("Registered subscription [%s] of [%s].", sub.Id.String(), sub.InitiatedBy)
+
+		rm, err := TransformSubscription(sub)
+		if err != nil {
+			d.Logger().WithError(err).Error("Failed to transform subscription")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		query := r.URL.Query()
+		queryParams := jsonapi.ParseQueryFields(&query)
+		server.MarshalResponse[SubscriptionRestModel](d.Logger())(w)(c.ServerInformation())(queryParams)(rm)
+	}
+}
+
+// deleteSubscriptionHandler returns a handler for the DELETE /subscriptions/{subscriptionId} endpoint
+func deleteSubscriptionHandler(d *rest.HandlerDependency, c *rest.HandlerContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		subscriptionId, err := uuid.Parse(mux.Vars(r)["subscriptionId"])
+		if err != nil {
+			d.Logger().WithError(err).Errorf("Unable to properly parse subscriptionId from path.")
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if !GetSubscriptionRegistry().Remove(tenant.MustFromContext(d.Context()).Id(), subscriptionId) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
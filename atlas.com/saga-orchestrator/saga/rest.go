@@ -8,20 +8,40 @@ import (
 
 // RestModel is the JSON:API resource for sagas
 type RestModel struct {
-	TransactionID uuid.UUID       `json:"transactionId"` // Unique ID for the transaction
-	SagaType      Type            `json:"sagaType"`      // Type of the saga (e.g., inventory_transaction)
-	InitiatedBy   string          `json:"initiatedBy"`   // Who initiated the saga (e.g., NPC ID, user)
-	Steps         []StepRestModel `json:"steps"`         // List of steps in the saga
+	TransactionID    uuid.UUID            `json:"transactionId"`              // Unique ID for the transaction
+	SagaType         Type                 `json:"sagaType"`                   // Type of the saga (e.g., inventory_transaction)
+	InitiatedBy      string               `json:"initiatedBy"`                // Who initiated the saga (e.g., NPC ID, user)
+	Labels           map[string]string    `json:"labels,omitempty"`           // Arbitrary labels for querying cohorts of sagas
+	Variables        Variables            `json:"variables,omitempty"`        // Values referenced by step payload templates
+	Steps            []StepRestModel      `json:"steps"`                      // List of steps in the saga
+	RequiresApproval bool                 `json:"requiresApproval,omitempty"` // Whether a second operator must approve the saga before it starts
+	Hold             Hold                 `json:"hold,omitempty"`             // Hold pausing the saga until an operator reviews it, if any
+	HoldReason       string               `json:"holdReason,omitempty"`       // Reason the saga is held
+	Reviews          []Review             `json:"reviews,omitempty"`          // Operator decisions on holds of the saga
+	Notes            []Note               `json:"notes,omitempty"`            // Comments left by operators handling the saga
+	OnComplete       *OnComplete          `json:"onComplete,omitempty"`       // Follow-up saga initiated from a template when the saga completes, if any
+	CoalesceAwards   bool                 `json:"coalesceAwards,omitempty"`   // Whether identical award_asset steps of stackable items are coalesced when the saga is created
+	Receipt          *CompensationReceipt `json:"receipt,omitempty"`          // Receipt of the saga's compensation, once compensated
 }
 
 // StepRestModel is the JSON:API resource for saga steps
 type StepRestModel struct {
-	StepID    string      `json:"stepId"`    // Unique ID for the step
-	Status    Status      `json:"status"`    // Status of the step (e.g., pending, completed, failed)
-	Action    Action      `json:"action"`    // The Action to be taken (e.g., validate_inventory, deduct_inventory)
-	Payload   interface{} `json:"payload"`   // Data required for the action (specific to the action type)
-	CreatedAt string      `json:"createdAt"` // Timestamp of when the step was created
-	UpdatedAt string      `json:"updatedAt"` // Timestamp of the last update to the step
+	StepID    string            `json:"stepId"`              // Unique ID for the step
+	Status    Status            `json:"status"`              // Status of the step (e.g., pending, completed, failed)
+	Action    Action            `json:"action"`              // The Action to be taken (e.g., validate_inventory, deduct_inventory)
+	Payload   interface{}       `json:"payload"`             // Data required for the action (specific to the action type)
+	CreatedAt string            `json:"createdAt"`           // Timestamp of when the step was created
+	UpdatedAt string            `json:"updatedAt"`           // Timestamp of the last update to the step
+	Attempts  []StepAttempt     `json:"attempts,omitempty"`  // History of each dispatch of the step's action
+	OnError   []ErrorHandler    `json:"onError,omitempty"`   // Reactions to specific error codes reported when the step fails
+	Capture   map[string]string `json:"capture,omitempty"`   // Variables set from the event completing the step, by JSONPath into the event
+	Branches  []Branch          `json:"branches,omitempty"`  // Alternative steps, one branch of which is selected to execute once the step completes
+	Branch    string            `json:"branch,omitempty"`    // Name of the branch selected once the step completed, if any
+	Outbox    []OutboxMessage   `json:"outbox,omitempty"`    // Commands of the step remaining to be produced, should producing them have been interrupted
+	DependsOn []string          `json:"dependsOn,omitempty"` // Step IDs of the saga's steps which must complete before the step is dispatched
+	Timeout   uint32            `json:"timeout,omitempty"`   // Seconds the event completing the step may take to arrive, after which the step fails
+
+	PayloadTemplate json.RawMessage `json:"payloadTemplate,omitempty"` // Payload containing template expressions, rendered when the step is dispatched
 }
 
 // GetID returns the resource ID
@@ -55,29 +75,256 @@ func Transform(s Saga) (RestModel, error) {
 			Payload:   step.Payload,
 			CreatedAt: step.CreatedAt.Format(time.RFC3339),
 			UpdatedAt: step.UpdatedAt.Format(time.RFC3339),
+			Attempts:  step.Attempts,
+			OnError:   step.OnError,
+			Capture:   step.Capture,
+			Branches:  step.Branches,
+			Branch:    step.Branch,
+			Outbox:    step.Outbox,
+			DependsOn: step.DependsOn,
+			Timeout:   step.Timeout,
+
+			PayloadTemplate: step.PayloadTemplate,
 		}
 	}
 
 	return RestModel{
-		TransactionID: s.TransactionId,
-		SagaType:      s.SagaType,
-		InitiatedBy:   s.InitiatedBy,
-		Steps:         steps,
+		TransactionID:    s.TransactionId,
+		SagaType:         s.SagaType,
+		InitiatedBy:      s.InitiatedBy,
+		Labels:           s.Labels,
+		Variables:        s.Variables,
+		Steps:            steps,
+		RequiresApproval: s.RequiresApproval,
+		Hold:             s.Hold,
+		HoldReason:       s.HoldReason,
+		Reviews:          s.Reviews,
+		Notes:            s.Notes,
+		OnComplete:       s.OnComplete,
+		CoalesceAwards:   s.CoalesceAwards,
+		Receipt:          s.Receipt,
+	}, nil
+}
+
+// ReviewRestModel is the JSON:API resource for an operator's review of a held saga
+type ReviewRestModel struct {
+	Id       string `json:"-"`                 // Unused, reviews are identified by the saga reviewed
+	Reviewer string `json:"reviewer"`          // Reviewer identifies the operator making the decision
+	Comment  string `json:"comment,omitempty"` // Comment left by the reviewer
+}
+
+// GetID returns the resource ID
+func (r ReviewRestModel) GetID() string {
+	return r.Id
+}
+
+// SetID sets the resource ID
+func (r *ReviewRestModel) SetID(id string) error {
+	r.Id = id
+	return nil
+}
+
+// GetName returns the resource name
+func (r ReviewRestModel) GetName() string {
+	return "reviews"
+}
+
+// ResolutionRestModel is the JSON:API resource for an operator's resolution of a dispute a saga is held pending
+type ResolutionRestModel struct {
+	Id         string `json:"-"`                 // Unused, resolutions are identified by the saga resolved
+	Resolver   string `json:"resolver"`          // Resolver identifies the operator resolving the dispute
+	Resolution string `json:"resolution"`        // Resolution chosen (e.g. release or confiscate)
+	Comment    string `json:"comment,omitempty"` // Comment left by the resolver
+}
+
+// GetID returns the resource ID
+func (r ResolutionRestModel) GetID() string {
+	return r.Id
+}
+
+// SetID sets the resource ID
+func (r *ResolutionRestModel) SetID(id string) error {
+	r.Id = id
+	return nil
+}
+
+// GetName returns the resource name
+func (r ResolutionRestModel) GetName() string {
+	return "resolutions"
+}
+
+// NoteRestModel is the JSON:API resource for an operator's note on a saga
+type NoteRestModel struct {
+	Id      string `json:"-"`       // Unused, notes are identified by the saga noted
+	Author  string `json:"author"`  // Author identifies the operator leaving the note
+	Comment string `json:"comment"` // Comment left by the author
+}
+
+// GetID returns the resource ID
+func (r NoteRestModel) GetID() string {
+	return r.Id
+}
+
+// SetID sets the resource ID
+func (r *NoteRestModel) SetID(id string) error {
+	r.Id = id
+	return nil
+}
+
+// GetName returns the resource name
+func (r NoteRestModel) GetName() string {
+	return "notes"
+}
+
+// AbortRestModel is the JSON:API resource for an operator's abort of a saga
+type AbortRestModel struct {
+	Id       string `json:"-"`                // Unused, aborts are identified by the saga aborted
+	Operator string `json:"operator"`         // Operator identifies the operator aborting the saga
+	Reason   string `json:"reason,omitempty"` // Reason the saga is aborted
+}
+
+// GetID returns the resource ID
+func (r AbortRestModel) GetID() string {
+	return r.Id
+}
+
+// SetID sets the resource ID
+func (r *AbortRestModel) SetID(id string) error {
+	r.Id = id
+	return nil
+}
+
+// GetName returns the resource name
+func (r AbortRestModel) GetName() string {
+	return "aborts"
+}
+
+// SubscriptionRestModel is the JSON:API resource for an initiator's subscription to saga status events
+type SubscriptionRestModel struct {
+	Id          string            `json:"-"`                     // Unique ID of the subscription, assigned when registered
+	InitiatedBy string            `json:"initiatedBy,omitempty"` // Initiator whose sagas match, any when empty
+	SagaTypes   []Type            `json:"sagaTypes,omitempty"`   // Types of sagas which match, any when empty
+	Labels      map[string]string `json:"labels,omitempty"`      // Labels matching sagas must carry
+	Topic       string            `json:"topic,omitempty"`       // Token of the topic events are produced to
+	WebhookUrl  string            `json:"webhookUrl,omitempty"`  // URL events are posted to
+}
+
+// GetID returns the resource ID
+func (r SubscriptionRestModel) GetID() string {
+	return r.Id
+}
+
+// SetID sets the resource ID
+func (r *SubscriptionRestModel) SetID(id string) error {
+	r.Id = id
+	return nil
+}
+
+// GetName returns the resource name
+func (r SubscriptionRestModel) GetName() string {
+	return "subscriptions"
+}
+
+// TransformSubscription converts a subscription to a REST model
+func TransformSubscription(sub Subscription) (SubscriptionRestModel, error) {
+	return SubscriptionRestModel{
+		Id:          sub.Id.String(),
+		InitiatedBy: sub.InitiatedBy,
+		SagaTypes:   sub.SagaTypes,
+		Labels:      sub.Labels,
+		Topic:       sub.Topic,
+		WebhookUrl:  sub.WebhookUrl,
 	}, nil
 }
 
+// ExtractSubscription converts a REST model to a subscription, which is assigned an ID when registered
+func ExtractSubscription(r SubscriptionRestModel) Subscription {
+	return Subscription{
+		InitiatedBy: r.InitiatedBy,
+		SagaTypes:   r.SagaTypes,
+		Labels:      r.Labels,
+		Topic:       r.Topic,
+		WebhookUrl:  r.WebhookUrl,
+	}
+}
+
 // PayloadUnmarshaler is a function type for unmarshaling payloads
 type PayloadUnmarshaler func(interface{}) (any, error)
 
 // payloadUnmarshalers maps action types to their payload unmarshalers
 var payloadUnmarshalers = map[Action]PayloadUnmarshaler{
-	AwardInventory:     unmarshalAwardInventoryPayload,
-	AwardExperience:    unmarshalAwardExperiencePayload,
-	AwardLevel:         unmarshalAwardLevelPayload,
-	AwardMesos:         unmarshalAwardMesosPayload,
-	WarpToRandomPortal: unmarshalWarpToRandomPortalPayload,
-	WarpToPortal:       unmarshalWarpToPortalPayload,
-	DestroyAsset:       unmarshalDestroyAssetPayload,
+	AwardInventory:              unmarshalAwardInventoryPayload,
+	AwardExperience:             unmarshalAwardExperiencePayload,
+	AwardLevel:                  unmarshalAwardLevelPayload,
+	AwardMesos:                  unmarshalAwardMesosPayload,
+	WarpToRandomPortal:          unmarshalWarpToRandomPortalPayload,
+	WarpToPortal:                unmarshalWarpToPortalPayload,
+	DestroyAsset:                unmarshalDestroyAssetPayload,
+	SetQuestTimer:               unmarshalSetQuestTimerPayload,
+	AdjustPopularity:            unmarshalAdjustPopularityPayload,
+	ResolvePrizeTable:           unmarshalResolvePrizeTablePayload,
+	CharacterBuffCleanse:        unmarshalCharacterBuffCleansePayload,
+	ResetSkillCooldowns:         unmarshalResetSkillCooldownsPayload,
+	ModifyInventoryItemPosition: unmarshalModifyInventoryItemPositionPayload,
+	ApplyEquipmentPreset:        unmarshalApplyEquipmentPresetPayload,
+	TransferCharacter:           unmarshalTransferCharacterPayload,
+	VerifyAccountMerge:          unmarshalVerifyAccountMergePayload,
+	RestoreAsset:                unmarshalRestoreAssetPayload,
+	RollbackCharacterToSnapshot: unmarshalRollbackCharacterToSnapshotPayload,
+	RestoreInventorySnapshot:    unmarshalRestoreInventorySnapshotPayload,
+	AdjustNpcShopStock:          unmarshalAdjustNpcShopStockPayload,
+	SetWorldEventFlag:           unmarshalSetWorldEventFlagPayload,
+	ValidateCoupon:              unmarshalValidateCouponPayload,
+	ConsumeCoupon:               unmarshalConsumeCouponPayload,
+	ApplyCharacterExpPenalty:    unmarshalApplyCharacterExpPenaltyPayload,
+	ApplyDurabilityPenalty:      unmarshalApplyDurabilityPenaltyPayload,
+	SetVariable:                 unmarshalSetVariablePayload,
+	HttpRequest:                 unmarshalHttpRequestPayload,
+	CreateAccountCharacterSlot:  unmarshalCreateAccountCharacterSlotPayload,
+	EmitAnalyticsEvent:          unmarshalEmitAnalyticsEventPayload,
+	ModifyAssetExpiration:       unmarshalModifyAssetExpirationPayload,
+	ApplyHammer:                 unmarshalApplyHammerPayload,
+	DeductMesos:                 unmarshalDeductMesosPayload,
+	ForEach:                     unmarshalForEachPayload,
+	VerifyAndConsumeTicket:      unmarshalVerifyAndConsumeTicketPayload,
+	AwaitKillCount:              unmarshalAwaitKillCountPayload,
+	AdjustReactorState:          unmarshalAdjustReactorStatePayload,
+	HitReactor:                  unmarshalHitReactorPayload,
+	UpdateCharacterResource:     unmarshalUpdateCharacterResourcePayload,
+	AwardAssetIf:                unmarshalAwardAssetIfPayload,
+	GrantPremiumTime:            unmarshalGrantPremiumTimePayload,
+	ValidateDivorce:             unmarshalValidateDivorcePayload,
+	DissolveMarriage:            unmarshalDissolveMarriagePayload,
+	SealAsset:                   unmarshalSealAssetPayload,
+	UnsealAsset:                 unmarshalUnsealAssetPayload,
+	ConfiscateAsset:             unmarshalConfiscateAssetPayload,
+	ResolveDispute:              unmarshalResolveDisputePayload,
+	IssueTransportTicket:        unmarshalIssueTransportTicketPayload,
+	ScheduleWarp:                unmarshalScheduleWarpPayload,
+	SpawnEscort:                 unmarshalSpawnEscortPayload,
+	AwaitEscort:                 unmarshalAwaitEscortPayload,
+	UpdateCharacterAlignment:    unmarshalUpdateCharacterAlignmentPayload,
+	ResetInstanceCooldown:       unmarshalResetInstanceCooldownPayload,
+	AuditInventory:              unmarshalAuditInventoryPayload,
+	ApplyTitleBuffOnLogin:       unmarshalApplyTitleBuffOnLoginPayload,
+	GrantMount:                  unmarshalGrantMountPayload,
+	ApplyWorldEventBuff:         unmarshalApplyWorldEventBuffPayload,
+	RemoveWorldEventBuff:        unmarshalRemoveWorldEventBuffPayload,
+	SettleEventCurrency:         unmarshalSettleEventCurrencyPayload,
+	CharacterExperienceLock:     unmarshalCharacterExperienceLockPayload,
+	CharacterExperienceUnlock:   unmarshalCharacterExperienceUnlockPayload,
+	NpcConversationState:        unmarshalNpcConversationStatePayload,
+	EquipAssetByTemplate:        unmarshalEquipAssetByTemplatePayload,
+	AwardPartyExperience:        unmarshalAwardPartyExperiencePayload,
+	ToggleCharacterAbility:      unmarshalToggleCharacterAbilityPayload,
+	GrantStorageCapacity:        unmarshalGrantStorageCapacityPayload,
+	StripEquipment:              unmarshalStripEquipmentPayload,
+	SnapshotCharacter:           unmarshalSnapshotCharacterPayload,
+	RestoreCharacter:            unmarshalRestoreCharacterPayload,
+	AwaitEvent:                  unmarshalAwaitEventPayload,
+	Notify:                      unmarshalNotifyPayload,
+	PetEquip:                    unmarshalPetEquipPayload,
+	PetUnequip:                  unmarshalPetUnequipPayload,
 }
 
 // parseTime parses a time string in RFC3339 format, returning current time if parsing fails
@@ -148,6 +395,266 @@ func unmarshalDestroyAssetPayload(rawPayload interface{}) (any, error) {
 	return unmarshalGenericPayload[DestroyAssetPayload](rawPayload)
 }
 
+func unmarshalSetQuestTimerPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[SetQuestTimerPayload](rawPayload)
+}
+
+func unmarshalAdjustPopularityPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[AdjustPopularityPayload](rawPayload)
+}
+
+func unmarshalResolvePrizeTablePayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[ResolvePrizeTablePayload](rawPayload)
+}
+
+func unmarshalCharacterBuffCleansePayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[CharacterBuffCleansePayload](rawPayload)
+}
+
+func unmarshalResetSkillCooldownsPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[ResetSkillCooldownsPayload](rawPayload)
+}
+
+func unmarshalModifyInventoryItemPositionPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[ModifyInventoryItemPositionPayload](rawPayload)
+}
+
+func unmarshalApplyEquipmentPresetPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[ApplyEquipmentPresetPayload](rawPayload)
+}
+
+func unmarshalTransferCharacterPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[TransferCharacterPayload](rawPayload)
+}
+
+func unmarshalVerifyAccountMergePayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[VerifyAccountMergePayload](rawPayload)
+}
+
+func unmarshalRestoreAssetPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[RestoreAssetPayload](rawPayload)
+}
+
+func unmarshalRollbackCharacterToSnapshotPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[RollbackCharacterToSnapshotPayload](rawPayload)
+}
+
+func unmarshalRestoreInventorySnapshotPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[RestoreInventorySnapshotPayload](rawPayload)
+}
+
+func unmarshalAdjustNpcShopStockPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[AdjustNpcShopStockPayload](rawPayload)
+}
+
+func unmarshalSetWorldEventFlagPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[SetWorldEventFlagPayload](rawPayload)
+}
+
+func unmarshalValidateCouponPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[ValidateCouponPayload](rawPayload)
+}
+
+func unmarshalConsumeCouponPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[ConsumeCouponPayload](rawPayload)
+}
+
+func unmarshalApplyCharacterExpPenaltyPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[ApplyCharacterExpPenaltyPayload](rawPayload)
+}
+
+func unmarshalApplyDurabilityPenaltyPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[ApplyDurabilityPenaltyPayload](rawPayload)
+}
+
+func unmarshalSetVariablePayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[SetVariablePayload](rawPayload)
+}
+
+func unmarshalHttpRequestPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[HttpRequestPayload](rawPayload)
+}
+
+func unmarshalCreateAccountCharacterSlotPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[CreateAccountCharacterSlotPayload](rawPayload)
+}
+
+func unmarshalEmitAnalyticsEventPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[EmitAnalyticsEventPayload](rawPayload)
+}
+
+func unmarshalModifyAssetExpirationPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[ModifyAssetExpirationPayload](rawPayload)
+}
+
+func unmarshalApplyHammerPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[ApplyHammerPayload](rawPayload)
+}
+
+func unmarshalDeductMesosPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[DeductMesosPayload](rawPayload)
+}
+
+func unmarshalForEachPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[ForEachPayload](rawPayload)
+}
+
+func unmarshalVerifyAndConsumeTicketPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[VerifyAndConsumeTicketPayload](rawPayload)
+}
+
+func unmarshalAwaitKillCountPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[AwaitKillCountPayload](rawPayload)
+}
+
+func unmarshalAdjustReactorStatePayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[AdjustReactorStatePayload](rawPayload)
+}
+
+func unmarshalHitReactorPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[HitReactorPayload](rawPayload)
+}
+
+func unmarshalUpdateCharacterResourcePayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[UpdateCharacterResourcePayload](rawPayload)
+}
+
+func unmarshalAwardAssetIfPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[AwardAssetIfPayload](rawPayload)
+}
+
+func unmarshalGrantPremiumTimePayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[GrantPremiumTimePayload](rawPayload)
+}
+
+func unmarshalValidateDivorcePayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[ValidateDivorcePayload](rawPayload)
+}
+
+func unmarshalDissolveMarriagePayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[DissolveMarriagePayload](rawPayload)
+}
+
+func unmarshalSealAssetPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[SealAssetPayload](rawPayload)
+}
+
+func unmarshalUnsealAssetPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[UnsealAssetPayload](rawPayload)
+}
+
+func unmarshalConfiscateAssetPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[ConfiscateAssetPayload](rawPayload)
+}
+
+func unmarshalResolveDisputePayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[ResolveDisputePayload](rawPayload)
+}
+
+func unmarshalIssueTransportTicketPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[IssueTransportTicketPayload](rawPayload)
+}
+
+func unmarshalScheduleWarpPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[ScheduleWarpPayload](rawPayload)
+}
+
+func unmarshalSpawnEscortPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[SpawnEscortPayload](rawPayload)
+}
+
+func unmarshalAwaitEscortPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[AwaitEscortPayload](rawPayload)
+}
+
+func unmarshalUpdateCharacterAlignmentPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[UpdateCharacterAlignmentPayload](rawPayload)
+}
+
+func unmarshalResetInstanceCooldownPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[ResetInstanceCooldownPayload](rawPayload)
+}
+
+func unmarshalAuditInventoryPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[AuditInventoryPayload](rawPayload)
+}
+
+func unmarshalApplyTitleBuffOnLoginPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[ApplyTitleBuffOnLoginPayload](rawPayload)
+}
+
+func unmarshalGrantMountPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[GrantMountPayload](rawPayload)
+}
+
+func unmarshalApplyWorldEventBuffPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[ApplyWorldEventBuffPayload](rawPayload)
+}
+
+func unmarshalRemoveWorldEventBuffPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[RemoveWorldEventBuffPayload](rawPayload)
+}
+
+func unmarshalSettleEventCurrencyPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[SettleEventCurrencyPayload](rawPayload)
+}
+
+func unmarshalCharacterExperienceLockPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[CharacterExperienceLockPayload](rawPayload)
+}
+
+func unmarshalCharacterExperienceUnlockPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[CharacterExperienceUnlockPayload](rawPayload)
+}
+
+func unmarshalNpcConversationStatePayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[NpcConversationStatePayload](rawPayload)
+}
+
+func unmarshalEquipAssetByTemplatePayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[EquipAssetByTemplatePayload](rawPayload)
+}
+
+func unmarshalAwardPartyExperiencePayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[AwardPartyExperiencePayload](rawPayload)
+}
+
+func unmarshalToggleCharacterAbilityPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[ToggleCharacterAbilityPayload](rawPayload)
+}
+
+func unmarshalGrantStorageCapacityPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[GrantStorageCapacityPayload](rawPayload)
+}
+
+func unmarshalStripEquipmentPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[StripEquipmentPayload](rawPayload)
+}
+
+func unmarshalSnapshotCharacterPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[SnapshotCharacterPayload](rawPayload)
+}
+
+func unmarshalRestoreCharacterPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[RestoreCharacterPayload](rawPayload)
+}
+
+func unmarshalAwaitEventPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[AwaitEventPayload](rawPayload)
+}
+
+func unmarshalNotifyPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[NotifyPayload](rawPayload)
+}
+
+func unmarshalPetEquipPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[PetEquipPayload](rawPayload)
+}
+
+func unmarshalPetUnequipPayload(rawPayload interface{}) (any, error) {
+	return unmarshalGenericPayload[PetUnequipPayload](rawPayload)
+}
+
 // Extract converts a REST model to a domain model
 func Extract(r RestModel) (Saga, error) {
 	steps := make([]Step[any], len(r.Steps))
@@ -156,10 +663,16 @@ func Extract(r RestModel) (Saga, error) {
 		createdAt := parseTime(step.CreatedAt)
 		updatedAt := parseTime(step.UpdatedAt)
 
-		// Unmarshal payload based on action type
-		payload, err := unmarshalPayload(step.Action, step.Payload)
-		if err != nil {
-			return Saga{}, err
+		// Payloads containing template expressions are typed once rendered, when the step is dispatched
+		var payload any
+		template := step.PayloadTemplate
+		if raw, err := json.Marshal(step.Payload); err == nil && step.Action != ForEach && IsPayloadTemplate(raw) {
+			template = raw
+		} else {
+			// Unmarshal payload based on action type
+			if payload, err = unmarshalPayload(step.Action, step.Payload); err != nil {
+				return Saga{}, err
+			}
 		}
 
 		steps[i] = Step[any]{
@@ -169,13 +682,28 @@ func Extract(r RestModel) (Saga, error) {
 			CreatedAt: createdAt,
 			UpdatedAt: updatedAt,
 			Payload:   payload,
+			Attempts:  step.Attempts,
+			OnError:   step.OnError,
+			Capture:   step.Capture,
+			Branches:  step.Branches,
+			Branch:    step.Branch,
+			Outbox:    step.Outbox,
+			DependsOn: step.DependsOn,
+			Timeout:   step.Timeout,
+
+			PayloadTemplate: template,
 		}
 	}
 
 	return Saga{
-		TransactionId: r.TransactionID,
-		SagaType:      r.SagaType,
-		InitiatedBy:   r.InitiatedBy,
-		Steps:         steps,
+		TransactionId:    r.TransactionID,
+		SagaType:         r.SagaType,
+		InitiatedBy:      r.InitiatedBy,
+		Labels:           r.Labels,
+		Variables:        r.Variables,
+		Steps:            steps,
+		RequiresApproval: r.RequiresApproval,
+		OnComplete:       r.OnComplete,
+		CoalesceAwards:   r.CoalesceAwards,
 	}, nil
 }
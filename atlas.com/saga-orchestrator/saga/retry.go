@@ -0,0 +1,379 @@
+package saga
+
+import (
+	"atlas-saga-orchestrator/kafka/message"
+	"atlas-saga-orchestrator/kafka/message/saga"
+	"atlas-saga-orchestrator/metrics"
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	tenant "github.com/Chronicle20/atlas-tenant"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrorCodeDispatchFailed is the error code recorded against a step attempt whose commands could not be produced. Once
+// the step exhausts its redeliveries, it fails with this error code, so that its error handlers may declare a fallback.
+const ErrorCodeDispatchFailed = "DISPATCH_FAILED"
+
+// RoleRedeliveryScheduler is the role of the replica redelivering parked steps once due. Parked steps are found across
+// replicas through the archive, so the scheduler runs on only one.
+const RoleRedeliveryScheduler = "redelivery_scheduler"
+
+// RetryConfig configures the redelivery of steps whose commands could not be produced
+type RetryConfig struct {
+	BaseDelay   time.Duration // BaseDelay before the first redelivery, doubling with each subsequent redelivery
+	MaxDelay    time.Duration // MaxDelay between redeliveries
+	MaxAttempts int           // MaxAttempts to dispatch a step before it fails. When 0, steps are not redelivered.
+}
+
+// DefaultRetryConfig is the retry configuration used when none is configured
+var DefaultRetryConfig = RetryConfig{BaseDelay: time.Second, MaxDelay: time.Minute, MaxAttempts: 10}
+
+// RetryConfigFromEnv loads the retry configuration from the environment
+func RetryConfigFromEnv() (RetryConfig, error) {
+	c := DefaultRetryConfig
+	if v, ok := os.LookupEnv("SAGA_DISPATCH_RETRY_BASE_DELAY"); ok && v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return RetryConfig{}, fmt.Errorf("invalid SAGA_DISPATCH_RETRY_BASE_DELAY '%s'", v)
+		}
+		c.BaseDelay = d
+	}
+	if v, ok := os.LookupEnv("SAGA_DISPATCH_RETRY_MAX_DELAY"); ok && v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return RetryConfig{}, fmt.Errorf("invalid SAGA_DISPATCH_RETRY_MAX_DELAY '%s'", v)
+		}
+		c.MaxDelay = d
+	}
+	if v, ok := os.LookupEnv("SAGA_DISPATCH_RETRY_MAX_ATTEMPTS"); ok && v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return RetryConfig{}, fmt.Errorf("invalid SAGA_DISPATCH_RETRY_MAX_ATTEMPTS '%s'", v)
+		}
+		c.MaxAttempts = n
+	}
+	if c.MaxDelay < c.BaseDelay {
+		return RetryConfig{}, fmt.Errorf("SAGA_DISPATCH_RETRY_MAX_DELAY must not be less than SAGA_DISPATCH_RETRY_BASE_DELAY")
+	}
+	return c, nil
+}
+
+// Singleton retry configuration
+var retryConfig = DefaultRetryConfig
+
+// InitRetryConfig replaces the singleton retry configuration
+func InitRetryConfig(config RetryConfig) {
+	retryConfig = config
+}
+
+// GetRetryConfig returns the singleton retry configuration
+func GetRetryConfig() RetryConfig {
+	return retryConfig
+}
+
+// Delay returns how long to wait before redelivering a step whose dispatch failed the given number of times. The delay
+// doubles with each failure up to the maximum, and is jittered over its upper half, so steps parked together by a
+// Kafka outage are not redelivered in lockstep once it recovers.
+func (c RetryConfig) Delay(failures int) time.Duration {
+	d := c.BaseDelay
+	for i := 1; i < failures && d < c.MaxDelay; i++ {
+		d *= 2
+	}
+	if d > c.MaxDelay {
+		d = c.MaxDelay
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int64N(int64(d-half)+1))
+}
+
+// RetryEntry is a step parked for redelivery
+type RetryEntry struct {
+	Tenant        tenant.Model
+	TransactionId uuid.UUID
+	InitiatedBy   string
+	StepId        string
+	DueAt         time.Time
+}
+
+// RetryQueue is an interface for parking steps whose commands could not be produced until they are redelivered
+type RetryQueue interface {
+	// Park parks a step for redelivery, replacing any entry already parked for the saga
+	Park(entry RetryEntry)
+
+	// Due removes and returns the entries due for redelivery at the given time
+	Due(now time.Time) []RetryEntry
+
+	// Remove removes the entry parked for a saga, returning whether there was one
+	Remove(tenantId uuid.UUID, transactionId uuid.UUID) bool
+
+	// Depth returns the number of steps parked for each tenant
+	Depth() map[uuid.UUID]int
+
+	// DepthByInitiator returns the number of steps parked for each tenant, by the initiator of their sagas
+	DepthByInitiator() map[uuid.UUID]map[string]int
+}
+
+// InMemoryRetryQueue is an in-memory implementation of the RetryQueue interface. The attempts of parked steps, and when
+// they are redelivered, are recorded on the steps themselves, so are retained wherever the saga is.
+type InMemoryRetryQueue struct {
+	// entries is a map of tenant IDs to maps of transaction IDs to parked steps
+	entries map[uuid.UUID]map[uuid.UUID]RetryEntry
+
+	// mutex is used to synchronize access to the queue
+	mutex sync.Mutex
+}
+
+// Singleton instance of the retry queue
+var retryQueueInstance *InMemoryRetryQueue
+var retryQueueOnce sync.Once
+
+// GetRetryQueue returns the singleton instance of the retry queue
+func GetRetryQueue() RetryQueue {
+	retryQueueOnce.Do(func() {
+		retryQueueInstance = &InMemoryRetryQueue{
+			entries: make(map[uuid.UUID]map[uuid.UUID]RetryEntry),
+		}
+		metrics.GetRegistry().RegisterGauge("saga_dispatch_retry_queue_depth", "Number of saga steps parked for redelivery after their commands could not be produced.", func() []metrics.Sample {
+			samples := make([]metrics.Sample, 0)
+			for tenantId, initiators := range retryQueueInstance.DepthByInitiator() {
+				for initiatedBy, depth := range initiators {
+					samples = append(samples, metrics.Sample{Labels: map[string]string{"tenant_id": tenantId.String(), "initiated_by": initiatedBy}, Value: float64(depth)})
+				}
+			}
+			return samples
+		})
+	})
+	return retryQueueInstance
+}
+
+// ResetRetryQueue resets the singleton retry queue for testing
+func ResetRetryQueue() {
+	q := GetRetryQueue().(*InMemoryRetryQueue)
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	q.entries = make(map[uuid.UUID]map[uuid.UUID]RetryEntry)
+}
+
+// Park parks a step for redelivery
+func (q *InMemoryRetryQueue) Park(entry RetryEntry) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	tenantId := entry.Tenant.Id()
+	if _, ok := q.entries[tenantId]; !ok {
+		q.entries[tenantId] = make(map[uuid.UUID]RetryEntry)
+	}
+	q.entries[tenantId][entry.TransactionId] = entry
+}
+
+// Due removes and returns the entries due for redelivery, earliest first
+func (q *InMemoryRetryQueue) Due(now time.Time) []RetryEntry {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	due := make([]RetryEntry, 0)
+	for tenantId, sagas := range q.entries {
+		for transactionId, e := range sagas {
+			if e.DueAt.After(now) {
+				continue
+			}
+			due = append(due, e)
+			delete(sagas, transactionId)
+		}
+		if len(sagas) == 0 {
+			delete(q.entries, tenantId)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].DueAt.Before(due[j].DueAt) })
+	return due
+}
+
+// Remove removes the entry parked for a saga
+func (q *InMemoryRetryQueue) Remove(tenantId uuid.UUID, transactionId uuid.UUID) bool {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	sagas, ok := q.entries[tenantId]
+	if !ok {
+		return false
+	}
+	if _, ok = sagas[transactionId]; !ok {
+		return false
+	}
+	delete(sagas, transactionId)
+	if len(sagas) == 0 {
+		delete(q.entries, tenantId)
+	}
+	return true
+}
+
+// Depth returns the number of steps parked for each tenant
+func (q *InMemoryRetryQueue) Depth() map[uuid.UUID]int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	r := make(map[uuid.UUID]int, len(q.entries))
+	for tenantId, sagas := range q.entries {
+		r[tenantId] = len(sagas)
+	}
+	return r
+}
+
+// DepthByInitiator returns the number of steps parked for each tenant, by the initiator of their sagas
+func (q *InMemoryRetryQueue) DepthByInitiator() map[uuid.UUID]map[string]int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	r := make(map[uuid.UUID]map[string]int, len(q.entries))
+	for tenantId, sagas := range q.entries {
+		r[tenantId] = make(map[string]int)
+		for _, e := range sagas {
+			r[tenantId][e.InitiatedBy]++
+		}
+	}
+	return r
+}
+
+// dispatchFailures returns the number of consecutive attempts of the step, preceding the attempt being dispatched, whose
+// commands could not be produced
+func dispatchFailures(st Step[any]) int {
+	n := 0
+	for i := len(st.Attempts) - 2; i >= 0 && st.Attempts[i].ErrorCode == ErrorCodeDispatchFailed; i-- {
+		n++
+	}
+	return n
+}
+
+// parkStep records that the current step's commands could not be produced against its latest attempt, and parks it for
+// a jittered redelivery. Returns false, having recorded nothing, when the step has exhausted its attempts.
+func (p *ProcessorImpl) parkStep(s Saga, st Step[any], cause error) bool {
+	c := GetRetryConfig()
+	failures := dispatchFailures(st) + 1
+	if failures >= c.MaxAttempts {
+		return false
+	}
+	// The handler may have recorded state on the saga since it was dispatched
+	if cur, err := p.GetById(s.TransactionId); err == nil {
+		s = cur
+	}
+	idx := s.FindStepIndex(st.StepId)
+	if idx == -1 || len(s.Steps[idx].Attempts) == 0 {
+		return false
+	}
+
+	dueAt := time.Now().Add(c.Delay(failures))
+	if err := s.RecordStepAttemptError(idx, ErrorCodeDispatchFailed, cause.Error()); err != nil {
+		return false
+	}
+	s.Steps[idx].Attempts[len(s.Steps[idx].Attempts)-1].RetryAt = &dueAt
+	GetCache().Put(p.t.Id(), s)
+	GetRetryQueue().Park(RetryEntry{Tenant: p.t, TransactionId: s.TransactionId, InitiatedBy: s.InitiatedBy, StepId: st.StepId, DueAt: dueAt})
+
+	p.l.WithFields(logrus.Fields{
+		"transaction_id": s.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        st.StepId,
+		"tenant_id":      p.t.Id().String(),
+	}).WithError(cause).Warnf("Unable to dispatch saga step. Redelivering after [%d] failed attempts at [%s].", failures, dueAt.Format(time.RFC3339))
+	return true
+}
+
+// handleDispatchError parks a step whose commands could not be produced, or whose dispatch could not be persisted, for
+// redelivery, or, once it exhausts its attempts, fails it. Returns whether the step was parked.
+func (p *ProcessorImpl) handleDispatchError(s Saga, st Step[any], err error) bool {
+	if !errors.Is(err, message.ErrProduce) && !errors.Is(err, ErrPersist) {
+		return false
+	}
+	if p.parkStep(s, st, err) {
+		return true
+	}
+	_ = p.StepFailed(s.TransactionId, ErrorCodeDispatchFailed, err.Error())
+	return false
+}
+
+// redeliver dispatches a parked step again. Steps which have since progressed, or sagas which are compensating or no
+// longer exist, are left alone.
+func redeliver(l logrus.FieldLogger, p Processor, e RetryEntry) {
+	s, err := p.GetById(e.TransactionId)
+	if err != nil {
+		return
+	}
+	st, ok := s.GetCurrentStep()
+	if !ok || s.Failing() || st.StepId != e.StepId {
+		return
+	}
+
+	fl := l.WithFields(logrus.Fields{
+		"transaction_id": e.TransactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        e.StepId,
+		"tenant_id":      e.Tenant.Id().String(),
+	})
+	fl.Debug("Redelivering saga step.")
+	if err = p.Step(e.TransactionId); err != nil {
+		fl.WithError(err).Error("Unable to redeliver saga step.")
+	}
+}
+
+// redeliveryDue returns the current step of the saga should it be parked for a redelivery which is due
+func redeliveryDue(s Saga, now time.Time) (Step[any], bool) {
+	if s.Failing() {
+		return Step[any]{}, false
+	}
+	st, ok := s.GetCurrentStep()
+	if !ok || len(st.Attempts) == 0 {
+		return Step[any]{}, false
+	}
+	a := st.Attempts[len(st.Attempts)-1]
+	if a.RetryAt == nil || now.Before(*a.RetryAt) {
+		return Step[any]{}, false
+	}
+	return st, true
+}
+
+// redeliverDue redelivers the parked step of a saga owned by this replica, as found due by the replica leading the
+// redelivery scheduler. Steps which are no longer parked, or not yet due, are left alone.
+func redeliverDue(l logrus.FieldLogger, p Processor, t tenant.Model, transactionId uuid.UUID, stepId string, now time.Time) {
+	s, err := p.GetById(transactionId)
+	if err != nil {
+		return
+	}
+	st, ok := redeliveryDue(s, now)
+	if !ok || st.StepId != stepId {
+		return
+	}
+	GetRetryQueue().Remove(t.Id(), transactionId)
+	redeliver(l, p, RetryEntry{Tenant: t, TransactionId: transactionId, InitiatedBy: s.InitiatedBy, StepId: stepId, DueAt: now})
+}
+
+// RetryTask redelivers parked steps once they are due. Leading several replicas, it redelivers those it parked, and has
+// the others redeliver theirs as they fall due.
+type RetryTask struct {
+	l        logrus.FieldLogger
+	interval time.Duration
+}
+
+// NewRetryTask creates a task redelivering parked steps, checking for those due at the interval
+func NewRetryTask(l logrus.FieldLogger, interval time.Duration) *RetryTask {
+	// Initialize the queue, so its depth is reported before any step is parked
+	GetRetryQueue()
+	return &RetryTask{l: l, interval: interval}
+}
+
+func (t *RetryTask) Run() {
+	now := time.Now()
+	for _, e := range GetRetryQueue().Due(now) {
+		redeliver(t.l, NewProcessor(t.l, tenant.WithContext(context.Background(), e.Tenant)), e)
+	}
+	if _, err := forwardDue(t.l, context.Background(), now, saga.StepDueTypeRedelivery, redeliveryDue); err != nil {
+		t.l.WithError(err).Error("Unable to find saga steps due for redelivery.")
+	}
+}
+
+func (t *RetryTask) SleepTime() time.Duration {
+	return t.interval
+}
@@ -0,0 +1,164 @@
+package saga
+
+import (
+	"atlas-saga-orchestrator/character/mock"
+	"atlas-saga-orchestrator/kafka/message"
+	"errors"
+	"fmt"
+	"github.com/Chronicle20/atlas-constants/channel"
+	"github.com/Chronicle20/atlas-constants/world"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+// TestRetryConfigFromEnv tests loading the dispatch retry configuration from the environment
+func TestRetryConfigFromEnv(t *testing.T) {
+	c, err := RetryConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, DefaultRetryConfig, c)
+
+	t.Setenv("SAGA_DISPATCH_RETRY_BASE_DELAY", "500ms")
+	t.Setenv("SAGA_DISPATCH_RETRY_MAX_DELAY", "30s")
+	t.Setenv("SAGA_DISPATCH_RETRY_MAX_ATTEMPTS", "5")
+	c, err = RetryConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, RetryConfig{BaseDelay: 500 * time.Millisecond, MaxDelay: 30 * time.Second, MaxAttempts: 5}, c)
+
+	for k, v := range map[string]string{
+		"SAGA_DISPATCH_RETRY_BASE_DELAY":   "soon",
+		"SAGA_DISPATCH_RETRY_MAX_DELAY":    "100ms",
+		"SAGA_DISPATCH_RETRY_MAX_ATTEMPTS": "-1",
+	} {
+		t.Run(k, func(t *testing.T) {
+			t.Setenv(k, v)
+			_, err := RetryConfigFromEnv()
+			assert.Error(t, err)
+		})
+	}
+}
+
+// TestRetryConfigDelay tests that redelivery delays double with each failure up to the maximum, jittered over their
+// upper half
+func TestRetryConfigDelay(t *testing.T) {
+	c := RetryConfig{BaseDelay: time.Second, MaxDelay: 10 * time.Second, MaxAttempts: 10}
+	for failures, expected := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 8 * time.Second, 5: 10 * time.Second, 20: 10 * time.Second} {
+		for i := 0; i < 20; i++ {
+			d := c.Delay(failures)
+			assert.GreaterOrEqual(t, d, expected/2, failures)
+			assert.LessOrEqual(t, d, expected, failures)
+		}
+	}
+}
+
+// TestRetryQueue tests parking and releasing steps due for redelivery
+func TestRetryQueue(t *testing.T) {
+	defer ResetRetryQueue()
+	te, _ := setupContext()
+	now := time.Now()
+	later := RetryEntry{Tenant: te, TransactionId: uuid.New(), StepId: "a", DueAt: now.Add(time.Minute)}
+	soon := RetryEntry{Tenant: te, TransactionId: uuid.New(), StepId: "b", DueAt: now.Add(time.Second)}
+	sooner := RetryEntry{Tenant: te, TransactionId: uuid.New(), StepId: "c", DueAt: now}
+
+	q := GetRetryQueue()
+	q.Park(later)
+	q.Park(soon)
+	q.Park(sooner)
+	assert.Equal(t, 3, q.Depth()[te.Id()])
+
+	assert.Equal(t, []RetryEntry{sooner, soon}, q.Due(now.Add(time.Second)))
+	assert.Equal(t, 1, q.Depth()[te.Id()])
+	assert.True(t, q.Remove(te.Id(), later.TransactionId))
+	assert.False(t, q.Remove(te.Id(), later.TransactionId))
+	assert.Empty(t, q.Depth())
+}
+
+// TestDispatchRetry tests that a step whose commands cannot be produced is parked and redelivered, rather than failing
+// the saga, until it exhausts its attempts
+func TestDispatchRetry(t *testing.T) {
+	te, ctx := setupContext()
+	defer ResetRetryQueue()
+	defer InitRetryConfig(DefaultRetryConfig)
+	InitRetryConfig(RetryConfig{BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, MaxAttempts: 3})
+
+	unavailable := 0
+	charP := &mock.ProcessorMock{
+		AwardMesosAndEmitFunc: func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, actorId uint32, actorType string, amount int32) error {
+			if unavailable > 0 {
+				unavailable--
+				return fmt.Errorf("%w to [%s]: %w", message.ErrProduce, "COMMAND_TOPIC_CHARACTER", errors.New("leader not available"))
+			}
+			return nil
+		},
+	}
+	processor, _ := setupTestProcessor(ctx, charP, nil)
+	build := func() Saga {
+		return NewBuilder().
+			SetSagaType(QuestReward).
+			AddStep("mesos", Pending, AwardMesos, AwardMesosPayload{CharacterId: 12345, ActorType: "NPC", Amount: 1000}).
+			Build()
+	}
+	redeliverDue := func() {
+		time.Sleep(5 * time.Millisecond)
+		for _, e := range GetRetryQueue().Due(time.Now()) {
+			redeliver(processor.(*ProcessorImpl).l, processor, e)
+		}
+	}
+
+	t.Run("step redelivered once produced", func(t *testing.T) {
+		unavailable = 2
+		s := build()
+		require.NoError(t, processor.Put(s))
+		defer GetCache().Remove(te.Id(), s.TransactionId)
+
+		s, err := processor.GetById(s.TransactionId)
+		require.NoError(t, err)
+		require.Len(t, s.Steps[0].Attempts, 1)
+		assert.Equal(t, ErrorCodeDispatchFailed, s.Steps[0].Attempts[0].ErrorCode)
+		assert.Contains(t, s.Steps[0].Attempts[0].ErrorMessage, "leader not available")
+		assert.NotNil(t, s.Steps[0].Attempts[0].RetryAt)
+		assert.Equal(t, 1, GetRetryQueue().Depth()[te.Id()])
+
+		redeliverDue()
+		redeliverDue()
+		s, err = processor.GetById(s.TransactionId)
+		require.NoError(t, err)
+		require.Len(t, s.Steps[0].Attempts, 3)
+		assert.Empty(t, s.Steps[0].Attempts[2].ErrorCode)
+		assert.Nil(t, s.Steps[0].Attempts[2].RetryAt)
+		assert.Equal(t, Pending, s.Steps[0].Status)
+		assert.Empty(t, GetRetryQueue().Depth())
+	})
+
+	t.Run("step fails once attempts are exhausted", func(t *testing.T) {
+		unavailable = 3
+		s := build()
+		done, unsubscribe := GetNotifier().Subscribe(te.Id(), s.TransactionId)
+		defer unsubscribe()
+		require.NoError(t, processor.Put(s))
+		defer GetCache().Remove(te.Id(), s.TransactionId)
+
+		redeliverDue()
+		redeliverDue()
+		select {
+		case s = <-done:
+		case <-time.After(time.Second):
+			t.Fatal("saga did not fail")
+		}
+		require.Len(t, s.Steps[0].Attempts, 3)
+		assert.Equal(t, ErrorCodeDispatchFailed, s.Steps[0].Attempts[2].ErrorCode)
+		assert.Empty(t, GetRetryQueue().Depth())
+	})
+
+	t.Run("business errors are not redelivered", func(t *testing.T) {
+		charP.AwardMesosAndEmitFunc = func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, actorId uint32, actorType string, amount int32) error {
+			return errors.New("character not found")
+		}
+		s := build()
+		assert.Error(t, processor.Put(s))
+		defer GetCache().Remove(te.Id(), s.TransactionId)
+		assert.Empty(t, GetRetryQueue().Depth())
+	})
+}
@@ -0,0 +1,129 @@
+package saga
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// CurrentSchemaVersion is the version of the schema sagas are persisted with. Sagas persisted before schema versions
+// were recorded are version 1.
+const CurrentSchemaVersion = 2
+
+// ErrUnsupportedSchemaVersion is returned when a persisted saga was serialized with a schema version newer than this
+// orchestrator, such as by an upgraded replica during a rolling upgrade
+var ErrUnsupportedSchemaVersion = errors.New("unsupported saga schema version")
+
+// Migration migrates the document of a persisted saga from the schema version it is registered for to the next
+type Migration func(doc map[string]any) error
+
+// migrations by the schema version they migrate from. Changes to how sagas are serialized which older documents would
+// not load correctly with increment CurrentSchemaVersion, and register the migration of the previous version here.
+var migrations = map[int]Migration{
+	1: backfillStepAttempts,
+}
+
+// Serialize serializes a saga for persistence, such as by an archive, recording the schema version it was serialized
+// with
+func Serialize(s Saga) ([]byte, error) {
+	type Alias Saga
+	return json.Marshal(struct {
+		SchemaVersion int `json:"schemaVersion"`
+		Alias
+	}{SchemaVersion: CurrentSchemaVersion, Alias: Alias(s)})
+}
+
+// Deserialize deserializes a persisted saga, migrating it from the schema version it was serialized with to the
+// current
+func Deserialize(data []byte) (Saga, error) {
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return Saga{}, err
+	}
+
+	version := 1
+	if v, ok := doc["schemaVersion"]; ok {
+		f, ok := v.(float64)
+		if !ok || f < 1 || f != float64(int(f)) {
+			return Saga{}, fmt.Errorf("%w: %v", ErrUnsupportedSchemaVersion, v)
+		}
+		version = int(f)
+	}
+	if version > CurrentSchemaVersion {
+		return Saga{}, fmt.Errorf("%w: %d is newer than %d", ErrUnsupportedSchemaVersion, version, CurrentSchemaVersion)
+	}
+	delete(doc, "schemaVersion")
+
+	for ; version < CurrentSchemaVersion; version++ {
+		m, ok := migrations[version]
+		if !ok {
+			return Saga{}, fmt.Errorf("%w: no migration from %d", ErrUnsupportedSchemaVersion, version)
+		}
+		if err := m(doc); err != nil {
+			return Saga{}, fmt.Errorf("unable to migrate saga from schema version %d: %w", version, err)
+		}
+	}
+
+	bs, err := json.Marshal(doc)
+	if err != nil {
+		return Saga{}, err
+	}
+	var s Saga
+	if err = json.Unmarshal(bs, &s); err != nil {
+		return Saga{}, err
+	}
+	return s, nil
+}
+
+// backfillStepAttempts migrates sagas persisted before the attempts of steps were recorded (version 1). Steps which
+// have completed or failed, and the current step, which was dispatched when it became current, are given the attempt
+// which dispatched them, so failure events reported against them are recorded rather than rejected. Sagas recording
+// attempts, or held before their first step was dispatched, are left as they are.
+func backfillStepAttempts(doc map[string]any) error {
+	steps, ok := doc["steps"].([]any)
+	if !ok {
+		return nil
+	}
+	if h, _ := doc["hold"].(string); h != "" {
+		return nil
+	}
+	// A failing saga's steps after the failed step were never dispatched
+	failing := false
+	for _, v := range steps {
+		st, ok := v.(map[string]any)
+		if !ok {
+			return errors.New("step is not an object")
+		}
+		if as, ok := st["attempts"].([]any); ok && len(as) > 0 {
+			return nil
+		}
+		if st["status"] == string(Failed) {
+			failing = true
+		}
+	}
+
+	for _, v := range steps {
+		st := v.(map[string]any)
+		status, _ := st["status"].(string)
+		if status == string(Pending) && failing {
+			break
+		}
+		stepId, _ := st["stepId"].(string)
+		dispatchedAt, _ := st["updatedAt"].(string)
+		if dispatchedAt == "" {
+			dispatchedAt = time.Time{}.Format(time.RFC3339Nano)
+		}
+		st["attempts"] = []any{map[string]any{
+			"attempt":      1,
+			"dispatchedAt": dispatchedAt,
+			"commandKey":   fmt.Sprintf("%s#%d", stepId, 1),
+		}}
+
+		// Steps after the current step have not been dispatched
+		if status == string(Pending) {
+			break
+		}
+	}
+	return nil
+}
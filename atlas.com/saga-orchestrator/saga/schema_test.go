@@ -0,0 +1,98 @@
+package saga
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"testing"
+	"time"
+)
+
+func loadFixture(t *testing.T, name string) Saga {
+	bs, err := os.ReadFile("testdata/schema/" + name)
+	require.NoError(t, err)
+	s, err := Deserialize(bs)
+	require.NoError(t, err)
+	return s
+}
+
+// TestDeserializeVersion1 tests that sagas persisted before schema versions were recorded load with the attempts which
+// dispatched their steps
+func TestDeserializeVersion1(t *testing.T) {
+	t.Run("in flight saga", func(t *testing.T) {
+		s := loadFixture(t, "v1_in_flight.json")
+		assert.Equal(t, uuid.MustParse("9f2a6c1e-4b7d-4e0a-8f3b-2d5c6e7f8a91"), s.TransactionId)
+		require.Len(t, s.Steps, 3)
+		assert.Equal(t, AwardItemActionPayload{CharacterId: 12345, Item: ItemPayload{TemplateId: 2000000, Quantity: 5}}, s.Steps[0].Payload)
+		assert.Equal(t, AwardMesosPayload{CharacterId: 12345, ChannelId: 1, ActorId: 9010000, ActorType: "NPC", Amount: 1000}, s.Steps[1].Payload)
+
+		dispatchedAt := time.Date(2025, 1, 10, 12, 0, 1, 0, time.UTC)
+		assert.Equal(t, []StepAttempt{{Attempt: 1, DispatchedAt: dispatchedAt, CommandKey: "give_item#1"}}, s.Steps[0].Attempts)
+		assert.Equal(t, []StepAttempt{{Attempt: 1, DispatchedAt: dispatchedAt, CommandKey: "give_mesos#1"}}, s.Steps[1].Attempts)
+		assert.Empty(t, s.Steps[2].Attempts)
+
+		// Failures reported against the current step are recorded
+		require.NoError(t, s.RecordStepAttemptError(1, "INVENTORY_FULL", "inventory is full"))
+		assert.True(t, s.Steps[1].ReportedError())
+	})
+
+	t.Run("failing saga", func(t *testing.T) {
+		s := loadFixture(t, "v1_failing.json")
+		require.Len(t, s.Steps, 3)
+		assert.Len(t, s.Steps[0].Attempts, 1)
+		assert.Len(t, s.Steps[1].Attempts, 1)
+		assert.False(t, s.Steps[1].ReportedError())
+		assert.Empty(t, s.Steps[2].Attempts)
+		assert.Equal(t, 1, s.FindFailedStepIndex())
+	})
+
+	t.Run("held saga", func(t *testing.T) {
+		s := loadFixture(t, "v1_held.json")
+		assert.Equal(t, PendingApproval, s.Hold)
+		assert.Equal(t, map[string]string{"event": "halloween2025"}, s.Labels)
+		require.Len(t, s.Steps, 1)
+		assert.Empty(t, s.Steps[0].Attempts)
+	})
+}
+
+// TestDeserializeCurrentVersion tests that sagas persisted with the current schema version load as they were
+// serialized
+func TestDeserializeCurrentVersion(t *testing.T) {
+	s := loadFixture(t, "v2.json")
+	assert.Equal(t, Variables{"characterId": float64(12345)}, s.Variables)
+	require.Len(t, s.Steps, 1)
+	require.Len(t, s.Steps[0].Attempts, 2)
+	assert.Equal(t, ErrorCodeDispatchFailed, s.Steps[0].Attempts[0].ErrorCode)
+	assert.Equal(t, "give_mesos#2", s.Steps[0].Attempts[1].CommandKey)
+}
+
+// TestSerialize tests that serialized sagas record the current schema version, and deserialize unchanged
+func TestSerialize(t *testing.T) {
+	s := NewBuilder().
+		SetSagaType(QuestReward).
+		SetInitiatedBy("9010000").
+		AddStep("give_mesos", Pending, AwardMesos, AwardMesosPayload{CharacterId: 12345, ActorType: "NPC", Amount: 1000}).
+		Build()
+
+	bs, err := Serialize(s)
+	require.NoError(t, err)
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal(bs, &doc))
+	assert.Equal(t, float64(CurrentSchemaVersion), doc["schemaVersion"])
+
+	ds, err := Deserialize(bs)
+	require.NoError(t, err)
+	assert.Equal(t, s.TransactionId, ds.TransactionId)
+	assert.Equal(t, s.Steps[0].Payload, ds.Steps[0].Payload)
+	assert.Empty(t, ds.Steps[0].Attempts)
+}
+
+// TestDeserializeUnsupportedVersion tests that sagas persisted with a newer schema version are not loaded
+func TestDeserializeUnsupportedVersion(t *testing.T) {
+	for _, v := range []string{`{"schemaVersion": 3, "steps": []}`, `{"schemaVersion": "2", "steps": []}`, `{"schemaVersion": 0, "steps": []}`} {
+		_, err := Deserialize([]byte(v))
+		assert.ErrorIs(t, err, ErrUnsupportedSchemaVersion, v)
+	}
+}
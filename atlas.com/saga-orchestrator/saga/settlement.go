@@ -0,0 +1,93 @@
+package saga
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultSettlementPageSize is the number of holders a settle_event_currency step settles, when not declared
+const DefaultSettlementPageSize = 50
+
+// MaxSettlementPageSize is the number of holders a settle_event_currency step may settle, bounding the steps it expands
+// into
+const MaxSettlementPageSize = 100
+
+// settlementPage returns the page a settle_event_currency step settles
+func settlementPage(p SettleEventCurrencyPayload) uint32 {
+	if p.Page == 0 {
+		return 1
+	}
+	return p.Page
+}
+
+// settlementPageSize returns the number of holders a settle_event_currency step settles
+func settlementPageSize(p SettleEventCurrencyPayload) uint32 {
+	if p.PageSize == 0 {
+		return DefaultSettlementPageSize
+	}
+	return p.PageSize
+}
+
+// settlementPageStepId returns the step ID of a page of the settlement begun by the root step (e.g. settle_page_2)
+func settlementPageStepId(root string, page uint32) string {
+	if page == 1 {
+		return root
+	}
+	return fmt.Sprintf("%s_page_%d", root, page)
+}
+
+// expandSettlement expands the holders of a settle_event_currency step into the steps settling them, destroying the
+// currency each holds and awarding a reward for each rate units of it, in ascending order of character id. Step IDs
+// are prefixed with the page's step ID and the holder (e.g. settle_12345_destroy), so they are unique across pages. A
+// full page is followed by a step settling the next page, of the holders after the last settled.
+func expandSettlement(stepId string, p SettleEventCurrencyPayload) []Step[any] {
+	rate := p.Rate
+	if rate == 0 {
+		rate = 1
+	}
+	holders := append([]SettledHolder{}, p.Holders...)
+	sort.Slice(holders, func(i, j int) bool {
+		return holders[i].CharacterId < holders[j].CharacterId
+	})
+
+	steps := make([]Step[any], 0, len(holders)*2+1)
+	for _, holder := range holders {
+		if holder.Quantity == 0 {
+			continue
+		}
+		steps = append(steps, Step[any]{
+			StepId:  fmt.Sprintf("%s_%d_destroy", stepId, holder.CharacterId),
+			Status:  Pending,
+			Action:  DestroyAsset,
+			Payload: DestroyAssetPayload{CharacterId: holder.CharacterId, TemplateId: p.CurrencyId, Quantity: holder.Quantity},
+		})
+		if rewards := holder.Quantity / rate; rewards > 0 {
+			steps = append(steps, Step[any]{
+				StepId:  fmt.Sprintf("%s_%d_award", stepId, holder.CharacterId),
+				Status:  Pending,
+				Action:  AwardAsset,
+				Payload: AwardItemActionPayload{CharacterId: holder.CharacterId, Item: ItemPayload{TemplateId: p.RewardId, Quantity: rewards}},
+			})
+		}
+	}
+
+	if len(holders) > 0 && uint32(len(holders)) >= settlementPageSize(p) {
+		page := settlementPage(p)
+		root := strings.TrimSuffix(stepId, fmt.Sprintf("_page_%d", page))
+		steps = append(steps, Step[any]{
+			StepId: settlementPageStepId(root, page+1),
+			Status: Pending,
+			Action: SettleEventCurrency,
+			Payload: SettleEventCurrencyPayload{
+				CurrencyId: p.CurrencyId,
+				RewardId:   p.RewardId,
+				Rate:       p.Rate,
+				PageSize:   p.PageSize,
+				Page:       page + 1,
+				After:      holders[len(holders)-1].CharacterId,
+			},
+		})
+	}
+	return steps
+}
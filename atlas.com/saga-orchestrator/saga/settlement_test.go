@@ -0,0 +1,122 @@
+package saga
+
+import (
+	"atlas-saga-orchestrator/compartment"
+	mock2 "atlas-saga-orchestrator/compartment/mock"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestExpandSettlement tests that holders are settled in order of character id, with a reward for each rate units of
+// currency, and that only full pages are followed by the next
+func TestExpandSettlement(t *testing.T) {
+	payload := SettleEventCurrencyPayload{
+		CurrencyId: 4001126,
+		RewardId:   2022179,
+		Rate:       10,
+		PageSize:   3,
+		Page:       2,
+		After:      100,
+		Holders:    []SettledHolder{{CharacterId: 300, Quantity: 25}, {CharacterId: 200, Quantity: 5}, {CharacterId: 400}},
+	}
+	steps := expandSettlement("settle_page_2", payload)
+
+	ids := make([]string, 0, len(steps))
+	for _, st := range steps {
+		ids = append(ids, st.StepId)
+	}
+	assert.Equal(t, []string{"settle_page_2_200_destroy", "settle_page_2_300_destroy", "settle_page_2_300_award", "settle_page_3"}, ids)
+	assert.Equal(t, DestroyAssetPayload{CharacterId: 200, TemplateId: 4001126, Quantity: 5}, steps[0].Payload)
+	assert.Equal(t, AwardItemActionPayload{CharacterId: 300, Item: ItemPayload{TemplateId: 2022179, Quantity: 2}}, steps[2].Payload)
+	assert.Equal(t, SettleEventCurrencyPayload{CurrencyId: 4001126, RewardId: 2022179, Rate: 10, PageSize: 3, Page: 3, After: 400}, steps[3].Payload)
+
+	// A page which is not full is the last
+	payload.PageSize = 4
+	steps = expandSettlement("settle_page_2", payload)
+	assert.NotEqual(t, SettleEventCurrency, steps[len(steps)-1].Action)
+
+	assert.Empty(t, expandSettlement("settle", SettleEventCurrencyPayload{CurrencyId: 4001126, RewardId: 2022179, Holders: []SettledHolder{}}))
+}
+
+// TestEventShopSettlementSaga tests that holders of event currency are settled a page at a time, and that a page
+// dispatched again once interrupted settles the holders it listed before
+func TestEventShopSettlementSaga(t *testing.T) {
+	holders := []compartment.Holder{compartment.NewHolder(100, 20), compartment.NewHolder(200, 5), compartment.NewHolder(300, 10)}
+	var calls []string
+	compP := &mock2.ProcessorMock{
+		GetHoldersFunc: func(templateId uint32, after uint32, size uint32) ([]compartment.Holder, error) {
+			calls = append(calls, fmt.Sprintf("list after %d", after))
+			page := make([]compartment.Holder, 0, size)
+			for _, h := range holders {
+				if h.CharacterId() > after && uint32(len(page)) < size {
+					page = append(page, h)
+				}
+			}
+			return page, nil
+		},
+		RequestDestroyItemFunc: func(transactionId uuid.UUID, characterId uint32, templateId uint32, quantity uint32) error {
+			calls = append(calls, fmt.Sprintf("destroy %d x%d from %d", templateId, quantity, characterId))
+			return nil
+		},
+		RequestCreateItemFunc: func(transactionId uuid.UUID, characterId uint32, templateId uint32, quantity uint32) error {
+			calls = append(calls, fmt.Sprintf("award %d x%d to %d", templateId, quantity, characterId))
+			return nil
+		},
+	}
+	te, ctx := setupContext()
+	processor, _ := setupTestProcessor(ctx, nil, compP)
+
+	t.Run("holders are settled a page at a time", func(t *testing.T) {
+		calls = nil
+		s := NewBuilder().
+			SetSagaType(EventShopSettlement).
+			AddStep("settle", Pending, SettleEventCurrency, SettleEventCurrencyPayload{CurrencyId: 4001126, RewardId: 2022179, Rate: 10, PageSize: 2}).
+			Build()
+		defer GetCache().Remove(te.Id(), s.TransactionId)
+		require.NoError(t, processor.Put(s))
+
+		// Each settling step awaits its status event, so is completed here, until the saga ends
+		for i := 0; i < 10; i++ {
+			if _, err := processor.GetById(s.TransactionId); err != nil {
+				break
+			}
+			require.NoError(t, processor.StepCompleted(s.TransactionId, true))
+		}
+
+		assert.Equal(t, []string{
+			"list after 0",
+			"destroy 4001126 x20 from 100",
+			"award 2022179 x2 to 100",
+			"destroy 4001126 x5 from 200",
+			"list after 200",
+			"destroy 4001126 x10 from 300",
+			"award 2022179 x1 to 300",
+		}, calls)
+	})
+
+	t.Run("interrupted pages settle the holders they listed", func(t *testing.T) {
+		calls = nil
+		s := NewBuilder().
+			SetSagaType(EventShopSettlement).
+			AddStep("settle", Pending, SettleEventCurrency, SettleEventCurrencyPayload{CurrencyId: 4001126, RewardId: 2022179, Holders: []SettledHolder{{CharacterId: 100, Quantity: 3}}}).
+			AddStep("settle_100_destroy", Pending, DestroyAsset, DestroyAssetPayload{CharacterId: 100, TemplateId: 4001126, Quantity: 3}).
+			AddStep("settle_100_award", Pending, AwardAsset, AwardItemActionPayload{CharacterId: 100, Item: ItemPayload{TemplateId: 2022179, Quantity: 3}}).
+			Build()
+		defer GetCache().Remove(te.Id(), s.TransactionId)
+		GetCache().Put(te.Id(), s)
+		require.NoError(t, processor.Step(s.TransactionId))
+
+		cs, err := processor.GetById(s.TransactionId)
+		require.NoError(t, err)
+		ids := make([]string, 0, len(cs.Steps))
+		for _, st := range cs.Steps {
+			ids = append(ids, st.StepId)
+		}
+		assert.Equal(t, []string{"settle", "settle_100_destroy", "settle_100_award"}, ids)
+		assert.Equal(t, []string{"destroy 4001126 x3 from 100"}, calls)
+	})
+}
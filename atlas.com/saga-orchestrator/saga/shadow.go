@@ -0,0 +1,212 @@
+package saga
+
+import (
+	"atlas-saga-orchestrator/metrics"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	tenant "github.com/Chronicle20/atlas-tenant"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// ShadowConfig configures shadow mode, in which a replica processes the status events the active replicas do without
+// affecting anything beyond itself, so new correlation logic may be validated against production traffic before cutover
+type ShadowConfig struct {
+	Enabled      bool          // Run as a shadow replica, discarding the commands and events it would produce
+	CompareDelay time.Duration // Delay after an event is processed before the saga is compared with the active replicas'
+}
+
+// DefaultShadowConfig is the shadow mode configuration used when none is configured
+var DefaultShadowConfig = ShadowConfig{CompareDelay: 5 * time.Second}
+
+// ShadowConfigFromEnv loads the shadow mode configuration from the environment
+func ShadowConfigFromEnv() (ShadowConfig, error) {
+	c := DefaultShadowConfig
+	if v, ok := os.LookupEnv("SAGA_SHADOW_MODE"); ok && v != "" {
+		enabled, err := strconv.ParseBool(v)
+		if err != nil {
+			return ShadowConfig{}, fmt.Errorf("invalid SAGA_SHADOW_MODE '%s'", v)
+		}
+		c.Enabled = enabled
+	}
+	if v, ok := os.LookupEnv("SAGA_SHADOW_COMPARE_DELAY"); ok && v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return ShadowConfig{}, fmt.Errorf("invalid SAGA_SHADOW_COMPARE_DELAY '%s'", v)
+		}
+		c.CompareDelay = d
+	}
+	return c, nil
+}
+
+var shadowConfig = ShadowConfig{}
+
+// InitShadowConfig sets the singleton shadow mode configuration
+func InitShadowConfig(c ShadowConfig) {
+	shadowConfig = c
+}
+
+// GetShadowConfig returns the singleton shadow mode configuration
+func GetShadowConfig() ShadowConfig {
+	return shadowConfig
+}
+
+// ShadowGuarded guards a handler of a request which would mutate sagas or subscriptions, so a shadow replica refuses it
+// as unavailable rather than acting upon it. Such requests are made of the active replicas.
+func ShadowGuarded(l logrus.FieldLogger, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if GetShadowConfig().Enabled {
+			l.Debugf("Refusing [%s %s] as a shadow replica.", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// StepTransition is the change in status of a step of a saga made in processing an event. Steps inserted by the event,
+// such as those of a selected branch, transition from no status.
+type StepTransition struct {
+	StepId string `json:"stepId"`
+	From   Status `json:"from,omitempty"`
+	To     Status `json:"to"`
+}
+
+func (t StepTransition) String() string {
+	return fmt.Sprintf("%s: %s -> %s", t.StepId, t.From, t.To)
+}
+
+// Transitions returns the transitions of the steps of a saga from one state of it to another, in the order of the steps
+func Transitions(before Saga, after Saga) []StepTransition {
+	previous := make(map[string]Status, len(before.Steps))
+	for _, st := range before.Steps {
+		previous[st.StepId] = st.Status
+	}
+	r := make([]StepTransition, 0)
+	for _, st := range after.Steps {
+		if from, ok := previous[st.StepId]; !ok || from != st.Status {
+			r = append(r, StepTransition{StepId: st.StepId, From: from, To: st.Status})
+		}
+	}
+	return r
+}
+
+// Divergences describes how the state of a saga held by a shadow replica differs from that held by the active replicas,
+// being its overall status and the status of each of its steps. Sagas in the same state have none.
+func Divergences(shadow Saga, active Saga) []string {
+	r := make([]string, 0)
+	if shadow.OverallStatus() != active.OverallStatus() {
+		r = append(r, fmt.Sprintf("saga is [%s], expected [%s]", shadow.OverallStatus(), active.OverallStatus()))
+	}
+	statuses := make(map[string]Status, len(active.Steps))
+	for _, st := range active.Steps {
+		statuses[st.StepId] = st.Status
+	}
+	seen := make(map[string]bool, len(shadow.Steps))
+	for _, st := range shadow.Steps {
+		seen[st.StepId] = true
+		expected, ok := statuses[st.StepId]
+		if !ok {
+			r = append(r, fmt.Sprintf("step [%s] is not expected", st.StepId))
+			continue
+		}
+		if st.Status != expected {
+			r = append(r, fmt.Sprintf("step [%s] is [%s], expected [%s]", st.StepId, st.Status, expected))
+		}
+	}
+	for _, st := range active.Steps {
+		if !seen[st.StepId] {
+			r = append(r, fmt.Sprintf("step [%s] is missing", st.StepId))
+		}
+	}
+	return r
+}
+
+var shadowDivergences *metrics.Counter
+var shadowDivergencesOnce sync.Once
+
+// getShadowDivergences returns the counter of sagas found by a shadow replica to diverge from the active replicas', by
+// tenant and saga type
+func getShadowDivergences() *metrics.Counter {
+	shadowDivergencesOnce.Do(func() {
+		shadowDivergences = metrics.GetRegistry().RegisterCounter("saga_shadow_divergences_total", "Number of events after which a shadow replica's state of a saga diverged from the active replicas', by saga type.")
+	})
+	return shadowDivergences
+}
+
+// ShadowObserve observes a shadow replica processing an event of the saga, returning a function to call once it has. The
+// transitions processing the event made are logged, and once the active replicas have had time to process it too, the
+// saga is compared with their state of it, as read from the archive, logging any divergence. Events of no saga are not
+// observed, and sagas are compared only when an archive is configured.
+func ShadowObserve(l logrus.FieldLogger, ctx context.Context, transactionId uuid.UUID) func() {
+	t, err := tenant.FromContext(ctx)()
+	if err != nil || transactionId == uuid.Nil {
+		return func() {}
+	}
+	// Sagas not yet cached are hydrated from the archive as the event is processed, so transition from its state
+	before, ok := GetCache().GetById(t.Id(), transactionId)
+	if a, archived := GetArchive(); !ok && archived {
+		before, _, _ = a.GetById(t.Id(), transactionId)
+	}
+	// Steps are updated in place as the event is processed, so the state before it is copied
+	before.Steps = append([]Step[any]{}, before.Steps...)
+	// Sagas completing are removed from the cache, so their terminal state is observed as it is notified
+	terminal, unsubscribe := GetNotifier().Subscribe(t.Id(), transactionId)
+
+	return func() {
+		defer unsubscribe()
+		after, ok := GetCache().GetById(t.Id(), transactionId)
+		if !ok {
+			select {
+			case after = <-terminal:
+			default:
+				return
+			}
+		}
+		fl := l.WithFields(logrus.Fields{
+			"transaction_id": transactionId.String(),
+			"saga_type":      after.SagaType,
+			"tenant_id":      t.Id().String(),
+		})
+		if ts := Transitions(before, after); len(ts) > 0 {
+			fl.Infof("Shadow processing transitioned steps %v.", ts)
+		}
+
+		a, ok := GetArchive()
+		if !ok {
+			return
+		}
+		time.AfterFunc(GetShadowConfig().CompareDelay, func() {
+			compareShadow(fl, a, t.Id(), after)
+		})
+	}
+}
+
+// compareShadow compares the shadow replica's state of the saga with the active replicas', preferring its latest state
+// should the saga remain cached
+func compareShadow(l logrus.FieldLogger, a Archive, tenantId uuid.UUID, s Saga) {
+	if cs, ok := GetCache().GetById(tenantId, s.TransactionId); ok {
+		s = cs
+	}
+	active, ok, err := a.GetById(tenantId, s.TransactionId)
+	if err != nil {
+		l.WithError(err).Warn("Unable to read active state of saga for shadow comparison.")
+		return
+	}
+	if !ok {
+		l.Debug("Saga has no active state for shadow comparison.")
+		return
+	}
+	ds := Divergences(s, active)
+	if len(ds) == 0 {
+		return
+	}
+	getShadowDivergences().Inc(map[string]string{"tenant_id": tenantId.String(), "saga_type": string(s.SagaType)})
+	l.Warnf("Shadow state of saga diverged from active state: %v.", ds)
+}
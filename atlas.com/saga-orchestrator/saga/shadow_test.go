@@ -0,0 +1,126 @@
+package saga
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestShadowGuarded tests that a shadow replica refuses guarded requests as unavailable, which others handle
+func TestShadowGuarded(t *testing.T) {
+	l, _ := test.NewNullLogger()
+	handled := false
+	h := ShadowGuarded(l, func(w http.ResponseWriter, r *http.Request) {
+		handled = true
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	w := httptest.NewRecorder()
+	h(w, httptest.NewRequest(http.MethodPost, "/sagas", nil))
+	assert.True(t, handled)
+	assert.Equal(t, http.StatusAccepted, w.Code)
+
+	InitShadowConfig(ShadowConfig{Enabled: true})
+	defer InitShadowConfig(ShadowConfig{})
+	handled = false
+	w = httptest.NewRecorder()
+	h(w, httptest.NewRequest(http.MethodPost, "/sagas", nil))
+	assert.False(t, handled)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+// TestTransitions tests that the steps whose status changed, including those inserted, are reported in order
+func TestTransitions(t *testing.T) {
+	before := NewBuilder().
+		AddStep("first", Pending, SetVariable, SetVariablePayload{Name: "a", Value: 1}).
+		AddStep("second", Pending, SetVariable, SetVariablePayload{Name: "b", Value: 2}).
+		Build()
+	after := before
+	after.Steps = []Step[any]{before.Steps[0], {StepId: "branch", Status: Pending}, before.Steps[1]}
+	after.Steps[0].Status = Completed
+
+	assert.Equal(t, []StepTransition{{StepId: "first", From: Pending, To: Completed}, {StepId: "branch", To: Pending}}, Transitions(before, after))
+	assert.Empty(t, Transitions(before, before))
+}
+
+// TestDivergences tests that differences in the overall status of a saga, and the status of its steps, are described
+func TestDivergences(t *testing.T) {
+	active := NewBuilder().
+		AddStep("first", Completed, SetVariable, SetVariablePayload{Name: "a", Value: 1}).
+		AddStep("second", Pending, SetVariable, SetVariablePayload{Name: "b", Value: 2}).
+		Build()
+	assert.Empty(t, Divergences(active, active))
+
+	shadow := active
+	shadow.Steps = []Step[any]{active.Steps[0], {StepId: "extra", Status: Pending}}
+	shadow.Steps[0].Status = Failed
+	assert.Equal(t, []string{
+		"saga is [failing], expected [pending]",
+		"step [first] is [failed], expected [completed]",
+		"step [extra] is not expected",
+		"step [second] is missing",
+	}, Divergences(shadow, active))
+}
+
+// TestShadowObserve tests that a shadow replica's processing of an event is compared with the active replicas' state
+// of the saga, counting and logging divergences, including of sagas the event completed
+func TestShadowObserve(t *testing.T) {
+	te, ctx := setupContext()
+	defer InitArchive(nil)
+	InitShadowConfig(ShadowConfig{Enabled: true})
+	defer InitShadowConfig(ShadowConfig{})
+	processor, _ := setupTestProcessor(ctx, nil, nil)
+	l, hook := test.NewNullLogger()
+
+	count := func() float64 {
+		for _, s := range getShadowDivergences().Samples() {
+			if s.Labels["tenant_id"] == te.Id().String() {
+				return s.Value
+			}
+		}
+		return 0
+	}
+
+	s := NewBuilder().
+		SetSagaType(QuestReward).
+		AddStep("first", Pending, AwardMesos, AwardMesosPayload{CharacterId: 12345, ActorType: "NPC", Amount: 100}).
+		AddStep("second", Pending, SetVariable, SetVariablePayload{Name: "done", Value: true}).
+		Build()
+	GetCache().Put(te.Id(), s)
+	defer GetCache().Remove(te.Id(), s.TransactionId)
+
+	// The active replicas have yet to process the event completing the first step
+	active := s
+	active.Steps = append([]Step[any]{}, s.Steps...)
+	InitArchive(&testArchive{t: te, sagas: map[uuid.UUID]Saga{s.TransactionId: active}})
+
+	done := ShadowObserve(l, ctx, s.TransactionId)
+	require.NoError(t, processor.StepCompleted(s.TransactionId, true))
+	done()
+
+	assert.Eventually(t, func() bool { return count() == 1 }, time.Second, 10*time.Millisecond)
+	var transitioned, diverged *logrus.Entry
+	for _, e := range hook.AllEntries() {
+		switch e.Level {
+		case logrus.InfoLevel:
+			transitioned = e
+		case logrus.WarnLevel:
+			diverged = e
+		}
+	}
+	require.NotNil(t, transitioned)
+	assert.Contains(t, transitioned.Message, "first: pending -> completed")
+	require.NotNil(t, diverged)
+	assert.Contains(t, diverged.Message, "saga is [completed], expected [pending]")
+	assert.Contains(t, diverged.Message, "step [first] is [completed], expected [pending]")
+
+	// Events of no saga are not observed
+	ShadowObserve(l, ctx, uuid.Nil)()
+}
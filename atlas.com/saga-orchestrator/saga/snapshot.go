@@ -0,0 +1,110 @@
+package saga
+
+import (
+	"atlas-saga-orchestrator/character"
+	"slices"
+)
+
+// snapshotRestoredActions are the actions whose effect on a character is reversed by restoring a snapshot of the
+// character taken before them, as they only change its progression, stats or meso
+var snapshotRestoredActions = []Action{AwardExperience, AwardLevel, AwardMesos, DeductMesos, ApplyCharacterExpPenalty}
+
+// NewCharacterSnapshot captures the progression and stats of the character
+func NewCharacterSnapshot(c character.Model) CharacterSnapshot {
+	return CharacterSnapshot{
+		Level:        c.Level(),
+		Experience:   c.Experience(),
+		Meso:         c.Meso(),
+		Strength:     c.Strength(),
+		Dexterity:    c.Dexterity(),
+		Intelligence: c.Intelligence(),
+		Luck:         c.Luck(),
+		Hp:           c.Hp(),
+		MaxHp:        c.MaxHp(),
+		Mp:           c.Mp(),
+		MaxMp:        c.MaxMp(),
+		Ap:           c.Ap(),
+	}
+}
+
+// Model returns the character identified as captured by the snapshot
+func (cs CharacterSnapshot) Model(characterId uint32) character.Model {
+	return character.NewModelBuilder(characterId).
+		SetLevel(cs.Level).
+		SetExperience(cs.Experience).
+		SetMeso(cs.Meso).
+		SetStrength(cs.Strength).
+		SetDexterity(cs.Dexterity).
+		SetIntelligence(cs.Intelligence).
+		SetLuck(cs.Luck).
+		SetHp(cs.Hp).
+		SetMaxHp(cs.MaxHp).
+		SetMp(cs.Mp).
+		SetMaxMp(cs.MaxMp).
+		SetAp(cs.Ap).
+		Build()
+}
+
+// findCharacterSnapshot returns the payload of the completed snapshot_character step of the character identified by its
+// StepId, or the character's latest snapshot when no StepId is given
+func findCharacterSnapshot(s Saga, characterId uint32, stepId string) (SnapshotCharacterPayload, bool) {
+	var found SnapshotCharacterPayload
+	ok := false
+	for _, st := range s.Steps {
+		if st.Action != SnapshotCharacter || st.Status != Completed || (stepId != "" && st.StepId != stepId) {
+			continue
+		}
+		payload, isSnapshot := st.Payload.(SnapshotCharacterPayload)
+		if !isSnapshot || payload.CharacterId != characterId || payload.Snapshot == nil {
+			continue
+		}
+		found, ok = payload, true
+	}
+	return found, ok
+}
+
+// pendingCharacterSnapshots returns the indexes of the latest completed snapshot_character step of each character the
+// saga took a snapshot of, and has not restored since, in the order they were taken. A character whose restore is the
+// failed step is omitted.
+func pendingCharacterSnapshots(s Saga, failedStep Step[any]) []int {
+	latest := make(map[uint32]int)
+	for i, st := range s.Steps {
+		if st.Status != Completed && st.StepId != failedStep.StepId {
+			continue
+		}
+		switch payload := st.Payload.(type) {
+		case SnapshotCharacterPayload:
+			if st.Status == Completed && payload.Snapshot != nil {
+				latest[payload.CharacterId] = i
+			}
+		case RestoreCharacterPayload:
+			delete(latest, payload.CharacterId)
+		}
+	}
+
+	r := make([]int, 0, len(latest))
+	for _, i := range latest {
+		r = append(r, i)
+	}
+	slices.Sort(r)
+	return r
+}
+
+// restoredBySnapshot returns whether the effect of the completed step at the index is reversed by a character snapshot
+// restored when the failed step is compensated
+func restoredBySnapshot(s Saga, failedStep Step[any], idx int) bool {
+	st := s.Steps[idx]
+	if !slices.Contains(snapshotRestoredActions, st.Action) {
+		return false
+	}
+	characterId, ok := stepCharacterId(st)
+	if !ok {
+		return false
+	}
+	for _, i := range pendingCharacterSnapshots(s, failedStep) {
+		if i < idx && s.Steps[i].Payload.(SnapshotCharacterPayload).CharacterId == characterId {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,148 @@
+package saga
+
+import (
+	"atlas-saga-orchestrator/metrics"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// RoleStaleSagaReporter is the role of the replica reporting stale sagas. Reporting spans the sagas of every replica
+// through the archive, so runs on only one.
+const RoleStaleSagaReporter = "stale_saga_reporter"
+
+// StaleConfig configures the reporting of sagas which have not progressed
+type StaleConfig struct {
+	Threshold time.Duration // Threshold after which a saga which has not progressed is reported. When 0, sagas are not reported.
+	Interval  time.Duration // Interval at which stale sagas are reported
+}
+
+// DefaultStaleConfig is the stale saga configuration used when none is configured
+var DefaultStaleConfig = StaleConfig{Interval: time.Minute}
+
+// StaleConfigFromEnv loads the stale saga configuration from the environment
+func StaleConfigFromEnv() (StaleConfig, error) {
+	c := DefaultStaleConfig
+	if v, ok := os.LookupEnv("SAGA_STALE_THRESHOLD"); ok && v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return StaleConfig{}, fmt.Errorf("invalid SAGA_STALE_THRESHOLD '%s'", v)
+		}
+		c.Threshold = d
+	}
+	if v, ok := os.LookupEnv("SAGA_STALE_REPORT_INTERVAL"); ok && v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return StaleConfig{}, fmt.Errorf("invalid SAGA_STALE_REPORT_INTERVAL '%s'", v)
+		}
+		c.Interval = d
+	}
+	return c, nil
+}
+
+// LastProgressed returns when the saga last progressed, being the latest update of its steps
+func (s Saga) LastProgressed() time.Time {
+	var r time.Time
+	for _, st := range s.Steps {
+		if st.UpdatedAt.After(r) {
+			r = st.UpdatedAt
+		}
+	}
+	return r
+}
+
+// findStale returns the sagas with work remaining which have not progressed within the threshold, by tenant. Sagas held
+// for review or approval await an operator, so are not stale. Sagas are read from the archive, spanning replicas, or
+// from the cache without one.
+func findStale(now time.Time, threshold time.Duration) (map[uuid.UUID][]Saga, error) {
+	active := make(map[uuid.UUID][]Saga)
+	if a, ok := GetArchive(); ok {
+		as, err := a.GetActive()
+		if err != nil {
+			return nil, err
+		}
+		for t, sagas := range as {
+			active[t.Id()] = append(active[t.Id()], sagas...)
+		}
+	} else {
+		for _, tenantId := range GetCache().Tenants() {
+			active[tenantId] = GetCache().GetAll(tenantId)
+		}
+	}
+
+	r := make(map[uuid.UUID][]Saga)
+	for tenantId, sagas := range active {
+		for _, s := range sagas {
+			if !s.active() || s.Held() || now.Sub(s.LastProgressed()) < threshold {
+				continue
+			}
+			r[tenantId] = append(r[tenantId], s)
+		}
+	}
+	return r, nil
+}
+
+// StaleReporter reports sagas which have not progressed within the threshold, such as those awaiting an event which
+// was lost
+type StaleReporter struct {
+	l      logrus.FieldLogger
+	config StaleConfig
+	mutex  sync.Mutex
+	counts map[uuid.UUID]map[string]int
+}
+
+// NewStaleReporter creates a task reporting stale sagas at the configured interval
+func NewStaleReporter(l logrus.FieldLogger, c StaleConfig) *StaleReporter {
+	r := &StaleReporter{l: l, config: c, counts: make(map[uuid.UUID]map[string]int)}
+	metrics.GetRegistry().RegisterGauge("saga_stale", "Number of sagas which have not progressed within the stale threshold, as of the last report.", func() []metrics.Sample {
+		r.mutex.Lock()
+		defer r.mutex.Unlock()
+		samples := make([]metrics.Sample, 0, len(r.counts))
+		for tenantId, initiators := range r.counts {
+			for initiatedBy, n := range initiators {
+				samples = append(samples, metrics.Sample{Labels: map[string]string{"tenant_id": tenantId.String(), "initiated_by": initiatedBy}, Value: float64(n)})
+			}
+		}
+		return samples
+	})
+	return r
+}
+
+func (r *StaleReporter) Run() {
+	now := time.Now()
+	stale, err := findStale(now, r.config.Threshold)
+	if err != nil {
+		r.l.WithError(err).Error("Unable to find stale sagas.")
+		return
+	}
+
+	counts := make(map[uuid.UUID]map[string]int, len(stale))
+	for tenantId, sagas := range stale {
+		counts[tenantId] = make(map[string]int)
+		for _, s := range sagas {
+			counts[tenantId][s.InitiatedBy]++
+			stepId := ""
+			if st, ok := s.GetCurrentStep(); ok {
+				stepId = st.StepId
+			}
+			r.l.WithFields(logrus.Fields{
+				"transaction_id": s.TransactionId.String(),
+				"saga_type":      s.SagaType,
+				"initiated_by":   s.InitiatedBy,
+				"step_id":        stepId,
+				"tenant_id":      tenantId.String(),
+			}).Warnf("Saga has not progressed since [%s].", s.LastProgressed().Format(time.RFC3339))
+		}
+	}
+	r.mutex.Lock()
+	r.counts = counts
+	r.mutex.Unlock()
+}
+
+func (r *StaleReporter) SleepTime() time.Duration {
+	return r.config.Interval
+}
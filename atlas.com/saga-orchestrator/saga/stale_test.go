@@ -0,0 +1,87 @@
+package saga
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestStaleConfigFromEnv tests loading the stale saga configuration from the environment
+func TestStaleConfigFromEnv(t *testing.T) {
+	c, err := StaleConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, DefaultStaleConfig, c)
+
+	t.Setenv("SAGA_STALE_THRESHOLD", "10m")
+	t.Setenv("SAGA_STALE_REPORT_INTERVAL", "30s")
+	c, err = StaleConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, StaleConfig{Threshold: 10 * time.Minute, Interval: 30 * time.Second}, c)
+
+	t.Setenv("SAGA_STALE_REPORT_INTERVAL", "0s")
+	_, err = StaleConfigFromEnv()
+	assert.Error(t, err)
+}
+
+// TestStaleReporter tests that sagas with work remaining which have not progressed within the threshold are reported,
+// from the cache or the archive
+func TestStaleReporter(t *testing.T) {
+	te, _ := setupContext()
+	defer InitArchive(nil)
+	now := time.Now()
+
+	build := func(updatedAt time.Time, statuses ...Status) Saga {
+		b := NewBuilder().SetTransactionId(uuid.New()).SetSagaType(QuestReward)
+		for i, status := range statuses {
+			b.AddStep("step-"+string(rune('a'+i)), status, AwardMesos, AwardMesosPayload{CharacterId: 12345, ActorType: "NPC", Amount: 1000})
+		}
+		s := b.Build()
+		for i := range s.Steps {
+			s.Steps[i].UpdatedAt = updatedAt
+		}
+		return s
+	}
+	stale := build(now.Add(-time.Hour), Completed, Pending)
+	stale.InitiatedBy = "quest-service"
+	fresh := build(now.Add(-time.Second), Completed, Pending)
+	finished := build(now.Add(-time.Hour), Completed, Completed)
+	held := build(now.Add(-time.Hour), Pending)
+	held.Hold = PendingReview
+
+	ids := func(sagas []Saga) []uuid.UUID {
+		r := make([]uuid.UUID, 0, len(sagas))
+		for _, s := range sagas {
+			r = append(r, s.TransactionId)
+		}
+		return r
+	}
+
+	t.Run("stale sagas are found in the cache", func(t *testing.T) {
+		for _, s := range []Saga{stale, fresh, finished, held} {
+			GetCache().Put(te.Id(), s)
+			defer GetCache().Remove(te.Id(), s.TransactionId)
+		}
+		found, err := findStale(now, time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{stale.TransactionId}, ids(found[te.Id()]))
+	})
+
+	t.Run("stale sagas are found in the archive", func(t *testing.T) {
+		InitArchive(&testArchive{t: te, sagas: map[uuid.UUID]Saga{stale.TransactionId: stale, fresh.TransactionId: fresh, held.TransactionId: held}})
+		found, err := findStale(now, time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{stale.TransactionId}, ids(found[te.Id()]))
+
+		l, hook := test.NewNullLogger()
+		r := NewStaleReporter(l, StaleConfig{Threshold: time.Minute, Interval: time.Minute})
+		r.Run()
+		require.Len(t, hook.AllEntries(), 1)
+		assert.Equal(t, stale.TransactionId.String(), hook.LastEntry().Data["transaction_id"])
+		assert.Equal(t, "step-b", hook.LastEntry().Data["step_id"])
+		assert.Equal(t, map[string]int{"quest-service": 1}, r.counts[te.Id()])
+	})
+}
@@ -0,0 +1,184 @@
+package saga
+
+import (
+	"atlas-saga-orchestrator/kafka/producer"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrInvalidSubscription is returned when a subscription has no destination, or one it cannot be delivered to
+var ErrInvalidSubscription = errors.New("invalid subscription")
+
+// subscriptionWebhookAttempts is the most attempts made to deliver a status event to a webhook
+const subscriptionWebhookAttempts = 3
+
+// Subscription is an initiator's interest in the status events of the sagas matching its filter, delivered to a
+// dedicated topic or webhook rather than consumed from the EVENT_TOPIC_SAGA_STATUS firehose
+type Subscription struct {
+	Id          uuid.UUID         `json:"id"`                    // Id identifies the subscription
+	InitiatedBy string            `json:"initiatedBy,omitempty"` // InitiatedBy matches sagas of the initiator. When empty, sagas of any initiator match.
+	SagaTypes   []Type            `json:"sagaTypes,omitempty"`   // SagaTypes matches sagas of any of the types. When empty, sagas of any type match.
+	Labels      map[string]string `json:"labels,omitempty"`      // Labels matches sagas carrying every one of the labels
+	Topic       string            `json:"topic,omitempty"`       // Topic is the token of the topic events are produced to, configured in the environment
+	WebhookUrl  string            `json:"webhookUrl,omitempty"`  // WebhookUrl is the URL events are posted to, whose host must be allowed for http_request steps
+}
+
+// Matches returns whether the saga satisfies the subscription's filter
+func (sub Subscription) Matches(s Saga) bool {
+	if sub.InitiatedBy != "" && sub.InitiatedBy != s.InitiatedBy {
+		return false
+	}
+	if len(sub.SagaTypes) > 0 && !slices.Contains(sub.SagaTypes, s.SagaType) {
+		return false
+	}
+	return s.HasLabels(sub.Labels)
+}
+
+// Validate checks the subscription has a destination events can be delivered to
+func (sub Subscription) Validate() error {
+	if sub.Topic == "" && sub.WebhookUrl == "" {
+		return fmt.Errorf("%w: a topic or webhookUrl is required", ErrInvalidSubscription)
+	}
+	if sub.Topic != "" {
+		if v, ok := os.LookupEnv(sub.Topic); !ok || v == "" {
+			return fmt.Errorf("%w: topic [%s] is not configured", ErrInvalidSubscription, sub.Topic)
+		}
+	}
+	if sub.WebhookUrl != "" {
+		u, err := url.Parse(sub.WebhookUrl)
+		if err != nil || !GetHttpRequestConfig().Allowed(u) {
+			return fmt.Errorf("%w: webhookUrl [%s] is not allowed", ErrInvalidSubscription, sub.WebhookUrl)
+		}
+	}
+	if (&Saga{Labels: sub.Labels}).ValidateLabels() != nil {
+		return fmt.Errorf("%w: invalid labels", ErrInvalidSubscription)
+	}
+	return nil
+}
+
+// SubscriptionRegistry holds the subscriptions registered by initiators, by tenant
+type SubscriptionRegistry struct {
+	subscriptions map[uuid.UUID]map[uuid.UUID]Subscription
+	mutex         sync.RWMutex
+}
+
+// Singleton instance of the subscription registry
+var subscriptionRegistry *SubscriptionRegistry
+var subscriptionRegistryOnce sync.Once
+
+// GetSubscriptionRegistry returns the singleton instance of the subscription registry
+func GetSubscriptionRegistry() *SubscriptionRegistry {
+	subscriptionRegistryOnce.Do(func() {
+		subscriptionRegistry = &SubscriptionRegistry{subscriptions: make(map[uuid.UUID]map[uuid.UUID]Subscription)}
+	})
+	return subscriptionRegistry
+}
+
+// Add registers the subscription for the tenant, assigning it an ID when it has none
+func (r *SubscriptionRegistry) Add(tenantId uuid.UUID, sub Subscription) Subscription {
+	if sub.Id == uuid.Nil {
+		sub.Id = uuid.New()
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if _, ok := r.subscriptions[tenantId]; !ok {
+		r.subscriptions[tenantId] = make(map[uuid.UUID]Subscription)
+	}
+	r.subscriptions[tenantId][sub.Id] = sub
+	return sub
+}
+
+// Remove removes the subscription of the tenant, returning whether it was registered
+func (r *SubscriptionRegistry) Remove(tenantId uuid.UUID, id uuid.UUID) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if _, ok := r.subscriptions[tenantId][id]; !ok {
+		return false
+	}
+	delete(r.subscriptions[tenantId], id)
+	if len(r.subscriptions[tenantId]) == 0 {
+		delete(r.subscriptions, tenantId)
+	}
+	return true
+}
+
+// GetAll returns the subscriptions of the tenant, ordered by initiator then ID
+func (r *SubscriptionRegistry) GetAll(tenantId uuid.UUID) []Subscription {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	subs := make([]Subscription, 0, len(r.subscriptions[tenantId]))
+	for _, sub := range r.subscriptions[tenantId] {
+		subs = append(subs, sub)
+	}
+	sort.Slice(subs, func(i, j int) bool {
+		if subs[i].InitiatedBy != subs[j].InitiatedBy {
+			return subs[i].InitiatedBy < subs[j].InitiatedBy
+		}
+		return subs[i].Id.String() < subs[j].Id.String()
+	})
+	return subs
+}
+
+// Matching returns the subscriptions of the tenant whose filter the saga satisfies
+func (r *SubscriptionRegistry) Matching(tenantId uuid.UUID, s Saga) []Subscription {
+	subs := make([]Subscription, 0)
+	for _, sub := range r.GetAll(tenantId) {
+		if sub.Matches(s) {
+			subs = append(subs, sub)
+		}
+	}
+	return subs
+}
+
+// publishToSubscribers delivers the status event of the saga to the subscriptions it matches. Delivery is best-effort, so
+// failures are logged rather than returned.
+func (p *ProcessorImpl) publishToSubscribers(s Saga, event model.Provider[[]kafka.Message]) {
+	for _, sub := range GetSubscriptionRegistry().Matching(p.t.Id(), s) {
+		fl := p.l.WithFields(logrus.Fields{
+			"transaction_id":  s.TransactionId.String(),
+			"saga_type":       s.SagaType,
+			"tenant_id":       p.t.Id().String(),
+			"subscription_id": sub.Id.String(),
+		})
+		if sub.Topic != "" {
+			if err := producer.ProviderImpl(p.l)(p.ctx)(sub.Topic)(event); err != nil {
+				fl.WithError(err).Errorf("Unable to deliver saga status event to topic [%s].", sub.Topic)
+			}
+		}
+		if sub.WebhookUrl != "" && !GetShadowConfig().Enabled {
+			ms, err := event()
+			if err != nil || len(ms) == 0 {
+				fl.WithError(err).Error("Unable to create saga status event for webhook.")
+				continue
+			}
+			go deliverWebhook(fl, p.t.Id(), sub, ms[0].Value)
+		}
+	}
+}
+
+// deliverWebhook posts the status event to the subscription's webhook, retrying transport errors, 429 and 5xx responses
+func deliverWebhook(l logrus.FieldLogger, tenantId uuid.UUID, sub Subscription, event []byte) {
+	p := HttpRequestPayload{
+		Method:      http.MethodPost,
+		Url:         sub.WebhookUrl,
+		Headers:     map[string]string{"TENANT_ID": tenantId.String(), "SUBSCRIPTION_ID": sub.Id.String()},
+		Body:        json.RawMessage(event),
+		MaxAttempts: subscriptionWebhookAttempts,
+	}
+	if _, err := executeHttpRequest(context.Background(), GetHttpRequestConfig(), p); err != nil {
+		l.WithError(err).Warnf("Unable to deliver saga status event to webhook [%s].", sub.WebhookUrl)
+	}
+}
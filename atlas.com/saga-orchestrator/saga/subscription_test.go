@@ -0,0 +1,181 @@
+package saga
+
+import (
+	"atlas-saga-orchestrator/character/mock"
+	"atlas-saga-orchestrator/kafka/message/saga"
+	"atlas-saga-orchestrator/kafka/producer"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Chronicle20/atlas-constants/channel"
+	"github.com/Chronicle20/atlas-constants/world"
+	producer2 "github.com/Chronicle20/atlas-kafka/producer"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSubscriptionMatches tests that sagas match subscriptions by initiator, saga type and labels
+func TestSubscriptionMatches(t *testing.T) {
+	s := NewBuilder().
+		SetSagaType(QuestReward).
+		SetInitiatedBy("quest-service").
+		SetLabel("event", "halloween2025").
+		Build()
+
+	tests := []struct {
+		name     string
+		sub      Subscription
+		expected bool
+	}{
+		{name: "no filter", sub: Subscription{}, expected: true},
+		{name: "initiator", sub: Subscription{InitiatedBy: "quest-service"}, expected: true},
+		{name: "other initiator", sub: Subscription{InitiatedBy: "npc-service"}, expected: false},
+		{name: "saga type", sub: Subscription{SagaTypes: []Type{InventoryTransaction, QuestReward}}, expected: true},
+		{name: "other saga type", sub: Subscription{SagaTypes: []Type{InventoryTransaction}}, expected: false},
+		{name: "labels", sub: Subscription{Labels: map[string]string{"event": "halloween2025"}}, expected: true},
+		{name: "other labels", sub: Subscription{Labels: map[string]string{"event": "christmas2025"}}, expected: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.sub.Matches(s))
+		})
+	}
+}
+
+// TestSubscriptionValidate tests that subscriptions are only accepted with a destination events can be delivered to
+func TestSubscriptionValidate(t *testing.T) {
+	defer InitHttpRequestConfig(HttpRequestConfig{})
+	InitHttpRequestConfig(HttpRequestConfig{AllowedHosts: []string{"quest-service"}})
+	t.Setenv("EVENT_TOPIC_SAGA_STATUS_QUEST", "saga.status.quest")
+
+	assert.NoError(t, Subscription{Topic: "EVENT_TOPIC_SAGA_STATUS_QUEST"}.Validate())
+	assert.NoError(t, Subscription{WebhookUrl: "http://quest-service/sagas/events"}.Validate())
+
+	for name, sub := range map[string]Subscription{
+		"no destination":       {InitiatedBy: "quest-service"},
+		"unconfigured topic":   {Topic: "EVENT_TOPIC_SAGA_STATUS_NPC"},
+		"disallowed webhook":   {WebhookUrl: "http://npc-service/sagas/events"},
+		"invalid webhook":      {WebhookUrl: "quest-service"},
+		"invalid label filter": {Topic: "EVENT_TOPIC_SAGA_STATUS_QUEST", Labels: map[string]string{"": "halloween2025"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.True(t, errors.Is(sub.Validate(), ErrInvalidSubscription))
+		})
+	}
+}
+
+// TestPublishToSubscribers tests that the status events of finished sagas are delivered to the topics and webhooks of
+// the subscriptions they match, alongside the firehose
+func TestPublishToSubscribers(t *testing.T) {
+	te, ctx := setupContext()
+
+	var mutex sync.Mutex
+	tokens := make([]string, 0)
+	ctx = producer.WithProvider(ctx, func(token string) producer2.MessageProducer {
+		return func(provider model.Provider[[]kafka.Message]) error {
+			if _, err := provider(); err != nil {
+				return err
+			}
+			mutex.Lock()
+			defer mutex.Unlock()
+			if token == saga.EnvStatusEventTopic || token == "EVENT_TOPIC_SAGA_STATUS_QUEST" {
+				tokens = append(tokens, token)
+			}
+			return nil
+		}
+	})
+
+	type delivery struct {
+		tenantId       string
+		subscriptionId string
+		event          saga.StatusEvent[saga.StatusEventCompletedBody]
+	}
+	deliveries := make(chan delivery, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		d := delivery{tenantId: r.Header.Get("TENANT_ID"), subscriptionId: r.Header.Get("SUBSCRIPTION_ID")}
+		_ = json.NewDecoder(r.Body).Decode(&d.event)
+		deliveries <- d
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	su, _ := url.Parse(server.URL)
+	defer InitHttpRequestConfig(HttpRequestConfig{})
+	InitHttpRequestConfig(HttpRequestConfig{AllowedHosts: []string{su.Host}, Timeout: time.Second})
+
+	topicSub := GetSubscriptionRegistry().Add(te.Id(), Subscription{InitiatedBy: "quest-service", SagaTypes: []Type{QuestReward}, Topic: "EVENT_TOPIC_SAGA_STATUS_QUEST"})
+	webhookSub := GetSubscriptionRegistry().Add(te.Id(), Subscription{Labels: map[string]string{"event": "halloween2025"}, WebhookUrl: server.URL + "/sagas/events"})
+	defer GetSubscriptionRegistry().Remove(te.Id(), topicSub.Id)
+	defer GetSubscriptionRegistry().Remove(te.Id(), webhookSub.Id)
+	assert.Equal(t, []Subscription{webhookSub, topicSub}, GetSubscriptionRegistry().GetAll(te.Id()))
+	assert.Empty(t, GetSubscriptionRegistry().GetAll(uuid.New()))
+
+	charP := &mock.ProcessorMock{
+		AwardMesosAndEmitFunc: func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, actorId uint32, actorType string, amount int32) error {
+			return nil
+		},
+	}
+	processor, _ := setupTestProcessor(ctx, charP, nil)
+	complete := func(t *testing.T, b *Builder) Saga {
+		mutex.Lock()
+		tokens = tokens[:0]
+		mutex.Unlock()
+		s := b.AddStep("mesos", Pending, AwardMesos, AwardMesosPayload{CharacterId: 12345, ActorType: "NPC", Amount: 1000}).Build()
+		require.NoError(t, processor.Put(s))
+		require.NoError(t, processor.StepCompleted(s.TransactionId, true))
+		return s
+	}
+
+	t.Run("matching subscriptions receive the event", func(t *testing.T) {
+		s := complete(t, NewBuilder().SetSagaType(QuestReward).SetInitiatedBy("quest-service").SetLabel("event", "halloween2025"))
+		assert.Equal(t, []string{"EVENT_TOPIC_SAGA_STATUS_QUEST", saga.EnvStatusEventTopic}, tokens)
+
+		select {
+		case d := <-deliveries:
+			assert.Equal(t, te.Id().String(), d.tenantId)
+			assert.Equal(t, webhookSub.Id.String(), d.subscriptionId)
+			assert.Equal(t, s.TransactionId, d.event.TransactionId)
+			assert.Equal(t, saga.StatusEventTypeCompleted, d.event.Type)
+		case <-time.After(time.Second):
+			t.Fatal("webhook was not called")
+		}
+	})
+
+	t.Run("other sagas are only on the firehose", func(t *testing.T) {
+		complete(t, NewBuilder().SetSagaType(InventoryTransaction).SetInitiatedBy("quest-service"))
+		assert.Equal(t, []string{saga.EnvStatusEventTopic}, tokens)
+
+		select {
+		case <-deliveries:
+			t.Fatal("webhook was called")
+		case <-time.After(50 * time.Millisecond):
+		}
+	})
+
+	t.Run("shadow replicas deliver no webhooks", func(t *testing.T) {
+		InitShadowConfig(ShadowConfig{Enabled: true})
+		defer InitShadowConfig(ShadowConfig{})
+		complete(t, NewBuilder().SetSagaType(QuestReward).SetLabel("event", "halloween2025"))
+
+		select {
+		case <-deliveries:
+			t.Fatal("webhook was called")
+		case <-time.After(50 * time.Millisecond):
+		}
+	})
+
+	t.Run("removed subscriptions receive nothing", func(t *testing.T) {
+		assert.True(t, GetSubscriptionRegistry().Remove(te.Id(), topicSub.Id))
+		assert.False(t, GetSubscriptionRegistry().Remove(te.Id(), topicSub.Id))
+		complete(t, NewBuilder().SetSagaType(QuestReward).SetInitiatedBy("quest-service"))
+		assert.Equal(t, []string{saga.EnvStatusEventTopic}, tokens)
+	})
+}
@@ -0,0 +1,245 @@
+package saga
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// ErrPayloadTemplate is returned when a step payload template cannot be rendered
+var ErrPayloadTemplate = errors.New("unable to render payload template")
+
+// jsonPathPrefix identifies payload strings which are JSONPath expressions, rather than literals
+const jsonPathPrefix = "$."
+
+// IsPayloadTemplate returns whether a raw payload contains template expressions, being strings containing a Go
+// template action (e.g. "{{ .variables.characterId }}") or strings which are a JSONPath expression
+// (e.g. "$.steps.draw.resolved.mesos").
+func IsPayloadTemplate(raw []byte) bool {
+	if !bytes.Contains(raw, []byte("{{")) && !bytes.Contains(raw, []byte(jsonPathPrefix)) {
+		return false
+	}
+	var v any
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return false
+	}
+	return containsTemplate(v)
+}
+
+func containsTemplate(v any) bool {
+	switch t := v.(type) {
+	case string:
+		return strings.Contains(t, "{{") || strings.HasPrefix(t, jsonPathPrefix)
+	case map[string]any:
+		for _, e := range t {
+			if containsTemplate(e) {
+				return true
+			}
+		}
+	case []any:
+		for _, e := range t {
+			if containsTemplate(e) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// templateContext returns the document templates are evaluated against. It exposes the saga's transactionId,
+// initiatedBy, labels and variables, and the payloads of the steps preceding the step being rendered by step id,
+// which carry the results recorded while executing them (e.g. a resolved prize).
+func templateContext(s Saga, stepId string) (map[string]any, error) {
+	steps := make(map[string]any)
+	for _, st := range s.Steps {
+		if st.StepId == stepId {
+			break
+		}
+		bs, err := json.Marshal(st.Payload)
+		if err != nil {
+			return nil, err
+		}
+		var p any
+		if err = json.Unmarshal(bs, &p); err != nil {
+			return nil, err
+		}
+		steps[st.StepId] = p
+	}
+
+	variables := make(map[string]any, len(s.Variables))
+	for k, v := range s.Variables {
+		variables[k] = v
+	}
+	labels := make(map[string]any, len(s.Labels))
+	for k, v := range s.Labels {
+		labels[k] = v
+	}
+
+	return map[string]any{
+		"transactionId": s.TransactionId.String(),
+		"initiatedBy":   s.InitiatedBy,
+		"labels":        labels,
+		"variables":     variables,
+		"steps":         steps,
+	}, nil
+}
+
+// RenderPayload renders the payload template of a step against the saga, returning the payload typed according to
+// the step action. Strings which consist of a single expression take on the type of its result, so templates may
+// populate numeric fields, while expressions embedded in longer strings are substituted as text.
+func RenderPayload(s Saga, st Step[any]) (any, error) {
+	ctx, err := templateContext(s, st.StepId)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrPayloadTemplate, err.Error())
+	}
+	return renderPayloadTemplate(st.Action, st.PayloadTemplate, ctx)
+}
+
+// renderPayloadTemplate renders a payload template of the action against the document given, returning the payload
+// typed according to the action
+func renderPayloadTemplate(action Action, tmpl json.RawMessage, ctx map[string]any) (any, error) {
+	var raw any
+	if err := json.Unmarshal(tmpl, &raw); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrPayloadTemplate, err.Error())
+	}
+	rendered, err := renderValue(raw, ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrPayloadTemplate, err.Error())
+	}
+	bs, err := json.Marshal(rendered)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrPayloadTemplate, err.Error())
+	}
+
+	// Defer to the step decoding so the rendered payload is typed identically to a literal one
+	var rs Step[any]
+	if err = json.Unmarshal([]byte(fmt.Sprintf(`{"action":%q,"payload":%s}`, action, bs)), &rs); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrPayloadTemplate, err.Error())
+	}
+	if len(rs.PayloadTemplate) > 0 {
+		return nil, fmt.Errorf("%w: rendered payload contains template expressions", ErrPayloadTemplate)
+	}
+	return rs.Payload, nil
+}
+
+func renderValue(v any, ctx map[string]any) (any, error) {
+	switch t := v.(type) {
+	case string:
+		return renderString(t, ctx)
+	case map[string]any:
+		r := make(map[string]any, len(t))
+		for k, e := range t {
+			re, err := renderValue(e, ctx)
+			if err != nil {
+				return nil, err
+			}
+			r[k] = re
+		}
+		return r, nil
+	case []any:
+		r := make([]any, 0, len(t))
+		for _, e := range t {
+			re, err := renderValue(e, ctx)
+			if err != nil {
+				return nil, err
+			}
+			r = append(r, re)
+		}
+		return r, nil
+	}
+	return v, nil
+}
+
+func renderString(v string, ctx map[string]any) (any, error) {
+	if strings.HasPrefix(v, jsonPathPrefix) {
+		return evaluateJSONPath(v, ctx)
+	}
+	if !strings.Contains(v, "{{") {
+		return v, nil
+	}
+
+	tmpl, err := template.New("payload").Option("missingkey=error").Parse(v)
+	if err != nil {
+		return nil, err
+	}
+	var out bytes.Buffer
+	if err = tmpl.Execute(&out, ctx); err != nil {
+		return nil, err
+	}
+
+	// A string which is a single action takes on the type of its result when it is a number or boolean
+	trimmed := strings.TrimSpace(v)
+	if strings.HasPrefix(trimmed, "{{") && strings.HasSuffix(trimmed, "}}") && strings.Count(trimmed, "{{") == 1 {
+		if n, err := strconv.ParseFloat(out.String(), 64); err == nil {
+			return n, nil
+		}
+		if b, err := strconv.ParseBool(out.String()); err == nil {
+			return b, nil
+		}
+	}
+	return out.String(), nil
+}
+
+// evaluateJSONPath resolves a JSONPath expression of member (.name or ['name']) and index ([n]) segments
+func evaluateJSONPath(path string, root any) (any, error) {
+	if !strings.HasPrefix(path, jsonPathPrefix) {
+		return nil, fmt.Errorf("invalid path '%s'", path)
+	}
+	cur := root
+	rest := path[1:]
+	for rest != "" {
+		switch {
+		case strings.HasPrefix(rest, "."):
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+			name := rest[:end]
+			rest = rest[end:]
+			if name == "" {
+				return nil, fmt.Errorf("invalid path '%s'", path)
+			}
+			m, ok := cur.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("path '%s' member '%s' is not of an object", path, name)
+			}
+			if cur, ok = m[name]; !ok {
+				return nil, fmt.Errorf("path '%s' member '%s' does not exist", path, name)
+			}
+		case strings.HasPrefix(rest, "["):
+			end := strings.Index(rest, "]")
+			if end == -1 {
+				return nil, fmt.Errorf("invalid path '%s'", path)
+			}
+			segment := rest[1:end]
+			rest = rest[end+1:]
+			if name, err := strconv.Unquote(strings.ReplaceAll(segment, "'", "\"")); err == nil {
+				m, ok := cur.(map[string]any)
+				if !ok {
+					return nil, fmt.Errorf("path '%s' member '%s' is not of an object", path, name)
+				}
+				if cur, ok = m[name]; !ok {
+					return nil, fmt.Errorf("path '%s' member '%s' does not exist", path, name)
+				}
+				continue
+			}
+			i, err := strconv.Atoi(segment)
+			if err != nil {
+				return nil, fmt.Errorf("invalid path '%s'", path)
+			}
+			a, ok := cur.([]any)
+			if !ok || i < 0 || i >= len(a) {
+				return nil, fmt.Errorf("path '%s' index [%d] does not exist", path, i)
+			}
+			cur = a[i]
+		default:
+			return nil, fmt.Errorf("invalid path '%s'", path)
+		}
+	}
+	return cur, nil
+}
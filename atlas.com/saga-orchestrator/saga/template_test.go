@@ -0,0 +1,100 @@
+package saga
+
+import (
+	"atlas-saga-orchestrator/character/mock"
+	"encoding/json"
+	"github.com/Chronicle20/atlas-constants/channel"
+	"github.com/Chronicle20/atlas-constants/world"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func templateSaga(template string) Saga {
+	return Saga{
+		TransactionId: uuid.New(),
+		SagaType:      QuestReward,
+		InitiatedBy:   "npc-9010000",
+		Variables:     map[string]any{"characterId": float64(12345), "reason": "quest"},
+		Steps: []Step[any]{
+			{StepId: "draw", Status: Completed, Action: ResolvePrizeTable, Payload: ResolvePrizeTablePayload{
+				CharacterId: 12345,
+				Prizes:      []PrizeEntry{{Weight: 1, Mesos: 500}},
+				Resolved:    &PrizeEntry{Weight: 1, Mesos: 500},
+			}},
+			{StepId: "award", Status: Pending, Action: AwardMesos, PayloadTemplate: json.RawMessage(template)},
+		},
+	}
+}
+
+// TestIsPayloadTemplate tests detection of payloads containing template expressions
+func TestIsPayloadTemplate(t *testing.T) {
+	assert.True(t, IsPayloadTemplate([]byte(`{"characterId": "{{ .variables.characterId }}"}`)))
+	assert.True(t, IsPayloadTemplate([]byte(`{"item": {"templateId": "$.variables.templateId"}}`)))
+	assert.False(t, IsPayloadTemplate([]byte(`{"characterId": 12345, "actorType": "NPC"}`)))
+	assert.False(t, IsPayloadTemplate([]byte(`null`)))
+}
+
+// TestRenderPayload tests rendering payload templates against saga variables and prior step results
+func TestRenderPayload(t *testing.T) {
+	t.Run("expressions take on the type of their result", func(t *testing.T) {
+		s := templateSaga(`{"characterId": "{{ .variables.characterId }}", "actorType": "SYSTEM", "amount": "$.steps.draw.resolved.mesos"}`)
+		payload, err := RenderPayload(s, s.Steps[1])
+		require.NoError(t, err)
+		assert.Equal(t, AwardMesosPayload{CharacterId: 12345, ActorType: "SYSTEM", Amount: 500}, payload)
+	})
+
+	t.Run("embedded expressions are substituted as text", func(t *testing.T) {
+		s := templateSaga(`{"characterId": "$.variables.characterId", "actorType": "{{ .variables.reason }}-{{ .initiatedBy }}", "amount": 1}`)
+		payload, err := RenderPayload(s, s.Steps[1])
+		require.NoError(t, err)
+		assert.Equal(t, "quest-npc-9010000", payload.(AwardMesosPayload).ActorType)
+	})
+
+	t.Run("missing references fail", func(t *testing.T) {
+		s := templateSaga(`{"characterId": "{{ .variables.missing }}"}`)
+		_, err := RenderPayload(s, s.Steps[1])
+		assert.ErrorIs(t, err, ErrPayloadTemplate)
+
+		s = templateSaga(`{"characterId": "$.steps.award.characterId"}`)
+		_, err = RenderPayload(s, s.Steps[1])
+		assert.ErrorIs(t, err, ErrPayloadTemplate)
+	})
+}
+
+// TestStepUnmarshalPayloadTemplate tests that templated payloads are retained untyped until dispatch
+func TestStepUnmarshalPayloadTemplate(t *testing.T) {
+	var st Step[any]
+	err := json.Unmarshal([]byte(`{"stepId": "award", "status": "pending", "action": "award_mesos", "payload": {"characterId": "$.variables.characterId", "amount": 100}}`), &st)
+	require.NoError(t, err)
+	assert.Nil(t, st.Payload)
+	assert.JSONEq(t, `{"characterId": "$.variables.characterId", "amount": 100}`, string(st.PayloadTemplate))
+}
+
+// TestStepRendersPayloadTemplate tests that the payload template of a step is rendered when it is dispatched
+func TestStepRendersPayloadTemplate(t *testing.T) {
+	te, ctx := setupContext()
+
+	var awarded int32
+	charP := &mock.ProcessorMock{
+		AwardMesosAndEmitFunc: func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, actorId uint32, actorType string, amount int32) error {
+			assert.Equal(t, uint32(12345), characterId)
+			awarded = amount
+			return nil
+		},
+	}
+	processor, _ := setupTestProcessor(ctx, charP, nil)
+
+	s := templateSaga(`{"characterId": "$.variables.characterId", "actorType": "SYSTEM", "amount": "{{ .steps.draw.resolved.mesos }}"}`)
+	GetCache().Put(te.Id(), s)
+	defer GetCache().Remove(te.Id(), s.TransactionId)
+
+	require.NoError(t, processor.Step(s.TransactionId))
+	assert.Equal(t, int32(500), awarded)
+
+	// The rendered payload is recorded with the step
+	rs, err := processor.GetById(s.TransactionId)
+	require.NoError(t, err)
+	assert.Equal(t, int32(500), rs.Steps[1].Payload.(AwardMesosPayload).Amount)
+}
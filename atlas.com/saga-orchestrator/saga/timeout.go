@@ -0,0 +1,141 @@
+package saga
+
+import (
+	"atlas-saga-orchestrator/kafka/message/saga"
+	"atlas-saga-orchestrator/metrics"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	tenant "github.com/Chronicle20/atlas-tenant"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrorCodeStepTimeout is the error code a step fails with when the event completing it does not arrive within the
+// step's timeout, such as when the event was lost, so that its error handlers may declare a fallback
+const ErrorCodeStepTimeout = "STEP_TIMEOUT"
+
+// RoleStepTimeoutReaper is the role of the replica failing timed out steps. Timed out steps are found across replicas
+// through the archive, so the reaper runs on only one.
+const RoleStepTimeoutReaper = "step_timeout_reaper"
+
+// Deadline returns when the step's latest attempt times out, being the attempt's dispatch plus the step's timeout.
+// Steps without a timeout, which have not been dispatched, or whose latest attempt is parked for redelivery have none.
+func (st Step[T]) Deadline() (time.Time, bool) {
+	if st.Timeout == 0 || len(st.Attempts) == 0 {
+		return time.Time{}, false
+	}
+	a := st.Attempts[len(st.Attempts)-1]
+	if a.RetryAt != nil {
+		return time.Time{}, false
+	}
+	return a.DispatchedAt.Add(time.Duration(st.Timeout) * time.Second), true
+}
+
+// timedOut returns the step of the saga which has timed out, if any. Only the current step of a saga which is neither
+// compensating nor held awaits an event, so only it may time out.
+func timedOut(s Saga, now time.Time) (Step[any], bool) {
+	if s.Failing() || s.Held() {
+		return Step[any]{}, false
+	}
+	st, ok := s.GetCurrentStep()
+	if !ok {
+		return Step[any]{}, false
+	}
+	deadline, ok := st.Deadline()
+	if !ok || now.Before(deadline) {
+		return Step[any]{}, false
+	}
+	return st, true
+}
+
+// findTimedOut returns the cached sagas whose current step has timed out, by tenant. The sagas of other replicas are
+// expired by them, as forwarded by forwardDue.
+func findTimedOut(now time.Time) map[uuid.UUID][]Saga {
+	r := make(map[uuid.UUID][]Saga)
+	for _, tenantId := range GetCache().Tenants() {
+		for _, s := range GetCache().GetAll(tenantId) {
+			if _, ok := timedOut(s, now); ok {
+				r[tenantId] = append(r[tenantId], s)
+			}
+		}
+	}
+	return r
+}
+
+var timeouts *metrics.Counter
+var timeoutsOnce sync.Once
+
+// getTimeouts returns the counter of steps failed for timing out, by tenant and action
+func getTimeouts() *metrics.Counter {
+	timeoutsOnce.Do(func() {
+		timeouts = metrics.GetRegistry().RegisterCounter("saga_step_timeouts_total", "Number of saga steps failed for the event completing them not arriving within their timeout, by action.")
+	})
+	return timeouts
+}
+
+// expireStep fails the step of the saga should it remain current and timed out, so the saga is compensated unless the
+// step's error handlers declare otherwise. The step's attempt is abandoned, as its action may have taken effect with
+// only the event completing it lost, so compensation reverses it. Steps which have since progressed, or sagas which are
+// compensating or no longer exist, are left alone.
+func expireStep(l logrus.FieldLogger, p Processor, tenantId uuid.UUID, transactionId uuid.UUID, stepId string, now time.Time) {
+	s, err := p.GetById(transactionId)
+	if err != nil {
+		return
+	}
+	st, ok := timedOut(s, now)
+	if !ok || st.StepId != stepId {
+		return
+	}
+
+	fl := l.WithFields(logrus.Fields{
+		"transaction_id": transactionId.String(),
+		"saga_type":      s.SagaType,
+		"step_id":        stepId,
+		"tenant_id":      tenantId.String(),
+	})
+	fl.Warnf("Saga step did not complete within [%d] seconds. Failing step.", st.Timeout)
+	getTimeouts().Inc(map[string]string{"tenant_id": tenantId.String(), "action": string(st.Action)})
+	if err = p.StepAbandoned(transactionId, ErrorCodeStepTimeout, fmt.Sprintf("step did not complete within [%d] seconds", st.Timeout)); err != nil {
+		fl.WithError(err).Error("Unable to apply saga step timeout.")
+	}
+}
+
+// StepTimeoutTask fails the steps whose completing event has not arrived within their timeout, so a saga awaiting an
+// event which was lost is compensated rather than left pending indefinitely. Leading several replicas, it fails the
+// steps of the sagas it owns, and has the others fail theirs.
+type StepTimeoutTask struct {
+	l        logrus.FieldLogger
+	interval time.Duration
+}
+
+// NewStepTimeoutTask creates a task failing timed out steps, checking for them at the interval
+func NewStepTimeoutTask(l logrus.FieldLogger, interval time.Duration) *StepTimeoutTask {
+	// Initialize the counter, so it is reported before any step times out
+	getTimeouts()
+	return &StepTimeoutTask{l: l, interval: interval}
+}
+
+func (t *StepTimeoutTask) Run() {
+	now := time.Now()
+	for tenantId, sagas := range findTimedOut(now) {
+		te, ok := tenantById(tenantId)
+		if !ok {
+			continue
+		}
+		p := NewProcessor(t.l, tenant.WithContext(context.Background(), te))
+		for _, s := range sagas {
+			st, _ := s.GetCurrentStep()
+			expireStep(t.l, p, tenantId, s.TransactionId, st.StepId, now)
+		}
+	}
+	if _, err := forwardDue(t.l, context.Background(), now, saga.StepDueTypeTimedOut, timedOut); err != nil {
+		t.l.WithError(err).Error("Unable to find timed out saga steps.")
+	}
+}
+
+func (t *StepTimeoutTask) SleepTime() time.Duration {
+	return t.interval
+}
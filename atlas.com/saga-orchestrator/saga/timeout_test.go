@@ -0,0 +1,92 @@
+package saga
+
+import (
+	"atlas-saga-orchestrator/character/mock"
+	"testing"
+	"time"
+
+	"github.com/Chronicle20/atlas-constants/channel"
+	"github.com/Chronicle20/atlas-constants/world"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestStepDeadline tests that steps time out relative to the dispatch of their latest attempt
+func TestStepDeadline(t *testing.T) {
+	now := time.Now()
+	retryAt := now.Add(time.Second)
+
+	tests := []struct {
+		name     string
+		step     Step[any]
+		deadline time.Time
+		ok       bool
+	}{
+		{name: "no timeout", step: Step[any]{Attempts: []StepAttempt{{Attempt: 1, DispatchedAt: now}}}},
+		{name: "not dispatched", step: Step[any]{Timeout: 30}},
+		{name: "dispatched", step: Step[any]{Timeout: 30, Attempts: []StepAttempt{{Attempt: 1, DispatchedAt: now.Add(-time.Minute)}, {Attempt: 2, DispatchedAt: now}}}, deadline: now.Add(30 * time.Second), ok: true},
+		{name: "parked", step: Step[any]{Timeout: 30, Attempts: []StepAttempt{{Attempt: 1, DispatchedAt: now, RetryAt: &retryAt}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deadline, ok := tt.step.Deadline()
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.deadline, deadline)
+		})
+	}
+}
+
+// TestStepTimeout tests that a step whose completing event does not arrive within its timeout is failed, compensating
+// the saga, while steps within their timeout are left pending
+func TestStepTimeout(t *testing.T) {
+	te, ctx := setupContext()
+
+	charP := &mock.ProcessorMock{
+		AwardMesosAndEmitFunc: func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, actorId uint32, actorType string, amount int32) error {
+			return nil
+		},
+	}
+	processor, _ := setupTestProcessor(ctx, charP, nil)
+	l := processor.(*ProcessorImpl).l
+
+	s := NewBuilder().
+		SetSagaType(QuestReward).
+		AddStep("mesos", Pending, AwardMesos, AwardMesosPayload{CharacterId: 12345, ActorType: "NPC", Amount: 1000}).
+		SetTimeout(30).
+		Build()
+	done, unsubscribe := GetNotifier().Subscribe(te.Id(), s.TransactionId)
+	defer unsubscribe()
+	require.NoError(t, processor.Put(s))
+	defer GetCache().Remove(te.Id(), s.TransactionId)
+
+	s, err := processor.GetById(s.TransactionId)
+	require.NoError(t, err)
+	deadline, ok := s.Steps[0].Deadline()
+	require.True(t, ok)
+
+	t.Run("step within its timeout is left pending", func(t *testing.T) {
+		assert.NotContains(t, findTimedOut(deadline.Add(-time.Second))[te.Id()], s)
+		expireStep(l, processor, te.Id(), s.TransactionId, "mesos", deadline.Add(-time.Second))
+		s, err := processor.GetById(s.TransactionId)
+		require.NoError(t, err)
+		assert.Equal(t, Pending, s.Steps[0].Status)
+	})
+
+	t.Run("timed out step fails", func(t *testing.T) {
+		assert.Contains(t, findTimedOut(deadline)[te.Id()], s)
+		expireStep(l, processor, te.Id(), s.TransactionId, "mesos", deadline)
+		select {
+		case s = <-done:
+		case <-time.After(time.Second):
+			t.Fatal("saga did not fail")
+		}
+		assert.NotEqual(t, Completed, s.Steps[0].Status)
+		assert.Equal(t, ErrorCodeStepTimeout, s.Steps[0].Attempts[len(s.Steps[0].Attempts)-1].ErrorCode)
+		// The award may have taken effect, so is not treated as rejected
+		assert.True(t, s.Steps[0].Attempts[len(s.Steps[0].Attempts)-1].Abandoned)
+		assert.False(t, s.Steps[0].ReportedError())
+		require.NotNil(t, s.Receipt)
+		assert.Equal(t, ErrorCodeStepTimeout, s.Receipt.ErrorCode)
+	})
+}
@@ -0,0 +1,241 @@
+package saga
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Chronicle20/atlas-tenant"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"sync"
+	"time"
+)
+
+// ErrorCodeQuestTimerExpired is the error code the current step of a saga fails with when a quest timer expires before
+// the saga completes, so that its error handlers may declare a fallback
+const ErrorCodeQuestTimerExpired = "QUEST_TIMER_EXPIRED"
+
+// ErrInvalidTimer is returned when a saga has a quest timer which could never expire
+var ErrInvalidTimer = errors.New("invalid quest timer")
+
+// ValidateTimers validates the saga's quest timers. Countdowns are cancelled as their saga completes, so a timer started
+// by the saga's last step would never expire.
+func (s Saga) ValidateTimers() error {
+	if n := len(s.Steps); n > 0 && s.Steps[n-1].Action == SetQuestTimer {
+		return fmt.Errorf("%w: step '%s' is the saga's last, so its countdown would be cancelled as the saga completes", ErrInvalidTimer, s.Steps[n-1].StepId)
+	}
+	return nil
+}
+
+// TimerRegistry is an interface for tracking server-side countdowns tied to sagas
+type TimerRegistry interface {
+	// Start starts a countdown for a saga step, invoking onExpire when the duration elapses
+	Start(tenantId uuid.UUID, transactionId uuid.UUID, stepId string, duration time.Duration, onExpire func())
+
+	// Cancel stops all countdowns for a saga, returning the number of countdowns cancelled
+	Cancel(tenantId uuid.UUID, transactionId uuid.UUID) int
+
+	// Stop stops the countdown for a saga step, returning whether it had one
+	Stop(tenantId uuid.UUID, transactionId uuid.UUID, stepId string) bool
+
+	// Deadline returns the expiry time of a countdown for a saga step
+	Deadline(tenantId uuid.UUID, transactionId uuid.UUID, stepId string) (time.Time, bool)
+}
+
+type timerEntry struct {
+	id       uint64
+	timer    *time.Timer
+	deadline time.Time
+}
+
+// InMemoryTimerRegistry is an in-memory implementation of the TimerRegistry interface
+type InMemoryTimerRegistry struct {
+	// timers is a map of tenant IDs to maps of transaction IDs to step timers
+	timers map[uuid.UUID]map[uuid.UUID]map[string]timerEntry
+
+	// sequence distinguishes a countdown from any which later replace it
+	sequence uint64
+
+	// mutex is used to synchronize access to the registry
+	mutex sync.Mutex
+}
+
+// Singleton instance of the timer registry
+var timerInstance *InMemoryTimerRegistry
+var timerOnce sync.Once
+
+// GetTimerRegistry returns the singleton instance of the timer registry
+func GetTimerRegistry() TimerRegistry {
+	timerOnce.Do(func() {
+		timerInstance = &InMemoryTimerRegistry{
+			timers: make(map[uuid.UUID]map[uuid.UUID]map[string]timerEntry),
+		}
+	})
+	return timerInstance
+}
+
+// ResetTimerRegistry stops all countdowns and resets the singleton timer registry for testing
+func ResetTimerRegistry() {
+	r := GetTimerRegistry().(*InMemoryTimerRegistry)
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, sagas := range r.timers {
+		for _, steps := range sagas {
+			for _, e := range steps {
+				e.timer.Stop()
+			}
+		}
+	}
+	r.timers = make(map[uuid.UUID]map[uuid.UUID]map[string]timerEntry)
+}
+
+// Start starts a countdown for a saga step. Starting a countdown for a step which already has one replaces it.
+func (r *InMemoryTimerRegistry) Start(tenantId uuid.UUID, transactionId uuid.UUID, stepId string, duration time.Duration, onExpire func()) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.timers[tenantId]; !exists {
+		r.timers[tenantId] = make(map[uuid.UUID]map[string]timerEntry)
+	}
+	if _, exists := r.timers[tenantId][transactionId]; !exists {
+		r.timers[tenantId][transactionId] = make(map[string]timerEntry)
+	}
+	if e, exists := r.timers[tenantId][transactionId][stepId]; exists {
+		e.timer.Stop()
+	}
+
+	r.sequence++
+	id := r.sequence
+	t := time.AfterFunc(duration, func() {
+		if !r.expire(tenantId, transactionId, stepId, id) {
+			return
+		}
+		onExpire()
+	})
+	r.timers[tenantId][transactionId][stepId] = timerEntry{id: id, timer: t, deadline: time.Now().Add(duration)}
+}
+
+// expire removes a fired countdown from the registry, returning false if it was cancelled or replaced in the meantime
+func (r *InMemoryTimerRegistry) expire(tenantId uuid.UUID, transactionId uuid.UUID, stepId string, id uint64) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	e, exists := r.timers[tenantId][transactionId][stepId]
+	if !exists || e.id != id {
+		return false
+	}
+	delete(r.timers[tenantId][transactionId], stepId)
+	if len(r.timers[tenantId][transactionId]) == 0 {
+		delete(r.timers[tenantId], transactionId)
+	}
+	return true
+}
+
+// Cancel stops all countdowns for a saga
+func (r *InMemoryTimerRegistry) Cancel(tenantId uuid.UUID, transactionId uuid.UUID) int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	steps, exists := r.timers[tenantId][transactionId]
+	if !exists {
+		return 0
+	}
+	for _, e := range steps {
+		e.timer.Stop()
+	}
+	delete(r.timers[tenantId], transactionId)
+	return len(steps)
+}
+
+// Stop stops the countdown for a saga step
+func (r *InMemoryTimerRegistry) Stop(tenantId uuid.UUID, transactionId uuid.UUID, stepId string) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	e, exists := r.timers[tenantId][transactionId][stepId]
+	if !exists {
+		return false
+	}
+	e.timer.Stop()
+	delete(r.timers[tenantId][transactionId], stepId)
+	if len(r.timers[tenantId][transactionId]) == 0 {
+		delete(r.timers[tenantId], transactionId)
+	}
+	return true
+}
+
+// Deadline returns the expiry time of a countdown for a saga step
+func (r *InMemoryTimerRegistry) Deadline(tenantId uuid.UUID, transactionId uuid.UUID, stepId string) (time.Time, bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	e, exists := r.timers[tenantId][transactionId][stepId]
+	if !exists {
+		return time.Time{}, false
+	}
+	return e.deadline, true
+}
+
+// ExpiryHandler handles the expiry of a saga step's countdown
+type ExpiryHandler func(l logrus.FieldLogger, ctx context.Context, transactionId uuid.UUID, stepId string)
+
+// startDetachedTimer starts the countdown of a saga step. The countdown outlives the context of the request which
+// started it, so it expires with a context carrying only the tenant.
+func startDetachedTimer(l logrus.FieldLogger, t tenant.Model, transactionId uuid.UUID, stepId string, duration time.Duration, onExpire ExpiryHandler) {
+	ctx := tenant.WithContext(context.Background(), t)
+	GetTimerRegistry().Start(t.Id(), transactionId, stepId, duration, func() {
+		onExpire(l, ctx, transactionId, stepId)
+	})
+}
+
+// rearmTimers starts again the countdowns of a saga restored from the archive, which are held in memory by the replica
+// which started them, so they still expire once the saga is hydrated or moves between replicas. Deadlines are derived
+// from the steps, those of relative countdowns being measured from the dispatch of the step's latest attempt, and
+// countdowns whose deadline has passed expire at once. Compensating sagas have no countdowns.
+func rearmTimers(l logrus.FieldLogger, t tenant.Model, s Saga) {
+	if s.Failing() {
+		return
+	}
+	for _, st := range s.Steps {
+		payload, ok := st.Payload.(SetQuestTimerPayload)
+		if !ok || st.Status != Completed || len(st.Attempts) == 0 {
+			continue
+		}
+		deadline := st.Attempts[len(st.Attempts)-1].DispatchedAt.Add(time.Duration(payload.Duration) * time.Second)
+		startDetachedTimer(l, t, s.TransactionId, st.StepId, time.Until(deadline), func(l logrus.FieldLogger, ctx context.Context, transactionId uuid.UUID, stepId string) {
+			questTimerExpired(l, ctx, transactionId, stepId, payload)
+		})
+	}
+
+	st, ok := s.GetCurrentStep()
+	if !ok || len(st.Attempts) == 0 {
+		return
+	}
+	dispatchedAt := st.Attempts[len(st.Attempts)-1].DispatchedAt
+	after := func(seconds uint32) time.Duration {
+		return time.Until(dispatchedAt.Add(time.Duration(seconds) * time.Second))
+	}
+	switch payload := st.Payload.(type) {
+	case CreateInvitePayload:
+		if payload.Ttl > 0 {
+			startDetachedTimer(l, t, s.TransactionId, st.StepId, after(payload.Ttl), inviteExpired)
+		}
+	case AwaitKillCountPayload:
+		if payload.Timeout > 0 {
+			startDetachedTimer(l, t, s.TransactionId, st.StepId, after(payload.Timeout), killCountExpired)
+		}
+	case AwaitEscortPayload:
+		if payload.Timeout > 0 {
+			startDetachedTimer(l, t, s.TransactionId, st.StepId, after(payload.Timeout), escortExpired)
+		}
+	case ScheduleWarpPayload:
+		// Steps dispatched again at departure await the character's arrival, rather than their departure
+		if dispatchedAt.Before(payload.DepartsAt) {
+			startDetachedTimer(l, t, s.TransactionId, st.StepId, time.Until(payload.DepartsAt), departureReached)
+		}
+	case RemoveWorldEventBuffPayload:
+		if dispatchedAt.Before(payload.RemoveAt) {
+			startDetachedTimer(l, t, s.TransactionId, st.StepId, time.Until(payload.RemoveAt), eventBuffExpired)
+		}
+	}
+}
@@ -0,0 +1,386 @@
+package saga
+
+import (
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+// TestTimerRegistry tests starting, expiring and cancelling countdowns
+func TestTimerRegistry(t *testing.T) {
+	ResetTimerRegistry()
+	tenantId := uuid.New()
+
+	t.Run("expired countdown invokes callback and is removed", func(t *testing.T) {
+		transactionId := uuid.New()
+		expired := make(chan struct{})
+		GetTimerRegistry().Start(tenantId, transactionId, "timer-step", 10*time.Millisecond, func() {
+			close(expired)
+		})
+
+		_, ok := GetTimerRegistry().Deadline(tenantId, transactionId, "timer-step")
+		assert.True(t, ok)
+
+		select {
+		case <-expired:
+		case <-time.After(time.Second):
+			t.Fatal("countdown did not expire")
+		}
+
+		_, ok = GetTimerRegistry().Deadline(tenantId, transactionId, "timer-step")
+		assert.False(t, ok)
+	})
+
+	t.Run("cancelled countdown does not invoke callback", func(t *testing.T) {
+		transactionId := uuid.New()
+		expired := make(chan struct{})
+		GetTimerRegistry().Start(tenantId, transactionId, "timer-step", 20*time.Millisecond, func() {
+			close(expired)
+		})
+
+		assert.Equal(t, 1, GetTimerRegistry().Cancel(tenantId, transactionId))
+		assert.Equal(t, 0, GetTimerRegistry().Cancel(tenantId, transactionId))
+
+		select {
+		case <-expired:
+			t.Fatal("cancelled countdown expired")
+		case <-time.After(50 * time.Millisecond):
+		}
+	})
+
+	t.Run("stopped countdown of a step does not invoke callback", func(t *testing.T) {
+		transactionId := uuid.New()
+		expired := make(chan string, 2)
+		GetTimerRegistry().Start(tenantId, transactionId, "timer-step", 20*time.Millisecond, func() {
+			expired <- "timer-step"
+		})
+		GetTimerRegistry().Start(tenantId, transactionId, "other-step", 20*time.Millisecond, func() {
+			expired <- "other-step"
+		})
+
+		assert.True(t, GetTimerRegistry().Stop(tenantId, transactionId, "timer-step"))
+		assert.False(t, GetTimerRegistry().Stop(tenantId, transactionId, "timer-step"))
+
+		select {
+		case step := <-expired:
+			assert.Equal(t, "other-step", step)
+		case <-time.After(time.Second):
+			t.Fatal("countdown did not expire")
+		}
+		assert.Len(t, expired, 0)
+	})
+
+	t.Run("restarting a countdown replaces the original", func(t *testing.T) {
+		transactionId := uuid.New()
+		calls := make(chan string, 2)
+		GetTimerRegistry().Start(tenantId, transactionId, "timer-step", 20*time.Millisecond, func() {
+			calls <- "original"
+		})
+		GetTimerRegistry().Start(tenantId, transactionId, "timer-step", 30*time.Millisecond, func() {
+			calls <- "replacement"
+		})
+
+		select {
+		case c := <-calls:
+			assert.Equal(t, "replacement", c)
+		case <-time.After(time.Second):
+			t.Fatal("countdown did not expire")
+		}
+		assert.Len(t, calls, 0)
+	})
+}
+
+// TestHandleSetQuestTimer tests the handleSetQuestTimer function
+func TestHandleSetQuestTimer(t *testing.T) {
+	tests := []struct {
+		name          string
+		payload       any
+		expectTimer   bool
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name: "Success case - compensate on expiry",
+			payload: SetQuestTimerPayload{
+				CharacterId: 12345,
+				QuestId:     2001,
+				Duration:    600,
+				OnExpiry:    TimerExpiryCompensate,
+			},
+			expectTimer: true,
+		},
+		{
+			name: "Success case - follow up saga on expiry",
+			payload: SetQuestTimerPayload{
+				CharacterId: 12345,
+				QuestId:     2001,
+				Duration:    600,
+				OnExpiry:    TimerExpiryFollowUpSaga,
+				FollowUp:    &Saga{SagaType: QuestReward, InitiatedBy: "quest-timer"},
+			},
+			expectTimer: true,
+		},
+		{
+			name: "Error case - zero duration",
+			payload: SetQuestTimerPayload{
+				CharacterId: 12345,
+				QuestId:     2001,
+			},
+			expectError:   true,
+			errorContains: "duration must be greater than zero",
+		},
+		{
+			name: "Error case - missing follow up saga",
+			payload: SetQuestTimerPayload{
+				CharacterId: 12345,
+				QuestId:     2001,
+				Duration:    600,
+				OnExpiry:    TimerExpiryFollowUpSaga,
+			},
+			expectError:   true,
+			errorContains: "follow up saga not provided",
+		},
+		{
+			name: "Error case - unknown expiry behavior",
+			payload: SetQuestTimerPayload{
+				CharacterId: 12345,
+				QuestId:     2001,
+				Duration:    600,
+				OnExpiry:    "warp",
+			},
+			expectError:   true,
+			errorContains: "unknown quest timer expiry behavior",
+		},
+		{
+			name:          "Error case - invalid payload type",
+			payload:       "invalid-payload",
+			expectError:   true,
+			errorContains: "invalid payload",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			ResetTimerRegistry()
+			logger, _ := test.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			te, ctx := setupContext()
+
+			saga := Saga{
+				TransactionId: uuid.New(),
+				SagaType:      QuestReward,
+				InitiatedBy:   "test",
+			}
+			step := Step[any]{
+				StepId:    "quest-timer-step",
+				Status:    Pending,
+				Action:    SetQuestTimer,
+				Payload:   tt.payload,
+				CreatedAt: time.Now(),
+				UpdatedAt: time.Now(),
+			}
+
+			// Execute
+			err := NewHandler(logger, ctx).handleSetQuestTimer(saga, step)
+
+			// Verify
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+				if _, ok := tt.payload.(SetQuestTimerPayload); ok {
+					assert.ErrorIs(t, err, ErrActionRejected)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+
+			deadline, ok := GetTimerRegistry().Deadline(te.Id(), saga.TransactionId, step.StepId)
+			assert.Equal(t, tt.expectTimer, ok)
+			if tt.expectTimer {
+				assert.WithinDuration(t, time.Now().Add(600*time.Second), deadline, time.Second)
+			}
+			GetTimerRegistry().Cancel(te.Id(), saga.TransactionId)
+		})
+	}
+}
+
+// TestCompensateQuestTimer tests that compensating a saga stops the countdowns of its quest timers, so they do not
+// expire against the compensated saga
+func TestCompensateQuestTimer(t *testing.T) {
+	tests := []struct {
+		name   string
+		failed bool
+	}{
+		{name: "Success case - timer of failed step stopped", failed: true},
+		{name: "Success case - timer of completed step stopped"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ResetTimerRegistry()
+			logger, _ := test.NewNullLogger()
+			te, ctx := setupContext()
+
+			payload := SetQuestTimerPayload{CharacterId: 12345, QuestId: 2001, Duration: 600}
+			b := NewBuilder().SetSagaType(QuestReward)
+			if tt.failed {
+				b = b.AddStep("quest-timer-step", Failed, SetQuestTimer, payload)
+			} else {
+				b = b.AddStep("quest-timer-step", Completed, SetQuestTimer, payload).
+					AddStep("failed-step", Failed, SetVariable, SetVariablePayload{})
+			}
+			s := b.Build()
+			defer GetCache().Remove(te.Id(), s.TransactionId)
+
+			expired := make(chan struct{})
+			GetTimerRegistry().Start(te.Id(), s.TransactionId, "quest-timer-step", 20*time.Millisecond, func() {
+				close(expired)
+			})
+
+			assert.NoError(t, NewCompensator(logger, ctx).CompensateFailedStep(s))
+
+			_, ok := GetTimerRegistry().Deadline(te.Id(), s.TransactionId, "quest-timer-step")
+			assert.False(t, ok)
+			select {
+			case <-expired:
+				t.Fatal("quest timer of compensated saga expired")
+			case <-time.After(50 * time.Millisecond):
+			}
+		})
+	}
+}
+
+// TestRearmTimers tests that the countdowns of a saga restored from the archive are started again from the deadlines
+// its steps record, expiring at once should their deadline have passed
+func TestRearmTimers(t *testing.T) {
+	dispatchedAt := time.Now().Add(-10 * time.Second)
+	departsAt := time.Now().Add(time.Minute)
+
+	tests := []struct {
+		name           string
+		status         Status
+		action         Action
+		payload        any
+		dispatchedAt   time.Time
+		expectDeadline time.Time
+	}{
+		{name: "Success case - quest timer of completed step", status: Completed, action: SetQuestTimer, payload: SetQuestTimerPayload{CharacterId: 12345, QuestId: 2001, Duration: 600}, dispatchedAt: dispatchedAt, expectDeadline: dispatchedAt.Add(10 * time.Minute)},
+		{name: "Success case - invitation TTL", status: Pending, action: CreateInvite, payload: CreateInvitePayload{InviteType: "PARTY", OriginatorId: 12345, TargetId: 67890, Ttl: 30}, dispatchedAt: dispatchedAt, expectDeadline: dispatchedAt.Add(30 * time.Second)},
+		{name: "Success case - kill count timeout", status: Pending, action: AwaitKillCount, payload: AwaitKillCountPayload{MapId: 103000800, Count: 2, Timeout: 60}, dispatchedAt: dispatchedAt, expectDeadline: dispatchedAt.Add(time.Minute)},
+		{name: "Success case - escort timeout", status: Pending, action: AwaitEscort, payload: AwaitEscortPayload{NpcId: 9010000, Timeout: 120}, dispatchedAt: dispatchedAt, expectDeadline: dispatchedAt.Add(2 * time.Minute)},
+		{name: "Success case - scheduled departure", status: Pending, action: ScheduleWarp, payload: ScheduleWarpPayload{CharacterId: 12345, FieldId: "0:1:200000100", DepartsAt: departsAt}, dispatchedAt: dispatchedAt, expectDeadline: departsAt},
+		{name: "Success case - scheduled buff removal", status: Pending, action: RemoveWorldEventBuff, payload: RemoveWorldEventBuffPayload{BuffId: "halloween", RemoveAt: departsAt}, dispatchedAt: dispatchedAt, expectDeadline: departsAt},
+		{name: "Success case - departed warp awaits arrival", status: Pending, action: ScheduleWarp, payload: ScheduleWarpPayload{CharacterId: 12345, FieldId: "0:1:200000100", DepartsAt: dispatchedAt}, dispatchedAt: time.Now()},
+		{name: "Success case - step awaiting no countdown", status: Pending, action: AwaitKillCount, payload: AwaitKillCountPayload{MapId: 103000800, Count: 2}, dispatchedAt: dispatchedAt},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ResetTimerRegistry()
+			defer ResetTimerRegistry()
+			logger, _ := test.NewNullLogger()
+			te, _ := setupContext()
+
+			s := NewBuilder().
+				SetSagaType(QuestReward).
+				AddStep("timed", tt.status, tt.action, tt.payload).
+				AddStep("next", Pending, AwaitKillCount, AwaitKillCountPayload{MapId: 103000800, Count: 2}).
+				Build()
+			_, err := s.RecordStepAttempt(0, tt.dispatchedAt)
+			require.NoError(t, err)
+			restoreSaga(logger, te, s)
+			defer GetCache().Remove(te.Id(), s.TransactionId)
+
+			deadline, ok := GetTimerRegistry().Deadline(te.Id(), s.TransactionId, "timed")
+			assert.Equal(t, !tt.expectDeadline.IsZero(), ok)
+			if ok {
+				assert.WithinDuration(t, tt.expectDeadline, deadline, time.Second)
+			}
+		})
+	}
+
+	t.Run("Success case - passed deadline expires at once", func(t *testing.T) {
+		ResetTimerRegistry()
+		logger, _ := test.NewNullLogger()
+		te, ctx := setupContext()
+		setupTestProcessor(ctx, nil, nil)
+
+		s := NewBuilder().SetSagaType(QuestReward).AddStep("kills", Pending, AwaitKillCount, AwaitKillCountPayload{MapId: 103000800, Count: 2, Timeout: 60}).Build()
+		_, err := s.RecordStepAttempt(0, time.Now().Add(-2*time.Minute))
+		require.NoError(t, err)
+		done, unsubscribe := GetNotifier().Subscribe(te.Id(), s.TransactionId)
+		defer unsubscribe()
+		restoreSaga(logger, te, s)
+		defer GetCache().Remove(te.Id(), s.TransactionId)
+
+		select {
+		case s = <-done:
+		case <-time.After(time.Second):
+			t.Fatal("saga did not fail")
+		}
+		assert.Equal(t, ErrorCodeKillCountTimeout, s.Steps[0].Attempts[len(s.Steps[0].Attempts)-1].ErrorCode)
+	})
+}
+
+// TestQuestTimerExpired tests that a quest timer expiring before its saga completes abandons the saga's current step,
+// failing it with QUEST_TIMER_EXPIRED
+func TestQuestTimerExpired(t *testing.T) {
+	defer ResetTimerRegistry()
+	logger, _ := test.NewNullLogger()
+	te, ctx := setupContext()
+	processor, _ := setupTestProcessor(ctx, nil, nil)
+
+	payload := SetQuestTimerPayload{CharacterId: 12345, QuestId: 2001, Duration: 600}
+	s := NewBuilder().
+		SetSagaType(QuestReward).
+		AddStep("quest-timer-step", Pending, SetQuestTimer, payload).
+		AddStep("gate", Pending, AwaitEvent, AwaitEventPayload{CharacterId: 12345, Event: AwaitedEventMapEntry, MapId: 10000}).
+		Build()
+	done, unsubscribe := GetNotifier().Subscribe(te.Id(), s.TransactionId)
+	defer unsubscribe()
+	require.NoError(t, processor.Put(s))
+	defer GetCache().Remove(te.Id(), s.TransactionId)
+
+	questTimerExpired(logger, ctx, s.TransactionId, "quest-timer-step", payload)
+	select {
+	case s = <-done:
+	case <-time.After(time.Second):
+		t.Fatal("saga did not fail")
+	}
+	require.NotEmpty(t, s.Steps[1].Attempts)
+	a := s.Steps[1].Attempts[len(s.Steps[1].Attempts)-1]
+	assert.Equal(t, ErrorCodeQuestTimerExpired, a.ErrorCode)
+	assert.True(t, a.Abandoned)
+}
+
+// TestValidateTimers tests that a quest timer started by a saga's last step, whose countdown would be cancelled as the
+// saga completes, is rejected
+func TestValidateTimers(t *testing.T) {
+	payload := SetQuestTimerPayload{CharacterId: 12345, QuestId: 2001, Duration: 600}
+	gate := AwaitEventPayload{CharacterId: 12345, Event: AwaitedEventMapEntry, MapId: 10000}
+	tests := []struct {
+		name string
+		s    Saga
+		err  bool
+	}{
+		{name: "timer before the last step", s: NewBuilder().AddStep("timer", Pending, SetQuestTimer, payload).AddStep("gate", Pending, AwaitEvent, gate).Build()},
+		{name: "timer as the last step", s: NewBuilder().AddStep("gate", Pending, AwaitEvent, gate).AddStep("timer", Pending, SetQuestTimer, payload).Build(), err: true},
+		{name: "no steps", s: NewBuilder().Build()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.s.ValidateTimers()
+			if tt.err {
+				assert.ErrorIs(t, err, ErrInvalidTimer)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
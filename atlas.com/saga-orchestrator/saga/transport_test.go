@@ -0,0 +1,105 @@
+package saga
+
+import (
+	"atlas-saga-orchestrator/character/mock"
+	character2 "atlas-saga-orchestrator/kafka/message/character"
+	"atlas-saga-orchestrator/kafka/producer"
+	"encoding/json"
+	"github.com/Chronicle20/atlas-constants/field"
+	producer2 "github.com/Chronicle20/atlas-kafka/producer"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+// TestScheduleWarp tests that a schedule_warp step warps the character immediately, or once its departure time is
+// reached when departing in the future
+func TestScheduleWarp(t *testing.T) {
+	te, ctx := setupContext()
+	defer ResetTimerRegistry()
+
+	warped := make(chan uint32, 1)
+	charP := &mock.ProcessorMock{
+		WarpToPortalAndEmitFunc: func(transactionId uuid.UUID, characterId uint32, f field.Model, pp model.Provider[uint32]) error {
+			portalId, _ := pp()
+			warped <- portalId
+			return nil
+		},
+	}
+	processor, _ := setupTestProcessor(ctx, charP, nil)
+	payload := ScheduleWarpPayload{CharacterId: 12345, FieldId: field.Id("0:1:200000100:00000000-0000-0000-0000-000000000000"), PortalId: 2, Fare: 5000}
+
+	t.Run("departs immediately", func(t *testing.T) {
+		s := NewBuilder().SetSagaType(Transport).AddStep("warp", Pending, ScheduleWarp, payload).Build()
+		require.NoError(t, processor.Put(s))
+		defer GetCache().Remove(te.Id(), s.TransactionId)
+
+		select {
+		case portalId := <-warped:
+			assert.Equal(t, uint32(2), portalId)
+		default:
+			t.Fatal("character was not warped")
+		}
+	})
+
+	t.Run("departs at departure time", func(t *testing.T) {
+		p := payload
+		p.DepartsAt = time.Now().Add(200 * time.Millisecond)
+		s := NewBuilder().SetSagaType(Transport).AddStep("warp", Pending, ScheduleWarp, p).Build()
+		require.NoError(t, processor.Put(s))
+		defer GetCache().Remove(te.Id(), s.TransactionId)
+
+		select {
+		case <-warped:
+			t.Fatal("character was warped before departure")
+		default:
+		}
+		deadline, ok := GetTimerRegistry().Deadline(te.Id(), s.TransactionId, "warp")
+		require.True(t, ok)
+		assert.WithinDuration(t, p.DepartsAt, deadline, 100*time.Millisecond)
+
+		// Departure dispatches the step again through a processor of its own, so it is reached here instead, with its
+		// warp command captured
+		GetTimerRegistry().Cancel(te.Id(), s.TransactionId)
+		time.Sleep(time.Until(p.DepartsAt))
+		commands := make([]character2.Command[character2.ChangeMapBody], 0)
+		dctx := producer.WithProvider(ctx, func(token string) producer2.MessageProducer {
+			return func(provider model.Provider[[]kafka.Message]) error {
+				ms, err := provider()
+				if err != nil {
+					return err
+				}
+				for _, m := range ms {
+					var c character2.Command[character2.ChangeMapBody]
+					if token == character2.EnvCommandTopic && json.Unmarshal(m.Value, &c) == nil && c.Type == character2.CommandChangeMap {
+						commands = append(commands, c)
+					}
+				}
+				return nil
+			}
+		})
+		departureReached(processor.(*ProcessorImpl).l, dctx, s.TransactionId, "warp")
+		require.Len(t, commands, 1)
+		assert.Equal(t, s.TransactionId, commands[0].TransactionId)
+		assert.Equal(t, uint32(12345), commands[0].CharacterId)
+		assert.Equal(t, uint32(2), commands[0].Body.PortalId)
+	})
+
+	t.Run("cancelled saga does not depart", func(t *testing.T) {
+		p := payload
+		p.DepartsAt = time.Now().Add(50 * time.Millisecond)
+		s := NewBuilder().SetSagaType(Transport).AddStep("warp", Pending, ScheduleWarp, p).Build()
+		require.NoError(t, processor.Put(s))
+		GetCache().Remove(te.Id(), s.TransactionId)
+
+		select {
+		case <-warped:
+			t.Fatal("character of a cancelled saga was warped")
+		case <-time.After(200 * time.Millisecond):
+		}
+	})
+}
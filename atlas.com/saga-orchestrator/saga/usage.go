@@ -0,0 +1,119 @@
+package saga
+
+import (
+	"atlas-saga-orchestrator/metrics"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/Chronicle20/atlas-rest/server"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+// Outcomes of finished sagas, by which finished sagas are counted
+const (
+	OutcomeCompleted   = "completed"   // Every step of the saga completed
+	OutcomeCompensated = "compensated" // A step of the saga failed, and its failure was compensated
+)
+
+var sagasStarted *metrics.Counter
+var stepsDispatched *metrics.Counter
+var sagasFinished *metrics.Counter
+var usageOnce sync.Once
+
+// getUsage registers the counters of the orchestrator's usage. Usage is counted by tenant and by the initiator of the
+// saga, so the load of each service initiating sagas may be attributed to it.
+func getUsage() (*metrics.Counter, *metrics.Counter, *metrics.Counter) {
+	usageOnce.Do(func() {
+		sagasStarted = metrics.GetRegistry().RegisterCounter("saga_started_total", "Number of sagas started, by type and initiator.")
+		stepsDispatched = metrics.GetRegistry().RegisterCounter("saga_steps_dispatched_total", "Number of attempts of saga steps dispatched, by action and initiator.")
+		sagasFinished = metrics.GetRegistry().RegisterCounter("saga_finished_total", "Number of sagas finished, by type, initiator and outcome.")
+	})
+	return sagasStarted, stepsDispatched, sagasFinished
+}
+
+func countStarted(tenantId uuid.UUID, s Saga) {
+	started, _, _ := getUsage()
+	started.Inc(map[string]string{"tenant_id": tenantId.String(), "saga_type": string(s.SagaType), "initiated_by": s.InitiatedBy})
+}
+
+func countDispatched(tenantId uuid.UUID, s Saga, action Action) {
+	_, dispatched, _ := getUsage()
+	dispatched.Inc(map[string]string{"tenant_id": tenantId.String(), "action": string(action), "initiated_by": s.InitiatedBy})
+}
+
+func countFinished(tenantId uuid.UUID, s Saga, outcome string) {
+	_, _, finished := getUsage()
+	finished.Inc(map[string]string{"tenant_id": tenantId.String(), "saga_type": string(s.SagaType), "initiated_by": s.InitiatedBy, "outcome": outcome})
+}
+
+// InitiatorUsage is the usage of the orchestrator by sagas of a single initiator, since the replica started
+type InitiatorUsage struct {
+	InitiatedBy      string `json:"initiatedBy"`      // Initiator of the sagas
+	SagasStarted     uint64 `json:"sagasStarted"`     // Number of sagas started
+	StepsDispatched  uint64 `json:"stepsDispatched"`  // Number of attempts of steps dispatched
+	SagasCompleted   uint64 `json:"sagasCompleted"`   // Number of sagas which completed
+	SagasCompensated uint64 `json:"sagasCompensated"` // Number of sagas whose failure was compensated
+}
+
+// GetUsage rolls the orchestrator's usage up by initiator, ordered by initiator. When a tenant is given, only the usage
+// of its sagas is included.
+func GetUsage(tenantId *uuid.UUID) []InitiatorUsage {
+	started, dispatched, finished := getUsage()
+	byInitiator := make(map[string]*InitiatorUsage)
+	each := func(c *metrics.Counter, f func(u *InitiatorUsage, s metrics.Sample)) {
+		for _, s := range c.Samples() {
+			if tenantId != nil && s.Labels["tenant_id"] != tenantId.String() {
+				continue
+			}
+			initiatedBy := s.Labels["initiated_by"]
+			if _, ok := byInitiator[initiatedBy]; !ok {
+				byInitiator[initiatedBy] = &InitiatorUsage{InitiatedBy: initiatedBy}
+			}
+			f(byInitiator[initiatedBy], s)
+		}
+	}
+	each(started, func(u *InitiatorUsage, s metrics.Sample) { u.SagasStarted += uint64(s.Value) })
+	each(dispatched, func(u *InitiatorUsage, s metrics.Sample) { u.StepsDispatched += uint64(s.Value) })
+	each(finished, func(u *InitiatorUsage, s metrics.Sample) {
+		switch s.Labels["outcome"] {
+		case OutcomeCompleted:
+			u.SagasCompleted += uint64(s.Value)
+		case OutcomeCompensated:
+			u.SagasCompensated += uint64(s.Value)
+		}
+	})
+
+	r := make([]InitiatorUsage, 0, len(byInitiator))
+	for _, u := range byInitiator {
+		r = append(r, *u)
+	}
+	sort.Slice(r, func(i, j int) bool { return r[i].InitiatedBy < r[j].InitiatedBy })
+	return r
+}
+
+// InitUsageResource registers the usage rollup route with the router. Usage spans tenants, so no tenant is required,
+// though usage may be filtered to one with the tenantId query parameter.
+func InitUsageResource() server.RouteInitializer {
+	return func(r *mux.Router, l logrus.FieldLogger) {
+		r.HandleFunc("/usage", func(w http.ResponseWriter, r *http.Request) {
+			var tenantId *uuid.UUID
+			if v := r.URL.Query().Get("tenantId"); v != "" {
+				id, err := uuid.Parse(v)
+				if err != nil {
+					l.WithError(err).Errorf("Unable to properly parse tenantId from query.")
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				tenantId = &id
+			}
+			w.Header().Set("Content-Type", "application/json")
+			if err := json.NewEncoder(w).Encode(GetUsage(tenantId)); err != nil {
+				l.WithError(err).Error("Unable to write usage.")
+			}
+		}).Methods(http.MethodGet)
+	}
+}
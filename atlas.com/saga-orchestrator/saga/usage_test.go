@@ -0,0 +1,70 @@
+package saga
+
+import (
+	"atlas-saga-orchestrator/character/mock"
+	"encoding/json"
+	"github.com/Chronicle20/atlas-constants/channel"
+	"github.com/Chronicle20/atlas-constants/world"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestUsage tests that the orchestrator's usage is counted by initiator, and rolled up by initiator for a tenant
+func TestUsage(t *testing.T) {
+	te, ctx := setupContext()
+	charP := &mock.ProcessorMock{
+		AwardMesosAndEmitFunc: func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, actorId uint32, actorType string, amount int32) error {
+			return nil
+		},
+	}
+	processor, _ := setupTestProcessor(ctx, charP, nil)
+
+	completed := NewBuilder().
+		SetSagaType(QuestReward).
+		SetInitiatedBy("quest-service").
+		AddStep("variable", Pending, SetVariable, SetVariablePayload{Name: "done", Value: true}).
+		Build()
+	require.NoError(t, processor.Put(completed))
+
+	compensated := NewBuilder().
+		SetSagaType(InventoryTransaction).
+		SetInitiatedBy("gm-tools").
+		AddStep("mesos", Pending, AwardMesos, AwardMesosPayload{CharacterId: 12345, ActorType: "SYSTEM", Amount: 1000}).
+		Build()
+	require.NoError(t, processor.Put(compensated))
+	defer GetCache().Remove(te.Id(), compensated.TransactionId)
+	require.NoError(t, processor.StepCompleted(compensated.TransactionId, false))
+
+	expected := []InitiatorUsage{
+		{InitiatedBy: "gm-tools", SagasStarted: 1, StepsDispatched: 1, SagasCompensated: 1},
+		{InitiatedBy: "quest-service", SagasStarted: 1, StepsDispatched: 1, SagasCompleted: 1},
+	}
+	tenantId := te.Id()
+	assert.Equal(t, expected, GetUsage(&tenantId))
+
+	other := uuid.New()
+	assert.Empty(t, GetUsage(&other))
+
+	t.Run("usage is rolled up by the REST endpoint", func(t *testing.T) {
+		l, _ := test.NewNullLogger()
+		router := mux.NewRouter()
+		InitUsageResource()(router, l)
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/usage?tenantId="+tenantId.String(), nil))
+		require.Equal(t, http.StatusOK, rr.Code)
+		var actual []InitiatorUsage
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &actual))
+		assert.Equal(t, expected, actual)
+
+		rr = httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/usage?tenantId=tenant", nil))
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
@@ -0,0 +1,173 @@
+package v2
+
+import (
+	"atlas-saga-orchestrator/rest"
+	"atlas-saga-orchestrator/saga"
+	"errors"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/Chronicle20/atlas-rest/server"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/jtumidanski/api2go/jsonapi"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"slices"
+)
+
+// InitResource registers the v2 routes with the router
+func InitResource(si jsonapi.ServerInformation) server.RouteInitializer {
+	return func(r *mux.Router, l logrus.FieldLogger) {
+		r.HandleFunc("/v2/sagas", rest.RegisterHandler(l)(si)("get_all_sagas_v2", getAllSagasHandler)).Methods(http.MethodGet)
+		r.HandleFunc("/v2/sagas", saga.ShadowGuarded(l, rest.RegisterInputHandler[RestModel](l)(si)("create_saga_v2", createSagaHandler))).Methods(http.MethodPost)
+		r.HandleFunc("/v2/sagas/{transactionId}", rest.RegisterHandler(l)(si)("get_saga_by_id_v2", getSagaByIdHandler)).Methods(http.MethodGet)
+	}
+}
+
+// getAllSagasHandler returns a handler for the GET /v2/sagas endpoint
+func getAllSagasHandler(d *rest.HandlerDependency, c *rest.HandlerContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		labels, err := saga.ParseLabels(r)
+		if err != nil {
+			d.Logger().WithError(err).Errorf("Unable to properly parse labels from query.")
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		rms, err := model.SliceMap(Transform)(saga.NewProcessor(d.Logger(), d.Context()).ByLabelsProvider(labels))(model.ParallelMap())()
+		if err != nil {
+			d.Logger().WithError(err).Error("Failed to retrieve sagas")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		query := r.URL.Query()
+		queryParams := jsonapi.ParseQueryFields(&query)
+		server.MarshalResponse[[]RestModel](d.Logger())(w)(c.ServerInformation())(queryParams)(rms)
+	}
+}
+
+// getSagaByIdHandler returns a handler for the GET /v2/sagas/{transactionId} endpoint
+func getSagaByIdHandler(d *rest.HandlerDependency, c *rest.HandlerContext) http.HandlerFunc {
+	return rest.ParseTransactionId(d.Logger(), func(transactionId uuid.UUID) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			wait, err := saga.ParseWait(r)
+			if err != nil {
+				d.Logger().WithError(err).Errorf("Unable to properly parse wait from query.")
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			s, err := saga.NewProcessor(d.Logger(), d.Context()).AwaitTerminal(r.Context(), transactionId, wait)
+			if err != nil {
+				d.Logger().WithError(err).Debugf("Unable to locate saga [%s].", transactionId.String())
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			rm, err := model.Map(Transform)(model.FixedProvider(s))()
+			if err != nil {
+				d.Logger().WithError(err).Error("Failed to transform saga")
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+
+			query := r.URL.Query()
+			queryParams := jsonapi.ParseQueryFields(&query)
+			server.MarshalResponse[RestModel](d.Logger())(w)(c.ServerInformation())(queryParams)(rm)
+		}
+	})
+}
+
+// createSagaHandler returns a handler for the POST /v2/sagas endpoint
+func createSagaHandler(d *rest.HandlerDependency, c *rest.HandlerContext, im RestModel) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if im.Id == uuid.Nil {
+			im.Id = uuid.New()
+		}
+
+		// Steps whose actions are not supported cannot be decoded, so are rejected before extraction
+		if unsupported := UnsupportedActions(im); len(unsupported) > 0 {
+			fes := make([]saga.FieldError, 0)
+			for _, st := range im.Steps {
+				if slices.Contains(unsupported, st.Action) {
+					fes = append(fes, saga.UnsupportedActionError("", st.StepId, st.Action))
+				}
+			}
+			saga.WriteUnsupportedActions(d.Logger(), w, fes, unsupported)
+			return
+		}
+
+		s, err := Extract(im)
+		if err != nil {
+			d.Logger().WithError(err).Error("Failed to extract saga from request")
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		p := saga.NewProcessor(d.Logger(), d.Context())
+		err = p.Put(s)
+		if errors.Is(err, saga.ErrSagaQueued) {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		if errors.Is(err, saga.ErrBudgetExceeded) {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		if errors.Is(err, saga.ErrAssetConflict) {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		if errors.Is(err, saga.ErrInvalidVariable) {
+			d.Logger().WithError(err).Error("Saga has invalid variables")
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, saga.ErrInvalidSagaType) {
+			d.Logger().WithError(err).Error("Saga violates the contract of its type")
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, saga.ErrInvalidDependency) {
+			d.Logger().WithError(err).Error("Saga has invalid step dependencies")
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, saga.ErrInvalidAudit) {
+			d.Logger().WithError(err).Error("Saga has an invalid inventory audit")
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, saga.ErrChainTemplate) {
+			d.Logger().WithError(err).Error("Saga has an invalid onComplete")
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, saga.ErrActionNotEnabled) {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			return
+		}
+		if err != nil {
+			d.Logger().WithError(err).Error("Failed to create saga")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		// The saga may have already completed and been removed, in which case the submitted saga is returned
+		cs, err := p.GetById(s.TransactionId)
+		if err != nil {
+			cs = s
+		}
+
+		rm, err := model.Map(Transform)(model.FixedProvider(cs))()
+		if err != nil {
+			d.Logger().WithError(err).Error("Failed to transform saga")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		query := r.URL.Query()
+		queryParams := jsonapi.ParseQueryFields(&query)
+		server.MarshalResponse[RestModel](d.Logger())(w)(c.ServerInformation())(queryParams)(rm)
+	}
+}
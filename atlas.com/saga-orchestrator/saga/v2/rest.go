@@ -0,0 +1,324 @@
+package v2
+
+import (
+	"atlas-saga-orchestrator/saga"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jtumidanski/api2go/jsonapi"
+	"strings"
+	"time"
+)
+
+const (
+	stepsRelationship = "steps"
+	stepIdSeparator   = ":"
+)
+
+// RestModel is the JSON:API resource for sagas
+type RestModel struct {
+	Id               uuid.UUID                 `json:"-"`                          // Unique ID for the transaction
+	SagaType         saga.Type                 `json:"sagaType"`                   // Type of the saga (e.g., inventory_transaction)
+	InitiatedBy      string                    `json:"initiatedBy"`                // Who initiated the saga (e.g., NPC ID, user)
+	Labels           map[string]string         `json:"labels,omitempty"`           // Arbitrary labels for querying cohorts of sagas
+	Variables        saga.Variables            `json:"variables,omitempty"`        // Values referenced by step payload templates
+	Steps            []StepRestModel           `json:"-"`                          // Steps in the saga, exposed as the "steps" relationship
+	RequiresApproval bool                      `json:"requiresApproval,omitempty"` // Whether a second operator must approve the saga before it starts
+	Hold             saga.Hold                 `json:"hold,omitempty"`             // Hold pausing the saga until an operator reviews it, if any
+	HoldReason       string                    `json:"holdReason,omitempty"`       // Reason the saga is held
+	Reviews          []saga.Review             `json:"reviews,omitempty"`          // Operator decisions on holds of the saga
+	Notes            []saga.Note               `json:"notes,omitempty"`            // Comments left by operators handling the saga
+	OnComplete       *saga.OnComplete          `json:"onComplete,omitempty"`       // Follow-up saga initiated from a template when the saga completes, if any
+	CoalesceAwards   bool                      `json:"coalesceAwards,omitempty"`   // Whether identical award_asset steps of stackable items are coalesced when the saga is created
+	Receipt          *saga.CompensationReceipt `json:"receipt,omitempty"`          // Receipt of the saga's compensation, once compensated
+}
+
+// GetID returns the resource ID
+func (r RestModel) GetID() string {
+	return r.Id.String()
+}
+
+// SetID sets the resource ID. An empty ID is accepted for sagas being created.
+func (r *RestModel) SetID(id string) error {
+	if id == "" {
+		return nil
+	}
+	transactionId, err := uuid.Parse(id)
+	if err != nil {
+		return err
+	}
+	r.Id = transactionId
+	return nil
+}
+
+// GetName returns the resource name
+func (r RestModel) GetName() string {
+	return "sagas"
+}
+
+// GetReferences returns the relationships of the resource
+func (r RestModel) GetReferences() []jsonapi.Reference {
+	return []jsonapi.Reference{
+		{
+			Type:         StepRestModel{}.GetName(),
+			Name:         stepsRelationship,
+			Relationship: jsonapi.ToManyRelationship,
+		},
+	}
+}
+
+// GetReferencedIDs returns the identifiers of the related steps
+func (r RestModel) GetReferencedIDs() []jsonapi.ReferenceID {
+	var result []jsonapi.ReferenceID
+	for _, s := range r.Steps {
+		result = append(result, jsonapi.ReferenceID{
+			ID:           s.GetID(),
+			Type:         s.GetName(),
+			Name:         stepsRelationship,
+			Relationship: jsonapi.ToManyRelationship,
+		})
+	}
+	return result
+}
+
+// GetReferencedStructs returns the related steps to be included in the document
+func (r RestModel) GetReferencedStructs() []jsonapi.MarshalIdentifier {
+	var result []jsonapi.MarshalIdentifier
+	for _, s := range r.Steps {
+		result = append(result, s)
+	}
+	return result
+}
+
+// SetToManyReferenceIDs sets the identifiers of the related steps, preserving their order
+func (r *RestModel) SetToManyReferenceIDs(name string, IDs []string) error {
+	if name != stepsRelationship {
+		return nil
+	}
+	r.Steps = make([]StepRestModel, 0, len(IDs))
+	for _, id := range IDs {
+		s := StepRestModel{}
+		if err := s.SetID(id); err != nil {
+			return err
+		}
+		r.Steps = append(r.Steps, s)
+	}
+	return nil
+}
+
+// SetReferencedStructs populates the related steps from the included resources
+func (r *RestModel) SetReferencedStructs(references map[string]map[string]jsonapi.Data) error {
+	included, ok := references[StepRestModel{}.GetName()]
+	if !ok {
+		return nil
+	}
+	for i, s := range r.Steps {
+		d, ok := included[s.GetID()]
+		if !ok {
+			continue
+		}
+		if err := json.Unmarshal(d.Attributes, &r.Steps[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StepRestModel is the JSON:API resource for saga steps
+type StepRestModel struct {
+	TransactionId uuid.UUID           `json:"-"`                   // Transaction the step belongs to
+	StepId        string              `json:"-"`                   // Unique ID for the step within the saga
+	Status        saga.Status         `json:"status"`              // Status of the step (e.g., pending, completed, failed)
+	Action        saga.Action         `json:"action"`              // The Action to be taken (e.g., award_asset)
+	Payload       json.RawMessage     `json:"payload"`             // Data required for the action (specific to the action type)
+	CreatedAt     time.Time           `json:"createdAt"`           // Timestamp of when the step was created
+	UpdatedAt     time.Time           `json:"updatedAt"`           // Timestamp of the last update to the step
+	Attempts      []saga.StepAttempt  `json:"attempts,omitempty"`  // History of each dispatch of the step's action
+	OnError       []saga.ErrorHandler `json:"onError,omitempty"`   // Reactions to specific error codes reported when the step fails
+	Capture       map[string]string   `json:"capture,omitempty"`   // Variables set from the event completing the step, by JSONPath into the event
+	Branches      []saga.Branch       `json:"branches,omitempty"`  // Alternative steps, one branch of which is selected to execute once the step completes
+	Branch        string              `json:"branch,omitempty"`    // Name of the branch selected once the step completed, if any
+	DependsOn     []string            `json:"dependsOn,omitempty"` // Step IDs of the saga's steps which must complete before the step is dispatched
+	Timeout       uint32              `json:"timeout,omitempty"`   // Seconds the event completing the step may take to arrive, after which the step fails
+
+	PayloadTemplate json.RawMessage `json:"payloadTemplate,omitempty"` // Payload containing template expressions, rendered when the step is dispatched
+}
+
+// GetID returns the resource ID. Step IDs are only unique within a saga, so the transaction ID is used to qualify them.
+func (r StepRestModel) GetID() string {
+	if r.TransactionId == uuid.Nil {
+		return r.StepId
+	}
+	return r.TransactionId.String() + stepIdSeparator + r.StepId
+}
+
+// SetID sets the resource ID. Both qualified and plain step IDs are accepted.
+func (r *StepRestModel) SetID(id string) error {
+	if id == "" {
+		return errors.New("step id must not be empty")
+	}
+	if prefix, stepId, ok := strings.Cut(id, stepIdSeparator); ok {
+		if transactionId, err := uuid.Parse(prefix); err == nil {
+			r.TransactionId = transactionId
+			r.StepId = stepId
+			return nil
+		}
+	}
+	r.StepId = id
+	return nil
+}
+
+// GetName returns the resource name
+func (r StepRestModel) GetName() string {
+	return "steps"
+}
+
+// Transform converts a domain model to a REST model
+func Transform(s saga.Saga) (RestModel, error) {
+	steps := make([]StepRestModel, 0, len(s.Steps))
+	for _, st := range s.Steps {
+		rs, err := TransformStep(s.TransactionId)(st)
+		if err != nil {
+			return RestModel{}, err
+		}
+		steps = append(steps, rs)
+	}
+
+	return RestModel{
+		Id:               s.TransactionId,
+		SagaType:         s.SagaType,
+		InitiatedBy:      s.InitiatedBy,
+		Labels:           s.Labels,
+		Variables:        s.Variables,
+		Steps:            steps,
+		RequiresApproval: s.RequiresApproval,
+		Hold:             s.Hold,
+		HoldReason:       s.HoldReason,
+		Reviews:          s.Reviews,
+		Notes:            s.Notes,
+		OnComplete:       s.OnComplete,
+		CoalesceAwards:   s.CoalesceAwards,
+		Receipt:          s.Receipt,
+	}, nil
+}
+
+// TransformStep converts a domain step to a REST model
+func TransformStep(transactionId uuid.UUID) func(st saga.Step[any]) (StepRestModel, error) {
+	return func(st saga.Step[any]) (StepRestModel, error) {
+		payload, err := json.Marshal(st.Payload)
+		if err != nil {
+			return StepRestModel{}, fmt.Errorf("failed to marshal payload for step %s: %w", st.StepId, err)
+		}
+
+		return StepRestModel{
+			TransactionId: transactionId,
+			StepId:        st.StepId,
+			Status:        st.Status,
+			Action:        st.Action,
+			Payload:       payload,
+			CreatedAt:     st.CreatedAt,
+			UpdatedAt:     st.UpdatedAt,
+			Attempts:      st.Attempts,
+			OnError:       st.OnError,
+			Capture:       st.Capture,
+			Branches:      st.Branches,
+			Branch:        st.Branch,
+			DependsOn:     st.DependsOn,
+			Timeout:       st.Timeout,
+
+			PayloadTemplate: st.PayloadTemplate,
+		}, nil
+	}
+}
+
+// Extract converts a REST model to a domain model
+func Extract(r RestModel) (saga.Saga, error) {
+	steps := make([]saga.Step[any], 0, len(r.Steps))
+	for _, rs := range r.Steps {
+		st, err := ExtractStep(rs)
+		if err != nil {
+			return saga.Saga{}, err
+		}
+		steps = append(steps, st)
+	}
+
+	return saga.Saga{
+		TransactionId:    r.Id,
+		SagaType:         r.SagaType,
+		InitiatedBy:      r.InitiatedBy,
+		Labels:           r.Labels,
+		Variables:        r.Variables,
+		Steps:            steps,
+		RequiresApproval: r.RequiresApproval,
+		OnComplete:       r.OnComplete,
+		CoalesceAwards:   r.CoalesceAwards,
+	}, nil
+}
+
+// UnsupportedActions returns the actions of the saga's steps which this version of the orchestrator does not support
+func UnsupportedActions(r RestModel) []saga.Action {
+	actions := make([]saga.Action, 0, len(r.Steps))
+	for _, rs := range r.Steps {
+		actions = append(actions, rs.Action)
+	}
+	return saga.UnsupportedActions(actions)
+}
+
+// ExtractStep converts a REST model to a domain step, decoding the payload according to the step action
+func ExtractStep(r StepRestModel) (saga.Step[any], error) {
+	if r.Status == "" {
+		r.Status = saga.Pending
+	}
+	now := time.Now()
+	if r.CreatedAt.IsZero() {
+		r.CreatedAt = now
+	}
+	if r.UpdatedAt.IsZero() {
+		r.UpdatedAt = now
+	}
+
+	// Defer to the domain step decoding so the payload is typed identically to saga commands
+	bs, err := json.Marshal(struct {
+		StepId    string              `json:"stepId"`
+		Status    saga.Status         `json:"status"`
+		Action    saga.Action         `json:"action"`
+		Payload   json.RawMessage     `json:"payload"`
+		CreatedAt time.Time           `json:"createdAt"`
+		UpdatedAt time.Time           `json:"updatedAt"`
+		Attempts  []saga.StepAttempt  `json:"attempts,omitempty"`
+		OnError   []saga.ErrorHandler `json:"onError,omitempty"`
+		Capture   map[string]string   `json:"capture,omitempty"`
+		Branches  []saga.Branch       `json:"branches,omitempty"`
+		Branch    string              `json:"branch,omitempty"`
+		DependsOn []string            `json:"dependsOn,omitempty"`
+		Timeout   uint32              `json:"timeout,omitempty"`
+
+		PayloadTemplate json.RawMessage `json:"payloadTemplate,omitempty"`
+	}{
+		StepId:    r.StepId,
+		Status:    r.Status,
+		Action:    r.Action,
+		Payload:   r.Payload,
+		CreatedAt: r.CreatedAt,
+		UpdatedAt: r.UpdatedAt,
+		Attempts:  r.Attempts,
+		OnError:   r.OnError,
+		Capture:   r.Capture,
+		Branches:  r.Branches,
+		Branch:    r.Branch,
+		DependsOn: r.DependsOn,
+		Timeout:   r.Timeout,
+
+		PayloadTemplate: r.PayloadTemplate,
+	})
+	if err != nil {
+		return saga.Step[any]{}, err
+	}
+
+	var st saga.Step[any]
+	if err = json.Unmarshal(bs, &st); err != nil {
+		return saga.Step[any]{}, err
+	}
+	return st, nil
+}
@@ -0,0 +1,153 @@
+package v2
+
+import (
+	"atlas-saga-orchestrator/saga"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/jtumidanski/api2go/jsonapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type testServerInformation struct{}
+
+func (testServerInformation) GetBaseURL() string {
+	return ""
+}
+
+func (testServerInformation) GetPrefix() string {
+	return "/api/v2/"
+}
+
+func testSaga() saga.Saga {
+	now := time.Now().UTC().Truncate(time.Second)
+	return saga.Saga{
+		TransactionId: uuid.New(),
+		SagaType:      saga.QuestReward,
+		InitiatedBy:   "npc-9010000",
+		Labels:        map[string]string{"event": "halloween2025"},
+		Steps: []saga.Step[any]{
+			{
+				StepId: "award-mesos",
+				Status: saga.Completed,
+				Action: saga.AwardMesos,
+				Payload: saga.AwardMesosPayload{
+					CharacterId: 12345,
+					ActorType:   "SYSTEM",
+					Amount:      1000,
+				},
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+			{
+				StepId: "award-asset",
+				Status: saga.Pending,
+				Action: saga.AwardAsset,
+				Payload: saga.AwardItemActionPayload{
+					CharacterId: 12345,
+					Item:        saga.ItemPayload{TemplateId: 2000000, Quantity: 5},
+				},
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+		},
+	}
+}
+
+// TestTransformExtract tests that a saga survives a round trip through the v2 REST model
+func TestTransformExtract(t *testing.T) {
+	s := testSaga()
+
+	rm, err := Transform(s)
+	require.NoError(t, err)
+	assert.Equal(t, s.TransactionId.String(), rm.GetID())
+	require.Len(t, rm.Steps, 2)
+	assert.Equal(t, s.TransactionId.String()+":award-mesos", rm.Steps[0].GetID())
+
+	es, err := Extract(rm)
+	require.NoError(t, err)
+	assert.Equal(t, s, es)
+}
+
+// TestMarshalIncludesSteps tests that steps are exposed as an included relationship
+func TestMarshalIncludesSteps(t *testing.T) {
+	s := testSaga()
+	rm, err := Transform(s)
+	require.NoError(t, err)
+
+	bs, err := jsonapi.MarshalWithURLs(rm, testServerInformation{})
+	require.NoError(t, err)
+
+	var doc struct {
+		Data struct {
+			Type          string                     `json:"type"`
+			Id            string                     `json:"id"`
+			Attributes    map[string]json.RawMessage `json:"attributes"`
+			Relationships map[string]struct {
+				Data []struct {
+					Type string `json:"type"`
+					Id   string `json:"id"`
+				} `json:"data"`
+			} `json:"relationships"`
+		} `json:"data"`
+		Included []struct {
+			Type string `json:"type"`
+			Id   string `json:"id"`
+		} `json:"included"`
+	}
+	require.NoError(t, json.Unmarshal(bs, &doc))
+
+	assert.Equal(t, "sagas", doc.Data.Type)
+	assert.Equal(t, s.TransactionId.String(), doc.Data.Id)
+	assert.Contains(t, doc.Data.Attributes, "sagaType")
+	assert.NotContains(t, doc.Data.Attributes, "steps")
+	require.Len(t, doc.Data.Relationships["steps"].Data, 2)
+	assert.Equal(t, "steps", doc.Data.Relationships["steps"].Data[0].Type)
+	assert.Len(t, doc.Included, 2)
+}
+
+// TestUnmarshalWithIncludedSteps tests that a create request with included steps is decoded in relationship order
+func TestUnmarshalWithIncludedSteps(t *testing.T) {
+	body := `{
+		"data": {
+			"type": "sagas",
+			"attributes": {"sagaType": "quest_reward", "initiatedBy": "npc-9010000"},
+			"relationships": {"steps": {"data": [{"type": "steps", "id": "second"}, {"type": "steps", "id": "first"}]}}
+		},
+		"included": [
+			{"type": "steps", "id": "first", "attributes": {"action": "award_level", "payload": {"characterId": 12345, "amount": 1}}},
+			{"type": "steps", "id": "second", "attributes": {"action": "award_mesos", "payload": {"characterId": 12345, "amount": 500}}}
+		]
+	}`
+
+	var rm RestModel
+	require.NoError(t, jsonapi.Unmarshal([]byte(body), &rm))
+	require.Len(t, rm.Steps, 2)
+	assert.Equal(t, "second", rm.Steps[0].StepId)
+	assert.Equal(t, saga.AwardMesos, rm.Steps[0].Action)
+
+	s, err := Extract(rm)
+	require.NoError(t, err)
+	assert.Equal(t, saga.Pending, s.Steps[0].Status)
+	assert.Equal(t, saga.AwardMesosPayload{CharacterId: 12345, Amount: 500}, s.Steps[0].Payload)
+	assert.Equal(t, saga.AwardLevelPayload{CharacterId: 12345, Amount: 1}, s.Steps[1].Payload)
+}
+
+// TestExtractStepUnknownAction tests that steps with unknown actions are rejected
+func TestExtractStepUnknownAction(t *testing.T) {
+	_, err := ExtractStep(StepRestModel{StepId: "bad", Action: "unknown_action", Payload: json.RawMessage(`{}`)})
+	assert.Error(t, err)
+}
+
+// TestUnsupportedActions tests that the unsupported actions of a saga's steps are reported once each
+func TestUnsupportedActions(t *testing.T) {
+	rm := RestModel{Steps: []StepRestModel{
+		{StepId: "mesos", Action: saga.AwardMesos},
+		{StepId: "pet", Action: "award_pet"},
+		{StepId: "pet2", Action: "award_pet"},
+	}}
+	assert.Equal(t, []saga.Action{"award_pet"}, UnsupportedActions(rm))
+	assert.Empty(t, UnsupportedActions(RestModel{Steps: rm.Steps[:1]}))
+}
@@ -0,0 +1,116 @@
+package saga
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// ErrInvalidVariable is returned when a saga variable has an invalid name or a value which cannot be represented as JSON
+var ErrInvalidVariable = errors.New("invalid saga variable")
+
+// variableNamePattern restricts variable names to identifiers, so they can be referenced by templates and JSONPath
+var variableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Variables are named values carried by a saga, referenced by step payload templates. Values are held as they decode
+// from JSON: strings, numbers (float64), booleans, and lists and objects of them.
+type Variables map[string]any
+
+// String returns the value of a string variable
+func (v Variables) String(name string) (string, bool) {
+	s, ok := v[name].(string)
+	return s, ok
+}
+
+// Number returns the value of a numeric variable
+func (v Variables) Number(name string) (float64, bool) {
+	n, ok := v[name].(float64)
+	return n, ok
+}
+
+// Bool returns the value of a boolean variable
+func (v Variables) Bool(name string) (bool, bool) {
+	b, ok := v[name].(bool)
+	return b, ok
+}
+
+// With returns a copy of the variables with the variable set, leaving the receiver, which may be shared with cached
+// copies of the saga, unmodified
+func (v Variables) With(name string, value any) Variables {
+	r := make(Variables, len(v)+1)
+	for k, e := range v {
+		r[k] = e
+	}
+	r[name] = value
+	return r
+}
+
+// NormalizeVariable validates a variable, returning its value as it decodes from JSON (e.g. an int becomes a float64),
+// so variables are typed identically whether set through REST, Kafka, or in process.
+func NormalizeVariable(name string, value any) (any, error) {
+	if !variableNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("%w: name '%s' must be an identifier", ErrInvalidVariable, name)
+	}
+	bs, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: value of '%s' %s", ErrInvalidVariable, name, err.Error())
+	}
+	var r any
+	if err = json.Unmarshal(bs, &r); err != nil {
+		return nil, fmt.Errorf("%w: value of '%s' %s", ErrInvalidVariable, name, err.Error())
+	}
+	if r == nil {
+		return nil, fmt.Errorf("%w: value of '%s' must not be null", ErrInvalidVariable, name)
+	}
+	return r, nil
+}
+
+// NormalizeVariables validates and normalizes the saga's variables
+func (s *Saga) NormalizeVariables() error {
+	if len(s.Variables) == 0 {
+		return nil
+	}
+	r := make(Variables, len(s.Variables))
+	for k, v := range s.Variables {
+		nv, err := NormalizeVariable(k, v)
+		if err != nil {
+			return err
+		}
+		r[k] = nv
+	}
+	s.Variables = r
+	return nil
+}
+
+// CaptureVariables evaluates the step's capture rules, each a JSONPath expression, against the event which completed
+// it, returning the variables with the captured values set
+func CaptureVariables(vars Variables, st Step[any], event any) (Variables, error) {
+	if len(st.Capture) == 0 {
+		return vars, nil
+	}
+	bs, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	var doc any
+	if err = json.Unmarshal(bs, &doc); err != nil {
+		return nil, err
+	}
+	return captureVariables(vars, st.Capture, doc)
+}
+
+// captureVariables evaluates capture rules, variable names to JSONPath expressions, against a JSON document
+func captureVariables(vars Variables, rules map[string]string, doc any) (Variables, error) {
+	for name, path := range rules {
+		value, err := evaluateJSONPath(path, doc)
+		if err != nil {
+			return nil, fmt.Errorf("unable to capture '%s': %w", name, err)
+		}
+		if value, err = NormalizeVariable(name, value); err != nil {
+			return nil, err
+		}
+		vars = vars.With(name, value)
+	}
+	return vars, nil
+}
@@ -0,0 +1,122 @@
+package saga
+
+import (
+	"atlas-saga-orchestrator/character/mock"
+	character2 "atlas-saga-orchestrator/kafka/message/character"
+	"encoding/json"
+	"github.com/Chronicle20/atlas-constants/channel"
+	"github.com/Chronicle20/atlas-constants/world"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+// TestNormalizeVariables tests that variables are validated and typed as they decode from JSON
+func TestNormalizeVariables(t *testing.T) {
+	s := Saga{Variables: Variables{"characterId": uint32(12345), "event": "halloween", "vip": true, "items": []uint32{2000000, 2000001}}}
+	require.NoError(t, s.NormalizeVariables())
+
+	n, ok := s.Variables.Number("characterId")
+	assert.True(t, ok)
+	assert.Equal(t, float64(12345), n)
+	e, ok := s.Variables.String("event")
+	assert.True(t, ok)
+	assert.Equal(t, "halloween", e)
+	b, ok := s.Variables.Bool("vip")
+	assert.True(t, ok)
+	assert.True(t, b)
+	assert.Equal(t, []any{float64(2000000), float64(2000001)}, s.Variables["items"])
+	_, ok = s.Variables.String("characterId")
+	assert.False(t, ok)
+
+	s = Saga{Variables: Variables{"": 1}}
+	assert.ErrorIs(t, s.NormalizeVariables(), ErrInvalidVariable)
+	s = Saga{Variables: Variables{"reward": nil}}
+	assert.ErrorIs(t, s.NormalizeVariables(), ErrInvalidVariable)
+	s = Saga{Variables: Variables{"callback": func() {}}}
+	assert.ErrorIs(t, s.NormalizeVariables(), ErrInvalidVariable)
+}
+
+// TestSagaVariablesJSON tests that variables round trip through JSON
+func TestSagaVariablesJSON(t *testing.T) {
+	s := Saga{TransactionId: uuid.New(), SagaType: QuestReward, Variables: Variables{"characterId": float64(12345)}, Steps: []Step[any]{}}
+	bs, err := json.Marshal(s)
+	require.NoError(t, err)
+
+	var rs Saga
+	require.NoError(t, json.Unmarshal(bs, &rs))
+	assert.Equal(t, s.Variables, rs.Variables)
+}
+
+// TestStepCompletedWithEvent tests that steps capture variables from the events completing them
+func TestStepCompletedWithEvent(t *testing.T) {
+	te, ctx := setupContext()
+
+	type createdEvent struct {
+		TransactionId uuid.UUID `json:"transactionId"`
+		CharacterId   uint32    `json:"characterId"`
+		Type          string    `json:"type"`
+	}
+
+	var awarded uint32
+	charP := &mock.ProcessorMock{
+		AwardMesosAndEmitFunc: func(transactionId uuid.UUID, worldId world.Id, characterId uint32, channelId channel.Id, actorId uint32, actorType string, amount int32) error {
+			awarded = characterId
+			return nil
+		},
+	}
+	processor, _ := setupTestProcessor(ctx, charP, nil)
+
+	newSaga := func(capture map[string]string) Saga {
+		return Saga{
+			TransactionId: uuid.New(),
+			SagaType:      CharacterCreation,
+			InitiatedBy:   "login",
+			Steps: []Step[any]{
+				{StepId: "create", Status: Pending, Action: CreateCharacter, Payload: CharacterCreatePayload{Name: "Atlas"}, Capture: capture},
+				{StepId: "award", Status: Pending, Action: AwardMesos, PayloadTemplate: json.RawMessage(`{"characterId": "$.variables.characterId", "actorType": "SYSTEM", "amount": 100}`)},
+			},
+		}
+	}
+
+	t.Run("captured variables are available to later steps", func(t *testing.T) {
+		s := newSaga(map[string]string{"characterId": "$.characterId"})
+		GetCache().Put(te.Id(), s)
+		defer GetCache().Remove(te.Id(), s.TransactionId)
+
+		require.NoError(t, processor.StepCompletedWithEvent(s.TransactionId, createdEvent{TransactionId: s.TransactionId, CharacterId: 54321, Type: "CREATED"}))
+		assert.Equal(t, uint32(54321), awarded)
+
+		rs, err := processor.GetById(s.TransactionId)
+		require.NoError(t, err)
+		assert.Equal(t, float64(54321), rs.Variables["characterId"])
+		assert.Equal(t, Completed, rs.Steps[0].Status)
+	})
+
+	t.Run("created characters are available to later steps without capture rules", func(t *testing.T) {
+		awarded = 0
+		s := newSaga(nil)
+		GetCache().Put(te.Id(), s)
+		defer GetCache().Remove(te.Id(), s.TransactionId)
+
+		e := character2.StatusEvent[character2.StatusEventCreatedBody]{TransactionId: s.TransactionId, CharacterId: 54321, Type: character2.StatusEventTypeCreated, Body: character2.StatusEventCreatedBody{Name: "Atlas"}}
+		require.NoError(t, processor.StepCompletedWithEvent(s.TransactionId, e))
+		assert.Equal(t, uint32(54321), awarded)
+
+		rs, err := processor.GetById(s.TransactionId)
+		require.NoError(t, err)
+		assert.Equal(t, float64(54321), rs.Variables[CreatedCharacterVariable])
+		assert.Equal(t, uint32(54321), rs.Steps[0].Payload.(CharacterCreatePayload).CharacterId)
+	})
+
+	t.Run("unresolved captures fail the step", func(t *testing.T) {
+		awarded = 0
+		s := newSaga(map[string]string{"characterId": "$.body.characterId"})
+		GetCache().Put(te.Id(), s)
+		defer GetCache().Remove(te.Id(), s.TransactionId)
+
+		_ = processor.StepCompletedWithEvent(s.TransactionId, createdEvent{TransactionId: s.TransactionId, CharacterId: 54321, Type: "CREATED"})
+		assert.Equal(t, uint32(0), awarded)
+	})
+}
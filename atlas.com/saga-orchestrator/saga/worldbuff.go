@@ -0,0 +1,32 @@
+package saga
+
+import (
+	"context"
+
+	tenant "github.com/Chronicle20/atlas-tenant"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// eventBuffExpired dispatches the remove_world_event_buff step of a saga again once the buff's removal time is reached,
+// should the saga still await it, so the buff is removed
+func eventBuffExpired(l logrus.FieldLogger, ctx context.Context, transactionId uuid.UUID, stepId string) {
+	p := NewProcessor(l, ctx)
+	s, err := p.GetById(transactionId)
+	if err != nil {
+		return
+	}
+	st, ok := s.GetCurrentStep()
+	if !ok || s.Failing() || st.StepId != stepId {
+		return
+	}
+
+	if err = p.Step(transactionId); err != nil {
+		l.WithError(err).WithFields(logrus.Fields{
+			"transaction_id": transactionId.String(),
+			"saga_type":      s.SagaType,
+			"step_id":        stepId,
+			"tenant_id":      tenant.MustFromContext(ctx).Id().String(),
+		}).Error("Unable to dispatch world event buff removal.")
+	}
+}
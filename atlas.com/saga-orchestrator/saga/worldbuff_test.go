@@ -0,0 +1,81 @@
+package saga
+
+import (
+	worldstate2 "atlas-saga-orchestrator/kafka/message/worldstate"
+	"atlas-saga-orchestrator/kafka/producer"
+	"encoding/json"
+	producer2 "github.com/Chronicle20/atlas-kafka/producer"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+// TestApplyWorldEventBuff tests that an apply_world_event_buff step enables the buff, and links a step removing it once
+// its duration has elapsed
+func TestApplyWorldEventBuff(t *testing.T) {
+	te, ctx := setupContext()
+	defer ResetTimerRegistry()
+
+	commands := make([]worldstate2.Command[json.RawMessage], 0)
+	ctx = producer.WithProvider(ctx, func(token string) producer2.MessageProducer {
+		return func(provider model.Provider[[]kafka.Message]) error {
+			ms, err := provider()
+			if err != nil {
+				return err
+			}
+			for _, m := range ms {
+				var c worldstate2.Command[json.RawMessage]
+				if token == worldstate2.EnvCommandTopic && json.Unmarshal(m.Value, &c) == nil {
+					commands = append(commands, c)
+				}
+			}
+			return nil
+		}
+	})
+	processor, _ := setupTestProcessor(ctx, nil, nil)
+
+	s := NewBuilder().
+		SetSagaType(InventoryTransaction).
+		AddStep("buff", Pending, ApplyWorldEventBuff, ApplyWorldEventBuffPayload{WorldId: 1, BuffId: "double_exp", Type: "exp", Rate: 2, Duration: 1}).
+		Build()
+	defer GetCache().Remove(te.Id(), s.TransactionId)
+	require.NoError(t, processor.Put(s))
+
+	require.Len(t, commands, 1)
+	assert.Equal(t, worldstate2.CommandTypeApplyEventBuff, commands[0].Type)
+	var applied worldstate2.ApplyEventBuffBody
+	require.NoError(t, json.Unmarshal(commands[0].Body, &applied))
+	assert.Equal(t, worldstate2.ApplyEventBuffBody{BuffId: "double_exp", Type: "exp", Rate: 2}, applied)
+
+	cs, err := processor.GetById(s.TransactionId)
+	require.NoError(t, err)
+	require.Len(t, cs.Steps, 2)
+	assert.Equal(t, "buff_remove", cs.Steps[1].StepId)
+	assert.Equal(t, RemoveWorldEventBuff, cs.Steps[1].Action)
+	removal, ok := cs.Steps[1].Payload.(RemoveWorldEventBuffPayload)
+	require.True(t, ok)
+	assert.Equal(t, "double_exp", removal.BuffId)
+	assert.WithinDuration(t, time.Now().Add(time.Second), removal.RemoveAt, 100*time.Millisecond)
+
+	// The removal is scheduled once the buff is applied, rather than dispatched immediately
+	require.NoError(t, processor.StepCompleted(s.TransactionId, true))
+	assert.Len(t, commands, 1)
+	deadline, ok := GetTimerRegistry().Deadline(te.Id(), s.TransactionId, "buff_remove")
+	require.True(t, ok)
+	assert.WithinDuration(t, removal.RemoveAt, deadline, 100*time.Millisecond)
+
+	// Expiry dispatches the step again through a processor of its own, so it is reached here instead, with the context
+	// capturing its command
+	GetTimerRegistry().Cancel(te.Id(), s.TransactionId)
+	time.Sleep(time.Until(removal.RemoveAt))
+	eventBuffExpired(processor.(*ProcessorImpl).l, ctx, s.TransactionId, "buff_remove")
+	require.Len(t, commands, 2)
+	assert.Equal(t, worldstate2.CommandTypeRemoveEventBuff, commands[1].Type)
+	assert.Equal(t, s.TransactionId, commands[1].TransactionId)
+	var removed worldstate2.RemoveEventBuffBody
+	require.NoError(t, json.Unmarshal(commands[1].Body, &removed))
+	assert.Equal(t, "double_exp", removed.BuffId)
+}
@@ -0,0 +1,72 @@
+package mock
+
+import (
+	"atlas-saga-orchestrator/kafka/message"
+	"atlas-saga-orchestrator/session"
+	"time"
+
+	"github.com/Chronicle20/atlas-constants/world"
+	"github.com/google/uuid"
+)
+
+// ProcessorMock is a mock implementation of the session.Processor interface
+type ProcessorMock struct {
+	GetByCharacterIdFunc            func(characterId uint32) ([]session.Session, error)
+	IsLoggedInFunc                  func(characterId uint32) (bool, error)
+	RegisterTitleBuffAndEmitFunc    func(transactionId uuid.UUID, worldId world.Id, characterId uint32, effectId uuid.UUID, titleId uint32, duration uint32, expiresAt *time.Time) error
+	RegisterTitleBuffFunc           func(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, effectId uuid.UUID, titleId uint32, duration uint32, expiresAt *time.Time) error
+	CancelDeferredEffectAndEmitFunc func(transactionId uuid.UUID, worldId world.Id, characterId uint32, effectId uuid.UUID) error
+	CancelDeferredEffectFunc        func(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, effectId uuid.UUID) error
+}
+
+// GetByCharacterId is a mock implementation of the session.Processor.GetByCharacterId method
+func (m *ProcessorMock) GetByCharacterId(characterId uint32) ([]session.Session, error) {
+	if m.GetByCharacterIdFunc != nil {
+		return m.GetByCharacterIdFunc(characterId)
+	}
+	return nil, nil
+}
+
+// IsLoggedIn is a mock implementation of the session.Processor.IsLoggedIn method
+func (m *ProcessorMock) IsLoggedIn(characterId uint32) (bool, error) {
+	if m.IsLoggedInFunc != nil {
+		return m.IsLoggedInFunc(characterId)
+	}
+	return true, nil
+}
+
+// RegisterTitleBuffAndEmit is a mock implementation of the session.Processor.RegisterTitleBuffAndEmit method
+func (m *ProcessorMock) RegisterTitleBuffAndEmit(transactionId uuid.UUID, worldId world.Id, characterId uint32, effectId uuid.UUID, titleId uint32, duration uint32, expiresAt *time.Time) error {
+	if m.RegisterTitleBuffAndEmitFunc != nil {
+		return m.RegisterTitleBuffAndEmitFunc(transactionId, worldId, characterId, effectId, titleId, duration, expiresAt)
+	}
+	return nil
+}
+
+// RegisterTitleBuff is a mock implementation of the session.Processor.RegisterTitleBuff method
+func (m *ProcessorMock) RegisterTitleBuff(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, effectId uuid.UUID, titleId uint32, duration uint32, expiresAt *time.Time) error {
+	if m.RegisterTitleBuffFunc != nil {
+		return m.RegisterTitleBuffFunc(mb)
+	}
+	return func(transactionId uuid.UUID, worldId world.Id, characterId uint32, effectId uuid.UUID, titleId uint32, duration uint32, expiresAt *time.Time) error {
+		return nil
+	}
+}
+
+// CancelDeferredEffectAndEmit is a mock implementation of the session.Processor.CancelDeferredEffectAndEmit method
+func (m *ProcessorMock) CancelDeferredEffectAndEmit(transactionId uuid.UUID, worldId world.Id, characterId uint32, effectId uuid.UUID) error {
+	if m.CancelDeferredEffectAndEmitFunc != nil {
+		return m.CancelDeferredEffectAndEmitFunc(transactionId, worldId, characterId, effectId)
+	}
+	return nil
+}
+
+// CancelDeferredEffect is a mock implementation of the session.Processor.CancelDeferredEffect method
+func (m *ProcessorMock) CancelDeferredEffect(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, effectId uuid.UUID) error {
+	if m.CancelDeferredEffectFunc != nil {
+		return m.CancelDeferredEffectFunc(mb)
+	}
+	return func(transactionId uuid.UUID, worldId world.Id, characterId uint32, effectId uuid.UUID) error {
+		return nil
+	}
+}
@@ -0,0 +1,24 @@
+package session
+
+import "github.com/Chronicle20/atlas-constants/channel"
+
+// Session is a character's presence in a channel, held from login until logout
+type Session struct {
+	id        string
+	channelId channel.Id
+}
+
+func (s Session) Id() string {
+	return s.id
+}
+
+func (s Session) ChannelId() channel.Id {
+	return s.channelId
+}
+
+func NewSession(id string, channelId channel.Id) Session {
+	return Session{
+		id:        id,
+		channelId: channelId,
+	}
+}
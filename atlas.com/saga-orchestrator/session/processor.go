@@ -0,0 +1,80 @@
+package session
+
+import (
+	"atlas-saga-orchestrator/kafka/message"
+	session2 "atlas-saga-orchestrator/kafka/message/session"
+	"atlas-saga-orchestrator/kafka/producer"
+	"context"
+	"time"
+
+	"github.com/Chronicle20/atlas-constants/world"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/Chronicle20/atlas-rest/requests"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type Processor interface {
+	GetByCharacterId(characterId uint32) ([]Session, error)
+	IsLoggedIn(characterId uint32) (bool, error)
+	RegisterTitleBuffAndEmit(transactionId uuid.UUID, worldId world.Id, characterId uint32, effectId uuid.UUID, titleId uint32, duration uint32, expiresAt *time.Time) error
+	RegisterTitleBuff(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, effectId uuid.UUID, titleId uint32, duration uint32, expiresAt *time.Time) error
+	CancelDeferredEffectAndEmit(transactionId uuid.UUID, worldId world.Id, characterId uint32, effectId uuid.UUID) error
+	CancelDeferredEffect(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, effectId uuid.UUID) error
+}
+
+type ProcessorImpl struct {
+	l   logrus.FieldLogger
+	ctx context.Context
+	p   producer.Provider
+}
+
+func NewProcessor(l logrus.FieldLogger, ctx context.Context) Processor {
+	return &ProcessorImpl{
+		l:   l,
+		ctx: ctx,
+		p:   producer.ProviderImpl(l)(ctx),
+	}
+}
+
+// GetByCharacterId returns the character's active sessions, of which there are none while the character is offline
+func (p *ProcessorImpl) GetByCharacterId(characterId uint32) ([]Session, error) {
+	return requests.SliceProvider[RestModel, Session](p.l, p.ctx)(requestByCharacterId(characterId), Extract, model.Filters[Session]())()
+}
+
+// IsLoggedIn returns whether the character is logged in to a channel
+func (p *ProcessorImpl) IsLoggedIn(characterId uint32) (bool, error) {
+	ss, err := p.GetByCharacterId(characterId)
+	if err != nil {
+		return false, err
+	}
+	return len(ss) > 0, nil
+}
+
+// RegisterTitleBuffAndEmit requests the buff of the title be applied to the character for the duration, in seconds, on
+// their next login. An effect which has not been applied by its expiration, if any, is dropped.
+func (p *ProcessorImpl) RegisterTitleBuffAndEmit(transactionId uuid.UUID, worldId world.Id, characterId uint32, effectId uuid.UUID, titleId uint32, duration uint32, expiresAt *time.Time) error {
+	return message.Emit(p.p)(func(mb *message.Buffer) error {
+		return p.RegisterTitleBuff(mb)(transactionId, worldId, characterId, effectId, titleId, duration, expiresAt)
+	})
+}
+
+func (p *ProcessorImpl) RegisterTitleBuff(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, effectId uuid.UUID, titleId uint32, duration uint32, expiresAt *time.Time) error {
+	return func(transactionId uuid.UUID, worldId world.Id, characterId uint32, effectId uuid.UUID, titleId uint32, duration uint32, expiresAt *time.Time) error {
+		return mb.Put(session2.EnvCommandTopic, RegisterTitleBuffProvider(transactionId, worldId, characterId, effectId, titleId, duration, expiresAt))
+	}
+}
+
+// CancelDeferredEffectAndEmit requests a deferred effect registered for the character, which has not been applied, be
+// dropped
+func (p *ProcessorImpl) CancelDeferredEffectAndEmit(transactionId uuid.UUID, worldId world.Id, characterId uint32, effectId uuid.UUID) error {
+	return message.Emit(p.p)(func(mb *message.Buffer) error {
+		return p.CancelDeferredEffect(mb)(transactionId, worldId, characterId, effectId)
+	})
+}
+
+func (p *ProcessorImpl) CancelDeferredEffect(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, characterId uint32, effectId uuid.UUID) error {
+	return func(transactionId uuid.UUID, worldId world.Id, characterId uint32, effectId uuid.UUID) error {
+		return mb.Put(session2.EnvCommandTopic, CancelDeferredEffectProvider(transactionId, worldId, characterId, effectId))
+	}
+}
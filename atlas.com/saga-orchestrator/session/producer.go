@@ -0,0 +1,44 @@
+package session
+
+import (
+	session2 "atlas-saga-orchestrator/kafka/message/session"
+	"time"
+
+	"github.com/Chronicle20/atlas-constants/world"
+	"github.com/Chronicle20/atlas-kafka/producer"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+)
+
+func RegisterTitleBuffProvider(transactionId uuid.UUID, worldId world.Id, characterId uint32, effectId uuid.UUID, titleId uint32, duration uint32, expiresAt *time.Time) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(characterId))
+	value := &session2.Command[session2.RegisterDeferredEffectCommandBody]{
+		TransactionId: transactionId,
+		WorldId:       worldId,
+		CharacterId:   characterId,
+		Type:          session2.CommandTypeRegisterDeferredEffect,
+		Body: session2.RegisterDeferredEffectCommandBody{
+			EffectId:  effectId,
+			Effect:    session2.DeferredEffectTitleBuff,
+			TitleId:   titleId,
+			Duration:  duration,
+			ExpiresAt: expiresAt,
+		},
+	}
+	return producer.SingleMessageProvider(key, value)
+}
+
+func CancelDeferredEffectProvider(transactionId uuid.UUID, worldId world.Id, characterId uint32, effectId uuid.UUID) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(characterId))
+	value := &session2.Command[session2.CancelDeferredEffectCommandBody]{
+		TransactionId: transactionId,
+		WorldId:       worldId,
+		CharacterId:   characterId,
+		Type:          session2.CommandTypeCancelDeferredEffect,
+		Body: session2.CancelDeferredEffectCommandBody{
+			EffectId: effectId,
+		},
+	}
+	return producer.SingleMessageProvider(key, value)
+}
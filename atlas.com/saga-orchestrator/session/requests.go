@@ -0,0 +1,19 @@
+package session
+
+import (
+	"atlas-saga-orchestrator/rest"
+	"fmt"
+	"github.com/Chronicle20/atlas-rest/requests"
+)
+
+const (
+	sessionsForCharacter = "characters/%d/sessions"
+)
+
+func getBaseRequest() string {
+	return requests.RootUrl("SESSIONS")
+}
+
+func requestByCharacterId(characterId uint32) requests.Request[[]RestModel] {
+	return rest.MakeGetRequest[[]RestModel](fmt.Sprintf(getBaseRequest()+sessionsForCharacter, characterId))
+}
@@ -0,0 +1,26 @@
+package session
+
+import "github.com/Chronicle20/atlas-constants/channel"
+
+// RestModel is a session of a character, identified by the session
+type RestModel struct {
+	Id        string     `json:"-"`
+	ChannelId channel.Id `json:"channelId"`
+}
+
+func (r RestModel) GetName() string {
+	return "sessions"
+}
+
+func (r RestModel) GetID() string {
+	return r.Id
+}
+
+func (r *RestModel) SetID(id string) error {
+	r.Id = id
+	return nil
+}
+
+func Extract(rm RestModel) (Session, error) {
+	return NewSession(rm.Id, rm.ChannelId), nil
+}
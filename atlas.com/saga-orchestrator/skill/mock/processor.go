@@ -0,0 +1,91 @@
+package mock
+
+import (
+	"atlas-saga-orchestrator/kafka/message"
+	"github.com/google/uuid"
+	"time"
+)
+
+// ProcessorMock is a mock implementation of the skill.Processor interface
+type ProcessorMock struct {
+	RequestCreateAndEmitFunc  func(transactionId uuid.UUID, characterId uint32, skillId uint32, level byte, masterLevel byte, expiration time.Time) error
+	RequestCreateFunc         func(mb *message.Buffer) func(transactionId uuid.UUID, characterId uint32, skillId uint32, level byte, masterLevel byte, expiration time.Time) error
+	RequestUpdateAndEmitFunc  func(transactionId uuid.UUID, characterId uint32, skillId uint32, level byte, masterLevel byte, expiration time.Time) error
+	RequestUpdateFunc         func(mb *message.Buffer) func(transactionId uuid.UUID, characterId uint32, skillId uint32, level byte, masterLevel byte, expiration time.Time) error
+	ResetCooldownsAndEmitFunc func(transactionId uuid.UUID, characterId uint32, skillIds []uint32) error
+	ResetCooldownsFunc        func(mb *message.Buffer) func(transactionId uuid.UUID, characterId uint32, skillIds []uint32) error
+	RequestDeleteAndEmitFunc  func(transactionId uuid.UUID, characterId uint32, skillId uint32) error
+	RequestDeleteFunc         func(mb *message.Buffer) func(transactionId uuid.UUID, characterId uint32, skillId uint32) error
+}
+
+// RequestCreateAndEmit is a mock implementation of the skill.Processor.RequestCreateAndEmit method
+func (m *ProcessorMock) RequestCreateAndEmit(transactionId uuid.UUID, characterId uint32, skillId uint32, level byte, masterLevel byte, expiration time.Time) error {
+	if m.RequestCreateAndEmitFunc != nil {
+		return m.RequestCreateAndEmitFunc(transactionId, characterId, skillId, level, masterLevel, expiration)
+	}
+	return nil
+}
+
+// RequestCreate is a mock implementation of the skill.Processor.RequestCreate method
+func (m *ProcessorMock) RequestCreate(mb *message.Buffer) func(transactionId uuid.UUID, characterId uint32, skillId uint32, level byte, masterLevel byte, expiration time.Time) error {
+	if m.RequestCreateFunc != nil {
+		return m.RequestCreateFunc(mb)
+	}
+	return func(transactionId uuid.UUID, characterId uint32, skillId uint32, level byte, masterLevel byte, expiration time.Time) error {
+		return nil
+	}
+}
+
+// RequestUpdateAndEmit is a mock implementation of the skill.Processor.RequestUpdateAndEmit method
+func (m *ProcessorMock) RequestUpdateAndEmit(transactionId uuid.UUID, characterId uint32, skillId uint32, level byte, masterLevel byte, expiration time.Time) error {
+	if m.RequestUpdateAndEmitFunc != nil {
+		return m.RequestUpdateAndEmitFunc(transactionId, characterId, skillId, level, masterLevel, expiration)
+	}
+	return nil
+}
+
+// RequestUpdate is a mock implementation of the skill.Processor.RequestUpdate method
+func (m *ProcessorMock) RequestUpdate(mb *message.Buffer) func(transactionId uuid.UUID, characterId uint32, skillId uint32, level byte, masterLevel byte, expiration time.Time) error {
+	if m.RequestUpdateFunc != nil {
+		return m.RequestUpdateFunc(mb)
+	}
+	return func(transactionId uuid.UUID, characterId uint32, skillId uint32, level byte, masterLevel byte, expiration time.Time) error {
+		return nil
+	}
+}
+
+// ResetCooldownsAndEmit is a mock implementation of the skill.Processor.ResetCooldownsAndEmit method
+func (m *ProcessorMock) ResetCooldownsAndEmit(transactionId uuid.UUID, characterId uint32, skillIds []uint32) error {
+	if m.ResetCooldownsAndEmitFunc != nil {
+		return m.ResetCooldownsAndEmitFunc(transactionId, characterId, skillIds)
+	}
+	return nil
+}
+
+// ResetCooldowns is a mock implementation of the skill.Processor.ResetCooldowns method
+func (m *ProcessorMock) ResetCooldowns(mb *message.Buffer) func(transactionId uuid.UUID, characterId uint32, skillIds []uint32) error {
+	if m.ResetCooldownsFunc != nil {
+		return m.ResetCooldownsFunc(mb)
+	}
+	return func(transactionId uuid.UUID, characterId uint32, skillIds []uint32) error {
+		return nil
+	}
+}
+
+// RequestDeleteAndEmit is a mock implementation of the skill.Processor.RequestDeleteAndEmit method
+func (m *ProcessorMock) RequestDeleteAndEmit(transactionId uuid.UUID, characterId uint32, skillId uint32) error {
+	if m.RequestDeleteAndEmitFunc != nil {
+		return m.RequestDeleteAndEmitFunc(transactionId, characterId, skillId)
+	}
+	return nil
+}
+
+// RequestDelete is a mock implementation of the skill.Processor.RequestDelete method
+func (m *ProcessorMock) RequestDelete(mb *message.Buffer) func(transactionId uuid.UUID, characterId uint32, skillId uint32) error {
+	if m.RequestDeleteFunc != nil {
+		return m.RequestDeleteFunc(mb)
+	}
+	return func(transactionId uuid.UUID, characterId uint32, skillId uint32) error {
+		return nil
+	}
+}
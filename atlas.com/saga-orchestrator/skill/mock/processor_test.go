@@ -0,0 +1,12 @@
+package mock
+
+import (
+	"atlas-saga-orchestrator/skill"
+	"testing"
+)
+
+// TestProcessorMockImplementsProcessor verifies that ProcessorMock implements the skill.Processor interface
+func TestProcessorMockImplementsProcessor(t *testing.T) {
+	// This test will fail to compile if ProcessorMock doesn't implement skill.Processor
+	var _ skill.Processor = &ProcessorMock{}
+}
@@ -15,6 +15,10 @@ type Processor interface {
 	RequestCreate(mb *message.Buffer) func(transactionId uuid.UUID, characterId uint32, skillId uint32, level byte, masterLevel byte, expiration time.Time) error
 	RequestUpdateAndEmit(transactionId uuid.UUID, characterId uint32, skillId uint32, level byte, masterLevel byte, expiration time.Time) error
 	RequestUpdate(mb *message.Buffer) func(transactionId uuid.UUID, characterId uint32, skillId uint32, level byte, masterLevel byte, expiration time.Time) error
+	ResetCooldownsAndEmit(transactionId uuid.UUID, characterId uint32, skillIds []uint32) error
+	ResetCooldowns(mb *message.Buffer) func(transactionId uuid.UUID, characterId uint32, skillIds []uint32) error
+	RequestDeleteAndEmit(transactionId uuid.UUID, characterId uint32, skillId uint32) error
+	RequestDelete(mb *message.Buffer) func(transactionId uuid.UUID, characterId uint32, skillId uint32) error
 }
 
 type ProcessorImpl struct {
@@ -54,3 +58,28 @@ func (p *ProcessorImpl) RequestUpdate(mb *message.Buffer) func(transactionId uui
 		return mb.Put(skill2.EnvCommandTopic, RequestUpdateProvider(transactionId, characterId, skillId, level, masterLevel, expiration))
 	}
 }
+
+func (p *ProcessorImpl) ResetCooldownsAndEmit(transactionId uuid.UUID, characterId uint32, skillIds []uint32) error {
+	return message.Emit(p.p)(func(mb *message.Buffer) error {
+		return p.ResetCooldowns(mb)(transactionId, characterId, skillIds)
+	})
+}
+
+func (p *ProcessorImpl) ResetCooldowns(mb *message.Buffer) func(transactionId uuid.UUID, characterId uint32, skillIds []uint32) error {
+	return func(transactionId uuid.UUID, characterId uint32, skillIds []uint32) error {
+		return mb.Put(skill2.EnvCommandTopic, ResetCooldownsProvider(transactionId, characterId, skillIds))
+	}
+}
+
+// RequestDeleteAndEmit requests the skill be removed from the character
+func (p *ProcessorImpl) RequestDeleteAndEmit(transactionId uuid.UUID, characterId uint32, skillId uint32) error {
+	return message.Emit(p.p)(func(mb *message.Buffer) error {
+		return p.RequestDelete(mb)(transactionId, characterId, skillId)
+	})
+}
+
+func (p *ProcessorImpl) RequestDelete(mb *message.Buffer) func(transactionId uuid.UUID, characterId uint32, skillId uint32) error {
+	return func(transactionId uuid.UUID, characterId uint32, skillId uint32) error {
+		return mb.Put(skill2.EnvCommandTopic, RequestDeleteProvider(transactionId, characterId, skillId))
+	}
+}
@@ -13,8 +13,8 @@ func RequestCreateProvider(transactionId uuid.UUID, characterId uint32, id uint3
 	key := producer.CreateKey(int(characterId))
 	value := &skill2.Command[skill2.RequestCreateBody]{
 		TransactionId: transactionId,
-		CharacterId: characterId,
-		Type:        skill2.CommandTypeRequestCreate,
+		CharacterId:   characterId,
+		Type:          skill2.CommandTypeRequestCreate,
 		Body: skill2.RequestCreateBody{
 			SkillId:     id,
 			Level:       level,
@@ -29,8 +29,8 @@ func RequestUpdateProvider(transactionId uuid.UUID, characterId uint32, id uint3
 	key := producer.CreateKey(int(characterId))
 	value := &skill2.Command[skill2.RequestUpdateBody]{
 		TransactionId: transactionId,
-		CharacterId: characterId,
-		Type:        skill2.CommandTypeRequestUpdate,
+		CharacterId:   characterId,
+		Type:          skill2.CommandTypeRequestUpdate,
 		Body: skill2.RequestUpdateBody{
 			SkillId:     id,
 			Level:       level,
@@ -40,3 +40,29 @@ func RequestUpdateProvider(transactionId uuid.UUID, characterId uint32, id uint3
 	}
 	return producer.SingleMessageProvider(key, value)
 }
+
+func ResetCooldownsProvider(transactionId uuid.UUID, characterId uint32, skillIds []uint32) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(characterId))
+	value := &skill2.Command[skill2.ResetCooldownsBody]{
+		TransactionId: transactionId,
+		CharacterId:   characterId,
+		Type:          skill2.CommandTypeResetCooldowns,
+		Body: skill2.ResetCooldownsBody{
+			SkillIds: skillIds,
+		},
+	}
+	return producer.SingleMessageProvider(key, value)
+}
+
+func RequestDeleteProvider(transactionId uuid.UUID, characterId uint32, id uint32) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(characterId))
+	value := &skill2.Command[skill2.RequestDeleteBody]{
+		TransactionId: transactionId,
+		CharacterId:   characterId,
+		Type:          skill2.CommandTypeRequestDelete,
+		Body: skill2.RequestDeleteBody{
+			SkillId: id,
+		},
+	}
+	return producer.SingleMessageProvider(key, value)
+}
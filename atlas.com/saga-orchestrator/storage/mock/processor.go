@@ -0,0 +1,31 @@
+package mock
+
+import (
+	"atlas-saga-orchestrator/kafka/message"
+	"github.com/Chronicle20/atlas-constants/world"
+	"github.com/google/uuid"
+)
+
+// ProcessorMock is a mock implementation of the storage.Processor interface
+type ProcessorMock struct {
+	ChangeCapacityAndEmitFunc func(transactionId uuid.UUID, worldId world.Id, accountId uint32, amount int16) error
+	ChangeCapacityFunc        func(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, accountId uint32, amount int16) error
+}
+
+// ChangeCapacityAndEmit is a mock implementation of the storage.Processor.ChangeCapacityAndEmit method
+func (m *ProcessorMock) ChangeCapacityAndEmit(transactionId uuid.UUID, worldId world.Id, accountId uint32, amount int16) error {
+	if m.ChangeCapacityAndEmitFunc != nil {
+		return m.ChangeCapacityAndEmitFunc(transactionId, worldId, accountId, amount)
+	}
+	return nil
+}
+
+// ChangeCapacity is a mock implementation of the storage.Processor.ChangeCapacity method
+func (m *ProcessorMock) ChangeCapacity(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, accountId uint32, amount int16) error {
+	if m.ChangeCapacityFunc != nil {
+		return m.ChangeCapacityFunc(mb)
+	}
+	return func(transactionId uuid.UUID, worldId world.Id, accountId uint32, amount int16) error {
+		return nil
+	}
+}
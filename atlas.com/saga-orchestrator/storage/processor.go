@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"atlas-saga-orchestrator/kafka/message"
+	storage2 "atlas-saga-orchestrator/kafka/message/storage"
+	"atlas-saga-orchestrator/kafka/producer"
+	"context"
+	"github.com/Chronicle20/atlas-constants/world"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type Processor interface {
+	ChangeCapacityAndEmit(transactionId uuid.UUID, worldId world.Id, accountId uint32, amount int16) error
+	ChangeCapacity(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, accountId uint32, amount int16) error
+}
+
+type ProcessorImpl struct {
+	l   logrus.FieldLogger
+	ctx context.Context
+	p   producer.Provider
+}
+
+func NewProcessor(l logrus.FieldLogger, ctx context.Context) Processor {
+	return &ProcessorImpl{
+		l:   l,
+		ctx: ctx,
+		p:   producer.ProviderImpl(l)(ctx),
+	}
+}
+
+// ChangeCapacityAndEmit requests the capacity of the account's storage in the world be changed by the amount of slots,
+// which is negative to remove slots
+func (p *ProcessorImpl) ChangeCapacityAndEmit(transactionId uuid.UUID, worldId world.Id, accountId uint32, amount int16) error {
+	return message.Emit(p.p)(func(mb *message.Buffer) error {
+		return p.ChangeCapacity(mb)(transactionId, worldId, accountId, amount)
+	})
+}
+
+func (p *ProcessorImpl) ChangeCapacity(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, accountId uint32, amount int16) error {
+	return func(transactionId uuid.UUID, worldId world.Id, accountId uint32, amount int16) error {
+		return mb.Put(storage2.EnvCommandTopic, ChangeCapacityProvider(transactionId, worldId, accountId, amount))
+	}
+}
@@ -0,0 +1,24 @@
+package storage
+
+import (
+	storage2 "atlas-saga-orchestrator/kafka/message/storage"
+	"github.com/Chronicle20/atlas-constants/world"
+	"github.com/Chronicle20/atlas-kafka/producer"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+)
+
+func ChangeCapacityProvider(transactionId uuid.UUID, worldId world.Id, accountId uint32, amount int16) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(accountId))
+	value := &storage2.Command[storage2.ChangeCapacityCommandBody]{
+		TransactionId: transactionId,
+		WorldId:       byte(worldId),
+		AccountId:     accountId,
+		Type:          storage2.CommandTypeChangeCapacity,
+		Body: storage2.ChangeCapacityCommandBody{
+			Amount: amount,
+		},
+	}
+	return producer.SingleMessageProvider(key, value)
+}
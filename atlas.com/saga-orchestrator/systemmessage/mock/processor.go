@@ -0,0 +1,32 @@
+package mock
+
+import (
+	"atlas-saga-orchestrator/kafka/message"
+	"github.com/Chronicle20/atlas-constants/channel"
+	"github.com/Chronicle20/atlas-constants/world"
+	"github.com/google/uuid"
+)
+
+// ProcessorMock is a mock implementation of the systemmessage.Processor interface
+type ProcessorMock struct {
+	SendMessageAndEmitFunc func(transactionId uuid.UUID, worldId world.Id, channelId channel.Id, characterId uint32, messageType string, msg string) error
+	SendMessageFunc        func(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, channelId channel.Id, characterId uint32, messageType string, msg string) error
+}
+
+// SendMessageAndEmit is a mock implementation of the systemmessage.Processor.SendMessageAndEmit method
+func (m *ProcessorMock) SendMessageAndEmit(transactionId uuid.UUID, worldId world.Id, channelId channel.Id, characterId uint32, messageType string, msg string) error {
+	if m.SendMessageAndEmitFunc != nil {
+		return m.SendMessageAndEmitFunc(transactionId, worldId, channelId, characterId, messageType, msg)
+	}
+	return nil
+}
+
+// SendMessage is a mock implementation of the systemmessage.Processor.SendMessage method
+func (m *ProcessorMock) SendMessage(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, channelId channel.Id, characterId uint32, messageType string, msg string) error {
+	if m.SendMessageFunc != nil {
+		return m.SendMessageFunc(mb)
+	}
+	return func(transactionId uuid.UUID, worldId world.Id, channelId channel.Id, characterId uint32, messageType string, msg string) error {
+		return nil
+	}
+}
@@ -0,0 +1,45 @@
+package systemmessage
+
+import (
+	"atlas-saga-orchestrator/kafka/message"
+	systemmessage2 "atlas-saga-orchestrator/kafka/message/systemmessage"
+	"atlas-saga-orchestrator/kafka/producer"
+	"context"
+	"github.com/Chronicle20/atlas-constants/channel"
+	"github.com/Chronicle20/atlas-constants/world"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type Processor interface {
+	SendMessageAndEmit(transactionId uuid.UUID, worldId world.Id, channelId channel.Id, characterId uint32, messageType string, msg string) error
+	SendMessage(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, channelId channel.Id, characterId uint32, messageType string, msg string) error
+}
+
+type ProcessorImpl struct {
+	l   logrus.FieldLogger
+	ctx context.Context
+	p   producer.Provider
+}
+
+func NewProcessor(l logrus.FieldLogger, ctx context.Context) Processor {
+	return &ProcessorImpl{
+		l:   l,
+		ctx: ctx,
+		p:   producer.ProviderImpl(l)(ctx),
+	}
+}
+
+// SendMessageAndEmit requests the channel service show the message to the character, as the type of message (e.g. a
+// notice or a pop-up)
+func (p *ProcessorImpl) SendMessageAndEmit(transactionId uuid.UUID, worldId world.Id, channelId channel.Id, characterId uint32, messageType string, msg string) error {
+	return message.Emit(p.p)(func(mb *message.Buffer) error {
+		return p.SendMessage(mb)(transactionId, worldId, channelId, characterId, messageType, msg)
+	})
+}
+
+func (p *ProcessorImpl) SendMessage(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, channelId channel.Id, characterId uint32, messageType string, msg string) error {
+	return func(transactionId uuid.UUID, worldId world.Id, channelId channel.Id, characterId uint32, messageType string, msg string) error {
+		return mb.Put(systemmessage2.EnvCommandTopic, SendMessageProvider(transactionId, worldId, channelId, characterId, messageType, msg))
+	}
+}
@@ -0,0 +1,27 @@
+package systemmessage
+
+import (
+	systemmessage2 "atlas-saga-orchestrator/kafka/message/systemmessage"
+	"github.com/Chronicle20/atlas-constants/channel"
+	"github.com/Chronicle20/atlas-constants/world"
+	"github.com/Chronicle20/atlas-kafka/producer"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+)
+
+func SendMessageProvider(transactionId uuid.UUID, worldId world.Id, channelId channel.Id, characterId uint32, messageType string, msg string) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(characterId))
+	value := &systemmessage2.Command[systemmessage2.SendMessageBody]{
+		TransactionId: transactionId,
+		WorldId:       worldId,
+		ChannelId:     channelId,
+		CharacterId:   characterId,
+		Type:          systemmessage2.CommandTypeSendMessage,
+		Body: systemmessage2.SendMessageBody{
+			MessageType: messageType,
+			Message:     msg,
+		},
+	}
+	return producer.SingleMessageProvider(key, value)
+}
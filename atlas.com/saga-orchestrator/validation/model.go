@@ -13,6 +13,17 @@ const (
 	MapCondition  ConditionType = "mapId"
 	FameCondition ConditionType = "fame"
 	ItemCondition ConditionType = "item"
+
+	// DailyFameCondition evaluates the number of times the character has given fame within the past day
+	DailyFameCondition ConditionType = "dailyFame"
+	// MonthlyFameTargetCondition evaluates the number of times the character has given fame to the referenced character within the past month
+	MonthlyFameTargetCondition ConditionType = "monthlyFameTarget"
+	// AccountCondition evaluates the ID of the account which owns the character
+	AccountCondition ConditionType = "accountId"
+	// GuildLeaderCondition evaluates whether the character leads their guild, 1 when they do and 0 otherwise
+	GuildLeaderCondition ConditionType = "guildLeader"
+	// LevelCondition evaluates the character's level
+	LevelCondition ConditionType = "level"
 )
 
 // Operator represents the comparison operator in a condition
@@ -28,10 +39,11 @@ const (
 
 // ConditionInput represents the structured input for creating a condition
 type ConditionInput struct {
-	Type     string `json:"type"`             // e.g., "jobId", "meso", "item"
-	Operator string `json:"operator"`         // e.g., "=", ">=", "<"
-	Value    int    `json:"value"`            // Value or quantity
-	ItemId   uint32 `json:"itemId,omitempty"` // Only for item checks
+	Type        string `json:"type"`                  // e.g., "jobId", "meso", "item"
+	Operator    string `json:"operator"`              // e.g., "=", ">=", "<"
+	Value       int    `json:"value"`                 // Value or quantity
+	ItemId      uint32 `json:"itemId,omitempty"`      // Only for item checks
+	ReferenceId uint32 `json:"referenceId,omitempty"` // Only for checks against another character, e.g. monthlyFameTarget
 }
 
 // ConditionResult represents the result of a condition evaluation
@@ -42,6 +54,7 @@ type ConditionResult struct {
 	Operator    Operator
 	Value       int
 	ItemId      uint32
+	ReferenceId uint32
 	ActualValue int
 }
 
@@ -51,6 +64,7 @@ type Condition struct {
 	operator      Operator
 	value         int
 	itemId        uint32 // Used for item conditions
+	referenceId   uint32 // Used for conditions against another character
 }
 
 // ConditionBuilder is used to safely construct Condition objects
@@ -59,6 +73,7 @@ type ConditionBuilder struct {
 	operator      Operator
 	value         int
 	itemId        *uint32
+	referenceId   *uint32
 	err           error
 }
 
@@ -74,7 +89,7 @@ func (b *ConditionBuilder) SetType(condType string) *ConditionBuilder {
 	}
 
 	switch ConditionType(condType) {
-	case JobCondition, MesoCondition, MapCondition, FameCondition, ItemCondition:
+	case JobCondition, MesoCondition, MapCondition, FameCondition, ItemCondition, DailyFameCondition, MonthlyFameTargetCondition, AccountCondition, GuildLeaderCondition, LevelCondition:
 		b.conditionType = ConditionType(condType)
 	default:
 		b.err = fmt.Errorf("unsupported condition type: %s", condType)
@@ -117,6 +132,16 @@ func (b *ConditionBuilder) SetItemId(itemId uint32) *ConditionBuilder {
 	return b
 }
 
+// SetReferenceId sets the referenced character ID (only for conditions against another character)
+func (b *ConditionBuilder) SetReferenceId(referenceId uint32) *ConditionBuilder {
+	if b.err != nil {
+		return b
+	}
+
+	b.referenceId = &referenceId
+	return b
+}
+
 // FromInput creates a condition builder from a ConditionInput
 func (b *ConditionBuilder) FromInput(input ConditionInput) *ConditionBuilder {
 	b.SetType(input.Type)
@@ -129,6 +154,10 @@ func (b *ConditionBuilder) FromInput(input ConditionInput) *ConditionBuilder {
 		b.err = fmt.Errorf("itemId is required for item conditions")
 	}
 
+	if input.ReferenceId != 0 {
+		b.SetReferenceId(input.ReferenceId)
+	}
+
 	return b
 }
 
@@ -156,6 +185,12 @@ func (b *ConditionBuilder) Validate() *ConditionBuilder {
 		return b
 	}
 
+	// Check if referenceId is set for conditions against another character
+	if b.conditionType == MonthlyFameTargetCondition && b.referenceId == nil {
+		b.err = fmt.Errorf("referenceId is required for monthly fame target conditions")
+		return b
+	}
+
 	return b
 }
 
@@ -177,6 +212,10 @@ func (b *ConditionBuilder) Build() (Condition, error) {
 		condition.itemId = *b.itemId
 	}
 
+	if b.referenceId != nil {
+		condition.referenceId = *b.referenceId
+	}
+
 	return condition, nil
 }
 
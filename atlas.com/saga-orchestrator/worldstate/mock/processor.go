@@ -0,0 +1,91 @@
+package mock
+
+import (
+	"atlas-saga-orchestrator/kafka/message"
+	"github.com/Chronicle20/atlas-constants/world"
+	"github.com/google/uuid"
+)
+
+// ProcessorMock is a mock implementation of the worldstate.Processor interface
+type ProcessorMock struct {
+	AdjustShopStockAndEmitFunc func(transactionId uuid.UUID, worldId world.Id, npcId uint32, templateId uint32, amount int32) error
+	AdjustShopStockFunc        func(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, npcId uint32, templateId uint32, amount int32) error
+	SetEventFlagAndEmitFunc    func(transactionId uuid.UUID, worldId world.Id, key string, value string) error
+	SetEventFlagFunc           func(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, key string, value string) error
+	ApplyEventBuffAndEmitFunc  func(transactionId uuid.UUID, worldId world.Id, buffId string, buffType string, rate float64) error
+	ApplyEventBuffFunc         func(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, buffId string, buffType string, rate float64) error
+	RemoveEventBuffAndEmitFunc func(transactionId uuid.UUID, worldId world.Id, buffId string) error
+	RemoveEventBuffFunc        func(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, buffId string) error
+}
+
+// AdjustShopStockAndEmit is a mock implementation of the worldstate.Processor.AdjustShopStockAndEmit method
+func (m *ProcessorMock) AdjustShopStockAndEmit(transactionId uuid.UUID, worldId world.Id, npcId uint32, templateId uint32, amount int32) error {
+	if m.AdjustShopStockAndEmitFunc != nil {
+		return m.AdjustShopStockAndEmitFunc(transactionId, worldId, npcId, templateId, amount)
+	}
+	return nil
+}
+
+// AdjustShopStock is a mock implementation of the worldstate.Processor.AdjustShopStock method
+func (m *ProcessorMock) AdjustShopStock(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, npcId uint32, templateId uint32, amount int32) error {
+	if m.AdjustShopStockFunc != nil {
+		return m.AdjustShopStockFunc(mb)
+	}
+	return func(transactionId uuid.UUID, worldId world.Id, npcId uint32, templateId uint32, amount int32) error {
+		return nil
+	}
+}
+
+// SetEventFlagAndEmit is a mock implementation of the worldstate.Processor.SetEventFlagAndEmit method
+func (m *ProcessorMock) SetEventFlagAndEmit(transactionId uuid.UUID, worldId world.Id, key string, value string) error {
+	if m.SetEventFlagAndEmitFunc != nil {
+		return m.SetEventFlagAndEmitFunc(transactionId, worldId, key, value)
+	}
+	return nil
+}
+
+// SetEventFlag is a mock implementation of the worldstate.Processor.SetEventFlag method
+func (m *ProcessorMock) SetEventFlag(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, key string, value string) error {
+	if m.SetEventFlagFunc != nil {
+		return m.SetEventFlagFunc(mb)
+	}
+	return func(transactionId uuid.UUID, worldId world.Id, key string, value string) error {
+		return nil
+	}
+}
+
+// ApplyEventBuffAndEmit is a mock implementation of the worldstate.Processor.ApplyEventBuffAndEmit method
+func (m *ProcessorMock) ApplyEventBuffAndEmit(transactionId uuid.UUID, worldId world.Id, buffId string, buffType string, rate float64) error {
+	if m.ApplyEventBuffAndEmitFunc != nil {
+		return m.ApplyEventBuffAndEmitFunc(transactionId, worldId, buffId, buffType, rate)
+	}
+	return nil
+}
+
+// ApplyEventBuff is a mock implementation of the worldstate.Processor.ApplyEventBuff method
+func (m *ProcessorMock) ApplyEventBuff(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, buffId string, buffType string, rate float64) error {
+	if m.ApplyEventBuffFunc != nil {
+		return m.ApplyEventBuffFunc(mb)
+	}
+	return func(transactionId uuid.UUID, worldId world.Id, buffId string, buffType string, rate float64) error {
+		return nil
+	}
+}
+
+// RemoveEventBuffAndEmit is a mock implementation of the worldstate.Processor.RemoveEventBuffAndEmit method
+func (m *ProcessorMock) RemoveEventBuffAndEmit(transactionId uuid.UUID, worldId world.Id, buffId string) error {
+	if m.RemoveEventBuffAndEmitFunc != nil {
+		return m.RemoveEventBuffAndEmitFunc(transactionId, worldId, buffId)
+	}
+	return nil
+}
+
+// RemoveEventBuff is a mock implementation of the worldstate.Processor.RemoveEventBuff method
+func (m *ProcessorMock) RemoveEventBuff(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, buffId string) error {
+	if m.RemoveEventBuffFunc != nil {
+		return m.RemoveEventBuffFunc(mb)
+	}
+	return func(transactionId uuid.UUID, worldId world.Id, buffId string) error {
+		return nil
+	}
+}
@@ -0,0 +1,84 @@
+package worldstate
+
+import (
+	"atlas-saga-orchestrator/kafka/message"
+	worldstate2 "atlas-saga-orchestrator/kafka/message/worldstate"
+	"atlas-saga-orchestrator/kafka/producer"
+	"context"
+	"github.com/Chronicle20/atlas-constants/world"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type Processor interface {
+	AdjustShopStockAndEmit(transactionId uuid.UUID, worldId world.Id, npcId uint32, templateId uint32, amount int32) error
+	AdjustShopStock(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, npcId uint32, templateId uint32, amount int32) error
+	SetEventFlagAndEmit(transactionId uuid.UUID, worldId world.Id, key string, value string) error
+	SetEventFlag(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, key string, value string) error
+	ApplyEventBuffAndEmit(transactionId uuid.UUID, worldId world.Id, buffId string, buffType string, rate float64) error
+	ApplyEventBuff(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, buffId string, buffType string, rate float64) error
+	RemoveEventBuffAndEmit(transactionId uuid.UUID, worldId world.Id, buffId string) error
+	RemoveEventBuff(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, buffId string) error
+}
+
+type ProcessorImpl struct {
+	l   logrus.FieldLogger
+	ctx context.Context
+	p   producer.Provider
+}
+
+func NewProcessor(l logrus.FieldLogger, ctx context.Context) Processor {
+	return &ProcessorImpl{
+		l:   l,
+		ctx: ctx,
+		p:   producer.ProviderImpl(l)(ctx),
+	}
+}
+
+func (p *ProcessorImpl) AdjustShopStockAndEmit(transactionId uuid.UUID, worldId world.Id, npcId uint32, templateId uint32, amount int32) error {
+	return message.Emit(p.p)(func(mb *message.Buffer) error {
+		return p.AdjustShopStock(mb)(transactionId, worldId, npcId, templateId, amount)
+	})
+}
+
+func (p *ProcessorImpl) AdjustShopStock(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, npcId uint32, templateId uint32, amount int32) error {
+	return func(transactionId uuid.UUID, worldId world.Id, npcId uint32, templateId uint32, amount int32) error {
+		return mb.Put(worldstate2.EnvCommandTopic, AdjustShopStockProvider(transactionId, worldId, npcId, templateId, amount))
+	}
+}
+
+func (p *ProcessorImpl) SetEventFlagAndEmit(transactionId uuid.UUID, worldId world.Id, key string, value string) error {
+	return message.Emit(p.p)(func(mb *message.Buffer) error {
+		return p.SetEventFlag(mb)(transactionId, worldId, key, value)
+	})
+}
+
+func (p *ProcessorImpl) SetEventFlag(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, key string, value string) error {
+	return func(transactionId uuid.UUID, worldId world.Id, key string, value string) error {
+		return mb.Put(worldstate2.EnvCommandTopic, SetEventFlagProvider(transactionId, worldId, key, value))
+	}
+}
+
+func (p *ProcessorImpl) ApplyEventBuffAndEmit(transactionId uuid.UUID, worldId world.Id, buffId string, buffType string, rate float64) error {
+	return message.Emit(p.p)(func(mb *message.Buffer) error {
+		return p.ApplyEventBuff(mb)(transactionId, worldId, buffId, buffType, rate)
+	})
+}
+
+func (p *ProcessorImpl) ApplyEventBuff(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, buffId string, buffType string, rate float64) error {
+	return func(transactionId uuid.UUID, worldId world.Id, buffId string, buffType string, rate float64) error {
+		return mb.Put(worldstate2.EnvCommandTopic, ApplyEventBuffProvider(transactionId, worldId, buffId, buffType, rate))
+	}
+}
+
+func (p *ProcessorImpl) RemoveEventBuffAndEmit(transactionId uuid.UUID, worldId world.Id, buffId string) error {
+	return message.Emit(p.p)(func(mb *message.Buffer) error {
+		return p.RemoveEventBuff(mb)(transactionId, worldId, buffId)
+	})
+}
+
+func (p *ProcessorImpl) RemoveEventBuff(mb *message.Buffer) func(transactionId uuid.UUID, worldId world.Id, buffId string) error {
+	return func(transactionId uuid.UUID, worldId world.Id, buffId string) error {
+		return mb.Put(worldstate2.EnvCommandTopic, RemoveEventBuffProvider(transactionId, worldId, buffId))
+	}
+}
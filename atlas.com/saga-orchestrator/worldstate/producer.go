@@ -0,0 +1,67 @@
+package worldstate
+
+import (
+	worldstate2 "atlas-saga-orchestrator/kafka/message/worldstate"
+	"github.com/Chronicle20/atlas-constants/world"
+	"github.com/Chronicle20/atlas-kafka/producer"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+)
+
+func AdjustShopStockProvider(transactionId uuid.UUID, worldId world.Id, npcId uint32, templateId uint32, amount int32) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(npcId))
+	value := &worldstate2.Command[worldstate2.AdjustShopStockBody]{
+		TransactionId: transactionId,
+		WorldId:       worldId,
+		Type:          worldstate2.CommandTypeAdjustShopStock,
+		Body: worldstate2.AdjustShopStockBody{
+			NpcId:      npcId,
+			TemplateId: templateId,
+			Amount:     amount,
+		},
+	}
+	return producer.SingleMessageProvider(key, value)
+}
+
+func SetEventFlagProvider(transactionId uuid.UUID, worldId world.Id, key string, value string) model.Provider[[]kafka.Message] {
+	k := producer.CreateKey(int(worldId))
+	v := &worldstate2.Command[worldstate2.SetEventFlagBody]{
+		TransactionId: transactionId,
+		WorldId:       worldId,
+		Type:          worldstate2.CommandTypeSetEventFlag,
+		Body: worldstate2.SetEventFlagBody{
+			Key:   key,
+			Value: value,
+		},
+	}
+	return producer.SingleMessageProvider(k, v)
+}
+
+func ApplyEventBuffProvider(transactionId uuid.UUID, worldId world.Id, buffId string, buffType string, rate float64) model.Provider[[]kafka.Message] {
+	k := producer.CreateKey(int(worldId))
+	v := &worldstate2.Command[worldstate2.ApplyEventBuffBody]{
+		TransactionId: transactionId,
+		WorldId:       worldId,
+		Type:          worldstate2.CommandTypeApplyEventBuff,
+		Body: worldstate2.ApplyEventBuffBody{
+			BuffId: buffId,
+			Type:   buffType,
+			Rate:   rate,
+		},
+	}
+	return producer.SingleMessageProvider(k, v)
+}
+
+func RemoveEventBuffProvider(transactionId uuid.UUID, worldId world.Id, buffId string) model.Provider[[]kafka.Message] {
+	k := producer.CreateKey(int(worldId))
+	v := &worldstate2.Command[worldstate2.RemoveEventBuffBody]{
+		TransactionId: transactionId,
+		WorldId:       worldId,
+		Type:          worldstate2.CommandTypeRemoveEventBuff,
+		Body: worldstate2.RemoveEventBuffBody{
+			BuffId: buffId,
+		},
+	}
+	return producer.SingleMessageProvider(k, v)
+}